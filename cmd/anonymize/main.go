@@ -0,0 +1,90 @@
+// Command anonymize clones the production database into staging with emails,
+// names, Google IDs and notification channel credentials pseudonymized.
+//
+//	ANONYMIZE_SECRET=... anonymize -source "$PROD_DSN" -target "$STAGING_DSN" -truncate
+//
+// The target schema must be migrated first. Pseudonyms are derived from
+// ANONYMIZE_SECRET, so runs with the same secret produce the same pseudonyms.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/anonymize"
+)
+
+func main() {
+	source := flag.String("source", os.Getenv("ANONYMIZE_SOURCE_DSN"), "production database DSN to read from")
+	target := flag.String("target", os.Getenv("ANONYMIZE_TARGET_DSN"), "staging database DSN to write to")
+	batchSize := flag.Int("batch-size", 500, "rows read and written at once")
+	marketData := flag.Bool("market-data", false, "also copy price history and technical indicators")
+	truncate := flag.Bool("truncate", false, "empty the staging tables before copying")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+
+	if *source == "" || *target == "" {
+		logger.Fatal("Both -source and -target are required")
+	}
+	if *source == *target {
+		logger.Fatal("Refusing to anonymize a database into itself")
+	}
+	if os.Getenv("APP_ENV") == "production" {
+		logger.Fatal("Refusing to run with APP_ENV=production, the target must be a staging database")
+	}
+
+	secret := []byte(os.Getenv("ANONYMIZE_SECRET"))
+	if len(secret) == 0 {
+		// Pseudonyms stay consistent within the run but change on the next one
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatalf("Failed to generate a pseudonym secret: %v", err)
+		}
+		logger.Warn("ANONYMIZE_SECRET is not set, using a random secret for this run")
+	}
+
+	sourceDB, err := open(*source)
+	if err != nil {
+		logger.Fatalf("Failed to connect to the source database: %v", err)
+	}
+	targetDB, err := open(*target)
+	if err != nil {
+		logger.Fatalf("Failed to connect to the target database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cloner := anonymize.NewCloner(sourceDB, targetDB, anonymize.NewPseudonymizer(secret), anonymize.Options{
+		BatchSize:  *batchSize,
+		MarketData: *marketData,
+		Truncate:   *truncate,
+	}, logger)
+
+	counts, err := cloner.Run(ctx)
+	for _, count := range counts {
+		fmt.Printf("%-24s %d\n", count.Table, count.Rows)
+	}
+	if err != nil {
+		logger.Fatalf("Anonymized clone failed: %v", err)
+	}
+	logger.Info("Anonymized clone completed")
+}
+
+func open(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/database"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+)
+
+// backfillDateLayout is the short date format accepted by -backfill-from and -backfill-to
+const backfillDateLayout = "2006-01-02"
+
+// runBackfill fetches the candles of market, given as SYMBOL:TIMEFRAME, between
+// from and to (now when empty) and stores the missing ones, returning the process
+// exit code. Unlike the admin endpoint, the range isn't capped.
+func runBackfill(market, from, to string) int {
+	symbol, timeframe, found := strings.Cut(market, ":")
+	if !found || symbol == "" || timeframe == "" {
+		fmt.Fprintln(os.Stderr, "-backfill must be SYMBOL:TIMEFRAME, e.g. BTCUSDT:1h")
+		return 2
+	}
+	fromTime, err := parseBackfillTime(from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -backfill-from: %v\n", err)
+		return 2
+	}
+	toTime := time.Now()
+	if to != "" {
+		if toTime, err = parseBackfillTime(to); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -backfill-to: %v\n", err)
+			return 2
+		}
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	logger := setupLogger(cfg)
+
+	dbManager, err := database.NewManager(cfg, logger)
+	if err != nil {
+		logger.Errorf("Failed to initialize database connections: %v", err)
+		return 1
+	}
+	defer dbManager.Close()
+
+	marketData, err := external.NewMarketDataProvider(cfg, nil, logger)
+	if err != nil {
+		logger.Errorf("Failed to create market data provider: %v", err)
+		return 1
+	}
+
+	backfill := services.NewBackfillService(
+		marketData,
+		repository.NewPriceHistoryRepository(dbManager.GetDB()),
+		logger,
+	)
+	backfill.SetMaxCandles(0)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	result, err := backfill.Backfill(ctx, services.BackfillRequest{
+		Symbol:    strings.ToUpper(symbol),
+		Timeframe: timeframe,
+		From:      fromTime,
+		To:        toTime,
+	})
+	if err != nil {
+		logger.Errorf("Backfill failed: %v", err)
+		return 1
+	}
+
+	fmt.Printf("Backfilled %s %s: %d fetched, %d inserted, %d already stored in %s\n",
+		result.Symbol, result.Timeframe, result.Fetched, result.Inserted, result.Skipped, result.Duration.Round(time.Millisecond))
+	return 0
+}
+
+// parseBackfillTime accepts a date, taken as midnight UTC, or an RFC 3339 time
+func parseBackfillTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("a date is required")
+	}
+	if t, err := time.Parse(backfillDateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
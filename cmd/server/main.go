@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -19,6 +21,19 @@ import (
 )
 
 func main() {
+	validateOnly := flag.Bool("validate-config", false, "check the configuration, report every problem and exit")
+	backfill := flag.String("backfill", "", "backfill the candles of SYMBOL:TIMEFRAME from Binance and exit")
+	backfillFrom := flag.String("backfill-from", "", "start of the backfill, YYYY-MM-DD or RFC 3339")
+	backfillTo := flag.String("backfill-to", "", "end of the backfill, YYYY-MM-DD or RFC 3339 (default now)")
+	flag.Parse()
+
+	if *validateOnly {
+		os.Exit(validateConfig())
+	}
+	if *backfill != "" {
+		os.Exit(runBackfill(*backfill, *backfillFrom, *backfillTo))
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -33,6 +48,19 @@ func main() {
 	}
 	defer zapLogger.Sync()
 
+	// Watch the secrets backend for rotations; values are read once at startup
+	if cfg.SecretsManager != nil {
+		for _, key := range config.SecretKeys {
+			cfg.SecretsManager.OnRotate(key, func(key, _ string) {
+				logger.WithField("secret", key).Warn("Secret rotated in the secrets backend, restart to apply it")
+			})
+		}
+		cfg.SecretsManager.Start(context.Background(), cfg.Secrets.RefreshInterval, func(err error) {
+			logger.WithError(err).Error("Failed to refresh secrets")
+		})
+		defer cfg.SecretsManager.Stop()
+	}
+
 	logger.Info("Starting PriceGuard API server...")
 	zapLogger.Info("Zap logger initialized successfully")
 
@@ -71,7 +99,10 @@ func main() {
 		RedisClient:    dbManager.GetRedis().GetClient(),
 		TracingManager: tracingManager,
 	}
-	wsManager := httphandler.SetupRoutes(router, routerDeps)
+	// Background services run until shutdown cancels their context
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
+	wsManager := httphandler.SetupRoutes(appCtx, router, routerDeps)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -97,9 +128,9 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
-	// Stop WebSocket worker
-	if wsManager != nil && wsManager.Worker != nil {
-		wsManager.Worker.Stop()
+	// Stop the background services and wait for their workers
+	if wsManager != nil && wsManager.App != nil {
+		wsManager.App.Stop()
 	}
 
 	// Give outstanding requests 30 seconds to complete
@@ -114,6 +145,27 @@ func main() {
 }
 
 // setupLogger configures the application logger
+// validateConfig loads the configuration like startup does and prints every
+// problem found, returning the process exit code
+func validateConfig() int {
+	cfg, err := config.LoadConfig()
+	if err == nil {
+		fmt.Printf("Configuration OK (environment: %s)\n", cfg.App.Environment)
+		return 0
+	}
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Configuration has %d problem(s):\n", len(validationErr.Problems))
+	for _, problem := range validationErr.Problems {
+		fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+	}
+	return 1
+}
+
 func setupLogger(cfg *config.Config) *logrus.Logger {
 	logger := logrus.New()
 
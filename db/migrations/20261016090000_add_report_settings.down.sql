@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_user_settings_report_frequency;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS last_report_at,
+    DROP COLUMN IF EXISTS report_hour,
+    DROP COLUMN IF EXISTS report_frequency;
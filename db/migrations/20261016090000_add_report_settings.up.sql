@@ -0,0 +1,7 @@
+-- Market summary report schedule per user
+ALTER TABLE user_settings
+    ADD COLUMN report_frequency VARCHAR(10) DEFAULT 'none',
+    ADD COLUMN report_hour SMALLINT DEFAULT 8 CHECK (report_hour BETWEEN 0 AND 23),
+    ADD COLUMN last_report_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_user_settings_report_frequency ON user_settings(report_frequency) WHERE report_frequency <> 'none';
@@ -0,0 +1,3 @@
+-- Snapshot of the alert evaluation (prices, indicator values) that produced the notification
+ALTER TABLE notifications
+    ADD COLUMN context JSONB;
@@ -0,0 +1,3 @@
+-- Notification queue priority chosen per alert
+ALTER TABLE alerts
+    ADD COLUMN priority VARCHAR(10) DEFAULT 'high' CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
@@ -0,0 +1,3 @@
+-- Custom display labels for favorite symbols, keyed by symbol; favorite_symbols keeps the order
+ALTER TABLE user_settings
+    ADD COLUMN favorite_labels JSONB DEFAULT '{}'::jsonb;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_technical_indicators_metadata;
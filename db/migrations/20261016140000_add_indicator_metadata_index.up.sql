@@ -0,0 +1,3 @@
+-- Lets indicator lookups filter on metadata keys such as period and multiplier
+CREATE INDEX IF NOT EXISTS idx_technical_indicators_metadata
+    ON technical_indicators USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,17 @@
+ALTER TABLE technical_indicators
+    DROP CONSTRAINT IF EXISTS technical_indicators_identity_key;
+
+-- The old key can't tell periods apart, so keep one row per type and timestamp
+-- (the lowest indicator_key, e.g. EMA_12 over EMA_26) and discard the others
+DELETE FROM technical_indicators
+WHERE id NOT IN (
+    SELECT DISTINCT ON (symbol, timeframe, indicator_type, timestamp) id
+    FROM technical_indicators
+    ORDER BY symbol, timeframe, indicator_type, timestamp, indicator_key, id
+);
+
+ALTER TABLE technical_indicators
+    ADD UNIQUE (symbol, timeframe, indicator_type, timestamp);
+
+ALTER TABLE technical_indicators
+    DROP COLUMN IF EXISTS indicator_key;
@@ -0,0 +1,29 @@
+-- Composite indicator identity: type plus period and multiplier, e.g. EMA_26 or SuperTrend_10_3
+ALTER TABLE technical_indicators
+    ADD COLUMN indicator_key VARCHAR(80);
+
+UPDATE technical_indicators
+SET indicator_key = indicator_type
+    || COALESCE('_' || (metadata->>'period'), '')
+    || COALESCE('_' || (metadata->>'multiplier'), '');
+
+ALTER TABLE technical_indicators
+    ALTER COLUMN indicator_key SET NOT NULL;
+
+-- The old unique constraint was on the bare type, so two periods stored at the same
+-- timestamp collided; its generated name depends on truncation, so look it up
+DO $$
+DECLARE
+    old_constraint TEXT;
+BEGIN
+    SELECT conname INTO old_constraint
+    FROM pg_constraint
+    WHERE conrelid = 'technical_indicators'::regclass
+      AND contype = 'u';
+    IF old_constraint IS NOT NULL THEN
+        EXECUTE format('ALTER TABLE technical_indicators DROP CONSTRAINT %I', old_constraint);
+    END IF;
+END $$;
+
+ALTER TABLE technical_indicators
+    ADD CONSTRAINT technical_indicators_identity_key UNIQUE (symbol, timeframe, indicator_key, timestamp);
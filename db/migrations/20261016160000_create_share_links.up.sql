@@ -0,0 +1,15 @@
+-- Public read-only links to frozen pullback analyses and alert trigger snapshots
+CREATE TABLE share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    resource_type VARCHAR(50) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    snapshot JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_share_links_user_id ON share_links(user_id, created_at DESC);
+CREATE INDEX idx_share_links_expires_at ON share_links(expires_at);
@@ -0,0 +1,15 @@
+-- Users flagged for alert configurations that look designed to spam notifications
+CREATE TABLE abuse_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(50) NOT NULL,
+    alert_count INTEGER NOT NULL,
+    details JSONB,
+    throttled_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_abuse_flags_user_id ON abuse_flags(user_id, created_at DESC);
+CREATE INDEX idx_abuse_flags_throttled_until ON abuse_flags(throttled_until);
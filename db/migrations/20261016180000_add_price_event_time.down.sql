@@ -0,0 +1,2 @@
+ALTER TABLE price_history
+    DROP COLUMN IF EXISTS event_time;
@@ -0,0 +1,3 @@
+-- Exchange event time of real-time prices, used to trace alert latency
+ALTER TABLE price_history
+    ADD COLUMN event_time TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,14 @@
+-- Telegram chats the users' alerts are sent to. chat_id is envelope encrypted
+-- when ENCRYPTION_MASTER_KEY is set; code_hash is the pending one-time link code.
+CREATE TABLE telegram_links (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_id TEXT,
+    username VARCHAR(100),
+    code_hash VARCHAR(64),
+    code_expires_at TIMESTAMP WITH TIME ZONE,
+    linked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_telegram_links_code_hash ON telegram_links(code_hash);
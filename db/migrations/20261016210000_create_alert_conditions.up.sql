@@ -0,0 +1,14 @@
+-- Condition trees of composite alerts. Groups have alert_type 'composite' and
+-- combine their children with condition_type 'and' or 'or'.
+CREATE TABLE alert_conditions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES alert_conditions(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    alert_type VARCHAR(50) NOT NULL,
+    condition_type VARCHAR(20) NOT NULL,
+    target_value DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alert_conditions_alert_id ON alert_conditions(alert_id, position);
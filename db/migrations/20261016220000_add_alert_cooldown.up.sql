@@ -0,0 +1,4 @@
+-- Quiet period after an alert triggers, chosen per alert; 0 falls back to the default
+ALTER TABLE alerts
+    ADD COLUMN cooldown_seconds INTEGER NOT NULL DEFAULT 300
+        CHECK (cooldown_seconds = 0 OR cooldown_seconds BETWEEN 60 AND 604800);
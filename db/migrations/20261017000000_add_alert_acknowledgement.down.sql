@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_alerts_awaiting_ack_since;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS acknowledged_at,
+    DROP COLUMN IF EXISTS last_escalated_at,
+    DROP COLUMN IF EXISTS escalation_level,
+    DROP COLUMN IF EXISTS awaiting_ack_since,
+    DROP COLUMN IF EXISTS ack_interval_minutes,
+    DROP COLUMN IF EXISTS ack_required;
@@ -0,0 +1,14 @@
+-- Acknowledgement-required alerts re-notify every ack_interval_minutes (0 for the
+-- default) until the user acknowledges the trigger
+ALTER TABLE alerts
+    ADD COLUMN ack_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN ack_interval_minutes INTEGER NOT NULL DEFAULT 0
+        CHECK (ack_interval_minutes = 0 OR ack_interval_minutes BETWEEN 1 AND 1440),
+    ADD COLUMN awaiting_ack_since TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN escalation_level INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_escalated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN acknowledged_at TIMESTAMP WITH TIME ZONE;
+
+-- The escalation worker only reads the alerts waiting for an acknowledgement
+CREATE INDEX idx_alerts_awaiting_ack_since ON alerts (awaiting_ack_since)
+    WHERE awaiting_ack_since IS NOT NULL;
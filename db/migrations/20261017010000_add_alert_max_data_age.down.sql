@@ -0,0 +1 @@
+ALTER TABLE alerts DROP COLUMN IF EXISTS max_data_age_seconds;
@@ -0,0 +1,5 @@
+-- Evaluations are skipped when the market data is older than max_data_age_seconds
+-- past its candle's close (0 for the engine default)
+ALTER TABLE alerts
+    ADD COLUMN max_data_age_seconds INTEGER NOT NULL DEFAULT 0
+        CHECK (max_data_age_seconds = 0 OR max_data_age_seconds BETWEEN 10 AND 86400);
@@ -0,0 +1,17 @@
+-- One row per attempt to deliver a queued notification through a channel, feeding
+-- the per-channel success rates, latency and retries of the notification stats.
+-- Rows older than the notification retention are removed by the cleanup job.
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    attempt INTEGER NOT NULL DEFAULT 1,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_notification_deliveries_notification_id ON notification_deliveries(notification_id);
+CREATE INDEX idx_notification_deliveries_channel_time ON notification_deliveries(channel, delivered_at);
@@ -0,0 +1,10 @@
+-- Admin-managed blacklist and greylist of symbols users can't set new alerts on
+CREATE TABLE symbol_restrictions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    symbol VARCHAR(20) NOT NULL UNIQUE,
+    level VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
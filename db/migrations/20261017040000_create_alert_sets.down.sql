@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_alerts_set_id;
+ALTER TABLE alerts DROP COLUMN IF EXISTS set_id;
+DROP TABLE IF EXISTS alert_sets;
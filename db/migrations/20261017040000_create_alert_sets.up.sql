@@ -0,0 +1,15 @@
+-- Named groups of a user's alerts, enabled, disabled and deleted together
+CREATE TABLE alert_sets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT idx_alert_sets_user_name UNIQUE (user_id, name)
+);
+
+-- Deleting a set deletes its alerts in the application, unless they are kept, in
+-- which case they are only taken out of the set
+ALTER TABLE alerts ADD COLUMN set_id UUID REFERENCES alert_sets(id) ON DELETE SET NULL;
+CREATE INDEX idx_alerts_set_id ON alerts(set_id);
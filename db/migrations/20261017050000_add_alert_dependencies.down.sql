@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_alerts_depends_on_id;
+ALTER TABLE alerts DROP COLUMN IF EXISTS armed_at;
+ALTER TABLE alerts DROP COLUMN IF EXISTS depends_on_id;
@@ -0,0 +1,5 @@
+-- Chained alerts are only evaluated once the alert they depend on triggered, which
+-- arms them; deleting that alert turns them into plain alerts
+ALTER TABLE alerts ADD COLUMN depends_on_id UUID REFERENCES alerts(id) ON DELETE SET NULL;
+ALTER TABLE alerts ADD COLUMN armed_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX idx_alerts_depends_on_id ON alerts(depends_on_id);
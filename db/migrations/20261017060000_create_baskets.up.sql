@@ -0,0 +1,15 @@
+-- User-defined weighted indices of symbols. A basket's value is computed in the
+-- application and stored in price_history under its symbol, like a real symbol's.
+CREATE TABLE baskets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    base_value DECIMAL(20,8) NOT NULL,
+    components JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_baskets_symbol ON baskets(symbol);
+CREATE INDEX idx_baskets_user_id ON baskets(user_id);
@@ -0,0 +1,4 @@
+-- Sandbox users are demo accounts fed by the market simulator; the stale ones are
+-- deleted together with their alerts, settings and sessions
+ALTER TABLE users ADD COLUMN sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_users_sandbox ON users(sandbox);
@@ -0,0 +1,14 @@
+-- Prices of each symbol observed on every aggregated exchange. Their consolidated
+-- price is stored in price_history under the AGG-<symbol> symbol.
+CREATE TABLE exchange_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    exchange VARCHAR(20) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    volume DECIMAL(30,8) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_exchange_price_symbol ON exchange_price_history(symbol, exchange, timestamp DESC);
+CREATE INDEX idx_exchange_price_timestamp ON exchange_price_history(timestamp);
@@ -0,0 +1,19 @@
+-- Daily OHLCV and volatility of each symbol, compacted incrementally from its
+-- intraday candles so long windows don't scan price_history
+CREATE TABLE price_daily_summaries (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    day TIMESTAMP WITH TIME ZONE NOT NULL,
+    open_price DECIMAL(20,8) NOT NULL,
+    high_price DECIMAL(20,8) NOT NULL,
+    low_price DECIMAL(20,8) NOT NULL,
+    close_price DECIMAL(20,8) NOT NULL,
+    volume DECIMAL(30,8) NOT NULL,
+    volatility DECIMAL(12,6) NOT NULL,
+    candles INTEGER NOT NULL,
+    source_timeframe VARCHAR(10) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_price_daily_summary ON price_daily_summaries(symbol, day);
+CREATE INDEX idx_price_daily_summaries_day ON price_daily_summaries(day);
@@ -0,0 +1,5 @@
+-- Percentage alerts compare the price with window_days days ago, read from the
+-- daily summaries (0 compares it with 24 hours ago)
+ALTER TABLE alerts
+    ADD COLUMN window_days INTEGER NOT NULL DEFAULT 0
+        CHECK (window_days BETWEEN 0 AND 365);
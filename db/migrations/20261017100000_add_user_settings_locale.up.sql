@@ -0,0 +1,4 @@
+-- Locale the API formats display strings of numbers and prices for (e.g. pt-BR);
+-- empty leaves formatting to the Accept-Language header of requests asking for it
+ALTER TABLE user_settings
+    ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT '';
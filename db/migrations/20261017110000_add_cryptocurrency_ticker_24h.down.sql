@@ -0,0 +1,7 @@
+ALTER TABLE cryptocurrencies
+    DROP COLUMN IF EXISTS last_price,
+    DROP COLUMN IF EXISTS price_change_24h,
+    DROP COLUMN IF EXISTS price_change_percent_24h,
+    DROP COLUMN IF EXISTS volume_24h,
+    DROP COLUMN IF EXISTS quote_volume_24h,
+    DROP COLUMN IF EXISTS ticker_24h_at;
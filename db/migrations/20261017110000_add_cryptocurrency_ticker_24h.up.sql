@@ -0,0 +1,9 @@
+-- Exchange-reported rolling 24h statistics, refreshed by the ticker_24h job;
+-- change_24h alerts compare price_change_percent_24h with their target
+ALTER TABLE cryptocurrencies
+    ADD COLUMN last_price DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN price_change_24h DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN price_change_percent_24h DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    ADD COLUMN volume_24h DECIMAL(30, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN quote_volume_24h DECIMAL(30, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN ticker_24h_at TIMESTAMP WITH TIME ZONE;
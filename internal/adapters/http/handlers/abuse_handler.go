@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+type AbuseHandler struct {
+	abuseService *services.AbuseService
+}
+
+// NewAbuseHandler creates a new abuse handler
+func NewAbuseHandler(abuseService *services.AbuseService) *AbuseHandler {
+	return &AbuseHandler{
+		abuseService: abuseService,
+	}
+}
+
+// GetAbuseFlags godoc
+// @Summary List abuse flags
+// @Description List users flagged for alert configurations that look designed to spam notifications, newest first (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param active query bool false "Only flags still throttling the user"
+// @Param limit query int false "Limit number of results" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/abuse-flags [get]
+func (h *AbuseHandler) GetAbuseFlags(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 100 {
+		limit = 100
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	flags, err := h.abuseService.ListFlags(c.Request.Context(), activeOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch abuse flags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   flags,
+		"limit":  limit,
+		"offset": offset,
+		"count":  len(flags),
+	})
+}
+
+// ScanForAbuse godoc
+// @Summary Scan alerts for abuse
+// @Description Run the abuse heuristics over all enabled alerts now instead of waiting for the next scheduled scan (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/abuse-flags/scan [post]
+func (h *AbuseHandler) ScanForAbuse(c *gin.Context) {
+	flags, err := h.abuseService.Scan(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan alerts for abuse"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  flags,
+		"count": len(flags),
+	})
+}
+
+// GetEvaluationCosts godoc
+// @Summary List alert evaluation costs
+// @Description List the users whose alerts took the longest to evaluate on a UTC day, with their evaluation and database call counts (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "UTC day as YYYY-MM-DD, defaults to today"
+// @Param limit query int false "Limit number of results" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/alert-costs [get]
+func (h *AbuseHandler) GetEvaluationCosts(c *gin.Context) {
+	day := time.Now().UTC()
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be formatted as YYYY-MM-DD"})
+			return
+		}
+		day = parsed
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit > 500 {
+		limit = 500
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	costs, err := h.abuseService.EvaluationCosts(c.Request.Context(), day, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch evaluation costs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  costs,
+		"date":  day.Format("2006-01-02"),
+		"limit": limit,
+		"count": len(costs),
+	})
+}
+
+// ResolveAbuseFlag godoc
+// @Summary Resolve an abuse flag
+// @Description Clear a flag and lift the user's throttle unless another flag is still active (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Abuse flag ID"
+// @Success 200 {object} entities.AbuseFlag
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Abuse flag not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/abuse-flags/{id}/resolve [post]
+func (h *AbuseHandler) ResolveAbuseFlag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid abuse flag ID"})
+		return
+	}
+
+	resolvedBy := ""
+	if value, exists := c.Get("user"); exists {
+		if user, ok := value.(*entities.User); ok {
+			resolvedBy = user.Email
+		}
+	}
+
+	flag, err := h.abuseService.ResolveFlag(c.Request.Context(), id, resolvedBy)
+	if err != nil {
+		respondError(c, err, "Failed to resolve abuse flag")
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
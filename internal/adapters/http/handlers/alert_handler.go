@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,6 +18,14 @@ type AlertHandler struct {
 	alertRepo    repositories.AlertRepository
 	alertMonitor *services.AlertMonitor
 	alertEngine  *services.AlertEngine
+	alertLevels  *services.AlertLevelService
+	alertCharts  *services.AlertChartService
+	escalations  *services.AlertEscalationService
+	restrictions *services.SymbolRestrictionService
+	alertSets    *services.AlertSetService
+	baskets      *services.BasketService
+	aggregation  *services.PriceAggregationService
+	settingsRepo repositories.UserSettingsRepository
 }
 
 // NewAlertHandler creates a new alert handler
@@ -32,6 +41,139 @@ func NewAlertHandler(
 	}
 }
 
+// SetAlertLevelService enables pushing updated alert lines to chart clients when
+// an alert is created, changed or deleted
+func (h *AlertHandler) SetAlertLevelService(alertLevels *services.AlertLevelService) {
+	h.alertLevels = alertLevels
+}
+
+// SetAlertChartService enables the chart context endpoint used for alert mini-charts
+func (h *AlertHandler) SetAlertChartService(alertCharts *services.AlertChartService) {
+	h.alertCharts = alertCharts
+}
+
+// SetSymbolRestrictionService refuses new alerts on blacklisted or greylisted
+// symbols and re-enabling alerts on blacklisted ones
+func (h *AlertHandler) SetSymbolRestrictionService(restrictions *services.SymbolRestrictionService) {
+	h.restrictions = restrictions
+}
+
+// SetAlertSetService enables putting alerts in one of the user's alert sets
+func (h *AlertHandler) SetAlertSetService(alertSets *services.AlertSetService) {
+	h.alertSets = alertSets
+}
+
+// SetBasketService enables alerts on the user's symbol baskets
+func (h *AlertHandler) SetBasketService(baskets *services.BasketService) {
+	h.baskets = baskets
+}
+
+// SetPriceAggregationService enables alerts on prices consolidated across exchanges
+func (h *AlertHandler) SetPriceAggregationService(aggregation *services.PriceAggregationService) {
+	h.aggregation = aggregation
+}
+
+// SetEscalationService enables acknowledging the triggers of acknowledgement-required alerts
+func (h *AlertHandler) SetEscalationService(escalations *services.AlertEscalationService) {
+	h.escalations = escalations
+}
+
+// SetUserSettingsRepo enables display strings formatted for the user's locale
+func (h *AlertHandler) SetUserSettingsRepo(settingsRepo repositories.UserSettingsRepository) {
+	h.settingsRepo = settingsRepo
+}
+
+// publishAlertLevels pushes the user's alert lines on the symbol, if enabled
+func (h *AlertHandler) publishAlertLevels(c *gin.Context, alert *entities.Alert) {
+	if h.alertLevels != nil {
+		h.alertLevels.PublishAlertLevels(c.Request.Context(), alert.UserID, alert.Symbol)
+	}
+}
+
+// alertSetID parses the set_id of an alert request and checks the set belongs to
+// the user; an empty value means no set. It responds and reports false on error.
+func (h *AlertHandler) alertSetID(c *gin.Context, userID uuid.UUID, value string) (*uuid.UUID, bool) {
+	if value == "" {
+		return nil, true
+	}
+	if h.alertSets == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Alert sets are not available"})
+		return nil, false
+	}
+	setID, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid set_id"})
+		return nil, false
+	}
+	if _, err := h.alertSets.CheckSet(c.Request.Context(), userID, setID); err != nil {
+		respondError(c, err, "Failed to check alert set")
+		return nil, false
+	}
+	return &setID, true
+}
+
+// alertDependency parses the depends_on_id of an alert request and checks the alert
+// can be chained after it; an empty value means no dependency. It responds and
+// reports false on error.
+func (h *AlertHandler) alertDependency(c *gin.Context, alert *entities.Alert, value string) (*uuid.UUID, bool) {
+	if value == "" {
+		return nil, true
+	}
+	dependsOnID, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid depends_on_id"})
+		return nil, false
+	}
+	if err := services.CheckAlertDependency(c.Request.Context(), h.alertRepo, alert, dependsOnID); err != nil {
+		respondError(c, err, "Failed to check alert dependency")
+		return nil, false
+	}
+	return &dependsOnID, true
+}
+
+// checkBasketAlert checks an alert on a basket symbol can watch the basket. It
+// responds and reports false on error.
+func (h *AlertHandler) checkBasketAlert(c *gin.Context, alert *entities.Alert) bool {
+	if !entities.IsBasketSymbol(alert.Symbol) {
+		return true
+	}
+	if h.baskets == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Baskets are not available"})
+		return false
+	}
+	if err := h.baskets.CheckAlert(c.Request.Context(), alert); err != nil {
+		respondError(c, err, "Failed to check basket")
+		return false
+	}
+	return true
+}
+
+// checkConsolidatedAlert checks an alert on a consolidated symbol can watch the
+// consolidated price. It responds and reports false on error.
+func (h *AlertHandler) checkConsolidatedAlert(c *gin.Context, alert *entities.Alert) bool {
+	if !entities.IsConsolidatedSymbol(alert.Symbol) {
+		return true
+	}
+	if h.aggregation == nil || !h.aggregation.Enabled() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Price aggregation is not available"})
+		return false
+	}
+	if err := h.aggregation.CheckAlert(c.Request.Context(), alert); err != nil {
+		respondError(c, err, "Failed to check consolidated price")
+		return false
+	}
+	return true
+}
+
+// cooldownOrDefault stores an omitted or zero cooldown as the default, so changing
+// the default later doesn't change the cooldown of existing alerts
+func cooldownOrDefault(seconds int) int {
+	if seconds == 0 {
+		return int(entities.DefaultAlertCooldown.Seconds())
+	}
+	return seconds
+}
+
 // GetAlerts godoc
 // @Summary Get user alerts
 // @Description Get list of alerts for the authenticated user
@@ -41,6 +183,8 @@ func NewAlertHandler(
 // @Security BearerAuth
 // @Param limit query int false "Limit number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
+// @Param display query bool false "Add display strings formatted for the Accept-Language locale; always on for users with a locale setting"
+// @Param Accept-Language header string false "Locale of the display strings, e.g. pt-BR"
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -74,7 +218,7 @@ func (h *AlertHandler) GetAlerts(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":   alerts,
+		"data":   alertsWithDisplay(displayFormatter(c, h.settingsRepo), alerts),
 		"limit":  limit,
 		"offset": offset,
 		"count":  len(alerts),
@@ -92,6 +236,7 @@ func (h *AlertHandler) GetAlerts(c *gin.Context) {
 // @Success 201 {object} entities.Alert
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Symbol blacklisted or greylisted"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/alerts [post]
 func (h *AlertHandler) CreateAlert(c *gin.Context) {
@@ -101,14 +246,25 @@ func (h *AlertHandler) CreateAlert(c *gin.Context) {
 		return
 	}
 
+	// Composite alerts have no target of their own, the alert validation checks
+	// the target of every other type
 	var alertData struct {
-		Symbol        string   `json:"symbol" binding:"required"`
-		AlertType     string   `json:"alert_type" binding:"required"`
-		ConditionType string   `json:"condition_type" binding:"required"`
-		TargetValue   float64  `json:"target_value" binding:"required"`
-		Timeframe     string   `json:"timeframe" binding:"required"`
-		NotifyVia     []string `json:"notify_via,omitempty"`
-		Enabled       *bool    `json:"enabled,omitempty"`
+		Symbol        string                    `json:"symbol" binding:"required"`
+		AlertType     string                    `json:"alert_type" binding:"required"`
+		ConditionType string                    `json:"condition_type" binding:"required"`
+		TargetValue   float64                   `json:"target_value"`
+		Timeframe     string                    `json:"timeframe" binding:"required"`
+		Conditions    []entities.AlertCondition `json:"conditions,omitempty"`
+		NotifyVia     []string                  `json:"notify_via,omitempty"`
+		Priority      string                    `json:"priority,omitempty"`
+		Cooldown      int                       `json:"cooldown_seconds,omitempty"`
+		AckRequired   bool                      `json:"ack_required,omitempty"`
+		AckInterval   int                       `json:"ack_interval_minutes,omitempty"`
+		MaxDataAge    int                       `json:"max_data_age_seconds,omitempty"`
+		WindowDays    int                       `json:"window_days,omitempty"`
+		Enabled       *bool                     `json:"enabled,omitempty"`
+		SetID         string                    `json:"set_id,omitempty"`
+		DependsOnID   string                    `json:"depends_on_id,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&alertData); err != nil {
@@ -116,39 +272,71 @@ func (h *AlertHandler) CreateAlert(c *gin.Context) {
 		return
 	}
 
-	// Validate condition type
-	validConditions := map[string]bool{
-		"above": true, "below": true, "crosses_up": true, "crosses_down": true,
-	}
-	if !validConditions[alertData.ConditionType] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid condition type"})
-		return
-	}
-
 	// Set default notify_via if not provided
 	notifyVia := alertData.NotifyVia
 	if len(notifyVia) == 0 {
 		notifyVia = []string{"app"}
 	}
 
+	priority := alertData.Priority
+	if priority == "" {
+		priority = entities.DefaultAlertPriority
+	}
+
 	// Create alert
 	alert := &entities.Alert{
-		UserID:        userID.(uuid.UUID),
-		Symbol:        alertData.Symbol,
-		AlertType:     alertData.AlertType,
-		ConditionType: alertData.ConditionType,
-		TargetValue:   alertData.TargetValue,
-		Timeframe:     alertData.Timeframe,
-		Enabled:       alertData.Enabled == nil || *alertData.Enabled,
-		NotifyVia:     notifyVia,
+		UserID:             userID.(uuid.UUID),
+		Symbol:             alertData.Symbol,
+		AlertType:          alertData.AlertType,
+		ConditionType:      alertData.ConditionType,
+		TargetValue:        alertData.TargetValue,
+		Timeframe:          alertData.Timeframe,
+		Enabled:            alertData.Enabled == nil || *alertData.Enabled,
+		NotifyVia:          notifyVia,
+		Priority:           priority,
+		CooldownSeconds:    cooldownOrDefault(alertData.Cooldown),
+		AckRequired:        alertData.AckRequired,
+		AckIntervalMinutes: alertData.AckInterval,
+		MaxDataAgeSeconds:  alertData.MaxDataAge,
+		WindowDays:         alertData.WindowDays,
+	}
+	alert.ReplaceConditions(alertData.Conditions)
+
+	if err := alert.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if !h.checkBasketAlert(c, alert) || !h.checkConsolidatedAlert(c, alert) {
+		return
+	}
+
+	setID, ok := h.alertSetID(c, alert.UserID, alertData.SetID)
+	if !ok {
+		return
+	}
+	alert.SetID = setID
+
+	// New alerts have no ID yet, so they can't be part of a cycle
+	dependsOnID, ok := h.alertDependency(c, alert, alertData.DependsOnID)
+	if !ok {
+		return
+	}
+	alert.DependsOnID = dependsOnID
+
+	if h.restrictions != nil {
+		if err := h.restrictions.CheckNewAlert(c.Request.Context(), alert.Symbol); err != nil {
+			respondError(c, err, "Failed to create alert")
+			return
+		}
 	}
 
 	if err := h.alertRepo.Create(c.Request.Context(), alert); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create alert"})
 		return
 	}
+	h.publishAlertLevels(c, alert)
 
-	c.JSON(http.StatusCreated, alert)
+	c.JSON(http.StatusCreated, alertWithDisplay(displayFormatter(c, h.settingsRepo), alert))
 }
 
 // UpdateAlert godoc
@@ -164,6 +352,7 @@ func (h *AlertHandler) CreateAlert(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "Alert not found"
+// @Failure 409 {object} map[string]interface{} "Symbol blacklisted"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/alerts/{id} [put]
 func (h *AlertHandler) UpdateAlert(c *gin.Context) {
@@ -194,12 +383,24 @@ func (h *AlertHandler) UpdateAlert(c *gin.Context) {
 	}
 
 	var updateData struct {
-		AlertType     *string   `json:"alert_type,omitempty"`
-		ConditionType *string   `json:"condition_type,omitempty"`
-		TargetValue   *float64  `json:"target_value,omitempty"`
-		Timeframe     *string   `json:"timeframe,omitempty"`
-		NotifyVia     *[]string `json:"notify_via,omitempty"`
-		Enabled       *bool     `json:"enabled,omitempty"`
+		AlertType     *string                    `json:"alert_type,omitempty"`
+		ConditionType *string                    `json:"condition_type,omitempty"`
+		TargetValue   *float64                   `json:"target_value,omitempty"`
+		Timeframe     *string                    `json:"timeframe,omitempty"`
+		Conditions    *[]entities.AlertCondition `json:"conditions,omitempty"`
+		NotifyVia     *[]string                  `json:"notify_via,omitempty"`
+		Priority      *string                    `json:"priority,omitempty"`
+		Cooldown      *int                       `json:"cooldown_seconds,omitempty"`
+		AckRequired   *bool                      `json:"ack_required,omitempty"`
+		AckInterval   *int                       `json:"ack_interval_minutes,omitempty"`
+		MaxDataAge    *int                       `json:"max_data_age_seconds,omitempty"`
+		WindowDays    *int                       `json:"window_days,omitempty"`
+		Enabled       *bool                      `json:"enabled,omitempty"`
+		// SetID moves the alert to another of the user's sets; empty takes it out of its set
+		SetID *string `json:"set_id,omitempty"`
+		// DependsOnID chains the alert after another of the user's alerts, disarming
+		// it until that one triggers; empty removes the dependency
+		DependsOnID *string `json:"depends_on_id,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -220,19 +421,83 @@ func (h *AlertHandler) UpdateAlert(c *gin.Context) {
 	if updateData.Timeframe != nil {
 		alert.Timeframe = *updateData.Timeframe
 	}
+	if updateData.Conditions != nil {
+		alert.ReplaceConditions(*updateData.Conditions)
+	} else if !alert.IsComposite() {
+		// Switching away from a composite alert drops its conditions
+		alert.Conditions = nil
+	}
 	if updateData.NotifyVia != nil {
 		alert.NotifyVia = *updateData.NotifyVia
 	}
+	if updateData.Priority != nil {
+		alert.Priority = *updateData.Priority
+	}
+	if updateData.Cooldown != nil {
+		alert.CooldownSeconds = cooldownOrDefault(*updateData.Cooldown)
+	}
+	if updateData.AckRequired != nil {
+		alert.AckRequired = *updateData.AckRequired
+		if !alert.AckRequired {
+			// Nothing left to acknowledge once acknowledgements are no longer required
+			alert.AwaitingAckSince = nil
+		}
+	}
+	if updateData.AckInterval != nil {
+		alert.AckIntervalMinutes = *updateData.AckInterval
+	}
+	if updateData.MaxDataAge != nil {
+		alert.MaxDataAgeSeconds = *updateData.MaxDataAge
+	}
+	if updateData.WindowDays != nil {
+		alert.WindowDays = *updateData.WindowDays
+	}
+	wasEnabled := alert.Enabled
 	if updateData.Enabled != nil {
 		alert.Enabled = *updateData.Enabled
 	}
 
+	if err := alert.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if !h.checkBasketAlert(c, alert) || !h.checkConsolidatedAlert(c, alert) {
+		return
+	}
+
+	if updateData.SetID != nil {
+		setID, ok := h.alertSetID(c, alert.UserID, *updateData.SetID)
+		if !ok {
+			return
+		}
+		alert.SetID = setID
+	}
+
+	if updateData.DependsOnID != nil {
+		dependsOnID, ok := h.alertDependency(c, alert, *updateData.DependsOnID)
+		if !ok {
+			return
+		}
+		if !sameAlertID(alert.DependsOnID, dependsOnID) {
+			alert.DependsOnID = dependsOnID
+			alert.ArmedAt = nil
+		}
+	}
+
+	if h.restrictions != nil && alert.Enabled && !wasEnabled {
+		if err := h.restrictions.CheckEnableAlert(c.Request.Context(), alert.Symbol); err != nil {
+			respondError(c, err, "Failed to update alert")
+			return
+		}
+	}
+
 	if err := h.alertRepo.Update(c.Request.Context(), alert); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update alert"})
 		return
 	}
+	h.publishAlertLevels(c, alert)
 
-	c.JSON(http.StatusOK, alert)
+	c.JSON(http.StatusOK, alertWithDisplay(displayFormatter(c, h.settingsRepo), alert))
 }
 
 // DeleteAlert godoc
@@ -280,6 +545,7 @@ func (h *AlertHandler) DeleteAlert(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete alert"})
 		return
 	}
+	h.publishAlertLevels(c, alert)
 
 	c.Status(http.StatusNoContent)
 }
@@ -353,6 +619,7 @@ func (h *AlertHandler) TriggerEvaluation(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "Alert not found"
+// @Failure 422 {object} map[string]interface{} "Market data is stale"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/alerts/{id}/evaluate [post]
 func (h *AlertHandler) EvaluateAlert(c *gin.Context) {
@@ -384,6 +651,10 @@ func (h *AlertHandler) EvaluateAlert(c *gin.Context) {
 
 	// Evaluate the alert
 	result, err := h.alertEngine.EvaluateAlert(c.Request.Context(), alert)
+	if errors.Is(err, entities.ErrStaleData) {
+		respondError(c, err, "Market data is stale")
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate alert", "details": err.Error()})
 		return
@@ -396,6 +667,70 @@ func (h *AlertHandler) EvaluateAlert(c *gin.Context) {
 	})
 }
 
+// GetAlertChartContext godoc
+// @Summary Get alert chart context
+// @Description Get recent candles, the indicator series the condition uses and the target level of an alert, sized for a sparkline
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Param points query int false "Number of candles (max 200)" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Alert not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 503 {object} map[string]interface{} "Chart context not available"
+// @Router /api/alerts/{id}/chart-context [get]
+func (h *AlertHandler) GetAlertChartContext(c *gin.Context) {
+	if h.alertCharts == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Chart context not available"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	points, err := strconv.Atoi(c.DefaultQuery("points", strconv.Itoa(services.DefaultChartPoints)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid points"})
+		return
+	}
+
+	alert, err := h.alertRepo.GetByID(c.Request.Context(), alertID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alert not found"})
+		return
+	}
+
+	if alert.UserID != userID.(uuid.UUID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	chart, err := h.alertCharts.GetChartContext(c.Request.Context(), alert, points)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chart context", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alert_id": alertID,
+		"data":     chart,
+	})
+}
+
 // GetAlertTypes godoc
 // @Summary Get available alert types and conditions
 // @Description Get list of available alert types and their supported conditions
@@ -407,8 +742,8 @@ func (h *AlertHandler) EvaluateAlert(c *gin.Context) {
 func (h *AlertHandler) GetAlertTypes(c *gin.Context) {
 	alertTypes := map[string]interface{}{
 		"price": map[string]interface{}{
-			"description":    "Price-based alerts",
-			"conditions":     []string{"above", "below"},
+			"description":    "Price-based alerts; crosses conditions trigger when the close moves past the target",
+			"conditions":     []string{"above", "below", "crosses_up", "crosses_down"},
 			"example_target": 50000.0,
 		},
 		"percentage": map[string]interface{}{
@@ -416,6 +751,11 @@ func (h *AlertHandler) GetAlertTypes(c *gin.Context) {
 			"conditions":     []string{"up", "down"},
 			"example_target": 5.0,
 		},
+		"change_24h": map[string]interface{}{
+			"description":    "Alerts on the exchange-reported 24h percentage change, refreshed every minute by default",
+			"conditions":     []string{"up", "down"},
+			"example_target": 5.0,
+		},
 		"rsi": map[string]interface{}{
 			"description":    "RSI indicator alerts",
 			"conditions":     []string{"above", "below"},
@@ -431,15 +771,85 @@ func (h *AlertHandler) GetAlertTypes(c *gin.Context) {
 			"conditions":     []string{"up", "down"},
 			"example_target": 20.0,
 		},
+		"macd_cross": map[string]interface{}{
+			"description":    "MACD(12,26,9) signal line crossover alerts",
+			"conditions":     []string{"up", "down"},
+			"example_target": 1.0,
+		},
+		"ichimoku_cloud": map[string]interface{}{
+			"description":    "Ichimoku(9,26,52) alerts on the close entering or leaving the cloud; the target is not used",
+			"conditions":     []string{"enters", "exits", "exits_above", "exits_below"},
+			"example_target": 0.0,
+		},
+		"volume": map[string]interface{}{
+			"description":    "Candle volume alerts; spike targets are multiples of the 20-candle average",
+			"conditions":     []string{"above", "below", "spike"},
+			"example_target": 3.0,
+		},
+		"composite": map[string]interface{}{
+			"description": "Alerts combining up to 10 conditions of the other types, in groups nested up to 3 deep",
+			"conditions":  []string{"and", "or"},
+			"example_conditions": []map[string]interface{}{
+				{"alert_type": "price", "condition_type": "above", "target_value": 50000.0},
+				{"alert_type": "rsi", "condition_type": "below", "target_value": 30.0},
+			},
+		},
 	}
 
-	timeframes := []string{"1m", "5m", "15m", "1h", "4h", "1d"}
-
-	notificationChannels := []string{"app", "email", "push", "sms"}
-
 	c.JSON(http.StatusOK, gin.H{
 		"alert_types":           alertTypes,
-		"supported_timeframes":  timeframes,
-		"notification_channels": notificationChannels,
+		"supported_timeframes":  entities.Timeframes,
+		"notification_channels": entities.NotificationChannels,
 	})
 }
+
+// AcknowledgeAlert godoc
+// @Summary Acknowledge alert
+// @Description Acknowledge the trigger of an acknowledgement-required alert, stopping its re-notifications
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Success 200 {object} entities.Alert
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert not found"
+// @Failure 409 {object} map[string]interface{} "Alert not awaiting acknowledgement"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 503 {object} map[string]interface{} "Acknowledgements not available"
+// @Router /api/alerts/{id}/acknowledge [post]
+func (h *AlertHandler) AcknowledgeAlert(c *gin.Context) {
+	if h.escalations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Acknowledgements not available"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	alert, err := h.escalations.Acknowledge(c.Request.Context(), userID.(uuid.UUID), alertID)
+	if err != nil {
+		respondError(c, err, "Failed to acknowledge alert")
+		return
+	}
+
+	c.JSON(http.StatusOK, alertWithDisplay(displayFormatter(c, h.settingsRepo), alert))
+}
+
+// sameAlertID reports whether two optional alert IDs are equal
+func sameAlertID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
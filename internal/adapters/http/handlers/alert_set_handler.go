@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type AlertSetHandler struct {
+	alertSetService *services.AlertSetService
+}
+
+// NewAlertSetHandler creates a new alert set handler
+func NewAlertSetHandler(alertSetService *services.AlertSetService) *AlertSetHandler {
+	return &AlertSetHandler{
+		alertSetService: alertSetService,
+	}
+}
+
+type createAlertSetRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type updateAlertSetRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ListAlertSets godoc
+// @Summary List alert sets
+// @Description List the authenticated user's alert sets ordered by name, with the number of alerts, enabled and triggered ones and their symbols
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets [get]
+func (h *AlertSetHandler) ListAlertSets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sets, err := h.alertSetService.ListSets(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch alert sets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  sets,
+		"count": len(sets),
+	})
+}
+
+// CreateAlertSet godoc
+// @Summary Create an alert set
+// @Description Create an empty named set, such as "BTC swing plan"; alerts join it through their set_id
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body createAlertSetRequest true "Set name and description"
+// @Success 201 {object} entities.AlertSet
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Name already used"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets [post]
+func (h *AlertSetHandler) CreateAlertSet(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req createAlertSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	set, err := h.alertSetService.CreateSet(c.Request.Context(), userID.(uuid.UUID), req.Name, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to create alert set")
+		return
+	}
+
+	c.JSON(http.StatusCreated, set)
+}
+
+// GetAlertSet godoc
+// @Summary Get an alert set
+// @Description Get one of the authenticated user's alert sets with its stats and alerts
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert set ID"
+// @Success 200 {object} services.AlertSetDetails
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert set not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets/{id} [get]
+func (h *AlertSetHandler) GetAlertSet(c *gin.Context) {
+	userID, id, ok := alertSetParams(c)
+	if !ok {
+		return
+	}
+
+	set, err := h.alertSetService.GetSet(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, err, "Failed to fetch alert set")
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// UpdateAlertSet godoc
+// @Summary Update an alert set
+// @Description Rename or redescribe one of the authenticated user's alert sets
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert set ID"
+// @Param request body updateAlertSetRequest true "Fields to change"
+// @Success 200 {object} entities.AlertSet
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert set not found"
+// @Failure 409 {object} map[string]interface{} "Name already used"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets/{id} [put]
+func (h *AlertSetHandler) UpdateAlertSet(c *gin.Context) {
+	userID, id, ok := alertSetParams(c)
+	if !ok {
+		return
+	}
+
+	var req updateAlertSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	set, err := h.alertSetService.UpdateSet(c.Request.Context(), userID, id, services.AlertSetUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to update alert set")
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+// DeleteAlertSet godoc
+// @Summary Delete an alert set
+// @Description Delete one of the authenticated user's alert sets together with its alerts, or keep the alerts outside any set with keep_alerts
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert set ID"
+// @Param keep_alerts query bool false "Keep the set's alerts" default(false)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert set not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets/{id} [delete]
+func (h *AlertSetHandler) DeleteAlertSet(c *gin.Context) {
+	userID, id, ok := alertSetParams(c)
+	if !ok {
+		return
+	}
+
+	keepAlerts, err := strconv.ParseBool(c.DefaultQuery("keep_alerts", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keep_alerts"})
+		return
+	}
+
+	alerts, err := h.alertSetService.DeleteSet(c.Request.Context(), userID, id, keepAlerts)
+	if err != nil {
+		respondError(c, err, "Failed to delete alert set")
+		return
+	}
+
+	response := gin.H{"message": "Alert set deleted"}
+	if keepAlerts {
+		response["alerts_kept"] = alerts
+	} else {
+		response["alerts_deleted"] = alerts
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// EnableAlertSet godoc
+// @Summary Enable an alert set
+// @Description Enable every alert of one of the authenticated user's alert sets; alerts on blacklisted symbols stay disabled and are counted as skipped
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert set ID"
+// @Success 200 {object} services.AlertSetToggleResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert set not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets/{id}/enable [post]
+func (h *AlertSetHandler) EnableAlertSet(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+// DisableAlertSet godoc
+// @Summary Disable an alert set
+// @Description Disable every alert of one of the authenticated user's alert sets
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert set ID"
+// @Success 200 {object} services.AlertSetToggleResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Alert set not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/alert-sets/{id}/disable [post]
+func (h *AlertSetHandler) DisableAlertSet(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+func (h *AlertSetHandler) setEnabled(c *gin.Context, enabled bool) {
+	userID, id, ok := alertSetParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.alertSetService.SetEnabled(c.Request.Context(), userID, id, enabled)
+	if err != nil {
+		respondError(c, err, "Failed to update alert set")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// alertSetParams reads the authenticated user and the set ID from the path,
+// responding and reporting false when either is missing or invalid
+func alertSetParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert set ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID.(uuid.UUID), id, true
+}
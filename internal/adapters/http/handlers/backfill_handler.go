@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type BackfillHandler struct {
+	backfillService *services.BackfillService
+}
+
+// NewBackfillHandler creates a new backfill handler
+func NewBackfillHandler(backfillService *services.BackfillService) *BackfillHandler {
+	return &BackfillHandler{
+		backfillService: backfillService,
+	}
+}
+
+// BackfillRequest is the payload for backfilling a symbol's candles
+type BackfillRequest struct {
+	Symbol    string    `json:"symbol" binding:"required"`
+	Timeframe string    `json:"timeframe" binding:"required"`
+	From      time.Time `json:"from" binding:"required"` // RFC 3339
+	To        time.Time `json:"to" binding:"required"`   // RFC 3339
+}
+
+// Backfill godoc
+// @Summary Backfill historical candles
+// @Description Fetch a symbol's klines between from and to from Binance and store the candles missing; at most 20000 candles per request (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BackfillRequest true "Symbol, timeframe and range"
+// @Success 200 {object} services.BackfillResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 503 {object} map[string]interface{} "Market data provider unavailable"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/backfill [post]
+func (h *BackfillHandler) Backfill(c *gin.Context) {
+	var req BackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	result, err := h.backfillService.Backfill(c.Request.Context(), services.BackfillRequest{
+		Symbol:    strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Timeframe: req.Timeframe,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to backfill candles")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
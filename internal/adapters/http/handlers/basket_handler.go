@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+type BasketHandler struct {
+	basketService *services.BasketService
+}
+
+// NewBasketHandler creates a new symbol basket handler
+func NewBasketHandler(basketService *services.BasketService) *BasketHandler {
+	return &BasketHandler{
+		basketService: basketService,
+	}
+}
+
+type basketComponentRequest struct {
+	Symbol string  `json:"symbol" binding:"required"`
+	Weight float64 `json:"weight" binding:"required"`
+}
+
+type createBasketRequest struct {
+	Name       string                   `json:"name" binding:"required"`
+	Components []basketComponentRequest `json:"components" binding:"required"`
+}
+
+type renameBasketRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ListBaskets godoc
+// @Summary List baskets
+// @Description List the authenticated user's symbol baskets ordered by name, with their latest values
+// @Tags Baskets
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets [get]
+func (h *BasketHandler) ListBaskets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	baskets, err := h.basketService.ListBaskets(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch baskets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  baskets,
+		"count": len(baskets),
+	})
+}
+
+// CreateBasket godoc
+// @Summary Create a basket
+// @Description Create a weighted basket of symbols, e.g. 50% BTCUSDT, 30% ETHUSDT and 20% SOLUSDT. Its value starts at 100 and follows the weighted returns of its symbols; alerts watch it through its symbol.
+// @Tags Baskets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body createBasketRequest true "Basket name and components, weighted in percent"
+// @Success 201 {object} entities.Basket
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets [post]
+func (h *BasketHandler) CreateBasket(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req createBasketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	components := make([]entities.BasketComponent, len(req.Components))
+	for i, component := range req.Components {
+		components[i] = entities.BasketComponent{Symbol: component.Symbol, Weight: component.Weight}
+	}
+
+	basket, err := h.basketService.CreateBasket(c.Request.Context(), userID.(uuid.UUID), req.Name, components)
+	if err != nil {
+		respondError(c, err, "Failed to create basket")
+		return
+	}
+
+	c.JSON(http.StatusCreated, basket)
+}
+
+// GetBasket godoc
+// @Summary Get a basket
+// @Description Get one of the authenticated user's baskets with its latest value
+// @Tags Baskets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Basket ID"
+// @Success 200 {object} services.BasketValue
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Basket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets/{id} [get]
+func (h *BasketHandler) GetBasket(c *gin.Context) {
+	userID, id, ok := basketParams(c)
+	if !ok {
+		return
+	}
+
+	basket, err := h.basketService.GetBasket(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, err, "Failed to fetch basket")
+		return
+	}
+
+	c.JSON(http.StatusOK, basket)
+}
+
+// RenameBasket godoc
+// @Summary Rename a basket
+// @Description Rename one of the authenticated user's baskets; the components of a basket can't change
+// @Tags Baskets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Basket ID"
+// @Param request body renameBasketRequest true "New name"
+// @Success 200 {object} entities.Basket
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Basket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets/{id} [put]
+func (h *BasketHandler) RenameBasket(c *gin.Context) {
+	userID, id, ok := basketParams(c)
+	if !ok {
+		return
+	}
+
+	var req renameBasketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	basket, err := h.basketService.RenameBasket(c.Request.Context(), userID, id, req.Name)
+	if err != nil {
+		respondError(c, err, "Failed to update basket")
+		return
+	}
+
+	c.JSON(http.StatusOK, basket)
+}
+
+// DeleteBasket godoc
+// @Summary Delete a basket
+// @Description Delete one of the authenticated user's baskets together with the user's alerts on it
+// @Tags Baskets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Basket ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Basket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets/{id} [delete]
+func (h *BasketHandler) DeleteBasket(c *gin.Context) {
+	userID, id, ok := basketParams(c)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.basketService.DeleteBasket(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, err, "Failed to delete basket")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Basket deleted",
+		"alerts_deleted": alerts,
+	})
+}
+
+// GetBasketHistory godoc
+// @Summary Get basket history
+// @Description Get the latest computed values of one of the authenticated user's baskets as candles, oldest first, for charting
+// @Tags Baskets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Basket ID"
+// @Param timeframe query string false "Timeframe" default(1m)
+// @Param limit query int false "Number of candles (max 500)" default(100)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Basket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/baskets/{id}/history [get]
+func (h *BasketHandler) GetBasketHistory(c *gin.Context) {
+	userID, id, ok := basketParams(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	timeframe := c.DefaultQuery("timeframe", "1m")
+
+	history, err := h.basketService.GetHistory(c.Request.Context(), userID, id, timeframe, limit)
+	if err != nil {
+		respondError(c, err, "Failed to fetch basket history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timeframe": timeframe,
+		"data":      history,
+		"count":     len(history),
+	})
+}
+
+// basketParams reads the authenticated user and the basket ID from the path,
+// responding and reporting false when either is missing or invalid
+func basketParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid basket ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID.(uuid.UUID), id, true
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
 )
@@ -14,6 +20,14 @@ type CryptoHandler struct {
 	cryptoRepo    repositories.CryptoCurrencyRepository
 	priceHistRepo repositories.PriceHistoryRepository
 	techRepo      repositories.TechnicalIndicatorRepository
+	detailService *services.CryptoDetailService
+	searchService *services.SymbolSearchService
+	settingsRepo  repositories.UserSettingsRepository
+	correlation   *services.CorrelationService
+	ohlcv         *services.OHLCVService
+	aggregation   *services.PriceAggregationService
+	summaries     *services.PriceSummaryService
+	orderBooks    *services.OrderBookService
 }
 
 // NewCryptoHandler creates a new crypto handler
@@ -29,9 +43,56 @@ func NewCryptoHandler(
 	}
 }
 
+// SetDetailService enables the include query of the crypto detail endpoint
+func (h *CryptoHandler) SetDetailService(detailService *services.CryptoDetailService) {
+	h.detailService = detailService
+}
+
+// SetSearchService enables the symbol search endpoint
+func (h *CryptoHandler) SetSearchService(searchService *services.SymbolSearchService) {
+	h.searchService = searchService
+}
+
+// SetUserSettingsRepo enables listing the user's favorite symbols first in GetCryptoData
+func (h *CryptoHandler) SetUserSettingsRepo(settingsRepo repositories.UserSettingsRepository) {
+	h.settingsRepo = settingsRepo
+}
+
+// SetCorrelationService enables the correlation matrix endpoint
+func (h *CryptoHandler) SetCorrelationService(correlation *services.CorrelationService) {
+	h.correlation = correlation
+}
+
+// SetOHLCVService enables the resampled candles endpoint
+func (h *CryptoHandler) SetOHLCVService(ohlcv *services.OHLCVService) {
+	h.ohlcv = ohlcv
+}
+
+// SetPriceAggregationService enables the consolidated price endpoint
+func (h *CryptoHandler) SetPriceAggregationService(aggregation *services.PriceAggregationService) {
+	h.aggregation = aggregation
+}
+
+// SetPriceSummaryService enables the daily summaries endpoint
+func (h *CryptoHandler) SetPriceSummaryService(summaries *services.PriceSummaryService) {
+	h.summaries = summaries
+}
+
+// SetOrderBookService enables the order book endpoint
+func (h *CryptoHandler) SetOrderBookService(orderBooks *services.OrderBookService) {
+	h.orderBooks = orderBooks
+}
+
+// cryptoDetailResponse flattens the optional sections next to the cryptocurrency fields
+type cryptoDetailResponse struct {
+	*entities.CryptoCurrency
+	*services.CryptoDetailExtras
+}
+
 // GetCryptoData godoc
 // @Summary Get cryptocurrency data
-// @Description Get list of cryptocurrencies with optional filtering
+// @Description Get list of cryptocurrencies with optional filtering. The user's favorites come first
+// @Description within each page, in their chosen order, and are listed with their labels under "favorites".
 // @Tags Crypto
 // @Accept json
 // @Produce json
@@ -74,11 +135,37 @@ func (h *CryptoHandler) GetCryptoData(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"data":   cryptos,
 		"limit":  limit,
 		"offset": offset,
 		"count":  len(cryptos),
+	}
+
+	// Favorites first; settings are optional, so a missing row just keeps the default order
+	if h.settingsRepo != nil {
+		if userID, exists := c.Get("user_id"); exists {
+			if settings, err := h.settingsRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID)); err == nil {
+				sortFavoritesFirst(cryptos, settings)
+				response["favorites"] = settings.Favorites()
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sortFavoritesFirst moves favorite symbols to the front in the user's order and
+// keeps the repository order for the rest
+func sortFavoritesFirst(cryptos []entities.CryptoCurrency, settings *entities.UserSettings) {
+	rank := func(crypto entities.CryptoCurrency) int {
+		if position, ok := settings.FavoritePosition(crypto.Symbol); ok {
+			return position
+		}
+		return len(settings.FavoriteSymbols)
+	}
+	sort.SliceStable(cryptos, func(i, j int) bool {
+		return rank(cryptos[i]) < rank(cryptos[j])
 	})
 }
 
@@ -90,7 +177,10 @@ func (h *CryptoHandler) GetCryptoData(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param symbol path string true "Cryptocurrency symbol"
+// @Param include query string false "Sections to embed: indicators, stats, alerts (comma separated)"
+// @Param timeframe query string false "Timeframe of the indicator snapshot" default("1h")
 // @Success 200 {object} entities.CryptoCurrency
+// @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "Cryptocurrency not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -102,13 +192,54 @@ func (h *CryptoHandler) GetCryptoDetail(c *gin.Context) {
 		return
 	}
 
+	opts, err := services.ParseDetailIncludes(c.Query("include"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Timeframe = c.DefaultQuery("timeframe", "1h")
+
+	// Validate timeframe
+	validTimeframes := map[string]bool{
+		"1m": true, "5m": true, "15m": true, "30m": true,
+		"1h": true, "4h": true, "1d": true, "1w": true,
+	}
+	if !validTimeframes[opts.Timeframe] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timeframe"})
+		return
+	}
+
 	crypto, err := h.cryptoRepo.GetBySymbol(c.Request.Context(), symbol)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cryptocurrency not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, crypto)
+	if !opts.Indicators && !opts.Stats && !opts.Alerts {
+		c.JSON(http.StatusOK, crypto)
+		return
+	}
+	if h.detailService == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include is not supported"})
+		return
+	}
+
+	if opts.Alerts {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		opts.UserID = userID.(uuid.UUID)
+	}
+
+	extras, err := h.detailService.GetDetailExtras(c.Request.Context(), crypto.Symbol, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cryptocurrency details"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cryptoDetailResponse{CryptoCurrency: crypto, CryptoDetailExtras: extras})
 }
 
 // GetPriceHistory godoc
@@ -121,6 +252,7 @@ func (h *CryptoHandler) GetCryptoDetail(c *gin.Context) {
 // @Param symbol path string true "Cryptocurrency symbol"
 // @Param timeframe query string false "Timeframe (1m, 5m, 15m, 1h, 4h, 1d)" default("1h")
 // @Param limit query int false "Limit number of results" default(100)
+// @Param format query string false "Response format: full, or compact for one [timestamp_ms, open, high, low, close, volume] array per candle" default("full")
 // @Success 200 {array} entities.PriceHistory
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 400 {object} map[string]interface{} "Bad request"
@@ -135,6 +267,11 @@ func (h *CryptoHandler) GetPriceHistory(c *gin.Context) {
 
 	timeframe := c.DefaultQuery("timeframe", "1h")
 	limitStr := c.DefaultQuery("limit", "100")
+	format := c.DefaultQuery("format", historyFormatFull)
+	if format != historyFormatFull && format != historyFormatCompact {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected full or compact"})
+		return
+	}
 
 	limit, _ := strconv.Atoi(limitStr)
 	if limit > 1000 {
@@ -160,6 +297,18 @@ func (h *CryptoHandler) GetPriceHistory(c *gin.Context) {
 		return
 	}
 
+	if format == historyFormatCompact {
+		c.JSON(http.StatusOK, gin.H{
+			"symbol":    symbol,
+			"timeframe": timeframe,
+			"format":    historyFormatCompact,
+			"columns":   compactHistoryColumns,
+			"data":      compactPriceHistory(history),
+			"count":     len(history),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"symbol":    symbol,
 		"timeframe": timeframe,
@@ -168,6 +317,112 @@ func (h *CryptoHandler) GetPriceHistory(c *gin.Context) {
 	})
 }
 
+// GetOHLCV godoc
+// @Summary Get candles of any timeframe
+// @Description Resample the stored 1m candles of a symbol into any timeframe, such as 2h, 3d or 90m, over a range of at most 31 days. Buckets are aligned to the Unix epoch and from is aligned down to the start of its candle; the last candle is still forming when to falls in it.
+// @Tags Crypto
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param timeframe query string false "A number of minutes, hours, days or weeks, e.g. 2h" default("1h")
+// @Param from query string false "Start, RFC 3339 or Unix milliseconds; defaults to 200 candles before to"
+// @Param to query string false "End, RFC 3339 or Unix milliseconds; defaults to now"
+// @Param format query string false "Response format: full, or compact for one [timestamp_ms, open, high, low, close, volume] array per candle" default("full")
+// @Success 200 {object} services.OHLCVResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/crypto/ohlcv/{symbol} [get]
+func (h *CryptoHandler) GetOHLCV(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if symbol == "" || len(symbol) > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid symbol"})
+		return
+	}
+	format := c.DefaultQuery("format", historyFormatFull)
+	if format != historyFormatFull && format != historyFormatCompact {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected full or compact"})
+		return
+	}
+
+	req := services.OHLCVRequest{Symbol: symbol, Timeframe: c.DefaultQuery("timeframe", "1h")}
+	var err error
+	if req.From, err = parseTimeQuery(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 or Unix milliseconds"})
+		return
+	}
+	if req.To, err = parseTimeQuery(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 or Unix milliseconds"})
+		return
+	}
+
+	result, err := h.ohlcv.GetCandles(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Failed to resample candles")
+		return
+	}
+
+	if format == historyFormatCompact {
+		c.JSON(http.StatusOK, gin.H{
+			"symbol":    result.Symbol,
+			"timeframe": result.Timeframe,
+			"from":      result.From,
+			"to":        result.To,
+			"format":    historyFormatCompact,
+			"columns":   compactHistoryColumns,
+			"data":      compactPriceHistory(result.Candles),
+			"count":     len(result.Candles),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":    result.Symbol,
+		"timeframe": result.Timeframe,
+		"from":      result.From,
+		"to":        result.To,
+		"data":      result.Candles,
+		"count":     len(result.Candles),
+	})
+}
+
+// parseTimeQuery reads an RFC 3339 time or Unix milliseconds; empty gives the zero time
+func parseTimeQuery(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Price history response formats
+const (
+	historyFormatFull    = "full"
+	historyFormatCompact = "compact"
+)
+
+// compactHistoryColumns names the values of each compact candle, in order
+var compactHistoryColumns = []string{"timestamp", "open", "high", "low", "close", "volume"}
+
+// compactPriceHistory encodes candles as arrays of numbers with a millisecond timestamp,
+// dropping the repeated keys, symbol and timeframe of the full format
+func compactPriceHistory(history []entities.PriceHistory) [][]float64 {
+	rows := make([][]float64, len(history))
+	for i, candle := range history {
+		rows[i] = []float64{
+			float64(candle.Timestamp.UnixMilli()),
+			candle.OpenPrice,
+			candle.HighPrice,
+			candle.LowPrice,
+			candle.ClosePrice,
+			candle.Volume,
+		}
+	}
+	return rows
+}
+
 // GetTechnicalIndicators godoc
 // @Summary Get technical indicators
 // @Description Get technical indicators for a cryptocurrency
@@ -177,7 +432,7 @@ func (h *CryptoHandler) GetPriceHistory(c *gin.Context) {
 // @Security BearerAuth
 // @Param symbol path string true "Cryptocurrency symbol"
 // @Param timeframe query string false "Timeframe (1m, 5m, 15m, 1h, 4h, 1d)" default("1h")
-// @Param indicator_type query string false "Indicator type (RSI, EMA, SMA, SuperTrend)" default("RSI")
+// @Param indicator_type query string false "Indicator type (RSI, EMA, SMA, SuperTrend, ADX)" default("RSI")
 // @Param limit query int false "Limit number of results" default(50)
 // @Success 200 {array} entities.TechnicalIndicator
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -216,7 +471,7 @@ func (h *CryptoHandler) GetTechnicalIndicators(c *gin.Context) {
 	// Validate indicator type
 	validIndicators := map[string]bool{
 		"RSI": true, "EMA": true, "SMA": true, "SuperTrend": true,
-		"MACD": true, "BB": true, "Stochastic": true,
+		"MACD": true, "BB": true, "Stochastic": true, "ADX": true,
 	}
 	if !validIndicators[indicatorType] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid indicator type"})
@@ -237,3 +492,198 @@ func (h *CryptoHandler) GetTechnicalIndicators(c *gin.Context) {
 		"count":          len(indicators),
 	})
 }
+
+// SearchSymbols godoc
+// @Summary Search symbols
+// @Description Autocomplete symbols by symbol or asset name, best matches first and ranked by 24h volume
+// @Tags Crypto
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text, e.g. btc"
+// @Param limit query int false "Limit number of results" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/crypto/search [get]
+func (h *CryptoHandler) SearchSymbols(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len(query) > 32 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be between 1 and 32 characters"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	matches, err := h.searchService.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search symbols"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=60")
+	c.JSON(http.StatusOK, gin.H{
+		"query": query,
+		"data":  matches,
+		"count": len(matches),
+	})
+}
+
+// GetCorrelation returns the pairwise return correlation of several symbols
+// @Summary Get correlation matrix
+// @Description Pearson correlation of close-to-close returns over the last window candles, for assessing portfolio diversification
+// @Tags Crypto
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param symbols query string true "Comma-separated symbols, e.g. BTCUSDT,ETHUSDT"
+// @Param timeframe query string false "Timeframe" default(1d)
+// @Param window query int false "Number of returns correlated" default(30)
+// @Success 200 {object} services.CorrelationMatrix
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/crypto/correlation [get]
+func (h *CryptoHandler) GetCorrelation(c *gin.Context) {
+	symbols := parseSymbolList(c.Query("symbols"))
+	if len(symbols) < 2 || len(symbols) > services.MaxCorrelationSymbols {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("symbols must list between 2 and %d distinct symbols", services.MaxCorrelationSymbols)})
+		return
+	}
+	for _, symbol := range symbols {
+		if len(symbol) > 20 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid symbol " + symbol})
+			return
+		}
+	}
+
+	timeframe := c.DefaultQuery("timeframe", "1d")
+	validTimeframes := map[string]bool{
+		"1m": true, "5m": true, "15m": true, "30m": true,
+		"1h": true, "4h": true, "1d": true, "1w": true,
+	}
+	if !validTimeframes[timeframe] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timeframe"})
+		return
+	}
+
+	window, err := strconv.Atoi(c.DefaultQuery("window", strconv.Itoa(services.DefaultCorrelationWindow)))
+	if err != nil || window < 2 || window > services.MaxCorrelationWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be between 2 and %d", services.MaxCorrelationWindow)})
+		return
+	}
+
+	matrix, err := h.correlation.Correlation(c.Request.Context(), symbols, timeframe, window)
+	if err != nil {
+		respondError(c, err, "Failed to compute correlation")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, matrix)
+}
+
+// parseSymbolList splits a comma-separated symbol list, upper-casing and dropping
+// blanks and duplicates while keeping the order given
+func parseSymbolList(raw string) []string {
+	seen := make(map[string]bool)
+	symbols := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		symbol := strings.ToUpper(strings.TrimSpace(part))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// GetConsolidatedPrice returns a symbol's price consolidated across exchanges
+// @Summary Get consolidated price
+// @Description Median or volume-weighted price of the symbol across the aggregated exchanges with a recent price of it, each exchange's price, the cheapest and dearest exchange and the spread between them. Alerts watch the consolidated price on the returned alert_symbol.
+// @Tags Crypto
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Symbol, e.g. BTCUSDT"
+// @Success 200 {object} services.ConsolidatedPrice
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Symbol not aggregated"
+// @Failure 422 {object} map[string]interface{} "No recent exchange price"
+// @Router /api/crypto/consolidated/{symbol} [get]
+func (h *CryptoHandler) GetConsolidatedPrice(c *gin.Context) {
+	if h.aggregation == nil || !h.aggregation.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price aggregation is not available"})
+		return
+	}
+
+	price, err := h.aggregation.GetConsolidatedPrice(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		respondError(c, err, "Failed to get consolidated price")
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+// GetDailySummaries returns a symbol's daily price summaries
+// @Summary Get daily price summaries
+// @Description Daily open, high, low, close, volume and volatility of the symbol over its last days, oldest first, compacted from its intraday candles. The current day's summary is updated as its candles close.
+// @Tags Crypto
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Symbol, e.g. BTCUSDT"
+// @Param days query int false "Days to return, today's included" default(30)
+// @Success 200 {array} entities.PriceSummary
+// @Failure 400 {object} map[string]interface{} "Invalid days"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No summaries for the symbol"
+// @Router /api/crypto/daily/{symbol} [get]
+func (h *CryptoHandler) GetDailySummaries(c *gin.Context) {
+	if h.summaries == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Daily summaries are not available"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(services.DefaultSummaryDays)))
+	if err != nil || days < 1 || days > services.MaxSummaryDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 1 and %d", services.MaxSummaryDays)})
+		return
+	}
+
+	summaries, err := h.summaries.GetDailySummaries(c.Request.Context(), strings.ToUpper(c.Param("symbol")), days)
+	if err != nil {
+		respondError(c, err, "Failed to get daily summaries")
+		return
+	}
+
+	c.JSON(http.StatusOK, summaries)
+}
+
+// GetOrderBook returns a symbol's order book
+// @Summary Get order book
+// @Description Best bids and asks of the symbol on Binance, best first, with the best bid and ask and the spread between them, kept up to date from the partial book depth stream. The orderbook:<symbol> WebSocket room streams the same book as it updates.
+// @Tags Crypto
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Symbol, e.g. BTCUSDT"
+// @Success 200 {object} services.OrderBook
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Symbol's order book not streamed"
+// @Failure 422 {object} map[string]interface{} "Order book is stale"
+// @Router /api/crypto/orderbook/{symbol} [get]
+func (h *CryptoHandler) GetOrderBook(c *gin.Context) {
+	if h.orderBooks == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order books are not available"})
+		return
+	}
+
+	book, err := h.orderBooks.GetOrderBook(c.Param("symbol"))
+	if err != nil {
+		respondError(c, err, "Failed to get order book")
+		return
+	}
+
+	c.JSON(http.StatusOK, book)
+}
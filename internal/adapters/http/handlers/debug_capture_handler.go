@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+const (
+	defaultCaptureDuration = 15 * time.Minute
+	maxCaptureDuration     = 24 * time.Hour
+)
+
+type DebugCaptureHandler struct {
+	capture *middleware.PayloadCapture
+}
+
+// NewDebugCaptureHandler creates a new debug capture handler
+func NewDebugCaptureHandler(capture *middleware.PayloadCapture) *DebugCaptureHandler {
+	return &DebugCaptureHandler{
+		capture: capture,
+	}
+}
+
+// CreateCaptureRuleRequest selects what to capture: a route pattern or path
+// prefix, or a single X-Request-ID. Duration is a Go duration such as "30m".
+type CreateCaptureRuleRequest struct {
+	Route     string `json:"route,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+}
+
+// CreateCaptureRule godoc
+// @Summary Start capturing payloads
+// @Description Capture redacted request/response bodies of a route or request ID until the rule expires (admin only, default 15m, at most 24h)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body CreateCaptureRuleRequest true "Capture rule"
+// @Success 201 {object} middleware.CaptureRule
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/admin/debug/capture-rules [post]
+func (h *DebugCaptureHandler) CreateCaptureRule(c *gin.Context) {
+	var req CreateCaptureRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	duration := defaultCaptureDuration
+	if req.Duration != "" {
+		parsed, err := time.ParseDuration(req.Duration)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration"})
+			return
+		}
+		duration = parsed
+	}
+	if duration > maxCaptureDuration {
+		duration = maxCaptureDuration
+	}
+
+	rule := middleware.CaptureRule{
+		Route:     req.Route,
+		RequestID: req.RequestID,
+		ExpiresAt: time.Now().Add(duration),
+	}
+	if value, exists := c.Get("user"); exists {
+		if user, ok := value.(*entities.User); ok {
+			rule.CreatedBy = user.Email
+		}
+	}
+
+	created, err := h.capture.AddRule(rule)
+	if err != nil {
+		if errors.Is(err, middleware.ErrInvalidCaptureRule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either route or request_id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create capture rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetCaptureRules godoc
+// @Summary List capture rules
+// @Description List the payload capture rules that haven't expired (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/admin/debug/capture-rules [get]
+func (h *DebugCaptureHandler) GetCaptureRules(c *gin.Context) {
+	rules := h.capture.Rules()
+	c.JSON(http.StatusOK, gin.H{
+		"data":  rules,
+		"count": len(rules),
+	})
+}
+
+// DeleteCaptureRule godoc
+// @Summary Stop capturing payloads
+// @Description Remove a payload capture rule before it expires (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Capture rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{} "Capture rule not found"
+// @Router /api/admin/debug/capture-rules/{id} [delete]
+func (h *DebugCaptureHandler) DeleteCaptureRule(c *gin.Context) {
+	if !h.capture.RemoveRule(c.Param("id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Capture rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Capture rule removed"})
+}
+
+// GetCaptures godoc
+// @Summary List captured payloads
+// @Description Get the captured request/response pairs, newest first, with tokens and personal data redacted (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/admin/debug/captures [get]
+func (h *DebugCaptureHandler) GetCaptures(c *gin.Context) {
+	captures := h.capture.Captures()
+	c.JSON(http.StatusOK, gin.H{
+		"data":  captures,
+		"count": len(captures),
+	})
+}
+
+// ClearCaptures godoc
+// @Summary Clear captured payloads
+// @Description Drop every captured request/response pair from the buffer (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/admin/debug/captures [delete]
+func (h *DebugCaptureHandler) ClearCaptures(c *gin.Context) {
+	h.capture.Clear()
+	c.JSON(http.StatusOK, gin.H{"message": "Captures cleared"})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// maxDevicesPerUser bounds the devices a push notification fans out to
+const maxDevicesPerUser = 20
+
+// devicePlatforms are the platforms a device token can be registered for
+var devicePlatforms = map[string]bool{"android": true, "ios": true, "web": true}
+
+type DeviceHandler struct {
+	deviceRepo repositories.DeviceTokenRepository
+}
+
+// NewDeviceHandler creates a new push device handler
+func NewDeviceHandler(deviceRepo repositories.DeviceTokenRepository) *DeviceHandler {
+	return &DeviceHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RegisterDeviceRequest is an FCM registration token to send push notifications to
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=4096"`
+	Platform string `json:"platform" binding:"required"`
+	Name     string `json:"name" binding:"max=100"`
+}
+
+// ListDevices godoc
+// @Summary List push devices
+// @Description List the devices registered for push notifications by the authenticated user
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/user/devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	devices, err := h.deviceRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get devices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": devices, "count": len(devices)})
+}
+
+// RegisterDevice godoc
+// @Summary Register push device
+// @Description Register an FCM registration token for push notifications. Registering a known token
+// @Description again updates it, and moves it to this user if another account had it.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param device body RegisterDeviceRequest true "Token, platform (android, ios, web) and optional name"
+// @Success 201 {object} entities.DeviceToken
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Too many devices"
+// @Router /api/user/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	if !devicePlatforms[req.Platform] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "platform must be android, ios or web"})
+		return
+	}
+
+	devices, err := h.deviceRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		return
+	}
+	known := false
+	for _, device := range devices {
+		if device.Token == req.Token {
+			known = true
+		}
+	}
+	if !known && len(devices) >= maxDevicesPerUser {
+		c.JSON(http.StatusConflict, gin.H{"error": "Too many devices registered, remove one first"})
+		return
+	}
+
+	device := &entities.DeviceToken{
+		UserID:   userID.(uuid.UUID),
+		Token:    req.Token,
+		Platform: req.Platform,
+		Name:     req.Name,
+	}
+	if err := h.deviceRepo.Register(c.Request.Context(), device); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// DeleteDevice godoc
+// @Summary Remove push device
+// @Description Stop sending push notifications to one of the authenticated user's devices
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Invalid device ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Device not found"
+// @Router /api/user/devices/{id} [delete]
+func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	if err := h.deviceRepo.Delete(c.Request.Context(), id, userID.(uuid.UUID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device removed"})
+}
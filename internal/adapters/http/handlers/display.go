@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/locale"
+)
+
+// displayFormatter returns the formatter of the display strings added to alert and
+// notification responses, or nil when the request gets raw numbers only. Users with a
+// locale in their settings always get display strings in it; others get them with
+// display=true, in the best locale of their Accept-Language header.
+func displayFormatter(c *gin.Context, settingsRepo repositories.UserSettingsRepository) *locale.Formatter {
+	if settingsRepo != nil {
+		if userID, ok := c.Get("user_id"); ok {
+			settings, err := settingsRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID))
+			if err == nil && settings.Locale != "" {
+				return locale.New(settings.Locale)
+			}
+		}
+	}
+
+	if display, _ := strconv.ParseBool(c.Query("display")); !display {
+		return nil
+	}
+	tag, _ := locale.Match(c.GetHeader("Accept-Language"))
+	return locale.New(tag)
+}
+
+// alertResponse is an alert with its display strings
+type alertResponse struct {
+	*entities.Alert
+	Display *alertDisplay `json:"display,omitempty"`
+}
+
+// alertDisplay holds an alert's numbers formatted for a locale
+type alertDisplay struct {
+	Locale      string `json:"locale"`
+	TargetValue string `json:"target_value"`
+}
+
+// alertWithDisplay adds the display strings of an alert when f is set
+func alertWithDisplay(f *locale.Formatter, alert *entities.Alert) interface{} {
+	if f == nil {
+		return alert
+	}
+	return alertResponse{
+		Alert: alert,
+		Display: &alertDisplay{
+			Locale:      f.Locale(),
+			TargetValue: formatAlertValue(f, alert.AlertType, alert.Symbol, alert.TargetValue),
+		},
+	}
+}
+
+// alertsWithDisplay adds the display strings of the alerts when f is set
+func alertsWithDisplay(f *locale.Formatter, alerts []entities.Alert) interface{} {
+	if f == nil {
+		return alerts
+	}
+	responses := make([]interface{}, len(alerts))
+	for i := range alerts {
+		responses[i] = alertWithDisplay(f, &alerts[i])
+	}
+	return responses
+}
+
+// notificationResponse is a notification with its display strings
+type notificationResponse struct {
+	*entities.Notification
+	Display *notificationDisplay `json:"display,omitempty"`
+}
+
+// notificationDisplay holds the numbers of a notification's evaluation context
+// formatted for a locale, under the same keys
+type notificationDisplay struct {
+	Locale  string            `json:"locale"`
+	Context map[string]string `json:"context,omitempty"`
+}
+
+// notificationWithDisplay adds the display strings of a notification when f is set
+func notificationWithDisplay(f *locale.Formatter, notification *entities.Notification) interface{} {
+	if f == nil {
+		return notification
+	}
+	return notificationResponse{
+		Notification: notification,
+		Display: &notificationDisplay{
+			Locale:  f.Locale(),
+			Context: formatEvaluationContext(f, notification.Context),
+		},
+	}
+}
+
+// notificationsWithDisplay adds the display strings of the notifications when f is set
+func notificationsWithDisplay(f *locale.Formatter, notifications []entities.Notification) interface{} {
+	if f == nil {
+		return notifications
+	}
+	responses := make([]interface{}, len(notifications))
+	for i := range notifications {
+		responses[i] = notificationWithDisplay(f, &notifications[i])
+	}
+	return responses
+}
+
+// formatEvaluationContext formats the prices, percent changes, volumes and alert
+// values of an evaluation context; other entries have no display string
+func formatEvaluationContext(f *locale.Formatter, context map[string]interface{}) map[string]string {
+	symbol, _ := context["symbol"].(string)
+	alertType, _ := context["alert_type"].(string)
+
+	display := make(map[string]string)
+	for key, value := range context {
+		number, ok := value.(float64)
+		if !ok {
+			continue
+		}
+		switch {
+		case key == "current_value" || key == "target_value":
+			display[key] = formatAlertValue(f, alertType, symbol, number)
+		case strings.HasSuffix(key, "_price") || key == "previous_close":
+			display[key] = f.Price(number, symbol)
+		case strings.HasSuffix(key, "_change") || strings.HasSuffix(key, "_percent"):
+			display[key] = f.Percent(number)
+		case strings.HasSuffix(key, "volume"):
+			display[key] = f.Number(number, 2)
+		}
+	}
+	if len(display) == 0 {
+		return nil
+	}
+	return display
+}
+
+// formatAlertValue formats a target or current value of an alert type: prices in
+// the symbol's quote currency, percentage changes as percentages, indicator values
+// as numbers
+func formatAlertValue(f *locale.Formatter, alertType, symbol string, value float64) string {
+	switch alertType {
+	case "price":
+		return f.Price(value, symbol)
+	case "percentage", entities.Change24hAlertType:
+		return f.Percent(value)
+	default:
+		return f.Number(value, 2)
+	}
+}
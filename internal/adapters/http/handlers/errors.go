@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// respondError translates a service error into a response. Validation errors and the
+// entities error kinds get their own status; anything else is a 500 with the fallback
+// message so internal details don't leak.
+func respondError(c *gin.Context, err error, fallback string) {
+	if respondValidationError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": domainErrorMessage(err, fallback)})
+	case errors.Is(err, entities.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": domainErrorMessage(err, fallback)})
+	case errors.Is(err, entities.ErrInsufficientData), errors.Is(err, entities.ErrStaleData):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": domainErrorMessage(err, fallback)})
+	case errors.Is(err, entities.ErrUpstreamUnavailable):
+		// Upstream errors carry raw responses from the provider, so only say what failed
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market data provider unavailable"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// domainErrorMessage returns the message of the DomainError in err's chain, which is
+// written for clients, instead of the wrapped text built up by the services
+func domainErrorMessage(err error, fallback string) string {
+	var domainErr *entities.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return fallback
+}
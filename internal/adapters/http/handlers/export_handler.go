@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// CreateExport godoc
+// @Summary Export user data
+// @Description Generate a JSON account export or a CSV of alerts and return a time-limited download link
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format (json or csv)" default(json)
+// @Success 201 {object} services.ExportResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/user/export [post]
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	format, err := services.ParseExportFormat(c.DefaultQuery("format", "json"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export format", "details": err.Error()})
+		return
+	}
+
+	result, err := h.exportService.ExportUserData(c.Request.Context(), userID.(uuid.UUID), format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate export"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+// maxFaultDuration bounds how long an admin-injected fault stays active
+const maxFaultDuration = 24 * time.Hour
+
+type FaultHandler struct {
+	injector *faults.Injector
+}
+
+// NewFaultHandler creates a new fault injection handler
+func NewFaultHandler(injector *faults.Injector) *FaultHandler {
+	return &FaultHandler{
+		injector: injector,
+	}
+}
+
+// SetFaultRequest describes a fault on one dependency. Delay and Duration are Go
+// durations such as "500ms"; without a duration the fault stays until cleared.
+type SetFaultRequest struct {
+	Percent  float64 `json:"percent" binding:"required"`
+	Delay    string  `json:"delay,omitempty"`
+	Error    bool    `json:"error"`
+	Duration string  `json:"duration,omitempty"`
+}
+
+// GetFaults godoc
+// @Summary List injected faults
+// @Description List the active faults injected into the exchange clients, Redis and Postgres (admin only, test environments)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/admin/faults [get]
+func (h *FaultHandler) GetFaults(c *gin.Context) {
+	active := h.injector.Faults()
+	c.JSON(http.StatusOK, gin.H{
+		"data":    active,
+		"count":   len(active),
+		"targets": faults.Targets,
+	})
+}
+
+// SetFault godoc
+// @Summary Inject a fault
+// @Description Make a percentage of the calls to a dependency fail and/or wait, replacing its current fault (admin only, test environments)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param target path string true "Dependency (binance, coinbase, kraken, redis, postgres)"
+// @Param fault body SetFaultRequest true "Fault"
+// @Success 200 {object} faults.Fault
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/admin/faults/{target} [put]
+func (h *FaultHandler) SetFault(c *gin.Context) {
+	var req SetFaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	fault := faults.Fault{
+		Target:  faults.Target(c.Param("target")),
+		Percent: req.Percent,
+		Error:   req.Error,
+	}
+	if req.Delay != "" {
+		delay, err := time.ParseDuration(req.Delay)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delay"})
+			return
+		}
+		fault.Delay = delay
+	}
+	if req.Duration != "" {
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration"})
+			return
+		}
+		if duration > maxFaultDuration {
+			duration = maxFaultDuration
+		}
+		expiresAt := time.Now().Add(duration)
+		fault.ExpiresAt = &expiresAt
+	}
+
+	if err := h.injector.Set(fault); err != nil {
+		if errors.Is(err, faults.ErrInvalidFault) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to inject fault"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fault)
+}
+
+// ClearFault godoc
+// @Summary Clear an injected fault
+// @Description Stop injecting faults into a dependency (admin only, test environments)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param target path string true "Dependency (binance, coinbase, kraken, redis, postgres)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{} "No fault on the dependency"
+// @Router /api/admin/faults/{target} [delete]
+func (h *FaultHandler) ClearFault(c *gin.Context) {
+	if !h.injector.Clear(faults.Target(c.Param("target"))) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No fault on this dependency"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fault cleared"})
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type FavoriteHandler struct {
+	userSettingsRepo repositories.UserSettingsRepository
+}
+
+// NewFavoriteHandler creates a new favorite symbols handler
+func NewFavoriteHandler(userSettingsRepo repositories.UserSettingsRepository) *FavoriteHandler {
+	return &FavoriteHandler{
+		userSettingsRepo: userSettingsRepo,
+	}
+}
+
+// GetFavorites godoc
+// @Summary List favorite symbols
+// @Description List the authenticated user's favorite symbols in display order with their labels
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Settings not found"
+// @Router /api/user/favorites [get]
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	settings, ok := h.loadSettings(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settings.Favorites()})
+}
+
+// AddFavorite godoc
+// @Summary Add favorite symbol
+// @Description Add a symbol to the favorites, optionally with a label and position (appended by default)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param favorite body entities.FavoriteSymbol true "Symbol, label and position"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Already a favorite"
+// @Router /api/user/favorites [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	var req struct {
+		Symbol   string `json:"symbol" binding:"required"`
+		Label    string `json:"label"`
+		Position *int   `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+		return
+	}
+
+	settings, ok := h.loadSettings(c)
+	if !ok {
+		return
+	}
+
+	if err := settings.AddFavorite(req.Symbol, req.Label, req.Position); err != nil {
+		respondError(c, err, "Failed to add favorite")
+		return
+	}
+	h.saveSettings(c, settings, http.StatusCreated)
+}
+
+// UpdateFavorite godoc
+// @Summary Update favorite symbol
+// @Description Change the label or position of a favorite symbol; an empty label clears it
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Favorite symbol"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not a favorite"
+// @Router /api/user/favorites/{symbol} [put]
+func (h *FavoriteHandler) UpdateFavorite(c *gin.Context) {
+	var req struct {
+		Label    *string `json:"label"`
+		Position *int    `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	settings, ok := h.loadSettings(c)
+	if !ok {
+		return
+	}
+
+	if err := settings.UpdateFavorite(c.Param("symbol"), req.Label, req.Position); err != nil {
+		respondError(c, err, "Failed to update favorite")
+		return
+	}
+	h.saveSettings(c, settings, http.StatusOK)
+}
+
+// ReorderFavorites godoc
+// @Summary Reorder favorite symbols
+// @Description Set the display order of all favorite symbols at once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/user/favorites [put]
+func (h *FavoriteHandler) ReorderFavorites(c *gin.Context) {
+	var req struct {
+		Symbols []string `json:"symbols" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbols is required"})
+		return
+	}
+
+	settings, ok := h.loadSettings(c)
+	if !ok {
+		return
+	}
+
+	if err := settings.ReorderFavorites(req.Symbols); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	h.saveSettings(c, settings, http.StatusOK)
+}
+
+// RemoveFavorite godoc
+// @Summary Remove favorite symbol
+// @Description Remove a symbol and its label from the favorites
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Favorite symbol"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not a favorite"
+// @Router /api/user/favorites/{symbol} [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	settings, ok := h.loadSettings(c)
+	if !ok {
+		return
+	}
+
+	if err := settings.RemoveFavorite(c.Param("symbol")); err != nil {
+		respondError(c, err, "Failed to remove favorite")
+		return
+	}
+	h.saveSettings(c, settings, http.StatusOK)
+}
+
+func (h *FavoriteHandler) loadSettings(c *gin.Context) (*entities.UserSettings, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	settings, err := h.userSettingsRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Settings not found"})
+		return nil, false
+	}
+	return settings, true
+}
+
+// saveSettings validates and stores the settings, then responds with the favorites
+func (h *FavoriteHandler) saveSettings(c *gin.Context, settings *entities.UserSettings, status int) {
+	if err := settings.Validate(); err != nil {
+		if !respondValidationError(c, err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if err := h.userSettingsRepo.Update(c.Request.Context(), settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update favorites"})
+		return
+	}
+
+	c.JSON(status, gin.H{"data": settings.Favorites()})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+type IncidentHandler struct {
+	incidentService *services.IncidentService
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(incidentService *services.IncidentService) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incidentService,
+	}
+}
+
+// PublishBannerRequest is the payload for publishing a system banner. Either
+// expires_at or duration (a Go duration such as "2h") must be provided.
+type PublishBannerRequest struct {
+	Title     string     `json:"title" binding:"required"`
+	Message   string     `json:"message" binding:"required"`
+	Severity  string     `json:"severity"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Duration  string     `json:"duration,omitempty"`
+}
+
+// PublishBanner godoc
+// @Summary Publish a system banner
+// @Description Broadcast an incident/maintenance banner to all WebSocket clients and pin it for every user until it expires (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param banner body PublishBannerRequest true "Banner"
+// @Success 201 {object} entities.SystemBanner
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/banners [post]
+func (h *IncidentHandler) PublishBanner(c *gin.Context) {
+	var req PublishBannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	banner := &entities.SystemBanner{
+		Title:    req.Title,
+		Message:  req.Message,
+		Severity: req.Severity,
+	}
+
+	switch {
+	case req.ExpiresAt != nil:
+		banner.ExpiresAt = *req.ExpiresAt
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration", "details": err.Error()})
+			return
+		}
+		banner.ExpiresAt = time.Now().Add(duration)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at or duration is required"})
+		return
+	}
+
+	if value, exists := c.Get("user"); exists {
+		if user, ok := value.(*entities.User); ok {
+			banner.CreatedBy = user.Email
+		}
+	}
+
+	if err := h.incidentService.PublishBanner(c.Request.Context(), banner); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish banner"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, banner)
+}
+
+// RevokeBanner godoc
+// @Summary Revoke a system banner
+// @Description Take an active banner down before it expires (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Banner ID"
+// @Success 200 {object} entities.SystemBanner
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Banner not found"
+// @Failure 409 {object} map[string]interface{} "Banner not active"
+// @Router /api/admin/banners/{id} [delete]
+func (h *IncidentHandler) RevokeBanner(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid banner ID"})
+		return
+	}
+
+	banner, err := h.incidentService.RevokeBanner(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to revoke banner")
+		return
+	}
+
+	c.JSON(http.StatusOK, banner)
+}
+
+// GetActiveBanners godoc
+// @Summary List active system banners
+// @Description Get the incident/maintenance banners currently pinned for all users
+// @Tags System
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/system/banners [get]
+func (h *IncidentHandler) GetActiveBanners(c *gin.Context) {
+	banners, err := h.incidentService.GetActiveBanners(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch banners"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  banners,
+		"count": len(banners),
+	})
+}
@@ -1,18 +1,21 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 // IndicatorHandler handles technical indicator requests
 type IndicatorHandler struct {
-	indicatorService *services.TechnicalIndicatorService
-	logger           *logrus.Logger
+	indicatorService  *services.TechnicalIndicatorService
+	statisticsService *services.StatisticsService
+	logger            *logrus.Logger
 }
 
 // NewIndicatorHandler creates a new indicator handler
@@ -23,6 +26,11 @@ func NewIndicatorHandler(indicatorService *services.TechnicalIndicatorService, l
 	}
 }
 
+// SetStatisticsService enables the symbol statistics endpoint
+func (h *IndicatorHandler) SetStatisticsService(statisticsService *services.StatisticsService) {
+	h.statisticsService = statisticsService
+}
+
 // CalculateRSI calculates RSI for a symbol and timeframe
 // @Summary Calculate RSI
 // @Description Calculate RSI indicator for a specific symbol and timeframe
@@ -57,9 +65,7 @@ func (h *IndicatorHandler) CalculateRSI(c *gin.Context) {
 	err := h.indicatorService.CalculateAndStoreRSI(c.Request.Context(), symbol, timeframe, period)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to calculate RSI")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to calculate RSI",
-		})
+		respondError(c, err, "Failed to calculate RSI")
 		return
 	}
 
@@ -105,9 +111,7 @@ func (h *IndicatorHandler) CalculateEMA(c *gin.Context) {
 	err := h.indicatorService.CalculateAndStoreEMA(c.Request.Context(), symbol, timeframe, period)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to calculate EMA")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to calculate EMA",
-		})
+		respondError(c, err, "Failed to calculate EMA")
 		return
 	}
 
@@ -153,9 +157,7 @@ func (h *IndicatorHandler) CalculateSMA(c *gin.Context) {
 	err := h.indicatorService.CalculateAndStoreSMA(c.Request.Context(), symbol, timeframe, period)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to calculate SMA")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to calculate SMA",
-		})
+		respondError(c, err, "Failed to calculate SMA")
 		return
 	}
 
@@ -209,9 +211,7 @@ func (h *IndicatorHandler) CalculateSuperTrend(c *gin.Context) {
 	err := h.indicatorService.CalculateAndStoreSuperTrend(c.Request.Context(), symbol, timeframe, period, multiplier)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to calculate SuperTrend")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to calculate SuperTrend",
-		})
+		respondError(c, err, "Failed to calculate SuperTrend")
 		return
 	}
 
@@ -224,6 +224,91 @@ func (h *IndicatorHandler) CalculateSuperTrend(c *gin.Context) {
 	})
 }
 
+// CalculateADX calculates ADX with +DI/-DI for a symbol and timeframe
+// @Summary Calculate ADX
+// @Description Calculate the ADX trend strength with the +DI and -DI directional indicators for a specific symbol and timeframe
+// @Tags indicators
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param timeframe query string true "Timeframe (1m, 5m, 15m, 1h, 4h, 1d)"
+// @Param period query int false "ADX period (default: 14)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/indicators/{symbol}/adx [post]
+func (h *IndicatorHandler) CalculateADX(c *gin.Context) {
+	symbol := c.Param("symbol")
+	timeframe := c.Query("timeframe")
+
+	if symbol == "" || timeframe == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol and timeframe are required",
+		})
+		return
+	}
+
+	period := 14 // default
+	if p := c.Query("period"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil {
+			period = parsed
+		}
+	}
+
+	err := h.indicatorService.CalculateAndStoreADX(c.Request.Context(), symbol, timeframe, period)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to calculate ADX")
+		respondError(c, err, "Failed to calculate ADX")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "ADX calculated successfully",
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"period":    period,
+	})
+}
+
+// GetIchimoku gets the Ichimoku lines and cloud for a symbol and timeframe
+// @Summary Get Ichimoku
+// @Description Get the Tenkan, Kijun, Senkou A/B and Chikou lines (9, 26, 52) of the latest candle with the cloud under it and where the price stands against it, calculated once per candle
+// @Tags indicators
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param timeframe query string true "Timeframe (1m, 5m, 15m, 1h, 4h, 1d)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/indicators/{symbol}/ichimoku [get]
+func (h *IndicatorHandler) GetIchimoku(c *gin.Context) {
+	symbol := c.Param("symbol")
+	timeframe := c.Query("timeframe")
+
+	if symbol == "" || timeframe == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol and timeframe are required",
+		})
+		return
+	}
+
+	ichimoku, err := h.indicatorService.GetIchimoku(c.Request.Context(), symbol, timeframe)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get Ichimoku")
+		respondError(c, err, "Failed to get Ichimoku")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"ichimoku":  ichimoku,
+	})
+}
+
 // CalculateAllIndicators calculates all indicators for a symbol and timeframe
 // @Summary Calculate All Indicators
 // @Description Calculate all available indicators for a specific symbol and timeframe
@@ -250,9 +335,7 @@ func (h *IndicatorHandler) CalculateAllIndicators(c *gin.Context) {
 	err := h.indicatorService.CalculateAllIndicators(c.Request.Context(), symbol, timeframe)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to calculate all indicators")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to calculate indicators",
-		})
+		respondError(c, err, "Failed to calculate indicators")
 		return
 	}
 
@@ -289,9 +372,7 @@ func (h *IndicatorHandler) GetLatestIndicators(c *gin.Context) {
 	indicators, err := h.indicatorService.GetLatestIndicators(c.Request.Context(), symbol, timeframe)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get latest indicators")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get indicators",
-		})
+		respondError(c, err, "Failed to get indicators")
 		return
 	}
 
@@ -301,3 +382,49 @@ func (h *IndicatorHandler) GetLatestIndicators(c *gin.Context) {
 		"indicators": indicators,
 	})
 }
+
+// GetSymbolStats gets the risk statistics of a symbol
+// @Summary Get Symbol Statistics
+// @Description Get rolling volatility, max drawdown and Sharpe ratio over the last window candles, computed once per candle
+// @Tags indicators
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param timeframe query string false "Timeframe (1m, 5m, 15m, 1h, 4h, 1d)" default(1d)
+// @Param window query int false "Number of returns covered (default: 30)"
+// @Success 200 {object} services.SymbolStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/indicators/{symbol}/stats [get]
+func (h *IndicatorHandler) GetSymbolStats(c *gin.Context) {
+	symbol := c.Param("symbol")
+	timeframe := c.DefaultQuery("timeframe", "1d")
+
+	if symbol == "" || indicators.GetTimeframeMilliseconds(timeframe) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol and a valid timeframe are required",
+		})
+		return
+	}
+
+	window := services.DefaultStatsWindow
+	if w := c.Query("window"); w != "" {
+		parsed, err := strconv.Atoi(w)
+		if err != nil || parsed < services.MinStatsWindow || parsed > services.MaxStatsWindow {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("window must be between %d and %d", services.MinStatsWindow, services.MaxStatsWindow),
+			})
+			return
+		}
+		window = parsed
+	}
+
+	stats, err := h.statisticsService.GetStats(c.Request.Context(), symbol, timeframe, window)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get symbol statistics")
+		respondError(c, err, "Failed to get statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
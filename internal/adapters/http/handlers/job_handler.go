@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// JobHandler lets admins see the scheduled background jobs and run them on demand
+type JobHandler struct {
+	scheduler *services.JobScheduler
+}
+
+// NewJobHandler creates a new background job handler
+func NewJobHandler(scheduler *services.JobScheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+	}
+}
+
+// GetJobs godoc
+// @Summary List the background jobs
+// @Description List the scheduled jobs with their interval, when they last ran on this instance, how long
+// @Description it took, the error it failed with and when they run next; jobs with a zero interval only
+// @Description run when triggered (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/admin/jobs [get]
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	jobs := h.scheduler.Statuses()
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// RunJob godoc
+// @Summary Run a background job
+// @Description Start a run of the job now, in the background; its outcome shows in the job list once it
+// @Description finishes (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name, e.g. price_retention"
+// @Success 202 {object} services.JobStatus
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Job not found"
+// @Failure 409 {object} map[string]interface{} "Job already running"
+// @Router /api/admin/jobs/{name}/run [post]
+func (h *JobHandler) RunJob(c *gin.Context) {
+	status, err := h.scheduler.Trigger(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err, "Failed to run job")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -32,8 +33,9 @@ func NewMetricsHandler(db *gorm.DB, rdb *redis.Client, logger *zap.Logger) *Metr
 
 // PrometheusMetrics endpoint para métricas Prometheus
 func (h *MetricsHandler) PrometheusMetrics() gin.HandlerFunc {
-	// Retorna o handler HTTP do Prometheus
-	promHandler := promhttp.Handler()
+	// Retorna o handler HTTP do Prometheus; OpenMetrics expõe os exemplars com trace IDs
+	promHandler := promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
 	return gin.WrapH(promHandler)
 }
 
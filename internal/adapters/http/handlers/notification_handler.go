@@ -16,6 +16,8 @@ import (
 type NotificationHandler struct {
 	notificationRepo    repositories.NotificationRepository
 	notificationService *services.NotificationService
+	incidentService     *services.IncidentService
+	settingsRepo        repositories.UserSettingsRepository
 }
 
 // NewNotificationHandler creates a new notification handler
@@ -29,6 +31,16 @@ func NewNotificationHandler(
 	}
 }
 
+// SetIncidentService enables pinning active system banners above the notification list
+func (h *NotificationHandler) SetIncidentService(incidentService *services.IncidentService) {
+	h.incidentService = incidentService
+}
+
+// SetUserSettingsRepo enables display strings formatted for the user's locale
+func (h *NotificationHandler) SetUserSettingsRepo(settingsRepo repositories.UserSettingsRepository) {
+	h.settingsRepo = settingsRepo
+}
+
 // GetNotifications godoc
 // @Summary Get user notifications
 // @Description Get list of notifications for the authenticated user
@@ -38,6 +50,8 @@ func NewNotificationHandler(
 // @Security BearerAuth
 // @Param limit query int false "Limit number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
+// @Param display query bool false "Add display strings formatted for the Accept-Language locale; always on for users with a locale setting"
+// @Param Accept-Language header string false "Locale of the display strings, e.g. pt-BR"
 // @Param unread_only query bool false "Show only unread notifications" default(false)
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -81,13 +95,68 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":        notifications,
+	response := gin.H{
+		"data":        notificationsWithDisplay(displayFormatter(c, h.settingsRepo), notifications),
 		"limit":       limit,
 		"offset":      offset,
 		"count":       len(notifications),
 		"unread_only": unreadOnly,
-	})
+	}
+
+	// Active incident banners stay pinned regardless of pagination or read state
+	if h.incidentService != nil {
+		banners, err := h.incidentService.GetActiveBanners(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch system banners"})
+			return
+		}
+		response["pinned"] = banners
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNotification godoc
+// @Summary Get notification
+// @Description Get a single notification, including the evaluation context that triggered it
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Param display query bool false "Add display strings formatted for the Accept-Language locale; always on for users with a locale setting"
+// @Param Accept-Language header string false "Locale of the display strings, e.g. pt-BR"
+// @Success 200 {object} entities.Notification
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Router /api/notifications/{id} [get]
+func (h *NotificationHandler) GetNotification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	notification, err := h.notificationRepo.GetByID(c.Request.Context(), notificationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if notification.UserID != userID.(uuid.UUID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationWithDisplay(displayFormatter(c, h.settingsRepo), notification))
 }
 
 // MarkAsRead godoc
@@ -210,6 +279,8 @@ func (h *NotificationHandler) CreateTestNotification(c *gin.Context) {
 				channels = append(channels, services.ChannelPush)
 			case "sms":
 				channels = append(channels, services.ChannelSMS)
+			case "telegram":
+				channels = append(channels, services.ChannelTelegram)
 			}
 		}
 
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type NotificationProviderHandler struct {
+	registry *services.ProviderRegistry
+}
+
+// NewNotificationProviderHandler creates a new notification provider handler
+func NewNotificationProviderHandler(registry *services.ProviderRegistry) *NotificationProviderHandler {
+	return &NotificationProviderHandler{
+		registry: registry,
+	}
+}
+
+// GetProviders godoc
+// @Summary List notification providers
+// @Description Show the active provider of each notification channel, the alternatives and their health (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/admin/notification-providers [get]
+func (h *NotificationProviderHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.registry.Status(c.Request.Context()),
+	})
+}
+
+// UpdateProvider godoc
+// @Summary Switch notification provider
+// @Description Hot swap the provider behind a notification channel without restarting (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param channel path string true "Channel (email, push, sms, telegram)"
+// @Param provider body services.ProviderSelection true "Provider and its settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Router /api/admin/notification-providers/{channel} [put]
+func (h *NotificationProviderHandler) UpdateProvider(c *gin.Context) {
+	var selection services.ProviderSelection
+	if err := c.ShouldBindJSON(&selection); err != nil || selection.Provider == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "provider is required"})
+		return
+	}
+
+	channel := services.NotificationChannel(c.Param("channel"))
+	if err := h.registry.Configure(channel, selection); err != nil {
+		if errors.Is(err, services.ErrUnknownProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	provider, _ := h.registry.Provider(channel)
+	status := gin.H{"channel": channel, "provider": provider.Name(), "healthy": true}
+	if err := provider.HealthCheck(c.Request.Context()); err != nil {
+		status["healthy"] = false
+		status["error"] = err.Error()
+	}
+	c.JSON(http.StatusOK, status)
+}
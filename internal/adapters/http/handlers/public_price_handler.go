@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type PublicPriceHandler struct {
+	publicPriceService *services.PublicPriceService
+}
+
+// NewPublicPriceHandler creates a new public market data handler
+func NewPublicPriceHandler(publicPriceService *services.PublicPriceService) *PublicPriceHandler {
+	return &PublicPriceHandler{
+		publicPriceService: publicPriceService,
+	}
+}
+
+// GetTicker godoc
+// @Summary Get a public ticker
+// @Description Latest price and rolling 24h statistics of a symbol, for embedding in third-party tools.
+// @Description No authentication required; requests with an X-API-Key header get a higher, per-key quota.
+// @Tags Public
+// @Produce json
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param X-API-Key header string false "API key"
+// @Success 200 {object} services.PublicTicker
+// @Failure 401 {object} map[string]interface{} "Invalid or revoked API key"
+// @Failure 404 {object} map[string]interface{} "No prices for symbol"
+// @Failure 429 {object} map[string]interface{} "Rate limit or quota exceeded"
+// @Router /api/public/v1/tickers/{symbol} [get]
+func (h *PublicPriceHandler) GetTicker(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	ticker, err := h.publicPriceService.GetTicker(c.Request.Context(), symbol)
+	if err != nil {
+		respondError(c, err, "Failed to fetch ticker")
+		return
+	}
+
+	setPublicCacheHeaders(c)
+	c.JSON(http.StatusOK, ticker)
+}
+
+// GetTickers godoc
+// @Summary Get public tickers
+// @Description Latest prices and rolling 24h statistics of up to 20 symbols; symbols without prices are left out.
+// @Description No authentication required; requests with an X-API-Key header get a higher, per-key quota.
+// @Tags Public
+// @Produce json
+// @Param symbols query string true "Comma-separated cryptocurrency symbols"
+// @Param X-API-Key header string false "API key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Invalid or revoked API key"
+// @Failure 429 {object} map[string]interface{} "Rate limit or quota exceeded"
+// @Router /api/public/v1/tickers [get]
+func (h *PublicPriceHandler) GetTickers(c *gin.Context) {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbols is required"})
+		return
+	}
+	if len(symbols) > services.MaxPublicTickerSymbols {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d symbols can be requested at once", services.MaxPublicTickerSymbols)})
+		return
+	}
+
+	tickers, err := h.publicPriceService.GetTickers(c.Request.Context(), symbols)
+	if err != nil {
+		respondError(c, err, "Failed to fetch tickers")
+		return
+	}
+
+	setPublicCacheHeaders(c)
+	c.JSON(http.StatusOK, gin.H{
+		"data":  tickers,
+		"count": len(tickers),
+	})
+}
+
+// setPublicCacheHeaders lets browsers and shared caches reuse a response for as
+// long as the service reuses the ticker, so embeds polling it don't reach the API
+func setPublicCacheHeaders(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(services.PublicTickerTTL.Seconds())))
+	c.Header("Vary", "X-API-Key")
+}
@@ -48,9 +48,7 @@ func (h *PullbackHandler) AnalyzePullbackEntry(c *gin.Context) {
 	entry, err := h.pullbackService.AnalyzePullbackEntry(c.Request.Context(), symbol, timeframe)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to analyze pullback entry")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to analyze pullback entry",
-		})
+		respondError(c, err, "Failed to analyze pullback entry")
 		return
 	}
 
@@ -84,9 +82,7 @@ func (h *PullbackHandler) GetPullbackEntriesMultiTimeframe(c *gin.Context) {
 	entries, err := h.pullbackService.GetPullbackEntriesForTimeframes(c.Request.Context(), symbol, timeframes)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get pullback entries")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get pullback entries",
-		})
+		respondError(c, err, "Failed to get pullback entries")
 		return
 	}
 
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type RetentionHandler struct {
+	retentionService *services.PriceRetentionService
+}
+
+// NewRetentionHandler creates a new price history retention handler
+func NewRetentionHandler(retentionService *services.PriceRetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// GetPolicies godoc
+// @Summary Get the price history retention policies
+// @Description List the configured retention policies, in the order they are applied (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/admin/retention [get]
+func (h *RetentionHandler) GetPolicies(c *gin.Context) {
+	policies := h.retentionService.Policies()
+	if policies == nil {
+		policies = []services.RetentionPolicy{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  policies,
+		"count": len(policies),
+	})
+}
+
+// Run godoc
+// @Summary Apply the price history retention policies
+// @Description Downsample and delete the candles the retention policies expire. Runs dry by default,
+// @Description only reporting what would be stored and deleted; pass dry_run=false to apply them (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param dry_run query bool false "Only report the changes" default(true)
+// @Success 200 {object} services.RetentionRun
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/retention/run [post]
+func (h *RetentionHandler) Run(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run"})
+		return
+	}
+
+	run, err := h.retentionService.RunOnce(c.Request.Context(), dryRun)
+	if err != nil {
+		respondError(c, err, "Failed to apply retention policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
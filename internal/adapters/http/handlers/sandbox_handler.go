@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/sirupsen/logrus"
+)
+
+// SandboxHandler signs visitors in as freshly provisioned demo users
+type SandboxHandler struct {
+	sandboxService *services.SandboxService
+	authService    *services.AuthService
+	logger         *logrus.Logger
+}
+
+// NewSandboxHandler creates a new sandbox handler
+func NewSandboxHandler(sandboxService *services.SandboxService, authService *services.AuthService, logger *logrus.Logger) *SandboxHandler {
+	return &SandboxHandler{
+		sandboxService: sandboxService,
+		authService:    authService,
+		logger:         logger,
+	}
+}
+
+// CreateSandboxSession godoc
+// @Summary Sign in as a demo user
+// @Description Provision a sandbox user watching simulated symbols, with sample alerts, and sign in as it; the user and its data are deleted once expires_at passes
+// @Tags auth
+// @Produce json
+// @Success 201 {object} services.LoginResult
+// @Failure 429 {object} map[string]interface{} "Rate limit exceeded"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth/sandbox [post]
+func (h *SandboxHandler) CreateSandboxSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := h.sandboxService.Provision(ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to provision sandbox user")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to create demo user",
+		})
+		return
+	}
+
+	tokens, err := h.authService.StartSession(ctx, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to start demo session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":    true,
+		"data":       services.LoginResult{User: user, Tokens: tokens},
+		"expires_at": user.CreatedAt.Add(h.sandboxService.TTL()).UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+// NewShareHandler creates a new share link handler
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+type createShareRequest struct {
+	Timeframe      string `json:"timeframe"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+// sharedSnapshotResponse is what a public link shows, without the owner
+type sharedSnapshotResponse struct {
+	ResourceType string                 `json:"resource_type"`
+	Symbol       string                 `json:"symbol"`
+	Snapshot     map[string]interface{} `json:"snapshot"`
+	CreatedAt    time.Time              `json:"created_at"`
+	ExpiresAt    time.Time              `json:"expires_at"`
+}
+
+// SharePullbackAnalysis godoc
+// @Summary Share a pullback analysis
+// @Description Freeze the current pullback analysis of a symbol and create a public read-only link to it.
+// @Description The token is only returned once; links expire after 7 days unless expires_in_hours is given (at most 720).
+// @Tags pullback
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Cryptocurrency symbol"
+// @Param request body createShareRequest true "Timeframe and optional expiry"
+// @Success 201 {object} services.CreatedShareLink
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/pullback/{symbol}/share [post]
+func (h *ShareHandler) SharePullbackAnalysis(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	req, ok := bindShareRequest(c)
+	if !ok {
+		return
+	}
+	if req.Timeframe == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timeframe is required"})
+		return
+	}
+
+	symbol := strings.ToUpper(c.Param("symbol"))
+	link, err := h.shareService.SharePullbackAnalysis(c.Request.Context(), userID.(uuid.UUID), symbol, req.Timeframe, shareTTL(req))
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to share pullback analysis"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ShareAlertTrigger godoc
+// @Summary Share an alert trigger
+// @Description Create a public read-only link to the evaluation snapshot of one of the user's alert trigger notifications
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Param request body createShareRequest false "Optional expiry"
+// @Success 201 {object} services.CreatedShareLink
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/notifications/{id}/share [post]
+func (h *ShareHandler) ShareAlertTrigger(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	req, ok := bindShareRequest(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.shareService.ShareAlertTrigger(c.Request.Context(), userID.(uuid.UUID), notificationID, shareTTL(req))
+	if err != nil {
+		if errors.Is(err, services.ErrShareLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+		if errors.Is(err, services.ErrNotAlertTrigger) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only alert trigger notifications can be shared"})
+			return
+		}
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to share alert trigger"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListShareLinks godoc
+// @Summary List share links
+// @Description List the authenticated user's share links, newest first, including expired and revoked ones
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit number of results" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/user/shares [get]
+func (h *ShareHandler) ListShareLinks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 100 {
+		limit = 100
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	links, err := h.shareService.ListShareLinks(c.Request.Context(), userID.(uuid.UUID), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch share links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   links,
+		"limit":  limit,
+		"offset": offset,
+		"count":  len(links),
+	})
+}
+
+// RevokeShareLink godoc
+// @Summary Revoke a share link
+// @Description Disable one of the authenticated user's share links before it expires
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Share link ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Share link not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/user/shares/{id} [delete]
+func (h *ShareHandler) RevokeShareLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share link ID"})
+		return
+	}
+
+	if err := h.shareService.RevokeShareLink(c.Request.Context(), userID.(uuid.UUID), id); err != nil {
+		respondError(c, err, "Failed to revoke share link")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked"})
+}
+
+// GetSharedSnapshot godoc
+// @Summary Open a share link
+// @Description Public, read-only view of a shared pullback analysis or alert trigger snapshot; no authentication required
+// @Tags Public
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} sharedSnapshotResponse
+// @Failure 404 {object} map[string]interface{} "Share link not found or expired"
+// @Router /api/public/shares/{token} [get]
+func (h *ShareHandler) GetSharedSnapshot(c *gin.Context) {
+	link, err := h.shareService.GetSharedSnapshot(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found or expired"})
+		return
+	}
+
+	// Not cached, so a revoked link stops working immediately
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, sharedSnapshotResponse{
+		ResourceType: link.ResourceType,
+		Symbol:       link.Symbol,
+		Snapshot:     link.Snapshot,
+		CreatedAt:    link.CreatedAt,
+		ExpiresAt:    link.ExpiresAt,
+	})
+}
+
+// bindShareRequest reads the optional share body; an empty body keeps the defaults
+func bindShareRequest(c *gin.Context) (createShareRequest, bool) {
+	var req createShareRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return req, false
+		}
+	}
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_hours must not be negative"})
+		return req, false
+	}
+	return req, true
+}
+
+func shareTTL(req createShareRequest) time.Duration {
+	return time.Duration(req.ExpiresInHours) * time.Hour
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+type SymbolRestrictionHandler struct {
+	restrictionService *services.SymbolRestrictionService
+}
+
+// NewSymbolRestrictionHandler creates a new symbol restriction handler
+func NewSymbolRestrictionHandler(restrictionService *services.SymbolRestrictionService) *SymbolRestrictionHandler {
+	return &SymbolRestrictionHandler{
+		restrictionService: restrictionService,
+	}
+}
+
+// RestrictSymbolRequest is the payload for blacklisting or greylisting a symbol
+type RestrictSymbolRequest struct {
+	Level  string `json:"level" binding:"required"` // 'blacklist', 'greylist'
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GetRestrictions godoc
+// @Summary List symbol restrictions
+// @Description Get the blacklisted and greylisted symbols (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/symbol-restrictions [get]
+func (h *SymbolRestrictionHandler) GetRestrictions(c *gin.Context) {
+	restrictions, err := h.restrictionService.GetRestrictions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch symbol restrictions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  restrictions,
+		"count": len(restrictions),
+	})
+}
+
+// RestrictSymbol godoc
+// @Summary Blacklist or greylist a symbol
+// @Description Refuse new alerts on a symbol. A blacklist also stops collecting its market data and pauses its enabled alerts, notifying their owners (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Symbol"
+// @Param restriction body RestrictSymbolRequest true "Restriction"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/symbol-restrictions/{symbol} [put]
+func (h *SymbolRestrictionHandler) RestrictSymbol(c *gin.Context) {
+	var req RestrictSymbolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	restriction := &entities.SymbolRestriction{
+		Symbol: c.Param("symbol"),
+		Level:  req.Level,
+		Reason: req.Reason,
+	}
+	if value, exists := c.Get("user"); exists {
+		if user, ok := value.(*entities.User); ok {
+			restriction.CreatedBy = user.Email
+		}
+	}
+
+	paused, err := h.restrictionService.Restrict(c.Request.Context(), restriction)
+	if err != nil {
+		respondError(c, err, "Failed to restrict symbol")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":          restriction,
+		"paused_alerts": paused,
+	})
+}
+
+// LiftRestriction godoc
+// @Summary Lift a symbol restriction
+// @Description Take a symbol off the blacklist or greylist; alerts paused by a blacklist stay paused until their owners re-enable them (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Symbol"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Symbol not restricted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/admin/symbol-restrictions/{symbol} [delete]
+func (h *SymbolRestrictionHandler) LiftRestriction(c *gin.Context) {
+	if err := h.restrictionService.Lift(c.Request.Context(), c.Param("symbol")); err != nil {
+		respondError(c, err, "Failed to lift symbol restriction")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// telegramSecretHeader carries the secret_token the webhook was registered with
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type TelegramHandler struct {
+	linkService   *services.TelegramLinkService
+	webhookSecret string
+}
+
+// NewTelegramHandler creates a new Telegram channel handler. Webhook updates are
+// only accepted with webhookSecret, so an empty secret disables the webhook.
+func NewTelegramHandler(linkService *services.TelegramLinkService, webhookSecret string) *TelegramHandler {
+	return &TelegramHandler{
+		linkService:   linkService,
+		webhookSecret: webhookSecret,
+	}
+}
+
+// GetTelegramLink godoc
+// @Summary Get Telegram link
+// @Description Get whether the authenticated user's alerts are sent to a Telegram chat
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/notifications/channels/telegram/link [get]
+func (h *TelegramHandler) GetTelegramLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	link, err := h.linkService.GetLink(c.Request.Context(), userID.(uuid.UUID))
+	if errors.Is(err, services.ErrTelegramLinkNotFound) {
+		c.JSON(http.StatusOK, gin.H{"linked": false})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Telegram link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"linked":    link.IsLinked(),
+		"username":  link.Username,
+		"linked_at": link.LinkedAt,
+	})
+}
+
+// LinkTelegram godoc
+// @Summary Link Telegram chat
+// @Description Create a one-time link that connects a Telegram chat to the authenticated user. Opening
+// @Description the URL starts the bot, which sends the code back; alerts notifying via "telegram" then
+// @Description go to that chat. The code expires after 15 minutes.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} services.TelegramLinkCode
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 503 {object} map[string]interface{} "Telegram is not configured"
+// @Router /api/notifications/channels/telegram/link [post]
+func (h *TelegramHandler) LinkTelegram(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	code, err := h.linkService.CreateLinkCode(c.Request.Context(), userID.(uuid.UUID))
+	if errors.Is(err, services.ErrTelegramNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Telegram notifications are not available"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Telegram link"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, code)
+}
+
+// UnlinkTelegram godoc
+// @Summary Unlink Telegram chat
+// @Description Stop sending the authenticated user's notifications to Telegram
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No Telegram link"
+// @Router /api/notifications/channels/telegram/link [delete]
+func (h *TelegramHandler) UnlinkTelegram(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.linkService.Unlink(c.Request.Context(), userID.(uuid.UUID)); err != nil {
+		if errors.Is(err, services.ErrTelegramLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Telegram is not linked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink Telegram"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Telegram unlinked"})
+}
+
+// Webhook godoc
+// @Summary Telegram bot webhook
+// @Description Receives bot updates from Telegram, authenticated by the X-Telegram-Bot-Api-Secret-Token header
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{} "Invalid secret token"
+// @Failure 500 {object} map[string]interface{} "Update not processed, Telegram retries it"
+// @Router /api/telegram/webhook [post]
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	secret := c.GetHeader(telegramSecretHeader)
+	if h.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid secret token"})
+		return
+	}
+
+	// Telegram redelivers any update that doesn't get a 200: malformed updates are
+	// acknowledged so they aren't retried forever, failures are left to be retried
+	var update services.TelegramUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusOK, gin.H{"ok": false})
+		return
+	}
+	if err := h.linkService.HandleUpdate(c.Request.Context(), &update); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process update"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// dcaDateLayout is the date format of DCA simulation requests
+const dcaDateLayout = "2006-01-02"
+
+type ToolsHandler struct {
+	dcaService *services.DCASimulationService
+}
+
+// NewToolsHandler creates a new tools handler
+func NewToolsHandler(dcaService *services.DCASimulationService) *ToolsHandler {
+	return &ToolsHandler{
+		dcaService: dcaService,
+	}
+}
+
+type dcaSimulationRequest struct {
+	Symbol    string  `json:"symbol" binding:"required"`
+	Amount    float64 `json:"amount" binding:"required"`
+	Frequency string  `json:"frequency" binding:"required"`
+	StartDate string  `json:"start_date" binding:"required"`
+	EndDate   string  `json:"end_date" binding:"required"`
+}
+
+// SimulateDCA godoc
+// @Summary Simulate dollar-cost averaging
+// @Description Replay stored daily closes buying a fixed amount every day, week or month, returning
+// @Description the amount invested, holdings and ROI after each purchase and at the end of the range
+// @Tags Tools
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dcaSimulationRequest true "Symbol, amount, frequency (daily, weekly, monthly) and dates as YYYY-MM-DD"
+// @Success 200 {object} services.DCASimulationResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/tools/dca-simulate [post]
+func (h *ToolsHandler) SimulateDCA(c *gin.Context) {
+	var req dcaSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse(dcaDateLayout, req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date, expected YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse(dcaDateLayout, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.dcaService.Simulate(c.Request.Context(), services.DCASimulationRequest{
+		Symbol:    strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Amount:    req.Amount,
+		Frequency: strings.ToLower(req.Frequency),
+		StartDate: startDate,
+		// The end date is inclusive, so its daily candle is part of the range
+		EndDate: endDate.Add(24*time.Hour - time.Nanosecond),
+	})
+	if err != nil {
+		respondError(c, err, "Failed to simulate DCA")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,17 +1,33 @@
 package handlers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
 )
 
+// DefaultMaxAvatarSize caps avatar uploads when no limit is configured
+const DefaultMaxAvatarSize int64 = 2 << 20
+
+// avatarExtensions maps the accepted avatar content types to their file extension
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
 type UserHandler struct {
 	userRepo         repositories.UserRepository
 	userSettingsRepo repositories.UserSettingsRepository
+	avatarStorage    storage.ObjectStorage
+	maxAvatarSize    int64
 }
 
 // NewUserHandler creates a new user handler
@@ -19,6 +35,15 @@ func NewUserHandler(userRepo repositories.UserRepository, userSettingsRepo repos
 	return &UserHandler{
 		userRepo:         userRepo,
 		userSettingsRepo: userSettingsRepo,
+		maxAvatarSize:    DefaultMaxAvatarSize,
+	}
+}
+
+// SetAvatarStorage enables avatar uploads backed by the given object storage
+func (h *UserHandler) SetAvatarStorage(avatarStorage storage.ObjectStorage, maxSize int64) {
+	h.avatarStorage = avatarStorage
+	if maxSize > 0 {
+		h.maxAvatarSize = maxSize
 	}
 }
 
@@ -47,6 +72,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	user.ResolveAvatarURL()
 	c.JSON(http.StatusOK, user)
 }
 
@@ -107,6 +133,110 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	user.ResolveAvatarURL()
+	c.JSON(http.StatusOK, user)
+}
+
+// UploadAvatar godoc
+// @Summary Upload a custom avatar
+// @Description Upload a PNG, JPEG, WebP or GIF image as the authenticated user's avatar
+// @Tags User
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} entities.User
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 413 {object} map[string]interface{} "Avatar too large"
+// @Failure 415 {object} map[string]interface{} "Unsupported image type"
+// @Failure 503 {object} map[string]interface{} "Avatar storage not configured"
+// @Router /api/user/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if h.avatarStorage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Avatar storage not configured"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxAvatarSize+(1<<20))
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar file is required"})
+		return
+	}
+	if fileHeader.Size > h.maxAvatarSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Avatar too large",
+			"max_bytes": h.maxAvatarSize,
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid avatar file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxAvatarSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid avatar file"})
+		return
+	}
+	if int64(len(data)) > h.maxAvatarSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Avatar too large",
+			"max_bytes": h.maxAvatarSize,
+		})
+		return
+	}
+
+	// Trust the file contents rather than the client supplied Content-Type
+	contentType := http.DetectContentType(data)
+	extension, allowed := avatarExtensions[contentType]
+	if !allowed {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported image type"})
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	key := fmt.Sprintf("avatars/%s/%s%s", user.ID, uuid.New(), extension)
+	if err := h.avatarStorage.PutObject(c.Request.Context(), key, data, contentType); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to store avatar"})
+		return
+	}
+
+	previous := user.Avatar
+	avatarURL := h.avatarStorage.PublicURL(key)
+	user.Avatar = &avatarURL
+
+	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
+		_ = h.avatarStorage.DeleteObject(c.Request.Context(), key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+
+	// Best effort cleanup of the replaced upload; OAuth pictures and foreign URLs are left alone
+	if previous != nil {
+		if oldKey, owned := h.avatarStorage.KeyFromURL(*previous); owned {
+			_ = h.avatarStorage.DeleteObject(c.Request.Context(), oldKey)
+		}
+	}
+
+	user.ResolveAvatarURL()
 	c.JSON(http.StatusOK, user)
 }
 
@@ -176,6 +306,9 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 		NotificationsSMS   *bool    `json:"notifications_sms,omitempty"`
 		RiskProfile        *string  `json:"risk_profile,omitempty"`
 		FavoriteSymbols    []string `json:"favorite_symbols,omitempty"`
+		ReportFrequency    *string  `json:"report_frequency,omitempty"`
+		ReportHour         *int     `json:"report_hour,omitempty"`
+		Locale             *string  `json:"locale,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -206,7 +339,21 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 		settings.RiskProfile = *updateData.RiskProfile
 	}
 	if updateData.FavoriteSymbols != nil {
-		settings.FavoriteSymbols = updateData.FavoriteSymbols
+		settings.SetFavoriteSymbols(updateData.FavoriteSymbols)
+	}
+	if updateData.ReportFrequency != nil {
+		settings.ReportFrequency = *updateData.ReportFrequency
+	}
+	if updateData.ReportHour != nil {
+		settings.ReportHour = *updateData.ReportHour
+	}
+	if updateData.Locale != nil {
+		settings.Locale = *updateData.Locale
+	}
+
+	if err := settings.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
 	}
 
 	// Save updates
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// respondValidationError writes a 400 for entity validation failures and reports whether it did
+func respondValidationError(c *gin.Context, err error) bool {
+	var validationErr *entities.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Validation failed",
+		"field":   validationErr.Field,
+		"details": validationErr.Message,
+	})
+	return true
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// maxWebhookPayloadSize bounds the payloads accepted for verification
+const maxWebhookPayloadSize = 1 << 20
+
+// WebhookHandler lets webhook consumers check that a delivery came from PriceGuard
+type WebhookHandler struct {
+	signer *services.WebhookSigner
+	logger *logrus.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(signer *services.WebhookSigner, logger *logrus.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		signer: signer,
+		logger: logger,
+	}
+}
+
+// VerifyWebhook godoc
+// @Summary Verify a webhook delivery
+// @Description Post a received webhook as is, with its raw body and its X-PriceGuard-Timestamp, X-PriceGuard-Nonce
+// @Description and X-PriceGuard-Signature headers, to check its HMAC-SHA256 signature. A delivery is only
+// @Description reported valid once: verifying it again reports a replay.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{} "valid, with a reason when it isn't"
+// @Failure 400 {object} map[string]interface{} "Missing signature headers"
+// @Failure 413 {object} map[string]interface{} "Payload too large"
+// @Router /api/webhooks/verify [post]
+func (h *WebhookHandler) VerifyWebhook(c *gin.Context) {
+	sig := services.WebhookSignature{
+		Timestamp: c.GetHeader(services.WebhookTimestampHeader),
+		Nonce:     c.GetHeader(services.WebhookNonceHeader),
+		Signature: c.GetHeader(services.WebhookSignatureHeader),
+	}
+	if sig.Timestamp == "" || sig.Nonce == "" || sig.Signature == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "missing_signature",
+			"message": "The " + services.WebhookTimestampHeader + ", " + services.WebhookNonceHeader + " and " + services.WebhookSignatureHeader + " headers are required",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "payload_too_large",
+			"message": "Webhook payloads are at most 1MB",
+		})
+		return
+	}
+
+	err = h.signer.Verify(c.Request.Context(), sig, body)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"valid": true})
+	case errors.Is(err, services.ErrWebhookSignatureMismatch):
+		c.JSON(http.StatusOK, gin.H{"valid": false, "reason": "signature_mismatch"})
+	case errors.Is(err, services.ErrWebhookTimestampOutOfRange):
+		c.JSON(http.StatusOK, gin.H{"valid": false, "reason": "timestamp_out_of_range"})
+	case errors.Is(err, services.ErrWebhookReplayed):
+		c.JSON(http.StatusOK, gin.H{"valid": false, "reason": "replayed"})
+	default:
+		h.logger.WithError(err).Error("Failed to verify webhook")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to verify webhook",
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+type WebSocketStatsHandler struct {
+	wsService services.AlertWebSocketService
+}
+
+// NewWebSocketStatsHandler creates a new WebSocket stats handler
+func NewWebSocketStatsHandler(wsService services.AlertWebSocketService) *WebSocketStatsHandler {
+	return &WebSocketStatsHandler{
+		wsService: wsService,
+	}
+}
+
+// GetStats godoc
+// @Summary WebSocket statistics
+// @Description Show connected users per room, the message rate and dropped message counts (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/ws/stats [get]
+func (h *WebSocketStatsHandler) GetStats(c *gin.Context) {
+	stats, err := h.wsService.GetConnectedUsersStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to collect WebSocket stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+const (
+	// APIKeyHeader carries the key of public API requests made with one
+	APIKeyHeader = "X-API-Key"
+	// APIKeyContextKey holds the *entities.APIKey of a request made with a key
+	APIKeyContextKey = "api_key"
+
+	// DefaultAPIKeyDailyQuota is how many public API requests a key can make per UTC day
+	DefaultAPIKeyDailyQuota = 10000
+)
+
+// APIKeyMiddleware identifies public API requests made with a key. Requests without
+// one go on anonymously; an unknown or revoked key is rejected rather than treated
+// as anonymous, so a client notices its key stopped working.
+func APIKeyMiddleware(apiKeyService *services.APIKeyService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			c.Next()
+			return
+		}
+
+		key, err := apiKeyService.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			if errors.Is(err, entities.ErrNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":   "unauthorized",
+					"message": "Invalid or revoked API key",
+				})
+			} else {
+				logger.WithError(err).Error("Failed to verify API key")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(APIKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// PublicAPIRateLimitMiddleware limits requests made with a key per key and the
+// anonymous ones per IP, under the tighter PublicAPIRateLimitConfig
+func PublicAPIRateLimitMiddleware(rdb *redis.Client) gin.HandlerFunc {
+	anonymous := RateLimitMiddleware(rdb, PublicAPIRateLimitConfig())
+	withKey := RateLimitMiddleware(rdb, APIKeyRateLimitConfig())
+	return func(c *gin.Context) {
+		if _, exists := c.Get(APIKeyContextKey); exists {
+			withKey(c)
+			return
+		}
+		anonymous(c)
+	}
+}
+
+// APIKeyQuotaMiddleware caps the requests each key makes per UTC day, reporting
+// the quota in X-Quota-* headers. Anonymous requests aren't counted, and like the
+// rate limit the quota isn't enforced while Redis is unavailable.
+func APIKeyQuotaMiddleware(rdb *redis.Client, dailyQuota int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(APIKeyContextKey)
+		if !exists {
+			c.Next()
+			return
+		}
+		key := value.(*entities.APIKey)
+
+		now := time.Now().UTC()
+		day := now.Format("20060102")
+		resetAt := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		counter := fmt.Sprintf("quota:api_key:%s:%s", key.ID, day)
+
+		ctx := context.Background()
+		used, err := rdb.Incr(ctx, counter).Result()
+		if err != nil {
+			c.Next()
+			return
+		}
+		if used == 1 {
+			rdb.ExpireAt(ctx, counter, resetAt.Add(time.Hour))
+		}
+
+		remaining := int64(dailyQuota) - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-Quota-Limit", strconv.Itoa(dailyQuota))
+		c.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("X-Quota-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if used > int64(dailyQuota) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Quota exceeded",
+				"message":     fmt.Sprintf("Maximum %d requests per day allowed for this API key", dailyQuota),
+				"retry_after": int(resetAt.Sub(now).Seconds()),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -103,6 +103,34 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	})
 }
 
+// RequireAdmin rejects authenticated users whose email is not in the admin list.
+// It must run after RequireAuth.
+func (m *AuthMiddleware) RequireAdmin(isAdmin func(email string) bool) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		user, ok := GetUserFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "unauthorized",
+				"message": "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		if !isAdmin(user.Email) {
+			m.logger.WithField("user_id", user.ID).Warn("Non-admin user attempted to access admin route")
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "Administrator access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	})
+}
+
 // WebSocketAuth authenticates WebSocket connections via query parameter
 func (m *AuthMiddleware) WebSocketAuth() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
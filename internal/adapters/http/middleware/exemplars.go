@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exemplarTraceIDLabel is the exemplar label Grafana links to the trace of
+const exemplarTraceIDLabel = "trace_id"
+
+// ObserveWithTraceExemplar records value, attaching the ID of the sampled trace of
+// ctx as an exemplar so a latency spike leads to the trace behind it. Exemplars are
+// only exposed to scrapes negotiating OpenMetrics.
+func ObserveWithTraceExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if exemplars, ok := observer.(prometheus.ExemplarObserver); ok && spanContext.IsSampled() {
+		exemplars.ObserveWithExemplar(value, prometheus.Labels{exemplarTraceIDLabel: spanContext.TraceID().String()})
+		return
+	}
+	observer.Observe(value)
+}
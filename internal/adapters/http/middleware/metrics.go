@@ -52,6 +52,14 @@ var (
 		[]string{"direction", "type"},
 	)
 
+	websocketBroadcastDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "websocket_broadcast_duration_seconds",
+			Help:    "Duration of the WebSocket broadcast of a triggered alert in seconds",
+			Buckets: []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+	)
+
 	// Database metrics
 	databaseConnectionsActive = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -104,6 +112,48 @@ var (
 		},
 	)
 
+	alertPipelineLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "alert_pipeline_latency_seconds",
+			Help:    "Latency of each alert pipeline stage, from the exchange event to the user",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"stage"},
+	)
+
+	alertEvaluationsStaleTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "alert_evaluations_stale_total",
+			Help: "Total number of alert evaluations skipped because their market data was too old",
+		},
+		[]string{"source"},
+	)
+
+	alertShardsOwned = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "alert_shards_owned",
+			Help: "Number of alert evaluation shards held by this instance",
+		},
+	)
+
+	alertShardHandoffsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "alert_shard_handoffs_total",
+			Help: "Total number of alert shards acquired, released or lost by this instance",
+		},
+		[]string{"direction"},
+	)
+
+	// Indicator metrics
+	indicatorCalculationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "indicator_calculation_duration_seconds",
+			Help:    "Duration of the scheduled indicator calculation, per run and per batch",
+			Buckets: []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"scope"},
+	)
+
 	// Notification metrics
 	notificationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -113,6 +163,23 @@ var (
 		[]string{"channel", "status"},
 	)
 
+	notificationDeliveryLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "notification_delivery_latency_seconds",
+			Help:    "Time from queuing a notification to its successful delivery, per channel",
+			Buckets: []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600},
+		},
+		[]string{"channel"},
+	)
+
+	notificationRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "notification_retries_total",
+			Help: "Total number of notification delivery attempts that were retries",
+		},
+		[]string{"channel", "status"},
+	)
+
 	notificationQueueSize = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "notification_queue_size",
@@ -129,6 +196,8 @@ func PrometheusMiddleware() gin.HandlerFunc {
 		defer httpRequestsInFlight.Dec()
 
 		start := time.Now()
+		// The request context holds the request's span while it is served
+		ctx := c.Request.Context()
 
 		c.Next()
 
@@ -146,43 +215,59 @@ func PrometheusMiddleware() gin.HandlerFunc {
 		// Incrementa contador total de requests
 		httpRequestsTotal.WithLabelValues(method, path, status).Inc()
 
-		// Registra duração da request
-		httpRequestDuration.WithLabelValues(method, path, status).Observe(duration)
+		// Registra duração da request, ligada ao trace da request
+		ObserveWithTraceExemplar(ctx, httpRequestDuration.WithLabelValues(method, path, status), duration)
 	}
 }
 
 // GetMetricsCollectors retorna os coletores de métricas para uso externo
 func GetMetricsCollectors() *MetricsCollectors {
 	return &MetricsCollectors{
-		HTTPRequestsTotal:          httpRequestsTotal,
-		HTTPRequestDuration:        httpRequestDuration,
-		HTTPRequestsInFlight:       httpRequestsInFlight,
-		WebSocketConnectionsActive: websocketConnectionsActive,
-		WebSocketMessagesTotal:     websocketMessagesTotal,
-		DatabaseConnectionsActive:  databaseConnectionsActive,
-		DatabaseQueryDuration:      databaseQueryDuration,
-		RedisOperationsTotal:       redisOperationsTotal,
-		RedisOperationDuration:     redisOperationDuration,
-		AlertsProcessedTotal:       alertsProcessedTotal,
-		AlertEvaluationDuration:    alertEvaluationDuration,
-		NotificationsTotal:         notificationsTotal,
-		NotificationQueueSize:      notificationQueueSize,
+		HTTPRequestsTotal:            httpRequestsTotal,
+		HTTPRequestDuration:          httpRequestDuration,
+		HTTPRequestsInFlight:         httpRequestsInFlight,
+		WebSocketConnectionsActive:   websocketConnectionsActive,
+		WebSocketMessagesTotal:       websocketMessagesTotal,
+		WebSocketBroadcastDuration:   websocketBroadcastDuration,
+		DatabaseConnectionsActive:    databaseConnectionsActive,
+		DatabaseQueryDuration:        databaseQueryDuration,
+		RedisOperationsTotal:         redisOperationsTotal,
+		RedisOperationDuration:       redisOperationDuration,
+		AlertsProcessedTotal:         alertsProcessedTotal,
+		AlertEvaluationDuration:      alertEvaluationDuration,
+		AlertPipelineLatency:         alertPipelineLatency,
+		AlertEvaluationsStaleTotal:   alertEvaluationsStaleTotal,
+		AlertShardsOwned:             alertShardsOwned,
+		AlertShardHandoffsTotal:      alertShardHandoffsTotal,
+		IndicatorCalculationDuration: indicatorCalculationDuration,
+		NotificationsTotal:           notificationsTotal,
+		NotificationDeliveryLatency:  notificationDeliveryLatency,
+		NotificationRetriesTotal:     notificationRetriesTotal,
+		NotificationQueueSize:        notificationQueueSize,
 	}
 }
 
 // MetricsCollectors estrutura que agrupa todos os coletores de métricas
 type MetricsCollectors struct {
-	HTTPRequestsTotal          *prometheus.CounterVec
-	HTTPRequestDuration        *prometheus.HistogramVec
-	HTTPRequestsInFlight       prometheus.Gauge
-	WebSocketConnectionsActive prometheus.Gauge
-	WebSocketMessagesTotal     *prometheus.CounterVec
-	DatabaseConnectionsActive  prometheus.Gauge
-	DatabaseQueryDuration      *prometheus.HistogramVec
-	RedisOperationsTotal       *prometheus.CounterVec
-	RedisOperationDuration     *prometheus.HistogramVec
-	AlertsProcessedTotal       *prometheus.CounterVec
-	AlertEvaluationDuration    prometheus.Histogram
-	NotificationsTotal         *prometheus.CounterVec
-	NotificationQueueSize      prometheus.Gauge
+	HTTPRequestsTotal            *prometheus.CounterVec
+	HTTPRequestDuration          *prometheus.HistogramVec
+	HTTPRequestsInFlight         prometheus.Gauge
+	WebSocketConnectionsActive   prometheus.Gauge
+	WebSocketMessagesTotal       *prometheus.CounterVec
+	WebSocketBroadcastDuration   prometheus.Histogram
+	DatabaseConnectionsActive    prometheus.Gauge
+	DatabaseQueryDuration        *prometheus.HistogramVec
+	RedisOperationsTotal         *prometheus.CounterVec
+	RedisOperationDuration       *prometheus.HistogramVec
+	AlertsProcessedTotal         *prometheus.CounterVec
+	AlertEvaluationDuration      prometheus.Histogram
+	AlertPipelineLatency         *prometheus.HistogramVec
+	AlertEvaluationsStaleTotal   *prometheus.CounterVec
+	AlertShardsOwned             prometheus.Gauge
+	AlertShardHandoffsTotal      *prometheus.CounterVec
+	IndicatorCalculationDuration *prometheus.HistogramVec
+	NotificationsTotal           *prometheus.CounterVec
+	NotificationDeliveryLatency  *prometheus.HistogramVec
+	NotificationRetriesTotal     *prometheus.CounterVec
+	NotificationQueueSize        prometheus.Gauge
 }
@@ -0,0 +1,289 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+// payloadCaptureAdminPrefix is never captured so reading the buffer doesn't fill it
+const payloadCaptureAdminPrefix = "/api/admin/debug"
+
+// ErrInvalidCaptureRule is returned for rules without a route or request ID, or already expired
+var ErrInvalidCaptureRule = errors.New("capture rule needs a route or request_id and a future expiry")
+
+// CaptureRule turns payload capture on for a route (a gin route pattern or a
+// path prefix) or a single request ID until the rule expires
+type CaptureRule struct {
+	ID        string    `json:"id"`
+	Route     string    `json:"route,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// matches reports whether the rule selects the request
+func (r *CaptureRule) matches(c *gin.Context, requestID string) bool {
+	if r.RequestID != "" {
+		return r.RequestID == requestID
+	}
+	return r.Route == c.FullPath() || strings.HasPrefix(c.Request.URL.Path, r.Route)
+}
+
+// CapturedExchange is a captured request/response pair with tokens and PII redacted
+type CapturedExchange struct {
+	ID              string            `json:"id"`
+	RuleID          string            `json:"rule_id"`
+	RequestID       string            `json:"request_id"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Route           string            `json:"route"`
+	Query           string            `json:"query,omitempty"`
+	Status          int               `json:"status"`
+	DurationMS      int64             `json:"duration_ms"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	Truncated       bool              `json:"truncated"`
+	CapturedAt      time.Time         `json:"captured_at"`
+}
+
+// PayloadCapture records the payloads of requests selected by admin rules into
+// a fixed size ring buffer. Requests no rule selects pass through untouched.
+type PayloadCapture struct {
+	maxBodySize int
+	clock       clock.Clock
+
+	rules    []CaptureRule
+	captures []CapturedExchange
+	next     int
+	full     bool
+	mutex    sync.RWMutex
+}
+
+// NewPayloadCapture creates a capture keeping the last capacity exchanges, with
+// bodies cut at maxBodySize bytes
+func NewPayloadCapture(capacity, maxBodySize int) *PayloadCapture {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	if maxBodySize <= 0 {
+		maxBodySize = 16 << 10
+	}
+	return &PayloadCapture{
+		maxBodySize: maxBodySize,
+		clock:       clock.New(),
+		captures:    make([]CapturedExchange, capacity),
+	}
+}
+
+// SetClock replaces the clock used to expire rules and date captures
+func (pc *PayloadCapture) SetClock(c clock.Clock) {
+	pc.clock = c
+}
+
+// AddRule activates a capture rule and returns it with its assigned ID
+func (pc *PayloadCapture) AddRule(rule CaptureRule) (CaptureRule, error) {
+	rule.Route = strings.TrimSpace(rule.Route)
+	rule.RequestID = strings.TrimSpace(rule.RequestID)
+	if (rule.Route == "") == (rule.RequestID == "") || !rule.ExpiresAt.After(pc.clock.Now()) {
+		return CaptureRule{}, ErrInvalidCaptureRule
+	}
+	rule.ID = uuid.New().String()
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.rules = append(pc.rules, rule)
+	return rule, nil
+}
+
+// RemoveRule deactivates a rule, reporting false if it doesn't exist
+func (pc *PayloadCapture) RemoveRule(id string) bool {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	for i, rule := range pc.rules {
+		if rule.ID == id {
+			pc.rules = append(pc.rules[:i], pc.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the rules that haven't expired
+func (pc *PayloadCapture) Rules() []CaptureRule {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.pruneRules()
+	rules := make([]CaptureRule, len(pc.rules))
+	copy(rules, pc.rules)
+	return rules
+}
+
+// Captures returns the buffered exchanges, newest first
+func (pc *PayloadCapture) Captures() []CapturedExchange {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+
+	count := pc.next
+	if pc.full {
+		count = len(pc.captures)
+	}
+
+	captures := make([]CapturedExchange, 0, count)
+	for i := 1; i <= count; i++ {
+		captures = append(captures, pc.captures[(pc.next-i+len(pc.captures))%len(pc.captures)])
+	}
+	return captures
+}
+
+// Clear drops every buffered exchange
+func (pc *PayloadCapture) Clear() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.captures = make([]CapturedExchange, len(pc.captures))
+	pc.next = 0
+	pc.full = false
+}
+
+// Middleware captures the payloads of requests matched by an active rule. It
+// must run after the request ID middleware.
+func (pc *PayloadCapture) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, payloadCaptureAdminPrefix) {
+			c.Next()
+			return
+		}
+
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = c.GetString("request_id")
+		}
+
+		rule, ok := pc.match(c, requestID)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		start := pc.clock.Now()
+		var requestBody []byte
+		if body := c.Request.Body; body != nil {
+			// Read one byte past the limit to tell a truncated body; the handler gets
+			// the bytes read followed by the rest, still streamed
+			requestBody, _ = io.ReadAll(io.LimitReader(body, int64(pc.maxBodySize)+1))
+			c.Request.Body = replayedBody{Reader: io.MultiReader(bytes.NewReader(requestBody), body), Closer: body}
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: pc.maxBodySize}
+		c.Writer = writer
+
+		c.Next()
+
+		exchange := CapturedExchange{
+			ID:              uuid.New().String(),
+			RuleID:          rule.ID,
+			RequestID:       requestID,
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Route:           c.FullPath(),
+			Query:           redactQuery(c.Request.URL.RawQuery),
+			Status:          writer.Status(),
+			DurationMS:      pc.clock.Since(start).Milliseconds(),
+			RequestHeaders:  redactHeaders(c.Request.Header),
+			ResponseHeaders: redactHeaders(writer.Header()),
+			Truncated:       len(requestBody) > pc.maxBodySize || writer.truncated,
+			CapturedAt:      start,
+		}
+		if len(requestBody) > pc.maxBodySize {
+			requestBody = requestBody[:pc.maxBodySize]
+		}
+		exchange.RequestBody = redactBody(requestBody)
+		exchange.ResponseBody = redactBody(writer.body.Bytes())
+
+		pc.store(exchange)
+	}
+}
+
+// replayedBody is a request body whose start was already read for the capture
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// match returns the first active rule selecting the request
+func (pc *PayloadCapture) match(c *gin.Context, requestID string) (CaptureRule, bool) {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+
+	now := pc.clock.Now()
+	for _, rule := range pc.rules {
+		if now.Before(rule.ExpiresAt) && rule.matches(c, requestID) {
+			return rule, true
+		}
+	}
+	return CaptureRule{}, false
+}
+
+// store appends an exchange, overwriting the oldest once the buffer is full
+func (pc *PayloadCapture) store(exchange CapturedExchange) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.captures[pc.next] = exchange
+	pc.next = (pc.next + 1) % len(pc.captures)
+	if pc.next == 0 {
+		pc.full = true
+	}
+}
+
+// pruneRules drops expired rules; callers hold the write lock
+func (pc *PayloadCapture) pruneRules() {
+	now := pc.clock.Now()
+	active := pc.rules[:0]
+	for _, rule := range pc.rules {
+		if now.Before(rule.ExpiresAt) {
+			active = append(active, rule)
+		}
+	}
+	pc.rules = active
+}
+
+// captureWriter copies up to limit bytes of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.copy(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.copy([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) copy(data []byte) {
+	remaining := w.limit - w.body.Len()
+	if len(data) > remaining {
+		data = data[:remaining]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
 )
 
 // RateLimitConfig configuração do rate limiting
@@ -46,6 +48,56 @@ func AuthenticatedUserRateLimitConfig() RateLimitConfig {
 	}
 }
 
+// DebugRoutesRateLimitConfig limite restrito para as rotas de diagnóstico (/test)
+func DebugRoutesRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 10,
+		BurstSize:         2,
+		KeyGenerator: func(c *gin.Context) string {
+			if userID, exists := c.Get("user_id"); exists {
+				return fmt.Sprintf("rate_limit:debug:user:%v", userID)
+			}
+			return "rate_limit:debug:" + c.ClientIP()
+		},
+	}
+}
+
+// SandboxRateLimitConfig limite restrito para a criação de usuários de demonstração por IP
+func SandboxRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 5,
+		BurstSize:         1,
+		KeyGenerator: func(c *gin.Context) string {
+			return "rate_limit:sandbox:" + c.ClientIP()
+		},
+	}
+}
+
+// PublicAPIRateLimitConfig limite restrito para a API pública de preços sem chave de API
+func PublicAPIRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 10,
+		BurstSize:         2,
+		KeyGenerator: func(c *gin.Context) string {
+			return "rate_limit:public:" + c.ClientIP()
+		},
+	}
+}
+
+// APIKeyRateLimitConfig limite por chave na API pública de preços
+func APIKeyRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 60,
+		BurstSize:         10,
+		KeyGenerator: func(c *gin.Context) string {
+			if key, exists := c.Get(APIKeyContextKey); exists {
+				return fmt.Sprintf("rate_limit:api_key:%v", key.(*entities.APIKey).ID)
+			}
+			return "rate_limit:public:" + c.ClientIP()
+		},
+	}
+}
+
 // RateLimitMiddleware middleware de rate limiting usando Redis
 func RateLimitMiddleware(rdb *redis.Client, config RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces every secret or personal value in captured payloads
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyParts mark credential fields wherever they appear in a key name
+var sensitiveKeyParts = []string{
+	"password",
+	"token",
+	"secret",
+	"authorization",
+	"cookie",
+	"api_key",
+	"apikey",
+	"credential",
+	"signature",
+}
+
+// exactSensitiveKeys are redacted by exact name: personal data and the OAuth code
+var exactSensitiveKeys = map[string]bool{
+	"code":         true,
+	"email":        true,
+	"name":         true,
+	"phone":        true,
+	"phone_number": true,
+	"google_id":    true,
+	"picture":      true,
+	"avatar":       true,
+	"avatar_url":   true,
+	"chat_id":      true,
+	"client_ip":    true,
+	"ip":           true,
+}
+
+// sensitiveHeaders are never captured in clear
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Csrf-Token":        true,
+	"X-Api-Key":           true,
+	"Proxy-Authorization": true,
+	"X-Forwarded-For":     true,
+	"X-Real-Ip":           true,
+}
+
+var (
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// isSensitiveKey reports whether a JSON field or query parameter must be redacted
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if exactSensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBody redacts sensitive fields of JSON bodies and token or email
+// looking strings of any other body
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		if redacted, err := json.Marshal(redactValue(data)); err == nil {
+			return string(redacted)
+		}
+	}
+	return redactText(string(body))
+}
+
+// redactValue walks decoded JSON replacing sensitive fields and patterns
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		return redactText(v)
+	default:
+		return v
+	}
+}
+
+// redactText masks bearer tokens, JWTs and email addresses in free text
+func redactText(text string) string {
+	text = bearerPattern.ReplaceAllString(text, "Bearer "+redactedValue)
+	text = jwtPattern.ReplaceAllString(text, redactedValue)
+	return emailPattern.ReplaceAllString(text, redactedValue)
+}
+
+// redactHeaders flattens headers, masking credentials and client addresses
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		canonical := http.CanonicalHeaderKey(name)
+		if sensitiveHeaders[canonical] {
+			headers[canonical] = redactedValue
+			continue
+		}
+		headers[canonical] = redactText(strings.Join(values, ", "))
+	}
+	return headers
+}
+
+// redactQuery masks sensitive query parameters, such as the WebSocket token
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redactText(rawQuery)
+	}
+	for key, values := range query {
+		for i := range values {
+			if isSensitiveKey(key) {
+				values[i] = redactedValue
+			} else {
+				values[i] = redactText(values[i])
+			}
+		}
+	}
+	return query.Encode()
+}
@@ -8,347 +8,114 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"go.uber.org/zap"
 
 	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
 	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
-	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
 	"github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
 	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/container"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
-	domainservices "github.com/growthfolio/go-priceguard-api/internal/domain/services"
-	"github.com/growthfolio/go-priceguard-api/internal/infrastructure"
-	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
-	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/database"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
 	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
-	"github.com/sirupsen/logrus"
 )
 
 // RouterDependencies holds all dependencies needed for setting up routes
-type RouterDependencies struct {
-	Config         *config.Config
-	Logger         *logrus.Logger
-	ZapLogger      *zap.Logger
-	DBManager      *database.Manager
-	RedisClient    *redis.Client
-	TracingManager *infrastructure.TracingManager
-}
+type RouterDependencies = container.Dependencies
 
 // WebSocketManager holds WebSocket-related components
 type WebSocketManager struct {
 	Hub     *websocket.Hub
 	Handler *websocket.WebSocketHandler
 	Worker  *websocket.Worker
+
+	// App is the started application; stop it on shutdown
+	App *container.Container
 }
 
-// SetupRoutes configures all API routes and WebSocket endpoints
-func SetupRoutes(router *gin.Engine, deps *RouterDependencies) *WebSocketManager {
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(deps.DBManager.GetDB())
-	userSettingsRepo := repository.NewUserSettingsRepository(deps.DBManager.GetDB())
-	cryptoRepo := repository.NewCryptoCurrencyRepository(deps.DBManager.GetDB())
-	alertRepo := repository.NewAlertRepository(deps.DBManager.GetDB())
-	notificationRepo := repository.NewNotificationRepository(deps.DBManager.GetDB())
-	priceHistoryRepo := repository.NewPriceHistoryRepository(deps.DBManager.GetDB())
-	technicalIndicatorRepo := repository.NewTechnicalIndicatorRepository(deps.DBManager.GetDB())
-	sessionRepo := repository.NewSessionRepository(deps.DBManager.GetDB())
-
-	// Initialize domain services
-	jwtService := domainservices.NewJWTService(deps.Config.JWT.Secret, deps.Config.JWT.Expiration, deps.Config.JWT.RefreshExpiration)
-	googleOAuthService := domainservices.NewGoogleOAuthService(deps.Config.Google.ClientID, deps.Config.Google.ClientSecret, deps.Config.Google.RedirectURL)
-
-	// Initialize application services
-	authService := appservices.NewAuthService(
-		userRepo,
-		sessionRepo,
-		userSettingsRepo,
-		jwtService,
-		googleOAuthService,
-		deps.DBManager.GetRedis(),
-		deps.Logger,
-	)
-
-	// Initialize technical indicator service
-	technicalIndicatorService := appservices.NewTechnicalIndicatorService(
-		priceHistoryRepo,
-		technicalIndicatorRepo,
-		deps.Logger,
-	)
-
-	// Initialize pullback entry service
-	pullbackEntryService := appservices.NewPullbackEntryService(
-		priceHistoryRepo,
-		technicalIndicatorRepo,
-		deps.Logger,
-	)
-
-	// Initialize Binance client (for crypto data service)
-	binanceClient := external.NewBinanceClient(&deps.Config.Binance, deps.Logger)
-
-	// Initialize crypto data service
-	cryptoDataService := appservices.NewCryptoDataService(
-		binanceClient,
-		cryptoRepo,
-		priceHistoryRepo,
-		technicalIndicatorRepo,
-		deps.Logger,
-	)
-
-	// Initialize Alert Engine
-	alertEngine := appservices.NewAlertEngine(
-		alertRepo,
-		priceHistoryRepo,
-		technicalIndicatorRepo,
-		notificationRepo,
-		technicalIndicatorService,
-		deps.Logger,
-	)
-
-	// Initialize Notification Service
-	notificationService := appservices.NewNotificationService(
-		notificationRepo,
-		userRepo,
-		deps.DBManager.GetRedis().GetClient(),
-		deps.Logger,
-	)
-
-	// Initialize WebSocket components
-	wsHub := websocket.NewHub(authService, deps.Logger)
-
-	// Initialize Alert WebSocket Service
-	alertWebSocketService := appservices.NewAlertWebSocketService(
-		wsHub,
-		notificationService,
-		alertEngine,
-		deps.Logger,
-	)
-
-	// Set WebSocket service in alert engine for broadcasting
-	alertEngine.SetWebSocketService(alertWebSocketService)
-
-	// Initialize Alert Monitor
-	alertMonitor := appservices.NewAlertMonitor(
-		alertEngine,
-		notificationService,
-		cryptoDataService,
-		alertRepo,
-		deps.Logger,
-	)
-
-	// Start services
-	ctx := context.Background()
-	notificationService.StartProcessing(ctx)
-	alertMonitor.Start(ctx)
-
-	wsHandler := websocket.NewWebSocketHandler(wsHub, cryptoDataService, technicalIndicatorService, pullbackEntryService, deps.Logger)
-	wsWorker := websocket.NewWorker(
-		wsHub,
-		wsHandler,
-		cryptoDataService,
-		technicalIndicatorService,
-		pullbackEntryService,
-		alertEngine,
-		notificationService,
-		alertRepo,
-		priceHistoryRepo,
-		deps.Logger,
-	)
-
-	// Start WebSocket hub and worker
-	go wsHub.Start()
-	go wsWorker.Start(ctx)
-
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(authService, deps.Logger)
+// SetupRoutes builds the application, starts its background services with ctx and
+// configures all API routes and WebSocket endpoints
+func SetupRoutes(ctx context.Context, router *gin.Engine, deps *RouterDependencies) *WebSocketManager {
+	app := container.New(deps)
+	app.Start(ctx)
+	RegisterRoutes(router, app)
+
+	return &WebSocketManager{
+		Hub:     app.Realtime.Hub,
+		Handler: app.Realtime.Handler,
+		Worker:  app.Realtime.Worker,
+		App:     app,
+	}
+}
+
+// RegisterRoutes configures the middlewares and routes of an application built by container.New
+func RegisterRoutes(router *gin.Engine, app *container.Container) {
+	deps := app.Deps
+	h := app.Handlers
+	authMiddleware := h.AuthMiddleware
 
 	// Setup global middlewares
 	setupGlobalMiddlewares(router, deps)
-
-	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(deps.DBManager.GetDB(), deps.RedisClient)
-	metricsHandler := handlers.NewMetricsHandler(deps.DBManager.GetDB(), deps.RedisClient, deps.ZapLogger)
-	authHandler := handlers.NewAuthHandler(authService, deps.Logger)
-	userHandler := handlers.NewUserHandler(userRepo, userSettingsRepo)
-	cryptoHandler := handlers.NewCryptoHandler(cryptoRepo, priceHistoryRepo, technicalIndicatorRepo)
-	alertHandler := handlers.NewAlertHandler(alertRepo, alertMonitor, alertEngine)
-	notificationHandler := handlers.NewNotificationHandler(notificationRepo, notificationService)
-	indicatorHandler := handlers.NewIndicatorHandler(technicalIndicatorService, deps.Logger)
-	pullbackHandler := handlers.NewPullbackHandler(pullbackEntryService, deps.Logger)
+	router.Use(h.PayloadCapture.Middleware())
 
 	// Health check routes (no auth required)
-	setupHealthRoutes(router, healthHandler, metricsHandler)
+	setupHealthRoutes(router, h.Health, h.Metrics)
+
+	// Diagnostics routes for the Binance integration (disabled unless explicitly enabled)
+	if deps.Config.App.EnableDebugRoutes {
+		debug := router.Group("/test")
+		debug.Use(authMiddleware.RequireAuth(), authMiddleware.RequireAdmin(deps.Config.App.IsAdmin))
+		if deps.RedisClient != nil {
+			debug.Use(middleware.RateLimitMiddleware(deps.RedisClient, middleware.DebugRoutesRateLimitConfig()))
+		}
+		setupDebugRoutes(debug, app.Services.MarketData, app.Repositories.Cryptos, app.Repositories.PriceHistory, app.Services.CryptoData)
+	}
 
-	// Public test routes for Binance integration
-	publicTest := router.Group("/test")
+	// Public routes
+	publicAPI := router.Group("/api")
 	{
-		publicTest.GET("/binance/ping", func(c *gin.Context) {
-			// Test Binance connectivity
-			ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), "BTCUSDT")
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to connect to Binance",
-					"details": err.Error(),
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"status":    "success",
-				"message":   "Binance connection OK",
-				"btc_price": ticker.Price,
-				"timestamp": time.Now().UTC(),
-			})
-		})
-
-		publicTest.GET("/binance/symbols", func(c *gin.Context) {
-			// Test getting exchange info
-			cryptos, err := cryptoRepo.GetActive(c.Request.Context(), 10, 0)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to get cryptocurrencies from database",
-					"details": err.Error(),
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"status":  "success",
-				"count":   len(cryptos),
-				"symbols": cryptos,
-			})
-		})
-
-		publicTest.GET("/crypto/live-prices", func(c *gin.Context) {
-			// Get a few symbols and their live prices from Binance
-			symbols := []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "ADAUSDT", "SOLUSDT"}
-			prices := make(map[string]interface{})
-
-			for _, symbol := range symbols {
-				ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), symbol)
-				if err != nil {
-					prices[symbol] = gin.H{
-						"error":   "Failed to get price",
-						"details": err.Error(),
-					}
-					continue
-				}
-				prices[symbol] = gin.H{
-					"price":  ticker.Price,
-					"symbol": ticker.Symbol,
+		// Authentication routes
+		auth := publicAPI.Group("/auth")
+		{
+			auth.POST("/login", h.Auth.Login)
+			auth.POST("/refresh", h.Auth.RefreshToken)
+			auth.POST("/logout", authMiddleware.RequireAuth(), h.Auth.Logout)
+			auth.GET("/verify", authMiddleware.RequireAuth(), h.Auth.VerifyToken)
+
+			// Demo sign-in, provisioning a sandbox user per call
+			if h.Sandbox != nil {
+				sandbox := auth.Group("/sandbox")
+				if deps.RedisClient != nil {
+					sandbox.Use(middleware.RateLimitMiddleware(deps.RedisClient, middleware.SandboxRateLimitConfig()))
 				}
+				sandbox.POST("", h.Sandbox.CreateSandboxSession)
 			}
+		}
 
-			c.JSON(http.StatusOK, gin.H{
-				"status":    "success",
-				"message":   "Live prices from Binance",
-				"data":      prices,
-				"timestamp": time.Now().UTC(),
-			})
-		})
-
-		publicTest.GET("/crypto/start-collection", func(c *gin.Context) {
-			// Test starting the crypto data collection service
-			if cryptoDataService.IsCollecting() {
-				c.JSON(http.StatusOK, gin.H{
-					"status":     "info",
-					"message":    "Crypto data collection is already running",
-					"collecting": true,
-				})
-				return
-			}
-
-			err := cryptoDataService.StartDataCollection(c.Request.Context())
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":   "Failed to start data collection",
-					"details": err.Error(),
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"status":     "success",
-				"message":    "Crypto data collection started",
-				"collecting": cryptoDataService.IsCollecting(),
-			})
-		})
-
-		publicTest.GET("/crypto/collection-status", func(c *gin.Context) {
-			// Check if data collection is running
-			c.JSON(http.StatusOK, gin.H{
-				"status":     "success",
-				"collecting": cryptoDataService.IsCollecting(),
-				"message":    "Data collection status",
-			})
-		})
-
-		publicTest.GET("/crypto/collect-single", func(c *gin.Context) {
-			// Collect data for a single cryptocurrency and store it
-			symbol := c.DefaultQuery("symbol", "BTCUSDT")
+		// Shared snapshots are opened without an account
+		publicAPI.GET("/public/shares/:token", h.Share.GetSharedSnapshot)
 
-			// Get ticker price from Binance
-			ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), symbol)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to get price from Binance",
-					"details": err.Error(),
-				})
-				return
-			}
-
-			// Parse price
-			price, err := strconv.ParseFloat(ticker.Price, 64)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to parse price",
-					"details": err.Error(),
-				})
-				return
-			}
+		// Market data for third-party tools, anonymous or with an API key
+		publicData := publicAPI.Group("/public/v1")
+		publicData.Use(middleware.APIKeyMiddleware(app.Services.APIKeys, deps.Logger))
+		if deps.RedisClient != nil {
+			publicData.Use(middleware.PublicAPIRateLimitMiddleware(deps.RedisClient))
+			publicData.Use(middleware.APIKeyQuotaMiddleware(deps.RedisClient, middleware.DefaultAPIKeyDailyQuota))
+		}
+		{
+			publicData.GET("/tickers", h.PublicPrice.GetTickers)
+			publicData.GET("/tickers/:symbol", h.PublicPrice.GetTicker)
+		}
 
-			// Create price history record
-			priceHistory := &entities.PriceHistory{
-				Symbol:     symbol,
-				Timeframe:  "1m",
-				Timestamp:  time.Now(),
-				OpenPrice:  price,
-				HighPrice:  price,
-				LowPrice:   price,
-				ClosePrice: price,
-				Volume:     0.0, // We don't have volume from ticker
-			}
+		// Bot updates, authenticated by the webhook secret token
+		publicAPI.POST("/telegram/webhook", h.Telegram.Webhook)
 
-			// Store in database
-			if err := priceHistoryRepo.Create(c.Request.Context(), priceHistory); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to store price data",
-					"details": err.Error(),
-				})
-				return
+		// Webhook consumers check deliveries without an account
+		if h.Webhook != nil {
+			webhooks := publicAPI.Group("/webhooks")
+			if deps.RedisClient != nil {
+				webhooks.Use(middleware.PublicAPIRateLimitMiddleware(deps.RedisClient))
 			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"status":  "success",
-				"message": "Price data collected and stored",
-				"data": gin.H{
-					"symbol":    symbol,
-					"price":     price,
-					"timestamp": priceHistory.Timestamp,
-				},
-			})
-		})
-	}
-
-	// Public routes
-	publicAPI := router.Group("/api")
-	{
-		// Authentication routes
-		auth := publicAPI.Group("/auth")
-		{
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/logout", authMiddleware.RequireAuth(), authHandler.Logout)
-			auth.GET("/verify", authMiddleware.RequireAuth(), authHandler.VerifyToken)
+			webhooks.POST("/verify", h.Webhook.VerifyWebhook)
 		}
 	}
 
@@ -360,72 +127,169 @@ func SetupRoutes(router *gin.Engine, deps *RouterDependencies) *WebSocketManager
 		// User routes
 		user := protectedAPI.Group("/user")
 		{
-			user.GET("/profile", userHandler.GetProfile)
-			user.PUT("/profile", userHandler.UpdateProfile)
-			user.GET("/settings", userHandler.GetSettings)
-			user.PUT("/settings", userHandler.UpdateSettings)
+			user.GET("/profile", h.User.GetProfile)
+			user.PUT("/profile", h.User.UpdateProfile)
+			user.PUT("/avatar", h.User.UploadAvatar)
+			if h.Export != nil {
+				user.POST("/export", h.Export.CreateExport)
+			}
+			user.GET("/settings", h.User.GetSettings)
+			user.PUT("/settings", h.User.UpdateSettings)
+			user.GET("/favorites", h.Favorite.GetFavorites)
+			user.POST("/favorites", h.Favorite.AddFavorite)
+			user.PUT("/favorites", h.Favorite.ReorderFavorites)
+			user.PUT("/favorites/:symbol", h.Favorite.UpdateFavorite)
+			user.DELETE("/favorites/:symbol", h.Favorite.RemoveFavorite)
+			user.GET("/shares", h.Share.ListShareLinks)
+			user.DELETE("/shares/:id", h.Share.RevokeShareLink)
+			user.GET("/api-keys", h.APIKey.ListAPIKeys)
+			user.POST("/api-keys", h.APIKey.CreateAPIKey)
+			user.DELETE("/api-keys/:id", h.APIKey.RevokeAPIKey)
+			user.GET("/devices", h.Device.ListDevices)
+			user.POST("/devices", h.Device.RegisterDevice)
+			user.DELETE("/devices/:id", h.Device.DeleteDevice)
 		}
 
 		// Cryptocurrency routes
 		crypto := protectedAPI.Group("/crypto")
 		{
-			crypto.GET("/data", cryptoHandler.GetCryptoData)
-			crypto.GET("/detail/:symbol", cryptoHandler.GetCryptoDetail)
-			crypto.GET("/history/:symbol", cryptoHandler.GetPriceHistory)
-			crypto.GET("/indicators/:symbol", cryptoHandler.GetTechnicalIndicators)
+			crypto.GET("/data", h.Crypto.GetCryptoData)
+			crypto.GET("/detail/:symbol", h.Crypto.GetCryptoDetail)
+			crypto.GET("/search", h.Crypto.SearchSymbols)
+			crypto.GET("/correlation", h.Crypto.GetCorrelation)
+			crypto.GET("/history/:symbol", middleware.CompressionMiddleware(), h.Crypto.GetPriceHistory)
+			crypto.GET("/ohlcv/:symbol", middleware.CompressionMiddleware(), h.Crypto.GetOHLCV)
+			crypto.GET("/indicators/:symbol", h.Crypto.GetTechnicalIndicators)
+			crypto.GET("/consolidated/:symbol", h.Crypto.GetConsolidatedPrice)
+			crypto.GET("/daily/:symbol", h.Crypto.GetDailySummaries)
+			crypto.GET("/orderbook/:symbol", h.Crypto.GetOrderBook)
 		}
 
 		// Alert routes
 		alerts := protectedAPI.Group("/alerts")
 		{
-			alerts.GET("", alertHandler.GetAlerts)
-			alerts.POST("", alertHandler.CreateAlert)
-			alerts.PUT("/:id", alertHandler.UpdateAlert)
-			alerts.DELETE("/:id", alertHandler.DeleteAlert)
-			alerts.GET("/types", alertHandler.GetAlertTypes)
-			alerts.GET("/stats", alertHandler.GetAlertStats)
-			alerts.POST("/trigger-evaluation", alertHandler.TriggerEvaluation)
-			alerts.POST("/:id/evaluate", alertHandler.EvaluateAlert)
+			alerts.GET("", h.Alert.GetAlerts)
+			alerts.POST("", h.Alert.CreateAlert)
+			alerts.PUT("/:id", h.Alert.UpdateAlert)
+			alerts.DELETE("/:id", h.Alert.DeleteAlert)
+			alerts.GET("/types", h.Alert.GetAlertTypes)
+			alerts.GET("/stats", h.Alert.GetAlertStats)
+			alerts.POST("/trigger-evaluation", h.Alert.TriggerEvaluation)
+			alerts.POST("/:id/evaluate", h.Alert.EvaluateAlert)
+			alerts.POST("/:id/acknowledge", h.Alert.AcknowledgeAlert)
+			alerts.GET("/:id/chart-context", h.Alert.GetAlertChartContext)
+		}
+
+		// Alert set routes
+		alertSets := protectedAPI.Group("/alert-sets")
+		{
+			alertSets.GET("", h.AlertSet.ListAlertSets)
+			alertSets.POST("", h.AlertSet.CreateAlertSet)
+			alertSets.GET("/:id", h.AlertSet.GetAlertSet)
+			alertSets.PUT("/:id", h.AlertSet.UpdateAlertSet)
+			alertSets.DELETE("/:id", h.AlertSet.DeleteAlertSet)
+			alertSets.POST("/:id/enable", h.AlertSet.EnableAlertSet)
+			alertSets.POST("/:id/disable", h.AlertSet.DisableAlertSet)
+		}
+
+		// Symbol basket routes
+		baskets := protectedAPI.Group("/baskets")
+		{
+			baskets.GET("", h.Basket.ListBaskets)
+			baskets.POST("", h.Basket.CreateBasket)
+			baskets.GET("/:id", h.Basket.GetBasket)
+			baskets.PUT("/:id", h.Basket.RenameBasket)
+			baskets.DELETE("/:id", h.Basket.DeleteBasket)
+			baskets.GET("/:id/history", h.Basket.GetBasketHistory)
 		}
 
 		// Notification routes
 		notifications := protectedAPI.Group("/notifications")
 		{
-			notifications.GET("", notificationHandler.GetNotifications)
-			notifications.POST("/mark-read", notificationHandler.MarkAsRead)
-			notifications.POST("/mark-all-read", notificationHandler.MarkAllAsRead)
-			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
-			notifications.POST("/test", notificationHandler.CreateTestNotification)
-			notifications.GET("/stats", notificationHandler.GetNotificationStats)
+			notifications.GET("", h.Notification.GetNotifications)
+			notifications.POST("/mark-read", h.Notification.MarkAsRead)
+			notifications.POST("/mark-all-read", h.Notification.MarkAllAsRead)
+			notifications.DELETE("/:id", h.Notification.DeleteNotification)
+			notifications.POST("/test", h.Notification.CreateTestNotification)
+			notifications.GET("/stats", h.Notification.GetNotificationStats)
+			notifications.GET("/:id", h.Notification.GetNotification)
+			notifications.POST("/:id/share", h.Share.ShareAlertTrigger)
+			notifications.GET("/channels/telegram/link", h.Telegram.GetTelegramLink)
+			notifications.POST("/channels/telegram/link", h.Telegram.LinkTelegram)
+			notifications.DELETE("/channels/telegram/link", h.Telegram.UnlinkTelegram)
 		}
 
 		// Technical Indicator routes
 		indicators := protectedAPI.Group("/indicators")
 		{
-			indicators.POST("/:symbol/rsi", indicatorHandler.CalculateRSI)
-			indicators.POST("/:symbol/ema", indicatorHandler.CalculateEMA)
-			indicators.POST("/:symbol/sma", indicatorHandler.CalculateSMA)
-			indicators.POST("/:symbol/supertrend", indicatorHandler.CalculateSuperTrend)
-			indicators.POST("/:symbol/all", indicatorHandler.CalculateAllIndicators)
-			indicators.GET("/:symbol/latest", indicatorHandler.GetLatestIndicators)
+			indicators.POST("/:symbol/rsi", h.Indicator.CalculateRSI)
+			indicators.POST("/:symbol/ema", h.Indicator.CalculateEMA)
+			indicators.POST("/:symbol/sma", h.Indicator.CalculateSMA)
+			indicators.POST("/:symbol/supertrend", h.Indicator.CalculateSuperTrend)
+			indicators.POST("/:symbol/adx", h.Indicator.CalculateADX)
+			indicators.POST("/:symbol/all", h.Indicator.CalculateAllIndicators)
+			indicators.GET("/:symbol/latest", h.Indicator.GetLatestIndicators)
+			indicators.GET("/:symbol/ichimoku", h.Indicator.GetIchimoku)
+			indicators.GET("/:symbol/stats", h.Indicator.GetSymbolStats)
 		}
 
 		// Pullback Entry routes
 		pullback := protectedAPI.Group("/pullback")
 		{
-			pullback.GET("/:symbol/analyze", pullbackHandler.AnalyzePullbackEntry)
-			pullback.GET("/:symbol/multi", pullbackHandler.GetPullbackEntriesMultiTimeframe)
+			pullback.GET("/:symbol/analyze", h.Pullback.AnalyzePullbackEntry)
+			pullback.GET("/:symbol/multi", h.Pullback.GetPullbackEntriesMultiTimeframe)
+			pullback.POST("/:symbol/share", h.Share.SharePullbackAnalysis)
 		}
-	}
 
-	// WebSocket routes (with JWT authentication via query parameter)
-	router.GET("/ws", wsHandler.HandleConnection)
+		// Tools routes
+		tools := protectedAPI.Group("/tools")
+		{
+			tools.POST("/dca-simulate", h.Tools.SimulateDCA)
+		}
 
-	return &WebSocketManager{
-		Hub:     wsHub,
-		Handler: wsHandler,
-		Worker:  wsWorker,
+		// System banner routes
+		protectedAPI.GET("/system/banners", h.Incident.GetActiveBanners)
+
+		// Admin routes
+		admin := protectedAPI.Group("/admin")
+		admin.Use(authMiddleware.RequireAdmin(deps.Config.App.IsAdmin))
+		{
+			admin.POST("/banners", h.Incident.PublishBanner)
+			admin.DELETE("/banners/:id", h.Incident.RevokeBanner)
+			admin.GET("/notification-providers", h.NotificationProviders.GetProviders)
+			admin.PUT("/notification-providers/:channel", h.NotificationProviders.UpdateProvider)
+			admin.GET("/abuse-flags", h.Abuse.GetAbuseFlags)
+			admin.POST("/abuse-flags/scan", h.Abuse.ScanForAbuse)
+			admin.POST("/abuse-flags/:id/resolve", h.Abuse.ResolveAbuseFlag)
+			admin.GET("/alert-costs", h.Abuse.GetEvaluationCosts)
+			admin.GET("/symbol-restrictions", h.SymbolRestrictions.GetRestrictions)
+			admin.PUT("/symbol-restrictions/:symbol", h.SymbolRestrictions.RestrictSymbol)
+			admin.DELETE("/symbol-restrictions/:symbol", h.SymbolRestrictions.LiftRestriction)
+			admin.POST("/backfill", h.Backfill.Backfill)
+			admin.GET("/retention", h.Retention.GetPolicies)
+			admin.POST("/retention/run", h.Retention.Run)
+			admin.GET("/jobs", h.Jobs.GetJobs)
+			admin.POST("/jobs/:name/run", h.Jobs.RunJob)
+			admin.GET("/debug/capture-rules", h.DebugCapture.GetCaptureRules)
+			admin.POST("/debug/capture-rules", h.DebugCapture.CreateCaptureRule)
+			admin.DELETE("/debug/capture-rules/:id", h.DebugCapture.DeleteCaptureRule)
+			admin.GET("/debug/captures", h.DebugCapture.GetCaptures)
+			admin.DELETE("/debug/captures", h.DebugCapture.ClearCaptures)
+
+			// Fault injection only exists in test environments
+			if h.Fault != nil {
+				admin.GET("/faults", h.Fault.GetFaults)
+				admin.PUT("/faults/:target", h.Fault.SetFault)
+				admin.DELETE("/faults/:target", h.Fault.ClearFault)
+			}
+		}
+
+		// WebSocket stats (admin only)
+		protectedAPI.GET("/ws/stats", authMiddleware.RequireAdmin(deps.Config.App.IsAdmin), h.WebSocketStats.GetStats)
 	}
+
+	// WebSocket routes (with JWT authentication via query parameter)
+	router.GET("/ws", app.Realtime.Handler.HandleConnection)
 }
 
 // setupGlobalMiddlewares configura middlewares globais de segurança e observabilidade
@@ -503,3 +367,169 @@ func setupAuthenticatedRateLimit(group *gin.RouterGroup, redisClient *redis.Clie
 		group.Use(middleware.RateLimitMiddleware(redisClient, authRateLimit))
 	}
 }
+
+// setupDebugRoutes registra as rotas de diagnóstico da integração com a Binance
+func setupDebugRoutes(
+	debug *gin.RouterGroup,
+	binanceClient external.MarketDataProvider,
+	cryptoRepo repositories.CryptoCurrencyRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	cryptoDataService *appservices.CryptoDataService,
+) {
+	debug.GET("/binance/ping", func(c *gin.Context) {
+		// Test Binance connectivity
+		ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), "BTCUSDT")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to connect to Binance",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "success",
+			"message":   "Binance connection OK",
+			"btc_price": ticker.Price,
+			"timestamp": time.Now().UTC(),
+		})
+	})
+
+	debug.GET("/binance/symbols", func(c *gin.Context) {
+		// Test getting exchange info
+		cryptos, err := cryptoRepo.GetActive(c.Request.Context(), 10, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get cryptocurrencies from database",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"count":   len(cryptos),
+			"symbols": cryptos,
+		})
+	})
+
+	debug.GET("/crypto/live-prices", func(c *gin.Context) {
+		// Get a few symbols and their live prices from Binance
+		symbols := []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "ADAUSDT", "SOLUSDT"}
+		prices := make(map[string]interface{})
+
+		for _, symbol := range symbols {
+			ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), symbol)
+			if err != nil {
+				prices[symbol] = gin.H{
+					"error":   "Failed to get price",
+					"details": err.Error(),
+				}
+				continue
+			}
+			prices[symbol] = gin.H{
+				"price":  ticker.Price,
+				"symbol": ticker.Symbol,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "success",
+			"message":   "Live prices from Binance",
+			"data":      prices,
+			"timestamp": time.Now().UTC(),
+		})
+	})
+
+	debug.GET("/crypto/start-collection", func(c *gin.Context) {
+		// Test starting the crypto data collection service
+		if cryptoDataService.IsCollecting() {
+			c.JSON(http.StatusOK, gin.H{
+				"status":     "info",
+				"message":    "Crypto data collection is already running",
+				"collecting": true,
+			})
+			return
+		}
+
+		err := cryptoDataService.StartDataCollection(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Failed to start data collection",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":     "success",
+			"message":    "Crypto data collection started",
+			"collecting": cryptoDataService.IsCollecting(),
+		})
+	})
+
+	debug.GET("/crypto/collection-status", func(c *gin.Context) {
+		// Check if data collection is running
+		c.JSON(http.StatusOK, gin.H{
+			"status":     "success",
+			"collecting": cryptoDataService.IsCollecting(),
+			"message":    "Data collection status",
+		})
+	})
+
+	debug.GET("/crypto/collect-single", func(c *gin.Context) {
+		// Collect data for a single cryptocurrency and store it
+		symbol := c.DefaultQuery("symbol", "BTCUSDT")
+
+		// Get ticker price from Binance
+		ticker, err := binanceClient.GetTickerPrice(c.Request.Context(), symbol)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get price from Binance",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Parse price
+		price, err := strconv.ParseFloat(ticker.Price, 64)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to parse price",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Create price history record
+		priceHistory := &entities.PriceHistory{
+			Symbol:     symbol,
+			Timeframe:  "1m",
+			Timestamp:  time.Now(),
+			OpenPrice:  price,
+			HighPrice:  price,
+			LowPrice:   price,
+			ClosePrice: price,
+			Volume:     0.0, // We don't have volume from ticker
+		}
+
+		// Store in database
+		if err := priceHistoryRepo.Create(c.Request.Context(), priceHistory); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to store price data",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"message": "Price data collected and stored",
+			"data": gin.H{
+				"symbol":    symbol,
+				"price":     price,
+				"timestamp": priceHistory.Timestamp,
+			},
+		})
+	})
+}
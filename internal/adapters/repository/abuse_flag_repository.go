@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type abuseFlagRepository struct {
+	db *gorm.DB
+}
+
+// NewAbuseFlagRepository creates a new abuse flag repository
+func NewAbuseFlagRepository(db *gorm.DB) repositories.AbuseFlagRepository {
+	return &abuseFlagRepository{
+		db: db,
+	}
+}
+
+func (r *abuseFlagRepository) Create(ctx context.Context, flag *entities.AbuseFlag) error {
+	if flag.ID == uuid.Nil {
+		flag.ID = uuid.New()
+	}
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Create(flag).Error
+}
+
+func (r *abuseFlagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AbuseFlag, error) {
+	var flag entities.AbuseFlag
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&flag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *abuseFlagRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AbuseFlag, error) {
+	var flags []entities.AbuseFlag
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&flags).Error
+	return flags, err
+}
+
+func (r *abuseFlagRepository) GetActive(ctx context.Context, now time.Time) ([]entities.AbuseFlag, error) {
+	var flags []entities.AbuseFlag
+	err := r.db.WithContext(ctx).
+		Where("resolved_at IS NULL AND throttled_until > ?", now).
+		Order("created_at DESC").
+		Find(&flags).Error
+	return flags, err
+}
+
+func (r *abuseFlagRepository) List(ctx context.Context, limit, offset int) ([]entities.AbuseFlag, error) {
+	var flags []entities.AbuseFlag
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&flags).Error
+	return flags, err
+}
+
+// Resolve clears a flag that isn't resolved yet
+func (r *abuseFlagRepository) Resolve(ctx context.Context, id uuid.UUID, resolvedBy string, resolvedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.AbuseFlag{}).
+		Where("id = ? AND resolved_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"resolved_at": resolvedAt,
+			"resolved_by": resolvedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -29,7 +29,15 @@ func (r *alertRepository) Create(ctx context.Context, alert *entities.Alert) err
 	alert.CreatedAt = time.Now()
 	alert.UpdatedAt = time.Now()
 
-	return r.db.WithContext(ctx).Create(alert).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
+		if rows := alert.FlattenConditions(); len(rows) > 0 {
+			return tx.Create(&rows).Error
+		}
+		return nil
+	})
 }
 
 func (r *alertRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Alert, error) {
@@ -38,7 +46,11 @@ func (r *alertRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.
 	if err != nil {
 		return nil, err
 	}
-	return &alert, nil
+	alerts := []entities.Alert{alert}
+	if err := r.loadConditions(ctx, alerts); err != nil {
+		return nil, err
+	}
+	return &alerts[0], nil
 }
 
 func (r *alertRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.Alert, error) {
@@ -52,20 +64,53 @@ func (r *alertRepository) GetByUserID(ctx context.Context, userID uuid.UUID, lim
 		query = query.Offset(offset)
 	}
 
-	err := query.Find(&alerts).Error
-	return alerts, err
+	if err := query.Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
 }
 
 func (r *alertRepository) GetBySymbol(ctx context.Context, symbol string) ([]entities.Alert, error) {
 	var alerts []entities.Alert
-	err := r.db.WithContext(ctx).Where("symbol = ?", symbol).Find(&alerts).Error
-	return alerts, err
+	if err := r.db.WithContext(ctx).Where("symbol = ?", symbol).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
+}
+
+func (r *alertRepository) GetBySetID(ctx context.Context, setID uuid.UUID) ([]entities.Alert, error) {
+	var alerts []entities.Alert
+	if err := r.db.WithContext(ctx).Where("set_id = ?", setID).Order("created_at ASC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
+}
+
+func (r *alertRepository) GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error) {
+	var alerts []entities.Alert
+	if err := r.db.WithContext(ctx).Where("depends_on_id = ?", alertID).Order("created_at ASC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
 }
 
 func (r *alertRepository) GetEnabled(ctx context.Context) ([]entities.Alert, error) {
 	var alerts []entities.Alert
-	err := r.db.WithContext(ctx).Where("enabled = ?", true).Find(&alerts).Error
-	return alerts, err
+	if err := r.db.WithContext(ctx).Where("enabled = ?", true).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
+}
+
+func (r *alertRepository) GetAwaitingAck(ctx context.Context) ([]entities.Alert, error) {
+	var alerts []entities.Alert
+	if err := r.db.WithContext(ctx).
+		Where("enabled = ? AND awaiting_ack_since IS NOT NULL", true).
+		Order("awaiting_ack_since ASC").
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, r.loadConditions(ctx, alerts)
 }
 
 func (r *alertRepository) MarkTriggered(ctx context.Context, id uuid.UUID) error {
@@ -79,9 +124,70 @@ func (r *alertRepository) MarkTriggered(ctx context.Context, id uuid.UUID) error
 
 func (r *alertRepository) Update(ctx context.Context, alert *entities.Alert) error {
 	alert.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Save(alert).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(alert).Error; err != nil {
+			return err
+		}
+
+		// Keep the IDs of unchanged conditions, crossover state is tracked by them
+		rows := alert.FlattenConditions()
+		stale := tx.Where("alert_id = ?", alert.ID)
+		if len(rows) > 0 {
+			ids := make([]uuid.UUID, len(rows))
+			for i, row := range rows {
+				ids[i] = row.ID
+			}
+			stale = stale.Where("id NOT IN ?", ids)
+		}
+		if err := stale.Delete(&entities.AlertCondition{}).Error; err != nil {
+			return err
+		}
+		for i := range rows {
+			if err := tx.Save(&rows[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *alertRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entities.Alert{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("alert_id = ?", id).Delete(&entities.AlertCondition{}).Error; err != nil {
+			return err
+		}
+		// The foreign key does this too, but SQLite only enforces it when asked to
+		if err := tx.Model(&entities.Alert{}).Where("depends_on_id = ?", id).Update("depends_on_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Alert{}, id).Error
+	})
+}
+
+// loadConditions fills in the condition trees of the composite alerts among alerts
+func (r *alertRepository) loadConditions(ctx context.Context, alerts []entities.Alert) error {
+	var ids []uuid.UUID
+	for _, alert := range alerts {
+		if alert.IsComposite() {
+			ids = append(ids, alert.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rows []entities.AlertCondition
+	if err := r.db.WithContext(ctx).Where("alert_id IN ?", ids).Order("position").Find(&rows).Error; err != nil {
+		return err
+	}
+	byAlert := make(map[uuid.UUID][]entities.AlertCondition)
+	for _, row := range rows {
+		byAlert[row.AlertID] = append(byAlert[row.AlertID], row)
+	}
+	for i := range alerts {
+		if alerts[i].IsComposite() {
+			alerts[i].SetConditions(byAlert[alerts[i].ID])
+		}
+	}
+	return nil
 }
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type alertSetRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertSetRepository creates a new alert set repository
+func NewAlertSetRepository(db *gorm.DB) repositories.AlertSetRepository {
+	return &alertSetRepository{
+		db: db,
+	}
+}
+
+func (r *alertSetRepository) Create(ctx context.Context, set *entities.AlertSet) error {
+	if set.ID == uuid.Nil {
+		set.ID = uuid.New()
+	}
+	set.CreatedAt = time.Now()
+	set.UpdatedAt = set.CreatedAt
+
+	return r.db.WithContext(ctx).Create(set).Error
+}
+
+func (r *alertSetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AlertSet, error) {
+	var set entities.AlertSet
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&set).Error
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// GetByUserID returns the user's sets ordered by name
+func (r *alertSetRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AlertSet, error) {
+	var sets []entities.AlertSet
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&sets).Error
+	return sets, err
+}
+
+func (r *alertSetRepository) Update(ctx context.Context, set *entities.AlertSet) error {
+	set.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&entities.AlertSet{}).
+		Where("id = ?", set.ID).
+		Updates(map[string]interface{}{
+			"name":        set.Name,
+			"description": set.Description,
+			"updated_at":  set.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete deletes the set; its alerts are deleted or detached by the caller first
+func (r *alertSetRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.AlertSet{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type basketRepository struct {
+	db *gorm.DB
+}
+
+// NewBasketRepository creates a new symbol basket repository
+func NewBasketRepository(db *gorm.DB) repositories.BasketRepository {
+	return &basketRepository{
+		db: db,
+	}
+}
+
+func (r *basketRepository) Create(ctx context.Context, basket *entities.Basket) error {
+	if basket.ID == uuid.Nil {
+		basket.ID = uuid.New()
+	}
+	basket.CreatedAt = time.Now()
+	basket.UpdatedAt = basket.CreatedAt
+
+	return r.db.WithContext(ctx).Create(basket).Error
+}
+
+func (r *basketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
+	var basket entities.Basket
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&basket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &basket, nil
+}
+
+func (r *basketRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.Basket, error) {
+	var basket entities.Basket
+	err := r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&basket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &basket, nil
+}
+
+// GetByUserID returns the user's baskets ordered by name
+func (r *basketRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.Basket, error) {
+	var baskets []entities.Basket
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&baskets).Error
+	return baskets, err
+}
+
+func (r *basketRepository) GetAll(ctx context.Context) ([]entities.Basket, error) {
+	var baskets []entities.Basket
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&baskets).Error
+	return baskets, err
+}
+
+// Update renames the basket; its components are fixed at creation
+func (r *basketRepository) Update(ctx context.Context, basket *entities.Basket) error {
+	basket.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&entities.Basket{}).
+		Where("id = ?", basket.ID).
+		Updates(map[string]interface{}{
+			"name":       basket.Name,
+			"updated_at": basket.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *basketRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.Basket{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
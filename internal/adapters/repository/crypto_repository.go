@@ -34,7 +34,7 @@ func (r *CryptoCurrencyRepositoryImpl) GetByID(ctx context.Context, id int) (*en
 	var crypto entities.CryptoCurrency
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&crypto).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("cryptocurrency not found")
+			return nil, fmt.Errorf("cryptocurrency not found: %w", err)
 		}
 		return nil, fmt.Errorf("failed to get cryptocurrency by ID: %w", err)
 	}
@@ -46,7 +46,7 @@ func (r *CryptoCurrencyRepositoryImpl) GetBySymbol(ctx context.Context, symbol s
 	var crypto entities.CryptoCurrency
 	if err := r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&crypto).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("cryptocurrency not found")
+			return nil, fmt.Errorf("cryptocurrency not found: %w", err)
 		}
 		return nil, fmt.Errorf("failed to get cryptocurrency by symbol: %w", err)
 	}
@@ -97,6 +97,18 @@ func (r *CryptoCurrencyRepositoryImpl) Update(ctx context.Context, crypto *entit
 	return nil
 }
 
+// UpdateTicker24h replaces the 24h statistics of a symbol
+func (r *CryptoCurrencyRepositoryImpl) UpdateTicker24h(ctx context.Context, symbol string, ticker entities.Ticker24h) error {
+	err := r.db.WithContext(ctx).Model(&entities.CryptoCurrency{}).
+		Where("symbol = ?", symbol).
+		Select("last_price", "price_change_24h", "price_change_percent_24h", "volume_24h", "quote_volume_24h", "ticker_24h_at").
+		Updates(&entities.CryptoCurrency{Ticker24h: ticker}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update 24h ticker: %w", err)
+	}
+	return nil
+}
+
 // Delete deletes a cryptocurrency by ID
 func (r *CryptoCurrencyRepositoryImpl) Delete(ctx context.Context, id int) error {
 	if err := r.db.WithContext(ctx).Delete(&entities.CryptoCurrency{}, "id = ?", id).Error; err != nil {
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository creates a new device token repository
+func NewDeviceTokenRepository(db *gorm.DB) repositories.DeviceTokenRepository {
+	return &deviceTokenRepository{
+		db: db,
+	}
+}
+
+// Register creates the device, or updates the existing row holding the same token
+// so a phone that changes accounts only notifies the new one
+func (r *deviceTokenRepository) Register(ctx context.Context, device *entities.DeviceToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var existing entities.DeviceToken
+		err := tx.Where("token = ?", device.Token).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if device.ID == uuid.Nil {
+				device.ID = uuid.New()
+			}
+			device.CreatedAt = now
+			device.UpdatedAt = now
+			return tx.Create(device).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		device.ID = existing.ID
+		device.CreatedAt = existing.CreatedAt
+		device.UpdatedAt = now
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"user_id":    device.UserID,
+			"platform":   device.Platform,
+			"name":       device.Name,
+			"updated_at": now,
+		}).Error
+	})
+}
+
+func (r *deviceTokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.DeviceToken, error) {
+	var devices []entities.DeviceToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+// Delete removes one of the user's devices
+func (r *deviceTokenRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&entities.DeviceToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByTokens removes the devices whose tokens FCM rejected
+func (r *deviceTokenRepository) DeleteByTokens(ctx context.Context, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("token IN ?", tokens).
+		Delete(&entities.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type exchangePriceRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangePriceRepository creates a new per exchange price repository
+func NewExchangePriceRepository(db *gorm.DB) repositories.ExchangePriceRepository {
+	return &exchangePriceRepository{
+		db: db,
+	}
+}
+
+func (r *exchangePriceRepository) BulkInsert(ctx context.Context, prices []entities.ExchangePrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for i := range prices {
+		prices[i].CreatedAt = now
+	}
+
+	return r.db.WithContext(ctx).CreateInBatches(prices, 1000).Error
+}
+
+// GetLatest returns the latest price of the symbol on each exchange observed since
+// the given time, ordered by exchange
+func (r *exchangePriceRepository) GetLatest(ctx context.Context, symbol string, since time.Time) ([]entities.ExchangePrice, error) {
+	// The window holds a few observations per exchange, so the latest are picked here
+	var prices []entities.ExchangePrice
+	err := r.db.WithContext(ctx).
+		Where("symbol = ? AND timestamp >= ?", symbol, since).
+		Order("timestamp DESC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	latest := make([]entities.ExchangePrice, 0)
+	for _, price := range prices {
+		if !seen[price.Exchange] {
+			seen[price.Exchange] = true
+			latest = append(latest, price)
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].Exchange < latest[j].Exchange })
+	return latest, nil
+}
+
+func (r *exchangePriceRepository) GetHistory(ctx context.Context, symbol, exchange string, limit int) ([]entities.ExchangePrice, error) {
+	var prices []entities.ExchangePrice
+	query := r.db.WithContext(ctx).Where("symbol = ? AND exchange = ?", symbol, exchange).Order("timestamp DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Find(&prices).Error
+	return prices, err
+}
+
+func (r *exchangePriceRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("timestamp < ?", before).Delete(&entities.ExchangePrice{})
+	return result.RowsAffected, result.Error
+}
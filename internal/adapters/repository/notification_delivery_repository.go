@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type notificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationDeliveryRepository creates a new notification delivery repository
+func NewNotificationDeliveryRepository(db *gorm.DB) repositories.NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *entities.NotificationDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.DeliveredAt.IsZero() {
+		delivery.DeliveredAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// ChannelWindows aggregates the deliveries made since a time per channel, ordered by channel
+func (r *notificationDeliveryRepository) ChannelWindows(ctx context.Context, since time.Time) ([]entities.ChannelDeliveryWindow, error) {
+	var windows []entities.ChannelDeliveryWindow
+	err := r.db.WithContext(ctx).
+		Model(&entities.NotificationDelivery{}).
+		Select(`channel,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS sent,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failed,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS skipped,
+			SUM(CASE WHEN attempt > 1 THEN 1 ELSE 0 END) AS retries,
+			COALESCE(AVG(CASE WHEN status = ? THEN latency_ms END), 0) AS avg_latency_ms`,
+			entities.DeliveryStatusSent, entities.DeliveryStatusFailed, entities.DeliveryStatusSkipped, entities.DeliveryStatusSent).
+		Where("delivered_at >= ?", since).
+		Group("channel").
+		Order("channel").
+		Scan(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range windows {
+		windows[i].ComputeSuccessRate()
+	}
+	return windows, nil
+}
+
+// DeleteOlderThan removes the deliveries made before a time and returns how many were removed
+func (r *notificationDeliveryRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("delivered_at < ?", before).Delete(&entities.NotificationDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -50,6 +50,16 @@ func (r *priceHistoryRepository) GetLatest(ctx context.Context, symbol, timefram
 	return &history, nil
 }
 
+// GetByTimeRange returns the candles between from and to inclusive, oldest first
+func (r *priceHistoryRepository) GetByTimeRange(ctx context.Context, symbol, timeframe string, from, to time.Time) ([]entities.PriceHistory, error) {
+	var histories []entities.PriceHistory
+	err := r.db.WithContext(ctx).
+		Where("symbol = ? AND timeframe = ? AND timestamp BETWEEN ? AND ?", symbol, timeframe, from, to).
+		Order("timestamp ASC").
+		Find(&histories).Error
+	return histories, err
+}
+
 func (r *priceHistoryRepository) BulkInsert(ctx context.Context, histories []entities.PriceHistory) error {
 	if len(histories) == 0 {
 		return nil
@@ -64,6 +74,39 @@ func (r *priceHistoryRepository) BulkInsert(ctx context.Context, histories []ent
 	return r.db.WithContext(ctx).CreateInBatches(histories, 1000).Error
 }
 
+// GetSymbols returns the symbols with candles on the timeframe, ordered by symbol
+func (r *priceHistoryRepository) GetSymbols(ctx context.Context, timeframe string) ([]string, error) {
+	var symbols []string
+	err := r.db.WithContext(ctx).
+		Model(&entities.PriceHistory{}).
+		Where("timeframe = ?", timeframe).
+		Distinct("symbol").
+		Order("symbol ASC").
+		Pluck("symbol", &symbols).Error
+	return symbols, err
+}
+
+func (r *priceHistoryRepository) GetOldest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	var history entities.PriceHistory
+	err := r.db.WithContext(ctx).
+		Where("symbol = ? AND timeframe = ?", symbol, timeframe).
+		Order("timestamp ASC").
+		First(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (r *priceHistoryRepository) CountBefore(ctx context.Context, symbol, timeframe string, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.PriceHistory{}).
+		Where("symbol = ? AND timeframe = ? AND timestamp < ?", symbol, timeframe, before).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *priceHistoryRepository) DeleteOld(ctx context.Context, symbol, timeframe string, keepDays int) error {
 	cutoffDate := time.Now().AddDate(0, 0, -keepDays)
 	return r.db.WithContext(ctx).
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type priceSummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceSummaryRepository creates a new daily price summary repository
+func NewPriceSummaryRepository(db *gorm.DB) repositories.PriceSummaryRepository {
+	return &priceSummaryRepository{
+		db: db,
+	}
+}
+
+func (r *priceSummaryRepository) Upsert(ctx context.Context, summaries []entities.PriceSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for i := range summaries {
+		summaries[i].UpdatedAt = now
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "symbol"}, {Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"open_price", "high_price", "low_price", "close_price", "volume",
+				"volatility", "candles", "source_timeframe", "updated_at",
+			}),
+		}).
+		CreateInBatches(summaries, 1000).Error
+}
+
+func (r *priceSummaryRepository) GetByRange(ctx context.Context, symbol string, from, to time.Time) ([]entities.PriceSummary, error) {
+	var summaries []entities.PriceSummary
+	err := r.db.WithContext(ctx).
+		Where("symbol = ? AND day BETWEEN ? AND ?", symbol, from, to).
+		Order("day ASC").
+		Find(&summaries).Error
+	return summaries, err
+}
+
+func (r *priceSummaryRepository) GetLatest(ctx context.Context, symbol string) (*entities.PriceSummary, error) {
+	var summary entities.PriceSummary
+	err := r.db.WithContext(ctx).
+		Where("symbol = ?", symbol).
+		Order("day DESC").
+		First(&summary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
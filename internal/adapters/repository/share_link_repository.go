@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type shareLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db *gorm.DB) repositories.ShareLinkRepository {
+	return &shareLinkRepository{
+		db: db,
+	}
+}
+
+func (r *shareLinkRepository) Create(ctx context.Context, link *entities.ShareLink) error {
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *shareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error) {
+	var link entities.ShareLink
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shareLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.ShareLink, error) {
+	var links []entities.ShareLink
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&links).Error
+	return links, err
+}
+
+// Revoke revokes one of the user's links that isn't revoked yet
+func (r *shareLinkRepository) Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.ShareLink{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
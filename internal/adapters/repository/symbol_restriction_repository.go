@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type symbolRestrictionRepository struct {
+	db *gorm.DB
+}
+
+// NewSymbolRestrictionRepository creates a new symbol restriction repository
+func NewSymbolRestrictionRepository(db *gorm.DB) repositories.SymbolRestrictionRepository {
+	return &symbolRestrictionRepository{
+		db: db,
+	}
+}
+
+func (r *symbolRestrictionRepository) Upsert(ctx context.Context, restriction *entities.SymbolRestriction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing entities.SymbolRestriction
+		err := tx.Where("symbol = ?", restriction.Symbol).First(&existing).Error
+		now := time.Now()
+
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if restriction.ID == uuid.Nil {
+				restriction.ID = uuid.New()
+			}
+			restriction.CreatedAt = now
+			restriction.UpdatedAt = now
+			return tx.Create(restriction).Error
+		case err != nil:
+			return err
+		}
+
+		restriction.ID = existing.ID
+		restriction.CreatedAt = existing.CreatedAt
+		restriction.UpdatedAt = now
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"level":      restriction.Level,
+			"reason":     restriction.Reason,
+			"created_by": restriction.CreatedBy,
+			"updated_at": now,
+		}).Error
+	})
+}
+
+func (r *symbolRestrictionRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.SymbolRestriction, error) {
+	var restriction entities.SymbolRestriction
+	err := r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&restriction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+func (r *symbolRestrictionRepository) GetAll(ctx context.Context) ([]entities.SymbolRestriction, error) {
+	var restrictions []entities.SymbolRestriction
+	err := r.db.WithContext(ctx).Order("symbol ASC").Find(&restrictions).Error
+	return restrictions, err
+}
+
+func (r *symbolRestrictionRepository) Delete(ctx context.Context, symbol string) error {
+	result := r.db.WithContext(ctx).Where("symbol = ?", symbol).Delete(&entities.SymbolRestriction{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
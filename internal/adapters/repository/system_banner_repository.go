@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+type systemBannerRepository struct {
+	db *gorm.DB
+}
+
+// NewSystemBannerRepository creates a new system banner repository
+func NewSystemBannerRepository(db *gorm.DB) repositories.SystemBannerRepository {
+	return &systemBannerRepository{
+		db: db,
+	}
+}
+
+func (r *systemBannerRepository) Create(ctx context.Context, banner *entities.SystemBanner) error {
+	if banner.ID == uuid.Nil {
+		banner.ID = uuid.New()
+	}
+	if banner.CreatedAt.IsZero() {
+		banner.CreatedAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Create(banner).Error
+}
+
+func (r *systemBannerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.SystemBanner, error) {
+	var banner entities.SystemBanner
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&banner).Error
+	if err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+func (r *systemBannerRepository) GetActive(ctx context.Context, now time.Time) ([]entities.SystemBanner, error) {
+	var banners []entities.SystemBanner
+	err := r.db.WithContext(ctx).
+		Where("revoked_at IS NULL AND expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&banners).Error
+	return banners, err
+}
+
+func (r *systemBannerRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.SystemBanner{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -52,6 +54,84 @@ func (r *technicalIndicatorRepository) GetLatest(ctx context.Context, symbol, ti
 	return &indicator, nil
 }
 
+func (r *technicalIndicatorRepository) GetByKey(ctx context.Context, symbol, timeframe, indicatorKey string, limit int) ([]entities.TechnicalIndicator, error) {
+	var indicators []entities.TechnicalIndicator
+	query := r.db.WithContext(ctx).
+		Where("symbol = ? AND timeframe = ? AND indicator_key = ?", symbol, timeframe, indicatorKey).
+		Order("timestamp DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Find(&indicators).Error
+	return indicators, err
+}
+
+func (r *technicalIndicatorRepository) GetLatestByKey(ctx context.Context, symbol, timeframe, indicatorKey string) (*entities.TechnicalIndicator, error) {
+	var indicator entities.TechnicalIndicator
+	err := r.db.WithContext(ctx).
+		Where("symbol = ? AND timeframe = ? AND indicator_key = ?", symbol, timeframe, indicatorKey).
+		Order("timestamp DESC").
+		First(&indicator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &indicator, nil
+}
+
+func (r *technicalIndicatorRepository) GetIndicatorKeys(ctx context.Context, symbol, timeframe string) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).
+		Model(&entities.TechnicalIndicator{}).
+		Where("symbol = ? AND timeframe = ?", symbol, timeframe).
+		Distinct("indicator_key").
+		Order("indicator_key").
+		Pluck("indicator_key", &keys).Error
+	return keys, err
+}
+
+func (r *technicalIndicatorRepository) GetByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}, limit int) ([]entities.TechnicalIndicator, error) {
+	query, err := r.metadataQuery(ctx, symbol, timeframe, indicatorType, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var indicators []entities.TechnicalIndicator
+	err = query.Find(&indicators).Error
+	return indicators, err
+}
+
+func (r *technicalIndicatorRepository) GetLatestByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}) (*entities.TechnicalIndicator, error) {
+	query, err := r.metadataQuery(ctx, symbol, timeframe, indicatorType, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var indicator entities.TechnicalIndicator
+	if err := query.First(&indicator).Error; err != nil {
+		return nil, err
+	}
+	return &indicator, nil
+}
+
+// metadataQuery filters with JSONB containment, which the GIN index on metadata serves
+func (r *technicalIndicatorRepository) metadataQuery(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}) (*gorm.DB, error) {
+	filter, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata filter: %w", err)
+	}
+
+	return r.db.WithContext(ctx).
+		Where("symbol = ? AND timeframe = ? AND indicator_type = ?", symbol, timeframe, indicatorType).
+		Where("metadata @> ?::jsonb", string(filter)).
+		Order("timestamp DESC"), nil
+}
+
 func (r *technicalIndicatorRepository) BulkInsert(ctx context.Context, indicators []entities.TechnicalIndicator) error {
 	if len(indicators) == 0 {
 		return nil
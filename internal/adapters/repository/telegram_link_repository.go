@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// ValueCipher encrypts channel credentials before they are stored, binding them to
+// their owner; *encryption.Envelope implements it
+type ValueCipher interface {
+	Encrypt(ctx context.Context, userID uuid.UUID, plaintext string) (string, error)
+	Decrypt(ctx context.Context, userID uuid.UUID, value string) (string, error)
+}
+
+type telegramLinkRepository struct {
+	db     *gorm.DB
+	cipher ValueCipher
+}
+
+// NewTelegramLinkRepository creates a new Telegram link repository. Chat IDs are
+// encrypted with cipher, or stored as is when it is nil.
+func NewTelegramLinkRepository(db *gorm.DB, cipher ValueCipher) repositories.TelegramLinkRepository {
+	return &telegramLinkRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+func (r *telegramLinkRepository) Save(ctx context.Context, link *entities.TelegramLink) error {
+	now := time.Now()
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
+	}
+	link.UpdatedAt = now
+
+	row := *link
+	if r.cipher != nil {
+		chatID, err := r.cipher.Encrypt(ctx, link.UserID, link.ChatID)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt chat ID: %w", err)
+		}
+		row.ChatID = chatID
+	}
+
+	return r.db.WithContext(ctx).Save(&row).Error
+}
+
+func (r *telegramLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.TelegramLink, error) {
+	return r.first(ctx, "user_id = ?", userID)
+}
+
+func (r *telegramLinkRepository) GetByCodeHash(ctx context.Context, codeHash string) (*entities.TelegramLink, error) {
+	return r.first(ctx, "code_hash = ?", codeHash)
+}
+
+// Delete removes the user's link
+func (r *telegramLinkRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.TelegramLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *telegramLinkRepository) first(ctx context.Context, query string, arg interface{}) (*entities.TelegramLink, error) {
+	var link entities.TelegramLink
+	if err := r.db.WithContext(ctx).Where(query, arg).First(&link).Error; err != nil {
+		return nil, err
+	}
+
+	if r.cipher != nil {
+		chatID, err := r.cipher.Decrypt(ctx, link.UserID, link.ChatID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt chat ID: %w", err)
+		}
+		link.ChatID = chatID
+	}
+	return &link, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
@@ -66,6 +67,18 @@ func (r *UserRepositoryImpl) GetByGoogleID(ctx context.Context, googleID string)
 	return &user, nil
 }
 
+// GetSandboxCreatedBefore retrieves the sandbox users created before the time
+func (r *UserRepositoryImpl) GetSandboxCreatedBefore(ctx context.Context, before time.Time) ([]entities.User, error) {
+	var users []entities.User
+	if err := r.db.WithContext(ctx).
+		Where("sandbox = ? AND created_at < ?", true, before).
+		Order("created_at ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sandbox users: %w", err)
+	}
+	return users, nil
+}
+
 // Update updates an existing user
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *entities.User) error {
 	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
@@ -42,6 +42,17 @@ func (r *UserSettingsRepositoryImpl) GetByUserID(ctx context.Context, userID uui
 	return &settings, nil
 }
 
+// GetWithReportsEnabled retrieves every user settings row with a report schedule
+func (r *UserSettingsRepositoryImpl) GetWithReportsEnabled(ctx context.Context) ([]entities.UserSettings, error) {
+	var settings []entities.UserSettings
+	if err := r.db.WithContext(ctx).
+		Where("report_frequency IS NOT NULL AND report_frequency <> ?", "none").
+		Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user settings with reports enabled: %w", err)
+	}
+	return settings, nil
+}
+
 // Update updates existing user settings
 func (r *UserSettingsRepositoryImpl) Update(ctx context.Context, settings *entities.UserSettings) error {
 	if err := r.db.WithContext(ctx).Save(settings).Error; err != nil {
@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+)
+
+// alertAckTimeout bounds an alert acknowledgement request
+const alertAckTimeout = 5 * time.Second
+
+// AlertAckSource acknowledges the trigger of one of the user's alerts
+type AlertAckSource func(ctx context.Context, userID, alertID uuid.UUID) (*entities.Alert, error)
+
+// AlertAckRequest is the payload of an "ack_alert" message
+type AlertAckRequest struct {
+	AlertID string `json:"alert_id"`
+}
+
+// AlertAckReply is the payload of the "alert_acknowledged" reply
+type AlertAckReply struct {
+	AlertID        uuid.UUID  `json:"alert_id"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+// SetAlertAckSource lets clients acknowledge triggered alerts with "ack_alert"
+// messages, stopping their escalation from the notification they were shown
+func (h *Hub) SetAlertAckSource(source AlertAckSource) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.alertAcks = source
+}
+
+// handleAckAlert acknowledges the alert and replies with the acknowledgement time;
+// the reply carries the request ID so it can be matched to the request
+func (c *Client) handleAckAlert(msg WebSocketMessage) {
+	c.Hub.mutex.RLock()
+	source := c.Hub.alertAcks
+	c.Hub.mutex.RUnlock()
+
+	if source == nil {
+		c.sendRequestError(msg.ID, "alert acknowledgements are not available over this connection")
+		return
+	}
+
+	var req AlertAckRequest
+	dataBytes, err := json.Marshal(msg.Data)
+	if err == nil {
+		err = json.Unmarshal(dataBytes, &req)
+	}
+	alertID, parseErr := uuid.Parse(req.AlertID)
+	if err != nil || parseErr != nil {
+		c.SendMessage(WebSocketMessage{
+			ID:   msg.ID,
+			Type: "error",
+			Data: &ProtocolError{Code: ErrCodeInvalidPayload, Message: "alert_id must be a UUID", Field: "alert_id", RequestID: msg.ID},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertAckTimeout)
+	defer cancel()
+
+	alert, err := source(ctx, c.UserID, alertID)
+	if err != nil {
+		var domainErr *entities.DomainError
+		if errors.As(err, &domainErr) {
+			c.sendRequestError(msg.ID, domainErr.Error())
+			return
+		}
+		c.Hub.logger.WithFields(logrus.Fields{
+			"client_id": c.ID,
+			"user_id":   c.UserID,
+			"alert_id":  alertID,
+		}).WithError(err).Error("Failed to acknowledge alert for WebSocket client")
+		c.sendRequestError(msg.ID, "failed to acknowledge alert")
+		return
+	}
+
+	c.SendMessage(WebSocketMessage{
+		ID:   msg.ID,
+		Type: "alert_acknowledged",
+		Data: &AlertAckReply{AlertID: alert.ID, AcknowledgedAt: alert.AcknowledgedAt},
+	})
+}
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/sirupsen/logrus"
+)
+
+// alertLevelTimeout bounds the lookup done while answering a subscription
+const alertLevelTimeout = 2 * time.Second
+
+// AlertLevelSource looks up a user's active alert levels on a symbol
+type AlertLevelSource func(ctx context.Context, userID uuid.UUID, symbol string) ([]services.AlertLevel, error)
+
+// SetAlertLevelSource makes symbol room subscriptions reply with the subscriber's alert
+// levels on that symbol, so charts can draw them without another API call
+func (h *Hub) SetAlertLevelSource(source AlertLevelSource) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.alertLevels = source
+}
+
+// alertLevelsFor returns the levels to include in a subscription reply; ok is false
+// when the room is not a symbol room or the levels could not be loaded
+func (h *Hub) alertLevelsFor(client *Client, roomID string) ([]services.AlertLevel, bool) {
+	h.mutex.RLock()
+	source := h.alertLevels
+	h.mutex.RUnlock()
+
+	roomType, symbol, valid := ParseRoom(roomID)
+	if source == nil || !valid || roomType != RoomTypeSymbol {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertLevelTimeout)
+	defer cancel()
+
+	levels, err := source(ctx, client.UserID, symbol)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"client_id": client.ID,
+			"room_id":   roomID,
+		}).WithError(err).Warn("Failed to load alert levels for subscription")
+		return nil, false
+	}
+	return levels, true
+}
@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RoomType groups rooms that share the same authorization rule
+type RoomType string
+
+const (
+	// RoomTypePublic rooms carry data every authenticated user may see
+	RoomTypePublic RoomType = "public"
+	// RoomTypeSymbol rooms stream market data for a single symbol and are open to everyone
+	RoomTypeSymbol RoomType = "symbol"
+	// RoomTypeUser rooms carry one user's private data and only that user may join
+	RoomTypeUser RoomType = "user"
+)
+
+// Rejection codes sent back to clients whose subscription is refused
+const (
+	RoomRejectInvalid   = "invalid_room"
+	RoomRejectUnknown   = "unknown_room"
+	RoomRejectForbidden = "forbidden"
+)
+
+// publicRooms are joined by name, without a symbol or user suffix
+var publicRooms = map[string]bool{
+	SystemRoom:       true,
+	"market_summary": true,
+}
+
+// roomPrefixes maps "<prefix>_<key>" or "<prefix>:<key>" rooms to their type
+var roomPrefixes = map[string]RoomType{
+	"crypto":        RoomTypeSymbol,
+	"indicators":    RoomTypeSymbol,
+	"pullback":      RoomTypeSymbol,
+	"orderbook":     RoomTypeSymbol,
+	"user":          RoomTypeUser,
+	"alerts":        RoomTypeUser,
+	"notifications": RoomTypeUser,
+}
+
+// RoomAccessError explains why a client may not join a room
+type RoomAccessError struct {
+	Room   string `json:"room"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e *RoomAccessError) Error() string {
+	return fmt.Sprintf("cannot join room %q: %s", e.Room, e.Reason)
+}
+
+// WatchlistRoomPrefix starts the rooms streaming a user's watchlist percent changes
+const WatchlistRoomPrefix = "watchlist_user_"
+
+// UserRoom returns the private room of a user
+func UserRoom(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// WatchlistRoom returns the room streaming a user's watchlist percent changes
+func WatchlistRoom(userID uuid.UUID) string {
+	return WatchlistRoomPrefix + userID.String()
+}
+
+// ParseRoom splits a room ID into its type and key (symbol or user ID)
+func ParseRoom(roomID string) (RoomType, string, bool) {
+	if publicRooms[roomID] {
+		return RoomTypePublic, "", true
+	}
+
+	// The watchlist prefix has a separator of its own
+	if userKey, ok := strings.CutPrefix(roomID, WatchlistRoomPrefix); ok && userKey != "" {
+		return RoomTypeUser, userKey, true
+	}
+
+	separator := strings.IndexAny(roomID, ":_")
+	if separator <= 0 || separator == len(roomID)-1 {
+		return "", "", false
+	}
+
+	roomType, ok := roomPrefixes[roomID[:separator]]
+	if !ok {
+		return "", "", false
+	}
+	return roomType, roomID[separator+1:], true
+}
+
+// AuthorizeRoom checks whether a user may join a room: public and symbol rooms are
+// open, user rooms require the user ID in the room to match, anything else is refused
+func AuthorizeRoom(userID uuid.UUID, roomID string) error {
+	if roomID == "" {
+		return &RoomAccessError{Room: roomID, Code: RoomRejectInvalid, Reason: "room is required"}
+	}
+
+	roomType, key, ok := ParseRoom(roomID)
+	if !ok {
+		return &RoomAccessError{Room: roomID, Code: RoomRejectUnknown, Reason: "room does not exist"}
+	}
+
+	switch roomType {
+	case RoomTypeSymbol:
+		if !isSymbol(key) {
+			return &RoomAccessError{Room: roomID, Code: RoomRejectInvalid, Reason: "invalid symbol"}
+		}
+	case RoomTypeUser:
+		owner, err := uuid.Parse(key)
+		if err != nil {
+			return &RoomAccessError{Room: roomID, Code: RoomRejectInvalid, Reason: "invalid user ID"}
+		}
+		if owner != userID {
+			return &RoomAccessError{Room: roomID, Code: RoomRejectForbidden, Reason: "room belongs to another user"}
+		}
+	}
+
+	return nil
+}
+
+func isSymbol(value string) bool {
+	for _, r := range value {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
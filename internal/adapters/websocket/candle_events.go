@@ -0,0 +1,53 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// indicatorTimeframe is the timeframe of the indicators and pullback signals broadcast
+// to the indicator rooms
+const indicatorTimeframe = "1h"
+
+// SetCandleEvents refreshes indicators and evaluates alerts when candles close instead
+// of on the worker's own tickers: indicators and pullback signals on each hourly
+// close, the alerts of a symbol on each of its minute closes. It must be called
+// before Start.
+func (w *Worker) SetCandleEvents(bus *services.CandleEventBus) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.candleEvents = bus
+
+	bus.Subscribe(indicatorTimeframe, "websocket_indicators", w.onIndicatorCandleClosed)
+	bus.Subscribe("1m", "websocket_alerts", w.onAlertCandleClosed)
+}
+
+// running reports whether the worker is started; stopped workers ignore candle closes
+func (w *Worker) running() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.isRunning
+}
+
+func (w *Worker) onIndicatorCandleClosed(ctx context.Context, event services.CandleClosed) error {
+	if !w.running() {
+		return nil
+	}
+	w.refreshIndicators(ctx, event.Symbol, event.Timeframe)
+	return nil
+}
+
+func (w *Worker) onAlertCandleClosed(ctx context.Context, event services.CandleClosed) error {
+	if !w.running() || w.alertEngine == nil {
+		return nil
+	}
+
+	results, err := w.alertEngine.EvaluateSymbolAlerts(ctx, event.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate alerts on %s: %w", event.Symbol, err)
+	}
+	w.processAlertResults(ctx, results)
+	return nil
+}
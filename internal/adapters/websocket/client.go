@@ -2,9 +2,11 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -49,10 +51,18 @@ func (c *Client) readPump() {
 		c.LastSeen = time.Now()
 		c.mutex.Unlock()
 
-		// Parse message
-		var msg WebSocketMessage
-		if err := json.Unmarshal(messageBytes, &msg); err != nil {
-			c.Hub.logger.WithError(err).Error("Failed to parse WebSocket message")
+		// Parse and validate message
+		msg, perr := DecodeMessage(messageBytes, c.protocolVersion)
+		if perr != nil {
+			c.Hub.logger.WithFields(logrus.Fields{
+				"client_id": c.ID,
+				"code":      perr.Code,
+			}).Debug("Rejected WebSocket message")
+			c.SendMessage(WebSocketMessage{
+				ID:   perr.RequestID,
+				Type: "error",
+				Data: perr,
+			})
 			continue
 		}
 
@@ -79,17 +89,27 @@ func (c *Client) writePump() {
 				return
 			}
 
+			// Add queued chat messages to the current websocket message
+			frame := [][]byte{message}
+			size := len(message)
+			n := len(c.Send)
+			for i := 0; i < n; i++ {
+				queued := <-c.Send
+				frame = append(frame, queued)
+				size += 1 + len(queued)
+			}
+
+			// Only frames large enough to benefit are compressed
+			c.Conn.EnableWriteCompression(c.compressFrame(size))
 			w, err := c.Conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
 			}
-			w.Write(message)
-
-			// Add queued chat messages to the current websocket message
-			n := len(c.Send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte("\n"))
-				w.Write(<-c.Send)
+			for i, part := range frame {
+				if i > 0 {
+					w.Write([]byte("\n"))
+				}
+				w.Write(part)
 			}
 
 			if err := w.Close(); err != nil {
@@ -107,6 +127,10 @@ func (c *Client) writePump() {
 
 // SendMessage sends a message to the client
 func (c *Client) SendMessage(message WebSocketMessage) {
+	if message.Version == 0 {
+		message.Version = c.protocolVersion
+	}
+
 	messageBytes, err := json.Marshal(message)
 	if err != nil {
 		c.Hub.logger.WithError(err).Error("Failed to marshal message")
@@ -115,7 +139,9 @@ func (c *Client) SendMessage(message WebSocketMessage) {
 
 	select {
 	case c.Send <- messageBytes:
+		c.Hub.stats.sent.Add(1)
 	default:
+		c.Hub.stats.dropped.Add(1)
 		close(c.Send)
 		delete(c.Hub.clients, c.ID)
 	}
@@ -129,9 +155,13 @@ func (c *Client) handleMessage(msg WebSocketMessage) {
 	case "unsubscribe":
 		c.handleUnsubscribe(msg)
 	case "ping":
-		c.handlePing()
-	default:
-		c.Hub.logger.WithField("type", msg.Type).Warn("Unknown message type")
+		c.handlePing(msg)
+	case "stats":
+		c.handleStats(msg)
+	case "get_notifications":
+		c.handleGetNotifications(msg)
+	case "ack_alert":
+		c.handleAckAlert(msg)
 	}
 }
 
@@ -151,15 +181,31 @@ func (c *Client) handleSubscribe(msg WebSocketMessage) {
 	}
 
 	// Join the requested room
-	c.Hub.joinRoom(c, subMsg.Room)
+	if err := c.Hub.JoinRoom(c, subMsg.Room); err != nil {
+		var rejection *RoomAccessError
+		if !errors.As(err, &rejection) {
+			rejection = &RoomAccessError{Room: subMsg.Room, Code: RoomRejectInvalid, Reason: err.Error()}
+		}
+		c.SendMessage(WebSocketMessage{
+			ID:   msg.ID,
+			Type: "subscription_rejected",
+			Data: rejection,
+		})
+		return
+	}
 
-	// Send confirmation
+	// Send confirmation, with the user's alert lines when subscribing to a symbol
+	data := map[string]interface{}{
+		"room":   subMsg.Room,
+		"symbol": subMsg.Symbol,
+	}
+	if levels, ok := c.Hub.alertLevelsFor(c, subMsg.Room); ok {
+		data["alert_levels"] = levels
+	}
 	response := WebSocketMessage{
+		ID:   msg.ID,
 		Type: "subscribed",
-		Data: map[string]interface{}{
-			"room":   subMsg.Room,
-			"symbol": subMsg.Symbol,
-		},
+		Data: data,
 	}
 	c.SendMessage(response)
 }
@@ -184,6 +230,7 @@ func (c *Client) handleUnsubscribe(msg WebSocketMessage) {
 
 	// Send confirmation
 	response := WebSocketMessage{
+		ID:   msg.ID,
 		Type: "unsubscribed",
 		Data: map[string]interface{}{
 			"room": subMsg.Room,
@@ -193,8 +240,9 @@ func (c *Client) handleUnsubscribe(msg WebSocketMessage) {
 }
 
 // handlePing handles ping messages
-func (c *Client) handlePing() {
+func (c *Client) handlePing(msg WebSocketMessage) {
 	response := WebSocketMessage{
+		ID:   msg.ID,
 		Type: "pong",
 		Data: map[string]interface{}{
 			"timestamp": time.Now(),
@@ -202,3 +250,12 @@ func (c *Client) handlePing() {
 	}
 	c.SendMessage(response)
 }
+
+// handleStats replies with connection statistics for the rooms the client may join
+func (c *Client) handleStats(msg WebSocketMessage) {
+	c.SendMessage(WebSocketMessage{
+		ID:   msg.ID,
+		Type: "stats",
+		Data: c.Hub.visibleStats(c.UserID),
+	})
+}
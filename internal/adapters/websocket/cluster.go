@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// relayRetryInterval is how long the hub waits before resubscribing to a failed relay
+const relayRetryInterval = time.Second
+
+// ResumeState is what a suspended session needs to be resumed on any instance
+type ResumeState struct {
+	UserID uuid.UUID `json:"user_id"`
+	Rooms  []string  `json:"rooms"`
+}
+
+// ResumeStore shares suspended sessions between instances, so a reconnect doesn't
+// have to land on the instance that served the dropped connection
+type ResumeStore interface {
+	Save(ctx context.Context, token string, state ResumeState, ttl time.Duration) error
+	// Take returns and deletes a session; it returns nil when the token is unknown or expired
+	Take(ctx context.Context, token string) (*ResumeState, error)
+}
+
+// RelayedMessage is a broadcast forwarded between instances. Room broadcasts set
+// Room and user broadcasts set UserID.
+type RelayedMessage struct {
+	Origin string          `json:"origin"`
+	Room   string          `json:"room,omitempty"`
+	UserID *uuid.UUID      `json:"user_id,omitempty"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// BroadcastRelay forwards broadcasts to the hubs of the other instances, so any
+// instance can serve any client without sticky sessions
+type BroadcastRelay interface {
+	Publish(ctx context.Context, message *RelayedMessage) error
+	// Subscribe delivers the messages published by every instance until ctx is done
+	Subscribe(ctx context.Context, deliver func(*RelayedMessage)) error
+}
+
+// SetResumeStore shares suspended sessions through store; it must be called before Start
+func (h *Hub) SetResumeStore(store ResumeStore) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.resumeStore = store
+}
+
+// SetRelay forwards this hub's broadcasts to other instances and delivers theirs
+// locally; instanceID tells this hub's own messages apart. It must be called before Start.
+func (h *Hub) SetRelay(relay BroadcastRelay, instanceID string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.relay = relay
+	h.instanceID = instanceID
+}
+
+// runRelay receives the other instances' broadcasts until the hub stops,
+// resubscribing when the relay connection drops
+func (h *Hub) runRelay() {
+	defer h.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-h.stopChan
+		cancel()
+	}()
+
+	for {
+		err := h.relay.Subscribe(ctx, h.deliverRelayed)
+		if ctx.Err() != nil {
+			return
+		}
+		h.logger.WithError(err).Warn("Broadcast relay subscription ended, resubscribing")
+
+		select {
+		case <-time.After(relayRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverRelayed hands a broadcast from another instance to the local clients
+func (h *Hub) deliverRelayed(message *RelayedMessage) {
+	if message.Origin == h.instanceID {
+		return
+	}
+
+	if message.UserID != nil {
+		h.sendToUser(*message.UserID, message.Type, message.Data)
+		return
+	}
+
+	select {
+	case h.shardFor(message.Room).broadcast <- &BroadcastMessage{
+		Room: message.Room,
+		Type: message.Type,
+		Data: message.Data,
+	}:
+	case <-h.stopChan:
+	}
+}
+
+// publish forwards a broadcast to the other instances when a relay is set
+func (h *Hub) publish(message *RelayedMessage, data interface{}) {
+	if h.relay == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to marshal relayed broadcast")
+		return
+	}
+	message.Origin = h.instanceID
+	message.Data = encoded
+
+	if err := h.relay.Publish(context.Background(), message); err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"type": message.Type,
+			"room": message.Room,
+		}).Error("Failed to relay broadcast to other instances")
+	}
+}
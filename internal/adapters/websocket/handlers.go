@@ -56,8 +56,8 @@ func (h *WebSocketHandler) BroadcastCryptoDataUpdate(symbol string, priceData *e
 		"timeframe":   priceData.Timeframe,
 	}
 
-	// Broadcast to room
-	h.hub.Broadcast(room, "crypto_data_update", data)
+	// Every instance's worker sends this update itself, so it isn't relayed
+	h.hub.BroadcastLocal(room, "crypto_data_update", data)
 
 	h.logger.WithFields(logrus.Fields{
 		"symbol":      symbol,
@@ -100,7 +100,7 @@ func (h *WebSocketHandler) BroadcastTechnicalIndicatorUpdate(symbol string, indi
 		"timestamp":  time.Now(),
 	}
 
-	h.hub.Broadcast(room, "technical_indicator_update", data)
+	h.hub.BroadcastLocal(room, "technical_indicator_update", data)
 
 	h.logger.WithFields(logrus.Fields{
 		"symbol": symbol,
@@ -118,7 +118,7 @@ func (h *WebSocketHandler) BroadcastPullbackSignal(symbol string, signal map[str
 		"timestamp": time.Now(),
 	}
 
-	h.hub.Broadcast(room, "pullback_signal", data)
+	h.hub.BroadcastLocal(room, "pullback_signal", data)
 
 	h.logger.WithFields(logrus.Fields{
 		"symbol": symbol,
@@ -135,7 +135,7 @@ func (h *WebSocketHandler) BroadcastMarketSummary(summary map[string]interface{}
 		"timestamp": time.Now(),
 	}
 
-	h.hub.Broadcast(room, "market_summary_update", data)
+	h.hub.BroadcastLocal(room, "market_summary_update", data)
 
 	h.logger.WithField("room", room).Debug("Broadcasted market summary")
 }
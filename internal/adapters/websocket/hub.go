@@ -11,9 +11,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
 	"github.com/sirupsen/logrus"
 )
 
+// SystemRoom is joined by every client on connect so system-wide alerts reach everyone
+const SystemRoom = "system"
+
 // Hub manages WebSocket connections and broadcasting
 // AuthService defines the token validation behavior required by the hub
 type AuthService interface {
@@ -21,16 +25,34 @@ type AuthService interface {
 }
 
 type Hub struct {
-	clients     map[string]*Client     // Connected clients
-	rooms       map[string]*Room       // Chat rooms/channels
-	register    chan *Client           // Register requests from clients
-	unregister  chan *Client           // Unregister requests from clients
-	broadcast   chan *BroadcastMessage // Broadcast messages
+	clients     map[string]*Client // Connected clients
+	shards      []*hubShard        // Rooms, partitioned across broadcast workers
+	register    chan *Client       // Register requests from clients
+	unregister  chan *Client       // Unregister requests from clients
 	authService AuthService
 	logger      *logrus.Logger
 	mutex       sync.RWMutex
 	stopChan    chan struct{}
+	stopped     bool
 	wg          sync.WaitGroup
+
+	sessions    map[string]*resumeSession // Resume token -> session
+	resumeTTL   time.Duration
+	resumeStore ResumeStore // Optional, lets other instances resume this hub's sessions
+	clock       clock.Clock
+
+	relay      BroadcastRelay // Optional, exchanges broadcasts with other instances
+	instanceID string
+	presence   PresenceStore // Optional, shares which users are connected
+
+	alertLevels   AlertLevelSource       // Optional, fills symbol subscription snapshots
+	notifications NotificationPageSource // Optional, serves "get_notifications"
+	alertAcks     AlertAckSource         // Optional, serves "ack_alert"
+	stats         hubStats
+
+	upgrader             websocket.Upgrader
+	compressionLevel     int // Deflate level of connections that negotiated compression
+	compressionThreshold int // Smallest frame compressed, in bytes
 }
 
 // Client represents a WebSocket client
@@ -43,6 +65,12 @@ type Client struct {
 	Rooms    map[string]bool `json:"rooms"`
 	LastSeen time.Time       `json:"last_seen"`
 	mutex    sync.RWMutex
+
+	resumeToken     string // Issued to this connection
+	requestedResume string // Sent by the client to restore a previous session
+	protocolVersion int    // Negotiated at connect
+
+	compressionThreshold int // Smallest frame compressed, if compression was negotiated
 }
 
 // Room represents a WebSocket room/channel
@@ -62,8 +90,10 @@ type BroadcastMessage struct {
 
 // WebSocketMessage represents incoming/outgoing WebSocket messages
 type WebSocketMessage struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	Version int         `json:"v,omitempty"`
+	ID      string      `json:"id,omitempty"` // Request ID echoed in replies
+	Type    string      `json:"type"`
+	Data    interface{} `json:"data"`
 }
 
 // SubscribeMessage represents subscription messages
@@ -72,34 +102,70 @@ type SubscribeMessage struct {
 	Symbol string `json:"symbol,omitempty"`
 }
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow all origins in development
-		// In production, implement proper origin checking
-		return true
-	},
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-}
-
 // NewHub creates a new WebSocket hub
 func NewHub(authService AuthService, logger *logrus.Logger) *Hub {
-	return &Hub{
+	hub := &Hub{
 		clients:     make(map[string]*Client),
-		rooms:       make(map[string]*Room),
+		shards:      newShards(DefaultBroadcastWorkers, DefaultBroadcastQueueSize),
 		register:    make(chan *Client),
 		unregister:  make(chan *Client),
-		broadcast:   make(chan *BroadcastMessage, 256),
 		authService: authService,
 		logger:      logger,
 		stopChan:    make(chan struct{}),
+		sessions:    make(map[string]*resumeSession),
+		resumeTTL:   DefaultResumeTTL,
+		clock:       clock.New(),
+
+		upgrader:             newUpgrader(false),
+		compressionLevel:     DefaultCompressionLevel,
+		compressionThreshold: DefaultCompressionThreshold,
 	}
+	hub.stats.sampledAt = hub.clock.Now()
+	return hub
+}
+
+// SetClock replaces the clock used to expire resume tokens and sample message
+// rates (used by tests)
+func (h *Hub) SetClock(c clock.Clock) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.clock = c
+
+	h.stats.mutex.Lock()
+	h.stats.sampledAt = c.Now()
+	h.stats.mutex.Unlock()
 }
 
-// Start starts the WebSocket hub
+// Start starts the WebSocket hub and its broadcast workers. It returns at once if
+// the hub was already stopped, which can happen when Start runs in its own goroutine.
 func (h *Hub) Start() {
-	h.logger.Info("Starting WebSocket hub")
+	h.mutex.Lock()
+	if h.stopped {
+		h.mutex.Unlock()
+		return
+	}
 	h.wg.Add(1)
+	h.mutex.Unlock()
+
+	h.logger.WithField("broadcast_workers", len(h.shards)).Info("Starting WebSocket hub")
+
+	for _, shard := range h.shards {
+		h.wg.Add(1)
+		go h.runShard(shard)
+	}
+
+	if h.relay != nil {
+		h.wg.Add(1)
+		go h.runRelay()
+	}
+
+	// Without a presence store the refresh channel stays nil and never fires
+	var refreshPresence <-chan time.Time
+	if h.presence != nil {
+		ticker := time.NewTicker(presenceRefreshInterval)
+		defer ticker.Stop()
+		refreshPresence = ticker.C
+	}
 
 	for {
 		select {
@@ -109,8 +175,8 @@ func (h *Hub) Start() {
 		case client := <-h.unregister:
 			h.unregisterClient(client)
 
-		case message := <-h.broadcast:
-			h.broadcastToRoom(message)
+		case <-refreshPresence:
+			h.refreshPresence()
 
 		case <-h.stopChan:
 			h.logger.Info("Stopping WebSocket hub")
@@ -126,21 +192,41 @@ func (h *Hub) registerClient(client *Client) {
 	defer h.mutex.Unlock()
 
 	h.clients[client.ID] = client
+	h.addToRoom(client, SystemRoom)
+	h.announcePresence(client.UserID)
+
+	var resume ResumeResult
+	if client.requestedResume != "" {
+		resume = h.resumeSession(client, client.requestedResume)
+	}
+	h.issueResumeToken(client)
 
 	h.logger.WithFields(logrus.Fields{
 		"client_id": client.ID,
 		"user_id":   client.UserID,
+		"resumed":   resume.Resumed,
 	}).Info("Client registered")
 
 	// Send welcome message
-	welcomeMsg := WebSocketMessage{
-		Type: "welcome",
-		Data: map[string]interface{}{
-			"client_id": client.ID,
-			"timestamp": time.Now(),
-		},
+	welcome := map[string]interface{}{
+		"client_id":         client.ID,
+		"timestamp":         time.Now(),
+		"resume_token":      client.resumeToken,
+		"resume_expires_in": int(h.resumeTTL.Seconds()),
+		"protocol_version":  client.protocolVersion,
+	}
+	if client.requestedResume != "" {
+		welcome["resumed"] = resume.Resumed
+		if resume.Resumed {
+			welcome["rooms"] = resume.Rooms
+		} else {
+			welcome["resume_error"] = resume.Error
+		}
 	}
-	client.SendMessage(welcomeMsg)
+	client.SendMessage(WebSocketMessage{
+		Type: "welcome",
+		Data: welcome,
+	})
 }
 
 // unregisterClient unregisters a client
@@ -149,6 +235,9 @@ func (h *Hub) unregisterClient(client *Client) {
 	defer h.mutex.Unlock()
 
 	if _, exists := h.clients[client.ID]; exists {
+		// Remember the subscriptions so a reconnect can resume them
+		h.suspendSession(client)
+
 		// Remove from all rooms
 		for roomID := range client.Rooms {
 			h.leaveRoom(client, roomID)
@@ -156,6 +245,7 @@ func (h *Hub) unregisterClient(client *Client) {
 
 		delete(h.clients, client.ID)
 		close(client.Send)
+		h.withdrawPresence(client.UserID)
 
 		h.logger.WithFields(logrus.Fields{
 			"client_id": client.ID,
@@ -164,22 +254,13 @@ func (h *Hub) unregisterClient(client *Client) {
 	}
 }
 
-// broadcastToRoom broadcasts a message to all clients in a room
-func (h *Hub) broadcastToRoom(message *BroadcastMessage) {
-	h.mutex.RLock()
-	room, exists := h.rooms[message.Room]
-	h.mutex.RUnlock()
-
-	if !exists {
-		return
-	}
-
-	room.mutex.RLock()
-	defer room.mutex.RUnlock()
-
+// broadcastToRoom broadcasts a message to all clients in a room; it runs on the
+// worker that owns the room's shard
+func (h *Hub) broadcastToRoom(shard *hubShard, message *BroadcastMessage) {
 	wsMessage := WebSocketMessage{
-		Type: message.Type,
-		Data: message.Data,
+		Version: ProtocolVersion,
+		Type:    message.Type,
+		Data:    message.Data,
 	}
 
 	messageBytes, err := json.Marshal(wsMessage)
@@ -188,34 +269,73 @@ func (h *Hub) broadcastToRoom(message *BroadcastMessage) {
 		return
 	}
 
-	for _, client := range room.Clients {
-		select {
-		case client.Send <- messageBytes:
-		default:
-			// Client's send channel is full, remove client
-			h.unregister <- client
+	var slow []*Client
+
+	shard.mutex.RLock()
+	if room, exists := shard.rooms[message.Room]; exists {
+		room.mutex.RLock()
+		for _, client := range room.Clients {
+			select {
+			case client.Send <- messageBytes:
+				h.stats.sent.Add(1)
+			default:
+				// Client's send channel is full, remove client
+				h.stats.dropped.Add(1)
+				slow = append(slow, client)
+			}
 		}
+		room.mutex.RUnlock()
+	}
+	shard.mutex.RUnlock()
+
+	// Unregistering takes the shard lock, so it must happen after the fan out
+	for _, client := range slow {
+		go func(client *Client) {
+			select {
+			case h.unregister <- client:
+			case <-h.stopChan:
+			}
+		}(client)
 	}
 }
 
-// joinRoom adds a client to a room
-func (h *Hub) joinRoom(client *Client, roomID string) {
+// JoinRoom adds a client to a room after checking the client may see it
+func (h *Hub) JoinRoom(client *Client, roomID string) error {
+	if err := AuthorizeRoom(client.UserID, roomID); err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"client_id": client.ID,
+			"user_id":   client.UserID,
+			"room_id":   roomID,
+		}).WithError(err).Warn("Room subscription rejected")
+		return err
+	}
+
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
+	h.addToRoom(client, roomID)
+	return nil
+}
+
+// addToRoom adds a client to a room; the caller must hold h.mutex
+func (h *Hub) addToRoom(client *Client, roomID string) {
+	shard := h.shardFor(roomID)
+	shard.mutex.Lock()
+
 	// Create room if it doesn't exist
-	if _, exists := h.rooms[roomID]; !exists {
-		h.rooms[roomID] = &Room{
+	if _, exists := shard.rooms[roomID]; !exists {
+		shard.rooms[roomID] = &Room{
 			ID:      roomID,
 			Clients: make(map[string]*Client),
 		}
 	}
 
-	room := h.rooms[roomID]
+	room := shard.rooms[roomID]
 
 	room.mutex.Lock()
 	room.Clients[client.ID] = client
 	room.mutex.Unlock()
+	shard.mutex.Unlock()
 
 	client.mutex.Lock()
 	client.Rooms[roomID] = true
@@ -229,8 +349,12 @@ func (h *Hub) joinRoom(client *Client, roomID string) {
 
 // leaveRoom removes a client from a room
 func (h *Hub) leaveRoom(client *Client, roomID string) {
-	room, exists := h.rooms[roomID]
+	shard := h.shardFor(roomID)
+	shard.mutex.Lock()
+
+	room, exists := shard.rooms[roomID]
 	if !exists {
+		shard.mutex.Unlock()
 		return
 	}
 
@@ -239,14 +363,15 @@ func (h *Hub) leaveRoom(client *Client, roomID string) {
 	isEmpty := len(room.Clients) == 0
 	room.mutex.Unlock()
 
-	client.mutex.Lock()
-	delete(client.Rooms, roomID)
-	client.mutex.Unlock()
-
 	// Remove empty room
 	if isEmpty {
-		delete(h.rooms, roomID)
+		delete(shard.rooms, roomID)
 	}
+	shard.mutex.Unlock()
+
+	client.mutex.Lock()
+	delete(client.Rooms, roomID)
+	client.mutex.Unlock()
 
 	h.logger.WithFields(logrus.Fields{
 		"client_id": client.ID,
@@ -270,43 +395,81 @@ func (h *Hub) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Agree on the message protocol before upgrading
+	version, subprotocol, err := NegotiateProtocol(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":              err.Error(),
+			"supported_versions": SupportedProtocolVersions(),
+		})
+		return
+	}
+
+	var responseHeader http.Header
+	if subprotocol != "" {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {subprotocol}}
+	}
+
 	// Upgrade connection
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to upgrade WebSocket connection")
 		return
 	}
+	h.setupCompression(conn)
 
 	// Create client
 	client := &Client{
-		ID:       uuid.New().String(),
-		UserID:   user.ID,
-		Conn:     conn,
-		Hub:      h,
-		Send:     make(chan []byte, 256),
-		Rooms:    make(map[string]bool),
-		LastSeen: time.Now(),
+		ID:              uuid.New().String(),
+		UserID:          user.ID,
+		Conn:            conn,
+		Hub:             h,
+		Send:            make(chan []byte, 256),
+		Rooms:           make(map[string]bool),
+		LastSeen:        time.Now(),
+		requestedResume: c.Query("resume"),
+		protocolVersion: version,
+
+		compressionThreshold: h.compressionThreshold,
 	}
 
 	// Register client
-	h.register <- client
+	h.Register(client)
 
 	// Start goroutines for reading and writing
 	go client.writePump()
 	go client.readPump()
 }
 
-// Broadcast sends a message to a specific room
+// Register hands a new client to the hub loop
+func (h *Hub) Register(client *Client) {
+	h.register <- client
+}
+
+// Broadcast sends a message to a specific room on every instance
 func (h *Hub) Broadcast(room, messageType string, data interface{}) {
-	h.broadcast <- &BroadcastMessage{
+	h.BroadcastLocal(room, messageType, data)
+	h.publish(&RelayedMessage{Room: room, Type: messageType}, data)
+}
+
+// BroadcastLocal sends a message to a room on this instance only, for updates that
+// every instance produces on its own such as periodic market data
+func (h *Hub) BroadcastLocal(room, messageType string, data interface{}) {
+	h.shardFor(room).broadcast <- &BroadcastMessage{
 		Room: room,
 		Type: messageType,
 		Data: data,
 	}
 }
 
-// BroadcastToUser sends a message to a specific user
+// BroadcastToUser sends a message to a specific user on every instance
 func (h *Hub) BroadcastToUser(userID uuid.UUID, messageType string, data interface{}) {
+	h.sendToUser(userID, messageType, data)
+	h.publish(&RelayedMessage{UserID: &userID, Type: messageType}, data)
+}
+
+// sendToUser sends a message to the user's clients connected to this instance
+func (h *Hub) sendToUser(userID uuid.UUID, messageType string, data interface{}) {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 
@@ -330,14 +493,15 @@ func (h *Hub) GetConnectedClients() int {
 
 // GetRooms returns information about active rooms
 func (h *Hub) GetRooms() map[string]int {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
-
 	rooms := make(map[string]int)
-	for roomID, room := range h.rooms {
-		room.mutex.RLock()
-		rooms[roomID] = len(room.Clients)
-		room.mutex.RUnlock()
+	for _, shard := range h.shards {
+		shard.mutex.RLock()
+		for roomID, room := range shard.rooms {
+			room.mutex.RLock()
+			rooms[roomID] = len(room.Clients)
+			room.mutex.RUnlock()
+		}
+		shard.mutex.RUnlock()
 	}
 
 	return rooms
@@ -345,6 +509,14 @@ func (h *Hub) GetRooms() map[string]int {
 
 // Stop gracefully stops the hub goroutine
 func (h *Hub) Stop() {
+	h.mutex.Lock()
+	if h.stopped {
+		h.mutex.Unlock()
+		return
+	}
+	h.stopped = true
 	close(h.stopChan)
+	h.mutex.Unlock()
+
 	h.wg.Wait()
 }
@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/sirupsen/logrus"
+)
+
+// notificationPageTimeout bounds a notification center request
+const notificationPageTimeout = 5 * time.Second
+
+// NotificationPageSource loads one page of a user's notification center
+type NotificationPageSource func(ctx context.Context, userID uuid.UUID, limit, offset int, unreadOnly bool) (*services.NotificationPage, error)
+
+// NotificationPageRequest is the payload of a "get_notifications" message
+type NotificationPageRequest struct {
+	Limit      int  `json:"limit,omitempty"`
+	Offset     int  `json:"offset,omitempty"`
+	UnreadOnly bool `json:"unread_only,omitempty"`
+}
+
+// SetNotificationSource lets clients page through their notifications with
+// "get_notifications" messages instead of polling the REST API
+func (h *Hub) SetNotificationSource(source NotificationPageSource) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.notifications = source
+}
+
+// handleGetNotifications replies with a page of the client's notifications and the
+// unread count; the reply carries the request ID so it can be matched to the request
+func (c *Client) handleGetNotifications(msg WebSocketMessage) {
+	c.Hub.mutex.RLock()
+	source := c.Hub.notifications
+	c.Hub.mutex.RUnlock()
+
+	if source == nil {
+		c.sendRequestError(msg.ID, "notifications are not available over this connection")
+		return
+	}
+
+	var req NotificationPageRequest
+	dataBytes, err := json.Marshal(msg.Data)
+	if err == nil {
+		err = json.Unmarshal(dataBytes, &req)
+	}
+	if err != nil {
+		c.SendMessage(WebSocketMessage{
+			ID:   msg.ID,
+			Type: "error",
+			Data: &ProtocolError{Code: ErrCodeInvalidPayload, Message: "limit and offset must be whole numbers", RequestID: msg.ID},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationPageTimeout)
+	defer cancel()
+
+	page, err := source(ctx, c.UserID, req.Limit, req.Offset, req.UnreadOnly)
+	if err != nil {
+		c.Hub.logger.WithFields(logrus.Fields{
+			"client_id": c.ID,
+			"user_id":   c.UserID,
+		}).WithError(err).Error("Failed to load notifications for WebSocket client")
+		c.sendRequestError(msg.ID, "failed to fetch notifications")
+		return
+	}
+
+	c.SendMessage(WebSocketMessage{
+		ID:   msg.ID,
+		Type: "notifications",
+		Data: page,
+	})
+}
+
+// sendRequestError tells the client a valid request could not be served
+func (c *Client) sendRequestError(requestID, message string) {
+	c.SendMessage(WebSocketMessage{
+		ID:   requestID,
+		Type: "error",
+		Data: &ProtocolError{Code: ErrCodeRequestFailed, Message: message, RequestID: requestID},
+	})
+}
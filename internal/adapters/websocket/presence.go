@@ -0,0 +1,107 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PresenceTTL is how long an instance's record of a connected user lasts without
+// being refreshed, so the users of a crashed instance don't look online for long
+const PresenceTTL = time.Minute
+
+// presenceRefreshInterval is how often the hub refreshes the records of its users
+const presenceRefreshInterval = PresenceTTL / 3
+
+// PresenceStore shares which users have a connection on which instance, so a
+// user connected elsewhere isn't mistaken for an offline one
+type PresenceStore interface {
+	// Announce records that userID is connected to instance for the next ttl
+	Announce(ctx context.Context, userID uuid.UUID, instance string, ttl time.Duration) error
+	// Withdraw records that userID has no connection left on instance
+	Withdraw(ctx context.Context, userID uuid.UUID, instance string) error
+	// Connected reports whether userID is connected to any instance
+	Connected(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// SetPresenceStore shares the users connected to this hub under the instance ID
+// given to SetRelay; it must be called before Start
+func (h *Hub) SetPresenceStore(store PresenceStore) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.presence = store
+}
+
+// IsUserConnected reports whether the user has a connection on this or, with a
+// presence store, any other instance. When the store can't be reached the user is
+// assumed connected: an event sent to nobody beats one never sent.
+func (h *Hub) IsUserConnected(ctx context.Context, userID uuid.UUID) bool {
+	h.mutex.RLock()
+	local := h.hasLocalClient(userID)
+	presence := h.presence
+	h.mutex.RUnlock()
+
+	if local || presence == nil {
+		return local
+	}
+	connected, err := presence.Connected(ctx, userID)
+	if err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Warn("Failed to check user presence")
+		return true
+	}
+	return connected
+}
+
+// hasLocalClient reports whether the user has a client on this instance; the caller
+// must hold h.mutex
+func (h *Hub) hasLocalClient(userID uuid.UUID) bool {
+	for _, client := range h.clients {
+		if client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// announcePresence records a newly registered client's user; the caller must hold h.mutex
+func (h *Hub) announcePresence(userID uuid.UUID) {
+	if h.presence == nil {
+		return
+	}
+	if err := h.presence.Announce(context.Background(), userID, h.instanceID, PresenceTTL); err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Warn("Failed to share user presence")
+	}
+}
+
+// withdrawPresence clears the user's record once their last client on this instance
+// is gone; the caller must hold h.mutex
+func (h *Hub) withdrawPresence(userID uuid.UUID) {
+	if h.presence == nil || h.hasLocalClient(userID) {
+		return
+	}
+	if err := h.presence.Withdraw(context.Background(), userID, h.instanceID); err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Warn("Failed to clear user presence")
+	}
+}
+
+// refreshPresence renews the records of every user connected to this instance
+func (h *Hub) refreshPresence() {
+	h.mutex.RLock()
+	users := make(map[uuid.UUID]bool)
+	for _, client := range h.clients {
+		users[client.UserID] = true
+	}
+	h.mutex.RUnlock()
+
+	for userID := range users {
+		if err := h.presence.Announce(context.Background(), userID, h.instanceID, PresenceTTL); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"user_id": userID,
+				"users":   len(users),
+			}).Warn("Failed to refresh user presence")
+			return
+		}
+	}
+}
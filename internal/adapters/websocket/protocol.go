@@ -0,0 +1,241 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProtocolVersion is the newest message protocol the server speaks
+	ProtocolVersion = 1
+	// MinProtocolVersion is the oldest protocol still accepted
+	MinProtocolVersion = 1
+
+	// subprotocolPrefix names versions in the Sec-WebSocket-Protocol header ("priceguard.v1")
+	subprotocolPrefix = "priceguard.v"
+)
+
+// Error codes sent in "error" messages
+const (
+	ErrCodeMalformedMessage   = "malformed_message"
+	ErrCodeUnknownType        = "unknown_type"
+	ErrCodeInvalidPayload     = "invalid_payload"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeRequestFailed      = "request_failed"
+)
+
+// ProtocolError is sent back to the client when a message is rejected
+type ProtocolError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Field     string `json:"field,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (e *ProtocolError) Error() string {
+	return e.Message
+}
+
+// inboundEnvelope is the wire format of client messages:
+// {"v": 1, "id": "optional request id", "type": "subscribe", "data": {...}}
+type inboundEnvelope struct {
+	Version *int            `json:"v"`
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// fieldSchema constrains one property of a message payload
+type fieldSchema struct {
+	Type      string // "string", "number" or "boolean"
+	Required  bool
+	MaxLength int
+}
+
+// messageSchemas lists every message type a client may send and the shape of its data;
+// an empty schema means the message carries no data
+var messageSchemas = map[string]map[string]fieldSchema{
+	"subscribe": {
+		"room":   {Type: "string", Required: true, MaxLength: 128},
+		"symbol": {Type: "string", MaxLength: 32},
+	},
+	"unsubscribe": {
+		"room":   {Type: "string", Required: true, MaxLength: 128},
+		"symbol": {Type: "string", MaxLength: 32},
+	},
+	"ping":  {},
+	"stats": {},
+	"get_notifications": {
+		"limit":       {Type: "number"},
+		"offset":      {Type: "number"},
+		"unread_only": {Type: "boolean"},
+	},
+	"ack_alert": {
+		"alert_id": {Type: "string", Required: true, MaxLength: 36},
+	},
+}
+
+// SupportedProtocolVersions lists the versions a client can negotiate
+func SupportedProtocolVersions() []int {
+	versions := make([]int, 0, ProtocolVersion-MinProtocolVersion+1)
+	for v := MinProtocolVersion; v <= ProtocolVersion; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// NegotiateProtocol picks the newest version both sides support. Clients offer versions
+// with ?protocol=1,2 or Sec-WebSocket-Protocol: priceguard.v1; clients that offer nothing
+// get the current version. The second value is the subprotocol to echo, if any.
+func NegotiateProtocol(r *http.Request) (int, string, error) {
+	var offered []int
+	subprotocols := make(map[int]string)
+
+	if value := r.URL.Query().Get("protocol"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return 0, "", fmt.Errorf("invalid protocol version %q", part)
+			}
+			offered = append(offered, v)
+		}
+	}
+	for _, name := range websocketSubprotocols(r) {
+		if !strings.HasPrefix(name, subprotocolPrefix) {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimPrefix(name, subprotocolPrefix))
+		if err != nil {
+			continue
+		}
+		offered = append(offered, v)
+		subprotocols[v] = name
+	}
+
+	if len(offered) == 0 {
+		return ProtocolVersion, "", nil
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(offered)))
+	for _, v := range offered {
+		if v >= MinProtocolVersion && v <= ProtocolVersion {
+			return v, subprotocols[v], nil
+		}
+	}
+	return 0, "", fmt.Errorf("unsupported protocol version, server supports %v", SupportedProtocolVersions())
+}
+
+func websocketSubprotocols(r *http.Request) []string {
+	var names []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, name := range strings.Split(header, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// DecodeMessage parses and validates a client message against the envelope and the
+// schema of its type. Messages without "v" are read as the negotiated version.
+func DecodeMessage(raw []byte, version int) (WebSocketMessage, *ProtocolError) {
+	var envelope inboundEnvelope
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&envelope); err != nil {
+		return WebSocketMessage{}, &ProtocolError{Code: ErrCodeMalformedMessage, Message: "message must be a JSON envelope with type and data"}
+	}
+
+	if envelope.Version != nil && *envelope.Version != version {
+		return WebSocketMessage{}, &ProtocolError{
+			Code:      ErrCodeUnsupportedVersion,
+			Message:   fmt.Sprintf("message version %d does not match negotiated version %d", *envelope.Version, version),
+			RequestID: envelope.ID,
+		}
+	}
+
+	schema, ok := messageSchemas[envelope.Type]
+	if !ok {
+		return WebSocketMessage{}, &ProtocolError{
+			Code:      ErrCodeUnknownType,
+			Message:   fmt.Sprintf("unknown message type %q", envelope.Type),
+			Field:     "type",
+			RequestID: envelope.ID,
+		}
+	}
+
+	data, perr := validatePayload(envelope.Data, schema)
+	if perr != nil {
+		perr.RequestID = envelope.ID
+		return WebSocketMessage{}, perr
+	}
+
+	return WebSocketMessage{
+		Version: version,
+		ID:      envelope.ID,
+		Type:    envelope.Type,
+		Data:    data,
+	}, nil
+}
+
+func validatePayload(raw json.RawMessage, schema map[string]fieldSchema) (map[string]interface{}, *ProtocolError) {
+	fields := make(map[string]json.RawMessage)
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: "data must be an object", Field: "data"}
+		}
+	}
+
+	data := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		rule, ok := schema[name]
+		if !ok {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("unexpected field %q", name), Field: "data." + name}
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("invalid value for %q", name), Field: "data." + name}
+		}
+		if !matchesType(decoded, rule) {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("%q must be a %s", name, rule.Type), Field: "data." + name}
+		}
+		if s, isString := decoded.(string); isString && rule.Required && s == "" {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("%q is required", name), Field: "data." + name}
+		}
+		if s, isString := decoded.(string); isString && rule.MaxLength > 0 && len(s) > rule.MaxLength {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("%q exceeds %d characters", name, rule.MaxLength), Field: "data." + name}
+		}
+		data[name] = decoded
+	}
+
+	for name, rule := range schema {
+		if _, ok := data[name]; rule.Required && !ok {
+			return nil, &ProtocolError{Code: ErrCodeInvalidPayload, Message: fmt.Sprintf("%q is required", name), Field: "data." + name}
+		}
+	}
+
+	return data, nil
+}
+
+func matchesType(value interface{}, rule fieldSchema) bool {
+	switch rule.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	default:
+		return false
+	}
+}
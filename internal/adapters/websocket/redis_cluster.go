@@ -0,0 +1,155 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRelayChannel is the Redis pub/sub channel hubs exchange broadcasts on
+const DefaultRelayChannel = "ws:broadcast"
+
+// RedisResumeStore keeps suspended sessions in Redis keys that expire with the resume window
+type RedisResumeStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisResumeStore creates a resume store under the "ws:resume:" key space
+func NewRedisResumeStore(client *redis.Client) *RedisResumeStore {
+	return &RedisResumeStore{client: client, prefix: "ws:resume:"}
+}
+
+func (s *RedisResumeStore) Save(ctx context.Context, token string, state ResumeState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode resume session: %w", err)
+	}
+	if err := s.client.Set(ctx, s.prefix+token, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save resume session: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisResumeStore) Take(ctx context.Context, token string) (*ResumeState, error) {
+	// GETDEL makes the token single use even when two instances race for it
+	data, err := s.client.GetDel(ctx, s.prefix+token).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take resume session: %w", err)
+	}
+
+	var state ResumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode resume session: %w", err)
+	}
+	return &state, nil
+}
+
+// RedisPresenceStore keeps, per user, a Redis hash of the instances they are connected
+// to and until when each record holds
+type RedisPresenceStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisPresenceStore creates a presence store under the "ws:presence:" key space
+func NewRedisPresenceStore(client *redis.Client) *RedisPresenceStore {
+	return &RedisPresenceStore{client: client, prefix: "ws:presence:"}
+}
+
+func (s *RedisPresenceStore) Announce(ctx context.Context, userID uuid.UUID, instance string, ttl time.Duration) error {
+	key := s.prefix + userID.String()
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, instance, time.Now().Add(ttl).UnixMilli())
+	// Every instance uses the same TTL, so the latest record sets the key's lifetime
+	pipe.PExpire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to announce presence: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisPresenceStore) Withdraw(ctx context.Context, userID uuid.UUID, instance string) error {
+	if err := s.client.HDel(ctx, s.prefix+userID.String(), instance).Err(); err != nil {
+		return fmt.Errorf("failed to withdraw presence: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisPresenceStore) Connected(ctx context.Context, userID uuid.UUID) (bool, error) {
+	records, err := s.client.HGetAll(ctx, s.prefix+userID.String()).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to read presence: %w", err)
+	}
+	// Records of instances that stopped refreshing them have expired on their own
+	now := time.Now().UnixMilli()
+	for _, record := range records {
+		if expiresAt, err := strconv.ParseInt(record, 10, 64); err == nil && expiresAt > now {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RedisRelay exchanges broadcasts between hubs over Redis pub/sub. Delivery is at
+// most once: instances that are disconnected while a message is published miss it.
+type RedisRelay struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisRelay creates a relay on the given pub/sub channel
+func NewRedisRelay(client *redis.Client, channel string) *RedisRelay {
+	if channel == "" {
+		channel = DefaultRelayChannel
+	}
+	return &RedisRelay{client: client, channel: channel}
+}
+
+func (r *RedisRelay) Publish(ctx context.Context, message *RelayedMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode relayed message: %w", err)
+	}
+	if err := r.client.Publish(ctx, r.channel, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish relayed message: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisRelay) Subscribe(ctx context.Context, deliver func(*RelayedMessage)) error {
+	pubsub := r.client.Subscribe(ctx, r.channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed so failures surface to the caller
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", r.channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("relay channel %s closed", r.channel)
+			}
+
+			var message RelayedMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				continue
+			}
+			deliver(&message)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultResumeTTL is how long a dropped session can be resumed
+const DefaultResumeTTL = 2 * time.Minute
+
+// resumeSession remembers a client's subscriptions so a reconnect can restore them
+type resumeSession struct {
+	userID    uuid.UUID
+	clientID  string
+	rooms     []string
+	expiresAt time.Time // zero while the client is still connected
+}
+
+// ResumeResult reports what happened to the resume token sent on reconnect
+type ResumeResult struct {
+	Resumed bool
+	Rooms   []string
+	Error   string
+}
+
+// SetResumeTTL sets how long after a disconnect the resume token stays valid
+func (h *Hub) SetResumeTTL(ttl time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if ttl > 0 {
+		h.resumeTTL = ttl
+	}
+}
+
+func newResumeToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// issueResumeToken starts tracking a session for the client; the caller must hold h.mutex
+func (h *Hub) issueResumeToken(client *Client) {
+	token, err := newResumeToken()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to generate resume token")
+		return
+	}
+
+	client.resumeToken = token
+	h.sessions[token] = &resumeSession{userID: client.UserID, clientID: client.ID}
+}
+
+// suspendSession snapshots the client's rooms and starts the resume window;
+// the caller must hold h.mutex and call it before the client leaves its rooms
+func (h *Hub) suspendSession(client *Client) {
+	session, ok := h.sessions[client.resumeToken]
+	if !ok {
+		return
+	}
+
+	client.mutex.RLock()
+	rooms := make([]string, 0, len(client.Rooms))
+	for roomID := range client.Rooms {
+		if roomID != SystemRoom {
+			rooms = append(rooms, roomID)
+		}
+	}
+	client.mutex.RUnlock()
+	sort.Strings(rooms)
+
+	session.rooms = rooms
+	session.expiresAt = h.clock.Now().Add(h.resumeTTL)
+
+	if h.resumeStore != nil {
+		state := ResumeState{UserID: client.UserID, Rooms: rooms}
+		if err := h.resumeStore.Save(context.Background(), client.resumeToken, state, h.resumeTTL); err != nil {
+			h.logger.WithError(err).WithField("client_id", client.ID).Warn("Failed to share resume session")
+		}
+	}
+}
+
+// resumeSession consumes a resume token and rejoins the rooms it recorded; the
+// caller must hold h.mutex. Tokens are single use and bound to the user that got them.
+// Sessions suspended on other instances are found through the resume store.
+func (h *Hub) resumeSession(client *Client, token string) ResumeResult {
+	h.purgeExpiredSessions()
+
+	var state *ResumeState
+	if session, ok := h.sessions[token]; ok {
+		if session.userID != client.UserID {
+			return ResumeResult{Error: "invalid or expired resume token"}
+		}
+		if session.expiresAt.IsZero() {
+			return ResumeResult{Error: "session is still connected"}
+		}
+		delete(h.sessions, token)
+		state = &ResumeState{UserID: session.userID, Rooms: session.rooms}
+	}
+
+	// The shared copy is authoritative: taking it makes the token single use across
+	// instances, and a local session whose shared copy is gone was resumed elsewhere.
+	// Only when the store can't be reached does the local copy decide.
+	if h.resumeStore != nil {
+		shared, err := h.resumeStore.Take(context.Background(), token)
+		if err != nil {
+			h.logger.WithError(err).WithField("client_id", client.ID).Warn("Failed to load shared resume session")
+		} else {
+			state = shared
+		}
+	}
+
+	if state == nil || state.UserID != client.UserID {
+		return ResumeResult{Error: "invalid or expired resume token"}
+	}
+
+	restored := make([]string, 0, len(state.Rooms))
+	for _, roomID := range state.Rooms {
+		// Permissions are checked again in case the rules changed while disconnected
+		if err := AuthorizeRoom(client.UserID, roomID); err != nil {
+			continue
+		}
+		h.addToRoom(client, roomID)
+		restored = append(restored, roomID)
+	}
+
+	return ResumeResult{Resumed: true, Rooms: restored}
+}
+
+// purgeExpiredSessions drops sessions whose resume window closed; the caller must hold h.mutex
+func (h *Hub) purgeExpiredSessions() {
+	now := h.clock.Now()
+	for token, session := range h.sessions {
+		if !session.expiresAt.IsZero() && now.After(session.expiresAt) {
+			delete(h.sessions, token)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package websocket
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+const (
+	// DefaultBroadcastWorkers is the number of room shards, each with its own worker
+	DefaultBroadcastWorkers = 4
+	// DefaultBroadcastQueueSize is the pending broadcast buffer of each shard
+	DefaultBroadcastQueueSize = 256
+)
+
+// hubShard owns a subset of the rooms. Each shard has its own lock and broadcast
+// queue drained by a dedicated worker, so fan out to busy rooms runs in parallel
+// and never waits on connects and disconnects handled by the hub loop.
+type hubShard struct {
+	rooms     map[string]*Room
+	broadcast chan *BroadcastMessage
+	mutex     sync.RWMutex
+}
+
+func newShards(workers, queueSize int) []*hubShard {
+	if workers <= 0 {
+		workers = DefaultBroadcastWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultBroadcastQueueSize
+	}
+
+	shards := make([]*hubShard, workers)
+	for i := range shards {
+		shards[i] = &hubShard{
+			rooms:     make(map[string]*Room),
+			broadcast: make(chan *BroadcastMessage, queueSize),
+		}
+	}
+	return shards
+}
+
+// SetBroadcastWorkers sets how many shards (and broadcast workers) rooms are spread
+// across and the queue size of each; it must be called before Start
+func (h *Hub) SetBroadcastWorkers(workers, queueSize int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.shards = newShards(workers, queueSize)
+}
+
+// shardFor maps a room to the shard that owns it
+func (h *Hub) shardFor(roomID string) *hubShard {
+	if len(h.shards) == 1 {
+		return h.shards[0]
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(roomID))
+	return h.shards[hash.Sum32()%uint32(len(h.shards))]
+}
+
+// runShard delivers the shard's broadcasts until the hub stops
+func (h *Hub) runShard(shard *hubShard) {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case message := <-shard.broadcast:
+			h.broadcastToRoom(shard, message)
+		case <-h.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package websocket
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// hubStats counts the messages the hub hands to clients. Rates are derived from
+// the totals between two samples, so the first reading after start reports the
+// average since the hub was created.
+type hubStats struct {
+	sent    atomic.Uint64 // Messages queued on a client's send channel
+	dropped atomic.Uint64 // Messages discarded because the client was too slow
+
+	mutex       sync.Mutex
+	sampledAt   time.Time
+	sampledSent uint64
+	rate        float64
+}
+
+// minRateWindow keeps frequent stats requests from reporting noisy rates
+const minRateWindow = time.Second
+
+// messagesPerSecond returns the send rate since the previous sample
+func (s *hubStats) messagesPerSecond(now time.Time) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	elapsed := now.Sub(s.sampledAt)
+	if elapsed < minRateWindow {
+		return s.rate
+	}
+
+	sent := s.sent.Load()
+	s.rate = float64(sent-s.sampledSent) / elapsed.Seconds()
+	s.sampledAt = now
+	s.sampledSent = sent
+	return s.rate
+}
+
+// Stats reports connected users per room, the message rate and dropped messages
+func (h *Hub) Stats() services.WebSocketStats {
+	h.mutex.RLock()
+	users := make(map[uuid.UUID]struct{}, len(h.clients))
+	for _, client := range h.clients {
+		users[client.UserID] = struct{}{}
+	}
+	connected := len(h.clients)
+	now := h.clock.Now()
+	h.mutex.RUnlock()
+
+	roomUsers := make(map[string]int)
+	for _, shard := range h.shards {
+		shard.mutex.RLock()
+		for roomID, room := range shard.rooms {
+			room.mutex.RLock()
+			present := make(map[uuid.UUID]struct{}, len(room.Clients))
+			for _, client := range room.Clients {
+				present[client.UserID] = struct{}{}
+			}
+			room.mutex.RUnlock()
+			roomUsers[roomID] = len(present)
+		}
+		shard.mutex.RUnlock()
+	}
+
+	return services.WebSocketStats{
+		ConnectedClients:  connected,
+		ConnectedUsers:    len(users),
+		RoomUsers:         roomUsers,
+		MessagesPerSecond: h.stats.messagesPerSecond(now),
+		MessagesSent:      h.stats.sent.Load(),
+		MessagesDropped:   h.stats.dropped.Load(),
+	}
+}
+
+// visibleStats narrows the stats to the rooms a user may join, so a "stats"
+// request does not reveal which other users are online
+func (h *Hub) visibleStats(userID uuid.UUID) services.WebSocketStats {
+	stats := h.Stats()
+	for roomID := range stats.RoomUsers {
+		if AuthorizeRoom(userID, roomID) != nil {
+			delete(stats.RoomUsers, roomID)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// watchlistInterval is how often the watchlist rooms are updated
+const watchlistInterval = 5 * time.Second
+
+// watchlistUpdate is what was last sent to a watchlist room and to how many clients
+type watchlistUpdate struct {
+	payload string
+	clients int
+}
+
+// SetWatchlistService streams the percent changes of each user's watchlist to their
+// watchlist room; it must be called before Start
+func (w *Worker) SetWatchlistService(watchlists *services.WatchlistTickerService) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.watchlists = watchlists
+}
+
+// watchlistWorker periodically broadcasts watchlist percent changes
+func (w *Worker) watchlistWorker(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(watchlistInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("Started watchlist worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.BroadcastWatchlists(ctx)
+		}
+	}
+}
+
+// BroadcastWatchlists sends each watchlist room on this instance the ticks of its
+// user's symbols. Every symbol is computed once however many watchlists hold it,
+// and a room only gets an update when its ticks changed or a client joined it.
+// The running worker calls it every watchlistInterval; it isn't safe to call
+// while the worker runs.
+func (w *Worker) BroadcastWatchlists(ctx context.Context) {
+	rooms := w.hub.GetRooms()
+	watchlists := make(map[string][]string)
+	var symbols []string
+	for room := range rooms {
+		userKey, ok := strings.CutPrefix(room, WatchlistRoomPrefix)
+		if !ok {
+			continue
+		}
+		userID, err := uuid.Parse(userKey)
+		if err != nil {
+			continue
+		}
+
+		watchlist, err := w.watchlists.WatchlistSymbols(ctx, userID)
+		if err != nil {
+			w.logger.WithError(err).WithField("user_id", userID).Error("Failed to get watchlist")
+			continue
+		}
+		watchlists[room] = watchlist
+		symbols = append(symbols, watchlist...)
+	}
+
+	for room := range w.watchlistSent {
+		if _, exists := rooms[room]; !exists {
+			delete(w.watchlistSent, room)
+		}
+	}
+	if len(watchlists) == 0 {
+		return
+	}
+
+	ticks, err := w.watchlists.Ticks(ctx, symbols)
+	if err != nil {
+		w.logger.WithError(err).WithField("symbols", len(symbols)).Error("Failed to compute watchlist ticks")
+		return
+	}
+
+	if w.watchlistSent == nil {
+		w.watchlistSent = make(map[string]watchlistUpdate)
+	}
+	for room, watchlist := range watchlists {
+		roomTicks := make([]services.WatchlistTick, 0, len(watchlist))
+		for _, symbol := range watchlist {
+			if tick, ok := ticks[symbol]; ok {
+				roomTicks = append(roomTicks, tick)
+			}
+		}
+
+		encoded, err := json.Marshal(roomTicks)
+		if err != nil {
+			continue
+		}
+		update := watchlistUpdate{payload: string(encoded), clients: rooms[room]}
+		if w.watchlistSent[room] == update {
+			continue
+		}
+		w.watchlistSent[room] = update
+
+		// Every instance's worker updates its own clients, so this isn't relayed
+		w.hub.BroadcastLocal(room, "watchlist_update", map[string]interface{}{
+			"t": time.Now().Unix(),
+			"d": roomTicks,
+		})
+
+		w.logger.WithFields(logrus.Fields{
+			"room":    room,
+			"symbols": len(roomTicks),
+		}).Debug("Broadcasted watchlist update")
+	}
+}
@@ -22,8 +22,13 @@ type Worker struct {
 	notificationService       *services.NotificationService
 	alertRepo                 repositories.AlertRepository
 	priceHistoryRepo          repositories.PriceHistoryRepository
+	watchlists                *services.WatchlistTickerService
+	candleEvents              *services.CandleEventBus
 	logger                    *logrus.Logger
 
+	// Last update sent to each watchlist room, only touched by the watchlist worker
+	watchlistSent map[string]watchlistUpdate
+
 	// Control channels
 	stopChan  chan struct{}
 	wg        sync.WaitGroup
@@ -73,11 +78,19 @@ func (w *Worker) Start(ctx context.Context) {
 	w.logger.Info("Starting WebSocket background workers")
 
 	// Start different worker goroutines
-	w.wg.Add(4)
+	w.wg.Add(2)
 	go w.priceDataWorker(ctx)
-	go w.alertWorker(ctx)
-	go w.technicalIndicatorWorker(ctx)
 	go w.marketSummaryWorker(ctx)
+	// With candle-close events, alerts and indicators are refreshed as candles close
+	if w.candleEvents == nil {
+		w.wg.Add(2)
+		go w.alertWorker(ctx)
+		go w.technicalIndicatorWorker(ctx)
+	}
+	if w.watchlists != nil {
+		w.wg.Add(1)
+		go w.watchlistWorker(ctx)
+	}
 }
 
 // Stop stops all background workers
@@ -222,6 +235,11 @@ func (w *Worker) evaluateAndProcessAlerts(ctx context.Context) {
 		return
 	}
 
+	w.processAlertResults(ctx, results)
+}
+
+// processAlertResults broadcasts the triggered alerts and queues their notifications
+func (w *Worker) processAlertResults(ctx context.Context, results []services.AlertEvaluationResult) {
 	// Process triggered alerts
 	for _, result := range results {
 		if result.ShouldTrigger {
@@ -248,10 +266,12 @@ func (w *Worker) evaluateAndProcessAlerts(ctx context.Context) {
 						channels = append(channels, services.ChannelPush)
 					case "sms":
 						channels = append(channels, services.ChannelSMS)
+					case "telegram":
+						channels = append(channels, services.ChannelTelegram)
 					}
 				}
 
-				err := w.notificationService.QueueAlertNotification(ctx, alert, result.CurrentValue, channels)
+				err := w.notificationService.QueueAlertNotification(services.WithAlertTrace(ctx, result.Trace), alert, result.CurrentValue, channels)
 				if err != nil {
 					w.logger.WithError(err).WithField("alert_id", result.AlertID).Error("Failed to queue alert notification")
 				}
@@ -277,71 +297,78 @@ func (w *Worker) calculateAndBroadcastIndicators(ctx context.Context) {
 	timeframe := "1h"
 
 	for _, symbol := range symbols {
-		// Check if anyone is subscribed to indicators for this symbol
-		room := "indicators_" + symbol
-		rooms := w.hub.GetRooms()
-		if _, exists := rooms[room]; !exists {
-			continue // Skip if no one is subscribed
-		}
+		w.refreshIndicators(ctx, symbol, timeframe)
+	}
+}
 
-		// Calculate indicators first
-		err := w.technicalIndicatorService.CalculateAllIndicators(ctx, symbol, timeframe)
-		if err != nil {
-			w.logger.WithError(err).WithFields(logrus.Fields{
-				"symbol":    symbol,
-				"timeframe": timeframe,
-			}).Error("Failed to calculate indicators")
-			continue
-		}
+// refreshIndicators calculates and broadcasts the indicators and pullback signal of
+// a symbol, if anyone is subscribed to them
+func (w *Worker) refreshIndicators(ctx context.Context, symbol, timeframe string) {
+	// Check if anyone is subscribed to indicators for this symbol
+	room := "indicators_" + symbol
+	rooms := w.hub.GetRooms()
+	if _, exists := rooms[room]; !exists {
+		return // Skip if no one is subscribed
+	}
 
-		// Get latest indicators
-		indicatorMap, err := w.technicalIndicatorService.GetLatestIndicators(ctx, symbol, timeframe)
-		if err != nil {
-			w.logger.WithError(err).WithFields(logrus.Fields{
-				"symbol":    symbol,
-				"timeframe": timeframe,
-			}).Error("Failed to get latest indicators")
-			continue
-		}
+	// Calculate indicators first
+	err := w.technicalIndicatorService.CalculateAllIndicators(ctx, symbol, timeframe)
+	if err != nil {
+		w.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":    symbol,
+			"timeframe": timeframe,
+		}).Error("Failed to calculate indicators")
+		return
+	}
 
-		// Convert to simple map[string]float64 for broadcasting
-		indicators := make(map[string]float64)
-		for key, indicator := range indicatorMap {
-			if indicator != nil && indicator.Value != nil {
-				indicators[key] = *indicator.Value
-			}
+	// Get latest indicators
+	indicatorMap, err := w.technicalIndicatorService.GetLatestIndicators(ctx, symbol, timeframe)
+	if err != nil {
+		w.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":    symbol,
+			"timeframe": timeframe,
+		}).Error("Failed to get latest indicators")
+		return
+	}
+
+	// Convert to simple map[string]float64 for broadcasting
+	indicators := make(map[string]float64)
+	for key, indicator := range indicatorMap {
+		if indicator != nil && indicator.Value != nil {
+			indicators[key] = *indicator.Value
 		}
+	}
 
-		// Broadcast the indicators
-		w.handler.BroadcastTechnicalIndicatorUpdate(symbol, indicators)
+	// Broadcast the indicators
+	w.handler.BroadcastTechnicalIndicatorUpdate(symbol, indicators)
 
-		// Check for pullback signals
-		signal, err := w.pullbackEntryService.AnalyzePullbackEntry(ctx, symbol, timeframe)
-		if err != nil {
-			w.logger.WithError(err).WithFields(logrus.Fields{
-				"symbol":    symbol,
-				"timeframe": timeframe,
-			}).Error("Failed to analyze pullback entry")
-			continue
-		}
+	// Check for pullback signals
+	signal, err := w.pullbackEntryService.AnalyzePullbackEntry(ctx, symbol, timeframe)
+	if err != nil {
+		w.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":    symbol,
+			"timeframe": timeframe,
+		}).Error("Failed to analyze pullback entry")
+		return
+	}
 
-		if signal != nil {
-			// Convert PullbackEntry to map for broadcasting
-			signalData := map[string]interface{}{
-				"signal":        signal.Signal,
-				"confidence":    signal.Confidence,
-				"entry_price":   signal.EntryPrice,
-				"stop_loss":     signal.StopLoss,
-				"take_profit_1": signal.TakeProfit1,
-				"take_profit_2": signal.TakeProfit2,
-				"rsi":           signal.RSI,
-				"ema_trend":     signal.EMATrend,
-				"supertrend":    signal.SuperTrend,
-				"timeframe":     signal.Timeframe,
-				"timestamp":     signal.Timestamp,
-			}
-			w.handler.BroadcastPullbackSignal(symbol, signalData)
+	if signal != nil {
+		// Convert PullbackEntry to map for broadcasting
+		signalData := map[string]interface{}{
+			"signal":         signal.Signal,
+			"confidence":     signal.Confidence,
+			"entry_price":    signal.EntryPrice,
+			"stop_loss":      signal.StopLoss,
+			"take_profit_1":  signal.TakeProfit1,
+			"take_profit_2":  signal.TakeProfit2,
+			"rsi":            signal.RSI,
+			"ema_trend":      signal.EMATrend,
+			"supertrend":     signal.SuperTrend,
+			"trend_strength": signal.TrendStrength,
+			"timeframe":      signal.Timeframe,
+			"timestamp":      signal.Timestamp,
 		}
+		w.handler.BroadcastPullbackSignal(symbol, signalData)
 	}
 }
 
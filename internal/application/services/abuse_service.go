@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Reasons a user is flagged for
+const (
+	// AbuseReasonDuplicateConditions flags many alerts on the same symbol, type, condition and timeframe
+	AbuseReasonDuplicateConditions = "duplicate_conditions"
+	// AbuseReasonAlertVolume flags a large number of alerts notifying outside the app
+	AbuseReasonAlertVolume = "alert_volume"
+)
+
+// ErrAbuseFlagNotFound is returned when resolving an unknown or already resolved flag
+var ErrAbuseFlagNotFound = entities.NewDomainError(entities.ErrNotFound, "abuse flag not found")
+
+// AbuseThresholds tune the abuse heuristics and the throttle applied to offenders
+type AbuseThresholds struct {
+	// MaxDuplicateAlerts is the most enabled alerts a user may have on one condition
+	MaxDuplicateAlerts int
+	// MaxExternalAlerts is the most enabled alerts a user may route to email, push, SMS or Telegram
+	MaxExternalAlerts int
+	// ThrottleDuration is how long a flag throttles the user, and how long an
+	// admin's resolution holds before the user can be flagged again
+	ThrottleDuration time.Duration
+	// TriggerInterval is the minimum time between two triggers of a throttled user
+	TriggerInterval time.Duration
+}
+
+// DefaultAbuseThresholds returns thresholds no legitimate configuration gets close to
+func DefaultAbuseThresholds() AbuseThresholds {
+	return AbuseThresholds{
+		MaxDuplicateAlerts: 25,
+		MaxExternalAlerts:  200,
+		ThrottleDuration:   24 * time.Hour,
+		TriggerInterval:    15 * time.Minute,
+	}
+}
+
+// AbuseService detects alert configurations designed to spam notifications, such
+// as hundreds of alerts on the same condition, flags their owners for admins to
+// review and throttles the flagged users' triggers until the flag expires or is
+// resolved
+type AbuseService struct {
+	alertRepo  repositories.AlertRepository
+	flagRepo   repositories.AbuseFlagRepository
+	thresholds AbuseThresholds
+	logger     *logrus.Logger
+	clock      clock.Clock
+
+	// Throttle state of flagged users; the flags live in Postgres and the trigger
+	// spacing in the throttle store, so every instance applies the same limits
+	throttledUntil map[uuid.UUID]time.Time
+	throttleMutex  sync.Mutex
+	triggers       ThrottleStore
+
+	// Per-user evaluation costs recorded by the alert engine, for fair-use reviews
+	costs AlertCostStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewAbuseService creates a new abuse detection service
+func NewAbuseService(
+	alertRepo repositories.AlertRepository,
+	flagRepo repositories.AbuseFlagRepository,
+	logger *logrus.Logger,
+) *AbuseService {
+	return &AbuseService{
+		alertRepo:      alertRepo,
+		flagRepo:       flagRepo,
+		thresholds:     DefaultAbuseThresholds(),
+		logger:         logger,
+		clock:          clock.New(),
+		throttledUntil: make(map[uuid.UUID]time.Time),
+		triggers:       NewMemoryThrottleStore(),
+	}
+}
+
+// SetClock replaces the clock used to expire flags and space throttled triggers
+func (as *AbuseService) SetClock(c clock.Clock) {
+	as.clock = c
+	if memory, ok := as.triggers.(*MemoryThrottleStore); ok {
+		memory.SetClock(c)
+	}
+}
+
+// SetThrottleStore replaces the in-process spacing of flagged users' triggers, e.g.
+// with a Redis store so a flagged user can't get one trigger per instance
+func (as *AbuseService) SetThrottleStore(store ThrottleStore) {
+	as.triggers = store
+}
+
+// SetCostStore reports the evaluation costs the alert engine records in store
+func (as *AbuseService) SetCostStore(store AlertCostStore) {
+	as.costs = store
+}
+
+// SetThresholds replaces the default heuristics thresholds
+func (as *AbuseService) SetThresholds(thresholds AbuseThresholds) {
+	as.thresholds = thresholds
+}
+
+// Start scans once and then every interval until Stop is called
+func (as *AbuseService) Start(ctx context.Context, interval time.Duration) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if as.isRunning {
+		as.logger.Warn("Abuse scanner is already running")
+		return
+	}
+	as.isRunning = true
+	as.stopChan = make(chan struct{})
+	as.logger.Info("Starting abuse scanner")
+
+	as.wg.Add(1)
+	go func() {
+		defer as.wg.Done()
+
+		// The first scan also restores the throttles of flags raised before a restart
+		if _, err := as.Scan(ctx); err != nil {
+			as.logger.WithError(err).Error("Failed to scan alerts for abuse")
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-as.stopChan:
+				return
+			case <-ticker.C:
+				if _, err := as.Scan(ctx); err != nil {
+					as.logger.WithError(err).Error("Failed to scan alerts for abuse")
+				}
+			}
+		}
+	}()
+}
+
+// Stop halts the scanner and waits for the current scan to finish
+func (as *AbuseService) Stop() {
+	as.mutex.Lock()
+	if !as.isRunning {
+		as.mutex.Unlock()
+		return
+	}
+	as.isRunning = false
+	close(as.stopChan)
+	as.mutex.Unlock()
+
+	as.wg.Wait()
+	as.logger.Info("Abuse scanner stopped")
+}
+
+// abuseOffence is one heuristic a user's alerts tripped
+type abuseOffence struct {
+	reason     string
+	alertCount int
+	details    map[string]interface{}
+}
+
+// Scan runs the heuristics over all enabled alerts, flags new offenders and
+// returns the flags it created
+func (as *AbuseService) Scan(ctx context.Context) ([]entities.AbuseFlag, error) {
+	alerts, err := as.alertRepo.GetEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled alerts: %w", err)
+	}
+
+	offences := as.detect(alerts)
+	userIDs := make([]uuid.UUID, 0, len(offences))
+	for userID := range offences {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i].String() < userIDs[j].String() })
+
+	now := as.clock.Now()
+	created := make([]entities.AbuseFlag, 0)
+	for _, userID := range userIDs {
+		existing, err := as.flagRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return created, fmt.Errorf("failed to get abuse flags: %w", err)
+		}
+
+		for _, offence := range offences[userID] {
+			if as.alreadyFlagged(existing, offence.reason, now) {
+				continue
+			}
+
+			flag := entities.AbuseFlag{
+				UserID:         userID,
+				Reason:         offence.reason,
+				AlertCount:     offence.alertCount,
+				Details:        offence.details,
+				ThrottledUntil: now.Add(as.thresholds.ThrottleDuration),
+				CreatedAt:      now,
+			}
+			if err := as.flagRepo.Create(ctx, &flag); err != nil {
+				return created, fmt.Errorf("failed to create abuse flag: %w", err)
+			}
+			created = append(created, flag)
+
+			as.logger.WithFields(logrus.Fields{
+				"user_id":         userID,
+				"reason":          offence.reason,
+				"alert_count":     offence.alertCount,
+				"throttled_until": flag.ThrottledUntil,
+			}).Warn("User flagged for alert abuse")
+		}
+	}
+
+	if err := as.loadThrottles(ctx); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// detect applies the heuristics to the enabled alerts, grouped by user
+func (as *AbuseService) detect(alerts []entities.Alert) map[uuid.UUID][]abuseOffence {
+	type conditionKey struct {
+		userID        uuid.UUID
+		symbol        string
+		alertType     string
+		conditionType string
+		timeframe     string
+	}
+	conditions := make(map[conditionKey]int)
+	external := make(map[uuid.UUID]int)
+	total := make(map[uuid.UUID]int)
+
+	for _, alert := range alerts {
+		conditions[conditionKey{alert.UserID, alert.Symbol, alert.AlertType, alert.ConditionType, alert.Timeframe}]++
+		total[alert.UserID]++
+		for _, channel := range alert.NotifyVia {
+			if channel != "app" {
+				external[alert.UserID]++
+				break
+			}
+		}
+	}
+
+	offences := make(map[uuid.UUID][]abuseOffence)
+
+	// Report only the largest duplicate group of each user
+	worst := make(map[uuid.UUID]conditionKey)
+	for key, count := range conditions {
+		if count <= as.thresholds.MaxDuplicateAlerts {
+			continue
+		}
+		current, found := worst[key.userID]
+		if !found || count > conditions[current] {
+			worst[key.userID] = key
+		}
+	}
+	for userID, key := range worst {
+		offences[userID] = append(offences[userID], abuseOffence{
+			reason:     AbuseReasonDuplicateConditions,
+			alertCount: conditions[key],
+			details: map[string]interface{}{
+				"symbol":         key.symbol,
+				"alert_type":     key.alertType,
+				"condition_type": key.conditionType,
+				"timeframe":      key.timeframe,
+			},
+		})
+	}
+
+	for userID, count := range external {
+		if count <= as.thresholds.MaxExternalAlerts {
+			continue
+		}
+		offences[userID] = append(offences[userID], abuseOffence{
+			reason:     AbuseReasonAlertVolume,
+			alertCount: count,
+			details: map[string]interface{}{
+				"total_alerts": total[userID],
+			},
+		})
+	}
+
+	return offences
+}
+
+// alreadyFlagged reports whether the user has an active flag for the reason, or
+// one an admin resolved less than a throttle period ago
+func (as *AbuseService) alreadyFlagged(flags []entities.AbuseFlag, reason string, now time.Time) bool {
+	for _, flag := range flags {
+		if flag.Reason != reason {
+			continue
+		}
+		if flag.IsActive(now) {
+			return true
+		}
+		if flag.ResolvedAt != nil && now.Sub(*flag.ResolvedAt) < as.thresholds.ThrottleDuration {
+			return true
+		}
+	}
+	return false
+}
+
+// loadThrottles rebuilds the throttled users from the active flags
+func (as *AbuseService) loadThrottles(ctx context.Context) error {
+	flags, err := as.flagRepo.GetActive(ctx, as.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get active abuse flags: %w", err)
+	}
+
+	throttledUntil := make(map[uuid.UUID]time.Time, len(flags))
+	for _, flag := range flags {
+		if flag.ThrottledUntil.After(throttledUntil[flag.UserID]) {
+			throttledUntil[flag.UserID] = flag.ThrottledUntil
+		}
+	}
+
+	as.throttleMutex.Lock()
+	defer as.throttleMutex.Unlock()
+	as.throttledUntil = throttledUntil
+	return nil
+}
+
+// IsThrottled reports whether the user currently has an active flag
+func (as *AbuseService) IsThrottled(userID uuid.UUID) bool {
+	as.throttleMutex.Lock()
+	defer as.throttleMutex.Unlock()
+
+	until, found := as.throttledUntil[userID]
+	return found && as.clock.Now().Before(until)
+}
+
+// AllowTrigger reports whether an alert of the user may trigger now. Users that
+// aren't flagged always may; flagged users get one trigger per TriggerInterval.
+func (as *AbuseService) AllowTrigger(ctx context.Context, userID uuid.UUID) bool {
+	if !as.IsThrottled(userID) {
+		return true
+	}
+
+	allowed, err := as.triggers.Acquire(ctx, "abuse:"+userID.String(), as.thresholds.TriggerInterval)
+	if err != nil {
+		as.logger.WithError(err).WithField("user_id", userID).Warn("Failed to acquire abuse trigger throttle")
+		return true
+	}
+	return allowed
+}
+
+// ListFlags returns flags newest first; activeOnly limits them to those still throttling
+func (as *AbuseService) ListFlags(ctx context.Context, activeOnly bool, limit, offset int) ([]entities.AbuseFlag, error) {
+	if activeOnly {
+		flags, err := as.flagRepo.GetActive(ctx, as.clock.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get active abuse flags: %w", err)
+		}
+		return flags, nil
+	}
+
+	flags, err := as.flagRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get abuse flags: %w", err)
+	}
+	return flags, nil
+}
+
+// EvaluationCosts returns the limit users whose alerts took the longest to evaluate
+// on the UTC day of day, with their evaluation and database call counts
+func (as *AbuseService) EvaluationCosts(ctx context.Context, day time.Time, limit int) ([]UserEvaluationCost, error) {
+	if as.costs == nil {
+		return []UserEvaluationCost{}, nil
+	}
+	return as.costs.Top(ctx, day, limit)
+}
+
+// ResolveFlag clears a flag, lifting the user's throttle unless another flag is active
+func (as *AbuseService) ResolveFlag(ctx context.Context, id uuid.UUID, resolvedBy string) (*entities.AbuseFlag, error) {
+	flag, err := as.flagRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAbuseFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get abuse flag: %w", err)
+	}
+
+	now := as.clock.Now()
+	if err := as.flagRepo.Resolve(ctx, id, resolvedBy, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAbuseFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to resolve abuse flag: %w", err)
+	}
+	flag.ResolvedAt = &now
+	flag.ResolvedBy = resolvedBy
+
+	if err := as.loadThrottles(ctx); err != nil {
+		return nil, err
+	}
+
+	as.logger.WithFields(logrus.Fields{
+		"abuse_flag_id": id,
+		"user_id":       flag.UserID,
+		"resolved_by":   resolvedBy,
+	}).Info("Abuse flag resolved")
+	return flag, nil
+}
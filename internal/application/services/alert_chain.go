@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// MaxAlertChainLength is how many alerts a chain can hold, counting the alert being
+// chained and the alerts leading up to it
+const MaxAlertChainLength = 10
+
+// CheckAlertDependency checks the alert can be chained after the alert with
+// dependsOnID: that must be another of the user's alerts, and following its
+// dependencies must neither lead back to the alert nor exceed MaxAlertChainLength
+func CheckAlertDependency(ctx context.Context, alertRepo repositories.AlertRepository, alert *entities.Alert, dependsOnID uuid.UUID) error {
+	length := 1
+	for id := &dependsOnID; id != nil; {
+		if *id == alert.ID {
+			return &entities.ValidationError{Entity: "alert", Field: "depends_on_id", Message: "the dependency would form a cycle"}
+		}
+		if length++; length > MaxAlertChainLength {
+			return &entities.ValidationError{
+				Entity:  "alert",
+				Field:   "depends_on_id",
+				Message: fmt.Sprintf("chains can't be longer than %d alerts", MaxAlertChainLength),
+			}
+		}
+
+		dependency, err := alertRepo.GetByID(ctx, *id)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && dependency.UserID != alert.UserID) {
+			return &entities.ValidationError{Entity: "alert", Field: "depends_on_id", Message: "alert not found"}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get alert %s: %w", *id, err)
+		}
+		id = dependency.DependsOnID
+	}
+	return nil
+}
+
+// armDependents arms the enabled alerts chained after an alert that triggered at
+// now and returns how many it armed. A failure leaves the remaining dependents
+// disarmed until the next trigger, so it is logged rather than failing the trigger.
+func (ae *AlertEngine) armDependents(ctx context.Context, alert *entities.Alert, now time.Time) int {
+	countDBCall(ctx)
+	dependents, err := ae.alertRepo.GetDependents(ctx, alert.ID)
+	if err != nil {
+		ae.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to get chained alerts")
+		return 0
+	}
+
+	armed := 0
+	for i := range dependents {
+		dependent := &dependents[i]
+		if !dependent.Enabled || dependent.ArmedAt != nil {
+			continue
+		}
+		dependent.ArmedAt = &now
+
+		countDBCall(ctx)
+		if err := ae.alertRepo.Update(ctx, dependent); err != nil {
+			ae.logger.WithError(err).WithField("alert_id", dependent.ID).Error("Failed to arm chained alert")
+			continue
+		}
+		armed++
+	}
+
+	if armed > 0 {
+		ae.logger.WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"armed":    armed,
+		}).Info("Armed chained alerts")
+	}
+	return armed
+}
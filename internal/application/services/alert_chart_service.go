@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+const (
+	// DefaultChartPoints is the number of candles in a chart context when no size is given
+	DefaultChartPoints = 50
+	// MaxChartPoints caps the candles of one chart context
+	MaxChartPoints = 200
+)
+
+// Scales a chart context target level is expressed in
+const (
+	ChartScalePrice     = "price"
+	ChartScaleIndicator = "indicator"
+)
+
+// ChartCandle is one point of a sparkline
+type ChartCandle struct {
+	Timestamp time.Time `json:"timestamp"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+}
+
+// ChartPoint is one value of an indicator series
+type ChartPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// AlertChartContext is everything the alerts list needs to draw a mini-chart of an
+// alert: recent candles and indicator series oldest first, and the level the alert
+// fires at. TargetLevel is nil for crossovers, which have no fixed level.
+type AlertChartContext struct {
+	Symbol        string                  `json:"symbol"`
+	Timeframe     string                  `json:"timeframe"`
+	AlertType     string                  `json:"alert_type"`
+	ConditionType string                  `json:"condition_type"`
+	TargetValue   float64                 `json:"target_value"`
+	TargetLevel   *float64                `json:"target_level,omitempty"`
+	TargetScale   string                  `json:"target_scale"`
+	Candles       []ChartCandle           `json:"candles"`
+	Indicators    map[string][]ChartPoint `json:"indicators"`
+}
+
+// AlertChartService assembles the chart context of an alert
+type AlertChartService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	indicatorRepo    repositories.TechnicalIndicatorRepository
+}
+
+// NewAlertChartService creates a new alert chart service
+func NewAlertChartService(
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	indicatorRepo repositories.TechnicalIndicatorRepository,
+) *AlertChartService {
+	return &AlertChartService{
+		priceHistoryRepo: priceHistoryRepo,
+		indicatorRepo:    indicatorRepo,
+	}
+}
+
+// GetChartContext loads the last points candles on the alert's timeframe and the
+// indicator series its condition is evaluated against, using the same indicators
+// as the alert engine
+func (acs *AlertChartService) GetChartContext(ctx context.Context, alert *entities.Alert, points int) (*AlertChartContext, error) {
+	if points > MaxChartPoints {
+		points = MaxChartPoints
+	}
+	if points <= 0 {
+		points = DefaultChartPoints
+	}
+
+	history, err := acs.priceHistoryRepo.GetBySymbol(ctx, alert.Symbol, alert.Timeframe, points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	chart := &AlertChartContext{
+		Symbol:        alert.Symbol,
+		Timeframe:     alert.Timeframe,
+		AlertType:     alert.AlertType,
+		ConditionType: alert.ConditionType,
+		TargetValue:   alert.TargetValue,
+		TargetScale:   ChartScalePrice,
+		Candles:       make([]ChartCandle, len(history)),
+		Indicators:    make(map[string][]ChartPoint),
+	}
+	// The repository returns newest first; charts draw oldest first
+	for i, candle := range history {
+		chart.Candles[len(history)-1-i] = ChartCandle{
+			Timestamp: candle.Timestamp,
+			High:      candle.HighPrice,
+			Low:       candle.LowPrice,
+			Close:     candle.ClosePrice,
+		}
+	}
+
+	var series []string
+	switch alert.AlertType {
+	case "price":
+		target := alert.TargetValue
+		chart.TargetLevel = &target
+	case "percentage":
+		chart.TargetLevel = percentageTargetLevel(history, alert)
+	case "rsi":
+		target := alert.TargetValue
+		chart.TargetLevel = &target
+		chart.TargetScale = ChartScaleIndicator
+		series = []string{entities.IndicatorKey("RSI", rsiPeriod)}
+	case "ema_cross", "sma_cross":
+		// Same periods as the alert engine: the target is the short period, the long one is double
+		maType := strings.ToUpper(alert.AlertType[:3])
+		shortPeriod := int(alert.TargetValue)
+		series = []string{entities.IndicatorKey(maType, shortPeriod), entities.IndicatorKey(maType, shortPeriod*2)}
+	}
+
+	for _, indicatorKey := range series {
+		values, err := acs.indicatorSeries(ctx, alert, indicatorKey, len(history))
+		if err != nil {
+			return nil, err
+		}
+		chart.Indicators[indicatorKey] = values
+	}
+
+	return chart, nil
+}
+
+// indicatorSeries loads an indicator oldest first, skipping values not computed yet
+func (acs *AlertChartService) indicatorSeries(ctx context.Context, alert *entities.Alert, indicatorKey string, limit int) ([]ChartPoint, error) {
+	indicators, err := acs.indicatorRepo.GetByKey(ctx, alert.Symbol, alert.Timeframe, indicatorKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s series: %w", indicatorKey, err)
+	}
+
+	points := make([]ChartPoint, 0, len(indicators))
+	for i := len(indicators) - 1; i >= 0; i-- {
+		if indicators[i].Value == nil {
+			continue
+		}
+		points = append(points, ChartPoint{Timestamp: indicators[i].Timestamp, Value: *indicators[i].Value})
+	}
+	return points, nil
+}
+
+// percentageTargetLevel converts a percentage target into a price, using the close
+// nearest to the start of the alert's window before the latest candle as the base
+// like the alert engine does; it returns nil when the history has no usable base price
+func percentageTargetLevel(history []entities.PriceHistory, alert *entities.Alert) *float64 {
+	if len(history) == 0 {
+		return nil
+	}
+
+	pastTime := history[0].Timestamp.Add(-alert.ChangeWindow())
+	var basePrice float64
+	minTimeDiff := time.Duration(math.MaxInt64)
+	for _, candle := range history {
+		timeDiff := candle.Timestamp.Sub(pastTime)
+		if timeDiff < 0 {
+			timeDiff = -timeDiff
+		}
+		if timeDiff < minTimeDiff {
+			minTimeDiff = timeDiff
+			basePrice = candle.ClosePrice
+		}
+	}
+	if basePrice == 0 {
+		return nil
+	}
+
+	change := alert.TargetValue / 100
+	if alert.ConditionType == "down" {
+		change = -change
+	}
+	level := basePrice * (1 + change)
+	return &level
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// alertCostRetention is how many days of per-user evaluation costs are kept
+const alertCostRetention = 31 * 24 * time.Hour
+
+// EvaluationCost is what evaluating alerts cost: how many evaluations ran, the
+// database reads and writes they made and the time they took
+type EvaluationCost struct {
+	Evaluations int64         `json:"evaluations"`
+	DBCalls     int64         `json:"db_calls"`
+	Duration    time.Duration `json:"-"`
+}
+
+// UserEvaluationCost is the evaluation cost of one user's alerts over a day
+type UserEvaluationCost struct {
+	UserID uuid.UUID `json:"user_id"`
+	EvaluationCost
+	DurationMS float64 `json:"evaluation_ms"`
+}
+
+func newUserEvaluationCost(userID uuid.UUID, cost EvaluationCost) UserEvaluationCost {
+	return UserEvaluationCost{
+		UserID:         userID,
+		EvaluationCost: cost,
+		DurationMS:     float64(cost.Duration) / float64(time.Millisecond),
+	}
+}
+
+// AlertCostStore accumulates evaluation costs per user and UTC day, so admins can
+// see whose alerts weigh the most on the engine
+type AlertCostStore interface {
+	// Add adds cost to the user's total of the day
+	Add(ctx context.Context, day time.Time, userID uuid.UUID, cost EvaluationCost) error
+	// Top returns the limit users whose evaluations took the longest that day
+	Top(ctx context.Context, day time.Time, limit int) ([]UserEvaluationCost, error)
+}
+
+// costDay formats the UTC day a cost is accounted under
+func costDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// evaluationMeter counts the database calls of one alert evaluation
+type evaluationMeter struct {
+	dbCalls atomic.Int64
+}
+
+type evaluationMeterKey struct{}
+
+// withEvaluationMeter returns a context whose database calls are counted by meter
+func withEvaluationMeter(ctx context.Context, meter *evaluationMeter) context.Context {
+	return context.WithValue(ctx, evaluationMeterKey{}, meter)
+}
+
+// countDBCall counts a database call against the evaluation carried by ctx, if any
+func countDBCall(ctx context.Context) {
+	if meter, ok := ctx.Value(evaluationMeterKey{}).(*evaluationMeter); ok {
+		meter.dbCalls.Add(1)
+	}
+}
+
+// MemoryAlertCostStore keeps evaluation costs in process; it is only safe for a
+// single instance and is lost on restart
+type MemoryAlertCostStore struct {
+	costs map[string]map[uuid.UUID]EvaluationCost
+	mutex sync.Mutex
+}
+
+// NewMemoryAlertCostStore creates an empty in-process cost store
+func NewMemoryAlertCostStore() *MemoryAlertCostStore {
+	return &MemoryAlertCostStore{costs: make(map[string]map[uuid.UUID]EvaluationCost)}
+}
+
+func (s *MemoryAlertCostStore) Add(ctx context.Context, day time.Time, userID uuid.UUID, cost EvaluationCost) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	users, found := s.costs[costDay(day)]
+	if !found {
+		users = make(map[uuid.UUID]EvaluationCost)
+		s.costs[costDay(day)] = users
+	}
+	total := users[userID]
+	total.Evaluations += cost.Evaluations
+	total.DBCalls += cost.DBCalls
+	total.Duration += cost.Duration
+	users[userID] = total
+	return nil
+}
+
+func (s *MemoryAlertCostStore) Top(ctx context.Context, day time.Time, limit int) ([]UserEvaluationCost, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	top := make([]UserEvaluationCost, 0, len(s.costs[costDay(day)]))
+	for userID, cost := range s.costs[costDay(day)] {
+		top = append(top, newUserEvaluationCost(userID, cost))
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].Duration > top[j].Duration
+	})
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top, nil
+}
+
+// redisCostClient is the subset of the Redis client used by RedisAlertCostStore
+type redisCostClient interface {
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) *redis.ZSliceCmd
+}
+
+// RedisAlertCostStore shares evaluation costs between instances under the
+// "alert_cost:" key space: a hash of totals per user and day, and a sorted set
+// ranking the day's users by evaluation time. Both expire after a month.
+type RedisAlertCostStore struct {
+	client redisCostClient
+	prefix string
+}
+
+// NewRedisAlertCostStore creates a Redis backed cost store
+func NewRedisAlertCostStore(client redisCostClient) *RedisAlertCostStore {
+	return &RedisAlertCostStore{client: client, prefix: "alert_cost:"}
+}
+
+func (s *RedisAlertCostStore) rankingKey(day time.Time) string {
+	return s.prefix + costDay(day)
+}
+
+func (s *RedisAlertCostStore) userKey(day time.Time, userID string) string {
+	return s.prefix + costDay(day) + ":" + userID
+}
+
+func (s *RedisAlertCostStore) Add(ctx context.Context, day time.Time, userID uuid.UUID, cost EvaluationCost) error {
+	userKey := s.userKey(day, userID.String())
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, userKey, "evaluations", cost.Evaluations)
+		pipe.HIncrBy(ctx, userKey, "db_calls", cost.DBCalls)
+		pipe.HIncrBy(ctx, userKey, "duration_us", cost.Duration.Microseconds())
+		pipe.Expire(ctx, userKey, alertCostRetention)
+		pipe.ZIncrBy(ctx, s.rankingKey(day), float64(cost.Duration.Microseconds()), userID.String())
+		pipe.Expire(ctx, s.rankingKey(day), alertCostRetention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add evaluation cost of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+func (s *RedisAlertCostStore) Top(ctx context.Context, day time.Time, limit int) ([]UserEvaluationCost, error) {
+	stop := int64(limit) - 1
+	if limit <= 0 {
+		stop = -1
+	}
+	ranking, err := s.client.ZRevRangeWithScores(ctx, s.rankingKey(day), 0, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank evaluation costs: %w", err)
+	}
+	if len(ranking) == 0 {
+		return []UserEvaluationCost{}, nil
+	}
+
+	totals := make([]*redis.MapStringStringCmd, len(ranking))
+	if _, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, entry := range ranking {
+			totals[i] = pipe.HGetAll(ctx, s.userKey(day, fmt.Sprint(entry.Member)))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read evaluation costs: %w", err)
+	}
+
+	top := make([]UserEvaluationCost, 0, len(ranking))
+	for i, entry := range ranking {
+		userID, err := uuid.Parse(fmt.Sprint(entry.Member))
+		if err != nil {
+			continue
+		}
+		fields := totals[i].Val()
+		evaluations, _ := strconv.ParseInt(fields["evaluations"], 10, 64)
+		dbCalls, _ := strconv.ParseInt(fields["db_calls"], 10, 64)
+		durationUS, _ := strconv.ParseInt(fields["duration_us"], 10, 64)
+		top = append(top, newUserEvaluationCost(userID, EvaluationCost{
+			Evaluations: evaluations,
+			DBCalls:     dbCalls,
+			Duration:    time.Duration(durationUS) * time.Microsecond,
+		}))
+	}
+	return top, nil
+}
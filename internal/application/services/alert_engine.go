@@ -2,15 +2,21 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
 )
 
 // AlertCondition represents the different types of alert conditions
@@ -19,6 +25,8 @@ type AlertCondition string
 const (
 	ConditionPriceAbove     AlertCondition = "price_above"
 	ConditionPriceBelow     AlertCondition = "price_below"
+	ConditionPriceCrossUp   AlertCondition = "price_crosses_up"
+	ConditionPriceCrossDown AlertCondition = "price_crosses_down"
 	ConditionRSIAbove       AlertCondition = "rsi_above"
 	ConditionRSIBelow       AlertCondition = "rsi_below"
 	ConditionPercentageUp   AlertCondition = "percentage_up"
@@ -27,8 +35,25 @@ const (
 	ConditionEMACrossDown   AlertCondition = "ema_cross_down"
 	ConditionSMACrossUp     AlertCondition = "sma_cross_up"
 	ConditionSMACrossDown   AlertCondition = "sma_cross_down"
+	ConditionMACDCrossUp    AlertCondition = "macd_cross_up"
+	ConditionMACDCrossDown  AlertCondition = "macd_cross_down"
+	ConditionCloudEnters    AlertCondition = "ichimoku_cloud_enters"
+	ConditionCloudExits     AlertCondition = "ichimoku_cloud_exits"
+	ConditionCloudExitsUp   AlertCondition = "ichimoku_cloud_exits_above"
+	ConditionCloudExitsDown AlertCondition = "ichimoku_cloud_exits_below"
+	ConditionVolumeAbove    AlertCondition = "volume_above"
+	ConditionVolumeBelow    AlertCondition = "volume_below"
+	ConditionVolumeSpike    AlertCondition = "volume_spike"
+	ConditionChange24hUp    AlertCondition = "change_24h_up"
+	ConditionChange24hDown  AlertCondition = "change_24h_down"
+	ConditionCompositeAnd   AlertCondition = "composite_and"
+	ConditionCompositeOr    AlertCondition = "composite_or"
 )
 
+// volumeAverageWindow is how many candles before the current one a volume spike
+// is measured against
+const volumeAverageWindow = 20
+
 // AlertEvaluationResult represents the result of evaluating an alert
 type AlertEvaluationResult struct {
 	AlertID       uuid.UUID              `json:"alert_id"`
@@ -37,6 +62,7 @@ type AlertEvaluationResult struct {
 	TargetValue   float64                `json:"target_value"`
 	Message       string                 `json:"message"`
 	Context       map[string]interface{} `json:"context"`
+	Trace         *AlertTrace            `json:"trace,omitempty"`
 }
 
 // AlertEngine handles the logic for evaluating and managing alerts
@@ -47,15 +73,36 @@ type AlertEngine struct {
 	notificationRepo          repositories.NotificationRepository
 	technicalIndicatorService *TechnicalIndicatorService
 	webSocketService          AlertWebSocketService
+	abuseService              *AbuseService
+	latencyRecorder           LatencyRecorder
+	durationRecorder          DurationRecorder
+	staleDataRecorder         StaleDataRecorder
 	logger                    *logrus.Logger
+	clock                     clock.Clock
+
+	// Alert throttling, shared between instances when backed by Redis
+	throttles ThrottleStore
+
+	// State of crossover conditions, shared between instances when backed by Redis
+	stateStore AlertStateStore
+
+	// Per-user evaluation costs; nil leaves evaluations unaccounted
+	costStore AlertCostStore
+
+	// Shards of the alerts this replica evaluates; nil evaluates them all
+	sharder *AlertSharder
 
-	// Alert throttling
-	throttleMap   map[uuid.UUID]time.Time
-	throttleMutex sync.RWMutex
+	// Daily summaries percentage alerts over more than a day read their base price
+	// from; nil scans the alert's candles
+	summaryRepo repositories.PriceSummaryRepository
 
-	// Alert state cache
-	alertStateCache map[uuid.UUID]map[string]interface{}
-	stateCacheMutex sync.RWMutex
+	// Cryptocurrencies change_24h alerts read the exchange's 24h statistics from;
+	// nil fails their evaluation
+	cryptoRepo repositories.CryptoCurrencyRepository
+
+	// How old market data may be past its candle's close for alerts without a
+	// limit of their own; 0 evaluates data of any age
+	maxDataAge time.Duration
 }
 
 // NewAlertEngine creates a new alert engine
@@ -74,8 +121,9 @@ func NewAlertEngine(
 		notificationRepo:          notificationRepo,
 		technicalIndicatorService: technicalIndicatorService,
 		logger:                    logger,
-		throttleMap:               make(map[uuid.UUID]time.Time),
-		alertStateCache:           make(map[uuid.UUID]map[string]interface{}),
+		clock:                     clock.New(),
+		throttles:                 NewMemoryThrottleStore(),
+		stateStore:                NewMemoryAlertStateStore(),
 	}
 }
 
@@ -84,12 +132,118 @@ func (ae *AlertEngine) SetWebSocketService(webSocketService AlertWebSocketServic
 	ae.webSocketService = webSocketService
 }
 
+// SetAbuseService throttles the triggers of users flagged for alert abuse
+func (ae *AlertEngine) SetAbuseService(abuseService *AbuseService) {
+	ae.abuseService = abuseService
+}
+
+// SetLatencyRecorder reports the evaluation, broadcast and end-to-end latencies of
+// triggered alerts
+func (ae *AlertEngine) SetLatencyRecorder(recorder LatencyRecorder) {
+	ae.latencyRecorder = recorder
+}
+
+// SetDurationRecorder reports how long each evaluation and trigger broadcast took,
+// with the context of its span
+func (ae *AlertEngine) SetDurationRecorder(recorder DurationRecorder) {
+	ae.durationRecorder = recorder
+}
+
+// SetStaleDataRecorder counts the evaluations skipped because of stale market data
+func (ae *AlertEngine) SetStaleDataRecorder(recorder StaleDataRecorder) {
+	ae.staleDataRecorder = recorder
+}
+
+// SetMaxDataAge skips the evaluation of alerts without a limit of their own when
+// their latest candle or indicator closed more than maxAge ago
+func (ae *AlertEngine) SetMaxDataAge(maxAge time.Duration) {
+	ae.maxDataAge = maxAge
+}
+
+// SetClock replaces the clock used for throttling and timestamps
+func (ae *AlertEngine) SetClock(c clock.Clock) {
+	ae.clock = c
+	if memory, ok := ae.throttles.(*MemoryThrottleStore); ok {
+		memory.SetClock(c)
+	}
+	if memory, ok := ae.stateStore.(*MemoryAlertStateStore); ok {
+		memory.SetClock(c)
+	}
+}
+
+// SetThrottleStore replaces the in-process alert throttles, e.g. with a Redis store so
+// every instance sees the same cooldowns and an alert triggers only once across regions
+func (ae *AlertEngine) SetThrottleStore(store ThrottleStore) {
+	ae.throttles = store
+}
+
+// SetStateStore replaces the in-process state of crossover conditions, e.g. with a
+// Redis store so the side of the level, average or signal line an alert was last
+// seen on survives restarts and is shared between instances
+func (ae *AlertEngine) SetStateStore(store AlertStateStore) {
+	ae.stateStore = store
+}
+
+// SetCostStore accounts the evaluations, database calls and evaluation time of every
+// alert to its owner, e.g. to spot users whose alerts cost more than their fair share
+func (ae *AlertEngine) SetCostStore(store AlertCostStore) {
+	ae.costStore = store
+}
+
+// SetSharder splits the evaluation of enabled alerts with the other replicas, each
+// evaluating only the alerts of the shards it holds
+func (ae *AlertEngine) SetSharder(sharder *AlertSharder) {
+	ae.sharder = sharder
+}
+
+// SetPriceSummaryRepository makes percentage alerts over windows longer than a day
+// read their base price from the daily summaries rather than scan their candles
+func (ae *AlertEngine) SetPriceSummaryRepository(repo repositories.PriceSummaryRepository) {
+	ae.summaryRepo = repo
+}
+
+// SetCryptoCurrencyRepository makes change_24h alerts read the exchange-reported 24h
+// change stored on the cryptocurrencies
+func (ae *AlertEngine) SetCryptoCurrencyRepository(repo repositories.CryptoCurrencyRepository) {
+	ae.cryptoRepo = repo
+}
+
 // EvaluateAllAlerts evaluates all enabled alerts and triggers those that meet conditions
 func (ae *AlertEngine) EvaluateAllAlerts(ctx context.Context) ([]AlertEvaluationResult, error) {
 	alerts, err := ae.alertRepo.GetEnabled(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get enabled alerts: %w", err)
 	}
+	results := ae.evaluateAlerts(ctx, ae.ownedAlerts(ctx, alerts))
+
+	// Only a full cycle tells startup recovery that no candle went unevaluated
+	ae.markEvaluated(ctx, ae.clock.Now())
+	return results, nil
+}
+
+// EvaluateSymbolAlerts evaluates the enabled alerts on one symbol, e.g. when one of
+// its candles closed
+func (ae *AlertEngine) EvaluateSymbolAlerts(ctx context.Context, symbol string) ([]AlertEvaluationResult, error) {
+	alerts, err := ae.alertRepo.GetEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled alerts: %w", err)
+	}
+
+	onSymbol := alerts[:0]
+	for _, alert := range alerts {
+		if alert.Symbol == symbol {
+			onSymbol = append(onSymbol, alert)
+		}
+	}
+	return ae.evaluateAlerts(ctx, ae.ownedAlerts(ctx, onSymbol)), nil
+}
+
+// evaluateAlerts evaluates the alerts concurrently and returns the results of those
+// that could be evaluated
+func (ae *AlertEngine) evaluateAlerts(ctx context.Context, alerts []entities.Alert) []AlertEvaluationResult {
+	// Alerts on the same symbol and timeframe share one read of its market data
+	snapshot := newMarketSnapshot(ae.priceHistoryRepo, ae.technicalIndicatorRepo)
+	ctx = withMarketSnapshot(ctx, snapshot)
 
 	var results []AlertEvaluationResult
 	var wg sync.WaitGroup
@@ -102,6 +256,10 @@ func (ae *AlertEngine) EvaluateAllAlerts(ctx context.Context) ([]AlertEvaluation
 			defer wg.Done()
 
 			result, err := ae.EvaluateAlert(ctx, &alert)
+			if errors.Is(err, entities.ErrStaleData) {
+				ae.logger.WithError(err).WithField("alert_id", alert.ID).Debug("Skipped alert evaluation on stale data")
+				return
+			}
 			if err != nil {
 				ae.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to evaluate alert")
 				return
@@ -124,24 +282,75 @@ func (ae *AlertEngine) EvaluateAllAlerts(ctx context.Context) ([]AlertEvaluation
 		results = append(results, result)
 	}
 
-	return results, nil
+	ae.logger.WithFields(logrus.Fields{
+		"alerts":       len(alerts),
+		"market_reads": snapshot.size(),
+	}).Debug("Evaluated enabled alerts")
+
+	return results
+}
+
+// ownedAlerts rebalances the shards and keeps the alerts of the ones this replica holds
+func (ae *AlertEngine) ownedAlerts(ctx context.Context, alerts []entities.Alert) []entities.Alert {
+	if ae.sharder == nil {
+		return alerts
+	}
+	if err := ae.sharder.Rebalance(ctx); err != nil {
+		ae.logger.WithError(err).Warn("Failed to rebalance alert shards")
+	}
+
+	owned := alerts[:0]
+	for _, alert := range alerts {
+		if ae.sharder.Owns(alert.ID) {
+			owned = append(owned, alert)
+		}
+	}
+	return owned
 }
 
 // EvaluateAlert evaluates a single alert and returns the result
 func (ae *AlertEngine) EvaluateAlert(ctx context.Context, alert *entities.Alert) (*AlertEvaluationResult, error) {
+	// Chained alerts wait for the alert they depend on to trigger
+	if !alert.Armed() {
+		return nil, nil
+	}
+
 	// Check if alert is throttled
-	if ae.isThrottled(alert.ID) {
+	if ae.isThrottled(ctx, alert) {
 		return nil, nil
 	}
 
+	ctx, span := startSpan(ctx, "alert.evaluate",
+		attribute.String("alert.id", alert.ID.String()),
+		attribute.String("alert.symbol", alert.Symbol),
+		attribute.String("alert.type", alert.AlertType))
+	defer span.End()
+	defer observeDuration(ctx, ae.durationRecorder, DurationAlertEvaluation, time.Now())
+
+	// Account the evaluation's database calls and time to the alert's owner
+	if ae.costStore != nil {
+		meter := &evaluationMeter{}
+		ctx = withEvaluationMeter(ctx, meter)
+		defer ae.recordCost(ctx, alert, meter, time.Now())
+	}
+
+	// Reject alerts the engine cannot evaluate before touching market data
+	if err := alert.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Get current market data
-	priceData, err := ae.priceHistoryRepo.GetLatest(ctx, alert.Symbol, alert.Timeframe)
+	priceData, err := ae.latestPrice(ctx, alert)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price data for %s: %w", alert.Symbol, err)
 	}
 
 	if priceData == nil {
-		return nil, fmt.Errorf("no price data available for %s", alert.Symbol)
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no price data available for %s", alert.Symbol)
+	}
+
+	if err := ae.checkFreshness(alert, StaleSourcePrice, priceData.Timestamp); err != nil {
+		return nil, err
 	}
 
 	// Evaluate based on alert type
@@ -149,6 +358,45 @@ func (ae *AlertEngine) EvaluateAlert(ctx context.Context, alert *entities.Alert)
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate condition: %w", err)
 	}
+	result.Trace = newAlertTrace(priceData, ae.clock.Now())
+
+	return ae.settleEvaluation(ctx, alert, result), nil
+}
+
+// recordCost adds an evaluation that started at started to its owner's costs. Missing
+// one only skews the statistics, so a failure is logged rather than returned.
+func (ae *AlertEngine) recordCost(ctx context.Context, alert *entities.Alert, meter *evaluationMeter, started time.Time) {
+	cost := EvaluationCost{
+		Evaluations: 1,
+		DBCalls:     meter.dbCalls.Load(),
+		Duration:    time.Since(started),
+	}
+	if err := ae.costStore.Add(ctx, ae.clock.Now(), alert.UserID, cost); err != nil {
+		ae.logger.WithError(err).WithField("user_id", alert.UserID).Warn("Failed to record alert evaluation cost")
+	}
+}
+
+// settleEvaluation lets a result that meets its condition trigger the alert unless the
+// user is throttled for abuse or another instance already triggered it
+func (ae *AlertEngine) settleEvaluation(ctx context.Context, alert *entities.Alert, result *AlertEvaluationResult) *AlertEvaluationResult {
+	// Flagged users only get a trigger through every so often
+	if result.ShouldTrigger && ae.abuseService != nil && !ae.abuseService.AllowTrigger(ctx, alert.UserID) {
+		ae.logger.WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"user_id":  alert.UserID,
+		}).Debug("Alert trigger suppressed for flagged user")
+		result.ShouldTrigger = false
+		result.Context["abuse_throttled"] = true
+		return result
+	}
+
+	// Another instance may be triggering the same alert; only the one that takes the throttle does
+	if result.ShouldTrigger && !ae.acquireTrigger(ctx, alert) {
+		ae.logger.WithField("alert_id", alert.ID).Debug("Alert already triggered by another instance")
+		result.ShouldTrigger = false
+		result.Context["deduplicated"] = true
+		return result
+	}
 
 	// If alert should trigger, process it
 	if result.ShouldTrigger {
@@ -158,7 +406,7 @@ func (ae *AlertEngine) EvaluateAlert(ctx context.Context, alert *entities.Alert)
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // evaluateCondition evaluates the specific condition for an alert
@@ -182,16 +430,37 @@ func (ae *AlertEngine) evaluateCondition(ctx context.Context, alert *entities.Al
 		result.ShouldTrigger = priceData.ClosePrice < alert.TargetValue
 		result.Message = fmt.Sprintf("Price of %s is %.8f (target: %.8f)", alert.Symbol, priceData.ClosePrice, alert.TargetValue)
 
+	case ConditionPriceCrossUp, ConditionPriceCrossDown:
+		ae.evaluatePriceCross(ctx, alert, priceData, result)
+
 	case ConditionPercentageUp, ConditionPercentageDown:
 		return ae.evaluatePercentageChange(ctx, alert, priceData, result)
 
+	case ConditionChange24hUp, ConditionChange24hDown:
+		return ae.evaluateChange24h(ctx, alert, result)
+
 	case ConditionRSIAbove, ConditionRSIBelow:
 		return ae.evaluateRSICondition(ctx, alert, priceData, result)
 
 	case ConditionEMACrossUp, ConditionEMACrossDown, ConditionSMACrossUp, ConditionSMACrossDown:
 		return ae.evaluateMovingAverageCross(ctx, alert, priceData, result)
 
+	case ConditionMACDCrossUp, ConditionMACDCrossDown:
+		return ae.evaluateMACDCross(ctx, alert, priceData, result)
+
+	case ConditionCloudEnters, ConditionCloudExits, ConditionCloudExitsUp, ConditionCloudExitsDown:
+		return ae.evaluateIchimokuCloud(ctx, alert, priceData, result)
+
+	case ConditionVolumeAbove, ConditionVolumeBelow, ConditionVolumeSpike:
+		return ae.evaluateVolumeCondition(ctx, alert, priceData, result)
+
+	case ConditionCompositeAnd, ConditionCompositeOr:
+		return ae.evaluateComposite(ctx, alert, priceData, result)
+
 	default:
+		if indicator, ok := indicators.LookupCustomIndicator(alert.AlertType); ok {
+			return ae.evaluateCustomIndicator(ctx, alert, indicator, priceData, result)
+		}
 		return nil, fmt.Errorf("unsupported alert condition: %s", alertCondition)
 	}
 
@@ -206,34 +475,126 @@ func (ae *AlertEngine) evaluateCondition(ctx context.Context, alert *entities.Al
 	return result, nil
 }
 
-// evaluatePercentageChange evaluates percentage change conditions
-func (ae *AlertEngine) evaluatePercentageChange(ctx context.Context, alert *entities.Alert, currentPrice *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
-	// Get price from 24 hours ago
-	pastTime := currentPrice.Timestamp.Add(-24 * time.Hour)
+// evaluatePriceCross triggers when the close moved from one side of the target to the
+// other since the previous evaluation; the first evaluation only records the side
+func (ae *AlertEngine) evaluatePriceCross(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) {
+	previousState, exists := ae.previousState(ctx, alert)
+	currentClose := priceData.ClosePrice
+	ae.saveState(ctx, alert, map[string]interface{}{
+		"close":     currentClose,
+		"timestamp": priceData.Timestamp,
+	})
+
+	result.CurrentValue = currentClose
+	previousClose, ok := previousState["close"].(float64)
+	if !exists || !ok {
+		result.Message = fmt.Sprintf("Monitoring %s for a cross of %.8f", alert.Symbol, alert.TargetValue)
+		return
+	}
+	result.Context["previous_close"] = previousClose
+
+	direction := "above"
+	switch AlertCondition(alert.AlertType + "_" + alert.ConditionType) {
+	case ConditionPriceCrossUp:
+		result.ShouldTrigger = previousClose <= alert.TargetValue && currentClose > alert.TargetValue
+	case ConditionPriceCrossDown:
+		direction = "below"
+		result.ShouldTrigger = previousClose >= alert.TargetValue && currentClose < alert.TargetValue
+	}
 
-	// Get historical data near that time
-	historicalData, err := ae.priceHistoryRepo.GetBySymbol(ctx, alert.Symbol, alert.Timeframe, 50)
+	if result.ShouldTrigger {
+		result.Message = fmt.Sprintf("Price of %s crossed %s %.8f (from %.8f to %.8f)", alert.Symbol, direction, alert.TargetValue, previousClose, currentClose)
+	} else {
+		result.Message = fmt.Sprintf("Price of %s is %.8f (target: crosses %s %.8f)", alert.Symbol, currentClose, direction, alert.TargetValue)
+	}
+}
+
+// alertStateKey is the state store key of an alert or composite leaf
+func alertStateKey(alertID uuid.UUID) string {
+	return "alert:" + alertID.String()
+}
+
+// previousState returns the state saved by the alert's last evaluation, upgraded to
+// the current schema version. It is always read from the state store, as another
+// instance may have evaluated the alert since. A snapshot written by a newer build,
+// or taken before the alert's timeframe or target changed, is ignored, so the
+// evaluation starts over rather than comparing against values it can't trust.
+func (ae *AlertEngine) previousState(ctx context.Context, alert *entities.Alert) (map[string]interface{}, bool) {
+	state, exists, err := ae.stateStore.Load(ctx, alertStateKey(alert.ID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get historical data: %w", err)
+		ae.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to load alert state")
+		return nil, false
+	}
+	if !exists {
+		return nil, false
 	}
 
-	var basePrice float64
-	minTimeDiff := time.Duration(math.MaxInt64)
+	state, reason := upgradeAlertState(alert, state)
+	if reason != "" {
+		ae.logger.WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"reason":   reason,
+		}).Debug("Ignoring saved alert state")
+		return nil, false
+	}
+	return state, true
+}
 
-	// Find the closest price to 24 hours ago
-	for _, data := range historicalData {
-		timeDiff := data.Timestamp.Sub(pastTime)
-		if timeDiff < 0 {
-			timeDiff = -timeDiff
+// saveState records the alert's state for its next evaluation, versioned and kept for
+// a few candles of the alert's timeframe. A state store failure only risks missing a
+// cross, so it is logged rather than returned.
+func (ae *AlertEngine) saveState(ctx context.Context, alert *entities.Alert, state map[string]interface{}) {
+	state = versionAlertState(alert, state)
+	if err := ae.stateStore.Save(ctx, alertStateKey(alert.ID), state, AlertStateTTL(alert.Timeframe)); err != nil {
+		ae.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to save alert state")
+	}
+}
+
+// evaluatePercentageChange evaluates percentage change conditions
+func (ae *AlertEngine) evaluatePercentageChange(ctx context.Context, alert *entities.Alert, currentPrice *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	window := alert.ChangeWindow()
+	windowLabel := "24h"
+	if alert.WindowDays > 1 {
+		windowLabel = fmt.Sprintf("%dd", alert.WindowDays)
+	}
+	pastTime := currentPrice.Timestamp.Add(-window)
+
+	var basePrice float64
+	if window > oneDay {
+		// Windows longer than a day reach past the candles read below, so their base
+		// price is found among the daily summaries
+		if ae.summaryRepo == nil {
+			return nil, entities.NewDomainError(entities.ErrInsufficientData, "daily summaries are not available for the %s window", windowLabel)
 		}
-		if timeDiff < minTimeDiff {
-			minTimeDiff = timeDiff
-			basePrice = data.ClosePrice
+		var err error
+		basePrice, err = ae.summaryBasePrice(ctx, alert.Symbol, pastTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+		}
+	} else {
+		// Get historical data near that time
+		historicalData, err := ae.priceHistory(ctx, alert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get historical data: %w", err)
+		}
+
+		minTimeDiff := time.Duration(math.MaxInt64)
+
+		// Find the closest price to the start of the window
+		for _, data := range historicalData {
+			timeDiff := data.Timestamp.Sub(pastTime)
+			if timeDiff < 0 {
+				timeDiff = -timeDiff
+			}
+			if timeDiff < minTimeDiff {
+				minTimeDiff = timeDiff
+				basePrice = data.ClosePrice
+			}
 		}
 	}
 
 	if basePrice == 0 {
-		return nil, fmt.Errorf("no historical data found for percentage calculation")
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no historical data found for percentage calculation")
 	}
 
 	percentageChange := ((currentPrice.ClosePrice - basePrice) / basePrice) * 100
@@ -243,29 +604,293 @@ func (ae *AlertEngine) evaluatePercentageChange(ctx context.Context, alert *enti
 	switch alertCondition {
 	case ConditionPercentageUp:
 		result.ShouldTrigger = percentageChange >= alert.TargetValue
-		result.Message = fmt.Sprintf("%s gained %.2f%% in 24h (target: %.2f%%)", alert.Symbol, percentageChange, alert.TargetValue)
+		result.Message = fmt.Sprintf("%s gained %.2f%% in %s (target: %.2f%%)", alert.Symbol, percentageChange, windowLabel, alert.TargetValue)
 	case ConditionPercentageDown:
 		result.ShouldTrigger = percentageChange <= -alert.TargetValue
-		result.Message = fmt.Sprintf("%s lost %.2f%% in 24h (target: %.2f%%)", alert.Symbol, math.Abs(percentageChange), alert.TargetValue)
+		result.Message = fmt.Sprintf("%s lost %.2f%% in %s (target: %.2f%%)", alert.Symbol, math.Abs(percentageChange), windowLabel, alert.TargetValue)
 	}
 
 	result.Context["base_price"] = basePrice
 	result.Context["current_price"] = currentPrice.ClosePrice
 	result.Context["percentage_change"] = percentageChange
+	result.Context["window"] = windowLabel
+
+	return result, nil
+}
+
+// evaluateChange24h compares the exchange-reported 24h percentage change of the
+// symbol with the target, up by at least the target or down by at least it
+func (ae *AlertEngine) evaluateChange24h(ctx context.Context, alert *entities.Alert, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	if ae.cryptoRepo == nil {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "24h statistics are not available")
+	}
+	countDBCall(ctx)
+	crypto, err := ae.cryptoRepo.GetBySymbol(ctx, alert.Symbol)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.NewDomainError(entities.ErrInsufficientData, "no 24h statistics for %s", alert.Symbol)
+		}
+		return nil, fmt.Errorf("failed to get cryptocurrency: %w", err)
+	}
+	ticker := crypto.Ticker24h
+	if ticker.RefreshedAt == nil {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no 24h statistics for %s", alert.Symbol)
+	}
+	if err := ae.checkAge(alert, StaleSourceTicker, ae.clock.Now().Sub(*ticker.RefreshedAt)); err != nil {
+		return nil, err
+	}
+
+	change := ticker.PriceChangePercent
+	result.CurrentValue = change
+	switch AlertCondition(alert.AlertType + "_" + alert.ConditionType) {
+	case ConditionChange24hUp:
+		result.ShouldTrigger = change >= alert.TargetValue
+		result.Message = fmt.Sprintf("%s changed %+.2f%% in 24h on the exchange (target: up %.2f%%)", alert.Symbol, change, alert.TargetValue)
+	case ConditionChange24hDown:
+		result.ShouldTrigger = change <= -alert.TargetValue
+		result.Message = fmt.Sprintf("%s changed %+.2f%% in 24h on the exchange (target: down %.2f%%)", alert.Symbol, change, alert.TargetValue)
+	}
+
+	result.Context["last_price"] = ticker.LastPrice
+	result.Context["price_change_24h"] = ticker.PriceChange
+	result.Context["price_change_percent"] = change
+	result.Context["quote_volume"] = ticker.QuoteVolume
+	result.Context["ticker_24h_at"] = ticker.RefreshedAt.Format(time.RFC3339)
+
+	return result, nil
+}
+
+// summaryBasePrice returns the close of the daily summary ending nearest pastTime,
+// or 0 when no summary is near it
+func (ae *AlertEngine) summaryBasePrice(ctx context.Context, symbol string, pastTime time.Time) (float64, error) {
+	countDBCall(ctx)
+	summaries, err := ae.summaryRepo.GetByRange(ctx, symbol, startOfDay(pastTime).Add(-oneDay), pastTime)
+	if err != nil {
+		return 0, err
+	}
+
+	var basePrice float64
+	minTimeDiff := time.Duration(math.MaxInt64)
+	for _, summary := range summaries {
+		timeDiff := summary.Day.Add(oneDay).Sub(pastTime)
+		if timeDiff < 0 {
+			timeDiff = -timeDiff
+		}
+		if timeDiff < minTimeDiff {
+			minTimeDiff = timeDiff
+			basePrice = summary.ClosePrice
+		}
+	}
+	return basePrice, nil
+}
+
+// evaluateVolumeCondition compares the volume of the current candle with the target,
+// or for spikes with target times the average volume of the candles before it
+func (ae *AlertEngine) evaluateVolumeCondition(ctx context.Context, alert *entities.Alert, currentCandle *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	volume := currentCandle.Volume
+	result.CurrentValue = volume
+	result.Context["volume"] = volume
+
+	switch AlertCondition(alert.AlertType + "_" + alert.ConditionType) {
+	case ConditionVolumeAbove:
+		result.ShouldTrigger = volume > alert.TargetValue
+		result.Message = fmt.Sprintf("Volume of %s is %.2f (target: above %.2f)", alert.Symbol, volume, alert.TargetValue)
+	case ConditionVolumeBelow:
+		result.ShouldTrigger = volume < alert.TargetValue
+		result.Message = fmt.Sprintf("Volume of %s is %.2f (target: below %.2f)", alert.Symbol, volume, alert.TargetValue)
+	case ConditionVolumeSpike:
+		historicalData, err := ae.priceHistory(ctx, alert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get historical data: %w", err)
+		}
+
+		// History is newest first and may include the current candle
+		var total float64
+		candles := 0
+		for _, data := range historicalData {
+			if !data.Timestamp.Before(currentCandle.Timestamp) {
+				continue
+			}
+			total += data.Volume
+			if candles++; candles == volumeAverageWindow {
+				break
+			}
+		}
+		if candles < volumeAverageWindow || total == 0 {
+			return nil, entities.NewDomainError(entities.ErrInsufficientData, "need %d candles of volume history for %s", volumeAverageWindow, alert.Symbol)
+		}
+
+		averageVolume := total / float64(candles)
+		ratio := volume / averageVolume
+		result.CurrentValue = ratio
+		result.ShouldTrigger = ratio >= alert.TargetValue
+		result.Message = fmt.Sprintf("Volume of %s is %.2fx its %d-candle average (target: %.2fx)", alert.Symbol, ratio, volumeAverageWindow, alert.TargetValue)
+		result.Context["average_volume"] = averageVolume
+		result.Context["volume_ratio"] = ratio
+	}
+
+	return result, nil
+}
+
+// conditionOutcome is the evaluation of one leaf of a composite alert
+type conditionOutcome struct {
+	AlertType     string
+	ConditionType string
+	TargetValue   float64
+	CurrentValue  float64
+	Met           bool
+	Message       string
+}
+
+// evaluateComposite evaluates the condition tree of a composite alert, combining
+// each group's conditions with its "and" or "or"
+func (ae *AlertEngine) evaluateComposite(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	var outcomes []conditionOutcome
+	met, err := ae.evaluateConditionGroup(ctx, alert, alert.ConditionType, alert.Conditions, priceData, &outcomes)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	metCount := 0
+	conditions := make([]map[string]interface{}, len(outcomes))
+	for i, outcome := range outcomes {
+		if outcome.Met {
+			metCount++
+			messages = append(messages, outcome.Message)
+		}
+		conditions[i] = map[string]interface{}{
+			"alert_type":     outcome.AlertType,
+			"condition_type": outcome.ConditionType,
+			"target_value":   outcome.TargetValue,
+			"current_value":  outcome.CurrentValue,
+			"met":            outcome.Met,
+			"message":        outcome.Message,
+		}
+	}
+
+	result.ShouldTrigger = met
+	result.CurrentValue = float64(metCount)
+	result.Message = fmt.Sprintf("%d of %d conditions met for %s", metCount, len(outcomes), alert.Symbol)
+	if len(messages) > 0 {
+		result.Message += ": " + strings.Join(messages, "; ")
+	}
+	result.Context["conditions"] = conditions
 
 	return result, nil
 }
 
+// evaluateConditionGroup evaluates every condition of a group, recursing into nested
+// groups. Conditions aren't short-circuited: crossover conditions must see every
+// candle to detect a cross. A condition without market data yet counts as not met,
+// so it can't hold back the other side of an "or".
+func (ae *AlertEngine) evaluateConditionGroup(ctx context.Context, alert *entities.Alert, operator string, conditions []entities.AlertCondition, priceData *entities.PriceHistory, outcomes *[]conditionOutcome) (bool, error) {
+	met := operator == "and"
+	for _, condition := range conditions {
+		var conditionMet bool
+		if condition.IsGroup() {
+			groupMet, err := ae.evaluateConditionGroup(ctx, alert, condition.ConditionType, condition.Conditions, priceData, outcomes)
+			if err != nil {
+				return false, err
+			}
+			conditionMet = groupMet
+		} else {
+			// Each leaf is evaluated as an alert of its own, keyed by the condition ID
+			// so crossover state isn't shared between leaves
+			leaf := *alert
+			leaf.ID = condition.ID
+			leaf.AlertType = condition.AlertType
+			leaf.ConditionType = condition.ConditionType
+			leaf.TargetValue = condition.TargetValue
+			leaf.Conditions = nil
+
+			outcome := conditionOutcome{
+				AlertType:     condition.AlertType,
+				ConditionType: condition.ConditionType,
+				TargetValue:   condition.TargetValue,
+			}
+			leafResult, err := ae.evaluateCondition(ctx, &leaf, priceData)
+			switch {
+			case errors.Is(err, entities.ErrInsufficientData), errors.Is(err, gorm.ErrRecordNotFound):
+				outcome.Message = err.Error()
+			case err != nil:
+				return false, fmt.Errorf("failed to evaluate %s %s condition: %w", condition.AlertType, condition.ConditionType, err)
+			default:
+				outcome.CurrentValue = leafResult.CurrentValue
+				outcome.Met = leafResult.ShouldTrigger
+				outcome.Message = leafResult.Message
+			}
+			*outcomes = append(*outcomes, outcome)
+			conditionMet = outcome.Met
+		}
+
+		if operator == "and" {
+			met = met && conditionMet
+		} else {
+			met = met || conditionMet
+		}
+	}
+	return met, nil
+}
+
+// latestPrice returns the latest candle of the alert's market, from the cycle's
+// snapshot when there is one
+func (ae *AlertEngine) latestPrice(ctx context.Context, alert *entities.Alert) (*entities.PriceHistory, error) {
+	if snapshot := marketSnapshotFrom(ctx); snapshot != nil {
+		return snapshot.latest(ctx, alert.Symbol, alert.Timeframe)
+	}
+	countDBCall(ctx)
+	return ae.priceHistoryRepo.GetLatest(ctx, alert.Symbol, alert.Timeframe)
+}
+
+// priceHistory returns the recent candles of the alert's market, from the cycle's
+// snapshot when there is one
+func (ae *AlertEngine) priceHistory(ctx context.Context, alert *entities.Alert) ([]entities.PriceHistory, error) {
+	if snapshot := marketSnapshotFrom(ctx); snapshot != nil {
+		return snapshot.history(ctx, alert.Symbol, alert.Timeframe)
+	}
+	countDBCall(ctx)
+	return ae.priceHistoryRepo.GetBySymbol(ctx, alert.Symbol, alert.Timeframe, percentageHistoryLimit)
+}
+
+// latestIndicator returns the latest value of the indicator, failing with
+// ErrStaleData when it is too old to evaluate the alert on
+func (ae *AlertEngine) latestIndicator(ctx context.Context, alert *entities.Alert, indicatorKey string, candleTime time.Time) (*entities.TechnicalIndicator, error) {
+	indicator, err := ae.readIndicator(ctx, alert, indicatorKey, candleTime)
+	if err != nil || indicator == nil {
+		return indicator, err
+	}
+	if err := ae.checkFreshness(alert, StaleSourceIndicator, indicator.Timestamp); err != nil {
+		return nil, err
+	}
+	return indicator, nil
+}
+
+// readIndicator prefers the result the indicator service computed for the current
+// candle over a database read
+func (ae *AlertEngine) readIndicator(ctx context.Context, alert *entities.Alert, indicatorKey string, candleTime time.Time) (*entities.TechnicalIndicator, error) {
+	if ae.technicalIndicatorService != nil {
+		if indicator, found := ae.technicalIndicatorService.CachedIndicator(ctx, alert.Symbol, alert.Timeframe, indicatorKey, candleTime); found {
+			return indicator, nil
+		}
+	}
+	if snapshot := marketSnapshotFrom(ctx); snapshot != nil {
+		return snapshot.indicator(ctx, alert.Symbol, alert.Timeframe, indicatorKey)
+	}
+	countDBCall(ctx)
+	return ae.technicalIndicatorRepo.GetLatestByKey(ctx, alert.Symbol, alert.Timeframe, indicatorKey)
+}
+
 // evaluateRSICondition evaluates RSI-based conditions
 func (ae *AlertEngine) evaluateRSICondition(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
 	// Get latest RSI indicator
-	rsiIndicator, err := ae.technicalIndicatorRepo.GetLatest(ctx, alert.Symbol, alert.Timeframe, "rsi")
+	rsiIndicator, err := ae.latestIndicator(ctx, alert, entities.IndicatorKey("RSI", rsiPeriod), priceData.Timestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get RSI indicator: %w", err)
 	}
 
 	if rsiIndicator == nil || rsiIndicator.Value == nil {
-		return nil, fmt.Errorf("no RSI data available for %s", alert.Symbol)
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no RSI data available for %s", alert.Symbol)
 	}
 
 	rsiValue := *rsiIndicator.Value
@@ -287,15 +912,41 @@ func (ae *AlertEngine) evaluateRSICondition(ctx context.Context, alert *entities
 	return result, nil
 }
 
+// evaluateCustomIndicator evaluates above/below conditions on a registered custom indicator
+func (ae *AlertEngine) evaluateCustomIndicator(ctx context.Context, alert *entities.Alert, indicator indicators.CustomIndicator, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	latest, err := ae.latestIndicator(ctx, alert, indicators.CustomIndicatorKey(indicator), priceData.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s indicator: %w", indicator.Name(), err)
+	}
+
+	if latest == nil || latest.Value == nil {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no %s data available for %s", indicator.Name(), alert.Symbol)
+	}
+
+	value := *latest.Value
+	result.CurrentValue = value
+
+	switch alert.ConditionType {
+	case "above":
+		result.ShouldTrigger = value > alert.TargetValue
+	case "below":
+		result.ShouldTrigger = value < alert.TargetValue
+	}
+	result.Message = fmt.Sprintf("%s of %s is %.4f (target: %s %.4f)", indicator.Name(), alert.Symbol, value, alert.ConditionType, alert.TargetValue)
+
+	result.Context["indicator_key"] = latest.IndicatorKey
+	result.Context["indicator_value"] = value
+	result.Context["indicator_timestamp"] = latest.Timestamp
+
+	return result, nil
+}
+
 // evaluateMovingAverageCross evaluates moving average crossover conditions
 func (ae *AlertEngine) evaluateMovingAverageCross(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
 	// For MA crosses, we need to check if there was a crossover
 	// This requires comparing current and previous states
 
-	// Get state from cache
-	ae.stateCacheMutex.RLock()
-	previousState, exists := ae.alertStateCache[alert.ID]
-	ae.stateCacheMutex.RUnlock()
+	previousState, exists := ae.previousState(ctx, alert)
 
 	// Get short and long period MAs (assuming target value represents the short period)
 	shortPeriod := int(alert.TargetValue)
@@ -306,26 +957,26 @@ func (ae *AlertEngine) evaluateMovingAverageCross(ctx context.Context, alert *en
 
 	switch alertCondition {
 	case ConditionEMACrossUp, ConditionEMACrossDown:
-		indicatorType = "ema"
+		indicatorType = "EMA"
 	case ConditionSMACrossUp, ConditionSMACrossDown:
-		indicatorType = "sma"
+		indicatorType = "SMA"
 	default:
 		return nil, fmt.Errorf("unsupported MA cross condition: %s", alertCondition)
 	}
 
 	// Get current MAs
-	shortMA, err := ae.technicalIndicatorRepo.GetLatest(ctx, alert.Symbol, alert.Timeframe, fmt.Sprintf("%s_%d", indicatorType, shortPeriod))
+	shortMA, err := ae.latestIndicator(ctx, alert, entities.IndicatorKey(indicatorType, shortPeriod), priceData.Timestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get short %s: %w", indicatorType, err)
 	}
 
-	longMA, err := ae.technicalIndicatorRepo.GetLatest(ctx, alert.Symbol, alert.Timeframe, fmt.Sprintf("%s_%d", indicatorType, longPeriod))
+	longMA, err := ae.latestIndicator(ctx, alert, entities.IndicatorKey(indicatorType, longPeriod), priceData.Timestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get long %s: %w", indicatorType, err)
 	}
 
 	if shortMA == nil || longMA == nil || shortMA.Value == nil || longMA.Value == nil {
-		return nil, fmt.Errorf("insufficient MA data for %s", alert.Symbol)
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "insufficient MA data for %s", alert.Symbol)
 	}
 
 	currentShort := *shortMA.Value
@@ -335,12 +986,10 @@ func (ae *AlertEngine) evaluateMovingAverageCross(ctx context.Context, alert *en
 	currentState := map[string]interface{}{
 		"short_ma":  currentShort,
 		"long_ma":   currentLong,
-		"timestamp": time.Now(),
+		"timestamp": ae.clock.Now(),
 	}
 
-	ae.stateCacheMutex.Lock()
-	ae.alertStateCache[alert.ID] = currentState
-	ae.stateCacheMutex.Unlock()
+	ae.saveState(ctx, alert, currentState)
 
 	result.CurrentValue = currentShort - currentLong
 
@@ -384,47 +1033,207 @@ func (ae *AlertEngine) evaluateMovingAverageCross(ctx context.Context, alert *en
 	return result, nil
 }
 
+// evaluateMACDCross evaluates crossovers of the MACD line over its signal line,
+// using the standard MACD(12,26,9) kept up to date by the indicator service
+func (ae *AlertEngine) evaluateMACDCross(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	previousState, exists := ae.previousState(ctx, alert)
+
+	indicatorKey := entities.IndicatorKey("MACD", macdFastPeriod, macdSlowPeriod, macdSignalPeriod)
+	macd, err := ae.latestIndicator(ctx, alert, indicatorKey, priceData.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MACD: %w", err)
+	}
+
+	var currentSignal float64
+	signalFound := false
+	if macd != nil {
+		currentSignal, signalFound = macd.Metadata["signal"].(float64)
+	}
+	if macd == nil || macd.Value == nil || !signalFound {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "insufficient MACD data for %s", alert.Symbol)
+	}
+	currentMACD := *macd.Value
+
+	ae.saveState(ctx, alert, map[string]interface{}{
+		"macd":      currentMACD,
+		"signal":    currentSignal,
+		"timestamp": ae.clock.Now(),
+	})
+
+	result.CurrentValue = currentMACD - currentSignal
+
+	if !exists {
+		// First evaluation, no crossover yet
+		result.ShouldTrigger = false
+		result.Message = fmt.Sprintf("Monitoring MACD crossover for %s", alert.Symbol)
+	} else {
+		prevMACD, _ := previousState["macd"].(float64)
+		prevSignal, _ := previousState["signal"].(float64)
+
+		switch AlertCondition(alert.AlertType + "_" + alert.ConditionType) {
+		case ConditionMACDCrossUp:
+			result.ShouldTrigger = prevMACD <= prevSignal && currentMACD > currentSignal
+			if result.ShouldTrigger {
+				result.Message = fmt.Sprintf("MACD crossed above its signal line for %s", alert.Symbol)
+			}
+		case ConditionMACDCrossDown:
+			result.ShouldTrigger = prevMACD >= prevSignal && currentMACD < currentSignal
+			if result.ShouldTrigger {
+				result.Message = fmt.Sprintf("MACD crossed below its signal line for %s", alert.Symbol)
+			}
+		}
+	}
+
+	result.Context["macd"] = currentMACD
+	result.Context["signal"] = currentSignal
+	result.Context["histogram"] = result.CurrentValue
+
+	return result, nil
+}
+
+// evaluateIchimokuCloud evaluates the close entering or leaving the Ichimoku(9,26,52)
+// cloud kept up to date by the indicator service; the first evaluation only records
+// where the close stands
+func (ae *AlertEngine) evaluateIchimokuCloud(ctx context.Context, alert *entities.Alert, priceData *entities.PriceHistory, result *AlertEvaluationResult) (*AlertEvaluationResult, error) {
+	previousState, exists := ae.previousState(ctx, alert)
+
+	ichimoku, err := ae.latestIndicator(ctx, alert, ichimokuIndicatorKey, priceData.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Ichimoku: %w", err)
+	}
+
+	var cloudTop, cloudBottom float64
+	topFound, bottomFound := false, false
+	if ichimoku != nil {
+		cloudTop, topFound = ichimoku.Metadata["cloud_top"].(float64)
+		cloudBottom, bottomFound = ichimoku.Metadata["cloud_bottom"].(float64)
+	}
+	if !topFound || !bottomFound {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "insufficient Ichimoku data for %s", alert.Symbol)
+	}
+
+	position := indicators.CloudPosition(priceData.ClosePrice, cloudTop, cloudBottom)
+	ae.saveState(ctx, alert, map[string]interface{}{
+		"position":  position,
+		"timestamp": ae.clock.Now(),
+	})
+
+	result.CurrentValue = priceData.ClosePrice
+	result.Context["cloud_top"] = cloudTop
+	result.Context["cloud_bottom"] = cloudBottom
+	result.Context["position"] = position
+
+	previous, ok := previousState["position"].(string)
+	if !exists || !ok {
+		result.Message = fmt.Sprintf("Monitoring %s against the Ichimoku cloud (%s)", alert.Symbol, position)
+		return result, nil
+	}
+	result.Context["previous_position"] = previous
+
+	switch AlertCondition(alert.AlertType + "_" + alert.ConditionType) {
+	case ConditionCloudEnters:
+		result.ShouldTrigger = previous != indicators.CloudInside && position == indicators.CloudInside
+	case ConditionCloudExits:
+		result.ShouldTrigger = previous == indicators.CloudInside && position != indicators.CloudInside
+	case ConditionCloudExitsUp:
+		result.ShouldTrigger = previous == indicators.CloudInside && position == indicators.CloudAbove
+	case ConditionCloudExitsDown:
+		result.ShouldTrigger = previous == indicators.CloudInside && position == indicators.CloudBelow
+	}
+
+	if result.ShouldTrigger {
+		result.Message = fmt.Sprintf("Price of %s moved from %s to %s the Ichimoku cloud (%.8f - %.8f)", alert.Symbol, previous, position, cloudBottom, cloudTop)
+	} else {
+		result.Message = fmt.Sprintf("Price of %s is %s the Ichimoku cloud (%.8f - %.8f)", alert.Symbol, position, cloudBottom, cloudTop)
+	}
+
+	return result, nil
+}
+
 // processTriggeredAlert handles the actions when an alert is triggered
 func (ae *AlertEngine) processTriggeredAlert(ctx context.Context, alert *entities.Alert, result *AlertEvaluationResult) error {
 	// Update alert with triggered timestamp
-	now := time.Now()
+	now := ae.clock.Now()
 	alert.TriggeredAt = &now
+	// Acknowledgement-required alerts escalate until the user acknowledges them
+	alert.AwaitAck(now)
+	if alert.AckRequired {
+		result.Context["ack_required"] = true
+	}
+	// A chained alert triggers once per trigger of the alert it depends on
+	if alert.DependsOnID != nil {
+		alert.ArmedAt = nil
+	}
 
+	countDBCall(ctx)
 	if err := ae.alertRepo.Update(ctx, alert); err != nil {
 		return fmt.Errorf("failed to update alert: %w", err)
 	}
+	if armed := ae.armDependents(ctx, alert, now); armed > 0 {
+		result.Context["armed_alerts"] = armed
+	}
+
+	// The trigger event only goes over WebSocket to users connected somewhere; offline
+	// users get it through the queued channels and the notification list instead
+	delivered := ae.webSocketService != nil && ae.webSocketService.IsUserConnected(ctx, alert.UserID)
+	result.Context["websocket_delivered"] = delivered
 
 	// Create notification
+	title := "Alert Triggered"
+	if isRecovered(result) {
+		title = "Alert Triggered During Downtime"
+	}
 	notification := &entities.Notification{
 		ID:               uuid.New(),
 		UserID:           alert.UserID,
 		AlertID:          &alert.ID,
-		Title:            "Alert Triggered",
+		Title:            title,
 		Message:          result.Message,
 		NotificationType: "alert_triggered",
+		Context:          evaluationSnapshot(alert, result, now),
 		CreatedAt:        now,
 	}
 
+	countDBCall(ctx)
 	if err := ae.notificationRepo.Create(ctx, notification); err != nil {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	// Broadcast via WebSocket if service is available
-	if ae.webSocketService != nil {
+	if trace := result.Trace; trace != nil {
+		observeLatency(ae.latencyRecorder, LatencyStageEvaluation, trace.IngestedAt, trace.EvaluatedAt)
+	}
+
+	if delivered && isRecovered(result) {
+		// A trigger missed during downtime is old news: it only shows up in the notification list
+		if err := ae.webSocketService.BroadcastNotificationUpdate(ctx, notification); err != nil {
+			ae.logger.WithError(err).Warn("Failed to broadcast notification update")
+		}
+	} else if delivered {
 		// Broadcast alert triggered event
-		if err := ae.webSocketService.BroadcastAlertTriggered(ctx, alert, result); err != nil {
+		broadcastCtx, span := startSpan(ctx, "alert.broadcast")
+		broadcastStarted := time.Now()
+		err := ae.webSocketService.BroadcastAlertTriggered(broadcastCtx, alert, result)
+		span.End()
+		observeDuration(broadcastCtx, ae.durationRecorder, DurationBroadcast, broadcastStarted)
+		if err != nil {
 			ae.logger.WithError(err).Warn("Failed to broadcast alert triggered event")
+		} else if trace := result.Trace; trace != nil {
+			broadcastAt := ae.clock.Now()
+			observeLatency(ae.latencyRecorder, LatencyStageBroadcast, trace.EvaluatedAt, broadcastAt)
+			observeLatency(ae.latencyRecorder, LatencyStageEndToEnd, trace.EventTime, broadcastAt)
 		}
 
 		// Broadcast notification update
 		if err := ae.webSocketService.BroadcastNotificationUpdate(ctx, notification); err != nil {
 			ae.logger.WithError(err).Warn("Failed to broadcast notification update")
 		}
+	} else if ae.webSocketService != nil {
+		ae.logger.WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"user_id":  alert.UserID,
+		}).Debug("User not connected, alert trigger left to the queued channels")
 	}
 
-	// Set throttle to prevent spam
-	ae.setThrottle(alert.ID, 5*time.Minute) // 5 minute throttle
-
 	ae.logger.WithFields(logrus.Fields{
 		"alert_id":      alert.ID,
 		"user_id":       alert.UserID,
@@ -438,37 +1247,57 @@ func (ae *AlertEngine) processTriggeredAlert(ctx context.Context, alert *entitie
 	return nil
 }
 
-// isThrottled checks if an alert is currently throttled
-func (ae *AlertEngine) isThrottled(alertID uuid.UUID) bool {
-	ae.throttleMutex.RLock()
-	defer ae.throttleMutex.RUnlock()
-
-	throttleTime, exists := ae.throttleMap[alertID]
-	if !exists {
-		return false
+// evaluationSnapshot copies the evaluation context together with the values compared,
+// so the stored notification shows exactly what caused the trigger
+func evaluationSnapshot(alert *entities.Alert, result *AlertEvaluationResult, evaluatedAt time.Time) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(result.Context)+6)
+	for key, value := range result.Context {
+		snapshot[key] = value
 	}
+	snapshot["symbol"] = alert.Symbol
+	snapshot["alert_type"] = alert.AlertType
+	snapshot["condition_type"] = alert.ConditionType
+	snapshot["current_value"] = result.CurrentValue
+	snapshot["target_value"] = result.TargetValue
+	snapshot["evaluated_at"] = evaluatedAt
+	return snapshot
+}
 
-	return time.Now().Before(throttleTime)
+// alertThrottleKey is the throttle store key of an alert
+func alertThrottleKey(alertID uuid.UUID) string {
+	return "alert:" + alertID.String()
 }
 
-// setThrottle sets a throttle period for an alert
-func (ae *AlertEngine) setThrottle(alertID uuid.UUID, duration time.Duration) {
-	ae.throttleMutex.Lock()
-	defer ae.throttleMutex.Unlock()
+// isThrottled checks if an alert is in its cooldown. The trigger time stored with
+// the alert covers restarts, the throttle store covers triggers by other instances
+// that this copy of the alert hasn't seen yet.
+func (ae *AlertEngine) isThrottled(ctx context.Context, alert *entities.Alert) bool {
+	if alert.InCooldown(ae.clock.Now()) {
+		return true
+	}
+	throttled, err := ae.throttles.Active(ctx, alertThrottleKey(alert.ID))
+	if err != nil {
+		ae.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to check alert throttle")
+		return false
+	}
+	return throttled
+}
 
-	ae.throttleMap[alertID] = time.Now().Add(duration)
+// acquireTrigger takes the alert's throttle for its cooldown before it triggers. If the
+// store is unreachable the trigger goes ahead: a duplicate notification beats a missed one.
+func (ae *AlertEngine) acquireTrigger(ctx context.Context, alert *entities.Alert) bool {
+	acquired, err := ae.throttles.Acquire(ctx, alertThrottleKey(alert.ID), alert.Cooldown())
+	if err != nil {
+		ae.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to acquire alert throttle")
+		return true
+	}
+	return acquired
 }
 
-// CleanupThrottles removes expired throttles
+// CleanupThrottles removes expired throttles; stores that expire keys on their own need no cleanup
 func (ae *AlertEngine) CleanupThrottles() {
-	ae.throttleMutex.Lock()
-	defer ae.throttleMutex.Unlock()
-
-	now := time.Now()
-	for alertID, throttleTime := range ae.throttleMap {
-		if now.After(throttleTime) {
-			delete(ae.throttleMap, alertID)
-		}
+	if memory, ok := ae.throttles.(*MemoryThrottleStore); ok {
+		memory.Cleanup()
 	}
 }
 
@@ -479,19 +1308,24 @@ func (ae *AlertEngine) GetAlertStats(ctx context.Context) (map[string]interface{
 		return nil, err
 	}
 
-	ae.throttleMutex.RLock()
-	throttledCount := len(ae.throttleMap)
-	ae.throttleMutex.RUnlock()
+	throttledCount, err := ae.throttles.Count(ctx, "alert:")
+	if err != nil {
+		return nil, err
+	}
 
-	ae.stateCacheMutex.RLock()
-	cachedStatesCount := len(ae.alertStateCache)
-	ae.stateCacheMutex.RUnlock()
+	cachedStatesCount, err := ae.stateStore.Count(ctx, "alert:")
+	if err != nil {
+		return nil, err
+	}
 
 	stats := map[string]interface{}{
 		"total_enabled_alerts": len(alerts),
 		"throttled_alerts":     throttledCount,
 		"cached_alert_states":  cachedStatesCount,
-		"last_update":          time.Now(),
+		"last_update":          ae.clock.Now(),
+	}
+	if ae.sharder != nil {
+		stats["owned_shards"] = ae.sharder.OwnedShards()
 	}
 
 	return stats, nil
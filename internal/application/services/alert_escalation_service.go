@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAlertEscalations is how many times an unacknowledged trigger re-notifies
+	// before the escalation gives up; the alert can still be acknowledged after that
+	MaxAlertEscalations = 12
+
+	// NotificationTypeAlertEscalation tags the re-notifications of unacknowledged triggers
+	NotificationTypeAlertEscalation = "alert_escalation"
+)
+
+// EscalationChannels are added to an alert's own channels one per escalation
+// level, from the least to the most intrusive
+var EscalationChannels = []NotificationChannel{ChannelPush, ChannelTelegram, ChannelEmail, ChannelSMS}
+
+var (
+	// ErrAlertNotFound is returned for unknown alerts and alerts of other users
+	ErrAlertNotFound = entities.NewDomainError(entities.ErrNotFound, "alert not found")
+	// ErrAlertNotAwaitingAck is returned when acknowledging an alert with nothing to acknowledge
+	ErrAlertNotAwaitingAck = entities.NewDomainError(entities.ErrConflict, "alert is not awaiting acknowledgement")
+)
+
+// AlertEscalationService re-notifies the triggers of acknowledgement-required alerts
+// every ack interval, over more channels each time, until the user acknowledges them
+type AlertEscalationService struct {
+	alertRepo           repositories.AlertRepository
+	notificationRepo    repositories.NotificationRepository
+	notificationService *NotificationService
+	webSocketService    AlertWebSocketService
+	logger              *logrus.Logger
+	clock               clock.Clock
+
+	// escalations makes sure each level is sent once however many instances run the job
+	escalations ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewAlertEscalationService creates a new alert escalation service
+func NewAlertEscalationService(
+	alertRepo repositories.AlertRepository,
+	notificationRepo repositories.NotificationRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *AlertEscalationService {
+	return &AlertEscalationService{
+		alertRepo:           alertRepo,
+		notificationRepo:    notificationRepo,
+		notificationService: notificationService,
+		logger:              logger,
+		clock:               clock.New(),
+		escalations:         NewMemoryThrottleStore(),
+	}
+}
+
+// SetClock replaces the clock escalations are scheduled and acknowledgements stamped with
+func (es *AlertEscalationService) SetClock(c clock.Clock) {
+	es.clock = c
+	if memory, ok := es.escalations.(*MemoryThrottleStore); ok {
+		memory.SetClock(c)
+	}
+}
+
+// SetThrottleStore replaces the in-process deduplication of escalations, e.g. with
+// a Redis store so users don't get one re-notification per instance
+func (es *AlertEscalationService) SetThrottleStore(store ThrottleStore) {
+	es.escalations = store
+}
+
+// SetWebSocketService enables pushing escalation notifications to connected users
+func (es *AlertEscalationService) SetWebSocketService(webSocketService AlertWebSocketService) {
+	es.webSocketService = webSocketService
+}
+
+// Start escalates once and then every interval until Stop is called
+func (es *AlertEscalationService) Start(ctx context.Context, interval time.Duration) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	if es.isRunning {
+		es.logger.Warn("Alert escalation is already running")
+		return
+	}
+	es.isRunning = true
+	es.stopChan = make(chan struct{})
+	es.logger.Info("Starting alert escalation")
+
+	es.wg.Add(1)
+	go func() {
+		defer es.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := es.EscalateDue(ctx); err != nil {
+				es.logger.WithError(err).Error("Failed to escalate unacknowledged alerts")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-es.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the escalation and waits for the current run to finish
+func (es *AlertEscalationService) Stop() {
+	es.mutex.Lock()
+	if !es.isRunning {
+		es.mutex.Unlock()
+		return
+	}
+	es.isRunning = false
+	close(es.stopChan)
+	es.mutex.Unlock()
+
+	es.wg.Wait()
+	es.logger.Info("Alert escalation stopped")
+}
+
+// EscalateDue re-notifies every unacknowledged alert whose interval elapsed and
+// returns how many it re-notified
+func (es *AlertEscalationService) EscalateDue(ctx context.Context) (int, error) {
+	alerts, err := es.alertRepo.GetAwaitingAck(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get alerts awaiting acknowledgement: %w", err)
+	}
+
+	now := es.clock.Now()
+	escalated := 0
+	for i := range alerts {
+		alert := &alerts[i]
+		if alert.EscalationLevel >= MaxAlertEscalations || !alert.EscalationDue(now) {
+			continue
+		}
+		if err := es.escalate(ctx, alert, now); err != nil {
+			es.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to escalate alert")
+			continue
+		}
+		escalated++
+	}
+	return escalated, nil
+}
+
+// escalate sends the next level of re-notification of one alert
+func (es *AlertEscalationService) escalate(ctx context.Context, alert *entities.Alert, now time.Time) error {
+	level := alert.EscalationLevel + 1
+
+	// Another instance may have sent this level since the alerts were read
+	key := "escalation:" + alert.ID.String() + ":" + strconv.Itoa(level)
+	acquired, err := es.escalations.Acquire(ctx, key, alert.AckInterval())
+	if err != nil {
+		es.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to acquire alert escalation")
+	} else if !acquired {
+		return nil
+	}
+
+	alert.EscalationLevel = level
+	alert.LastEscalatedAt = &now
+	if err := es.alertRepo.Update(ctx, alert); err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	channels := AlertEscalationChannels(alert, level)
+	waiting := now.Sub(*alert.AwaitingAckSince).Round(time.Minute)
+	title := "Alert awaiting acknowledgement"
+	message := fmt.Sprintf("Your %s alert on %s triggered %s ago and is still waiting for acknowledgement",
+		alert.AlertType, alert.Symbol, waiting)
+	data := map[string]interface{}{
+		"alert_id":           alert.ID,
+		"symbol":             alert.Symbol,
+		"alert_type":         alert.AlertType,
+		"condition":          alert.ConditionType,
+		"target_value":       alert.TargetValue,
+		"escalation_level":   level,
+		"awaiting_ack_since": *alert.AwaitingAckSince,
+		"channels":           channels,
+	}
+
+	notification := &entities.Notification{
+		ID:               uuid.New(),
+		UserID:           alert.UserID,
+		AlertID:          &alert.ID,
+		Title:            title,
+		Message:          message,
+		NotificationType: NotificationTypeAlertEscalation,
+		Context:          data,
+		CreatedAt:        now,
+	}
+	if err := es.notificationRepo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	if es.webSocketService != nil && es.webSocketService.IsUserConnected(ctx, alert.UserID) {
+		if err := es.webSocketService.BroadcastNotificationUpdate(ctx, notification); err != nil {
+			es.logger.WithError(err).Warn("Failed to broadcast alert escalation")
+		}
+	}
+
+	external := make([]NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		if channel != ChannelInApp {
+			external = append(external, channel)
+		}
+	}
+	if len(external) > 0 {
+		if err := es.notificationService.QueueNotification(ctx, &QueuedNotification{
+			UserID:   alert.UserID,
+			Type:     NotificationTypeAlertEscalation,
+			Title:    title,
+			Message:  message,
+			Channels: external,
+			Priority: PriorityUrgent,
+			Data:     data,
+		}); err != nil {
+			return fmt.Errorf("failed to queue notification: %w", err)
+		}
+	}
+
+	es.logger.WithFields(logrus.Fields{
+		"alert_id":         alert.ID,
+		"user_id":          alert.UserID,
+		"escalation_level": level,
+		"channels":         channels,
+	}).Info("Unacknowledged alert escalated")
+
+	return nil
+}
+
+// Acknowledge stops the escalation of the user's alert
+func (es *AlertEscalationService) Acknowledge(ctx context.Context, userID, alertID uuid.UUID) (*entities.Alert, error) {
+	alert, err := es.alertRepo.GetByID(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+	if alert.UserID != userID {
+		return nil, ErrAlertNotFound
+	}
+	if !alert.AwaitingAck() {
+		return nil, ErrAlertNotAwaitingAck
+	}
+
+	alert.Acknowledge(es.clock.Now())
+	if err := es.alertRepo.Update(ctx, alert); err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	es.logger.WithFields(logrus.Fields{
+		"alert_id":         alert.ID,
+		"user_id":          userID,
+		"escalation_level": alert.EscalationLevel,
+	}).Info("Alert acknowledged")
+
+	return alert, nil
+}
+
+// AlertEscalationChannels are the channels of an escalation level: the alert's own
+// channels plus the first level EscalationChannels
+func AlertEscalationChannels(alert *entities.Alert, level int) []NotificationChannel {
+	channels := make([]NotificationChannel, 0, len(alert.NotifyVia)+len(EscalationChannels))
+	seen := make(map[NotificationChannel]bool)
+	add := func(channel NotificationChannel) {
+		if !seen[channel] {
+			seen[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+
+	for _, name := range alert.NotifyVia {
+		switch channel := NotificationChannel(name); channel {
+		case ChannelInApp, ChannelEmail, ChannelPush, ChannelSMS, ChannelTelegram:
+			add(channel)
+		}
+	}
+	if level > len(EscalationChannels) {
+		level = len(EscalationChannels)
+	}
+	for _, channel := range EscalationChannels[:level] {
+		add(channel)
+	}
+	return channels
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+)
+
+// Market data an evaluation can be skipped for, reported to the StaleDataRecorder
+const (
+	StaleSourcePrice     = "price"
+	StaleSourceIndicator = "indicator"
+	StaleSourceTicker    = "ticker_24h"
+)
+
+// StaleDataRecorder counts the evaluations skipped because their market data was too
+// old, usually to feed a counter labelled by source
+type StaleDataRecorder interface {
+	ObserveStaleData(source string)
+}
+
+// StaleDataRecorderFunc adapts a function to StaleDataRecorder
+type StaleDataRecorderFunc func(source string)
+
+// ObserveStaleData calls f(source)
+func (f StaleDataRecorderFunc) ObserveStaleData(source string) {
+	f(source)
+}
+
+// dataAge is how long ago the candle of timeframe opened at timestamp closed; data of
+// the candle still open is current
+func dataAge(timestamp time.Time, timeframe string, now time.Time) time.Duration {
+	closedAt := timestamp.Add(time.Duration(indicators.GetTimeframeMilliseconds(timeframe)) * time.Millisecond)
+	if now.Before(closedAt) {
+		return 0
+	}
+	return now.Sub(closedAt)
+}
+
+// checkFreshness fails with ErrStaleData when the alert's market data stamped with
+// timestamp is older than the alert allows, so the evaluation is skipped rather than
+// triggering on prices the market has moved away from
+func (ae *AlertEngine) checkFreshness(alert *entities.Alert, source string, timestamp time.Time) error {
+	return ae.checkAge(alert, source, dataAge(timestamp, alert.Timeframe, ae.clock.Now()))
+}
+
+// checkAge fails with ErrStaleData when the alert's market data is age old, more
+// than the alert allows
+func (ae *AlertEngine) checkAge(alert *entities.Alert, source string, age time.Duration) error {
+	maxAge := alert.MaxDataAge(ae.maxDataAge)
+	if maxAge <= 0 {
+		return nil
+	}
+	if age <= maxAge {
+		return nil
+	}
+
+	if ae.staleDataRecorder != nil {
+		ae.staleDataRecorder.ObserveStaleData(source)
+	}
+	return entities.NewDomainError(entities.ErrStaleData, "latest %s data for %s %s is %s old, more than the %s allowed",
+		source, alert.Symbol, alert.Timeframe, age.Round(time.Second), maxAge)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertLevel is a price a chart draws as a horizontal line for one of the user's alerts
+type AlertLevel struct {
+	AlertID       uuid.UUID `json:"alert_id"`
+	ConditionType string    `json:"condition_type"`
+	TargetValue   float64   `json:"target_value"`
+	Timeframe     string    `json:"timeframe"`
+	Priority      string    `json:"priority"`
+}
+
+// ActiveAlertLevels picks the enabled price alerts on a symbol; other alert types
+// target indicator values or percentages, which have no place on a price chart
+func ActiveAlertLevels(alerts []entities.Alert, symbol string) []AlertLevel {
+	levels := make([]AlertLevel, 0)
+	for _, alert := range alerts {
+		if !alert.Enabled || alert.AlertType != "price" || !strings.EqualFold(alert.Symbol, symbol) {
+			continue
+		}
+		levels = append(levels, AlertLevel{
+			AlertID:       alert.ID,
+			ConditionType: alert.ConditionType,
+			TargetValue:   alert.TargetValue,
+			Timeframe:     alert.Timeframe,
+			Priority:      alert.Priority,
+		})
+	}
+	return levels
+}
+
+// AlertLevelService serves the alert lines of a symbol to chart clients: they are
+// included when a client subscribes to the symbol and pushed again whenever one of
+// the user's alerts on it changes
+type AlertLevelService struct {
+	alertRepo repositories.AlertRepository
+	wsService AlertWebSocketService
+	logger    *logrus.Logger
+}
+
+// NewAlertLevelService creates a new alert level service
+func NewAlertLevelService(
+	alertRepo repositories.AlertRepository,
+	wsService AlertWebSocketService,
+	logger *logrus.Logger,
+) *AlertLevelService {
+	return &AlertLevelService{
+		alertRepo: alertRepo,
+		wsService: wsService,
+		logger:    logger,
+	}
+}
+
+// GetAlertLevels returns the user's active alert levels on a symbol
+func (als *AlertLevelService) GetAlertLevels(ctx context.Context, userID uuid.UUID, symbol string) ([]AlertLevel, error) {
+	alerts, err := userAlerts(ctx, als.alertRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ActiveAlertLevels(alerts, symbol), nil
+}
+
+// userAlerts loads every alert of a user, page by page
+func userAlerts(ctx context.Context, alertRepo repositories.AlertRepository, userID uuid.UUID) ([]entities.Alert, error) {
+	alerts := make([]entities.Alert, 0)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := alertRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load alerts: %w", err)
+		}
+		alerts = append(alerts, page...)
+		if len(page) < exportPageSize {
+			return alerts, nil
+		}
+	}
+}
+
+// PublishAlertLevels pushes the user's current levels on a symbol to their connected
+// clients; failures are logged since the alert change itself already succeeded
+func (als *AlertLevelService) PublishAlertLevels(ctx context.Context, userID uuid.UUID, symbol string) {
+	levels, err := als.GetAlertLevels(ctx, userID, symbol)
+	if err != nil {
+		als.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id": userID,
+			"symbol":  symbol,
+		}).Warn("Failed to load alert levels")
+		return
+	}
+
+	if err := als.wsService.BroadcastAlertLevels(ctx, userID, symbol, levels); err != nil {
+		als.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id": userID,
+			"symbol":  symbol,
+		}).Warn("Failed to broadcast alert levels")
+	}
+}
@@ -27,6 +27,7 @@ type AlertMonitor struct {
 	// Configuration
 	evaluationInterval time.Duration
 	cleanupInterval    time.Duration
+	recoveryWindow     time.Duration
 }
 
 // NewAlertMonitor creates a new alert monitor
@@ -46,6 +47,7 @@ func NewAlertMonitor(
 		stopChan:            make(chan struct{}),
 		evaluationInterval:  30 * time.Second, // Evaluate alerts every 30 seconds
 		cleanupInterval:     5 * time.Minute,  // Cleanup throttles every 5 minutes
+		recoveryWindow:      DefaultRecoveryWindow,
 	}
 }
 
@@ -102,6 +104,9 @@ func (am *AlertMonitor) evaluationWorker(ctx context.Context) {
 
 	am.logger.Info("Alert evaluation worker started")
 
+	// Catch up on the triggers missed while the service was down before the first tick
+	am.performRecovery(ctx)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -173,11 +178,13 @@ func (am *AlertMonitor) performEvaluation(ctx context.Context) {
 					channels = append(channels, ChannelPush)
 				case "sms":
 					channels = append(channels, ChannelSMS)
+				case "telegram":
+					channels = append(channels, ChannelTelegram)
 				}
 			}
 
 			// Queue the notification
-			err = am.notificationService.QueueAlertNotification(ctx, alert, result.CurrentValue, channels)
+			err = am.notificationService.QueueAlertNotification(WithAlertTrace(ctx, result.Trace), alert, result.CurrentValue, channels)
 			if err != nil {
 				am.logger.WithError(err).WithField("alert_id", result.AlertID).Error("Failed to queue alert notification")
 			}
@@ -192,6 +199,24 @@ func (am *AlertMonitor) performEvaluation(ctx context.Context) {
 	}).Debug("Alert evaluation completed")
 }
 
+// performRecovery replays the evaluations missed during downtime. Recovered triggers
+// are kept quiet: they land in the notification list but aren't queued to the external
+// channels like fresh ones.
+func (am *AlertMonitor) performRecovery(ctx context.Context) {
+	if am.alertEngine == nil {
+		return
+	}
+
+	results, err := am.alertEngine.RecoverMissedEvaluations(ctx, am.recoveryWindow)
+	if err != nil {
+		am.logger.WithError(err).Error("Failed to recover missed alert evaluations")
+		return
+	}
+	if len(results) > 0 {
+		am.logger.WithField("recovered_count", len(results)).Info("Alerts triggered during downtime recovered")
+	}
+}
+
 // performCleanup cleans up throttles and old data
 func (am *AlertMonitor) performCleanup(ctx context.Context) {
 	// Cleanup alert throttles
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRecoveryWindow bounds how far back startup recovery replays candles; triggers
+// older than that are no longer worth telling users about
+const DefaultRecoveryWindow = 6 * time.Hour
+
+// lastEvaluationKey is the state store key of the time alerts were last evaluated.
+// With a sharder each shard has its own, suffixed with the shard number.
+const lastEvaluationKey = "engine:last_evaluation"
+
+// recoverableConditions can be evaluated against a past candle alone. The others read
+// the latest indicators or price history, so replaying them would evaluate the present.
+var recoverableConditions = map[AlertCondition]bool{
+	ConditionPriceAbove:     true,
+	ConditionPriceBelow:     true,
+	ConditionPriceCrossUp:   true,
+	ConditionPriceCrossDown: true,
+	ConditionVolumeAbove:    true,
+	ConditionVolumeBelow:    true,
+}
+
+// isRecovered reports whether a result comes from replaying a candle missed during downtime
+func isRecovered(result *AlertEvaluationResult) bool {
+	recovered, _ := result.Context["recovered"].(bool)
+	return recovered
+}
+
+// evaluationKey returns the state key recording when the alert was last evaluated
+func (ae *AlertEngine) evaluationKey(alertID uuid.UUID) string {
+	if ae.sharder == nil {
+		return lastEvaluationKey
+	}
+	return shardEvaluationKey(ae.sharder.ShardOf(alertID))
+}
+
+// shardEvaluationKey returns the state key recording when a shard was last evaluated
+func shardEvaluationKey(shard int) string {
+	return fmt.Sprintf("%s:%d", lastEvaluationKey, shard)
+}
+
+// markEvaluated records when the alerts of this instance were last evaluated, so the
+// next start knows how long the service was down. A sharded instance only marks
+// the shards it holds.
+func (ae *AlertEngine) markEvaluated(ctx context.Context, at time.Time) {
+	keys := []string{lastEvaluationKey}
+	if ae.sharder != nil {
+		keys = keys[:0]
+		for _, shard := range ae.sharder.HeldShards() {
+			keys = append(keys, shardEvaluationKey(shard))
+		}
+	}
+
+	state := map[string]interface{}{"evaluated_at": at.UTC().Format(time.RFC3339Nano)}
+	for _, key := range keys {
+		if err := ae.stateStore.Save(ctx, key, state, 0); err != nil {
+			ae.logger.WithError(err).WithField("key", key).Warn("Failed to save last alert evaluation time")
+		}
+	}
+}
+
+// lastEvaluation returns when the alerts under key were last evaluated, by any instance
+func (ae *AlertEngine) lastEvaluation(ctx context.Context, key string) (time.Time, bool) {
+	state, found, err := ae.stateStore.Load(ctx, key)
+	if err != nil {
+		ae.logger.WithError(err).Warn("Failed to load last alert evaluation time")
+		return time.Time{}, false
+	}
+	if !found {
+		return time.Time{}, false
+	}
+	value, _ := state["evaluated_at"].(string)
+	evaluatedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return evaluatedAt, true
+}
+
+// RecoverMissedEvaluations replays the candles closed since alerts were last evaluated,
+// at most window back, and triggers the alerts that would have fired in the meantime.
+// Recovered triggers carry "recovered" in their context and only land in the
+// notification list, without the live trigger event. The latest candle is left to the
+// regular evaluation. Nothing is replayed on the very first start, or for a shard
+// never evaluated before.
+func (ae *AlertEngine) RecoverMissedEvaluations(ctx context.Context, window time.Duration) ([]AlertEvaluationResult, error) {
+	// Alerts sharing an evaluation key share its last evaluation time
+	type lastEvaluated struct {
+		at    time.Time
+		found bool
+	}
+	evaluations := make(map[string]lastEvaluated)
+	if ae.sharder == nil {
+		at, found := ae.lastEvaluation(ctx, lastEvaluationKey)
+		if !found {
+			return nil, nil
+		}
+		evaluations[lastEvaluationKey] = lastEvaluated{at: at, found: true}
+	}
+
+	now := ae.clock.Now()
+	earliest := now.Add(-window)
+
+	alerts, err := ae.alertRepo.GetEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled alerts: %w", err)
+	}
+	alerts = ae.ownedAlerts(ctx, alerts)
+
+	var recovered []AlertEvaluationResult
+	for i := range alerts {
+		alert := &alerts[i]
+		if !recoverableConditions[AlertCondition(alert.AlertType+"_"+alert.ConditionType)] {
+			continue
+		}
+
+		key := ae.evaluationKey(alert.ID)
+		evaluation, loaded := evaluations[key]
+		if !loaded {
+			evaluation.at, evaluation.found = ae.lastEvaluation(ctx, key)
+			evaluations[key] = evaluation
+		}
+		if !evaluation.found {
+			continue
+		}
+		since := evaluation.at
+		if since.Before(earliest) {
+			since = earliest
+		}
+
+		result, err := ae.recoverAlert(ctx, alert, since, now)
+		if err != nil {
+			ae.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to recover missed alert evaluations")
+			continue
+		}
+		if result != nil && result.ShouldTrigger {
+			recovered = append(recovered, *result)
+		}
+	}
+
+	ae.markEvaluated(ctx, now)
+
+	ae.logger.WithFields(logrus.Fields{
+		"window":    window,
+		"alerts":    len(alerts),
+		"recovered": len(recovered),
+	}).Info("Recovered missed alert evaluations")
+
+	return recovered, nil
+}
+
+// recoverAlert replays the alert over the candles closed after since and triggers it
+// on the first one that meets its condition
+func (ae *AlertEngine) recoverAlert(ctx context.Context, alert *entities.Alert, since, now time.Time) (*AlertEvaluationResult, error) {
+	if !alert.Armed() || ae.isThrottled(ctx, alert) {
+		return nil, nil
+	}
+	if err := alert.Validate(); err != nil {
+		return nil, err
+	}
+
+	candles, err := ae.priceHistoryRepo.GetByTimeRange(ctx, alert.Symbol, alert.Timeframe, since, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history for %s: %w", alert.Symbol, err)
+	}
+
+	for i := 0; i < len(candles)-1; i++ {
+		candle := &candles[i]
+		if !candle.Timestamp.After(since) {
+			continue
+		}
+
+		result, err := ae.evaluateCondition(ctx, alert, candle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate condition: %w", err)
+		}
+		if !result.ShouldTrigger {
+			continue
+		}
+
+		result.Context["recovered"] = true
+		result.Context["candle_time"] = candle.Timestamp
+		result.Message = fmt.Sprintf("While the service was down, at %s: %s",
+			candle.Timestamp.UTC().Format(time.RFC3339), result.Message)
+		return ae.settleEvaluation(ctx, alert, result), nil
+	}
+	return nil, nil
+}
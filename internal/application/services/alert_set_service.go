@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlertSetNotFound is returned for unknown or foreign alert sets
+	ErrAlertSetNotFound = entities.NewDomainError(entities.ErrNotFound, "alert set not found")
+	// ErrAlertSetNameTaken is returned when the user already has a set with the name
+	ErrAlertSetNameTaken = entities.NewDomainError(entities.ErrConflict, "an alert set with this name already exists")
+)
+
+// AlertSetStats summarizes the alerts of a set
+type AlertSetStats struct {
+	Total     int      `json:"total"`
+	Enabled   int      `json:"enabled"`
+	Triggered int      `json:"triggered"`
+	Symbols   []string `json:"symbols"`
+}
+
+// AlertSetSummary is a set together with the stats of its alerts
+type AlertSetSummary struct {
+	entities.AlertSet
+	Stats AlertSetStats `json:"stats"`
+}
+
+// AlertSetDetails is a set with its stats and alerts
+type AlertSetDetails struct {
+	AlertSetSummary
+	Alerts []entities.Alert `json:"alerts"`
+}
+
+// AlertSetToggleResult counts the alerts a set enable or disable changed; alerts
+// on blacklisted symbols stay disabled and are counted as skipped
+type AlertSetToggleResult struct {
+	Changed int `json:"changed"`
+	Skipped int `json:"skipped"`
+}
+
+// AlertSetUpdate holds the set fields to change; nil fields are left as they are
+type AlertSetUpdate struct {
+	Name        *string
+	Description *string
+}
+
+// AlertSetService manages named groups of a user's alerts, such as the alerts of
+// one trading plan, which are enabled, disabled and deleted together
+type AlertSetService struct {
+	setRepo      repositories.AlertSetRepository
+	alertRepo    repositories.AlertRepository
+	restrictions *SymbolRestrictionService
+	alertLevels  *AlertLevelService
+	logger       *logrus.Logger
+}
+
+// NewAlertSetService creates a new alert set service
+func NewAlertSetService(
+	setRepo repositories.AlertSetRepository,
+	alertRepo repositories.AlertRepository,
+	logger *logrus.Logger,
+) *AlertSetService {
+	return &AlertSetService{
+		setRepo:   setRepo,
+		alertRepo: alertRepo,
+		logger:    logger,
+	}
+}
+
+// SetSymbolRestrictionService keeps alerts on blacklisted symbols disabled when
+// their set is enabled
+func (s *AlertSetService) SetSymbolRestrictionService(restrictions *SymbolRestrictionService) {
+	s.restrictions = restrictions
+}
+
+// SetAlertLevelService enables pushing updated alert lines to chart clients when a
+// set's alerts are enabled, disabled or deleted
+func (s *AlertSetService) SetAlertLevelService(alertLevels *AlertLevelService) {
+	s.alertLevels = alertLevels
+}
+
+// CreateSet creates an empty set for the user
+func (s *AlertSetService) CreateSet(ctx context.Context, userID uuid.UUID, name, description string) (*entities.AlertSet, error) {
+	set := &entities.AlertSet{
+		UserID:      userID,
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+	}
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameFree(ctx, set); err != nil {
+		return nil, err
+	}
+
+	if err := s.setRepo.Create(ctx, set); err != nil {
+		return nil, fmt.Errorf("failed to create alert set: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"alert_set_id": set.ID,
+	}).Info("Alert set created")
+	return set, nil
+}
+
+// ListSets returns the user's sets ordered by name, with the stats of their alerts
+func (s *AlertSetService) ListSets(ctx context.Context, userID uuid.UUID) ([]AlertSetSummary, error) {
+	sets, err := s.setRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert sets: %w", err)
+	}
+	alerts, err := s.alertRepo.GetByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	bySet := make(map[uuid.UUID][]entities.Alert)
+	for _, alert := range alerts {
+		if alert.SetID != nil {
+			bySet[*alert.SetID] = append(bySet[*alert.SetID], alert)
+		}
+	}
+
+	summaries := make([]AlertSetSummary, len(sets))
+	for i, set := range sets {
+		summaries[i] = AlertSetSummary{AlertSet: set, Stats: alertSetStats(bySet[set.ID])}
+	}
+	return summaries, nil
+}
+
+// GetSet returns one of the user's sets with its stats and alerts
+func (s *AlertSetService) GetSet(ctx context.Context, userID, id uuid.UUID) (*AlertSetDetails, error) {
+	set, err := s.CheckSet(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := s.setAlerts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlertSetDetails{
+		AlertSetSummary: AlertSetSummary{AlertSet: *set, Stats: alertSetStats(alerts)},
+		Alerts:          alerts,
+	}, nil
+}
+
+// UpdateSet renames or redescribes one of the user's sets
+func (s *AlertSetService) UpdateSet(ctx context.Context, userID, id uuid.UUID, update AlertSetUpdate) (*entities.AlertSet, error) {
+	set, err := s.CheckSet(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		renamed = name != set.Name
+		set.Name = name
+	}
+	if update.Description != nil {
+		set.Description = strings.TrimSpace(*update.Description)
+	}
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+	if renamed {
+		if err := s.checkNameFree(ctx, set); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.setRepo.Update(ctx, set); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertSetNotFound
+		}
+		return nil, fmt.Errorf("failed to update alert set: %w", err)
+	}
+	return set, nil
+}
+
+// SetEnabled enables or disables every alert of one of the user's sets. Alerts on
+// blacklisted symbols can't be enabled and are skipped rather than failing the set.
+func (s *AlertSetService) SetEnabled(ctx context.Context, userID, id uuid.UUID, enabled bool) (*AlertSetToggleResult, error) {
+	if _, err := s.CheckSet(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	alerts, err := s.setAlerts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AlertSetToggleResult{}
+	var changed []entities.Alert
+	for i := range alerts {
+		alert := &alerts[i]
+		if alert.Enabled == enabled {
+			continue
+		}
+		if enabled && s.restrictions != nil {
+			if err := s.restrictions.CheckEnableAlert(ctx, alert.Symbol); err != nil {
+				result.Skipped++
+				continue
+			}
+		}
+
+		alert.Enabled = enabled
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return result, fmt.Errorf("failed to update alert %s: %w", alert.ID, err)
+		}
+		result.Changed++
+		changed = append(changed, *alert)
+	}
+	s.publishAlertLevels(ctx, userID, changed)
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"alert_set_id": id,
+		"enabled":      enabled,
+		"changed":      result.Changed,
+		"skipped":      result.Skipped,
+	}).Info("Alert set toggled")
+	return result, nil
+}
+
+// DeleteSet deletes one of the user's sets together with its alerts, or only takes
+// the alerts out of the set when keepAlerts is set. It returns how many alerts were
+// deleted or kept.
+func (s *AlertSetService) DeleteSet(ctx context.Context, userID, id uuid.UUID, keepAlerts bool) (int, error) {
+	if _, err := s.CheckSet(ctx, userID, id); err != nil {
+		return 0, err
+	}
+	alerts, err := s.setAlerts(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// The alerts go first, so a failure leaves the set in place to retry with
+	for i := range alerts {
+		alert := &alerts[i]
+		if keepAlerts {
+			alert.SetID = nil
+			err = s.alertRepo.Update(ctx, alert)
+		} else {
+			err = s.alertRepo.Delete(ctx, alert.ID)
+		}
+		if err != nil {
+			return i, fmt.Errorf("failed to release alert %s: %w", alert.ID, err)
+		}
+	}
+
+	if err := s.setRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return len(alerts), ErrAlertSetNotFound
+		}
+		return len(alerts), fmt.Errorf("failed to delete alert set: %w", err)
+	}
+	if !keepAlerts {
+		s.publishAlertLevels(ctx, userID, alerts)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"alert_set_id": id,
+		"alerts":       len(alerts),
+		"kept":         keepAlerts,
+	}).Info("Alert set deleted")
+	return len(alerts), nil
+}
+
+// CheckSet returns the set when it belongs to the user, so alerts can be put in
+// it; other users' sets are reported as not found
+func (s *AlertSetService) CheckSet(ctx context.Context, userID, id uuid.UUID) (*entities.AlertSet, error) {
+	set, err := s.setRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertSetNotFound
+		}
+		return nil, fmt.Errorf("failed to get alert set: %w", err)
+	}
+	if set.UserID != userID {
+		return nil, ErrAlertSetNotFound
+	}
+	return set, nil
+}
+
+// checkNameFree returns ErrAlertSetNameTaken when another of the user's sets has
+// the set's name, ignoring case
+func (s *AlertSetService) checkNameFree(ctx context.Context, set *entities.AlertSet) error {
+	sets, err := s.setRepo.GetByUserID(ctx, set.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get alert sets: %w", err)
+	}
+	for _, other := range sets {
+		if other.ID != set.ID && strings.EqualFold(other.Name, set.Name) {
+			return ErrAlertSetNameTaken
+		}
+	}
+	return nil
+}
+
+func (s *AlertSetService) setAlerts(ctx context.Context, id uuid.UUID) ([]entities.Alert, error) {
+	alerts, err := s.alertRepo.GetBySetID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts of alert set: %w", err)
+	}
+	return alerts, nil
+}
+
+// publishAlertLevels pushes the user's alert lines once per symbol among alerts
+func (s *AlertSetService) publishAlertLevels(ctx context.Context, userID uuid.UUID, alerts []entities.Alert) {
+	if s.alertLevels == nil {
+		return
+	}
+	published := make(map[string]bool)
+	for _, alert := range alerts {
+		if !published[alert.Symbol] {
+			published[alert.Symbol] = true
+			s.alertLevels.PublishAlertLevels(ctx, userID, alert.Symbol)
+		}
+	}
+}
+
+// alertSetStats counts the enabled and triggered alerts and lists their symbols
+func alertSetStats(alerts []entities.Alert) AlertSetStats {
+	stats := AlertSetStats{Total: len(alerts), Symbols: []string{}}
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if alert.Enabled {
+			stats.Enabled++
+		}
+		if alert.TriggeredAt != nil {
+			stats.Triggered++
+		}
+		if !seen[alert.Symbol] {
+			seen[alert.Symbol] = true
+			stats.Symbols = append(stats.Symbols, alert.Symbol)
+		}
+	}
+	sort.Strings(stats.Symbols)
+	return stats
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Shard handoff directions reported to the ShardRecorder
+	ShardAcquired = "acquired"
+	ShardReleased = "released"
+	ShardLost     = "lost"
+)
+
+// claimShardScript takes the lease when it is free and renews it when the caller holds it
+var claimShardScript = `
+local holder = redis.call("GET", KEYS[1])
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if not holder then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`
+
+// releaseShardScript drops the lease only when the caller holds it
+var releaseShardScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`
+
+// ShardRecorder reports shard ownership and handoffs, e.g. to Prometheus
+type ShardRecorder interface {
+	SetOwnedShards(owned int)
+	ObserveHandoff(direction string)
+}
+
+// redisShardClient is the subset of the Redis client used by AlertSharder
+type redisShardClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+	ZAdd(ctx context.Context, key string, members ...redis.Z) *redis.IntCmd
+	ZRemRangeByScore(ctx context.Context, key, min, max string) *redis.IntCmd
+	ZCard(ctx context.Context, key string) *redis.IntCmd
+	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+}
+
+// AlertSharder splits alert evaluation between the replicas running the alert monitor.
+// Alerts hash into a fixed number of shards and each replica holds Redis leases on
+// its fair share of them, so every alert is evaluated by exactly one replica per
+// cycle. Leases of a replica that stops renewing them expire and are picked up by
+// the others.
+type AlertSharder struct {
+	client   redisShardClient
+	owner    string
+	shards   int
+	leaseTTL time.Duration
+	recorder ShardRecorder
+	logger   *logrus.Logger
+	clock    clock.Clock
+
+	owned map[int]bool
+	// validUntil is when the leases renewed by the last successful rebalance expire
+	validUntil time.Time
+	mutex      sync.RWMutex
+}
+
+// NewAlertSharder creates a sharder for this replica, identified by owner. Leases
+// last leaseTTL, which must cover several evaluation cycles.
+func NewAlertSharder(client redisShardClient, owner string, shards int, leaseTTL time.Duration, logger *logrus.Logger) *AlertSharder {
+	return &AlertSharder{
+		client:   client,
+		owner:    owner,
+		shards:   shards,
+		leaseTTL: leaseTTL,
+		logger:   logger,
+		clock:    clock.New(),
+		owned:    make(map[int]bool),
+	}
+}
+
+// SetClock replaces the clock replica heartbeats are stamped with
+func (s *AlertSharder) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetRecorder reports shard ownership and handoffs
+func (s *AlertSharder) SetRecorder(recorder ShardRecorder) {
+	s.recorder = recorder
+}
+
+// AlertShard returns the shard of an alert out of shards
+func AlertShard(alertID uuid.UUID, shards int) int {
+	hash := fnv.New32a()
+	hash.Write(alertID[:])
+	return int(hash.Sum32() % uint32(shards))
+}
+
+// Owns reports whether this replica evaluates the alert. Once the leases may have
+// expired without being renewed it owns nothing, as another replica may hold them.
+func (s *AlertSharder) Owns(alertID uuid.UUID) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.clock.Now().Before(s.validUntil) && s.owned[AlertShard(alertID, s.shards)]
+}
+
+// OwnedShards returns how many shards this replica holds
+func (s *AlertSharder) OwnedShards() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.owned)
+}
+
+// HeldShards returns the shards this replica evaluates, in order; none once its
+// leases may have expired, as for Owns
+func (s *AlertSharder) HeldShards() []int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if !s.clock.Now().Before(s.validUntil) {
+		return nil
+	}
+	held := make([]int, 0, len(s.owned))
+	for shard := range s.owned {
+		held = append(held, shard)
+	}
+	sort.Ints(held)
+	return held
+}
+
+// ShardOf returns the shard of an alert
+func (s *AlertSharder) ShardOf(alertID uuid.UUID) int {
+	return AlertShard(alertID, s.shards)
+}
+
+// Rebalance renews this replica's leases and claims or releases shards to hold its
+// fair share among the live replicas. It runs before every evaluation cycle; on a
+// Redis failure the replica keeps its current shards until their leases expire.
+func (s *AlertSharder) Rebalance(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Leases renewed from now on last at least until then
+	validUntil := s.clock.Now().Add(s.leaseTTL)
+
+	replicas, err := s.heartbeat(ctx)
+	if err != nil {
+		return err
+	}
+	fairShare := (s.shards + replicas - 1) / replicas
+
+	// Renew the shards held, in order, dropping the ones above the fair share
+	for shard := 0; shard < s.shards; shard++ {
+		if !s.owned[shard] {
+			continue
+		}
+		if len(s.owned) > fairShare {
+			if err := s.release(ctx, shard); err != nil {
+				return err
+			}
+			delete(s.owned, shard)
+			s.handoff(shard, ShardReleased)
+			continue
+		}
+		held, err := s.claim(ctx, shard)
+		if err != nil {
+			return err
+		}
+		if !held {
+			delete(s.owned, shard)
+			s.handoff(shard, ShardLost)
+		}
+	}
+
+	// Claim free shards up to the fair share, starting from a point of the ring
+	// that depends on the owner so replicas starting together don't all collide
+	start := int(fnv32(s.owner) % uint32(s.shards))
+	for i := 0; i < s.shards && len(s.owned) < fairShare; i++ {
+		shard := (start + i) % s.shards
+		if s.owned[shard] {
+			continue
+		}
+		held, err := s.claim(ctx, shard)
+		if err != nil {
+			return err
+		}
+		if held {
+			s.owned[shard] = true
+			s.handoff(shard, ShardAcquired)
+		}
+	}
+
+	s.validUntil = validUntil
+	if s.recorder != nil {
+		s.recorder.SetOwnedShards(len(s.owned))
+	}
+	return nil
+}
+
+// ReleaseAll gives every shard back, e.g. on shutdown, so the other replicas take
+// them over without waiting for the leases to expire
+func (s *AlertSharder) ReleaseAll(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for shard := range s.owned {
+		if err := s.release(ctx, shard); err != nil {
+			return err
+		}
+		delete(s.owned, shard)
+		s.handoff(shard, ShardReleased)
+	}
+	if err := s.client.ZRem(ctx, s.membersKey(), s.owner).Err(); err != nil {
+		return fmt.Errorf("failed to leave alert shard members: %w", err)
+	}
+	if s.recorder != nil {
+		s.recorder.SetOwnedShards(0)
+	}
+	return nil
+}
+
+// heartbeat marks this replica as live for a lease TTL and returns how many are
+func (s *AlertSharder) heartbeat(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	key := s.membersKey()
+	expiresAt := float64(now.Add(s.leaseTTL).UnixMilli())
+	if err := s.client.ZAdd(ctx, key, redis.Z{Score: expiresAt, Member: s.owner}).Err(); err != nil {
+		return 0, fmt.Errorf("failed to register alert shard member: %w", err)
+	}
+	if err := s.client.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.UnixMilli(), 10)).Err(); err != nil {
+		return 0, fmt.Errorf("failed to expire alert shard members: %w", err)
+	}
+	replicas, err := s.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count alert shard members: %w", err)
+	}
+	if replicas < 1 {
+		replicas = 1
+	}
+	return int(replicas), nil
+}
+
+func (s *AlertSharder) claim(ctx context.Context, shard int) (bool, error) {
+	held, err := s.client.Eval(ctx, claimShardScript, []string{s.leaseKey(shard)}, s.owner, s.leaseTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim alert shard %d: %w", shard, err)
+	}
+	return held == 1, nil
+}
+
+func (s *AlertSharder) release(ctx context.Context, shard int) error {
+	if err := s.client.Eval(ctx, releaseShardScript, []string{s.leaseKey(shard)}, s.owner).Err(); err != nil {
+		return fmt.Errorf("failed to release alert shard %d: %w", shard, err)
+	}
+	return nil
+}
+
+func (s *AlertSharder) handoff(shard int, direction string) {
+	s.logger.WithFields(logrus.Fields{
+		"shard": shard,
+		"owner": s.owner,
+	}).Infof("Alert shard %s", direction)
+	if s.recorder != nil {
+		s.recorder.ObserveHandoff(direction)
+	}
+}
+
+func (s *AlertSharder) leaseKey(shard int) string {
+	return "alert_shards:lease:" + strconv.Itoa(shard)
+}
+
+func (s *AlertSharder) membersKey() string {
+	return "alert_shards:members"
+}
+
+func fnv32(value string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(value))
+	return hash.Sum32()
+}
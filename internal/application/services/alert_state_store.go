@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/redis/go-redis/v9"
+)
+
+// alertStateTTL expires the states saved without a TTL of their own
+const alertStateTTL = 7 * 24 * time.Hour
+
+// Alert state stores
+const (
+	AlertStateStoreRedis  = "redis"
+	AlertStateStoreMemory = "memory"
+)
+
+// AlertStateVersion is the schema version of the alert state snapshots saved by this
+// build. Version 1 snapshots predate versioning and lack the alert's timeframe and
+// target; version 2 records them, so a snapshot taken before the alert changed isn't
+// compared against.
+const AlertStateVersion = 2
+
+// Fields every alert state snapshot carries next to the condition's own values
+const (
+	alertStateVersionField   = "version"
+	alertStateTimeframeField = "timeframe"
+	alertStateTargetField    = "target_value"
+)
+
+// alertStateTTLCandles is how many candles of its timeframe an alert's state outlives
+// its last evaluation by, and minAlertStateTTL the least it is kept, so a state
+// survives the downtime startup recovery replays
+const (
+	alertStateTTLCandles = 3
+	minAlertStateTTL     = DefaultRecoveryWindow
+)
+
+// AlertStateTTL returns how long the state of an alert on the timeframe is kept after
+// its last evaluation: a few candles, at least the recovery window, and the default
+// week for unknown timeframes
+func AlertStateTTL(timeframe string) time.Duration {
+	candle := time.Duration(indicators.GetTimeframeMilliseconds(timeframe)) * time.Millisecond
+	if candle <= 0 {
+		return alertStateTTL
+	}
+	if ttl := alertStateTTLCandles * candle; ttl > minAlertStateTTL {
+		return ttl
+	}
+	return minAlertStateTTL
+}
+
+// versionAlertState stamps the alert's state with the current schema version and the
+// alert's timeframe and target
+func versionAlertState(alert *entities.Alert, state map[string]interface{}) map[string]interface{} {
+	state[alertStateVersionField] = AlertStateVersion
+	state[alertStateTimeframeField] = alert.Timeframe
+	state[alertStateTargetField] = alert.TargetValue
+	return state
+}
+
+// upgradeAlertState migrates a saved snapshot of the alert's state to the current
+// schema version. It returns why the snapshot can't be used: a newer build wrote it,
+// or the alert's timeframe or target changed since.
+func upgradeAlertState(alert *entities.Alert, state map[string]interface{}) (map[string]interface{}, string) {
+	// Numbers decode from JSON as float64 but stay int in process
+	version := 1
+	switch saved := state[alertStateVersionField].(type) {
+	case float64:
+		version = int(saved)
+	case int:
+		version = saved
+	}
+	if version > AlertStateVersion {
+		return nil, fmt.Sprintf("schema version %d is newer than %d", version, AlertStateVersion)
+	}
+	if version < 2 {
+		// Unversioned snapshots hold the same values; they are taken as the alert's own
+		state = versionAlertState(alert, state)
+	}
+
+	if timeframe, _ := state[alertStateTimeframeField].(string); timeframe != alert.Timeframe {
+		return nil, "alert timeframe changed"
+	}
+	if target, _ := state[alertStateTargetField].(float64); target != alert.TargetValue {
+		return nil, "alert target changed"
+	}
+	return state, ""
+}
+
+// AlertStateStore keeps the state crossover conditions compare the next evaluation
+// against: the previous moving averages, MACD lines or close. Backed by Redis it
+// survives restarts and is shared by every replica evaluating the alert.
+type AlertStateStore interface {
+	// Load returns the state saved under key and whether there was one
+	Load(ctx context.Context, key string) (map[string]interface{}, bool, error)
+	// Save replaces the state saved under key, kept for ttl or the default week when 0
+	Save(ctx context.Context, key string, state map[string]interface{}, ttl time.Duration) error
+	// Count returns how many keys with the prefix hold a state
+	Count(ctx context.Context, prefix string) (int, error)
+}
+
+// memoryAlertState is a state held by MemoryAlertStateStore until it expires
+type memoryAlertState struct {
+	state map[string]interface{}
+	until time.Time
+}
+
+// MemoryAlertStateStore keeps alert state in process; it is only safe for a single
+// instance and is lost on restart
+type MemoryAlertStateStore struct {
+	states map[string]memoryAlertState
+	mutex  sync.Mutex
+	clock  clock.Clock
+}
+
+// NewMemoryAlertStateStore creates an empty in-process alert state store
+func NewMemoryAlertStateStore() *MemoryAlertStateStore {
+	return &MemoryAlertStateStore{
+		states: make(map[string]memoryAlertState),
+		clock:  clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to expire states
+func (s *MemoryAlertStateStore) SetClock(c clock.Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clock = c
+}
+
+func (s *MemoryAlertStateStore) Load(ctx context.Context, key string) (map[string]interface{}, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	saved, found := s.states[key]
+	if !found || !s.clock.Now().Before(saved.until) {
+		return nil, false, nil
+	}
+	return saved.state, true, nil
+}
+
+func (s *MemoryAlertStateStore) Save(ctx context.Context, key string, state map[string]interface{}, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if ttl <= 0 {
+		ttl = alertStateTTL
+	}
+	s.states[key] = memoryAlertState{state: state, until: s.clock.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryAlertStateStore) Count(ctx context.Context, prefix string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	count := 0
+	for key, saved := range s.states {
+		if strings.HasPrefix(key, prefix) && now.Before(saved.until) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Cleanup drops expired states
+func (s *MemoryAlertStateStore) Cleanup() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	for key, saved := range s.states {
+		if !now.Before(saved.until) {
+			delete(s.states, key)
+		}
+	}
+}
+
+// redisStateClient is the subset of the Redis client used by RedisAlertStateStore
+type redisStateClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+}
+
+// RedisAlertStateStore saves alert state as JSON under the "alert_state:" key space;
+// every save sets the key's TTL anew
+type RedisAlertStateStore struct {
+	client redisStateClient
+	prefix string
+}
+
+// NewRedisAlertStateStore creates a Redis backed alert state store
+func NewRedisAlertStateStore(client redisStateClient) *RedisAlertStateStore {
+	return &RedisAlertStateStore{client: client, prefix: "alert_state:"}
+}
+
+func (s *RedisAlertStateStore) Load(ctx context.Context, key string) (map[string]interface{}, bool, error) {
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load alert state %s: %w", key, err)
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, false, fmt.Errorf("failed to decode alert state %s: %w", key, err)
+	}
+	return state, true, nil
+}
+
+func (s *RedisAlertStateStore) Save(ctx context.Context, key string, state map[string]interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert state %s: %w", key, err)
+	}
+	if ttl <= 0 {
+		ttl = alertStateTTL
+	}
+	if err := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save alert state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *RedisAlertStateStore) Count(ctx context.Context, prefix string) (int, error) {
+	count := 0
+	iter := s.client.Scan(ctx, 0, s.prefix+prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to count alert states: %w", err)
+	}
+	return count, nil
+}
@@ -13,19 +13,34 @@ import (
 type WebSocketHub interface {
 	Broadcast(room, messageType string, data interface{})
 	BroadcastToUser(userID uuid.UUID, messageType string, data interface{})
+	IsUserConnected(ctx context.Context, userID uuid.UUID) bool
 	GetConnectedClients() int
 	GetRooms() map[string]int
+	Stats() WebSocketStats
+}
+
+// WebSocketStats is a snapshot of hub activity. Message counts are totals since
+// the hub started; the rate covers the interval since the previous snapshot.
+type WebSocketStats struct {
+	ConnectedClients  int            `json:"connected_clients"`
+	ConnectedUsers    int            `json:"connected_users"`
+	RoomUsers         map[string]int `json:"room_users"` // Distinct users per room
+	MessagesPerSecond float64        `json:"messages_per_second"`
+	MessagesSent      uint64         `json:"messages_sent"`
+	MessagesDropped   uint64         `json:"messages_dropped"`
 }
 
 // AlertWebSocketService defines the methods required for broadcasting alert and
 // notification events over WebSocket. It is kept as an interface to simplify
 // testing of components that depend on WebSocket broadcasting.
 type AlertWebSocketService interface {
+	IsUserConnected(ctx context.Context, userID uuid.UUID) bool
 	BroadcastAlertTriggered(ctx context.Context, alert *entities.Alert, result *AlertEvaluationResult) error
 	BroadcastNotificationUpdate(ctx context.Context, notification *entities.Notification) error
 	BroadcastCryptoDataUpdate(ctx context.Context, symbol string, data map[string]interface{}) error
 	BroadcastSystemAlert(ctx context.Context, alertType, title, message string, data map[string]interface{}) error
 	NotifyAlertEvaluation(ctx context.Context, userID uuid.UUID, results []AlertEvaluationResult) error
+	BroadcastAlertLevels(ctx context.Context, userID uuid.UUID, symbol string, levels []AlertLevel) error
 	GetConnectedUsersStats(ctx context.Context) (map[string]interface{}, error)
 }
 
@@ -52,6 +67,11 @@ func NewAlertWebSocketService(
 	}
 }
 
+// IsUserConnected reports whether the user has a WebSocket connection on any instance
+func (aws *alertWebSocketService) IsUserConnected(ctx context.Context, userID uuid.UUID) bool {
+	return aws.wsHub.IsUserConnected(ctx, userID)
+}
+
 // BroadcastAlertTriggered broadcasts alert triggered events to connected clients
 func (aws *alertWebSocketService) BroadcastAlertTriggered(ctx context.Context, alert *entities.Alert, result *AlertEvaluationResult) error {
 	// Create broadcast data
@@ -67,6 +87,10 @@ func (aws *alertWebSocketService) BroadcastAlertTriggered(ctx context.Context, a
 		"triggered_at":   alert.TriggeredAt,
 		"context":        result.Context,
 	}
+	// Lets the client measure the last hop, from the exchange event to the device
+	if result.Trace != nil {
+		data["trace"] = result.Trace
+	}
 
 	// Broadcast to specific user
 	aws.wsHub.BroadcastToUser(alert.UserID, "alert_triggered", data)
@@ -179,11 +203,35 @@ func (aws *alertWebSocketService) NotifyAlertEvaluation(ctx context.Context, use
 	return nil
 }
 
+// BroadcastAlertLevels sends a user's current alert lines on a symbol to their clients
+func (aws *alertWebSocketService) BroadcastAlertLevels(ctx context.Context, userID uuid.UUID, symbol string, levels []AlertLevel) error {
+	data := map[string]interface{}{
+		"symbol": symbol,
+		"levels": levels,
+	}
+
+	aws.wsHub.BroadcastToUser(userID, "alert_levels", data)
+
+	aws.logger.WithFields(logrus.Fields{
+		"user_id":     userID,
+		"symbol":      symbol,
+		"level_count": len(levels),
+	}).Debug("Alert levels broadcasted via WebSocket")
+
+	return nil
+}
+
 // GetConnectedUsersStats returns statistics about connected users
 func (aws *alertWebSocketService) GetConnectedUsersStats(ctx context.Context) (map[string]interface{}, error) {
+	hubStats := aws.wsHub.Stats()
 	stats := map[string]interface{}{
-		"total_connections": aws.wsHub.GetConnectedClients(),
-		"rooms":             aws.wsHub.GetRooms(),
+		"total_connections":   hubStats.ConnectedClients,
+		"connected_users":     hubStats.ConnectedUsers,
+		"rooms":               aws.wsHub.GetRooms(),
+		"room_users":          hubStats.RoomUsers,
+		"messages_per_second": hubStats.MessagesPerSecond,
+		"messages_sent":       hubStats.MessagesSent,
+		"messages_dropped":    hubStats.MessagesDropped,
 	}
 
 	return stats, nil
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAPIKeysPerUser caps the keys a user can hold at once
+	MaxAPIKeysPerUser = 5
+
+	// apiKeyPrefix marks PriceGuard keys so they are recognizable when leaked
+	apiKeyPrefix = "pg_"
+	apiKeyBytes  = 24
+	// apiKeyVisiblePrefix is how much of the key is stored in clear to tell keys apart
+	apiKeyVisiblePrefix = len(apiKeyPrefix) + 8
+	// apiKeyTouchInterval limits how often a key's last use is written back
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	// ErrAPIKeyNotFound is returned for unknown, revoked or foreign API keys
+	ErrAPIKeyNotFound = entities.NewDomainError(entities.ErrNotFound, "API key not found")
+	// ErrTooManyAPIKeys is returned when the user already holds MaxAPIKeysPerUser active keys
+	ErrTooManyAPIKeys = entities.NewDomainError(entities.ErrConflict, "at most %d API keys can be active at once", MaxAPIKeysPerUser)
+)
+
+// CreatedAPIKey is a new API key together with the key itself, which is only
+// available when the key is created
+type CreatedAPIKey struct {
+	*entities.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyService issues the keys users' own tools read the public market data API
+// with, and resolves the key sent with each request
+type APIKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	logger     *logrus.Logger
+	clock      clock.Clock
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, logger *logrus.Logger) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+		clock:      clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to stamp key usage and revocation
+func (s *APIKeyService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// CreateAPIKey issues a new key for the user
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID uuid.UUID, name string) (*CreatedAPIKey, error) {
+	key := &entities.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		CreatedAt: s.clock.Now(),
+	}
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+
+	active, err := s.apiKeyRepo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+	if active >= MaxAPIKeysPerUser {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key.Prefix = secret[:apiKeyVisiblePrefix]
+	key.KeyHash = hashShareToken(secret)
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"api_key_id": key.ID,
+	}).Info("API key created")
+
+	return &CreatedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// ListAPIKeys returns the user's keys, newest first
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]entities.APIKey, error) {
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey disables one of the user's keys
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.apiKeyRepo.Revoke(ctx, id, userID, s.clock.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"api_key_id": id,
+	}).Info("API key revoked")
+	return nil
+}
+
+// Authenticate returns the active key matching secret and records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (*entities.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	key, err := s.apiKeyRepo.GetByKeyHash(ctx, hashShareToken(secret))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if !key.IsActive() {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	// Busy keys would otherwise cost a write per request
+	now := s.clock.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.logger.WithError(err).WithField("api_key_id", key.ID).Warn("Failed to record API key use")
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
+
+func newAPIKey() (string, error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
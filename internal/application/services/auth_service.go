@@ -82,7 +82,9 @@ func (a *AuthService) LoginWithGoogleIDToken(ctx context.Context, idToken string
 			GoogleID: googleUser.ID,
 			Email:    googleUser.Email,
 			Name:     googleUser.Name,
-			Picture:  &googleUser.Picture,
+		}
+		if googleUser.Picture != "" {
+			user.Picture = &googleUser.Picture
 		}
 
 		if err := a.userRepo.Create(ctx, user); err != nil {
@@ -100,6 +102,8 @@ func (a *AuthService) LoginWithGoogleIDToken(ctx context.Context, idToken string
 			NotificationsSMS:   false,
 			RiskProfile:        "moderate",
 			FavoriteSymbols:    []string{},
+			ReportFrequency:    "none",
+			ReportHour:         8,
 		}
 
 		if err := a.settingsRepo.Create(ctx, settings); err != nil {
@@ -109,19 +113,35 @@ func (a *AuthService) LoginWithGoogleIDToken(ctx context.Context, idToken string
 		a.logger.WithField("user_id", user.ID).Info("Novo usuário Google criado com sucesso")
 	} else {
 		a.logger.WithField("user_id", user.ID).Info("Usuário Google encontrado, atualizando dados")
-		user.Name = googleUser.Name
-		user.Picture = &googleUser.Picture
-		if err := a.userRepo.Update(ctx, user); err != nil {
-			a.logger.WithError(err).Error("Falha ao atualizar dados do usuário Google")
+		if refreshGoogleProfile(user, googleUser) {
+			if err := a.userRepo.Update(ctx, user); err != nil {
+				a.logger.WithError(err).Error("Falha ao atualizar dados do usuário Google")
+			}
 		}
 	}
+	user.ResolveAvatarURL()
+
+	tokens, err := a.StartSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.WithField("user_id", user.ID).Info("User logged in successfully")
+
+	return &LoginResult{
+		User:   user,
+		Tokens: tokens,
+	}, nil
+}
 
+// StartSession issues the user's tokens and stores the session of the refresh token
+func (a *AuthService) StartSession(ctx context.Context, user *entities.User) (*AuthTokens, error) {
 	accessToken, refreshToken, err := a.jwtService.GenerateTokens(user.ID, user.Email, user.Name, user.GoogleID)
 	if err != nil {
-		a.logger.WithError(err).Error("Falha ao gerar tokens JWT para usuário Google")
+		a.logger.WithError(err).Error("Falha ao gerar tokens JWT")
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
-	a.logger.WithField("user_id", user.ID).Info("Tokens JWT gerados com sucesso para usuário Google")
+	a.logger.WithField("user_id", user.ID).Info("Tokens JWT gerados com sucesso")
 
 	// Store session in database
 	tokenHash := a.jwtService.GetTokenHash(refreshToken)
@@ -140,18 +160,11 @@ func (a *AuthService) LoginWithGoogleIDToken(ctx context.Context, idToken string
 		a.logger.WithError(err).Error("Failed to cache session in Redis")
 	}
 
-	tokens := &AuthTokens{
+	return &AuthTokens{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    int64(24 * time.Hour.Seconds()), // 24 hours for access token
 		TokenType:    "Bearer",
-	}
-
-	a.logger.WithField("user_id", user.ID).Info("User logged in successfully")
-
-	return &LoginResult{
-		User:   user,
-		Tokens: tokens,
 	}, nil
 }
 
@@ -289,3 +302,20 @@ func (a *AuthService) ValidateToken(ctx context.Context, accessToken string) (*e
 func (a *AuthService) CleanupExpiredSessions(ctx context.Context) error {
 	return a.sessionRepo.DeleteExpired(ctx)
 }
+
+// refreshGoogleProfile copies the Google display name and picture onto the user,
+// reporting whether anything changed. Empty values never overwrite stored ones and
+// a custom Avatar is left untouched.
+func refreshGoogleProfile(user *entities.User, googleUser *services.GoogleUser) bool {
+	changed := false
+	if googleUser.Name != "" && user.Name != googleUser.Name {
+		user.Name = googleUser.Name
+		changed = true
+	}
+	if googleUser.Picture != "" && (user.Picture == nil || *user.Picture != googleUser.Picture) {
+		picture := googleUser.Picture
+		user.Picture = &picture
+		changed = true
+	}
+	return changed
+}
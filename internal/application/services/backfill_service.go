@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// backfillPageSize is the most klines Binance returns per request
+	backfillPageSize = 1000
+	// DefaultMaxBackfillCandles caps the candles one backfill fetches, keeping an
+	// admin request within a few dozen upstream calls
+	DefaultMaxBackfillCandles = 20000
+)
+
+// KlineSource serves historical klines in Binance's array format, paged by open time
+type KlineSource interface {
+	GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error)
+}
+
+// BackfillRequest asks for the candles of Symbol on Timeframe opened between From
+// and To inclusive
+type BackfillRequest struct {
+	Symbol    string
+	Timeframe string
+	From      time.Time
+	To        time.Time
+}
+
+// BackfillResult summarizes a backfill; candles already stored are skipped
+type BackfillResult struct {
+	Symbol    string        `json:"symbol"`
+	Timeframe string        `json:"timeframe"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Fetched   int           `json:"fetched"`
+	Inserted  int           `json:"inserted"`
+	Skipped   int           `json:"skipped"`
+	Pages     int           `json:"pages"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// BackfillService fills gaps in the stored candles from the exchange's history,
+// a page of klines at a time
+type BackfillService struct {
+	klines           KlineSource
+	priceHistoryRepo repositories.PriceHistoryRepository
+	maxCandles       int
+	logger           *logrus.Logger
+}
+
+// NewBackfillService creates a backfill service fetching at most
+// DefaultMaxBackfillCandles per backfill
+func NewBackfillService(klines KlineSource, priceHistoryRepo repositories.PriceHistoryRepository, logger *logrus.Logger) *BackfillService {
+	return &BackfillService{
+		klines:           klines,
+		priceHistoryRepo: priceHistoryRepo,
+		maxCandles:       DefaultMaxBackfillCandles,
+		logger:           logger,
+	}
+}
+
+// SetMaxCandles caps the candles one backfill fetches; 0 removes the cap
+func (s *BackfillService) SetMaxCandles(maxCandles int) {
+	s.maxCandles = maxCandles
+}
+
+// validate checks the request against the supported timeframes and the candle cap
+func (s *BackfillService) validate(req BackfillRequest) error {
+	step := indicators.GetTimeframeMilliseconds(req.Timeframe)
+	switch {
+	case req.Symbol == "" || len(req.Symbol) > 20:
+		return backfillValidationError("symbol", "must be between 1 and 20 characters")
+	case step == 0:
+		return backfillValidationError("timeframe", fmt.Sprintf("unsupported timeframe %q", req.Timeframe))
+	case req.To.Before(req.From):
+		return backfillValidationError("to", "must not be before from")
+	case s.maxCandles > 0 && req.To.Sub(req.From).Milliseconds()/step >= int64(s.maxCandles):
+		return backfillValidationError("to", fmt.Sprintf("range must span at most %d candles", s.maxCandles))
+	}
+	return nil
+}
+
+func backfillValidationError(field, message string) error {
+	return &entities.ValidationError{Entity: "backfill", Field: field, Message: message}
+}
+
+// Backfill fetches the requested candles page by page and stores the ones missing,
+// so running it again over the same range inserts nothing
+func (s *BackfillService) Backfill(ctx context.Context, req BackfillRequest) (*BackfillResult, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+
+	stored, err := s.priceHistoryRepo.GetByTimeRange(ctx, req.Symbol, req.Timeframe, req.From, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored candles: %w", err)
+	}
+	existing := make(map[int64]bool, len(stored))
+	for _, candle := range stored {
+		existing[candle.Timestamp.UnixMilli()] = true
+	}
+
+	result := &BackfillResult{Symbol: req.Symbol, Timeframe: req.Timeframe, From: req.From, To: req.To}
+	step := indicators.GetTimeframeMilliseconds(req.Timeframe)
+	start, end := req.From.UnixMilli(), req.To.UnixMilli()
+	for start <= end {
+		page, err := s.klines.GetKlines(ctx, req.Symbol, req.Timeframe, backfillPageSize, &start, &end)
+		if err != nil {
+			return result, fmt.Errorf("failed to get klines from %s: %w", time.UnixMilli(start).UTC(), err)
+		}
+		result.Pages++
+		if len(page) == 0 {
+			break
+		}
+
+		var missing []entities.PriceHistory
+		lastOpen := start
+		for _, kline := range page {
+			candle, ok := klineHistory(req.Symbol, req.Timeframe, kline)
+			if !ok {
+				continue
+			}
+			result.Fetched++
+			openTime := candle.Timestamp.UnixMilli()
+			if openTime > lastOpen {
+				lastOpen = openTime
+			}
+			if existing[openTime] {
+				result.Skipped++
+				continue
+			}
+			existing[openTime] = true
+			missing = append(missing, candle)
+		}
+
+		if err := s.priceHistoryRepo.BulkInsert(ctx, missing); err != nil {
+			return result, fmt.Errorf("failed to store backfilled candles: %w", err)
+		}
+		result.Inserted += len(missing)
+
+		if len(page) < backfillPageSize {
+			break
+		}
+		start = lastOpen + step
+	}
+	result.Duration = time.Since(started)
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    req.Symbol,
+		"timeframe": req.Timeframe,
+		"from":      req.From,
+		"to":        req.To,
+		"inserted":  result.Inserted,
+		"skipped":   result.Skipped,
+		"pages":     result.Pages,
+	}).Info("Candle backfill completed")
+	return result, nil
+}
+
+// klineHistory converts a kline in Binance's array format to a candle, reporting
+// false when it is malformed
+func klineHistory(symbol, interval string, kline []interface{}) (entities.PriceHistory, bool) {
+	if len(kline) < 6 {
+		return entities.PriceHistory{}, false
+	}
+	openTime, ok := kline[0].(float64)
+	if !ok {
+		return entities.PriceHistory{}, false
+	}
+
+	var values [5]float64
+	for i := range values {
+		raw, ok := kline[i+1].(string)
+		if !ok {
+			return entities.PriceHistory{}, false
+		}
+		values[i], _ = strconv.ParseFloat(raw, 64)
+	}
+
+	return entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  interval,
+		Timestamp:  time.UnixMilli(int64(openTime)),
+		OpenPrice:  values[0],
+		HighPrice:  values[1],
+		LowPrice:   values[2],
+		ClosePrice: values[3],
+		Volume:     values[4],
+	}, true
+}
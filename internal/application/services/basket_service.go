@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// basketComputationLockKey keeps replicas from storing the same basket values twice
+const basketComputationLockKey = "basket_computation"
+
+// Basket defaults
+const (
+	DefaultMaxBasketsPerUser = 10
+	DefaultBasketMaxPriceAge = 5 * time.Minute
+	// basketReferenceTimeframe is the timeframe whose latest close becomes a
+	// component's reference price
+	basketReferenceTimeframe = "1m"
+	maxBasketHistoryLimit    = 500
+)
+
+// BasketAlertTypes are the alert types that can watch a basket; baskets have no
+// volume or indicators
+var BasketAlertTypes = []string{"price", "percentage"}
+
+// ErrBasketNotFound is returned for unknown or foreign baskets
+var ErrBasketNotFound = entities.NewDomainError(entities.ErrNotFound, "basket not found")
+
+// BasketValue is a basket together with its latest stored value, if it has one yet
+type BasketValue struct {
+	entities.Basket
+	Value   *float64   `json:"value,omitempty"`
+	ValueAt *time.Time `json:"value_at,omitempty"`
+}
+
+// BasketComputation summarizes one pass over the baskets
+type BasketComputation struct {
+	Stored  int `json:"stored"`
+	Skipped int `json:"skipped"` // Baskets and timeframes with a component price missing, stale or unchanged
+	Failed  int `json:"failed"`
+}
+
+// BasketService manages users' symbol baskets and computes their values on a
+// schedule. Each value is stored in price_history under the basket's symbol, so
+// baskets chart and trigger price and percentage alerts like real symbols.
+type BasketService struct {
+	basketRepo       repositories.BasketRepository
+	priceHistoryRepo repositories.PriceHistoryRepository
+	alertRepo        repositories.AlertRepository
+	logger           *logrus.Logger
+
+	timeframes  []string
+	maxPerUser  int
+	maxPriceAge time.Duration
+	clock       clock.Clock
+
+	// runs makes sure each interval is computed by one instance only
+	runs ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewBasketService creates a basket service computing 1m values
+func NewBasketService(
+	basketRepo repositories.BasketRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	alertRepo repositories.AlertRepository,
+	logger *logrus.Logger,
+) *BasketService {
+	return &BasketService{
+		basketRepo:       basketRepo,
+		priceHistoryRepo: priceHistoryRepo,
+		alertRepo:        alertRepo,
+		logger:           logger,
+		timeframes:       []string{basketReferenceTimeframe},
+		maxPerUser:       DefaultMaxBasketsPerUser,
+		maxPriceAge:      DefaultBasketMaxPriceAge,
+		clock:            clock.New(),
+		runs:             NewMemoryThrottleStore(),
+	}
+}
+
+// SetTimeframes replaces the timeframes basket values are computed on, from the
+// component candles of the same timeframe; unsupported timeframes are ignored
+func (s *BasketService) SetTimeframes(timeframes []string) {
+	supported := make([]string, 0, len(timeframes))
+	for _, timeframe := range timeframes {
+		if indicators.GetTimeframeMilliseconds(timeframe) > 0 {
+			supported = append(supported, timeframe)
+		}
+	}
+	if len(supported) > 0 {
+		s.timeframes = supported
+	}
+}
+
+// SetMaxPerUser caps how many baskets a user can have; 0 removes the cap
+func (s *BasketService) SetMaxPerUser(max int) {
+	s.maxPerUser = max
+}
+
+// SetMaxPriceAge sets how long past its candle's close a component price can be used;
+// a basket with an older component keeps its last value
+func (s *BasketService) SetMaxPriceAge(maxAge time.Duration) {
+	s.maxPriceAge = maxAge
+}
+
+// SetClock replaces the clock the staleness of component prices is judged by
+func (s *BasketService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetThrottleStore replaces the in-process run lock, e.g. with a Redis store so only
+// one replica computes the baskets each interval
+func (s *BasketService) SetThrottleStore(store ThrottleStore) {
+	s.runs = store
+}
+
+// CreateBasket creates a basket of the components, weighted in percent, taking each
+// component's latest price as its reference
+func (s *BasketService) CreateBasket(ctx context.Context, userID uuid.UUID, name string, components []entities.BasketComponent) (*entities.Basket, error) {
+	basket := &entities.Basket{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       strings.TrimSpace(name),
+		BaseValue:  entities.DefaultBasketBaseValue,
+		Components: make([]entities.BasketComponent, len(components)),
+	}
+	basket.Symbol = entities.BasketSymbol(basket.ID)
+	for i, component := range components {
+		basket.Components[i] = entities.BasketComponent{Symbol: strings.ToUpper(strings.TrimSpace(component.Symbol)), Weight: component.Weight}
+	}
+	if err := basket.Validate(); err != nil {
+		return nil, err
+	}
+
+	if s.maxPerUser > 0 {
+		existing, err := s.basketRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get baskets: %w", err)
+		}
+		if len(existing) >= s.maxPerUser {
+			return nil, &entities.ValidationError{Entity: "basket", Field: "user_id", Message: fmt.Sprintf("at most %d baskets are allowed", s.maxPerUser)}
+		}
+	}
+
+	for i := range basket.Components {
+		component := &basket.Components[i]
+		latest, err := s.priceHistoryRepo.GetLatest(ctx, component.Symbol, basketReferenceTimeframe)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && latest.ClosePrice <= 0) {
+			return nil, &entities.ValidationError{Entity: "basket", Field: "components", Message: fmt.Sprintf("no price for %s", component.Symbol)}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price of %s: %w", component.Symbol, err)
+		}
+		component.ReferencePrice = latest.ClosePrice
+	}
+
+	if err := s.basketRepo.Create(ctx, basket); err != nil {
+		return nil, fmt.Errorf("failed to create basket: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"basket_id": basket.ID,
+		"symbol":    basket.Symbol,
+	}).Info("Basket created")
+	return basket, nil
+}
+
+// ListBaskets returns the user's baskets ordered by name, with their latest values
+func (s *BasketService) ListBaskets(ctx context.Context, userID uuid.UUID) ([]BasketValue, error) {
+	baskets, err := s.basketRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baskets: %w", err)
+	}
+
+	values := make([]BasketValue, len(baskets))
+	for i, basket := range baskets {
+		value, err := s.withValue(ctx, basket)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = *value
+	}
+	return values, nil
+}
+
+// GetBasket returns one of the user's baskets with its latest value
+func (s *BasketService) GetBasket(ctx context.Context, userID, id uuid.UUID) (*BasketValue, error) {
+	basket, err := s.checkBasket(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withValue(ctx, *basket)
+}
+
+// RenameBasket renames one of the user's baskets; its components can't change, as
+// that would break the continuity of its values
+func (s *BasketService) RenameBasket(ctx context.Context, userID, id uuid.UUID, name string) (*entities.Basket, error) {
+	basket, err := s.checkBasket(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	basket.Name = strings.TrimSpace(name)
+	if err := basket.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.basketRepo.Update(ctx, basket); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBasketNotFound
+		}
+		return nil, fmt.Errorf("failed to update basket: %w", err)
+	}
+	return basket, nil
+}
+
+// DeleteBasket deletes one of the user's baskets and the user's alerts on it, which
+// could never trigger again, and returns how many alerts were deleted. Its stored
+// values are left to the price history retention.
+func (s *BasketService) DeleteBasket(ctx context.Context, userID, id uuid.UUID) (int, error) {
+	basket, err := s.checkBasket(ctx, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	alerts, err := s.alertRepo.GetBySymbol(ctx, basket.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get alerts of basket: %w", err)
+	}
+
+	// The alerts go first, so a failure leaves the basket in place to retry with
+	deleted := 0
+	for _, alert := range alerts {
+		if alert.UserID != userID {
+			continue
+		}
+		if err := s.alertRepo.Delete(ctx, alert.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete alert %s: %w", alert.ID, err)
+		}
+		deleted++
+	}
+
+	if err := s.basketRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return deleted, ErrBasketNotFound
+		}
+		return deleted, fmt.Errorf("failed to delete basket: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"basket_id": id,
+		"alerts":    deleted,
+	}).Info("Basket deleted")
+	return deleted, nil
+}
+
+// GetHistory returns up to limit of the latest stored values of one of the user's
+// baskets on the timeframe, oldest first
+func (s *BasketService) GetHistory(ctx context.Context, userID, id uuid.UUID, timeframe string, limit int) ([]entities.PriceHistory, error) {
+	basket, err := s.checkBasket(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.computes(timeframe) {
+		return nil, &entities.ValidationError{Entity: "basket", Field: "timeframe", Message: fmt.Sprintf("basket values are computed on %s", strings.Join(s.timeframes, ", "))}
+	}
+	if limit <= 0 || limit > maxBasketHistoryLimit {
+		limit = maxBasketHistoryLimit
+	}
+
+	history, err := s.priceHistoryRepo.GetBySymbol(ctx, basket.Symbol, timeframe, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get basket history: %w", err)
+	}
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
+// CheckAlert checks an alert on a basket symbol: the basket must be the alert
+// owner's, the alert type one of BasketAlertTypes and the timeframe one the basket
+// values are computed on. Alerts on market symbols pass.
+func (s *BasketService) CheckAlert(ctx context.Context, alert *entities.Alert) error {
+	if !entities.IsBasketSymbol(alert.Symbol) {
+		return nil
+	}
+
+	basket, err := s.basketRepo.GetBySymbol(ctx, alert.Symbol)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && basket.UserID != alert.UserID) {
+		return &entities.ValidationError{Entity: "alert", Field: "symbol", Message: "basket not found"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get basket: %w", err)
+	}
+
+	if !slices.Contains(BasketAlertTypes, alert.AlertType) {
+		return &entities.ValidationError{Entity: "alert", Field: "alert_type", Message: fmt.Sprintf("alerts on baskets must be of type %s", strings.Join(BasketAlertTypes, " or "))}
+	}
+	if !s.computes(alert.Timeframe) {
+		return &entities.ValidationError{Entity: "alert", Field: "timeframe", Message: fmt.Sprintf("basket values are computed on %s", strings.Join(s.timeframes, ", "))}
+	}
+	return nil
+}
+
+// Start computes the baskets once and then every interval until Stop is called
+func (s *BasketService) Start(ctx context.Context, interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isRunning {
+		s.logger.Warn("Basket computation is already running")
+		return
+	}
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.logger.WithFields(logrus.Fields{
+		"interval":   interval,
+		"timeframes": s.timeframes,
+	}).Info("Starting basket computation")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the computation and waits for the current pass to finish
+func (s *BasketService) Stop() {
+	s.mutex.Lock()
+	if !s.isRunning {
+		s.mutex.Unlock()
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	s.logger.Info("Basket computation stopped")
+}
+
+// runScheduled computes unless another instance already took this interval
+func (s *BasketService) runScheduled(ctx context.Context, interval time.Duration) {
+	acquired, err := s.runs.Acquire(ctx, basketComputationLockKey, interval)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to acquire basket computation lock")
+	} else if !acquired {
+		return
+	}
+
+	if _, err := s.ComputeAll(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to compute baskets")
+	}
+}
+
+// ComputeAll stores a new value of every basket on every timeframe whose component
+// candles are all fresh and one of them is newer than the basket's last value. A
+// basket that fails is logged, counted and retried on the next pass.
+func (s *BasketService) ComputeAll(ctx context.Context) (*BasketComputation, error) {
+	baskets, err := s.basketRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baskets: %w", err)
+	}
+
+	run := &BasketComputation{}
+	for _, timeframe := range s.timeframes {
+		// Baskets often share components, so each one's latest candle is read once
+		latest := make(map[string]*entities.PriceHistory)
+		for _, basket := range baskets {
+			stored, err := s.compute(ctx, basket, timeframe, latest)
+			switch {
+			case err != nil:
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"basket_id": basket.ID,
+					"timeframe": timeframe,
+				}).Error("Failed to compute basket")
+				run.Failed++
+			case stored:
+				run.Stored++
+			default:
+				run.Skipped++
+			}
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"baskets": len(baskets),
+		"stored":  run.Stored,
+		"skipped": run.Skipped,
+		"failed":  run.Failed,
+	}).Debug("Computed baskets")
+	return run, nil
+}
+
+// compute stores the basket's value on the timeframe from the latest component
+// candles, which are looked up in and added to latest. The candle is stamped with
+// the newest component candle, so nothing is stored until one of them changes.
+func (s *BasketService) compute(ctx context.Context, basket entities.Basket, timeframe string, latest map[string]*entities.PriceHistory) (bool, error) {
+	opens := make(map[string]float64, len(basket.Components))
+	highs := make(map[string]float64, len(basket.Components))
+	lows := make(map[string]float64, len(basket.Components))
+	closes := make(map[string]float64, len(basket.Components))
+	var timestamp time.Time
+	for _, component := range basket.Components {
+		candle, cached := latest[component.Symbol]
+		if !cached {
+			var err error
+			candle, err = s.priceHistoryRepo.GetLatest(ctx, component.Symbol, timeframe)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, fmt.Errorf("failed to get price of %s: %w", component.Symbol, err)
+			}
+			latest[component.Symbol] = candle
+		}
+		if candle == nil || (s.maxPriceAge > 0 && dataAge(candle.Timestamp, timeframe, s.clock.Now()) > s.maxPriceAge) {
+			return false, nil
+		}
+
+		opens[component.Symbol] = candle.OpenPrice
+		highs[component.Symbol] = candle.HighPrice
+		lows[component.Symbol] = candle.LowPrice
+		closes[component.Symbol] = candle.ClosePrice
+		if candle.Timestamp.After(timestamp) {
+			timestamp = candle.Timestamp
+		}
+	}
+
+	previous, err := s.priceHistoryRepo.GetLatest(ctx, basket.Symbol, timeframe)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("failed to get last value: %w", err)
+	}
+	if previous != nil && !timestamp.After(previous.Timestamp) {
+		return false, nil
+	}
+
+	// The components' highs and lows needn't happen at the same time, so the basket's
+	// are bounds rather than values it actually reached
+	candle := &entities.PriceHistory{Symbol: basket.Symbol, Timeframe: timeframe, Timestamp: timestamp}
+	candle.OpenPrice, _ = basket.Value(opens)
+	candle.HighPrice, _ = basket.Value(highs)
+	candle.LowPrice, _ = basket.Value(lows)
+	candle.ClosePrice, _ = basket.Value(closes)
+	if err := s.priceHistoryRepo.Create(ctx, candle); err != nil {
+		return false, fmt.Errorf("failed to store value: %w", err)
+	}
+	return true, nil
+}
+
+// checkBasket returns the basket when it belongs to the user; other users' baskets
+// are reported as not found
+func (s *BasketService) checkBasket(ctx context.Context, userID, id uuid.UUID) (*entities.Basket, error) {
+	basket, err := s.basketRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBasketNotFound
+		}
+		return nil, fmt.Errorf("failed to get basket: %w", err)
+	}
+	if basket.UserID != userID {
+		return nil, ErrBasketNotFound
+	}
+	return basket, nil
+}
+
+// withValue adds the basket's latest stored value on the first timeframe
+func (s *BasketService) withValue(ctx context.Context, basket entities.Basket) (*BasketValue, error) {
+	value := &BasketValue{Basket: basket}
+	latest, err := s.priceHistoryRepo.GetLatest(ctx, basket.Symbol, s.timeframes[0])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return value, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get basket value: %w", err)
+	}
+	value.Value = &latest.ClosePrice
+	value.ValueAt = &latest.Timestamp
+	return value, nil
+}
+
+// computes reports whether basket values are computed on the timeframe
+func (s *BasketService) computes(timeframe string) bool {
+	return slices.Contains(s.timeframes, timeframe)
+}
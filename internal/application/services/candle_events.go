@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/sirupsen/logrus"
+)
+
+// CandleTimeframes are the timeframes candle-close events are published for
+var CandleTimeframes = []string{"1m", "5m", "15m", "30m", "1h", "4h", "1d"}
+
+// CandleClosed signals that the candle of a symbol and timeframe opened at OpenTime
+// closed; ClosePrice is the last price collected before CloseTime
+type CandleClosed struct {
+	Symbol     string
+	Timeframe  string
+	OpenTime   time.Time
+	CloseTime  time.Time
+	ClosePrice float64
+}
+
+// CandleHandler reacts to a candle-close event; errors are logged by the bus
+type CandleHandler func(ctx context.Context, event CandleClosed) error
+
+// candleSubscription is a handler registered on the bus under a name for the logs
+type candleSubscription struct {
+	name    string
+	handler CandleHandler
+}
+
+// CandleEventBus dispatches candle-close events to the internal consumers, such as
+// the indicator refresh and the alert evaluation, so they run when a candle closes
+// rather than on tickers of their own. Handlers of a timeframe run in the order they
+// subscribed, on the publisher's goroutine, so a consumer can rely on the ones
+// registered before it, e.g. alerts on the indicators of the candle just closed.
+type CandleEventBus struct {
+	subscriptions map[string][]candleSubscription
+	mutex         sync.RWMutex
+	logger        *logrus.Logger
+}
+
+// NewCandleEventBus creates a bus without subscribers
+func NewCandleEventBus(logger *logrus.Logger) *CandleEventBus {
+	return &CandleEventBus{
+		subscriptions: make(map[string][]candleSubscription),
+		logger:        logger,
+	}
+}
+
+// Subscribe calls handler for every candle of the timeframe that closes
+func (b *CandleEventBus) Subscribe(timeframe, name string, handler CandleHandler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscriptions[timeframe] = append(b.subscriptions[timeframe], candleSubscription{name: name, handler: handler})
+}
+
+// Publish runs the handlers subscribed to the event's timeframe. A failing or
+// panicking handler is logged and doesn't keep the next ones from running.
+func (b *CandleEventBus) Publish(ctx context.Context, event CandleClosed) {
+	b.mutex.RLock()
+	subscriptions := b.subscriptions[event.Timeframe]
+	b.mutex.RUnlock()
+
+	for _, subscription := range subscriptions {
+		if err := b.dispatch(ctx, subscription, event); err != nil {
+			b.logger.WithError(err).WithFields(logrus.Fields{
+				"handler":   subscription.name,
+				"symbol":    event.Symbol,
+				"timeframe": event.Timeframe,
+			}).Error("Candle close handler failed")
+		}
+	}
+}
+
+func (b *CandleEventBus) dispatch(ctx context.Context, subscription candleSubscription, event CandleClosed) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return subscription.handler(ctx, event)
+}
+
+// CandleCloseTracker turns the stream of collected prices of each symbol into
+// candle-close events: a candle closed once a price of a later candle comes in
+type CandleCloseTracker struct {
+	last  map[string]entities.PriceHistory
+	mutex sync.Mutex
+}
+
+// NewCandleCloseTracker creates a tracker that hasn't seen any price yet
+func NewCandleCloseTracker() *CandleCloseTracker {
+	return &CandleCloseTracker{last: make(map[string]entities.PriceHistory)}
+}
+
+// Observe records a collected price and returns the candles of CandleTimeframes it
+// closed, longest timeframe first, so the consumers of a minute close see the hourly
+// indicators of an hour that closed with it
+func (t *CandleCloseTracker) Observe(price entities.PriceHistory) []CandleClosed {
+	t.mutex.Lock()
+	previous, seen := t.last[price.Symbol]
+	if !seen || price.Timestamp.After(previous.Timestamp) {
+		t.last[price.Symbol] = price
+	}
+	t.mutex.Unlock()
+
+	if !seen || !price.Timestamp.After(previous.Timestamp) {
+		return nil
+	}
+
+	var closed []CandleClosed
+	for i := len(CandleTimeframes) - 1; i >= 0; i-- {
+		timeframe := CandleTimeframes[i]
+		step := time.Duration(indicators.GetTimeframeMilliseconds(timeframe)) * time.Millisecond
+		openTime := previous.Timestamp.Truncate(step)
+		if price.Timestamp.Truncate(step).Equal(openTime) {
+			continue
+		}
+		closed = append(closed, CandleClosed{
+			Symbol:     price.Symbol,
+			Timeframe:  timeframe,
+			OpenTime:   openTime,
+			CloseTime:  openTime.Add(step),
+			ClosePrice: previous.ClosePrice,
+		})
+	}
+	return closed
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCorrelationWindow is the number of returns correlated when no window is given
+	DefaultCorrelationWindow = 30
+	// MaxCorrelationWindow caps the returns correlated per symbol
+	MaxCorrelationWindow = 365
+	// MaxCorrelationSymbols caps the symbols of one matrix
+	MaxCorrelationSymbols = 20
+
+	// minCorrelationReturns is the fewest overlapping returns a pair is correlated from
+	minCorrelationReturns = 3
+	// correlationTTL is how long a computed matrix is reused
+	correlationTTL = 5 * time.Minute
+	// correlationCacheSize bounds the cached matrices
+	correlationCacheSize = 500
+)
+
+// CorrelationMatrix holds the pairwise Pearson correlation of candle-to-candle close
+// returns, in the order Symbols are listed. A cell is nil when the two symbols have
+// too few candles in common or one of them didn't move; Observations holds the
+// number of returns each cell was computed from.
+type CorrelationMatrix struct {
+	Timeframe    string       `json:"timeframe"`
+	Window       int          `json:"window"`
+	Symbols      []string     `json:"symbols"`
+	Matrix       [][]*float64 `json:"matrix"`
+	Observations [][]int      `json:"observations"`
+	CalculatedAt time.Time    `json:"calculated_at"`
+}
+
+// CorrelationService computes return correlation matrices from stored candles
+type CorrelationService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	cache            *cache.MemoryCache
+	logger           *logrus.Logger
+}
+
+// NewCorrelationService creates a new correlation service
+func NewCorrelationService(priceHistoryRepo repositories.PriceHistoryRepository, logger *logrus.Logger) *CorrelationService {
+	return &CorrelationService{
+		priceHistoryRepo: priceHistoryRepo,
+		cache:            cache.NewMemoryCache(correlationCacheSize, time.Minute),
+		logger:           logger,
+	}
+}
+
+// Correlation correlates the returns of the last window candles of each symbol on
+// the timeframe; returns are matched by candle time so gaps in one symbol's history
+// don't shift the others
+func (cs *CorrelationService) Correlation(ctx context.Context, symbols []string, timeframe string, window int) (*CorrelationMatrix, error) {
+	if window > MaxCorrelationWindow {
+		window = MaxCorrelationWindow
+	}
+	if window <= 0 {
+		window = DefaultCorrelationWindow
+	}
+
+	cacheKey := fmt.Sprintf("correlation:%s:%d:%s", timeframe, window, strings.Join(symbols, ","))
+	if cached, ok := cs.cache.Get(cacheKey); ok {
+		return cached.(*CorrelationMatrix), nil
+	}
+
+	returns := make([]map[int64]float64, len(symbols))
+	for i, symbol := range symbols {
+		history, err := cs.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, window+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price history for %s: %w", symbol, err)
+		}
+		returns[i] = closeReturns(history)
+	}
+
+	matrix := &CorrelationMatrix{
+		Timeframe:    timeframe,
+		Window:       window,
+		Symbols:      symbols,
+		Matrix:       make([][]*float64, len(symbols)),
+		Observations: make([][]int, len(symbols)),
+		CalculatedAt: time.Now(),
+	}
+	for i := range symbols {
+		matrix.Matrix[i] = make([]*float64, len(symbols))
+		matrix.Observations[i] = make([]int, len(symbols))
+	}
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			value, observations := pearsonCorrelation(returns[i], returns[j])
+			matrix.Matrix[i][j], matrix.Matrix[j][i] = value, value
+			matrix.Observations[i][j], matrix.Observations[j][i] = observations, observations
+		}
+	}
+
+	cs.logger.WithFields(logrus.Fields{
+		"symbols":   len(symbols),
+		"timeframe": timeframe,
+		"window":    window,
+	}).Debug("Computed correlation matrix")
+
+	cs.cache.Set(cacheKey, matrix, correlationTTL)
+	return matrix, nil
+}
+
+// closeReturns returns the close-to-close return of each candle keyed by its unix
+// time, whatever order the history is in
+func closeReturns(history []entities.PriceHistory) map[int64]float64 {
+	sorted := make([]entities.PriceHistory, len(history))
+	copy(sorted, history)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	returns := make(map[int64]float64, len(sorted))
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].ClosePrice == 0 {
+			continue
+		}
+		returns[sorted[i].Timestamp.Unix()] = sorted[i].ClosePrice/sorted[i-1].ClosePrice - 1
+	}
+	return returns
+}
+
+// pearsonCorrelation correlates the returns two series have at the same times,
+// returning nil when there are too few of them or either series is flat
+func pearsonCorrelation(a, b map[int64]float64) (*float64, int) {
+	// Sum in time order so the result doesn't depend on map iteration
+	timestamps := make([]int64, 0, len(a))
+	for timestamp := range a {
+		if _, ok := b[timestamp]; ok {
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	xs := make([]float64, len(timestamps))
+	ys := make([]float64, len(timestamps))
+	for i, timestamp := range timestamps {
+		xs[i], ys[i] = a[timestamp], b[timestamp]
+	}
+
+	n := len(xs)
+	if n < minCorrelationReturns {
+		return nil, n
+	}
+
+	var meanX, meanY float64
+	for i := 0; i < n; i++ {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var covariance, varianceX, varianceY float64
+	for i := 0; i < n; i++ {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return nil, n
+	}
+
+	// Rounding to 6 decimals also absorbs floating point error, so the diagonal is exactly 1
+	value := math.Round(covariance/math.Sqrt(varianceX*varianceY)*1e6) / 1e6
+	return &value, n
+}
@@ -15,10 +15,15 @@ import (
 
 // CryptoDataService handles cryptocurrency data collection and management
 type CryptoDataService struct {
-	binanceClient          *external.BinanceClient
+	marketData             external.MarketDataProvider
 	cryptoRepo             repositories.CryptoCurrencyRepository
 	priceHistoryRepo       repositories.PriceHistoryRepository
 	technicalIndicatorRepo repositories.TechnicalIndicatorRepository
+	latencyRecorder        LatencyRecorder
+	candleEvents           *CandleEventBus
+	restrictions           *SymbolRestrictionService
+	candles                *CandleCloseTracker
+	buffer                 *PriceHistoryBuffer
 	logger                 *logrus.Logger
 
 	// Internal state
@@ -26,27 +31,57 @@ type CryptoDataService struct {
 	isCollecting   bool
 	stopChan       chan struct{}
 	updateInterval time.Duration
+
+	// pendingEvents are the candle closes seen while prices are buffered, published
+	// once the prices are stored
+	eventsMu      sync.Mutex
+	pendingEvents []CandleClosed
 }
 
 // NewCryptoDataService creates a new crypto data service
 func NewCryptoDataService(
-	binanceClient *external.BinanceClient,
+	marketData external.MarketDataProvider,
 	cryptoRepo repositories.CryptoCurrencyRepository,
 	priceHistoryRepo repositories.PriceHistoryRepository,
 	technicalIndicatorRepo repositories.TechnicalIndicatorRepository,
 	logger *logrus.Logger,
 ) *CryptoDataService {
 	return &CryptoDataService{
-		binanceClient:          binanceClient,
+		marketData:             marketData,
 		cryptoRepo:             cryptoRepo,
 		priceHistoryRepo:       priceHistoryRepo,
 		technicalIndicatorRepo: technicalIndicatorRepo,
+		candles:                NewCandleCloseTracker(),
 		logger:                 logger,
 		updateInterval:         30 * time.Second, // Default 30 seconds
 		stopChan:               make(chan struct{}),
 	}
 }
 
+// SetLatencyRecorder reports how long collected prices take to be stored after the
+// exchange observed them
+func (s *CryptoDataService) SetLatencyRecorder(recorder LatencyRecorder) {
+	s.latencyRecorder = recorder
+}
+
+// SetCandleEvents publishes a candle-close event on the bus whenever a collected price
+// falls in a later candle than the previous one of its symbol
+func (s *CryptoDataService) SetCandleEvents(bus *CandleEventBus) {
+	s.candleEvents = bus
+}
+
+// SetSymbolRestrictions leaves blacklisted symbols out of data collection
+func (s *CryptoDataService) SetSymbolRestrictions(restrictions *SymbolRestrictionService) {
+	s.restrictions = restrictions
+}
+
+// SetPriceHistoryBuffer batches the collected prices into bulk inserts instead of
+// storing each one as it is collected; the buffer is started and stopped with the
+// data collection
+func (s *CryptoDataService) SetPriceHistoryBuffer(buffer *PriceHistoryBuffer) {
+	s.buffer = buffer
+}
+
 // StartDataCollection starts the background data collection process
 func (s *CryptoDataService) StartDataCollection(ctx context.Context) error {
 	s.mu.Lock()
@@ -58,6 +93,9 @@ func (s *CryptoDataService) StartDataCollection(ctx context.Context) error {
 
 	s.isCollecting = true
 	s.logger.Info("Starting cryptocurrency data collection")
+	if s.buffer != nil {
+		s.buffer.Start(ctx)
+	}
 
 	// Start the collection goroutine
 	go s.collectDataLoop(ctx)
@@ -78,6 +116,9 @@ func (s *CryptoDataService) StopDataCollection() {
 	close(s.stopChan)
 	s.isCollecting = false
 	s.stopChan = make(chan struct{})
+	if s.buffer != nil {
+		s.buffer.Stop()
+	}
 }
 
 // IsCollecting returns whether data collection is active
@@ -138,6 +179,10 @@ func (s *CryptoDataService) collectAllData(ctx context.Context) {
 	semaphore := make(chan struct{}, 10) // Limit concurrent requests
 
 	for _, crypto := range cryptos {
+		if s.restrictions != nil && !s.restrictions.IsCollected(ctx, crypto.Symbol) {
+			continue
+		}
+
 		wg.Add(1)
 		go func(crypto entities.CryptoCurrency) {
 			defer wg.Done()
@@ -149,6 +194,7 @@ func (s *CryptoDataService) collectAllData(ctx context.Context) {
 	}
 
 	wg.Wait()
+	s.publishBufferedEvents(ctx)
 
 	duration := time.Since(start)
 	s.logger.WithFields(logrus.Fields{
@@ -160,7 +206,7 @@ func (s *CryptoDataService) collectAllData(ctx context.Context) {
 // collectCryptoData collects data for a specific cryptocurrency
 func (s *CryptoDataService) collectCryptoData(ctx context.Context, symbol string) {
 	// Get current price
-	ticker, err := s.binanceClient.GetTickerPrice(ctx, symbol)
+	ticker, err := s.marketData.GetTickerPrice(ctx, symbol)
 	if err != nil {
 		s.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get ticker price")
 		return
@@ -184,9 +230,30 @@ func (s *CryptoDataService) collectCryptoData(ctx context.Context, symbol string
 		ClosePrice: price,
 		Volume:     0, // We'll get this from klines later
 	}
+	if !ticker.EventTime.IsZero() {
+		priceHistory.EventTime = &ticker.EventTime
+	}
 
-	if err := s.priceHistoryRepo.Create(ctx, priceHistory); err != nil {
+	if s.buffer != nil {
+		s.buffer.Add(ctx, *priceHistory)
+	} else if err := s.priceHistoryRepo.Create(ctx, priceHistory); err != nil {
 		s.logger.WithError(err).WithField("symbol", symbol).Error("Failed to store price history")
+		return
+	}
+	// Buffered prices count as ingested once queued; the flush interval bounds the rest
+	observeLatency(s.latencyRecorder, LatencyStageIngest, ticker.EventTime, time.Now())
+
+	if s.candleEvents != nil {
+		events := s.candles.Observe(*priceHistory)
+		if s.buffer != nil {
+			s.eventsMu.Lock()
+			s.pendingEvents = append(s.pendingEvents, events...)
+			s.eventsMu.Unlock()
+		} else {
+			for _, event := range events {
+				s.candleEvents.Publish(ctx, event)
+			}
+		}
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -195,6 +262,25 @@ func (s *CryptoDataService) collectCryptoData(ctx context.Context, symbol string
 	}).Debug("Collected crypto data")
 }
 
+// publishBufferedEvents flushes the buffered prices when candles closed during the
+// cycle, so the subscribers read the closing prices, and then publishes the closes
+func (s *CryptoDataService) publishBufferedEvents(ctx context.Context) {
+	s.eventsMu.Lock()
+	events := s.pendingEvents
+	s.pendingEvents = nil
+	s.eventsMu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.buffer.Flush(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to flush price history before publishing candle closes")
+	}
+	for _, event := range events {
+		s.candleEvents.Publish(ctx, event)
+	}
+}
+
 // CollectHistoricalData collects historical data for a symbol
 func (s *CryptoDataService) CollectHistoricalData(ctx context.Context, symbol, interval string, limit int) error {
 	s.logger.WithFields(logrus.Fields{
@@ -203,8 +289,8 @@ func (s *CryptoDataService) CollectHistoricalData(ctx context.Context, symbol, i
 		"limit":    limit,
 	}).Info("Collecting historical data")
 
-	// Get klines from Binance
-	klines, err := s.binanceClient.GetKlines(ctx, symbol, interval, limit, nil, nil)
+	// Get klines from the exchange
+	klines, err := s.marketData.GetKlines(ctx, symbol, interval, limit, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get klines: %w", err)
 	}
@@ -212,30 +298,9 @@ func (s *CryptoDataService) CollectHistoricalData(ctx context.Context, symbol, i
 	// Convert klines to price history entities
 	var histories []entities.PriceHistory
 	for _, kline := range klines {
-		if len(kline) < 6 {
-			continue
+		if history, ok := klineHistory(symbol, interval, kline); ok {
+			histories = append(histories, history)
 		}
-
-		// Parse kline data
-		openTime := int64(kline[0].(float64))
-		open, _ := strconv.ParseFloat(kline[1].(string), 64)
-		high, _ := strconv.ParseFloat(kline[2].(string), 64)
-		low, _ := strconv.ParseFloat(kline[3].(string), 64)
-		close, _ := strconv.ParseFloat(kline[4].(string), 64)
-		volume, _ := strconv.ParseFloat(kline[5].(string), 64)
-
-		history := entities.PriceHistory{
-			Symbol:     symbol,
-			Timeframe:  interval,
-			Timestamp:  time.Unix(openTime/1000, 0),
-			OpenPrice:  open,
-			HighPrice:  high,
-			LowPrice:   low,
-			ClosePrice: close,
-			Volume:     volume,
-		}
-
-		histories = append(histories, history)
 	}
 
 	// Bulk insert historical data
@@ -257,8 +322,8 @@ func (s *CryptoDataService) CollectHistoricalData(ctx context.Context, symbol, i
 func (s *CryptoDataService) UpdateCryptocurrencyList(ctx context.Context) error {
 	s.logger.Info("Updating cryptocurrency list")
 
-	// Get exchange info from Binance
-	exchangeInfo, err := s.binanceClient.GetExchangeInfo(ctx)
+	// Get exchange info from the exchange
+	exchangeInfo, err := s.marketData.GetExchangeInfo(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get exchange info: %w", err)
 	}
@@ -310,7 +375,7 @@ func (s *CryptoDataService) UpdateCryptocurrencyList(ctx context.Context) error
 
 // GetCurrentPrice gets the current price for a symbol
 func (s *CryptoDataService) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
-	ticker, err := s.binanceClient.GetTickerPrice(ctx, symbol)
+	ticker, err := s.marketData.GetTickerPrice(ctx, symbol)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get current price: %w", err)
 	}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// Sections that can be embedded in a crypto detail response
+const (
+	DetailIncludeIndicators = "indicators"
+	DetailIncludeStats      = "stats"
+	DetailIncludeAlerts     = "alerts"
+)
+
+// stats24hCandles is the number of hourly candles covering the last 24 hours
+const stats24hCandles = 24
+
+// CryptoStats24h summarizes price action over the last 24 hourly candles
+type CryptoStats24h struct {
+	Open          float64 `json:"open"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Close         float64 `json:"close"`
+	Volume        float64 `json:"volume"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// CryptoDetailOptions selects the sections to embed in a crypto detail response
+type CryptoDetailOptions struct {
+	Indicators bool
+	Stats      bool
+	Alerts     bool
+	Timeframe  string    // Timeframe of the indicator snapshot
+	UserID     uuid.UUID // Owner of the counted alerts
+}
+
+// ParseDetailIncludes reads a comma separated include list such as "indicators,alerts"
+func ParseDetailIncludes(include string) (CryptoDetailOptions, error) {
+	var opts CryptoDetailOptions
+	for _, section := range strings.Split(include, ",") {
+		switch strings.TrimSpace(section) {
+		case "":
+		case DetailIncludeIndicators:
+			opts.Indicators = true
+		case DetailIncludeStats:
+			opts.Stats = true
+		case DetailIncludeAlerts:
+			opts.Alerts = true
+		default:
+			return opts, fmt.Errorf("unknown include %q, expected %s, %s or %s",
+				section, DetailIncludeIndicators, DetailIncludeStats, DetailIncludeAlerts)
+		}
+	}
+	return opts, nil
+}
+
+// CryptoDetailExtras holds the optional sections of a crypto detail response
+type CryptoDetailExtras struct {
+	Indicators   map[string]*entities.TechnicalIndicator `json:"indicators,omitempty"`
+	Stats24h     *CryptoStats24h                         `json:"stats_24h,omitempty"`
+	ActiveAlerts *int                                    `json:"active_alerts,omitempty"`
+}
+
+// CryptoDetailService assembles the optional sections of the crypto detail endpoint
+type CryptoDetailService struct {
+	indicatorService *TechnicalIndicatorService
+	priceHistoryRepo repositories.PriceHistoryRepository
+	alertRepo        repositories.AlertRepository
+}
+
+// NewCryptoDetailService creates a new crypto detail service
+func NewCryptoDetailService(
+	indicatorService *TechnicalIndicatorService,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	alertRepo repositories.AlertRepository,
+) *CryptoDetailService {
+	return &CryptoDetailService{
+		indicatorService: indicatorService,
+		priceHistoryRepo: priceHistoryRepo,
+		alertRepo:        alertRepo,
+	}
+}
+
+// GetDetailExtras loads the requested sections concurrently; any failure fails the
+// whole request rather than returning a partially filled response
+func (cds *CryptoDetailService) GetDetailExtras(ctx context.Context, symbol string, opts CryptoDetailOptions) (*CryptoDetailExtras, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	extras := &CryptoDetailExtras{}
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	if opts.Indicators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			indicators, err := cds.indicatorService.GetLatestIndicators(ctx, symbol, opts.Timeframe)
+			if err != nil {
+				fail(fmt.Errorf("failed to get indicators: %w", err))
+				return
+			}
+			extras.Indicators = indicators
+		}()
+	}
+
+	if opts.Stats {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			history, err := cds.priceHistoryRepo.GetBySymbol(ctx, symbol, "1h", stats24hCandles)
+			if err != nil {
+				fail(fmt.Errorf("failed to get price history: %w", err))
+				return
+			}
+			extras.Stats24h = Stats24h(history)
+		}()
+	}
+
+	if opts.Alerts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			alerts, err := userAlerts(ctx, cds.alertRepo, opts.UserID)
+			if err != nil {
+				fail(err)
+				return
+			}
+			active := 0
+			for _, alert := range alerts {
+				if alert.Enabled && strings.EqualFold(alert.Symbol, symbol) {
+					active++
+				}
+			}
+			extras.ActiveAlerts = &active
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return extras, nil
+}
+
+// Stats24h summarizes hourly candles ordered newest first, as the price history
+// repository returns them; it returns nil when there is no history
+func Stats24h(history []entities.PriceHistory) *CryptoStats24h {
+	if len(history) == 0 {
+		return nil
+	}
+
+	newest, oldest := history[0], history[len(history)-1]
+	stats := &CryptoStats24h{
+		Open:  oldest.OpenPrice,
+		High:  newest.HighPrice,
+		Low:   newest.LowPrice,
+		Close: newest.ClosePrice,
+	}
+	for _, candle := range history {
+		if candle.HighPrice > stats.High {
+			stats.High = candle.HighPrice
+		}
+		if candle.LowPrice < stats.Low {
+			stats.Low = candle.LowPrice
+		}
+		stats.Volume += candle.Volume
+	}
+
+	stats.Change = stats.Close - stats.Open
+	if stats.Open != 0 {
+		stats.ChangePercent = stats.Change / stats.Open * 100
+	}
+	return stats
+}
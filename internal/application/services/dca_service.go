@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// Frequencies a DCA simulation can buy at
+const (
+	DCAFrequencyDaily   = "daily"
+	DCAFrequencyWeekly  = "weekly"
+	DCAFrequencyMonthly = "monthly"
+)
+
+const (
+	// MaxDCASimulationRange caps the period one simulation replays
+	MaxDCASimulationRange = 10 * 365 * 24 * time.Hour
+	// dcaTimeframe is the candle the simulation buys at the close of
+	dcaTimeframe = "1d"
+)
+
+// DCASimulationRequest describes a recurring purchase of Amount in quote currency
+// on symbol, every Frequency from StartDate to EndDate
+type DCASimulationRequest struct {
+	Symbol    string
+	Amount    float64
+	Frequency string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// Validate checks the symbol, amount, frequency and date range
+func (r *DCASimulationRequest) Validate() error {
+	switch {
+	case r.Symbol == "" || len(r.Symbol) > 20:
+		return dcaValidationError("symbol", "must be between 1 and 20 characters")
+	case r.Amount <= 0 || math.IsInf(r.Amount, 0) || math.IsNaN(r.Amount):
+		return dcaValidationError("amount", "must be greater than zero")
+	case r.Frequency != DCAFrequencyDaily && r.Frequency != DCAFrequencyWeekly && r.Frequency != DCAFrequencyMonthly:
+		return dcaValidationError("frequency", fmt.Sprintf("unsupported frequency %q", r.Frequency))
+	case !r.EndDate.After(r.StartDate):
+		return dcaValidationError("end_date", "must be after start_date")
+	case r.EndDate.Sub(r.StartDate) > MaxDCASimulationRange:
+		return dcaValidationError("end_date", "range must be at most 10 years")
+	}
+	return nil
+}
+
+func dcaValidationError(field, message string) error {
+	return &entities.ValidationError{Entity: "dca_simulation", Field: field, Message: message}
+}
+
+// DCAPoint is the state of the position right after one purchase
+type DCAPoint struct {
+	Date     time.Time `json:"date"`
+	Price    float64   `json:"price"`
+	Bought   float64   `json:"bought"`
+	Invested float64   `json:"invested"`
+	Holdings float64   `json:"holdings"`
+	Value    float64   `json:"value"`
+	ROI      float64   `json:"roi"`
+}
+
+// DCASimulationResult summarizes a simulation; ROI is in percent and the final
+// value uses the last close in the range
+type DCASimulationResult struct {
+	Symbol      string     `json:"symbol"`
+	Frequency   string     `json:"frequency"`
+	Amount      float64    `json:"amount"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     time.Time  `json:"end_date"`
+	Purchases   int        `json:"purchases"`
+	Invested    float64    `json:"invested"`
+	Holdings    float64    `json:"holdings"`
+	AverageCost float64    `json:"average_cost"`
+	FinalPrice  float64    `json:"final_price"`
+	FinalValue  float64    `json:"final_value"`
+	ROI         float64    `json:"roi"`
+	Points      []DCAPoint `json:"points"`
+}
+
+// DCASimulationService replays stored daily candles to simulate dollar-cost averaging
+type DCASimulationService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+}
+
+// NewDCASimulationService creates a new DCA simulation service
+func NewDCASimulationService(priceHistoryRepo repositories.PriceHistoryRepository) *DCASimulationService {
+	return &DCASimulationService{
+		priceHistoryRepo: priceHistoryRepo,
+	}
+}
+
+// Simulate buys at the close of the first daily candle on or after each scheduled
+// date; scheduled dates with no candle left in the range are skipped
+func (ds *DCASimulationService) Simulate(ctx context.Context, req DCASimulationRequest) (*DCASimulationResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	candles, err := ds.priceHistoryRepo.GetByTimeRange(ctx, req.Symbol, dcaTimeframe, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+	if len(candles) == 0 {
+		return nil, dcaValidationError("symbol", fmt.Sprintf("no price history for %s in the requested range", req.Symbol))
+	}
+
+	result := &DCASimulationResult{
+		Symbol:    req.Symbol,
+		Frequency: req.Frequency,
+		Amount:    req.Amount,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Points:    make([]DCAPoint, 0),
+	}
+
+	next := 0
+	for i := 0; ; i++ {
+		buyDate := dcaScheduleDate(req.StartDate, req.Frequency, i)
+		if buyDate.After(req.EndDate) {
+			break
+		}
+		for next < len(candles) && candles[next].Timestamp.Before(buyDate) {
+			next++
+		}
+		if next == len(candles) {
+			break
+		}
+
+		candle := candles[next]
+		if candle.ClosePrice <= 0 {
+			continue
+		}
+		// Several scheduled dates can land on the same candle after a gap; buy once
+		if n := len(result.Points); n > 0 && result.Points[n-1].Date.Equal(candle.Timestamp) {
+			continue
+		}
+
+		bought := req.Amount / candle.ClosePrice
+		result.Invested += req.Amount
+		result.Holdings += bought
+		value := result.Holdings * candle.ClosePrice
+		result.Points = append(result.Points, DCAPoint{
+			Date:     candle.Timestamp,
+			Price:    candle.ClosePrice,
+			Bought:   bought,
+			Invested: result.Invested,
+			Holdings: result.Holdings,
+			Value:    value,
+			ROI:      dcaROI(value, result.Invested),
+		})
+	}
+
+	result.Purchases = len(result.Points)
+	result.FinalPrice = candles[len(candles)-1].ClosePrice
+	result.FinalValue = result.Holdings * result.FinalPrice
+	result.ROI = dcaROI(result.FinalValue, result.Invested)
+	if result.Holdings > 0 {
+		result.AverageCost = result.Invested / result.Holdings
+	}
+
+	return result, nil
+}
+
+// dcaScheduleDate returns the nth scheduled purchase, counted from the start so
+// monthly purchases don't drift after short months
+func dcaScheduleDate(start time.Time, frequency string, n int) time.Time {
+	switch frequency {
+	case DCAFrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case DCAFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+func dcaROI(value, invested float64) float64 {
+	if invested == 0 {
+		return 0
+	}
+	return math.Round((value/invested-1)*100*1e4) / 1e4
+}
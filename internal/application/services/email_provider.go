@@ -0,0 +1,387 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// SMTPProviderName is the email provider delivering through an SMTP server
+const SMTPProviderName = "smtp"
+
+// EmailMessage is a rendered email ready to hand to a transport
+type EmailMessage struct {
+	To       string
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+// EmailSender hands rendered emails to a mail transport
+type EmailSender interface {
+	SendEmail(ctx context.Context, message *EmailMessage) error
+}
+
+// SMTPConfig configures SMTP delivery
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Defaults to From
+	Password string
+	From     string
+
+	// MaxAttempts bounds the sends of one email; transient failures are retried
+	// after Backoff, doubled on each retry
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// SMTPProviderFactory builds the SMTP email provider. Selection settings may
+// override host, port, from and username; the password only comes from cfg.
+func SMTPProviderFactory(cfg SMTPConfig, users repositories.UserRepository) ProviderFactory {
+	return func(settings map[string]string) (NotificationProvider, error) {
+		cfg := cfg
+		if host := settings["host"]; host != "" {
+			cfg.Host = host
+		}
+		if port := settings["port"]; port != "" {
+			parsed, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q", port)
+			}
+			cfg.Port = parsed
+		}
+		if from := settings["from"]; from != "" {
+			cfg.From = from
+		}
+		if username := settings["username"]; username != "" {
+			cfg.Username = username
+		}
+
+		if cfg.Host == "" || cfg.Port <= 0 {
+			return nil, errors.New("SMTP host and port are required")
+		}
+		if _, err := mail.ParseAddress(cfg.From); err != nil {
+			return nil, fmt.Errorf("invalid from address %q", cfg.From)
+		}
+
+		provider := NewEmailProvider(SMTPProviderName, NewSMTPSender(cfg), users)
+		provider.SetRetry(cfg.MaxAttempts, cfg.Backoff)
+		return provider, nil
+	}
+}
+
+// EmailProvider renders notifications as HTML emails and sends them to the
+// address of the user's account
+type EmailProvider struct {
+	name        string
+	sender      EmailSender
+	users       repositories.UserRepository
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewEmailProvider creates an email provider registered as name
+func NewEmailProvider(name string, sender EmailSender, users repositories.UserRepository) *EmailProvider {
+	return &EmailProvider{
+		name:        name,
+		sender:      sender,
+		users:       users,
+		maxAttempts: 3,
+		backoff:     time.Second,
+	}
+}
+
+// SetRetry sets how many times an email is sent before the delivery fails and the
+// wait before the first retry
+func (p *EmailProvider) SetRetry(maxAttempts int, backoff time.Duration) {
+	if maxAttempts > 0 {
+		p.maxAttempts = maxAttempts
+	}
+	if backoff > 0 {
+		p.backoff = backoff
+	}
+}
+
+func (p *EmailProvider) Name() string {
+	return p.name
+}
+
+// Send emails the notification, retrying transient failures within the delivery
+// timeout. Rejections by the server (5xx replies) are not retried.
+func (p *EmailProvider) Send(ctx context.Context, notification *QueuedNotification) error {
+	user, err := p.users.GetByID(ctx, notification.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get recipient: %w", err)
+	}
+	if _, err := mail.ParseAddress(user.Email); err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	message, err := renderEmail(user.Name, user.Email, notification)
+	if err != nil {
+		return err
+	}
+
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		err = p.sender.SendEmail(ctx, message)
+		if err == nil || attempt >= p.maxAttempts || isPermanentEmailError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+		backoff *= 2
+	}
+}
+
+// HealthCheck checks the sender when it can be checked
+func (p *EmailProvider) HealthCheck(ctx context.Context) error {
+	if checker, ok := p.sender.(interface{ HealthCheck(context.Context) error }); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// isPermanentEmailError reports whether the server rejected the email for good
+func isPermanentEmailError(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
+
+// emailDetailFields are the notification data shown under the message, in order
+var emailDetailFields = []struct {
+	key   string
+	label string
+}{
+	{"symbol", "Symbol"},
+	{"alert_type", "Alert type"},
+	{"condition", "Condition"},
+	{"target_value", "Target"},
+	{"current_value", "Current value"},
+	{"timeframe", "Timeframe"},
+}
+
+type emailDetail struct {
+	Label string
+	Value string
+}
+
+var emailTemplate = template.Must(template.New("notification").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, Helvetica, sans-serif; color: #1f2937; background: #f9fafb; padding: 24px;">
+  <div style="max-width: 560px; margin: 0 auto; background: #ffffff; border-radius: 8px; padding: 24px;">
+    <h2 style="margin-top: 0;">{{.Title}}</h2>
+    {{if .Name}}<p>Hi {{.Name}},</p>{{end}}
+    <p>{{.Message}}</p>
+    {{if .Details}}<table style="border-collapse: collapse; width: 100%;">
+      {{range .Details}}<tr>
+        <td style="padding: 6px 0; color: #6b7280;">{{.Label}}</td>
+        <td style="padding: 6px 0; text-align: right; font-weight: bold;">{{.Value}}</td>
+      </tr>
+      {{end}}
+    </table>{{end}}
+    <p style="color: #9ca3af; font-size: 12px; margin-top: 24px;">You can turn off email notifications in your PriceGuard settings.</p>
+  </div>
+</body>
+</html>
+`))
+
+// renderEmail builds the email for a notification
+func renderEmail(name, to string, notification *QueuedNotification) (*EmailMessage, error) {
+	details := make([]emailDetail, 0, len(emailDetailFields))
+	for _, field := range emailDetailFields {
+		if value, ok := notification.Data[field.key]; ok && value != nil {
+			details = append(details, emailDetail{Label: field.label, Value: fmt.Sprint(value)})
+		}
+	}
+
+	var body bytes.Buffer
+	err := emailTemplate.Execute(&body, map[string]interface{}{
+		"Name":    name,
+		"Title":   notification.Title,
+		"Message": notification.Message,
+		"Details": details,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	return &EmailMessage{
+		To:       to,
+		Subject:  notification.Title,
+		HTMLBody: body.String(),
+		TextBody: notification.Message,
+	}, nil
+}
+
+// SMTPSender sends emails through an SMTP server, upgrading to TLS with STARTTLS
+// when offered, or over implicit TLS on port 465
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates an SMTP sender
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	if cfg.Username == "" {
+		cfg.Username = cfg.From
+	}
+	return &SMTPSender{config: cfg}
+}
+
+// SendEmail delivers message in a single SMTP session
+func (s *SMTPSender) SendEmail(ctx context.Context, message *EmailMessage) error {
+	client, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.config.Password != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+			if err := client.Auth(auth); err != nil {
+				return fmt.Errorf("smtp auth failed: %w", err)
+			}
+		}
+	}
+
+	data, err := buildMIMEMessage(s.config.From, message)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Mail(s.config.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp server did not accept the email: %w", err)
+	}
+	return client.Quit()
+}
+
+// HealthCheck opens a session and checks the server answers
+func (s *SMTPSender) HealthCheck(ctx context.Context) error {
+	client, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Noop(); err != nil {
+		return fmt.Errorf("smtp NOOP failed: %w", err)
+	}
+	return client.Quit()
+}
+
+// connect opens an SMTP session bounded by ctx
+func (s *SMTPSender) connect(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	tlsConfig := &tls.Config{ServerName: s.config.Host}
+
+	var conn net.Conn
+	var err error
+	if s.config.Port == 465 {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// buildMIMEMessage encodes message as a multipart/alternative email with a plain
+// text and an HTML part
+func buildMIMEMessage(from string, message *EmailMessage) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", message.TextBody},
+		{"text/html; charset=UTF-8", message.HTMLBody},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	// Header values come from user data, so line breaks are dropped to keep them
+	// from injecting headers
+	headerValue := strings.NewReplacer("\r", "", "\n", " ").Replace
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&msg, "To: %s\r\n", headerValue(message.To))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", headerValue(message.Subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
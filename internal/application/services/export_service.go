@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportFormat selects the file produced by ExportService
+type ExportFormat string
+
+const (
+	// ExportFormatJSON is a full account export (profile, settings, alerts, notifications)
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatCSV is a spreadsheet-friendly list of the user's alerts
+	ExportFormatCSV ExportFormat = "csv"
+
+	// ExportPrefix is the storage prefix holding generated exports
+	ExportPrefix = "exports/"
+
+	exportPageSize = 500
+)
+
+// ExportResult points at a generated export
+type ExportResult struct {
+	Key       string       `json:"key"`
+	Format    ExportFormat `json:"format"`
+	URL       string       `json:"url"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// userDataExport is the document written for JSON exports
+type userDataExport struct {
+	ExportedAt    time.Time               `json:"exported_at"`
+	User          *entities.User          `json:"user"`
+	Settings      *entities.UserSettings  `json:"settings,omitempty"`
+	Alerts        []entities.Alert        `json:"alerts"`
+	Notifications []entities.Notification `json:"notifications"`
+}
+
+// ExportService writes user exports to object storage and hands out presigned links
+type ExportService struct {
+	userRepo         repositories.UserRepository
+	settingsRepo     repositories.UserSettingsRepository
+	alertRepo        repositories.AlertRepository
+	notificationRepo repositories.NotificationRepository
+	storage          storage.ObjectStorage
+	linkExpiry       time.Duration
+	clock            clock.Clock
+	logger           *logrus.Logger
+}
+
+// NewExportService creates a new export service
+func NewExportService(
+	userRepo repositories.UserRepository,
+	settingsRepo repositories.UserSettingsRepository,
+	alertRepo repositories.AlertRepository,
+	notificationRepo repositories.NotificationRepository,
+	objectStorage storage.ObjectStorage,
+	linkExpiry time.Duration,
+	logger *logrus.Logger,
+) *ExportService {
+	if linkExpiry <= 0 {
+		linkExpiry = time.Hour
+	}
+	return &ExportService{
+		userRepo:         userRepo,
+		settingsRepo:     settingsRepo,
+		alertRepo:        alertRepo,
+		notificationRepo: notificationRepo,
+		storage:          objectStorage,
+		linkExpiry:       linkExpiry,
+		clock:            clock.New(),
+		logger:           logger,
+	}
+}
+
+// SetClock replaces the clock used to name and timestamp exports
+func (es *ExportService) SetClock(c clock.Clock) {
+	es.clock = c
+}
+
+// ParseExportFormat validates a user supplied export format, defaulting to JSON
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(value)) {
+	case "", ExportFormatJSON:
+		return ExportFormatJSON, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", value)
+	}
+}
+
+// ExportUserData builds an export for the user, uploads it and returns a presigned link
+func (es *ExportService) ExportUserData(ctx context.Context, userID uuid.UUID, format ExportFormat) (*ExportResult, error) {
+	now := es.clock.Now().UTC()
+
+	var (
+		data        []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case ExportFormatJSON:
+		data, err = es.buildJSON(ctx, userID, now)
+		contentType = "application/json"
+	case ExportFormatCSV:
+		data, err = es.buildAlertsCSV(ctx, userID)
+		contentType = "text/csv"
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%s/%s.%s", ExportPrefix, userID, now.Format("20060102T150405Z"), format)
+	if err := es.storage.PutObject(ctx, key, data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store export: %w", err)
+	}
+
+	url, err := es.storage.PresignGetURL(key, es.linkExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign export URL: %w", err)
+	}
+
+	es.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"format":  format,
+		"bytes":   len(data),
+	}).Info("User export generated")
+
+	return &ExportResult{
+		Key:       key,
+		Format:    format,
+		URL:       url,
+		ExpiresAt: now.Add(es.linkExpiry),
+	}, nil
+}
+
+func (es *ExportService) buildJSON(ctx context.Context, userID uuid.UUID, now time.Time) ([]byte, error) {
+	user, err := es.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	user.ResolveAvatarURL()
+
+	export := userDataExport{
+		ExportedAt: now,
+		User:       user,
+	}
+
+	// Settings are optional; a user without them still gets a valid export
+	if settings, err := es.settingsRepo.GetByUserID(ctx, userID); err == nil {
+		export.Settings = settings
+	}
+
+	if export.Alerts, err = es.allAlerts(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Notifications, err = es.allNotifications(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+func (es *ExportService) buildAlertsCSV(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	alerts, err := es.allAlerts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{
+		"id", "symbol", "alert_type", "condition_type", "target_value",
+		"timeframe", "enabled", "notify_via", "triggered_at", "created_at",
+	})
+
+	for _, alert := range alerts {
+		triggeredAt := ""
+		if alert.TriggeredAt != nil {
+			triggeredAt = alert.TriggeredAt.UTC().Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			alert.ID.String(),
+			alert.Symbol,
+			alert.AlertType,
+			alert.ConditionType,
+			strconv.FormatFloat(alert.TargetValue, 'f', -1, 64),
+			alert.Timeframe,
+			strconv.FormatBool(alert.Enabled),
+			strings.Join(alert.NotifyVia, "|"),
+			triggeredAt,
+			alert.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (es *ExportService) allAlerts(ctx context.Context, userID uuid.UUID) ([]entities.Alert, error) {
+	alerts := make([]entities.Alert, 0)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := es.alertRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load alerts: %w", err)
+		}
+		alerts = append(alerts, page...)
+		if len(page) < exportPageSize {
+			return alerts, nil
+		}
+	}
+}
+
+func (es *ExportService) allNotifications(ctx context.Context, userID uuid.UUID) ([]entities.Notification, error) {
+	notifications := make([]entities.Notification, 0)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := es.notificationRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load notifications: %w", err)
+		}
+		notifications = append(notifications, page...)
+		if len(page) < exportPageSize {
+			return notifications, nil
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+const (
+	// FCMProviderName is the push provider delivering through Firebase Cloud Messaging
+	FCMProviderName = "fcm"
+
+	fcmBaseURL = "https://fcm.googleapis.com"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+
+	// fcmConcurrency caps the sends in flight for one notification; the HTTP v1
+	// API takes one token per request, so a batch is sent as parallel requests
+	fcmConcurrency = 10
+)
+
+// FCMConfig configures Firebase Cloud Messaging
+type FCMConfig struct {
+	ProjectID string
+	// CredentialsFile is a service account key; the application default
+	// credentials are used when it is empty
+	CredentialsFile string
+}
+
+// FCMProviderFactory builds the FCM push provider. Selection settings may override
+// project_id.
+func FCMProviderFactory(cfg FCMConfig, devices repositories.DeviceTokenRepository, logger *logrus.Logger) ProviderFactory {
+	return func(settings map[string]string) (NotificationProvider, error) {
+		projectID := cfg.ProjectID
+		if override := settings["project_id"]; override != "" {
+			projectID = override
+		}
+		if projectID == "" {
+			return nil, errors.New("FCM project ID is required")
+		}
+
+		ctx := context.Background()
+		var tokenSource oauth2.TokenSource
+		if cfg.CredentialsFile != "" {
+			key, err := os.ReadFile(cfg.CredentialsFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+			}
+			credentials, err := google.CredentialsFromJSON(ctx, key, fcmScope)
+			if err != nil {
+				return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+			}
+			tokenSource = credentials.TokenSource
+		} else {
+			defaultSource, err := google.DefaultTokenSource(ctx, fcmScope)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find FCM credentials: %w", err)
+			}
+			tokenSource = defaultSource
+		}
+
+		return NewFCMProvider(projectID, tokenSource, devices, logger), nil
+	}
+}
+
+// FCMProvider sends push notifications to every registered device of a user and
+// prunes the tokens FCM reports as no longer valid
+type FCMProvider struct {
+	baseURL     string
+	projectID   string
+	tokenSource oauth2.TokenSource
+	httpClient  *http.Client
+	devices     repositories.DeviceTokenRepository
+	logger      *logrus.Logger
+}
+
+// NewFCMProvider creates an FCM provider for projectID authenticated by tokenSource
+func NewFCMProvider(projectID string, tokenSource oauth2.TokenSource, devices repositories.DeviceTokenRepository, logger *logrus.Logger) *FCMProvider {
+	return &FCMProvider{
+		baseURL:     fcmBaseURL,
+		projectID:   projectID,
+		tokenSource: oauth2.ReuseTokenSource(nil, tokenSource),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		devices:     devices,
+		logger:      logger,
+	}
+}
+
+// SetBaseURL points the provider at another endpoint (e.g. for tests)
+func (p *FCMProvider) SetBaseURL(baseURL string) {
+	p.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SetHTTPClient replaces the HTTP client (e.g. for tests)
+func (p *FCMProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+func (p *FCMProvider) Name() string {
+	return FCMProviderName
+}
+
+// HealthCheck checks an access token can be obtained
+func (p *FCMProvider) HealthCheck(ctx context.Context) error {
+	if _, err := p.tokenSource.Token(); err != nil {
+		return fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+	return nil
+}
+
+// fcmResult is the outcome of sending to one device token
+type fcmResult struct {
+	token   string
+	err     error
+	invalid bool // FCM rejected the token itself
+}
+
+// Send pushes the notification to all of the user's devices. It succeeds when at
+// least one device got it, or when there was no valid device to send to; invalid
+// tokens are deleted so later notifications skip them.
+func (p *FCMProvider) Send(ctx context.Context, notification *QueuedNotification) error {
+	devices, err := p.devices.GetByUserID(ctx, notification.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get device tokens: %w", err)
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	accessToken, err := p.tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+
+	results := make([]fcmResult, len(devices))
+	slots := make(chan struct{}, fcmConcurrency)
+	var wg sync.WaitGroup
+	for i, device := range devices {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, token string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = p.sendToToken(ctx, accessToken, token, notification)
+		}(i, device.Token)
+	}
+	wg.Wait()
+
+	var invalid []string
+	var errs []error
+	delivered := 0
+	for _, result := range results {
+		switch {
+		case result.invalid:
+			invalid = append(invalid, result.token)
+		case result.err != nil:
+			errs = append(errs, result.err)
+		default:
+			delivered++
+		}
+	}
+
+	if len(invalid) > 0 {
+		pruned, err := p.devices.DeleteByTokens(ctx, invalid)
+		if err != nil {
+			p.logger.WithError(err).Error("Failed to prune invalid FCM tokens")
+		} else {
+			p.logger.WithFields(logrus.Fields{
+				"user_id": notification.UserID,
+				"pruned":  pruned,
+			}).Info("Pruned invalid FCM tokens")
+		}
+	}
+
+	if delivered == 0 && len(errs) > 0 {
+		return fmt.Errorf("push failed on all %d devices: %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+type fcmMessage struct {
+	Message struct {
+		Token        string            `json:"token"`
+		Notification fcmNotification   `json:"notification"`
+		Data         map[string]string `json:"data,omitempty"`
+	} `json:"message"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// sendToToken sends one message through the HTTP v1 API
+func (p *FCMProvider) sendToToken(ctx context.Context, accessToken *oauth2.Token, token string, notification *QueuedNotification) fcmResult {
+	result := fcmResult{token: token}
+
+	var message fcmMessage
+	message.Message.Token = token
+	message.Message.Notification = fcmNotification{Title: notification.Title, Body: notification.Message}
+	message.Message.Data = fcmData(notification)
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		result.err = fmt.Errorf("failed to encode FCM message: %w", err)
+		return result
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.baseURL, p.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	accessToken.SetAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		result.err = fmt.Errorf("FCM request failed: %w", err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return result
+	}
+
+	var errResp fcmErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+	for _, detail := range errResp.Error.Details {
+		// UNREGISTERED: the app was uninstalled or the token expired. INVALID_ARGUMENT:
+		// the token is malformed, since the rest of the message is built here.
+		if detail.ErrorCode == "UNREGISTERED" || detail.ErrorCode == "INVALID_ARGUMENT" {
+			result.invalid = true
+		}
+	}
+	result.err = fmt.Errorf("FCM returned status %d: %s", resp.StatusCode, errResp.Error.Message)
+	return result
+}
+
+// fcmData flattens the notification data to the string map FCM requires
+func fcmData(notification *QueuedNotification) map[string]string {
+	data := map[string]string{
+		"notification_id": notification.ID.String(),
+		"type":            notification.Type,
+	}
+	for key, value := range notification.Data {
+		if value == nil {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			data[key] = s
+			continue
+		}
+		if encoded, err := json.Marshal(value); err == nil {
+			data[key] = string(encoded)
+		}
+	}
+	return data
+}
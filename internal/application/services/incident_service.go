@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// SystemAlertBanner is the system_alert type broadcast when a banner is published
+	SystemAlertBanner = "incident_banner"
+	// SystemAlertBannerRevoked is the system_alert type broadcast when a banner is taken down early
+	SystemAlertBannerRevoked = "incident_banner_revoked"
+)
+
+var (
+	// ErrBannerNotFound is returned when revoking a banner that doesn't exist
+	ErrBannerNotFound = entities.NewDomainError(entities.ErrNotFound, "banner not found")
+	// ErrBannerNotActive is returned when revoking a banner that already expired or was revoked
+	ErrBannerNotActive = entities.NewDomainError(entities.ErrConflict, "banner is not active")
+)
+
+// IncidentService publishes system-wide banners: they are persisted so every user sees
+// them pinned in-app until expiry, and broadcast live to connected WebSocket clients
+type IncidentService struct {
+	bannerRepo repositories.SystemBannerRepository
+	wsService  AlertWebSocketService
+	logger     *logrus.Logger
+	clock      clock.Clock
+}
+
+// NewIncidentService creates a new incident service
+func NewIncidentService(
+	bannerRepo repositories.SystemBannerRepository,
+	wsService AlertWebSocketService,
+	logger *logrus.Logger,
+) *IncidentService {
+	return &IncidentService{
+		bannerRepo: bannerRepo,
+		wsService:  wsService,
+		logger:     logger,
+		clock:      clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to validate and expire banners
+func (is *IncidentService) SetClock(c clock.Clock) {
+	is.clock = c
+}
+
+// PublishBanner validates, stores and broadcasts a new banner
+func (is *IncidentService) PublishBanner(ctx context.Context, banner *entities.SystemBanner) error {
+	now := is.clock.Now()
+	if banner.Severity == "" {
+		banner.Severity = "info"
+	}
+	if err := banner.Validate(now); err != nil {
+		return err
+	}
+	banner.CreatedAt = now
+
+	if err := is.bannerRepo.Create(ctx, banner); err != nil {
+		return fmt.Errorf("failed to create banner: %w", err)
+	}
+
+	is.broadcast(ctx, SystemAlertBanner, banner)
+
+	is.logger.WithFields(logrus.Fields{
+		"banner_id":  banner.ID,
+		"severity":   banner.Severity,
+		"expires_at": banner.ExpiresAt,
+		"created_by": banner.CreatedBy,
+	}).Info("System banner published")
+
+	return nil
+}
+
+// RevokeBanner takes an active banner down before it expires
+func (is *IncidentService) RevokeBanner(ctx context.Context, id uuid.UUID) (*entities.SystemBanner, error) {
+	banner, err := is.bannerRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBannerNotFound
+		}
+		return nil, fmt.Errorf("failed to get banner: %w", err)
+	}
+
+	now := is.clock.Now()
+	if !banner.IsActive(now) {
+		return nil, ErrBannerNotActive
+	}
+
+	if err := is.bannerRepo.Revoke(ctx, id, now); err != nil {
+		return nil, fmt.Errorf("failed to revoke banner: %w", err)
+	}
+	banner.RevokedAt = &now
+
+	is.broadcast(ctx, SystemAlertBannerRevoked, banner)
+
+	is.logger.WithField("banner_id", id).Info("System banner revoked")
+	return banner, nil
+}
+
+// GetActiveBanners returns the banners that should currently be pinned
+func (is *IncidentService) GetActiveBanners(ctx context.Context) ([]entities.SystemBanner, error) {
+	banners, err := is.bannerRepo.GetActive(ctx, is.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active banners: %w", err)
+	}
+	return banners, nil
+}
+
+// broadcast pushes the banner to every connected client; the banner is already
+// persisted, so a failed broadcast is only logged
+func (is *IncidentService) broadcast(ctx context.Context, alertType string, banner *entities.SystemBanner) {
+	if is.wsService == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"banner_id":  banner.ID,
+		"severity":   banner.Severity,
+		"expires_at": banner.ExpiresAt,
+		"revoked_at": banner.RevokedAt,
+		"pinned":     alertType == SystemAlertBanner,
+	}
+	if err := is.wsService.BroadcastSystemAlert(ctx, alertType, banner.Title, banner.Message, data); err != nil {
+		is.logger.WithError(err).WithField("banner_id", banner.ID).Error("Failed to broadcast system banner")
+	}
+}
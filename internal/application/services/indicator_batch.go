@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// IndicatorBatch holds the indicators calculated under a context returned by
+// TechnicalIndicatorService.BeginBatch until FlushBatch stores them. Calculations
+// may add to it concurrently.
+type IndicatorBatch struct {
+	mu      sync.Mutex
+	entries []indicatorCacheEntry
+}
+
+// indicatorCacheEntry is one calculation waiting in a batch: the indicators computed
+// for a candle and the cache key they go under once stored
+type indicatorCacheEntry struct {
+	symbol       string
+	timeframe    string
+	indicatorKey string
+	candleTime   time.Time
+	results      []entities.TechnicalIndicator
+}
+
+// Len returns how many calculations are waiting in the batch
+func (b *IndicatorBatch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.entries)
+}
+
+func (b *IndicatorBatch) add(entry indicatorCacheEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, entry)
+}
+
+// drain empties the batch and returns what it held
+func (b *IndicatorBatch) drain() []indicatorCacheEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entries := b.entries
+	b.entries = nil
+	return entries
+}
+
+type indicatorBatchKey struct{}
+
+// indicatorBatchFrom returns the batch carried by ctx, or nil
+func indicatorBatchFrom(ctx context.Context) *IndicatorBatch {
+	batch, _ := ctx.Value(indicatorBatchKey{}).(*IndicatorBatch)
+	return batch
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultIndicatorCacheTTL is used for timeframes without a known candle length
+const defaultIndicatorCacheTTL = time.Minute
+
+// IndicatorResultStore is the cache behind IndicatorCache; *cache.LayeredCache
+// implements it, sharing results across instances through Redis
+type IndicatorResultStore interface {
+	Get(ctx context.Context, key string, target interface{}) (bool, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
+
+// IndicatorCache keeps computed indicator results per symbol, timeframe, indicator
+// key and latest candle, so calculations requested again before a new candle
+// arrives, from the HTTP handlers, workers or the alert engine, reuse the result.
+// A nil *IndicatorCache is valid and never hits.
+type IndicatorCache struct {
+	store  IndicatorResultStore
+	logger *logrus.Logger
+}
+
+// NewIndicatorCache creates a new indicator result cache
+func NewIndicatorCache(store IndicatorResultStore, logger *logrus.Logger) *IndicatorCache {
+	return &IndicatorCache{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Get returns the indicators computed for the candle at candleTime, if cached;
+// cache errors are logged and reported as a miss
+func (ic *IndicatorCache) Get(ctx context.Context, symbol, timeframe, indicatorKey string, candleTime time.Time) ([]entities.TechnicalIndicator, bool) {
+	if ic == nil {
+		return nil, false
+	}
+
+	var cached []entities.TechnicalIndicator
+	found, err := ic.store.Get(ctx, indicatorCacheKey(symbol, timeframe, indicatorKey, candleTime), &cached)
+	if err != nil {
+		ic.logger.WithError(err).WithField("indicator_key", indicatorKey).Warn("Failed to read indicator cache")
+		return nil, false
+	}
+	return cached, found && len(cached) > 0
+}
+
+// Set caches the indicators computed for the candle at candleTime until the next
+// candle is due
+func (ic *IndicatorCache) Set(ctx context.Context, symbol, timeframe, indicatorKey string, candleTime time.Time, results []entities.TechnicalIndicator) {
+	if ic == nil {
+		return
+	}
+
+	ttl := time.Duration(indicators.GetTimeframeMilliseconds(timeframe)) * time.Millisecond
+	if ttl <= 0 {
+		ttl = defaultIndicatorCacheTTL
+	}
+
+	if err := ic.store.Set(ctx, indicatorCacheKey(symbol, timeframe, indicatorKey, candleTime), results, ttl); err != nil {
+		ic.logger.WithError(err).WithField("indicator_key", indicatorKey).Warn("Failed to write indicator cache")
+	}
+}
+
+// Latest returns the cached indicator with the given key, if any
+func (ic *IndicatorCache) Latest(ctx context.Context, symbol, timeframe, indicatorKey string, candleTime time.Time) (*entities.TechnicalIndicator, bool) {
+	results, found := ic.Get(ctx, symbol, timeframe, indicatorKey, candleTime)
+	if !found {
+		return nil, false
+	}
+	for i := range results {
+		if results[i].IndicatorKey == indicatorKey {
+			return &results[i], true
+		}
+	}
+	return nil, false
+}
+
+func indicatorCacheKey(symbol, timeframe, indicatorKey string, candleTime time.Time) string {
+	return fmt.Sprintf("indicator:%s:%s:%s:%d", symbol, timeframe, indicatorKey, candleTime.Unix())
+}
+
+// latestCandleTime returns the timestamp of the newest candle, whatever the order
+func latestCandleTime(history []entities.PriceHistory) time.Time {
+	var latest time.Time
+	for _, candle := range history {
+		if candle.Timestamp.After(latest) {
+			latest = candle.Timestamp
+		}
+	}
+	return latest
+}
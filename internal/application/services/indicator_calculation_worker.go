@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// Scopes of the indicator calculation durations reported to the recorder
+const (
+	IndicatorCalculationScopeRun   = "run"   // A whole pass over the active symbols
+	IndicatorCalculationScopeBatch = "batch" // One batch, from the first calculation to the bulk insert
+)
+
+// activeCryptoPageSize is how many active cryptocurrencies are read at a time
+const activeCryptoPageSize = 500
+
+// indicatorCalculationLockKey keeps replicas from calculating the same interval twice
+const indicatorCalculationLockKey = "indicator_calculation"
+
+// IndicatorCalculationRecorder receives how long the scheduled indicator calculation
+// takes, usually to feed a histogram labelled by scope
+type IndicatorCalculationRecorder interface {
+	ObserveIndicatorCalculation(scope string, duration time.Duration)
+}
+
+// IndicatorCalculationRecorderFunc adapts a function to IndicatorCalculationRecorder
+type IndicatorCalculationRecorderFunc func(scope string, duration time.Duration)
+
+// ObserveIndicatorCalculation calls f(scope, duration)
+func (f IndicatorCalculationRecorderFunc) ObserveIndicatorCalculation(scope string, duration time.Duration) {
+	f(scope, duration)
+}
+
+// IndicatorCalculationRun summarizes one pass of the IndicatorCalculationWorker
+type IndicatorCalculationRun struct {
+	Markets  int           `json:"markets"` // Symbol and timeframe pairs calculated
+	Stored   int           `json:"stored"`  // Indicators stored; candles already calculated are skipped
+	Failed   int           `json:"failed"`  // Batches whose bulk insert failed
+	Duration time.Duration `json:"duration"`
+}
+
+// IndicatorCalculationWorker calculates the indicators of every active
+// cryptocurrency on each configured timeframe on a schedule, a few symbols at a
+// time, storing each batch with one bulk insert
+type IndicatorCalculationWorker struct {
+	cryptoRepo       repositories.CryptoCurrencyRepository
+	indicatorService *TechnicalIndicatorService
+	recorder         IndicatorCalculationRecorder
+	logger           *logrus.Logger
+
+	timeframes  []string
+	concurrency int
+	batchSize   int
+
+	// runs makes sure each interval is calculated by one instance only
+	runs ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewIndicatorCalculationWorker creates a worker calculating the 1h, 4h and 1d
+// indicators of 4 symbols at a time, in batches of 20 pairs
+func NewIndicatorCalculationWorker(
+	cryptoRepo repositories.CryptoCurrencyRepository,
+	indicatorService *TechnicalIndicatorService,
+	logger *logrus.Logger,
+) *IndicatorCalculationWorker {
+	return &IndicatorCalculationWorker{
+		cryptoRepo:       cryptoRepo,
+		indicatorService: indicatorService,
+		logger:           logger,
+		timeframes:       []string{"1h", "4h", "1d"},
+		concurrency:      4,
+		batchSize:        20,
+		runs:             NewMemoryThrottleStore(),
+	}
+}
+
+// SetTimeframes replaces the timeframes calculated for each symbol
+func (w *IndicatorCalculationWorker) SetTimeframes(timeframes []string) {
+	w.timeframes = timeframes
+}
+
+// SetConcurrency limits how many pairs are calculated at once
+func (w *IndicatorCalculationWorker) SetConcurrency(concurrency int) {
+	if concurrency > 0 {
+		w.concurrency = concurrency
+	}
+}
+
+// SetBatchSize sets how many pairs share one bulk insert
+func (w *IndicatorCalculationWorker) SetBatchSize(batchSize int) {
+	if batchSize > 0 {
+		w.batchSize = batchSize
+	}
+}
+
+// SetRecorder reports the duration of each run and batch
+func (w *IndicatorCalculationWorker) SetRecorder(recorder IndicatorCalculationRecorder) {
+	w.recorder = recorder
+}
+
+// SetThrottleStore replaces the in-process run lock, e.g. with a Redis store so only
+// one replica calculates each interval
+func (w *IndicatorCalculationWorker) SetThrottleStore(store ThrottleStore) {
+	w.runs = store
+}
+
+// Start calculates once and then every interval until Stop is called
+func (w *IndicatorCalculationWorker) Start(ctx context.Context, interval time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.isRunning {
+		w.logger.Warn("Indicator calculation is already running")
+		return
+	}
+	w.isRunning = true
+	w.stopChan = make(chan struct{})
+	w.logger.WithField("interval", interval).Info("Starting indicator calculation")
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			w.runScheduled(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the calculation and waits for the current run to finish
+func (w *IndicatorCalculationWorker) Stop() {
+	w.mutex.Lock()
+	if !w.isRunning {
+		w.mutex.Unlock()
+		return
+	}
+	w.isRunning = false
+	close(w.stopChan)
+	w.mutex.Unlock()
+
+	w.wg.Wait()
+	w.logger.Info("Indicator calculation stopped")
+}
+
+// runScheduled runs unless another instance already took this interval
+func (w *IndicatorCalculationWorker) runScheduled(ctx context.Context, interval time.Duration) {
+	acquired, err := w.runs.Acquire(ctx, indicatorCalculationLockKey, interval)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to acquire indicator calculation lock")
+	} else if !acquired {
+		return
+	}
+
+	run, err := w.RunOnce(ctx)
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to calculate indicators")
+		return
+	}
+	w.logger.WithFields(logrus.Fields{
+		"markets":  run.Markets,
+		"stored":   run.Stored,
+		"failed":   run.Failed,
+		"duration": run.Duration,
+	}).Info("Indicators calculated")
+}
+
+// indicatorMarket is a symbol and timeframe pair to calculate
+type indicatorMarket struct {
+	symbol    string
+	timeframe string
+}
+
+// RunOnce calculates the indicators of every active cryptocurrency on every
+// timeframe now. A batch whose bulk insert fails is counted and the run goes on.
+func (w *IndicatorCalculationWorker) RunOnce(ctx context.Context) (*IndicatorCalculationRun, error) {
+	started := time.Now()
+
+	markets, err := w.markets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &IndicatorCalculationRun{Markets: len(markets)}
+	for start := 0; start < len(markets); start += w.batchSize {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		end := min(start+w.batchSize, len(markets))
+
+		stored, err := w.calculateBatch(ctx, markets[start:end])
+		if err != nil {
+			w.logger.WithError(err).WithField("markets", end-start).Error("Failed to store indicator batch")
+			run.Failed++
+			continue
+		}
+		run.Stored += stored
+	}
+
+	run.Duration = time.Since(started)
+	w.observe(IndicatorCalculationScopeRun, run.Duration)
+	return run, nil
+}
+
+// markets pairs every active cryptocurrency with every timeframe
+func (w *IndicatorCalculationWorker) markets(ctx context.Context) ([]indicatorMarket, error) {
+	var markets []indicatorMarket
+	for offset := 0; ; offset += activeCryptoPageSize {
+		cryptos, err := w.cryptoRepo.GetActive(ctx, activeCryptoPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, crypto := range cryptos {
+			for _, timeframe := range w.timeframes {
+				markets = append(markets, indicatorMarket{symbol: crypto.Symbol, timeframe: timeframe})
+			}
+		}
+		if len(cryptos) < activeCryptoPageSize {
+			return markets, nil
+		}
+	}
+}
+
+// calculateBatch calculates the markets, concurrency at a time, and stores the
+// results with one bulk insert
+func (w *IndicatorCalculationWorker) calculateBatch(ctx context.Context, markets []indicatorMarket) (int, error) {
+	started := time.Now()
+	batchCtx, batch := w.indicatorService.BeginBatch(ctx)
+
+	slots := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	for _, market := range markets {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(market indicatorMarket) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := w.indicatorService.CalculateAllIndicators(batchCtx, market.symbol, market.timeframe); err != nil {
+				w.logger.WithError(err).WithFields(logrus.Fields{
+					"symbol":    market.symbol,
+					"timeframe": market.timeframe,
+				}).Error("Failed to calculate indicators")
+			}
+		}(market)
+	}
+	wg.Wait()
+
+	stored, err := w.indicatorService.FlushBatch(ctx, batch)
+	w.observe(IndicatorCalculationScopeBatch, time.Since(started))
+	return stored, err
+}
+
+func (w *IndicatorCalculationWorker) observe(scope string, duration time.Duration) {
+	if w.recorder != nil {
+		w.recorder.ObserveIndicatorCalculation(scope, duration)
+	}
+}
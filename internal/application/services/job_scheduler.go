@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the scheduled jobs
+const (
+	JobPriceRetention       = "price_retention"
+	JobCacheWarmup          = "cache_warmup"
+	JobIndicatorCalculation = "indicator_calculation"
+	JobExchangeSync         = "exchange_sync"
+	JobPriceAggregation     = "price_aggregation"
+	JobPriceSummaries       = "price_summaries"
+	JobTicker24h            = "ticker_24h"
+)
+
+// How a job run was started
+const (
+	JobTriggerScheduled = "scheduled"
+	JobTriggerManual    = "manual"
+)
+
+// jobLockKeyPrefix namespaces the locks taking a job's interval in the throttle store
+const jobLockKeyPrefix = "job_lock:"
+
+var (
+	// ErrJobNotFound is returned for names no job is registered under
+	ErrJobNotFound = entities.NewDomainError(entities.ErrNotFound, "job not found")
+	// ErrJobRunning is returned when a job is triggered while it runs on this instance
+	ErrJobRunning = entities.NewDomainError(entities.ErrConflict, "job is already running")
+)
+
+// JobFunc is one run of a job
+type JobFunc func(ctx context.Context) error
+
+// JobStatus is a job's schedule and the outcome of its last run on this instance
+type JobStatus struct {
+	Name string `json:"name"`
+	// Interval is zero for jobs that only run when triggered
+	Interval     time.Duration `json:"interval"`
+	Running      bool          `json:"running"`
+	Runs         int           `json:"runs"`
+	LastRunAt    *time.Time    `json:"last_run_at,omitempty"`
+	LastTrigger  string        `json:"last_trigger,omitempty"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+	NextRunAt    *time.Time    `json:"next_run_at,omitempty"`
+}
+
+type scheduledJob struct {
+	status JobStatus
+	run    JobFunc
+}
+
+// JobScheduler runs the registered jobs on their intervals, one instance taking
+// each interval of a job, and keeps the outcome of their last run so admins can see
+// what ran and trigger a job on demand
+type JobScheduler struct {
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	// locks makes sure each interval of a job is run by one instance only
+	locks ThrottleStore
+
+	mu    sync.Mutex
+	jobs  map[string]*scheduledJob
+	order []string
+
+	// Scheduling control
+	isRunning bool
+	baseCtx   context.Context
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewJobScheduler creates a scheduler without jobs
+func NewJobScheduler(logger *logrus.Logger) *JobScheduler {
+	return &JobScheduler{
+		logger: logger,
+		clock:  clock.New(),
+		locks:  NewMemoryThrottleStore(),
+		jobs:   make(map[string]*scheduledJob),
+	}
+}
+
+// SetClock replaces the clock the runs are timed with
+func (s *JobScheduler) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetThrottleStore replaces the in-process job locks, e.g. with a Redis store so
+// only one replica runs a job each interval
+func (s *JobScheduler) SetThrottleStore(store ThrottleStore) {
+	s.locks = store
+}
+
+// Register adds a job run every interval once the scheduler starts; with a zero
+// interval it only runs when triggered. Registering a name again replaces the job.
+func (s *JobScheduler) Register(name string, interval time.Duration, run JobFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; !exists {
+		s.order = append(s.order, name)
+	}
+	s.jobs[name] = &scheduledJob{
+		status: JobStatus{Name: name, Interval: interval},
+		run:    run,
+	}
+}
+
+// Statuses returns the status of every job, in the order they were registered
+func (s *JobScheduler) Statuses() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		statuses = append(statuses, s.jobs[name].status)
+	}
+	return statuses
+}
+
+// Status returns the status of one job
+func (s *JobScheduler) Status(name string) (*JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	status := job.status
+	return &status, nil
+}
+
+// Start runs every job with an interval now and then every interval until Stop is
+// called
+func (s *JobScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		s.logger.Warn("Job scheduler is already running")
+		return
+	}
+	s.isRunning = true
+	s.baseCtx = ctx
+	s.stopChan = make(chan struct{})
+	s.logger.WithField("jobs", len(s.order)).Info("Starting job scheduler")
+
+	for _, name := range s.order {
+		if interval := s.jobs[name].status.Interval; interval > 0 {
+			s.wg.Add(1)
+			go s.schedule(ctx, name, interval)
+		}
+	}
+}
+
+// Stop halts the schedules and waits for the runs in progress to finish
+func (s *JobScheduler) Stop() {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	s.logger.Info("Job scheduler stopped")
+}
+
+// Trigger starts a run of the job now, in the background, and returns its status.
+// The run outlives the request triggering it, and isn't skipped when another
+// instance holds the job's interval.
+func (s *JobScheduler) Trigger(ctx context.Context, name string) (*JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if job.status.Running {
+		return nil, ErrJobRunning
+	}
+
+	runCtx := context.WithoutCancel(ctx)
+	if s.isRunning {
+		// Runs started while the scheduler runs are waited for by Stop
+		runCtx = s.baseCtx
+		s.wg.Add(1)
+	}
+	s.begin(job, JobTriggerManual)
+	status := job.status
+
+	go func(tracked bool) {
+		if tracked {
+			defer s.wg.Done()
+		}
+		s.execute(runCtx, job)
+	}(s.isRunning)
+
+	return &status, nil
+}
+
+func (s *JobScheduler) schedule(ctx context.Context, name string, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.runScheduled(ctx, name, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runScheduled runs the job unless it is still running or another instance already
+// took this interval
+func (s *JobScheduler) runScheduled(ctx context.Context, name string, interval time.Duration) {
+	s.mu.Lock()
+	job := s.jobs[name]
+	next := s.clock.Now().Add(interval)
+	job.status.NextRunAt = &next
+	running := job.status.Running
+	s.mu.Unlock()
+
+	if running {
+		s.logger.WithField("job", name).Warn("Skipping scheduled run of a job still running")
+		return
+	}
+
+	acquired, err := s.locks.Acquire(ctx, jobLockKeyPrefix+name, interval)
+	if err != nil {
+		s.logger.WithError(err).WithField("job", name).Warn("Failed to acquire job lock")
+	} else if !acquired {
+		return
+	}
+
+	s.mu.Lock()
+	if job.status.Running {
+		// Triggered while the lock was being acquired
+		s.mu.Unlock()
+		return
+	}
+	s.begin(job, JobTriggerScheduled)
+	s.mu.Unlock()
+
+	s.execute(ctx, job)
+}
+
+// begin marks the job as running; the caller holds s.mu
+func (s *JobScheduler) begin(job *scheduledJob, trigger string) {
+	started := s.clock.Now()
+	job.status.Running = true
+	job.status.LastRunAt = &started
+	job.status.LastTrigger = trigger
+}
+
+// execute runs the job, recovering from panics, and records the outcome
+func (s *JobScheduler) execute(ctx context.Context, job *scheduledJob) {
+	s.mu.Lock()
+	started := *job.status.LastRunAt
+	s.mu.Unlock()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return job.run(ctx)
+	}()
+	duration := s.clock.Since(started)
+
+	s.mu.Lock()
+	job.status.Running = false
+	job.status.Runs++
+	job.status.LastDuration = duration
+	job.status.LastError = ""
+	if err != nil {
+		job.status.LastError = err.Error()
+	}
+	trigger := job.status.LastTrigger
+	s.mu.Unlock()
+
+	fields := logrus.Fields{
+		"job":      job.status.Name,
+		"trigger":  trigger,
+		"duration": duration,
+	}
+	if err != nil {
+		s.logger.WithError(err).WithFields(fields).Error("Job failed")
+		return
+	}
+	s.logger.WithFields(fields).Info("Job completed")
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// Stages of the alert pipeline whose latencies are reported. Each stage covers the
+// time since the previous one, so the slowest stage stands out; end_to_end covers
+// the whole path from the exchange event to the WebSocket broadcast.
+const (
+	LatencyStageIngest       = "ingest"       // Exchange event to price stored
+	LatencyStageEvaluation   = "evaluation"   // Price stored to alert evaluated
+	LatencyStageBroadcast    = "ws_broadcast" // Alert evaluated to WebSocket broadcast
+	LatencyStageNotification = "notification" // Alert evaluated to notification delivered
+	LatencyStageEndToEnd     = "end_to_end"
+)
+
+// LatencyRecorder receives the latency of each alert pipeline stage, usually to
+// feed a histogram labelled by stage
+type LatencyRecorder interface {
+	ObserveLatency(stage string, latency time.Duration)
+}
+
+// LatencyRecorderFunc adapts a function to LatencyRecorder
+type LatencyRecorderFunc func(stage string, latency time.Duration)
+
+// ObserveLatency calls f(stage, latency)
+func (f LatencyRecorderFunc) ObserveLatency(stage string, latency time.Duration) {
+	f(stage, latency)
+}
+
+// observeLatency reports the time between two pipeline timestamps, skipping
+// stages without a recorder or with a timestamp missing
+func observeLatency(recorder LatencyRecorder, stage string, from, to time.Time) {
+	if recorder == nil || from.IsZero() || to.IsZero() {
+		return
+	}
+	latency := to.Sub(from)
+	if latency < 0 {
+		// Clock skew between instances; a zero is more useful than dropping the sample
+		latency = 0
+	}
+	recorder.ObserveLatency(stage, latency)
+}
+
+// AlertTrace carries the timestamps of the price event behind an alert evaluation
+// through notification and broadcast
+type AlertTrace struct {
+	EventTime   time.Time `json:"event_time"`
+	IngestedAt  time.Time `json:"ingested_at"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
+// newAlertTrace starts a trace for an evaluation of priceData; it returns nil for
+// prices ingested without an exchange event time
+func newAlertTrace(priceData *entities.PriceHistory, evaluatedAt time.Time) *AlertTrace {
+	if priceData == nil || priceData.EventTime == nil {
+		return nil
+	}
+	return &AlertTrace{
+		EventTime:   *priceData.EventTime,
+		IngestedAt:  priceData.CreatedAt,
+		EvaluatedAt: evaluatedAt,
+	}
+}
+
+type alertTraceKey struct{}
+
+// WithAlertTrace returns a context carrying trace, so the notification queued for
+// a triggered alert reports its delivery latency
+func WithAlertTrace(ctx context.Context, trace *AlertTrace) context.Context {
+	if trace == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, alertTraceKey{}, trace)
+}
+
+// AlertTraceFrom returns the trace carried by ctx, or nil
+func AlertTraceFrom(ctx context.Context) *AlertTrace {
+	trace, _ := ctx.Value(alertTraceKey{}).(*AlertTrace)
+	return trace
+}
+
+// Operations whose durations are reported with the context they ran in
+const (
+	DurationAlertEvaluation = "alert_evaluation"
+	DurationBroadcast       = "ws_broadcast"
+)
+
+// DurationRecorder receives how long an operation took along with its context, so
+// the sample can be linked to the trace the operation ran in
+type DurationRecorder interface {
+	ObserveDuration(ctx context.Context, operation string, duration time.Duration)
+}
+
+// DurationRecorderFunc adapts a function to DurationRecorder
+type DurationRecorderFunc func(ctx context.Context, operation string, duration time.Duration)
+
+// ObserveDuration calls f(ctx, operation, duration)
+func (f DurationRecorderFunc) ObserveDuration(ctx context.Context, operation string, duration time.Duration) {
+	f(ctx, operation, duration)
+}
+
+// observeDuration reports the time since started, skipping operations without a recorder
+func observeDuration(ctx context.Context, recorder DurationRecorder, operation string, started time.Time) {
+	if recorder != nil {
+		recorder.ObserveDuration(ctx, operation, time.Since(started))
+	}
+}
+
+// serviceTracer traces background work such as alert evaluations; it records
+// nothing unless tracing is enabled
+var serviceTracer = otel.Tracer("priceguard-api/services")
+
+// startSpan starts a span named name as a child of the span of ctx, if any
+func startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return serviceTracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
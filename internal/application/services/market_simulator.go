@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// marketSimulationLockKey keeps replicas from storing the same simulated prices twice
+const marketSimulationLockKey = "market_simulation"
+
+// Market simulator defaults
+const (
+	// DefaultSimulationVolatilityBps is the standard deviation of a simulated price
+	// move per tick, in basis points
+	DefaultSimulationVolatilityBps = 20
+	// simulatedTimeframe holds the simulated prices, like the collected real-time ones
+	simulatedTimeframe = "1m"
+)
+
+// DefaultSimulatedSymbols are the sandbox symbols the simulator prices, with the
+// price each one starts at when its real pair has no price yet
+var DefaultSimulatedSymbols = map[string]float64{
+	entities.SandboxSymbolPrefix + "BTCUSDT": 65000,
+	entities.SandboxSymbolPrefix + "ETHUSDT": 3200,
+	entities.SandboxSymbolPrefix + "SOLUSDT": 150,
+	entities.SandboxSymbolPrefix + "BNBUSDT": 580,
+}
+
+// MarketSimulator is the data provider of sandbox users: on a schedule it stores a
+// random-walk price for each sandbox symbol in price_history, so their watchlists,
+// charts and alerts behave as on a live market without touching real market data
+type MarketSimulator struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	logger           *logrus.Logger
+
+	symbols       map[string]float64
+	volatilityBps int
+	roll          func() float64
+	clock         clock.Clock
+
+	// runs makes sure each interval is simulated by one instance only
+	runs ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewMarketSimulator creates a simulator of DefaultSimulatedSymbols
+func NewMarketSimulator(priceHistoryRepo repositories.PriceHistoryRepository, logger *logrus.Logger) *MarketSimulator {
+	return &MarketSimulator{
+		priceHistoryRepo: priceHistoryRepo,
+		logger:           logger,
+		symbols:          DefaultSimulatedSymbols,
+		volatilityBps:    DefaultSimulationVolatilityBps,
+		roll:             rand.NormFloat64,
+		clock:            clock.New(),
+		runs:             NewMemoryThrottleStore(),
+	}
+}
+
+// SetVolatilityBps sets the standard deviation of a price move per tick, in basis
+// points; values below 1 are ignored
+func (s *MarketSimulator) SetVolatilityBps(bps int) {
+	if bps > 0 {
+		s.volatilityBps = bps
+	}
+}
+
+// SetRoll replaces the source of the standard normal draws each price move is
+// scaled from, so tests can make the walk deterministic
+func (s *MarketSimulator) SetRoll(roll func() float64) {
+	s.roll = roll
+}
+
+// SetClock replaces the clock the simulated prices are stamped with
+func (s *MarketSimulator) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetThrottleStore replaces the in-process run lock, e.g. with a Redis store so only
+// one replica simulates each interval
+func (s *MarketSimulator) SetThrottleStore(store ThrottleStore) {
+	s.runs = store
+}
+
+// Symbols returns the simulated symbols, sorted
+func (s *MarketSimulator) Symbols() []string {
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// CurrentPrice returns the latest simulated price of the symbol, or the price its
+// walk will start from when none was stored yet
+func (s *MarketSimulator) CurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	if _, ok := s.symbols[symbol]; !ok {
+		return 0, fmt.Errorf("%s is not a simulated symbol", symbol)
+	}
+	latest, err := s.latest(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if latest != nil {
+		return latest.ClosePrice, nil
+	}
+	return s.startingPrice(ctx, symbol), nil
+}
+
+// Start simulates a price move once and then every interval until Stop is called
+func (s *MarketSimulator) Start(ctx context.Context, interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isRunning {
+		s.logger.Warn("Market simulation is already running")
+		return
+	}
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.logger.WithFields(logrus.Fields{
+		"interval": interval,
+		"symbols":  s.Symbols(),
+	}).Info("Starting market simulation")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the simulation and waits for the current tick to finish
+func (s *MarketSimulator) Stop() {
+	s.mutex.Lock()
+	if !s.isRunning {
+		s.mutex.Unlock()
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	s.logger.Info("Market simulation stopped")
+}
+
+// runScheduled simulates unless another instance already took this interval
+func (s *MarketSimulator) runScheduled(ctx context.Context, interval time.Duration) {
+	acquired, err := s.runs.Acquire(ctx, marketSimulationLockKey, interval)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to acquire market simulation lock")
+	} else if !acquired {
+		return
+	}
+
+	if _, err := s.Tick(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to simulate market")
+	}
+}
+
+// Tick stores the next price of every simulated symbol, moved from its latest one by
+// a normally distributed step, and returns how many were stored. A symbol that fails
+// is logged and moves on the next tick.
+func (s *MarketSimulator) Tick(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	stored := 0
+	for _, symbol := range s.Symbols() {
+		if err := s.tick(ctx, symbol, now); err != nil {
+			s.logger.WithError(err).WithField("symbol", symbol).Error("Failed to simulate price")
+			continue
+		}
+		stored++
+	}
+
+	if stored == 0 && len(s.symbols) > 0 {
+		return 0, errors.New("no simulated price could be stored")
+	}
+	return stored, nil
+}
+
+func (s *MarketSimulator) tick(ctx context.Context, symbol string, now time.Time) error {
+	open, err := s.CurrentPrice(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	// The step is multiplicative, so the price stays positive however long it walks
+	step := float64(s.volatilityBps) / 10000 * s.roll()
+	price := open * math.Exp(step)
+
+	return s.priceHistoryRepo.Create(ctx, &entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  simulatedTimeframe,
+		Timestamp:  now,
+		OpenPrice:  open,
+		HighPrice:  math.Max(open, price),
+		LowPrice:   math.Min(open, price),
+		ClosePrice: price,
+	})
+}
+
+func (s *MarketSimulator) latest(ctx context.Context, symbol string) (*entities.PriceHistory, error) {
+	latest, err := s.priceHistoryRepo.GetLatest(ctx, symbol, simulatedTimeframe)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest price of %s: %w", symbol, err)
+	}
+	return latest, nil
+}
+
+// startingPrice is the real pair's latest price, so the demo looks like today's
+// market, or the symbol's default when the pair isn't collected
+func (s *MarketSimulator) startingPrice(ctx context.Context, symbol string) float64 {
+	pair, err := s.latest(ctx, strings.TrimPrefix(symbol, entities.SandboxSymbolPrefix))
+	if err == nil && pair != nil && pair.ClosePrice > 0 {
+		return pair.ClosePrice
+	}
+	return s.symbols[symbol]
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+// percentageHistoryLimit is how many candles a percentage alert reads to find the
+// price 24h ago
+const percentageHistoryLimit = 50
+
+// marketSnapshot memoizes the market data read during one evaluation cycle, so the
+// alerts sharing a symbol and timeframe load the latest candle, the history and
+// each indicator once instead of once per alert. Concurrent evaluations asking for
+// the same data wait for the first read; its error is shared as well, and only the
+// evaluation that made the read is charged for it.
+type marketSnapshot struct {
+	priceHistoryRepo       repositories.PriceHistoryRepository
+	technicalIndicatorRepo repositories.TechnicalIndicatorRepository
+
+	mu      sync.Mutex
+	entries map[snapshotKey]*snapshotEntry
+}
+
+type snapshotKey struct {
+	kind      string // "latest", "history" or "indicator"
+	symbol    string
+	timeframe string
+	indicator string
+}
+
+type snapshotEntry struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+func newMarketSnapshot(priceHistoryRepo repositories.PriceHistoryRepository, technicalIndicatorRepo repositories.TechnicalIndicatorRepository) *marketSnapshot {
+	return &marketSnapshot{
+		priceHistoryRepo:       priceHistoryRepo,
+		technicalIndicatorRepo: technicalIndicatorRepo,
+		entries:                make(map[snapshotKey]*snapshotEntry),
+	}
+}
+
+// load returns the value stored under key, reading it with fetch the first time
+func (s *marketSnapshot) load(key snapshotKey, fetch func() (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	entry, exists := s.entries[key]
+	if !exists {
+		entry = &snapshotEntry{}
+		s.entries[key] = entry
+	}
+	s.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = fetch()
+	})
+	return entry.value, entry.err
+}
+
+// latest returns the latest candle of symbol on timeframe
+func (s *marketSnapshot) latest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	value, err := s.load(snapshotKey{kind: "latest", symbol: symbol, timeframe: timeframe}, func() (interface{}, error) {
+		countDBCall(ctx)
+		return s.priceHistoryRepo.GetLatest(ctx, symbol, timeframe)
+	})
+	priceData, _ := value.(*entities.PriceHistory)
+	return priceData, err
+}
+
+// history returns the recent candles percentage alerts compare against
+func (s *marketSnapshot) history(ctx context.Context, symbol, timeframe string) ([]entities.PriceHistory, error) {
+	value, err := s.load(snapshotKey{kind: "history", symbol: symbol, timeframe: timeframe}, func() (interface{}, error) {
+		countDBCall(ctx)
+		return s.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, percentageHistoryLimit)
+	})
+	history, _ := value.([]entities.PriceHistory)
+	return history, err
+}
+
+// indicator returns the latest stored value of the indicator identified by key
+func (s *marketSnapshot) indicator(ctx context.Context, symbol, timeframe, key string) (*entities.TechnicalIndicator, error) {
+	value, err := s.load(snapshotKey{kind: "indicator", symbol: symbol, timeframe: timeframe, indicator: key}, func() (interface{}, error) {
+		countDBCall(ctx)
+		return s.technicalIndicatorRepo.GetLatestByKey(ctx, symbol, timeframe, key)
+	})
+	indicator, _ := value.(*entities.TechnicalIndicator)
+	return indicator, err
+}
+
+// size returns how many distinct reads the snapshot served
+func (s *marketSnapshot) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+type marketSnapshotKey struct{}
+
+// withMarketSnapshot returns a context whose alert evaluations read market data
+// through snapshot
+func withMarketSnapshot(ctx context.Context, snapshot *marketSnapshot) context.Context {
+	return context.WithValue(ctx, marketSnapshotKey{}, snapshot)
+}
+
+// marketSnapshotFrom returns the snapshot carried by ctx, or nil
+func marketSnapshotFrom(ctx context.Context) *marketSnapshot {
+	snapshot, _ := ctx.Value(marketSnapshotKey{}).(*marketSnapshot)
+	return snapshot
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+const (
+	// DefaultNotificationPageSize is used when a page request gives no limit
+	DefaultNotificationPageSize = 50
+	// MaxNotificationPageSize caps a single page of the notification center
+	MaxNotificationPageSize = 100
+)
+
+// NotificationPage is one page of a user's notification center
+type NotificationPage struct {
+	Notifications []entities.Notification `json:"data"`
+	Limit         int                     `json:"limit"`
+	Offset        int                     `json:"offset"`
+	Count         int                     `json:"count"`
+	UnreadOnly    bool                    `json:"unread_only"`
+	UnreadCount   int64                   `json:"unread_count"`
+}
+
+// GetNotificationPage loads a page of the user's notifications together with their
+// unread count, so clients can refresh the badge and list in one round trip
+func (ns *NotificationService) GetNotificationPage(ctx context.Context, userID uuid.UUID, limit, offset int, unreadOnly bool) (*NotificationPage, error) {
+	if limit > MaxNotificationPageSize {
+		limit = MaxNotificationPageSize
+	}
+	if limit <= 0 {
+		limit = DefaultNotificationPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var notifications []entities.Notification
+	var err error
+	if unreadOnly {
+		notifications, err = ns.notificationRepo.GetUnread(ctx, userID, limit, offset)
+	} else {
+		notifications, err = ns.notificationRepo.GetByUserID(ctx, userID, limit, offset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+	}
+
+	unread, err := ns.notificationRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &NotificationPage{
+		Notifications: notifications,
+		Limit:         limit,
+		Offset:        offset,
+		Count:         len(notifications),
+		UnreadOnly:    unreadOnly,
+		UnreadCount:   unread,
+	}, nil
+}
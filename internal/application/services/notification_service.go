@@ -9,6 +9,7 @@ import (
 
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -18,10 +19,11 @@ import (
 type NotificationChannel string
 
 const (
-	ChannelInApp NotificationChannel = "app"
-	ChannelEmail NotificationChannel = "email"
-	ChannelPush  NotificationChannel = "push"
-	ChannelSMS   NotificationChannel = "sms"
+	ChannelInApp    NotificationChannel = "app"
+	ChannelEmail    NotificationChannel = "email"
+	ChannelPush     NotificationChannel = "push"
+	ChannelSMS      NotificationChannel = "sms"
+	ChannelTelegram NotificationChannel = "telegram"
 )
 
 // NotificationPriority represents the priority of notifications
@@ -48,6 +50,7 @@ type QueuedNotification struct {
 	CreatedAt   time.Time              `json:"created_at"`
 	Retries     int                    `json:"retries"`
 	MaxRetries  int                    `json:"max_retries"`
+	Trace       *AlertTrace            `json:"trace,omitempty"` // Set for triggered alerts whose price has an event time
 }
 
 // NotificationDeliveryResult represents the result of a notification delivery attempt
@@ -55,6 +58,7 @@ type NotificationDeliveryResult struct {
 	NotificationID uuid.UUID           `json:"notification_id"`
 	Channel        NotificationChannel `json:"channel"`
 	Success        bool                `json:"success"`
+	Skipped        bool                `json:"skipped,omitempty"` // The user turned the channel off
 	Error          string              `json:"error,omitempty"`
 	DeliveredAt    time.Time           `json:"delivered_at"`
 }
@@ -63,8 +67,13 @@ type NotificationDeliveryResult struct {
 type NotificationService struct {
 	notificationRepo repositories.NotificationRepository
 	userRepo         repositories.UserRepository
+	settingsRepo     repositories.UserSettingsRepository
+	deliveryRepo     repositories.NotificationDeliveryRepository
 	redisClient      RedisClientInterface
+	latencyRecorder  LatencyRecorder
+	deliveryRecorder DeliveryRecorder
 	logger           *logrus.Logger
+	clock            clock.Clock
 
 	// Processing control
 	isProcessing bool
@@ -75,8 +84,88 @@ type NotificationService struct {
 	// Configuration
 	queueKey          string
 	dlqKey            string // Dead Letter Queue
+	inFlightKey       string // Claimed notifications, scored by visibility deadline
 	processingTimeout time.Duration
 	batchSize         int
+	pipelining        bool
+	maxPipelineSize   int
+
+	// Delivery
+	delivery     DeliveryConfig
+	channelSlots map[NotificationChannel]chan struct{}
+	providers    *ProviderRegistry
+
+	// Delivery results since the service started, for the stats endpoint
+	statsMutex    sync.Mutex
+	deliveryStats map[NotificationChannel]*ChannelDeliveryStats
+	// Sliding windows the persisted delivery results are aggregated over
+	statsWindows []time.Duration
+}
+
+// DeliveryRecorder receives every delivery attempt, usually to feed Prometheus
+// counters and a latency histogram labelled by channel. Attempt counts from 1 and
+// latency runs from when the notification was queued.
+type DeliveryRecorder interface {
+	ObserveDelivery(channel NotificationChannel, status string, attempt int, latency time.Duration)
+}
+
+// DeliveryRecorderFunc adapts a function to DeliveryRecorder
+type DeliveryRecorderFunc func(channel NotificationChannel, status string, attempt int, latency time.Duration)
+
+// ObserveDelivery calls f(channel, status, attempt, latency)
+func (f DeliveryRecorderFunc) ObserveDelivery(channel NotificationChannel, status string, attempt int, latency time.Duration) {
+	f(channel, status, attempt, latency)
+}
+
+// ChannelDeliveryStats counts the delivery results of a channel
+type ChannelDeliveryStats struct {
+	Sent        int64      `json:"sent"`
+	Failed      int64      `json:"failed"`
+	Skipped     int64      `json:"skipped"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// NotificationSender delivers queued notifications through one channel
+type NotificationSender interface {
+	Send(ctx context.Context, notification *QueuedNotification) error
+}
+
+// DeliveryConfig tunes concurrent notification delivery
+type DeliveryConfig struct {
+	// Workers is how many notifications of a batch are delivered at once
+	Workers int
+	// ChannelConcurrency caps in-flight deliveries per channel
+	ChannelConcurrency int
+	// ChannelTimeouts bounds a single delivery per channel; zero means no timeout
+	ChannelTimeouts map[NotificationChannel]time.Duration
+}
+
+// DefaultDeliveryConfig returns the delivery settings used unless SetDeliveryConfig is called
+func DefaultDeliveryConfig() DeliveryConfig {
+	return DeliveryConfig{
+		Workers:            4,
+		ChannelConcurrency: 4,
+		ChannelTimeouts: map[NotificationChannel]time.Duration{
+			ChannelEmail:    10 * time.Second,
+			ChannelPush:     5 * time.Second,
+			ChannelSMS:      10 * time.Second,
+			ChannelTelegram: 10 * time.Second,
+		},
+	}
+}
+
+// queueOp is a single sorted set change made while processing a batch
+type queueOp struct {
+	key    string
+	remove string
+	add    *redis.Z
+}
+
+// zsetWriter is satisfied by both the Redis client and its pipelines
+type zsetWriter interface {
+	ZAdd(ctx context.Context, key string, members ...redis.Z) *redis.IntCmd
+	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
 }
 
 // NewNotificationService creates a new notification service
@@ -86,17 +175,129 @@ func NewNotificationService(
 	redisClient RedisClientInterface,
 	logger *logrus.Logger,
 ) *NotificationService {
-	return &NotificationService{
+	ns := &NotificationService{
 		notificationRepo:  notificationRepo,
 		userRepo:          userRepo,
 		redisClient:       redisClient,
 		logger:            logger,
+		clock:             clock.New(),
 		queueKey:          "notification_queue",
 		dlqKey:            "notification_dlq",
+		inFlightKey:       "notification_inflight",
 		processingTimeout: 30 * time.Second,
 		batchSize:         10,
+		pipelining:        true,
+		maxPipelineSize:   100,
+		providers:         NewProviderRegistry(logger),
+		deliveryStats:     make(map[NotificationChannel]*ChannelDeliveryStats),
+		statsWindows:      []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
 		stopChan:          make(chan struct{}),
 	}
+	ns.SetDeliveryConfig(DefaultDeliveryConfig())
+	return ns
+}
+
+// SetDeliveryConfig replaces the worker pool size, per-channel concurrency and timeouts.
+// It must be called before processing starts.
+func (ns *NotificationService) SetDeliveryConfig(cfg DeliveryConfig) {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.ChannelConcurrency <= 0 {
+		cfg.ChannelConcurrency = 1
+	}
+	ns.delivery = cfg
+
+	ns.channelSlots = make(map[NotificationChannel]chan struct{})
+	for _, channel := range []NotificationChannel{ChannelInApp, ChannelEmail, ChannelPush, ChannelSMS, ChannelTelegram} {
+		ns.channelSlots[channel] = make(chan struct{}, cfg.ChannelConcurrency)
+	}
+}
+
+// Providers returns the registry selecting the provider behind each channel
+func (ns *NotificationService) Providers() *ProviderRegistry {
+	return ns.providers
+}
+
+// SetChannelSender routes deliveries for a channel through sender, replacing the active provider
+func (ns *NotificationService) SetChannelSender(channel NotificationChannel, sender NotificationSender) {
+	provider, ok := sender.(NotificationProvider)
+	if !ok {
+		provider = customProvider{sender}
+	}
+	ns.providers.Activate(channel, provider)
+}
+
+// SetUserSettingsRepository makes email and push deliveries follow each user's
+// notification settings
+func (ns *NotificationService) SetUserSettingsRepository(repo repositories.UserSettingsRepository) {
+	ns.settingsRepo = repo
+}
+
+// SetDeliveryRepository persists the result of every delivery attempt, so the stats
+// report per-channel success rates, latency and retries over sliding windows
+func (ns *NotificationService) SetDeliveryRepository(repo repositories.NotificationDeliveryRepository) {
+	ns.deliveryRepo = repo
+}
+
+// SetDeliveryRecorder reports every delivery attempt with its outcome and latency
+func (ns *NotificationService) SetDeliveryRecorder(recorder DeliveryRecorder) {
+	ns.deliveryRecorder = recorder
+}
+
+// SetDeliveryStatsWindows replaces the sliding windows the stats aggregate the
+// persisted delivery results over; the defaults are 15m, 1h and 24h
+func (ns *NotificationService) SetDeliveryStatsWindows(windows ...time.Duration) {
+	valid := make([]time.Duration, 0, len(windows))
+	for _, window := range windows {
+		if window > 0 {
+			valid = append(valid, window)
+		}
+	}
+	ns.statsWindows = valid
+}
+
+// SetQueuePartition moves the queue, in-flight set and DLQ to keys of their own for
+// the region, so each region's workers only deliver what was queued there and a
+// region outage can't strand another region's notifications. The region is a Redis
+// Cluster hash tag, keeping a partition's keys in one slot for pipelining.
+// It must be called before processing starts.
+func (ns *NotificationService) SetQueuePartition(region string) {
+	if region == "" {
+		return
+	}
+	suffix := ":{" + region + "}"
+	ns.queueKey = "notification_queue" + suffix
+	ns.dlqKey = "notification_dlq" + suffix
+	ns.inFlightKey = "notification_inflight" + suffix
+}
+
+// SetVisibilityTimeout sets how long a claimed notification may stay in flight before
+// the reclaimer returns it to the queue for another attempt
+func (ns *NotificationService) SetVisibilityTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		ns.processingTimeout = timeout
+	}
+}
+
+// SetPipelining controls whether queue updates made by a batch are sent in Redis
+// pipelines of at most maxSize commands, or one round trip per command
+func (ns *NotificationService) SetPipelining(enabled bool, maxSize int) {
+	ns.pipelining = enabled
+	if maxSize > 0 {
+		ns.maxPipelineSize = maxSize
+	}
+}
+
+// SetLatencyRecorder reports how long alert notifications take to be delivered
+// after their alert was evaluated
+func (ns *NotificationService) SetLatencyRecorder(recorder LatencyRecorder) {
+	ns.latencyRecorder = recorder
+}
+
+// SetClock replaces the clock used for scheduling and backoff
+func (ns *NotificationService) SetClock(c clock.Clock) {
+	ns.clock = c
 }
 
 // StartProcessing starts the notification processing worker
@@ -139,7 +340,11 @@ func (ns *NotificationService) CreateNotification(ctx context.Context, userID uu
 		Title:            title,
 		Message:          message,
 		NotificationType: notificationType,
-		CreatedAt:        time.Now(),
+		CreatedAt:        ns.clock.Now(),
+	}
+
+	if err := notification.Validate(); err != nil {
+		return nil, err
 	}
 
 	if err := ns.notificationRepo.Create(ctx, notification); err != nil {
@@ -162,10 +367,10 @@ func (ns *NotificationService) QueueNotification(ctx context.Context, notificati
 		notification.ID = uuid.New()
 	}
 	if notification.CreatedAt.IsZero() {
-		notification.CreatedAt = time.Now()
+		notification.CreatedAt = ns.clock.Now()
 	}
 	if notification.ScheduledAt.IsZero() {
-		notification.ScheduledAt = time.Now()
+		notification.ScheduledAt = ns.clock.Now()
 	}
 	if notification.MaxRetries == 0 {
 		notification.MaxRetries = 3
@@ -174,26 +379,12 @@ func (ns *NotificationService) QueueNotification(ctx context.Context, notificati
 		notification.Priority = PriorityNormal
 	}
 
-	// Serialize notification
-	data, err := json.Marshal(notification)
+	entry, err := queueEntry(notification)
 	if err != nil {
-		return fmt.Errorf("failed to serialize notification: %w", err)
-	}
-
-	// Add to Redis queue with priority
-	score := float64(notification.ScheduledAt.Unix())
-	if notification.Priority == PriorityUrgent {
-		score -= 86400 // Move urgent notifications 24h earlier in queue
-	} else if notification.Priority == PriorityHigh {
-		score -= 3600 // Move high priority 1h earlier
+		return err
 	}
 
-	err = ns.redisClient.ZAdd(ctx, ns.queueKey, redis.Z{
-		Score:  score,
-		Member: string(data),
-	}).Err()
-
-	if err != nil {
+	if err := ns.redisClient.ZAdd(ctx, ns.queueKey, entry).Err(); err != nil {
 		return fmt.Errorf("failed to queue notification: %w", err)
 	}
 
@@ -208,6 +399,38 @@ func (ns *NotificationService) QueueNotification(ctx context.Context, notificati
 	return nil
 }
 
+// AlertNotificationPriority maps the priority chosen on an alert to the queue priority,
+// keeping alerts without one at PriorityHigh
+func AlertNotificationPriority(alert *entities.Alert) NotificationPriority {
+	switch NotificationPriority(alert.Priority) {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return NotificationPriority(alert.Priority)
+	default:
+		return PriorityHigh
+	}
+}
+
+// queueEntry serializes a notification into its sorted set member, scored by
+// scheduled time and pulled earlier for higher priorities
+func queueEntry(notification *QueuedNotification) (redis.Z, error) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return redis.Z{}, fmt.Errorf("failed to serialize notification: %w", err)
+	}
+
+	return redis.Z{Score: queueScore(notification), Member: string(data)}, nil
+}
+
+func queueScore(notification *QueuedNotification) float64 {
+	score := float64(notification.ScheduledAt.Unix())
+	if notification.Priority == PriorityUrgent {
+		score -= 86400 // Move urgent notifications 24h earlier in queue
+	} else if notification.Priority == PriorityHigh {
+		score -= 3600 // Move high priority 1h earlier
+	}
+	return score
+}
+
 // QueueAlertNotification is a convenience method for queuing alert-related notifications
 func (ns *NotificationService) QueueAlertNotification(ctx context.Context, alert *entities.Alert, currentValue float64, channels []NotificationChannel) error {
 	// Get user to check preferences (for future use)
@@ -239,8 +462,9 @@ func (ns *NotificationService) QueueAlertNotification(ctx context.Context, alert
 		Title:    title,
 		Message:  message,
 		Channels: channels,
-		Priority: PriorityHigh,
+		Priority: AlertNotificationPriority(alert),
 		Data:     data,
+		Trace:    AlertTraceFrom(ctx),
 	}
 
 	// First create in-app notification
@@ -271,15 +495,21 @@ func (ns *NotificationService) processNotificationQueue(ctx context.Context) {
 		case <-ns.stopChan:
 			return
 		case <-ticker.C:
-			ns.processBatch(ctx)
+			if _, err := ns.ReclaimExpired(ctx); err != nil {
+				ns.logger.WithError(err).Error("Failed to reclaim in-flight notifications")
+			}
+			ns.ProcessBatch(ctx)
 		}
 	}
 }
 
-// processBatch processes a batch of notifications from the queue
-func (ns *NotificationService) processBatch(ctx context.Context) {
+// ProcessBatch delivers the notifications that are due and returns how many were claimed.
+// Claimed notifications sit in the in-flight set until they are acknowledged, so a crash
+// mid-batch leaves them to ReclaimExpired instead of losing them. Acknowledgements,
+// reschedules and dead letter moves are applied together at the end of the batch.
+func (ns *NotificationService) ProcessBatch(ctx context.Context) int {
 	// Get notifications ready for processing
-	now := time.Now().Unix()
+	now := ns.clock.Now().Unix()
 	results, err := ns.redisClient.ZRangeByScoreWithScores(ctx, ns.queueKey, &redis.ZRangeBy{
 		Min:   "-inf",
 		Max:   fmt.Sprintf("%d", now),
@@ -288,65 +518,256 @@ func (ns *NotificationService) processBatch(ctx context.Context) {
 
 	if err != nil {
 		ns.logger.WithError(err).Error("Failed to get notifications from queue")
-		return
+		return 0
 	}
 
 	if len(results) == 0 {
-		return
+		return 0
+	}
+
+	claimed := ns.claim(ctx, results)
+	if len(claimed) == 0 {
+		return 0
 	}
 
-	ns.logger.WithField("count", len(results)).Debug("Processing notification batch")
+	ns.logger.WithField("count", len(claimed)).Debug("Processing notification batch")
 
+	// Deliver concurrently, keeping the resulting queue changes in claim order
+	pending := make(chan int)
+	outcomes := make([][]queueOp, len(claimed))
+	workers := ns.delivery.Workers
+	if workers > len(claimed) {
+		workers = len(claimed)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				outcomes[i] = ns.handleClaimed(ctx, claimed[i])
+			}
+		}()
+	}
+	for i := range claimed {
+		pending <- i
+	}
+	close(pending)
+	wg.Wait()
+
+	ops := make([]queueOp, 0, 2*len(claimed))
+	for _, outcome := range outcomes {
+		ops = append(ops, outcome...)
+	}
+
+	ns.applyQueueOps(ctx, ops)
+	return len(claimed)
+}
+
+// handleClaimed delivers one claimed notification and returns the queue changes
+// acknowledging, rescheduling or dead-lettering it
+func (ns *NotificationService) handleClaimed(ctx context.Context, notificationData string) []queueOp {
+	ack := queueOp{key: ns.inFlightKey, remove: notificationData}
+
+	// Parse notification
+	var notification QueuedNotification
+	if err := json.Unmarshal([]byte(notificationData), &notification); err != nil {
+		ns.logger.WithError(err).Error("Failed to parse queued notification")
+		return append(ns.appendDeadLetterOp(nil, notificationData, "parse_error"), ack)
+	}
+
+	// Process notification
+	if ns.processNotification(ctx, &notification) {
+		return []queueOp{ack}
+	}
+
+	// Handle retry logic
+	notification.Retries++
+	if notification.Retries >= notification.MaxRetries {
+		// Move to dead letter queue
+		return append(ns.appendDeadLetterOp(nil, notificationData, "max_retries_exceeded"), ack)
+	}
+
+	// Reschedule with exponential backoff: acknowledge the old entry and queue the new one
+	backoffDelay := time.Duration(notification.Retries*notification.Retries) * time.Minute
+	notification.ScheduledAt = ns.clock.Now().Add(backoffDelay)
+
+	entry, err := queueEntry(&notification)
+	if err != nil {
+		ns.logger.WithError(err).Error("Failed to reschedule notification")
+		return []queueOp{ack}
+	}
+	return []queueOp{ack, {key: ns.queueKey, add: &entry}}
+}
+
+// claimScript moves the given members (ARGV[2:]) from the queue (KEYS[1]) to the
+// in-flight set (KEYS[2]) with the deadline ARGV[1], skipping members another worker
+// already removed, and returns the members it moved
+const claimScript = `
+local claimed = {}
+for i = 2, #ARGV do
+	if redis.call('ZREM', KEYS[1], ARGV[i]) == 1 then
+		redis.call('ZADD', KEYS[2], ARGV[1], ARGV[i])
+		claimed[#claimed + 1] = ARGV[i]
+	end
+end
+return claimed
+`
+
+// claim moves due notifications into the in-flight set. The move is a single script,
+// so an entry is always in exactly one of them and only the worker that removed it
+// from the queue gets it back.
+func (ns *NotificationService) claim(ctx context.Context, results []redis.Z) []string {
+	deadline := ns.clock.Now().Add(ns.processingTimeout).Unix()
+
+	args := make([]interface{}, 0, len(results)+1)
+	args = append(args, deadline)
 	for _, result := range results {
-		notificationData := result.Member.(string)
+		args = append(args, result.Member)
+	}
+
+	claimed, err := ns.redisClient.Eval(ctx, claimScript, []string{ns.queueKey, ns.inFlightKey}, args...).StringSlice()
+	if err != nil {
+		ns.logger.WithError(err).Error("Failed to claim notifications")
+		return nil
+	}
+	return claimed
+}
+
+// ReclaimExpired returns in-flight notifications whose visibility timeout passed to the
+// queue, keeping their original schedule, and reports how many were returned
+func (ns *NotificationService) ReclaimExpired(ctx context.Context) (int, error) {
+	expired, err := ns.redisClient.ZRangeByScoreWithScores(ctx, ns.inFlightKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", ns.clock.Now().Unix()),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired in-flight notifications: %w", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	ops := make([]queueOp, 0, 2*len(expired))
+	for _, entry := range expired {
+		member := entry.Member.(string)
 
-		// Parse notification
+		// Unparseable entries go back as due so the next batch dead-letters them
+		score := float64(ns.clock.Now().Unix())
 		var notification QueuedNotification
-		if err := json.Unmarshal([]byte(notificationData), &notification); err != nil {
-			ns.logger.WithError(err).Error("Failed to parse queued notification")
-			ns.moveToDeadLetterQueue(ctx, notificationData, "parse_error")
-			ns.removeFromQueue(ctx, notificationData)
-			continue
+		if err := json.Unmarshal([]byte(member), &notification); err == nil {
+			score = queueScore(&notification)
 		}
 
-		// Process notification
-		success := ns.processNotification(ctx, &notification)
-
-		if success {
-			// Remove from queue on success
-			ns.removeFromQueue(ctx, notificationData)
-		} else {
-			// Handle retry logic
-			notification.Retries++
-			if notification.Retries >= notification.MaxRetries {
-				// Move to dead letter queue
-				ns.moveToDeadLetterQueue(ctx, notificationData, "max_retries_exceeded")
-				ns.removeFromQueue(ctx, notificationData)
-			} else {
-				// Reschedule with exponential backoff
-				backoffDelay := time.Duration(notification.Retries*notification.Retries) * time.Minute
-				notification.ScheduledAt = time.Now().Add(backoffDelay)
-
-				// Remove old entry and add new one
-				ns.removeFromQueue(ctx, notificationData)
-				ns.QueueNotification(ctx, &notification)
+		ops = append(ops,
+			queueOp{key: ns.queueKey, add: &redis.Z{Score: score, Member: member}},
+			queueOp{key: ns.inFlightKey, remove: member},
+		)
+	}
+	ns.applyQueueOps(ctx, ops)
+
+	ns.logger.WithField("count", len(expired)).Warn("Reclaimed in-flight notifications after visibility timeout")
+	return len(expired), nil
+}
+
+// appendDeadLetterOp adds the DLQ entry for a notification to ops
+func (ns *NotificationService) appendDeadLetterOp(ops []queueOp, notificationData, reason string) []queueOp {
+	dlqData := map[string]interface{}{
+		"notification": notificationData,
+		"reason":       reason,
+		"timestamp":    ns.clock.Now(),
+	}
+
+	data, err := json.Marshal(dlqData)
+	if err != nil {
+		ns.logger.WithError(err).Error("Failed to serialize DLQ entry")
+		return ops
+	}
+
+	return append(ops, queueOp{key: ns.dlqKey, add: &redis.Z{
+		Score:  float64(ns.clock.Now().Unix()),
+		Member: string(data),
+	}})
+}
+
+// applyQueueOps writes queue changes in order, pipelined in chunks of
+// maxPipelineSize commands when pipelining is enabled, and returns one command per op
+func (ns *NotificationService) applyQueueOps(ctx context.Context, ops []queueOp) []*redis.IntCmd {
+	results := make([]*redis.IntCmd, 0, len(ops))
+
+	if !ns.pipelining {
+		for _, op := range ops {
+			cmd := op.apply(ctx, ns.redisClient)
+			if err := cmd.Err(); err != nil {
+				ns.logger.WithError(err).WithField("key", op.key).Error("Failed to update notification queue")
 			}
+			results = append(results, cmd)
 		}
+		return results
 	}
+
+	for start := 0; start < len(ops); start += ns.maxPipelineSize {
+		end := start + ns.maxPipelineSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		pipe := ns.redisClient.Pipeline()
+		for _, op := range ops[start:end] {
+			results = append(results, op.apply(ctx, pipe))
+		}
+
+		cmds, err := pipe.Exec(ctx)
+		if err != nil {
+			failed := 0
+			for _, cmd := range cmds {
+				if cmd.Err() != nil {
+					failed++
+				}
+			}
+			ns.logger.WithError(err).WithFields(logrus.Fields{
+				"commands": len(cmds),
+				"failed":   failed,
+			}).Error("Failed to update notification queue")
+		}
+	}
+	return results
 }
 
-// processNotification processes a single notification across all its channels
-func (ns *NotificationService) processNotification(ctx context.Context, notification *QueuedNotification) bool {
-	allSuccess := true
+func (op queueOp) apply(ctx context.Context, w zsetWriter) *redis.IntCmd {
+	if op.add != nil {
+		return w.ZAdd(ctx, op.key, *op.add)
+	}
+	return w.ZRem(ctx, op.key, op.remove)
+}
 
-	for _, channel := range notification.Channels {
-		result := ns.deliverToChannel(ctx, notification, channel)
+// processNotification delivers a notification to all its channels concurrently
+func (ns *NotificationService) processNotification(ctx context.Context, notification *QueuedNotification) bool {
+	results := make([]*NotificationDeliveryResult, len(notification.Channels))
+
+	var wg sync.WaitGroup
+	for i, channel := range notification.Channels {
+		wg.Add(1)
+		go func(i int, channel NotificationChannel) {
+			defer wg.Done()
+			results[i] = ns.deliverWithTimeout(ctx, notification, channel)
+		}(i, channel)
+	}
+	wg.Wait()
 
+	allSuccess := true
+	for _, result := range results {
+		ns.recordDelivery(result)
+		ns.observeDelivery(ctx, notification, result)
+		if result.Success && !result.Skipped && notification.Trace != nil {
+			observeLatency(ns.latencyRecorder, LatencyStageNotification, notification.Trace.EvaluatedAt, result.DeliveredAt)
+		}
 		if !result.Success {
 			allSuccess = false
 			ns.logger.WithFields(logrus.Fields{
 				"notification_id": notification.ID,
-				"channel":         channel,
+				"channel":         result.Channel,
 				"error":           result.Error,
 			}).Error("Failed to deliver notification")
 		}
@@ -355,109 +776,220 @@ func (ns *NotificationService) processNotification(ctx context.Context, notifica
 	return allSuccess
 }
 
+// deliverWithTimeout bounds a delivery by the channel's timeout and concurrency limit.
+// A delivery that outlives its timeout keeps its channel slot until it returns, so a
+// stalled provider can only tie up its own channel's slots.
+func (ns *NotificationService) deliverWithTimeout(ctx context.Context, notification *QueuedNotification, channel NotificationChannel) *NotificationDeliveryResult {
+	if timeout := ns.delivery.ChannelTimeouts[channel]; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	failed := func(reason string) *NotificationDeliveryResult {
+		return &NotificationDeliveryResult{
+			NotificationID: notification.ID,
+			Channel:        channel,
+			Error:          reason,
+			DeliveredAt:    ns.clock.Now(),
+		}
+	}
+
+	slots := ns.channelSlots[channel]
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return failed(fmt.Sprintf("%s delivery timed out waiting for a free slot", channel))
+		}
+	}
+
+	done := make(chan *NotificationDeliveryResult, 1)
+	go func() {
+		if slots != nil {
+			defer func() { <-slots }()
+		}
+		done <- ns.deliverToChannel(ctx, notification, channel)
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return failed(fmt.Sprintf("%s delivery timed out", channel))
+	}
+}
+
 // deliverToChannel delivers a notification to a specific channel
 func (ns *NotificationService) deliverToChannel(ctx context.Context, notification *QueuedNotification, channel NotificationChannel) *NotificationDeliveryResult {
 	result := &NotificationDeliveryResult{
 		NotificationID: notification.ID,
 		Channel:        channel,
-		DeliveredAt:    time.Now(),
+		DeliveredAt:    ns.clock.Now(),
 	}
 
-	switch channel {
-	case ChannelInApp:
+	if channel == ChannelInApp {
 		// In-app notifications are already created, just mark as delivered
 		result.Success = true
+		return result
+	}
 
-	case ChannelEmail:
-		// Email delivery would be implemented here
-		// For now, just simulate
-		result.Success = ns.simulateEmailDelivery(notification)
-		if !result.Success {
-			result.Error = "email delivery failed"
-		}
-
-	case ChannelPush:
-		// Push notification delivery would be implemented here
-		result.Success = ns.simulatePushDelivery(notification)
-		if !result.Success {
-			result.Error = "push delivery failed"
-		}
-
-	case ChannelSMS:
-		// SMS delivery would be implemented here
-		result.Success = ns.simulateSMSDelivery(notification)
-		if !result.Success {
-			result.Error = "sms delivery failed"
-		}
+	if !ns.channelEnabled(ctx, notification.UserID, channel) {
+		result.Success = true
+		result.Skipped = true
+		return result
+	}
 
-	default:
-		result.Success = false
+	provider, ok := ns.providers.Provider(channel)
+	if !ok {
 		result.Error = fmt.Sprintf("unsupported channel: %s", channel)
+		return result
 	}
 
+	err := provider.Send(ctx, notification)
+	result.DeliveredAt = ns.clock.Now()
+	if err != nil {
+		result.Error = fmt.Sprintf("%s delivery via %s failed: %v", channel, provider.Name(), err)
+		return result
+	}
+
+	result.Success = true
 	return result
 }
 
-// simulateEmailDelivery simulates email delivery (placeholder for actual implementation)
-func (ns *NotificationService) simulateEmailDelivery(notification *QueuedNotification) bool {
-	ns.logger.WithFields(logrus.Fields{
-		"notification_id": notification.ID,
-		"user_id":         notification.UserID,
-		"title":           notification.Title,
-	}).Info("Email notification would be sent here")
-	return true // Simulate success
-}
+// channelEnabled reports whether the user accepts notifications on channel. Email
+// and push follow UserSettings.NotificationsEmail and NotificationsPush; when the
+// settings can't be read the default, enabled, applies.
+func (ns *NotificationService) channelEnabled(ctx context.Context, userID uuid.UUID, channel NotificationChannel) bool {
+	if (channel != ChannelEmail && channel != ChannelPush) || ns.settingsRepo == nil {
+		return true
+	}
 
-// simulatePushDelivery simulates push notification delivery
-func (ns *NotificationService) simulatePushDelivery(notification *QueuedNotification) bool {
-	ns.logger.WithFields(logrus.Fields{
-		"notification_id": notification.ID,
-		"user_id":         notification.UserID,
-		"title":           notification.Title,
-	}).Info("Push notification would be sent here")
-	return true // Simulate success
+	settings, err := ns.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		ns.logger.WithError(err).WithField("user_id", userID).Debug("Failed to get user settings, delivering anyway")
+		return true
+	}
+	if channel == ChannelPush {
+		return settings.NotificationsPush
+	}
+	return settings.NotificationsEmail
 }
 
-// simulateSMSDelivery simulates SMS delivery
-func (ns *NotificationService) simulateSMSDelivery(notification *QueuedNotification) bool {
-	ns.logger.WithFields(logrus.Fields{
-		"notification_id": notification.ID,
-		"user_id":         notification.UserID,
-		"message":         notification.Message,
-	}).Info("SMS notification would be sent here")
-	return true // Simulate success
-}
+// recordDelivery counts a delivery result in the channel's stats
+func (ns *NotificationService) recordDelivery(result *NotificationDeliveryResult) {
+	ns.statsMutex.Lock()
+	defer ns.statsMutex.Unlock()
 
-// removeFromQueue removes a notification from the processing queue
-func (ns *NotificationService) removeFromQueue(ctx context.Context, notificationData string) {
-	err := ns.redisClient.ZRem(ctx, ns.queueKey, notificationData).Err()
-	if err != nil {
-		ns.logger.WithError(err).Error("Failed to remove notification from queue")
+	stats := ns.deliveryStats[result.Channel]
+	if stats == nil {
+		stats = &ChannelDeliveryStats{}
+		ns.deliveryStats[result.Channel] = stats
+	}
+
+	switch {
+	case result.Skipped:
+		stats.Skipped++
+	case result.Success:
+		stats.Sent++
+	default:
+		stats.Failed++
+		failedAt := result.DeliveredAt
+		stats.LastError = result.Error
+		stats.LastErrorAt = &failedAt
 	}
 }
 
-// moveToDeadLetterQueue moves a notification to the dead letter queue
-func (ns *NotificationService) moveToDeadLetterQueue(ctx context.Context, notificationData, reason string) {
-	dlqData := map[string]interface{}{
-		"notification": notificationData,
-		"reason":       reason,
-		"timestamp":    time.Now(),
+// observeDelivery reports a delivery attempt to the recorder and persists it. A
+// result that can't be stored is only logged; it must not fail the delivery.
+func (ns *NotificationService) observeDelivery(ctx context.Context, notification *QueuedNotification, result *NotificationDeliveryResult) {
+	if ns.deliveryRecorder == nil && ns.deliveryRepo == nil {
+		return
 	}
 
-	data, err := json.Marshal(dlqData)
-	if err != nil {
-		ns.logger.WithError(err).Error("Failed to serialize DLQ entry")
+	status := entities.DeliveryStatusSent
+	switch {
+	case result.Skipped:
+		status = entities.DeliveryStatusSkipped
+	case !result.Success:
+		status = entities.DeliveryStatusFailed
+	}
+	attempt := notification.Retries + 1
+	latency := result.DeliveredAt.Sub(notification.CreatedAt)
+	if latency < 0 || notification.CreatedAt.IsZero() {
+		latency = 0
+	}
+
+	if ns.deliveryRecorder != nil {
+		ns.deliveryRecorder.ObserveDelivery(result.Channel, status, attempt, latency)
+	}
+	if ns.deliveryRepo == nil {
 		return
 	}
 
-	err = ns.redisClient.ZAdd(ctx, ns.dlqKey, redis.Z{
-		Score:  float64(time.Now().Unix()),
-		Member: string(data),
-	}).Err()
+	delivery := &entities.NotificationDelivery{
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Channel:        string(result.Channel),
+		Status:         status,
+		Attempt:        attempt,
+		LatencyMs:      latency.Milliseconds(),
+		Error:          result.Error,
+		DeliveredAt:    result.DeliveredAt,
+	}
+	if err := ns.deliveryRepo.Create(ctx, delivery); err != nil {
+		ns.logger.WithError(err).WithFields(logrus.Fields{
+			"notification_id": notification.ID,
+			"channel":         result.Channel,
+		}).Warn("Failed to store notification delivery result")
+	}
+}
 
-	if err != nil {
-		ns.logger.WithError(err).Error("Failed to add to dead letter queue")
+// DeliveryWindows aggregates the persisted delivery results per channel over each
+// stats window, keyed by the window (15m, 1h, 24h). It returns nil when delivery
+// results aren't persisted.
+func (ns *NotificationService) DeliveryWindows(ctx context.Context) (map[string][]entities.ChannelDeliveryWindow, error) {
+	if ns.deliveryRepo == nil {
+		return nil, nil
+	}
+
+	now := ns.clock.Now()
+	windows := make(map[string][]entities.ChannelDeliveryWindow, len(ns.statsWindows))
+	for _, window := range ns.statsWindows {
+		channels, err := ns.deliveryRepo.ChannelWindows(ctx, now.Add(-window))
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate deliveries over %s: %w", window, err)
+		}
+		if channels == nil {
+			channels = []entities.ChannelDeliveryWindow{}
+		}
+		windows[windowLabel(window)] = channels
+	}
+	return windows, nil
+}
+
+// windowLabel formats a stats window the short way it is configured, e.g. 15m or 24h
+func windowLabel(window time.Duration) string {
+	switch {
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	default:
+		return window.String()
+	}
+}
+
+// DeliveryStats returns the delivery results per channel since the service started
+func (ns *NotificationService) DeliveryStats() map[NotificationChannel]ChannelDeliveryStats {
+	ns.statsMutex.Lock()
+	defer ns.statsMutex.Unlock()
+
+	stats := make(map[NotificationChannel]ChannelDeliveryStats, len(ns.deliveryStats))
+	for channel, channelStats := range ns.deliveryStats {
+		stats[channel] = *channelStats
 	}
+	return stats
 }
 
 // GetNotificationStats returns statistics about the notification system
@@ -472,11 +1004,26 @@ func (ns *NotificationService) GetNotificationStats(ctx context.Context) (map[st
 		return nil, fmt.Errorf("failed to get DLQ size: %w", err)
 	}
 
+	inFlightSize, err := ns.redisClient.ZCard(ctx, ns.inFlightKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight size: %w", err)
+	}
+
 	stats := map[string]interface{}{
 		"queue_size":    queueSize,
 		"dlq_size":      dlqSize,
+		"in_flight":     inFlightSize,
+		"deliveries":    ns.DeliveryStats(),
 		"is_processing": ns.isProcessing,
-		"last_update":   time.Now(),
+		"last_update":   ns.clock.Now(),
+	}
+
+	windows, err := ns.DeliveryWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if windows != nil {
+		stats["delivery_windows"] = windows
 	}
 
 	return stats, nil
@@ -484,7 +1031,7 @@ func (ns *NotificationService) GetNotificationStats(ctx context.Context) (map[st
 
 // CleanupOldNotifications removes old processed notifications and DLQ entries
 func (ns *NotificationService) CleanupOldNotifications(ctx context.Context, olderThan time.Duration) error {
-	cutoff := time.Now().Add(-olderThan).Unix()
+	cutoff := ns.clock.Now().Add(-olderThan).Unix()
 
 	// Clean up DLQ
 	removed, err := ns.redisClient.ZRemRangeByScore(ctx, ns.dlqKey, "-inf", fmt.Sprintf("%d", cutoff)).Result()
@@ -493,5 +1040,13 @@ func (ns *NotificationService) CleanupOldNotifications(ctx context.Context, olde
 	}
 
 	ns.logger.WithField("removed_count", removed).Info("Cleaned up old DLQ entries")
+
+	if ns.deliveryRepo != nil {
+		removed, err := ns.deliveryRepo.DeleteOlderThan(ctx, ns.clock.Now().Add(-olderThan))
+		if err != nil {
+			return fmt.Errorf("failed to cleanup delivery results: %w", err)
+		}
+		ns.logger.WithField("removed_count", removed).Info("Cleaned up old notification delivery results")
+	}
 	return nil
 }
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+const (
+	// MaxOHLCVRange caps the time range of one request, about a month of 1m candles
+	MaxOHLCVRange = 31 * 24 * time.Hour
+	// DefaultOHLCVCandles is how many candles are returned when no start is given
+	DefaultOHLCVCandles = 200
+	// ohlcvSourceTimeframe holds the candles every timeframe is resampled from
+	ohlcvSourceTimeframe = "1m"
+)
+
+// OHLCVRequest asks for the candles of Timeframe between From and To; a zero From
+// covers the last DefaultOHLCVCandles candles and a zero To ends now
+type OHLCVRequest struct {
+	Symbol    string
+	Timeframe string
+	From      time.Time
+	To        time.Time
+}
+
+// OHLCVResult holds the resampled candles, oldest first. From is aligned down to the
+// start of its candle, so the first one is complete; the last one is still forming
+// when To is in it.
+type OHLCVResult struct {
+	Symbol    string                  `json:"symbol"`
+	Timeframe string                  `json:"timeframe"`
+	From      time.Time               `json:"from"`
+	To        time.Time               `json:"to"`
+	Candles   []entities.PriceHistory `json:"data"`
+}
+
+// OHLCVService builds candles of any timeframe, such as 2h or 3d, from the stored 1m
+// candles, so clients don't need every timeframe collected
+type OHLCVService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	clock            clock.Clock
+}
+
+// NewOHLCVService creates a new OHLCV service
+func NewOHLCVService(priceHistoryRepo repositories.PriceHistoryRepository) *OHLCVService {
+	return &OHLCVService{
+		priceHistoryRepo: priceHistoryRepo,
+		clock:            clock.New(),
+	}
+}
+
+// SetClock replaces the clock a missing end of range defaults to
+func (s *OHLCVService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// GetCandles resamples the symbol's 1m candles of the range into the timeframe
+func (s *OHLCVService) GetCandles(ctx context.Context, req OHLCVRequest) (*OHLCVResult, error) {
+	step := time.Duration(indicators.ParseTimeframe(req.Timeframe)) * time.Millisecond
+	if step == 0 {
+		return nil, &entities.ValidationError{Entity: "ohlcv", Field: "timeframe",
+			Message: fmt.Sprintf("unsupported timeframe %q, expected a number of minutes, hours, days or weeks such as 2h", req.Timeframe)}
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = s.clock.Now()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-DefaultOHLCVCandles * step)
+		if to.Sub(from) > MaxOHLCVRange {
+			from = to.Add(-MaxOHLCVRange)
+		}
+	}
+	// Buckets are aligned to the Unix epoch, like Resample's
+	from = time.UnixMilli(from.UnixMilli() / step.Milliseconds() * step.Milliseconds()).UTC()
+	to = to.UTC()
+
+	if !to.After(from) {
+		return nil, &entities.ValidationError{Entity: "ohlcv", Field: "to", Message: "must be after from"}
+	}
+	if to.Sub(from) > MaxOHLCVRange {
+		return nil, &entities.ValidationError{Entity: "ohlcv", Field: "from",
+			Message: fmt.Sprintf("the range must not exceed %d days", int(MaxOHLCVRange.Hours()/24))}
+	}
+
+	candles, err := s.priceHistoryRepo.GetByTimeRange(ctx, req.Symbol, ohlcvSourceTimeframe, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles: %w", err)
+	}
+
+	resampled := indicators.ResampleCustom(candles, req.Timeframe)
+	if resampled == nil {
+		resampled = []entities.PriceHistory{}
+	}
+	return &OHLCVResult{
+		Symbol:    req.Symbol,
+		Timeframe: req.Timeframe,
+		From:      from,
+		To:        to,
+		Candles:   resampled,
+	}, nil
+}
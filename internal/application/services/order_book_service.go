@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Order book defaults
+const (
+	DefaultOrderBookLevels = 20
+	DefaultOrderBookMaxAge = 10 * time.Second
+	// orderBookBroadcastInterval is how often at most a symbol's order book room is
+	// sent its book; the depth stream updates it ten times a second
+	orderBookBroadcastInterval = time.Second
+)
+
+// OrderBookRoomPrefix starts the WebSocket rooms streaming a symbol's order book
+const OrderBookRoomPrefix = "orderbook:"
+
+// OrderBookRoom returns the WebSocket room streaming the symbol's order book
+func OrderBookRoom(symbol string) string {
+	return OrderBookRoomPrefix + strings.ToUpper(symbol)
+}
+
+// DepthStreamSource is the Binance partial book depth stream
+type DepthStreamSource interface {
+	StartWebSocket(ctx context.Context, streams []string) error
+	StopWebSocket()
+	SubscribeToStream(stream string) <-chan []byte
+	UnsubscribeFromStream(stream string)
+	GetDepthWebSocketStream(symbol string, levels int) string
+}
+
+// OrderBookBroadcaster sends a message to the clients of a room on this instance
+type OrderBookBroadcaster interface {
+	BroadcastLocal(room, messageType string, data interface{})
+}
+
+// OrderBookLevel is the quantity offered at a price
+type OrderBookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook is the latest partial book of a symbol, best levels first
+type OrderBook struct {
+	Symbol       string           `json:"symbol"`
+	LastUpdateID int64            `json:"last_update_id"`
+	Bids         []OrderBookLevel `json:"bids"`
+	Asks         []OrderBookLevel `json:"asks"`
+	BestBid      float64          `json:"best_bid"`
+	BestAsk      float64          `json:"best_ask"`
+	// Spread is the best ask less the best bid; SpreadPercent is relative to the
+	// price halfway between them
+	Spread        float64   `json:"spread"`
+	SpreadPercent float64   `json:"spread_percent"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// OrderBookService keeps the order book of a few symbols in memory from the Binance
+// partial book depth stream. Each update replaces the symbol's book, since it
+// carries the full best levels, and is sent to the symbol's order book room at most
+// once every orderBookBroadcastInterval. Every instance runs its own stream, so the
+// books are only broadcast to the clients connected to this instance.
+type OrderBookService struct {
+	source      DepthStreamSource
+	broadcaster OrderBookBroadcaster
+	logger      *logrus.Logger
+	maxAge      time.Duration
+	clock       clock.Clock
+
+	books         map[string]*OrderBook
+	lastBroadcast map[string]time.Time
+	symbols       map[string]bool
+	streams       []string
+	mutex         sync.RWMutex
+	wg            sync.WaitGroup
+}
+
+// NewOrderBookService creates an order book service reporting books older than
+// DefaultOrderBookMaxAge as stale
+func NewOrderBookService(source DepthStreamSource, logger *logrus.Logger) *OrderBookService {
+	return &OrderBookService{
+		source:        source,
+		logger:        logger,
+		maxAge:        DefaultOrderBookMaxAge,
+		clock:         clock.New(),
+		books:         make(map[string]*OrderBook),
+		lastBroadcast: make(map[string]time.Time),
+		symbols:       make(map[string]bool),
+	}
+}
+
+// SetBroadcaster sends the books to their order book room as they update; it must
+// be called before Start
+func (s *OrderBookService) SetBroadcaster(broadcaster OrderBookBroadcaster) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.broadcaster = broadcaster
+}
+
+// SetMaxAge sets how old a book can be before it is reported as stale
+func (s *OrderBookService) SetMaxAge(maxAge time.Duration) {
+	if maxAge > 0 {
+		s.maxAge = maxAge
+	}
+}
+
+// SetClock replaces the clock timestamping the updates
+func (s *OrderBookService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Start subscribes to the partial book depth of every symbol with the given number
+// of levels, until ctx is done or Stop is called
+func (s *OrderBookService) Start(ctx context.Context, symbols []string, levels int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.streams) > 0 {
+		return fmt.Errorf("order books are already streaming")
+	}
+	switch levels {
+	case 5, 10, 20:
+	default:
+		return fmt.Errorf("order book levels must be 5, 10 or 20, got %d", levels)
+	}
+
+	var streams []string
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		stream := s.source.GetDepthWebSocketStream(symbol, levels)
+		streams = append(streams, stream)
+		s.symbols[symbol] = true
+
+		messages := s.source.SubscribeToStream(stream)
+		s.wg.Add(1)
+		go s.consume(ctx, stream, messages)
+	}
+
+	if err := s.source.StartWebSocket(ctx, streams); err != nil {
+		for _, stream := range streams {
+			s.source.UnsubscribeFromStream(stream)
+		}
+		s.symbols = make(map[string]bool)
+		return fmt.Errorf("failed to start depth stream: %w", err)
+	}
+	s.streams = streams
+
+	s.logger.WithField("symbols", len(symbols)).Info("Order book streaming started")
+	return nil
+}
+
+// Stop closes the depth stream and waits for the pending updates to be applied
+func (s *OrderBookService) Stop() {
+	s.mutex.Lock()
+	streams := s.streams
+	s.streams = nil
+	s.mutex.Unlock()
+
+	if len(streams) == 0 {
+		return
+	}
+
+	s.source.StopWebSocket()
+	for _, stream := range streams {
+		s.source.UnsubscribeFromStream(stream)
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Order book streaming stopped")
+}
+
+// consume applies the depth updates of one stream
+func (s *OrderBookService) consume(ctx context.Context, stream string, messages <-chan []byte) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			update, err := external.ParseDepthMessage(message)
+			if err != nil {
+				s.logger.WithError(err).WithField("stream", stream).Warn("Failed to parse depth message")
+				continue
+			}
+			if err := s.ApplyDepth(update); err != nil {
+				s.logger.WithError(err).WithField("stream", stream).Warn("Invalid depth update")
+			}
+		}
+	}
+}
+
+// ApplyDepth replaces the symbol's book with a depth update. Updates older than the
+// book are ignored, and levels without quantity are left out.
+func (s *OrderBookService) ApplyDepth(update *external.DepthWebSocketData) error {
+	bids, err := orderBookLevels(update.Bids)
+	if err != nil {
+		return fmt.Errorf("invalid bids: %w", err)
+	}
+	asks, err := orderBookLevels(update.Asks)
+	if err != nil {
+		return fmt.Errorf("invalid asks: %w", err)
+	}
+
+	book := &OrderBook{
+		Symbol:       strings.ToUpper(update.Symbol),
+		LastUpdateID: update.LastUpdateID,
+		Bids:         bids,
+		Asks:         asks,
+		UpdatedAt:    s.clock.Now(),
+	}
+	if len(bids) > 0 {
+		book.BestBid = bids[0].Price
+	}
+	if len(asks) > 0 {
+		book.BestAsk = asks[0].Price
+	}
+	if book.BestBid > 0 && book.BestAsk > 0 {
+		book.Spread = book.BestAsk - book.BestBid
+		book.SpreadPercent = book.Spread / ((book.BestAsk + book.BestBid) / 2) * 100
+	}
+
+	s.mutex.Lock()
+	if current, ok := s.books[book.Symbol]; ok && current.LastUpdateID > book.LastUpdateID {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.books[book.Symbol] = book
+	broadcaster := s.broadcaster
+	broadcast := broadcaster != nil && book.UpdatedAt.Sub(s.lastBroadcast[book.Symbol]) >= orderBookBroadcastInterval
+	if broadcast {
+		s.lastBroadcast[book.Symbol] = book.UpdatedAt
+	}
+	s.mutex.Unlock()
+
+	if broadcast {
+		broadcaster.BroadcastLocal(OrderBookRoom(book.Symbol), "order_book_update", book)
+	}
+	return nil
+}
+
+// GetOrderBook returns the latest book of a streamed symbol
+func (s *OrderBookService) GetOrderBook(symbol string) (*OrderBook, error) {
+	symbol = strings.ToUpper(symbol)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	book, ok := s.books[symbol]
+	if !ok {
+		if !s.symbols[symbol] {
+			return nil, entities.NewDomainError(entities.ErrNotFound, "order book of %s is not streamed", symbol)
+		}
+		return nil, entities.NewDomainError(entities.ErrStaleData, "order book of %s has not been received yet", symbol)
+	}
+	if age := s.clock.Now().Sub(book.UpdatedAt); age > s.maxAge {
+		return nil, entities.NewDomainError(entities.ErrStaleData, "order book of %s was last updated %s ago", symbol, age.Round(time.Second))
+	}
+
+	copied := *book
+	return &copied, nil
+}
+
+// orderBookLevels parses price and quantity pairs, skipping empty levels
+func orderBookLevels(pairs [][2]string) ([]OrderBookLevel, error) {
+	levels := make([]OrderBookLevel, 0, len(pairs))
+	for _, pair := range pairs {
+		price, err := strconv.ParseFloat(pair[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q: %w", pair[0], err)
+		}
+		quantity, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q: %w", pair[1], err)
+		}
+		if quantity == 0 {
+			continue
+		}
+		levels = append(levels, OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Methods consolidating the prices of a symbol across exchanges
+const (
+	AggregationMedian = "median"
+	AggregationVWAP   = "vwap"
+)
+
+// Price aggregation defaults
+const (
+	DefaultAggregationMaxQuoteAge = 5 * time.Minute
+	// aggregationTimeframe is the timeframe of the klines whose close is an exchange's
+	// price, and of the consolidated prices stored
+	aggregationTimeframe = "1m"
+)
+
+// ConsolidatedAlertTypes are the alert types that can watch a consolidated price;
+// consolidated prices have no indicators
+var ConsolidatedAlertTypes = []string{"price", "percentage"}
+
+var (
+	// ErrAggregationDisabled is returned when fewer than two exchanges or no symbols
+	// are configured
+	ErrAggregationDisabled = errors.New("price aggregation is not configured")
+	// ErrSymbolNotAggregated is returned for symbols whose prices aren't aggregated
+	ErrSymbolNotAggregated = entities.NewDomainError(entities.ErrNotFound, "symbol is not aggregated")
+	// ErrNoFreshQuotes is returned when no exchange has a recent price of the symbol
+	ErrNoFreshQuotes = entities.NewDomainError(entities.ErrStaleData, "no exchange has a recent price of the symbol")
+)
+
+// ExchangeQuote is the latest price of a symbol on one exchange
+type ExchangeQuote struct {
+	Exchange  string    `json:"exchange"`
+	Price     float64   `json:"price"`
+	Volume    float64   `json:"volume"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ConsolidatedPrice is the price of a symbol consolidated across the exchanges with a
+// recent price of it, and the exchanges it is cheapest and dearest on
+type ConsolidatedPrice struct {
+	Symbol string `json:"symbol"`
+	// AlertSymbol is the symbol alerts on the consolidated price are created on
+	AlertSymbol string          `json:"alert_symbol"`
+	Method      string          `json:"method"`
+	Price       float64         `json:"price"`
+	Volume      float64         `json:"volume"`
+	Quotes      []ExchangeQuote `json:"quotes"`
+	// BestBuy is the exchange with the lowest price, BestSell the one with the highest
+	BestBuy  ExchangeQuote `json:"best_buy"`
+	BestSell ExchangeQuote `json:"best_sell"`
+	// SpreadPercent is how much higher the highest price is than the lowest
+	SpreadPercent float64   `json:"spread_percent"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// AggregationRun summarizes one collection of the aggregated symbols
+type AggregationRun struct {
+	Stored  int   `json:"stored"`  // Symbols whose consolidated price was stored
+	Quotes  int   `json:"quotes"`  // Exchange prices stored
+	Failed  int   `json:"failed"`  // Exchange prices that couldn't be fetched or were stale
+	Skipped int   `json:"skipped"` // Symbols without any fresh exchange price
+	Deleted int64 `json:"deleted"` // Exchange prices past the history retention
+}
+
+// PriceAggregationService collects the same symbols from several exchanges, stores
+// each exchange's price and consolidates them into one price by median or
+// volume-weighted average. The consolidated price is stored in price_history under
+// the symbol's AGG- symbol, so alerts watch it like a market's.
+type PriceAggregationService struct {
+	sources           map[string]KlineSource
+	exchangePriceRepo repositories.ExchangePriceRepository
+	priceHistoryRepo  repositories.PriceHistoryRepository
+	logger            *logrus.Logger
+
+	symbols          []string
+	method           string
+	maxQuoteAge      time.Duration
+	historyRetention time.Duration
+	clock            clock.Clock
+}
+
+// NewPriceAggregationService creates a service consolidating by median the prices
+// of no symbols yet
+func NewPriceAggregationService(
+	exchangePriceRepo repositories.ExchangePriceRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	logger *logrus.Logger,
+) *PriceAggregationService {
+	return &PriceAggregationService{
+		sources:           make(map[string]KlineSource),
+		exchangePriceRepo: exchangePriceRepo,
+		priceHistoryRepo:  priceHistoryRepo,
+		logger:            logger,
+		method:            AggregationMedian,
+		maxQuoteAge:       DefaultAggregationMaxQuoteAge,
+		clock:             clock.New(),
+	}
+}
+
+// AddExchange adds an exchange the symbols are collected from
+func (s *PriceAggregationService) AddExchange(name string, source KlineSource) {
+	s.sources[name] = source
+}
+
+// SetSymbols replaces the symbols whose prices are aggregated
+func (s *PriceAggregationService) SetSymbols(symbols []string) {
+	s.symbols = make([]string, len(symbols))
+	for i, symbol := range symbols {
+		s.symbols[i] = strings.ToUpper(symbol)
+	}
+}
+
+// SetMethod selects AggregationMedian or AggregationVWAP
+func (s *PriceAggregationService) SetMethod(method string) error {
+	switch method {
+	case AggregationMedian, AggregationVWAP:
+		s.method = method
+		return nil
+	default:
+		return fmt.Errorf("unsupported aggregation method %q", method)
+	}
+}
+
+// SetMaxQuoteAge sets how long past its candle's close an exchange's price counts in
+// the consolidated price
+func (s *PriceAggregationService) SetMaxQuoteAge(maxAge time.Duration) {
+	s.maxQuoteAge = maxAge
+}
+
+// SetHistoryRetention sets how long the prices of each exchange are kept; 0 keeps them
+func (s *PriceAggregationService) SetHistoryRetention(retention time.Duration) {
+	s.historyRetention = retention
+}
+
+// SetClock replaces the clock prices are stamped and judged stale by
+func (s *PriceAggregationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Enabled reports whether at least two exchanges and a symbol are configured
+func (s *PriceAggregationService) Enabled() bool {
+	return len(s.sources) >= 2 && len(s.symbols) > 0
+}
+
+// Aggregates reports whether the symbol's prices are aggregated
+func (s *PriceAggregationService) Aggregates(symbol string) bool {
+	return slices.Contains(s.symbols, strings.ToUpper(symbol))
+}
+
+// CollectOnce fetches every symbol from every exchange at once, stores the fresh
+// prices and their consolidated price, and deletes the exchange prices past the
+// history retention. An exchange failing leaves it out of the symbol's consolidated
+// price.
+func (s *PriceAggregationService) CollectOnce(ctx context.Context) (*AggregationRun, error) {
+	if !s.Enabled() {
+		return nil, ErrAggregationDisabled
+	}
+
+	run := &AggregationRun{}
+	now := s.clock.Now()
+	for _, symbol := range s.symbols {
+		quotes, failed := s.fetchQuotes(ctx, symbol, now)
+		run.Failed += failed
+		if len(quotes) == 0 {
+			run.Skipped++
+			continue
+		}
+
+		prices := make([]entities.ExchangePrice, len(quotes))
+		for i, quote := range quotes {
+			prices[i] = entities.ExchangePrice{Symbol: symbol, Exchange: quote.Exchange, Price: quote.Price, Volume: quote.Volume, Timestamp: now}
+		}
+		if err := s.exchangePriceRepo.BulkInsert(ctx, prices); err != nil {
+			return run, fmt.Errorf("failed to store exchange prices of %s: %w", symbol, err)
+		}
+		run.Quotes += len(prices)
+
+		price, volume := consolidate(quotes, s.method)
+		err := s.priceHistoryRepo.Create(ctx, &entities.PriceHistory{
+			Symbol:     entities.ConsolidatedSymbol(symbol),
+			Timeframe:  aggregationTimeframe,
+			OpenPrice:  price,
+			HighPrice:  price,
+			LowPrice:   price,
+			ClosePrice: price,
+			Volume:     volume,
+			Timestamp:  now,
+		})
+		if err != nil {
+			return run, fmt.Errorf("failed to store consolidated price of %s: %w", symbol, err)
+		}
+		run.Stored++
+	}
+
+	if s.historyRetention > 0 {
+		deleted, err := s.exchangePriceRepo.DeleteBefore(ctx, now.Add(-s.historyRetention))
+		if err != nil {
+			return run, fmt.Errorf("failed to delete old exchange prices: %w", err)
+		}
+		run.Deleted = deleted
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"stored":  run.Stored,
+		"quotes":  run.Quotes,
+		"failed":  run.Failed,
+		"skipped": run.Skipped,
+		"deleted": run.Deleted,
+	}).Debug("Collected aggregated prices")
+	return run, nil
+}
+
+// fetchQuotes fetches the symbol's latest 1m kline from every exchange concurrently
+// and returns the fresh prices ordered by exchange, with how many were missing
+func (s *PriceAggregationService) fetchQuotes(ctx context.Context, symbol string, now time.Time) ([]ExchangeQuote, int) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		quotes []ExchangeQuote
+		failed int
+	)
+	for exchange, source := range s.sources {
+		wg.Add(1)
+		go func(exchange string, source KlineSource) {
+			defer wg.Done()
+
+			quote, err := s.fetchQuote(ctx, exchange, source, symbol, now)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"exchange": exchange,
+					"symbol":   symbol,
+				}).Warn("Failed to fetch exchange price")
+				failed++
+				return
+			}
+			quotes = append(quotes, *quote)
+		}(exchange, source)
+	}
+	wg.Wait()
+
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Exchange < quotes[j].Exchange })
+	return quotes, failed
+}
+
+func (s *PriceAggregationService) fetchQuote(ctx context.Context, exchange string, source KlineSource, symbol string, now time.Time) (*ExchangeQuote, error) {
+	klines, err := source.GetKlines(ctx, symbol, aggregationTimeframe, 1, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, errors.New("no kline returned")
+	}
+	candle, ok := klineHistory(symbol, aggregationTimeframe, klines[len(klines)-1])
+	if !ok {
+		return nil, errors.New("malformed kline")
+	}
+	if s.maxQuoteAge > 0 && dataAge(candle.Timestamp, aggregationTimeframe, now) > s.maxQuoteAge {
+		return nil, fmt.Errorf("latest kline opened at %s is stale", candle.Timestamp.Format(time.RFC3339))
+	}
+
+	return &ExchangeQuote{Exchange: exchange, Price: candle.ClosePrice, Volume: candle.Volume, Timestamp: now}, nil
+}
+
+// GetConsolidatedPrice consolidates the latest stored prices of the symbol on each
+// exchange that are no older than the max quote age
+func (s *PriceAggregationService) GetConsolidatedPrice(ctx context.Context, symbol string) (*ConsolidatedPrice, error) {
+	symbol = strings.ToUpper(symbol)
+	if !s.Aggregates(symbol) {
+		return nil, ErrSymbolNotAggregated
+	}
+
+	now := s.clock.Now()
+	prices, err := s.exchangePriceRepo.GetLatest(ctx, symbol, now.Add(-s.maxQuoteAge))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange prices: %w", err)
+	}
+	if len(prices) == 0 {
+		return nil, ErrNoFreshQuotes
+	}
+
+	consolidated := &ConsolidatedPrice{
+		Symbol:      symbol,
+		AlertSymbol: entities.ConsolidatedSymbol(symbol),
+		Method:      s.method,
+		Quotes:      make([]ExchangeQuote, len(prices)),
+	}
+	for i, price := range prices {
+		quote := ExchangeQuote{Exchange: price.Exchange, Price: price.Price, Volume: price.Volume, Timestamp: price.Timestamp}
+		consolidated.Quotes[i] = quote
+		if i == 0 || quote.Price < consolidated.BestBuy.Price {
+			consolidated.BestBuy = quote
+		}
+		if i == 0 || quote.Price > consolidated.BestSell.Price {
+			consolidated.BestSell = quote
+		}
+		if quote.Timestamp.After(consolidated.Timestamp) {
+			consolidated.Timestamp = quote.Timestamp
+		}
+	}
+	consolidated.Price, consolidated.Volume = consolidate(consolidated.Quotes, s.method)
+	if consolidated.BestBuy.Price > 0 {
+		consolidated.SpreadPercent = (consolidated.BestSell.Price - consolidated.BestBuy.Price) / consolidated.BestBuy.Price * 100
+	}
+	return consolidated, nil
+}
+
+// CheckAlert checks an alert on a consolidated symbol: the symbol's prices must be
+// aggregated, the alert type one of ConsolidatedAlertTypes and the timeframe 1m.
+// Alerts on market symbols pass.
+func (s *PriceAggregationService) CheckAlert(ctx context.Context, alert *entities.Alert) error {
+	if !entities.IsConsolidatedSymbol(alert.Symbol) {
+		return nil
+	}
+
+	if !s.Aggregates(entities.ConsolidatedMarket(alert.Symbol)) {
+		return &entities.ValidationError{Entity: "alert", Field: "symbol", Message: "symbol is not aggregated"}
+	}
+	if !slices.Contains(ConsolidatedAlertTypes, alert.AlertType) {
+		return &entities.ValidationError{Entity: "alert", Field: "alert_type", Message: fmt.Sprintf("alerts on consolidated prices must be of type %s", strings.Join(ConsolidatedAlertTypes, " or "))}
+	}
+	if alert.Timeframe != aggregationTimeframe {
+		return &entities.ValidationError{Entity: "alert", Field: "timeframe", Message: fmt.Sprintf("consolidated prices are computed on %s", aggregationTimeframe)}
+	}
+	return nil
+}
+
+// consolidate returns the consolidated price of the quotes by the method and their
+// total volume. VWAP falls back to the median when no volume was traded.
+func consolidate(quotes []ExchangeQuote, method string) (float64, float64) {
+	var volume, notional float64
+	for _, quote := range quotes {
+		volume += quote.Volume
+		notional += quote.Price * quote.Volume
+	}
+	if method == AggregationVWAP && volume > 0 {
+		return notional / volume, volume
+	}
+
+	prices := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Price
+	}
+	sort.Float64s(prices)
+	middle := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[middle-1] + prices[middle]) / 2, volume
+	}
+	return prices[middle], volume
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// Price history buffer defaults
+const (
+	DefaultPriceBufferFlushSize     = 500
+	DefaultPriceBufferFlushInterval = 5 * time.Second
+	// priceBufferBacklogFactor bounds the rows kept for retry after failed flushes,
+	// in flush sizes, so an unavailable database can't exhaust memory
+	priceBufferBacklogFactor = 10
+)
+
+// PriceHistoryBuffer batches collected candles and stores them with BulkInsert once
+// FlushSize rows are waiting or FlushInterval passed, instead of one insert per
+// candle, so collection keeps up with thousands of symbols
+type PriceHistoryBuffer struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	logger           *logrus.Logger
+
+	flushSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []entities.PriceHistory
+	// flushMu serializes the inserts, so batches are stored in the order they were taken
+	flushMu sync.Mutex
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	runMu     sync.Mutex
+}
+
+// NewPriceHistoryBuffer creates a buffer flushing every DefaultPriceBufferFlushSize
+// rows or DefaultPriceBufferFlushInterval
+func NewPriceHistoryBuffer(priceHistoryRepo repositories.PriceHistoryRepository, logger *logrus.Logger) *PriceHistoryBuffer {
+	return &PriceHistoryBuffer{
+		priceHistoryRepo: priceHistoryRepo,
+		logger:           logger,
+		flushSize:        DefaultPriceBufferFlushSize,
+		flushInterval:    DefaultPriceBufferFlushInterval,
+	}
+}
+
+// SetFlushSize sets how many waiting rows trigger a flush; values below 1 are ignored
+func (b *PriceHistoryBuffer) SetFlushSize(size int) {
+	if size > 0 {
+		b.flushSize = size
+	}
+}
+
+// SetFlushInterval sets how often waiting rows are flushed whatever their number;
+// it takes effect on the next Start, and values below 1 are ignored
+func (b *PriceHistoryBuffer) SetFlushInterval(interval time.Duration) {
+	if interval > 0 {
+		b.flushInterval = interval
+	}
+}
+
+// Add queues the candle, flushing the queue each time it grows by the flush size;
+// after a failed flush that is also how often the backlog is retried
+func (b *PriceHistoryBuffer) Add(ctx context.Context, history entities.PriceHistory) {
+	b.mu.Lock()
+	b.pending = append(b.pending, history)
+	full := len(b.pending)%b.flushSize == 0
+	b.mu.Unlock()
+
+	if full {
+		if err := b.Flush(ctx); err != nil {
+			b.logger.WithError(err).Error("Failed to flush price history")
+		}
+	}
+}
+
+// Pending returns how many rows are waiting to be stored
+func (b *PriceHistoryBuffer) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+// Flush stores the waiting rows in one bulk insert. When it fails the rows are queued
+// again ahead of the newer ones, up to a backlog of a few flush sizes beyond which
+// the oldest are dropped.
+func (b *PriceHistoryBuffer) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := b.priceHistoryRepo.BulkInsert(ctx, batch); err != nil {
+		b.requeue(batch)
+		return fmt.Errorf("failed to store %d candles: %w", len(batch), err)
+	}
+
+	b.logger.WithField("candles", len(batch)).Debug("Flushed price history")
+	return nil
+}
+
+func (b *PriceHistoryBuffer) requeue(batch []entities.PriceHistory) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(batch, b.pending...)
+	if backlog := b.flushSize * priceBufferBacklogFactor; len(b.pending) > backlog {
+		dropped := len(b.pending) - backlog
+		b.pending = append([]entities.PriceHistory(nil), b.pending[dropped:]...)
+		b.logger.WithField("dropped", dropped).Warn("Price history backlog full, dropping the oldest candles")
+	}
+}
+
+// Start flushes the waiting rows every flush interval until Stop is called or the
+// context is done
+func (b *PriceHistoryBuffer) Start(ctx context.Context) {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	if b.isRunning {
+		return
+	}
+	b.isRunning = true
+	b.stopChan = make(chan struct{})
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(b.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.stopChan:
+				return
+			case <-ticker.C:
+				if err := b.Flush(ctx); err != nil {
+					b.logger.WithError(err).Error("Failed to flush price history")
+				}
+			}
+		}
+	}()
+}
+
+// Stop halts the periodic flush and stores the rows still waiting
+func (b *PriceHistoryBuffer) Stop() {
+	b.runMu.Lock()
+	if b.isRunning {
+		b.isRunning = false
+		close(b.stopChan)
+	}
+	b.runMu.Unlock()
+	b.wg.Wait()
+
+	if err := b.Flush(context.Background()); err != nil {
+		b.logger.WithError(err).Error("Failed to flush price history on stop")
+	}
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// priceRetentionLockKey keeps replicas from applying the retention policies twice
+// in the same interval
+const priceRetentionLockKey = "price_retention"
+
+// RetentionPolicy keeps the candles of Timeframe for KeepDays days. Older candles
+// are first aggregated into each of the DownsampleTo timeframes, where those
+// candles are missing, and then deleted.
+type RetentionPolicy struct {
+	Timeframe    string   `json:"timeframe"`
+	KeepDays     int      `json:"keep_days"`
+	DownsampleTo []string `json:"downsample_to,omitempty"`
+}
+
+// Validate checks the policy's timeframes: each downsampled timeframe must be a
+// whole number of source candles and fit in a day, so aggregates never straddle
+// the windows they are built in
+func (p RetentionPolicy) Validate() error {
+	step := indicators.GetTimeframeMilliseconds(p.Timeframe)
+	if step == 0 {
+		return retentionValidationError("timeframe", fmt.Sprintf("unsupported timeframe %q", p.Timeframe))
+	}
+	if p.KeepDays <= 0 {
+		return retentionValidationError("keep_days", "must be positive")
+	}
+	for _, target := range p.DownsampleTo {
+		targetStep := indicators.GetTimeframeMilliseconds(target)
+		switch {
+		case targetStep == 0:
+			return retentionValidationError("downsample_to", fmt.Sprintf("unsupported timeframe %q", target))
+		case targetStep <= step || targetStep%step != 0:
+			return retentionValidationError("downsample_to", fmt.Sprintf("%s is not a multiple of %s", target, p.Timeframe))
+		case targetStep > retentionWindow.Milliseconds():
+			return retentionValidationError("downsample_to", fmt.Sprintf("%s is longer than a day", target))
+		}
+	}
+	return nil
+}
+
+func retentionValidationError(field, message string) error {
+	return &entities.ValidationError{Entity: "retention_policy", Field: field, Message: message}
+}
+
+// retentionWindow is how much of a series is downsampled at a time
+const retentionWindow = 24 * time.Hour
+
+// RetentionResult is what a policy did, or would do in a dry run, to one symbol
+type RetentionResult struct {
+	Symbol    string    `json:"symbol"`
+	Timeframe string    `json:"timeframe"`
+	Cutoff    time.Time `json:"cutoff"`
+	// Downsampled counts the aggregates stored per timeframe
+	Downsampled map[string]int `json:"downsampled,omitempty"`
+	Deleted     int64          `json:"deleted"`
+}
+
+// RetentionRun summarizes one pass over the retention policies
+type RetentionRun struct {
+	DryRun      bool              `json:"dry_run"`
+	Results     []RetentionResult `json:"results"`
+	Downsampled int               `json:"downsampled"`
+	Deleted     int64             `json:"deleted"`
+	Failed      int               `json:"failed"` // Symbols left for the next run after an error
+	Duration    time.Duration     `json:"duration"`
+}
+
+// PriceRetentionService bounds the growth of price_history by applying retention
+// policies on a schedule, e.g. keeping 1m candles for 7 days once they are
+// aggregated into 1h and 1d candles. A dry run reports what would be stored and
+// deleted without changing anything.
+type PriceRetentionService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	policies         []RetentionPolicy
+	dryRun           bool
+	logger           *logrus.Logger
+
+	// runs makes sure each interval is applied by one instance only
+	runs ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewPriceRetentionService creates a retention service without policies, which
+// keeps every candle
+func NewPriceRetentionService(priceHistoryRepo repositories.PriceHistoryRepository, logger *logrus.Logger) *PriceRetentionService {
+	return &PriceRetentionService{
+		priceHistoryRepo: priceHistoryRepo,
+		logger:           logger,
+		runs:             NewMemoryThrottleStore(),
+	}
+}
+
+// SetPolicies replaces the policies applied, rejecting them all if one is invalid
+func (s *PriceRetentionService) SetPolicies(policies []RetentionPolicy) error {
+	for _, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+	s.policies = policies
+	return nil
+}
+
+// Policies returns the policies applied
+func (s *PriceRetentionService) Policies() []RetentionPolicy {
+	return s.policies
+}
+
+// SetDryRun makes the scheduled runs only report what they would do
+func (s *PriceRetentionService) SetDryRun(dryRun bool) {
+	s.dryRun = dryRun
+}
+
+// SetThrottleStore replaces the in-process run lock, e.g. with a Redis store so only
+// one replica applies the policies each interval
+func (s *PriceRetentionService) SetThrottleStore(store ThrottleStore) {
+	s.runs = store
+}
+
+// Start applies the policies once and then every interval until Stop is called
+func (s *PriceRetentionService) Start(ctx context.Context, interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isRunning {
+		s.logger.Warn("Price retention is already running")
+		return
+	}
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.logger.WithFields(logrus.Fields{
+		"interval": interval,
+		"policies": len(s.policies),
+		"dry_run":  s.dryRun,
+	}).Info("Starting price retention")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the retention and waits for the current run to finish
+func (s *PriceRetentionService) Stop() {
+	s.mutex.Lock()
+	if !s.isRunning {
+		s.mutex.Unlock()
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	s.logger.Info("Price retention stopped")
+}
+
+// runScheduled runs unless another instance already took this interval
+func (s *PriceRetentionService) runScheduled(ctx context.Context, interval time.Duration) {
+	acquired, err := s.runs.Acquire(ctx, priceRetentionLockKey, interval)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to acquire price retention lock")
+	} else if !acquired {
+		return
+	}
+
+	if _, err := s.RunOnce(ctx, s.dryRun); err != nil {
+		s.logger.WithError(err).Error("Failed to apply price retention")
+	}
+}
+
+// RunOnce applies every policy to every symbol now. A symbol that fails is logged,
+// counted and retried on the next run; the others go on.
+func (s *PriceRetentionService) RunOnce(ctx context.Context, dryRun bool) (*RetentionRun, error) {
+	started := time.Now()
+	run := &RetentionRun{DryRun: dryRun, Results: []RetentionResult{}}
+
+	for _, policy := range s.policies {
+		symbols, err := s.priceHistoryRepo.GetSymbols(ctx, policy.Timeframe)
+		if err != nil {
+			return run, fmt.Errorf("failed to get symbols with %s candles: %w", policy.Timeframe, err)
+		}
+
+		for _, symbol := range symbols {
+			if err := ctx.Err(); err != nil {
+				return run, err
+			}
+			result, err := s.apply(ctx, policy, symbol, dryRun)
+			if err != nil {
+				run.Failed++
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"symbol":    symbol,
+					"timeframe": policy.Timeframe,
+				}).Error("Failed to apply retention policy")
+				continue
+			}
+			if result.Deleted == 0 && len(result.Downsampled) == 0 {
+				continue
+			}
+			run.Results = append(run.Results, *result)
+			run.Deleted += result.Deleted
+			for _, stored := range result.Downsampled {
+				run.Downsampled += stored
+			}
+		}
+	}
+	run.Duration = time.Since(started)
+
+	s.logger.WithFields(logrus.Fields{
+		"dry_run":     dryRun,
+		"downsampled": run.Downsampled,
+		"deleted":     run.Deleted,
+		"failed":      run.Failed,
+		"duration":    run.Duration,
+	}).Info("Price retention applied")
+	return run, nil
+}
+
+// apply downsamples the candles of the symbol the policy expires and then deletes
+// them. Aggregates are stored before anything is deleted, so a failure loses no data.
+func (s *PriceRetentionService) apply(ctx context.Context, policy RetentionPolicy, symbol string, dryRun bool) (*RetentionResult, error) {
+	// DeleteOld takes the same cutoff from the current time
+	cutoff := time.Now().AddDate(0, 0, -policy.KeepDays)
+	result := &RetentionResult{Symbol: symbol, Timeframe: policy.Timeframe, Cutoff: cutoff}
+
+	expired, err := s.priceHistoryRepo.CountBefore(ctx, symbol, policy.Timeframe, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count expired candles: %w", err)
+	}
+	if expired == 0 {
+		return result, nil
+	}
+
+	if len(policy.DownsampleTo) > 0 {
+		if err := s.downsample(ctx, policy, symbol, cutoff, dryRun, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Deleted = expired
+	if !dryRun {
+		if err := s.priceHistoryRepo.DeleteOld(ctx, symbol, policy.Timeframe, policy.KeepDays); err != nil {
+			return nil, fmt.Errorf("failed to delete expired candles: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// downsample aggregates the symbol's candles from the oldest up to the end of the
+// day holding the cutoff, a day at a time, storing the aggregates missing. The day
+// is complete by then, so its aggregates are final even though some of its source
+// candles are kept a little longer.
+func (s *PriceRetentionService) downsample(ctx context.Context, policy RetentionPolicy, symbol string, cutoff time.Time, dryRun bool, result *RetentionResult) error {
+	oldest, err := s.priceHistoryRepo.GetOldest(ctx, symbol, policy.Timeframe)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get oldest candle: %w", err)
+	}
+
+	result.Downsampled = make(map[string]int)
+	end := cutoff.UTC().Truncate(retentionWindow).Add(retentionWindow)
+	for from := oldest.Timestamp.UTC().Truncate(retentionWindow); from.Before(end); from = from.Add(retentionWindow) {
+		to := from.Add(retentionWindow - time.Millisecond)
+		candles, err := s.priceHistoryRepo.GetByTimeRange(ctx, symbol, policy.Timeframe, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to get candles from %s: %w", from, err)
+		}
+		if len(candles) == 0 {
+			continue
+		}
+
+		for _, target := range policy.DownsampleTo {
+			stored, err := s.storeMissing(ctx, symbol, target, from, to, indicators.Resample(candles, target), dryRun)
+			if err != nil {
+				return err
+			}
+			result.Downsampled[target] += stored
+		}
+	}
+
+	for target, stored := range result.Downsampled {
+		if stored == 0 {
+			delete(result.Downsampled, target)
+		}
+	}
+	if len(result.Downsampled) == 0 {
+		result.Downsampled = nil
+	}
+	return nil
+}
+
+// storeMissing stores the aggregates whose candle isn't stored yet, keeping the
+// candles collected from the exchange, and returns how many it stored
+func (s *PriceRetentionService) storeMissing(ctx context.Context, symbol, timeframe string, from, to time.Time, aggregates []entities.PriceHistory, dryRun bool) (int, error) {
+	stored, err := s.priceHistoryRepo.GetByTimeRange(ctx, symbol, timeframe, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stored %s candles: %w", timeframe, err)
+	}
+	existing := make(map[int64]bool, len(stored))
+	for _, candle := range stored {
+		existing[candle.Timestamp.UnixMilli()] = true
+	}
+
+	var missing []entities.PriceHistory
+	for _, aggregate := range aggregates {
+		if !existing[aggregate.Timestamp.UnixMilli()] {
+			missing = append(missing, aggregate)
+		}
+	}
+	if !dryRun {
+		if err := s.priceHistoryRepo.BulkInsert(ctx, missing); err != nil {
+			return 0, fmt.Errorf("failed to store %s aggregates: %w", timeframe, err)
+		}
+	}
+	return len(missing), nil
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Price summary defaults
+const (
+	DefaultSummaryTimeframe    = "1h"
+	DefaultSummaryBackfillDays = 30
+	DefaultSummaryDays         = 30
+	MaxSummaryDays             = 365
+)
+
+// oneDay is the period summarized into one row
+const oneDay = 24 * time.Hour
+
+// SummaryRun summarizes one compaction of the candles into daily summaries
+type SummaryRun struct {
+	Symbols int `json:"symbols"`
+	Stored  int `json:"stored"` // Daily summaries written, the current day's included
+	Failed  int `json:"failed"` // Symbols left for the next run after an error
+}
+
+// PriceSummaryService compacts the intraday candles of every symbol into daily
+// summaries of their OHLCV and volatility, so queries over weeks or months read a
+// row per day instead of scanning price_history. Each run picks up at the latest
+// summarized day, which it rewrites since the day may not have closed yet.
+type PriceSummaryService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	summaryRepo      repositories.PriceSummaryRepository
+	logger           *logrus.Logger
+
+	timeframe    string
+	backfillDays int
+	clock        clock.Clock
+}
+
+// NewPriceSummaryService creates a service summarizing the 1h candles of the last
+// DefaultSummaryBackfillDays days on its first run
+func NewPriceSummaryService(
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	summaryRepo repositories.PriceSummaryRepository,
+	logger *logrus.Logger,
+) *PriceSummaryService {
+	return &PriceSummaryService{
+		priceHistoryRepo: priceHistoryRepo,
+		summaryRepo:      summaryRepo,
+		logger:           logger,
+		timeframe:        DefaultSummaryTimeframe,
+		backfillDays:     DefaultSummaryBackfillDays,
+		clock:            clock.New(),
+	}
+}
+
+// SetTimeframe selects the timeframe of the candles summarized, which must divide a day
+func (s *PriceSummaryService) SetTimeframe(timeframe string) error {
+	step := indicators.ParseTimeframe(timeframe)
+	if step == 0 || step > oneDay.Milliseconds() || oneDay.Milliseconds()%step != 0 {
+		return fmt.Errorf("timeframe %q does not divide a day", timeframe)
+	}
+	s.timeframe = timeframe
+	return nil
+}
+
+// SetBackfillDays sets how many days back a symbol without summaries is summarized from
+func (s *PriceSummaryService) SetBackfillDays(days int) {
+	if days > 0 {
+		s.backfillDays = days
+	}
+}
+
+// SetClock replaces the clock deciding the current day
+func (s *PriceSummaryService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// CompactOnce summarizes, for every symbol with candles on the timeframe, the days
+// since its latest summary. A symbol failing is left for the next run.
+func (s *PriceSummaryService) CompactOnce(ctx context.Context) (*SummaryRun, error) {
+	symbols, err := s.priceHistoryRepo.GetSymbols(ctx, s.timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbols: %w", err)
+	}
+
+	run := &SummaryRun{Symbols: len(symbols)}
+	now := s.clock.Now()
+	var errs []error
+	for _, symbol := range symbols {
+		stored, err := s.compactSymbol(ctx, symbol, now)
+		if err != nil {
+			run.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		run.Stored += stored
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbols": run.Symbols,
+		"stored":  run.Stored,
+		"failed":  run.Failed,
+	}).Debug("Compacted daily price summaries")
+	return run, errors.Join(errs...)
+}
+
+func (s *PriceSummaryService) compactSymbol(ctx context.Context, symbol string, now time.Time) (int, error) {
+	from := startOfDay(now).AddDate(0, 0, -s.backfillDays)
+	latest, err := s.summaryRepo.GetLatest(ctx, symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest summary: %w", err)
+	}
+	if latest != nil && latest.Day.After(from) {
+		from = latest.Day
+	}
+
+	candles, err := s.priceHistoryRepo.GetByTimeRange(ctx, symbol, s.timeframe, from, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get candles: %w", err)
+	}
+	summaries := summarizeDays(symbol, s.timeframe, candles)
+	if err := s.summaryRepo.Upsert(ctx, summaries); err != nil {
+		return 0, fmt.Errorf("failed to store summaries: %w", err)
+	}
+	return len(summaries), nil
+}
+
+// GetDailySummaries returns the summaries of the symbol's last days, today's
+// included, oldest first
+func (s *PriceSummaryService) GetDailySummaries(ctx context.Context, symbol string, days int) ([]entities.PriceSummary, error) {
+	if days <= 0 {
+		days = DefaultSummaryDays
+	}
+	if days > MaxSummaryDays {
+		days = MaxSummaryDays
+	}
+
+	now := s.clock.Now()
+	summaries, err := s.summaryRepo.GetByRange(ctx, symbol, startOfDay(now).AddDate(0, 0, 1-days), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+	if len(summaries) == 0 {
+		return nil, entities.NewDomainError(entities.ErrNotFound, "no daily summaries for %s", symbol)
+	}
+	return summaries, nil
+}
+
+// summarizeDays groups candles ordered oldest first by UTC day. The volatility of a
+// day is the standard deviation of the log returns between its closes, scaled by
+// the square root of the candles in a day.
+func summarizeDays(symbol, timeframe string, candles []entities.PriceHistory) []entities.PriceSummary {
+	candlesPerDay := 0.0
+	if step := indicators.ParseTimeframe(timeframe); step > 0 {
+		candlesPerDay = float64(oneDay.Milliseconds() / step)
+	}
+
+	var summaries []entities.PriceSummary
+	for start := 0; start < len(candles); {
+		dayStart := startOfDay(candles[start].Timestamp)
+		end := start
+		for end < len(candles) && startOfDay(candles[end].Timestamp).Equal(dayStart) {
+			end++
+		}
+		dayCandles := candles[start:end]
+		start = end
+
+		summary := entities.PriceSummary{
+			Symbol:          symbol,
+			Day:             dayStart,
+			OpenPrice:       dayCandles[0].OpenPrice,
+			HighPrice:       dayCandles[0].HighPrice,
+			LowPrice:        dayCandles[0].LowPrice,
+			ClosePrice:      dayCandles[len(dayCandles)-1].ClosePrice,
+			Candles:         len(dayCandles),
+			SourceTimeframe: timeframe,
+		}
+		closes := make([]float64, len(dayCandles))
+		for i, candle := range dayCandles {
+			summary.HighPrice = math.Max(summary.HighPrice, candle.HighPrice)
+			summary.LowPrice = math.Min(summary.LowPrice, candle.LowPrice)
+			summary.Volume += candle.Volume
+			closes[i] = candle.ClosePrice
+		}
+		summary.Volatility = roundStat(returnsStdDev(closes) * math.Sqrt(candlesPerDay) * 100)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// returnsStdDev is the sample standard deviation of the log returns between closes,
+// 0 with fewer than two returns or a non-positive close
+func returnsStdDev(closes []float64) float64 {
+	if len(closes) < 3 {
+		return 0
+	}
+	returns := make([]float64, 0, len(closes)-1)
+	var mean float64
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			return 0
+		}
+		r := math.Log(closes[i] / closes[i-1])
+		returns = append(returns, r)
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// startOfDay is midnight UTC of the day t falls on
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(oneDay)
+}
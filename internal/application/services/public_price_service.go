@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"gorm.io/gorm"
+)
+
+const (
+	// PublicTickerTTL is how long a computed ticker is served before it is
+	// recomputed; public clients may cache responses for as long
+	PublicTickerTTL = 15 * time.Second
+	// MaxPublicTickerSymbols caps the symbols of one public ticker request
+	MaxPublicTickerSymbols = 20
+
+	// publicTickerTimeframe holds the real-time prices collected every minute
+	publicTickerTimeframe = "1m"
+	// publicTickerCacheSize bounds the cached tickers, missing symbols included
+	publicTickerCacheSize = 2000
+)
+
+// ErrPublicTickerNotFound is returned for symbols without recent prices
+var ErrPublicTickerNotFound = entities.NewDomainError(entities.ErrNotFound, "no prices for symbol")
+
+// PublicTicker is the latest price of a symbol and its rolling 24h statistics
+type PublicTicker struct {
+	Symbol           string    `json:"symbol"`
+	Price            float64   `json:"price"`
+	Open24h          float64   `json:"open_24h"`
+	High24h          float64   `json:"high_24h"`
+	Low24h           float64   `json:"low_24h"`
+	Volume24h        float64   `json:"volume_24h"`
+	Change24h        float64   `json:"change_24h"`
+	ChangePercent24h float64   `json:"change_percent_24h"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// PublicPriceService serves the latest prices and 24h statistics of the public
+// market data API. Tickers are cached for PublicTickerTTL, so however many clients
+// poll a symbol it costs the database a couple of queries per TTL.
+type PublicPriceService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	cache            *cache.MemoryCache
+	clock            clock.Clock
+}
+
+// NewPublicPriceService creates a new public price service
+func NewPublicPriceService(priceHistoryRepo repositories.PriceHistoryRepository) *PublicPriceService {
+	return &PublicPriceService{
+		priceHistoryRepo: priceHistoryRepo,
+		cache:            cache.NewMemoryCache(publicTickerCacheSize, time.Minute),
+		clock:            clock.New(),
+	}
+}
+
+// SetClock replaces the clock the 24h window and the cache expire with
+func (s *PublicPriceService) SetClock(c clock.Clock) {
+	s.clock = c
+	s.cache.SetClock(c)
+}
+
+// GetTicker returns the ticker of one symbol
+func (s *PublicPriceService) GetTicker(ctx context.Context, symbol string) (*PublicTicker, error) {
+	cacheKey := "public_ticker:" + symbol
+	if cached, ok := s.cache.Get(cacheKey); ok {
+		// Symbols without prices are cached too, so polling them stays cheap
+		if ticker := cached.(*PublicTicker); ticker != nil {
+			return ticker, nil
+		}
+		return nil, ErrPublicTickerNotFound
+	}
+
+	ticker, err := s.computeTicker(ctx, symbol)
+	if err != nil && !errors.Is(err, ErrPublicTickerNotFound) {
+		return nil, err
+	}
+	s.cache.Set(cacheKey, ticker, PublicTickerTTL)
+	if ticker == nil {
+		return nil, ErrPublicTickerNotFound
+	}
+	return ticker, nil
+}
+
+// GetTickers returns the tickers of the symbols that have prices, in the order
+// they were asked for
+func (s *PublicPriceService) GetTickers(ctx context.Context, symbols []string) ([]PublicTicker, error) {
+	tickers := make([]PublicTicker, 0, len(symbols))
+	for _, symbol := range symbols {
+		ticker, err := s.GetTicker(ctx, symbol)
+		if errors.Is(err, ErrPublicTickerNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, *ticker)
+	}
+	return tickers, nil
+}
+
+// WarmTickers recomputes and caches the tickers of symbols, so the next requests for
+// them hit the cache; it returns how many symbols had prices
+func (s *PublicPriceService) WarmTickers(ctx context.Context, symbols []string) (int, error) {
+	warmed := 0
+	for _, symbol := range symbols {
+		if err := ctx.Err(); err != nil {
+			return warmed, err
+		}
+		ticker, err := s.computeTicker(ctx, symbol)
+		if err != nil && !errors.Is(err, ErrPublicTickerNotFound) {
+			return warmed, fmt.Errorf("failed to warm the ticker of %s: %w", symbol, err)
+		}
+		s.cache.Set("public_ticker:"+symbol, ticker, PublicTickerTTL)
+		if ticker != nil {
+			warmed++
+		}
+	}
+	return warmed, nil
+}
+
+// computeTicker reads the latest price and the prices of the 24h before it
+func (s *PublicPriceService) computeTicker(ctx context.Context, symbol string) (*PublicTicker, error) {
+	latest, err := s.priceHistoryRepo.GetLatest(ctx, symbol, publicTickerTimeframe)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPublicTickerNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest price: %w", err)
+	}
+
+	// A symbol that stopped being collected has no current price to show
+	now := s.clock.Now()
+	if latest.Timestamp.Before(now.Add(-24 * time.Hour)) {
+		return nil, ErrPublicTickerNotFound
+	}
+
+	history, err := s.priceHistoryRepo.GetByTimeRange(ctx, symbol, publicTickerTimeframe, now.Add(-24*time.Hour), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h prices: %w", err)
+	}
+
+	ticker := &PublicTicker{
+		Symbol:    symbol,
+		Price:     latest.ClosePrice,
+		Open24h:   latest.OpenPrice,
+		High24h:   latest.HighPrice,
+		Low24h:    latest.LowPrice,
+		UpdatedAt: latest.Timestamp,
+	}
+	if len(history) > 0 {
+		ticker.Open24h = history[0].OpenPrice
+		ticker.High24h = 0
+		ticker.Low24h = math.MaxFloat64
+		for _, candle := range history {
+			ticker.High24h = math.Max(ticker.High24h, candle.HighPrice)
+			ticker.Low24h = math.Min(ticker.Low24h, candle.LowPrice)
+			ticker.Volume24h += candle.Volume
+		}
+	}
+
+	ticker.Change24h = ticker.Price - ticker.Open24h
+	if ticker.Open24h != 0 {
+		ticker.ChangePercent24h = ticker.Change24h / ticker.Open24h * 100
+	}
+	return ticker, nil
+}
@@ -20,18 +20,19 @@ type PullbackEntryService struct {
 
 // PullbackEntry represents a pullback entry signal
 type PullbackEntry struct {
-	Symbol      string    `json:"symbol"`
-	Timeframe   string    `json:"timeframe"`
-	Signal      string    `json:"signal"`     // "LONG", "SHORT", "NEUTRAL"
-	Confidence  float64   `json:"confidence"` // 0-100
-	EntryPrice  float64   `json:"entry_price"`
-	StopLoss    float64   `json:"stop_loss"`
-	TakeProfit1 float64   `json:"take_profit_1"`
-	TakeProfit2 float64   `json:"take_profit_2"`
-	RSI         float64   `json:"rsi"`
-	EMATrend    string    `json:"ema_trend"`
-	SuperTrend  string    `json:"supertrend"`
-	Timestamp   time.Time `json:"timestamp"`
+	Symbol        string    `json:"symbol"`
+	Timeframe     string    `json:"timeframe"`
+	Signal        string    `json:"signal"`     // "LONG", "SHORT", "NEUTRAL"
+	Confidence    float64   `json:"confidence"` // 0-100
+	EntryPrice    float64   `json:"entry_price"`
+	StopLoss      float64   `json:"stop_loss"`
+	TakeProfit1   float64   `json:"take_profit_1"`
+	TakeProfit2   float64   `json:"take_profit_2"`
+	RSI           float64   `json:"rsi"`
+	EMATrend      string    `json:"ema_trend"`
+	SuperTrend    string    `json:"supertrend"`
+	TrendStrength float64   `json:"trend_strength"` // ADX, 0 when it hasn't been calculated
+	Timestamp     time.Time `json:"timestamp"`
 }
 
 // NewPullbackEntryService creates a new pullback entry service
@@ -61,7 +62,7 @@ func (s *PullbackEntryService) AnalyzePullbackEntry(ctx context.Context, symbol,
 	}
 
 	if len(priceHistory) < 20 {
-		return nil, fmt.Errorf("insufficient price data for pullback analysis")
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for pullback analysis")
 	}
 
 	// Get current price
@@ -78,15 +79,16 @@ func (s *PullbackEntryService) AnalyzePullbackEntry(ctx context.Context, symbol,
 
 	// Calculate entry levels
 	entry := &PullbackEntry{
-		Symbol:     symbol,
-		Timeframe:  timeframe,
-		Signal:     signal.Signal,
-		Confidence: signal.Confidence,
-		EntryPrice: currentPrice,
-		RSI:        signal.RSI,
-		EMATrend:   signal.EMATrend,
-		SuperTrend: signal.SuperTrend,
-		Timestamp:  time.Now(),
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		Signal:        signal.Signal,
+		Confidence:    signal.Confidence,
+		EntryPrice:    currentPrice,
+		RSI:           signal.RSI,
+		EMATrend:      signal.EMATrend,
+		SuperTrend:    signal.SuperTrend,
+		TrendStrength: signal.TrendStrength,
+		Timestamp:     time.Now(),
 	}
 
 	// Calculate risk management levels
@@ -97,11 +99,12 @@ func (s *PullbackEntryService) AnalyzePullbackEntry(ctx context.Context, symbol,
 
 // PullbackSignal represents the analysis result
 type PullbackSignal struct {
-	Signal     string
-	Confidence float64
-	RSI        float64
-	EMATrend   string
-	SuperTrend string
+	Signal        string
+	Confidence    float64
+	RSI           float64
+	EMATrend      string
+	SuperTrend    string
+	TrendStrength float64
 }
 
 // analyzePullbackSignal analyzes price action and indicators for pullback signals
@@ -112,7 +115,7 @@ func (s *PullbackEntryService) analyzePullbackSignal(priceHistory []entities.Pri
 	}
 
 	// Analyze RSI
-	if rsiIndicator, exists := indicators["RSI"]; exists && rsiIndicator.Value != nil {
+	if rsiIndicator, exists := indicators[entities.IndicatorKey("RSI", rsiPeriod)]; exists && rsiIndicator.Value != nil {
 		signal.RSI = *rsiIndicator.Value
 
 		// RSI oversold condition for long entries
@@ -132,8 +135,8 @@ func (s *PullbackEntryService) analyzePullbackSignal(priceHistory []entities.Pri
 	}
 
 	// Analyze EMA trend
-	ema12 := s.getIndicatorValue(indicators, "EMA", 12)
-	ema26 := s.getIndicatorValue(indicators, "EMA", 26)
+	ema12 := s.getIndicatorValue(indicators, "EMA", emaFastPeriod)
+	ema26 := s.getIndicatorValue(indicators, "EMA", emaSlowPeriod)
 
 	if ema12 != nil && ema26 != nil {
 		if *ema12 > *ema26 {
@@ -150,7 +153,7 @@ func (s *PullbackEntryService) analyzePullbackSignal(priceHistory []entities.Pri
 	}
 
 	// Analyze SuperTrend
-	if stIndicator, exists := indicators["SuperTrend"]; exists && stIndicator.Metadata != nil {
+	if stIndicator, exists := indicators[entities.IndicatorKey("SuperTrend", superTrendPeriod, superTrendMultiplier)]; exists && stIndicator.Metadata != nil {
 		if trend, ok := stIndicator.Metadata["trend"].(string); ok {
 			signal.SuperTrend = trend
 
@@ -163,13 +166,22 @@ func (s *PullbackEntryService) analyzePullbackSignal(priceHistory []entities.Pri
 		}
 	}
 
+	// Analyze trend strength: a pullback only resumes a trend that is there
+	if adxIndicator, exists := indicators[entities.IndicatorKey("ADX", adxPeriod)]; exists && adxIndicator.Value != nil {
+		signal.TrendStrength = *adxIndicator.Value
+		signal.Confidence += s.analyzeTrendStrength(signal.Signal, signal.TrendStrength, adxIndicator.Metadata)
+	}
+
 	// Analyze price action patterns
 	signal.Confidence += s.analyzePriceAction(priceHistory)
 
-	// Ensure confidence doesn't exceed 100
+	// Keep confidence between 0 and 100
 	if signal.Confidence > 100 {
 		signal.Confidence = 100
 	}
+	if signal.Confidence < 0 {
+		signal.Confidence = 0
+	}
 
 	// Minimum confidence threshold
 	if signal.Confidence < 30 {
@@ -179,6 +191,23 @@ func (s *PullbackEntryService) analyzePullbackSignal(priceHistory []entities.Pri
 	return signal
 }
 
+// analyzeTrendStrength scores the ADX for a signal: a strong trend in the signal's
+// direction confirms it, a ranging market makes it less likely to play out
+func (s *PullbackEntryService) analyzeTrendStrength(direction string, adx float64, metadata map[string]interface{}) float64 {
+	if adx < indicators.ADXTrendDeveloping {
+		return -10
+	}
+	if adx < indicators.ADXTrendStrong {
+		return 0
+	}
+
+	trend, _ := metadata["direction"].(string)
+	if (trend == "up" && direction == "LONG") || (trend == "down" && direction == "SHORT") {
+		return 15
+	}
+	return 0
+}
+
 // analyzePriceAction analyzes price action patterns for additional confirmation
 func (s *PullbackEntryService) analyzePriceAction(priceHistory []entities.PriceHistory) float64 {
 	if len(priceHistory) < 10 {
@@ -229,12 +258,8 @@ func (s *PullbackEntryService) analyzePriceAction(priceHistory []entities.PriceH
 
 // getIndicatorValue gets indicator value by type and period
 func (s *PullbackEntryService) getIndicatorValue(indicators map[string]*entities.TechnicalIndicator, indicatorType string, period int) *float64 {
-	for _, indicator := range indicators {
-		if indicator.IndicatorType == indicatorType {
-			if metadata, ok := indicator.Metadata["period"].(float64); ok && int(metadata) == period {
-				return indicator.Value
-			}
-		}
+	if indicator, exists := indicators[entities.IndicatorKey(indicatorType, period)]; exists {
+		return indicator.Value
 	}
 	return nil
 }
@@ -274,7 +299,7 @@ func (s *PullbackEntryService) calculateATR(priceHistory []entities.PriceHistory
 		return 0
 	}
 
-	var priceData []indicators.PriceData
+	priceData := make([]indicators.PriceData, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		priceData = append(priceData, indicators.PriceData{
 			Open:   ph.OpenPrice,
@@ -336,19 +361,17 @@ func (s *PullbackEntryService) findRecentHigh(priceHistory []entities.PriceHisto
 	return highest
 }
 
-// getLatestIndicators retrieves latest technical indicators
+// getLatestIndicators retrieves latest technical indicators, keyed by indicator key
 func (s *PullbackEntryService) getLatestIndicators(ctx context.Context, symbol, timeframe string) (map[string]*entities.TechnicalIndicator, error) {
 	indicators := map[string]*entities.TechnicalIndicator{}
 
-	indicatorTypes := []string{"RSI", "EMA", "SMA", "SuperTrend", "BB_Upper", "BB_Middle", "BB_Lower"}
-
-	for _, indicatorType := range indicatorTypes {
-		indicator, err := s.technicalIndicatorRepo.GetLatest(ctx, symbol, timeframe, indicatorType)
+	for _, indicatorKey := range defaultIndicatorKeys {
+		indicator, err := s.technicalIndicatorRepo.GetLatestByKey(ctx, symbol, timeframe, indicatorKey)
 		if err != nil {
-			s.logger.WithError(err).WithField("indicator_type", indicatorType).Warn("Failed to get latest indicator")
+			s.logger.WithError(err).WithField("indicator_key", indicatorKey).Warn("Failed to get latest indicator")
 			continue
 		}
-		indicators[indicatorType] = indicator
+		indicators[indicatorKey] = indicator
 	}
 
 	return indicators, nil
@@ -13,6 +13,8 @@ type RedisClientInterface interface {
 	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
 	ZRemRangeByScore(ctx context.Context, key, min, max string) *redis.IntCmd
 	ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd
+	Pipeline() redis.Pipeliner
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
 }
 
 // RedisClientWrapper wraps redis.Client to implement RedisClientInterface
@@ -43,3 +45,11 @@ func (w *RedisClientWrapper) ZRemRangeByScore(ctx context.Context, key, min, max
 func (w *RedisClientWrapper) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd {
 	return w.client.ZRangeByScoreWithScores(ctx, key, opt)
 }
+
+func (w *RedisClientWrapper) Pipeline() redis.Pipeliner {
+	return w.client.Pipeline()
+}
+
+func (w *RedisClientWrapper) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	return w.client.Eval(ctx, script, keys, args...)
+}
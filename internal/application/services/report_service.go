@@ -0,0 +1,557 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/growthfolio/go-priceguard-api/pkg/pdf"
+	"github.com/sirupsen/logrus"
+)
+
+// ReportPeriod is the window covered by a market summary report
+type ReportPeriod string
+
+const (
+	ReportDaily  ReportPeriod = "daily"
+	ReportWeekly ReportPeriod = "weekly"
+
+	// ReportPrefix is the storage prefix holding rendered reports
+	ReportPrefix = "reports/"
+
+	// NotificationTypeMarketReport tags in-app notifications carrying a report
+	NotificationTypeMarketReport = "market_report"
+
+	reportTimeframe  = "1h"
+	reportIndicator  = "RSI"
+	rsiOverbought    = 70.0
+	rsiOversold      = 30.0
+	notableRSIChange = 10.0
+)
+
+// WatchlistPerformance summarises how a favourite symbol moved over the period
+type WatchlistPerformance struct {
+	Symbol        string  `json:"symbol"`
+	Open          float64 `json:"open"`
+	Close         float64 `json:"close"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// IndicatorChange is a notable indicator move on a watchlist symbol
+type IndicatorChange struct {
+	Symbol    string  `json:"symbol"`
+	Indicator string  `json:"indicator"`
+	Previous  float64 `json:"previous"`
+	Current   float64 `json:"current"`
+	Note      string  `json:"note"`
+}
+
+// MarketReport is the per-user summary compiled for a period
+type MarketReport struct {
+	UserID           uuid.UUID              `json:"user_id"`
+	Period           ReportPeriod           `json:"period"`
+	From             time.Time              `json:"from"`
+	To               time.Time              `json:"to"`
+	GeneratedAt      time.Time              `json:"generated_at"`
+	Watchlist        []WatchlistPerformance `json:"watchlist"`
+	TriggeredAlerts  []entities.Alert       `json:"triggered_alerts"`
+	IndicatorChanges []IndicatorChange      `json:"indicator_changes"`
+}
+
+// Summary is the one-line description used in notifications
+func (r *MarketReport) Summary() string {
+	return fmt.Sprintf("%d watchlist symbols, %d alerts triggered, %d notable indicator changes",
+		len(r.Watchlist), len(r.TriggeredAlerts), len(r.IndicatorChanges))
+}
+
+// ReportService compiles market summary reports and delivers them on each user's schedule
+type ReportService struct {
+	settingsRepo           repositories.UserSettingsRepository
+	alertRepo              repositories.AlertRepository
+	priceHistoryRepo       repositories.PriceHistoryRepository
+	technicalIndicatorRepo repositories.TechnicalIndicatorRepository
+	summaryRepo            repositories.PriceSummaryRepository
+	notificationService    *NotificationService
+	storage                storage.ObjectStorage
+	logger                 *logrus.Logger
+	clock                  clock.Clock
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewReportService creates a new report service
+func NewReportService(
+	settingsRepo repositories.UserSettingsRepository,
+	alertRepo repositories.AlertRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	technicalIndicatorRepo repositories.TechnicalIndicatorRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *ReportService {
+	return &ReportService{
+		settingsRepo:           settingsRepo,
+		alertRepo:              alertRepo,
+		priceHistoryRepo:       priceHistoryRepo,
+		technicalIndicatorRepo: technicalIndicatorRepo,
+		notificationService:    notificationService,
+		logger:                 logger,
+		clock:                  clock.New(),
+	}
+}
+
+// SetClock replaces the clock used for scheduling
+func (rs *ReportService) SetClock(c clock.Clock) {
+	rs.clock = c
+}
+
+// SetStorage enables uploading rendered HTML/PDF reports and linking them in notifications
+func (rs *ReportService) SetStorage(objectStorage storage.ObjectStorage) {
+	rs.storage = objectStorage
+}
+
+// SetPriceSummaryRepository makes weekly reports read the watchlist's performance
+// from the daily summaries instead of a week of hourly candles
+func (rs *ReportService) SetPriceSummaryRepository(repo repositories.PriceSummaryRepository) {
+	rs.summaryRepo = repo
+}
+
+// Start checks for due reports every interval until Stop is called
+func (rs *ReportService) Start(ctx context.Context, interval time.Duration) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	if rs.isRunning {
+		rs.logger.Warn("Report scheduler is already running")
+		return
+	}
+	rs.isRunning = true
+	rs.stopChan = make(chan struct{})
+	rs.logger.Info("Starting report scheduler")
+
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-rs.stopChan:
+				return
+			case <-ticker.C:
+				if _, err := rs.RunDueReports(ctx); err != nil {
+					rs.logger.WithError(err).Error("Failed to deliver scheduled reports")
+				}
+			}
+		}
+	}()
+}
+
+// Stop halts the scheduler and waits for the current run to finish
+func (rs *ReportService) Stop() {
+	rs.mutex.Lock()
+	if !rs.isRunning {
+		rs.mutex.Unlock()
+		return
+	}
+	rs.isRunning = false
+	close(rs.stopChan)
+	rs.mutex.Unlock()
+
+	rs.wg.Wait()
+	rs.logger.Info("Report scheduler stopped")
+}
+
+// DueSlot returns the most recent scheduled delivery time for the settings and
+// whether a report for that slot still has to be sent
+func DueSlot(settings *entities.UserSettings, now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	slot := time.Date(now.Year(), now.Month(), now.Day(), settings.ReportHour, 0, 0, 0, time.UTC)
+	if slot.After(now) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+
+	switch ReportPeriod(settings.ReportFrequency) {
+	case ReportDaily:
+	case ReportWeekly:
+		// Weekly reports go out on Mondays
+		for slot.Weekday() != time.Monday {
+			slot = slot.AddDate(0, 0, -1)
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	due := settings.LastReportAt == nil || settings.LastReportAt.Before(slot)
+	return slot, due
+}
+
+// RunDueReports generates and delivers every report whose slot has passed, returning how many were sent
+func (rs *ReportService) RunDueReports(ctx context.Context) (int, error) {
+	allSettings, err := rs.settingsRepo.GetWithReportsEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := rs.clock.Now()
+	sent := 0
+	var errs []error
+
+	for i := range allSettings {
+		settings := &allSettings[i]
+		slot, due := DueSlot(settings, now)
+		if !due {
+			continue
+		}
+
+		report, err := rs.GenerateReport(ctx, settings, ReportPeriod(settings.ReportFrequency), slot)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", settings.UserID, err))
+			continue
+		}
+		if err := rs.DeliverReport(ctx, settings, report); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", settings.UserID, err))
+			continue
+		}
+
+		settings.LastReportAt = &now
+		if err := rs.settingsRepo.Update(ctx, settings); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", settings.UserID, err))
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		rs.logger.WithField("reports", sent).Info("Scheduled market reports delivered")
+	}
+	return sent, errors.Join(errs...)
+}
+
+// GenerateReport compiles the report for the period ending at to
+func (rs *ReportService) GenerateReport(ctx context.Context, settings *entities.UserSettings, period ReportPeriod, to time.Time) (*MarketReport, error) {
+	var window time.Duration
+	switch period {
+	case ReportDaily:
+		window = 24 * time.Hour
+	case ReportWeekly:
+		window = 7 * 24 * time.Hour
+	default:
+		return nil, fmt.Errorf("unsupported report period %q", period)
+	}
+
+	report := &MarketReport{
+		UserID:           settings.UserID,
+		Period:           period,
+		From:             to.Add(-window),
+		To:               to,
+		GeneratedAt:      rs.clock.Now(),
+		Watchlist:        make([]WatchlistPerformance, 0, len(settings.FavoriteSymbols)),
+		TriggeredAlerts:  make([]entities.Alert, 0),
+		IndicatorChanges: make([]IndicatorChange, 0),
+	}
+
+	candles := int(window/time.Hour) + 1
+	for _, symbol := range settings.FavoriteSymbols {
+		performance, ok, err := rs.watchlistPerformance(ctx, symbol, period, candles, report.From, report.To)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			report.Watchlist = append(report.Watchlist, performance)
+		}
+
+		indicators, err := rs.technicalIndicatorRepo.GetBySymbol(ctx, symbol, reportTimeframe, reportIndicator, candles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load indicators for %s: %w", symbol, err)
+		}
+		if change, ok := notableIndicatorChange(symbol, indicators, report.From, report.To); ok {
+			report.IndicatorChanges = append(report.IndicatorChanges, change)
+		}
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		alerts, err := rs.alertRepo.GetByUserID(ctx, settings.UserID, exportPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load alerts: %w", err)
+		}
+		for _, alert := range alerts {
+			if alert.TriggeredAt != nil && !alert.TriggeredAt.Before(report.From) && !alert.TriggeredAt.After(report.To) {
+				report.TriggeredAlerts = append(report.TriggeredAlerts, alert)
+			}
+		}
+		if len(alerts) < exportPageSize {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// DeliverReport renders the report, stores it when storage is configured and notifies
+// the user in-app plus on every channel enabled in their settings
+func (rs *ReportService) DeliverReport(ctx context.Context, settings *entities.UserSettings, report *MarketReport) error {
+	html, err := RenderReportHTML(report)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"report_period": report.Period,
+		"from":          report.From,
+		"to":            report.To,
+	}
+
+	if rs.storage != nil {
+		base := fmt.Sprintf("%s%s/%s-%s", ReportPrefix, report.UserID, report.Period, report.To.Format("20060102"))
+		if data["html_url"], err = rs.storeReport(ctx, base+".html", html, "text/html; charset=utf-8"); err != nil {
+			return err
+		}
+		if data["pdf_url"], err = rs.storeReport(ctx, base+".pdf", RenderReportPDF(report), "application/pdf"); err != nil {
+			return err
+		}
+	}
+
+	title := fmt.Sprintf("Your %s market summary", report.Period)
+	if _, err := rs.notificationService.CreateNotification(ctx, report.UserID, NotificationTypeMarketReport, title, report.Summary(), data); err != nil {
+		return fmt.Errorf("failed to create report notification: %w", err)
+	}
+
+	var channels []NotificationChannel
+	if settings.NotificationsEmail {
+		channels = append(channels, ChannelEmail)
+	}
+	if settings.NotificationsPush {
+		channels = append(channels, ChannelPush)
+	}
+	if settings.NotificationsSMS {
+		channels = append(channels, ChannelSMS)
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	queued := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		queued[key] = value
+	}
+	queued["html"] = string(html)
+
+	return rs.notificationService.QueueNotification(ctx, &QueuedNotification{
+		UserID:   report.UserID,
+		Type:     NotificationTypeMarketReport,
+		Title:    title,
+		Message:  report.Summary(),
+		Channels: channels,
+		Priority: PriorityLow,
+		Data:     queued,
+	})
+}
+
+func (rs *ReportService) storeReport(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	if err := rs.storage.PutObject(ctx, key, content, contentType); err != nil {
+		return "", fmt.Errorf("failed to store report: %w", err)
+	}
+	url, err := rs.storage.PresignGetURL(key, storage.MaxPresignExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign report URL: %w", err)
+	}
+	return url, nil
+}
+
+// watchlistPerformance summarises how the symbol moved between from and to, over
+// the daily summaries of the days a weekly period spans when they are available
+func (rs *ReportService) watchlistPerformance(ctx context.Context, symbol string, period ReportPeriod, candles int, from, to time.Time) (WatchlistPerformance, bool, error) {
+	if period == ReportWeekly && rs.summaryRepo != nil {
+		summaries, err := rs.summaryRepo.GetByRange(ctx, symbol, startOfDay(from), to)
+		if err != nil {
+			return WatchlistPerformance{}, false, fmt.Errorf("failed to load daily summaries for %s: %w", symbol, err)
+		}
+		if len(summaries) > 0 {
+			return summariseDays(symbol, summaries), true, nil
+		}
+	}
+
+	history, err := rs.priceHistoryRepo.GetBySymbol(ctx, symbol, reportTimeframe, candles)
+	if err != nil {
+		return WatchlistPerformance{}, false, fmt.Errorf("failed to load price history for %s: %w", symbol, err)
+	}
+	performance, ok := summarisePerformance(symbol, history, from, to)
+	return performance, ok, nil
+}
+
+// summariseDays reduces oldest-first daily summaries to a performance row
+func summariseDays(symbol string, summaries []entities.PriceSummary) WatchlistPerformance {
+	performance := WatchlistPerformance{
+		Symbol: symbol,
+		Open:   summaries[0].OpenPrice,
+		Close:  summaries[len(summaries)-1].ClosePrice,
+		High:   summaries[0].HighPrice,
+		Low:    summaries[0].LowPrice,
+	}
+	for _, summary := range summaries {
+		performance.High = math.Max(performance.High, summary.HighPrice)
+		performance.Low = math.Min(performance.Low, summary.LowPrice)
+	}
+	if performance.Open != 0 {
+		performance.ChangePercent = (performance.Close - performance.Open) / performance.Open * 100
+	}
+	return performance
+}
+
+// summarisePerformance reduces newest-first candles inside [from, to] to a performance row
+func summarisePerformance(symbol string, history []entities.PriceHistory, from, to time.Time) (WatchlistPerformance, bool) {
+	performance := WatchlistPerformance{Symbol: symbol, Low: math.MaxFloat64}
+	found := false
+
+	for _, candle := range history {
+		if candle.Timestamp.Before(from) || candle.Timestamp.After(to) {
+			continue
+		}
+		if !found {
+			performance.Close = candle.ClosePrice
+			found = true
+		}
+		// Walking newest to oldest, the last candle seen opens the period
+		performance.Open = candle.OpenPrice
+		performance.High = math.Max(performance.High, candle.HighPrice)
+		performance.Low = math.Min(performance.Low, candle.LowPrice)
+	}
+
+	if !found {
+		return WatchlistPerformance{}, false
+	}
+	if performance.Open != 0 {
+		performance.ChangePercent = (performance.Close - performance.Open) / performance.Open * 100
+	}
+	return performance, true
+}
+
+// notableIndicatorChange reports RSI moves into or out of the overbought/oversold
+// zones, or large swings, between the start and end of the period
+func notableIndicatorChange(symbol string, indicators []entities.TechnicalIndicator, from, to time.Time) (IndicatorChange, bool) {
+	var current, previous *float64
+	for _, indicator := range indicators {
+		if indicator.Value == nil || indicator.Timestamp.Before(from) || indicator.Timestamp.After(to) {
+			continue
+		}
+		if current == nil {
+			current = indicator.Value
+		}
+		previous = indicator.Value
+	}
+	if current == nil || previous == current {
+		return IndicatorChange{}, false
+	}
+
+	change := IndicatorChange{Symbol: symbol, Indicator: reportIndicator, Previous: *previous, Current: *current}
+	switch {
+	case change.Previous < rsiOverbought && change.Current >= rsiOverbought:
+		change.Note = "entered overbought territory"
+	case change.Previous > rsiOversold && change.Current <= rsiOversold:
+		change.Note = "entered oversold territory"
+	case change.Previous >= rsiOverbought && change.Current < rsiOverbought:
+		change.Note = "left overbought territory"
+	case change.Previous <= rsiOversold && change.Current > rsiOversold:
+		change.Note = "left oversold territory"
+	case math.Abs(change.Current-change.Previous) >= notableRSIChange:
+		change.Note = fmt.Sprintf("moved %+.1f points", change.Current-change.Previous)
+	default:
+		return IndicatorChange{}, false
+	}
+	return change, true
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+	"price": func(v float64) string { return fmt.Sprintf("%.4f", v) },
+	"pct":   func(v float64) string { return fmt.Sprintf("%+.2f%%", v) },
+	"rsi":   func(v float64) string { return fmt.Sprintf("%.1f", v) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>PriceGuard {{.Period}} market summary</title></head>
+<body style="font-family: Helvetica, Arial, sans-serif; color: #1f2933;">
+<h1>PriceGuard {{.Period}} market summary</h1>
+<p>{{date .From}} &ndash; {{date .To}}</p>
+
+<h2>Watchlist performance</h2>
+{{if .Watchlist}}<table cellpadding="4">
+<tr><th align="left">Symbol</th><th align="right">Open</th><th align="right">Close</th><th align="right">High</th><th align="right">Low</th><th align="right">Change</th></tr>
+{{range .Watchlist}}<tr><td>{{.Symbol}}</td><td align="right">{{price .Open}}</td><td align="right">{{price .Close}}</td><td align="right">{{price .High}}</td><td align="right">{{price .Low}}</td><td align="right">{{pct .ChangePercent}}</td></tr>
+{{end}}</table>{{else}}<p>No price data for your watchlist in this period.</p>{{end}}
+
+<h2>Triggered alerts</h2>
+{{if .TriggeredAlerts}}<ul>
+{{range .TriggeredAlerts}}<li>{{.Symbol}} {{.AlertType}} {{.ConditionType}} {{.TargetValue}} ({{.Timeframe}}) at {{date .TriggeredAt}}</li>
+{{end}}</ul>{{else}}<p>No alerts triggered.</p>{{end}}
+
+<h2>Notable indicator changes</h2>
+{{if .IndicatorChanges}}<ul>
+{{range .IndicatorChanges}}<li>{{.Symbol}} {{.Indicator}} {{rsi .Previous}} &rarr; {{rsi .Current}}: {{.Note}}</li>
+{{end}}</ul>{{else}}<p>No notable indicator changes.</p>{{end}}
+</body>
+</html>
+`))
+
+// RenderReportHTML renders the report as an HTML email body
+func RenderReportHTML(report *MarketReport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderReportPDF renders the report as a plain text PDF attachment
+func RenderReportPDF(report *MarketReport) []byte {
+	lines := []string{
+		fmt.Sprintf("%s - %s", report.From.UTC().Format("2006-01-02 15:04"), report.To.UTC().Format("2006-01-02 15:04 MST")),
+		"",
+		"Watchlist performance",
+	}
+	if len(report.Watchlist) == 0 {
+		lines = append(lines, "  No price data for your watchlist in this period.")
+	}
+	for _, w := range report.Watchlist {
+		lines = append(lines, fmt.Sprintf("  %-12s open %.4f  close %.4f  high %.4f  low %.4f  %+.2f%%",
+			w.Symbol, w.Open, w.Close, w.High, w.Low, w.ChangePercent))
+	}
+
+	lines = append(lines, "", "Triggered alerts")
+	if len(report.TriggeredAlerts) == 0 {
+		lines = append(lines, "  No alerts triggered.")
+	}
+	for _, a := range report.TriggeredAlerts {
+		lines = append(lines, fmt.Sprintf("  %s %s %s %g (%s) at %s",
+			a.Symbol, a.AlertType, a.ConditionType, a.TargetValue, a.Timeframe, a.TriggeredAt.UTC().Format("2006-01-02 15:04")))
+	}
+
+	lines = append(lines, "", "Notable indicator changes")
+	if len(report.IndicatorChanges) == 0 {
+		lines = append(lines, "  No notable indicator changes.")
+	}
+	for _, c := range report.IndicatorChanges {
+		lines = append(lines, fmt.Sprintf("  %s %s %.1f -> %.1f: %s", c.Symbol, c.Indicator, c.Previous, c.Current, c.Note))
+	}
+
+	return pdf.RenderText(fmt.Sprintf("PriceGuard %s market summary", report.Period), lines)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// sandboxCleanupLockKey keeps replicas from deleting the same stale sandbox users
+const sandboxCleanupLockKey = "sandbox_cleanup"
+
+const (
+	// DefaultSandboxUserTTL is how long a sandbox user is kept after it was provisioned
+	DefaultSandboxUserTTL = 24 * time.Hour
+	// sandboxEmailDomain is reserved, so sandbox users never get a deliverable address
+	sandboxEmailDomain = "sandbox.invalid"
+	// sandboxAlertDistance is how far from the current price, in percent, the sample
+	// price alerts are set, close enough for the simulated walk to reach them
+	sandboxAlertDistance = 1.0
+)
+
+// SandboxService provisions demo users, so the frontend can showcase the product
+// without a real account. Each one gets a watchlist and alerts on the symbols of the
+// market simulator, and is deleted with all its data once older than the TTL.
+type SandboxService struct {
+	userRepo     repositories.UserRepository
+	settingsRepo repositories.UserSettingsRepository
+	alertRepo    repositories.AlertRepository
+	simulator    *MarketSimulator
+	logger       *logrus.Logger
+
+	ttl   time.Duration
+	clock clock.Clock
+
+	// runs makes sure each interval is cleaned up by one instance only
+	runs ThrottleStore
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// NewSandboxService creates a sandbox service keeping users for DefaultSandboxUserTTL
+func NewSandboxService(
+	userRepo repositories.UserRepository,
+	settingsRepo repositories.UserSettingsRepository,
+	alertRepo repositories.AlertRepository,
+	simulator *MarketSimulator,
+	logger *logrus.Logger,
+) *SandboxService {
+	return &SandboxService{
+		userRepo:     userRepo,
+		settingsRepo: settingsRepo,
+		alertRepo:    alertRepo,
+		simulator:    simulator,
+		logger:       logger,
+		ttl:          DefaultSandboxUserTTL,
+		clock:        clock.New(),
+		runs:         NewMemoryThrottleStore(),
+	}
+}
+
+// SetTTL sets how long sandbox users are kept; values below a minute are ignored
+func (s *SandboxService) SetTTL(ttl time.Duration) {
+	if ttl >= time.Minute {
+		s.ttl = ttl
+	}
+}
+
+// SetClock replaces the clock the age of sandbox users is judged by
+func (s *SandboxService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetThrottleStore replaces the in-process run lock, e.g. with a Redis store so only
+// one replica cleans up each interval
+func (s *SandboxService) SetThrottleStore(store ThrottleStore) {
+	s.runs = store
+}
+
+// TTL returns how long sandbox users are kept
+func (s *SandboxService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Provision creates a sandbox user watching the simulated symbols, with a price alert
+// a little above and below each one's current price and a percentage alert on the
+// first. Notifications stay in the app: sandbox users have no reachable address.
+func (s *SandboxService) Provision(ctx context.Context) (*entities.User, error) {
+	id := uuid.New()
+	user := &entities.User{
+		ID:        id,
+		GoogleID:  "sandbox:" + id.String(),
+		Email:     id.String() + "@" + sandboxEmailDomain,
+		Name:      "Demo user",
+		Sandbox:   true,
+		CreatedAt: s.clock.Now(),
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create sandbox user: %w", err)
+	}
+
+	symbols := s.simulator.Symbols()
+	settings := &entities.UserSettings{
+		UserID:             user.ID,
+		Theme:              "dark",
+		DefaultTimeframe:   simulatedTimeframe,
+		DefaultView:        "overview",
+		NotificationsEmail: false,
+		NotificationsPush:  false,
+		NotificationsSMS:   false,
+		RiskProfile:        "moderate",
+		FavoriteSymbols:    symbols,
+		ReportFrequency:    "none",
+		ReportHour:         8,
+	}
+	if err := s.settingsRepo.Create(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to create sandbox settings: %w", err)
+	}
+
+	alerts := 0
+	for i, symbol := range symbols {
+		price, err := s.simulator.CurrentPrice(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		samples := []*entities.Alert{
+			sandboxAlert(user.ID, symbol, "price", "above", roundPrice(price*(1+sandboxAlertDistance/100))),
+			sandboxAlert(user.ID, symbol, "price", "below", roundPrice(price*(1-sandboxAlertDistance/100))),
+		}
+		if i == 0 {
+			samples = append(samples, sandboxAlert(user.ID, symbol, "percentage", "up", sandboxAlertDistance))
+		}
+		for _, alert := range samples {
+			if err := s.alertRepo.Create(ctx, alert); err != nil {
+				return nil, fmt.Errorf("failed to create sandbox alert: %w", err)
+			}
+			alerts++
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": user.ID,
+		"symbols": len(symbols),
+		"alerts":  alerts,
+	}).Info("Sandbox user provisioned")
+	return user, nil
+}
+
+// CleanupStale deletes the sandbox users older than the TTL and returns how many were
+// deleted; their alerts, settings, sessions and notifications go with them. A user
+// that fails is logged and retried on the next pass.
+func (s *SandboxService) CleanupStale(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetSandboxCreatedBefore(ctx, s.clock.Now().Add(-s.ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stale sandbox users: %w", err)
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+			s.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to delete sandbox user")
+			continue
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Info("Stale sandbox users deleted")
+	}
+	return deleted, nil
+}
+
+// Start cleans up stale sandbox users once and then every interval until Stop is called
+func (s *SandboxService) Start(ctx context.Context, interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isRunning {
+		s.logger.Warn("Sandbox cleanup is already running")
+		return
+	}
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.logger.WithFields(logrus.Fields{
+		"interval": interval,
+		"ttl":      s.ttl,
+	}).Info("Starting sandbox cleanup")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the cleanup and waits for the current pass to finish
+func (s *SandboxService) Stop() {
+	s.mutex.Lock()
+	if !s.isRunning {
+		s.mutex.Unlock()
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	s.logger.Info("Sandbox cleanup stopped")
+}
+
+// runScheduled cleans up unless another instance already took this interval
+func (s *SandboxService) runScheduled(ctx context.Context, interval time.Duration) {
+	acquired, err := s.runs.Acquire(ctx, sandboxCleanupLockKey, interval)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to acquire sandbox cleanup lock")
+	} else if !acquired {
+		return
+	}
+
+	if _, err := s.CleanupStale(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to clean up sandbox users")
+	}
+}
+
+func sandboxAlert(userID uuid.UUID, symbol, alertType, conditionType string, target float64) *entities.Alert {
+	return &entities.Alert{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Symbol:        symbol,
+		AlertType:     alertType,
+		ConditionType: conditionType,
+		TargetValue:   target,
+		Timeframe:     simulatedTimeframe,
+		Enabled:       true,
+		NotifyVia:     []string{"app"},
+		Priority:      "normal",
+	}
+}
+
+// roundPrice keeps the significant digits of a sample alert target readable
+func roundPrice(price float64) float64 {
+	if price >= 100 {
+		return math.Round(price)
+	}
+	return math.Round(price*100) / 100
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultShareLinkTTL is how long a share link stays valid when no expiry is given
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	// SharedSnapshotPath is the public route share tokens are opened at
+	SharedSnapshotPath = "/api/public/shares/"
+
+	shareTokenBytes = 24
+)
+
+// Resource types a share link can point to
+const (
+	ShareResourcePullback     = "pullback_analysis"
+	ShareResourceAlertTrigger = "alert_trigger"
+)
+
+var (
+	// ErrShareLinkNotFound is returned for unknown, expired, revoked or foreign share links
+	ErrShareLinkNotFound = entities.NewDomainError(entities.ErrNotFound, "share link not found")
+	// ErrNotAlertTrigger is returned when sharing a notification that isn't an alert trigger
+	ErrNotAlertTrigger = errors.New("notification is not an alert trigger")
+)
+
+// PullbackAnalyzer produces the pullback analysis a share link freezes;
+// *PullbackEntryService implements it
+type PullbackAnalyzer interface {
+	AnalyzePullbackEntry(ctx context.Context, symbol, timeframe string) (*PullbackEntry, error)
+}
+
+// CreatedShareLink is a new share link together with its token, which is only
+// available when the link is created
+type CreatedShareLink struct {
+	*entities.ShareLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// ShareService creates public read-only links to snapshots of a pullback analysis
+// or an alert trigger. The snapshot is frozen when the link is created, so the
+// link shows what the user saw even after the market moved.
+type ShareService struct {
+	shareRepo        repositories.ShareLinkRepository
+	pullbackAnalyzer PullbackAnalyzer
+	notificationRepo repositories.NotificationRepository
+	logger           *logrus.Logger
+	clock            clock.Clock
+}
+
+// NewShareService creates a new share service
+func NewShareService(
+	shareRepo repositories.ShareLinkRepository,
+	pullbackAnalyzer PullbackAnalyzer,
+	notificationRepo repositories.NotificationRepository,
+	logger *logrus.Logger,
+) *ShareService {
+	return &ShareService{
+		shareRepo:        shareRepo,
+		pullbackAnalyzer: pullbackAnalyzer,
+		notificationRepo: notificationRepo,
+		logger:           logger,
+		clock:            clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to expire share links
+func (ss *ShareService) SetClock(c clock.Clock) {
+	ss.clock = c
+}
+
+// SharePullbackAnalysis runs the pullback analysis of a symbol and shares the result
+func (ss *ShareService) SharePullbackAnalysis(ctx context.Context, userID uuid.UUID, symbol, timeframe string, ttl time.Duration) (*CreatedShareLink, error) {
+	entry, err := ss.pullbackAnalyzer.AnalyzePullbackEntry(ctx, symbol, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze pullback entry: %w", err)
+	}
+
+	snapshot, err := snapshotOf(entry)
+	if err != nil {
+		return nil, err
+	}
+	return ss.create(ctx, userID, ShareResourcePullback, symbol, snapshot, ttl)
+}
+
+// ShareAlertTrigger shares the evaluation snapshot stored with one of the user's
+// alert trigger notifications
+func (ss *ShareService) ShareAlertTrigger(ctx context.Context, userID, notificationID uuid.UUID, ttl time.Duration) (*CreatedShareLink, error) {
+	notification, err := ss.notificationRepo.GetByID(ctx, notificationID)
+	if err != nil || notification.UserID != userID {
+		return nil, ErrShareLinkNotFound
+	}
+	if notification.AlertID == nil || len(notification.Context) == 0 {
+		return nil, ErrNotAlertTrigger
+	}
+
+	snapshot := make(map[string]interface{}, len(notification.Context)+2)
+	for key, value := range notification.Context {
+		snapshot[key] = value
+	}
+	snapshot["title"] = notification.Title
+	snapshot["message"] = notification.Message
+
+	symbol, _ := notification.Context["symbol"].(string)
+	return ss.create(ctx, userID, ShareResourceAlertTrigger, symbol, snapshot, ttl)
+}
+
+// GetSharedSnapshot returns the link a token opens, if it is still active
+func (ss *ShareService) GetSharedSnapshot(ctx context.Context, token string) (*entities.ShareLink, error) {
+	link, err := ss.shareRepo.GetByTokenHash(ctx, hashShareToken(token))
+	if err != nil || !link.IsActive(ss.clock.Now()) {
+		return nil, ErrShareLinkNotFound
+	}
+	return link, nil
+}
+
+// ListShareLinks returns the user's share links, newest first
+func (ss *ShareService) ListShareLinks(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.ShareLink, error) {
+	links, err := ss.shareRepo.GetByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+	return links, nil
+}
+
+// RevokeShareLink disables one of the user's links before it expires
+func (ss *ShareService) RevokeShareLink(ctx context.Context, userID, id uuid.UUID) error {
+	if err := ss.shareRepo.Revoke(ctx, id, userID, ss.clock.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrShareLinkNotFound
+		}
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+
+	ss.logger.WithFields(logrus.Fields{
+		"user_id":       userID,
+		"share_link_id": id,
+	}).Info("Share link revoked")
+	return nil
+}
+
+func (ss *ShareService) create(ctx context.Context, userID uuid.UUID, resourceType, symbol string, snapshot map[string]interface{}, ttl time.Duration) (*CreatedShareLink, error) {
+	if ttl <= 0 {
+		ttl = DefaultShareLinkTTL
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	now := ss.clock.Now()
+	link := &entities.ShareLink{
+		UserID:       userID,
+		TokenHash:    hashShareToken(token),
+		ResourceType: resourceType,
+		Symbol:       symbol,
+		Snapshot:     snapshot,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+	if err := link.Validate(now); err != nil {
+		return nil, err
+	}
+
+	if err := ss.shareRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	ss.logger.WithFields(logrus.Fields{
+		"user_id":       userID,
+		"share_link_id": link.ID,
+		"resource_type": resourceType,
+		"expires_at":    link.ExpiresAt,
+	}).Info("Share link created")
+
+	return &CreatedShareLink{ShareLink: link, Token: token, URL: SharedSnapshotPath + token}, nil
+}
+
+// snapshotOf freezes a value as the JSON object it is served as
+func snapshotOf(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashShareToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultStatsWindow is the number of returns statistics cover when no window is given
+	DefaultStatsWindow = 30
+	// MinStatsWindow is the fewest returns statistics are computed from
+	MinStatsWindow = 5
+	// MaxStatsWindow caps the returns statistics cover
+	MaxStatsWindow = 365
+)
+
+// Indicator types the statistics are stored under; combined with the window they
+// form indicator keys, e.g. IndicatorKey(StatVolatility, 30), for screener filters
+const (
+	StatVolatility  = "Volatility"
+	StatMaxDrawdown = "MaxDrawdown"
+	StatSharpe      = "Sharpe"
+)
+
+// millisecondsPerYear annualizes per-candle statistics; crypto markets trade around the clock
+const millisecondsPerYear = 365 * 24 * 60 * 60 * 1000
+
+// SymbolStats are the risk statistics of a symbol over its last Window candles.
+// Volatility is the annualized standard deviation of log returns and MaxDrawdown
+// the largest peak-to-trough fall of the close, both in percent; Sharpe is the
+// annualized mean return over its standard deviation, with no risk-free rate.
+type SymbolStats struct {
+	Symbol       string    `json:"symbol"`
+	Timeframe    string    `json:"timeframe"`
+	Window       int       `json:"window"`
+	Volatility   float64   `json:"volatility"`
+	MaxDrawdown  float64   `json:"max_drawdown"`
+	Sharpe       float64   `json:"sharpe"`
+	PeriodReturn float64   `json:"period_return"`
+	CandleTime   time.Time `json:"candle_time"`
+}
+
+// StatisticsService computes per-symbol risk statistics and stores them as technical
+// indicators stamped with the candle they were computed at, so they are calculated
+// once per candle and can be queried like any other indicator
+type StatisticsService struct {
+	priceHistoryRepo       repositories.PriceHistoryRepository
+	technicalIndicatorRepo repositories.TechnicalIndicatorRepository
+	logger                 *logrus.Logger
+}
+
+// NewStatisticsService creates a new statistics service
+func NewStatisticsService(
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	technicalIndicatorRepo repositories.TechnicalIndicatorRepository,
+	logger *logrus.Logger,
+) *StatisticsService {
+	return &StatisticsService{
+		priceHistoryRepo:       priceHistoryRepo,
+		technicalIndicatorRepo: technicalIndicatorRepo,
+		logger:                 logger,
+	}
+}
+
+// GetStats returns the statistics at the latest candle, reading them back when they
+// were already stored for it and computing and storing them otherwise
+func (ss *StatisticsService) GetStats(ctx context.Context, symbol, timeframe string, window int) (*SymbolStats, error) {
+	if window > MaxStatsWindow {
+		window = MaxStatsWindow
+	}
+	if window < MinStatsWindow {
+		window = DefaultStatsWindow
+	}
+
+	latest, err := ss.priceHistoryRepo.GetLatest(ctx, symbol, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest candle: %w", err)
+	}
+	if latest == nil {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "no price data for %s", symbol)
+	}
+
+	if stats, found := ss.storedStats(ctx, symbol, timeframe, window, latest.Timestamp); found {
+		return stats, nil
+	}
+	return ss.CalculateAndStoreStats(ctx, symbol, timeframe, window)
+}
+
+// CalculateAndStoreStats computes the statistics over the last window returns and
+// stores them under their indicator keys
+func (ss *StatisticsService) CalculateAndStoreStats(ctx context.Context, symbol, timeframe string, window int) (*SymbolStats, error) {
+	timeframeMillis := indicators.GetTimeframeMilliseconds(timeframe)
+	if timeframeMillis == 0 {
+		return nil, fmt.Errorf("unsupported timeframe %q", timeframe)
+	}
+
+	history, err := ss.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, window+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+	if len(history) < MinStatsWindow+1 {
+		return nil, entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for statistics: need at least %d candles, got %d", MinStatsWindow+1, len(history))
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+	closes := make([]float64, len(history))
+	for i, candle := range history {
+		closes[i] = candle.ClosePrice
+	}
+
+	stats, err := computeStats(closes, float64(millisecondsPerYear/timeframeMillis))
+	if err != nil {
+		return nil, err
+	}
+	stats.Symbol = symbol
+	stats.Timeframe = timeframe
+	stats.Window = window
+	stats.CandleTime = history[len(history)-1].Timestamp
+
+	metadata := map[string]interface{}{
+		"window":        window,
+		"returns":       len(closes) - 1,
+		"period_return": stats.PeriodReturn,
+	}
+	records := make([]entities.TechnicalIndicator, 0, 3)
+	for _, stat := range []struct {
+		indicatorType string
+		value         float64
+	}{
+		{StatVolatility, stats.Volatility},
+		{StatMaxDrawdown, stats.MaxDrawdown},
+		{StatSharpe, stats.Sharpe},
+	} {
+		value := stat.value
+		records = append(records, entities.TechnicalIndicator{
+			Symbol:        symbol,
+			Timeframe:     timeframe,
+			IndicatorType: stat.indicatorType,
+			IndicatorKey:  entities.IndicatorKey(stat.indicatorType, window),
+			Value:         &value,
+			Metadata:      metadata,
+			Timestamp:     stats.CandleTime,
+		})
+	}
+	// A concurrent request may have stored this candle already; the result is still valid
+	if err := ss.technicalIndicatorRepo.BulkInsert(ctx, records); err != nil {
+		ss.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to store statistics")
+	}
+
+	ss.logger.WithFields(logrus.Fields{
+		"symbol":       symbol,
+		"timeframe":    timeframe,
+		"volatility":   stats.Volatility,
+		"max_drawdown": stats.MaxDrawdown,
+		"sharpe":       stats.Sharpe,
+	}).Info("Statistics calculated and stored")
+
+	return stats, nil
+}
+
+// storedStats reads back the statistics stored for the candle at candleTime
+func (ss *StatisticsService) storedStats(ctx context.Context, symbol, timeframe string, window int, candleTime time.Time) (*SymbolStats, bool) {
+	values := make(map[string]*entities.TechnicalIndicator, 3)
+	for _, indicatorType := range []string{StatVolatility, StatMaxDrawdown, StatSharpe} {
+		indicator, err := ss.technicalIndicatorRepo.GetLatestByKey(ctx, symbol, timeframe, entities.IndicatorKey(indicatorType, window))
+		if err != nil || indicator == nil || indicator.Value == nil || !indicator.Timestamp.Equal(candleTime) {
+			return nil, false
+		}
+		values[indicatorType] = indicator
+	}
+
+	stats := &SymbolStats{
+		Symbol:      symbol,
+		Timeframe:   timeframe,
+		Window:      window,
+		Volatility:  *values[StatVolatility].Value,
+		MaxDrawdown: *values[StatMaxDrawdown].Value,
+		Sharpe:      *values[StatSharpe].Value,
+		CandleTime:  candleTime,
+	}
+	if periodReturn, ok := values[StatSharpe].Metadata["period_return"].(float64); ok {
+		stats.PeriodReturn = periodReturn
+	}
+	return stats, true
+}
+
+// computeStats derives the statistics from closes ordered oldest first
+func computeStats(closes []float64, periodsPerYear float64) (*SymbolStats, error) {
+	returns := make([]float64, 0, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			return nil, fmt.Errorf("invalid close price in history")
+		}
+		returns = append(returns, math.Log(closes[i]/closes[i-1]))
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+
+	var sharpe float64
+	if stdDev > 0 {
+		sharpe = mean / stdDev * math.Sqrt(periodsPerYear)
+	}
+
+	var maxDrawdown float64
+	peak := closes[0]
+	for _, close := range closes {
+		if close > peak {
+			peak = close
+		}
+		if drawdown := (peak - close) / peak; drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+	}
+
+	return &SymbolStats{
+		Volatility:   roundStat(stdDev * math.Sqrt(periodsPerYear) * 100),
+		MaxDrawdown:  roundStat(maxDrawdown * 100),
+		Sharpe:       roundStat(sharpe),
+		PeriodReturn: roundStat((closes[len(closes)-1]/closes[0] - 1) * 100),
+	}, nil
+}
+
+func roundStat(value float64) float64 {
+	return math.Round(value*1e4) / 1e4
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/sirupsen/logrus"
+)
+
+// atrPeriod is the period of the ATR the streaming indicators keep up to date
+const atrPeriod = 14
+
+// streamingSeedCandles is how many stored candles rebuild a market's rolling state;
+// the Wilder and exponential averages forget older candles well before that
+const streamingSeedCandles = 500
+
+// streamingMarket is the rolling state of one symbol and timeframe
+type streamingMarket struct {
+	rsi      *indicators.StreamingRSI
+	emaFast  *indicators.StreamingEMA
+	emaSlow  *indicators.StreamingEMA
+	atr      *indicators.StreamingATR
+	lastOpen time.Time
+}
+
+func newStreamingMarket() *streamingMarket {
+	return &streamingMarket{
+		rsi:     indicators.NewStreamingRSI(rsiPeriod),
+		emaFast: indicators.NewStreamingEMA(emaFastPeriod),
+		emaSlow: indicators.NewStreamingEMA(emaSlowPeriod),
+		atr:     indicators.NewStreamingATR(atrPeriod),
+	}
+}
+
+// update feeds the market one closed candle
+func (m *streamingMarket) update(candle entities.PriceHistory) {
+	m.rsi.Update(candle.ClosePrice)
+	m.emaFast.Update(candle.ClosePrice)
+	m.emaSlow.Update(candle.ClosePrice)
+	m.atr.Update(indicators.PriceData{
+		Open:   candle.OpenPrice,
+		High:   candle.HighPrice,
+		Low:    candle.LowPrice,
+		Close:  candle.ClosePrice,
+		Volume: candle.Volume,
+	})
+	m.lastOpen = candle.Timestamp
+}
+
+// StreamingIndicatorService keeps the RSI, the fast and slow EMA and the ATR of a
+// few markets up to date from the Binance kline stream. Each market's rolling state
+// is built once from the stored candles, then every closed kline updates it in
+// constant time, so the indicators are stored seconds after their candle closes
+// instead of on the next scheduled calculation. A missed candle rebuilds the state.
+type StreamingIndicatorService struct {
+	marketData       external.MarketDataProvider
+	priceHistoryRepo repositories.PriceHistoryRepository
+	indicatorService *TechnicalIndicatorService
+	logger           *logrus.Logger
+
+	markets map[string]*streamingMarket
+	streams []string
+	mutex   sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// NewStreamingIndicatorService creates a new streaming indicator service
+func NewStreamingIndicatorService(
+	marketData external.MarketDataProvider,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	indicatorService *TechnicalIndicatorService,
+	logger *logrus.Logger,
+) *StreamingIndicatorService {
+	return &StreamingIndicatorService{
+		marketData:       marketData,
+		priceHistoryRepo: priceHistoryRepo,
+		indicatorService: indicatorService,
+		logger:           logger,
+		markets:          make(map[string]*streamingMarket),
+	}
+}
+
+// Start subscribes to the kline stream of every symbol on every timeframe and
+// updates their indicators as the candles close, until ctx is done or Stop is called
+func (s *StreamingIndicatorService) Start(ctx context.Context, symbols, timeframes []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.streams) > 0 {
+		return fmt.Errorf("streaming indicators are already running")
+	}
+
+	var streams []string
+	for _, symbol := range symbols {
+		for _, timeframe := range timeframes {
+			stream := s.marketData.GetKlineWebSocketStream(symbol, timeframe)
+			streams = append(streams, stream)
+
+			messages := s.marketData.SubscribeToStream(stream)
+			s.wg.Add(1)
+			go s.consume(ctx, stream, messages)
+		}
+	}
+
+	if err := s.marketData.StartWebSocket(ctx, streams); err != nil {
+		for _, stream := range streams {
+			s.marketData.UnsubscribeFromStream(stream)
+		}
+		return fmt.Errorf("failed to start kline stream: %w", err)
+	}
+	s.streams = streams
+
+	s.logger.WithField("streams", len(streams)).Info("Streaming indicators started")
+	return nil
+}
+
+// Stop closes the kline stream and waits for the pending candles to be applied
+func (s *StreamingIndicatorService) Stop() {
+	s.mutex.Lock()
+	streams := s.streams
+	s.streams = nil
+	s.mutex.Unlock()
+
+	if len(streams) == 0 {
+		return
+	}
+
+	s.marketData.StopWebSocket()
+	for _, stream := range streams {
+		s.marketData.UnsubscribeFromStream(stream)
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Streaming indicators stopped")
+}
+
+// consume applies the closed klines of one stream; klines of the candle still open
+// are ignored
+func (s *StreamingIndicatorService) consume(ctx context.Context, stream string, messages <-chan []byte) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			kline, err := external.ParseKlineMessage(message)
+			if err != nil {
+				s.logger.WithError(err).WithField("stream", stream).Warn("Failed to parse kline message")
+				continue
+			}
+			if !kline.Kline.IsClosed {
+				continue
+			}
+
+			candle, err := klineCandle(kline)
+			if err != nil {
+				s.logger.WithError(err).WithField("stream", stream).Warn("Invalid kline")
+				continue
+			}
+			if err := s.ApplyClosedCandle(ctx, candle); err != nil {
+				s.logger.WithError(err).WithField("stream", stream).Error("Failed to update streaming indicators")
+			}
+		}
+	}
+}
+
+// ApplyClosedCandle stores a closed candle and the indicators it updates. Candles
+// already applied are ignored; the first candle of a market, or one following a
+// gap, rebuilds its state from the stored candles first.
+func (s *StreamingIndicatorService) ApplyClosedCandle(ctx context.Context, candle entities.PriceHistory) error {
+	// The scheduled collection may have stored the candle already
+	if err := s.priceHistoryRepo.Create(ctx, &candle); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":    candle.Symbol,
+			"timeframe": candle.Timeframe,
+		}).Debug("Streamed candle not stored")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := candle.Symbol + "|" + candle.Timeframe
+	market, found := s.markets[key]
+	switch {
+	case !found || s.missedCandle(market, candle):
+		rebuilt, err := s.seed(ctx, candle)
+		if err != nil {
+			return err
+		}
+		market = rebuilt
+		s.markets[key] = market
+	case !candle.Timestamp.After(market.lastOpen):
+		return nil
+	default:
+		market.update(candle)
+	}
+
+	return s.storeIndicators(ctx, candle, market)
+}
+
+// missedCandle reports whether candle doesn't directly follow the market's last one
+func (s *StreamingIndicatorService) missedCandle(market *streamingMarket, candle entities.PriceHistory) bool {
+	step := time.Duration(indicators.GetTimeframeMilliseconds(candle.Timeframe)) * time.Millisecond
+	return candle.Timestamp.After(market.lastOpen.Add(step))
+}
+
+// seed builds a market's state from its stored candles, oldest first, ending with
+// candle
+func (s *StreamingIndicatorService) seed(ctx context.Context, candle entities.PriceHistory) (*streamingMarket, error) {
+	history, err := s.priceHistoryRepo.GetBySymbol(ctx, candle.Symbol, candle.Timeframe, streamingSeedCandles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	market := newStreamingMarket()
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Timestamp.After(candle.Timestamp) {
+			continue
+		}
+		if !market.lastOpen.IsZero() && !history[i].Timestamp.After(market.lastOpen) {
+			continue
+		}
+		market.update(history[i])
+	}
+	if candle.Timestamp.After(market.lastOpen) {
+		market.update(candle)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    candle.Symbol,
+		"timeframe": candle.Timeframe,
+		"candles":   len(history),
+	}).Debug("Streaming indicator state rebuilt")
+	return market, nil
+}
+
+// storeIndicators stores the market's indicators whose first window is complete
+func (s *StreamingIndicatorService) storeIndicators(ctx context.Context, candle entities.PriceHistory, market *streamingMarket) error {
+	var results []*entities.TechnicalIndicator
+	add := func(indicatorType string, period int, value float64, metadata map[string]interface{}) {
+		metadata["period"] = period
+		results = append(results, &entities.TechnicalIndicator{
+			Symbol:        candle.Symbol,
+			Timeframe:     candle.Timeframe,
+			IndicatorType: indicatorType,
+			IndicatorKey:  entities.IndicatorKey(indicatorType, period),
+			Value:         &value,
+			Metadata:      metadata,
+			Timestamp:     time.Now(),
+		})
+	}
+
+	if rsi, ok := market.rsi.Value(); ok {
+		add("RSI", rsiPeriod, rsi, map[string]interface{}{"signal": indicators.RSISignal(rsi)})
+	}
+	if ema, ok := market.emaFast.Value(); ok {
+		add("EMA", emaFastPeriod, ema, map[string]interface{}{})
+	}
+	if ema, ok := market.emaSlow.Value(); ok {
+		add("EMA", emaSlowPeriod, ema, map[string]interface{}{})
+	}
+	if atr, ok := market.atr.Value(); ok {
+		add("ATR", atrPeriod, atr, map[string]interface{}{})
+	}
+
+	for _, result := range results {
+		if err := s.indicatorService.store(ctx, candle.Symbol, candle.Timeframe, result.IndicatorKey, candle.Timestamp, result); err != nil {
+			return fmt.Errorf("failed to store %s indicator: %w", result.IndicatorKey, err)
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":     candle.Symbol,
+		"timeframe":  candle.Timeframe,
+		"indicators": len(results),
+	}).Debug("Streaming indicators updated")
+	return nil
+}
+
+// klineCandle converts a streamed kline to the candle it closes
+func klineCandle(data *external.KlineWebSocketData) (entities.PriceHistory, error) {
+	var prices [5]float64
+	for i, raw := range []string{data.Kline.Open, data.Kline.High, data.Kline.Low, data.Kline.Close, data.Kline.Volume} {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return entities.PriceHistory{}, fmt.Errorf("invalid kline value %q: %w", raw, err)
+		}
+		prices[i] = value
+	}
+
+	candle := entities.PriceHistory{
+		Symbol:     data.Symbol,
+		Timeframe:  data.Kline.Interval,
+		Timestamp:  time.UnixMilli(data.Kline.OpenTime),
+		OpenPrice:  prices[0],
+		HighPrice:  prices[1],
+		LowPrice:   prices[2],
+		ClosePrice: prices[3],
+		Volume:     prices[4],
+	}
+	if data.EventTime > 0 {
+		eventTime := time.UnixMilli(data.EventTime)
+		candle.EventTime = &eventTime
+	}
+	return candle, nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// NotificationTypeAlertsPaused tags the in-app notification telling a user their
+	// alerts on a blacklisted symbol were paused
+	NotificationTypeAlertsPaused = "alerts_paused"
+
+	// symbolRestrictionsTTL is how long the restrictions are reused before being read
+	// again, which is also how long other instances take to see a change
+	symbolRestrictionsTTL = time.Minute
+	symbolRestrictionsKey = "symbol_restrictions"
+)
+
+var (
+	// ErrSymbolRestrictionNotFound is returned when lifting a restriction that doesn't exist
+	ErrSymbolRestrictionNotFound = entities.NewDomainError(entities.ErrNotFound, "symbol restriction not found")
+)
+
+// SymbolRestrictionService manages the admin blacklist and greylist of symbols.
+// Neither list takes new alerts; blacklisted symbols are also left out of data
+// collection and their enabled alerts are paused, telling each owner why. The
+// restrictions are stored in the database and cached, since alert creation and
+// every collection cycle check them.
+type SymbolRestrictionService struct {
+	restrictionRepo     repositories.SymbolRestrictionRepository
+	alertRepo           repositories.AlertRepository
+	notificationService *NotificationService
+	cache               *cache.MemoryCache
+	logger              *logrus.Logger
+	loadMutex           sync.Mutex
+}
+
+// NewSymbolRestrictionService creates a new symbol restriction service
+func NewSymbolRestrictionService(
+	restrictionRepo repositories.SymbolRestrictionRepository,
+	alertRepo repositories.AlertRepository,
+	logger *logrus.Logger,
+) *SymbolRestrictionService {
+	return &SymbolRestrictionService{
+		restrictionRepo: restrictionRepo,
+		alertRepo:       alertRepo,
+		cache:           cache.NewMemoryCache(1, time.Minute),
+		logger:          logger,
+	}
+}
+
+// SetNotificationService tells the owners of alerts paused by a blacklist in-app
+func (srs *SymbolRestrictionService) SetNotificationService(notificationService *NotificationService) {
+	srs.notificationService = notificationService
+}
+
+// Restrict blacklists or greylists a symbol, replacing any restriction it already
+// has, and returns how many alerts a blacklist paused
+func (srs *SymbolRestrictionService) Restrict(ctx context.Context, restriction *entities.SymbolRestriction) (int, error) {
+	restriction.Symbol = strings.ToUpper(restriction.Symbol)
+	if err := restriction.Validate(); err != nil {
+		return 0, err
+	}
+
+	if err := srs.restrictionRepo.Upsert(ctx, restriction); err != nil {
+		return 0, fmt.Errorf("failed to store symbol restriction: %w", err)
+	}
+	srs.cache.Delete(symbolRestrictionsKey)
+
+	srs.logger.WithFields(logrus.Fields{
+		"symbol":     restriction.Symbol,
+		"level":      restriction.Level,
+		"created_by": restriction.CreatedBy,
+	}).Info("Symbol restricted")
+
+	if restriction.Level != entities.SymbolBlacklisted {
+		return 0, nil
+	}
+	return srs.pauseAlerts(ctx, restriction)
+}
+
+// Lift removes a symbol's restriction. Paused alerts aren't re-enabled; their
+// owners decide whether they still want them.
+func (srs *SymbolRestrictionService) Lift(ctx context.Context, symbol string) error {
+	symbol = strings.ToUpper(symbol)
+	if err := srs.restrictionRepo.Delete(ctx, symbol); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSymbolRestrictionNotFound
+		}
+		return fmt.Errorf("failed to delete symbol restriction: %w", err)
+	}
+	srs.cache.Delete(symbolRestrictionsKey)
+
+	srs.logger.WithField("symbol", symbol).Info("Symbol restriction lifted")
+	return nil
+}
+
+// GetRestrictions returns every restriction, ordered by symbol
+func (srs *SymbolRestrictionService) GetRestrictions(ctx context.Context) ([]entities.SymbolRestriction, error) {
+	restrictions, err := srs.restrictionRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol restrictions: %w", err)
+	}
+	return restrictions, nil
+}
+
+// Restriction returns the symbol's restriction, if it has one
+func (srs *SymbolRestrictionService) Restriction(ctx context.Context, symbol string) (*entities.SymbolRestriction, bool, error) {
+	restrictions, err := srs.restrictions(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	restriction, found := restrictions[strings.ToUpper(symbol)]
+	if !found {
+		return nil, false, nil
+	}
+	return &restriction, true, nil
+}
+
+// CheckNewAlert returns a conflict error when the symbol is blacklisted or greylisted
+func (srs *SymbolRestrictionService) CheckNewAlert(ctx context.Context, symbol string) error {
+	restriction, found, err := srs.Restriction(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if found {
+		return restrictedSymbolError(restriction)
+	}
+	return nil
+}
+
+// CheckEnableAlert returns a conflict error when the symbol is blacklisted, so
+// paused alerts can't be turned back on; greylisted symbols keep their alerts
+func (srs *SymbolRestrictionService) CheckEnableAlert(ctx context.Context, symbol string) error {
+	restriction, found, err := srs.Restriction(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if found && restriction.Level == entities.SymbolBlacklisted {
+		return restrictedSymbolError(restriction)
+	}
+	return nil
+}
+
+// IsCollected reports whether market data is collected for the symbol. Collection
+// goes on when the restrictions can't be read.
+func (srs *SymbolRestrictionService) IsCollected(ctx context.Context, symbol string) bool {
+	restriction, found, err := srs.Restriction(ctx, symbol)
+	if err != nil {
+		srs.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to check symbol restriction")
+		return true
+	}
+	return !found || restriction.Level != entities.SymbolBlacklisted
+}
+
+func restrictedSymbolError(restriction *entities.SymbolRestriction) error {
+	return entities.NewDomainError(entities.ErrConflict, "symbol %s is %sed: %s", restriction.Symbol, restriction.Level, restriction.Reason)
+}
+
+// restrictions returns the cached restrictions by symbol, reading them once when
+// they expire even if many checks miss at the same time
+func (srs *SymbolRestrictionService) restrictions(ctx context.Context) (map[string]entities.SymbolRestriction, error) {
+	if cached, ok := srs.cache.Get(symbolRestrictionsKey); ok {
+		return cached.(map[string]entities.SymbolRestriction), nil
+	}
+
+	srs.loadMutex.Lock()
+	defer srs.loadMutex.Unlock()
+	if cached, ok := srs.cache.Get(symbolRestrictionsKey); ok {
+		return cached.(map[string]entities.SymbolRestriction), nil
+	}
+
+	restrictions, err := srs.restrictionRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol restrictions: %w", err)
+	}
+	bySymbol := make(map[string]entities.SymbolRestriction, len(restrictions))
+	for _, restriction := range restrictions {
+		bySymbol[restriction.Symbol] = restriction
+	}
+	srs.cache.Set(symbolRestrictionsKey, bySymbol, symbolRestrictionsTTL)
+	return bySymbol, nil
+}
+
+// pauseAlerts disables the enabled alerts on a blacklisted symbol and notifies each
+// owner once. An alert that fails to update is logged and left enabled.
+func (srs *SymbolRestrictionService) pauseAlerts(ctx context.Context, restriction *entities.SymbolRestriction) (int, error) {
+	alerts, err := srs.alertRepo.GetBySymbol(ctx, restriction.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get alerts of %s: %w", restriction.Symbol, err)
+	}
+
+	pausedByUser := make(map[uuid.UUID]int)
+	var owners []uuid.UUID
+	for i := range alerts {
+		alert := &alerts[i]
+		if !alert.Enabled {
+			continue
+		}
+		alert.Enabled = false
+		if err := srs.alertRepo.Update(ctx, alert); err != nil {
+			srs.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to pause alert")
+			continue
+		}
+		if pausedByUser[alert.UserID] == 0 {
+			owners = append(owners, alert.UserID)
+		}
+		pausedByUser[alert.UserID]++
+	}
+
+	paused := 0
+	for _, userID := range owners {
+		paused += pausedByUser[userID]
+		srs.notifyPaused(ctx, userID, restriction, pausedByUser[userID])
+	}
+
+	srs.logger.WithFields(logrus.Fields{
+		"symbol": restriction.Symbol,
+		"paused": paused,
+		"users":  len(owners),
+	}).Info("Paused alerts of blacklisted symbol")
+	return paused, nil
+}
+
+// notifyPaused tells the user their alerts were paused; the alerts already are,
+// so a failed notification is only logged
+func (srs *SymbolRestrictionService) notifyPaused(ctx context.Context, userID uuid.UUID, restriction *entities.SymbolRestriction, paused int) {
+	if srs.notificationService == nil {
+		return
+	}
+
+	title := fmt.Sprintf("Alerts on %s paused", restriction.Symbol)
+	message := fmt.Sprintf("%d of your alerts on %s were paused because the symbol is no longer supported: %s",
+		paused, restriction.Symbol, restriction.Reason)
+	data := map[string]interface{}{
+		"symbol": restriction.Symbol,
+		"paused": paused,
+		"reason": restriction.Reason,
+	}
+	if _, err := srs.notificationService.CreateNotification(ctx, userID, NotificationTypeAlertsPaused, title, message, data); err != nil {
+		srs.logger.WithError(err).WithField("user_id", userID).Error("Failed to notify paused alerts")
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSearchLimit is the number of matches returned when no limit is given
+	DefaultSearchLimit = 10
+	// MaxSearchLimit caps the matches returned by one search
+	MaxSearchLimit = 50
+
+	// symbolIndexTTL is how long the searchable list of symbols is reused
+	symbolIndexTTL = 15 * time.Minute
+	// searchResultTTL is how long the matches of a query are reused
+	searchResultTTL = 5 * time.Minute
+	// searchCacheSize bounds the cached queries; autocomplete repeats short prefixes
+	searchCacheSize = 2000
+
+	symbolIndexKey = "symbol_index"
+)
+
+// Match quality, best first; results are ranked by quality, then by volume
+const (
+	matchExact = iota
+	matchSymbolPrefix
+	matchNamePrefix
+	matchSubstring
+	matchFuzzy
+	noMatch
+)
+
+// SymbolMatch is one search result
+type SymbolMatch struct {
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	MarketType string  `json:"market_type"`
+	ImageURL   *string `json:"image_url,omitempty"`
+	Volume24h  float64 `json:"volume_24h"`
+
+	quality int
+}
+
+// SymbolSearchService matches symbols and asset names for autocomplete. The list of
+// active symbols with their daily volume and the results of each query are cached,
+// since the alert form searches on every keystroke.
+type SymbolSearchService struct {
+	cryptoRepo       repositories.CryptoCurrencyRepository
+	priceHistoryRepo repositories.PriceHistoryRepository
+	cache            *cache.MemoryCache
+	logger           *logrus.Logger
+	buildMutex       sync.Mutex
+}
+
+// NewSymbolSearchService creates a new symbol search service
+func NewSymbolSearchService(
+	cryptoRepo repositories.CryptoCurrencyRepository,
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	logger *logrus.Logger,
+) *SymbolSearchService {
+	return &SymbolSearchService{
+		cryptoRepo:       cryptoRepo,
+		priceHistoryRepo: priceHistoryRepo,
+		cache:            cache.NewMemoryCache(searchCacheSize, time.Minute),
+		logger:           logger,
+	}
+}
+
+// Search returns the active symbols matching the query: exact and prefix matches on
+// the symbol first, then name prefixes, substrings and finally fuzzy matches where
+// the query letters appear in order (so "btusd" finds BTCUSDT)
+func (sss *SymbolSearchService) Search(ctx context.Context, query string, limit int) ([]SymbolMatch, error) {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	resultKey := fmt.Sprintf("search:%d:%s", limit, query)
+	if cached, ok := sss.cache.Get(resultKey); ok {
+		return cached.([]SymbolMatch), nil
+	}
+
+	index, err := sss.symbolIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]SymbolMatch, 0, limit)
+	for _, candidate := range index {
+		if quality := matchQuality(query, candidate.Symbol, strings.ToUpper(candidate.Name)); quality != noMatch {
+			candidate.quality = quality
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].quality != matches[j].quality {
+			return matches[i].quality < matches[j].quality
+		}
+		if matches[i].Volume24h != matches[j].Volume24h {
+			return matches[i].Volume24h > matches[j].Volume24h
+		}
+		return matches[i].Symbol < matches[j].Symbol
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	sss.cache.Set(resultKey, matches, searchResultTTL)
+	return matches, nil
+}
+
+// symbolIndex returns the cached list of active symbols, rebuilding it once when
+// it expires even if many searches miss at the same time
+func (sss *SymbolSearchService) symbolIndex(ctx context.Context) ([]SymbolMatch, error) {
+	if cached, ok := sss.cache.Get(symbolIndexKey); ok {
+		return cached.([]SymbolMatch), nil
+	}
+
+	sss.buildMutex.Lock()
+	defer sss.buildMutex.Unlock()
+	if cached, ok := sss.cache.Get(symbolIndexKey); ok {
+		return cached.([]SymbolMatch), nil
+	}
+
+	cryptos := make([]entities.CryptoCurrency, 0)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := sss.cryptoRepo.GetActive(ctx, exportPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load symbols: %w", err)
+		}
+		cryptos = append(cryptos, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	index := make([]SymbolMatch, 0, len(cryptos))
+	for _, crypto := range cryptos {
+		match := SymbolMatch{
+			Symbol:     strings.ToUpper(crypto.Symbol),
+			Name:       crypto.Name,
+			MarketType: crypto.MarketType,
+			ImageURL:   crypto.ImageURL,
+		}
+		// Symbols without a daily candle yet rank last among equal matches
+		if candle, err := sss.priceHistoryRepo.GetLatest(ctx, crypto.Symbol, "1d"); err == nil && candle != nil {
+			match.Volume24h = candle.Volume
+		}
+		index = append(index, match)
+	}
+
+	sss.logger.WithField("symbols", len(index)).Debug("Rebuilt symbol search index")
+	sss.cache.Set(symbolIndexKey, index, symbolIndexTTL)
+	return index, nil
+}
+
+// matchQuality grades how well an upper-cased query matches a symbol or name
+func matchQuality(query, symbol, name string) int {
+	switch {
+	case query == "":
+		return noMatch
+	case symbol == query:
+		return matchExact
+	case strings.HasPrefix(symbol, query):
+		return matchSymbolPrefix
+	case strings.HasPrefix(name, query):
+		return matchNamePrefix
+	case strings.Contains(symbol, query) || strings.Contains(name, query):
+		return matchSubstring
+	case isSubsequence(query, symbol) || isSubsequence(query, name):
+		return matchFuzzy
+	}
+	return noMatch
+}
+
+// isSubsequence reports whether the letters of query appear in order in s
+func isSubsequence(query, s string) bool {
+	i := 0
+	for j := 0; j < len(s) && i < len(query); j++ {
+		if s[j] == query[i] {
+			i++
+		}
+	}
+	return i == len(query)
+}
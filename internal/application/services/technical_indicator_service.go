@@ -15,9 +15,50 @@ import (
 type TechnicalIndicatorService struct {
 	priceHistoryRepo       repositories.PriceHistoryRepository
 	technicalIndicatorRepo repositories.TechnicalIndicatorRepository
+	resultCache            *IndicatorCache
 	logger                 *logrus.Logger
 }
 
+// Parameters of the indicators CalculateAllIndicators keeps up to date
+const (
+	rsiPeriod            = 14
+	emaFastPeriod        = 12
+	emaSlowPeriod        = 26
+	smaFastPeriod        = 20
+	smaSlowPeriod        = 50
+	superTrendPeriod     = 10
+	superTrendMultiplier = 3.0
+	bollingerPeriod      = 20
+	bollingerMultiplier  = 2.0
+	macdFastPeriod       = 12
+	macdSlowPeriod       = 26
+	macdSignalPeriod     = 9
+	adxPeriod            = 14
+	ichimokuTenkan       = 9
+	ichimokuKijun        = 26
+	ichimokuSenkouB      = 52
+)
+
+// defaultIndicatorKeys lists the series CalculateAllIndicators stores
+var defaultIndicatorKeys = []string{
+	entities.IndicatorKey("RSI", rsiPeriod),
+	entities.IndicatorKey("EMA", emaFastPeriod),
+	entities.IndicatorKey("EMA", emaSlowPeriod),
+	entities.IndicatorKey("SMA", smaFastPeriod),
+	entities.IndicatorKey("SMA", smaSlowPeriod),
+	entities.IndicatorKey("SuperTrend", superTrendPeriod, superTrendMultiplier),
+	entities.IndicatorKey("BB_Upper", bollingerPeriod, bollingerMultiplier),
+	entities.IndicatorKey("BB_Middle", bollingerPeriod, bollingerMultiplier),
+	entities.IndicatorKey("BB_Lower", bollingerPeriod, bollingerMultiplier),
+	entities.IndicatorKey("MACD", macdFastPeriod, macdSlowPeriod, macdSignalPeriod),
+	entities.IndicatorKey("MACD_Signal", macdFastPeriod, macdSlowPeriod, macdSignalPeriod),
+	entities.IndicatorKey("ADX", adxPeriod),
+	ichimokuIndicatorKey,
+}
+
+// ichimokuIndicatorKey is the key of the Ichimoku lines CalculateAllIndicators stores
+var ichimokuIndicatorKey = entities.IndicatorKey("Ichimoku", ichimokuTenkan, ichimokuKijun, ichimokuSenkouB)
+
 // NewTechnicalIndicatorService creates a new technical indicator service
 func NewTechnicalIndicatorService(
 	priceHistoryRepo repositories.PriceHistoryRepository,
@@ -31,6 +72,69 @@ func NewTechnicalIndicatorService(
 	}
 }
 
+// SetResultCache enables reusing indicator results computed for the current candle
+func (s *TechnicalIndicatorService) SetResultCache(resultCache *IndicatorCache) {
+	s.resultCache = resultCache
+}
+
+// CachedIndicator returns the indicator computed for the candle at candleTime, if cached
+func (s *TechnicalIndicatorService) CachedIndicator(ctx context.Context, symbol, timeframe, indicatorKey string, candleTime time.Time) (*entities.TechnicalIndicator, bool) {
+	return s.resultCache.Latest(ctx, symbol, timeframe, indicatorKey, candleTime)
+}
+
+// store saves the indicators computed for the candle at candleTime and caches them.
+// Inside a batch both wait for FlushBatch, which saves the whole batch at once.
+func (s *TechnicalIndicatorService) store(ctx context.Context, symbol, timeframe, indicatorKey string, candleTime time.Time, results ...*entities.TechnicalIndicator) error {
+	cached := make([]entities.TechnicalIndicator, len(results))
+	for i, result := range results {
+		cached[i] = *result
+	}
+
+	if batch := indicatorBatchFrom(ctx); batch != nil {
+		batch.add(indicatorCacheEntry{symbol: symbol, timeframe: timeframe, indicatorKey: indicatorKey, candleTime: candleTime, results: cached})
+		return nil
+	}
+
+	for i, result := range results {
+		if err := s.technicalIndicatorRepo.Create(ctx, result); err != nil {
+			return err
+		}
+		cached[i] = *result
+	}
+	s.resultCache.Set(ctx, symbol, timeframe, indicatorKey, candleTime, cached)
+	return nil
+}
+
+// BeginBatch returns a context whose calculations are held in batch instead of
+// being stored one by one; FlushBatch stores them
+func (s *TechnicalIndicatorService) BeginBatch(ctx context.Context) (context.Context, *IndicatorBatch) {
+	batch := &IndicatorBatch{}
+	return context.WithValue(ctx, indicatorBatchKey{}, batch), batch
+}
+
+// FlushBatch stores the indicators calculated in batch with one bulk insert and
+// caches them, returning how many were stored. They are only cached once stored,
+// so a failed flush lets the next run calculate them again.
+func (s *TechnicalIndicatorService) FlushBatch(ctx context.Context, batch *IndicatorBatch) (int, error) {
+	entries := batch.drain()
+
+	var pending []entities.TechnicalIndicator
+	for _, entry := range entries {
+		pending = append(pending, entry.results...)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if err := s.technicalIndicatorRepo.BulkInsert(ctx, pending); err != nil {
+		return 0, fmt.Errorf("failed to store indicator batch: %w", err)
+	}
+
+	for _, entry := range entries {
+		s.resultCache.Set(ctx, entry.symbol, entry.timeframe, entry.indicatorKey, entry.candleTime, entry.results)
+	}
+	return len(pending), nil
+}
+
 // CalculateAndStoreRSI calculates RSI for a symbol and timeframe and stores it
 func (s *TechnicalIndicatorService) CalculateAndStoreRSI(ctx context.Context, symbol, timeframe string, period int) error {
 	// Get price history
@@ -40,11 +144,18 @@ func (s *TechnicalIndicatorService) CalculateAndStoreRSI(ctx context.Context, sy
 	}
 
 	if len(priceHistory) < period+1 {
-		return fmt.Errorf("insufficient price data for RSI calculation")
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for RSI calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("RSI", period)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
 	}
 
 	// Extract close prices
-	var closePrices []float64
+	closePrices := make([]float64, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		closePrices = append(closePrices, ph.ClosePrice)
 	}
@@ -60,6 +171,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreRSI(ctx context.Context, sy
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "RSI",
+		IndicatorKey:  indicatorKey,
 		Value:         &rsiResult.Value,
 		Metadata: map[string]interface{}{
 			"period": period,
@@ -68,7 +180,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreRSI(ctx context.Context, sy
 		Timestamp: time.Now(),
 	}
 
-	if err := s.technicalIndicatorRepo.Create(ctx, indicator); err != nil {
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, indicator); err != nil {
 		return fmt.Errorf("failed to store RSI indicator: %w", err)
 	}
 
@@ -91,11 +203,18 @@ func (s *TechnicalIndicatorService) CalculateAndStoreEMA(ctx context.Context, sy
 	}
 
 	if len(priceHistory) < period {
-		return fmt.Errorf("insufficient price data for EMA calculation")
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for EMA calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("EMA", period)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
 	}
 
 	// Extract close prices
-	var closePrices []float64
+	closePrices := make([]float64, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		closePrices = append(closePrices, ph.ClosePrice)
 	}
@@ -111,6 +230,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreEMA(ctx context.Context, sy
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "EMA",
+		IndicatorKey:  indicatorKey,
 		Value:         &emaResult.Value,
 		Metadata: map[string]interface{}{
 			"period": period,
@@ -118,7 +238,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreEMA(ctx context.Context, sy
 		Timestamp: time.Now(),
 	}
 
-	if err := s.technicalIndicatorRepo.Create(ctx, indicator); err != nil {
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, indicator); err != nil {
 		return fmt.Errorf("failed to store EMA indicator: %w", err)
 	}
 
@@ -141,11 +261,18 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSMA(ctx context.Context, sy
 	}
 
 	if len(priceHistory) < period {
-		return fmt.Errorf("insufficient price data for SMA calculation")
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for SMA calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("SMA", period)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
 	}
 
 	// Extract close prices
-	var closePrices []float64
+	closePrices := make([]float64, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		closePrices = append(closePrices, ph.ClosePrice)
 	}
@@ -161,6 +288,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSMA(ctx context.Context, sy
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "SMA",
+		IndicatorKey:  indicatorKey,
 		Value:         &smaResult.Value,
 		Metadata: map[string]interface{}{
 			"period": period,
@@ -168,7 +296,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSMA(ctx context.Context, sy
 		Timestamp: time.Now(),
 	}
 
-	if err := s.technicalIndicatorRepo.Create(ctx, indicator); err != nil {
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, indicator); err != nil {
 		return fmt.Errorf("failed to store SMA indicator: %w", err)
 	}
 
@@ -191,11 +319,18 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSuperTrend(ctx context.Cont
 	}
 
 	if len(priceHistory) < period+1 {
-		return fmt.Errorf("insufficient price data for SuperTrend calculation")
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for SuperTrend calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("SuperTrend", period, multiplier)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
 	}
 
 	// Convert to price data format
-	var priceData []indicators.PriceData
+	priceData := make([]indicators.PriceData, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		priceData = append(priceData, indicators.PriceData{
 			Open:   ph.OpenPrice,
@@ -217,6 +352,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSuperTrend(ctx context.Cont
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "SuperTrend",
+		IndicatorKey:  indicatorKey,
 		Value:         &stResult.Value,
 		Metadata: map[string]interface{}{
 			"period":     period,
@@ -229,7 +365,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreSuperTrend(ctx context.Cont
 		Timestamp: time.Now(),
 	}
 
-	if err := s.technicalIndicatorRepo.Create(ctx, indicator); err != nil {
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, indicator); err != nil {
 		return fmt.Errorf("failed to store SuperTrend indicator: %w", err)
 	}
 
@@ -252,11 +388,18 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 	}
 
 	if len(priceHistory) < period {
-		return fmt.Errorf("insufficient price data for Bollinger Bands calculation")
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for Bollinger Bands calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("BB", period, multiplier)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
 	}
 
 	// Extract close prices
-	var closePrices []float64
+	closePrices := make([]float64, 0, len(priceHistory))
 	for _, ph := range priceHistory {
 		closePrices = append(closePrices, ph.ClosePrice)
 	}
@@ -273,6 +416,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "BB_Upper",
+		IndicatorKey:  entities.IndicatorKey("BB_Upper", period, multiplier),
 		Value:         &upperValue,
 		Metadata: map[string]interface{}{
 			"period":     period,
@@ -289,6 +433,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "BB_Middle",
+		IndicatorKey:  entities.IndicatorKey("BB_Middle", period, multiplier),
 		Value:         &middleValue,
 		Metadata: map[string]interface{}{
 			"period":     period,
@@ -303,6 +448,7 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 		Symbol:        symbol,
 		Timeframe:     timeframe,
 		IndicatorType: "BB_Lower",
+		IndicatorKey:  entities.IndicatorKey("BB_Lower", period, multiplier),
 		Value:         &lowerValue,
 		Metadata: map[string]interface{}{
 			"period":     period,
@@ -314,14 +460,8 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 	}
 
 	// Store all indicators
-	if err := s.technicalIndicatorRepo.Create(ctx, upperIndicator); err != nil {
-		return fmt.Errorf("failed to store BB upper indicator: %w", err)
-	}
-	if err := s.technicalIndicatorRepo.Create(ctx, middleIndicator); err != nil {
-		return fmt.Errorf("failed to store BB middle indicator: %w", err)
-	}
-	if err := s.technicalIndicatorRepo.Create(ctx, lowerIndicator); err != nil {
-		return fmt.Errorf("failed to store BB lower indicator: %w", err)
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, upperIndicator, middleIndicator, lowerIndicator); err != nil {
+		return fmt.Errorf("failed to store Bollinger Bands indicators: %w", err)
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -335,6 +475,333 @@ func (s *TechnicalIndicatorService) CalculateAndStoreBollingerBands(ctx context.
 	return nil
 }
 
+// CalculateAndStoreMACD calculates the MACD line and its signal line for a symbol
+// and timeframe and stores both
+func (s *TechnicalIndicatorService) CalculateAndStoreMACD(ctx context.Context, symbol, timeframe string, fastPeriod, slowPeriod, signalPeriod int) error {
+	if fastPeriod <= 0 || slowPeriod <= fastPeriod || signalPeriod <= 0 {
+		return &entities.ValidationError{Entity: "macd", Field: "periods", Message: "must be positive with the fast period shorter than the slow one"}
+	}
+
+	// The signal line needs signalPeriod MACD values, each needing slowPeriod prices
+	required := slowPeriod + signalPeriod - 1
+	priceHistory, err := s.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, required*2) // Get extra data for accuracy
+	if err != nil {
+		return fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	if len(priceHistory) < required {
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for MACD calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("MACD", fastPeriod, float64(slowPeriod), float64(signalPeriod))
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
+	}
+
+	// The repository returns newest first; the series are computed oldest first
+	closePrices := make([]float64, len(priceHistory))
+	for i, ph := range priceHistory {
+		closePrices[len(priceHistory)-1-i] = ph.ClosePrice
+	}
+
+	macdLine, err := indicators.MACDSeries(nil, closePrices, fastPeriod, slowPeriod)
+	if err != nil {
+		return fmt.Errorf("failed to calculate MACD: %w", err)
+	}
+	signalLine, err := indicators.EMASeries(nil, macdLine, signalPeriod)
+	if err != nil {
+		return fmt.Errorf("failed to calculate MACD signal: %w", err)
+	}
+
+	macdValue := macdLine[len(macdLine)-1]
+	signalValue := signalLine[len(signalLine)-1]
+	histogram := macdValue - signalValue
+
+	// Store MACD line, with the signal so a crossover can be read from one record
+	macdIndicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: "MACD",
+		IndicatorKey:  indicatorKey,
+		Value:         &macdValue,
+		Metadata: map[string]interface{}{
+			"fast_period":   fastPeriod,
+			"slow_period":   slowPeriod,
+			"signal_period": signalPeriod,
+			"signal":        signalValue,
+			"histogram":     histogram,
+		},
+		Timestamp: time.Now(),
+	}
+
+	// Store signal line
+	signalIndicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: "MACD_Signal",
+		IndicatorKey:  entities.IndicatorKey("MACD_Signal", fastPeriod, float64(slowPeriod), float64(signalPeriod)),
+		Value:         &signalValue,
+		Metadata: map[string]interface{}{
+			"fast_period":   fastPeriod,
+			"slow_period":   slowPeriod,
+			"signal_period": signalPeriod,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, macdIndicator, signalIndicator); err != nil {
+		return fmt.Errorf("failed to store MACD indicators: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"macd":      macdValue,
+		"signal":    signalValue,
+		"histogram": histogram,
+	}).Info("MACD calculated and stored")
+
+	return nil
+}
+
+// CalculateAndStoreADX calculates the ADX with +DI and -DI for a symbol and timeframe
+// and stores it, the directional indicators and trend in its metadata
+func (s *TechnicalIndicatorService) CalculateAndStoreADX(ctx context.Context, symbol, timeframe string, period int) error {
+	if period <= 0 {
+		return &entities.ValidationError{Entity: "adx", Field: "period", Message: "must be positive"}
+	}
+
+	// The first ADX averages period DX values, each needing period candles
+	required := 2 * period
+	priceHistory, err := s.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, required*2) // Get extra data for accuracy
+	if err != nil {
+		return fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	if len(priceHistory) < required {
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for ADX calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := entities.IndicatorKey("ADX", period)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
+	}
+
+	// The repository returns newest first; the smoothing runs oldest first
+	priceData := make([]indicators.PriceData, len(priceHistory))
+	for i, ph := range priceHistory {
+		priceData[len(priceHistory)-1-i] = indicators.PriceData{
+			Open:   ph.OpenPrice,
+			High:   ph.HighPrice,
+			Low:    ph.LowPrice,
+			Close:  ph.ClosePrice,
+			Volume: ph.Volume,
+		}
+	}
+
+	adxResult, err := indicators.CalculateADX(priceData, period)
+	if err != nil {
+		return fmt.Errorf("failed to calculate ADX: %w", err)
+	}
+
+	// Store indicator
+	indicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: "ADX",
+		IndicatorKey:  indicatorKey,
+		Value:         &adxResult.Value,
+		Metadata: map[string]interface{}{
+			"period":    period,
+			"plus_di":   adxResult.PlusDI,
+			"minus_di":  adxResult.MinusDI,
+			"strength":  adxResult.Strength,
+			"direction": adxResult.Direction,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, indicator); err != nil {
+		return fmt.Errorf("failed to store ADX indicator: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"adx":       adxResult.Value,
+		"plus_di":   adxResult.PlusDI,
+		"minus_di":  adxResult.MinusDI,
+	}).Info("ADX calculated and stored")
+
+	return nil
+}
+
+// CalculateAndStoreIchimoku calculates the Ichimoku lines (9, 26, 52) for a symbol
+// and timeframe and stores them with the Tenkan as value, the other lines and the
+// cloud under the latest candle in its metadata
+func (s *TechnicalIndicatorService) CalculateAndStoreIchimoku(ctx context.Context, symbol, timeframe string) error {
+	// The cloud under the latest candle was projected a Kijun period ago
+	required := ichimokuSenkouB + ichimokuKijun
+	priceHistory, err := s.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, required)
+	if err != nil {
+		return fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	if len(priceHistory) < required {
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for Ichimoku calculation")
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, ichimokuIndicatorKey, candleTime); found {
+		return nil
+	}
+
+	// The repository returns newest first; the displacement counts oldest first
+	priceData := make([]indicators.PriceData, len(priceHistory))
+	for i, ph := range priceHistory {
+		priceData[len(priceHistory)-1-i] = indicators.PriceData{
+			Open:   ph.OpenPrice,
+			High:   ph.HighPrice,
+			Low:    ph.LowPrice,
+			Close:  ph.ClosePrice,
+			Volume: ph.Volume,
+		}
+	}
+
+	ichimoku, err := indicators.CalculateIchimoku(priceData, ichimokuTenkan, ichimokuKijun, ichimokuSenkouB)
+	if err != nil {
+		return fmt.Errorf("failed to calculate Ichimoku: %w", err)
+	}
+
+	// Store indicator
+	indicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: "Ichimoku",
+		IndicatorKey:  ichimokuIndicatorKey,
+		Value:         &ichimoku.Tenkan,
+		Metadata: map[string]interface{}{
+			"tenkan_period":   ichimokuTenkan,
+			"kijun_period":    ichimokuKijun,
+			"senkou_b_period": ichimokuSenkouB,
+			"tenkan":          ichimoku.Tenkan,
+			"kijun":           ichimoku.Kijun,
+			"senkou_a":        ichimoku.SenkouA,
+			"senkou_b":        ichimoku.SenkouB,
+			"chikou":          ichimoku.Chikou,
+			"cloud_top":       ichimoku.CloudTop,
+			"cloud_bottom":    ichimoku.CloudBottom,
+			"cloud_color":     ichimoku.CloudColor,
+			"position":        ichimoku.Position,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := s.store(ctx, symbol, timeframe, ichimokuIndicatorKey, candleTime, indicator); err != nil {
+		return fmt.Errorf("failed to store Ichimoku indicator: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"tenkan":    ichimoku.Tenkan,
+		"kijun":     ichimoku.Kijun,
+		"position":  ichimoku.Position,
+	}).Info("Ichimoku calculated and stored")
+
+	return nil
+}
+
+// GetIchimoku returns the Ichimoku lines of the latest candle, calculating them
+// first when that candle has not been computed yet
+func (s *TechnicalIndicatorService) GetIchimoku(ctx context.Context, symbol, timeframe string) (*entities.TechnicalIndicator, error) {
+	if err := s.CalculateAndStoreIchimoku(ctx, symbol, timeframe); err != nil {
+		return nil, err
+	}
+
+	indicator, err := s.technicalIndicatorRepo.GetLatestByKey(ctx, symbol, timeframe, ichimokuIndicatorKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Ichimoku indicator: %w", err)
+	}
+	return indicator, nil
+}
+
+// CalculateAndStoreCustom calculates a registered custom indicator for a symbol and
+// timeframe and stores it under its indicator key
+func (s *TechnicalIndicatorService) CalculateAndStoreCustom(ctx context.Context, symbol, timeframe string, indicator indicators.CustomIndicator) error {
+	period := indicator.Period()
+
+	// Get price history
+	priceHistory, err := s.priceHistoryRepo.GetBySymbol(ctx, symbol, timeframe, period)
+	if err != nil {
+		return fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	if len(priceHistory) < period {
+		return entities.NewDomainError(entities.ErrInsufficientData, "insufficient price data for %s calculation", indicator.Name())
+	}
+
+	// Skip the calculation when this candle was already computed
+	candleTime := latestCandleTime(priceHistory)
+	indicatorKey := indicators.CustomIndicatorKey(indicator)
+	if _, found := s.resultCache.Get(ctx, symbol, timeframe, indicatorKey, candleTime); found {
+		return nil
+	}
+
+	// The repository returns newest first; custom indicators get candles oldest first
+	priceData := make([]indicators.PriceData, len(priceHistory))
+	for i, ph := range priceHistory {
+		priceData[len(priceHistory)-1-i] = indicators.PriceData{
+			Open:   ph.OpenPrice,
+			High:   ph.HighPrice,
+			Low:    ph.LowPrice,
+			Close:  ph.ClosePrice,
+			Volume: ph.Volume,
+		}
+	}
+
+	value, metadata, err := indicator.Calculate(priceData)
+	if err != nil {
+		return fmt.Errorf("failed to calculate %s: %w", indicator.Name(), err)
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["period"] = period
+	if params := indicator.Params(); len(params) > 0 {
+		metadata["params"] = params
+	}
+
+	// Store indicator
+	technicalIndicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: indicator.Name(),
+		IndicatorKey:  indicatorKey,
+		Value:         &value,
+		Metadata:      metadata,
+		Timestamp:     time.Now(),
+	}
+
+	if err := s.store(ctx, symbol, timeframe, indicatorKey, candleTime, technicalIndicator); err != nil {
+		return fmt.Errorf("failed to store %s indicator: %w", indicator.Name(), err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbol":    symbol,
+		"timeframe": timeframe,
+		"indicator": indicatorKey,
+		"value":     value,
+	}).Info("Custom indicator calculated and stored")
+
+	return nil
+}
+
 // CalculateAllIndicators calculates all indicators for a symbol and timeframe
 func (s *TechnicalIndicatorService) CalculateAllIndicators(ctx context.Context, symbol, timeframe string) error {
 	s.logger.WithFields(logrus.Fields{
@@ -343,36 +810,58 @@ func (s *TechnicalIndicatorService) CalculateAllIndicators(ctx context.Context,
 	}).Info("Calculating all indicators")
 
 	// Calculate RSI (14 period)
-	if err := s.CalculateAndStoreRSI(ctx, symbol, timeframe, 14); err != nil {
+	if err := s.CalculateAndStoreRSI(ctx, symbol, timeframe, rsiPeriod); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate RSI")
 	}
 
 	// Calculate EMAs (12, 26 period)
-	if err := s.CalculateAndStoreEMA(ctx, symbol, timeframe, 12); err != nil {
+	if err := s.CalculateAndStoreEMA(ctx, symbol, timeframe, emaFastPeriod); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate EMA 12")
 	}
-	if err := s.CalculateAndStoreEMA(ctx, symbol, timeframe, 26); err != nil {
+	if err := s.CalculateAndStoreEMA(ctx, symbol, timeframe, emaSlowPeriod); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate EMA 26")
 	}
 
 	// Calculate SMAs (20, 50 period)
-	if err := s.CalculateAndStoreSMA(ctx, symbol, timeframe, 20); err != nil {
+	if err := s.CalculateAndStoreSMA(ctx, symbol, timeframe, smaFastPeriod); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate SMA 20")
 	}
-	if err := s.CalculateAndStoreSMA(ctx, symbol, timeframe, 50); err != nil {
+	if err := s.CalculateAndStoreSMA(ctx, symbol, timeframe, smaSlowPeriod); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate SMA 50")
 	}
 
 	// Calculate SuperTrend (10 period, 3.0 multiplier)
-	if err := s.CalculateAndStoreSuperTrend(ctx, symbol, timeframe, 10, 3.0); err != nil {
+	if err := s.CalculateAndStoreSuperTrend(ctx, symbol, timeframe, superTrendPeriod, superTrendMultiplier); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate SuperTrend")
 	}
 
 	// Calculate Bollinger Bands (20 period, 2.0 multiplier)
-	if err := s.CalculateAndStoreBollingerBands(ctx, symbol, timeframe, 20, 2.0); err != nil {
+	if err := s.CalculateAndStoreBollingerBands(ctx, symbol, timeframe, bollingerPeriod, bollingerMultiplier); err != nil {
 		s.logger.WithError(err).Error("Failed to calculate Bollinger Bands")
 	}
 
+	// Calculate MACD (12, 26, 9 period)
+	if err := s.CalculateAndStoreMACD(ctx, symbol, timeframe, macdFastPeriod, macdSlowPeriod, macdSignalPeriod); err != nil {
+		s.logger.WithError(err).Error("Failed to calculate MACD")
+	}
+
+	// Calculate ADX (14 period)
+	if err := s.CalculateAndStoreADX(ctx, symbol, timeframe, adxPeriod); err != nil {
+		s.logger.WithError(err).Error("Failed to calculate ADX")
+	}
+
+	// Calculate Ichimoku (9, 26, 52 period)
+	if err := s.CalculateAndStoreIchimoku(ctx, symbol, timeframe); err != nil {
+		s.logger.WithError(err).Error("Failed to calculate Ichimoku")
+	}
+
+	// Calculate the registered custom indicators
+	for _, indicator := range indicators.CustomIndicators() {
+		if err := s.CalculateAndStoreCustom(ctx, symbol, timeframe, indicator); err != nil {
+			s.logger.WithError(err).WithField("indicator", indicator.Name()).Error("Failed to calculate custom indicator")
+		}
+	}
+
 	s.logger.WithFields(logrus.Fields{
 		"symbol":    symbol,
 		"timeframe": timeframe,
@@ -381,20 +870,25 @@ func (s *TechnicalIndicatorService) CalculateAllIndicators(ctx context.Context,
 	return nil
 }
 
-// GetLatestIndicators gets the latest calculated indicators for a symbol and timeframe
+// GetLatestIndicators gets the latest calculated indicators for a symbol and timeframe,
+// keyed by indicator key (e.g. EMA_12 and EMA_26). Every series stored for the symbol
+// is included, so new indicators show up without being listed here.
 func (s *TechnicalIndicatorService) GetLatestIndicators(ctx context.Context, symbol, timeframe string) (map[string]*entities.TechnicalIndicator, error) {
-	indicators := map[string]*entities.TechnicalIndicator{}
+	latest := map[string]*entities.TechnicalIndicator{}
 
-	indicatorTypes := []string{"RSI", "EMA", "SMA", "SuperTrend", "BB_Upper", "BB_Middle", "BB_Lower"}
+	indicatorKeys, err := s.technicalIndicatorRepo.GetIndicatorKeys(ctx, symbol, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indicators: %w", err)
+	}
 
-	for _, indicatorType := range indicatorTypes {
-		indicator, err := s.technicalIndicatorRepo.GetLatest(ctx, symbol, timeframe, indicatorType)
+	for _, indicatorKey := range indicatorKeys {
+		indicator, err := s.technicalIndicatorRepo.GetLatestByKey(ctx, symbol, timeframe, indicatorKey)
 		if err != nil {
-			s.logger.WithError(err).WithField("indicator_type", indicatorType).Warn("Failed to get latest indicator")
+			s.logger.WithError(err).WithField("indicator_key", indicatorKey).Warn("Failed to get latest indicator")
 			continue
 		}
-		indicators[indicatorType] = indicator
+		latest[indicatorKey] = indicator
 	}
 
-	return indicators, nil
+	return latest, nil
 }
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+const (
+	// TelegramLinkCodeTTL is how long a link code can be sent to the bot
+	TelegramLinkCodeTTL = 15 * time.Minute
+
+	// Telegram start parameters are limited to 64 characters of [A-Za-z0-9_-]
+	telegramCodeBytes = 16
+)
+
+var (
+	// ErrTelegramNotConfigured is returned when no bot has been configured
+	ErrTelegramNotConfigured = errors.New("telegram bot is not configured")
+	// ErrTelegramLinkNotFound is returned when the user has no Telegram link
+	ErrTelegramLinkNotFound = entities.NewDomainError(entities.ErrNotFound, "telegram link not found")
+)
+
+// TelegramLinkCode is a pending link: the user opens URL, which starts the bot with Code
+type TelegramLinkCode struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TelegramUpdate is the part of a Bot API update the link flow reads
+type TelegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID       int64  `json:"id"`
+			Type     string `json:"type"`
+			Username string `json:"username"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// TelegramLinkService links users to the Telegram chat their alerts are sent to.
+// The user asks for a code, opens the bot with it, and the bot's /start update
+// tells which chat the code came from.
+type TelegramLinkService struct {
+	links       repositories.TelegramLinkRepository
+	bot         TelegramSender
+	botUsername string
+	logger      *logrus.Logger
+	clock       clock.Clock
+}
+
+// NewTelegramLinkService creates a link service for the bot named botUsername. A
+// nil bot leaves linking disabled.
+func NewTelegramLinkService(links repositories.TelegramLinkRepository, bot TelegramSender, botUsername string, logger *logrus.Logger) *TelegramLinkService {
+	return &TelegramLinkService{
+		links:       links,
+		bot:         bot,
+		botUsername: strings.TrimPrefix(botUsername, "@"),
+		logger:      logger,
+		clock:       clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to expire link codes
+func (ts *TelegramLinkService) SetClock(c clock.Clock) {
+	ts.clock = c
+}
+
+// CreateLinkCode starts linking a chat. A chat that is already linked keeps
+// receiving alerts until another one confirms the new code.
+func (ts *TelegramLinkService) CreateLinkCode(ctx context.Context, userID uuid.UUID) (*TelegramLinkCode, error) {
+	if ts.bot == nil || ts.botUsername == "" {
+		return nil, ErrTelegramNotConfigured
+	}
+
+	buf := make([]byte, telegramCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate link code: %w", err)
+	}
+	code := hex.EncodeToString(buf)
+	expiresAt := ts.clock.Now().Add(TelegramLinkCodeTTL)
+
+	link, err := ts.links.GetByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		link = &entities.TelegramLink{UserID: userID}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get telegram link: %w", err)
+	}
+
+	link.CodeHash = hashTelegramCode(code)
+	link.CodeExpiresAt = &expiresAt
+	if err := ts.links.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save telegram link code: %w", err)
+	}
+
+	return &TelegramLinkCode{
+		Code:      code,
+		URL:       fmt.Sprintf("https://t.me/%s?start=%s", ts.botUsername, code),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetLink returns the user's link, linked or pending
+func (ts *TelegramLinkService) GetLink(ctx context.Context, userID uuid.UUID) (*entities.TelegramLink, error) {
+	link, err := ts.links.GetByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTelegramLinkNotFound
+	}
+	return link, err
+}
+
+// Unlink stops sending the user's alerts to Telegram
+func (ts *TelegramLinkService) Unlink(ctx context.Context, userID uuid.UUID) error {
+	if err := ts.links.Delete(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTelegramLinkNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// HandleUpdate processes an update from the bot webhook. Only "/start <code>" in
+// a private chat does anything; it links the chat to the code's owner.
+func (ts *TelegramLinkService) HandleUpdate(ctx context.Context, update *TelegramUpdate) error {
+	if update.Message == nil || update.Message.Chat.Type != "private" {
+		return nil
+	}
+	command, code, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	if command != "/start" {
+		return nil
+	}
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ts.reply(ctx, chatID, "Open PriceGuard and choose <b>Connect Telegram</b> to receive your alerts here.")
+	}
+
+	link, err := ts.links.GetByCodeHash(ctx, hashTelegramCode(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) ||
+		(err == nil && (link.CodeExpiresAt == nil || !ts.clock.Now().Before(*link.CodeExpiresAt))) {
+		return ts.reply(ctx, chatID, "This link has expired. Connect Telegram again from PriceGuard to get a new one.")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get telegram link: %w", err)
+	}
+
+	now := ts.clock.Now()
+	link.ChatID = chatID
+	link.Username = update.Message.Chat.Username
+	link.LinkedAt = &now
+	link.CodeHash = ""
+	link.CodeExpiresAt = nil
+	if err := ts.links.Save(ctx, link); err != nil {
+		return fmt.Errorf("failed to save telegram link: %w", err)
+	}
+
+	ts.logger.WithField("user_id", link.UserID).Info("Telegram chat linked")
+	return ts.reply(ctx, chatID, "Connected. Your PriceGuard alerts will be sent to this chat.")
+}
+
+// reply answers the chat; a failed reply is logged since the link itself is saved
+func (ts *TelegramLinkService) reply(ctx context.Context, chatID, text string) error {
+	if ts.bot == nil {
+		return nil
+	}
+	if err := ts.bot.SendMessage(ctx, chatID, text); err != nil {
+		ts.logger.WithError(err).Warn("Failed to reply to telegram chat")
+	}
+	return nil
+}
+
+func hashTelegramCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
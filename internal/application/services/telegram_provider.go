@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+)
+
+const (
+	// TelegramProviderName is the provider delivering through a Telegram bot
+	TelegramProviderName = "telegram"
+
+	telegramBaseURL = "https://api.telegram.org"
+)
+
+// TelegramConfig configures the Telegram bot
+type TelegramConfig struct {
+	BotToken string
+	// BotUsername builds the t.me link users open to connect their chat
+	BotUsername string
+	// WebhookSecret is the secret_token the webhook was registered with; Telegram
+	// sends it back on every update
+	WebhookSecret string
+}
+
+// ErrTelegramChatUnavailable is returned when Telegram refuses to deliver to a chat
+// because the user blocked the bot or the chat no longer exists
+var ErrTelegramChatUnavailable = errors.New("telegram chat unavailable")
+
+// TelegramBot calls the Telegram Bot API
+type TelegramBot struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewTelegramBot creates a Bot API client authenticated by token
+func NewTelegramBot(token string) *TelegramBot {
+	return &TelegramBot{
+		baseURL:    telegramBaseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetBaseURL points the bot at another endpoint (e.g. for tests)
+func (b *TelegramBot) SetBaseURL(baseURL string) {
+	b.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// SendMessage sends an HTML formatted message to a chat
+func (b *TelegramBot) SendMessage(ctx context.Context, chatID, text string) error {
+	_, err := b.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	return err
+}
+
+// GetMe checks the token is valid and returns the bot's username
+func (b *TelegramBot) GetMe(ctx context.Context) (string, error) {
+	result, err := b.call(ctx, "getMe", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(result, &me); err != nil {
+		return "", fmt.Errorf("invalid getMe response: %w", err)
+	}
+	return me.Username, nil
+}
+
+func (b *TelegramBot) call(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/%s", b.baseURL, b.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		// The URL holds the token, so don't let it reach the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("telegram %s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid telegram %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !result.OK {
+		err := fmt.Errorf("telegram %s failed: %d %s", method, result.ErrorCode, result.Description)
+		if result.ErrorCode == http.StatusForbidden ||
+			(result.ErrorCode == http.StatusBadRequest && strings.Contains(result.Description, "chat not found")) {
+			err = fmt.Errorf("%w: %s", ErrTelegramChatUnavailable, result.Description)
+		}
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// TelegramProviderFactory builds the Telegram provider. Selection settings may
+// override bot_token.
+func TelegramProviderFactory(cfg TelegramConfig, links repositories.TelegramLinkRepository, logger *logrus.Logger) ProviderFactory {
+	return func(settings map[string]string) (NotificationProvider, error) {
+		token := cfg.BotToken
+		if override := settings["bot_token"]; override != "" {
+			token = override
+		}
+		if token == "" {
+			return nil, errors.New("telegram bot token is required")
+		}
+		return NewTelegramProvider(NewTelegramBot(token), links, logger), nil
+	}
+}
+
+// TelegramSender sends messages to Telegram chats; *TelegramBot implements it
+type TelegramSender interface {
+	SendMessage(ctx context.Context, chatID, text string) error
+	GetMe(ctx context.Context) (string, error)
+}
+
+// TelegramProvider sends notifications to the chat a user linked, and unlinks
+// chats that blocked the bot
+type TelegramProvider struct {
+	bot    TelegramSender
+	links  repositories.TelegramLinkRepository
+	logger *logrus.Logger
+}
+
+// NewTelegramProvider creates a Telegram provider sending through bot
+func NewTelegramProvider(bot TelegramSender, links repositories.TelegramLinkRepository, logger *logrus.Logger) *TelegramProvider {
+	return &TelegramProvider{
+		bot:    bot,
+		links:  links,
+		logger: logger,
+	}
+}
+
+func (p *TelegramProvider) Name() string {
+	return TelegramProviderName
+}
+
+// HealthCheck checks the bot token is accepted
+func (p *TelegramProvider) HealthCheck(ctx context.Context) error {
+	_, err := p.bot.GetMe(ctx)
+	return err
+}
+
+// Send delivers the notification to the user's linked chat. Users without a
+// linked chat are skipped rather than failed, like users without push devices.
+func (p *TelegramProvider) Send(ctx context.Context, notification *QueuedNotification) error {
+	link, err := p.links.GetByUserID(ctx, notification.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get telegram link: %w", err)
+	}
+	if !link.IsLinked() {
+		return nil
+	}
+
+	err = p.bot.SendMessage(ctx, link.ChatID, FormatTelegramMessage(notification))
+	if errors.Is(err, ErrTelegramChatUnavailable) {
+		if err := p.links.Delete(ctx, notification.UserID); err != nil {
+			p.logger.WithError(err).Error("Failed to remove unavailable telegram link")
+		} else {
+			p.logger.WithField("user_id", notification.UserID).Info("Removed telegram link of a chat that blocked the bot")
+		}
+		return nil
+	}
+	return err
+}
+
+// FormatTelegramMessage renders a notification as a Telegram HTML message. Alert
+// triggers show the symbol, the condition and the current price; anything else
+// shows its title and message.
+func FormatTelegramMessage(notification *QueuedNotification) string {
+	var b strings.Builder
+	b.WriteString("<b>" + html.EscapeString(notification.Title) + "</b>\n")
+
+	symbol, isAlert := notification.Data["symbol"].(string)
+	if !isAlert || notification.Type != "alert_triggered" {
+		b.WriteString(html.EscapeString(notification.Message))
+		return b.String()
+	}
+
+	alertType, _ := notification.Data["alert_type"].(string)
+	condition, _ := notification.Data["condition"].(string)
+	timeframe, _ := notification.Data["timeframe"].(string)
+
+	b.WriteString("\n<b>" + html.EscapeString(symbol) + "</b>")
+	if timeframe != "" {
+		b.WriteString(" · " + html.EscapeString(timeframe))
+	}
+	b.WriteString("\n")
+	b.WriteString("Condition: " + html.EscapeString(strings.TrimSpace(alertType+" "+condition)))
+	if target, ok := telegramNumber(notification.Data["target_value"]); ok {
+		b.WriteString(" " + target)
+	}
+	b.WriteString("\n")
+	if current, ok := telegramNumber(notification.Data["current_value"]); ok {
+		b.WriteString("Current price: <b>" + current + "</b>")
+	}
+	return b.String()
+}
+
+// telegramNumber formats a value from notification data, which holds float64
+// after a round trip through the queue
+func telegramNumber(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/redis/go-redis/v9"
+)
+
+// ThrottleStore holds cooldowns by key. Acquire is atomic, so when instances in
+// several regions race to trigger the same alert only one of them wins.
+type ThrottleStore interface {
+	// Acquire starts a cooldown of ttl unless one is already running and reports whether it did
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Active reports whether the key is cooling down
+	Active(ctx context.Context, key string) (bool, error)
+	// Count returns how many keys with the prefix are cooling down
+	Count(ctx context.Context, prefix string) (int, error)
+}
+
+// MemoryThrottleStore keeps cooldowns in process; it is only safe for a single instance
+type MemoryThrottleStore struct {
+	until map[string]time.Time
+	mutex sync.Mutex
+	clock clock.Clock
+}
+
+// NewMemoryThrottleStore creates an empty in-process throttle store
+func NewMemoryThrottleStore() *MemoryThrottleStore {
+	return &MemoryThrottleStore{
+		until: make(map[string]time.Time),
+		clock: clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to expire cooldowns
+func (s *MemoryThrottleStore) SetClock(c clock.Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clock = c
+}
+
+func (s *MemoryThrottleStore) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	if until, found := s.until[key]; found && now.Before(until) {
+		return false, nil
+	}
+	s.until[key] = now.Add(ttl)
+	return true, nil
+}
+
+func (s *MemoryThrottleStore) Active(ctx context.Context, key string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	until, found := s.until[key]
+	return found && s.clock.Now().Before(until), nil
+}
+
+func (s *MemoryThrottleStore) Count(ctx context.Context, prefix string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	count := 0
+	for key, until := range s.until {
+		if strings.HasPrefix(key, prefix) && now.Before(until) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Cleanup drops expired cooldowns
+func (s *MemoryThrottleStore) Cleanup() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	for key, until := range s.until {
+		if !now.Before(until) {
+			delete(s.until, key)
+		}
+	}
+}
+
+// redisThrottleClient is the subset of the Redis client used by RedisThrottleStore
+type redisThrottleClient interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+}
+
+// RedisThrottleStore shares cooldowns between instances through Redis keys that
+// expire on their own, so no cleanup is needed
+type RedisThrottleStore struct {
+	client redisThrottleClient
+	prefix string
+	owner  string
+}
+
+// NewRedisThrottleStore creates a throttle store under the "throttle:" key space;
+// owner is stored as the value to show which instance took each cooldown
+func NewRedisThrottleStore(client redisThrottleClient, owner string) *RedisThrottleStore {
+	return &RedisThrottleStore{client: client, prefix: "throttle:", owner: owner}
+}
+
+func (s *RedisThrottleStore) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	acquired, err := s.client.SetNX(ctx, s.prefix+key, s.owner, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire throttle %s: %w", key, err)
+	}
+	return acquired, nil
+}
+
+func (s *RedisThrottleStore) Active(ctx context.Context, key string) (bool, error) {
+	exists, err := s.client.Exists(ctx, s.prefix+key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check throttle %s: %w", key, err)
+	}
+	return exists == 1, nil
+}
+
+func (s *RedisThrottleStore) Count(ctx context.Context, prefix string) (int, error) {
+	count := 0
+	iter := s.client.Scan(ctx, 0, s.prefix+prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to count throttles: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// Ticker24hSource is the exchange's rolling 24 hour statistics
+type Ticker24hSource interface {
+	GetAllTickers24h(ctx context.Context) ([]external.Ticker24h, error)
+}
+
+// Ticker24hRun summarizes one refresh of the 24h statistics
+type Ticker24hRun struct {
+	Symbols int `json:"symbols"`
+	Updated int `json:"updated"`
+	Missing int `json:"missing"` // Active symbols the exchange reported no statistics for
+	Failed  int `json:"failed"`  // Symbols left for the next run after an error
+}
+
+// Ticker24hService copies the exchange-reported 24h price change and volume of the
+// active cryptocurrencies onto them, so change_24h alerts and clients read the
+// exchange's figures rather than an approximation from the stored candles
+type Ticker24hService struct {
+	source     Ticker24hSource
+	cryptoRepo repositories.CryptoCurrencyRepository
+	logger     *logrus.Logger
+	clock      clock.Clock
+}
+
+// NewTicker24hService creates a service refreshing the 24h statistics from source
+func NewTicker24hService(
+	source Ticker24hSource,
+	cryptoRepo repositories.CryptoCurrencyRepository,
+	logger *logrus.Logger,
+) *Ticker24hService {
+	return &Ticker24hService{
+		source:     source,
+		cryptoRepo: cryptoRepo,
+		logger:     logger,
+		clock:      clock.New(),
+	}
+}
+
+// SetClock replaces the clock stamping statistics without a close time
+func (s *Ticker24hService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// RefreshOnce fetches the statistics of every symbol in one request and stores
+// those of the active cryptocurrencies. A symbol failing is left for the next run.
+func (s *Ticker24hService) RefreshOnce(ctx context.Context) (*Ticker24hRun, error) {
+	cryptos, err := s.cryptoRepo.GetActive(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active cryptocurrencies: %w", err)
+	}
+	if len(cryptos) == 0 {
+		return &Ticker24hRun{}, nil
+	}
+
+	tickers, err := s.source.GetAllTickers24h(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h tickers: %w", err)
+	}
+	bySymbol := make(map[string]external.Ticker24h, len(tickers))
+	for _, ticker := range tickers {
+		bySymbol[ticker.Symbol] = ticker
+	}
+
+	run := &Ticker24hRun{Symbols: len(cryptos)}
+	now := s.clock.Now()
+	var errs []error
+	for _, crypto := range cryptos {
+		raw, ok := bySymbol[crypto.Symbol]
+		if !ok {
+			run.Missing++
+			continue
+		}
+		ticker, err := parseTicker24h(raw, now)
+		if err == nil {
+			err = s.cryptoRepo.UpdateTicker24h(ctx, crypto.Symbol, ticker)
+		}
+		if err != nil {
+			run.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", crypto.Symbol, err))
+			continue
+		}
+		run.Updated++
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"symbols": run.Symbols,
+		"updated": run.Updated,
+		"missing": run.Missing,
+		"failed":  run.Failed,
+	}).Debug("Refreshed 24h ticker statistics")
+	return run, errors.Join(errs...)
+}
+
+// parseTicker24h converts the exchange's statistics, stamped with their close time
+// or now when the exchange didn't send one
+func parseTicker24h(raw external.Ticker24h, now time.Time) (entities.Ticker24h, error) {
+	var errs []error
+	parse := func(name, value string) float64 {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q: %w", name, value, err))
+		}
+		return parsed
+	}
+
+	ticker := entities.Ticker24h{
+		LastPrice:          parse("lastPrice", raw.LastPrice),
+		PriceChange:        parse("priceChange", raw.PriceChange),
+		PriceChangePercent: parse("priceChangePercent", raw.PriceChangePercent),
+		Volume:             parse("volume", raw.Volume),
+		QuoteVolume:        parse("quoteVolume", raw.QuoteVolume),
+	}
+	if len(errs) > 0 {
+		return entities.Ticker24h{}, errors.Join(errs...)
+	}
+
+	refreshedAt := now
+	if raw.CloseTime > 0 {
+		refreshedAt = time.UnixMilli(raw.CloseTime).UTC()
+	}
+	ticker.RefreshedAt = &refreshedAt
+	return ticker, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	// watchlistTimeframe holds the real-time prices collected every minute
+	watchlistTimeframe = "1m"
+	// watchlistLongestWindow is the longest window a change is computed over
+	watchlistLongestWindow = time.Hour
+	// watchlistCollectionSlack absorbs the jitter of the minute collection, so a price
+	// stored a few seconds less than a minute ago still counts as the 1m reference
+	watchlistCollectionSlack = 5 * time.Second
+)
+
+// WatchlistTick is the compact update of one watchlist symbol: its latest price and
+// its percent change over the last minute, 5 minutes and hour, nil while there is
+// no price that old
+type WatchlistTick struct {
+	Symbol   string   `json:"s"`
+	Price    float64  `json:"p"`
+	Change1m *float64 `json:"1m"`
+	Change5m *float64 `json:"5m"`
+	Change1h *float64 `json:"1h"`
+}
+
+// WatchlistTickerService computes the rolling percent changes of the symbols in
+// users' watchlists, so clients don't have to derive them from raw prices
+type WatchlistTickerService struct {
+	priceHistoryRepo repositories.PriceHistoryRepository
+	userSettingsRepo repositories.UserSettingsRepository
+}
+
+// NewWatchlistTickerService creates a new watchlist ticker service
+func NewWatchlistTickerService(
+	priceHistoryRepo repositories.PriceHistoryRepository,
+	userSettingsRepo repositories.UserSettingsRepository,
+) *WatchlistTickerService {
+	return &WatchlistTickerService{
+		priceHistoryRepo: priceHistoryRepo,
+		userSettingsRepo: userSettingsRepo,
+	}
+}
+
+// WatchlistSymbols returns the symbols of the user's watchlist in display order
+func (s *WatchlistTickerService) WatchlistSymbols(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	settings, err := s.userSettingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	return settings.FavoriteSymbols, nil
+}
+
+// Ticks computes the tick of each symbol, keyed by symbol; symbols without prices
+// are left out
+func (s *WatchlistTickerService) Ticks(ctx context.Context, symbols []string) (map[string]WatchlistTick, error) {
+	ticks := make(map[string]WatchlistTick, len(symbols))
+	for _, symbol := range symbols {
+		if _, done := ticks[symbol]; done {
+			continue
+		}
+		tick, err := s.tick(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if tick != nil {
+			ticks[symbol] = *tick
+		}
+	}
+	return ticks, nil
+}
+
+// tick compares the latest price with the newest price at least each window older
+func (s *WatchlistTickerService) tick(ctx context.Context, symbol string) (*WatchlistTick, error) {
+	latest, err := s.priceHistoryRepo.GetLatest(ctx, symbol, watchlistTimeframe)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest price of %s: %w", symbol, err)
+	}
+
+	from := latest.Timestamp.Add(-watchlistLongestWindow - time.Minute)
+	history, err := s.priceHistoryRepo.GetByTimeRange(ctx, symbol, watchlistTimeframe, from, latest.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history of %s: %w", symbol, err)
+	}
+
+	change := func(window time.Duration) *float64 {
+		cutoff := latest.Timestamp.Add(-window + watchlistCollectionSlack)
+		// History is oldest first, so the last price before the cutoff is the reference
+		var reference float64
+		for _, candle := range history {
+			if candle.Timestamp.After(cutoff) {
+				break
+			}
+			reference = candle.ClosePrice
+		}
+		if reference == 0 {
+			return nil
+		}
+		percent := math.Round((latest.ClosePrice-reference)/reference*100*1000) / 1000
+		return &percent
+	}
+
+	return &WatchlistTick{
+		Symbol:   symbol,
+		Price:    latest.ClosePrice,
+		Change1m: change(time.Minute),
+		Change5m: change(5 * time.Minute),
+		Change1h: change(time.Hour),
+	}, nil
+}
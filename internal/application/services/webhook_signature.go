@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+// Webhook signing scheme. Every webhook request carries three headers:
+//
+//	X-PriceGuard-Timestamp: Unix time in seconds the payload was signed at
+//	X-PriceGuard-Nonce:     random value unique to the delivery
+//	X-PriceGuard-Signature: v1=<hex HMAC-SHA256 of "<timestamp>.<nonce>.<body>">
+//
+// keyed with the webhook signing secret. Consumers recompute the HMAC over the raw
+// body, compare it in constant time, reject timestamps older than the tolerance and
+// remember nonces for twice the tolerance to reject replays, or post the delivery
+// to /api/webhooks/verify which does all of that for them.
+const (
+	WebhookTimestampHeader = "X-PriceGuard-Timestamp"
+	WebhookNonceHeader     = "X-PriceGuard-Nonce"
+	WebhookSignatureHeader = "X-PriceGuard-Signature"
+
+	// DefaultWebhookTolerance is how far a signature timestamp may be from now
+	DefaultWebhookTolerance = 5 * time.Minute
+
+	webhookSignatureVersion = "v1="
+	webhookNoncePrefix      = "webhook_nonce:"
+)
+
+var (
+	// ErrWebhookSignatureMismatch is returned when the signature doesn't match the payload
+	ErrWebhookSignatureMismatch = errors.New("webhook signature mismatch")
+	// ErrWebhookTimestampOutOfRange is returned when the payload was signed too long ago, or in the future
+	ErrWebhookTimestampOutOfRange = errors.New("webhook timestamp outside the tolerance")
+	// ErrWebhookReplayed is returned when the nonce of a valid signature was already seen
+	ErrWebhookReplayed = errors.New("webhook nonce already used")
+)
+
+// WebhookSignature holds the values of the signature headers
+type WebhookSignature struct {
+	Timestamp string `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+// Headers returns the signature as request headers
+func (s WebhookSignature) Headers() map[string]string {
+	return map[string]string{
+		WebhookTimestampHeader: s.Timestamp,
+		WebhookNonceHeader:     s.Nonce,
+		WebhookSignatureHeader: s.Signature,
+	}
+}
+
+// WebhookSigner signs webhook payloads and verifies signatures, remembering the
+// nonces of verified payloads so each one is only accepted once
+type WebhookSigner struct {
+	secret    []byte
+	tolerance time.Duration
+	nonces    ThrottleStore
+	clock     clock.Clock
+}
+
+// NewWebhookSigner creates a signer keyed by secret, with an in-process nonce store
+func NewWebhookSigner(secret string) *WebhookSigner {
+	return &WebhookSigner{
+		secret:    []byte(secret),
+		tolerance: DefaultWebhookTolerance,
+		nonces:    NewMemoryThrottleStore(),
+		clock:     clock.New(),
+	}
+}
+
+// SetTolerance sets how far a signature timestamp may be from now; values below a
+// second are ignored
+func (s *WebhookSigner) SetTolerance(tolerance time.Duration) {
+	if tolerance >= time.Second {
+		s.tolerance = tolerance
+	}
+}
+
+// SetNonceStore shares the seen nonces between instances
+func (s *WebhookSigner) SetNonceStore(store ThrottleStore) {
+	s.nonces = store
+}
+
+// SetClock replaces the clock signatures are dated and checked with
+func (s *WebhookSigner) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Sign signs the payload with the current time and a random nonce
+func (s *WebhookSigner) Sign(body []byte) (WebhookSignature, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return WebhookSignature{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(s.clock.Now().Unix(), 10)
+	encodedNonce := hex.EncodeToString(nonce)
+	return WebhookSignature{
+		Timestamp: timestamp,
+		Nonce:     encodedNonce,
+		Signature: webhookSignatureVersion + hex.EncodeToString(s.mac(timestamp, encodedNonce, body)),
+	}, nil
+}
+
+// Verify checks the signature of the payload and consumes its nonce
+func (s *WebhookSigner) Verify(ctx context.Context, sig WebhookSignature, body []byte) error {
+	signedAt, err := strconv.ParseInt(sig.Timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookSignatureMismatch
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(sig.Signature, webhookSignatureVersion))
+	if err != nil || !strings.HasPrefix(sig.Signature, webhookSignatureVersion) || sig.Nonce == "" {
+		return ErrWebhookSignatureMismatch
+	}
+	if !hmac.Equal(expected, s.mac(sig.Timestamp, sig.Nonce, body)) {
+		return ErrWebhookSignatureMismatch
+	}
+
+	age := s.clock.Now().Sub(time.Unix(signedAt, 0))
+	if age > s.tolerance || age < -s.tolerance {
+		return ErrWebhookTimestampOutOfRange
+	}
+
+	// Only signed nonces get here, so the store can't be filled by forged requests.
+	// A nonce is kept for as long as its timestamp can be accepted.
+	fresh, err := s.nonces.Acquire(ctx, webhookNoncePrefix+sig.Nonce, 2*s.tolerance)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook nonce: %w", err)
+	}
+	if !fresh {
+		return ErrWebhookReplayed
+	}
+	return nil
+}
+
+func (s *WebhookSigner) mac(timestamp, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,148 @@
+// Package container is the composition root of the API. It builds the object graph
+// from the configuration and the connections, one provider per module, so the HTTP
+// server, a worker process and the tests all share the same wiring.
+package container
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/database"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+const (
+	// backgroundScanInterval is how often the market summary reports and the abuse scan run
+	backgroundScanInterval = 15 * time.Minute
+	// alertEscalationInterval is how often unacknowledged alerts are checked for re-notification
+	alertEscalationInterval = time.Minute
+	// alertShardLeaseTTL is how long an alert shard stays with an instance that stopped
+	// renewing it; it spans a few alert evaluation cycles
+	alertShardLeaseTTL = 90 * time.Second
+)
+
+// Dependencies are the inputs of the graph: configuration, loggers and connections
+type Dependencies struct {
+	Config         *config.Config
+	Logger         *logrus.Logger
+	ZapLogger      *zap.Logger
+	DBManager      *database.Manager
+	RedisClient    *redis.Client
+	TracingManager *infrastructure.TracingManager
+}
+
+// Container holds every component of the application, built but not started
+type Container struct {
+	Deps          *Dependencies
+	Faults        *faults.Injector // nil unless fault injection is enabled
+	Repositories  *Repositories
+	Services      *Services
+	Notifications *Notifications
+	Realtime      *Realtime
+	Jobs          *Jobs
+	Storage       *Storage // nil unless object storage is configured and reachable
+	Handlers      *Handlers
+
+	cancel context.CancelFunc // cancels the context the background work runs with
+}
+
+// New builds the whole graph. Nothing runs until Start is called, so callers that
+// only need part of it (a worker, a test) can pick the components they use.
+func New(deps *Dependencies) *Container {
+	// Fault injection hooks go in first so every repository and client sees them
+	faultInjector := NewFaultInjector(deps)
+
+	repos := NewRepositories(deps.DBManager.GetDB(), NewChannelCipher(deps))
+	services := NewServices(deps, repos, faultInjector)
+	notifications := NewNotifications(deps, repos, services)
+	realtime := NewRealtime(deps, repos, services, notifications)
+	jobs := NewJobs(deps, repos, services, notifications, realtime)
+	objectStorage := NewStorage(deps, repos, jobs)
+
+	return &Container{
+		Deps:          deps,
+		Faults:        faultInjector,
+		Repositories:  repos,
+		Services:      services,
+		Notifications: notifications,
+		Realtime:      realtime,
+		Jobs:          jobs,
+		Storage:       objectStorage,
+		Handlers:      NewHandlers(deps, repos, services, notifications, realtime, jobs, objectStorage, faultInjector),
+	}
+}
+
+// Start runs the background work of every configured component until Stop is called
+// or ctx is done
+func (c *Container) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	if c.Deps.Config.Collection.AutoStart {
+		if err := c.Services.CryptoData.StartDataCollection(ctx); err != nil {
+			c.Deps.Logger.WithError(err).Warn("Failed to start market data collection")
+		}
+	}
+	c.Notifications.Service.StartProcessing(ctx)
+	c.Jobs.AlertMonitor.Start(ctx)
+	c.Jobs.Reports.Start(ctx, backgroundScanInterval)
+	c.Services.Abuse.Start(ctx, backgroundScanInterval)
+	c.Jobs.Escalations.Start(ctx, alertEscalationInterval)
+	if indicatorConfig := c.Deps.Config.Indicators; len(indicatorConfig.StreamingSymbols) > 0 {
+		if err := c.Jobs.Streaming.Start(ctx, indicatorConfig.StreamingSymbols, indicatorConfig.CalculationTimeframes); err != nil {
+			c.Deps.Logger.WithError(err).Warn("Failed to start streaming indicators")
+		}
+	}
+	if orderBookConfig := c.Deps.Config.OrderBooks; len(orderBookConfig.Symbols) > 0 {
+		if err := c.Services.OrderBooks.Start(ctx, orderBookConfig.Symbols, orderBookConfig.Levels); err != nil {
+			c.Deps.Logger.WithError(err).Warn("Failed to start order book streaming")
+		}
+	}
+	c.Jobs.Scheduler.Start(ctx)
+	if interval := c.Deps.Config.Baskets.Interval; interval > 0 {
+		c.Jobs.Baskets.Start(ctx, interval)
+	}
+	if sandboxConfig := c.Deps.Config.Sandbox; sandboxConfig.Enabled {
+		c.Jobs.Simulator.Start(ctx, sandboxConfig.SimulationInterval)
+		c.Jobs.Sandbox.Start(ctx, sandboxConfig.CleanupInterval)
+	}
+
+	go c.Realtime.Hub.Start()
+	go c.Realtime.Worker.Start(ctx)
+
+	if c.Storage != nil {
+		c.Storage.Cleaner.Start(ctx, c.Deps.Config.Storage.CleanupInterval)
+	}
+}
+
+// Stop cancels the background work started by Start and waits for each worker to exit
+func (c *Container) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.cancel = nil
+
+	if c.Storage != nil {
+		c.Storage.Cleaner.Stop()
+	}
+	c.Realtime.Worker.Stop()
+	c.Realtime.Hub.Stop()
+	c.Jobs.Sandbox.Stop()
+	c.Jobs.Simulator.Stop()
+	c.Jobs.Baskets.Stop()
+	c.Jobs.Scheduler.Stop()
+	c.Services.OrderBooks.Stop()
+	c.Jobs.Streaming.Stop()
+	c.Jobs.Escalations.Stop()
+	c.Services.Abuse.Stop()
+	c.Jobs.Reports.Stop()
+	c.Jobs.AlertMonitor.Stop()
+	c.Notifications.Service.StopProcessing()
+	c.Services.CryptoData.StopDataCollection()
+}
@@ -0,0 +1,154 @@
+package container
+
+import (
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+// Handlers are the HTTP handlers and the middlewares that hold state
+type Handlers struct {
+	AuthMiddleware *middleware.AuthMiddleware
+	PayloadCapture *middleware.PayloadCapture
+
+	Health                *handlers.HealthHandler
+	Metrics               *handlers.MetricsHandler
+	Auth                  *handlers.AuthHandler
+	User                  *handlers.UserHandler
+	Crypto                *handlers.CryptoHandler
+	Favorite              *handlers.FavoriteHandler
+	Device                *handlers.DeviceHandler
+	Telegram              *handlers.TelegramHandler
+	Alert                 *handlers.AlertHandler
+	AlertSet              *handlers.AlertSetHandler
+	Basket                *handlers.BasketHandler
+	Notification          *handlers.NotificationHandler
+	Tools                 *handlers.ToolsHandler
+	Indicator             *handlers.IndicatorHandler
+	Pullback              *handlers.PullbackHandler
+	Share                 *handlers.ShareHandler
+	APIKey                *handlers.APIKeyHandler
+	PublicPrice           *handlers.PublicPriceHandler
+	Incident              *handlers.IncidentHandler
+	NotificationProviders *handlers.NotificationProviderHandler
+	WebSocketStats        *handlers.WebSocketStatsHandler
+	Abuse                 *handlers.AbuseHandler
+	SymbolRestrictions    *handlers.SymbolRestrictionHandler
+	Backfill              *handlers.BackfillHandler
+	Retention             *handlers.RetentionHandler
+	Jobs                  *handlers.JobHandler
+	DebugCapture          *handlers.DebugCaptureHandler
+	Export                *handlers.ExportHandler  // nil without object storage
+	Fault                 *handlers.FaultHandler   // nil without fault injection
+	Sandbox               *handlers.SandboxHandler // nil unless sandbox users are enabled
+	Webhook               *handlers.WebhookHandler // nil without a webhook signing secret
+}
+
+// NewHandlers builds the HTTP handlers; objectStorage and faultInjector may be nil
+func NewHandlers(
+	deps *Dependencies,
+	repos *Repositories,
+	services *Services,
+	notifications *Notifications,
+	realtime *Realtime,
+	jobs *Jobs,
+	objectStorage *Storage,
+	faultInjector *faults.Injector,
+) *Handlers {
+	db := deps.DBManager.GetDB()
+
+	cryptoHandler := handlers.NewCryptoHandler(repos.Cryptos, repos.PriceHistory, repos.Indicators)
+	cryptoHandler.SetDetailService(appservices.NewCryptoDetailService(services.Indicators, repos.PriceHistory, repos.Alerts))
+	cryptoHandler.SetSearchService(appservices.NewSymbolSearchService(repos.Cryptos, repos.PriceHistory, deps.Logger))
+	cryptoHandler.SetUserSettingsRepo(repos.UserSettings)
+	cryptoHandler.SetCorrelationService(appservices.NewCorrelationService(repos.PriceHistory, deps.Logger))
+	cryptoHandler.SetOHLCVService(appservices.NewOHLCVService(repos.PriceHistory))
+	cryptoHandler.SetPriceAggregationService(services.Aggregation)
+	cryptoHandler.SetPriceSummaryService(jobs.Summaries)
+	cryptoHandler.SetOrderBookService(services.OrderBooks)
+
+	var telegramBot appservices.TelegramSender
+	if deps.Config.Telegram.Enabled() {
+		telegramBot = appservices.NewTelegramBot(deps.Config.Telegram.BotToken)
+	}
+	telegramHandler := handlers.NewTelegramHandler(
+		appservices.NewTelegramLinkService(repos.TelegramLinks, telegramBot, deps.Config.Telegram.BotUsername, deps.Logger),
+		deps.Config.Telegram.WebhookSecret,
+	)
+
+	alertHandler := handlers.NewAlertHandler(repos.Alerts, jobs.AlertMonitor, services.AlertEngine)
+	alertHandler.SetAlertLevelService(realtime.AlertLevels)
+	alertHandler.SetAlertChartService(appservices.NewAlertChartService(repos.PriceHistory, repos.Indicators))
+	alertHandler.SetEscalationService(jobs.Escalations)
+	alertHandler.SetSymbolRestrictionService(services.Restrictions)
+
+	alertSets := appservices.NewAlertSetService(repos.AlertSets, repos.Alerts, deps.Logger)
+	alertSets.SetSymbolRestrictionService(services.Restrictions)
+	alertSets.SetAlertLevelService(realtime.AlertLevels)
+	alertHandler.SetAlertSetService(alertSets)
+	alertHandler.SetBasketService(jobs.Baskets)
+	alertHandler.SetPriceAggregationService(services.Aggregation)
+	alertHandler.SetUserSettingsRepo(repos.UserSettings)
+
+	notificationHandler := handlers.NewNotificationHandler(repos.Notifications, notifications.Service)
+	notificationHandler.SetIncidentService(realtime.Incidents)
+	notificationHandler.SetUserSettingsRepo(repos.UserSettings)
+
+	indicatorHandler := handlers.NewIndicatorHandler(services.Indicators, deps.Logger)
+	indicatorHandler.SetStatisticsService(appservices.NewStatisticsService(repos.PriceHistory, repos.Indicators, deps.Logger))
+
+	// Admin-triggered payload capture, idle until a capture rule is added
+	payloadCapture := middleware.NewPayloadCapture(200, 16<<10)
+
+	h := &Handlers{
+		AuthMiddleware: middleware.NewAuthMiddleware(services.Auth, deps.Logger),
+		PayloadCapture: payloadCapture,
+
+		Health:                handlers.NewHealthHandler(db, deps.RedisClient),
+		Metrics:               handlers.NewMetricsHandler(db, deps.RedisClient, deps.ZapLogger),
+		Auth:                  handlers.NewAuthHandler(services.Auth, deps.Logger),
+		User:                  handlers.NewUserHandler(repos.Users, repos.UserSettings),
+		Crypto:                cryptoHandler,
+		Favorite:              handlers.NewFavoriteHandler(repos.UserSettings),
+		Device:                handlers.NewDeviceHandler(repos.DeviceTokens),
+		Telegram:              telegramHandler,
+		Alert:                 alertHandler,
+		AlertSet:              handlers.NewAlertSetHandler(alertSets),
+		Basket:                handlers.NewBasketHandler(jobs.Baskets),
+		Notification:          notificationHandler,
+		Tools:                 handlers.NewToolsHandler(appservices.NewDCASimulationService(repos.PriceHistory)),
+		Indicator:             indicatorHandler,
+		Pullback:              handlers.NewPullbackHandler(services.Pullback, deps.Logger),
+		Share:                 handlers.NewShareHandler(appservices.NewShareService(repos.ShareLinks, services.Pullback, repos.Notifications, deps.Logger)),
+		APIKey:                handlers.NewAPIKeyHandler(services.APIKeys),
+		PublicPrice:           handlers.NewPublicPriceHandler(services.PublicPrices),
+		Incident:              handlers.NewIncidentHandler(realtime.Incidents),
+		NotificationProviders: handlers.NewNotificationProviderHandler(notifications.Service.Providers()),
+		WebSocketStats:        handlers.NewWebSocketStatsHandler(realtime.AlertWebSocket),
+		Abuse:                 handlers.NewAbuseHandler(services.Abuse),
+		SymbolRestrictions:    handlers.NewSymbolRestrictionHandler(services.Restrictions),
+		Backfill:              handlers.NewBackfillHandler(appservices.NewBackfillService(services.MarketData, repos.PriceHistory, deps.Logger)),
+		Retention:             handlers.NewRetentionHandler(jobs.Retention),
+		Jobs:                  handlers.NewJobHandler(jobs.Scheduler),
+		DebugCapture:          handlers.NewDebugCaptureHandler(payloadCapture),
+	}
+
+	if objectStorage != nil {
+		h.User.SetAvatarStorage(objectStorage.Objects, deps.Config.Storage.MaxAvatarSize)
+		h.Export = handlers.NewExportHandler(objectStorage.Exports)
+	}
+	if faultInjector != nil {
+		h.Fault = handlers.NewFaultHandler(faultInjector)
+	}
+	if deps.Config.Sandbox.Enabled {
+		h.Sandbox = handlers.NewSandboxHandler(jobs.Sandbox, services.Auth, deps.Logger)
+	}
+	if deps.Config.Webhooks.SigningSecret != "" {
+		signer := appservices.NewWebhookSigner(deps.Config.Webhooks.SigningSecret)
+		signer.SetTolerance(deps.Config.Webhooks.SignatureTolerance)
+		signer.SetNonceStore(services.Throttles)
+		h.Webhook = handlers.NewWebhookHandler(signer, deps.Logger)
+	}
+	return h
+}
@@ -0,0 +1,77 @@
+package container
+
+import (
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+)
+
+// Realtime is everything pushed to clients over WebSocket: market data, alert
+// triggers, alert levels and incident banners
+type Realtime struct {
+	Hub            *websocket.Hub
+	AlertWebSocket appservices.AlertWebSocketService
+	AlertLevels    *appservices.AlertLevelService
+	Incidents      *appservices.IncidentService
+	Handler        *websocket.WebSocketHandler
+	Worker         *websocket.Worker
+}
+
+// NewRealtime builds the WebSocket hub and worker and connects the alert engine to them
+func NewRealtime(deps *Dependencies, repos *Repositories, services *Services, notifications *Notifications) *Realtime {
+	redisClient := deps.DBManager.GetRedis().GetClient()
+
+	hub := websocket.NewHub(services.Auth, deps.Logger)
+	hub.SetResumeTTL(deps.Config.WebSocket.ResumeTTL)
+	// No sticky sessions: clients may resume and receive broadcasts on any instance
+	hub.SetResumeStore(websocket.NewRedisResumeStore(redisClient))
+	hub.SetRelay(websocket.NewRedisRelay(redisClient, websocket.DefaultRelayChannel), deps.Config.Cluster.InstanceID)
+	hub.SetPresenceStore(websocket.NewRedisPresenceStore(redisClient))
+	wsPerformance := config.GetDefaultPerformanceConfig().WebSocket
+	hub.SetBroadcastWorkers(wsPerformance.BroadcastWorkers, wsPerformance.BroadcastChannelSize)
+	hub.SetCompression(wsPerformance.EnableCompression, wsPerformance.CompressionLevel, wsPerformance.CompressionThreshold)
+
+	alertWebSocketService := appservices.NewAlertWebSocketService(
+		hub,
+		notifications.Service,
+		services.AlertEngine,
+		deps.Logger,
+	)
+	services.AlertEngine.SetWebSocketService(alertWebSocketService)
+
+	// Alert lines for chart clients: sent with symbol subscriptions and on alert changes
+	alertLevelService := appservices.NewAlertLevelService(repos.Alerts, alertWebSocketService, deps.Logger)
+	hub.SetAlertLevelSource(alertLevelService.GetAlertLevels)
+	hub.SetNotificationSource(notifications.Service.GetNotificationPage)
+
+	// Every instance streams the order books itself and sends them to its own clients
+	services.OrderBooks.SetBroadcaster(hub)
+
+	handler := websocket.NewWebSocketHandler(hub, services.CryptoData, services.Indicators, services.Pullback, deps.Logger)
+	worker := websocket.NewWorker(
+		hub,
+		handler,
+		services.CryptoData,
+		services.Indicators,
+		services.Pullback,
+		services.AlertEngine,
+		notifications.Service,
+		repos.Alerts,
+		repos.PriceHistory,
+		deps.Logger,
+	)
+	worker.SetWatchlistService(appservices.NewWatchlistTickerService(repos.PriceHistory, repos.UserSettings))
+	// Without collection running from the start, the worker keeps its own refresh timers
+	if deps.Config.Collection.AutoStart {
+		worker.SetCandleEvents(services.CandleEvents)
+	}
+
+	return &Realtime{
+		Hub:            hub,
+		AlertWebSocket: alertWebSocketService,
+		AlertLevels:    alertLevelService,
+		Incidents:      appservices.NewIncidentService(repos.SystemBanners, alertWebSocketService, deps.Logger),
+		Handler:        handler,
+		Worker:         worker,
+	}
+}
@@ -0,0 +1,118 @@
+package container
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/encryption"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+// Repositories are the database-backed repositories
+type Repositories struct {
+	Users                  repositories.UserRepository
+	UserSettings           repositories.UserSettingsRepository
+	Cryptos                repositories.CryptoCurrencyRepository
+	Alerts                 repositories.AlertRepository
+	AlertSets              repositories.AlertSetRepository
+	Baskets                repositories.BasketRepository
+	Notifications          repositories.NotificationRepository
+	NotificationDeliveries repositories.NotificationDeliveryRepository
+	PriceHistory           repositories.PriceHistoryRepository
+	ExchangePrices         repositories.ExchangePriceRepository
+	PriceSummaries         repositories.PriceSummaryRepository
+	Indicators             repositories.TechnicalIndicatorRepository
+	Sessions               repositories.SessionRepository
+	SystemBanners          repositories.SystemBannerRepository
+	SymbolRestrictions     repositories.SymbolRestrictionRepository
+	ShareLinks             repositories.ShareLinkRepository
+	APIKeys                repositories.APIKeyRepository
+	AbuseFlags             repositories.AbuseFlagRepository
+	DeviceTokens           repositories.DeviceTokenRepository
+	TelegramLinks          repositories.TelegramLinkRepository
+}
+
+// NewRepositories builds the repositories on db; cipher encrypts notification
+// channel credentials and may be nil
+func NewRepositories(db *gorm.DB, cipher repository.ValueCipher) *Repositories {
+	return &Repositories{
+		Users:                  repository.NewUserRepository(db),
+		UserSettings:           repository.NewUserSettingsRepository(db),
+		Cryptos:                repository.NewCryptoCurrencyRepository(db),
+		Alerts:                 repository.NewAlertRepository(db),
+		AlertSets:              repository.NewAlertSetRepository(db),
+		Baskets:                repository.NewBasketRepository(db),
+		Notifications:          repository.NewNotificationRepository(db),
+		NotificationDeliveries: repository.NewNotificationDeliveryRepository(db),
+		PriceHistory:           repository.NewPriceHistoryRepository(db),
+		ExchangePrices:         repository.NewExchangePriceRepository(db),
+		PriceSummaries:         repository.NewPriceSummaryRepository(db),
+		Indicators:             repository.NewTechnicalIndicatorRepository(db),
+		Sessions:               repository.NewSessionRepository(db),
+		SystemBanners:          repository.NewSystemBannerRepository(db),
+		SymbolRestrictions:     repository.NewSymbolRestrictionRepository(db),
+		ShareLinks:             repository.NewShareLinkRepository(db),
+		APIKeys:                repository.NewAPIKeyRepository(db),
+		AbuseFlags:             repository.NewAbuseFlagRepository(db),
+		DeviceTokens:           repository.NewDeviceTokenRepository(db),
+		TelegramLinks:          repository.NewTelegramLinkRepository(db, cipher),
+	}
+}
+
+// NewFaultInjector installs the fault injection hooks on Redis and Postgres when
+// enabled; it returns nil otherwise, which leaves every hook inactive
+func NewFaultInjector(deps *Dependencies) *faults.Injector {
+	if !deps.Config.Faults.Enabled {
+		return nil
+	}
+
+	injector := faults.NewInjector()
+	for _, fault := range deps.Config.Faults.Faults {
+		if err := injector.Set(fault); err != nil {
+			deps.Logger.WithError(err).WithField("target", fault.Target).Error("Failed to inject configured fault")
+		}
+	}
+
+	deps.DBManager.GetRedis().GetClient().AddHook(injector.RedisHook())
+	if err := deps.DBManager.GetDB().Use(injector.GormPlugin()); err != nil {
+		deps.Logger.WithError(err).Error("Failed to install database fault injection")
+	}
+
+	deps.Logger.WithField("faults", len(deps.Config.Faults.Faults)).Warn("Fault injection enabled, dependency calls may fail on purpose")
+	return injector
+}
+
+// NewChannelCipher builds the envelope that encrypts notification channel credentials
+// at rest. Without a master key they are stored as is.
+func NewChannelCipher(deps *Dependencies) repository.ValueCipher {
+	if !deps.Config.Encryption.Enabled() {
+		deps.Logger.Warn("ENCRYPTION_MASTER_KEY is not set, notification channel credentials are stored unencrypted")
+		return nil
+	}
+
+	envelope, err := encryption.NewEnvelopeFromConfig(&deps.Config.Encryption)
+	if err != nil {
+		// Falling back to plaintext would misread the values already encrypted, so
+		// channel credentials stay unavailable until the key is fixed
+		deps.Logger.WithError(err).Error("Failed to initialize encryption, notification channel credentials are unavailable")
+		return unavailableCipher{err: err}
+	}
+	return envelope
+}
+
+// unavailableCipher fails every operation with the error that prevented building the envelope
+type unavailableCipher struct {
+	err error
+}
+
+func (c unavailableCipher) Encrypt(context.Context, uuid.UUID, string) (string, error) {
+	return "", c.err
+}
+
+func (c unavailableCipher) Decrypt(context.Context, uuid.UUID, string) (string, error) {
+	return "", c.err
+}
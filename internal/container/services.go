@@ -0,0 +1,495 @@
+package container
+
+import (
+	"context"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	domainservices "github.com/growthfolio/go-priceguard-api/internal/domain/services"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+)
+
+// Services are the authentication, market data and alert evaluation services
+type Services struct {
+	Auth        *appservices.AuthService
+	Indicators  *appservices.TechnicalIndicatorService
+	Pullback    *appservices.PullbackEntryService
+	MarketData  external.MarketDataProvider
+	CryptoData  *appservices.CryptoDataService
+	AlertEngine *appservices.AlertEngine
+	// CandleEvents carries the candle closes seen by the data collection
+	CandleEvents *appservices.CandleEventBus
+	Throttles    *appservices.RedisThrottleStore
+	Abuse        *appservices.AbuseService
+	Restrictions *appservices.SymbolRestrictionService
+	APIKeys      *appservices.APIKeyService
+	PublicPrices *appservices.PublicPriceService
+	// Aggregation consolidates prices across exchanges; without AGGREGATION_EXCHANGES
+	// it has no exchanges and never runs
+	Aggregation *appservices.PriceAggregationService
+	// OrderBooks keeps the Binance order books of ORDER_BOOK_SYMBOLS in memory
+	OrderBooks *appservices.OrderBookService
+
+	// AlertLatency records the latency of each stage of the alert pipeline
+	AlertLatency appservices.LatencyRecorderFunc
+}
+
+// NewServices builds the core services; faultInjector may be nil
+func NewServices(deps *Dependencies, repos *Repositories, faultInjector *faults.Injector) *Services {
+	jwtService := domainservices.NewJWTService(deps.Config.JWT.Secret, deps.Config.JWT.Expiration, deps.Config.JWT.RefreshExpiration)
+	googleOAuthService := domainservices.NewGoogleOAuthService(deps.Config.Google.ClientID, deps.Config.Google.ClientSecret, deps.Config.Google.RedirectURL)
+	authService := appservices.NewAuthService(
+		repos.Users,
+		repos.Sessions,
+		repos.UserSettings,
+		jwtService,
+		googleOAuthService,
+		deps.DBManager.GetRedis(),
+		deps.Logger,
+	)
+
+	technicalIndicatorService := appservices.NewTechnicalIndicatorService(repos.PriceHistory, repos.Indicators, deps.Logger)
+	cachePerformance := config.GetDefaultPerformanceConfig().Cache
+	if cachePerformance.EnableMemoryCache {
+		indicatorResults := cache.NewLayeredCache(cachePerformance.MemoryCacheSize, cachePerformance.MemoryCacheCleanup,
+			deps.DBManager.GetRedis().GetClient(), cache.WriteThrough, deps.Logger)
+		technicalIndicatorService.SetResultCache(appservices.NewIndicatorCache(indicatorResults, deps.Logger))
+	}
+
+	pullbackEntryService := appservices.NewPullbackEntryService(repos.PriceHistory, repos.Indicators, deps.Logger)
+
+	marketData, err := external.NewMarketDataProvider(deps.Config, faultInjector, deps.Logger)
+	if err != nil {
+		deps.Logger.WithError(err).Error("Failed to create market data provider, falling back to Binance")
+		binanceClient := external.NewBinanceClient(&deps.Config.Binance, deps.Logger)
+		binanceClient.SetFaultInjector(faultInjector)
+		marketData = binanceClient
+	}
+	cryptoDataService := appservices.NewCryptoDataService(
+		marketData,
+		repos.Cryptos,
+		repos.PriceHistory,
+		repos.Indicators,
+		deps.Logger,
+	)
+	// Consumers of candle closes subscribe to the bus the data collection publishes on
+	candleEvents := appservices.NewCandleEventBus(deps.Logger)
+	cryptoDataService.SetCandleEvents(candleEvents)
+
+	alertEngine := appservices.NewAlertEngine(
+		repos.Alerts,
+		repos.PriceHistory,
+		repos.Indicators,
+		repos.Notifications,
+		technicalIndicatorService,
+		deps.Logger,
+	)
+
+	// Each stage of the alert pipeline reports its latency to one histogram, so the
+	// slowest stage between the exchange event and the user stands out
+	alertLatency := appservices.LatencyRecorderFunc(func(stage string, latency time.Duration) {
+		middleware.GetMetricsCollectors().AlertPipelineLatency.WithLabelValues(stage).Observe(latency.Seconds())
+	})
+	cryptoDataService.SetLatencyRecorder(alertLatency)
+	alertEngine.SetLatencyRecorder(alertLatency)
+	alertEngine.SetDurationRecorder(alertDurationMetrics)
+
+	// Collected prices are stored in batches, which keeps up with high symbol counts
+	if collection := deps.Config.Collection; collection.FlushSize > 0 {
+		priceBuffer := appservices.NewPriceHistoryBuffer(repos.PriceHistory, deps.Logger)
+		priceBuffer.SetFlushSize(collection.FlushSize)
+		priceBuffer.SetFlushInterval(collection.FlushInterval)
+		cryptoDataService.SetPriceHistoryBuffer(priceBuffer)
+	}
+
+	// Evaluations on market data the collection stopped updating are skipped and counted
+	alertEngine.SetMaxDataAge(deps.Config.Alerts.MaxDataAge)
+	alertEngine.SetStaleDataRecorder(appservices.StaleDataRecorderFunc(func(source string) {
+		middleware.GetMetricsCollectors().AlertEvaluationsStaleTotal.WithLabelValues(source).Inc()
+	}))
+	// Percentage alerts over more than a day read their base price from the daily summaries
+	alertEngine.SetPriceSummaryRepository(repos.PriceSummaries)
+	// change_24h alerts read the exchange's 24h statistics the ticker_24h job stores
+	alertEngine.SetCryptoCurrencyRepository(repos.Cryptos)
+
+	// Throttles live in Redis so every instance, in every region, sees the same
+	// cooldowns and an alert triggers only once however many instances evaluate it
+	throttleStore := appservices.NewRedisThrottleStore(deps.DBManager.GetRedis().GetClient(), deps.Config.Cluster.InstanceID)
+	alertEngine.SetThrottleStore(throttleStore)
+	// Crossover state in Redis survives restarts and is shared between replicas; the
+	// in-process store is only for a single instance
+	if deps.Config.Alerts.StateStore != appservices.AlertStateStoreMemory {
+		alertEngine.SetStateStore(appservices.NewRedisAlertStateStore(deps.DBManager.GetRedis().GetClient()))
+	}
+
+	// Replicas split the enabled alerts by shard, so each is evaluated once per cycle
+	if shards := deps.Config.Cluster.AlertShards; shards > 0 {
+		sharder := appservices.NewAlertSharder(deps.DBManager.GetRedis().GetClient(), deps.Config.Cluster.InstanceID, shards, alertShardLeaseTTL, deps.Logger)
+		sharder.SetRecorder(shardMetrics{})
+		alertEngine.SetSharder(sharder)
+	}
+
+	// Throttle the triggers of users whose alerts look designed to spam notifications
+	abuseService := appservices.NewAbuseService(repos.Alerts, repos.AbuseFlags, deps.Logger)
+	abuseService.SetThrottleStore(throttleStore)
+	alertEngine.SetAbuseService(abuseService)
+
+	// Every evaluation is accounted to the alert's owner for fair-use reviews
+	costStore := appservices.NewRedisAlertCostStore(deps.DBManager.GetRedis().GetClient())
+	alertEngine.SetCostStore(costStore)
+	abuseService.SetCostStore(costStore)
+
+	// Blacklisted symbols are no longer collected; the restrictions also gate alert creation
+	restrictions := appservices.NewSymbolRestrictionService(repos.SymbolRestrictions, repos.Alerts, deps.Logger)
+	cryptoDataService.SetSymbolRestrictions(restrictions)
+
+	// Symbols are also collected from the other configured exchanges, and their prices
+	// consolidated into the AGG- symbols alerts can watch
+	aggregationConfig := deps.Config.Aggregation
+	aggregation := appservices.NewPriceAggregationService(repos.ExchangePrices, repos.PriceHistory, deps.Logger)
+	aggregation.SetSymbols(aggregationConfig.Symbols)
+	aggregation.SetMaxQuoteAge(aggregationConfig.MaxQuoteAge)
+	aggregation.SetHistoryRetention(aggregationConfig.HistoryRetention)
+	if err := aggregation.SetMethod(aggregationConfig.Method); err != nil {
+		deps.Logger.WithError(err).Error("Invalid AGGREGATION_METHOD, consolidating by median")
+	}
+	for _, exchange := range aggregationConfig.Exchanges {
+		if exchange == marketData.Name() {
+			aggregation.AddExchange(exchange, marketData)
+			continue
+		}
+		provider, err := external.NewExchangeProvider(exchange, deps.Config, faultInjector, deps.Logger)
+		if err != nil {
+			deps.Logger.WithError(err).Error("Failed to create aggregated exchange client")
+			continue
+		}
+		aggregation.AddExchange(exchange, provider)
+	}
+
+	// The depth stream has a connection of its own: a Binance client streams one
+	// set of streams at a time, and the market data client's serves the indicators
+	depthClient := external.NewBinanceClient(&deps.Config.Binance, deps.Logger)
+	depthClient.SetFaultInjector(faultInjector)
+	orderBooks := appservices.NewOrderBookService(depthClient, deps.Logger)
+	orderBooks.SetMaxAge(deps.Config.OrderBooks.MaxAge)
+
+	return &Services{
+		Auth:         authService,
+		Indicators:   technicalIndicatorService,
+		Pullback:     pullbackEntryService,
+		MarketData:   marketData,
+		CryptoData:   cryptoDataService,
+		AlertEngine:  alertEngine,
+		CandleEvents: candleEvents,
+		Throttles:    throttleStore,
+		Abuse:        abuseService,
+		Restrictions: restrictions,
+		APIKeys:      appservices.NewAPIKeyService(repos.APIKeys, deps.Logger),
+		PublicPrices: appservices.NewPublicPriceService(repos.PriceHistory),
+		Aggregation:  aggregation,
+		OrderBooks:   orderBooks,
+		AlertLatency: alertLatency,
+	}
+}
+
+// Notifications is the notification pipeline: the queue and its delivery providers
+type Notifications struct {
+	Service *appservices.NotificationService
+}
+
+// NewNotifications builds the notification service and registers the providers of
+// every channel, selecting the ones named in the configuration
+func NewNotifications(deps *Dependencies, repos *Repositories, services *Services) *Notifications {
+	notificationService := appservices.NewNotificationService(
+		repos.Notifications,
+		repos.Users,
+		deps.DBManager.GetRedis().GetClient(),
+		deps.Logger,
+	)
+	notificationService.SetQueuePartition(deps.Config.Cluster.Region)
+	notificationService.SetLatencyRecorder(services.AlertLatency)
+	redisPerformance := config.GetDefaultPerformanceConfig().Redis
+	notificationService.SetPipelining(redisPerformance.EnablePipelining, redisPerformance.MaxPipelineSize)
+	notificationService.SetDeliveryConfig(appservices.DeliveryConfig{
+		Workers:            deps.Config.Notifications.Workers,
+		ChannelConcurrency: deps.Config.Notifications.ChannelConcurrency,
+		ChannelTimeouts: map[appservices.NotificationChannel]time.Duration{
+			appservices.ChannelEmail:    deps.Config.Notifications.EmailTimeout,
+			appservices.ChannelPush:     deps.Config.Notifications.PushTimeout,
+			appservices.ChannelSMS:      deps.Config.Notifications.SMSTimeout,
+			appservices.ChannelTelegram: deps.Config.Notifications.TelegramTimeout,
+		},
+	})
+	notificationService.SetUserSettingsRepository(repos.UserSettings)
+	notificationService.SetDeliveryRepository(repos.NotificationDeliveries)
+	notificationService.SetDeliveryRecorder(deliveryMetrics)
+	services.Restrictions.SetNotificationService(notificationService)
+
+	providers := notificationService.Providers()
+	providers.Register(appservices.ChannelEmail, appservices.SMTPProviderName, appservices.SMTPProviderFactory(appservices.SMTPConfig{
+		Host:        deps.Config.Email.SMTPHost,
+		Port:        deps.Config.Email.SMTPPort,
+		Username:    deps.Config.Email.Username,
+		Password:    deps.Config.Email.Password,
+		From:        deps.Config.Email.From,
+		MaxAttempts: deps.Config.Email.MaxAttempts,
+		Backoff:     deps.Config.Email.RetryBackoff,
+	}, repos.Users))
+	providers.Register(appservices.ChannelPush, appservices.FCMProviderName, appservices.FCMProviderFactory(appservices.FCMConfig{
+		ProjectID:       deps.Config.Push.FCMProjectID,
+		CredentialsFile: deps.Config.Push.FCMCredentialsFile,
+	}, repos.DeviceTokens, deps.Logger))
+	providers.Register(appservices.ChannelTelegram, appservices.TelegramProviderName, appservices.TelegramProviderFactory(appservices.TelegramConfig{
+		BotToken: deps.Config.Telegram.BotToken,
+	}, repos.TelegramLinks, deps.Logger))
+	if err := providers.Reload(map[appservices.NotificationChannel]appservices.ProviderSelection{
+		appservices.ChannelEmail:    {Provider: deps.Config.Notifications.EmailProvider},
+		appservices.ChannelPush:     {Provider: deps.Config.Notifications.PushProvider},
+		appservices.ChannelSMS:      {Provider: deps.Config.Notifications.SMSProvider},
+		appservices.ChannelTelegram: {Provider: deps.Config.Notifications.TelegramProvider},
+	}); err != nil {
+		deps.Logger.WithError(err).Error("Failed to configure notification providers")
+	}
+
+	return &Notifications{Service: notificationService}
+}
+
+// Jobs are the services that work in the background on a schedule
+type Jobs struct {
+	AlertMonitor *appservices.AlertMonitor
+	Reports      *appservices.ReportService
+	Escalations  *appservices.AlertEscalationService
+	Indicators   *appservices.IndicatorCalculationWorker
+	Streaming    *appservices.StreamingIndicatorService
+	Retention    *appservices.PriceRetentionService
+	Summaries    *appservices.PriceSummaryService
+	Tickers24h   *appservices.Ticker24hService
+	Baskets      *appservices.BasketService
+	// Simulator and Sandbox only run when sandbox users are enabled
+	Simulator *appservices.MarketSimulator
+	Sandbox   *appservices.SandboxService
+	// Scheduler runs the retention, cache warmup, indicator calculation, exchange sync,
+	// price aggregation, daily summary and 24h ticker jobs, which admins can list and
+	// trigger
+	Scheduler *appservices.JobScheduler
+}
+
+// NewJobs builds the alert monitor, the market summary reports, the escalation of
+// unacknowledged alerts, which clients can also acknowledge over WebSocket, the
+// scheduled indicator calculation, the indicators streamed from the kline stream, the
+// price history retention and daily summaries, the computation of symbol baskets, the
+// market simulator and stale user cleanup behind sandbox users, and the scheduler
+// running the indicator calculation, retention, cache warmup, exchange sync, price
+// aggregation, daily summaries and the refresh of the exchange's 24h statistics
+func NewJobs(deps *Dependencies, repos *Repositories, services *Services, notifications *Notifications, realtime *Realtime) *Jobs {
+	escalations := appservices.NewAlertEscalationService(repos.Alerts, repos.Notifications, notifications.Service, deps.Logger)
+	escalations.SetThrottleStore(services.Throttles)
+	escalations.SetWebSocketService(realtime.AlertWebSocket)
+	realtime.Hub.SetAlertAckSource(escalations.Acknowledge)
+
+	indicatorConfig := deps.Config.Indicators
+	indicators := appservices.NewIndicatorCalculationWorker(repos.Cryptos, services.Indicators, deps.Logger)
+	indicators.SetTimeframes(indicatorConfig.CalculationTimeframes)
+	indicators.SetConcurrency(indicatorConfig.CalculationConcurrency)
+	indicators.SetBatchSize(indicatorConfig.CalculationBatchSize)
+	indicators.SetThrottleStore(services.Throttles)
+	indicators.SetRecorder(indicatorCalculationMetrics)
+
+	retentionConfig := deps.Config.Retention
+	retention := appservices.NewPriceRetentionService(repos.PriceHistory, deps.Logger)
+	retention.SetDryRun(retentionConfig.DryRun)
+	retention.SetThrottleStore(services.Throttles)
+	policies := make([]appservices.RetentionPolicy, len(retentionConfig.Policies))
+	for i, policy := range retentionConfig.Policies {
+		policies[i] = appservices.RetentionPolicy{Timeframe: policy.Timeframe, KeepDays: policy.KeepDays, DownsampleTo: policy.DownsampleTo}
+	}
+	if err := retention.SetPolicies(policies); err != nil {
+		// Keeping every candle is safe; deleting by a misread policy isn't
+		deps.Logger.WithError(err).Error("Invalid PRICE_RETENTION_POLICIES, price history retention disabled")
+	}
+
+	summaryConfig := deps.Config.Summaries
+	summaries := appservices.NewPriceSummaryService(repos.PriceHistory, repos.PriceSummaries, deps.Logger)
+	summaries.SetBackfillDays(summaryConfig.BackfillDays)
+	if err := summaries.SetTimeframe(summaryConfig.Timeframe); err != nil {
+		deps.Logger.WithError(err).Error("Invalid PRICE_SUMMARY_TIMEFRAME, summarizing 1h candles")
+	}
+
+	// The 24h statistics come from Binance, through the market data client when it is one
+	tickerSource, ok := services.MarketData.(appservices.Ticker24hSource)
+	if !ok {
+		tickerSource = external.NewBinanceClient(&deps.Config.Binance, deps.Logger)
+	}
+	tickers24h := appservices.NewTicker24hService(tickerSource, repos.Cryptos, deps.Logger)
+
+	scheduler := appservices.NewJobScheduler(deps.Logger)
+	scheduler.SetThrottleStore(services.Throttles)
+	retentionInterval := retentionConfig.Interval
+	if len(retention.Policies()) == 0 {
+		retentionInterval = 0
+	}
+	scheduler.Register(appservices.JobPriceRetention, retentionInterval, func(ctx context.Context) error {
+		_, err := retention.RunOnce(ctx, retentionConfig.DryRun)
+		return err
+	})
+	scheduler.Register(appservices.JobCacheWarmup, deps.Config.Jobs.CacheWarmupInterval, func(ctx context.Context) error {
+		cryptos, err := repos.Cryptos.GetActive(ctx, 0, 0)
+		if err != nil {
+			return err
+		}
+		symbols := make([]string, len(cryptos))
+		for i, crypto := range cryptos {
+			symbols[i] = crypto.Symbol
+		}
+		_, err = services.PublicPrices.WarmTickers(ctx, symbols)
+		return err
+	})
+	scheduler.Register(appservices.JobIndicatorCalculation, indicatorConfig.CalculationInterval, func(ctx context.Context) error {
+		_, err := indicators.RunOnce(ctx)
+		return err
+	})
+	scheduler.Register(appservices.JobExchangeSync, deps.Config.Jobs.ExchangeSyncInterval, services.CryptoData.UpdateCryptocurrencyList)
+	aggregationInterval := deps.Config.Aggregation.Interval
+	if !services.Aggregation.Enabled() {
+		aggregationInterval = 0
+	}
+	scheduler.Register(appservices.JobPriceAggregation, aggregationInterval, func(ctx context.Context) error {
+		_, err := services.Aggregation.CollectOnce(ctx)
+		return err
+	})
+	scheduler.Register(appservices.JobPriceSummaries, summaryConfig.Interval, func(ctx context.Context) error {
+		_, err := summaries.CompactOnce(ctx)
+		return err
+	})
+	scheduler.Register(appservices.JobTicker24h, deps.Config.Jobs.Ticker24hInterval, func(ctx context.Context) error {
+		_, err := tickers24h.RefreshOnce(ctx)
+		return err
+	})
+
+	basketConfig := deps.Config.Baskets
+	baskets := appservices.NewBasketService(repos.Baskets, repos.PriceHistory, repos.Alerts, deps.Logger)
+	baskets.SetTimeframes(basketConfig.Timeframes)
+	baskets.SetMaxPriceAge(basketConfig.MaxPriceAge)
+	baskets.SetMaxPerUser(basketConfig.MaxPerUser)
+	baskets.SetThrottleStore(services.Throttles)
+
+	sandboxConfig := deps.Config.Sandbox
+	simulator := appservices.NewMarketSimulator(repos.PriceHistory, deps.Logger)
+	simulator.SetVolatilityBps(sandboxConfig.VolatilityBps)
+	simulator.SetThrottleStore(services.Throttles)
+	sandbox := appservices.NewSandboxService(repos.Users, repos.UserSettings, repos.Alerts, simulator, deps.Logger)
+	sandbox.SetTTL(sandboxConfig.UserTTL)
+	sandbox.SetThrottleStore(services.Throttles)
+
+	reports := appservices.NewReportService(
+		repos.UserSettings,
+		repos.Alerts,
+		repos.PriceHistory,
+		repos.Indicators,
+		notifications.Service,
+		deps.Logger,
+	)
+	reports.SetPriceSummaryRepository(repos.PriceSummaries)
+
+	return &Jobs{
+		AlertMonitor: appservices.NewAlertMonitor(
+			services.AlertEngine,
+			notifications.Service,
+			services.CryptoData,
+			repos.Alerts,
+			deps.Logger,
+		),
+		Reports:     reports,
+		Escalations: escalations,
+		Indicators:  indicators,
+		Streaming:   appservices.NewStreamingIndicatorService(services.MarketData, repos.PriceHistory, services.Indicators, deps.Logger),
+		Retention:   retention,
+		Summaries:   summaries,
+		Tickers24h:  tickers24h,
+		Baskets:     baskets,
+		Simulator:   simulator,
+		Sandbox:     sandbox,
+		Scheduler:   scheduler,
+	}
+}
+
+// Storage is the object storage behind avatar uploads, user exports and report files
+type Storage struct {
+	Objects *storage.S3Storage
+	Exports *appservices.ExportService
+	Cleaner *storage.LifecycleCleaner
+}
+
+// NewStorage connects to the object storage when it is configured, and returns nil
+// when it isn't or can't be reached; the features depending on it are left out
+func NewStorage(deps *Dependencies, repos *Repositories, jobs *Jobs) *Storage {
+	if !deps.Config.Storage.Enabled() {
+		return nil
+	}
+
+	objectStorage, err := storage.NewS3Storage(&deps.Config.Storage)
+	if err != nil {
+		deps.Logger.WithError(err).Error("Failed to initialize object storage")
+		return nil
+	}
+	jobs.Reports.SetStorage(objectStorage)
+
+	return &Storage{
+		Objects: objectStorage,
+		Exports: appservices.NewExportService(repos.Users, repos.UserSettings, repos.Alerts, repos.Notifications,
+			objectStorage, deps.Config.Storage.PresignExpiry, deps.Logger),
+		Cleaner: storage.NewLifecycleCleaner(objectStorage, deps.Logger,
+			storage.LifecycleRule{Prefix: appservices.ExportPrefix, MaxAge: deps.Config.Storage.ExportTTL},
+			// Report links are presigned for the maximum 7 days, so keep the files a little longer
+			storage.LifecycleRule{Prefix: appservices.ReportPrefix, MaxAge: 30 * 24 * time.Hour},
+		),
+	}
+}
+
+// deliveryMetrics reports every notification delivery attempt to Prometheus: its
+// outcome, its latency when sent, and whether it was a retry
+var deliveryMetrics = appservices.DeliveryRecorderFunc(func(channel appservices.NotificationChannel, status string, attempt int, latency time.Duration) {
+	metrics := middleware.GetMetricsCollectors()
+	metrics.NotificationsTotal.WithLabelValues(string(channel), status).Inc()
+	if status == entities.DeliveryStatusSent {
+		metrics.NotificationDeliveryLatency.WithLabelValues(string(channel)).Observe(latency.Seconds())
+	}
+	if attempt > 1 {
+		metrics.NotificationRetriesTotal.WithLabelValues(string(channel), status).Inc()
+	}
+})
+
+// indicatorCalculationMetrics reports how long the scheduled indicator calculation
+// takes to Prometheus
+var indicatorCalculationMetrics = appservices.IndicatorCalculationRecorderFunc(func(scope string, duration time.Duration) {
+	middleware.GetMetricsCollectors().IndicatorCalculationDuration.WithLabelValues(scope).Observe(duration.Seconds())
+})
+
+// alertDurationMetrics reports alert evaluation and broadcast durations to
+// Prometheus, with the trace they ran in as an exemplar
+var alertDurationMetrics = appservices.DurationRecorderFunc(func(ctx context.Context, operation string, duration time.Duration) {
+	metrics := middleware.GetMetricsCollectors()
+	switch operation {
+	case appservices.DurationAlertEvaluation:
+		middleware.ObserveWithTraceExemplar(ctx, metrics.AlertEvaluationDuration, duration.Seconds())
+	case appservices.DurationBroadcast:
+		middleware.ObserveWithTraceExemplar(ctx, metrics.WebSocketBroadcastDuration, duration.Seconds())
+	}
+})
+
+// shardMetrics reports the alert shards held by this instance to Prometheus
+type shardMetrics struct{}
+
+func (shardMetrics) SetOwnedShards(owned int) {
+	middleware.GetMetricsCollectors().AlertShardsOwned.Set(float64(owned))
+}
+
+func (shardMetrics) ObserveHandoff(direction string) {
+	middleware.GetMetricsCollectors().AlertShardHandoffsTotal.WithLabelValues(direction).Inc()
+}
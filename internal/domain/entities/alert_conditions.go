@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// CompositeAlertType is the alert type of alerts, and nested condition groups,
+// combining several conditions
+const CompositeAlertType = "composite"
+
+// IsComposite reports whether the alert combines the conditions in its tree
+func (a *Alert) IsComposite() bool {
+	return a.AlertType == CompositeAlertType
+}
+
+// IsGroup reports whether the condition combines nested conditions
+func (c *AlertCondition) IsGroup() bool {
+	return c.AlertType == CompositeAlertType
+}
+
+// ReplaceConditions sets the condition tree from client input, dropping any IDs it
+// carries so new rows never collide with stored ones
+func (a *Alert) ReplaceConditions(conditions []AlertCondition) {
+	a.Conditions = cloneConditions(conditions)
+}
+
+func cloneConditions(conditions []AlertCondition) []AlertCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	cloned := make([]AlertCondition, len(conditions))
+	for i, condition := range conditions {
+		cloned[i] = AlertCondition{
+			AlertType:     condition.AlertType,
+			ConditionType: condition.ConditionType,
+			TargetValue:   condition.TargetValue,
+			Conditions:    cloneConditions(condition.Conditions),
+		}
+	}
+	return cloned
+}
+
+// FlattenConditions numbers the condition tree, giving new conditions an ID and
+// linking every node to the alert, its parent and its position, and returns the
+// nodes parents first as they are stored
+func (a *Alert) FlattenConditions() []AlertCondition {
+	var rows []AlertCondition
+	flattenConditions(a.ID, nil, a.Conditions, &rows)
+	return rows
+}
+
+func flattenConditions(alertID uuid.UUID, parentID *uuid.UUID, conditions []AlertCondition, rows *[]AlertCondition) {
+	for i := range conditions {
+		condition := &conditions[i]
+		if condition.ID == uuid.Nil {
+			condition.ID = uuid.New()
+		}
+		condition.AlertID = alertID
+		condition.ParentID = parentID
+		condition.Position = i
+
+		row := *condition
+		row.Conditions = nil
+		*rows = append(*rows, row)
+	}
+	for i := range conditions {
+		flattenConditions(alertID, &conditions[i].ID, conditions[i].Conditions, rows)
+	}
+}
+
+// SetConditions rebuilds the condition tree from its stored rows
+func (a *Alert) SetConditions(rows []AlertCondition) {
+	children := make(map[uuid.UUID][]AlertCondition)
+	var roots []AlertCondition
+	for _, row := range rows {
+		if row.ParentID == nil {
+			roots = append(roots, row)
+		} else {
+			children[*row.ParentID] = append(children[*row.ParentID], row)
+		}
+	}
+	a.Conditions = buildConditionTree(roots, children)
+}
+
+func buildConditionTree(conditions []AlertCondition, children map[uuid.UUID][]AlertCondition) []AlertCondition {
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Position < conditions[j].Position })
+	for i := range conditions {
+		conditions[i].Conditions = buildConditionTree(children[conditions[i].ID], children)
+	}
+	return conditions
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// BasketSymbolPrefix starts the synthetic symbol a basket is priced under
+const BasketSymbolPrefix = "BASKET-"
+
+// DefaultBasketBaseValue is the value a basket starts at
+const DefaultBasketBaseValue = 100
+
+// BasketSymbol returns the synthetic symbol of the basket with the ID
+func BasketSymbol(id uuid.UUID) string {
+	return BasketSymbolPrefix + strings.ToUpper(strings.ReplaceAll(id.String(), "-", "")[:12])
+}
+
+// IsBasketSymbol reports whether the symbol is a basket's rather than a market's
+func IsBasketSymbol(symbol string) bool {
+	return strings.HasPrefix(symbol, BasketSymbolPrefix)
+}
+
+// Value is the basket's value at the component prices, or false when the price of
+// a component is missing
+func (b *Basket) Value(prices map[string]float64) (float64, bool) {
+	value := 0.0
+	for _, component := range b.Components {
+		price, ok := prices[component.Symbol]
+		if !ok || component.ReferencePrice <= 0 {
+			return 0, false
+		}
+		value += component.Weight / 100 * price / component.ReferencePrice
+	}
+	return b.BaseValue * value, true
+}
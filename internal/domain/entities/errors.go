@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds shared by every layer. Services wrap them so handlers can pick a
+// status code with errors.Is instead of matching messages.
+var (
+	// ErrNotFound means the requested resource doesn't exist or isn't visible to the caller
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the request clashes with the resource's current state
+	ErrConflict = errors.New("conflict")
+	// ErrUpstreamUnavailable means an external dependency such as Binance failed or timed out
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrInsufficientData means there isn't enough market data to compute the result yet
+	ErrInsufficientData = errors.New("insufficient data")
+	// ErrStaleData means the latest market data is too old to act on
+	ErrStaleData = errors.New("stale data")
+)
+
+// DomainError is an error of a known kind with its own message
+type DomainError struct {
+	Kind    error
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Unwrap lets callers use errors.Is(err, e.Kind)
+func (e *DomainError) Unwrap() error {
+	return e.Kind
+}
+
+// NewDomainError creates an error of kind with a formatted message
+func NewDomainError(kind error, format string, args ...interface{}) error {
+	return &DomainError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
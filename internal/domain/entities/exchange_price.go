@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// ConsolidatedSymbolPrefix starts the synthetic symbol the price of a symbol
+// consolidated across exchanges is stored under
+const ConsolidatedSymbolPrefix = "AGG-"
+
+// ConsolidatedSymbol returns the synthetic symbol of the consolidated price of the
+// symbol, such as AGG-BTCUSDT
+func ConsolidatedSymbol(symbol string) string {
+	return ConsolidatedSymbolPrefix + strings.ToUpper(symbol)
+}
+
+// IsConsolidatedSymbol reports whether the symbol is a consolidated price's rather
+// than a market's
+func IsConsolidatedSymbol(symbol string) bool {
+	return strings.HasPrefix(symbol, ConsolidatedSymbolPrefix)
+}
+
+// ConsolidatedMarket returns the symbol whose price a consolidated symbol holds
+func ConsolidatedMarket(symbol string) string {
+	return strings.TrimPrefix(symbol, ConsolidatedSymbolPrefix)
+}
+
+// ExchangePrice is the price of a symbol observed on one exchange, with the volume
+// traded there in the minute so far
+type ExchangePrice struct {
+	ID        int64     `json:"id" gorm:"primary_key;autoIncrement"`
+	Symbol    string    `json:"symbol" gorm:"not null;index:idx_exchange_price_symbol"`
+	Exchange  string    `json:"exchange" gorm:"not null;index:idx_exchange_price_symbol"`
+	Price     float64   `json:"price" gorm:"type:decimal(20,8);not null"`
+	Volume    float64   `json:"volume" gorm:"type:decimal(30,8);not null"`
+	Timestamp time.Time `json:"timestamp" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name used by ExchangePrice to `exchange_price_history`
+func (ExchangePrice) TableName() string {
+	return "exchange_price_history"
+}
@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"strings"
+)
+
+var (
+	// ErrFavoriteExists is returned when adding a symbol that is already a favorite
+	ErrFavoriteExists = NewDomainError(ErrConflict, "symbol is already a favorite")
+	// ErrFavoriteNotFound is returned when changing a symbol that is not a favorite
+	ErrFavoriteNotFound = NewDomainError(ErrNotFound, "symbol is not a favorite")
+)
+
+// FavoriteSymbol is a favorite with its display label and place in the user's list
+type FavoriteSymbol struct {
+	Symbol   string `json:"symbol"`
+	Label    string `json:"label,omitempty"`
+	Position int    `json:"position"`
+}
+
+// Favorites lists the favorite symbols in the user's order
+func (s *UserSettings) Favorites() []FavoriteSymbol {
+	favorites := make([]FavoriteSymbol, len(s.FavoriteSymbols))
+	for i, symbol := range s.FavoriteSymbols {
+		favorites[i] = FavoriteSymbol{Symbol: symbol, Label: s.FavoriteLabels[symbol], Position: i}
+	}
+	return favorites
+}
+
+// FavoritePosition returns where a symbol sits in the favorites list
+func (s *UserSettings) FavoritePosition(symbol string) (int, bool) {
+	for i, favorite := range s.FavoriteSymbols {
+		if strings.EqualFold(favorite, symbol) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// SetFavoriteSymbols replaces the favorites list, dropping the labels of symbols
+// that are no longer in it
+func (s *UserSettings) SetFavoriteSymbols(symbols []string) {
+	s.FavoriteSymbols = make([]string, len(symbols))
+	for i, symbol := range symbols {
+		s.FavoriteSymbols[i] = strings.ToUpper(strings.TrimSpace(symbol))
+	}
+
+	for symbol := range s.FavoriteLabels {
+		if _, ok := s.FavoritePosition(symbol); !ok {
+			delete(s.FavoriteLabels, symbol)
+		}
+	}
+}
+
+// AddFavorite inserts a symbol at position, or at the end when position is nil
+func (s *UserSettings) AddFavorite(symbol, label string, position *int) error {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if _, ok := s.FavoritePosition(symbol); ok {
+		return ErrFavoriteExists
+	}
+
+	s.FavoriteSymbols = append(s.FavoriteSymbols, symbol)
+	if position != nil {
+		s.moveFavorite(len(s.FavoriteSymbols)-1, *position)
+	}
+	s.setFavoriteLabel(symbol, label)
+	return nil
+}
+
+// UpdateFavorite changes the label and/or position of a favorite
+func (s *UserSettings) UpdateFavorite(symbol string, label *string, position *int) error {
+	from, ok := s.FavoritePosition(symbol)
+	if !ok {
+		return ErrFavoriteNotFound
+	}
+
+	if label != nil {
+		s.setFavoriteLabel(s.FavoriteSymbols[from], *label)
+	}
+	if position != nil {
+		s.moveFavorite(from, *position)
+	}
+	return nil
+}
+
+// RemoveFavorite drops a symbol and its label from the favorites
+func (s *UserSettings) RemoveFavorite(symbol string) error {
+	position, ok := s.FavoritePosition(symbol)
+	if !ok {
+		return ErrFavoriteNotFound
+	}
+
+	delete(s.FavoriteLabels, s.FavoriteSymbols[position])
+	s.FavoriteSymbols = append(s.FavoriteSymbols[:position], s.FavoriteSymbols[position+1:]...)
+	return nil
+}
+
+// ReorderFavorites puts the favorites in the given order, which must list every
+// favorite exactly once
+func (s *UserSettings) ReorderFavorites(symbols []string) error {
+	if len(symbols) != len(s.FavoriteSymbols) {
+		return newValidationError("user_settings", "favorite_symbols", "must list all %d favorites", len(s.FavoriteSymbols))
+	}
+
+	ordered := make([]string, 0, len(symbols))
+	seen := make(map[int]bool, len(symbols))
+	for _, symbol := range symbols {
+		position, ok := s.FavoritePosition(symbol)
+		if !ok || seen[position] {
+			return newValidationError("user_settings", "favorite_symbols", "must list each favorite exactly once, got %q", symbol)
+		}
+		seen[position] = true
+		ordered = append(ordered, s.FavoriteSymbols[position])
+	}
+
+	s.FavoriteSymbols = ordered
+	return nil
+}
+
+// moveFavorite moves the favorite at index from to index to, clamped to the list
+func (s *UserSettings) moveFavorite(from, to int) {
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(s.FavoriteSymbols) {
+		to = len(s.FavoriteSymbols) - 1
+	}
+
+	symbol := s.FavoriteSymbols[from]
+	s.FavoriteSymbols = append(s.FavoriteSymbols[:from], s.FavoriteSymbols[from+1:]...)
+	s.FavoriteSymbols = append(s.FavoriteSymbols[:to], append([]string{symbol}, s.FavoriteSymbols[to:]...)...)
+}
+
+// setFavoriteLabel stores a label, or clears it when empty
+func (s *UserSettings) setFavoriteLabel(symbol, label string) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		delete(s.FavoriteLabels, symbol)
+		return
+	}
+	if s.FavoriteLabels == nil {
+		s.FavoriteLabels = make(map[string]string)
+	}
+	s.FavoriteLabels[symbol] = label
+}
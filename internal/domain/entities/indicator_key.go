@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IndicatorKey identifies one configured indicator series by type, period and any
+// extra parameters, so EMA 12 and EMA 26 are stored and looked up separately:
+// IndicatorKey("EMA", 12) is "EMA_12", IndicatorKey("SuperTrend", 10, 3) is "SuperTrend_10_3"
+func IndicatorKey(indicatorType string, period int, params ...float64) string {
+	parts := make([]string, 0, len(params)+2)
+	parts = append(parts, indicatorType, strconv.Itoa(period))
+	for _, param := range params {
+		parts = append(parts, strconv.FormatFloat(param, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
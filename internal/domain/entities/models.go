@@ -18,6 +18,13 @@ type User struct {
 	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 
+	// Sandbox users are demo accounts provisioned without a Google sign-in, whose
+	// alerts watch simulated symbols; they are deleted once stale
+	Sandbox bool `json:"sandbox" gorm:"not null;default:false;index"`
+
+	// AvatarURL is the image clients should render; it is resolved, not stored
+	AvatarURL string `json:"avatar_url,omitempty" gorm:"-"`
+
 	// Relationships
 	Settings      *UserSettings  `json:"settings,omitempty" gorm:"foreignKey:UserID"`
 	Alerts        []Alert        `json:"alerts,omitempty" gorm:"foreignKey:UserID"`
@@ -25,20 +32,38 @@ type User struct {
 	Sessions      []Session      `json:"sessions,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// ResolveAvatarURL fills AvatarURL, preferring a custom upload over the OAuth picture
+func (u *User) ResolveAvatarURL() string {
+	switch {
+	case u.Avatar != nil && *u.Avatar != "":
+		u.AvatarURL = *u.Avatar
+	case u.Picture != nil && *u.Picture != "":
+		u.AvatarURL = *u.Picture
+	default:
+		u.AvatarURL = ""
+	}
+	return u.AvatarURL
+}
+
 // UserSettings represents user preferences and settings
 type UserSettings struct {
-	ID                 uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID             uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
-	Theme              string         `json:"theme" gorm:"default:'dark'"`
-	DefaultTimeframe   string         `json:"default_timeframe" gorm:"default:'1h'"`
-	DefaultView        string         `json:"default_view" gorm:"default:'overview'"`
-	NotificationsEmail bool           `json:"notifications_email" gorm:"default:true"`
-	NotificationsPush  bool           `json:"notifications_push" gorm:"default:true"`
-	NotificationsSMS   bool           `json:"notifications_sms" gorm:"default:false"`
-	RiskProfile        string         `json:"risk_profile" gorm:"default:'moderate'"`
-	FavoriteSymbols    pq.StringArray `json:"favorite_symbols" gorm:"type:text[]"`
-	CreatedAt          time.Time      `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt          time.Time      `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID                 uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID             uuid.UUID         `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Theme              string            `json:"theme" gorm:"default:'dark'"`
+	DefaultTimeframe   string            `json:"default_timeframe" gorm:"default:'1h'"`
+	DefaultView        string            `json:"default_view" gorm:"default:'overview'"`
+	NotificationsEmail bool              `json:"notifications_email" gorm:"default:true"`
+	NotificationsPush  bool              `json:"notifications_push" gorm:"default:true"`
+	NotificationsSMS   bool              `json:"notifications_sms" gorm:"default:false"`
+	RiskProfile        string            `json:"risk_profile" gorm:"default:'moderate'"`
+	FavoriteSymbols    pq.StringArray    `json:"favorite_symbols" gorm:"type:text[]"`                         // In display order
+	FavoriteLabels     map[string]string `json:"favorite_labels,omitempty" gorm:"type:jsonb;serializer:json"` // Symbol -> custom label
+	ReportFrequency    string            `json:"report_frequency" gorm:"default:'none'"`
+	ReportHour         int               `json:"report_hour" gorm:"default:8"`
+	LastReportAt       *time.Time        `json:"last_report_at,omitempty"`
+	Locale             string            `json:"locale" gorm:"not null;default:''"` // Display strings are formatted for it, e.g. pt-BR; empty only on request
+	CreatedAt          time.Time         `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time         `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 
 	// Relationships
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
@@ -54,6 +79,8 @@ type CryptoCurrency struct {
 	Active     bool      `json:"active" gorm:"default:true"`
 	CreatedAt  time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt  time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	// Ticker24h is refreshed from the exchange by the ticker_24h job
+	Ticker24h `gorm:"embedded"`
 
 	// Relationships
 	Alerts         []Alert              `json:"alerts,omitempty" gorm:"foreignKey:Symbol;references:Symbol"`
@@ -68,24 +95,166 @@ func (CryptoCurrency) TableName() string {
 
 // Alert represents a user alert
 type Alert struct {
-	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID        uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
-	Symbol        string         `json:"symbol" gorm:"not null;index"`
-	AlertType     string         `json:"alert_type" gorm:"not null"`     // 'price', 'rsi', 'ema_cross', etc.
-	ConditionType string         `json:"condition_type" gorm:"not null"` // 'above', 'below', 'crosses'
-	TargetValue   float64        `json:"target_value" gorm:"type:decimal(20,8);not null"`
-	Timeframe     string         `json:"timeframe" gorm:"not null"`
-	Enabled       bool           `json:"enabled" gorm:"default:true"`
-	NotifyVia     pq.StringArray `json:"notify_via" gorm:"type:text[];default:'{app}'"`
-	TriggeredAt   *time.Time     `json:"triggered_at,omitempty"`
-	CreatedAt     time.Time      `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt     time.Time      `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID          uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Symbol          string         `json:"symbol" gorm:"not null;index"`
+	AlertType       string         `json:"alert_type" gorm:"not null"`     // 'price', 'rsi', 'ema_cross', etc.
+	ConditionType   string         `json:"condition_type" gorm:"not null"` // 'above', 'below', 'crosses_up', 'crosses_down'
+	TargetValue     float64        `json:"target_value" gorm:"type:decimal(20,8);not null"`
+	Timeframe       string         `json:"timeframe" gorm:"not null"`
+	Enabled         bool           `json:"enabled" gorm:"default:true"`
+	NotifyVia       pq.StringArray `json:"notify_via" gorm:"type:text[];default:'{app}'"`
+	Priority        string         `json:"priority" gorm:"default:'high'"`               // 'low', 'normal', 'high', 'urgent'
+	CooldownSeconds int            `json:"cooldown_seconds" gorm:"not null;default:300"` // quiet period after a trigger, 0 for the default
+	TriggeredAt     *time.Time     `json:"triggered_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	// AckRequired alerts keep re-notifying, over more channels each time, every
+	// AckIntervalMinutes (0 for the default) until the user acknowledges the trigger
+	AckRequired        bool       `json:"ack_required" gorm:"not null;default:false"`
+	AckIntervalMinutes int        `json:"ack_interval_minutes" gorm:"not null;default:0"`
+	AwaitingAckSince   *time.Time `json:"awaiting_ack_since,omitempty" gorm:"index"`
+	EscalationLevel    int        `json:"escalation_level" gorm:"not null;default:0"` // re-notifications sent since the trigger
+	LastEscalatedAt    *time.Time `json:"last_escalated_at,omitempty"`
+	AcknowledgedAt     *time.Time `json:"acknowledged_at,omitempty"`
+	// MaxDataAgeSeconds is how long past its candle's close the market data of an
+	// evaluation may be; 0 uses the engine's default
+	MaxDataAgeSeconds int `json:"max_data_age_seconds" gorm:"not null;default:0"`
+	// WindowDays is how many days back a percentage alert compares the price with;
+	// 0 compares it with 24 hours ago
+	WindowDays int `json:"window_days" gorm:"not null;default:0"`
+	// Conditions is the condition tree of a composite alert, stored flat in alert_conditions
+	Conditions []AlertCondition `json:"conditions,omitempty" gorm:"-"`
+	// SetID is the alert set the alert belongs to, if any
+	SetID *uuid.UUID `json:"set_id,omitempty" gorm:"type:uuid;index"`
+	// DependsOnID chains the alert after another of the user's alerts: it is only
+	// evaluated once that alert triggered, which arms it at ArmedAt
+	DependsOnID *uuid.UUID `json:"depends_on_id,omitempty" gorm:"type:uuid;index"`
+	ArmedAt     *time.Time `json:"armed_at,omitempty"`
 
 	// Relationships
 	User          User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Notifications []Notification `json:"notifications,omitempty" gorm:"foreignKey:AlertID"`
 }
 
+// Bounds of the cooldown an alert can be given
+const (
+	DefaultAlertCooldown = 5 * time.Minute
+	MinAlertCooldown     = time.Minute
+	MaxAlertCooldown     = 7 * 24 * time.Hour
+)
+
+// Cooldown is how long the alert stays quiet after it triggers
+func (a *Alert) Cooldown() time.Duration {
+	if a.CooldownSeconds <= 0 {
+		return DefaultAlertCooldown
+	}
+	return time.Duration(a.CooldownSeconds) * time.Second
+}
+
+// InCooldown reports whether the alert triggered too recently to trigger again at now
+func (a *Alert) InCooldown(now time.Time) bool {
+	return a.TriggeredAt != nil && now.Before(a.TriggeredAt.Add(a.Cooldown()))
+}
+
+// Armed reports whether the alert is evaluated: alerts without a dependency always
+// are, chained ones once the alert they depend on triggered
+func (a *Alert) Armed() bool {
+	return a.DependsOnID == nil || a.ArmedAt != nil
+}
+
+// Bounds of the re-notification interval of an acknowledgement-required alert
+const (
+	DefaultAckInterval = 15 * time.Minute
+	MinAckInterval     = time.Minute
+	MaxAckInterval     = 24 * time.Hour
+)
+
+// AckInterval is how long an unacknowledged trigger waits before re-notifying
+func (a *Alert) AckInterval() time.Duration {
+	if a.AckIntervalMinutes <= 0 {
+		return DefaultAckInterval
+	}
+	return time.Duration(a.AckIntervalMinutes) * time.Minute
+}
+
+// Bounds of the market data age an alert can be given
+const (
+	MinAlertMaxDataAge = 10 * time.Second
+	MaxAlertMaxDataAge = 24 * time.Hour
+)
+
+// MaxDataAge is how old the data behind an evaluation may be, fallback when the
+// alert doesn't set its own; 0 means any age
+func (a *Alert) MaxDataAge(fallback time.Duration) time.Duration {
+	if a.MaxDataAgeSeconds <= 0 {
+		return fallback
+	}
+	return time.Duration(a.MaxDataAgeSeconds) * time.Second
+}
+
+// MaxAlertWindowDays bounds the window of a percentage alert
+const MaxAlertWindowDays = 365
+
+// ChangeWindow is how far back a percentage alert compares the price with
+func (a *Alert) ChangeWindow() time.Duration {
+	if a.WindowDays <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(a.WindowDays) * 24 * time.Hour
+}
+
+// AwaitingAck reports whether the alert triggered and nobody acknowledged it yet
+func (a *Alert) AwaitingAck() bool {
+	return a.AwaitingAckSince != nil
+}
+
+// AwaitAck puts a triggered acknowledgement-required alert on hold until the user
+// acknowledges it; triggers while it already waits keep the escalation going
+func (a *Alert) AwaitAck(at time.Time) {
+	if !a.AckRequired || a.AwaitingAck() {
+		return
+	}
+	a.AwaitingAckSince = &at
+	a.EscalationLevel = 0
+	a.LastEscalatedAt = nil
+	a.AcknowledgedAt = nil
+}
+
+// Acknowledge stops the escalation of the current trigger
+func (a *Alert) Acknowledge(at time.Time) {
+	a.AwaitingAckSince = nil
+	a.AcknowledgedAt = &at
+}
+
+// EscalationDue reports whether the unacknowledged alert should re-notify at now
+func (a *Alert) EscalationDue(now time.Time) bool {
+	if !a.AwaitingAck() {
+		return false
+	}
+	last := *a.AwaitingAckSince
+	if a.LastEscalatedAt != nil {
+		last = *a.LastEscalatedAt
+	}
+	return !now.Before(last.Add(a.AckInterval()))
+}
+
+// AlertCondition is a node of a composite alert's condition tree: a leaf compared like
+// a single-condition alert of its type, or a nested "composite" group combining its
+// own conditions with the "and" or "or" in ConditionType
+type AlertCondition struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	AlertID       uuid.UUID  `json:"-" gorm:"type:uuid;not null;index"`
+	ParentID      *uuid.UUID `json:"-" gorm:"type:uuid"`
+	Position      int        `json:"-" gorm:"not null"`
+	AlertType     string     `json:"alert_type" gorm:"not null"`
+	ConditionType string     `json:"condition_type" gorm:"not null"`
+	TargetValue   float64    `json:"target_value" gorm:"type:decimal(20,8);not null"`
+	CreatedAt     time.Time  `json:"-" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Conditions []AlertCondition `json:"conditions,omitempty" gorm:"-"`
+}
+
 // Notification represents a user notification
 type Notification struct {
 	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
@@ -94,26 +263,74 @@ type Notification struct {
 	Title            string     `json:"title" gorm:"not null"`
 	Message          string     `json:"message" gorm:"not null"`
 	NotificationType string     `json:"notification_type" gorm:"not null"` // 'alert_triggered', 'system', etc.
-	ReadAt           *time.Time `json:"read_at,omitempty"`
-	CreatedAt        time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	// Context is the evaluation snapshot (prices, indicator values, ...) that caused an alert to trigger
+	Context   map[string]interface{} `json:"context,omitempty" gorm:"type:jsonb;serializer:json"`
+	ReadAt    *time.Time             `json:"read_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 
 	// Relationships
 	User  User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Alert *Alert `json:"alert,omitempty" gorm:"foreignKey:AlertID"`
 }
 
+// Outcomes of a notification delivery attempt
+const (
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+	DeliveryStatusSkipped = "skipped" // The user turned the channel off
+)
+
+// NotificationDelivery is the result of one attempt to deliver a queued notification
+// through a channel. Attempt counts from 1, so attempts above 1 are retries; latency
+// runs from when the notification was queued to when the attempt finished.
+type NotificationDelivery struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	NotificationID uuid.UUID `json:"notification_id" gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Channel        string    `json:"channel" gorm:"not null;index:idx_notification_deliveries_channel_time"`
+	Status         string    `json:"status" gorm:"not null"`
+	Attempt        int       `json:"attempt" gorm:"not null;default:1"`
+	LatencyMs      int64     `json:"latency_ms" gorm:"not null;default:0"`
+	Error          string    `json:"error,omitempty"`
+	DeliveredAt    time.Time `json:"delivered_at" gorm:"not null;index:idx_notification_deliveries_channel_time"`
+}
+
+// ChannelDeliveryWindow aggregates the deliveries of a channel over a time window.
+// AvgLatencyMs only averages the deliveries that were sent.
+type ChannelDeliveryWindow struct {
+	Channel      string  `json:"channel"`
+	Sent         int64   `json:"sent"`
+	Failed       int64   `json:"failed"`
+	Skipped      int64   `json:"skipped"`
+	Retries      int64   `json:"retries"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	SuccessRate  float64 `json:"success_rate" gorm:"-"`
+}
+
+// ComputeSuccessRate sets SuccessRate to the share of sent deliveries among the sent
+// and failed ones; skipped deliveries don't count, and a window without either is 1
+func (w *ChannelDeliveryWindow) ComputeSuccessRate() {
+	attempted := w.Sent + w.Failed
+	if attempted == 0 {
+		w.SuccessRate = 1
+		return
+	}
+	w.SuccessRate = float64(w.Sent) / float64(attempted)
+}
+
 // PriceHistory represents historical price data
 type PriceHistory struct {
-	ID         int64     `json:"id" gorm:"primary_key;autoIncrement"`
-	Symbol     string    `json:"symbol" gorm:"not null;index:idx_symbol_timeframe"`
-	Timeframe  string    `json:"timeframe" gorm:"not null;index:idx_symbol_timeframe"`
-	OpenPrice  float64   `json:"open_price" gorm:"type:decimal(20,8);not null"`
-	HighPrice  float64   `json:"high_price" gorm:"type:decimal(20,8);not null"`
-	LowPrice   float64   `json:"low_price" gorm:"type:decimal(20,8);not null"`
-	ClosePrice float64   `json:"close_price" gorm:"type:decimal(20,8);not null"`
-	Volume     float64   `json:"volume" gorm:"type:decimal(30,8);not null"`
-	Timestamp  time.Time `json:"timestamp" gorm:"not null;index;uniqueIndex:idx_unique_price"`
-	CreatedAt  time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID         int64      `json:"id" gorm:"primary_key;autoIncrement"`
+	Symbol     string     `json:"symbol" gorm:"not null;index:idx_symbol_timeframe"`
+	Timeframe  string     `json:"timeframe" gorm:"not null;index:idx_symbol_timeframe"`
+	OpenPrice  float64    `json:"open_price" gorm:"type:decimal(20,8);not null"`
+	HighPrice  float64    `json:"high_price" gorm:"type:decimal(20,8);not null"`
+	LowPrice   float64    `json:"low_price" gorm:"type:decimal(20,8);not null"`
+	ClosePrice float64    `json:"close_price" gorm:"type:decimal(20,8);not null"`
+	Volume     float64    `json:"volume" gorm:"type:decimal(30,8);not null"`
+	Timestamp  time.Time  `json:"timestamp" gorm:"not null;index;uniqueIndex:idx_unique_price"`
+	EventTime  *time.Time `json:"event_time,omitempty"` // When the exchange observed the price, for latency tracing
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
 // TableName overrides the table name used by PriceHistory to `price_history`
@@ -127,8 +344,9 @@ type TechnicalIndicator struct {
 	Symbol        string                 `json:"symbol" gorm:"not null;index:idx_symbol_timeframe_type"`
 	Timeframe     string                 `json:"timeframe" gorm:"not null;index:idx_symbol_timeframe_type"`
 	IndicatorType string                 `json:"indicator_type" gorm:"not null;index:idx_symbol_timeframe_type"` // 'rsi', 'ema', 'sma', 'supertrend', etc.
+	IndicatorKey  string                 `json:"indicator_key" gorm:"not null;index:idx_symbol_timeframe_key"`   // Type plus parameters, see IndicatorKey
 	Value         *float64               `json:"value,omitempty" gorm:"type:decimal(20,8)"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty" gorm:"type:jsonb"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty" gorm:"type:jsonb;serializer:json"`
 	Timestamp     time.Time              `json:"timestamp" gorm:"not null;index;uniqueIndex:idx_unique_indicator"`
 	CreatedAt     time.Time              `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
@@ -145,6 +363,165 @@ type Session struct {
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// SystemBanner is an admin-published incident or maintenance message shown to every user until it expires
+type SystemBanner struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Title     string     `json:"title" gorm:"not null"`
+	Message   string     `json:"message" gorm:"not null"`
+	Severity  string     `json:"severity" gorm:"not null;default:'info'"` // 'info', 'warning', 'critical'
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// IsActive reports whether the banner should still be displayed at now
+func (b *SystemBanner) IsActive(now time.Time) bool {
+	return b.RevokedAt == nil && now.Before(b.ExpiresAt)
+}
+
+// ShareLink is a public, read-only link to a frozen pullback analysis or alert trigger
+// snapshot. Only the hash of its token is stored; the token itself is shown once.
+type ShareLink struct {
+	ID           uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID       uuid.UUID              `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash    string                 `json:"-" gorm:"uniqueIndex;not null"`
+	ResourceType string                 `json:"resource_type" gorm:"not null"` // 'pullback_analysis', 'alert_trigger'
+	Symbol       string                 `json:"symbol" gorm:"not null"`
+	Snapshot     map[string]interface{} `json:"snapshot" gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt    time.Time              `json:"expires_at" gorm:"not null;index"`
+	RevokedAt    *time.Time             `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// IsActive reports whether the link can still be opened at now
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// APIKey lets a user's own tools read the public market data API under a quota of
+// their own. Only the hash of the key is stored; the key itself is shown once and
+// its prefix tells the user's keys apart.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// IsActive reports whether the key still grants access
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
+
+// AbuseFlag records a user whose alert configuration looks designed to spam
+// notifications. While a flag is active the user's alert triggers are throttled.
+type AbuseFlag struct {
+	ID             uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID         uuid.UUID              `json:"user_id" gorm:"type:uuid;not null;index"`
+	Reason         string                 `json:"reason" gorm:"not null"` // 'duplicate_conditions', 'alert_volume'
+	AlertCount     int                    `json:"alert_count" gorm:"not null"`
+	Details        map[string]interface{} `json:"details" gorm:"type:jsonb;serializer:json"`
+	ThrottledUntil time.Time              `json:"throttled_until" gorm:"not null;index"`
+	ResolvedAt     *time.Time             `json:"resolved_at,omitempty"`
+	ResolvedBy     string                 `json:"resolved_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// IsActive reports whether the flag still throttles the user at now
+func (f *AbuseFlag) IsActive(now time.Time) bool {
+	return f.ResolvedAt == nil && now.Before(f.ThrottledUntil)
+}
+
+// Symbol restriction levels
+const (
+	// SymbolBlacklisted symbols, e.g. delisted or wash traded, take no new alerts, are
+	// no longer collected and their existing alerts are paused
+	SymbolBlacklisted = "blacklist"
+	// SymbolGreylisted symbols are still collected and keep their alerts, but take no new ones
+	SymbolGreylisted = "greylist"
+)
+
+// SymbolRestriction is an admin's decision to keep users off a symbol. Lifting the
+// restriction deletes it; alerts paused by a blacklist stay paused until re-enabled.
+type SymbolRestriction struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Symbol    string    `json:"symbol" gorm:"uniqueIndex;not null"`
+	Level     string    `json:"level" gorm:"not null"` // 'blacklist', 'greylist'
+	Reason    string    `json:"reason" gorm:"not null"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// AlertSet is a named group of a user's alerts, such as the alerts of one trading
+// plan, enabled, disabled and deleted together
+type AlertSet struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_alert_sets_user_name"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_alert_sets_user_name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BasketComponent is one symbol of a basket: its weight is a percentage of the
+// basket's value and its reference price the symbol's price when the basket was created
+type BasketComponent struct {
+	Symbol         string  `json:"symbol"`
+	Weight         float64 `json:"weight"`
+	ReferencePrice float64 `json:"reference_price"`
+}
+
+// Basket is a user-defined weighted index of symbols, such as 50% BTC, 30% ETH and
+// 20% SOL. Its value starts at BaseValue and follows the weighted returns of its
+// components; it is stored in price_history under Symbol like a real symbol's price.
+type Basket struct {
+	ID         uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID         `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name       string            `json:"name" gorm:"not null"`
+	Symbol     string            `json:"symbol" gorm:"not null;uniqueIndex"`
+	BaseValue  float64           `json:"base_value" gorm:"type:decimal(20,8);not null"`
+	Components []BasketComponent `json:"components" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// DeviceToken is the Firebase Cloud Messaging registration token of one of a user's
+// devices. A token belongs to one user at a time; registering it again moves it.
+type DeviceToken struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Token     string    `json:"-" gorm:"uniqueIndex;not null"`
+	Platform  string    `json:"platform" gorm:"not null"` // 'android', 'ios', 'web'
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TelegramLink connects a user to the Telegram chat their alerts are sent to. The
+// user gets a one-time code and sends it to the bot, which fills in the chat; the
+// chat ID is stored encrypted when encryption at rest is configured.
+type TelegramLink struct {
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;primary_key"`
+	ChatID        string     `json:"-"`
+	Username      string     `json:"username,omitempty"`
+	CodeHash      string     `json:"-" gorm:"index"`
+	CodeExpiresAt *time.Time `json:"-"`
+	LinkedAt      *time.Time `json:"linked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// IsLinked reports whether a chat has confirmed the link
+func (l *TelegramLink) IsLinked() bool {
+	return l.ChatID != ""
+}
+
 // RawCryptoData represents the real-time crypto data structure expected by the frontend
 type RawCryptoData struct {
 	DashboardData struct {
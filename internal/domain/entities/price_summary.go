@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// PriceSummary is a symbol's daily OHLCV compacted from its intraday candles, with
+// the volatility of their returns over the day. The current day's summary is
+// rewritten as its candles close.
+type PriceSummary struct {
+	ID         int64     `json:"id" gorm:"primary_key;autoIncrement"`
+	Symbol     string    `json:"symbol" gorm:"not null;uniqueIndex:idx_price_daily_summary"`
+	Day        time.Time `json:"day" gorm:"not null;uniqueIndex:idx_price_daily_summary;index"` // Midnight UTC
+	OpenPrice  float64   `json:"open_price" gorm:"type:decimal(20,8);not null"`
+	HighPrice  float64   `json:"high_price" gorm:"type:decimal(20,8);not null"`
+	LowPrice   float64   `json:"low_price" gorm:"type:decimal(20,8);not null"`
+	ClosePrice float64   `json:"close_price" gorm:"type:decimal(20,8);not null"`
+	Volume     float64   `json:"volume" gorm:"type:decimal(30,8);not null"`
+	// Volatility is the standard deviation of the log returns of the day's candles
+	// scaled to a day, in percent
+	Volatility float64 `json:"volatility" gorm:"type:decimal(12,6);not null"`
+	// Candles is how many SourceTimeframe candles the summary was compacted from
+	Candles         int       `json:"candles" gorm:"not null"`
+	SourceTimeframe string    `json:"source_timeframe" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name used by PriceSummary to `price_daily_summaries`
+func (PriceSummary) TableName() string {
+	return "price_daily_summaries"
+}
+
+// ChangePercent is how much the price moved over the day, in percent
+func (s *PriceSummary) ChangePercent() float64 {
+	if s.OpenPrice == 0 {
+		return 0
+	}
+	return (s.ClosePrice - s.OpenPrice) / s.OpenPrice * 100
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "strings"
+
+// SandboxSymbolPrefix starts the symbols priced by the market simulator, which keeps
+// demo data apart from the real market data of the same pairs
+const SandboxSymbolPrefix = "DEMO-"
+
+// IsSandboxSymbol reports whether the symbol is simulated rather than a market's
+func IsSandboxSymbol(symbol string) bool {
+	return strings.HasPrefix(symbol, SandboxSymbolPrefix)
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Change24hAlertType alerts on the exchange-reported 24h percentage change of a
+// symbol rather than on a change computed from the stored candles
+const Change24hAlertType = "change_24h"
+
+// Ticker24h is the exchange's rolling 24 hour statistics of a cryptocurrency, as of
+// RefreshedAt; nil RefreshedAt means they were never collected
+type Ticker24h struct {
+	LastPrice          float64    `json:"last_price" gorm:"column:last_price;type:decimal(20,8);not null;default:0"`
+	PriceChange        float64    `json:"price_change_24h" gorm:"column:price_change_24h;type:decimal(20,8);not null;default:0"`
+	PriceChangePercent float64    `json:"price_change_percent_24h" gorm:"column:price_change_percent_24h;type:decimal(12,4);not null;default:0"`
+	Volume             float64    `json:"volume_24h" gorm:"column:volume_24h;type:decimal(30,8);not null;default:0"`
+	QuoteVolume        float64    `json:"quote_volume_24h" gorm:"column:quote_volume_24h;type:decimal(30,8);not null;default:0"`
+	RefreshedAt        *time.Time `json:"ticker_24h_at,omitempty" gorm:"column:ticker_24h_at"`
+}
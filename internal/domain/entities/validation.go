@@ -0,0 +1,481 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/locale"
+)
+
+// ErrValidation is the sentinel matched by every ValidationError via errors.Is
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError describes a single invalid field on an entity
+type ValidationError struct {
+	Entity  string `json:"entity"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s.%s: %s", e.Entity, e.Field, e.Message)
+}
+
+// Unwrap lets callers use errors.Is(err, ErrValidation)
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+func newValidationError(entity, field, format string, args ...interface{}) error {
+	return &ValidationError{Entity: entity, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// AlertConditions lists the condition types supported for each alert type
+var AlertConditions = map[string][]string{
+	"price":          {"above", "below", "crosses_up", "crosses_down"},
+	"percentage":     {"up", "down"},
+	"change_24h":     {"up", "down"},
+	"rsi":            {"above", "below"},
+	"ema_cross":      {"up", "down"},
+	"sma_cross":      {"up", "down"},
+	"macd_cross":     {"up", "down"},
+	"ichimoku_cloud": {"enters", "exits", "exits_above", "exits_below"},
+	"volume":         {"above", "below", "spike"},
+	"composite":      {"and", "or"},
+}
+
+// RegisterAlertType adds an alert type with its conditions to AlertConditions; it
+// must be called during initialization, before alerts are validated
+func RegisterAlertType(alertType string, conditions []string) error {
+	if alertType == "" || len(conditions) == 0 {
+		return fmt.Errorf("alert type and conditions are required")
+	}
+	if _, exists := AlertConditions[alertType]; exists {
+		return fmt.Errorf("alert type %q is already supported", alertType)
+	}
+	AlertConditions[alertType] = conditions
+	return nil
+}
+
+// Timeframes lists the supported candle timeframes
+var Timeframes = []string{"1m", "5m", "15m", "1h", "4h", "1d"}
+
+// NotificationChannels lists the channels an alert can notify through
+var NotificationChannels = []string{"app", "email", "push", "sms", "telegram"}
+
+// AlertPriorities lists the notification priorities an alert can be queued with
+var AlertPriorities = []string{"low", "normal", "high", "urgent"}
+
+// DefaultAlertPriority is used for alerts that don't choose a priority
+const DefaultAlertPriority = "high"
+
+// Themes lists the supported UI themes
+var Themes = []string{"dark", "light"}
+
+// RiskProfiles lists the supported user risk profiles
+var RiskProfiles = []string{"conservative", "moderate", "aggressive"}
+
+// SymbolRestrictionLevels lists the supported symbol restriction levels
+var SymbolRestrictionLevels = []string{SymbolBlacklisted, SymbolGreylisted}
+
+// BannerSeverities lists the supported system banner severities
+var BannerSeverities = []string{"info", "warning", "critical"}
+
+// ShareResourceTypes lists what a public share link can point to
+var ShareResourceTypes = []string{"pullback_analysis", "alert_trigger"}
+
+// ReportFrequencies lists how often a market summary report can be delivered
+var ReportFrequencies = []string{"none", "daily", "weekly"}
+
+const (
+	maxSymbolLength         = 20
+	maxFavoriteSymbols      = 50
+	maxFavoriteLabelLength  = 32
+	maxNotificationTitle    = 255
+	maxNotificationTypeSize = 50
+	maxDefaultViewLength    = 20
+	maxBannerMessageLength  = 1000
+	maxBannerDuration       = 7 * 24 * time.Hour
+	maxShareLinkDuration    = 30 * 24 * time.Hour
+	maxAPIKeyNameLength     = 100
+	maxAlertSetNameLength   = 100
+	maxAlertSetDescription  = 500
+	maxBasketNameLength     = 100
+	minBasketComponents     = 2
+	maxBasketComponents     = 20
+	maxAlertConditions      = 10
+	maxConditionDepth       = 3
+)
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSymbol(entity, field, symbol string) error {
+	if symbol == "" {
+		return newValidationError(entity, field, "is required")
+	}
+	if len(symbol) > maxSymbolLength {
+		return newValidationError(entity, field, "must be at most %d characters", maxSymbolLength)
+	}
+	return nil
+}
+
+// Validate checks the alert type/condition pair, target range, condition tree,
+// timeframe and channels
+func (a *Alert) Validate() error {
+	if a.UserID == uuid.Nil {
+		return newValidationError("alert", "user_id", "is required")
+	}
+	if err := validateSymbol("alert", "symbol", a.Symbol); err != nil {
+		return err
+	}
+
+	if err := validateAlertCondition("alert_type", "condition_type", "target_value", a.AlertType, a.ConditionType, a.TargetValue); err != nil {
+		return err
+	}
+	if a.IsComposite() {
+		leaves := 0
+		if err := validateConditionTree(a.Conditions, 1, &leaves); err != nil {
+			return err
+		}
+	} else if len(a.Conditions) > 0 {
+		return newValidationError("alert", "conditions", "only composite alerts have conditions")
+	}
+
+	if !contains(Timeframes, a.Timeframe) {
+		return newValidationError("alert", "timeframe", "unsupported timeframe %q", a.Timeframe)
+	}
+
+	for _, channel := range a.NotifyVia {
+		if !contains(NotificationChannels, channel) {
+			return newValidationError("alert", "notify_via", "unsupported channel %q", channel)
+		}
+	}
+
+	if a.Priority != "" && !contains(AlertPriorities, a.Priority) {
+		return newValidationError("alert", "priority", "unsupported priority %q", a.Priority)
+	}
+
+	if a.CooldownSeconds != 0 {
+		cooldown := time.Duration(a.CooldownSeconds) * time.Second
+		if cooldown < MinAlertCooldown || cooldown > MaxAlertCooldown {
+			return newValidationError("alert", "cooldown_seconds", "must be between %d and %d",
+				int(MinAlertCooldown.Seconds()), int(MaxAlertCooldown.Seconds()))
+		}
+	}
+
+	if a.AckIntervalMinutes != 0 {
+		interval := time.Duration(a.AckIntervalMinutes) * time.Minute
+		if interval < MinAckInterval || interval > MaxAckInterval {
+			return newValidationError("alert", "ack_interval_minutes", "must be between %d and %d",
+				int(MinAckInterval.Minutes()), int(MaxAckInterval.Minutes()))
+		}
+	}
+
+	if a.DependsOnID != nil && *a.DependsOnID == a.ID {
+		return newValidationError("alert", "depends_on_id", "an alert can't depend on itself")
+	}
+
+	if a.MaxDataAgeSeconds != 0 {
+		maxAge := time.Duration(a.MaxDataAgeSeconds) * time.Second
+		if maxAge < MinAlertMaxDataAge || maxAge > MaxAlertMaxDataAge {
+			return newValidationError("alert", "max_data_age_seconds", "must be between %d and %d",
+				int(MinAlertMaxDataAge.Seconds()), int(MaxAlertMaxDataAge.Seconds()))
+		}
+	}
+
+	if a.WindowDays != 0 {
+		if a.AlertType != "percentage" {
+			return newValidationError("alert", "window_days", "only percentage alerts have a window")
+		}
+		if a.WindowDays < 1 || a.WindowDays > MaxAlertWindowDays {
+			return newValidationError("alert", "window_days", "must be between 1 and %d", MaxAlertWindowDays)
+		}
+	}
+
+	return nil
+}
+
+// validateAlertCondition checks a type/condition pair and its target, reporting
+// problems against the given fields
+func validateAlertCondition(typeField, conditionField, targetField, alertType, conditionType string, target float64) error {
+	conditions, ok := AlertConditions[alertType]
+	if !ok {
+		return newValidationError("alert", typeField, "unsupported alert type %q", alertType)
+	}
+	if !contains(conditions, conditionType) {
+		return newValidationError("alert", conditionField, "condition %q is not supported for %s alerts", conditionType, alertType)
+	}
+
+	switch alertType {
+	case "price", "percentage", Change24hAlertType:
+		if target <= 0 {
+			return newValidationError("alert", targetField, "must be greater than zero")
+		}
+	case "rsi":
+		if target < 0 || target > 100 {
+			return newValidationError("alert", targetField, "must be between 0 and 100")
+		}
+	case "volume":
+		// A spike target is a multiple of the average volume, anything up to 1× isn't a spike
+		if conditionType == "spike" && target <= 1 {
+			return newValidationError("alert", targetField, "must be greater than 1 for spike alerts")
+		}
+		if target <= 0 {
+			return newValidationError("alert", targetField, "must be greater than zero")
+		}
+	case CompositeAlertType:
+		// Groups have no target of their own
+	default:
+		if target < 0 {
+			return newValidationError("alert", targetField, "must not be negative")
+		}
+	}
+	return nil
+}
+
+// validateConditionTree checks every condition of a composite alert, its nesting
+// depth and how many leaf conditions it compares
+func validateConditionTree(conditions []AlertCondition, depth int, leaves *int) error {
+	if len(conditions) < 2 {
+		return newValidationError("alert", "conditions", "a condition group needs at least 2 conditions")
+	}
+	if depth > maxConditionDepth {
+		return newValidationError("alert", "conditions", "condition groups can be nested at most %d deep", maxConditionDepth)
+	}
+	for _, condition := range conditions {
+		err := validateAlertCondition("conditions.alert_type", "conditions.condition_type", "conditions.target_value",
+			condition.AlertType, condition.ConditionType, condition.TargetValue)
+		if err != nil {
+			return err
+		}
+		if condition.IsGroup() {
+			if err := validateConditionTree(condition.Conditions, depth+1, leaves); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(condition.Conditions) > 0 {
+			return newValidationError("alert", "conditions", "only composite conditions have nested conditions")
+		}
+		if *leaves++; *leaves > maxAlertConditions {
+			return newValidationError("alert", "conditions", "must contain at most %d conditions", maxAlertConditions)
+		}
+	}
+	return nil
+}
+
+// Validate checks the required notification fields and their lengths
+func (n *Notification) Validate() error {
+	if n.UserID == uuid.Nil {
+		return newValidationError("notification", "user_id", "is required")
+	}
+	if n.Title == "" {
+		return newValidationError("notification", "title", "is required")
+	}
+	if len(n.Title) > maxNotificationTitle {
+		return newValidationError("notification", "title", "must be at most %d characters", maxNotificationTitle)
+	}
+	if n.Message == "" {
+		return newValidationError("notification", "message", "is required")
+	}
+	if n.NotificationType == "" {
+		return newValidationError("notification", "notification_type", "is required")
+	}
+	if len(n.NotificationType) > maxNotificationTypeSize {
+		return newValidationError("notification", "notification_type", "must be at most %d characters", maxNotificationTypeSize)
+	}
+	return nil
+}
+
+// Validate checks theme, timeframe, risk profile, report schedule, locale and favorite symbols and labels
+func (s *UserSettings) Validate() error {
+	if s.Theme != "" && !contains(Themes, s.Theme) {
+		return newValidationError("user_settings", "theme", "unsupported theme %q", s.Theme)
+	}
+	if s.DefaultTimeframe != "" && !contains(Timeframes, s.DefaultTimeframe) {
+		return newValidationError("user_settings", "default_timeframe", "unsupported timeframe %q", s.DefaultTimeframe)
+	}
+	if len(s.DefaultView) > maxDefaultViewLength {
+		return newValidationError("user_settings", "default_view", "must be at most %d characters", maxDefaultViewLength)
+	}
+	if s.RiskProfile != "" && !contains(RiskProfiles, s.RiskProfile) {
+		return newValidationError("user_settings", "risk_profile", "unsupported risk profile %q", s.RiskProfile)
+	}
+	if s.ReportFrequency != "" && !contains(ReportFrequencies, s.ReportFrequency) {
+		return newValidationError("user_settings", "report_frequency", "unsupported report frequency %q", s.ReportFrequency)
+	}
+	if s.ReportHour < 0 || s.ReportHour > 23 {
+		return newValidationError("user_settings", "report_hour", "must be between 0 and 23")
+	}
+	if s.Locale != "" && !locale.IsSupported(s.Locale) {
+		return newValidationError("user_settings", "locale", "unsupported locale %q", s.Locale)
+	}
+	if len(s.FavoriteSymbols) > maxFavoriteSymbols {
+		return newValidationError("user_settings", "favorite_symbols", "must contain at most %d symbols", maxFavoriteSymbols)
+	}
+	for _, symbol := range s.FavoriteSymbols {
+		if err := validateSymbol("user_settings", "favorite_symbols", symbol); err != nil {
+			return err
+		}
+	}
+	for symbol, label := range s.FavoriteLabels {
+		if !contains(s.FavoriteSymbols, symbol) {
+			return newValidationError("user_settings", "favorite_labels", "%q is not a favorite symbol", symbol)
+		}
+		if len(label) > maxFavoriteLabelLength {
+			return newValidationError("user_settings", "favorite_labels", "must be at most %d characters", maxFavoriteLabelLength)
+		}
+	}
+	return nil
+}
+
+// Validate checks the banner text, severity and that it expires within a week of now
+func (b *SystemBanner) Validate(now time.Time) error {
+	if b.Title == "" {
+		return newValidationError("system_banner", "title", "is required")
+	}
+	if len(b.Title) > maxNotificationTitle {
+		return newValidationError("system_banner", "title", "must be at most %d characters", maxNotificationTitle)
+	}
+	if b.Message == "" {
+		return newValidationError("system_banner", "message", "is required")
+	}
+	if len(b.Message) > maxBannerMessageLength {
+		return newValidationError("system_banner", "message", "must be at most %d characters", maxBannerMessageLength)
+	}
+	if !contains(BannerSeverities, b.Severity) {
+		return newValidationError("system_banner", "severity", "unsupported severity %q", b.Severity)
+	}
+	if !b.ExpiresAt.After(now) {
+		return newValidationError("system_banner", "expires_at", "must be in the future")
+	}
+	if b.ExpiresAt.Sub(now) > maxBannerDuration {
+		return newValidationError("system_banner", "expires_at", "must be within %s", maxBannerDuration)
+	}
+	return nil
+}
+
+// Validate checks the symbol, level and that a reason is given
+func (r *SymbolRestriction) Validate() error {
+	if err := validateSymbol("symbol_restriction", "symbol", r.Symbol); err != nil {
+		return err
+	}
+	if !contains(SymbolRestrictionLevels, r.Level) {
+		return newValidationError("symbol_restriction", "level", "unsupported level %q", r.Level)
+	}
+	if r.Reason == "" {
+		return newValidationError("symbol_restriction", "reason", "is required")
+	}
+	if len(r.Reason) > maxBannerMessageLength {
+		return newValidationError("symbol_restriction", "reason", "must be at most %d characters", maxBannerMessageLength)
+	}
+	return nil
+}
+
+// Validate checks the resource type, snapshot and that the link expires within 30 days of now
+func (l *ShareLink) Validate(now time.Time) error {
+	if l.UserID == uuid.Nil {
+		return newValidationError("share_link", "user_id", "is required")
+	}
+	if !contains(ShareResourceTypes, l.ResourceType) {
+		return newValidationError("share_link", "resource_type", "unsupported resource type %q", l.ResourceType)
+	}
+	if err := validateSymbol("share_link", "symbol", l.Symbol); err != nil {
+		return err
+	}
+	if len(l.Snapshot) == 0 {
+		return newValidationError("share_link", "snapshot", "is required")
+	}
+	if !l.ExpiresAt.After(now) {
+		return newValidationError("share_link", "expires_at", "must be in the future")
+	}
+	if l.ExpiresAt.Sub(now) > maxShareLinkDuration {
+		return newValidationError("share_link", "expires_at", "must be within %s", maxShareLinkDuration)
+	}
+	return nil
+}
+
+// Validate checks the key's owner and name
+func (k *APIKey) Validate() error {
+	if k.UserID == uuid.Nil {
+		return newValidationError("api_key", "user_id", "is required")
+	}
+	if k.Name == "" {
+		return newValidationError("api_key", "name", "is required")
+	}
+	if len(k.Name) > maxAPIKeyNameLength {
+		return newValidationError("api_key", "name", "must be at most %d characters", maxAPIKeyNameLength)
+	}
+	return nil
+}
+
+// Validate checks the owner, name and description length
+func (s *AlertSet) Validate() error {
+	if s.UserID == uuid.Nil {
+		return newValidationError("alert_set", "user_id", "is required")
+	}
+	if s.Name == "" {
+		return newValidationError("alert_set", "name", "is required")
+	}
+	if len(s.Name) > maxAlertSetNameLength {
+		return newValidationError("alert_set", "name", "must be at most %d characters", maxAlertSetNameLength)
+	}
+	if len(s.Description) > maxAlertSetDescription {
+		return newValidationError("alert_set", "description", "must be at most %d characters", maxAlertSetDescription)
+	}
+	return nil
+}
+
+// basketWeightTolerance is how far from 100 the weights of a basket may add up to
+const basketWeightTolerance = 0.01
+
+// Validate checks the basket's name and components: between 2 and 20 distinct market
+// symbols with positive weights adding up to 100
+func (b *Basket) Validate() error {
+	if b.UserID == uuid.Nil {
+		return newValidationError("basket", "user_id", "is required")
+	}
+	if b.Name == "" {
+		return newValidationError("basket", "name", "is required")
+	}
+	if len(b.Name) > maxBasketNameLength {
+		return newValidationError("basket", "name", "must be at most %d characters", maxBasketNameLength)
+	}
+	if len(b.Components) < minBasketComponents || len(b.Components) > maxBasketComponents {
+		return newValidationError("basket", "components", "must have between %d and %d symbols", minBasketComponents, maxBasketComponents)
+	}
+
+	seen := make(map[string]bool, len(b.Components))
+	total := 0.0
+	for _, component := range b.Components {
+		if err := validateSymbol("basket", "components", component.Symbol); err != nil {
+			return err
+		}
+		if IsBasketSymbol(component.Symbol) {
+			return newValidationError("basket", "components", "%s is a basket", component.Symbol)
+		}
+		if seen[component.Symbol] {
+			return newValidationError("basket", "components", "%s is listed twice", component.Symbol)
+		}
+		seen[component.Symbol] = true
+		if component.Weight <= 0 {
+			return newValidationError("basket", "components", "weight of %s must be positive", component.Symbol)
+		}
+		total += component.Weight
+	}
+	if math.Abs(total-100) > basketWeightTolerance {
+		return newValidationError("basket", "components", "weights must add up to 100, not %g", total)
+	}
+	return nil
+}
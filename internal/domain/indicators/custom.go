@@ -0,0 +1,108 @@
+package indicators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// CustomIndicator is an indicator compiled into the binary on top of the built-in
+// ones. Registered indicators are calculated with the built-ins on every refresh,
+// stored under their indicator key and can be alerted on with "above" and "below"
+// conditions, using the lowercased name as the alert type.
+type CustomIndicator interface {
+	// Name is the indicator type values are stored with, e.g. "VWAP"
+	Name() string
+	// Period is the number of candles Calculate needs
+	Period() int
+	// Params are extra parameters that become part of the indicator key
+	Params() []float64
+	// Calculate returns the value at the last candle of data, which is ordered
+	// oldest first, and optional metadata stored alongside it
+	Calculate(data []PriceData) (float64, map[string]interface{}, error)
+}
+
+// CustomIndicatorConditions are the alert conditions every custom indicator supports
+var CustomIndicatorConditions = []string{"above", "below"}
+
+// builtinIndicatorNames can't be taken by a custom indicator
+var builtinIndicatorNames = []string{"rsi", "ema", "sma", "supertrend", "bb_upper", "bb_middle", "bb_lower", "macd", "macd_signal", "adx", "ichimoku"}
+
+var (
+	customIndicatorsMu sync.RWMutex
+	customIndicators   = map[string]CustomIndicator{}
+)
+
+// RegisterCustomIndicator adds an indicator to the registry and its alert type to
+// the supported alert conditions; call it from an init function
+func RegisterCustomIndicator(indicator CustomIndicator) error {
+	if indicator == nil || indicator.Name() == "" {
+		return fmt.Errorf("custom indicator must have a name")
+	}
+	if indicator.Period() <= 0 {
+		return fmt.Errorf("custom indicator %s must have a positive period", indicator.Name())
+	}
+
+	alertType := CustomIndicatorAlertType(indicator)
+	for _, name := range builtinIndicatorNames {
+		if alertType == name {
+			return fmt.Errorf("custom indicator %s conflicts with a built-in indicator", indicator.Name())
+		}
+	}
+
+	customIndicatorsMu.Lock()
+	defer customIndicatorsMu.Unlock()
+
+	if _, exists := customIndicators[alertType]; exists {
+		return fmt.Errorf("custom indicator %s is already registered", indicator.Name())
+	}
+	if err := entities.RegisterAlertType(alertType, CustomIndicatorConditions); err != nil {
+		return fmt.Errorf("failed to register custom indicator %s: %w", indicator.Name(), err)
+	}
+	customIndicators[alertType] = indicator
+	return nil
+}
+
+// MustRegisterCustomIndicator is RegisterCustomIndicator for init functions, panicking on error
+func MustRegisterCustomIndicator(indicator CustomIndicator) {
+	if err := RegisterCustomIndicator(indicator); err != nil {
+		panic(err)
+	}
+}
+
+// CustomIndicators returns the registered indicators ordered by name
+func CustomIndicators() []CustomIndicator {
+	customIndicatorsMu.RLock()
+	defer customIndicatorsMu.RUnlock()
+
+	registered := make([]CustomIndicator, 0, len(customIndicators))
+	for _, indicator := range customIndicators {
+		registered = append(registered, indicator)
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name() < registered[j].Name()
+	})
+	return registered
+}
+
+// LookupCustomIndicator returns the registered indicator alerts of alertType evaluate
+func LookupCustomIndicator(alertType string) (CustomIndicator, bool) {
+	customIndicatorsMu.RLock()
+	defer customIndicatorsMu.RUnlock()
+
+	indicator, ok := customIndicators[alertType]
+	return indicator, ok
+}
+
+// CustomIndicatorAlertType is the alert type of a custom indicator, its lowercased name
+func CustomIndicatorAlertType(indicator CustomIndicator) string {
+	return strings.ToLower(indicator.Name())
+}
+
+// CustomIndicatorKey is the key values of a custom indicator are stored under
+func CustomIndicatorKey(indicator CustomIndicator) string {
+	return entities.IndicatorKey(indicator.Name(), indicator.Period(), indicator.Params()...)
+}
@@ -0,0 +1,87 @@
+package indicators
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// timeframeUnits are the milliseconds of each unit a custom timeframe can count
+var timeframeUnits = map[byte]int64{
+	'm': 60 * 1000,
+	'h': 60 * 60 * 1000,
+	'd': 24 * 60 * 60 * 1000,
+	'w': 7 * 24 * 60 * 60 * 1000,
+}
+
+// maxTimeframeCount bounds the units of a custom timeframe
+const maxTimeframeCount = 1000
+
+// ParseTimeframe returns the milliseconds of any count of minutes, hours, days or
+// weeks, such as 2h or 3d, beyond the timeframes Binance serves; it returns 0 for
+// anything else
+func ParseTimeframe(timeframe string) int64 {
+	if len(timeframe) < 2 {
+		return 0
+	}
+	unit, ok := timeframeUnits[timeframe[len(timeframe)-1]]
+	if !ok {
+		return 0
+	}
+	count, err := strconv.Atoi(timeframe[:len(timeframe)-1])
+	if err != nil || count <= 0 || count > maxTimeframeCount || timeframe[0] == '0' {
+		return 0
+	}
+	return int64(count) * unit
+}
+
+// Resample aggregates candles, oldest first, into candles of timeframe: each takes
+// the open of its first candle, the close of its last, the extremes of their highs
+// and lows and the sum of their volumes. Buckets are aligned to the Unix epoch like
+// Binance's klines; buckets without candles are left out, and an unknown timeframe
+// gives no candles.
+func Resample(candles []entities.PriceHistory, timeframe string) []entities.PriceHistory {
+	return resample(candles, timeframe, GetTimeframeMilliseconds(timeframe))
+}
+
+// ResampleCustom is Resample for any timeframe ParseTimeframe accepts
+func ResampleCustom(candles []entities.PriceHistory, timeframe string) []entities.PriceHistory {
+	return resample(candles, timeframe, ParseTimeframe(timeframe))
+}
+
+func resample(candles []entities.PriceHistory, timeframe string, step int64) []entities.PriceHistory {
+	if step == 0 {
+		return nil
+	}
+
+	var resampled []entities.PriceHistory
+	for _, candle := range candles {
+		bucket := candle.Timestamp.UnixMilli() / step * step
+		last := len(resampled) - 1
+		if last < 0 || resampled[last].Timestamp.UnixMilli() != bucket {
+			resampled = append(resampled, entities.PriceHistory{
+				Symbol:     candle.Symbol,
+				Timeframe:  timeframe,
+				OpenPrice:  candle.OpenPrice,
+				HighPrice:  candle.HighPrice,
+				LowPrice:   candle.LowPrice,
+				ClosePrice: candle.ClosePrice,
+				Volume:     candle.Volume,
+				Timestamp:  time.UnixMilli(bucket).UTC(),
+			})
+			continue
+		}
+
+		current := &resampled[last]
+		if candle.HighPrice > current.HighPrice {
+			current.HighPrice = candle.HighPrice
+		}
+		if candle.LowPrice < current.LowPrice {
+			current.LowPrice = candle.LowPrice
+		}
+		current.ClosePrice = candle.ClosePrice
+		current.Volume += candle.Volume
+	}
+	return resampled
+}
@@ -0,0 +1,118 @@
+package indicators
+
+import "fmt"
+
+// The series functions compute an indicator for every candle in one pass, reusing
+// dst as the output buffer when it has enough capacity so callers refreshing a
+// chart on each tick don't allocate. Values before the first complete window are
+// not emitted: the result is aligned with the end of prices.
+
+// SMASeries returns the simple moving average ending at each price from index
+// period-1 on, keeping a rolling sum instead of re-adding every window
+func SMASeries(dst, prices []float64, period int) ([]float64, error) {
+	if period <= 0 || len(prices) < period {
+		return nil, fmt.Errorf("insufficient data: need at least %d prices, got %d", period, len(prices))
+	}
+
+	dst = resize(dst, len(prices)-period+1)
+	p := float64(period)
+	var sum float64
+	for i := 0; i < period; i++ {
+		sum += prices[i]
+	}
+	dst[0] = sum / p
+	for i := period; i < len(prices); i++ {
+		sum += prices[i] - prices[i-period]
+		dst[i-period+1] = sum / p
+	}
+	return dst, nil
+}
+
+// EMASeries returns the exponential moving average at each price from index
+// period-1 on, seeded with the SMA of the first period prices like CalculateEMA
+func EMASeries(dst, prices []float64, period int) ([]float64, error) {
+	if period <= 0 || len(prices) < period {
+		return nil, fmt.Errorf("insufficient data: need at least %d prices, got %d", period, len(prices))
+	}
+
+	dst = resize(dst, len(prices)-period+1)
+	multiplier := 2.0 / (float64(period) + 1.0)
+	var ema float64
+	for i := 0; i < period; i++ {
+		ema += prices[i]
+	}
+	ema /= float64(period)
+	dst[0] = ema
+	for i := period; i < len(prices); i++ {
+		ema = (prices[i] * multiplier) + (ema * (1 - multiplier))
+		dst[i-period+1] = ema
+	}
+	return dst, nil
+}
+
+// RSISeries returns the RSI at each price from index period on, with the same
+// Wilder smoothing as CalculateRSI
+func RSISeries(dst, prices []float64, period int) ([]float64, error) {
+	if period <= 0 || len(prices) < period+1 {
+		return nil, fmt.Errorf("insufficient data: need at least %d prices, got %d", period+1, len(prices))
+	}
+
+	dst = resize(dst, len(prices)-period)
+	p := float64(period)
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		gain, loss := splitChange(prices[i] - prices[i-1])
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= p
+	avgLoss /= p
+	dst[0] = rsiValue(avgGain, avgLoss)
+
+	for i := period + 1; i < len(prices); i++ {
+		gain, loss := splitChange(prices[i] - prices[i-1])
+		avgGain = ((avgGain * (p - 1)) + gain) / p
+		avgLoss = ((avgLoss * (p - 1)) + loss) / p
+		dst[i-period] = rsiValue(avgGain, avgLoss)
+	}
+	return dst, nil
+}
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		return 100
+	}
+	return 100 - (100 / (1 + avgGain/avgLoss))
+}
+
+// MACDSeries returns the MACD line, the fast EMA minus the slow EMA, at each price
+// from index slowPeriod-1 on, where both averages are available
+func MACDSeries(dst, prices []float64, fastPeriod, slowPeriod int) ([]float64, error) {
+	if fastPeriod <= 0 || fastPeriod >= slowPeriod {
+		return nil, fmt.Errorf("fast period must be positive and shorter than the slow period")
+	}
+
+	fast, err := EMASeries(nil, prices, fastPeriod)
+	if err != nil {
+		return nil, err
+	}
+	dst, err = EMASeries(dst, prices, slowPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	// fast starts slowPeriod-fastPeriod candles before dst
+	offset := slowPeriod - fastPeriod
+	for i := range dst {
+		dst[i] = fast[i+offset] - dst[i]
+	}
+	return dst, nil
+}
+
+// resize returns dst with length n, reallocating only when it is too small
+func resize(dst []float64, n int) []float64 {
+	if cap(dst) < n {
+		return make([]float64, n)
+	}
+	return dst[:n]
+}
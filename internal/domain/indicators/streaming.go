@@ -0,0 +1,134 @@
+package indicators
+
+// The streaming indicators keep the running state of an indicator so each closed
+// candle updates it in constant time, instead of recomputing a whole window. Fed
+// the same candles from the first one on, they produce the values of EMASeries,
+// RSISeries and CalculateATR. Update reports false until the first window is
+// complete.
+
+// StreamingEMA is an exponential moving average updated one price at a time
+type StreamingEMA struct {
+	period     int
+	multiplier float64
+	count      int
+	value      float64
+}
+
+// NewStreamingEMA creates an EMA seeded, like CalculateEMA, with the SMA of the
+// first period prices
+func NewStreamingEMA(period int) *StreamingEMA {
+	return &StreamingEMA{period: period, multiplier: 2.0 / (float64(period) + 1.0)}
+}
+
+// Update adds the next close and returns the EMA once period prices were seen
+func (e *StreamingEMA) Update(price float64) (float64, bool) {
+	e.count++
+	switch {
+	case e.count < e.period:
+		e.value += price
+		return 0, false
+	case e.count == e.period:
+		e.value = (e.value + price) / float64(e.period)
+	default:
+		e.value = (price * e.multiplier) + (e.value * (1 - e.multiplier))
+	}
+	return e.value, true
+}
+
+// Value returns the current EMA, if the first window is complete
+func (e *StreamingEMA) Value() (float64, bool) {
+	return e.value, e.count >= e.period
+}
+
+// StreamingRSI is a relative strength index with Wilder's smoothing, updated one
+// price at a time
+type StreamingRSI struct {
+	period  int
+	changes int
+	last    float64
+	avgGain float64
+	avgLoss float64
+}
+
+// NewStreamingRSI creates an RSI averaging its first period price changes
+func NewStreamingRSI(period int) *StreamingRSI {
+	return &StreamingRSI{period: period, changes: -1}
+}
+
+// Update adds the next close and returns the RSI once period changes were seen
+func (r *StreamingRSI) Update(price float64) (float64, bool) {
+	r.changes++
+	if r.changes == 0 {
+		r.last = price
+		return 0, false
+	}
+
+	gain, loss := splitChange(price - r.last)
+	r.last = price
+	p := float64(r.period)
+	switch {
+	case r.changes < r.period:
+		r.avgGain += gain
+		r.avgLoss += loss
+		return 0, false
+	case r.changes == r.period:
+		r.avgGain = (r.avgGain + gain) / p
+		r.avgLoss = (r.avgLoss + loss) / p
+	default:
+		r.avgGain = ((r.avgGain * (p - 1)) + gain) / p
+		r.avgLoss = ((r.avgLoss * (p - 1)) + loss) / p
+	}
+	return rsiValue(r.avgGain, r.avgLoss), true
+}
+
+// Value returns the current RSI, if the first window is complete
+func (r *StreamingRSI) Value() (float64, bool) {
+	if r.changes < r.period {
+		return 0, false
+	}
+	return rsiValue(r.avgGain, r.avgLoss), true
+}
+
+// StreamingATR is an average true range with Wilder's smoothing, updated one
+// candle at a time
+type StreamingATR struct {
+	period int
+	ranges int
+	last   PriceData
+	seen   bool
+	value  float64
+}
+
+// NewStreamingATR creates an ATR averaging its first period true ranges
+func NewStreamingATR(period int) *StreamingATR {
+	return &StreamingATR{period: period}
+}
+
+// Update adds the next candle and returns the ATR once period true ranges were seen
+func (a *StreamingATR) Update(candle PriceData) (float64, bool) {
+	if !a.seen {
+		a.last = candle
+		a.seen = true
+		return 0, false
+	}
+
+	tr := trueRange(candle, a.last)
+	a.last = candle
+	a.ranges++
+	p := float64(a.period)
+	switch {
+	case a.ranges < a.period:
+		a.value += tr
+		return 0, false
+	case a.ranges == a.period:
+		a.value = (a.value + tr) / p
+	default:
+		a.value = ((a.value * (p - 1)) + tr) / p
+	}
+	return a.value, true
+}
+
+// Value returns the current ATR, if the first window is complete
+func (a *StreamingATR) Value() (float64, bool) {
+	return a.value, a.ranges >= a.period
+}
@@ -46,6 +46,33 @@ type SuperTrendResult struct {
 	Timestamp  int64
 }
 
+// ADXResult represents ADX calculation result with the directional indicators it
+// is derived from
+type ADXResult struct {
+	Value     float64 // ADX, the strength of the trend whatever its direction
+	PlusDI    float64
+	MinusDI   float64
+	Strength  string // "weak", "developing", "strong", "very_strong"
+	Direction string // "up", "down"
+	Timestamp int64
+}
+
+// IchimokuResult represents Ichimoku Kinko Hyo calculation result. SenkouA and
+// SenkouB are projected displacement candles ahead; the cloud under the latest candle
+// is the one projected displacement candles ago.
+type IchimokuResult struct {
+	Tenkan      float64 // Conversion line
+	Kijun       float64 // Base line
+	SenkouA     float64 // Leading span A
+	SenkouB     float64 // Leading span B
+	Chikou      float64 // Lagging span, the latest close plotted displacement candles back
+	CloudTop    float64
+	CloudBottom float64
+	CloudColor  string // "bullish", "bearish"
+	Position    string // "above", "inside", "below" the cloud
+	Timestamp   int64
+}
+
 // TrueRangeResult represents True Range calculation result
 type TrueRangeResult struct {
 	Value     float64
@@ -58,36 +85,23 @@ func CalculateRSI(prices []float64, period int) (*RSIResult, error) {
 		return nil, fmt.Errorf("insufficient data: need at least %d prices, got %d", period+1, len(prices))
 	}
 
-	// Calculate price changes
-	var gains, losses []float64
-	for i := 1; i < len(prices); i++ {
-		change := prices[i] - prices[i-1]
-		if change > 0 {
-			gains = append(gains, change)
-			losses = append(losses, 0)
-		} else {
-			gains = append(gains, 0)
-			losses = append(losses, -change)
-		}
-	}
-
-	if len(gains) < period {
-		return nil, fmt.Errorf("insufficient data for RSI calculation")
-	}
-
-	// Calculate initial average gain and loss
+	// Wilder's averages are accumulated in a single pass over the price changes,
+	// without materializing gain and loss slices
+	p := float64(period)
 	var avgGain, avgLoss float64
-	for i := 0; i < period; i++ {
-		avgGain += gains[i]
-		avgLoss += losses[i]
+	for i := 1; i <= period; i++ {
+		gain, loss := splitChange(prices[i] - prices[i-1])
+		avgGain += gain
+		avgLoss += loss
 	}
-	avgGain /= float64(period)
-	avgLoss /= float64(period)
+	avgGain /= p
+	avgLoss /= p
 
 	// Calculate smoothed averages
-	for i := period; i < len(gains); i++ {
-		avgGain = ((avgGain * float64(period-1)) + gains[i]) / float64(period)
-		avgLoss = ((avgLoss * float64(period-1)) + losses[i]) / float64(period)
+	for i := period + 1; i < len(prices); i++ {
+		gain, loss := splitChange(prices[i] - prices[i-1])
+		avgGain = ((avgGain * (p - 1)) + gain) / p
+		avgLoss = ((avgLoss * (p - 1)) + loss) / p
 	}
 
 	// Calculate RSI
@@ -98,23 +112,30 @@ func CalculateRSI(prices []float64, period int) (*RSIResult, error) {
 		}, nil
 	}
 
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
+	rsi := rsiValue(avgGain, avgLoss)
 
-	// Determine signal
-	var signal string
+	return &RSIResult{
+		Value:  rsi,
+		Signal: RSISignal(rsi),
+	}, nil
+}
+
+// RSISignal classifies an RSI value as overbought, oversold or neutral
+func RSISignal(rsi float64) string {
 	if rsi > 70 {
-		signal = "overbought"
+		return "overbought"
 	} else if rsi < 30 {
-		signal = "oversold"
-	} else {
-		signal = "neutral"
+		return "oversold"
 	}
+	return "neutral"
+}
 
-	return &RSIResult{
-		Value:  rsi,
-		Signal: signal,
-	}, nil
+// splitChange returns a price change as a (gain, loss) pair with both values >= 0
+func splitChange(change float64) (float64, float64) {
+	if change > 0 {
+		return change, 0
+	}
+	return 0, -change
 }
 
 // CalculateEMA calculates the Exponential Moving Average
@@ -167,39 +188,32 @@ func CalculateSMA(prices []float64, period int) (*SMAResult, error) {
 
 // CalculateTrueRange calculates the True Range
 func CalculateTrueRange(current, previous PriceData) *TrueRangeResult {
-	tr1 := current.High - current.Low
-	tr2 := math.Abs(current.High - previous.Close)
-	tr3 := math.Abs(current.Low - previous.Close)
-
-	tr := math.Max(tr1, math.Max(tr2, tr3))
-
 	return &TrueRangeResult{
-		Value: tr,
+		Value: trueRange(current, previous),
 	}
 }
 
+// trueRange is CalculateTrueRange without the result allocation
+func trueRange(current, previous PriceData) float64 {
+	return math.Max(current.High-current.Low, math.Max(math.Abs(current.High-previous.Close), math.Abs(current.Low-previous.Close)))
+}
+
 // CalculateATR calculates the Average True Range
 func CalculateATR(priceData []PriceData, period int) (float64, error) {
 	if len(priceData) < period+1 {
 		return 0, fmt.Errorf("insufficient data: need at least %d price data points, got %d", period+1, len(priceData))
 	}
 
-	var trValues []float64
-	for i := 1; i < len(priceData); i++ {
-		tr := CalculateTrueRange(priceData[i], priceData[i-1])
-		trValues = append(trValues, tr.Value)
-	}
-
-	// Calculate initial ATR as SMA of True Range
+	// True ranges are computed as they are consumed rather than collected first
 	var sum float64
-	for i := 0; i < period; i++ {
-		sum += trValues[i]
+	for i := 1; i <= period; i++ {
+		sum += trueRange(priceData[i], priceData[i-1])
 	}
 	atr := sum / float64(period)
 
 	// Smooth ATR using Wilder's smoothing (similar to EMA with α = 1/period)
-	for i := period; i < len(trValues); i++ {
-		atr = ((atr * float64(period-1)) + trValues[i]) / float64(period)
+	for i := period + 1; i < len(priceData); i++ {
+		atr = ((atr * float64(period-1)) + trueRange(priceData[i], priceData[i-1])) / float64(period)
 	}
 
 	return atr, nil
@@ -256,6 +270,180 @@ func CalculateSuperTrend(priceData []PriceData, period int, multiplier float64)
 	}, nil
 }
 
+// ADX levels separating the trend strengths of ADXResult
+const (
+	ADXTrendDeveloping = 20.0
+	ADXTrendStrong     = 25.0
+	ADXTrendVeryStrong = 50.0
+)
+
+// CalculateADX calculates the Average Directional Index with +DI and -DI using
+// Wilder's smoothing. The first ADX averages period DX values, each needing period
+// candles of directional movement, so at least 2*period candles are required.
+func CalculateADX(priceData []PriceData, period int) (*ADXResult, error) {
+	if period <= 0 || len(priceData) < 2*period {
+		return nil, fmt.Errorf("insufficient data: need at least %d price data points, got %d", 2*period, len(priceData))
+	}
+
+	p := float64(period)
+	var tr, plusDM, minusDM float64
+	for i := 1; i <= period; i++ {
+		up, down, r := directionalMovement(priceData[i], priceData[i-1])
+		plusDM += up
+		minusDM += down
+		tr += r
+	}
+
+	// The first ADX is the mean of the first period DX values, then it is smoothed
+	plusDI, minusDI, dx := directionalIndex(tr, plusDM, minusDM)
+	sumDX, dxCount := dx, 1
+	var adx float64
+	if dxCount == period {
+		adx = sumDX / p
+	}
+	for i := period + 1; i < len(priceData); i++ {
+		up, down, r := directionalMovement(priceData[i], priceData[i-1])
+		plusDM = plusDM - plusDM/p + up
+		minusDM = minusDM - minusDM/p + down
+		tr = tr - tr/p + r
+		plusDI, minusDI, dx = directionalIndex(tr, plusDM, minusDM)
+
+		if dxCount < period {
+			sumDX += dx
+			dxCount++
+			if dxCount == period {
+				adx = sumDX / p
+			}
+			continue
+		}
+		adx = (adx*(p-1) + dx) / p
+	}
+
+	direction := "up"
+	if minusDI > plusDI {
+		direction = "down"
+	}
+
+	return &ADXResult{
+		Value:     adx,
+		PlusDI:    plusDI,
+		MinusDI:   minusDI,
+		Strength:  adxStrength(adx),
+		Direction: direction,
+	}, nil
+}
+
+// directionalMovement returns the +DM, -DM and true range of a candle; only the
+// larger of the two moves counts, and only when it is positive
+func directionalMovement(current, previous PriceData) (float64, float64, float64) {
+	up := current.High - previous.High
+	down := previous.Low - current.Low
+	var plusDM, minusDM float64
+	if up > down && up > 0 {
+		plusDM = up
+	}
+	if down > up && down > 0 {
+		minusDM = down
+	}
+	return plusDM, minusDM, trueRange(current, previous)
+}
+
+// directionalIndex returns +DI, -DI and DX from the smoothed true range and moves
+func directionalIndex(tr, plusDM, minusDM float64) (float64, float64, float64) {
+	if tr == 0 {
+		return 0, 0, 0
+	}
+	plusDI := 100 * plusDM / tr
+	minusDI := 100 * minusDM / tr
+	if plusDI+minusDI == 0 {
+		return plusDI, minusDI, 0
+	}
+	return plusDI, minusDI, 100 * math.Abs(plusDI-minusDI) / (plusDI + minusDI)
+}
+
+func adxStrength(adx float64) string {
+	switch {
+	case adx >= ADXTrendVeryStrong:
+		return "very_strong"
+	case adx >= ADXTrendStrong:
+		return "strong"
+	case adx >= ADXTrendDeveloping:
+		return "developing"
+	default:
+		return "weak"
+	}
+}
+
+// Positions of a price relative to the Ichimoku cloud
+const (
+	CloudAbove  = "above"
+	CloudInside = "inside"
+	CloudBelow  = "below"
+)
+
+// CalculateIchimoku calculates the Ichimoku lines with the usual displacement of
+// kijunPeriod candles. The cloud under the latest candle was projected from
+// displacement candles ago, so at least senkouBPeriod+kijunPeriod candles are required.
+func CalculateIchimoku(priceData []PriceData, tenkanPeriod, kijunPeriod, senkouBPeriod int) (*IchimokuResult, error) {
+	if tenkanPeriod <= 0 || kijunPeriod <= 0 || senkouBPeriod <= 0 {
+		return nil, fmt.Errorf("ichimoku periods must be positive")
+	}
+	displacement := kijunPeriod
+	required := max(tenkanPeriod, kijunPeriod, senkouBPeriod) + displacement
+	if len(priceData) < required {
+		return nil, fmt.Errorf("insufficient data: need at least %d price data points, got %d", required, len(priceData))
+	}
+
+	last := len(priceData) - 1
+	tenkan := midpoint(priceData, last, tenkanPeriod)
+	kijun := midpoint(priceData, last, kijunPeriod)
+
+	// The spans drawn under the latest candle were computed displacement candles ago
+	projected := last - displacement
+	cloudA := (midpoint(priceData, projected, tenkanPeriod) + midpoint(priceData, projected, kijunPeriod)) / 2
+	cloudB := midpoint(priceData, projected, senkouBPeriod)
+
+	cloudColor := "bullish"
+	if cloudA < cloudB {
+		cloudColor = "bearish"
+	}
+	cloudTop, cloudBottom := math.Max(cloudA, cloudB), math.Min(cloudA, cloudB)
+
+	return &IchimokuResult{
+		Tenkan:      tenkan,
+		Kijun:       kijun,
+		SenkouA:     (tenkan + kijun) / 2,
+		SenkouB:     midpoint(priceData, last, senkouBPeriod),
+		Chikou:      priceData[last].Close,
+		CloudTop:    cloudTop,
+		CloudBottom: cloudBottom,
+		CloudColor:  cloudColor,
+		Position:    CloudPosition(priceData[last].Close, cloudTop, cloudBottom),
+	}, nil
+}
+
+// CloudPosition tells whether price is above, inside or below the cloud
+func CloudPosition(price, cloudTop, cloudBottom float64) string {
+	switch {
+	case price > cloudTop:
+		return CloudAbove
+	case price < cloudBottom:
+		return CloudBelow
+	default:
+		return CloudInside
+	}
+}
+
+// midpoint returns (highest high + lowest low) / 2 of the period candles ending at end
+func midpoint(priceData []PriceData, end, period int) float64 {
+	high, low := priceData[end].High, priceData[end].Low
+	for i := end - period + 1; i < end; i++ {
+		high = math.Max(high, priceData[i].High)
+		low = math.Min(low, priceData[i].Low)
+	}
+	return (high + low) / 2
+}
+
 // CalculateStochastic calculates the Stochastic Oscillator
 func CalculateStochastic(priceData []PriceData, kPeriod, dPeriod int) (map[string]float64, error) {
 	if len(priceData) < kPeriod {
@@ -338,7 +526,8 @@ func CalculateBollingerBands(prices []float64, period int, multiplier float64) (
 	recentPrices := prices[len(prices)-period:]
 	var variance float64
 	for _, price := range recentPrices {
-		variance += math.Pow(price-sma.Value, 2)
+		deviation := price - sma.Value
+		variance += deviation * deviation
 	}
 	variance /= float64(period)
 	stdDev := math.Sqrt(variance)
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
 	"github.com/google/uuid"
@@ -13,6 +14,8 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	GetByGoogleID(ctx context.Context, googleID string) (*entities.User, error)
+	// GetSandboxCreatedBefore returns the sandbox users created before the time
+	GetSandboxCreatedBefore(ctx context.Context, before time.Time) ([]entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -21,6 +24,7 @@ type UserRepository interface {
 type UserSettingsRepository interface {
 	Create(ctx context.Context, settings *entities.UserSettings) error
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserSettings, error)
+	GetWithReportsEnabled(ctx context.Context) ([]entities.UserSettings, error)
 	Update(ctx context.Context, settings *entities.UserSettings) error
 	Delete(ctx context.Context, userID uuid.UUID) error
 }
@@ -33,6 +37,9 @@ type CryptoCurrencyRepository interface {
 	GetAll(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error)
 	GetActive(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error)
 	Update(ctx context.Context, crypto *entities.CryptoCurrency) error
+	// UpdateTicker24h replaces the 24h statistics of a symbol, leaving its other
+	// fields alone; unknown symbols are ignored
+	UpdateTicker24h(ctx context.Context, symbol string, ticker entities.Ticker24h) error
 	Delete(ctx context.Context, id int) error
 }
 
@@ -42,7 +49,12 @@ type AlertRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Alert, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.Alert, error)
 	GetBySymbol(ctx context.Context, symbol string) ([]entities.Alert, error)
+	GetBySetID(ctx context.Context, setID uuid.UUID) ([]entities.Alert, error)
+	// GetDependents returns the alerts chained after the alert
+	GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error)
 	GetEnabled(ctx context.Context) ([]entities.Alert, error)
+	// GetAwaitingAck returns the enabled alerts whose trigger wasn't acknowledged yet
+	GetAwaitingAck(ctx context.Context) ([]entities.Alert, error)
 	Update(ctx context.Context, alert *entities.Alert) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	MarkTriggered(ctx context.Context, id uuid.UUID) error
@@ -62,13 +74,26 @@ type NotificationRepository interface {
 	MarkAllAsReadByUserID(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
+// NotificationDeliveryRepository defines the interface for notification delivery result operations
+type NotificationDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entities.NotificationDelivery) error
+	// ChannelWindows aggregates the deliveries made since a time per channel
+	ChannelWindows(ctx context.Context, since time.Time) ([]entities.ChannelDeliveryWindow, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
 // PriceHistoryRepository defines the interface for price history operations
 type PriceHistoryRepository interface {
 	Create(ctx context.Context, history *entities.PriceHistory) error
 	GetBySymbol(ctx context.Context, symbol, timeframe string, limit int) ([]entities.PriceHistory, error)
 	GetLatest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error)
+	GetByTimeRange(ctx context.Context, symbol, timeframe string, from, to time.Time) ([]entities.PriceHistory, error)
 	BulkInsert(ctx context.Context, histories []entities.PriceHistory) error
 	DeleteOld(ctx context.Context, symbol, timeframe string, keepDays int) error
+	// GetSymbols returns the symbols with candles on the timeframe, ordered by symbol
+	GetSymbols(ctx context.Context, timeframe string) ([]string, error)
+	GetOldest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error)
+	CountBefore(ctx context.Context, symbol, timeframe string, before time.Time) (int64, error)
 }
 
 // TechnicalIndicatorRepository defines the interface for technical indicator operations
@@ -76,6 +101,15 @@ type TechnicalIndicatorRepository interface {
 	Create(ctx context.Context, indicator *entities.TechnicalIndicator) error
 	GetBySymbol(ctx context.Context, symbol, timeframe, indicatorType string, limit int) ([]entities.TechnicalIndicator, error)
 	GetLatest(ctx context.Context, symbol, timeframe, indicatorType string) (*entities.TechnicalIndicator, error)
+	// GetByKey and GetLatestByKey look up one series by its entities.IndicatorKey
+	GetByKey(ctx context.Context, symbol, timeframe, indicatorKey string, limit int) ([]entities.TechnicalIndicator, error)
+	GetLatestByKey(ctx context.Context, symbol, timeframe, indicatorKey string) (*entities.TechnicalIndicator, error)
+	// GetIndicatorKeys returns the distinct indicator keys stored for a symbol and timeframe, sorted
+	GetIndicatorKeys(ctx context.Context, symbol, timeframe string) ([]string, error)
+	// GetByMetadata and GetLatestByMetadata only match indicators whose metadata
+	// contains every given key and value, e.g. {"period": 26}
+	GetByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}, limit int) ([]entities.TechnicalIndicator, error)
+	GetLatestByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}) (*entities.TechnicalIndicator, error)
 	BulkInsert(ctx context.Context, indicators []entities.TechnicalIndicator) error
 	DeleteOld(ctx context.Context, symbol, timeframe string, keepDays int) error
 }
@@ -90,3 +124,109 @@ type SessionRepository interface {
 	DeleteExpired(ctx context.Context) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
+
+// SystemBannerRepository defines the interface for system banner operations
+type SystemBannerRepository interface {
+	Create(ctx context.Context, banner *entities.SystemBanner) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.SystemBanner, error)
+	GetActive(ctx context.Context, now time.Time) ([]entities.SystemBanner, error)
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+}
+
+// SymbolRestrictionRepository defines the interface for symbol blacklist and greylist operations
+type SymbolRestrictionRepository interface {
+	// Upsert creates the symbol's restriction or replaces its level and reason
+	Upsert(ctx context.Context, restriction *entities.SymbolRestriction) error
+	GetBySymbol(ctx context.Context, symbol string) (*entities.SymbolRestriction, error)
+	GetAll(ctx context.Context) ([]entities.SymbolRestriction, error)
+	Delete(ctx context.Context, symbol string) error
+}
+
+// AlertSetRepository defines the interface for alert set operations
+type AlertSetRepository interface {
+	Create(ctx context.Context, set *entities.AlertSet) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.AlertSet, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AlertSet, error)
+	Update(ctx context.Context, set *entities.AlertSet) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// BasketRepository defines the interface for symbol basket operations
+type BasketRepository interface {
+	Create(ctx context.Context, basket *entities.Basket) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error)
+	GetBySymbol(ctx context.Context, symbol string) (*entities.Basket, error)
+	// GetByUserID returns the user's baskets ordered by name
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.Basket, error)
+	GetAll(ctx context.Context) ([]entities.Basket, error)
+	// Update renames the basket; its components are fixed at creation
+	Update(ctx context.Context, basket *entities.Basket) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ExchangePriceRepository defines the interface for the prices observed per exchange
+type ExchangePriceRepository interface {
+	BulkInsert(ctx context.Context, prices []entities.ExchangePrice) error
+	// GetLatest returns the latest price of the symbol on each exchange observed
+	// since the given time, ordered by exchange
+	GetLatest(ctx context.Context, symbol string, since time.Time) ([]entities.ExchangePrice, error)
+	// GetHistory returns the latest prices of the symbol on the exchange, newest first
+	GetHistory(ctx context.Context, symbol, exchange string, limit int) ([]entities.ExchangePrice, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PriceSummaryRepository defines the interface for the daily price summaries
+type PriceSummaryRepository interface {
+	// Upsert stores the summaries, replacing those of the same symbol and day
+	Upsert(ctx context.Context, summaries []entities.PriceSummary) error
+	// GetByRange returns the summaries of the days between from and to inclusive, oldest first
+	GetByRange(ctx context.Context, symbol string, from, to time.Time) ([]entities.PriceSummary, error)
+	// GetLatest returns the summary of the symbol's latest day, or nil when it has none
+	GetLatest(ctx context.Context, symbol string) (*entities.PriceSummary, error)
+}
+
+// ShareLinkRepository defines the interface for public share link operations
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *entities.ShareLink) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.ShareLink, error)
+	Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error
+}
+
+// APIKeyRepository defines the interface for public API key operations
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+	GetByKeyHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.APIKey, error)
+	CountActiveByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// AbuseFlagRepository defines the interface for alert abuse flag operations
+type AbuseFlagRepository interface {
+	Create(ctx context.Context, flag *entities.AbuseFlag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.AbuseFlag, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AbuseFlag, error)
+	GetActive(ctx context.Context, now time.Time) ([]entities.AbuseFlag, error)
+	List(ctx context.Context, limit, offset int) ([]entities.AbuseFlag, error)
+	Resolve(ctx context.Context, id uuid.UUID, resolvedBy string, resolvedAt time.Time) error
+}
+
+// DeviceTokenRepository defines the interface for push device token operations
+type DeviceTokenRepository interface {
+	// Register stores the token for device.UserID, taking it over from any other user
+	Register(ctx context.Context, device *entities.DeviceToken) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.DeviceToken, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	DeleteByTokens(ctx context.Context, tokens []string) (int64, error)
+}
+
+// TelegramLinkRepository defines the interface for Telegram chat link operations
+type TelegramLinkRepository interface {
+	// Save creates or replaces the user's link
+	Save(ctx context.Context, link *entities.TelegramLink) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.TelegramLink, error)
+	GetByCodeHash(ctx context.Context, codeHash string) (*entities.TelegramLink, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,235 @@
+package anonymize
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// Options tune a clone
+type Options struct {
+	// BatchSize is how many rows are read and written at once
+	BatchSize int
+	// MarketData also copies the price history and technical indicators, which
+	// hold no personal data but are by far the largest tables
+	MarketData bool
+	// Truncate empties the staging tables before copying; otherwise rows are
+	// upserted by primary key and staging-only rows are kept
+	Truncate bool
+}
+
+// TableCount is how many rows of a table were copied
+type TableCount struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// Cloner copies production rows into staging, pseudonymizing them on the way.
+// Primary and foreign keys are copied as they are, so every reference between
+// the copied rows still resolves. Sessions are never copied.
+type Cloner struct {
+	source  *gorm.DB
+	target  *gorm.DB
+	pseudo  *Pseudonymizer
+	options Options
+	logger  *logrus.Logger
+}
+
+// NewCloner creates a cloner from source into target
+func NewCloner(source, target *gorm.DB, pseudo *Pseudonymizer, options Options, logger *logrus.Logger) *Cloner {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 500
+	}
+	return &Cloner{
+		source:  source,
+		target:  target,
+		pseudo:  pseudo,
+		options: options,
+		logger:  logger,
+	}
+}
+
+// table copies one table; tables are listed parents first
+type table struct {
+	name string
+	copy func(ctx context.Context, c *Cloner) (int64, error)
+	// model is used to truncate the table
+	model interface{}
+}
+
+func (c *Cloner) tables() []table {
+	p := c.pseudo
+	tables := []table{
+		{"users", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(user *entities.User) {
+				user.Email = p.Email(user.Email)
+				user.Name = p.Name(user.ID)
+				user.GoogleID = p.Token("google_id", user.GoogleID)
+				user.Picture = nil
+				user.Avatar = nil
+			})
+		}, &entities.User{}},
+		{"user_settings", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.UserSettings](ctx, c, nil)
+		}, &entities.UserSettings{}},
+		{"crypto_currencies", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyNewRows(ctx, c, func(crypto *entities.CryptoCurrency) { crypto.ID = 0 })
+		}, &entities.CryptoCurrency{}},
+		{"alert_sets", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.AlertSet](ctx, c, nil)
+		}, &entities.AlertSet{}},
+		{"baskets", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.Basket](ctx, c, nil)
+		}, &entities.Basket{}},
+		{"alerts", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.Alert](ctx, c, nil)
+		}, &entities.Alert{}},
+		{"alert_conditions", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.AlertCondition](ctx, c, nil)
+		}, &entities.AlertCondition{}},
+		{"notifications", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows[entities.Notification](ctx, c, nil)
+		}, &entities.Notification{}},
+		{"notification_deliveries", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(delivery *entities.NotificationDelivery) {
+				// Provider errors may quote the recipient
+				if delivery.Error != "" {
+					delivery.Error = "redacted"
+				}
+			})
+		}, &entities.NotificationDelivery{}},
+		{"system_banners", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(banner *entities.SystemBanner) {
+				banner.CreatedBy = p.Email(banner.CreatedBy)
+			})
+		}, &entities.SystemBanner{}},
+		{"symbol_restrictions", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(restriction *entities.SymbolRestriction) {
+				restriction.CreatedBy = p.Email(restriction.CreatedBy)
+			})
+		}, &entities.SymbolRestriction{}},
+		{"share_links", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(link *entities.ShareLink) {
+				link.TokenHash = p.Token("share_link", link.TokenHash)
+			})
+		}, &entities.ShareLink{}},
+		{"api_keys", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(key *entities.APIKey) {
+				key.KeyHash = p.Token("api_key", key.KeyHash)
+				if len(key.Prefix) <= len(key.KeyHash) {
+					key.Prefix = key.KeyHash[:len(key.Prefix)]
+				}
+			})
+		}, &entities.APIKey{}},
+		{"abuse_flags", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(flag *entities.AbuseFlag) {
+				flag.ResolvedBy = p.Email(flag.ResolvedBy)
+			})
+		}, &entities.AbuseFlag{}},
+		{"device_tokens", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(device *entities.DeviceToken) {
+				device.Token = p.Token("device_token", device.Token)
+				device.Name = ""
+			})
+		}, &entities.DeviceToken{}},
+		{"telegram_links", func(ctx context.Context, c *Cloner) (int64, error) {
+			return copyRows(ctx, c, func(link *entities.TelegramLink) {
+				// Chat IDs may be encrypted with the production key; either way the
+				// pseudonym can't reach a real chat
+				link.ChatID = p.Token("telegram_chat", link.ChatID)
+				if link.Username != "" {
+					link.Username = "user_" + p.Token("telegram_username", link.Username)[:12]
+				}
+				link.CodeHash = ""
+				link.CodeExpiresAt = nil
+			})
+		}, &entities.TelegramLink{}},
+	}
+
+	if c.options.MarketData {
+		tables = append(tables,
+			table{"price_histories", func(ctx context.Context, c *Cloner) (int64, error) {
+				return copyNewRows(ctx, c, func(price *entities.PriceHistory) { price.ID = 0 })
+			}, &entities.PriceHistory{}},
+			table{"technical_indicators", func(ctx context.Context, c *Cloner) (int64, error) {
+				return copyNewRows(ctx, c, func(indicator *entities.TechnicalIndicator) { indicator.ID = 0 })
+			}, &entities.TechnicalIndicator{}},
+			table{"exchange_price_history", func(ctx context.Context, c *Cloner) (int64, error) {
+				return copyNewRows(ctx, c, func(price *entities.ExchangePrice) { price.ID = 0 })
+			}, &entities.ExchangePrice{}},
+			table{"price_daily_summaries", func(ctx context.Context, c *Cloner) (int64, error) {
+				return copyNewRows(ctx, c, func(summary *entities.PriceSummary) { summary.ID = 0 })
+			}, &entities.PriceSummary{}},
+		)
+	}
+	return tables
+}
+
+// Run copies every table and returns how many rows each one got. A failure stops
+// the clone, leaving the tables copied so far in place.
+func (c *Cloner) Run(ctx context.Context) ([]TableCount, error) {
+	tables := c.tables()
+
+	if c.options.Truncate {
+		// Children first, so no row is left pointing at a deleted parent
+		for i := len(tables) - 1; i >= 0; i-- {
+			err := c.target.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(tables[i].model).Error
+			if err != nil {
+				return nil, fmt.Errorf("failed to truncate %s: %w", tables[i].name, err)
+			}
+		}
+		// Sessions reference users and would otherwise survive the truncation
+		err := c.target.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&entities.Session{}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to truncate sessions: %w", err)
+		}
+	}
+
+	counts := make([]TableCount, 0, len(tables))
+	for _, t := range tables {
+		rows, err := t.copy(ctx, c)
+		if err != nil {
+			return counts, fmt.Errorf("failed to copy %s: %w", t.name, err)
+		}
+		counts = append(counts, TableCount{Table: t.name, Rows: rows})
+		c.logger.WithFields(logrus.Fields{"table": t.name, "rows": rows}).Info("Table cloned")
+	}
+	return counts, nil
+}
+
+// copyRows upserts every row of T by primary key after mask pseudonymizes it
+func copyRows[T any](ctx context.Context, c *Cloner, mask func(*T)) (int64, error) {
+	return copyBatches(ctx, c, mask, clause.OnConflict{UpdateAll: true})
+}
+
+// copyNewRows inserts the rows of T not in staging yet, for tables with
+// auto-increment keys that mask clears so staging's sequences stay in step;
+// duplicates are recognised by the table's unique indexes
+func copyNewRows[T any](ctx context.Context, c *Cloner, mask func(*T)) (int64, error) {
+	return copyBatches(ctx, c, mask, clause.OnConflict{DoNothing: true})
+}
+
+func copyBatches[T any](ctx context.Context, c *Cloner, mask func(*T), onConflict clause.OnConflict) (int64, error) {
+	var copied int64
+	var batch []T
+	err := c.source.WithContext(ctx).FindInBatches(&batch, c.options.BatchSize, func(_ *gorm.DB, _ int) error {
+		// FindInBatches pages on the last primary key of batch, so mask a copy
+		rows := append([]T(nil), batch...)
+		if mask != nil {
+			for i := range rows {
+				mask(&rows[i])
+			}
+		}
+		if err := c.target.WithContext(ctx).Omit(clause.Associations).Clauses(onConflict).Create(&rows).Error; err != nil {
+			return err
+		}
+		copied += int64(len(rows))
+		return nil
+	}).Error
+	return copied, err
+}
@@ -0,0 +1,59 @@
+// Package anonymize clones production data into a staging database with the
+// personal data replaced by pseudonyms, so load tests run on realistic data
+// without leaking PII.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PseudonymDomain is the domain of pseudonymized emails; .invalid can never be delivered to
+const PseudonymDomain = "staging.priceguard.invalid"
+
+// Pseudonymizer derives pseudonyms with an HMAC keyed by a secret. The same value
+// always maps to the same pseudonym, so values repeated across tables still match
+// and unique columns stay unique, while the originals can't be recovered without
+// the secret.
+type Pseudonymizer struct {
+	secret []byte
+}
+
+// NewPseudonymizer creates a pseudonymizer keyed by secret
+func NewPseudonymizer(secret []byte) *Pseudonymizer {
+	return &Pseudonymizer{secret: secret}
+}
+
+// Email returns an undeliverable address for email, ignoring its case
+func (p *Pseudonymizer) Email(email string) string {
+	if email == "" {
+		return ""
+	}
+	return "user-" + p.hash("email", strings.ToLower(strings.TrimSpace(email)))[:16] + "@" + PseudonymDomain
+}
+
+// Name returns a display name for the user
+func (p *Pseudonymizer) Name(userID uuid.UUID) string {
+	return "User " + p.hash("name", userID.String())[:8]
+}
+
+// Token replaces a secret or an identifier of kind (a Google ID, a device token, a
+// key hash) with one of the same shape that matches nothing outside staging
+func (p *Pseudonymizer) Token(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	return p.hash(kind, value)
+}
+
+func (p *Pseudonymizer) hash(kind, value string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
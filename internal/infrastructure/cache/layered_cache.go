@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
@@ -59,6 +60,7 @@ type MemoryCache struct {
 	stats       CacheStats
 	janitor     *janitor
 	stopJanitor chan bool
+	clock       clock.Clock
 }
 
 // NewMemoryCache cria novo cache em memória
@@ -67,6 +69,7 @@ func NewMemoryCache(maxSize int, cleanupInterval time.Duration) *MemoryCache {
 		data:        make(map[string]*CacheItem),
 		maxSize:     maxSize,
 		stopJanitor: make(chan bool),
+		clock:       clock.New(),
 	}
 
 	// Iniciar janitor para limpeza automática
@@ -79,6 +82,13 @@ func NewMemoryCache(maxSize int, cleanupInterval time.Duration) *MemoryCache {
 	return mc
 }
 
+// SetClock substitui o relógio usado para expiração (útil em testes)
+func (mc *MemoryCache) SetClock(c clock.Clock) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	mc.clock = c
+}
+
 // Set adiciona item ao cache
 func (mc *MemoryCache) Set(key string, value interface{}, ttl time.Duration) error {
 	mc.mutex.Lock()
@@ -89,7 +99,7 @@ func (mc *MemoryCache) Set(key string, value interface{}, ttl time.Duration) err
 		mc.evictLRU()
 	}
 
-	now := time.Now()
+	now := mc.clock.Now()
 	mc.data[key] = &CacheItem{
 		Key:        key,
 		Value:      value,
@@ -106,8 +116,9 @@ func (mc *MemoryCache) Set(key string, value interface{}, ttl time.Duration) err
 
 // Get recupera item do cache
 func (mc *MemoryCache) Get(key string) (interface{}, bool) {
-	mc.mutex.RLock()
-	defer mc.mutex.RUnlock()
+	// Lock exclusivo: a leitura atualiza estatísticas e remove itens expirados
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
 
 	item, exists := mc.data[key]
 	if !exists {
@@ -116,7 +127,7 @@ func (mc *MemoryCache) Get(key string) (interface{}, bool) {
 	}
 
 	// Verificar se expirou
-	if time.Now().After(item.ExpiredAt) {
+	if mc.clock.Now().After(item.ExpiredAt) {
 		mc.stats.Misses++
 		delete(mc.data, key)
 		mc.stats.Size = int64(len(mc.data))
@@ -124,7 +135,7 @@ func (mc *MemoryCache) Get(key string) (interface{}, bool) {
 	}
 
 	// Atualizar estatísticas de acesso
-	item.AccessedAt = time.Now()
+	item.AccessedAt = mc.clock.Now()
 	item.HitCount++
 	mc.stats.Hits++
 
@@ -178,7 +189,7 @@ func (mc *MemoryCache) cleanup() {
 	mc.mutex.Lock()
 	defer mc.mutex.Unlock()
 
-	now := time.Now()
+	now := mc.clock.Now()
 	for key, item := range mc.data {
 		if now.After(item.ExpiredAt) {
 			delete(mc.data, key)
@@ -241,6 +252,11 @@ func NewLayeredCache(l1MaxSize int, l1CleanupInterval time.Duration,
 	}
 }
 
+// SetClock substitui o relógio do cache L1
+func (lc *LayeredCache) SetClock(c clock.Clock) {
+	lc.l1Cache.SetClock(c)
+}
+
 // Set adiciona item em ambas as camadas
 func (lc *LayeredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	// Serializar valor para JSON
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -9,21 +11,49 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/secrets"
 )
 
 // Config holds all configuration for our application
 type Config struct {
-	Server     ServerConfig
-	Database   DatabaseConfig
-	Redis      RedisConfig
-	JWT        JWTConfig
-	Google     GoogleOAuthConfig
-	Binance    BinanceConfig
-	WebSocket  WebSocketConfig
-	App        AppConfig
-	RateLimit  RateLimitConfig
-	Email      EmailConfig
-	Monitoring MonitoringConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	JWT           JWTConfig
+	Google        GoogleOAuthConfig
+	MarketData    MarketDataConfig
+	Binance       BinanceConfig
+	Coinbase      CoinbaseConfig
+	Kraken        KrakenConfig
+	Aggregation   AggregationConfig
+	OrderBooks    OrderBookConfig
+	Collection    CollectionConfig
+	WebSocket     WebSocketConfig
+	App           AppConfig
+	RateLimit     RateLimitConfig
+	Email         EmailConfig
+	Push          PushConfig
+	Telegram      TelegramConfig
+	Webhooks      WebhookConfig
+	Notifications NotificationConfig
+	Monitoring    MonitoringConfig
+	Storage       StorageConfig
+	Encryption    EncryptionConfig
+	Secrets       SecretsConfig
+	Cluster       ClusterConfig
+	Alerts        AlertConfig
+	Indicators    IndicatorConfig
+	Retention     RetentionConfig
+	Summaries     SummaryConfig
+	Jobs          JobConfig
+	Baskets       BasketConfig
+	Sandbox       SandboxConfig
+	Faults        FaultInjectionConfig
+
+	// SecretsManager serves the secret values when a secrets backend is configured
+	SecretsManager *secrets.Manager
 }
 
 type ServerConfig struct {
@@ -64,18 +94,93 @@ type BinanceConfig struct {
 	APIKey    string
 	APISecret string
 	TestNet   bool
+	// BaseURL and WSBaseURL override the public endpoints (e.g. for a local fake server)
+	BaseURL   string
+	WSBaseURL string
+}
+
+// MarketDataConfig selects the exchange market data is collected from
+type MarketDataConfig struct {
+	// Exchange is binance, coinbase or kraken
+	Exchange string
+}
+
+// CoinbaseConfig configures the public Coinbase Exchange API
+type CoinbaseConfig struct {
+	// BaseURL and WSBaseURL override the public endpoints (e.g. for a local fake server)
+	BaseURL   string
+	WSBaseURL string
+}
+
+// KrakenConfig configures the public Kraken API
+type KrakenConfig struct {
+	// BaseURL and WSBaseURL override the public endpoints (e.g. for a local fake server)
+	BaseURL   string
+	WSBaseURL string
+}
+
+// AggregationConfig consolidates the price of symbols across several exchanges
+type AggregationConfig struct {
+	// Exchanges are the exchanges the symbols are collected from; fewer than two
+	// disables the aggregation
+	Exchanges []string
+	Symbols   []string
+	// Method is median or vwap
+	Method string
+	// Interval is how often the prices are collected; 0 only collects them when triggered
+	Interval time.Duration
+	// MaxQuoteAge is how old an exchange's price can be to count in the consolidated price
+	MaxQuoteAge time.Duration
+	// HistoryRetention is how long the prices of each exchange are kept
+	HistoryRetention time.Duration
+}
+
+// OrderBookConfig streams the Binance partial book depth of a few symbols
+type OrderBookConfig struct {
+	// Symbols have their order book kept in memory; empty disables the depth stream
+	Symbols []string
+	// Levels is how many bids and asks each update carries: 5, 10 or 20
+	Levels int
+	// MaxAge is how old a book can be before it is reported as stale
+	MaxAge time.Duration
+}
+
+// CollectionConfig controls how the collected market data is stored
+type CollectionConfig struct {
+	// AutoStart starts the data collection with the server; otherwise it only runs
+	// once started from the debug route
+	AutoStart bool
+	// FlushSize is how many collected prices are stored per bulk insert; 0 stores
+	// each price as it is collected
+	FlushSize int
+	// FlushInterval is how long collected prices wait at most before being stored
+	FlushInterval time.Duration
 }
 
 type WebSocketConfig struct {
 	Path           string
 	UpdateInterval time.Duration
 	MaxConnections int
+	ResumeTTL      time.Duration
 }
 
 type AppConfig struct {
 	Environment        string
 	LogLevel           string
 	CORSAllowedOrigins []string
+	// EnableDebugRoutes exposes the /test diagnostics group (admin only)
+	EnableDebugRoutes bool
+	AdminEmails       []string
+}
+
+// IsAdmin reports whether the email belongs to a configured administrator
+func (a *AppConfig) IsAdmin(email string) bool {
+	for _, admin := range a.AdminEmails {
+		if strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
 }
 
 type RateLimitConfig struct {
@@ -84,10 +189,58 @@ type RateLimitConfig struct {
 }
 
 type EmailConfig struct {
-	SMTPHost string
-	SMTPPort int
-	From     string
-	Password string
+	SMTPHost     string
+	SMTPPort     int
+	Username     string
+	From         string
+	Password     string
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// PushConfig configures Firebase Cloud Messaging. Without a credentials file the
+// application default credentials are used.
+type PushConfig struct {
+	FCMProjectID       string
+	FCMCredentialsFile string
+}
+
+// TelegramConfig configures the bot alerts are sent from. Users link their chat
+// by opening the bot, which reports back through the webhook.
+type TelegramConfig struct {
+	BotToken    string
+	BotUsername string
+	// WebhookSecret is the secret_token passed to setWebhook
+	WebhookSecret string
+}
+
+// Enabled reports whether a bot has been configured
+func (t *TelegramConfig) Enabled() bool {
+	return t.BotToken != "" && t.BotUsername != ""
+}
+
+// WebhookConfig configures the signature of webhook payloads. Without a signing
+// secret the verification endpoint is disabled.
+type WebhookConfig struct {
+	SigningSecret string
+	// SignatureTolerance is how old a signed payload may be and still be accepted
+	SignatureTolerance time.Duration
+}
+
+// NotificationConfig tunes concurrent notification delivery
+type NotificationConfig struct {
+	Workers            int
+	ChannelConcurrency int
+	EmailTimeout       time.Duration
+	PushTimeout        time.Duration
+	SMSTimeout         time.Duration
+	TelegramTimeout    time.Duration
+
+	// Provider implementation selected per channel
+	EmailProvider    string
+	PushProvider     string
+	SMSProvider      string
+	TelegramProvider string
 }
 
 type MonitoringConfig struct {
@@ -97,6 +250,192 @@ type MonitoringConfig struct {
 	JaegerEndpoint string
 }
 
+// StorageConfig points at an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type StorageConfig struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicBaseURL is used to build object URLs (e.g. a CDN); defaults to the endpoint
+	PublicBaseURL string
+	UsePathStyle  bool
+	MaxAvatarSize int64
+	// PresignExpiry bounds download links; ExportTTL is how long exports are kept
+	PresignExpiry   time.Duration
+	ExportTTL       time.Duration
+	CleanupInterval time.Duration
+}
+
+// Enabled reports whether object storage has been configured
+func (s *StorageConfig) Enabled() bool {
+	return s.Endpoint != "" && s.Bucket != ""
+}
+
+// EncryptionConfig holds the master keys protecting channel credentials at rest
+type EncryptionConfig struct {
+	// MasterKey is a base64 encoded 32 byte key, identified by MasterKeyID in stored values
+	MasterKey   string
+	MasterKeyID string
+	// RetiredKeys are "id:key" pairs still accepted for decryption after a rotation
+	RetiredKeys []string
+}
+
+// Enabled reports whether a master key has been configured
+func (e *EncryptionConfig) Enabled() bool {
+	return e.MasterKey != ""
+}
+
+// ClusterConfig identifies this instance when several run active-active across regions
+type ClusterConfig struct {
+	// Region partitions the notification queue; empty keeps the single shared queue
+	Region string
+	// InstanceID tells this instance's WebSocket broadcasts apart from the others'
+	InstanceID string
+	// AlertShards splits alert evaluation between the instances; 0 makes every
+	// instance evaluate every alert
+	AlertShards int
+}
+
+// AlertConfig tunes alert evaluation
+type AlertConfig struct {
+	// MaxDataAge skips evaluations whose latest candle or indicator closed longer ago,
+	// for alerts without a limit of their own; 0 evaluates data of any age
+	MaxDataAge time.Duration
+	// StateStore keeps the state crossover conditions compare against: redis, shared
+	// by the replicas and kept across restarts, or memory
+	StateStore string
+}
+
+// IndicatorConfig tunes the scheduled calculation of every active symbol's indicators
+type IndicatorConfig struct {
+	// CalculationInterval is how often the calculation runs; 0 disables it
+	CalculationInterval time.Duration
+	// CalculationTimeframes are the timeframes calculated for each symbol
+	CalculationTimeframes []string
+	// CalculationConcurrency is how many symbols are calculated at once
+	CalculationConcurrency int
+	// CalculationBatchSize is how many symbol and timeframe pairs share one bulk insert
+	CalculationBatchSize int
+	// StreamingSymbols have their RSI, EMA and ATR updated from the kline WebSocket
+	// stream as each candle of CalculationTimeframes closes; empty disables streaming
+	StreamingSymbols []string
+}
+
+// RetentionConfig bounds how long candles are kept in price_history
+type RetentionConfig struct {
+	// Interval is how often the policies are applied; 0 disables retention
+	Interval time.Duration
+	// Policies are applied in order; without any, every candle is kept
+	Policies []RetentionPolicy
+	// DryRun only logs what the policies would downsample and delete
+	DryRun bool
+}
+
+// SummaryConfig controls the daily price summaries compacted from the candles
+type SummaryConfig struct {
+	// Interval is how often the latest candles are compacted; 0 only compacts them
+	// when admins trigger the job
+	Interval time.Duration
+	// Timeframe is the timeframe of the candles summarized, which must divide a day
+	Timeframe string
+	// BackfillDays is how many days are summarized for symbols without summaries yet
+	BackfillDays int
+}
+
+// JobConfig schedules the background jobs without settings of their own; a zero
+// interval leaves a job to admins triggering it
+type JobConfig struct {
+	// CacheWarmupInterval is how often the public tickers of the active symbols are
+	// recomputed ahead of the requests
+	CacheWarmupInterval time.Duration
+	// ExchangeSyncInterval is how often the symbols listed on the exchange are added
+	ExchangeSyncInterval time.Duration
+	// Ticker24hInterval is how often the exchange's 24h statistics of the active
+	// symbols are refreshed
+	Ticker24hInterval time.Duration
+}
+
+// RetentionPolicy keeps the candles of Timeframe for KeepDays days, aggregating
+// the older ones into the DownsampleTo timeframes before deleting them
+type RetentionPolicy struct {
+	Timeframe    string
+	KeepDays     int
+	DownsampleTo []string
+}
+
+// BasketConfig controls the computation of user-defined symbol baskets
+type BasketConfig struct {
+	// Interval is how often basket values are computed; 0 disables the computation
+	Interval time.Duration
+	// Timeframes are computed from the component candles of the same timeframe
+	Timeframes []string
+	// MaxPriceAge is how long past its candle's close a component price can be used
+	MaxPriceAge time.Duration
+	MaxPerUser  int
+}
+
+// SandboxConfig enables demo users fed by the market simulator, so the frontend can
+// showcase the product without real accounts
+type SandboxConfig struct {
+	Enabled bool
+	// UserTTL is how long a sandbox user is kept after it was provisioned
+	UserTTL         time.Duration
+	CleanupInterval time.Duration
+	// SimulationInterval is how often a simulated price is stored for each sandbox symbol
+	SimulationInterval time.Duration
+	// VolatilityBps is the standard deviation of a simulated price move, in basis points
+	VolatilityBps int
+}
+
+// FaultInjectionConfig enables the fault injection hooks used for resilience testing;
+// it can't be enabled in production
+type FaultInjectionConfig struct {
+	Enabled bool
+	// Faults are active from startup; admins can change them at runtime
+	Faults []faults.Fault
+}
+
+// SecretsConfig selects an optional secrets manager whose values take precedence
+// over the environment for the keys listed in SecretKeys
+type SecretsConfig struct {
+	// Backend is "vault", "aws" or "gcp"; empty reads secrets from the environment only
+	Backend         string
+	CacheTTL        time.Duration
+	RefreshInterval time.Duration
+
+	VaultAddr  string
+	VaultToken string
+	VaultMount string
+	VaultPath  string
+
+	AWSRegion          string
+	AWSSecretID        string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	AWSEndpoint        string
+
+	GCPProject      string
+	GCPSecretPrefix string
+}
+
+// SecretKeys are the configuration keys a secrets backend may provide
+var SecretKeys = []string{
+	"DB_PASSWORD",
+	"REDIS_PASSWORD",
+	"JWT_SECRET",
+	"GOOGLE_CLIENT_SECRET",
+	"BINANCE_API_KEY",
+	"BINANCE_API_SECRET",
+	"EMAIL_PASSWORD",
+	"STORAGE_SECRET_ACCESS_KEY",
+	"ENCRYPTION_MASTER_KEY",
+	"TELEGRAM_BOT_TOKEN",
+	"TELEGRAM_WEBHOOK_SECRET",
+	"WEBHOOK_SIGNING_SECRET",
+}
+
 // LoadConfig loads configuration from environment variables and .env file
 func LoadConfig() (*Config, error) {
 	// Load .env file explicitly
@@ -119,9 +458,29 @@ func LoadConfig() (*Config, error) {
 
 	config := &Config{}
 
+	// Secrets backends are configured from the environment and consulted first
+	secretsConfig, err := loadSecretsConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Secrets = secretsConfig
+
+	if secretsConfig.Backend != "" {
+		manager, err := newSecretsManager(secretsConfig)
+		if err != nil {
+			return nil, err
+		}
+		config.SecretsManager = manager
+	}
+	// Malformed values are collected and reported together with validation problems
+	env := &envReader{}
+	secret := func(key, defaultValue string) string {
+		return getSecretEnv(config.SecretsManager, key, defaultValue)
+	}
+
 	// Load server configuration
 	config.Server = ServerConfig{
-		Port: getIntEnv("PORT", 8080),
+		Port: env.int("PORT", 8080),
 		Host: getStringEnv("HOST", "localhost"),
 		Mode: getStringEnv("GIN_MODE", "debug"),
 	}
@@ -129,9 +488,9 @@ func LoadConfig() (*Config, error) {
 	// Load database configuration
 	config.Database = DatabaseConfig{
 		Host:     getStringEnv("DB_HOST", "localhost"),
-		Port:     getIntEnv("DB_PORT", 5432),
+		Port:     env.int("DB_PORT", 5432),
 		User:     getStringEnv("DB_USER", "postgres"),
-		Password: getStringEnv("DB_PASSWORD", "password"),
+		Password: secret("DB_PASSWORD", "password"),
 		Name:     getStringEnv("DB_NAME", "priceguard"),
 		SSLMode:  getStringEnv("DB_SSL_MODE", "disable"),
 	}
@@ -139,24 +498,18 @@ func LoadConfig() (*Config, error) {
 	// Load Redis configuration
 	config.Redis = RedisConfig{
 		Host:     getStringEnv("REDIS_HOST", "localhost"),
-		Port:     getIntEnv("REDIS_PORT", 6379),
-		Password: getStringEnv("REDIS_PASSWORD", ""),
-		DB:       getIntEnv("REDIS_DB", 0),
+		Port:     env.int("REDIS_PORT", 6379),
+		Password: secret("REDIS_PASSWORD", ""),
+		DB:       env.int("REDIS_DB", 0),
 	}
 
 	// Load JWT configuration
-	jwtExpiration, err := time.ParseDuration(getStringEnv("JWT_EXPIRATION", "24h"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRATION format: %w", err)
-	}
+	jwtExpiration := env.duration("JWT_EXPIRATION", "24h")
 
-	jwtRefreshExpiration, err := time.ParseDuration(getStringEnv("JWT_REFRESH_EXPIRATION", "168h"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRATION format: %w", err)
-	}
+	jwtRefreshExpiration := env.duration("JWT_REFRESH_EXPIRATION", "168h")
 
 	config.JWT = JWTConfig{
-		Secret:            getStringEnv("JWT_SECRET", ""),
+		Secret:            secret("JWT_SECRET", ""),
 		Expiration:        jwtExpiration,
 		RefreshExpiration: jwtRefreshExpiration,
 	}
@@ -164,27 +517,92 @@ func LoadConfig() (*Config, error) {
 	// Load Google OAuth configuration
 	config.Google = GoogleOAuthConfig{
 		ClientID:     getStringEnv("GOOGLE_CLIENT_ID", ""),
-		ClientSecret: getStringEnv("GOOGLE_CLIENT_SECRET", ""),
+		ClientSecret: secret("GOOGLE_CLIENT_SECRET", ""),
 		RedirectURL:  getStringEnv("GOOGLE_REDIRECT_URL", ""),
 	}
 
 	// Load Binance configuration
 	config.Binance = BinanceConfig{
-		APIKey:    getStringEnv("BINANCE_API_KEY", ""),
-		APISecret: getStringEnv("BINANCE_API_SECRET", ""),
-		TestNet:   getBoolEnv("BINANCE_TESTNET", true),
+		APIKey:    secret("BINANCE_API_KEY", ""),
+		APISecret: secret("BINANCE_API_SECRET", ""),
+		TestNet:   env.bool("BINANCE_TESTNET", true),
+		BaseURL:   getStringEnv("BINANCE_BASE_URL", ""),
+		WSBaseURL: getStringEnv("BINANCE_WS_BASE_URL", ""),
 	}
 
-	// Load WebSocket configuration
-	wsUpdateInterval, err := time.ParseDuration(getStringEnv("WS_UPDATE_INTERVAL", "1000ms"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid WS_UPDATE_INTERVAL format: %w", err)
+	config.MarketData = MarketDataConfig{
+		Exchange: strings.ToLower(getStringEnv("MARKET_DATA_EXCHANGE", "binance")),
+	}
+	config.Coinbase = CoinbaseConfig{
+		BaseURL:   getStringEnv("COINBASE_BASE_URL", ""),
+		WSBaseURL: getStringEnv("COINBASE_WS_BASE_URL", ""),
+	}
+	config.Kraken = KrakenConfig{
+		BaseURL:   getStringEnv("KRAKEN_BASE_URL", ""),
+		WSBaseURL: getStringEnv("KRAKEN_WS_BASE_URL", ""),
+	}
+
+	// Load multi-exchange price aggregation configuration
+	config.Aggregation = AggregationConfig{
+		Exchanges:        getStringSliceEnv("AGGREGATION_EXCHANGES"),
+		Symbols:          getStringSliceEnv("AGGREGATION_SYMBOLS"),
+		Method:           strings.ToLower(getStringEnv("AGGREGATION_METHOD", "median")),
+		Interval:         env.duration("AGGREGATION_INTERVAL", "1m"),
+		MaxQuoteAge:      env.duration("AGGREGATION_MAX_QUOTE_AGE", "5m"),
+		HistoryRetention: env.duration("AGGREGATION_HISTORY_RETENTION", "168h"),
+	}
+	for i, exchange := range config.Aggregation.Exchanges {
+		config.Aggregation.Exchanges[i] = strings.ToLower(exchange)
+	}
+	for i, symbol := range config.Aggregation.Symbols {
+		config.Aggregation.Symbols[i] = strings.ToUpper(symbol)
+	}
+	if config.Aggregation.Interval < 0 {
+		env.problems.addf("AGGREGATION_INTERVAL must not be negative")
+	}
+	if config.Aggregation.MaxQuoteAge <= 0 {
+		env.problems.addf("AGGREGATION_MAX_QUOTE_AGE must be positive")
+	}
+	if config.Aggregation.HistoryRetention < 0 {
+		env.problems.addf("AGGREGATION_HISTORY_RETENTION must not be negative")
+	}
+
+	// Load order book configuration
+	config.OrderBooks = OrderBookConfig{
+		Symbols: getStringSliceEnv("ORDER_BOOK_SYMBOLS"),
+		Levels:  env.int("ORDER_BOOK_LEVELS", 20),
+		MaxAge:  env.duration("ORDER_BOOK_MAX_AGE", "10s"),
+	}
+	for i, symbol := range config.OrderBooks.Symbols {
+		config.OrderBooks.Symbols[i] = strings.ToUpper(symbol)
+	}
+	if config.OrderBooks.MaxAge <= 0 {
+		env.problems.addf("ORDER_BOOK_MAX_AGE must be positive")
 	}
 
+	// Load market data collection configuration
+	config.Collection = CollectionConfig{
+		AutoStart:     env.bool("PRICE_COLLECTION_AUTO_START", true),
+		FlushSize:     env.int("PRICE_COLLECTION_FLUSH_SIZE", 500),
+		FlushInterval: env.duration("PRICE_COLLECTION_FLUSH_INTERVAL", "5s"),
+	}
+	if config.Collection.FlushSize < 0 {
+		env.problems.addf("PRICE_COLLECTION_FLUSH_SIZE must not be negative")
+	}
+	if config.Collection.FlushSize > 0 && config.Collection.FlushInterval <= 0 {
+		env.problems.addf("PRICE_COLLECTION_FLUSH_INTERVAL must be positive")
+	}
+
+	// Load WebSocket configuration
+	wsUpdateInterval := env.duration("WS_UPDATE_INTERVAL", "1000ms")
+
+	wsResumeTTL := env.duration("WS_RESUME_TTL", "2m")
+
 	config.WebSocket = WebSocketConfig{
 		Path:           getStringEnv("WS_PATH", "/ws/dashboard"),
 		UpdateInterval: wsUpdateInterval,
-		MaxConnections: getIntEnv("WS_MAX_CONNECTIONS", 1000),
+		MaxConnections: env.int("WS_MAX_CONNECTIONS", 1000),
+		ResumeTTL:      wsResumeTTL,
 	}
 
 	// Load app configuration
@@ -193,52 +611,251 @@ func LoadConfig() (*Config, error) {
 		Environment:        getStringEnv("APP_ENV", "development"),
 		LogLevel:           getStringEnv("LOG_LEVEL", "debug"),
 		CORSAllowedOrigins: corsOrigins,
+		EnableDebugRoutes:  env.bool("ENABLE_DEBUG_ROUTES", false),
+		AdminEmails:        getStringSliceEnv("ADMIN_EMAILS"),
 	}
 
 	// Load rate limit configuration
 	config.RateLimit = RateLimitConfig{
-		RequestsPerMinute: getIntEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
-		Burst:             getIntEnv("RATE_LIMIT_BURST", 10),
+		RequestsPerMinute: env.int("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
+		Burst:             env.int("RATE_LIMIT_BURST", 10),
 	}
 
 	// Load email configuration
+	emailRetryBackoff := env.duration("EMAIL_RETRY_BACKOFF", "1s")
+
 	config.Email = EmailConfig{
-		SMTPHost: getStringEnv("EMAIL_SMTP_HOST", ""),
-		SMTPPort: getIntEnv("EMAIL_SMTP_PORT", 587),
-		From:     getStringEnv("EMAIL_FROM", ""),
-		Password: getStringEnv("EMAIL_PASSWORD", ""),
+		SMTPHost:     getStringEnv("EMAIL_SMTP_HOST", ""),
+		SMTPPort:     env.int("EMAIL_SMTP_PORT", 587),
+		Username:     getStringEnv("EMAIL_SMTP_USERNAME", ""),
+		From:         getStringEnv("EMAIL_FROM", ""),
+		Password:     secret("EMAIL_PASSWORD", ""),
+		MaxAttempts:  env.int("EMAIL_MAX_ATTEMPTS", 3),
+		RetryBackoff: emailRetryBackoff,
+	}
+
+	config.Push = PushConfig{
+		FCMProjectID:       getStringEnv("FCM_PROJECT_ID", ""),
+		FCMCredentialsFile: getStringEnv("FCM_CREDENTIALS_FILE", ""),
+	}
+
+	config.Telegram = TelegramConfig{
+		BotToken:      secret("TELEGRAM_BOT_TOKEN", ""),
+		BotUsername:   getStringEnv("TELEGRAM_BOT_USERNAME", ""),
+		WebhookSecret: secret("TELEGRAM_WEBHOOK_SECRET", ""),
+	}
+
+	config.Webhooks = WebhookConfig{
+		SigningSecret:      secret("WEBHOOK_SIGNING_SECRET", ""),
+		SignatureTolerance: env.duration("WEBHOOK_SIGNATURE_TOLERANCE", "5m"),
+	}
+	if config.Webhooks.SigningSecret != "" && config.Webhooks.SignatureTolerance < time.Second {
+		env.problems.addf("WEBHOOK_SIGNATURE_TOLERANCE must be at least a second")
+	}
+
+	// Load notification delivery configuration
+	emailTimeout := env.duration("NOTIFICATION_EMAIL_TIMEOUT", "10s")
+
+	pushTimeout := env.duration("NOTIFICATION_PUSH_TIMEOUT", "5s")
+
+	smsTimeout := env.duration("NOTIFICATION_SMS_TIMEOUT", "10s")
+
+	telegramTimeout := env.duration("NOTIFICATION_TELEGRAM_TIMEOUT", "10s")
+
+	config.Notifications = NotificationConfig{
+		Workers:            env.int("NOTIFICATION_WORKERS", 4),
+		ChannelConcurrency: env.int("NOTIFICATION_CHANNEL_CONCURRENCY", 4),
+		EmailTimeout:       emailTimeout,
+		PushTimeout:        pushTimeout,
+		SMSTimeout:         smsTimeout,
+		TelegramTimeout:    telegramTimeout,
+		EmailProvider:      getStringEnv("NOTIFICATION_EMAIL_PROVIDER", "log"),
+		PushProvider:       getStringEnv("NOTIFICATION_PUSH_PROVIDER", "log"),
+		SMSProvider:        getStringEnv("NOTIFICATION_SMS_PROVIDER", "log"),
+		TelegramProvider:   getStringEnv("NOTIFICATION_TELEGRAM_PROVIDER", "log"),
 	}
 
 	// Load monitoring configuration
 	config.Monitoring = MonitoringConfig{
-		EnableMetrics:  getBoolEnv("ENABLE_METRICS", true),
-		MetricsPort:    getIntEnv("METRICS_PORT", 9090),
-		EnableTracing:  getBoolEnv("ENABLE_TRACING", false),
+		EnableMetrics:  env.bool("ENABLE_METRICS", true),
+		MetricsPort:    env.int("METRICS_PORT", 9090),
+		EnableTracing:  env.bool("ENABLE_TRACING", false),
 		JaegerEndpoint: getStringEnv("JAEGER_ENDPOINT", ""),
 	}
 
-	// Validate required configuration
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	// Load object storage configuration
+	presignExpiry := env.duration("STORAGE_PRESIGN_EXPIRY", "1h")
+
+	exportTTL := env.duration("STORAGE_EXPORT_TTL", "168h")
+
+	cleanupInterval := env.duration("STORAGE_CLEANUP_INTERVAL", "1h")
+
+	config.Storage = StorageConfig{
+		Endpoint:        getStringEnv("STORAGE_ENDPOINT", ""),
+		Region:          getStringEnv("STORAGE_REGION", "us-east-1"),
+		Bucket:          getStringEnv("STORAGE_BUCKET", ""),
+		AccessKeyID:     getStringEnv("STORAGE_ACCESS_KEY_ID", ""),
+		SecretAccessKey: secret("STORAGE_SECRET_ACCESS_KEY", ""),
+		PublicBaseURL:   getStringEnv("STORAGE_PUBLIC_URL", ""),
+		UsePathStyle:    env.bool("STORAGE_USE_PATH_STYLE", true),
+		MaxAvatarSize:   int64(env.int("AVATAR_MAX_SIZE_BYTES", 2<<20)),
+		PresignExpiry:   presignExpiry,
+		ExportTTL:       exportTTL,
+		CleanupInterval: cleanupInterval,
 	}
 
-	return config, nil
-}
+	// Load encryption-at-rest configuration
+	config.Encryption = EncryptionConfig{
+		MasterKey:   secret("ENCRYPTION_MASTER_KEY", ""),
+		MasterKeyID: getStringEnv("ENCRYPTION_MASTER_KEY_ID", "primary"),
+		RetiredKeys: getStringSliceEnv("ENCRYPTION_RETIRED_KEYS"),
+	}
+
+	// Load cluster configuration
+	hostname, _ := os.Hostname()
+	config.Cluster = ClusterConfig{
+		Region:      getStringEnv("REGION", ""),
+		InstanceID:  getStringEnv("INSTANCE_ID", hostname),
+		AlertShards: env.int("ALERT_SHARDS", 16),
+	}
+	if config.Cluster.AlertShards < 0 {
+		env.problems.addf("ALERT_SHARDS must not be negative")
+	}
+
+	// Load alert configuration
+	config.Alerts = AlertConfig{
+		MaxDataAge: env.duration("ALERT_MAX_DATA_AGE", "10m"),
+		StateStore: strings.ToLower(getStringEnv("ALERT_STATE_STORE", "redis")),
+	}
+	if config.Alerts.MaxDataAge < 0 {
+		env.problems.addf("ALERT_MAX_DATA_AGE must not be negative")
+	}
+
+	// Load indicator calculation configuration
+	config.Indicators = IndicatorConfig{
+		CalculationInterval:    env.duration("INDICATOR_CALCULATION_INTERVAL", "5m"),
+		CalculationTimeframes:  getStringSliceEnv("INDICATOR_CALCULATION_TIMEFRAMES"),
+		CalculationConcurrency: env.int("INDICATOR_CALCULATION_CONCURRENCY", 4),
+		CalculationBatchSize:   env.int("INDICATOR_CALCULATION_BATCH_SIZE", 20),
+		StreamingSymbols:       getStringSliceEnv("INDICATOR_STREAMING_SYMBOLS"),
+	}
+	if len(config.Indicators.CalculationTimeframes) == 0 {
+		config.Indicators.CalculationTimeframes = []string{"1h", "4h", "1d"}
+	}
+	if config.Indicators.CalculationInterval < 0 {
+		env.problems.addf("INDICATOR_CALCULATION_INTERVAL must not be negative")
+	}
+	if config.Indicators.CalculationConcurrency <= 0 {
+		env.problems.addf("INDICATOR_CALCULATION_CONCURRENCY must be positive")
+	}
+	if config.Indicators.CalculationBatchSize <= 0 {
+		env.problems.addf("INDICATOR_CALCULATION_BATCH_SIZE must be positive")
+	}
+
+	// Load price history retention configuration
+	retentionPolicies, err := parseRetentionPolicies(getStringEnv("PRICE_RETENTION_POLICIES", ""))
+	if err != nil {
+		env.problems.addf("invalid PRICE_RETENTION_POLICIES: %v", err)
+	}
+	config.Retention = RetentionConfig{
+		Interval: env.duration("PRICE_RETENTION_INTERVAL", "1h"),
+		Policies: retentionPolicies,
+		DryRun:   env.bool("PRICE_RETENTION_DRY_RUN", false),
+	}
+	if config.Retention.Interval < 0 {
+		env.problems.addf("PRICE_RETENTION_INTERVAL must not be negative")
+	}
+
+	// Load daily price summary configuration
+	config.Summaries = SummaryConfig{
+		Interval:     env.duration("PRICE_SUMMARY_INTERVAL", "15m"),
+		Timeframe:    getStringEnv("PRICE_SUMMARY_TIMEFRAME", "1h"),
+		BackfillDays: env.int("PRICE_SUMMARY_BACKFILL_DAYS", 30),
+	}
+	if config.Summaries.Interval < 0 {
+		env.problems.addf("PRICE_SUMMARY_INTERVAL must not be negative")
+	}
+	if config.Summaries.BackfillDays <= 0 {
+		env.problems.addf("PRICE_SUMMARY_BACKFILL_DAYS must be positive")
+	}
+
+	// Load background job configuration
+	config.Jobs = JobConfig{
+		CacheWarmupInterval:  env.duration("JOB_CACHE_WARMUP_INTERVAL", "0"),
+		ExchangeSyncInterval: env.duration("JOB_EXCHANGE_SYNC_INTERVAL", "0"),
+		Ticker24hInterval:    env.duration("JOB_TICKER_24H_INTERVAL", "1m"),
+	}
+	if config.Jobs.CacheWarmupInterval < 0 {
+		env.problems.addf("JOB_CACHE_WARMUP_INTERVAL must not be negative")
+	}
+	if config.Jobs.ExchangeSyncInterval < 0 {
+		env.problems.addf("JOB_EXCHANGE_SYNC_INTERVAL must not be negative")
+	}
+	if config.Jobs.Ticker24hInterval < 0 {
+		env.problems.addf("JOB_TICKER_24H_INTERVAL must not be negative")
+	}
 
-// Validate checks if all required configuration values are set
-func (c *Config) Validate() error {
-	if c.JWT.Secret == "" {
-		return fmt.Errorf("JWT_SECRET is required")
+	// Load symbol basket configuration
+	config.Baskets = BasketConfig{
+		Interval:    env.duration("BASKET_INTERVAL", "1m"),
+		Timeframes:  getStringSliceEnv("BASKET_TIMEFRAMES"),
+		MaxPriceAge: env.duration("BASKET_MAX_PRICE_AGE", "5m"),
+		MaxPerUser:  env.int("BASKET_MAX_PER_USER", 10),
+	}
+	if len(config.Baskets.Timeframes) == 0 {
+		config.Baskets.Timeframes = []string{"1m"}
+	}
+	if config.Baskets.Interval < 0 {
+		env.problems.addf("BASKET_INTERVAL must not be negative")
+	}
+	if config.Baskets.MaxPerUser < 0 {
+		env.problems.addf("BASKET_MAX_PER_USER must not be negative")
 	}
 
-	// In development, allow placeholder values for Google OAuth
-	if c.App.Environment != "development" {
-		if c.Google.ClientID == "" || c.Google.ClientSecret == "" {
-			return fmt.Errorf("Google OAuth credentials are required")
+	// Load sandbox configuration
+	config.Sandbox = SandboxConfig{
+		Enabled:            env.bool("SANDBOX_ENABLED", false),
+		UserTTL:            env.duration("SANDBOX_USER_TTL", "24h"),
+		CleanupInterval:    env.duration("SANDBOX_CLEANUP_INTERVAL", "15m"),
+		SimulationInterval: env.duration("SANDBOX_SIMULATION_INTERVAL", "30s"),
+		VolatilityBps:      env.int("SANDBOX_VOLATILITY_BPS", 20),
+	}
+	if config.Sandbox.Enabled {
+		if config.Sandbox.UserTTL < time.Minute {
+			env.problems.addf("SANDBOX_USER_TTL must be at least a minute")
+		}
+		if config.Sandbox.CleanupInterval <= 0 {
+			env.problems.addf("SANDBOX_CLEANUP_INTERVAL must be positive")
 		}
+		if config.Sandbox.SimulationInterval <= 0 {
+			env.problems.addf("SANDBOX_SIMULATION_INTERVAL must be positive")
+		}
+		if config.Sandbox.VolatilityBps <= 0 {
+			env.problems.addf("SANDBOX_VOLATILITY_BPS must be positive")
+		}
+	}
+
+	// Load fault injection configuration
+	initialFaults, err := faults.ParseFaults(getStringEnv("FAULT_INJECTION_FAULTS", ""))
+	if err != nil {
+		env.problems.addf("invalid FAULT_INJECTION_FAULTS: %v", err)
+	}
+	config.Faults = FaultInjectionConfig{
+		Enabled: env.bool("FAULT_INJECTION_ENABLED", false),
+		Faults:  initialFaults,
 	}
 
-	return nil
+	// Validate the configuration, reporting every problem at once
+	problems := env.problems
+	var validationErr *ValidationError
+	if err := config.Validate(); errors.As(err, &validationErr) {
+		problems = append(problems, validationErr.Problems...)
+	}
+	if err := problems.err(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return config, nil
 }
 
 // GetDatabaseDSN returns the database connection string
@@ -258,6 +875,77 @@ func (c *Config) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
 }
 
+// loadSecretsConfig reads the secrets backend settings from the environment
+func loadSecretsConfig() (SecretsConfig, error) {
+	cacheTTL, err := time.ParseDuration(getStringEnv("SECRETS_CACHE_TTL", "5m"))
+	if err != nil {
+		return SecretsConfig{}, fmt.Errorf("invalid SECRETS_CACHE_TTL format: %w", err)
+	}
+
+	refreshInterval, err := time.ParseDuration(getStringEnv("SECRETS_REFRESH_INTERVAL", "15m"))
+	if err != nil {
+		return SecretsConfig{}, fmt.Errorf("invalid SECRETS_REFRESH_INTERVAL format: %w", err)
+	}
+
+	return SecretsConfig{
+		Backend:            strings.ToLower(getStringEnv("SECRETS_BACKEND", "")),
+		CacheTTL:           cacheTTL,
+		RefreshInterval:    refreshInterval,
+		VaultAddr:          getStringEnv("VAULT_ADDR", ""),
+		VaultToken:         getStringEnv("VAULT_TOKEN", ""),
+		VaultMount:         getStringEnv("VAULT_MOUNT", "secret"),
+		VaultPath:          getStringEnv("VAULT_SECRET_PATH", "priceguard"),
+		AWSRegion:          getStringEnv("SECRETS_AWS_REGION", getStringEnv("AWS_REGION", "")),
+		AWSSecretID:        getStringEnv("SECRETS_AWS_SECRET_ID", ""),
+		AWSAccessKeyID:     getStringEnv("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey: getStringEnv("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:    getStringEnv("AWS_SESSION_TOKEN", ""),
+		AWSEndpoint:        getStringEnv("SECRETS_AWS_ENDPOINT", ""),
+		GCPProject:         getStringEnv("SECRETS_GCP_PROJECT", ""),
+		GCPSecretPrefix:    getStringEnv("SECRETS_GCP_PREFIX", "priceguard-"),
+	}, nil
+}
+
+// newSecretsManager builds the configured backend and loads the secrets once,
+// so a misconfigured backend fails startup instead of falling back silently
+func newSecretsManager(cfg SecretsConfig) (*secrets.Manager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var provider secrets.Provider
+	var err error
+	switch cfg.Backend {
+	case "vault":
+		provider, err = secrets.NewVaultProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultMount, cfg.VaultPath)
+	case "aws":
+		provider, err = secrets.NewAWSProvider(cfg.AWSRegion, cfg.AWSSecretID, cfg.AWSAccessKeyID,
+			cfg.AWSSecretAccessKey, cfg.AWSSessionToken, cfg.AWSEndpoint)
+	case "gcp":
+		provider, err = secrets.NewGCPProvider(ctx, cfg.GCPProject, cfg.GCPSecretPrefix)
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_BACKEND %q", cfg.Backend)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure %s secrets backend: %w", cfg.Backend, err)
+	}
+
+	manager := secrets.NewManager(provider, cfg.CacheTTL, SecretKeys...)
+	if err := manager.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+// getSecretEnv prefers the secrets backend and falls back to the environment
+func getSecretEnv(manager *secrets.Manager, key, defaultValue string) string {
+	if manager != nil {
+		if value, found, err := manager.Get(context.Background(), key); err == nil && found {
+			return value
+		}
+	}
+	return getStringEnv(key, defaultValue)
+}
+
 // Helper functions to get environment variables with defaults
 func getStringEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -266,20 +954,46 @@ func getStringEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+// parseRetentionPolicies reads a comma-separated list of timeframe:days[:targets],
+// where targets joins the timeframes to downsample to with "+", e.g.
+// "1m:7d:1h+1d,5m:30d"; the days may omit their "d" suffix
+func parseRetentionPolicies(spec string) ([]RetentionPolicy, error) {
+	var policies []RetentionPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("%q is not timeframe:days[:targets]", entry)
+		}
+		days, err := strconv.Atoi(strings.TrimSuffix(parts[1], "d"))
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid days in %q", entry)
 		}
+
+		policy := RetentionPolicy{Timeframe: parts[0], KeepDays: days}
+		if len(parts) == 3 {
+			for _, target := range strings.Split(parts[2], "+") {
+				if target == "" {
+					return nil, fmt.Errorf("empty target timeframe in %q", entry)
+				}
+				policy.DownsampleTo = append(policy.DownsampleTo, target)
+			}
+		}
+		policies = append(policies, policy)
 	}
-	return defaultValue
+	return policies, nil
 }
 
-func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
+func getStringSliceEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
 		}
 	}
-	return defaultValue
+	return values
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -119,3 +121,197 @@ func TestGetRedisAddr(t *testing.T) {
 	expected := "localhost:6379"
 	assert.Equal(t, expected, config.GetRedisAddr())
 }
+
+func TestDebugRoutesConfig(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test_secret")
+	os.Setenv("GOOGLE_CLIENT_ID", "test_client_id")
+	os.Setenv("GOOGLE_CLIENT_SECRET", "test_client_secret")
+	os.Setenv("ENABLE_DEBUG_ROUTES", "true")
+	os.Setenv("ADMIN_EMAILS", "ops@example.com, Admin@Example.com ,")
+
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("GOOGLE_CLIENT_ID")
+		os.Unsetenv("GOOGLE_CLIENT_SECRET")
+		os.Unsetenv("ENABLE_DEBUG_ROUTES")
+		os.Unsetenv("ADMIN_EMAILS")
+	}()
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+
+	assert.True(t, config.App.EnableDebugRoutes)
+	assert.Equal(t, []string{"ops@example.com", "Admin@Example.com"}, config.App.AdminEmails)
+	assert.True(t, config.App.IsAdmin("admin@example.com"))
+	assert.False(t, config.App.IsAdmin("user@example.com"))
+}
+
+func TestDebugRoutesDisabledByDefault(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test_secret")
+	os.Setenv("GOOGLE_CLIENT_ID", "test_client_id")
+	os.Setenv("GOOGLE_CLIENT_SECRET", "test_client_secret")
+
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("GOOGLE_CLIENT_ID")
+		os.Unsetenv("GOOGLE_CLIENT_SECRET")
+	}()
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+
+	assert.False(t, config.App.EnableDebugRoutes)
+	assert.Empty(t, config.App.AdminEmails)
+}
+
+func TestLoadConfigReadsSecretsFromVault(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"data":{"JWT_SECRET":"vault_secret","DB_PASSWORD":"vault_password","GOOGLE_CLIENT_SECRET":"vault_client_secret"}}}`))
+	}))
+	defer vault.Close()
+
+	os.Setenv("JWT_SECRET", "env_secret")
+	os.Setenv("GOOGLE_CLIENT_ID", "test_client_id")
+	os.Setenv("SECRETS_BACKEND", "vault")
+	os.Setenv("VAULT_ADDR", vault.URL)
+	os.Setenv("VAULT_TOKEN", "root")
+
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("GOOGLE_CLIENT_ID")
+		os.Unsetenv("SECRETS_BACKEND")
+		os.Unsetenv("VAULT_ADDR")
+		os.Unsetenv("VAULT_TOKEN")
+	}()
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	require.NotNil(t, config.SecretsManager)
+
+	assert.Equal(t, "vault_secret", config.JWT.Secret)
+	assert.Equal(t, "vault_password", config.Database.Password)
+	assert.Equal(t, "vault_client_secret", config.Google.ClientSecret)
+	// Keys the backend doesn't hold still come from the environment defaults
+	assert.Equal(t, "", config.Redis.Password)
+}
+
+func TestLoadConfigFailsOnUnreachableSecretsBackend(t *testing.T) {
+	os.Setenv("JWT_SECRET", "env_secret")
+	os.Setenv("SECRETS_BACKEND", "vault")
+
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("SECRETS_BACKEND")
+	}()
+
+	_, err := LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestFaultInjectionConfig(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test_secret")
+	os.Setenv("GOOGLE_CLIENT_ID", "test_client_id")
+	os.Setenv("GOOGLE_CLIENT_SECRET", "test_client_secret")
+	os.Setenv("APP_ENV", "development")
+	os.Setenv("FAULT_INJECTION_ENABLED", "true")
+	os.Setenv("FAULT_INJECTION_FAULTS", "binance:25:error,redis:10:200ms")
+
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("GOOGLE_CLIENT_ID")
+		os.Unsetenv("GOOGLE_CLIENT_SECRET")
+		os.Unsetenv("FAULT_INJECTION_ENABLED")
+		os.Unsetenv("FAULT_INJECTION_FAULTS")
+		os.Unsetenv("APP_ENV")
+	}()
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.True(t, config.Faults.Enabled)
+	assert.Len(t, config.Faults.Faults, 2)
+
+	// Fault injection is for test environments only
+	os.Setenv("APP_ENV", "production")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	os.Setenv("APP_ENV", "staging")
+	os.Setenv("FAULT_INJECTION_FAULTS", "binance:25")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestConfigValidation_ReportsEveryProblem(t *testing.T) {
+	os.Clearenv()
+	env := map[string]string{
+		"JWT_EXPIRATION":       "soon",
+		"PORT":                 "70000",
+		"REDIS_PORT":           "six",
+		"BINANCE_WS_BASE_URL":  "https://stream.binance.com",
+		"TELEGRAM_BOT_TOKEN":   "123:abc",
+		"CORS_ALLOWED_ORIGINS": "*,https://app.example.com",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer os.Clearenv()
+
+	_, err := LoadConfig()
+	require.Error(t, err)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ElementsMatch(t, []string{
+		`JWT_EXPIRATION must be a duration such as 30s or 5m, got "soon"`,
+		`REDIS_PORT must be an integer, got "six"`,
+		"JWT_SECRET is required",
+		"PORT must be between 1 and 65535, got 70000",
+		`BINANCE_WS_BASE_URL must use ws or wss, got "https://stream.binance.com"`,
+		"CORS_ALLOWED_ORIGINS can't mix * with explicit origins",
+		"TELEGRAM_BOT_TOKEN and TELEGRAM_BOT_USERNAME must be set together",
+	}, validationErr.Problems)
+	assert.Contains(t, err.Error(), "7 configuration problems")
+}
+
+func TestConfigValidation_EncryptionKeys(t *testing.T) {
+	valid := func() *Config {
+		os.Clearenv()
+		os.Setenv("JWT_SECRET", "test_secret")
+		defer os.Clearenv()
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		return config
+	}
+
+	config := valid()
+	config.Encryption.MasterKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // 32 bytes
+	config.Encryption.RetiredKeys = []string{"old:c2hvcnQ=", "missing-separator"}
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, config.Validate(), &validationErr)
+	assert.Equal(t, []string{
+		"ENCRYPTION_RETIRED_KEYS key old must decode to 32 bytes, got 5",
+		"ENCRYPTION_RETIRED_KEYS entries must be formatted as id:key",
+	}, validationErr.Problems)
+
+	config.Encryption.RetiredKeys = nil
+	assert.NoError(t, config.Validate())
+}
+
+func TestParseRetentionPolicies(t *testing.T) {
+	policies, err := parseRetentionPolicies("1m:7d:1h+1d, 5m:30")
+	require.NoError(t, err)
+	assert.Equal(t, []RetentionPolicy{
+		{Timeframe: "1m", KeepDays: 7, DownsampleTo: []string{"1h", "1d"}},
+		{Timeframe: "5m", KeepDays: 30},
+	}, policies)
+
+	policies, err = parseRetentionPolicies("")
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	for _, spec := range []string{"1m", "1m:week", "1m:0d", "1m:7d:1h+", ":7d", "1m:7d:1h:1d"} {
+		_, err := parseRetentionPolicies(spec)
+		assert.Error(t, err, spec)
+	}
+}
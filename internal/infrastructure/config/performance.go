@@ -114,6 +114,11 @@ type WebSocketPerformanceConfig struct {
 	PingPeriod time.Duration `mapstructure:"ping_period" default:"54s"`
 	PongWait   time.Duration `mapstructure:"pong_wait" default:"60s"`
 
+	// Compression (permessage-deflate, for clients that negotiate it)
+	EnableCompression    bool `mapstructure:"enable_compression" default:"true"`
+	CompressionLevel     int  `mapstructure:"compression_level" default:"1"`
+	CompressionThreshold int  `mapstructure:"compression_threshold" default:"256"` // Smallest frame compressed, in bytes
+
 	// Broadcasting
 	BroadcastChannelSize int           `mapstructure:"broadcast_channel_size" default:"1000"`
 	BroadcastWorkers     int           `mapstructure:"broadcast_workers" default:"10"`
@@ -243,6 +248,9 @@ func GetDefaultPerformanceConfig() *PerformanceConfig {
 			ReadTimeout:          60 * time.Second,
 			PingPeriod:           54 * time.Second,
 			PongWait:             60 * time.Second,
+			EnableCompression:    true,
+			CompressionLevel:     1,
+			CompressionThreshold: 256,
 			BroadcastChannelSize: 1000,
 			BroadcastWorkers:     10,
 			BroadcastTimeout:     5 * time.Second,
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in the configuration, so a deployment
+// can be fixed in one pass instead of one restart per missing value
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%d configuration problems: %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// problems collects configuration problems as they are found
+type problems []string
+
+func (p *problems) addf(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// envReader reads typed environment variables, recording malformed values as
+// problems instead of stopping at the first one
+type envReader struct {
+	problems problems
+}
+
+func (r *envReader) duration(key, defaultValue string) time.Duration {
+	value := getStringEnv(key, defaultValue)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.problems.addf("%s must be a duration such as 30s or 5m, got %q", key, value)
+		// Fall back to the default so the problem isn't reported twice by Validate
+		d, _ = time.ParseDuration(defaultValue)
+	}
+	return d
+}
+
+func (r *envReader) int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		r.problems.addf("%s must be an integer, got %q", key, value)
+		return defaultValue
+	}
+	return i
+}
+
+func (r *envReader) bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problems.addf("%s must be true or false, got %q", key, value)
+		return defaultValue
+	}
+	return b
+}
+
+// Validate checks the configuration as a whole and reports every problem at once
+// as a *ValidationError
+func (c *Config) Validate() error {
+	var p problems
+
+	// Required values
+	if c.JWT.Secret == "" {
+		p.addf("JWT_SECRET is required")
+	}
+	// In development, allow placeholder values for Google OAuth
+	if c.App.Environment != "development" {
+		if c.Google.ClientID == "" || c.Google.ClientSecret == "" {
+			p.addf("Google OAuth credentials are required: set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET")
+		}
+	}
+
+	switch c.App.Environment {
+	case "development", "test", "staging", "production":
+	default:
+		p.addf("APP_ENV must be development, test, staging or production, got %q", c.App.Environment)
+	}
+	switch c.MarketData.Exchange {
+	case "binance", "coinbase", "kraken":
+	default:
+		p.addf("MARKET_DATA_EXCHANGE must be binance, coinbase or kraken, got %q", c.MarketData.Exchange)
+	}
+	for _, exchange := range c.Aggregation.Exchanges {
+		switch exchange {
+		case "binance", "coinbase", "kraken":
+		default:
+			p.addf("AGGREGATION_EXCHANGES must list binance, coinbase or kraken, got %q", exchange)
+		}
+	}
+	switch c.Alerts.StateStore {
+	case "redis", "memory":
+	default:
+		p.addf("ALERT_STATE_STORE must be redis or memory, got %q", c.Alerts.StateStore)
+	}
+	switch c.Aggregation.Method {
+	case "median", "vwap":
+	default:
+		p.addf("AGGREGATION_METHOD must be median or vwap, got %q", c.Aggregation.Method)
+	}
+	switch c.OrderBooks.Levels {
+	case 5, 10, 20:
+	default:
+		p.addf("ORDER_BOOK_LEVELS must be 5, 10 or 20, got %d", c.OrderBooks.Levels)
+	}
+	switch c.Summaries.Timeframe {
+	case "1m", "5m", "15m", "30m", "1h", "4h", "1d":
+	default:
+		p.addf("PRICE_SUMMARY_TIMEFRAME must be a timeframe dividing a day, got %q", c.Summaries.Timeframe)
+	}
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		p.addf("GIN_MODE must be debug, release or test, got %q", c.Server.Mode)
+	}
+
+	// Ports
+	validatePort(&p, "PORT", c.Server.Port)
+	validatePort(&p, "DB_PORT", c.Database.Port)
+	validatePort(&p, "REDIS_PORT", c.Redis.Port)
+	validatePort(&p, "EMAIL_SMTP_PORT", c.Email.SMTPPort)
+	if c.Monitoring.EnableMetrics {
+		validatePort(&p, "METRICS_PORT", c.Monitoring.MetricsPort)
+		if c.Monitoring.MetricsPort == c.Server.Port {
+			p.addf("METRICS_PORT and PORT can't both be %d", c.Server.Port)
+		}
+	}
+	if c.Redis.DB < 0 {
+		p.addf("REDIS_DB can't be negative, got %d", c.Redis.DB)
+	}
+
+	// Durations and limits
+	if c.JWT.Expiration <= 0 {
+		p.addf("JWT_EXPIRATION must be positive")
+	}
+	if c.JWT.RefreshExpiration < c.JWT.Expiration {
+		p.addf("JWT_REFRESH_EXPIRATION (%s) can't be shorter than JWT_EXPIRATION (%s)", c.JWT.RefreshExpiration, c.JWT.Expiration)
+	}
+	if c.RateLimit.RequestsPerMinute <= 0 {
+		p.addf("RATE_LIMIT_REQUESTS_PER_MINUTE must be positive, got %d", c.RateLimit.RequestsPerMinute)
+	}
+	if c.Notifications.Workers <= 0 {
+		p.addf("NOTIFICATION_WORKERS must be positive, got %d", c.Notifications.Workers)
+	}
+	if c.Notifications.ChannelConcurrency <= 0 {
+		p.addf("NOTIFICATION_CHANNEL_CONCURRENCY must be positive, got %d", c.Notifications.ChannelConcurrency)
+	}
+
+	// URLs
+	validateURL(&p, "GOOGLE_REDIRECT_URL", c.Google.RedirectURL, "http", "https")
+	validateURL(&p, "BINANCE_BASE_URL", c.Binance.BaseURL, "http", "https")
+	validateURL(&p, "BINANCE_WS_BASE_URL", c.Binance.WSBaseURL, "ws", "wss")
+	validateURL(&p, "COINBASE_BASE_URL", c.Coinbase.BaseURL, "http", "https")
+	validateURL(&p, "COINBASE_WS_BASE_URL", c.Coinbase.WSBaseURL, "ws", "wss")
+	validateURL(&p, "KRAKEN_BASE_URL", c.Kraken.BaseURL, "http", "https")
+	validateURL(&p, "KRAKEN_WS_BASE_URL", c.Kraken.WSBaseURL, "ws", "wss")
+	validateURL(&p, "STORAGE_ENDPOINT", c.Storage.Endpoint, "http", "https")
+	validateURL(&p, "STORAGE_PUBLIC_URL", c.Storage.PublicBaseURL, "http", "https")
+	validateURL(&p, "JAEGER_ENDPOINT", c.Monitoring.JaegerEndpoint, "http", "https")
+	validateURL(&p, "VAULT_ADDR", c.Secrets.VaultAddr, "http", "https")
+	for _, origin := range c.App.CORSAllowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "*" {
+			validateURL(&p, "CORS_ALLOWED_ORIGINS", origin, "http", "https")
+		}
+	}
+
+	// Options that only make sense together, or not at all together
+	if len(c.App.CORSAllowedOrigins) > 1 {
+		for _, origin := range c.App.CORSAllowedOrigins {
+			if strings.TrimSpace(origin) == "*" {
+				p.addf("CORS_ALLOWED_ORIGINS can't mix * with explicit origins")
+				break
+			}
+		}
+	}
+	if (c.Telegram.BotToken == "") != (c.Telegram.BotUsername == "") {
+		p.addf("TELEGRAM_BOT_TOKEN and TELEGRAM_BOT_USERNAME must be set together")
+	}
+	if (c.Storage.Endpoint == "") != (c.Storage.Bucket == "") {
+		p.addf("STORAGE_ENDPOINT and STORAGE_BUCKET must be set together")
+	}
+	if c.Notifications.EmailProvider == "smtp" && c.Email.SMTPHost == "" {
+		p.addf("NOTIFICATION_EMAIL_PROVIDER=smtp requires EMAIL_SMTP_HOST")
+	}
+	if c.Notifications.TelegramProvider == "telegram" && c.Telegram.BotToken == "" {
+		p.addf("NOTIFICATION_TELEGRAM_PROVIDER=telegram requires TELEGRAM_BOT_TOKEN")
+	}
+	if c.Encryption.Enabled() {
+		validateMasterKey(&p, "ENCRYPTION_MASTER_KEY", c.Encryption.MasterKey)
+	}
+	for _, entry := range c.Encryption.RetiredKeys {
+		id, key, found := strings.Cut(entry, ":")
+		if !found || id == "" {
+			p.addf("ENCRYPTION_RETIRED_KEYS entries must be formatted as id:key")
+			continue
+		}
+		validateMasterKey(&p, "ENCRYPTION_RETIRED_KEYS key "+id, key)
+	}
+	if c.Faults.Enabled && c.App.Environment == "production" {
+		p.addf("fault injection can't be enabled in production")
+	}
+
+	return p.err()
+}
+
+func validatePort(p *problems, key string, port int) {
+	if port < 1 || port > 65535 {
+		p.addf("%s must be between 1 and 65535, got %d", key, port)
+	}
+}
+
+// validateURL checks that a configured URL is absolute and uses one of schemes;
+// empty values are left to the checks for required settings
+func validateURL(p *problems, key, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		p.addf("%s must be an absolute URL, got %q", key, value)
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	p.addf("%s must use %s, got %q", key, strings.Join(schemes, " or "), value)
+}
+
+// validateMasterKey checks a key the way the encryption envelope will read it
+func validateMasterKey(p *problems, key, value string) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		p.addf("%s is not valid base64", key)
+		return
+	}
+	if len(decoded) != 32 {
+		p.addf("%s must decode to 32 bytes, got %d", key, len(decoded))
+	}
+}
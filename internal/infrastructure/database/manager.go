@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -39,6 +40,16 @@ func NewManager(cfg *config.Config, logger *logrus.Logger) (*Manager, error) {
 	}, nil
 }
 
+// NewManagerWithConnections wraps connections opened elsewhere, e.g. by tests that
+// run the API against SQLite and an in-memory Redis
+func NewManagerWithConnections(db *gorm.DB, redisClient *redis.Client, logger *logrus.Logger) *Manager {
+	return &Manager{
+		Postgres: &PostgresClient{db: db, logger: logger},
+		Redis:    &RedisClient{client: redisClient, logger: logger},
+		logger:   logger,
+	}
+}
+
 // Close closes all database connections
 func (m *Manager) Close() error {
 	var errors []error
@@ -0,0 +1,200 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// envelopeVersion prefixes every encrypted value so the format can evolve
+	envelopeVersion = "v1"
+	// dataKeySize is the AES-256 key size used for data keys and static master keys
+	dataKeySize = 32
+)
+
+var (
+	// ErrUnknownKey is returned when a value was wrapped by a master key the keyring doesn't hold
+	ErrUnknownKey = errors.New("unknown master key")
+	// ErrMalformedValue is returned for values that aren't produced by Envelope.Encrypt
+	ErrMalformedValue = errors.New("malformed encrypted value")
+)
+
+// MasterKey wraps and unwraps data keys. The static key below keeps the master
+// key in configuration; a KMS backed key implements the same contract.
+type MasterKey interface {
+	// ID identifies the key in stored values so they survive rotation
+	ID() string
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// Envelope encrypts sensitive values (webhook secrets, phone numbers, chat IDs)
+// with a fresh data key per value, wrapped by the primary master key. The owning
+// user's ID is bound as associated data, so a value copied to another user's row
+// fails to decrypt.
+//
+// Encrypted values look like v1.<key id>.<wrapped data key>.<nonce and ciphertext>.
+type Envelope struct {
+	primary MasterKey
+	keys    map[string]MasterKey
+}
+
+// NewEnvelope encrypts with primary and decrypts with primary or any of the
+// retired keys, which lets old values be read while they are re-encrypted
+func NewEnvelope(primary MasterKey, retired ...MasterKey) (*Envelope, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("a primary master key is required")
+	}
+
+	e := &Envelope{
+		primary: primary,
+		keys:    make(map[string]MasterKey, len(retired)+1),
+	}
+	for _, key := range append([]MasterKey{primary}, retired...) {
+		if key.ID() == "" || strings.Contains(key.ID(), ".") {
+			return nil, fmt.Errorf("invalid master key ID %q", key.ID())
+		}
+		if _, exists := e.keys[key.ID()]; exists {
+			return nil, fmt.Errorf("duplicate master key ID %q", key.ID())
+		}
+		e.keys[key.ID()] = key
+	}
+	return e, nil
+}
+
+// Encrypt seals plaintext for userID. Empty values are stored as is.
+func (e *Envelope) Encrypt(ctx context.Context, userID uuid.UUID, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	sealed, err := seal(dataKey, []byte(plaintext), userID[:])
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := e.primary.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return strings.Join([]string{
+		envelopeVersion,
+		e.primary.ID(),
+		base64.RawURLEncoding.EncodeToString(wrapped),
+		base64.RawURLEncoding.EncodeToString(sealed),
+	}, "."), nil
+}
+
+// Decrypt opens a value produced by Encrypt for the same userID
+func (e *Envelope) Decrypt(ctx context.Context, userID uuid.UUID, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	keyID, wrapped, sealed, err := parseValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := e.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	dataKey, err := key.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	plaintext, err := open(dataKey, sealed, userID[:])
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value looks like an Envelope value, so rows
+// written before encryption was enabled can be told apart and migrated
+func IsEncrypted(value string) bool {
+	_, _, _, err := parseValue(value)
+	return err == nil
+}
+
+// NeedsRotation reports whether value isn't encrypted with the primary key yet
+func (e *Envelope) NeedsRotation(value string) bool {
+	keyID, _, _, err := parseValue(value)
+	return err == nil && keyID != e.primary.ID()
+}
+
+// parseValue splits an encrypted value into its key ID, wrapped data key and sealed payload
+func parseValue(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 4 || parts[0] != envelopeVersion || parts[1] == "" {
+		return "", nil, nil, ErrMalformedValue
+	}
+
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformedValue
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", nil, nil, ErrMalformedValue
+	}
+	return parts[1], wrapped, sealed, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open reverses seal
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
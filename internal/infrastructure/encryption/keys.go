@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+)
+
+// StaticMasterKey is an AES-256 master key taken from configuration
+type StaticMasterKey struct {
+	id  string
+	key []byte
+}
+
+// NewStaticMasterKey creates a master key from a base64 encoded 32 byte secret
+func NewStaticMasterKey(id, encodedKey string) (*StaticMasterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("master key %q is not valid base64: %w", id, err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, dataKeySize, len(key))
+	}
+	return &StaticMasterKey{id: id, key: key}, nil
+}
+
+// ID returns the key identifier stored alongside wrapped data keys
+func (k *StaticMasterKey) ID() string {
+	return k.id
+}
+
+// WrapKey encrypts a data key with the master key
+func (k *StaticMasterKey) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return seal(k.key, dataKey, []byte(k.id))
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey
+func (k *StaticMasterKey) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(k.key, wrapped, []byte(k.id))
+}
+
+// NewEnvelopeFromConfig builds an envelope from the configured master key and
+// the retired keys kept around for decryption after a rotation
+func NewEnvelopeFromConfig(cfg *config.EncryptionConfig) (*Envelope, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("encryption master key is not configured")
+	}
+
+	primary, err := NewStaticMasterKey(cfg.MasterKeyID, cfg.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	retired := make([]MasterKey, 0, len(cfg.RetiredKeys))
+	for _, entry := range cfg.RetiredKeys {
+		id, encodedKey, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("retired key must be formatted as id:key")
+		}
+		key, err := NewStaticMasterKey(id, encodedKey)
+		if err != nil {
+			return nil, err
+		}
+		retired = append(retired, key)
+	}
+
+	return NewEnvelope(primary, retired...)
+}
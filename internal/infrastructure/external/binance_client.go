@@ -12,12 +12,25 @@ import (
 	"sync"
 	"time"
 
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
 	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 )
 
+// WebSocket reconnection defaults
+const (
+	defaultWSReconnectMinBackoff = time.Second
+	defaultWSReconnectMaxBackoff = time.Minute
+	// maxWSBackfillCandles caps the missed candles of a stream backfilled after a gap,
+	// the capacity of a stream channel; older gaps are left to the candle backfill
+	maxWSBackfillCandles = 100
+)
+
 // BinanceClient handles interactions with Binance API
 type BinanceClient struct {
 	config     *config.BinanceConfig
@@ -35,16 +48,42 @@ type BinanceClient struct {
 	wsChannels  map[string]chan []byte
 	wsActive    bool
 	wsReconnect bool
+	wsStreams   []string
+	wsStop      chan struct{}
+	// wsLastClosed is the open time of the last closed kline of each kline stream,
+	// which gaps after a reconnection are measured from
+	wsLastClosed map[string]int64
+	wsMinBackoff time.Duration
+	wsMaxBackoff time.Duration
+	clock        clock.Clock
 
 	// Retry configuration
 	maxRetries    int
 	retryInterval time.Duration
+
+	// Fault injection for resilience testing; nil outside test environments
+	faults *faults.Injector
 }
 
 // TickerPrice represents a ticker price from Binance
 type TickerPrice struct {
 	Symbol string `json:"symbol"`
 	Price  string `json:"price"`
+	// EventTime is when the request was sent: the ticker endpoint carries no event
+	// time, and the price it returns is at most as old as the request
+	EventTime time.Time `json:"-"`
+}
+
+// Ticker24h is the rolling 24 hour statistics of a symbol from Binance
+type Ticker24h struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
+	Volume             string `json:"volume"`
+	QuoteVolume        string `json:"quoteVolume"`
+	OpenTime           int64  `json:"openTime"`
+	CloseTime          int64  `json:"closeTime"` // When the statistics were computed
 }
 
 // WebSocketMessage represents a WebSocket message from Binance
@@ -82,6 +121,16 @@ type KlineWebSocketData struct {
 	} `json:"k"`
 }
 
+// DepthWebSocketData is a partial book depth update: the best bids and asks of a
+// symbol as price and quantity pairs, best first. The symbol comes from the stream
+// name since Binance leaves it out of the message.
+type DepthWebSocketData struct {
+	Symbol       string      `json:"-"`
+	LastUpdateID int64       `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
 // KlineData represents a candlestick/kline from Binance
 type KlineData struct {
 	OpenTime                 int64  `json:"openTime"`
@@ -115,6 +164,12 @@ func NewBinanceClient(cfg *config.BinanceConfig, logger *logrus.Logger) *Binance
 		baseURL = "https://testnet.binance.vision"
 		wsBaseURL = "wss://testnet.binance.vision/ws"
 	}
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	}
+	if cfg.WSBaseURL != "" {
+		wsBaseURL = strings.TrimRight(cfg.WSBaseURL, "/")
+	}
 
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -131,17 +186,46 @@ func NewBinanceClient(cfg *config.BinanceConfig, logger *logrus.Logger) *Binance
 		logger:        logger,
 		rateLimiter:   rateLimiter,
 		wsChannels:    make(map[string]chan []byte),
+		wsLastClosed:  make(map[string]int64),
+		wsMinBackoff:  defaultWSReconnectMinBackoff,
+		wsMaxBackoff:  defaultWSReconnectMaxBackoff,
+		clock:         clock.New(),
 		maxRetries:    3,
 		retryInterval: time.Second * 2,
 	}
 }
 
+// Name returns the exchange name
+func (b *BinanceClient) Name() string {
+	return ExchangeBinance
+}
+
+// SetReconnectBackoff sets the first delay before reconnecting a dropped WebSocket
+// connection, doubled after each failed attempt up to max
+func (b *BinanceClient) SetReconnectBackoff(min, max time.Duration) {
+	b.wsMutex.Lock()
+	defer b.wsMutex.Unlock()
+	b.wsMinBackoff = min
+	b.wsMaxBackoff = max
+}
+
+// SetClock replaces the clock the candles missed while disconnected are counted by
+func (b *BinanceClient) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// SetFaultInjector makes API requests fail or slow down according to the binance fault
+func (b *BinanceClient) SetFaultInjector(injector *faults.Injector) {
+	b.faults = injector
+}
+
 // GetTickerPrice gets the current price for a symbol
 func (b *BinanceClient) GetTickerPrice(ctx context.Context, symbol string) (*TickerPrice, error) {
 	endpoint := "/api/v3/ticker/price"
 	params := url.Values{}
 	params.Set("symbol", symbol)
 
+	requestedAt := time.Now()
 	resp, err := b.makeRequest(ctx, "GET", endpoint, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ticker price: %w", err)
@@ -152,6 +236,7 @@ func (b *BinanceClient) GetTickerPrice(ctx context.Context, symbol string) (*Tic
 	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
 		return nil, fmt.Errorf("failed to decode ticker response: %w", err)
 	}
+	ticker.EventTime = requestedAt
 
 	return &ticker, nil
 }
@@ -174,6 +259,45 @@ func (b *BinanceClient) GetAllTickerPrices(ctx context.Context) ([]TickerPrice,
 	return tickers, nil
 }
 
+// GetTicker24h gets the rolling 24 hour statistics of a symbol
+func (b *BinanceClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	endpoint := "/api/v3/ticker/24hr"
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	resp, err := b.makeRequest(ctx, "GET", endpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h ticker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var ticker Ticker24h
+	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
+		return nil, fmt.Errorf("failed to decode 24h ticker response: %w", err)
+	}
+
+	return &ticker, nil
+}
+
+// GetAllTickers24h gets the rolling 24 hour statistics of every symbol in one
+// request, which costs less of the rate limit than a request per symbol
+func (b *BinanceClient) GetAllTickers24h(ctx context.Context) ([]Ticker24h, error) {
+	endpoint := "/api/v3/ticker/24hr"
+
+	resp, err := b.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all 24h tickers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tickers []Ticker24h
+	if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
+		return nil, fmt.Errorf("failed to decode 24h tickers response: %w", err)
+	}
+
+	return tickers, nil
+}
+
 // GetKlines gets candlestick/kline data for a symbol
 func (b *BinanceClient) GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error) {
 	endpoint := "/api/v3/klines"
@@ -246,14 +370,22 @@ func (b *BinanceClient) makeRequest(ctx context.Context, method, endpoint string
 		"endpoint": endpoint,
 	}).Debug("Making Binance API request")
 
+	if err := b.faults.Inject(ctx, faults.TargetBinance); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrUpstreamUnavailable, err)
+	}
+
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("%w: failed to execute request: %w", entities.ErrUpstreamUnavailable, err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		// Server errors and rate limit bans are Binance's problem, not the caller's
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 418 {
+			return nil, fmt.Errorf("%w: API request failed with status %d: %s", entities.ErrUpstreamUnavailable, resp.StatusCode, string(body))
+		}
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
@@ -273,7 +405,10 @@ func (b *BinanceClient) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-// StartWebSocket starts a WebSocket connection for real-time data
+// StartWebSocket starts a WebSocket connection for real-time data. A dropped
+// connection is reconnected with exponential backoff to the same streams, and the
+// kline candles closed while disconnected are fetched from the REST API and delivered
+// to their stream as closed klines before the live ones.
 func (b *BinanceClient) StartWebSocket(ctx context.Context, streams []string) error {
 	b.wsMutex.Lock()
 	defer b.wsMutex.Unlock()
@@ -281,13 +416,11 @@ func (b *BinanceClient) StartWebSocket(ctx context.Context, streams []string) er
 	if b.wsActive {
 		return fmt.Errorf("WebSocket connection is already active")
 	}
-
-	// Build stream URL
-	streamURL := b.wsBaseURL + "/stream?streams=" + fmt.Sprintf("%s", streams[0])
-	for _, stream := range streams[1:] {
-		streamURL += "/" + stream
+	if len(streams) == 0 {
+		return fmt.Errorf("no WebSocket streams to connect to")
 	}
 
+	streamURL := b.streamURL(streams)
 	b.logger.WithField("url", streamURL).Info("Connecting to Binance WebSocket")
 
 	// Establish WebSocket connection
@@ -299,19 +432,38 @@ func (b *BinanceClient) StartWebSocket(ctx context.Context, streams []string) er
 	b.wsConn = conn
 	b.wsActive = true
 	b.wsReconnect = true
+	b.wsStreams = append([]string(nil), streams...)
+	b.wsStop = make(chan struct{})
+	b.wsLastClosed = make(map[string]int64)
 
 	// Start message handling goroutine
-	go b.handleWebSocketMessages(ctx)
+	go b.handleWebSocketMessages(ctx, conn, b.wsStop)
+	go func(stop chan struct{}) {
+		select {
+		case <-ctx.Done():
+			b.StopWebSocket()
+		case <-stop:
+		}
+	}(b.wsStop)
 
 	return nil
 }
 
+// streamURL is the combined stream URL of the streams
+func (b *BinanceClient) streamURL(streams []string) string {
+	return b.wsBaseURL + "/stream?streams=" + strings.Join(streams, "/")
+}
+
 // StopWebSocket stops the WebSocket connection
 func (b *BinanceClient) StopWebSocket() {
 	b.wsMutex.Lock()
 	defer b.wsMutex.Unlock()
 
 	b.wsReconnect = false
+	if b.wsStop != nil {
+		close(b.wsStop)
+		b.wsStop = nil
+	}
 	if b.wsConn != nil {
 		b.wsConn.Close()
 		b.wsConn = nil
@@ -344,59 +496,238 @@ func (b *BinanceClient) UnsubscribeFromStream(stream string) {
 	}
 }
 
-// handleWebSocketMessages handles incoming WebSocket messages
-func (b *BinanceClient) handleWebSocketMessages(ctx context.Context) {
+// handleWebSocketMessages routes the messages of the connection to the stream
+// channels, reconnecting whenever the connection drops until the WebSocket is stopped
+func (b *BinanceClient) handleWebSocketMessages(ctx context.Context, conn *websocket.Conn, stop chan struct{}) {
 	defer func() {
 		b.wsMutex.Lock()
-		b.wsActive = false
+		if b.wsStop == stop {
+			b.wsActive = false
+		}
 		b.wsMutex.Unlock()
 	}()
 
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			if b.wsConn == nil {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			b.wsMutex.RLock()
+			reconnect := b.wsReconnect && b.wsStop == stop
+			b.wsMutex.RUnlock()
+			if !reconnect || ctx.Err() != nil {
 				return
 			}
 
-			// Read message
-			_, message, err := b.wsConn.ReadMessage()
+			b.logger.WithError(err).Warn("Binance WebSocket connection lost, reconnecting")
+			conn, err = b.reconnect(ctx, stop)
 			if err != nil {
-				b.logger.WithError(err).Error("Failed to read WebSocket message")
-
-				// Attempt reconnection if enabled
-				if b.wsReconnect {
-					b.logger.Info("Attempting to reconnect WebSocket")
-					time.Sleep(b.retryInterval)
-					// Implement reconnection logic here if needed
-				}
 				return
 			}
+			b.backfillGaps(ctx)
+			continue
+		}
 
-			// Parse and route message
-			var wsMsg WebSocketMessage
-			if err := json.Unmarshal(message, &wsMsg); err != nil {
-				b.logger.WithError(err).Error("Failed to parse WebSocket message")
-				continue
-			}
+		b.routeMessage(ctx, message)
+	}
+}
 
-			// Route message to appropriate channel
-			b.wsMutex.RLock()
-			if ch, exists := b.wsChannels[wsMsg.Stream]; exists {
-				select {
-				case ch <- message:
-				default:
-					// Channel is full, drop message
-					b.logger.Warn("WebSocket channel is full, dropping message")
-				}
+// reconnect dials the streams again, waiting twice as long after each failed
+// attempt, until it connects or the WebSocket is stopped
+func (b *BinanceClient) reconnect(ctx context.Context, stop chan struct{}) (*websocket.Conn, error) {
+	b.wsMutex.RLock()
+	backoff, maxBackoff := b.wsMinBackoff, b.wsMaxBackoff
+	streamURL := b.streamURL(b.wsStreams)
+	b.wsMutex.RUnlock()
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-stop:
+			return nil, fmt.Errorf("WebSocket stopped")
+		case <-time.After(backoff):
+		}
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, streamURL, nil)
+		if err != nil {
+			b.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt,
+				"backoff": backoff,
+			}).Warn("Failed to reconnect Binance WebSocket")
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
 			}
-			b.wsMutex.RUnlock()
+			continue
+		}
+
+		b.wsMutex.Lock()
+		if !b.wsReconnect || b.wsStop != stop {
+			b.wsMutex.Unlock()
+			conn.Close()
+			return nil, fmt.Errorf("WebSocket stopped")
+		}
+		b.wsConn = conn
+		b.wsMutex.Unlock()
+
+		b.logger.WithField("attempt", attempt).Info("Binance WebSocket reconnected")
+		return conn, nil
+	}
+}
+
+// routeMessage delivers a message to its stream's channel. A closed kline opened
+// more than one interval after the last one seen on its stream first has the candles
+// in between backfilled.
+func (b *BinanceClient) routeMessage(ctx context.Context, message []byte) {
+	var wsMsg WebSocketMessage
+	if err := json.Unmarshal(message, &wsMsg); err != nil {
+		b.logger.WithError(err).Error("Failed to parse WebSocket message")
+		return
+	}
+
+	if strings.Contains(wsMsg.Stream, "@kline_") {
+		if kline, err := ParseKlineMessage(message); err == nil && kline.Kline.IsClosed {
+			b.backfillStream(ctx, wsMsg.Stream, kline.Kline.OpenTime)
+			b.recordClosedKline(wsMsg.Stream, kline.Kline.OpenTime)
+		}
+	}
+
+	b.deliver(wsMsg.Stream, message)
+}
+
+// deliver hands the message to the stream's channel, dropping it when the channel is full
+func (b *BinanceClient) deliver(stream string, message []byte) {
+	b.wsMutex.RLock()
+	defer b.wsMutex.RUnlock()
+
+	if ch, exists := b.wsChannels[stream]; exists {
+		select {
+		case ch <- message:
+		default:
+			// Channel is full, drop message
+			b.logger.Warn("WebSocket channel is full, dropping message")
+		}
+	}
+}
+
+func (b *BinanceClient) recordClosedKline(stream string, openTime int64) {
+	b.wsMutex.Lock()
+	defer b.wsMutex.Unlock()
+
+	if openTime > b.wsLastClosed[stream] {
+		b.wsLastClosed[stream] = openTime
+	}
+}
+
+// backfillGaps backfills the candles every kline stream missed while disconnected,
+// up to the candle still open
+func (b *BinanceClient) backfillGaps(ctx context.Context) {
+	b.wsMutex.RLock()
+	streams := make([]string, 0, len(b.wsLastClosed))
+	for stream := range b.wsLastClosed {
+		streams = append(streams, stream)
+	}
+	b.wsMutex.RUnlock()
+
+	now := b.clock.Now().UnixMilli()
+	for _, stream := range streams {
+		_, interval, _ := strings.Cut(stream, "@kline_")
+		if step := indicators.ParseTimeframe(interval); step > 0 {
+			b.backfillStream(ctx, stream, now-now%step)
 		}
 	}
 }
 
+// backfillStream fetches the candles of the kline stream opened after the last closed
+// one seen and before the given open time, and delivers them as closed klines. Nothing
+// is fetched before the stream's first closed kline, as there is no gap to measure.
+func (b *BinanceClient) backfillStream(ctx context.Context, stream string, before int64) {
+	symbol, interval, _ := strings.Cut(stream, "@kline_")
+	step := indicators.ParseTimeframe(interval)
+
+	b.wsMutex.RLock()
+	last, seen := b.wsLastClosed[stream]
+	b.wsMutex.RUnlock()
+	if !seen || step <= 0 || before <= last+step {
+		return
+	}
+
+	from := last + step
+	missed := (before - from) / step
+	if missed > maxWSBackfillCandles {
+		b.logger.WithFields(logrus.Fields{
+			"stream": stream,
+			"missed": missed,
+		}).Warn("Too many candles missed by the WebSocket, backfilling the latest only")
+		from = before - maxWSBackfillCandles*step
+	}
+	to := before - 1
+
+	symbol = strings.ToUpper(symbol)
+	klines, err := b.GetKlines(ctx, symbol, interval, maxWSBackfillCandles, &from, &to)
+	if err != nil {
+		b.logger.WithError(err).WithField("stream", stream).Error("Failed to backfill candles missed by the WebSocket")
+		return
+	}
+
+	delivered := 0
+	for _, kline := range klines {
+		message, openTime, ok := binanceKlineMessage(stream, symbol, interval, kline, b.clock.Now().UnixMilli())
+		if !ok || openTime < from || openTime >= before {
+			continue
+		}
+		b.deliver(stream, message)
+		b.recordClosedKline(stream, openTime)
+		delivered++
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"stream":     stream,
+		"backfilled": delivered,
+	}).Info("Backfilled candles missed by the WebSocket")
+}
+
+// binanceKlineMessage builds the combined stream message of a closed kline from a
+// kline in the REST API's array format, returning its open time
+func binanceKlineMessage(stream, symbol, interval string, kline []interface{}, eventTime int64) ([]byte, int64, bool) {
+	if len(kline) < 7 {
+		return nil, 0, false
+	}
+	openTime, ok := kline[0].(float64)
+	if !ok {
+		return nil, 0, false
+	}
+	closeTime, ok := kline[6].(float64)
+	if !ok {
+		return nil, 0, false
+	}
+	var values [5]string
+	for i := range values {
+		if values[i], ok = kline[i+1].(string); !ok {
+			return nil, 0, false
+		}
+	}
+
+	data := KlineWebSocketData{EventType: "kline", EventTime: eventTime, Symbol: symbol}
+	data.Kline.Interval = interval
+	data.Kline.OpenTime = int64(openTime)
+	data.Kline.CloseTime = int64(closeTime)
+	data.Kline.Symbol = symbol
+	data.Kline.Open = values[0]
+	data.Kline.High = values[1]
+	data.Kline.Low = values[2]
+	data.Kline.Close = values[3]
+	data.Kline.Volume = values[4]
+	data.Kline.IsClosed = true
+
+	message, err := json.Marshal(struct {
+		Stream string             `json:"stream"`
+		Data   KlineWebSocketData `json:"data"`
+	}{Stream: stream, Data: data})
+	if err != nil {
+		return nil, 0, false
+	}
+	return message, data.Kline.OpenTime, true
+}
+
 // GetTickerWebSocketStream returns the stream name for ticker data
 func (b *BinanceClient) GetTickerWebSocketStream(symbol string) string {
 	return fmt.Sprintf("%s@ticker", strings.ToLower(symbol))
@@ -407,6 +738,46 @@ func (b *BinanceClient) GetKlineWebSocketStream(symbol, interval string) string
 	return fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
 }
 
+// GetDepthWebSocketStream returns the stream name for the partial book depth of
+// the symbol's best levels (5, 10 or 20), updated every 100ms
+func (b *BinanceClient) GetDepthWebSocketStream(symbol string, levels int) string {
+	return fmt.Sprintf("%s@depth%d@100ms", strings.ToLower(symbol), levels)
+}
+
+// ParseDepthMessage decodes a combined stream message of a partial book depth
+// stream, as delivered by SubscribeToStream
+func ParseDepthMessage(message []byte) (*DepthWebSocketData, error) {
+	var envelope struct {
+		Stream string             `json:"stream"`
+		Data   DepthWebSocketData `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse depth message: %w", err)
+	}
+	symbol, _, ok := strings.Cut(envelope.Stream, "@depth")
+	if !ok || symbol == "" {
+		return nil, fmt.Errorf("unexpected depth stream %q", envelope.Stream)
+	}
+	envelope.Data.Symbol = strings.ToUpper(symbol)
+	return &envelope.Data, nil
+}
+
+// ParseKlineMessage decodes a combined stream message of a kline stream, as
+// delivered by SubscribeToStream
+func ParseKlineMessage(message []byte) (*KlineWebSocketData, error) {
+	var envelope struct {
+		Stream string             `json:"stream"`
+		Data   KlineWebSocketData `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse kline message: %w", err)
+	}
+	if envelope.Data.EventType != "kline" {
+		return nil, fmt.Errorf("unexpected event type %q on stream %s", envelope.Data.EventType, envelope.Stream)
+	}
+	return &envelope.Data, nil
+}
+
 // makeRequestWithRetry makes an HTTP request with retry logic
 func (b *BinanceClient) makeRequestWithRetry(ctx context.Context, method, endpoint string, params url.Values) (*http.Response, error) {
 	var lastErr error
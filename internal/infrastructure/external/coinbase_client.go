@@ -0,0 +1,612 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+const (
+	coinbaseBaseURL   = "https://api.exchange.coinbase.com"
+	coinbaseWSBaseURL = "wss://ws-feed.exchange.coinbase.com"
+	// coinbaseCandlesPerRequest is the most candles Coinbase returns per request
+	coinbaseCandlesPerRequest = 300
+	// Binance's defaults, which callers of GetKlines expect
+	coinbaseDefaultKlines = 500
+	coinbaseMaxKlines     = 1000
+)
+
+// coinbaseGranularities are the candle sizes Coinbase serves, in seconds, largest
+// first; other intervals are aggregated from the largest one dividing them
+var coinbaseGranularities = []int64{86400, 21600, 3600, 900, 300, 60}
+
+// coinbaseQuotes are the quote currencies Binance style symbols are split on, the
+// longer ones first so BTCUSDT isn't read as BTCU-SDT
+var coinbaseQuotes = []string{"USDT", "USDC", "USD", "EUR", "GBP", "DAI", "BTC", "ETH"}
+
+// CoinbaseClient serves market data from the public Coinbase Exchange API. Symbols
+// map to products by splitting the quote currency off (BTCUSDT is BTC-USDT), and
+// klines of the intervals Coinbase lacks, such as 4h, are aggregated from smaller
+// candles. Streams are built from the ticker channel, which reports every trade:
+// kline streams update their candle on each trade and report it closed when the
+// first trade of the next one arrives.
+type CoinbaseClient struct {
+	httpClient *http.Client
+	baseURL    string
+	wsBaseURL  string
+	logger     *logrus.Logger
+
+	// Public endpoints allow 10 requests per second
+	rateLimiter *rate.Limiter
+
+	// WebSocket
+	wsConn     *websocket.Conn
+	wsMutex    sync.RWMutex
+	wsChannels map[string]chan []byte
+	wsActive   bool
+	// candles holds the forming candle of each kline stream
+	candles map[string]*coinbaseCandle
+
+	// Fault injection for resilience testing; nil outside test environments
+	faults *faults.Injector
+}
+
+type coinbaseCandle struct {
+	openTime int64
+	open     float64
+	high     float64
+	low      float64
+	close    float64
+	volume   float64
+}
+
+// NewCoinbaseClient creates a new Coinbase Exchange API client
+func NewCoinbaseClient(cfg *config.CoinbaseConfig, logger *logrus.Logger) *CoinbaseClient {
+	baseURL := coinbaseBaseURL
+	wsBaseURL := coinbaseWSBaseURL
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	}
+	if cfg.WSBaseURL != "" {
+		wsBaseURL = strings.TrimRight(cfg.WSBaseURL, "/")
+	}
+
+	return &CoinbaseClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseURL:     baseURL,
+		wsBaseURL:   wsBaseURL,
+		logger:      logger,
+		rateLimiter: rate.NewLimiter(rate.Limit(10), 10),
+		wsChannels:  make(map[string]chan []byte),
+		candles:     make(map[string]*coinbaseCandle),
+	}
+}
+
+// Name returns the exchange name
+func (c *CoinbaseClient) Name() string {
+	return ExchangeCoinbase
+}
+
+// SetFaultInjector makes API requests fail or slow down according to the coinbase fault
+func (c *CoinbaseClient) SetFaultInjector(injector *faults.Injector) {
+	c.faults = injector
+}
+
+// CoinbaseProductID returns the Coinbase product of a Binance style symbol, such as
+// BTC-USDT for BTCUSDT
+func CoinbaseProductID(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range coinbaseQuotes {
+		if len(symbol) > len(quote) && strings.HasSuffix(symbol, quote) {
+			return symbol[:len(symbol)-len(quote)] + "-" + quote
+		}
+	}
+	return symbol
+}
+
+// coinbaseSymbol returns the Binance style symbol of a product
+func coinbaseSymbol(productID string) string {
+	return strings.ReplaceAll(productID, "-", "")
+}
+
+// GetTickerPrice gets the last trade price of a symbol
+func (c *CoinbaseClient) GetTickerPrice(ctx context.Context, symbol string) (*TickerPrice, error) {
+	requestedAt := time.Now()
+	resp, err := c.makeRequest(ctx, "/products/"+url.PathEscape(CoinbaseProductID(symbol))+"/ticker", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker price: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var ticker struct {
+		Price string    `json:"price"`
+		Time  time.Time `json:"time"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
+		return nil, fmt.Errorf("failed to decode ticker response: %w", err)
+	}
+
+	// The time of the last trade is when the price was set
+	eventTime := ticker.Time
+	if eventTime.IsZero() {
+		eventTime = requestedAt
+	}
+	return &TickerPrice{Symbol: strings.ToUpper(symbol), Price: ticker.Price, EventTime: eventTime}, nil
+}
+
+// GetKlines gets the klines of a symbol like Binance's endpoint does: up to limit
+// klines opened from startTime, or the latest ones before endTime (or now), oldest
+// first. Aggregated intervals are aligned to the Unix epoch.
+func (c *CoinbaseClient) GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error) {
+	step := indicators.ParseTimeframe(interval)
+	granularity := coinbaseGranularity(step)
+	if granularity == 0 {
+		return nil, fmt.Errorf("unsupported kline interval %q", interval)
+	}
+	if limit <= 0 {
+		limit = coinbaseDefaultKlines
+	}
+	if limit > coinbaseMaxKlines {
+		limit = coinbaseMaxKlines
+	}
+
+	var from, to int64
+	if startTime != nil {
+		from = (*startTime + step - 1) / step * step
+		to = from + int64(limit)*step - 1
+		if endTime != nil && *endTime < to {
+			to = *endTime
+		}
+	} else {
+		to = time.Now().UnixMilli()
+		if endTime != nil {
+			to = *endTime
+		}
+		from = to/step*step - int64(limit-1)*step
+	}
+	if to < from {
+		return [][]interface{}{}, nil
+	}
+
+	candles, err := c.getCandles(ctx, CoinbaseProductID(symbol), granularity, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]entities.PriceHistory, 0, len(candles))
+	for _, candle := range candles {
+		histories = append(histories, entities.PriceHistory{
+			Timestamp:  time.UnixMilli(candle.openTime),
+			OpenPrice:  candle.open,
+			HighPrice:  candle.high,
+			LowPrice:   candle.low,
+			ClosePrice: candle.close,
+			Volume:     candle.volume,
+		})
+	}
+	if granularity*1000 != step {
+		histories = indicators.ResampleCustom(histories, interval)
+	}
+	if len(histories) > limit {
+		if startTime != nil {
+			histories = histories[:limit]
+		} else {
+			histories = histories[len(histories)-limit:]
+		}
+	}
+
+	klines := make([][]interface{}, 0, len(histories))
+	for _, history := range histories {
+		klines = append(klines, coinbaseKline(coinbaseCandle{
+			openTime: history.Timestamp.UnixMilli(),
+			open:     history.OpenPrice,
+			high:     history.HighPrice,
+			low:      history.LowPrice,
+			close:    history.ClosePrice,
+			volume:   history.Volume,
+		}, step))
+	}
+	return klines, nil
+}
+
+// coinbaseGranularity returns the largest Coinbase candle size, in seconds, that
+// divides the interval, or 0 when none does
+func coinbaseGranularity(step int64) int64 {
+	if step <= 0 {
+		return 0
+	}
+	for _, granularity := range coinbaseGranularities {
+		if step%(granularity*1000) == 0 {
+			return granularity
+		}
+	}
+	return 0
+}
+
+// getCandles fetches the candles opened between from and to, in milliseconds, a
+// page at a time, oldest first
+func (c *CoinbaseClient) getCandles(ctx context.Context, productID string, granularity, from, to int64) ([]coinbaseCandle, error) {
+	byOpenTime := make(map[int64]coinbaseCandle)
+	page := granularity * 1000 * coinbaseCandlesPerRequest
+	for start := from / (granularity * 1000) * (granularity * 1000); start <= to; start += page {
+		end := start + page - granularity*1000
+		if end > to {
+			end = to
+		}
+
+		params := url.Values{}
+		params.Set("granularity", strconv.FormatInt(granularity, 10))
+		params.Set("start", time.UnixMilli(start).UTC().Format(time.RFC3339))
+		params.Set("end", time.UnixMilli(end).UTC().Format(time.RFC3339))
+		resp, err := c.makeRequest(ctx, "/products/"+url.PathEscape(productID)+"/candles", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get klines: %w", err)
+		}
+
+		// Each candle is [time in seconds, low, high, open, close, volume], newest first
+		var rows [][]float64
+		err = json.NewDecoder(resp.Body).Decode(&rows)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode klines response: %w", err)
+		}
+		for _, row := range rows {
+			if len(row) < 6 {
+				continue
+			}
+			openTime := int64(row[0]) * 1000
+			if openTime < from || openTime > to {
+				continue
+			}
+			byOpenTime[openTime] = coinbaseCandle{openTime: openTime, low: row[1], high: row[2], open: row[3], close: row[4], volume: row[5]}
+		}
+	}
+
+	candles := make([]coinbaseCandle, 0, len(byOpenTime))
+	for _, candle := range byOpenTime {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].openTime < candles[j].openTime })
+	return candles, nil
+}
+
+// coinbaseKline converts a candle to Binance's kline array
+func coinbaseKline(candle coinbaseCandle, step int64) []interface{} {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []interface{}{
+		float64(candle.openTime),
+		format(candle.open),
+		format(candle.high),
+		format(candle.low),
+		format(candle.close),
+		format(candle.volume),
+		float64(candle.openTime + step - 1),
+	}
+}
+
+// GetExchangeInfo lists the products, online ones reported as TRADING
+func (c *CoinbaseClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	resp, err := c.makeRequest(ctx, "/products", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var products []struct {
+		ID              string `json:"id"`
+		Status          string `json:"status"`
+		TradingDisabled bool   `json:"trading_disabled"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange info response: %w", err)
+	}
+
+	info := &ExchangeInfo{Timezone: "UTC", ServerTime: time.Now().UnixMilli()}
+	for _, product := range products {
+		status := "BREAK"
+		if product.Status == "online" && !product.TradingDisabled {
+			status = "TRADING"
+		}
+		info.Symbols = append(info.Symbols, struct {
+			Symbol string `json:"symbol"`
+			Status string `json:"status"`
+		}{Symbol: coinbaseSymbol(product.ID), Status: status})
+	}
+	return info, nil
+}
+
+// HealthCheck checks if the Coinbase API is accessible
+func (c *CoinbaseClient) HealthCheck(ctx context.Context) error {
+	resp, err := c.makeRequest(ctx, "/time", nil)
+	if err != nil {
+		return fmt.Errorf("coinbase API health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// makeRequest makes a GET request to the Coinbase API
+func (c *CoinbaseClient) makeRequest(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	fullURL := c.baseURL + endpoint
+	if params != nil {
+		fullURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Coinbase rejects requests without a user agent
+	req.Header.Set("User-Agent", "priceguard-api")
+	req.Header.Set("Accept", "application/json")
+
+	c.logger.WithFields(logrus.Fields{
+		"url":      fullURL,
+		"endpoint": endpoint,
+	}).Debug("Making Coinbase API request")
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+	if err := c.faults.Inject(ctx, faults.TargetCoinbase); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrUpstreamUnavailable, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", entities.ErrUpstreamUnavailable, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: API request failed with status %d: %s", entities.ErrUpstreamUnavailable, resp.StatusCode, string(body))
+		}
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+// StartWebSocket connects to the feed and subscribes to the ticker channel of the
+// products of the streams
+func (c *CoinbaseClient) StartWebSocket(ctx context.Context, streams []string) error {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if c.wsActive {
+		return fmt.Errorf("WebSocket connection is already active")
+	}
+
+	seen := make(map[string]bool)
+	var productIDs []string
+	for _, stream := range streams {
+		symbol, _, _ := strings.Cut(stream, "@")
+		if productID := CoinbaseProductID(symbol); !seen[productID] {
+			seen[productID] = true
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	c.logger.WithField("url", c.wsBaseURL).Info("Connecting to Coinbase WebSocket")
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsBaseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+	subscribe := map[string]interface{}{
+		"type":        "subscribe",
+		"product_ids": productIDs,
+		"channels":    []string{"ticker"},
+	}
+	if err := conn.WriteJSON(subscribe); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to subscribe to WebSocket channels: %w", err)
+	}
+
+	c.wsConn = conn
+	c.wsActive = true
+
+	go c.handleWebSocketMessages(ctx, conn)
+
+	return nil
+}
+
+// StopWebSocket stops the WebSocket connection
+func (c *CoinbaseClient) StopWebSocket() {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if c.wsConn != nil {
+		c.wsConn.Close()
+		c.wsConn = nil
+	}
+	c.wsActive = false
+	c.candles = make(map[string]*coinbaseCandle)
+
+	c.logger.Info("WebSocket connection stopped")
+}
+
+// SubscribeToStream subscribes to a specific stream and returns a channel for messages
+func (c *CoinbaseClient) SubscribeToStream(stream string) <-chan []byte {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if _, exists := c.wsChannels[stream]; !exists {
+		c.wsChannels[stream] = make(chan []byte, 100)
+	}
+
+	return c.wsChannels[stream]
+}
+
+// UnsubscribeFromStream unsubscribes from a specific stream
+func (c *CoinbaseClient) UnsubscribeFromStream(stream string) {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if ch, exists := c.wsChannels[stream]; exists {
+		close(ch)
+		delete(c.wsChannels, stream)
+	}
+}
+
+// GetTickerWebSocketStream returns the stream name for ticker data
+func (c *CoinbaseClient) GetTickerWebSocketStream(symbol string) string {
+	return fmt.Sprintf("%s@ticker", strings.ToLower(symbol))
+}
+
+// GetKlineWebSocketStream returns the stream name for kline data
+func (c *CoinbaseClient) GetKlineWebSocketStream(symbol, interval string) string {
+	return fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
+}
+
+// coinbaseTicker is a message of the ticker channel, sent for every trade
+type coinbaseTicker struct {
+	Type      string    `json:"type"`
+	ProductID string    `json:"product_id"`
+	Price     string    `json:"price"`
+	Open24h   string    `json:"open_24h"`
+	Volume24h string    `json:"volume_24h"`
+	LastSize  string    `json:"last_size"`
+	Time      time.Time `json:"time"`
+}
+
+// handleWebSocketMessages turns the ticker messages into the subscribed streams'
+func (c *CoinbaseClient) handleWebSocketMessages(ctx context.Context, conn *websocket.Conn) {
+	defer func() {
+		c.wsMutex.Lock()
+		if c.wsConn == conn {
+			c.wsActive = false
+		}
+		c.wsMutex.Unlock()
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to read WebSocket message")
+			return
+		}
+
+		var ticker coinbaseTicker
+		if err := json.Unmarshal(message, &ticker); err != nil {
+			c.logger.WithError(err).Error("Failed to parse WebSocket message")
+			continue
+		}
+		if ticker.Type != "ticker" {
+			continue
+		}
+		c.routeTicker(&ticker)
+	}
+}
+
+// routeTicker sends a trade to the ticker stream and the kline streams of its symbol
+func (c *CoinbaseClient) routeTicker(ticker *coinbaseTicker) {
+	price, err := strconv.ParseFloat(ticker.Price, 64)
+	if err != nil {
+		return
+	}
+	size, _ := strconv.ParseFloat(ticker.LastSize, 64)
+	symbol := coinbaseSymbol(ticker.ProductID)
+	eventTime := ticker.Time.UnixMilli()
+	prefix := strings.ToLower(symbol) + "@"
+
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	for stream, ch := range c.wsChannels {
+		if !strings.HasPrefix(stream, prefix) {
+			continue
+		}
+
+		var messages []interface{}
+		switch kind := strings.TrimPrefix(stream, prefix); {
+		case kind == "ticker":
+			data := TickerData{EventType: "24hrTicker", EventTime: eventTime, Symbol: symbol, Price: ticker.Price, Volume: ticker.Volume24h}
+			if open, err := strconv.ParseFloat(ticker.Open24h, 64); err == nil && open > 0 {
+				data.Change = strconv.FormatFloat((price-open)/open*100, 'f', 3, 64)
+			}
+			messages = append(messages, data)
+		case strings.HasPrefix(kind, "kline_"):
+			interval := strings.TrimPrefix(kind, "kline_")
+			step := indicators.ParseTimeframe(interval)
+			if step == 0 {
+				continue
+			}
+			openTime := eventTime / step * step
+
+			candle := c.candles[stream]
+			if candle != nil && openTime > candle.openTime {
+				messages = append(messages, coinbaseKlineData(symbol, interval, eventTime, step, *candle, true))
+				candle = nil
+			}
+			if candle == nil {
+				candle = &coinbaseCandle{openTime: openTime, open: price, high: price, low: price}
+				c.candles[stream] = candle
+			}
+			if openTime < candle.openTime {
+				// A late trade of a candle already reported closed
+				continue
+			}
+			candle.high = max(candle.high, price)
+			candle.low = min(candle.low, price)
+			candle.close = price
+			candle.volume += size
+			messages = append(messages, coinbaseKlineData(symbol, interval, eventTime, step, *candle, false))
+		}
+
+		for _, data := range messages {
+			payload, err := json.Marshal(WebSocketMessage{Stream: stream, Data: data})
+			if err != nil {
+				continue
+			}
+			select {
+			case ch <- payload:
+			default:
+				// Channel is full, drop message
+				c.logger.Warn("WebSocket channel is full, dropping message")
+			}
+		}
+	}
+}
+
+// coinbaseKlineData builds the kline stream data of a candle
+func coinbaseKlineData(symbol, interval string, eventTime, step int64, candle coinbaseCandle, closed bool) KlineWebSocketData {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	data := KlineWebSocketData{EventType: "kline", EventTime: eventTime, Symbol: symbol}
+	data.Kline.Interval = interval
+	data.Kline.OpenTime = candle.openTime
+	data.Kline.CloseTime = candle.openTime + step - 1
+	data.Kline.Symbol = symbol
+	data.Kline.Open = format(candle.open)
+	data.Kline.High = format(candle.high)
+	data.Kline.Low = format(candle.low)
+	data.Kline.Close = format(candle.close)
+	data.Kline.Volume = format(candle.volume)
+	data.Kline.IsClosed = closed
+	return data
+}
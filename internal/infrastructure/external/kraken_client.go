@@ -0,0 +1,775 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+)
+
+const (
+	krakenBaseURL   = "https://api.kraken.com"
+	krakenWSBaseURL = "wss://ws.kraken.com/v2"
+	// Binance's defaults, which callers of GetKlines expect
+	krakenDefaultKlines = 500
+	krakenMaxKlines     = 1000
+)
+
+// krakenIntervals are the candle sizes Kraken serves, in minutes, largest first;
+// other intervals are aggregated from the largest one dividing them
+var krakenIntervals = []int64{21600, 10080, 1440, 240, 60, 30, 15, 5, 1}
+
+// krakenQuotes are the quote currencies symbols are split on, the longer ones first
+// so BTCUSDT isn't read as BTCU-SDT
+var krakenQuotes = []string{"USDT", "USDC", "USD", "EUR", "GBP", "CAD", "JPY", "CHF", "AUD", "DAI", "BTC", "ETH"}
+
+// krakenAssets are the assets Kraken's REST API names differently
+var krakenAssets = map[string]string{"BTC": "XBT", "DOGE": "XDG"}
+
+// KrakenClient serves market data from the public Kraken API. Symbols map to Kraken
+// pairs by splitting the quote currency off and using Kraken's asset names (BTCUSDT
+// is XBTUSDT, BTC/USDT on the WebSocket). Kraken only serves the latest 720 candles
+// of each interval; klines of the intervals it lacks, such as 2h, are aggregated
+// from smaller candles. Ticker streams come from the ticker channel and kline
+// streams from the ohlc channel, a candle being reported closed when the next one
+// starts.
+type KrakenClient struct {
+	httpClient *http.Client
+	baseURL    string
+	wsBaseURL  string
+	logger     *logrus.Logger
+
+	// Public endpoints allow about one request per second
+	rateLimiter *rate.Limiter
+
+	// WebSocket
+	wsConn     *websocket.Conn
+	wsMutex    sync.RWMutex
+	wsChannels map[string]chan []byte
+	wsActive   bool
+	// candles holds the forming candle of each kline stream
+	candles map[string]*krakenCandle
+
+	// Fault injection for resilience testing; nil outside test environments
+	faults *faults.Injector
+}
+
+type krakenCandle struct {
+	openTime int64
+	open     float64
+	high     float64
+	low      float64
+	close    float64
+	volume   float64
+}
+
+// NewKrakenClient creates a new Kraken API client
+func NewKrakenClient(cfg *config.KrakenConfig, logger *logrus.Logger) *KrakenClient {
+	baseURL := krakenBaseURL
+	wsBaseURL := krakenWSBaseURL
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	}
+	if cfg.WSBaseURL != "" {
+		wsBaseURL = strings.TrimRight(cfg.WSBaseURL, "/")
+	}
+
+	return &KrakenClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseURL:     baseURL,
+		wsBaseURL:   wsBaseURL,
+		logger:      logger,
+		rateLimiter: rate.NewLimiter(rate.Limit(1), 5),
+		wsChannels:  make(map[string]chan []byte),
+		candles:     make(map[string]*krakenCandle),
+	}
+}
+
+// Name returns the exchange name
+func (c *KrakenClient) Name() string {
+	return ExchangeKraken
+}
+
+// SetFaultInjector makes API requests fail or slow down according to the kraken fault
+func (c *KrakenClient) SetFaultInjector(injector *faults.Injector) {
+	c.faults = injector
+}
+
+// splitKrakenSymbol splits a symbol such as BTCUSDT into its base and quote assets
+func splitKrakenSymbol(symbol string) (string, string) {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range krakenQuotes {
+		if len(symbol) > len(quote) && strings.HasSuffix(symbol, quote) {
+			return symbol[:len(symbol)-len(quote)], quote
+		}
+	}
+	return symbol, ""
+}
+
+// krakenAsset returns Kraken's REST name of an asset
+func krakenAsset(asset string) string {
+	if name, ok := krakenAssets[asset]; ok {
+		return name
+	}
+	return asset
+}
+
+// KrakenPair returns the Kraken pair of a symbol, such as XBTUSDT for BTCUSDT
+func KrakenPair(symbol string) string {
+	base, quote := splitKrakenSymbol(symbol)
+	return krakenAsset(base) + krakenAsset(quote)
+}
+
+// krakenWSSymbol returns the WebSocket symbol of a symbol, such as BTC/USDT for
+// BTCUSDT; the WebSocket uses the common asset names
+func krakenWSSymbol(symbol string) string {
+	base, quote := splitKrakenSymbol(symbol)
+	if quote == "" {
+		return base
+	}
+	return base + "/" + quote
+}
+
+// krakenSymbol returns the symbol of a Kraken pair written as base/quote, with
+// either Kraken's or the common asset names: XBT/USDT and BTC/USDT are BTCUSDT
+func krakenSymbol(pair string) string {
+	base, quote, _ := strings.Cut(strings.ToUpper(pair), "/")
+	for common, name := range krakenAssets {
+		if base == name {
+			base = common
+		}
+		if quote == name {
+			quote = common
+		}
+	}
+	return base + quote
+}
+
+// GetTickerPrice gets the last trade price of a symbol
+func (c *KrakenClient) GetTickerPrice(ctx context.Context, symbol string) (*TickerPrice, error) {
+	requestedAt := time.Now()
+	params := url.Values{}
+	params.Set("pair", KrakenPair(symbol))
+	result, err := c.makeRequest(ctx, "/0/public/Ticker", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker price: %w", err)
+	}
+
+	// Results are keyed by Kraken's canonical pair name, such as XXBTZUSD for XBTUSD
+	var tickers map[string]struct {
+		// LastTrade is the price and lot volume of the last trade
+		LastTrade []string `json:"c"`
+	}
+	if err := json.Unmarshal(result, &tickers); err != nil {
+		return nil, fmt.Errorf("failed to decode ticker response: %w", err)
+	}
+	for _, ticker := range tickers {
+		if len(ticker.LastTrade) == 0 {
+			break
+		}
+		// Kraken doesn't say when the last trade happened
+		return &TickerPrice{Symbol: strings.ToUpper(symbol), Price: ticker.LastTrade[0], EventTime: requestedAt}, nil
+	}
+	return nil, fmt.Errorf("no ticker returned for %s", symbol)
+}
+
+// GetKlines gets the klines of a symbol like Binance's endpoint does: up to limit
+// klines opened from startTime, or the latest ones before endTime (or now), oldest
+// first. Klines older than the latest 720 candles Kraken serves are left out, and
+// aggregated intervals are aligned to the Unix epoch.
+func (c *KrakenClient) GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error) {
+	step := indicators.ParseTimeframe(interval)
+	granularity := krakenInterval(step)
+	if granularity == 0 {
+		return nil, fmt.Errorf("unsupported kline interval %q", interval)
+	}
+	if limit <= 0 {
+		limit = krakenDefaultKlines
+	}
+	if limit > krakenMaxKlines {
+		limit = krakenMaxKlines
+	}
+
+	var from, to int64
+	if startTime != nil {
+		from = (*startTime + step - 1) / step * step
+		to = from + int64(limit)*step - 1
+		if endTime != nil && *endTime < to {
+			to = *endTime
+		}
+	} else {
+		to = time.Now().UnixMilli()
+		if endTime != nil {
+			to = *endTime
+		}
+		from = to/step*step - int64(limit-1)*step
+	}
+	if to < from {
+		return [][]interface{}{}, nil
+	}
+
+	candles, err := c.getCandles(ctx, KrakenPair(symbol), granularity, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]entities.PriceHistory, 0, len(candles))
+	for _, candle := range candles {
+		histories = append(histories, entities.PriceHistory{
+			Timestamp:  time.UnixMilli(candle.openTime),
+			OpenPrice:  candle.open,
+			HighPrice:  candle.high,
+			LowPrice:   candle.low,
+			ClosePrice: candle.close,
+			Volume:     candle.volume,
+		})
+	}
+	if granularity*60*1000 != step {
+		histories = indicators.ResampleCustom(histories, interval)
+	}
+	if len(histories) > limit {
+		if startTime != nil {
+			histories = histories[:limit]
+		} else {
+			histories = histories[len(histories)-limit:]
+		}
+	}
+
+	klines := make([][]interface{}, 0, len(histories))
+	for _, history := range histories {
+		klines = append(klines, krakenKline(krakenCandle{
+			openTime: history.Timestamp.UnixMilli(),
+			open:     history.OpenPrice,
+			high:     history.HighPrice,
+			low:      history.LowPrice,
+			close:    history.ClosePrice,
+			volume:   history.Volume,
+		}, step))
+	}
+	return klines, nil
+}
+
+// krakenInterval returns the largest Kraken candle size, in minutes, that divides
+// the interval, or 0 when none does
+func krakenInterval(step int64) int64 {
+	if step <= 0 {
+		return 0
+	}
+	for _, interval := range krakenIntervals {
+		if step%(interval*60*1000) == 0 {
+			return interval
+		}
+	}
+	return 0
+}
+
+// getCandles fetches the candles opened between from and to, in milliseconds,
+// oldest first. Kraken returns the candles since a time in one response, capped
+// to the latest 720.
+func (c *KrakenClient) getCandles(ctx context.Context, pair string, interval, from, to int64) ([]krakenCandle, error) {
+	params := url.Values{}
+	params.Set("pair", pair)
+	params.Set("interval", strconv.FormatInt(interval, 10))
+	// Candles opened after since are returned
+	params.Set("since", strconv.FormatInt(from/1000-1, 10))
+	result, err := c.makeRequest(ctx, "/0/public/OHLC", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	// Each candle is [time in seconds, open, high, low, close, vwap, volume, count]
+	// under the pair's canonical name, next to the "last" cursor
+	var rows map[string]json.RawMessage
+	if err := json.Unmarshal(result, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode klines response: %w", err)
+	}
+	var candles []krakenCandle
+	for key, raw := range rows {
+		if key == "last" {
+			continue
+		}
+		var entries [][]interface{}
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode klines response: %w", err)
+		}
+		for _, entry := range entries {
+			candle, ok := parseKrakenCandle(entry)
+			if !ok || candle.openTime < from || candle.openTime > to {
+				continue
+			}
+			candles = append(candles, candle)
+		}
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].openTime < candles[j].openTime })
+	return candles, nil
+}
+
+// parseKrakenCandle reads an OHLC entry
+func parseKrakenCandle(entry []interface{}) (krakenCandle, bool) {
+	if len(entry) < 7 {
+		return krakenCandle{}, false
+	}
+	openTime, ok := entry[0].(float64)
+	if !ok {
+		return krakenCandle{}, false
+	}
+
+	var values [5]float64
+	for i, index := range []int{1, 2, 3, 4, 6} {
+		text, _ := entry[index].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return krakenCandle{}, false
+		}
+		values[i] = value
+	}
+	return krakenCandle{
+		openTime: int64(openTime) * 1000,
+		open:     values[0],
+		high:     values[1],
+		low:      values[2],
+		close:    values[3],
+		volume:   values[4],
+	}, true
+}
+
+// krakenKline converts a candle to Binance's kline array
+func krakenKline(candle krakenCandle, step int64) []interface{} {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []interface{}{
+		float64(candle.openTime),
+		format(candle.open),
+		format(candle.high),
+		format(candle.low),
+		format(candle.close),
+		format(candle.volume),
+		float64(candle.openTime + step - 1),
+	}
+}
+
+// GetExchangeInfo lists the pairs, online ones reported as TRADING
+func (c *KrakenClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	result, err := c.makeRequest(ctx, "/0/public/AssetPairs", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	var pairs map[string]struct {
+		WSName string `json:"wsname"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(result, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange info response: %w", err)
+	}
+
+	info := &ExchangeInfo{Timezone: "UTC", ServerTime: time.Now().UnixMilli()}
+	for _, pair := range pairs {
+		if pair.WSName == "" {
+			continue
+		}
+		status := "BREAK"
+		if pair.Status == "online" {
+			status = "TRADING"
+		}
+		info.Symbols = append(info.Symbols, struct {
+			Symbol string `json:"symbol"`
+			Status string `json:"status"`
+		}{Symbol: krakenSymbol(pair.WSName), Status: status})
+	}
+	sort.Slice(info.Symbols, func(i, j int) bool { return info.Symbols[i].Symbol < info.Symbols[j].Symbol })
+	return info, nil
+}
+
+// HealthCheck checks if the Kraken API is accessible and not under maintenance
+func (c *KrakenClient) HealthCheck(ctx context.Context) error {
+	result, err := c.makeRequest(ctx, "/0/public/SystemStatus", nil)
+	if err != nil {
+		return fmt.Errorf("kraken API health check failed: %w", err)
+	}
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(result, &status); err != nil {
+		return fmt.Errorf("kraken API health check failed: %w", err)
+	}
+	if status.Status != "online" {
+		return fmt.Errorf("kraken API health check failed: system status is %s", status.Status)
+	}
+	return nil
+}
+
+// krakenResponse wraps every REST response; errors are reported in it with a 200
+type krakenResponse struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// makeRequest makes a GET request to the Kraken API and returns its result
+func (c *KrakenClient) makeRequest(ctx context.Context, endpoint string, params url.Values) (json.RawMessage, error) {
+	fullURL := c.baseURL + endpoint
+	if params != nil {
+		fullURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "priceguard-api")
+	req.Header.Set("Accept", "application/json")
+
+	c.logger.WithFields(logrus.Fields{
+		"url":      fullURL,
+		"endpoint": endpoint,
+	}).Debug("Making Kraken API request")
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+	if err := c.faults.Inject(ctx, faults.TargetKraken); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrUpstreamUnavailable, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", entities.ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: API request failed with status %d: %s", entities.ErrUpstreamUnavailable, resp.StatusCode, string(body))
+		}
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response krakenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(response.Error) > 0 {
+		message := strings.Join(response.Error, ", ")
+		// Rate limits and outages are reported as EAPI:Rate limit exceeded,
+		// EGeneral:Too many requests and EService:Unavailable
+		for _, code := range response.Error {
+			if strings.HasPrefix(code, "EService:") || strings.HasPrefix(code, "EAPI:Rate limit") || strings.HasPrefix(code, "EGeneral:Too many requests") {
+				return nil, fmt.Errorf("%w: API request failed: %s", entities.ErrUpstreamUnavailable, message)
+			}
+		}
+		return nil, fmt.Errorf("API request failed: %s", message)
+	}
+
+	return response.Result, nil
+}
+
+// StartWebSocket connects to the WebSocket and subscribes to the ticker channel of
+// the symbols of ticker streams and the ohlc channel of the kline streams
+func (c *KrakenClient) StartWebSocket(ctx context.Context, streams []string) error {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if c.wsActive {
+		return fmt.Errorf("WebSocket connection is already active")
+	}
+
+	seen := make(map[string]bool)
+	var tickerSymbols []string
+	ohlcSymbols := make(map[int64][]string)
+	var ohlcIntervals []int64
+	for _, stream := range streams {
+		symbol, kind, _ := strings.Cut(stream, "@")
+		wsSymbol := krakenWSSymbol(symbol)
+		if seen[wsSymbol+"@"+kind] {
+			continue
+		}
+		seen[wsSymbol+"@"+kind] = true
+
+		switch {
+		case kind == "ticker":
+			tickerSymbols = append(tickerSymbols, wsSymbol)
+		case strings.HasPrefix(kind, "kline_"):
+			step := indicators.ParseTimeframe(strings.TrimPrefix(kind, "kline_"))
+			minutes := step / (60 * 1000)
+			if step%(60*1000) != 0 || !krakenHasInterval(minutes) {
+				c.logger.WithField("stream", stream).Warn("Kraken doesn't stream this kline interval, skipping it")
+				continue
+			}
+			if _, exists := ohlcSymbols[minutes]; !exists {
+				ohlcIntervals = append(ohlcIntervals, minutes)
+			}
+			ohlcSymbols[minutes] = append(ohlcSymbols[minutes], wsSymbol)
+		}
+	}
+
+	c.logger.WithField("url", c.wsBaseURL).Info("Connecting to Kraken WebSocket")
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsBaseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+
+	var subscriptions []map[string]interface{}
+	if len(tickerSymbols) > 0 {
+		subscriptions = append(subscriptions, map[string]interface{}{"channel": "ticker", "symbol": tickerSymbols})
+	}
+	for _, minutes := range ohlcIntervals {
+		subscriptions = append(subscriptions, map[string]interface{}{"channel": "ohlc", "symbol": ohlcSymbols[minutes], "interval": minutes})
+	}
+	for _, params := range subscriptions {
+		if err := conn.WriteJSON(map[string]interface{}{"method": "subscribe", "params": params}); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to subscribe to WebSocket channels: %w", err)
+		}
+	}
+
+	c.wsConn = conn
+	c.wsActive = true
+
+	go c.handleWebSocketMessages(ctx, conn)
+
+	return nil
+}
+
+// krakenHasInterval reports whether Kraken serves candles of the given minutes
+func krakenHasInterval(minutes int64) bool {
+	for _, interval := range krakenIntervals {
+		if interval == minutes {
+			return true
+		}
+	}
+	return false
+}
+
+// StopWebSocket stops the WebSocket connection
+func (c *KrakenClient) StopWebSocket() {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if c.wsConn != nil {
+		c.wsConn.Close()
+		c.wsConn = nil
+	}
+	c.wsActive = false
+	c.candles = make(map[string]*krakenCandle)
+
+	c.logger.Info("WebSocket connection stopped")
+}
+
+// SubscribeToStream subscribes to a specific stream and returns a channel for messages
+func (c *KrakenClient) SubscribeToStream(stream string) <-chan []byte {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if _, exists := c.wsChannels[stream]; !exists {
+		c.wsChannels[stream] = make(chan []byte, 100)
+	}
+
+	return c.wsChannels[stream]
+}
+
+// UnsubscribeFromStream unsubscribes from a specific stream
+func (c *KrakenClient) UnsubscribeFromStream(stream string) {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if ch, exists := c.wsChannels[stream]; exists {
+		close(ch)
+		delete(c.wsChannels, stream)
+	}
+}
+
+// GetTickerWebSocketStream returns the stream name for ticker data
+func (c *KrakenClient) GetTickerWebSocketStream(symbol string) string {
+	return fmt.Sprintf("%s@ticker", strings.ToLower(symbol))
+}
+
+// GetKlineWebSocketStream returns the stream name for kline data
+func (c *KrakenClient) GetKlineWebSocketStream(symbol, interval string) string {
+	return fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
+}
+
+// krakenMessage is a message of a WebSocket channel
+type krakenMessage struct {
+	Channel string            `json:"channel"`
+	Data    []json.RawMessage `json:"data"`
+}
+
+// krakenTicker is an entry of the ticker channel
+type krakenTicker struct {
+	Symbol    string  `json:"symbol"`
+	Last      float64 `json:"last"`
+	Volume    float64 `json:"volume"`
+	ChangePct float64 `json:"change_pct"`
+}
+
+// krakenOHLC is an entry of the ohlc channel, sent as the candle changes
+type krakenOHLC struct {
+	Symbol        string    `json:"symbol"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	Close         float64   `json:"close"`
+	Volume        float64   `json:"volume"`
+	IntervalBegin time.Time `json:"interval_begin"`
+	Interval      int64     `json:"interval"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// handleWebSocketMessages turns the channel messages into the subscribed streams'
+func (c *KrakenClient) handleWebSocketMessages(ctx context.Context, conn *websocket.Conn) {
+	defer func() {
+		c.wsMutex.Lock()
+		if c.wsConn == conn {
+			c.wsActive = false
+		}
+		c.wsMutex.Unlock()
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to read WebSocket message")
+			return
+		}
+
+		var msg krakenMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			c.logger.WithError(err).Error("Failed to parse WebSocket message")
+			continue
+		}
+		// Subscription acknowledgements, heartbeats and status messages are skipped
+		for _, entry := range msg.Data {
+			switch msg.Channel {
+			case "ticker":
+				var ticker krakenTicker
+				if err := json.Unmarshal(entry, &ticker); err == nil {
+					c.routeTicker(&ticker)
+				}
+			case "ohlc":
+				var ohlc krakenOHLC
+				if err := json.Unmarshal(entry, &ohlc); err == nil {
+					c.routeOHLC(&ohlc)
+				}
+			}
+		}
+	}
+}
+
+// send delivers a stream message without blocking; the caller holds c.wsMutex
+func (c *KrakenClient) send(stream string, ch chan []byte, data interface{}) {
+	payload, err := json.Marshal(WebSocketMessage{Stream: stream, Data: data})
+	if err != nil {
+		return
+	}
+	select {
+	case ch <- payload:
+	default:
+		// Channel is full, drop message
+		c.logger.Warn("WebSocket channel is full, dropping message")
+	}
+}
+
+// routeTicker sends a ticker update to the ticker stream of its symbol
+func (c *KrakenClient) routeTicker(ticker *krakenTicker) {
+	symbol := krakenSymbol(ticker.Symbol)
+	stream := strings.ToLower(symbol) + "@ticker"
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	if ch, exists := c.wsChannels[stream]; exists {
+		c.send(stream, ch, TickerData{
+			EventType: "24hrTicker",
+			// Ticker updates don't carry a time
+			EventTime: time.Now().UnixMilli(),
+			Symbol:    symbol,
+			Price:     format(ticker.Last),
+			Change:    strconv.FormatFloat(ticker.ChangePct, 'f', 3, 64),
+			Volume:    format(ticker.Volume),
+		})
+	}
+}
+
+// routeOHLC sends a candle update to the kline streams of its symbol and interval,
+// reporting the previous candle closed when a new one starts
+func (c *KrakenClient) routeOHLC(ohlc *krakenOHLC) {
+	symbol := krakenSymbol(ohlc.Symbol)
+	step := ohlc.Interval * 60 * 1000
+	eventTime := ohlc.Timestamp.UnixMilli()
+	candle := krakenCandle{
+		openTime: ohlc.IntervalBegin.UnixMilli(),
+		open:     ohlc.Open,
+		high:     ohlc.High,
+		low:      ohlc.Low,
+		close:    ohlc.Close,
+		volume:   ohlc.Volume,
+	}
+	prefix := strings.ToLower(symbol) + "@kline_"
+
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+
+	for stream, ch := range c.wsChannels {
+		if !strings.HasPrefix(stream, prefix) {
+			continue
+		}
+		interval := strings.TrimPrefix(stream, prefix)
+		if indicators.ParseTimeframe(interval) != step {
+			continue
+		}
+
+		previous := c.candles[stream]
+		if previous != nil && candle.openTime < previous.openTime {
+			// A late update of a candle already reported closed
+			continue
+		}
+		if previous != nil && candle.openTime > previous.openTime {
+			c.send(stream, ch, krakenKlineData(symbol, interval, eventTime, step, *previous, true))
+		}
+		current := candle
+		c.candles[stream] = &current
+		c.send(stream, ch, krakenKlineData(symbol, interval, eventTime, step, candle, false))
+	}
+}
+
+// krakenKlineData builds the kline stream data of a candle
+func krakenKlineData(symbol, interval string, eventTime, step int64, candle krakenCandle, closed bool) KlineWebSocketData {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	data := KlineWebSocketData{EventType: "kline", EventTime: eventTime, Symbol: symbol}
+	data.Kline.Interval = interval
+	data.Kline.OpenTime = candle.openTime
+	data.Kline.CloseTime = candle.openTime + step - 1
+	data.Kline.Symbol = symbol
+	data.Kline.Open = format(candle.open)
+	data.Kline.High = format(candle.high)
+	data.Kline.Low = format(candle.low)
+	data.Kline.Close = format(candle.close)
+	data.Kline.Volume = format(candle.volume)
+	data.Kline.IsClosed = closed
+	return data
+}
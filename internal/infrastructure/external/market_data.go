@@ -0,0 +1,72 @@
+package external
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+	"github.com/sirupsen/logrus"
+)
+
+// Supported market data exchanges
+const (
+	ExchangeBinance  = "binance"
+	ExchangeCoinbase = "coinbase"
+	ExchangeKraken   = "kraken"
+)
+
+// MarketDataProvider is an exchange serving tickers, klines, the listed symbols and
+// live streams. Every implementation speaks Binance's formats: symbols such as
+// BTCUSDT, klines as arrays with the open time in milliseconds first, TRADING
+// symbol statuses and combined stream messages with <symbol>@ticker and
+// <symbol>@kline_<interval> stream names, so callers don't depend on the exchange.
+type MarketDataProvider interface {
+	// Name returns the exchange, such as binance
+	Name() string
+
+	GetTickerPrice(ctx context.Context, symbol string) (*TickerPrice, error)
+	GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error)
+	GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error)
+	HealthCheck(ctx context.Context) error
+
+	StartWebSocket(ctx context.Context, streams []string) error
+	StopWebSocket()
+	SubscribeToStream(stream string) <-chan []byte
+	UnsubscribeFromStream(stream string)
+	GetTickerWebSocketStream(symbol string) string
+	GetKlineWebSocketStream(symbol, interval string) string
+}
+
+var (
+	_ MarketDataProvider = (*BinanceClient)(nil)
+	_ MarketDataProvider = (*CoinbaseClient)(nil)
+	_ MarketDataProvider = (*KrakenClient)(nil)
+)
+
+// NewMarketDataProvider creates the client of the exchange selected by
+// MARKET_DATA_EXCHANGE; injector may be nil
+func NewMarketDataProvider(cfg *config.Config, injector *faults.Injector, logger *logrus.Logger) (MarketDataProvider, error) {
+	return NewExchangeProvider(cfg.MarketData.Exchange, cfg, injector, logger)
+}
+
+// NewExchangeProvider creates the client of the named exchange, binance when empty;
+// injector may be nil
+func NewExchangeProvider(exchange string, cfg *config.Config, injector *faults.Injector, logger *logrus.Logger) (MarketDataProvider, error) {
+	switch exchange {
+	case ExchangeBinance, "":
+		client := NewBinanceClient(&cfg.Binance, logger)
+		client.SetFaultInjector(injector)
+		return client, nil
+	case ExchangeCoinbase:
+		client := NewCoinbaseClient(&cfg.Coinbase, logger)
+		client.SetFaultInjector(injector)
+		return client, nil
+	case ExchangeKraken:
+		client := NewKrakenClient(&cfg.Kraken, logger)
+		client.SetFaultInjector(injector)
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unsupported market data exchange %q", exchange)
+	}
+}
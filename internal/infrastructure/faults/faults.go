@@ -0,0 +1,225 @@
+// Package faults injects errors and delays into calls to external dependencies, so
+// retries, circuit breakers and fallbacks can be exercised outside production.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+// Target is a dependency whose calls can fail on purpose
+type Target string
+
+const (
+	TargetBinance  Target = "binance"
+	TargetCoinbase Target = "coinbase"
+	TargetKraken   Target = "kraken"
+	TargetRedis    Target = "redis"
+	TargetPostgres Target = "postgres"
+)
+
+// Targets lists every dependency with a fault injection hook
+var Targets = []Target{TargetBinance, TargetCoinbase, TargetKraken, TargetRedis, TargetPostgres}
+
+var (
+	// ErrInjected is returned by calls failed on purpose
+	ErrInjected = errors.New("injected fault")
+	// ErrInvalidFault is returned for faults with an unknown target or no effect
+	ErrInvalidFault = errors.New("invalid fault")
+)
+
+// Fault makes Percent of the calls to Target wait Delay and then, if Error is set, fail
+type Fault struct {
+	Target    Target        `json:"target"`
+	Percent   float64       `json:"percent"`
+	Delay     time.Duration `json:"delay"`
+	Error     bool          `json:"error"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+}
+
+// Validate checks that the fault targets a known dependency and has an effect
+func (f Fault) Validate() error {
+	known := false
+	for _, target := range Targets {
+		if f.Target == target {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: unknown target %q", ErrInvalidFault, f.Target)
+	}
+	if f.Percent <= 0 || f.Percent > 100 {
+		return fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalidFault)
+	}
+	if f.Delay < 0 || (f.Delay == 0 && !f.Error) {
+		return fmt.Errorf("%w: a fault needs a delay or an error", ErrInvalidFault)
+	}
+	return nil
+}
+
+// Injector holds at most one fault per target. A nil *Injector is valid and never
+// injects anything, so hooks can be installed unconditionally.
+type Injector struct {
+	mutex  sync.Mutex
+	faults map[Target]Fault
+	clock  clock.Clock
+	roll   func() float64
+}
+
+// NewInjector creates an injector without faults
+func NewInjector() *Injector {
+	return &Injector{
+		faults: make(map[Target]Fault),
+		clock:  clock.New(),
+		roll:   rand.Float64,
+	}
+}
+
+// SetClock replaces the clock used to expire faults
+func (i *Injector) SetClock(c clock.Clock) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.clock = c
+}
+
+// SetRoll replaces the random source deciding which calls are affected; roll must
+// return values in [0, 1)
+func (i *Injector) SetRoll(roll func() float64) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.roll = roll
+}
+
+// Set installs a fault, replacing the one on the same target
+func (i *Injector) Set(fault Fault) error {
+	if err := fault.Validate(); err != nil {
+		return err
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.faults[fault.Target] = fault
+	return nil
+}
+
+// Clear removes the fault on target and reports whether there was one
+func (i *Injector) Clear(target Target) bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	_, found := i.faults[target]
+	delete(i.faults, target)
+	return found
+}
+
+// Faults returns the faults that haven't expired, by target
+func (i *Injector) Faults() []Fault {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	now := i.clock.Now()
+	faults := make([]Fault, 0, len(i.faults))
+	for target, fault := range i.faults {
+		if fault.ExpiresAt != nil && !now.Before(*fault.ExpiresAt) {
+			delete(i.faults, target)
+			continue
+		}
+		faults = append(faults, fault)
+	}
+	sort.Slice(faults, func(a, b int) bool { return faults[a].Target < faults[b].Target })
+	return faults
+}
+
+// Inject applies the fault on target to the current call: it waits the fault's delay,
+// or until ctx is done, and returns ErrInjected if the fault fails calls
+func (i *Injector) Inject(ctx context.Context, target Target) error {
+	if i == nil {
+		return nil
+	}
+
+	fault, affected := i.affects(target)
+	if !affected {
+		return nil
+	}
+
+	if fault.Delay > 0 {
+		timer := time.NewTimer(fault.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if fault.Error {
+		return fmt.Errorf("%w: %s", ErrInjected, target)
+	}
+	return nil
+}
+
+// affects returns the fault on target if the current call falls in its percentage
+func (i *Injector) affects(target Target) (Fault, bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	fault, found := i.faults[target]
+	if !found {
+		return Fault{}, false
+	}
+	if fault.ExpiresAt != nil && !i.clock.Now().Before(*fault.ExpiresAt) {
+		delete(i.faults, target)
+		return Fault{}, false
+	}
+	return fault, i.roll()*100 < fault.Percent
+}
+
+// ParseFaults reads faults from a comma-separated list of target:percent:effects,
+// where effects joins "error" and a delay with "+", e.g.
+// "binance:25:error,redis:10:200ms+error,postgres:5:1s"
+func ParseFaults(spec string) ([]Fault, error) {
+	var faults []Fault
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: %q is not target:percent:effects", ErrInvalidFault, entry)
+		}
+		percent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid percent in %q", ErrInvalidFault, entry)
+		}
+
+		fault := Fault{Target: Target(parts[0]), Percent: percent}
+		for _, effect := range strings.Split(parts[2], "+") {
+			if effect == "error" {
+				fault.Error = true
+				continue
+			}
+			delay, err := time.ParseDuration(effect)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid effect %q in %q", ErrInvalidFault, effect, entry)
+			}
+			fault.Delay = delay
+		}
+
+		if err := fault.Validate(); err != nil {
+			return nil, err
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
@@ -0,0 +1,83 @@
+package faults
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// RedisHook fails or delays Redis commands according to the redis fault
+func (i *Injector) RedisHook() redis.Hook {
+	return redisHook{injector: i}
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.Inject(ctx, TargetRedis); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		// A pipeline is one round trip, so it fails or is delayed as a whole
+		if err := h.injector.Inject(ctx, TargetRedis); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// GormPlugin fails or delays database statements according to the postgres fault;
+// register it with db.Use
+func (i *Injector) GormPlugin() gorm.Plugin {
+	return gormPlugin{injector: i}
+}
+
+type gormPlugin struct {
+	injector *Injector
+}
+
+func (p gormPlugin) Name() string {
+	return "faults"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	inject := func(tx *gorm.DB) {
+		if err := p.injector.Inject(tx.Statement.Context, TargetPostgres); err != nil {
+			// The gorm callbacks skip the statement once an error is set
+			tx.AddError(err)
+		}
+	}
+
+	callbacks := db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register("faults:create", inject),
+		callbacks.Query().Before("gorm:query").Register("faults:query", inject),
+		callbacks.Update().Before("gorm:update").Register("faults:update", inject),
+		callbacks.Delete().Before("gorm:delete").Register("faults:delete", inject),
+		callbacks.Row().Before("gorm:row").Register("faults:row", inject),
+		callbacks.Raw().Before("gorm:raw").Register("faults:raw", inject),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+const (
+	awsService     = "secretsmanager"
+	awsTarget      = "secretsmanager.GetSecretValue"
+	awsContentType = "application/x-amz-json-1.1"
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat  = "20060102T150405Z"
+	amzShortDate   = "20060102"
+)
+
+// AWSProvider reads secrets from one AWS Secrets Manager secret holding a JSON
+// object whose fields are named after the configuration keys
+type AWSProvider struct {
+	endpoint        *url.URL
+	region          string
+	secretID        string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	httpClient      *http.Client
+	clock           clock.Clock
+}
+
+// NewAWSProvider creates a provider for secretID. An empty endpoint uses the
+// regional Secrets Manager endpoint.
+func NewAWSProvider(region, secretID, accessKeyID, secretAccessKey, sessionToken, endpoint string) (*AWSProvider, error) {
+	if region == "" || secretID == "" {
+		return nil, fmt.Errorf("aws region and secret id are required")
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("aws credentials are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	parsed, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid secrets manager endpoint %q", endpoint)
+	}
+
+	return &AWSProvider{
+		endpoint:        parsed,
+		region:          region,
+		secretID:        secretID,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+		httpClient:      newHTTPClient(),
+		clock:           clock.New(),
+	}, nil
+}
+
+// SetHTTPClient replaces the HTTP client (e.g. for tests)
+func (p *AWSProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+// SetClock replaces the clock used to date signatures
+func (p *AWSProvider) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Name identifies the backend
+func (p *AWSProvider) Name() string {
+	return "aws-secrets-manager"
+}
+
+// Fetch reads the current version of the secret
+func (p *AWSProvider) Fetch(ctx context.Context, keys []string) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": p.secretID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint.String()+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", awsContentType)
+	req.Header.Set("X-Amz-Target", awsTarget)
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+	}
+	p.sign(req, body)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secrets manager read failed: %s", readBackendError(resp))
+	}
+
+	var payload struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets manager response: %w", err)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(payload.SecretString), &document); err != nil {
+		return nil, fmt.Errorf("secret %q is not a JSON object: %w", p.secretID, err)
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, found := document[key]; found && value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
+
+// sign adds the SigV4 Authorization header to req
+func (p *AWSProvider) sign(req *http.Request, body []byte) {
+	now := p.clock.Now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Header names are already sorted as SigV4 requires
+	signed := [][2]string{
+		{"content-type", awsContentType},
+		{"host", req.URL.Host},
+		{"x-amz-date", amzDate},
+	}
+	if p.sessionToken != "" {
+		signed = append(signed, [2]string{"x-amz-security-token", p.sessionToken})
+	}
+	signed = append(signed, [2]string{"x-amz-target", awsTarget})
+
+	var canonicalHeaders strings.Builder
+	names := make([]string, 0, len(signed))
+	for _, header := range signed {
+		canonicalHeaders.WriteString(header[0] + ":" + header[1] + "\n")
+		names = append(names, header[0])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(amzShortDate), p.region, awsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.secretAccessKey), now.Format(amzShortDate))
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, p.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
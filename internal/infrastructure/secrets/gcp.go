@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const gcpSecretManagerURL = "https://secretmanager.googleapis.com"
+
+// GCPProvider reads secrets from Google Cloud Secret Manager, one secret per
+// configuration key. JWT_SECRET is read from the secret <prefix>jwt-secret.
+type GCPProvider struct {
+	baseURL     string
+	project     string
+	prefix      string
+	tokenSource oauth2.TokenSource
+	httpClient  *http.Client
+}
+
+// NewGCPProvider creates a provider for project authenticated with the
+// application default credentials
+func NewGCPProvider(ctx context.Context, project, prefix string) (*GCPProvider, error) {
+	if project == "" {
+		return nil, fmt.Errorf("gcp project is required")
+	}
+
+	tokenSource, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find gcp credentials: %w", err)
+	}
+	return NewGCPProviderWithTokenSource(project, prefix, tokenSource), nil
+}
+
+// NewGCPProviderWithTokenSource creates a provider authenticated by tokenSource
+func NewGCPProviderWithTokenSource(project, prefix string, tokenSource oauth2.TokenSource) *GCPProvider {
+	return &GCPProvider{
+		baseURL:     gcpSecretManagerURL,
+		project:     project,
+		prefix:      prefix,
+		tokenSource: tokenSource,
+		httpClient:  newHTTPClient(),
+	}
+}
+
+// SetBaseURL points the provider at another endpoint (e.g. for tests)
+func (p *GCPProvider) SetBaseURL(baseURL string) {
+	p.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SetHTTPClient replaces the HTTP client (e.g. for tests)
+func (p *GCPProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+// Name identifies the backend
+func (p *GCPProvider) Name() string {
+	return "gcp-secret-manager"
+}
+
+// Fetch reads the latest version of every key's secret, skipping those that don't exist
+func (p *GCPProvider) Fetch(ctx context.Context, keys []string) (map[string]string, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gcp access token: %w", err)
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, found, err := p.access(ctx, token, key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// access reads the latest version of one secret
+func (p *GCPProvider) access(ctx context.Context, token *oauth2.Token, key string) (string, bool, error) {
+	target := fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/latest:access", p.baseURL, p.project, p.secretName(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", false, err
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("secret manager read of %s failed: %s", key, readBackendError(resp))
+	}
+
+	var payload struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", false, fmt.Errorf("failed to decode secret manager response: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload.Payload.Data)
+	if err != nil {
+		return "", false, fmt.Errorf("secret %s payload is not valid base64: %w", key, err)
+	}
+	return string(data), true, nil
+}
+
+// secretName maps a configuration key to a Secret Manager secret ID
+func (p *GCPProvider) secretName(key string) string {
+	return p.prefix + strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
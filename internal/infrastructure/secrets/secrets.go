@@ -0,0 +1,188 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+// secretsClientTimeout bounds a single request to a secrets backend
+const secretsClientTimeout = 10 * time.Second
+
+// Provider fetches secret values from an external secrets manager
+type Provider interface {
+	// Name identifies the backend in logs and errors
+	Name() string
+	// Fetch returns the values the backend holds for keys; keys it doesn't hold are left out
+	Fetch(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// RotationHook is called with the new value when a secret changes on refresh
+type RotationHook func(key, value string)
+
+// Manager caches the values of a fixed set of secrets, refreshes them when the
+// cache goes stale and notifies rotation hooks about changed values
+type Manager struct {
+	provider Provider
+	keys     []string
+	ttl      time.Duration
+	clock    clock.Clock
+
+	values    map[string]string
+	fetchedAt time.Time
+	hooks     map[string][]RotationHook
+	mutex     sync.RWMutex
+
+	// Scheduling control
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	runMutex  sync.Mutex
+}
+
+// NewManager creates a manager for keys, caching values for ttl
+func NewManager(provider Provider, ttl time.Duration, keys ...string) *Manager {
+	return &Manager{
+		provider: provider,
+		keys:     keys,
+		ttl:      ttl,
+		clock:    clock.New(),
+		values:   make(map[string]string),
+		hooks:    make(map[string][]RotationHook),
+	}
+}
+
+// SetClock replaces the clock used to expire the cache
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Provider returns the backend the manager reads from
+func (m *Manager) Provider() Provider {
+	return m.provider
+}
+
+// OnRotate registers a hook called whenever a refresh changes the value of key
+func (m *Manager) OnRotate(key string, hook RotationHook) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.hooks[key] = append(m.hooks[key], hook)
+}
+
+// Get returns the cached value of key, refreshing the cache first when it is stale
+func (m *Manager) Get(ctx context.Context, key string) (string, bool, error) {
+	m.mutex.RLock()
+	stale := m.fetchedAt.IsZero() || m.clock.Since(m.fetchedAt) >= m.ttl
+	m.mutex.RUnlock()
+
+	if stale {
+		if err := m.Refresh(ctx); err != nil {
+			return "", false, err
+		}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	value, found := m.values[key]
+	return value, found, nil
+}
+
+// Refresh fetches every key from the backend and calls the rotation hooks of
+// those whose value changed since the previous refresh
+func (m *Manager) Refresh(ctx context.Context) error {
+	values, err := m.provider.Fetch(ctx, m.keys)
+	if err != nil {
+		return fmt.Errorf("failed to fetch secrets from %s: %w", m.provider.Name(), err)
+	}
+
+	type rotation struct {
+		key   string
+		value string
+		hooks []RotationHook
+	}
+	var rotations []rotation
+
+	m.mutex.Lock()
+	initial := m.fetchedAt.IsZero()
+	for _, key := range m.keys {
+		value, found := values[key]
+		if !found || initial || m.values[key] == value {
+			continue
+		}
+		rotations = append(rotations, rotation{key: key, value: value, hooks: m.hooks[key]})
+	}
+	m.values = values
+	m.fetchedAt = m.clock.Now()
+	m.mutex.Unlock()
+
+	// Hooks run outside the lock so they may read other secrets
+	for _, r := range rotations {
+		for _, hook := range r.hooks {
+			hook(r.key, r.value)
+		}
+	}
+	return nil
+}
+
+// Start refreshes the secrets every interval until Stop is called, so rotations
+// reach the hooks without waiting for a Get
+func (m *Manager) Start(ctx context.Context, interval time.Duration, onError func(error)) {
+	m.runMutex.Lock()
+	defer m.runMutex.Unlock()
+
+	if m.isRunning {
+		return
+	}
+	m.isRunning = true
+	m.stopChan = make(chan struct{})
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopChan:
+				return
+			case <-ticker.C:
+				if err := m.Refresh(ctx); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop halts the refresh loop
+func (m *Manager) Stop() {
+	m.runMutex.Lock()
+	if !m.isRunning {
+		m.runMutex.Unlock()
+		return
+	}
+	m.isRunning = false
+	close(m.stopChan)
+	m.runMutex.Unlock()
+
+	m.wg.Wait()
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: secretsClientTimeout}
+}
+
+func readBackendError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
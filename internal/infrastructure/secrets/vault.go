@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider reads secrets from one HashiCorp Vault KV v2 entry whose fields
+// are named after the configuration keys (JWT_SECRET, DB_PASSWORD, ...)
+type VaultProvider struct {
+	addr       string
+	token      string
+	mount      string
+	path       string
+	httpClient *http.Client
+}
+
+// NewVaultProvider creates a provider for the KV v2 entry at mount/path
+func NewVaultProvider(addr, token, mount, path string) (*VaultProvider, error) {
+	if addr == "" || token == "" || path == "" {
+		return nil, fmt.Errorf("vault address, token and path are required")
+	}
+	if mount == "" {
+		mount = "secret"
+	}
+
+	return &VaultProvider{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		mount:      strings.Trim(mount, "/"),
+		path:       strings.Trim(path, "/"),
+		httpClient: newHTTPClient(),
+	}, nil
+}
+
+// SetHTTPClient replaces the HTTP client (e.g. for tests)
+func (p *VaultProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+// Name identifies the backend
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch reads the latest version of the entry
+func (p *VaultProvider) Fetch(ctx context.Context, keys []string) (map[string]string, error) {
+	target := fmt.Sprintf("%s/v1/%s/data/%s", p.addr, p.mount, p.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault read failed: %s", readBackendError(resp))
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, found := payload.Data.Data[key]; found && value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
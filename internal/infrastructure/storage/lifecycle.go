@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// LifecycleRule expires objects under Prefix once they are older than MaxAge
+type LifecycleRule struct {
+	Prefix string
+	MaxAge time.Duration
+}
+
+// LifecycleCleaner periodically deletes expired objects. Buckets that support
+// native lifecycle policies can use those instead; this keeps MinIO and other
+// S3-compatible stores without them from growing forever.
+type LifecycleCleaner struct {
+	storage ObjectStorage
+	rules   []LifecycleRule
+	logger  *logrus.Logger
+	clock   clock.Clock
+
+	mutex     sync.Mutex
+	isRunning bool
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewLifecycleCleaner creates a cleaner enforcing the given rules
+func NewLifecycleCleaner(storage ObjectStorage, logger *logrus.Logger, rules ...LifecycleRule) *LifecycleCleaner {
+	return &LifecycleCleaner{
+		storage: storage,
+		rules:   rules,
+		logger:  logger,
+		clock:   clock.New(),
+	}
+}
+
+// SetClock replaces the clock used to age objects (used by tests)
+func (lc *LifecycleCleaner) SetClock(c clock.Clock) {
+	lc.clock = c
+}
+
+// RunOnce applies every rule a single time and returns how many objects were deleted
+func (lc *LifecycleCleaner) RunOnce(ctx context.Context) (int, error) {
+	deleted := 0
+	var errs []error
+
+	for _, rule := range lc.rules {
+		objects, err := lc.storage.ListObjects(ctx, rule.Prefix)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		cutoff := lc.clock.Now().Add(-rule.MaxAge)
+		for _, object := range objects {
+			if !object.LastModified.Before(cutoff) {
+				continue
+			}
+			if err := lc.storage.DeleteObject(ctx, object.Key); err != nil && !errors.Is(err, ErrObjectNotFound) {
+				errs = append(errs, err)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		lc.logger.WithField("deleted", deleted).Info("Expired storage objects removed")
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// Start runs the cleaner every interval until Stop is called or ctx is cancelled
+func (lc *LifecycleCleaner) Start(ctx context.Context, interval time.Duration) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if lc.isRunning || len(lc.rules) == 0 {
+		return
+	}
+	lc.isRunning = true
+	lc.stopChan = make(chan struct{})
+
+	lc.wg.Add(1)
+	go func() {
+		defer lc.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-lc.stopChan:
+				return
+			case <-ticker.C:
+				if _, err := lc.RunOnce(ctx); err != nil {
+					lc.logger.WithError(err).Error("Storage lifecycle cleanup failed")
+				}
+			}
+		}
+	}()
+}
+
+// Stop halts the background loop and waits for it to exit
+func (lc *LifecycleCleaner) Stop() {
+	lc.mutex.Lock()
+	if !lc.isRunning {
+		lc.mutex.Unlock()
+		return
+	}
+	lc.isRunning = false
+	close(lc.stopChan)
+	lc.mutex.Unlock()
+
+	lc.wg.Wait()
+}
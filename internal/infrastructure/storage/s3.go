@@ -0,0 +1,397 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+const (
+	s3Service       = "s3"
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	amzShortDate    = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	s3ClientTimeout = 30 * time.Second
+)
+
+// S3Storage talks to any S3-compatible endpoint using AWS Signature Version 4
+type S3Storage struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	publicBaseURL   string
+	usePathStyle    bool
+	httpClient      *http.Client
+	clock           clock.Clock
+}
+
+// NewS3Storage creates an S3-compatible storage client from configuration
+func NewS3Storage(cfg *config.StorageConfig) (*S3Storage, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("storage endpoint and bucket are required")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid storage endpoint %q", cfg.Endpoint)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3Storage{
+		endpoint:        endpoint,
+		region:          region,
+		bucket:          cfg.Bucket,
+		accessKeyID:     cfg.AccessKeyID,
+		secretAccessKey: cfg.SecretAccessKey,
+		publicBaseURL:   strings.TrimRight(cfg.PublicBaseURL, "/"),
+		usePathStyle:    cfg.UsePathStyle,
+		httpClient:      &http.Client{Timeout: s3ClientTimeout},
+		clock:           clock.New(),
+	}, nil
+}
+
+// SetClock replaces the clock used to timestamp signatures (used by tests)
+func (s *S3Storage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetHTTPClient replaces the HTTP client used for requests
+func (s *S3Storage) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+}
+
+// PutObject uploads data under key
+func (s *S3Storage) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	headers := map[string]string{}
+	if contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, data, headers)
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put object %s: %s", key, readS3Error(resp))
+	}
+	return nil
+}
+
+// DeleteObject removes the object stored under key
+func (s *S3Storage) DeleteObject(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrObjectNotFound
+	default:
+		return fmt.Errorf("failed to delete object %s: %s", key, readS3Error(resp))
+	}
+}
+
+// PresignGetURL returns a query-signed GET URL valid for expiry
+func (s *S3Storage) PresignGetURL(key string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > MaxPresignExpiry {
+		return "", fmt.Errorf("presign expiry must be between 1s and %s", MaxPresignExpiry)
+	}
+
+	now := s.clock.Now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := s.scope(now)
+	host, path := s.bucketLocation()
+	canonicalURI := path + "/" + encodeKey(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		canonicalURI,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	signature := s.signature(now, canonicalRequest)
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", s.endpoint.Scheme, host, canonicalURI, canonicalQuery, signature), nil
+}
+
+// ListObjects pages through ListObjectsV2 and returns every object under prefix
+func (s *S3Storage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var (
+		objects           []ObjectInfo
+		continuationToken string
+	)
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		page, err := s.listPage(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+		}
+
+		for _, content := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          content.Key,
+				Size:         content.Size,
+				LastModified: content.LastModified,
+			})
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = page.NextContinuationToken
+	}
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response we consume
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+func (s *S3Storage) listPage(ctx context.Context, query url.Values) (*listBucketResult, error) {
+	resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", readS3Error(resp))
+	}
+
+	var result listBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid list response: %w", err)
+	}
+	return &result, nil
+}
+
+// PublicURL returns the URL clients use to fetch the object
+func (s *S3Storage) PublicURL(key string) string {
+	return s.baseURL() + "/" + encodeKey(key)
+}
+
+// KeyFromURL reverses PublicURL, reporting false for URLs outside this bucket
+func (s *S3Storage) KeyFromURL(rawURL string) (string, bool) {
+	prefix := s.baseURL() + "/"
+	if !strings.HasPrefix(rawURL, prefix) {
+		return "", false
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(rawURL, prefix))
+	if err != nil || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// baseURL is the public prefix for object URLs
+func (s *S3Storage) baseURL() string {
+	if s.publicBaseURL != "" {
+		return s.publicBaseURL
+	}
+	host, path := s.bucketLocation()
+	return s.endpoint.Scheme + "://" + host + path
+}
+
+// bucketLocation returns the host and path prefix addressing the bucket
+func (s *S3Storage) bucketLocation() (string, string) {
+	if s.usePathStyle {
+		return s.endpoint.Host, strings.TrimRight(s.endpoint.Path, "/") + "/" + s.bucket
+	}
+	return s.bucket + "." + s.endpoint.Host, strings.TrimRight(s.endpoint.Path, "/")
+}
+
+func (s *S3Storage) do(ctx context.Context, method, key string, query url.Values, body []byte, headers map[string]string) (*http.Response, error) {
+	host, path := s.bucketLocation()
+	canonicalURI := path + "/" + encodeKey(key)
+	canonicalQuery := canonicalQueryString(query)
+
+	target := s.endpoint.Scheme + "://" + host + canonicalURI
+	if canonicalQuery != "" {
+		target += "?" + canonicalQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	s.sign(req, canonicalURI, canonicalQuery, body)
+	return s.httpClient.Do(req)
+}
+
+// sign adds the SigV4 Authorization header to req
+func (s *S3Storage) sign(req *http.Request, canonicalURI, canonicalQuery string, body []byte) {
+	now := s.clock.Now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signed["content-type"] = contentType
+	}
+
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signed[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.accessKeyID, s.scope(now), signedHeaders, s.signature(now, canonicalRequest)))
+}
+
+// signature signs a canonical request with the derived SigV4 key
+func (s *S3Storage) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(amzDateFormat),
+		s.scope(now),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(s.signingKey(now.Format(amzShortDate)), stringToSign))
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return strings.Join([]string{now.Format(amzShortDate), s.region, s3Service, "aws4_request"}, "/")
+}
+
+func (s *S3Storage) signingKey(shortDate string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), shortDate)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s3Service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// encodeKey URI-encodes each path segment of an object key as SigV4 expects
+func encodeKey(key string) string {
+	return uriEncode(strings.TrimLeft(key, "/"), false)
+}
+
+// canonicalQueryString sorts and encodes query parameters for signing
+func canonicalQueryString(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode applies the RFC 3986 encoding SigV4 requires; '/' is kept in paths
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func readS3Error(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrObjectNotFound is returned when the requested object does not exist in the bucket
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStorage is the minimal contract the API needs from an object store
+type ObjectStorage interface {
+	// PutObject stores data under key, replacing any existing object
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	// DeleteObject removes the object stored under key
+	DeleteObject(ctx context.Context, key string) error
+	// PublicURL returns the URL clients use to fetch the object
+	PublicURL(key string) string
+	// KeyFromURL reverses PublicURL, reporting false for URLs this storage does not own
+	KeyFromURL(url string) (string, bool)
+	// PresignGetURL returns a time-limited download URL for a private object
+	PresignGetURL(key string, expiry time.Duration) (string, error)
+	// ListObjects returns every object whose key starts with prefix
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// MaxPresignExpiry is the longest validity S3 accepts for a presigned URL
+const MaxPresignExpiry = 7 * 24 * time.Hour
@@ -0,0 +1,27 @@
+package clock
+
+import "time"
+
+// Clock abstracts the current time so time-based logic can be tested deterministically
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+// Real is a Clock backed by the system time
+type Real struct{}
+
+// New returns the system clock
+func New() Clock {
+	return Real{}
+}
+
+// Now returns the current system time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t
+func (Real) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
@@ -0,0 +1,203 @@
+// Package locale formats numbers, percentages and prices the way a locale writes them.
+package locale
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Default is the locale used when none of the requested ones is supported
+const Default = "en-US"
+
+// format is how a locale writes numbers
+type format struct {
+	group   string // Thousands separator
+	decimal string
+	// currencyFirst places the currency symbol before the amount, currencySpace
+	// between them
+	currencyFirst bool
+	currencySpace string
+	percentSpace  string // Between the number and the percent sign
+}
+
+// nbsp keeps the number and its symbol on one line
+const nbsp = "\u00a0"
+
+var formats = map[string]format{
+	"en-US": {group: ",", decimal: ".", currencyFirst: true},
+	"en-GB": {group: ",", decimal: ".", currencyFirst: true},
+	"ja-JP": {group: ",", decimal: ".", currencyFirst: true},
+	"pt-BR": {group: ".", decimal: ",", currencyFirst: true, currencySpace: nbsp},
+	"pt-PT": {group: nbsp, decimal: ",", currencySpace: nbsp},
+	"es-ES": {group: ".", decimal: ",", currencySpace: nbsp, percentSpace: nbsp},
+	"de-DE": {group: ".", decimal: ",", currencySpace: nbsp, percentSpace: nbsp},
+	"it-IT": {group: ".", decimal: ",", currencySpace: nbsp},
+	"fr-FR": {group: "\u202f", decimal: ",", currencySpace: nbsp, percentSpace: "\u202f"},
+}
+
+// Supported lists the supported locales
+var Supported = []string{"en-US", "en-GB", "ja-JP", "pt-BR", "pt-PT", "es-ES", "de-DE", "it-IT", "fr-FR"}
+
+// currencySymbols are the symbols of the quote currencies written as money; the
+// dollar stablecoins are written as dollars. Other quotes, such as BTC, follow the
+// amount as their code.
+var currencySymbols = map[string]string{
+	"USD": "$", "USDT": "$", "USDC": "$", "BUSD": "$", "FDUSD": "$", "TUSD": "$", "DAI": "$",
+	"EUR": "€", "GBP": "£", "BRL": "R$", "JPY": "¥", "TRY": "₺",
+}
+
+// quoteAssets are the quote assets symbols end with, longest first so FDUSD isn't
+// read as USD
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "DAI", "USD", "EUR", "GBP", "BRL", "JPY", "TRY", "BTC", "ETH", "BNB"}
+
+// IsSupported reports whether tag is a supported locale
+func IsSupported(tag string) bool {
+	_, ok := formats[tag]
+	return ok
+}
+
+// Match returns the first supported locale of an Accept-Language header, by
+// preference. A language without a supported region, such as pt, matches the
+// first supported locale of that language.
+func Match(acceptLanguage string) (string, bool) {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, c := range candidates {
+		if c.quality <= bestQuality {
+			continue
+		}
+		if tag, ok := matchTag(c.tag); ok {
+			best, bestQuality = tag, c.quality
+		}
+	}
+	return best, best != ""
+}
+
+// matchTag matches a language tag, in any case, to a supported locale
+func matchTag(tag string) (string, bool) {
+	language, region, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	language = strings.ToLower(language)
+	if region != "" {
+		if exact := language + "-" + strings.ToUpper(region); IsSupported(exact) {
+			return exact, true
+		}
+	}
+	for _, supported := range Supported {
+		if strings.HasPrefix(supported, language+"-") {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+// Formatter writes numbers the way a locale does
+type Formatter struct {
+	tag    string
+	format format
+}
+
+// New returns the formatter of a supported locale, or of Default
+func New(tag string) *Formatter {
+	f, ok := formats[tag]
+	if !ok {
+		tag, f = Default, formats[Default]
+	}
+	return &Formatter{tag: tag, format: f}
+}
+
+// Locale returns the formatter's locale
+func (f *Formatter) Locale() string {
+	return f.tag
+}
+
+// Number writes a number with its thousands grouped and the given decimals
+func (f *Formatter) Number(value float64, decimals int) string {
+	digits := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	if value < 0 && strings.Trim(digits, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.format.group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(f.format.decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// Percent writes a percentage, such as 5.25 for 5.25%, with two decimals
+func (f *Formatter) Percent(value float64) string {
+	return f.Number(value, 2) + f.format.percentSpace + "%"
+}
+
+// Price writes a price of the symbol in its quote currency. Prices of a unit or
+// more have two decimals, smaller ones four significant digits up to eight decimals.
+// Symbols without a known quote asset are written as plain numbers.
+func (f *Formatter) Price(value float64, symbol string) string {
+	amount := f.Number(value, priceDecimals(value))
+
+	quote := QuoteAsset(symbol)
+	currency, ok := currencySymbols[quote]
+	switch {
+	case quote == "":
+		return amount
+	case !ok:
+		return amount + nbsp + quote
+	case f.format.currencyFirst:
+		if strings.HasPrefix(amount, "-") {
+			return "-" + currency + f.format.currencySpace + amount[1:]
+		}
+		return currency + f.format.currencySpace + amount
+	default:
+		return amount + f.format.currencySpace + currency
+	}
+}
+
+// priceDecimals is how many decimals a price is written with
+func priceDecimals(value float64) int {
+	value = math.Abs(value)
+	if value >= 1 || value == 0 {
+		return 2
+	}
+	decimals := 3 - int(math.Floor(math.Log10(value)))
+	return min(max(decimals, 2), 8)
+}
+
+// QuoteAsset returns the asset a symbol such as BTCUSDT is priced in, or "" if
+// it doesn't end with a known one
+func QuoteAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range quoteAssets {
+		if len(symbol) > len(quote) && strings.HasSuffix(symbol, quote) {
+			return quote
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+// Package pdf renders simple text documents as PDF without external dependencies.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4 in points
+	pageHeight   = 842
+	margin       = 50
+	titleSize    = 16
+	bodySize     = 10
+	lineHeight   = 14
+	linesPerPage = (pageHeight - 2*margin - 2*lineHeight) / lineHeight
+)
+
+// RenderText lays out a title followed by lines of text on as many A4 pages as needed
+func RenderText(title string, lines []string) []byte {
+	pages := paginate(lines)
+
+	// Object layout: 1 catalog, 2 page tree, 3 regular font, 4 bold font,
+	// then a (page, content) pair per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, pageLines := range pages {
+		content := pageContent(title, pageLines, i == 0)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+func paginate(lines []string) [][]string {
+	if len(lines) == 0 {
+		return [][]string{nil}
+	}
+
+	var pages [][]string
+	for start := 0; start < len(lines); start += linesPerPage {
+		end := start + linesPerPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+		pages = append(pages, lines[start:end])
+	}
+	return pages
+}
+
+func pageContent(title string, lines []string, withTitle bool) string {
+	var content strings.Builder
+	y := pageHeight - margin
+
+	if withTitle {
+		fmt.Fprintf(&content, "BT /F2 %d Tf %d %d Td (%s) Tj ET\n", titleSize, margin, y, escape(title))
+		y -= 2 * lineHeight
+	}
+
+	for _, line := range lines {
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", bodySize, margin, y, escape(line))
+		y -= lineHeight
+	}
+	return strings.TrimRight(content.String(), "\n")
+}
+
+// escape makes text safe inside a PDF literal string; characters outside
+// Latin-1 are replaced since the standard fonts cannot render them
+func escape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r < 32:
+			escaped.WriteByte(' ')
+		case r < 128:
+			escaped.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&escaped, "\\%03o", r)
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,326 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// loadScenario describes the shape of an alert evaluation load run
+type loadScenario struct {
+	Users         int
+	AlertsPerUser int
+	Symbols       int
+	PriceUpdates  int
+	Workers       int
+}
+
+// loadReport summarises a load run
+type loadReport struct {
+	Evaluations    int
+	Triggered      int
+	Elapsed        time.Duration
+	EvalsPerSecond float64
+	P50            time.Duration
+	P99            time.Duration
+	QueueDepth     int64
+	QueueLag       time.Duration
+}
+
+func (r loadReport) String() string {
+	return fmt.Sprintf("evaluations=%d triggered=%d elapsed=%s evals/sec=%.0f p50=%s p99=%s queue_depth=%d queue_lag=%s",
+		r.Evaluations, r.Triggered, r.Elapsed, r.EvalsPerSecond, r.P50, r.P99, r.QueueDepth, r.QueueLag)
+}
+
+// scenarioFromEnv lets the load test be scaled up without code changes (LOADTEST_USERS, ...)
+func scenarioFromEnv() loadScenario {
+	envInt := func(key string, fallback int) int {
+		if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+			return value
+		}
+		return fallback
+	}
+
+	return loadScenario{
+		Users:         envInt("LOADTEST_USERS", 100),
+		AlertsPerUser: envInt("LOADTEST_ALERTS_PER_USER", 10),
+		Symbols:       envInt("LOADTEST_SYMBOLS", 20),
+		PriceUpdates:  envInt("LOADTEST_PRICE_UPDATES", 10),
+		Workers:       envInt("LOADTEST_WORKERS", 8),
+	}
+}
+
+// loadPriceStore serves the latest candle per symbol and accepts streaming updates.
+// Unused repository methods fall through to the embedded nil interface.
+type loadPriceStore struct {
+	repositories.PriceHistoryRepository
+	mu     sync.RWMutex
+	latest map[string]*entities.PriceHistory
+}
+
+func (s *loadPriceStore) GetLatest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	price, exists := s.latest[symbol+"|"+timeframe]
+	if !exists {
+		return nil, fmt.Errorf("no price for %s", symbol)
+	}
+	copied := *price
+	return &copied, nil
+}
+
+func (s *loadPriceStore) publish(symbol, timeframe string, close float64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latest[symbol+"|"+timeframe] = &entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  timeframe,
+		OpenPrice:  close,
+		HighPrice:  close,
+		LowPrice:   close,
+		ClosePrice: close,
+		Timestamp:  at,
+	}
+}
+
+type loadAlertStore struct {
+	repositories.AlertRepository
+	alerts []entities.Alert
+}
+
+func (s *loadAlertStore) GetEnabled(ctx context.Context) ([]entities.Alert, error) {
+	return s.alerts, nil
+}
+
+func (s *loadAlertStore) Update(ctx context.Context, alert *entities.Alert) error {
+	return nil
+}
+
+func (s *loadAlertStore) GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error) {
+	return nil, nil
+}
+
+type loadUserStore struct {
+	repositories.UserRepository
+}
+
+func (s *loadUserStore) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+type loadNotificationStore struct {
+	repositories.NotificationRepository
+	created int64
+}
+
+func (s *loadNotificationStore) Create(ctx context.Context, notification *entities.Notification) error {
+	atomic.AddInt64(&s.created, 1)
+	return nil
+}
+
+// runAlertLoad seeds users and alerts, streams price updates and evaluates every
+// alert after each update, queueing notifications for the ones that trigger.
+func runAlertLoad(tb testing.TB, scenario loadScenario) loadReport {
+	tb.Helper()
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(42))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	mr := miniredis.RunT(tb)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	symbols := make([]string, scenario.Symbols)
+	basePrices := make(map[string]float64, scenario.Symbols)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("SYM%dUSDT", i)
+		basePrices[symbols[i]] = 100 + float64(i)*10
+	}
+
+	priceStore := &loadPriceStore{latest: make(map[string]*entities.PriceHistory)}
+	for _, symbol := range symbols {
+		priceStore.publish(symbol, "1h", basePrices[symbol], fakeClock.Now())
+	}
+
+	alertStore := &loadAlertStore{}
+	for u := 0; u < scenario.Users; u++ {
+		userID := uuid.New()
+		for a := 0; a < scenario.AlertsPerUser; a++ {
+			symbol := symbols[rng.Intn(len(symbols))]
+			condition := "above"
+			if a%2 == 1 {
+				condition = "below"
+			}
+			alertStore.alerts = append(alertStore.alerts, entities.Alert{
+				ID:            uuid.New(),
+				UserID:        userID,
+				Symbol:        symbol,
+				AlertType:     "price",
+				ConditionType: condition,
+				TargetValue:   basePrices[symbol] * (0.98 + rng.Float64()*0.04),
+				Timeframe:     "1h",
+				Enabled:       true,
+				NotifyVia:     []string{"email"},
+			})
+		}
+	}
+
+	notificationStore := &loadNotificationStore{}
+	engine := services.NewAlertEngine(alertStore, priceStore, nil, notificationStore, nil, logger)
+	engine.SetClock(fakeClock)
+
+	notificationService := services.NewNotificationService(notificationStore, &loadUserStore{}, services.NewRedisClientWrapper(rdb), logger)
+	notificationService.SetClock(fakeClock)
+
+	var (
+		latencies []time.Duration
+		latencyMu sync.Mutex
+		triggered int64
+	)
+
+	start := time.Now()
+	for update := 0; update < scenario.PriceUpdates; update++ {
+		// Stream a new candle for every symbol, drifting up to ±2% from the base price
+		fakeClock.Advance(time.Minute)
+		for _, symbol := range symbols {
+			priceStore.publish(symbol, "1h", basePrices[symbol]*(0.98+rng.Float64()*0.04), fakeClock.Now())
+		}
+
+		alerts, err := alertStore.GetEnabled(ctx)
+		require.NoError(tb, err)
+
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < scenario.Workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				local := make([]time.Duration, 0, len(alerts)/scenario.Workers+1)
+				for i := range jobs {
+					alert := alerts[i]
+					began := time.Now()
+					result, err := engine.EvaluateAlert(ctx, &alert)
+					local = append(local, time.Since(began))
+
+					if err == nil && result != nil && result.ShouldTrigger {
+						atomic.AddInt64(&triggered, 1)
+						_ = notificationService.QueueAlertNotification(ctx, &alert, result.CurrentValue,
+							[]services.NotificationChannel{services.ChannelEmail})
+					}
+				}
+				latencyMu.Lock()
+				latencies = append(latencies, local...)
+				latencyMu.Unlock()
+			}()
+		}
+		for i := range alerts {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}
+	elapsed := time.Since(start)
+
+	report := loadReport{
+		Evaluations: len(latencies),
+		Triggered:   int(triggered),
+		Elapsed:     elapsed,
+	}
+	if elapsed > 0 {
+		report.EvalsPerSecond = float64(report.Evaluations) / elapsed.Seconds()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.P50 = percentile(latencies, 0.50)
+	report.P99 = percentile(latencies, 0.99)
+
+	report.QueueDepth, report.QueueLag = notificationQueueLag(ctx, tb, rdb, fakeClock.Now())
+	return report
+}
+
+// notificationQueueLag returns the queue depth and the age of the oldest queued notification
+func notificationQueueLag(ctx context.Context, tb testing.TB, rdb *redis.Client, now time.Time) (int64, time.Duration) {
+	depth, err := rdb.ZCard(ctx, "notification_queue").Result()
+	require.NoError(tb, err)
+
+	members, err := rdb.ZRange(ctx, "notification_queue", 0, -1).Result()
+	require.NoError(tb, err)
+
+	var lag time.Duration
+	for _, member := range members {
+		var queued services.QueuedNotification
+		if err := json.Unmarshal([]byte(member), &queued); err != nil {
+			continue
+		}
+		if age := now.Sub(queued.CreatedAt); age > lag {
+			lag = age
+		}
+	}
+
+	return depth, lag
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+// TestAlertEvaluationLoad runs the load scenario and logs throughput; scale it with LOADTEST_* env vars
+func TestAlertEvaluationLoad(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping load test in short mode")
+	}
+
+	scenario := scenarioFromEnv()
+	report := runAlertLoad(t, scenario)
+
+	t.Logf("alert evaluation load: users=%d alerts=%d symbols=%d updates=%d workers=%d",
+		scenario.Users, scenario.Users*scenario.AlertsPerUser, scenario.Symbols, scenario.PriceUpdates, scenario.Workers)
+	t.Log(report.String())
+
+	require.Equal(t, scenario.Users*scenario.AlertsPerUser*scenario.PriceUpdates, report.Evaluations)
+	require.Equal(t, int64(report.Triggered), report.QueueDepth)
+}
+
+// BenchmarkAlertEvaluationThroughput reports evaluations/sec and p99 latency as custom metrics
+func BenchmarkAlertEvaluationThroughput(b *testing.B) {
+	scenario := loadScenario{Users: 50, AlertsPerUser: 10, Symbols: 20, PriceUpdates: 1, Workers: 8}
+
+	var last loadReport
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		last = runAlertLoad(b, scenario)
+	}
+
+	b.ReportMetric(last.EvalsPerSecond, "evals/sec")
+	b.ReportMetric(float64(last.P99.Microseconds()), "p99_us")
+	b.ReportMetric(float64(last.QueueLag.Milliseconds()), "queue_lag_ms")
+}
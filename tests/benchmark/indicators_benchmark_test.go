@@ -0,0 +1,184 @@
+package benchmark
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+)
+
+const indicatorBenchCandles = 10000
+
+func benchCandles() ([]float64, []indicators.PriceData) {
+	rng := rand.New(rand.NewSource(42))
+	closes := make([]float64, indicatorBenchCandles)
+	candles := make([]indicators.PriceData, indicatorBenchCandles)
+	price := 30000.0
+	for i := range closes {
+		price += rng.NormFloat64() * 50
+		closes[i] = price
+		candles[i] = indicators.PriceData{
+			Open:  price - rng.Float64()*20,
+			High:  price + rng.Float64()*40,
+			Low:   price - rng.Float64()*40,
+			Close: price,
+		}
+	}
+	return closes, candles
+}
+
+// naiveRSI is the previous implementation, which collected every gain and loss
+// into growing slices before averaging them
+func naiveRSI(prices []float64, period int) float64 {
+	var gains, losses []float64
+	for i := 1; i < len(prices); i++ {
+		change := prices[i] - prices[i-1]
+		if change > 0 {
+			gains = append(gains, change)
+			losses = append(losses, 0)
+		} else {
+			gains = append(gains, 0)
+			losses = append(losses, -change)
+		}
+	}
+	var avgGain, avgLoss float64
+	for i := 0; i < period; i++ {
+		avgGain += gains[i]
+		avgLoss += losses[i]
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+	for i := period; i < len(gains); i++ {
+		avgGain = ((avgGain * float64(period-1)) + gains[i]) / float64(period)
+		avgLoss = ((avgLoss * float64(period-1)) + losses[i]) / float64(period)
+	}
+	return 100 - (100 / (1 + avgGain/avgLoss))
+}
+
+// naiveATR is the previous implementation, which collected every true range first
+func naiveATR(priceData []indicators.PriceData, period int) float64 {
+	var trValues []float64
+	for i := 1; i < len(priceData); i++ {
+		tr := indicators.CalculateTrueRange(priceData[i], priceData[i-1])
+		trValues = append(trValues, tr.Value)
+	}
+	var sum float64
+	for i := 0; i < period; i++ {
+		sum += trValues[i]
+	}
+	atr := sum / float64(period)
+	for i := period; i < len(trValues); i++ {
+		atr = ((atr * float64(period-1)) + trValues[i]) / float64(period)
+	}
+	return atr
+}
+
+var indicatorSink float64
+
+// BenchmarkRSI computes RSI 14 over 10k closes
+func BenchmarkRSI(b *testing.B) {
+	closes, _ := benchCandles()
+
+	b.Run("Naive", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			indicatorSink = naiveRSI(closes, 14)
+		}
+	})
+	b.Run("SinglePass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result, _ := indicators.CalculateRSI(closes, 14)
+			indicatorSink = result.Value
+		}
+	})
+	b.Run("Series", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]float64, 0, indicatorBenchCandles)
+		for i := 0; i < b.N; i++ {
+			buf, _ = indicators.RSISeries(buf, closes, 14)
+			indicatorSink = buf[len(buf)-1]
+		}
+	})
+}
+
+// BenchmarkEMA computes the EMA 26 at every one of 10k closes, as a chart does:
+// once by recomputing each prefix, once with the rolling series into a reused buffer
+func BenchmarkEMA(b *testing.B) {
+	closes, _ := benchCandles()
+
+	b.Run("PerCandle", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for end := 26; end <= len(closes); end++ {
+				result, _ := indicators.CalculateEMA(closes[:end], 26)
+				indicatorSink = result.Value
+			}
+		}
+	})
+	b.Run("Series", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]float64, 0, indicatorBenchCandles)
+		for i := 0; i < b.N; i++ {
+			buf, _ = indicators.EMASeries(buf, closes, 26)
+			indicatorSink = buf[len(buf)-1]
+		}
+	})
+}
+
+// BenchmarkSuperTrend computes SuperTrend (10, 3) over 10k candles; the ATR behind
+// it dominates the cost
+func BenchmarkSuperTrend(b *testing.B) {
+	_, candles := benchCandles()
+
+	b.Run("NaiveATR", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			indicatorSink = naiveATR(candles, 10)
+		}
+	})
+	b.Run("SinglePass", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result, _ := indicators.CalculateSuperTrend(candles, 10, 3)
+			indicatorSink = result.Value
+		}
+	})
+}
+
+// TestOptimizedIndicatorsMatchNaive guards the rewrite: the single-pass versions
+// must return exactly what the slice-based ones did
+func TestOptimizedIndicatorsMatchNaive(t *testing.T) {
+	closes, candles := benchCandles()
+
+	rsi, err := indicators.CalculateRSI(closes, 14)
+	if err != nil || rsi.Value != naiveRSI(closes, 14) {
+		t.Fatalf("RSI mismatch: %v vs %v (%v)", rsi, naiveRSI(closes, 14), err)
+	}
+
+	atr, err := indicators.CalculateATR(candles, 10)
+	if err != nil || atr != naiveATR(candles, 10) {
+		t.Fatalf("ATR mismatch: %v vs %v (%v)", atr, naiveATR(candles, 10), err)
+	}
+
+	rsiSeries, err := indicators.RSISeries(nil, closes, 14)
+	if err != nil || rsiSeries[len(rsiSeries)-1] != rsi.Value {
+		t.Fatalf("RSI series mismatch: %v vs %v (%v)", rsiSeries[len(rsiSeries)-1], rsi.Value, err)
+	}
+
+	ema, _ := indicators.CalculateEMA(closes, 26)
+	emaSeries, err := indicators.EMASeries(nil, closes, 26)
+	if err != nil || len(emaSeries) != len(closes)-25 || emaSeries[len(emaSeries)-1] != ema.Value {
+		t.Fatalf("EMA series mismatch: %v vs %v (%v)", emaSeries[len(emaSeries)-1], ema.Value, err)
+	}
+
+	sma, _ := indicators.CalculateSMA(closes, 50)
+	smaSeries, err := indicators.SMASeries(emaSeries, closes, 50)
+	if err != nil || math.Abs(smaSeries[len(smaSeries)-1]-sma.Value) > 1e-6 {
+		t.Fatalf("SMA series mismatch: %v vs %v (%v)", smaSeries[len(smaSeries)-1], sma.Value, err)
+	}
+	if &smaSeries[0] != &emaSeries[0] {
+		t.Fatal("SMA series did not reuse the destination buffer")
+	}
+}
@@ -0,0 +1,75 @@
+package benchmark
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkNotificationQueueBatch compares applying a batch's queue updates one
+// round trip at a time against sending them in pipelines. Every batch claims its
+// notifications into the in-flight set, then mixes acknowledged deliveries,
+// retries put back on the queue and dead letters.
+func BenchmarkNotificationQueueBatch(b *testing.B) {
+	b.Run("Unpipelined", func(b *testing.B) {
+		benchmarkNotificationQueueBatch(b, false)
+	})
+	b.Run("Pipelined", func(b *testing.B) {
+		benchmarkNotificationQueueBatch(b, true)
+	})
+}
+
+func benchmarkNotificationQueueBatch(b *testing.B, pipelining bool) {
+	ctx := context.Background()
+	mr := miniredis.RunT(b)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	b.Cleanup(func() { client.Close() })
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.ErrorLevel)
+
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+	service.SetClock(clock)
+	service.SetPipelining(pipelining, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		mr.FlushAll()
+		for j := 0; j < 10; j++ {
+			notification := &services.QueuedNotification{
+				UserID:   uuid.New(),
+				Type:     "alert_triggered",
+				Title:    "Price Alert Triggered",
+				Message:  "BTCUSDT crossed 50000",
+				Channels: []services.NotificationChannel{services.ChannelInApp},
+			}
+			switch j % 3 {
+			case 1:
+				// Unsupported channel fails delivery and is rescheduled
+				notification.Channels = []services.NotificationChannel{"fax"}
+			case 2:
+				// Last attempt fails and goes to the dead letter queue
+				notification.Channels = []services.NotificationChannel{"fax"}
+				notification.Retries = 2
+			}
+			require.NoError(b, service.QueueNotification(ctx, notification))
+		}
+		b.StartTimer()
+
+		require.Equal(b, 10, service.ProcessBatch(ctx))
+	}
+}
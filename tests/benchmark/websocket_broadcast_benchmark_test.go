@@ -0,0 +1,88 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	broadcastBenchClients = 10000
+	broadcastBenchRooms   = 100
+)
+
+// BenchmarkHubBroadcast fans price updates out to 10k clients spread over 100
+// symbol rooms, first through a single broadcast worker (the old hub loop) and
+// then with the rooms sharded across several workers. Each iteration waits until
+// every subscriber of the room has received the message.
+func BenchmarkHubBroadcast(b *testing.B) {
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("Workers%d", workers), func(b *testing.B) {
+			benchmarkHubBroadcast(b, workers)
+		})
+	}
+}
+
+func benchmarkHubBroadcast(b *testing.B, workers int) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.ErrorLevel)
+
+	hub := websocket.NewHub(nil, logger)
+	hub.SetBroadcastWorkers(workers, 1024)
+	go hub.Start()
+	b.Cleanup(hub.Stop)
+
+	var delivered atomic.Int64
+	for i := 0; i < broadcastBenchClients; i++ {
+		client := &websocket.Client{
+			ID:     uuid.NewString(),
+			UserID: uuid.New(),
+			Hub:    hub,
+			Send:   make(chan []byte, 1024),
+			Rooms:  make(map[string]bool),
+		}
+		hub.Register(client)
+		<-client.Send // welcome
+
+		require.NoError(b, hub.JoinRoom(client, benchRoom(i%broadcastBenchRooms)))
+
+		go func(send <-chan []byte) {
+			for range send {
+				delivered.Add(1)
+			}
+		}(client.Send)
+	}
+
+	update := map[string]interface{}{"symbol": "BTCUSDT", "price": 50000.0}
+	perRoom := int64(broadcastBenchClients / broadcastBenchRooms)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hub.Broadcast(benchRoom(i%broadcastBenchRooms), "price_update", update)
+	}
+
+	expected := int64(b.N) * perRoom
+	deadline := time.Now().Add(time.Minute)
+	for delivered.Load() < expected {
+		if time.Now().After(deadline) {
+			b.Fatalf("delivered %d of %d messages", delivered.Load(), expected)
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(expected)/b.Elapsed().Seconds(), "msgs/s")
+}
+
+func benchRoom(i int) string {
+	return fmt.Sprintf("crypto:SYM%03d", i)
+}
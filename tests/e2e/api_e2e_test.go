@@ -0,0 +1,575 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	apihttp "github.com/growthfolio/go-priceguard-api/internal/adapters/http"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/database"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+const googleClientID = "e2e-client.apps.googleusercontent.com"
+
+// APIE2ETestSuite boots the full router, middlewares and background services
+// included, against SQLite, miniredis, a fake Binance and a fake Google tokeninfo
+// endpoint, and drives it over real HTTP and WebSocket connections
+type APIE2ETestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	db      *gorm.DB
+	binance *testutils.FakeBinance
+	google  *httptest.Server
+	wsm     *apihttp.WebSocketManager
+
+	defaultTransport http.RoundTripper
+	candleTime       time.Time
+}
+
+func TestAPIE2ETestSuite(t *testing.T) {
+	suite.Run(t, new(APIE2ETestSuite))
+}
+
+func (s *APIE2ETestSuite) SetupSuite() {
+	t := s.T()
+	gin.SetMode(gin.TestMode)
+
+	// Google's tokeninfo endpoint: an ID token "valid-<name>" identifies <name>
+	s.google = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutPrefix(r.URL.Query().Get("id_token"), "valid-")
+		if !ok {
+			http.Error(w, `{"error":"invalid_token"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"aud":            googleClientID,
+			"sub":            "google-" + name,
+			"email":          name + "@example.com",
+			"email_verified": "true",
+			"name":           name,
+		})
+	}))
+
+	// The OAuth service calls Google through the default transport
+	googleURL, _ := url.Parse(s.google.URL)
+	s.defaultTransport = http.DefaultTransport
+	http.DefaultTransport = &rewriteTransport{base: s.defaultTransport, host: "oauth2.googleapis.com", target: googleURL}
+
+	s.binance = testutils.NewFakeBinance()
+	mr := miniredis.RunT(t)
+
+	for key, value := range map[string]string{
+		"APP_ENV":              "test",
+		"JWT_SECRET":           "e2e-jwt-secret",
+		"GOOGLE_CLIENT_ID":     googleClientID,
+		"GOOGLE_CLIENT_SECRET": "e2e-client-secret",
+		"BINANCE_BASE_URL":     s.binance.URL(),
+		"BINANCE_WS_BASE_URL":  s.binance.WSURL(),
+	} {
+		t.Setenv(key, value)
+	}
+	cfg, err := config.LoadConfig()
+	s.Require().NoError(err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	s.db = testutils.OpenSQLite(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	router := gin.New()
+	s.wsm = apihttp.SetupRoutes(context.Background(), router, &apihttp.RouterDependencies{
+		Config:      cfg,
+		Logger:      logger,
+		DBManager:   database.NewManagerWithConnections(s.db, redisClient, logger),
+		RedisClient: redisClient,
+	})
+
+	// Prices are injected by the tests, not polled from Binance
+	s.wsm.Worker.Stop()
+
+	s.server = httptest.NewServer(router)
+	s.candleTime = time.Now().Truncate(time.Hour)
+}
+
+func (s *APIE2ETestSuite) TearDownSuite() {
+	s.server.Close()
+	s.wsm.App.Stop()
+	s.google.Close()
+	s.binance.Close()
+	http.DefaultTransport = s.defaultTransport
+}
+
+func (s *APIE2ETestSuite) TestLogin_IssuesTokensForProtectedRoutes() {
+	tokens := s.login("ada")
+
+	status, body := s.request(http.MethodGet, "/api/user/profile", tokens.AccessToken, nil)
+	s.Require().Equal(http.StatusOK, status)
+	var profile entities.User
+	s.Require().NoError(json.Unmarshal(body, &profile))
+	s.Equal("ada@example.com", profile.Email)
+	s.Equal("google-ada", profile.GoogleID)
+
+	// Logging in again finds the same user
+	again := s.login("ada")
+	status, body = s.request(http.MethodGet, "/api/user/profile", again.AccessToken, nil)
+	s.Require().Equal(http.StatusOK, status)
+	var sameProfile entities.User
+	s.Require().NoError(json.Unmarshal(body, &sameProfile))
+	s.Equal(profile.ID, sameProfile.ID)
+
+	status, _ = s.request(http.MethodGet, "/api/user/profile", "", nil)
+	s.Equal(http.StatusUnauthorized, status)
+	status, _ = s.request(http.MethodGet, "/api/user/profile", "not-a-token", nil)
+	s.Equal(http.StatusUnauthorized, status)
+
+	status, _ = s.request(http.MethodPost, "/api/auth/login", "", map[string]string{"id_token": "forged"})
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *APIE2ETestSuite) TestAlertLifecycle_TriggersNotificationAndBroadcast() {
+	tokens := s.login("grace")
+	ws := s.connectWebSocket(tokens.AccessToken)
+
+	status, body := s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, map[string]interface{}{
+		"symbol":         "BTCUSDT",
+		"alert_type":     "price",
+		"condition_type": "above",
+		"target_value":   50000,
+		"timeframe":      "1h",
+		"notify_via":     []string{"app"},
+	})
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var alert entities.Alert
+	s.Require().NoError(json.Unmarshal(body, &alert))
+
+	// Below the target: evaluated, nothing triggers
+	s.injectPrice("BTCUSDT", "1h", 49000)
+	evaluation := s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Equal(false, evaluation["should_trigger"])
+	s.Equal(49000.0, evaluation["current_value"])
+	s.Empty(s.notifications(tokens.AccessToken))
+
+	// Above the target on the next candle: triggers
+	s.injectPrice("BTCUSDT", "1h", 51000)
+	evaluation = s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Equal(true, evaluation["should_trigger"])
+
+	triggered := readMessage(s.T(), ws, "alert_triggered")
+	s.Equal(alert.ID.String(), triggered["alert_id"])
+	s.Equal("BTCUSDT", triggered["symbol"])
+	s.Equal(51000.0, triggered["current_value"])
+
+	notifications := s.notifications(tokens.AccessToken)
+	s.Require().Len(notifications, 1)
+	notification := notifications[0]
+	s.Equal(alert.ID.String(), notification["alert_id"])
+	s.Equal("alert_triggered", notification["notification_type"])
+	s.Nil(notification["read_at"])
+	// The WebSocket connection counts as delivery in the trigger record
+	s.Equal(true, notification["context"].(map[string]interface{})["websocket_delivered"])
+
+	status, body = s.request(http.MethodPost, "/api/notifications/mark-read", tokens.AccessToken, map[string]interface{}{
+		"notification_ids": []interface{}{notification["id"]},
+	})
+	s.Require().Equal(http.StatusOK, status, string(body))
+	s.NotNil(s.notifications(tokens.AccessToken)[0]["read_at"])
+
+	// Within the throttle window the alert isn't evaluated again
+	evaluation = s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Nil(evaluation)
+	s.Len(s.notifications(tokens.AccessToken), 1)
+}
+
+func (s *APIE2ETestSuite) TestAlertCooldown_SetThroughAPI() {
+	tokens := s.login("edsger")
+	create := map[string]interface{}{
+		"symbol":           "BNBUSDT",
+		"alert_type":       "price",
+		"condition_type":   "above",
+		"target_value":     600,
+		"timeframe":        "1h",
+		"cooldown_seconds": 30,
+	}
+
+	status, body := s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, create)
+	s.Require().Equal(http.StatusBadRequest, status, string(body))
+	s.Contains(string(body), "cooldown_seconds")
+
+	delete(create, "cooldown_seconds")
+	status, body = s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, create)
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var alert entities.Alert
+	s.Require().NoError(json.Unmarshal(body, &alert))
+	s.Equal(300, alert.CooldownSeconds)
+
+	status, body = s.request(http.MethodPut, "/api/alerts/"+alert.ID.String(), tokens.AccessToken, map[string]interface{}{
+		"cooldown_seconds": 3600,
+	})
+	s.Require().Equal(http.StatusOK, status, string(body))
+
+	var stored entities.Alert
+	s.Require().NoError(s.db.First(&stored, "id = ?", alert.ID).Error)
+	s.Equal(3600, stored.CooldownSeconds)
+}
+
+func (s *APIE2ETestSuite) TestAckRequiredAlert_AcknowledgedOverWebSocket() {
+	tokens := s.login("margaret")
+	ws := s.connectWebSocket(tokens.AccessToken)
+
+	status, body := s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, map[string]interface{}{
+		"symbol":               "XRPUSDT",
+		"alert_type":           "price",
+		"condition_type":       "below",
+		"target_value":         0.5,
+		"timeframe":            "1h",
+		"ack_required":         true,
+		"ack_interval_minutes": 5,
+	})
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var alert entities.Alert
+	s.Require().NoError(json.Unmarshal(body, &alert))
+	s.True(alert.AckRequired)
+
+	// Nothing to acknowledge before the alert triggers
+	status, _ = s.request(http.MethodPost, "/api/alerts/"+alert.ID.String()+"/acknowledge", tokens.AccessToken, nil)
+	s.Equal(http.StatusConflict, status)
+
+	s.injectPrice("XRPUSDT", "1h", 0.45)
+	evaluation := s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Require().Equal(true, evaluation["should_trigger"])
+
+	var stored entities.Alert
+	s.Require().NoError(s.db.First(&stored, "id = ?", alert.ID).Error)
+	s.True(stored.AwaitingAck())
+
+	// Another user can't acknowledge it
+	other := s.login("ada")
+	status, _ = s.request(http.MethodPost, "/api/alerts/"+alert.ID.String()+"/acknowledge", other.AccessToken, nil)
+	s.Equal(http.StatusNotFound, status)
+
+	s.Require().NoError(ws.WriteJSON(map[string]interface{}{
+		"id":   "ack-1",
+		"type": "ack_alert",
+		"data": map[string]interface{}{"alert_id": alert.ID.String()},
+	}))
+	reply := readMessage(s.T(), ws, "alert_acknowledged")
+	s.Equal(alert.ID.String(), reply["alert_id"])
+	s.NotNil(reply["acknowledged_at"])
+
+	var acknowledged entities.Alert
+	s.Require().NoError(s.db.First(&acknowledged, "id = ?", alert.ID).Error)
+	s.False(acknowledged.AwaitingAck())
+	s.NotNil(acknowledged.AcknowledgedAt)
+
+	status, _ = s.request(http.MethodPost, "/api/alerts/"+alert.ID.String()+"/acknowledge", tokens.AccessToken, nil)
+	s.Equal(http.StatusConflict, status)
+}
+
+func (s *APIE2ETestSuite) TestAlerts_AreScopedToTheirOwner() {
+	owner := s.login("alan")
+	other := s.login("barbara")
+
+	status, body := s.request(http.MethodPost, "/api/alerts", owner.AccessToken, map[string]interface{}{
+		"symbol":         "ETHUSDT",
+		"alert_type":     "price",
+		"condition_type": "below",
+		"target_value":   3000,
+		"timeframe":      "1h",
+	})
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var alert entities.Alert
+	s.Require().NoError(json.Unmarshal(body, &alert))
+	s.injectPrice("ETHUSDT", "1h", 2500)
+
+	status, _ = s.request(http.MethodPost, "/api/alerts/"+alert.ID.String()+"/evaluate", other.AccessToken, nil)
+	s.Equal(http.StatusForbidden, status)
+	status, _ = s.request(http.MethodDelete, "/api/alerts/"+alert.ID.String(), other.AccessToken, nil)
+	s.Equal(http.StatusForbidden, status)
+	s.Empty(s.notifications(other.AccessToken))
+
+	status, body = s.request(http.MethodGet, "/api/alerts", other.AccessToken, nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.NotContains(string(body), alert.ID.String())
+}
+
+func (s *APIE2ETestSuite) TestCompositeAlert_CombinesConditions() {
+	tokens := s.login("ada")
+
+	// A group needs at least two conditions
+	status, body := s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, map[string]interface{}{
+		"symbol":         "SOLUSDT",
+		"alert_type":     "composite",
+		"condition_type": "and",
+		"timeframe":      "1h",
+		"conditions":     []map[string]interface{}{{"alert_type": "price", "condition_type": "above", "target_value": 100}},
+	})
+	s.Equal(http.StatusBadRequest, status, string(body))
+
+	status, body = s.request(http.MethodPost, "/api/alerts", tokens.AccessToken, map[string]interface{}{
+		"symbol":         "SOLUSDT",
+		"alert_type":     "composite",
+		"condition_type": "and",
+		"timeframe":      "1h",
+		"conditions": []map[string]interface{}{
+			{"alert_type": "price", "condition_type": "above", "target_value": 100},
+			{"alert_type": "composite", "condition_type": "or", "conditions": []map[string]interface{}{
+				{"alert_type": "price", "condition_type": "below", "target_value": 120},
+				{"alert_type": "volume", "condition_type": "above", "target_value": 5000},
+			}},
+		},
+	})
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var alert entities.Alert
+	s.Require().NoError(json.Unmarshal(body, &alert))
+	s.Require().Len(alert.Conditions, 2)
+	s.Len(alert.Conditions[1].Conditions, 2)
+
+	// Above 100 but not below 120, with a quiet volume
+	s.injectPrice("SOLUSDT", "1h", 130)
+	evaluation := s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Equal(false, evaluation["should_trigger"])
+	s.Equal(1.0, evaluation["current_value"])
+
+	s.injectPrice("SOLUSDT", "1h", 110)
+	evaluation = s.evaluate(tokens.AccessToken, alert.ID.String())
+	s.Equal(true, evaluation["should_trigger"])
+	s.Contains(evaluation["message"], "2 of 3 conditions met for SOLUSDT")
+
+	notifications := s.notifications(tokens.AccessToken)
+	s.Require().Len(notifications, 1)
+	s.Len(notifications[0]["context"].(map[string]interface{})["conditions"], 3)
+}
+
+type authTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// login signs name in with a Google ID token the fake tokeninfo endpoint accepts
+func (s *APIE2ETestSuite) TestPublicTickers_AnonymousAndWithAPIKey() {
+	prices := repository.NewPriceHistoryRepository(s.db)
+	now := time.Now()
+	for i, price := range []float64{40000, 42000, 41000} {
+		s.Require().NoError(prices.Create(context.Background(), &entities.PriceHistory{
+			Symbol:     "BTCUSDT",
+			Timeframe:  "1m",
+			OpenPrice:  price,
+			HighPrice:  price,
+			LowPrice:   price,
+			ClosePrice: price,
+			Timestamp:  now.Add(time.Duration(i-3) * time.Hour),
+		}))
+	}
+
+	status, header, body := s.publicRequest("/api/public/v1/tickers/btcusdt", "")
+	s.Require().Equal(http.StatusOK, status, string(body))
+	s.Equal("public, max-age=15", header.Get("Cache-Control"))
+	s.Equal("10", header.Get("X-RateLimit-Limit"))
+	var ticker map[string]interface{}
+	s.Require().NoError(json.Unmarshal(body, &ticker))
+	s.Equal(41000.0, ticker["price"])
+	s.Equal(42000.0, ticker["high_24h"])
+
+	tokens := s.login("grace")
+	status, body = s.request(http.MethodPost, "/api/user/api-keys", tokens.AccessToken, map[string]string{"name": "Spreadsheet"})
+	s.Require().Equal(http.StatusCreated, status, string(body))
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	s.Require().NoError(json.Unmarshal(body, &created))
+	s.Require().NotEmpty(created.Key)
+
+	status, header, body = s.publicRequest("/api/public/v1/tickers?symbols=BTCUSDT,DOGEUSDT", created.Key)
+	s.Require().Equal(http.StatusOK, status, string(body))
+	s.Equal("60", header.Get("X-RateLimit-Limit"))
+	s.Equal("10000", header.Get("X-Quota-Limit"))
+	s.Equal("9999", header.Get("X-Quota-Remaining"))
+	s.Contains(string(body), `"count":1`)
+
+	// The key is never shown again
+	status, body = s.request(http.MethodGet, "/api/user/api-keys", tokens.AccessToken, nil)
+	s.Require().Equal(http.StatusOK, status, string(body))
+	s.NotContains(string(body), created.Key)
+	s.Contains(string(body), created.ID)
+
+	status, body = s.request(http.MethodDelete, "/api/user/api-keys/"+created.ID, tokens.AccessToken, nil)
+	s.Require().Equal(http.StatusOK, status, string(body))
+	status, _, _ = s.publicRequest("/api/public/v1/tickers/BTCUSDT", created.Key)
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *APIE2ETestSuite) login(name string) authTokens {
+	status, body := s.request(http.MethodPost, "/api/auth/login", "", map[string]string{"id_token": "valid-" + name})
+	s.Require().Equal(http.StatusOK, status, string(body))
+
+	var response struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Tokens authTokens `json:"tokens"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(body, &response))
+	s.Require().True(response.Success)
+	s.Require().NotEmpty(response.Data.Tokens.AccessToken)
+	return response.Data.Tokens
+}
+
+// request sends a JSON request, with the Origin header the CSRF protection wants on
+// mutating requests, and returns the status and body
+func (s *APIE2ETestSuite) request(method, path, accessToken string, payload interface{}) (int, []byte) {
+	s.T().Helper()
+
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		s.Require().NoError(err)
+		body = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, s.server.URL+path, body)
+	s.Require().NoError(err)
+	req.Header.Set("Origin", s.server.URL)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := s.server.Client().Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp.StatusCode, respBody
+}
+
+// publicRequest calls the public market data API, with apiKey when given, and
+// returns the status, headers and body
+func (s *APIE2ETestSuite) publicRequest(path, apiKey string) (int, http.Header, []byte) {
+	s.T().Helper()
+
+	req, err := http.NewRequest(http.MethodGet, s.server.URL+path, nil)
+	s.Require().NoError(err)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := s.server.Client().Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp.StatusCode, resp.Header, body
+}
+
+// injectPrice stores a candle closing at price, one hour after the previous one,
+// so it becomes the latest the alert engine reads
+func (s *APIE2ETestSuite) injectPrice(symbol, timeframe string, price float64) {
+	s.candleTime = s.candleTime.Add(time.Hour)
+	err := repository.NewPriceHistoryRepository(s.db).Create(context.Background(), &entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  timeframe,
+		OpenPrice:  price,
+		HighPrice:  price,
+		LowPrice:   price,
+		ClosePrice: price,
+		Volume:     1000,
+		Timestamp:  s.candleTime,
+	})
+	s.Require().NoError(err)
+}
+
+// evaluate evaluates the alert through the API; the result is nil while it is throttled
+func (s *APIE2ETestSuite) evaluate(accessToken, alertID string) map[string]interface{} {
+	status, body := s.request(http.MethodPost, "/api/alerts/"+alertID+"/evaluate", accessToken, nil)
+	s.Require().Equal(http.StatusOK, status, string(body))
+
+	var response struct {
+		Evaluation map[string]interface{} `json:"evaluation"`
+	}
+	s.Require().NoError(json.Unmarshal(body, &response))
+	return response.Evaluation
+}
+
+func (s *APIE2ETestSuite) notifications(accessToken string) []map[string]interface{} {
+	status, body := s.request(http.MethodGet, "/api/notifications", accessToken, nil)
+	s.Require().Equal(http.StatusOK, status, string(body))
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(body, &response))
+	return response.Data
+}
+
+// connectWebSocket opens an authenticated connection and waits for the welcome
+// message, so the client is registered before anything is broadcast to it
+func (s *APIE2ETestSuite) connectWebSocket(accessToken string) *gorillaws.Conn {
+	wsURL := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/ws?token=" + url.QueryEscape(accessToken)
+	conn, resp, err := gorillaws.DefaultDialer.Dial(wsURL, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.T().Cleanup(func() { conn.Close() })
+
+	readMessage(s.T(), conn, "welcome")
+	return conn
+}
+
+// readMessage skips messages until one of the given type arrives and returns its data
+func readMessage(t *testing.T, conn *gorillaws.Conn, messageType string) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for {
+		var message struct {
+			Type string                 `json:"type"`
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("no %s message: %v", messageType, err)
+		}
+		if message.Type == messageType {
+			return message.Data
+		}
+	}
+}
+
+// rewriteTransport sends the requests for host to target instead
+type rewriteTransport struct {
+	base   http.RoundTripper
+	host   string
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,138 @@
+package binance_integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BinanceIntegrationTestSuite struct {
+	suite.Suite
+	fake   *testutils.FakeBinance
+	client *external.BinanceClient
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+func (suite *BinanceIntegrationTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.logger = logrus.New()
+	suite.logger.SetOutput(io.Discard)
+
+	suite.fake = testutils.NewFakeBinance()
+	suite.client = external.NewBinanceClient(suite.fake.Config(), suite.logger)
+}
+
+func (suite *BinanceIntegrationTestSuite) TearDownTest() {
+	suite.client.StopWebSocket()
+	suite.fake.Close()
+}
+
+func (suite *BinanceIntegrationTestSuite) TestHealthCheck() {
+	suite.NoError(suite.client.HealthCheck(suite.ctx))
+	suite.Equal(1, suite.fake.RequestCount("/api/v3/ping"))
+}
+
+func (suite *BinanceIntegrationTestSuite) TestTickerPrice() {
+	suite.fake.SetPrice("SOLUSDT", 142.5)
+
+	ticker, err := suite.client.GetTickerPrice(suite.ctx, "SOLUSDT")
+	suite.Require().NoError(err)
+	suite.Equal("SOLUSDT", ticker.Symbol)
+	suite.Equal("142.50000000", ticker.Price)
+
+	tickers, err := suite.client.GetAllTickerPrices(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Len(tickers, 3)
+
+	_, err = suite.client.GetTickerPrice(suite.ctx, "UNKNOWN")
+	suite.Error(err)
+}
+
+func (suite *BinanceIntegrationTestSuite) TestKlinesAreDeterministic() {
+	first, err := suite.client.GetKlines(suite.ctx, "BTCUSDT", "1h", 50, nil, nil)
+	suite.Require().NoError(err)
+	suite.Len(first, 50)
+	// An hourly kline closes a millisecond before the next one opens
+	suite.Equal(first[0][0].(float64)+float64(time.Hour.Milliseconds())-1, first[0][6])
+	suite.Equal(first[1][0].(float64)-1, first[0][6])
+
+	second, err := suite.client.GetKlines(suite.ctx, "BTCUSDT", "1h", 50, nil, nil)
+	suite.Require().NoError(err)
+	suite.Equal(first, second)
+}
+
+func (suite *BinanceIntegrationTestSuite) TestInjectedFailure() {
+	suite.fake.FailNext("/api/v3/ticker/price", 1)
+
+	_, err := suite.client.GetTickerPrice(suite.ctx, "BTCUSDT")
+	suite.Error(err)
+
+	_, err = suite.client.GetTickerPrice(suite.ctx, "BTCUSDT")
+	suite.NoError(err)
+}
+
+func (suite *BinanceIntegrationTestSuite) TestTickerStream() {
+	stream := suite.client.GetTickerWebSocketStream("BTCUSDT")
+	messages := suite.client.SubscribeToStream(stream)
+
+	suite.Require().NoError(suite.client.StartWebSocket(suite.ctx, []string{stream}))
+	suite.Eventually(func() bool {
+		return suite.fake.ConnectedStreams(stream) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	suite.fake.PushTicker("BTCUSDT", 51000)
+
+	select {
+	case raw := <-messages:
+		var msg struct {
+			Stream string              `json:"stream"`
+			Data   external.TickerData `json:"data"`
+		}
+		suite.Require().NoError(json.Unmarshal(raw, &msg))
+		suite.Equal(stream, msg.Stream)
+		suite.Equal("BTCUSDT", msg.Data.Symbol)
+		suite.Equal("51000.00000000", msg.Data.Price)
+	case <-time.After(2 * time.Second):
+		suite.Fail("timed out waiting for ticker stream message")
+	}
+}
+
+func (suite *BinanceIntegrationTestSuite) TestCryptoDataServiceUsesFakeServer() {
+	priceHistoryRepo := new(testutils.MockPriceHistoryRepository)
+	service := services.NewCryptoDataService(suite.client, nil, priceHistoryRepo, nil, suite.logger)
+
+	price, err := service.GetCurrentPrice(suite.ctx, "ETHUSDT")
+	suite.Require().NoError(err)
+	suite.Equal(3000.0, price)
+
+	start := testutils.FakeBinanceKlineStart
+	suite.fake.SetKlines("ETHUSDT", "1h", [][]interface{}{
+		testutils.Kline(start, "1h", 3000, 3050, 2990, 3040, 10),
+		testutils.Kline(start.Add(time.Hour), "1h", 3040, 3100, 3020, 3090, 12),
+	})
+
+	priceHistoryRepo.On("BulkInsert", mock.Anything, mock.MatchedBy(func(histories []entities.PriceHistory) bool {
+		return len(histories) == 2 &&
+			histories[0].ClosePrice == 3040 &&
+			histories[1].ClosePrice == 3090 &&
+			histories[1].Timestamp.Equal(start.Add(time.Hour))
+	})).Return(nil)
+
+	suite.Require().NoError(service.CollectHistoricalData(suite.ctx, "ETHUSDT", "1h", 100))
+	priceHistoryRepo.AssertExpectations(suite.T())
+}
+
+func TestBinanceIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(BinanceIntegrationTestSuite))
+}
@@ -0,0 +1,37 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils/contract"
+)
+
+// TestPostgresRepositoriesContract runs the repository contracts against the GORM
+// repositories on the migrated schema
+func TestPostgresRepositoriesContract(t *testing.T) {
+	pg := testutils.StartPostgres(t)
+	defer pg.Close()
+
+	contract.RunAll(t, func(t *testing.T) *contract.Harness {
+		pg.Truncate(t, "technical_indicators", "price_history", "notifications", "alerts", "user_settings", "users")
+
+		return &contract.Harness{
+			Alerts:        repository.NewAlertRepository(pg.DB),
+			Notifications: repository.NewNotificationRepository(pg.DB),
+			PriceHistory:  repository.NewPriceHistoryRepository(pg.DB),
+			NewUser: func(t *testing.T) uuid.UUID {
+				id := uuid.New()
+				user := &entities.User{GoogleID: "google-" + id.String(), Email: id.String() + "@example.com", Name: "Contract User"}
+				if err := repository.NewUserRepository(pg.DB).Create(context.Background(), user); err != nil {
+					t.Fatalf("failed to create user: %v", err)
+				}
+				return user.ID
+			},
+		}
+	})
+}
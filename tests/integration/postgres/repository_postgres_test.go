@@ -0,0 +1,201 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+// PostgresRepositoryTestSuite runs the GORM repositories against a real Postgres
+// schema built from db/migrations, covering the column types SQLite cannot emulate.
+type PostgresRepositoryTestSuite struct {
+	suite.Suite
+	pg  *testutils.PostgresHarness
+	ctx context.Context
+}
+
+func (suite *PostgresRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	suite.pg = testutils.StartPostgres(suite.T())
+}
+
+func (suite *PostgresRepositoryTestSuite) TearDownSuite() {
+	if suite.pg != nil {
+		suite.pg.Close()
+	}
+}
+
+func (suite *PostgresRepositoryTestSuite) SetupTest() {
+	suite.pg.Truncate(suite.T(),
+		"sessions", "technical_indicators", "price_history",
+		"notifications", "alerts", "user_settings", "users",
+	)
+}
+
+func (suite *PostgresRepositoryTestSuite) createUser(email string) *entities.User {
+	user := &entities.User{
+		GoogleID: "google-" + email,
+		Email:    email,
+		Name:     "Test User",
+	}
+	suite.Require().NoError(repository.NewUserRepository(suite.pg.DB).Create(suite.ctx, user))
+	return user
+}
+
+func (suite *PostgresRepositoryTestSuite) TestUserDefaultsFromSchema() {
+	user := suite.createUser("defaults@example.com")
+	suite.NotEqual(uuid.Nil, user.ID)
+
+	found, err := repository.NewUserRepository(suite.pg.DB).GetByEmail(suite.ctx, "defaults@example.com")
+	suite.Require().NoError(err)
+	suite.Equal(user.ID, found.ID)
+}
+
+func (suite *PostgresRepositoryTestSuite) TestUserSettingsFavoriteSymbolsArray() {
+	user := suite.createUser("settings@example.com")
+	settingsRepo := repository.NewUserSettingsRepository(suite.pg.DB)
+
+	settings := &entities.UserSettings{
+		UserID:          user.ID,
+		Theme:           "dark",
+		FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT"},
+	}
+	suite.Require().NoError(settingsRepo.Create(suite.ctx, settings))
+
+	found, err := settingsRepo.GetByUserID(suite.ctx, user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(pq.StringArray{"BTCUSDT", "ETHUSDT"}, found.FavoriteSymbols)
+
+	found.FavoriteSymbols = append(found.FavoriteSymbols, "SOLUSDT")
+	suite.Require().NoError(settingsRepo.Update(suite.ctx, found))
+
+	updated, err := settingsRepo.GetByUserID(suite.ctx, user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(pq.StringArray{"BTCUSDT", "ETHUSDT", "SOLUSDT"}, updated.FavoriteSymbols)
+}
+
+func (suite *PostgresRepositoryTestSuite) TestAlertNotifyViaArray() {
+	user := suite.createUser("alerts@example.com")
+	alertRepo := repository.NewAlertRepository(suite.pg.DB)
+
+	alert := &entities.Alert{
+		UserID:        user.ID,
+		Symbol:        "BTCUSDT",
+		AlertType:     "price",
+		ConditionType: "above",
+		TargetValue:   50000,
+		Timeframe:     "1h",
+		Enabled:       true,
+		NotifyVia:     pq.StringArray{"app", "email"},
+	}
+	suite.Require().NoError(alertRepo.Create(suite.ctx, alert))
+
+	found, err := alertRepo.GetByID(suite.ctx, alert.ID)
+	suite.Require().NoError(err)
+	suite.Equal(pq.StringArray{"app", "email"}, found.NotifyVia)
+	suite.InDelta(50000, found.TargetValue, 0.00000001)
+
+	suite.Require().NoError(alertRepo.MarkTriggered(suite.ctx, alert.ID))
+	triggered, err := alertRepo.GetByID(suite.ctx, alert.ID)
+	suite.Require().NoError(err)
+	suite.NotNil(triggered.TriggeredAt)
+
+	enabled, err := alertRepo.GetEnabled(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Len(enabled, 1)
+}
+
+func (suite *PostgresRepositoryTestSuite) TestAlertCascadeOnUserDelete() {
+	user := suite.createUser("cascade@example.com")
+	alertRepo := repository.NewAlertRepository(suite.pg.DB)
+
+	alert := &entities.Alert{
+		UserID:        user.ID,
+		Symbol:        "ETHUSDT",
+		AlertType:     "rsi",
+		ConditionType: "below",
+		TargetValue:   30,
+		Timeframe:     "4h",
+		Enabled:       true,
+		NotifyVia:     pq.StringArray{"app"},
+	}
+	suite.Require().NoError(alertRepo.Create(suite.ctx, alert))
+
+	suite.Require().NoError(repository.NewUserRepository(suite.pg.DB).Delete(suite.ctx, user.ID))
+
+	_, err := alertRepo.GetByID(suite.ctx, alert.ID)
+	suite.Error(err)
+}
+
+func (suite *PostgresRepositoryTestSuite) TestTechnicalIndicatorMetadataJSONB() {
+	indicatorRepo := repository.NewTechnicalIndicatorRepository(suite.pg.DB)
+	value := 65.5
+	now := time.Now().UTC().Truncate(time.Second)
+
+	indicator := &entities.TechnicalIndicator{
+		Symbol:        "BTCUSDT",
+		Timeframe:     "1h",
+		IndicatorType: "RSI",
+		Value:         &value,
+		Metadata: map[string]interface{}{
+			"period": 14,
+			"source": "close",
+		},
+		Timestamp: now,
+	}
+	suite.Require().NoError(indicatorRepo.Create(suite.ctx, indicator))
+
+	found, err := indicatorRepo.GetLatest(suite.ctx, "BTCUSDT", "1h", "RSI")
+	suite.Require().NoError(err)
+	suite.Require().NotNil(found.Value)
+	suite.InDelta(65.5, *found.Value, 0.00000001)
+	suite.Equal(float64(14), found.Metadata["period"])
+	suite.Equal("close", found.Metadata["source"])
+
+	// The column must be real JSONB so metadata can be queried server-side
+	var count int64
+	err = suite.pg.DB.Model(&entities.TechnicalIndicator{}).
+		Where("metadata->>'period' = ?", "14").
+		Count(&count).Error
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *PostgresRepositoryTestSuite) TestPriceHistoryBulkInsertAndUniqueness() {
+	historyRepo := repository.NewPriceHistoryRepository(suite.pg.DB)
+	base := time.Now().UTC().Truncate(time.Hour)
+
+	histories := make([]entities.PriceHistory, 0, 3)
+	for i := 0; i < 3; i++ {
+		histories = append(histories, entities.PriceHistory{
+			Symbol:     "BTCUSDT",
+			Timeframe:  "1h",
+			OpenPrice:  100 + float64(i),
+			HighPrice:  110 + float64(i),
+			LowPrice:   90 + float64(i),
+			ClosePrice: 105 + float64(i),
+			Volume:     1000,
+			Timestamp:  base.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	suite.Require().NoError(historyRepo.BulkInsert(suite.ctx, histories))
+
+	latest, err := historyRepo.GetLatest(suite.ctx, "BTCUSDT", "1h")
+	suite.Require().NoError(err)
+	suite.InDelta(107, latest.ClosePrice, 0.00000001)
+
+	duplicate := histories[0]
+	duplicate.ID = 0
+	suite.Error(historyRepo.Create(suite.ctx, &duplicate), "unique(symbol, timeframe, timestamp) should reject duplicates")
+}
+
+func TestPostgresRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresRepositoryTestSuite))
+}
@@ -0,0 +1,242 @@
+package testutils
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// AlertBuilder builds valid alerts for tests. It starts from an enabled BTCUSDT
+// 1h alert notifying in-app, so a test only spells out what it is about:
+//
+//	alert := NewAlertBuilder().ForUser(userID).Price("BTCUSDT").Above(50000).Build()
+type AlertBuilder struct {
+	alert entities.Alert
+}
+
+// NewAlertBuilder starts an alert with a new ID and owner
+func NewAlertBuilder() *AlertBuilder {
+	return &AlertBuilder{alert: entities.Alert{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Symbol:    "BTCUSDT",
+		AlertType: "price",
+		Timeframe: "1h",
+		Enabled:   true,
+		NotifyVia: []string{"app"},
+		Priority:  "high",
+	}}
+}
+
+// WithID sets the alert ID
+func (b *AlertBuilder) WithID(id uuid.UUID) *AlertBuilder {
+	b.alert.ID = id
+	return b
+}
+
+// ForUser sets the alert owner
+func (b *AlertBuilder) ForUser(userID uuid.UUID) *AlertBuilder {
+	b.alert.UserID = userID
+	return b
+}
+
+// Price watches the close price of symbol
+func (b *AlertBuilder) Price(symbol string) *AlertBuilder {
+	return b.watch(symbol, "price")
+}
+
+// Percentage watches the 24h change of symbol
+func (b *AlertBuilder) Percentage(symbol string) *AlertBuilder {
+	return b.watch(symbol, "percentage")
+}
+
+// RSI watches the RSI(14) of symbol
+func (b *AlertBuilder) RSI(symbol string) *AlertBuilder {
+	return b.watch(symbol, "rsi")
+}
+
+// EMACross watches EMA(shortPeriod) crossing EMA(2*shortPeriod) on symbol
+func (b *AlertBuilder) EMACross(symbol string, shortPeriod int) *AlertBuilder {
+	b.alert.TargetValue = float64(shortPeriod)
+	return b.watch(symbol, "ema_cross")
+}
+
+// SMACross watches SMA(shortPeriod) crossing SMA(2*shortPeriod) on symbol
+func (b *AlertBuilder) SMACross(symbol string, shortPeriod int) *AlertBuilder {
+	b.alert.TargetValue = float64(shortPeriod)
+	return b.watch(symbol, "sma_cross")
+}
+
+// MACDCross watches the MACD line crossing its signal line on symbol
+func (b *AlertBuilder) MACDCross(symbol string) *AlertBuilder {
+	return b.watch(symbol, "macd_cross")
+}
+
+// Volume watches the candle volume of symbol
+func (b *AlertBuilder) Volume(symbol string) *AlertBuilder {
+	return b.watch(symbol, "volume")
+}
+
+// AllOf triggers when every condition is met on symbol
+func (b *AlertBuilder) AllOf(symbol string, conditions ...entities.AlertCondition) *AlertBuilder {
+	return b.composite(symbol, "and", conditions)
+}
+
+// AnyOf triggers when at least one condition is met on symbol
+func (b *AlertBuilder) AnyOf(symbol string, conditions ...entities.AlertCondition) *AlertBuilder {
+	return b.composite(symbol, "or", conditions)
+}
+
+// Condition is a leaf condition of a composite alert
+func Condition(alertType, conditionType string, target float64) entities.AlertCondition {
+	return entities.AlertCondition{AlertType: alertType, ConditionType: conditionType, TargetValue: target}
+}
+
+// Group nests conditions combined with operator, "and" or "or", in a composite alert
+func Group(operator string, conditions ...entities.AlertCondition) entities.AlertCondition {
+	return entities.AlertCondition{AlertType: entities.CompositeAlertType, ConditionType: operator, Conditions: conditions}
+}
+
+// Type watches symbol with any registered alert type, e.g. a custom indicator
+func (b *AlertBuilder) Type(symbol, alertType string) *AlertBuilder {
+	return b.watch(symbol, alertType)
+}
+
+// Above triggers when the watched value rises above target
+func (b *AlertBuilder) Above(target float64) *AlertBuilder {
+	return b.condition("above", target)
+}
+
+// Below triggers when the watched value falls below target
+func (b *AlertBuilder) Below(target float64) *AlertBuilder {
+	return b.condition("below", target)
+}
+
+// Up triggers on a 24h gain of at least target percent
+func (b *AlertBuilder) Up(target float64) *AlertBuilder {
+	return b.condition("up", target)
+}
+
+// Down triggers on a 24h loss of at least target percent
+func (b *AlertBuilder) Down(target float64) *AlertBuilder {
+	return b.condition("down", target)
+}
+
+// Spike triggers when the volume reaches multiple times its rolling average
+func (b *AlertBuilder) Spike(multiple float64) *AlertBuilder {
+	return b.condition("spike", multiple)
+}
+
+// CrossesUp triggers when the price closes above target after closing at or below it
+func (b *AlertBuilder) CrossesUp(target float64) *AlertBuilder {
+	return b.condition("crosses_up", target)
+}
+
+// CrossesDown triggers when the price closes below target after closing at or above it
+func (b *AlertBuilder) CrossesDown(target float64) *AlertBuilder {
+	return b.condition("crosses_down", target)
+}
+
+// CrossUp triggers when the fast line crosses above the slow one
+func (b *AlertBuilder) CrossUp() *AlertBuilder {
+	b.alert.ConditionType = "up"
+	return b
+}
+
+// CrossDown triggers when the fast line crosses below the slow one
+func (b *AlertBuilder) CrossDown() *AlertBuilder {
+	b.alert.ConditionType = "down"
+	return b
+}
+
+// On sets the timeframe the alert is evaluated on
+func (b *AlertBuilder) On(timeframe string) *AlertBuilder {
+	b.alert.Timeframe = timeframe
+	return b
+}
+
+// NotifyVia sets the channels the alert notifies through
+func (b *AlertBuilder) NotifyVia(channels ...string) *AlertBuilder {
+	b.alert.NotifyVia = channels
+	return b
+}
+
+// WithPriority sets the notification priority
+func (b *AlertBuilder) WithPriority(priority string) *AlertBuilder {
+	b.alert.Priority = priority
+	return b
+}
+
+// WithCooldown sets how long the alert stays quiet after it triggers
+func (b *AlertBuilder) WithCooldown(cooldown time.Duration) *AlertBuilder {
+	b.alert.CooldownSeconds = int(cooldown.Seconds())
+	return b
+}
+
+// WithMaxDataAge sets how old the market data of an evaluation may be
+func (b *AlertBuilder) WithMaxDataAge(maxAge time.Duration) *AlertBuilder {
+	b.alert.MaxDataAgeSeconds = int(maxAge.Seconds())
+	return b
+}
+
+// Disabled builds the alert switched off
+func (b *AlertBuilder) Disabled() *AlertBuilder {
+	b.alert.Enabled = false
+	return b
+}
+
+// InSet puts the alert in the alert set
+func (b *AlertBuilder) InSet(setID uuid.UUID) *AlertBuilder {
+	b.alert.SetID = &setID
+	return b
+}
+
+// After chains the alert after the alert with alertID, leaving it disarmed
+func (b *AlertBuilder) After(alertID uuid.UUID) *AlertBuilder {
+	b.alert.DependsOnID = &alertID
+	return b
+}
+
+// TriggeredAt marks the alert as last triggered at t
+func (b *AlertBuilder) TriggeredAt(t time.Time) *AlertBuilder {
+	b.alert.TriggeredAt = &t
+	return b
+}
+
+// Build returns a copy of the alert, so a builder can be reused for variants
+func (b *AlertBuilder) Build() *entities.Alert {
+	alert := b.alert
+	alert.NotifyVia = append([]string(nil), b.alert.NotifyVia...)
+	alert.ReplaceConditions(b.alert.Conditions)
+	if b.alert.TriggeredAt != nil {
+		triggeredAt := *b.alert.TriggeredAt
+		alert.TriggeredAt = &triggeredAt
+	}
+	if b.alert.SetID != nil {
+		setID := *b.alert.SetID
+		alert.SetID = &setID
+	}
+	if b.alert.DependsOnID != nil {
+		dependsOnID := *b.alert.DependsOnID
+		alert.DependsOnID = &dependsOnID
+	}
+	return &alert
+}
+
+func (b *AlertBuilder) watch(symbol, alertType string) *AlertBuilder {
+	b.alert.Symbol = symbol
+	b.alert.AlertType = alertType
+	return b
+}
+
+func (b *AlertBuilder) composite(symbol, operator string, conditions []entities.AlertCondition) *AlertBuilder {
+	b.alert.Conditions = conditions
+	return b.watch(symbol, entities.CompositeAlertType).condition(operator, 0)
+}
+
+func (b *AlertBuilder) condition(conditionType string, target float64) *AlertBuilder {
+	b.alert.ConditionType = conditionType
+	b.alert.TargetValue = target
+	return b
+}
@@ -0,0 +1,219 @@
+package testutils
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+)
+
+// ScenarioStart is where the clock of every AlertScenario starts
+var ScenarioStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// AlertScenario runs an AlertEngine over in-memory repositories and a fake clock.
+// Market data is seeded at the current fake time, so a scenario reads as a
+// sequence of candles, indicator values and evaluations:
+//
+//	s := NewAlertScenario(t)
+//	alert := s.Alert(NewAlertBuilder().Price("BTCUSDT").Above(50000))
+//	s.Price("BTCUSDT", "1h", 51000)
+//	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+type AlertScenario struct {
+	t      testing.TB
+	ctx    context.Context
+	Repos  *MemoryRepositories
+	Clock  *FakeClock
+	Engine *appservices.AlertEngine
+
+	// States persists crossing state across restarts once PersistState is called
+	States appservices.AlertStateStore
+}
+
+// NewAlertScenario creates a scenario with empty repositories and the clock at ScenarioStart
+func NewAlertScenario(t testing.TB) *AlertScenario {
+	s := &AlertScenario{
+		t:     t,
+		ctx:   context.Background(),
+		Repos: NewMemoryRepositories(),
+		Clock: NewFakeClock(ScenarioStart),
+	}
+	return s.Restart()
+}
+
+// Restart replaces the engine with a new one on the same repositories and clock, as
+// after a process restart: in-process throttles and crossover state are lost
+func (s *AlertScenario) Restart() *AlertScenario {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	s.Engine = appservices.NewAlertEngine(s.Repos.Alerts, s.Repos.PriceHistory, s.Repos.TechnicalIndicators, s.Repos.Notifications, nil, logger)
+	s.Engine.SetClock(s.Clock)
+	if s.States != nil {
+		s.Engine.SetStateStore(s.States)
+	}
+	return s
+}
+
+// PersistState backs the engine's crossing state with Redis, as in production, so
+// it survives Restart
+func (s *AlertScenario) PersistState() *AlertScenario {
+	_, client := StartMiniredis(s.t)
+	s.States = appservices.NewRedisAlertStateStore(client)
+	s.Engine.SetStateStore(s.States)
+	return s
+}
+
+// Alert builds the alert and stores it
+func (s *AlertScenario) Alert(builder *AlertBuilder) *entities.Alert {
+	s.t.Helper()
+	alert := builder.Build()
+	if err := s.Repos.Alerts.Create(s.ctx, alert); err != nil {
+		s.t.Fatalf("failed to create alert: %v", err)
+	}
+	return alert
+}
+
+// Price seeds a candle closing at close at the current time
+func (s *AlertScenario) Price(symbol, timeframe string, close float64) *AlertScenario {
+	s.t.Helper()
+	return s.Candles(symbol, timeframe, close)
+}
+
+// Candles seeds one candle per close, oldest first, one timeframe apart with the
+// last one at the current time
+func (s *AlertScenario) Candles(symbol, timeframe string, closes ...float64) *AlertScenario {
+	s.t.Helper()
+	return s.seedCandles(symbol, timeframe, len(closes), func(i int) (float64, float64) { return closes[i], 1000 })
+}
+
+// Volumes seeds one candle per volume like Candles, all closing at 100
+func (s *AlertScenario) Volumes(symbol, timeframe string, volumes ...float64) *AlertScenario {
+	s.t.Helper()
+	return s.seedCandles(symbol, timeframe, len(volumes), func(i int) (float64, float64) { return 100, volumes[i] })
+}
+
+// seedCandles seeds n candles ending at the current time, candle returning the
+// close and volume of the i-th one
+func (s *AlertScenario) seedCandles(symbol, timeframe string, n int, candle func(i int) (close, volume float64)) *AlertScenario {
+	s.t.Helper()
+	step := time.Duration(indicators.GetTimeframeMilliseconds(timeframe)) * time.Millisecond
+	if step == 0 {
+		s.t.Fatalf("unknown timeframe %q", timeframe)
+	}
+
+	start := s.Clock.Now().Add(-step * time.Duration(n-1))
+	candles := make([]entities.PriceHistory, n)
+	for i := range candles {
+		close, volume := candle(i)
+		candles[i] = entities.PriceHistory{
+			Symbol:     symbol,
+			Timeframe:  timeframe,
+			OpenPrice:  close,
+			HighPrice:  close,
+			LowPrice:   close,
+			ClosePrice: close,
+			Volume:     volume,
+			Timestamp:  start.Add(step * time.Duration(i)),
+		}
+	}
+	if err := s.Repos.PriceHistory.BulkInsert(s.ctx, candles); err != nil {
+		s.t.Fatalf("failed to seed candles: %v", err)
+	}
+	return s
+}
+
+// Indicator seeds the value of the indicator identified by key (see
+// entities.IndicatorKey) at the current time
+func (s *AlertScenario) Indicator(symbol, timeframe, key string, value float64, metadata map[string]interface{}) *AlertScenario {
+	s.t.Helper()
+	indicatorType, _, _ := strings.Cut(key, "_")
+	indicator := &entities.TechnicalIndicator{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		IndicatorType: indicatorType,
+		IndicatorKey:  key,
+		Value:         &value,
+		Metadata:      metadata,
+		Timestamp:     s.Clock.Now(),
+	}
+	if err := s.Repos.TechnicalIndicators.Create(s.ctx, indicator); err != nil {
+		s.t.Fatalf("failed to seed %s: %v", key, err)
+	}
+	return s
+}
+
+// RSI seeds the RSI(14) read by rsi alerts
+func (s *AlertScenario) RSI(symbol, timeframe string, value float64) *AlertScenario {
+	s.t.Helper()
+	return s.Indicator(symbol, timeframe, entities.IndicatorKey("RSI", 14), value, nil)
+}
+
+// MovingAverages seeds the short and long EMA or SMA read by a cross alert on shortPeriod
+func (s *AlertScenario) MovingAverages(symbol, timeframe, indicatorType string, shortPeriod int, short, long float64) *AlertScenario {
+	s.t.Helper()
+	s.Indicator(symbol, timeframe, entities.IndicatorKey(indicatorType, shortPeriod), short, nil)
+	return s.Indicator(symbol, timeframe, entities.IndicatorKey(indicatorType, 2*shortPeriod), long, nil)
+}
+
+// MACD seeds the MACD(12,26,9) line and signal read by macd_cross alerts
+func (s *AlertScenario) MACD(symbol, timeframe string, macd, signal float64) *AlertScenario {
+	s.t.Helper()
+	return s.Indicator(symbol, timeframe, entities.IndicatorKey("MACD", 12, 26, 9), macd, map[string]interface{}{"signal": signal})
+}
+
+// Advance moves the clock forward, e.g. to the next candle or past the trigger throttle
+func (s *AlertScenario) Advance(d time.Duration) *AlertScenario {
+	s.Clock.Advance(d)
+	return s
+}
+
+// Evaluate evaluates the alert, failing the test on error. The result is nil
+// while the alert is throttled.
+func (s *AlertScenario) Evaluate(alert *entities.Alert) *appservices.AlertEvaluationResult {
+	s.t.Helper()
+	result, err := s.Engine.EvaluateAlert(s.ctx, alert)
+	if err != nil {
+		s.t.Fatalf("failed to evaluate alert %s: %v", alert.ID, err)
+	}
+	return result
+}
+
+// Notifications returns the user's notifications, newest first
+func (s *AlertScenario) Notifications(userID uuid.UUID) []entities.Notification {
+	s.t.Helper()
+	notifications, err := s.Repos.Notifications.GetByUserID(s.ctx, userID, 0, 0)
+	if err != nil {
+		s.t.Fatalf("failed to get notifications: %v", err)
+	}
+	return notifications
+}
+
+// EvaluateAll evaluates every enabled alert like one tick of the alert monitor,
+// failing the test on error
+func (s *AlertScenario) EvaluateAll() []appservices.AlertEvaluationResult {
+	s.t.Helper()
+	results, err := s.Engine.EvaluateAllAlerts(s.ctx)
+	if err != nil {
+		s.t.Fatalf("failed to evaluate alerts: %v", err)
+	}
+	return results
+}
+
+// Recover runs the startup recovery of evaluations missed since the last EvaluateAll,
+// failing the test on error
+func (s *AlertScenario) Recover(window time.Duration) []appservices.AlertEvaluationResult {
+	s.t.Helper()
+	results, err := s.Engine.RecoverMissedEvaluations(s.ctx, window)
+	if err != nil {
+		s.t.Fatalf("failed to recover missed evaluations: %v", err)
+	}
+	return results
+}
@@ -0,0 +1,283 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+// AlertRepositorySuite is the contract of repositories.AlertRepository
+type AlertRepositorySuite struct {
+	suite.Suite
+	NewHarness HarnessFactory
+
+	harness *Harness
+	repo    repositories.AlertRepository
+	ctx     context.Context
+}
+
+func (s *AlertRepositorySuite) SetupTest() {
+	s.ctx = context.Background()
+	s.harness = s.NewHarness(s.T())
+	s.repo = s.harness.Alerts
+	if s.repo == nil {
+		s.T().Skip("no AlertRepository in harness")
+	}
+}
+
+func (s *AlertRepositorySuite) newAlert(userID uuid.UUID, symbol string, enabled bool) *entities.Alert {
+	alert := &entities.Alert{
+		UserID:        userID,
+		Symbol:        symbol,
+		AlertType:     "price",
+		ConditionType: "above",
+		TargetValue:   50000,
+		Timeframe:     "1h",
+		Enabled:       enabled,
+		NotifyVia:     pq.StringArray{"app", "email"},
+		Priority:      "high",
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, alert))
+	return alert
+}
+
+func (s *AlertRepositorySuite) TestCreateAssignsIDAndTimestamps() {
+	alert := s.newAlert(s.harness.NewUser(s.T()), "BTCUSDT", true)
+
+	s.NotEqual(uuid.Nil, alert.ID)
+	s.False(alert.CreatedAt.IsZero())
+	s.False(alert.UpdatedAt.IsZero())
+}
+
+func (s *AlertRepositorySuite) TestCreateKeepsGivenID() {
+	id := uuid.New()
+	alert := &entities.Alert{ID: id, UserID: s.harness.NewUser(s.T()), Symbol: "BTCUSDT", AlertType: "price",
+		ConditionType: "above", TargetValue: 1, Timeframe: "1h", Enabled: true, NotifyVia: pq.StringArray{"app"}}
+	s.Require().NoError(s.repo.Create(s.ctx, alert))
+
+	s.Equal(id, alert.ID)
+	_, err := s.repo.GetByID(s.ctx, id)
+	s.NoError(err)
+}
+
+func (s *AlertRepositorySuite) TestGetByIDRoundTrips() {
+	userID := s.harness.NewUser(s.T())
+	alert := s.newAlert(userID, "BTCUSDT", true)
+
+	found, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Require().NoError(err)
+	s.Equal(alert.ID, found.ID)
+	s.Equal(userID, found.UserID)
+	s.Equal("BTCUSDT", found.Symbol)
+	s.Equal("price", found.AlertType)
+	s.Equal("above", found.ConditionType)
+	s.InDelta(50000, found.TargetValue, 1e-8)
+	s.Equal("1h", found.Timeframe)
+	s.True(found.Enabled)
+	s.Equal(pq.StringArray{"app", "email"}, found.NotifyVia)
+	s.Nil(found.TriggeredAt)
+}
+
+func (s *AlertRepositorySuite) TestGetByIDMissingFails() {
+	found, err := s.repo.GetByID(s.ctx, uuid.New())
+	s.Error(err)
+	s.Nil(found)
+}
+
+func (s *AlertRepositorySuite) TestGetByUserIDFiltersAndPaginates() {
+	userID := s.harness.NewUser(s.T())
+	other := s.harness.NewUser(s.T())
+	for i := 0; i < 5; i++ {
+		s.newAlert(userID, "BTCUSDT", true)
+	}
+	s.newAlert(other, "BTCUSDT", true)
+
+	all, err := s.repo.GetByUserID(s.ctx, userID, 0, 0)
+	s.Require().NoError(err)
+	s.Len(all, 5)
+
+	// Pages don't overlap and together hold every alert
+	seen := make(map[uuid.UUID]bool)
+	for offset := 0; offset < 6; offset += 2 {
+		page, err := s.repo.GetByUserID(s.ctx, userID, 2, offset)
+		s.Require().NoError(err)
+		s.LessOrEqual(len(page), 2)
+		for _, alert := range page {
+			s.Equal(userID, alert.UserID)
+			s.False(seen[alert.ID], "alert %s returned on two pages", alert.ID)
+			seen[alert.ID] = true
+		}
+	}
+	s.Len(seen, 5)
+
+	none, err := s.repo.GetByUserID(s.ctx, uuid.New(), 10, 0)
+	s.Require().NoError(err)
+	s.Empty(none)
+}
+
+func (s *AlertRepositorySuite) TestGetBySymbolAndEnabled() {
+	userID := s.harness.NewUser(s.T())
+	btc := s.newAlert(userID, "BTCUSDT", true)
+	s.newAlert(userID, "ETHUSDT", true)
+	disabled := s.newAlert(userID, "BTCUSDT", false)
+
+	bySymbol, err := s.repo.GetBySymbol(s.ctx, "BTCUSDT")
+	s.Require().NoError(err)
+	s.ElementsMatch([]uuid.UUID{btc.ID, disabled.ID}, alertIDs(bySymbol))
+
+	enabled, err := s.repo.GetEnabled(s.ctx)
+	s.Require().NoError(err)
+	s.Len(enabled, 2)
+	for _, alert := range enabled {
+		s.True(alert.Enabled)
+	}
+}
+
+func (s *AlertRepositorySuite) TestGetAwaitingAckSkipsAcknowledgedAndDisabled() {
+	userID := s.harness.NewUser(s.T())
+	waiting := s.newAlert(userID, "BTCUSDT", true)
+	s.newAlert(userID, "ETHUSDT", true)
+	disabled := s.newAlert(userID, "SOLUSDT", true)
+
+	triggeredAt := time.Now().UTC().Truncate(time.Second)
+	for _, alert := range []*entities.Alert{waiting, disabled} {
+		alert.AckRequired = true
+		alert.AwaitAck(triggeredAt)
+	}
+	disabled.Enabled = false
+	s.Require().NoError(s.repo.Update(s.ctx, waiting))
+	s.Require().NoError(s.repo.Update(s.ctx, disabled))
+
+	awaiting, err := s.repo.GetAwaitingAck(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(awaiting, 1)
+	s.Equal(waiting.ID, awaiting[0].ID)
+	s.Require().NotNil(awaiting[0].AwaitingAckSince)
+	s.WithinDuration(triggeredAt, *awaiting[0].AwaitingAckSince, time.Second)
+
+	waiting.Acknowledge(triggeredAt.Add(time.Minute))
+	s.Require().NoError(s.repo.Update(s.ctx, waiting))
+
+	awaiting, err = s.repo.GetAwaitingAck(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(awaiting)
+}
+
+func (s *AlertRepositorySuite) TestUpdatePersistsChanges() {
+	alert := s.newAlert(s.harness.NewUser(s.T()), "BTCUSDT", true)
+	createdAt := alert.UpdatedAt
+
+	alert.TargetValue = 60000
+	alert.Enabled = false
+	alert.NotifyVia = pq.StringArray{"push"}
+	s.Require().NoError(s.repo.Update(s.ctx, alert))
+	s.False(alert.UpdatedAt.Before(createdAt))
+
+	found, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Require().NoError(err)
+	s.InDelta(60000, found.TargetValue, 1e-8)
+	s.False(found.Enabled)
+	s.Equal(pq.StringArray{"push"}, found.NotifyVia)
+}
+
+func (s *AlertRepositorySuite) TestMarkTriggeredSetsTriggeredAt() {
+	alert := s.newAlert(s.harness.NewUser(s.T()), "BTCUSDT", true)
+
+	s.Require().NoError(s.repo.MarkTriggered(s.ctx, alert.ID))
+
+	found, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Require().NoError(err)
+	s.NotNil(found.TriggeredAt)
+	s.True(found.Enabled, "triggering doesn't disable the alert")
+}
+
+func (s *AlertRepositorySuite) TestDeleteRemovesAndIsIdempotent() {
+	userID := s.harness.NewUser(s.T())
+	alert := s.newAlert(userID, "BTCUSDT", true)
+	kept := s.newAlert(userID, "BTCUSDT", true)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, alert.ID))
+	_, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Error(err)
+	_, err = s.repo.GetByID(s.ctx, kept.ID)
+	s.NoError(err)
+
+	s.NoError(s.repo.Delete(s.ctx, alert.ID))
+}
+
+func (s *AlertRepositorySuite) TestGetDependentsAndDeleteDetachesThem() {
+	userID := s.harness.NewUser(s.T())
+	breakout := s.newAlert(userID, "BTCUSDT", true)
+	s.newAlert(userID, "BTCUSDT", true)
+	retest := s.newAlert(userID, "BTCUSDT", true)
+	retest.DependsOnID = &breakout.ID
+	s.Require().NoError(s.repo.Update(s.ctx, retest))
+
+	dependents, err := s.repo.GetDependents(s.ctx, breakout.ID)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{retest.ID}, alertIDs(dependents))
+
+	s.Require().NoError(s.repo.Delete(s.ctx, breakout.ID))
+	found, err := s.repo.GetByID(s.ctx, retest.ID)
+	s.Require().NoError(err)
+	s.Nil(found.DependsOnID)
+}
+
+func (s *AlertRepositorySuite) TestCompositeConditionTreeRoundTrips() {
+	alert := &entities.Alert{UserID: s.harness.NewUser(s.T()), Symbol: "BTCUSDT", AlertType: "composite",
+		ConditionType: "and", Timeframe: "1h", Enabled: true, NotifyVia: pq.StringArray{"app"},
+		Conditions: []entities.AlertCondition{
+			{AlertType: "price", ConditionType: "above", TargetValue: 50000},
+			{AlertType: "composite", ConditionType: "or", Conditions: []entities.AlertCondition{
+				{AlertType: "rsi", ConditionType: "below", TargetValue: 30},
+				{AlertType: "volume", ConditionType: "spike", TargetValue: 3},
+			}},
+		}}
+	s.Require().NoError(s.repo.Create(s.ctx, alert))
+	priceID := alert.Conditions[0].ID
+	s.NotEqual(uuid.Nil, priceID)
+	s.NotEqual(uuid.Nil, alert.Conditions[1].Conditions[1].ID)
+
+	found, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Require().NoError(err)
+	s.Require().Len(found.Conditions, 2)
+	s.Equal(priceID, found.Conditions[0].ID)
+	s.Equal("price", found.Conditions[0].AlertType)
+	s.InDelta(50000, found.Conditions[0].TargetValue, 1e-8)
+	s.Equal("or", found.Conditions[1].ConditionType)
+	s.Require().Len(found.Conditions[1].Conditions, 2)
+	s.Equal("rsi", found.Conditions[1].Conditions[0].AlertType)
+	s.Equal("spike", found.Conditions[1].Conditions[1].ConditionType)
+
+	enabled, err := s.repo.GetEnabled(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(enabled, 1)
+	s.Len(enabled[0].Conditions, 2)
+
+	// Replacing the group keeps the ID of the condition that stayed
+	found.Conditions = []entities.AlertCondition{
+		found.Conditions[0],
+		{AlertType: "rsi", ConditionType: "above", TargetValue: 70},
+	}
+	s.Require().NoError(s.repo.Update(s.ctx, found))
+
+	updated, err := s.repo.GetByID(s.ctx, alert.ID)
+	s.Require().NoError(err)
+	s.Require().Len(updated.Conditions, 2)
+	s.Equal(priceID, updated.Conditions[0].ID)
+	s.Equal("rsi", updated.Conditions[1].AlertType)
+	s.Empty(updated.Conditions[1].Conditions)
+}
+
+func alertIDs(alerts []entities.Alert) []uuid.UUID {
+	ids := make([]uuid.UUID, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return ids
+}
@@ -0,0 +1,41 @@
+// Package contract holds the behaviour every repository implementation must share.
+// The GORM repositories, test doubles and any future backend run the same suites,
+// so callers written against one implementation keep working against the others.
+package contract
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/stretchr/testify/suite"
+)
+
+// Harness is a fresh, empty set of repositories under test. Repositories left nil
+// skip their contract.
+type Harness struct {
+	Alerts        repositories.AlertRepository
+	Notifications repositories.NotificationRepository
+	PriceHistory  repositories.PriceHistoryRepository
+
+	// NewUser stores a user that alerts and notifications can belong to. Backends
+	// without foreign keys can return uuid.New().
+	NewUser func(t *testing.T) uuid.UUID
+}
+
+// HarnessFactory builds the harness for one contract test; it is called before
+// every test so each one starts from an empty store
+type HarnessFactory func(t *testing.T) *Harness
+
+// RunAll runs every repository contract against the harnesses built by newHarness
+func RunAll(t *testing.T, newHarness HarnessFactory) {
+	t.Run("AlertRepository", func(t *testing.T) {
+		suite.Run(t, &AlertRepositorySuite{NewHarness: newHarness})
+	})
+	t.Run("NotificationRepository", func(t *testing.T) {
+		suite.Run(t, &NotificationRepositorySuite{NewHarness: newHarness})
+	})
+	t.Run("PriceHistoryRepository", func(t *testing.T) {
+		suite.Run(t, &PriceHistoryRepositorySuite{NewHarness: newHarness})
+	})
+}
@@ -0,0 +1,151 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/stretchr/testify/suite"
+)
+
+// NotificationRepositorySuite is the contract of repositories.NotificationRepository
+type NotificationRepositorySuite struct {
+	suite.Suite
+	NewHarness HarnessFactory
+
+	harness *Harness
+	repo    repositories.NotificationRepository
+	ctx     context.Context
+}
+
+func (s *NotificationRepositorySuite) SetupTest() {
+	s.ctx = context.Background()
+	s.harness = s.NewHarness(s.T())
+	s.repo = s.harness.Notifications
+	if s.repo == nil {
+		s.T().Skip("no NotificationRepository in harness")
+	}
+}
+
+// newNotifications stores count notifications for userID, created a minute apart
+// with the last one the newest
+func (s *NotificationRepositorySuite) newNotifications(userID uuid.UUID, count int) []*entities.Notification {
+	base := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	notifications := make([]*entities.Notification, count)
+	for i := range notifications {
+		notification := &entities.Notification{
+			UserID:           userID,
+			Title:            "Price Alert Triggered",
+			Message:          "BTCUSDT crossed 50000",
+			NotificationType: "alert_triggered",
+			Context:          map[string]interface{}{"symbol": "BTCUSDT"},
+		}
+		s.Require().NoError(s.repo.Create(s.ctx, notification))
+
+		// Create stamps the current time, so spread them out explicitly
+		notification.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		s.Require().NoError(s.repo.Update(s.ctx, notification))
+		notifications[i] = notification
+	}
+	return notifications
+}
+
+func (s *NotificationRepositorySuite) TestCreateAssignsIDAndCreatedAt() {
+	notification := &entities.Notification{
+		UserID:           s.harness.NewUser(s.T()),
+		Title:            "Welcome",
+		Message:          "Hello",
+		NotificationType: "system",
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, notification))
+
+	s.NotEqual(uuid.Nil, notification.ID)
+	s.False(notification.CreatedAt.IsZero())
+
+	found, err := s.repo.GetByID(s.ctx, notification.ID)
+	s.Require().NoError(err)
+	s.Equal("Welcome", found.Title)
+	s.Equal("system", found.NotificationType)
+	s.Nil(found.ReadAt)
+}
+
+func (s *NotificationRepositorySuite) TestGetByIDMissingFails() {
+	found, err := s.repo.GetByID(s.ctx, uuid.New())
+	s.Error(err)
+	s.Nil(found)
+}
+
+func (s *NotificationRepositorySuite) TestGetByUserIDNewestFirst() {
+	userID := s.harness.NewUser(s.T())
+	created := s.newNotifications(userID, 4)
+	s.newNotifications(s.harness.NewUser(s.T()), 1)
+
+	all, err := s.repo.GetByUserID(s.ctx, userID, 0, 0)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{created[3].ID, created[2].ID, created[1].ID, created[0].ID}, notificationIDs(all))
+
+	page, err := s.repo.GetByUserID(s.ctx, userID, 2, 1)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{created[2].ID, created[1].ID}, notificationIDs(page))
+}
+
+func (s *NotificationRepositorySuite) TestReadTracking() {
+	userID := s.harness.NewUser(s.T())
+	created := s.newNotifications(userID, 3)
+
+	s.Require().NoError(s.repo.MarkAsRead(s.ctx, []uuid.UUID{created[0].ID}, userID))
+
+	unread, err := s.repo.GetUnread(s.ctx, userID, 0, 0)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{created[2].ID, created[1].ID}, notificationIDs(unread))
+
+	total, err := s.repo.CountByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Equal(int64(3), total)
+	unreadCount, err := s.repo.CountUnreadByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Equal(int64(2), unreadCount)
+
+	marked, err := s.repo.MarkAllAsReadByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Equal(2, marked, "only notifications still unread are counted")
+
+	unreadCount, err = s.repo.CountUnreadByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Zero(unreadCount)
+}
+
+func (s *NotificationRepositorySuite) TestMarkAsReadIgnoresOtherUsers() {
+	owner := s.harness.NewUser(s.T())
+	other := s.harness.NewUser(s.T())
+	notification := s.newNotifications(owner, 1)[0]
+
+	s.Require().NoError(s.repo.MarkAsRead(s.ctx, []uuid.UUID{notification.ID}, other))
+
+	found, err := s.repo.GetByID(s.ctx, notification.ID)
+	s.Require().NoError(err)
+	s.Nil(found.ReadAt)
+}
+
+func (s *NotificationRepositorySuite) TestDeleteRemoves() {
+	userID := s.harness.NewUser(s.T())
+	created := s.newNotifications(userID, 2)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, created[0].ID))
+
+	_, err := s.repo.GetByID(s.ctx, created[0].ID)
+	s.Error(err)
+	count, err := s.repo.CountByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func notificationIDs(notifications []entities.Notification) []uuid.UUID {
+	ids := make([]uuid.UUID, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+	return ids
+}
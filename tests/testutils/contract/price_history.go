@@ -0,0 +1,177 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/stretchr/testify/suite"
+)
+
+// PriceHistoryRepositorySuite is the contract of repositories.PriceHistoryRepository
+type PriceHistoryRepositorySuite struct {
+	suite.Suite
+	NewHarness HarnessFactory
+
+	harness *Harness
+	repo    repositories.PriceHistoryRepository
+	ctx     context.Context
+	base    time.Time
+}
+
+func (s *PriceHistoryRepositorySuite) SetupTest() {
+	s.ctx = context.Background()
+	s.harness = s.NewHarness(s.T())
+	s.repo = s.harness.PriceHistory
+	if s.repo == nil {
+		s.T().Skip("no PriceHistoryRepository in harness")
+	}
+	s.base = time.Now().UTC().Truncate(time.Hour).Add(-24 * time.Hour)
+}
+
+func (s *PriceHistoryRepositorySuite) candle(symbol, timeframe string, hour int, closePrice float64) entities.PriceHistory {
+	return entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  timeframe,
+		OpenPrice:  closePrice - 1,
+		HighPrice:  closePrice + 1,
+		LowPrice:   closePrice - 2,
+		ClosePrice: closePrice,
+		Volume:     1000,
+		Timestamp:  s.base.Add(time.Duration(hour) * time.Hour),
+	}
+}
+
+func (s *PriceHistoryRepositorySuite) TestGetLatestIsNewestByTimestamp() {
+	// Inserted out of order: the latest candle is the newest timestamp, not the last write
+	for _, candle := range []entities.PriceHistory{
+		s.candle("BTCUSDT", "1h", 2, 102),
+		s.candle("BTCUSDT", "1h", 5, 105),
+		s.candle("BTCUSDT", "1h", 3, 103),
+		s.candle("BTCUSDT", "4h", 8, 999),
+		s.candle("ETHUSDT", "1h", 9, 999),
+	} {
+		candle := candle
+		s.Require().NoError(s.repo.Create(s.ctx, &candle))
+	}
+
+	latest, err := s.repo.GetLatest(s.ctx, "BTCUSDT", "1h")
+	s.Require().NoError(err)
+	s.InDelta(105, latest.ClosePrice, 1e-8)
+	s.True(latest.Timestamp.Equal(s.base.Add(5 * time.Hour)))
+}
+
+func (s *PriceHistoryRepositorySuite) TestGetLatestMissingFails() {
+	latest, err := s.repo.GetLatest(s.ctx, "BTCUSDT", "1h")
+	s.Error(err)
+	s.Nil(latest)
+}
+
+func (s *PriceHistoryRepositorySuite) TestCreateStampsAndKeepsEventTime() {
+	candle := s.candle("BTCUSDT", "1m", 0, 100)
+	eventTime := s.base.Add(-time.Second)
+	candle.EventTime = &eventTime
+	s.Require().NoError(s.repo.Create(s.ctx, &candle))
+	s.False(candle.CreatedAt.IsZero())
+
+	latest, err := s.repo.GetLatest(s.ctx, "BTCUSDT", "1m")
+	s.Require().NoError(err)
+	s.Require().NotNil(latest.EventTime)
+	s.True(latest.EventTime.Equal(eventTime))
+}
+
+func (s *PriceHistoryRepositorySuite) TestCreateRejectsDuplicateCandle() {
+	candle := s.candle("BTCUSDT", "1h", 1, 100)
+	s.Require().NoError(s.repo.Create(s.ctx, &candle))
+
+	duplicate := s.candle("BTCUSDT", "1h", 1, 101)
+	s.Error(s.repo.Create(s.ctx, &duplicate))
+}
+
+func (s *PriceHistoryRepositorySuite) TestGetBySymbolNewestFirstWithLimit() {
+	candles := []entities.PriceHistory{
+		s.candle("BTCUSDT", "1h", 1, 101),
+		s.candle("BTCUSDT", "1h", 2, 102),
+		s.candle("BTCUSDT", "1h", 3, 103),
+		s.candle("BTCUSDT", "4h", 4, 999),
+	}
+	s.Require().NoError(s.repo.BulkInsert(s.ctx, candles))
+
+	all, err := s.repo.GetBySymbol(s.ctx, "BTCUSDT", "1h", 0)
+	s.Require().NoError(err)
+	s.Equal([]float64{103, 102, 101}, closePrices(all))
+
+	limited, err := s.repo.GetBySymbol(s.ctx, "BTCUSDT", "1h", 2)
+	s.Require().NoError(err)
+	s.Equal([]float64{103, 102}, closePrices(limited))
+}
+
+func (s *PriceHistoryRepositorySuite) TestGetByTimeRangeInclusiveOldestFirst() {
+	var candles []entities.PriceHistory
+	for hour := 0; hour < 6; hour++ {
+		candles = append(candles, s.candle("BTCUSDT", "1h", hour, float64(100+hour)))
+	}
+	s.Require().NoError(s.repo.BulkInsert(s.ctx, candles))
+
+	inRange, err := s.repo.GetByTimeRange(s.ctx, "BTCUSDT", "1h", s.base.Add(time.Hour), s.base.Add(3*time.Hour))
+	s.Require().NoError(err)
+	s.Equal([]float64{101, 102, 103}, closePrices(inRange))
+}
+
+func (s *PriceHistoryRepositorySuite) TestBulkInsertEmptyIsNoop() {
+	s.NoError(s.repo.BulkInsert(s.ctx, nil))
+
+	all, err := s.repo.GetBySymbol(s.ctx, "BTCUSDT", "1h", 0)
+	s.Require().NoError(err)
+	s.Empty(all)
+}
+
+func (s *PriceHistoryRepositorySuite) TestDeleteOldKeepsRecentAndOtherSeries() {
+	now := time.Now().UTC().Truncate(time.Hour)
+	old := entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 1, Timestamp: now.AddDate(0, 0, -10)}
+	recent := entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 2, Timestamp: now.Add(-time.Hour)}
+	otherSeries := entities.PriceHistory{Symbol: "ETHUSDT", Timeframe: "1h", ClosePrice: 3, Timestamp: now.AddDate(0, 0, -10)}
+	s.Require().NoError(s.repo.BulkInsert(s.ctx, []entities.PriceHistory{old, recent, otherSeries}))
+
+	s.Require().NoError(s.repo.DeleteOld(s.ctx, "BTCUSDT", "1h", 7))
+
+	btc, err := s.repo.GetBySymbol(s.ctx, "BTCUSDT", "1h", 0)
+	s.Require().NoError(err)
+	s.Equal([]float64{2}, closePrices(btc))
+	eth, err := s.repo.GetBySymbol(s.ctx, "ETHUSDT", "1h", 0)
+	s.Require().NoError(err)
+	s.Len(eth, 1)
+}
+
+func (s *PriceHistoryRepositorySuite) TestGetSymbolsOldestAndCountBefore() {
+	s.Require().NoError(s.repo.BulkInsert(s.ctx, []entities.PriceHistory{
+		s.candle("ETHUSDT", "1m", 3, 103),
+		s.candle("BTCUSDT", "1m", 2, 102),
+		s.candle("BTCUSDT", "1m", 1, 101),
+		s.candle("BTCUSDT", "1m", 4, 104),
+		s.candle("SOLUSDT", "1h", 1, 101),
+	}))
+
+	symbols, err := s.repo.GetSymbols(s.ctx, "1m")
+	s.Require().NoError(err)
+	s.Equal([]string{"BTCUSDT", "ETHUSDT"}, symbols)
+
+	oldest, err := s.repo.GetOldest(s.ctx, "BTCUSDT", "1m")
+	s.Require().NoError(err)
+	s.Equal(101.0, oldest.ClosePrice)
+	_, err = s.repo.GetOldest(s.ctx, "SOLUSDT", "1m")
+	s.Error(err)
+
+	count, err := s.repo.CountBefore(s.ctx, "BTCUSDT", "1m", s.base.Add(4*time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(2), count)
+}
+
+func closePrices(histories []entities.PriceHistory) []float64 {
+	prices := make([]float64, len(histories))
+	for i, history := range histories {
+		prices[i] = history.ClosePrice
+	}
+	return prices
+}
@@ -0,0 +1,401 @@
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+)
+
+// FakeBinanceKlineStart is the open time of the first generated kline, so runs are reproducible
+var FakeBinanceKlineStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// FakeBinance is an httptest-based stand-in for the Binance REST and WebSocket APIs.
+// It serves deterministic tickers, klines and exchange info, and lets tests push
+// stream messages to connected WebSocket clients.
+type FakeBinance struct {
+	server *httptest.Server
+
+	mu       sync.RWMutex
+	prices   map[string]string
+	klines   map[string][][]interface{}
+	symbols  []string
+	failures map[string]int
+	requests map[string]int
+
+	upgrader  websocket.Upgrader
+	wsMu      sync.Mutex
+	wsClients map[*websocket.Conn]map[string]bool
+}
+
+// NewFakeBinance starts a fake Binance server seeded with BTCUSDT and ETHUSDT prices
+func NewFakeBinance() *FakeBinance {
+	f := &FakeBinance{
+		prices: map[string]string{
+			"BTCUSDT": "50000.00000000",
+			"ETHUSDT": "3000.00000000",
+		},
+		klines:    make(map[string][][]interface{}),
+		symbols:   []string{"BTCUSDT", "ETHUSDT"},
+		failures:  make(map[string]int),
+		requests:  make(map[string]int),
+		wsClients: make(map[*websocket.Conn]map[string]bool),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/ping", f.handlePing)
+	mux.HandleFunc("/api/v3/ticker/price", f.handleTickerPrice)
+	mux.HandleFunc("/api/v3/klines", f.handleKlines)
+	mux.HandleFunc("/api/v3/exchangeInfo", f.handleExchangeInfo)
+	mux.HandleFunc("/stream", f.handleStream)
+
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+// URL returns the REST base URL of the fake server
+func (f *FakeBinance) URL() string {
+	return f.server.URL
+}
+
+// WSURL returns the WebSocket base URL of the fake server
+func (f *FakeBinance) WSURL() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// Config returns a Binance configuration pointing at the fake server
+func (f *FakeBinance) Config() *config.BinanceConfig {
+	return &config.BinanceConfig{
+		BaseURL:   f.URL(),
+		WSBaseURL: f.WSURL(),
+	}
+}
+
+// Close shuts down the server and all WebSocket connections
+func (f *FakeBinance) Close() {
+	f.wsMu.Lock()
+	for conn := range f.wsClients {
+		conn.Close()
+	}
+	f.wsClients = make(map[*websocket.Conn]map[string]bool)
+	f.wsMu.Unlock()
+
+	f.server.Close()
+}
+
+// SetPrice sets the ticker price served for a symbol and lists it as trading
+func (f *FakeBinance) SetPrice(symbol string, price float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.prices[symbol]; !exists {
+		f.symbols = append(f.symbols, symbol)
+	}
+	f.prices[symbol] = strconv.FormatFloat(price, 'f', 8, 64)
+}
+
+// SetKlines overrides the generated klines for a symbol and interval
+func (f *FakeBinance) SetKlines(symbol, interval string, klines [][]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.klines[symbol+"|"+interval] = klines
+}
+
+// FailNext makes the next n requests to path respond with HTTP 500
+func (f *FakeBinance) FailNext(path string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[path] = n
+}
+
+// RequestCount returns how many requests were received for path
+func (f *FakeBinance) RequestCount(path string) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.requests[path]
+}
+
+// Kline builds a kline row of the given interval, e.g. "1h", in the format returned
+// by /api/v3/klines; it closes a millisecond before the next one opens
+func Kline(openTime time.Time, interval string, open, high, low, close, volume float64) []interface{} {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 8, 64) }
+	openMillis := openTime.UnixMilli()
+	closeMillis := openTime.Add(intervalDuration(interval)).UnixMilli() - 1
+
+	return []interface{}{
+		float64(openMillis),
+		format(open),
+		format(high),
+		format(low),
+		format(close),
+		format(volume),
+		float64(closeMillis),
+		format(close * volume),
+		float64(100),
+		format(volume / 2),
+		format(close * volume / 2),
+		"0",
+	}
+}
+
+// PushTicker sends a 24hr ticker stream message to clients subscribed to <symbol>@ticker
+func (f *FakeBinance) PushTicker(symbol string, price float64) {
+	stream := strings.ToLower(symbol) + "@ticker"
+	f.push(stream, map[string]interface{}{
+		"e": "24hrTicker",
+		"E": time.Now().UnixMilli(),
+		"s": symbol,
+		"c": strconv.FormatFloat(price, 'f', 8, 64),
+		"P": "0.000",
+		"v": "0.00000000",
+	})
+}
+
+// PushKline sends a kline stream message to clients subscribed to <symbol>@kline_<interval>
+func (f *FakeBinance) PushKline(symbol, interval string, kline []interface{}, closed bool) {
+	stream := fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
+	f.push(stream, map[string]interface{}{
+		"e": "kline",
+		"E": time.Now().UnixMilli(),
+		"s": symbol,
+		"k": map[string]interface{}{
+			"i": interval,
+			"t": int64(kline[0].(float64)),
+			"T": int64(kline[6].(float64)),
+			"s": symbol,
+			"o": kline[1],
+			"h": kline[2],
+			"l": kline[3],
+			"c": kline[4],
+			"v": kline[5],
+			"x": closed,
+		},
+	})
+}
+
+// ConnectedStreams returns the number of WebSocket clients subscribed to stream
+func (f *FakeBinance) ConnectedStreams(stream string) int {
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+
+	count := 0
+	for _, streams := range f.wsClients {
+		if streams[stream] {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *FakeBinance) push(stream string, data interface{}) {
+	message, _ := json.Marshal(map[string]interface{}{
+		"stream": stream,
+		"data":   data,
+	})
+
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+
+	for conn, streams := range f.wsClients {
+		if !streams[stream] {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			conn.Close()
+			delete(f.wsClients, conn)
+		}
+	}
+}
+
+// begin records the request and reports whether an injected failure was served
+func (f *FakeBinance) begin(w http.ResponseWriter, r *http.Request) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[r.URL.Path]++
+	if f.failures[r.URL.Path] > 0 {
+		f.failures[r.URL.Path]--
+		writeBinanceError(w, http.StatusInternalServerError, -1000, "injected failure")
+		return true
+	}
+	return false
+}
+
+func (f *FakeBinance) handlePing(w http.ResponseWriter, r *http.Request) {
+	if f.begin(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (f *FakeBinance) handleTickerPrice(w http.ResponseWriter, r *http.Request) {
+	if f.begin(w, r) {
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	symbol := r.URL.Query().Get("symbol")
+	if symbol == "" {
+		tickers := make([]map[string]string, 0, len(f.symbols))
+		for _, s := range f.symbols {
+			tickers = append(tickers, map[string]string{"symbol": s, "price": f.prices[s]})
+		}
+		writeJSON(w, http.StatusOK, tickers)
+		return
+	}
+
+	price, exists := f.prices[symbol]
+	if !exists {
+		writeBinanceError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"symbol": symbol, "price": price})
+}
+
+func (f *FakeBinance) handleKlines(w http.ResponseWriter, r *http.Request) {
+	if f.begin(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	symbol := query.Get("symbol")
+	interval := query.Get("interval")
+	limit := 500
+	if raw := query.Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	f.mu.RLock()
+	klines, custom := f.klines[symbol+"|"+interval]
+	price, exists := f.prices[symbol]
+	f.mu.RUnlock()
+
+	if !exists && !custom {
+		writeBinanceError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+
+	if !custom {
+		base, _ := strconv.ParseFloat(price, 64)
+		klines = generateKlines(base, interval, limit)
+	}
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+
+	writeJSON(w, http.StatusOK, klines)
+}
+
+func (f *FakeBinance) handleExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	if f.begin(w, r) {
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	symbols := make([]map[string]string, 0, len(f.symbols))
+	for _, s := range f.symbols {
+		symbols = append(symbols, map[string]string{"symbol": s, "status": "TRADING"})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": time.Now().UnixMilli(),
+		"symbols":    symbols,
+	})
+}
+
+func (f *FakeBinance) handleStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	streams := make(map[string]bool)
+	for _, stream := range strings.Split(r.URL.Query().Get("streams"), "/") {
+		if stream != "" {
+			streams[stream] = true
+		}
+	}
+
+	f.wsMu.Lock()
+	f.wsClients[conn] = streams
+	f.wsMu.Unlock()
+
+	// Drain client frames until the connection closes
+	go func() {
+		defer func() {
+			f.wsMu.Lock()
+			delete(f.wsClients, conn)
+			f.wsMu.Unlock()
+			conn.Close()
+		}()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// generateKlines builds a deterministic oscillating series around base
+func generateKlines(base float64, interval string, limit int) [][]interface{} {
+	step := intervalDuration(interval)
+	klines := make([][]interface{}, 0, limit)
+
+	for i := 0; i < limit; i++ {
+		// Small saw-tooth drift keeps indicators like RSI away from their extremes
+		offset := float64(i%10-5) * base * 0.001
+		open := base + offset
+		close := open + base*0.0005
+		high := close + base*0.0005
+		low := open - base*0.0005
+		klines = append(klines, Kline(FakeBinanceKlineStart.Add(time.Duration(i)*step), interval, open, high, low, close, 100+float64(i%7)))
+	}
+
+	return klines
+}
+
+func intervalDuration(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "1h":
+		return time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	default:
+		return time.Minute
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeBinanceError(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "msg": msg})
+}
@@ -0,0 +1,570 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+// The in-memory repositories behave like the GORM ones, as checked by the
+// contract suites in tests/testutils/contract, for tests that need state
+// without a database. Missing records return gorm.ErrRecordNotFound. Every
+// repository is safe for concurrent use, so services can be driven from many
+// goroutines and their end state checked without mock expectations.
+
+var (
+	_ repositories.UserRepository                 = (*MemoryUserRepository)(nil)
+	_ repositories.UserSettingsRepository         = (*MemoryUserSettingsRepository)(nil)
+	_ repositories.CryptoCurrencyRepository       = (*MemoryCryptoCurrencyRepository)(nil)
+	_ repositories.AlertRepository                = (*MemoryAlertRepository)(nil)
+	_ repositories.AlertSetRepository             = (*MemoryAlertSetRepository)(nil)
+	_ repositories.BasketRepository               = (*MemoryBasketRepository)(nil)
+	_ repositories.NotificationRepository         = (*MemoryNotificationRepository)(nil)
+	_ repositories.NotificationDeliveryRepository = (*MemoryNotificationDeliveryRepository)(nil)
+	_ repositories.PriceHistoryRepository         = (*MemoryPriceHistoryRepository)(nil)
+	_ repositories.ExchangePriceRepository        = (*MemoryExchangePriceRepository)(nil)
+	_ repositories.PriceSummaryRepository         = (*MemoryPriceSummaryRepository)(nil)
+	_ repositories.TechnicalIndicatorRepository   = (*MemoryTechnicalIndicatorRepository)(nil)
+	_ repositories.SessionRepository              = (*MemorySessionRepository)(nil)
+	_ repositories.SystemBannerRepository         = (*MemorySystemBannerRepository)(nil)
+	_ repositories.ShareLinkRepository            = (*MemoryShareLinkRepository)(nil)
+	_ repositories.APIKeyRepository               = (*MemoryAPIKeyRepository)(nil)
+	_ repositories.AbuseFlagRepository            = (*MemoryAbuseFlagRepository)(nil)
+	_ repositories.DeviceTokenRepository          = (*MemoryDeviceTokenRepository)(nil)
+	_ repositories.TelegramLinkRepository         = (*MemoryTelegramLinkRepository)(nil)
+)
+
+// MemoryRepositories bundles one of each in-memory repository, for services that
+// need several of them
+type MemoryRepositories struct {
+	Users                  *MemoryUserRepository
+	UserSettings           *MemoryUserSettingsRepository
+	Cryptos                *MemoryCryptoCurrencyRepository
+	Alerts                 *MemoryAlertRepository
+	AlertSets              *MemoryAlertSetRepository
+	Baskets                *MemoryBasketRepository
+	Notifications          *MemoryNotificationRepository
+	NotificationDeliveries *MemoryNotificationDeliveryRepository
+	PriceHistory           *MemoryPriceHistoryRepository
+	ExchangePrices         *MemoryExchangePriceRepository
+	PriceSummaries         *MemoryPriceSummaryRepository
+	TechnicalIndicators    *MemoryTechnicalIndicatorRepository
+	Sessions               *MemorySessionRepository
+	SystemBanners          *MemorySystemBannerRepository
+	SymbolRestrictions     *MemorySymbolRestrictionRepository
+	ShareLinks             *MemoryShareLinkRepository
+	APIKeys                *MemoryAPIKeyRepository
+	AbuseFlags             *MemoryAbuseFlagRepository
+	DeviceTokens           *MemoryDeviceTokenRepository
+	TelegramLinks          *MemoryTelegramLinkRepository
+}
+
+// NewMemoryRepositories creates an empty set of in-memory repositories
+func NewMemoryRepositories() *MemoryRepositories {
+	return &MemoryRepositories{
+		Users:                  NewMemoryUserRepository(),
+		UserSettings:           NewMemoryUserSettingsRepository(),
+		Cryptos:                NewMemoryCryptoCurrencyRepository(),
+		Alerts:                 NewMemoryAlertRepository(),
+		AlertSets:              NewMemoryAlertSetRepository(),
+		Baskets:                NewMemoryBasketRepository(),
+		Notifications:          NewMemoryNotificationRepository(),
+		NotificationDeliveries: NewMemoryNotificationDeliveryRepository(),
+		PriceHistory:           NewMemoryPriceHistoryRepository(),
+		ExchangePrices:         NewMemoryExchangePriceRepository(),
+		PriceSummaries:         NewMemoryPriceSummaryRepository(),
+		TechnicalIndicators:    NewMemoryTechnicalIndicatorRepository(),
+		Sessions:               NewMemorySessionRepository(),
+		SystemBanners:          NewMemorySystemBannerRepository(),
+		SymbolRestrictions:     NewMemorySymbolRestrictionRepository(),
+		ShareLinks:             NewMemoryShareLinkRepository(),
+		APIKeys:                NewMemoryAPIKeyRepository(),
+		AbuseFlags:             NewMemoryAbuseFlagRepository(),
+		DeviceTokens:           NewMemoryDeviceTokenRepository(),
+		TelegramLinks:          NewMemoryTelegramLinkRepository(),
+	}
+}
+
+// MemoryAlertRepository is an in-memory repositories.AlertRepository
+type MemoryAlertRepository struct {
+	mu     sync.RWMutex
+	alerts map[uuid.UUID]entities.Alert
+	order  []uuid.UUID // Insertion order, for stable pagination
+}
+
+// NewMemoryAlertRepository creates an empty in-memory alert repository
+func NewMemoryAlertRepository() *MemoryAlertRepository {
+	return &MemoryAlertRepository{alerts: make(map[uuid.UUID]entities.Alert)}
+}
+
+func (r *MemoryAlertRepository) Create(ctx context.Context, alert *entities.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+	if _, exists := r.alerts[alert.ID]; exists {
+		return fmt.Errorf("duplicate alert id %s", alert.ID)
+	}
+	alert.FlattenConditions() // Gives new conditions an ID like the database does
+	alert.CreatedAt = time.Now()
+	alert.UpdatedAt = time.Now()
+
+	r.alerts[alert.ID] = *alert
+	r.order = append(r.order, alert.ID)
+	return nil
+}
+
+func (r *MemoryAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &alert, nil
+}
+
+func (r *MemoryAlertRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.Alert, error) {
+	alerts := r.filter(func(alert entities.Alert) bool { return alert.UserID == userID })
+	return paginate(alerts, limit, offset), nil
+}
+
+func (r *MemoryAlertRepository) GetBySymbol(ctx context.Context, symbol string) ([]entities.Alert, error) {
+	return r.filter(func(alert entities.Alert) bool { return alert.Symbol == symbol }), nil
+}
+
+func (r *MemoryAlertRepository) GetBySetID(ctx context.Context, setID uuid.UUID) ([]entities.Alert, error) {
+	return r.filter(func(alert entities.Alert) bool { return alert.SetID != nil && *alert.SetID == setID }), nil
+}
+
+func (r *MemoryAlertRepository) GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error) {
+	return r.filter(func(alert entities.Alert) bool { return alert.DependsOnID != nil && *alert.DependsOnID == alertID }), nil
+}
+
+func (r *MemoryAlertRepository) GetEnabled(ctx context.Context) ([]entities.Alert, error) {
+	return r.filter(func(alert entities.Alert) bool { return alert.Enabled }), nil
+}
+
+func (r *MemoryAlertRepository) GetAwaitingAck(ctx context.Context) ([]entities.Alert, error) {
+	return r.filter(func(alert entities.Alert) bool { return alert.Enabled && alert.AwaitingAck() }), nil
+}
+
+func (r *MemoryAlertRepository) Update(ctx context.Context, alert *entities.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert.UpdatedAt = time.Now()
+	alert.FlattenConditions()
+	if _, exists := r.alerts[alert.ID]; !exists {
+		r.order = append(r.order, alert.ID)
+	}
+	r.alerts[alert.ID] = *alert
+	return nil
+}
+
+func (r *MemoryAlertRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.alerts, id)
+	// Like the foreign key, deleting an alert detaches the alerts chained after it
+	for dependentID, alert := range r.alerts {
+		if alert.DependsOnID != nil && *alert.DependsOnID == id {
+			alert.DependsOnID = nil
+			r.alerts[dependentID] = alert
+		}
+	}
+	return nil
+}
+
+func (r *MemoryAlertRepository) MarkTriggered(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	alert.TriggeredAt = &now
+	alert.UpdatedAt = now
+	r.alerts[id] = alert
+	return nil
+}
+
+func (r *MemoryAlertRepository) filter(keep func(entities.Alert) bool) []entities.Alert {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alerts := []entities.Alert{}
+	for _, id := range r.order {
+		if alert, ok := r.alerts[id]; ok && keep(alert) {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// MemoryNotificationRepository is an in-memory repositories.NotificationRepository
+type MemoryNotificationRepository struct {
+	mu            sync.RWMutex
+	notifications map[uuid.UUID]entities.Notification
+}
+
+// NewMemoryNotificationRepository creates an empty in-memory notification repository
+func NewMemoryNotificationRepository() *MemoryNotificationRepository {
+	return &MemoryNotificationRepository{notifications: make(map[uuid.UUID]entities.Notification)}
+}
+
+func (r *MemoryNotificationRepository) Create(ctx context.Context, notification *entities.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	if _, exists := r.notifications[notification.ID]; exists {
+		return fmt.Errorf("duplicate notification id %s", notification.ID)
+	}
+	notification.CreatedAt = time.Now()
+
+	r.notifications[notification.ID] = *notification
+	return nil
+}
+
+func (r *MemoryNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &notification, nil
+}
+
+func (r *MemoryNotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.Notification, error) {
+	notifications := r.newestFirst(func(n entities.Notification) bool { return n.UserID == userID })
+	return paginate(notifications, limit, offset), nil
+}
+
+func (r *MemoryNotificationRepository) GetUnread(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.Notification, error) {
+	notifications := r.newestFirst(func(n entities.Notification) bool { return n.UserID == userID && n.ReadAt == nil })
+	return paginate(notifications, limit, offset), nil
+}
+
+func (r *MemoryNotificationRepository) MarkAsRead(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		if notification, ok := r.notifications[id]; ok && notification.UserID == userID {
+			notification.ReadAt = &now
+			r.notifications[id] = notification
+		}
+	}
+	return nil
+}
+
+func (r *MemoryNotificationRepository) Update(ctx context.Context, notification *entities.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifications[notification.ID] = *notification
+	return nil
+}
+
+func (r *MemoryNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.notifications, id)
+	return nil
+}
+
+func (r *MemoryNotificationRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return int64(len(r.newestFirst(func(n entities.Notification) bool { return n.UserID == userID }))), nil
+}
+
+func (r *MemoryNotificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return int64(len(r.newestFirst(func(n entities.Notification) bool { return n.UserID == userID && n.ReadAt == nil }))), nil
+}
+
+func (r *MemoryNotificationRepository) MarkAllAsReadByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	marked := 0
+	for id, notification := range r.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			notification.ReadAt = &now
+			r.notifications[id] = notification
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (r *MemoryNotificationRepository) newestFirst(keep func(entities.Notification) bool) []entities.Notification {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := []entities.Notification{}
+	for _, notification := range r.notifications {
+		if keep(notification) {
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	return notifications
+}
+
+// MemoryNotificationDeliveryRepository is an in-memory repositories.NotificationDeliveryRepository
+type MemoryNotificationDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries []entities.NotificationDelivery
+}
+
+// NewMemoryNotificationDeliveryRepository creates an empty in-memory notification delivery repository
+func NewMemoryNotificationDeliveryRepository() *MemoryNotificationDeliveryRepository {
+	return &MemoryNotificationDeliveryRepository{}
+}
+
+func (r *MemoryNotificationDeliveryRepository) Create(ctx context.Context, delivery *entities.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.DeliveredAt.IsZero() {
+		delivery.DeliveredAt = time.Now()
+	}
+
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+// ChannelWindows aggregates the deliveries made since a time per channel, ordered by channel
+func (r *MemoryNotificationDeliveryRepository) ChannelWindows(ctx context.Context, since time.Time) ([]entities.ChannelDeliveryWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byChannel := make(map[string]*entities.ChannelDeliveryWindow)
+	latencies := make(map[string]int64)
+	for _, delivery := range r.deliveries {
+		if delivery.DeliveredAt.Before(since) {
+			continue
+		}
+		window := byChannel[delivery.Channel]
+		if window == nil {
+			window = &entities.ChannelDeliveryWindow{Channel: delivery.Channel}
+			byChannel[delivery.Channel] = window
+		}
+		switch delivery.Status {
+		case entities.DeliveryStatusSent:
+			window.Sent++
+			latencies[delivery.Channel] += delivery.LatencyMs
+		case entities.DeliveryStatusFailed:
+			window.Failed++
+		case entities.DeliveryStatusSkipped:
+			window.Skipped++
+		}
+		if delivery.Attempt > 1 {
+			window.Retries++
+		}
+	}
+
+	windows := []entities.ChannelDeliveryWindow{}
+	for channel, window := range byChannel {
+		if window.Sent > 0 {
+			window.AvgLatencyMs = float64(latencies[channel]) / float64(window.Sent)
+		}
+		window.ComputeSuccessRate()
+		windows = append(windows, *window)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Channel < windows[j].Channel })
+	return windows, nil
+}
+
+func (r *MemoryNotificationDeliveryRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int
+	r.deliveries, deleted = deleteMatching(r.deliveries, func(delivery entities.NotificationDelivery) bool {
+		return delivery.DeliveredAt.Before(before)
+	})
+	return int64(deleted), nil
+}
+
+// All returns every stored delivery in insertion order
+func (r *MemoryNotificationDeliveryRepository) All() []entities.NotificationDelivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entities.NotificationDelivery(nil), r.deliveries...)
+}
+
+// MemoryPriceHistoryRepository is an in-memory repositories.PriceHistoryRepository.
+// Like the price_history table it holds one candle per symbol, timeframe and timestamp.
+type MemoryPriceHistoryRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	candles []entities.PriceHistory
+}
+
+// NewMemoryPriceHistoryRepository creates an empty in-memory price history repository
+func NewMemoryPriceHistoryRepository() *MemoryPriceHistoryRepository {
+	return &MemoryPriceHistoryRepository{}
+}
+
+func (r *MemoryPriceHistoryRepository) Create(ctx context.Context, history *entities.PriceHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history.CreatedAt = time.Now()
+	return r.insert(history)
+}
+
+func (r *MemoryPriceHistoryRepository) GetBySymbol(ctx context.Context, symbol, timeframe string, limit int) ([]entities.PriceHistory, error) {
+	candles := r.series(symbol, timeframe, func(entities.PriceHistory) bool { return true })
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Timestamp.After(candles[j].Timestamp) })
+	return paginate(candles, limit, 0), nil
+}
+
+func (r *MemoryPriceHistoryRepository) GetLatest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	candles, _ := r.GetBySymbol(ctx, symbol, timeframe, 1)
+	if len(candles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &candles[0], nil
+}
+
+// GetByTimeRange returns the candles between from and to inclusive, oldest first
+func (r *MemoryPriceHistoryRepository) GetByTimeRange(ctx context.Context, symbol, timeframe string, from, to time.Time) ([]entities.PriceHistory, error) {
+	candles := r.series(symbol, timeframe, func(candle entities.PriceHistory) bool {
+		return !candle.Timestamp.Before(from) && !candle.Timestamp.After(to)
+	})
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Timestamp.Before(candles[j].Timestamp) })
+	return candles, nil
+}
+
+// BulkInsert stores every candle or, if one is a duplicate, none of them
+func (r *MemoryPriceHistoryRepository) BulkInsert(ctx context.Context, histories []entities.PriceHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := len(r.candles)
+	now := time.Now()
+	for i := range histories {
+		histories[i].CreatedAt = now
+		if err := r.insert(&histories[i]); err != nil {
+			r.candles = r.candles[:stored]
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPriceHistoryRepository) DeleteOld(ctx context.Context, symbol, timeframe string, keepDays int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -keepDays)
+	kept := r.candles[:0]
+	for _, candle := range r.candles {
+		if candle.Symbol == symbol && candle.Timeframe == timeframe && candle.Timestamp.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, candle)
+	}
+	r.candles = kept
+	return nil
+}
+
+// GetSymbols returns the symbols with candles on the timeframe, ordered by symbol
+func (r *MemoryPriceHistoryRepository) GetSymbols(ctx context.Context, timeframe string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	symbols := []string{}
+	for _, candle := range r.candles {
+		if candle.Timeframe == timeframe && !seen[candle.Symbol] {
+			seen[candle.Symbol] = true
+			symbols = append(symbols, candle.Symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func (r *MemoryPriceHistoryRepository) GetOldest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	candles := r.series(symbol, timeframe, func(entities.PriceHistory) bool { return true })
+	if len(candles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Timestamp.Before(candles[j].Timestamp) })
+	return &candles[0], nil
+}
+
+func (r *MemoryPriceHistoryRepository) CountBefore(ctx context.Context, symbol, timeframe string, before time.Time) (int64, error) {
+	candles := r.series(symbol, timeframe, func(candle entities.PriceHistory) bool { return candle.Timestamp.Before(before) })
+	return int64(len(candles)), nil
+}
+
+// insert adds a candle, enforcing the (symbol, timeframe, timestamp) uniqueness;
+// the caller holds the lock
+func (r *MemoryPriceHistoryRepository) insert(history *entities.PriceHistory) error {
+	for _, candle := range r.candles {
+		if candle.Symbol == history.Symbol && candle.Timeframe == history.Timeframe && candle.Timestamp.Equal(history.Timestamp) {
+			return fmt.Errorf("duplicate candle %s %s at %s", history.Symbol, history.Timeframe, history.Timestamp)
+		}
+	}
+	r.nextID++
+	history.ID = r.nextID
+	r.candles = append(r.candles, *history)
+	return nil
+}
+
+func (r *MemoryPriceHistoryRepository) series(symbol, timeframe string, keep func(entities.PriceHistory) bool) []entities.PriceHistory {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candles := []entities.PriceHistory{}
+	for _, candle := range r.candles {
+		if candle.Symbol == symbol && candle.Timeframe == timeframe && keep(candle) {
+			candles = append(candles, candle)
+		}
+	}
+	return candles
+}
+
+// paginate applies a limit and offset like SQL, where a limit of 0 means no limit
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,475 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"gorm.io/gorm"
+)
+
+// MemoryUserRepository is an in-memory repositories.UserRepository. Like the users
+// table, emails and Google IDs are unique.
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users []entities.User
+}
+
+// NewMemoryUserRepository creates an empty in-memory user repository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if r.index(user.ID) >= 0 {
+		return fmt.Errorf("failed to create user: duplicate id %s", user.ID)
+	}
+	if err := r.checkUnique(user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *MemoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.find(func(user entities.User) bool { return user.ID == id })
+}
+
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.find(func(user entities.User) bool { return user.Email == email })
+}
+
+func (r *MemoryUserRepository) GetByGoogleID(ctx context.Context, googleID string) (*entities.User, error) {
+	return r.find(func(user entities.User) bool { return user.GoogleID == googleID })
+}
+
+func (r *MemoryUserRepository) GetSandboxCreatedBefore(ctx context.Context, before time.Time) ([]entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []entities.User
+	for _, user := range r.users {
+		if user.Sandbox && user.CreatedAt.Before(before) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].CreatedAt.Before(users[j].CreatedAt) })
+	return users, nil
+}
+
+// Update saves the user, creating it if it doesn't exist yet
+func (r *MemoryUserRepository) Update(ctx context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUnique(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	user.UpdatedAt = time.Now()
+	if i := r.index(user.ID); i >= 0 {
+		r.users[i] = *user
+		return nil
+	}
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.index(id); i >= 0 {
+		r.users = append(r.users[:i], r.users[i+1:]...)
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) find(match func(entities.User) bool) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if match(user) {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// index returns the position of the user with id, or -1; the caller holds the lock
+func (r *MemoryUserRepository) index(id uuid.UUID) int {
+	for i, user := range r.users {
+		if user.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkUnique rejects a user whose email or Google ID another user already has;
+// the caller holds the lock
+func (r *MemoryUserRepository) checkUnique(user *entities.User) error {
+	for _, other := range r.users {
+		if other.ID == user.ID {
+			continue
+		}
+		if other.Email == user.Email {
+			return fmt.Errorf("duplicate email %s", user.Email)
+		}
+		if other.GoogleID == user.GoogleID {
+			return fmt.Errorf("duplicate google_id %s", user.GoogleID)
+		}
+	}
+	return nil
+}
+
+// MemoryUserSettingsRepository is an in-memory repositories.UserSettingsRepository
+// holding at most one settings row per user
+type MemoryUserSettingsRepository struct {
+	mu       sync.RWMutex
+	settings map[uuid.UUID]entities.UserSettings // By user ID
+}
+
+// NewMemoryUserSettingsRepository creates an empty in-memory user settings repository
+func NewMemoryUserSettingsRepository() *MemoryUserSettingsRepository {
+	return &MemoryUserSettingsRepository{settings: make(map[uuid.UUID]entities.UserSettings)}
+}
+
+func (r *MemoryUserSettingsRepository) Create(ctx context.Context, settings *entities.UserSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.settings[settings.UserID]; exists {
+		return fmt.Errorf("failed to create user settings: duplicate user_id %s", settings.UserID)
+	}
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	now := time.Now()
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+
+	r.settings[settings.UserID] = *settings
+	return nil
+}
+
+func (r *MemoryUserSettingsRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, ok := r.settings[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &settings, nil
+}
+
+// GetWithReportsEnabled returns the settings with a report frequency other than
+// none; an empty frequency counts as none, as the column defaults to it
+func (r *MemoryUserSettingsRepository) GetWithReportsEnabled(ctx context.Context) ([]entities.UserSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	enabled := []entities.UserSettings{}
+	for _, settings := range r.settings {
+		if settings.ReportFrequency != "" && settings.ReportFrequency != "none" {
+			enabled = append(enabled, settings)
+		}
+	}
+	sort.Slice(enabled, func(i, j int) bool { return enabled[i].CreatedAt.Before(enabled[j].CreatedAt) })
+	return enabled, nil
+}
+
+// Update saves the settings, creating them if the user has none yet
+func (r *MemoryUserSettingsRepository) Update(ctx context.Context, settings *entities.UserSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	settings.UpdatedAt = time.Now()
+	r.settings[settings.UserID] = *settings
+	return nil
+}
+
+func (r *MemoryUserSettingsRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.settings, userID)
+	return nil
+}
+
+// MemorySessionRepository is an in-memory repositories.SessionRepository. Token
+// hashes are unique.
+type MemorySessionRepository struct {
+	mu       sync.RWMutex
+	sessions []entities.Session
+	now      func() time.Time
+}
+
+// NewMemorySessionRepository creates an empty in-memory session repository
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{now: time.Now}
+}
+
+// SetClock makes expiry checks use clock instead of the wall clock
+func (r *MemorySessionRepository) SetClock(clock *FakeClock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = clock.Now
+}
+
+func (r *MemorySessionRepository) Create(ctx context.Context, session *entities.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	for _, other := range r.sessions {
+		if other.ID == session.ID || other.TokenHash == session.TokenHash {
+			return fmt.Errorf("duplicate session %s", session.ID)
+		}
+	}
+	session.CreatedAt = r.now()
+
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *MemorySessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			return &session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetByUserID returns the user's unexpired sessions, newest first
+func (r *MemorySessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	sessions := []entities.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.ExpiresAt.After(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	newestFirst(sessions, func(s entities.Session) time.Time { return s.CreatedAt })
+	return sessions, nil
+}
+
+func (r *MemorySessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deleteWhere(func(session entities.Session) bool { return session.ID == id })
+	return nil
+}
+
+func (r *MemorySessionRepository) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	r.deleteWhere(func(session entities.Session) bool { return session.TokenHash == tokenHash })
+	return nil
+}
+
+func (r *MemorySessionRepository) DeleteExpired(ctx context.Context) error {
+	r.mu.RLock()
+	now := r.now()
+	r.mu.RUnlock()
+
+	r.deleteWhere(func(session entities.Session) bool { return !session.ExpiresAt.After(now) })
+	return nil
+}
+
+func (r *MemorySessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.deleteWhere(func(session entities.Session) bool { return session.UserID == userID })
+	return nil
+}
+
+func (r *MemorySessionRepository) deleteWhere(match func(entities.Session) bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int
+	r.sessions, deleted = deleteMatching(r.sessions, match)
+	return deleted
+}
+
+// MemoryDeviceTokenRepository is an in-memory repositories.DeviceTokenRepository.
+// A token belongs to one user at a time, as in the device_tokens table.
+type MemoryDeviceTokenRepository struct {
+	mu      sync.RWMutex
+	devices []entities.DeviceToken
+}
+
+// NewMemoryDeviceTokenRepository creates an empty in-memory device token repository
+func NewMemoryDeviceTokenRepository() *MemoryDeviceTokenRepository {
+	return &MemoryDeviceTokenRepository{}
+}
+
+// Register creates the device, or moves the existing one with the same token to
+// device.UserID
+func (r *MemoryDeviceTokenRepository) Register(ctx context.Context, device *entities.DeviceToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i, existing := range r.devices {
+		if existing.Token != device.Token {
+			continue
+		}
+		device.ID = existing.ID
+		device.CreatedAt = existing.CreatedAt
+		device.UpdatedAt = now
+		r.devices[i] = *device
+		return nil
+	}
+
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+	device.CreatedAt = now
+	device.UpdatedAt = now
+	r.devices = append(r.devices, *device)
+	return nil
+}
+
+// GetByUserID returns the user's devices, most recently registered first
+func (r *MemoryDeviceTokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.DeviceToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	devices := []entities.DeviceToken{}
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	newestFirst(devices, func(d entities.DeviceToken) time.Time { return d.UpdatedAt })
+	return devices, nil
+}
+
+func (r *MemoryDeviceTokenRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int
+	r.devices, deleted = deleteMatching(r.devices, func(device entities.DeviceToken) bool {
+		return device.ID == id && device.UserID == userID
+	})
+	if deleted == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *MemoryDeviceTokenRepository) DeleteByTokens(ctx context.Context, tokens []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rejected := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		rejected[token] = true
+	}
+	var deleted int
+	r.devices, deleted = deleteMatching(r.devices, func(device entities.DeviceToken) bool { return rejected[device.Token] })
+	return int64(deleted), nil
+}
+
+// newestFirst sorts items by descending time, keeping insertion order for ties
+func newestFirst[T any](items []T, at func(T) time.Time) {
+	sort.SliceStable(items, func(i, j int) bool { return at(items[i]).After(at(items[j])) })
+}
+
+// deleteMatching removes the matching items in place and reports how many it removed
+func deleteMatching[T any](items []T, match func(T) bool) ([]T, int) {
+	kept := items[:0]
+	for _, item := range items {
+		if !match(item) {
+			kept = append(kept, item)
+		}
+	}
+	deleted := len(items) - len(kept)
+	clear(items[len(kept):])
+	return kept, deleted
+}
+
+// MemoryTelegramLinkRepository is an in-memory repositories.TelegramLinkRepository.
+// Chat IDs are kept in plaintext.
+type MemoryTelegramLinkRepository struct {
+	mu    sync.RWMutex
+	links map[uuid.UUID]entities.TelegramLink
+}
+
+// NewMemoryTelegramLinkRepository creates an empty in-memory Telegram link repository
+func NewMemoryTelegramLinkRepository() *MemoryTelegramLinkRepository {
+	return &MemoryTelegramLinkRepository{links: make(map[uuid.UUID]entities.TelegramLink)}
+}
+
+func (r *MemoryTelegramLinkRepository) Save(ctx context.Context, link *entities.TelegramLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
+	}
+	link.UpdatedAt = now
+	r.links[link.UserID] = *link
+	return nil
+}
+
+func (r *MemoryTelegramLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.TelegramLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	link, ok := r.links[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &link, nil
+}
+
+func (r *MemoryTelegramLinkRepository) GetByCodeHash(ctx context.Context, codeHash string) (*entities.TelegramLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, link := range r.links {
+		if codeHash != "" && link.CodeHash == codeHash {
+			return &link, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *MemoryTelegramLinkRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.links[userID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.links, userID)
+	return nil
+}
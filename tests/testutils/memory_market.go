@@ -0,0 +1,556 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"gorm.io/gorm"
+)
+
+// MemoryCryptoCurrencyRepository is an in-memory repositories.CryptoCurrencyRepository.
+// IDs are assigned in sequence and symbols are unique.
+type MemoryCryptoCurrencyRepository struct {
+	mu      sync.RWMutex
+	nextID  int
+	cryptos []entities.CryptoCurrency
+}
+
+// NewMemoryCryptoCurrencyRepository creates an empty in-memory cryptocurrency repository
+func NewMemoryCryptoCurrencyRepository() *MemoryCryptoCurrencyRepository {
+	return &MemoryCryptoCurrencyRepository{}
+}
+
+func (r *MemoryCryptoCurrencyRepository) Create(ctx context.Context, crypto *entities.CryptoCurrency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.cryptos {
+		if other.Symbol == crypto.Symbol {
+			return fmt.Errorf("failed to create cryptocurrency: duplicate symbol %s", crypto.Symbol)
+		}
+	}
+	r.nextID++
+	crypto.ID = r.nextID
+	now := time.Now()
+	crypto.CreatedAt = now
+	crypto.UpdatedAt = now
+
+	r.cryptos = append(r.cryptos, *crypto)
+	return nil
+}
+
+func (r *MemoryCryptoCurrencyRepository) GetByID(ctx context.Context, id int) (*entities.CryptoCurrency, error) {
+	return r.find(func(crypto entities.CryptoCurrency) bool { return crypto.ID == id })
+}
+
+func (r *MemoryCryptoCurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.CryptoCurrency, error) {
+	return r.find(func(crypto entities.CryptoCurrency) bool { return crypto.Symbol == symbol })
+}
+
+// GetAll returns the cryptocurrencies ordered by name
+func (r *MemoryCryptoCurrencyRepository) GetAll(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error) {
+	return paginate(r.byName(func(entities.CryptoCurrency) bool { return true }), limit, offset), nil
+}
+
+// GetActive returns the active cryptocurrencies ordered by name
+func (r *MemoryCryptoCurrencyRepository) GetActive(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error) {
+	return paginate(r.byName(func(crypto entities.CryptoCurrency) bool { return crypto.Active }), limit, offset), nil
+}
+
+// Update saves the cryptocurrency, creating it if it doesn't exist yet
+func (r *MemoryCryptoCurrencyRepository) Update(ctx context.Context, crypto *entities.CryptoCurrency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	crypto.UpdatedAt = time.Now()
+	for i, existing := range r.cryptos {
+		if existing.ID == crypto.ID {
+			r.cryptos[i] = *crypto
+			return nil
+		}
+	}
+	if crypto.ID > r.nextID {
+		r.nextID = crypto.ID
+	}
+	r.cryptos = append(r.cryptos, *crypto)
+	return nil
+}
+
+func (r *MemoryCryptoCurrencyRepository) UpdateTicker24h(ctx context.Context, symbol string, ticker entities.Ticker24h) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.cryptos {
+		if r.cryptos[i].Symbol == symbol {
+			r.cryptos[i].Ticker24h = ticker
+		}
+	}
+	return nil
+}
+
+func (r *MemoryCryptoCurrencyRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cryptos, _ = deleteMatching(r.cryptos, func(crypto entities.CryptoCurrency) bool { return crypto.ID == id })
+	return nil
+}
+
+func (r *MemoryCryptoCurrencyRepository) find(match func(entities.CryptoCurrency) bool) (*entities.CryptoCurrency, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, crypto := range r.cryptos {
+		if match(crypto) {
+			return &crypto, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *MemoryCryptoCurrencyRepository) byName(keep func(entities.CryptoCurrency) bool) []entities.CryptoCurrency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cryptos := []entities.CryptoCurrency{}
+	for _, crypto := range r.cryptos {
+		if keep(crypto) {
+			cryptos = append(cryptos, crypto)
+		}
+	}
+	sort.SliceStable(cryptos, func(i, j int) bool { return cryptos[i].Name < cryptos[j].Name })
+	return cryptos
+}
+
+// MemoryTechnicalIndicatorRepository is an in-memory repositories.TechnicalIndicatorRepository.
+// Like the technical_indicators table it holds one value per symbol, timeframe,
+// indicator key and timestamp.
+type MemoryTechnicalIndicatorRepository struct {
+	mu         sync.RWMutex
+	nextID     int64
+	indicators []entities.TechnicalIndicator
+}
+
+// NewMemoryTechnicalIndicatorRepository creates an empty in-memory technical indicator repository
+func NewMemoryTechnicalIndicatorRepository() *MemoryTechnicalIndicatorRepository {
+	return &MemoryTechnicalIndicatorRepository{}
+}
+
+func (r *MemoryTechnicalIndicatorRepository) Create(ctx context.Context, indicator *entities.TechnicalIndicator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	indicator.CreatedAt = time.Now()
+	return r.insert(indicator)
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetBySymbol(ctx context.Context, symbol, timeframe, indicatorType string, limit int) ([]entities.TechnicalIndicator, error) {
+	return paginate(r.series(symbol, timeframe, func(indicator entities.TechnicalIndicator) bool {
+		return indicator.IndicatorType == indicatorType
+	}), limit, 0), nil
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetLatest(ctx context.Context, symbol, timeframe, indicatorType string) (*entities.TechnicalIndicator, error) {
+	return latest(r.GetBySymbol(ctx, symbol, timeframe, indicatorType, 1))
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetByKey(ctx context.Context, symbol, timeframe, indicatorKey string, limit int) ([]entities.TechnicalIndicator, error) {
+	return paginate(r.series(symbol, timeframe, func(indicator entities.TechnicalIndicator) bool {
+		return indicator.IndicatorKey == indicatorKey
+	}), limit, 0), nil
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetLatestByKey(ctx context.Context, symbol, timeframe, indicatorKey string) (*entities.TechnicalIndicator, error) {
+	return latest(r.GetByKey(ctx, symbol, timeframe, indicatorKey, 1))
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetIndicatorKeys(ctx context.Context, symbol, timeframe string) ([]string, error) {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, indicator := range r.series(symbol, timeframe, func(entities.TechnicalIndicator) bool { return true }) {
+		if !seen[indicator.IndicatorKey] {
+			seen[indicator.IndicatorKey] = true
+			keys = append(keys, indicator.IndicatorKey)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetByMetadata matches like the JSONB containment the GORM repository uses, so
+// {"period": 26} matches metadata holding 26 whether it was stored as an int or a float
+func (r *MemoryTechnicalIndicatorRepository) GetByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}, limit int) ([]entities.TechnicalIndicator, error) {
+	filter, err := normalizeJSON(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata filter: %w", err)
+	}
+
+	return paginate(r.series(symbol, timeframe, func(indicator entities.TechnicalIndicator) bool {
+		if indicator.IndicatorType != indicatorType {
+			return false
+		}
+		stored, err := normalizeJSON(indicator.Metadata)
+		if err != nil {
+			return false
+		}
+		for key, value := range filter {
+			if !reflect.DeepEqual(stored[key], value) {
+				return false
+			}
+		}
+		return true
+	}), limit, 0), nil
+}
+
+func (r *MemoryTechnicalIndicatorRepository) GetLatestByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}) (*entities.TechnicalIndicator, error) {
+	return latest(r.GetByMetadata(ctx, symbol, timeframe, indicatorType, metadata, 1))
+}
+
+// BulkInsert stores every indicator or, if one is a duplicate, none of them
+func (r *MemoryTechnicalIndicatorRepository) BulkInsert(ctx context.Context, indicators []entities.TechnicalIndicator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := len(r.indicators)
+	now := time.Now()
+	for i := range indicators {
+		indicators[i].CreatedAt = now
+		if err := r.insert(&indicators[i]); err != nil {
+			r.indicators = r.indicators[:stored]
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryTechnicalIndicatorRepository) DeleteOld(ctx context.Context, symbol, timeframe string, keepDays int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -keepDays)
+	r.indicators, _ = deleteMatching(r.indicators, func(indicator entities.TechnicalIndicator) bool {
+		return indicator.Symbol == symbol && indicator.Timeframe == timeframe && indicator.Timestamp.Before(cutoff)
+	})
+	return nil
+}
+
+// insert adds an indicator, enforcing the (symbol, timeframe, indicator_key, timestamp)
+// uniqueness; the caller holds the lock
+func (r *MemoryTechnicalIndicatorRepository) insert(indicator *entities.TechnicalIndicator) error {
+	for _, other := range r.indicators {
+		if other.Symbol == indicator.Symbol && other.Timeframe == indicator.Timeframe &&
+			other.IndicatorKey == indicator.IndicatorKey && other.Timestamp.Equal(indicator.Timestamp) {
+			return fmt.Errorf("duplicate indicator %s %s %s at %s",
+				indicator.Symbol, indicator.Timeframe, indicator.IndicatorKey, indicator.Timestamp)
+		}
+	}
+	r.nextID++
+	indicator.ID = r.nextID
+	r.indicators = append(r.indicators, *indicator)
+	return nil
+}
+
+// series returns the matching indicators of one symbol and timeframe, newest first
+func (r *MemoryTechnicalIndicatorRepository) series(symbol, timeframe string, keep func(entities.TechnicalIndicator) bool) []entities.TechnicalIndicator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	indicators := []entities.TechnicalIndicator{}
+	for _, indicator := range r.indicators {
+		if indicator.Symbol == symbol && indicator.Timeframe == timeframe && keep(indicator) {
+			indicators = append(indicators, indicator)
+		}
+	}
+	newestFirst(indicators, func(i entities.TechnicalIndicator) time.Time { return i.Timestamp })
+	return indicators
+}
+
+// latest returns the first of a newest-first result, or gorm.ErrRecordNotFound
+func latest[T any](items []T, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &items[0], nil
+}
+
+// normalizeJSON round-trips metadata through JSON so values compare as they would
+// in a jsonb column
+func normalizeJSON(metadata map[string]interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// MemoryBasketRepository is an in-memory repositories.BasketRepository. Basket
+// symbols are unique.
+type MemoryBasketRepository struct {
+	mu      sync.RWMutex
+	baskets map[uuid.UUID]entities.Basket
+}
+
+// NewMemoryBasketRepository creates an empty in-memory basket repository
+func NewMemoryBasketRepository() *MemoryBasketRepository {
+	return &MemoryBasketRepository{baskets: make(map[uuid.UUID]entities.Basket)}
+}
+
+func (r *MemoryBasketRepository) Create(ctx context.Context, basket *entities.Basket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if basket.ID == uuid.Nil {
+		basket.ID = uuid.New()
+	}
+	if _, exists := r.baskets[basket.ID]; exists {
+		return fmt.Errorf("duplicate basket id %s", basket.ID)
+	}
+	for _, other := range r.baskets {
+		if other.Symbol == basket.Symbol {
+			return fmt.Errorf("duplicate basket symbol %s", basket.Symbol)
+		}
+	}
+	basket.CreatedAt = time.Now()
+	basket.UpdatedAt = basket.CreatedAt
+
+	r.baskets[basket.ID] = copyBasket(*basket)
+	return nil
+}
+
+func (r *MemoryBasketRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Basket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	basket, found := r.baskets[id]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	basket = copyBasket(basket)
+	return &basket, nil
+}
+
+func (r *MemoryBasketRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.Basket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, basket := range r.baskets {
+		if basket.Symbol == symbol {
+			basket = copyBasket(basket)
+			return &basket, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetByUserID returns the user's baskets ordered by name
+func (r *MemoryBasketRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.Basket, error) {
+	baskets := r.filter(func(basket entities.Basket) bool { return basket.UserID == userID })
+	sort.SliceStable(baskets, func(i, j int) bool { return baskets[i].Name < baskets[j].Name })
+	return baskets, nil
+}
+
+func (r *MemoryBasketRepository) GetAll(ctx context.Context) ([]entities.Basket, error) {
+	return r.filter(func(entities.Basket) bool { return true }), nil
+}
+
+// Update renames the basket; its components are fixed at creation
+func (r *MemoryBasketRepository) Update(ctx context.Context, basket *entities.Basket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, found := r.baskets[basket.ID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	existing.Name = basket.Name
+	existing.UpdatedAt = time.Now()
+	basket.UpdatedAt = existing.UpdatedAt
+
+	r.baskets[basket.ID] = existing
+	return nil
+}
+
+func (r *MemoryBasketRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.baskets[id]; !found {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.baskets, id)
+	return nil
+}
+
+// filter returns copies of the matching baskets oldest first, like GetAll
+func (r *MemoryBasketRepository) filter(keep func(entities.Basket) bool) []entities.Basket {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	baskets := []entities.Basket{}
+	for _, basket := range r.baskets {
+		if keep(basket) {
+			baskets = append(baskets, copyBasket(basket))
+		}
+	}
+	sort.Slice(baskets, func(i, j int) bool { return baskets[i].CreatedAt.Before(baskets[j].CreatedAt) })
+	return baskets
+}
+
+// copyBasket copies the components too, so callers can't change the stored basket
+func copyBasket(basket entities.Basket) entities.Basket {
+	basket.Components = append([]entities.BasketComponent(nil), basket.Components...)
+	return basket
+}
+
+// MemoryExchangePriceRepository is an in-memory repositories.ExchangePriceRepository.
+// IDs are assigned in sequence.
+type MemoryExchangePriceRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	prices []entities.ExchangePrice
+}
+
+// NewMemoryExchangePriceRepository creates an empty in-memory exchange price repository
+func NewMemoryExchangePriceRepository() *MemoryExchangePriceRepository {
+	return &MemoryExchangePriceRepository{}
+}
+
+func (r *MemoryExchangePriceRepository) BulkInsert(ctx context.Context, prices []entities.ExchangePrice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range prices {
+		r.nextID++
+		prices[i].ID = r.nextID
+		prices[i].CreatedAt = now
+		r.prices = append(r.prices, prices[i])
+	}
+	return nil
+}
+
+// GetLatest returns the latest price of the symbol on each exchange observed since
+// the given time, ordered by exchange
+func (r *MemoryExchangePriceRepository) GetLatest(ctx context.Context, symbol string, since time.Time) ([]entities.ExchangePrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	latest := make(map[string]entities.ExchangePrice)
+	for _, price := range r.prices {
+		if price.Symbol != symbol || price.Timestamp.Before(since) {
+			continue
+		}
+		if current, found := latest[price.Exchange]; !found || price.Timestamp.After(current.Timestamp) {
+			latest[price.Exchange] = price
+		}
+	}
+
+	prices := make([]entities.ExchangePrice, 0, len(latest))
+	for _, price := range latest {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Exchange < prices[j].Exchange })
+	return prices, nil
+}
+
+// GetHistory returns the prices of the symbol on the exchange, newest first
+func (r *MemoryExchangePriceRepository) GetHistory(ctx context.Context, symbol, exchange string, limit int) ([]entities.ExchangePrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prices := []entities.ExchangePrice{}
+	for _, price := range r.prices {
+		if price.Symbol == symbol && price.Exchange == exchange {
+			prices = append(prices, price)
+		}
+	}
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].Timestamp.After(prices[j].Timestamp) })
+	return paginate(prices, limit, 0), nil
+}
+
+func (r *MemoryExchangePriceRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int
+	r.prices, deleted = deleteMatching(r.prices, func(price entities.ExchangePrice) bool { return price.Timestamp.Before(before) })
+	return int64(deleted), nil
+}
+
+// MemoryPriceSummaryRepository is an in-memory repositories.PriceSummaryRepository
+// keyed by symbol and day. IDs are assigned in sequence.
+type MemoryPriceSummaryRepository struct {
+	mu        sync.RWMutex
+	nextID    int64
+	summaries map[string]entities.PriceSummary
+}
+
+// NewMemoryPriceSummaryRepository creates an empty in-memory price summary repository
+func NewMemoryPriceSummaryRepository() *MemoryPriceSummaryRepository {
+	return &MemoryPriceSummaryRepository{summaries: make(map[string]entities.PriceSummary)}
+}
+
+func priceSummaryKey(symbol string, day time.Time) string {
+	return symbol + "|" + day.UTC().Format(time.RFC3339)
+}
+
+func (r *MemoryPriceSummaryRepository) Upsert(ctx context.Context, summaries []entities.PriceSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range summaries {
+		key := priceSummaryKey(summaries[i].Symbol, summaries[i].Day)
+		if existing, found := r.summaries[key]; found {
+			summaries[i].ID = existing.ID
+		} else {
+			r.nextID++
+			summaries[i].ID = r.nextID
+		}
+		summaries[i].UpdatedAt = now
+		r.summaries[key] = summaries[i]
+	}
+	return nil
+}
+
+func (r *MemoryPriceSummaryRepository) GetByRange(ctx context.Context, symbol string, from, to time.Time) ([]entities.PriceSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := []entities.PriceSummary{}
+	for _, summary := range r.summaries {
+		if summary.Symbol == symbol && !summary.Day.Before(from) && !summary.Day.After(to) {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Day.Before(summaries[j].Day) })
+	return summaries, nil
+}
+
+func (r *MemoryPriceSummaryRepository) GetLatest(ctx context.Context, symbol string) (*entities.PriceSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entities.PriceSummary
+	for _, summary := range r.summaries {
+		if summary.Symbol == symbol && (latest == nil || summary.Day.After(latest.Day)) {
+			summary := summary
+			latest = &summary
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,509 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"gorm.io/gorm"
+)
+
+// MemorySystemBannerRepository is an in-memory repositories.SystemBannerRepository
+type MemorySystemBannerRepository struct {
+	mu      sync.RWMutex
+	banners []entities.SystemBanner
+}
+
+// NewMemorySystemBannerRepository creates an empty in-memory system banner repository
+func NewMemorySystemBannerRepository() *MemorySystemBannerRepository {
+	return &MemorySystemBannerRepository{}
+}
+
+func (r *MemorySystemBannerRepository) Create(ctx context.Context, banner *entities.SystemBanner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if banner.ID == uuid.Nil {
+		banner.ID = uuid.New()
+	}
+	for _, other := range r.banners {
+		if other.ID == banner.ID {
+			return fmt.Errorf("duplicate banner id %s", banner.ID)
+		}
+	}
+	if banner.CreatedAt.IsZero() {
+		banner.CreatedAt = time.Now()
+	}
+
+	r.banners = append(r.banners, *banner)
+	return nil
+}
+
+func (r *MemorySystemBannerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.SystemBanner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, banner := range r.banners {
+		if banner.ID == id {
+			return &banner, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetActive returns the banners neither revoked nor expired at now, newest first
+func (r *MemorySystemBannerRepository) GetActive(ctx context.Context, now time.Time) ([]entities.SystemBanner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	banners := []entities.SystemBanner{}
+	for _, banner := range r.banners {
+		if banner.IsActive(now) {
+			banners = append(banners, banner)
+		}
+	}
+	newestFirst(banners, func(b entities.SystemBanner) time.Time { return b.CreatedAt })
+	return banners, nil
+}
+
+// Revoke revokes a banner that isn't revoked yet
+func (r *MemorySystemBannerRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, banner := range r.banners {
+		if banner.ID == id && banner.RevokedAt == nil {
+			r.banners[i].RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// MemorySymbolRestrictionRepository is an in-memory
+// repositories.SymbolRestrictionRepository with one restriction per symbol
+type MemorySymbolRestrictionRepository struct {
+	mu           sync.RWMutex
+	restrictions map[string]entities.SymbolRestriction
+}
+
+// NewMemorySymbolRestrictionRepository creates an empty in-memory symbol restriction repository
+func NewMemorySymbolRestrictionRepository() *MemorySymbolRestrictionRepository {
+	return &MemorySymbolRestrictionRepository{restrictions: make(map[string]entities.SymbolRestriction)}
+}
+
+func (r *MemorySymbolRestrictionRepository) Upsert(ctx context.Context, restriction *entities.SymbolRestriction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if existing, found := r.restrictions[restriction.Symbol]; found {
+		restriction.ID = existing.ID
+		restriction.CreatedAt = existing.CreatedAt
+	} else {
+		if restriction.ID == uuid.Nil {
+			restriction.ID = uuid.New()
+		}
+		restriction.CreatedAt = now
+	}
+	restriction.UpdatedAt = now
+
+	r.restrictions[restriction.Symbol] = *restriction
+	return nil
+}
+
+func (r *MemorySymbolRestrictionRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.SymbolRestriction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	restriction, found := r.restrictions[symbol]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &restriction, nil
+}
+
+// GetAll returns the restrictions ordered by symbol
+func (r *MemorySymbolRestrictionRepository) GetAll(ctx context.Context) ([]entities.SymbolRestriction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	restrictions := make([]entities.SymbolRestriction, 0, len(r.restrictions))
+	for _, restriction := range r.restrictions {
+		restrictions = append(restrictions, restriction)
+	}
+	sort.Slice(restrictions, func(i, j int) bool { return restrictions[i].Symbol < restrictions[j].Symbol })
+	return restrictions, nil
+}
+
+func (r *MemorySymbolRestrictionRepository) Delete(ctx context.Context, symbol string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.restrictions[symbol]; !found {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.restrictions, symbol)
+	return nil
+}
+
+// MemoryShareLinkRepository is an in-memory repositories.ShareLinkRepository. Token
+// hashes are unique.
+type MemoryShareLinkRepository struct {
+	mu    sync.RWMutex
+	links []entities.ShareLink
+}
+
+// NewMemoryShareLinkRepository creates an empty in-memory share link repository
+func NewMemoryShareLinkRepository() *MemoryShareLinkRepository {
+	return &MemoryShareLinkRepository{}
+}
+
+func (r *MemoryShareLinkRepository) Create(ctx context.Context, link *entities.ShareLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+	for _, other := range r.links {
+		if other.ID == link.ID || other.TokenHash == link.TokenHash {
+			return fmt.Errorf("duplicate share link %s", link.ID)
+		}
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	r.links = append(r.links, *link)
+	return nil
+}
+
+func (r *MemoryShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, link := range r.links {
+		if link.TokenHash == tokenHash {
+			return &link, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetByUserID returns the user's links, revoked and expired ones included, newest first
+func (r *MemoryShareLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.ShareLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := []entities.ShareLink{}
+	for _, link := range r.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	newestFirst(links, func(l entities.ShareLink) time.Time { return l.CreatedAt })
+	return paginate(links, limit, offset), nil
+}
+
+// Revoke revokes one of the user's links that isn't revoked yet
+func (r *MemoryShareLinkRepository) Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, link := range r.links {
+		if link.ID == id && link.UserID == userID && link.RevokedAt == nil {
+			r.links[i].RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// MemoryAPIKeyRepository is an in-memory repositories.APIKeyRepository. Key hashes
+// are unique.
+type MemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys []entities.APIKey
+}
+
+// NewMemoryAPIKeyRepository creates an empty in-memory API key repository
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{}
+}
+
+func (r *MemoryAPIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	for _, other := range r.keys {
+		if other.ID == key.ID || other.KeyHash == key.KeyHash {
+			return fmt.Errorf("duplicate API key %s", key.ID)
+		}
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return &key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetByUserID returns the user's keys, revoked ones included, newest first
+func (r *MemoryAPIKeyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []entities.APIKey{}
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	newestFirst(keys, func(k entities.APIKey) time.Time { return k.CreatedAt })
+	return keys, nil
+}
+
+func (r *MemoryAPIKeyRepository) CountActiveByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, key := range r.keys {
+		if key.UserID == userID && key.IsActive() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Revoke revokes one of the user's keys that isn't revoked yet
+func (r *MemoryAPIKeyRepository) Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, key := range r.keys {
+		if key.ID == id && key.UserID == userID && key.RevokedAt == nil {
+			r.keys[i].RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *MemoryAPIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, key := range r.keys {
+		if key.ID == id {
+			r.keys[i].LastUsedAt = &usedAt
+			return nil
+		}
+	}
+	return nil
+}
+
+// MemoryAbuseFlagRepository is an in-memory repositories.AbuseFlagRepository
+type MemoryAbuseFlagRepository struct {
+	mu    sync.RWMutex
+	flags []entities.AbuseFlag
+}
+
+// NewMemoryAbuseFlagRepository creates an empty in-memory abuse flag repository
+func NewMemoryAbuseFlagRepository() *MemoryAbuseFlagRepository {
+	return &MemoryAbuseFlagRepository{}
+}
+
+func (r *MemoryAbuseFlagRepository) Create(ctx context.Context, flag *entities.AbuseFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if flag.ID == uuid.Nil {
+		flag.ID = uuid.New()
+	}
+	for _, other := range r.flags {
+		if other.ID == flag.ID {
+			return fmt.Errorf("duplicate abuse flag id %s", flag.ID)
+		}
+	}
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = time.Now()
+	}
+
+	r.flags = append(r.flags, *flag)
+	return nil
+}
+
+func (r *MemoryAbuseFlagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AbuseFlag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, flag := range r.flags {
+		if flag.ID == id {
+			return &flag, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *MemoryAbuseFlagRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AbuseFlag, error) {
+	return r.newestFirst(func(flag entities.AbuseFlag) bool { return flag.UserID == userID }), nil
+}
+
+// GetActive returns the unresolved flags still throttling at now, newest first
+func (r *MemoryAbuseFlagRepository) GetActive(ctx context.Context, now time.Time) ([]entities.AbuseFlag, error) {
+	return r.newestFirst(func(flag entities.AbuseFlag) bool { return flag.IsActive(now) }), nil
+}
+
+func (r *MemoryAbuseFlagRepository) List(ctx context.Context, limit, offset int) ([]entities.AbuseFlag, error) {
+	return paginate(r.newestFirst(func(entities.AbuseFlag) bool { return true }), limit, offset), nil
+}
+
+// Resolve clears a flag that isn't resolved yet
+func (r *MemoryAbuseFlagRepository) Resolve(ctx context.Context, id uuid.UUID, resolvedBy string, resolvedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, flag := range r.flags {
+		if flag.ID == id && flag.ResolvedAt == nil {
+			r.flags[i].ResolvedAt = &resolvedAt
+			r.flags[i].ResolvedBy = resolvedBy
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *MemoryAbuseFlagRepository) newestFirst(keep func(entities.AbuseFlag) bool) []entities.AbuseFlag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flags := []entities.AbuseFlag{}
+	for _, flag := range r.flags {
+		if keep(flag) {
+			flags = append(flags, flag)
+		}
+	}
+	newestFirst(flags, func(f entities.AbuseFlag) time.Time { return f.CreatedAt })
+	return flags
+}
+
+// MemoryAlertSetRepository is an in-memory repositories.AlertSetRepository. Set
+// names are unique per user.
+type MemoryAlertSetRepository struct {
+	mu   sync.RWMutex
+	sets map[uuid.UUID]entities.AlertSet
+}
+
+// NewMemoryAlertSetRepository creates an empty in-memory alert set repository
+func NewMemoryAlertSetRepository() *MemoryAlertSetRepository {
+	return &MemoryAlertSetRepository{sets: make(map[uuid.UUID]entities.AlertSet)}
+}
+
+func (r *MemoryAlertSetRepository) Create(ctx context.Context, set *entities.AlertSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if set.ID == uuid.Nil {
+		set.ID = uuid.New()
+	}
+	if _, exists := r.sets[set.ID]; exists {
+		return fmt.Errorf("duplicate alert set id %s", set.ID)
+	}
+	if err := r.checkUnique(set); err != nil {
+		return err
+	}
+	set.CreatedAt = time.Now()
+	set.UpdatedAt = set.CreatedAt
+
+	r.sets[set.ID] = *set
+	return nil
+}
+
+func (r *MemoryAlertSetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AlertSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set, found := r.sets[id]
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &set, nil
+}
+
+// GetByUserID returns the user's sets ordered by name
+func (r *MemoryAlertSetRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AlertSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sets := []entities.AlertSet{}
+	for _, set := range r.sets {
+		if set.UserID == userID {
+			sets = append(sets, set)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets, nil
+}
+
+func (r *MemoryAlertSetRepository) Update(ctx context.Context, set *entities.AlertSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, found := r.sets[set.ID]
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+	if err := r.checkUnique(set); err != nil {
+		return err
+	}
+	existing.Name = set.Name
+	existing.Description = set.Description
+	existing.UpdatedAt = time.Now()
+	set.UpdatedAt = existing.UpdatedAt
+
+	r.sets[set.ID] = existing
+	return nil
+}
+
+func (r *MemoryAlertSetRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.sets[id]; !found {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.sets, id)
+	return nil
+}
+
+// checkUnique rejects a set named like another of the user's sets; the caller
+// holds the lock
+func (r *MemoryAlertSetRepository) checkUnique(set *entities.AlertSet) error {
+	for _, other := range r.sets {
+		if other.ID != set.ID && other.UserID == set.UserID && other.Name == set.Name {
+			return fmt.Errorf("duplicate alert set name %s", set.Name)
+		}
+	}
+	return nil
+}
@@ -7,10 +7,26 @@ import (
 	"github.com/google/uuid"
 	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/mock"
 )
 
+// The mocks must keep the signatures of the interfaces they stand in for
+var (
+	_ repositories.AlertRepository              = (*MockAlertRepository)(nil)
+	_ repositories.NotificationRepository       = (*MockNotificationRepository)(nil)
+	_ repositories.UserRepository               = (*MockUserRepository)(nil)
+	_ repositories.UserSettingsRepository       = (*MockUserSettingsRepository)(nil)
+	_ repositories.SystemBannerRepository       = (*MockSystemBannerRepository)(nil)
+	_ repositories.ShareLinkRepository          = (*MockShareLinkRepository)(nil)
+	_ repositories.AbuseFlagRepository          = (*MockAbuseFlagRepository)(nil)
+	_ repositories.CryptoCurrencyRepository     = (*MockCryptoCurrencyRepository)(nil)
+	_ repositories.PriceHistoryRepository       = (*MockPriceHistoryRepository)(nil)
+	_ repositories.TechnicalIndicatorRepository = (*MockTechnicalIndicatorRepository)(nil)
+	_ repositories.DeviceTokenRepository        = (*MockDeviceTokenRepository)(nil)
+)
+
 // MockAlertRepository implements the AlertRepository interface for testing
 type MockAlertRepository struct {
 	mock.Mock
@@ -34,11 +50,29 @@ func (m *MockAlertRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 	return args.Get(0).([]entities.Alert), args.Error(1)
 }
 
+func (m *MockAlertRepository) GetBySetID(ctx context.Context, setID uuid.UUID) ([]entities.Alert, error) {
+	args := m.Called(ctx, setID)
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error) {
+	args := m.Called(ctx, alertID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
 func (m *MockAlertRepository) GetEnabled(ctx context.Context) ([]entities.Alert, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]entities.Alert), args.Error(1)
 }
 
+func (m *MockAlertRepository) GetAwaitingAck(ctx context.Context) ([]entities.Alert, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
 func (m *MockAlertRepository) Update(ctx context.Context, alert *entities.Alert) error {
 	args := m.Called(ctx, alert)
 	return args.Error(0)
@@ -151,6 +185,11 @@ func (m *MockUserRepository) GetByGoogleID(ctx context.Context, googleID string)
 	return args.Get(0).(*entities.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetSandboxCreatedBefore(ctx context.Context, before time.Time) ([]entities.User, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]entities.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *entities.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -161,6 +200,184 @@ func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+// MockUserSettingsRepository implements the UserSettingsRepository interface for testing
+type MockUserSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserSettingsRepository) Create(ctx context.Context, settings *entities.UserSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func (m *MockUserSettingsRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.UserSettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserSettings), args.Error(1)
+}
+
+func (m *MockUserSettingsRepository) GetWithReportsEnabled(ctx context.Context) ([]entities.UserSettings, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entities.UserSettings), args.Error(1)
+}
+
+func (m *MockUserSettingsRepository) Update(ctx context.Context, settings *entities.UserSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func (m *MockUserSettingsRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockSystemBannerRepository implements the SystemBannerRepository interface for testing
+type MockSystemBannerRepository struct {
+	mock.Mock
+}
+
+func (m *MockSystemBannerRepository) Create(ctx context.Context, banner *entities.SystemBanner) error {
+	args := m.Called(ctx, banner)
+	return args.Error(0)
+}
+
+func (m *MockSystemBannerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.SystemBanner, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SystemBanner), args.Error(1)
+}
+
+func (m *MockSystemBannerRepository) GetActive(ctx context.Context, now time.Time) ([]entities.SystemBanner, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]entities.SystemBanner), args.Error(1)
+}
+
+func (m *MockSystemBannerRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+// MockShareLinkRepository implements the ShareLinkRepository interface for testing
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link *entities.ShareLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]entities.ShareLink, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]entities.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, userID, revokedAt)
+	return args.Error(0)
+}
+
+// MockAbuseFlagRepository implements the AbuseFlagRepository interface for testing
+type MockAbuseFlagRepository struct {
+	mock.Mock
+}
+
+func (m *MockAbuseFlagRepository) Create(ctx context.Context, flag *entities.AbuseFlag) error {
+	args := m.Called(ctx, flag)
+	return args.Error(0)
+}
+
+func (m *MockAbuseFlagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AbuseFlag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.AbuseFlag), args.Error(1)
+}
+
+func (m *MockAbuseFlagRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.AbuseFlag, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]entities.AbuseFlag), args.Error(1)
+}
+
+func (m *MockAbuseFlagRepository) GetActive(ctx context.Context, now time.Time) ([]entities.AbuseFlag, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]entities.AbuseFlag), args.Error(1)
+}
+
+func (m *MockAbuseFlagRepository) List(ctx context.Context, limit, offset int) ([]entities.AbuseFlag, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entities.AbuseFlag), args.Error(1)
+}
+
+func (m *MockAbuseFlagRepository) Resolve(ctx context.Context, id uuid.UUID, resolvedBy string, resolvedAt time.Time) error {
+	args := m.Called(ctx, id, resolvedBy, resolvedAt)
+	return args.Error(0)
+}
+
+// MockCryptoCurrencyRepository implements the CryptoCurrencyRepository interface for testing
+type MockCryptoCurrencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCryptoCurrencyRepository) Create(ctx context.Context, crypto *entities.CryptoCurrency) error {
+	args := m.Called(ctx, crypto)
+	return args.Error(0)
+}
+
+func (m *MockCryptoCurrencyRepository) GetByID(ctx context.Context, id int) (*entities.CryptoCurrency, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.CryptoCurrency), args.Error(1)
+}
+
+func (m *MockCryptoCurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*entities.CryptoCurrency, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.CryptoCurrency), args.Error(1)
+}
+
+func (m *MockCryptoCurrencyRepository) GetAll(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entities.CryptoCurrency), args.Error(1)
+}
+
+func (m *MockCryptoCurrencyRepository) GetActive(ctx context.Context, limit, offset int) ([]entities.CryptoCurrency, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entities.CryptoCurrency), args.Error(1)
+}
+
+func (m *MockCryptoCurrencyRepository) Update(ctx context.Context, crypto *entities.CryptoCurrency) error {
+	args := m.Called(ctx, crypto)
+	return args.Error(0)
+}
+
+func (m *MockCryptoCurrencyRepository) UpdateTicker24h(ctx context.Context, symbol string, ticker entities.Ticker24h) error {
+	args := m.Called(ctx, symbol, ticker)
+	return args.Error(0)
+}
+
+func (m *MockCryptoCurrencyRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockPriceHistoryRepository implements the PriceHistoryRepository interface for testing
 type MockPriceHistoryRepository struct {
 	mock.Mock
@@ -184,6 +401,11 @@ func (m *MockPriceHistoryRepository) GetLatest(ctx context.Context, symbol, time
 	return args.Get(0).(*entities.PriceHistory), args.Error(1)
 }
 
+func (m *MockPriceHistoryRepository) GetByTimeRange(ctx context.Context, symbol, timeframe string, from, to time.Time) ([]entities.PriceHistory, error) {
+	args := m.Called(ctx, symbol, timeframe, from, to)
+	return args.Get(0).([]entities.PriceHistory), args.Error(1)
+}
+
 func (m *MockPriceHistoryRepository) BulkInsert(ctx context.Context, histories []entities.PriceHistory) error {
 	args := m.Called(ctx, histories)
 	return args.Error(0)
@@ -194,6 +416,24 @@ func (m *MockPriceHistoryRepository) DeleteOld(ctx context.Context, symbol, time
 	return args.Error(0)
 }
 
+func (m *MockPriceHistoryRepository) GetSymbols(ctx context.Context, timeframe string) ([]string, error) {
+	args := m.Called(ctx, timeframe)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPriceHistoryRepository) GetOldest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	args := m.Called(ctx, symbol, timeframe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PriceHistory), args.Error(1)
+}
+
+func (m *MockPriceHistoryRepository) CountBefore(ctx context.Context, symbol, timeframe string, before time.Time) (int64, error) {
+	args := m.Called(ctx, symbol, timeframe, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockTechnicalIndicatorRepository implements the TechnicalIndicatorRepository interface for testing
 type MockTechnicalIndicatorRepository struct {
 	mock.Mock
@@ -217,6 +457,37 @@ func (m *MockTechnicalIndicatorRepository) GetLatest(ctx context.Context, symbol
 	return args.Get(0).(*entities.TechnicalIndicator), args.Error(1)
 }
 
+func (m *MockTechnicalIndicatorRepository) GetByKey(ctx context.Context, symbol, timeframe, indicatorKey string, limit int) ([]entities.TechnicalIndicator, error) {
+	args := m.Called(ctx, symbol, timeframe, indicatorKey, limit)
+	return args.Get(0).([]entities.TechnicalIndicator), args.Error(1)
+}
+
+func (m *MockTechnicalIndicatorRepository) GetLatestByKey(ctx context.Context, symbol, timeframe, indicatorKey string) (*entities.TechnicalIndicator, error) {
+	args := m.Called(ctx, symbol, timeframe, indicatorKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TechnicalIndicator), args.Error(1)
+}
+
+func (m *MockTechnicalIndicatorRepository) GetIndicatorKeys(ctx context.Context, symbol, timeframe string) ([]string, error) {
+	args := m.Called(ctx, symbol, timeframe)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTechnicalIndicatorRepository) GetByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}, limit int) ([]entities.TechnicalIndicator, error) {
+	args := m.Called(ctx, symbol, timeframe, indicatorType, metadata, limit)
+	return args.Get(0).([]entities.TechnicalIndicator), args.Error(1)
+}
+
+func (m *MockTechnicalIndicatorRepository) GetLatestByMetadata(ctx context.Context, symbol, timeframe, indicatorType string, metadata map[string]interface{}) (*entities.TechnicalIndicator, error) {
+	args := m.Called(ctx, symbol, timeframe, indicatorType, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TechnicalIndicator), args.Error(1)
+}
+
 func (m *MockTechnicalIndicatorRepository) BulkInsert(ctx context.Context, indicators []entities.TechnicalIndicator) error {
 	args := m.Called(ctx, indicators)
 	return args.Error(0)
@@ -227,6 +498,31 @@ func (m *MockTechnicalIndicatorRepository) DeleteOld(ctx context.Context, symbol
 	return args.Error(0)
 }
 
+// MockDeviceTokenRepository implements the DeviceTokenRepository interface for testing
+type MockDeviceTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceTokenRepository) Register(ctx context.Context, device *entities.DeviceToken) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entities.DeviceToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]entities.DeviceToken), args.Error(1)
+}
+
+func (m *MockDeviceTokenRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTokenRepository) DeleteByTokens(ctx context.Context, tokens []string) (int64, error) {
+	args := m.Called(ctx, tokens)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockAlertWebSocketService implements the AlertWebSocketService interface for testing
 type MockAlertWebSocketService struct {
 	mock.Mock
@@ -234,6 +530,11 @@ type MockAlertWebSocketService struct {
 
 var _ appservices.AlertWebSocketService = (*MockAlertWebSocketService)(nil)
 
+func (m *MockAlertWebSocketService) IsUserConnected(ctx context.Context, userID uuid.UUID) bool {
+	args := m.Called(ctx, userID)
+	return args.Bool(0)
+}
+
 func (m *MockAlertWebSocketService) BroadcastAlertTriggered(ctx context.Context, alert *entities.Alert, result *appservices.AlertEvaluationResult) error {
 	args := m.Called(ctx, alert, result)
 	return args.Error(0)
@@ -259,6 +560,11 @@ func (m *MockAlertWebSocketService) NotifyAlertEvaluation(ctx context.Context, u
 	return args.Error(0)
 }
 
+func (m *MockAlertWebSocketService) BroadcastAlertLevels(ctx context.Context, userID uuid.UUID, symbol string, levels []appservices.AlertLevel) error {
+	args := m.Called(ctx, userID, symbol, levels)
+	return args.Error(0)
+}
+
 func (m *MockAlertWebSocketService) GetConnectedUsersStats(ctx context.Context) (map[string]interface{}, error) {
 	args := m.Called(ctx)
 	if args.Get(0) != nil {
@@ -379,6 +685,11 @@ func (m *MockRedisClient) ZRemRangeByScore(ctx context.Context, key, min, max st
 	return cmd
 }
 
+func (m *MockRedisClient) Pipeline() redis.Pipeliner {
+	args := m.Called()
+	return args.Get(0).(redis.Pipeliner)
+}
+
 func (m *MockRedisClient) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd {
 	args := m.Called(ctx, key, opt)
 	cmd := redis.NewZSliceCmd(ctx)
@@ -387,3 +698,14 @@ func (m *MockRedisClient) ZRangeByScoreWithScores(ctx context.Context, key strin
 	}
 	return cmd
 }
+
+func (m *MockRedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	called := m.Called(ctx, script, keys, args)
+	cmd := redis.NewCmd(ctx)
+	if err := called.Error(1); err != nil {
+		cmd.SetErr(err)
+	} else if called.Get(0) != nil {
+		cmd.SetVal(called.Get(0))
+	}
+	return cmd
+}
@@ -0,0 +1,220 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// PostgresDSNEnv points the harness at an already running database instead of starting a container.
+	// Its public schema is dropped, so the database name must end in postgresTestSuffix.
+	PostgresDSNEnv = "PRICEGUARD_TEST_DATABASE_URL"
+
+	postgresTestSuffix = "_test"
+
+	postgresImage    = "postgres:15-alpine"
+	postgresUser     = "postgres"
+	postgresPassword = "postgres"
+	postgresDatabase = "priceguard_test"
+
+	// postgresStartTimeout bounds pulling the image and waiting for the server to accept connections
+	postgresStartTimeout = 2 * time.Minute
+)
+
+// PostgresHarness holds a Postgres database with the real migrations applied
+type PostgresHarness struct {
+	DB  *gorm.DB
+	DSN string
+
+	container *tcpostgres.PostgresContainer
+}
+
+// StartPostgres provides a migrated Postgres database for repository tests.
+// It uses PRICEGUARD_TEST_DATABASE_URL when set, otherwise it starts a disposable
+// container with testcontainers. The test is skipped when neither is available, and
+// fails rather than wiping a database given by DSN whose name doesn't end in _test.
+func StartPostgres(t testing.TB) *PostgresHarness {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping Postgres integration tests in short mode")
+	}
+
+	harness := &PostgresHarness{DSN: os.Getenv(PostgresDSNEnv)}
+	if harness.DSN == "" {
+		if err := harness.startContainer(); err != nil {
+			harness.Close()
+			t.Skipf("failed to start Postgres container and %s not set: %v", PostgresDSNEnv, err)
+		}
+	}
+
+	db, err := harness.connect()
+	if err != nil {
+		harness.Close()
+		t.Fatalf("failed to connect to Postgres: %v", err)
+	}
+	harness.DB = db
+
+	if err := harness.checkDisposable(); err != nil {
+		harness.Close()
+		t.Fatalf("refusing to reset schema: %v", err)
+	}
+	if err := harness.resetSchema(); err != nil {
+		harness.Close()
+		t.Fatalf("failed to reset schema: %v", err)
+	}
+	if err := harness.applyMigrations(); err != nil {
+		harness.Close()
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	return harness
+}
+
+// Truncate removes all rows from the given tables, cascading to dependent tables
+func (h *PostgresHarness) Truncate(t testing.TB, tables ...string) {
+	t.Helper()
+	if len(tables) == 0 {
+		return
+	}
+	if err := h.DB.Exec("TRUNCATE TABLE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("failed to truncate tables: %v", err)
+	}
+}
+
+// Close releases the connection pool and terminates the container if one was started
+func (h *PostgresHarness) Close() {
+	if h.DB != nil {
+		if sqlDB, err := h.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	if h.container != nil {
+		_ = testcontainers.TerminateContainer(h.container)
+		h.container = nil
+	}
+}
+
+// startContainer runs Postgres and waits until it accepts connections
+func (h *PostgresHarness) startContainer() (err error) {
+	// testcontainers panics rather than failing when no Docker host can be found
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresStartTimeout)
+	defer cancel()
+
+	container, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase(postgresDatabase),
+		tcpostgres.WithUsername(postgresUser),
+		tcpostgres.WithPassword(postgresPassword),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if container != nil {
+		h.container = container
+	}
+	if err != nil {
+		return err
+	}
+
+	h.DSN, err = container.ConnectionString(ctx, "sslmode=disable")
+	return err
+}
+
+func (h *PostgresHarness) connect() (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(h.DSN), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// checkDisposable makes sure the schema about to be dropped belongs to a test database
+func (h *PostgresHarness) checkDisposable() error {
+	if h.container != nil {
+		return nil
+	}
+	var name string
+	if err := h.DB.Raw("SELECT current_database()").Scan(&name).Error; err != nil {
+		return err
+	}
+	if !strings.HasSuffix(name, postgresTestSuffix) {
+		return fmt.Errorf("%s names database %q, expected a name ending in %q", PostgresDSNEnv, name, postgresTestSuffix)
+	}
+	return nil
+}
+
+func (h *PostgresHarness) resetSchema() error {
+	return h.DB.Exec("DROP SCHEMA IF EXISTS public CASCADE; CREATE SCHEMA public").Error
+}
+
+// applyMigrations runs every *.up.sql file in db/migrations in filename order
+func (h *PostgresHarness) applyMigrations() error {
+	dir, err := MigrationsDir()
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filepath.Base(file), err)
+		}
+		if err := h.DB.Exec(string(content)).Error; err != nil {
+			return fmt.Errorf("migration %s failed: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// MigrationsDir locates db/migrations by walking up from the working directory
+func MigrationsDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, "db", "migrations")
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("db/migrations not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,115 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	appservices "github.com/growthfolio/go-priceguard-api/internal/application/services"
+)
+
+// StartMiniredis starts an in-memory Redis server and a client connected to it,
+// both stopped when the test ends
+func StartMiniredis(t testing.TB) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+// NotificationQueue reads the sorted sets behind a NotificationService queue
+type NotificationQueue struct {
+	t      testing.TB
+	client *redis.Client
+
+	QueueKey    string
+	DLQKey      string
+	InFlightKey string
+}
+
+// DeadLetter is a notification moved to the dead letter queue
+type DeadLetter struct {
+	Notification string `json:"notification"` // As it was queued, it may not be valid JSON
+	Reason       string `json:"reason"`
+}
+
+// NewNotificationQueue reads the queue of the service partitioned for region, or the
+// unpartitioned one when region is empty (see NotificationService.SetQueuePartition)
+func NewNotificationQueue(t testing.TB, client *redis.Client, region string) *NotificationQueue {
+	suffix := ""
+	if region != "" {
+		suffix = ":{" + region + "}"
+	}
+	return &NotificationQueue{
+		t:           t,
+		client:      client,
+		QueueKey:    "notification_queue" + suffix,
+		DLQKey:      "notification_dlq" + suffix,
+		InFlightKey: "notification_inflight" + suffix,
+	}
+}
+
+// Queued returns the queued notifications in delivery order
+func (q *NotificationQueue) Queued() []appservices.QueuedNotification {
+	q.t.Helper()
+	members, err := q.client.ZRange(context.Background(), q.QueueKey, 0, -1).Result()
+	if err != nil {
+		q.t.Fatalf("failed to read %s: %v", q.QueueKey, err)
+	}
+
+	notifications := make([]appservices.QueuedNotification, len(members))
+	for i, member := range members {
+		if err := json.Unmarshal([]byte(member), &notifications[i]); err != nil {
+			q.t.Fatalf("failed to parse queued notification %q: %v", member, err)
+		}
+	}
+	return notifications
+}
+
+// Score returns the queue score of notification, failing the test when it isn't queued
+func (q *NotificationQueue) Score(notification appservices.QueuedNotification) float64 {
+	q.t.Helper()
+	entries, err := q.client.ZRangeWithScores(context.Background(), q.QueueKey, 0, -1).Result()
+	if err != nil {
+		q.t.Fatalf("failed to read %s: %v", q.QueueKey, err)
+	}
+	for _, entry := range entries {
+		var queued appservices.QueuedNotification
+		if json.Unmarshal([]byte(entry.Member.(string)), &queued) == nil && queued.ID == notification.ID {
+			return entry.Score
+		}
+	}
+	q.t.Fatalf("notification %s is not queued", notification.ID)
+	return 0
+}
+
+// DeadLetters returns the dead letter queue, oldest first
+func (q *NotificationQueue) DeadLetters() []DeadLetter {
+	q.t.Helper()
+	members, err := q.client.ZRange(context.Background(), q.DLQKey, 0, -1).Result()
+	if err != nil {
+		q.t.Fatalf("failed to read %s: %v", q.DLQKey, err)
+	}
+
+	letters := make([]DeadLetter, len(members))
+	for i, member := range members {
+		if err := json.Unmarshal([]byte(member), &letters[i]); err != nil {
+			q.t.Fatalf("failed to parse dead letter %q: %v", member, err)
+		}
+	}
+	return letters
+}
+
+// InFlight returns how many notifications are claimed and not yet acknowledged
+func (q *NotificationQueue) InFlight() int {
+	q.t.Helper()
+	count, err := q.client.ZCard(context.Background(), q.InFlightKey).Result()
+	if err != nil {
+		q.t.Fatalf("failed to read %s: %v", q.InFlightKey, err)
+	}
+	return int(count)
+}
@@ -0,0 +1,118 @@
+package testutils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+)
+
+// SQLiteModels are the entities OpenSQLite creates tables for
+var SQLiteModels = []interface{}{
+	&entities.User{},
+	&entities.UserSettings{},
+	&entities.CryptoCurrency{},
+	&entities.AlertSet{},
+	&entities.Basket{},
+	&entities.Alert{},
+	&entities.AlertCondition{},
+	&entities.Notification{},
+	&entities.NotificationDelivery{},
+	&entities.PriceHistory{},
+	&entities.ExchangePrice{},
+	&entities.PriceSummary{},
+	&entities.TechnicalIndicator{},
+	&entities.Session{},
+	&entities.SystemBanner{},
+	&entities.SymbolRestriction{},
+	&entities.ShareLink{},
+	&entities.APIKey{},
+	&entities.AbuseFlag{},
+	&entities.DeviceToken{},
+	&entities.TelegramLink{},
+}
+
+var sqliteDatabases atomic.Int64
+
+// OpenSQLite opens a private in-memory SQLite database with every entity's table.
+// SQLite has no uuid_generate_v4(), so those column defaults are dropped and UUID
+// primary keys left empty are generated on insert, as Postgres would.
+func OpenSQLite(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	// A named shared-cache database keeps every pooled connection on the same data
+	dsn := fmt.Sprintf("file:testutils_%d?mode=memory&cache=shared", sqliteDatabases.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open SQLite: %v", err)
+	}
+
+	for _, model := range SQLiteModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("failed to parse %T: %v", model, err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if strings.Contains(field.DefaultValue, "uuid_generate_v4") {
+				field.DefaultValue = ""
+				field.HasDefaultValue = false
+			}
+		}
+	}
+
+	if err := db.Callback().Create().Before("gorm:create").Register("testutils:generate_uuid", generateUUIDs); err != nil {
+		t.Fatalf("failed to register UUID generation: %v", err)
+	}
+	if err := db.AutoMigrate(SQLiteModels...); err != nil {
+		t.Fatalf("failed to migrate SQLite: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// generateUUIDs fills empty UUID primary keys of the rows being created
+func generateUUIDs(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || field.FieldType != uuidType {
+		return
+	}
+
+	setID := func(row reflect.Value) {
+		if _, isZero := field.ValueOf(db.Statement.Context, row); isZero {
+			if err := field.Set(db.Statement.Context, row, uuid.New()); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+
+	rows := reflect.Indirect(db.Statement.ReflectValue)
+	switch rows.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rows.Len(); i++ {
+			setID(reflect.Indirect(rows.Index(i)))
+		}
+	case reflect.Struct:
+		setID(rows)
+	}
+}
@@ -0,0 +1,116 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+	"github.com/growthfolio/go-priceguard-api/pkg/clock"
+)
+
+// MemoryStorage is an in-memory storage.ObjectStorage for handler and service tests
+type MemoryStorage struct {
+	BaseURL string
+
+	mu           sync.RWMutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+	modified     map[string]time.Time
+	clock        clock.Clock
+}
+
+// NewMemoryStorage creates an empty in-memory object store
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		BaseURL:      "https://cdn.example.test",
+		objects:      make(map[string][]byte),
+		contentTypes: make(map[string]string),
+		modified:     make(map[string]time.Time),
+		clock:        clock.New(),
+	}
+}
+
+// SetClock sets the clock used to stamp LastModified
+func (m *MemoryStorage) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+func (m *MemoryStorage) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = append([]byte(nil), data...)
+	m.contentTypes[key] = contentType
+	m.modified[key] = m.clock.Now()
+	return nil
+}
+
+func (m *MemoryStorage) DeleteObject(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.objects[key]; !exists {
+		return storage.ErrObjectNotFound
+	}
+	delete(m.objects, key)
+	delete(m.contentTypes, key)
+	delete(m.modified, key)
+	return nil
+}
+
+func (m *MemoryStorage) PublicURL(key string) string {
+	return m.BaseURL + "/" + key
+}
+
+func (m *MemoryStorage) KeyFromURL(url string) (string, bool) {
+	if !strings.HasPrefix(url, m.BaseURL+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(url, m.BaseURL+"/"), true
+}
+
+func (m *MemoryStorage) PresignGetURL(key string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > storage.MaxPresignExpiry {
+		return "", fmt.Errorf("invalid presign expiry %s", expiry)
+	}
+	return fmt.Sprintf("%s/%s?expires=%d", m.BaseURL, key, m.clock.Now().Add(expiry).Unix()), nil
+}
+
+func (m *MemoryStorage) ListObjects(ctx context.Context, prefix string) ([]storage.ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	objects := make([]storage.ObjectInfo, 0)
+	for key, data := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, storage.ObjectInfo{Key: key, Size: int64(len(data)), LastModified: m.modified[key]})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// Object returns the stored bytes and content type for key
+func (m *MemoryStorage) Object(key string) ([]byte, string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, exists := m.objects[key]
+	return data, m.contentTypes[key], exists
+}
+
+// Keys returns every stored key
+func (m *MemoryStorage) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.objects))
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -39,11 +40,26 @@ func (m *MockAlertRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 	return args.Get(0).([]entities.Alert), args.Error(1)
 }
 
+func (m *MockAlertRepository) GetBySetID(ctx context.Context, setID uuid.UUID) ([]entities.Alert, error) {
+	args := m.Called(ctx, setID)
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) GetDependents(ctx context.Context, alertID uuid.UUID) ([]entities.Alert, error) {
+	args := m.Called(ctx, alertID)
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
 func (m *MockAlertRepository) GetEnabled(ctx context.Context) ([]entities.Alert, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]entities.Alert), args.Error(1)
 }
 
+func (m *MockAlertRepository) GetAwaitingAck(ctx context.Context) ([]entities.Alert, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entities.Alert), args.Error(1)
+}
+
 func (m *MockAlertRepository) Update(ctx context.Context, alert *entities.Alert) error {
 	args := m.Called(ctx, alert)
 	return args.Error(0)
@@ -495,3 +511,91 @@ func TestAlertHandler_DeleteAlert_InvalidID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestAlertHandler_CreateAlert_InvalidTargetForType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := &MockAlertRepository{}
+	handler := handlers.NewAlertHandler(mockRepo, nil, nil)
+
+	router := gin.New()
+	router.POST("/alerts", func(c *gin.Context) {
+		c.Set("user_id", uuid.New())
+		handler.CreateAlert(c)
+	})
+
+	alertData := map[string]interface{}{
+		"symbol":         "BTCUSDT",
+		"alert_type":     "rsi",
+		"condition_type": "above",
+		"target_value":   150.0,
+		"timeframe":      "1h",
+	}
+
+	jsonData, _ := json.Marshal(alertData)
+
+	req, _ := http.NewRequest("POST", "/alerts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "target_value")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAlertHandler_GetAlerts_DisplayStrings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repos := testutils.NewMemoryRepositories()
+	handler := handlers.NewAlertHandler(repos.Alerts, nil, nil)
+	handler.SetUserSettingsRepo(repos.UserSettings)
+	ctx := context.Background()
+
+	browsing, settled := uuid.New(), uuid.New()
+	for _, alert := range []*entities.Alert{
+		{UserID: browsing, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 50000.5, Timeframe: "1h"},
+		{UserID: settled, Symbol: "ETHBTC", AlertType: "percentage", ConditionType: "up", TargetValue: -5.5, Timeframe: "1h"},
+	} {
+		assert.NoError(t, repos.Alerts.Create(ctx, alert))
+	}
+	assert.NoError(t, repos.UserSettings.Create(ctx, &entities.UserSettings{UserID: settled, Locale: "fr-FR"}))
+
+	getAlerts := func(userID uuid.UUID, query, acceptLanguage string) []map[string]interface{} {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", userID)
+			c.Next()
+		})
+		router.GET("/alerts", handler.GetAlerts)
+
+		req, _ := http.NewRequest("GET", "/alerts"+query, nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	// Raw numbers only unless asked for
+	alerts := getAlerts(browsing, "", "de-DE")
+	assert.Equal(t, 50000.5, alerts[0]["target_value"])
+	assert.NotContains(t, alerts[0], "display")
+
+	// A language without a supported region matches one of its locales
+	alerts = getAlerts(browsing, "?display=true", "xx, de-CH;q=0.9, en;q=0.8")
+	assert.Equal(t, 50000.5, alerts[0]["target_value"])
+	assert.Equal(t, map[string]interface{}{"locale": "de-DE", "target_value": "50.000,50 $"}, alerts[0]["display"])
+
+	alerts = getAlerts(browsing, "?display=true", "")
+	assert.Equal(t, "$50,000.50", alerts[0]["display"].(map[string]interface{})["target_value"])
+
+	// The settings' locale always applies
+	alerts = getAlerts(settled, "", "en-US")
+	assert.Equal(t, map[string]interface{}{"locale": "fr-FR", "target_value": "-5,50 %"}, alerts[0]["display"])
+}
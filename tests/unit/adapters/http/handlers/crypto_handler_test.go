@@ -0,0 +1,185 @@
+package handlers_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHistoryRouter() *gin.Engine {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := new(testutils.MockPriceHistoryRepository)
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 100).Return([]entities.PriceHistory{
+		{Symbol: "BTCUSDT", Timeframe: "1h", OpenPrice: 100, HighPrice: 110, LowPrice: 95, ClosePrice: 105, Volume: 12.5, Timestamp: timestamp},
+	}, nil)
+
+	handler := handlers.NewCryptoHandler(new(testutils.MockCryptoCurrencyRepository), priceRepo, new(testutils.MockTechnicalIndicatorRepository))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/crypto/history/:symbol", middleware.CompressionMiddleware(), handler.GetPriceHistory)
+	return router
+}
+
+func TestCryptoHandler_GetPriceHistory_Compact(t *testing.T) {
+	router := setupHistoryRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crypto/history/BTCUSDT?format=compact", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Format  string      `json:"format"`
+		Columns []string    `json:"columns"`
+		Data    [][]float64 `json:"data"`
+		Count   int         `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "compact", body.Format)
+	assert.Equal(t, []string{"timestamp", "open", "high", "low", "close", "volume"}, body.Columns)
+	assert.Equal(t, [][]float64{{1714564800000, 100, 110, 95, 105, 12.5}}, body.Data)
+	assert.Equal(t, 1, body.Count)
+}
+
+func TestCryptoHandler_GetPriceHistory_Gzip(t *testing.T) {
+	router := setupHistoryRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/crypto/history/BTCUSDT", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	var body struct {
+		Data []entities.PriceHistory `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(reader).Decode(&body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, 105.0, body.Data[0].ClosePrice)
+}
+
+func TestCryptoHandler_GetPriceHistory_InvalidFormat(t *testing.T) {
+	router := setupHistoryRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crypto/history/BTCUSDT?format=csv", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCryptoHandler_GetCorrelation_Validation(t *testing.T) {
+	handler := handlers.NewCryptoHandler(new(testutils.MockCryptoCurrencyRepository), new(testutils.MockPriceHistoryRepository), new(testutils.MockTechnicalIndicatorRepository))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/crypto/correlation", handler.GetCorrelation)
+
+	for _, query := range []string{
+		"symbols=BTCUSDT",
+		"symbols=btcusdt,BTCUSDT",
+		"symbols=BTCUSDT,ETHUSDT&timeframe=2h",
+		"symbols=BTCUSDT,ETHUSDT&window=1",
+		"symbols=BTCUSDT,ETHUSDT&window=1000",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crypto/correlation?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestCryptoHandler_GetCorrelation_TranslatesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "upstream unavailable", err: fmt.Errorf("%w: timeout", entities.ErrUpstreamUnavailable), status: http.StatusServiceUnavailable},
+		{name: "insufficient data", err: entities.NewDomainError(entities.ErrInsufficientData, "no candles yet"), status: http.StatusUnprocessableEntity},
+		{name: "unknown error", err: errors.New("connection reset"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceRepo := new(testutils.MockPriceHistoryRepository)
+			priceRepo.On("GetBySymbol", mock.Anything, mock.Anything, "1h", mock.Anything).Return([]entities.PriceHistory(nil), tt.err)
+
+			handler := handlers.NewCryptoHandler(new(testutils.MockCryptoCurrencyRepository), priceRepo, new(testutils.MockTechnicalIndicatorRepository))
+			handler.SetCorrelationService(services.NewCorrelationService(priceRepo, logrus.New()))
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/api/crypto/correlation", handler.GetCorrelation)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crypto/correlation?symbols=BTCUSDT,ETHUSDT&timeframe=1h", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.NotContains(t, w.Body.String(), "timeout")
+			assert.NotContains(t, w.Body.String(), "connection reset")
+		})
+	}
+}
+
+func TestCryptoHandler_GetOHLCV_ResamplesOneMinuteCandles(t *testing.T) {
+	ctx := context.Background()
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+	start := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	for i := 0; i < 240; i++ {
+		price := 100 + float64(i)
+		require.NoError(t, priceRepo.Create(ctx, &entities.PriceHistory{
+			Symbol: "BTCUSDT", Timeframe: "1m", Timestamp: start.Add(time.Duration(i) * time.Minute),
+			OpenPrice: price, HighPrice: price + 1, LowPrice: price - 1, ClosePrice: price, Volume: 1,
+		}))
+	}
+
+	handler := handlers.NewCryptoHandler(new(testutils.MockCryptoCurrencyRepository), priceRepo, new(testutils.MockTechnicalIndicatorRepository))
+	handler.SetOHLCVService(services.NewOHLCVService(priceRepo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/crypto/ohlcv/:symbol", handler.GetOHLCV)
+
+	// 2h buckets are aligned to even hours, so 11:00-14:59 spans three of them
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/api/crypto/ohlcv/btcusdt?timeframe=2h&from=2024-05-01T11:30:00Z&to=2024-05-01T15:00:00Z&format=compact", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		From  time.Time   `json:"from"`
+		Data  [][]float64 `json:"data"`
+		Count int         `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), body.From)
+	require.Equal(t, 3, body.Count)
+	assert.Equal(t, []float64{1714557600000, 100, 160, 99, 159, 60}, body.Data[0])
+	assert.Equal(t, []float64{1714564800000, 160, 280, 159, 279, 120}, body.Data[1])
+
+	for _, query := range []string{
+		"timeframe=2x",
+		"timeframe=0h",
+		"from=yesterday",
+		"from=2024-05-01T12:00:00Z&to=2024-05-01T11:00:00Z",
+		"from=2024-01-01T00:00:00Z&to=2024-05-01T00:00:00Z",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crypto/ohlcv/BTCUSDT?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDeviceRouter(handler *handlers.DeviceHandler, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/api/user/devices", handler.ListDevices)
+	router.POST("/api/user/devices", handler.RegisterDevice)
+	router.DELETE("/api/user/devices/:id", handler.DeleteDevice)
+	return router
+}
+
+func registerDevice(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/user/devices", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeviceHandler_RegisterDevice(t *testing.T) {
+	userID := uuid.New()
+	deviceRepo := new(testutils.MockDeviceTokenRepository)
+	deviceRepo.On("GetByUserID", mock.Anything, userID).Return([]entities.DeviceToken{}, nil)
+	deviceRepo.On("Register", mock.Anything, mock.MatchedBy(func(d *entities.DeviceToken) bool {
+		return d.UserID == userID && d.Token == "fcm-token" && d.Platform == "android" && d.Name == "Pixel"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.DeviceToken).ID = uuid.New()
+	}).Return(nil)
+
+	w := registerDevice(setupDeviceRouter(handlers.NewDeviceHandler(deviceRepo), userID),
+		`{"token":"fcm-token","platform":"android","name":"Pixel"}`)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "android", response["platform"])
+	assert.NotContains(t, response, "token", "the registration token is never echoed back")
+	deviceRepo.AssertExpectations(t)
+}
+
+func TestDeviceHandler_RegisterDevice_RejectsBadInput(t *testing.T) {
+	router := setupDeviceRouter(handlers.NewDeviceHandler(new(testutils.MockDeviceTokenRepository)), uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, registerDevice(router, `{"platform":"android"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, registerDevice(router, `{"token":"fcm-token","platform":"blackberry"}`).Code)
+}
+
+func TestDeviceHandler_RegisterDevice_LimitsDevices(t *testing.T) {
+	userID := uuid.New()
+	devices := make([]entities.DeviceToken, 20)
+	for i := range devices {
+		devices[i] = entities.DeviceToken{ID: uuid.New(), UserID: userID, Token: uuid.NewString(), Platform: "web"}
+	}
+	deviceRepo := new(testutils.MockDeviceTokenRepository)
+	deviceRepo.On("GetByUserID", mock.Anything, userID).Return(devices, nil)
+	deviceRepo.On("Register", mock.Anything, mock.Anything).Return(nil)
+	router := setupDeviceRouter(handlers.NewDeviceHandler(deviceRepo), userID)
+
+	assert.Equal(t, http.StatusConflict, registerDevice(router, `{"token":"new-token","platform":"web"}`).Code)
+
+	// Re-registering a known token is still allowed
+	assert.Equal(t, http.StatusCreated, registerDevice(router, `{"token":"`+devices[0].Token+`","platform":"web"}`).Code)
+}
+
+func TestDeviceHandler_DeleteDevice(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	deviceRepo := new(testutils.MockDeviceTokenRepository)
+	deviceRepo.On("Delete", mock.Anything, deviceID, userID).Return(nil)
+	deviceRepo.On("Delete", mock.Anything, mock.Anything, userID).Return(errors.New("record not found"))
+	router := setupDeviceRouter(handlers.NewDeviceHandler(deviceRepo), userID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/user/devices/"+deviceID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/user/devices/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/user/devices/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTelegramBot accepts every message without calling Telegram
+type stubTelegramBot struct{}
+
+func (stubTelegramBot) SendMessage(ctx context.Context, chatID, text string) error { return nil }
+func (stubTelegramBot) GetMe(ctx context.Context) (string, error)                  { return "priceguard_bot", nil }
+
+func setupTelegramRouter(linkService *services.TelegramLinkService, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTelegramHandler(linkService, "webhook-secret")
+	router := gin.New()
+	router.POST("/api/telegram/webhook", handler.Webhook)
+
+	protected := router.Group("/api/notifications", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	protected.GET("/channels/telegram/link", handler.GetTelegramLink)
+	protected.POST("/channels/telegram/link", handler.LinkTelegram)
+	protected.DELETE("/channels/telegram/link", handler.UnlinkTelegram)
+	return router
+}
+
+func newTelegramLinkService(bot services.TelegramSender, botUsername string) *services.TelegramLinkService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return services.NewTelegramLinkService(testutils.NewMemoryTelegramLinkRepository(), bot, botUsername, logger)
+}
+
+func serveTelegram(router *gin.Engine, method, path, secret, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTelegramHandler_LinkFlow(t *testing.T) {
+	router := setupTelegramRouter(newTelegramLinkService(stubTelegramBot{}, "priceguard_bot"), uuid.New())
+	const linkPath = "/api/notifications/channels/telegram/link"
+
+	w := serveTelegram(router, http.MethodGet, linkPath, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"linked":false}`, w.Body.String())
+
+	w = serveTelegram(router, http.MethodPost, linkPath, "", "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var code services.TelegramLinkCode
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &code))
+	assert.Equal(t, "https://t.me/priceguard_bot?start="+code.Code, code.URL)
+
+	update := `{"update_id":7,"message":{"text":"/start ` + code.Code + `","chat":{"id":42,"type":"private","username":"satoshi"}}}`
+	w = serveTelegram(router, http.MethodPost, "/api/telegram/webhook", "webhook-secret", update)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveTelegram(router, http.MethodGet, linkPath, "", "")
+	var link map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.Equal(t, true, link["linked"])
+	assert.Equal(t, "satoshi", link["username"])
+	assert.NotContains(t, link, "chat_id")
+
+	assert.Equal(t, http.StatusOK, serveTelegram(router, http.MethodDelete, linkPath, "", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveTelegram(router, http.MethodDelete, linkPath, "", "").Code)
+}
+
+func TestTelegramHandler_Webhook_RejectsBadSecret(t *testing.T) {
+	router := setupTelegramRouter(newTelegramLinkService(stubTelegramBot{}, "priceguard_bot"), uuid.New())
+	update := `{"update_id":7,"message":{"text":"/start","chat":{"id":42,"type":"private"}}}`
+
+	assert.Equal(t, http.StatusForbidden, serveTelegram(router, http.MethodPost, "/api/telegram/webhook", "", update).Code)
+	assert.Equal(t, http.StatusForbidden, serveTelegram(router, http.MethodPost, "/api/telegram/webhook", "wrong", update).Code)
+	assert.Equal(t, http.StatusOK, serveTelegram(router, http.MethodPost, "/api/telegram/webhook", "webhook-secret", "not json").Code)
+}
+
+func TestTelegramHandler_LinkTelegram_NotConfigured(t *testing.T) {
+	router := setupTelegramRouter(newTelegramLinkService(nil, ""), uuid.New())
+
+	w := serveTelegram(router, http.MethodPost, "/api/notifications/channels/telegram/link", "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,167 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough for http.DetectContentType to report image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func avatarRequest(t *testing.T, data []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("avatar", "avatar.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPut, "/api/user/avatar", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func setupAvatarRouter(handler *handlers.UserHandler, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/api/user/avatar", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		handler.UploadAvatar(c)
+	})
+	return router
+}
+
+func TestUserHandler_UploadAvatar_Success(t *testing.T) {
+	userID := uuid.New()
+	googlePicture := "https://lh3.googleusercontent.com/a/photo.jpg"
+	user := &entities.User{ID: userID, Name: "Ana", Picture: &googlePicture}
+
+	userRepo := new(testutils.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+	userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *entities.User) bool {
+		return u.Avatar != nil && strings.HasPrefix(*u.Avatar, "https://cdn.example.test/avatars/"+userID.String()+"/")
+	})).Return(nil)
+
+	avatarStorage := testutils.NewMemoryStorage()
+	handler := handlers.NewUserHandler(userRepo, nil)
+	handler.SetAvatarStorage(avatarStorage, 1024)
+
+	w := httptest.NewRecorder()
+	setupAvatarRouter(handler, userID).ServeHTTP(w, avatarRequest(t, append(pngHeader, make([]byte, 64)...)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response entities.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Avatar)
+	assert.Equal(t, *response.Avatar, response.AvatarURL)
+	assert.Equal(t, googlePicture, *response.Picture)
+
+	keys := avatarStorage.Keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasSuffix(keys[0], ".png"))
+	_, contentType, _ := avatarStorage.Object(keys[0])
+	assert.Equal(t, "image/png", contentType)
+	userRepo.AssertExpectations(t)
+}
+
+func TestUserHandler_UploadAvatar_ReplacesPreviousUpload(t *testing.T) {
+	userID := uuid.New()
+	avatarStorage := testutils.NewMemoryStorage()
+	oldKey := "avatars/" + userID.String() + "/old.png"
+	require.NoError(t, avatarStorage.PutObject(context.Background(), oldKey, pngHeader, "image/png"))
+	oldURL := avatarStorage.PublicURL(oldKey)
+
+	userRepo := new(testutils.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&entities.User{ID: userID, Avatar: &oldURL}, nil)
+	userRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	handler := handlers.NewUserHandler(userRepo, nil)
+	handler.SetAvatarStorage(avatarStorage, 1024)
+
+	w := httptest.NewRecorder()
+	setupAvatarRouter(handler, userID).ServeHTTP(w, avatarRequest(t, pngHeader))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	_, _, stillThere := avatarStorage.Object(oldKey)
+	assert.False(t, stillThere)
+	assert.Len(t, avatarStorage.Keys(), 1)
+}
+
+func TestUserHandler_UploadAvatar_RejectsInvalidUploads(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected int
+	}{
+		{name: "not an image", data: []byte("plain text, definitely not an image"), expected: http.StatusUnsupportedMediaType},
+		{name: "too large", data: append(pngHeader, make([]byte, 2048)...), expected: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(testutils.MockUserRepository)
+			avatarStorage := testutils.NewMemoryStorage()
+			handler := handlers.NewUserHandler(userRepo, nil)
+			handler.SetAvatarStorage(avatarStorage, 1024)
+
+			w := httptest.NewRecorder()
+			setupAvatarRouter(handler, userID).ServeHTTP(w, avatarRequest(t, tt.data))
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Empty(t, avatarStorage.Keys())
+			userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUserHandler_UploadAvatar_StorageNotConfigured(t *testing.T) {
+	handler := handlers.NewUserHandler(new(testutils.MockUserRepository), nil)
+
+	w := httptest.NewRecorder()
+	setupAvatarRouter(handler, uuid.New()).ServeHTTP(w, avatarRequest(t, pngHeader))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestUserHandler_GetProfile_FallsBackToGooglePicture(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	picture := "https://lh3.googleusercontent.com/a/photo.jpg"
+
+	userRepo := new(testutils.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&entities.User{ID: userID, Picture: &picture}, nil)
+	handler := handlers.NewUserHandler(userRepo, nil)
+
+	router := gin.New()
+	router.GET("/api/user/profile", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		handler.GetProfile(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/profile", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, picture, response["avatar_url"])
+}
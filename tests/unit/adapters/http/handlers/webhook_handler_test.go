@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/handlers"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func verifyWebhook(t *testing.T, router *gin.Engine, body string, sig services.WebhookSignature) map[string]interface{} {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/verify", bytes.NewBufferString(body))
+	for name, value := range sig.Headers() {
+		req.Header.Set(name, value)
+	}
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestWebhookHandler_VerifiesEachSignedDeliveryOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	signer := services.NewWebhookSigner("signing-secret")
+	signer.SetClock(clock)
+	nonces := services.NewMemoryThrottleStore()
+	nonces.SetClock(clock)
+	signer.SetNonceStore(nonces)
+	router := gin.New()
+	router.POST("/api/webhooks/verify", handlers.NewWebhookHandler(signer, logger).VerifyWebhook)
+
+	body := `{"event":"alert.triggered","symbol":"BTCUSDT","price":65000}`
+	sig, err := signer.Sign([]byte(body))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"valid": true}, verifyWebhook(t, router, body, sig))
+	assert.Equal(t, "replayed", verifyWebhook(t, router, body, sig)["reason"])
+
+	// A tampered body or a signature made with another secret don't match
+	sig, err = signer.Sign([]byte(body))
+	require.NoError(t, err)
+	assert.Equal(t, "signature_mismatch", verifyWebhook(t, router, `{"event":"alert.triggered","symbol":"BTCUSDT","price":1}`, sig)["reason"])
+	forged, err := services.NewWebhookSigner("other-secret").Sign([]byte(body))
+	require.NoError(t, err)
+	assert.Equal(t, "signature_mismatch", verifyWebhook(t, router, body, forged)["reason"])
+
+	// Deliveries signed too long ago are refused even when never seen
+	clock.Advance(services.DefaultWebhookTolerance + time.Second)
+	assert.Equal(t, "timestamp_out_of_range", verifyWebhook(t, router, body, sig)["reason"])
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/webhooks/verify", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
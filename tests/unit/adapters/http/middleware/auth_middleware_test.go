@@ -0,0 +1,72 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAdminRouter(user *entities.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	authMiddleware := middleware.NewAuthMiddleware(nil, logger)
+
+	isAdmin := func(email string) bool { return email == "admin@example.com" }
+
+	router := gin.New()
+	router.GET("/test/ping", func(c *gin.Context) {
+		if user != nil {
+			c.Set(middleware.UserContextKey, user)
+			c.Set(middleware.UserIDContextKey, user.ID)
+		}
+		c.Next()
+	}, authMiddleware.RequireAdmin(isAdmin), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         *entities.User
+		expectedCode int
+	}{
+		{name: "admin allowed", user: &entities.User{ID: uuid.New(), Email: "admin@example.com"}, expectedCode: http.StatusOK},
+		{name: "regular user forbidden", user: &entities.User{ID: uuid.New(), Email: "user@example.com"}, expectedCode: http.StatusForbidden},
+		{name: "anonymous rejected", user: nil, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAdminRouter(tt.user)
+
+			req, _ := http.NewRequest("GET", "/test/ping", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestDebugRoutesRateLimitConfig(t *testing.T) {
+	config := middleware.DebugRoutesRateLimitConfig()
+	assert.Less(t, config.RequestsPerMinute, middleware.DefaultRateLimitConfig().RequestsPerMinute)
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	userID := uuid.New()
+	c.Set("user_id", userID)
+	assert.Equal(t, "rate_limit:debug:user:"+userID.String(), config.KeyGenerator(c))
+}
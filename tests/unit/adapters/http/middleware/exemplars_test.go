@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/http/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scrapeOpenMetrics exposes histogram the way Prometheus scrapes it with exemplar
+// storage enabled
+func scrapeOpenMetrics(t *testing.T, histogram prometheus.Histogram) string {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(histogram)
+
+	req := httptest.NewRequest(http.MethodGet, "/prometheus", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}).ServeHTTP(w, req)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestObserveWithTraceExemplar_LinksSampledTraces(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	sampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{0, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "sampled_duration_seconds", Buckets: []float64{.1, 1}})
+	middleware.ObserveWithTraceExemplar(sampled, histogram, 0.5)
+
+	metrics := scrapeOpenMetrics(t, histogram)
+	assert.Contains(t, metrics, `sampled_duration_seconds_bucket{le="1.0"} 1 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.5`)
+}
+
+func TestObserveWithTraceExemplar_ObservesWithoutExemplarOutsideSampledTraces(t *testing.T) {
+	unsampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	}))
+
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "unsampled_duration_seconds", Buckets: []float64{.1, 1}})
+	middleware.ObserveWithTraceExemplar(context.Background(), histogram, 0.05)
+	middleware.ObserveWithTraceExemplar(unsampled, histogram, 0.5)
+
+	metrics := scrapeOpenMetrics(t, histogram)
+	assert.Contains(t, metrics, `unsampled_duration_seconds_count 2`)
+	assert.NotContains(t, metrics, "trace_id")
+}
@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Both repositories keep set names unique per user and find alerts by set
+func TestAlertSetRepository_CRUDAndAlertsBySet(t *testing.T) {
+	db := testutils.OpenSQLite(t)
+	type repos struct {
+		sets   repositories.AlertSetRepository
+		alerts repositories.AlertRepository
+	}
+	all := map[string]repos{
+		"gorm":   {repository.NewAlertSetRepository(db), repository.NewAlertRepository(db)},
+		"memory": {testutils.NewMemoryAlertSetRepository(), testutils.NewMemoryAlertRepository()},
+	}
+
+	for name, r := range all {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			userID, otherUserID := uuid.New(), uuid.New()
+
+			swing := &entities.AlertSet{UserID: userID, Name: "BTC swing plan"}
+			require.NoError(t, r.sets.Create(ctx, swing))
+			require.NoError(t, r.sets.Create(ctx, &entities.AlertSet{UserID: userID, Name: "Alt breakouts"}))
+			require.NoError(t, r.sets.Create(ctx, &entities.AlertSet{UserID: otherUserID, Name: "BTC swing plan"}))
+			assert.Error(t, r.sets.Create(ctx, &entities.AlertSet{UserID: userID, Name: "BTC swing plan"}))
+
+			sets, err := r.sets.GetByUserID(ctx, userID)
+			require.NoError(t, err)
+			require.Len(t, sets, 2)
+			assert.Equal(t, "Alt breakouts", sets[0].Name)
+			assert.Equal(t, "BTC swing plan", sets[1].Name)
+
+			swing.Description = "Entries on the daily pullback"
+			require.NoError(t, r.sets.Update(ctx, swing))
+			stored, err := r.sets.GetByID(ctx, swing.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Entries on the daily pullback", stored.Description)
+
+			builder := testutils.NewAlertBuilder().ForUser(userID).Price("BTCUSDT").Above(70000)
+			inSet := builder.InSet(swing.ID).Build()
+			require.NoError(t, r.alerts.Create(ctx, inSet))
+			require.NoError(t, r.alerts.Create(ctx, testutils.NewAlertBuilder().ForUser(userID).Price("BTCUSDT").Below(60000).Build()))
+
+			alerts, err := r.alerts.GetBySetID(ctx, swing.ID)
+			require.NoError(t, err)
+			require.Len(t, alerts, 1)
+			assert.Equal(t, inSet.ID, alerts[0].ID)
+
+			require.NoError(t, r.sets.Delete(ctx, swing.ID))
+			_, err = r.sets.GetByID(ctx, swing.ID)
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			assert.ErrorIs(t, r.sets.Delete(ctx, swing.ID), gorm.ErrRecordNotFound)
+			assert.ErrorIs(t, r.sets.Update(ctx, swing), gorm.ErrRecordNotFound)
+		})
+	}
+}
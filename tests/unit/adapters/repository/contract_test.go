@@ -0,0 +1,21 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils/contract"
+)
+
+// The in-memory repositories must behave like the GORM ones they stand in for
+func TestMemoryRepositoriesContract(t *testing.T) {
+	contract.RunAll(t, func(t *testing.T) *contract.Harness {
+		return &contract.Harness{
+			Alerts:        testutils.NewMemoryAlertRepository(),
+			Notifications: testutils.NewMemoryNotificationRepository(),
+			PriceHistory:  testutils.NewMemoryPriceHistoryRepository(),
+			NewUser:       func(t *testing.T) uuid.UUID { return uuid.New() },
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMemoryUserRepository_EnforcesUniqueEmail(t *testing.T) {
+	ctx := context.Background()
+	repo := testutils.NewMemoryUserRepository()
+
+	user := &entities.User{GoogleID: "gid-1", Email: "test@example.com", Name: "Test"}
+	require.NoError(t, repo.Create(ctx, user))
+	assert.NotEqual(t, uuid.Nil, user.ID)
+	assert.Error(t, repo.Create(ctx, &entities.User{GoogleID: "gid-2", Email: "test@example.com", Name: "Other"}))
+
+	found, err := repo.GetByGoogleID(ctx, "gid-1")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	_, err = repo.GetByEmail(ctx, "missing@example.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestMemorySessionRepository_ExpiresWithClock(t *testing.T) {
+	ctx := context.Background()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	repo := testutils.NewMemorySessionRepository()
+	repo.SetClock(clock)
+	userID := uuid.New()
+
+	require.NoError(t, repo.Create(ctx, &entities.Session{UserID: userID, TokenHash: "short", ExpiresAt: clock.Now().Add(time.Hour)}))
+	require.NoError(t, repo.Create(ctx, &entities.Session{UserID: userID, TokenHash: "long", ExpiresAt: clock.Now().Add(24 * time.Hour)}))
+	assert.Error(t, repo.Create(ctx, &entities.Session{UserID: userID, TokenHash: "long", ExpiresAt: clock.Now().Add(time.Hour)}))
+
+	clock.Advance(2 * time.Hour)
+	sessions, err := repo.GetByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "long", sessions[0].TokenHash)
+
+	require.NoError(t, repo.DeleteExpired(ctx))
+	_, err = repo.GetByTokenHash(ctx, "short")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestMemoryDeviceTokenRepository_RegisterMovesTokenBetweenUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := testutils.NewMemoryDeviceTokenRepository()
+	first, second := uuid.New(), uuid.New()
+
+	device := &entities.DeviceToken{UserID: first, Token: "shared-phone", Platform: "android"}
+	require.NoError(t, repo.Register(ctx, device))
+	moved := &entities.DeviceToken{UserID: second, Token: "shared-phone", Platform: "android"}
+	require.NoError(t, repo.Register(ctx, moved))
+	assert.Equal(t, device.ID, moved.ID, "the existing row is reused")
+
+	devices, _ := repo.GetByUserID(ctx, first)
+	assert.Empty(t, devices)
+	devices, _ = repo.GetByUserID(ctx, second)
+	assert.Len(t, devices, 1)
+
+	assert.ErrorIs(t, repo.Delete(ctx, moved.ID, first), gorm.ErrRecordNotFound)
+	pruned, err := repo.DeleteByTokens(ctx, []string{"shared-phone", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+}
+
+func TestMemoryTechnicalIndicatorRepository_MatchesMetadataLikeJSONB(t *testing.T) {
+	ctx := context.Background()
+	repo := testutils.NewMemoryTechnicalIndicatorRepository()
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	value := 42.0
+
+	require.NoError(t, repo.BulkInsert(ctx, []entities.TechnicalIndicator{
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_12", Value: &value, Metadata: map[string]interface{}{"period": 12}, Timestamp: base},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_26", Value: &value, Metadata: map[string]interface{}{"period": 26}, Timestamp: base},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_26", Value: &value, Metadata: map[string]interface{}{"period": 26}, Timestamp: base.Add(time.Hour)},
+	}))
+
+	latest, err := repo.GetLatestByMetadata(ctx, "BTCUSDT", "1h", "EMA", map[string]interface{}{"period": 26.0})
+	require.NoError(t, err)
+	assert.Equal(t, "EMA_26", latest.IndicatorKey)
+	assert.True(t, latest.Timestamp.Equal(base.Add(time.Hour)))
+
+	// A duplicate rolls back the whole batch
+	err = repo.BulkInsert(ctx, []entities.TechnicalIndicator{
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_12", Timestamp: base.Add(time.Hour)},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_12", Timestamp: base},
+	})
+	assert.Error(t, err)
+	series, _ := repo.GetByKey(ctx, "BTCUSDT", "1h", "EMA_12", 0)
+	assert.Len(t, series, 1)
+}
+
+func TestMemoryRepositories_ConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	userID := uuid.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repos.AbuseFlags.Create(ctx, &entities.AbuseFlag{UserID: userID, Reason: "alert_volume", ThrottledUntil: time.Now().Add(time.Hour)}))
+			assert.NoError(t, repos.ShareLinks.Create(ctx, &entities.ShareLink{UserID: userID, TokenHash: uuid.NewString(), ExpiresAt: time.Now().Add(time.Hour)}))
+		}()
+	}
+	wg.Wait()
+
+	flags, err := repos.AbuseFlags.GetActive(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, flags, 50)
+	links, err := repos.ShareLinks.GetByUserID(ctx, userID, 20, 40)
+	require.NoError(t, err)
+	assert.Len(t, links, 10)
+}
@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The GORM aggregation and the in-memory one must report the same windows
+func TestNotificationDeliveryRepository_ChannelWindows(t *testing.T) {
+	repos := map[string]repositories.NotificationDeliveryRepository{
+		"gorm":   repository.NewNotificationDeliveryRepository(testutils.OpenSQLite(t)),
+		"memory": testutils.NewMemoryNotificationDeliveryRepository(),
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deliveries := []entities.NotificationDelivery{
+		{Channel: "email", Status: entities.DeliveryStatusSent, Attempt: 1, LatencyMs: 200, DeliveredAt: now.Add(-time.Minute)},
+		{Channel: "email", Status: entities.DeliveryStatusSent, Attempt: 2, LatencyMs: 600, DeliveredAt: now.Add(-2 * time.Minute)},
+		{Channel: "email", Status: entities.DeliveryStatusFailed, Attempt: 1, DeliveredAt: now.Add(-3 * time.Minute)},
+		{Channel: "push", Status: entities.DeliveryStatusSkipped, Attempt: 1, DeliveredAt: now.Add(-time.Minute)},
+		{Channel: "push", Status: entities.DeliveryStatusFailed, Attempt: 1, DeliveredAt: now.Add(-2 * time.Hour)},
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, delivery := range deliveries {
+				delivery.NotificationID = uuid.New()
+				delivery.UserID = uuid.New()
+				require.NoError(t, repo.Create(ctx, &delivery))
+			}
+
+			windows, err := repo.ChannelWindows(ctx, now.Add(-time.Hour))
+			require.NoError(t, err)
+			require.Len(t, windows, 2)
+
+			assert.Equal(t, "email", windows[0].Channel)
+			assert.Equal(t, int64(2), windows[0].Sent)
+			assert.Equal(t, int64(1), windows[0].Failed)
+			assert.Equal(t, int64(1), windows[0].Retries)
+			assert.InDelta(t, 400, windows[0].AvgLatencyMs, 0.001)
+			assert.InDelta(t, 2.0/3.0, windows[0].SuccessRate, 0.001)
+
+			// Skipped deliveries don't lower the success rate
+			assert.Equal(t, entities.ChannelDeliveryWindow{Channel: "push", Skipped: 1, SuccessRate: 1}, windows[1])
+
+			removed, err := repo.DeleteOlderThan(ctx, now.Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), removed)
+		})
+	}
+}
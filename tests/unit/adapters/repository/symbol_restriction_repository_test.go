@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Both repositories keep one restriction per symbol, replaced in place
+func TestSymbolRestrictionRepository_UpsertAndDelete(t *testing.T) {
+	repos := map[string]repositories.SymbolRestrictionRepository{
+		"gorm":   repository.NewSymbolRestrictionRepository(testutils.OpenSQLite(t)),
+		"memory": testutils.NewMemorySymbolRestrictionRepository(),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			greylist := &entities.SymbolRestriction{Symbol: "LUNAUSDT", Level: entities.SymbolGreylisted, Reason: "wash trading"}
+			require.NoError(t, repo.Upsert(ctx, greylist))
+			require.NoError(t, repo.Upsert(ctx, &entities.SymbolRestriction{Symbol: "BTTUSDT", Level: entities.SymbolBlacklisted, Reason: "delisted"}))
+
+			blacklist := &entities.SymbolRestriction{Symbol: "LUNAUSDT", Level: entities.SymbolBlacklisted, Reason: "delisted"}
+			require.NoError(t, repo.Upsert(ctx, blacklist))
+			assert.Equal(t, greylist.ID, blacklist.ID)
+
+			stored, err := repo.GetBySymbol(ctx, "LUNAUSDT")
+			require.NoError(t, err)
+			assert.Equal(t, entities.SymbolBlacklisted, stored.Level)
+			assert.Equal(t, "delisted", stored.Reason)
+
+			all, err := repo.GetAll(ctx)
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+			assert.Equal(t, "BTTUSDT", all[0].Symbol)
+			assert.Equal(t, "LUNAUSDT", all[1].Symbol)
+
+			require.NoError(t, repo.Delete(ctx, "LUNAUSDT"))
+			_, err = repo.GetBySymbol(ctx, "LUNAUSDT")
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			assert.ErrorIs(t, repo.Delete(ctx, "LUNAUSDT"), gorm.ErrRecordNotFound)
+		})
+	}
+}
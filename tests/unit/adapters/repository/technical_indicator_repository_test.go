@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/adapters/repository"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Both repositories list each stored series of a market once
+func TestTechnicalIndicatorRepository_GetIndicatorKeys(t *testing.T) {
+	repos := map[string]repositories.TechnicalIndicatorRepository{
+		"gorm":   repository.NewTechnicalIndicatorRepository(testutils.OpenSQLite(t)),
+		"memory": testutils.NewMemoryTechnicalIndicatorRepository(),
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	indicators := []entities.TechnicalIndicator{
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "RSI", IndicatorKey: "RSI_14", Timestamp: now},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "RSI", IndicatorKey: "RSI_14", Timestamp: now.Add(time.Hour)},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "MACD", IndicatorKey: "MACD_12_26_9", Timestamp: now},
+		{Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: "ATR", IndicatorKey: "ATR_14", Timestamp: now},
+		{Symbol: "BTCUSDT", Timeframe: "4h", IndicatorType: "EMA", IndicatorKey: "EMA_12", Timestamp: now},
+		{Symbol: "ETHUSDT", Timeframe: "1h", IndicatorType: "EMA", IndicatorKey: "EMA_26", Timestamp: now},
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for i, indicator := range indicators {
+				value := 1.0
+				indicator.Value = &value
+				// The SQLite schema only has the timestamp of the unique index
+				indicator.Timestamp = indicator.Timestamp.Add(time.Duration(i) * time.Minute)
+				require.NoError(t, repo.Create(ctx, &indicator))
+			}
+
+			keys, err := repo.GetIndicatorKeys(ctx, "BTCUSDT", "1h")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ATR_14", "MACD_12_26_9", "RSI_14"}, keys)
+
+			keys, err = repo.GetIndicatorKeys(ctx, "SOLUSDT", "1h")
+			require.NoError(t, err)
+			assert.Empty(t, keys)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package websocket_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_SymbolSubscriptionIncludesAlertLevels(t *testing.T) {
+	f := newResumeFixture(t)
+
+	var lookedUp []string
+	f.hub.SetAlertLevelSource(func(ctx context.Context, userID uuid.UUID, symbol string) ([]services.AlertLevel, error) {
+		assert.Equal(t, f.user.ID, userID)
+		lookedUp = append(lookedUp, symbol)
+		return []services.AlertLevel{{AlertID: uuid.New(), ConditionType: "above", TargetValue: 52000}}, nil
+	})
+
+	conn, _ := f.connect(t, "")
+	subscribe(t, conn, "crypto_BTCUSDT")
+	subscribed := readMessage(t, conn, "subscribed")
+	levels, ok := subscribed["alert_levels"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, levels, 1)
+	assert.Equal(t, 52000.0, levels[0].(map[string]interface{})["target_value"])
+
+	// Rooms without a symbol are confirmed without levels
+	subscribe(t, conn, "market_summary")
+	assert.NotContains(t, readMessage(t, conn, "subscribed"), "alert_levels")
+	assert.Equal(t, []string{"BTCUSDT"}, lookedUp)
+}
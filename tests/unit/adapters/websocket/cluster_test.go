@@ -0,0 +1,184 @@
+package websocket_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterInstance is one hub of an active-active deployment sharing a Redis
+type clusterInstance struct {
+	hub *ws.Hub
+	url string
+}
+
+func newClusterInstance(t *testing.T, client *redis.Client, instanceID string, user *entities.User) *clusterInstance {
+	t.Helper()
+
+	mockAuth := &MockAuthService{}
+	mockAuth.On("ValidateToken", "valid_token").Return(user, nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	hub := ws.NewHub(mockAuth, logger)
+	hub.SetResumeStore(ws.NewRedisResumeStore(client))
+	hub.SetRelay(ws.NewRedisRelay(client, ws.DefaultRelayChannel), instanceID)
+	hub.SetPresenceStore(ws.NewRedisPresenceStore(client))
+	go hub.Start()
+	t.Cleanup(hub.Stop)
+
+	router := gin.New()
+	router.GET("/ws", hub.HandleWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &clusterInstance{
+		hub: hub,
+		url: "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=valid_token",
+	}
+}
+
+func (c *clusterInstance) connect(t *testing.T, resumeToken string) (*gws.Conn, map[string]interface{}) {
+	t.Helper()
+
+	url := c.url
+	if resumeToken != "" {
+		url += "&resume=" + resumeToken
+	}
+	conn, _, err := gws.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, readMessage(t, conn, "welcome")
+}
+
+func newCluster(t *testing.T, user *entities.User) (*clusterInstance, *clusterInstance) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	a := newClusterInstance(t, client, "instance-a", user)
+	b := newClusterInstance(t, client, "instance-b", user)
+
+	// Both hubs must be subscribed before anything is published
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub(ws.DefaultRelayChannel)[ws.DefaultRelayChannel] == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	return a, b
+}
+
+func TestHub_RelaysBroadcastsBetweenInstances(t *testing.T) {
+	user := &entities.User{ID: uuid.New()}
+	a, b := newCluster(t, user)
+
+	conn, _ := a.connect(t, "")
+	subscribe(t, conn, "crypto_BTCUSDT")
+	readMessage(t, conn, "subscribed")
+
+	b.hub.BroadcastToUser(user.ID, "alert_triggered", map[string]interface{}{"symbol": "BTCUSDT"})
+	data := readMessage(t, conn, "alert_triggered")
+	assert.Equal(t, "BTCUSDT", data["symbol"])
+
+	b.hub.Broadcast("crypto_BTCUSDT", "system_alert", map[string]interface{}{"title": "Maintenance"})
+	data = readMessage(t, conn, "system_alert")
+	assert.Equal(t, "Maintenance", data["title"])
+}
+
+func TestHub_BroadcastLocalIsNotRelayed(t *testing.T) {
+	user := &entities.User{ID: uuid.New()}
+	a, b := newCluster(t, user)
+
+	conn, _ := a.connect(t, "")
+	subscribe(t, conn, "crypto_BTCUSDT")
+	readMessage(t, conn, "subscribed")
+
+	b.hub.BroadcastLocal("crypto_BTCUSDT", "crypto_data_update", map[string]interface{}{"from": "b"})
+	a.hub.BroadcastLocal("crypto_BTCUSDT", "crypto_data_update", map[string]interface{}{"from": "a"})
+
+	data := readMessage(t, conn, "crypto_data_update")
+	assert.Equal(t, "a", data["from"])
+}
+
+func TestHub_ResumesSessionOnAnotherInstance(t *testing.T) {
+	user := &entities.User{ID: uuid.New()}
+	a, b := newCluster(t, user)
+
+	conn, welcome := a.connect(t, "")
+	token := welcome["resume_token"].(string)
+	subscribe(t, conn, "crypto_ETHUSDT")
+	readMessage(t, conn, "subscribed")
+	conn.Close()
+	require.Eventually(t, func() bool { return a.hub.GetConnectedClients() == 0 }, 2*time.Second, 10*time.Millisecond)
+
+	_, welcome = b.connect(t, token)
+	assert.Equal(t, true, welcome["resumed"])
+	assert.ElementsMatch(t, []interface{}{"crypto_ETHUSDT"}, welcome["rooms"])
+	assert.Equal(t, 1, b.hub.GetRooms()["crypto_ETHUSDT"])
+
+	// The token was consumed on b, so it can't be replayed on a
+	_, welcome = a.connect(t, token)
+	assert.Equal(t, false, welcome["resumed"])
+}
+
+func TestHub_SharesPresenceBetweenInstances(t *testing.T) {
+	user := &entities.User{ID: uuid.New()}
+	a, b := newCluster(t, user)
+	ctx := context.Background()
+	assert.False(t, b.hub.IsUserConnected(ctx, user.ID))
+
+	first, _ := a.connect(t, "")
+	second, _ := a.connect(t, "")
+	assert.True(t, a.hub.IsUserConnected(ctx, user.ID))
+	assert.True(t, b.hub.IsUserConnected(ctx, user.ID))
+	assert.False(t, b.hub.IsUserConnected(ctx, uuid.New()))
+
+	// Connected until the user's last client on a is gone
+	first.Close()
+	require.Eventually(t, func() bool { return a.hub.GetConnectedClients() == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, b.hub.IsUserConnected(ctx, user.ID))
+	second.Close()
+	require.Eventually(t, func() bool { return a.hub.GetConnectedClients() == 0 }, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, b.hub.IsUserConnected(ctx, user.ID))
+}
+
+func TestRedisPresenceStore_RecordsExpireWithoutRefresh(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	store := ws.NewRedisPresenceStore(client)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// A crashed instance never withdraws its record, it just stops refreshing it
+	require.NoError(t, store.Announce(ctx, userID, "crashed", time.Millisecond))
+	require.NoError(t, store.Announce(ctx, userID, "alive", time.Minute))
+	time.Sleep(5 * time.Millisecond)
+	connected, err := store.Connected(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, connected)
+
+	require.NoError(t, store.Withdraw(ctx, userID, "alive"))
+	connected, err = store.Connected(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, connected)
+
+	// Unreachable Redis is reported, the hub then assumes the user is connected
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err = store.Connected(ctx, userID)
+	assert.Error(t, err)
+}
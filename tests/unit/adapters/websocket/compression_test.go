@@ -0,0 +1,63 @@
+package websocket_test
+
+import (
+	"compress/flate"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_CompressionIsNegotiatedPerClient(t *testing.T) {
+	mockAuth := &MockAuthService{}
+	mockAuth.On("ValidateToken", "valid_token").Return(&entities.User{ID: uuid.New()}, nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	hub := ws.NewHub(mockAuth, logger)
+	hub.SetCompression(true, flate.BestSpeed, 64)
+	go hub.Start()
+	t.Cleanup(hub.Stop)
+
+	router := gin.New()
+	router.GET("/ws", hub.HandleWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=valid_token"
+
+	compressing := &gws.Dialer{EnableCompression: true}
+	for name, dialer := range map[string]*gws.Dialer{"compressing": compressing, "plain": gws.DefaultDialer} {
+		t.Run(name, func(t *testing.T) {
+			conn, resp, err := dialer.Dial(url, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { conn.Close() })
+
+			extensions := resp.Header.Get("Sec-Websocket-Extensions")
+			if dialer == compressing {
+				assert.Contains(t, extensions, "permessage-deflate")
+			} else {
+				assert.Empty(t, extensions)
+			}
+
+			readMessage(t, conn, "welcome")
+			subscribe(t, conn, "crypto_BTCUSDT")
+			readMessage(t, conn, "subscribed")
+
+			// Frames above and below the threshold both arrive intact
+			hub.Broadcast("crypto_BTCUSDT", "crypto_data_update", map[string]interface{}{"candles": strings.Repeat("50000.00,", 200)})
+			update := readMessage(t, conn, "crypto_data_update")
+			assert.Equal(t, strings.Repeat("50000.00,", 200), update["candles"])
+
+			hub.Broadcast("crypto_BTCUSDT", "tick", map[string]interface{}{"p": 1})
+			readMessage(t, conn, "tick")
+		})
+	}
+}
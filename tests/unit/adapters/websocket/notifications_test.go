@@ -0,0 +1,55 @@
+package websocket_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_GetNotificationsReturnsPage(t *testing.T) {
+	f := newResumeFixture(t)
+
+	var requested ws.NotificationPageRequest
+	f.hub.SetNotificationSource(func(ctx context.Context, userID uuid.UUID, limit, offset int, unreadOnly bool) (*services.NotificationPage, error) {
+		assert.Equal(t, f.user.ID, userID)
+		requested = ws.NotificationPageRequest{Limit: limit, Offset: offset, UnreadOnly: unreadOnly}
+		if offset > 0 {
+			return nil, errors.New("db down")
+		}
+		return &services.NotificationPage{
+			Notifications: []entities.Notification{{ID: uuid.New(), UserID: userID, Title: "Alert Triggered"}},
+			Limit:         limit,
+			Count:         1,
+			UnreadOnly:    unreadOnly,
+			UnreadCount:   3,
+		}, nil
+	})
+
+	conn, _ := f.connect(t, "")
+	require.NoError(t, conn.WriteJSON(ws.WebSocketMessage{
+		ID:   "req-1",
+		Type: "get_notifications",
+		Data: ws.NotificationPageRequest{Limit: 20, UnreadOnly: true},
+	}))
+	page := readMessage(t, conn, "notifications")
+	assert.Equal(t, ws.NotificationPageRequest{Limit: 20, UnreadOnly: true}, requested)
+	assert.Equal(t, float64(3), page["unread_count"])
+	assert.Len(t, page["data"], 1)
+
+	// Lookup failures are reported against the request
+	require.NoError(t, conn.WriteJSON(ws.WebSocketMessage{
+		ID:   "req-2",
+		Type: "get_notifications",
+		Data: ws.NotificationPageRequest{Limit: 20, Offset: 20},
+	}))
+	failure := readMessage(t, conn, "error")
+	assert.Equal(t, ws.ErrCodeRequestFailed, failure["code"])
+	assert.Equal(t, "req-2", failure["request_id"])
+}
@@ -0,0 +1,149 @@
+package websocket_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		code  string
+		field string
+	}{
+		{name: "subscribe", raw: `{"type":"subscribe","data":{"room":"crypto_BTCUSDT"}}`},
+		{name: "versioned with id", raw: `{"v":1,"id":"req-1","type":"subscribe","data":{"room":"crypto_BTCUSDT","symbol":"BTCUSDT"}}`},
+		{name: "ping without data", raw: `{"type":"ping"}`},
+		{name: "not json", raw: `subscribe crypto_BTCUSDT`, code: ws.ErrCodeMalformedMessage},
+		{name: "unknown envelope field", raw: `{"type":"ping","extra":true}`, code: ws.ErrCodeMalformedMessage},
+		{name: "unknown type", raw: `{"type":"shutdown"}`, code: ws.ErrCodeUnknownType, field: "type"},
+		{name: "missing type", raw: `{"data":{}}`, code: ws.ErrCodeUnknownType, field: "type"},
+		{name: "wrong version", raw: `{"v":2,"type":"ping"}`, code: ws.ErrCodeUnsupportedVersion},
+		{name: "data not an object", raw: `{"type":"subscribe","data":"crypto_BTCUSDT"}`, code: ws.ErrCodeInvalidPayload, field: "data"},
+		{name: "missing room", raw: `{"type":"subscribe","data":{"symbol":"BTCUSDT"}}`, code: ws.ErrCodeInvalidPayload, field: "data.room"},
+		{name: "empty room", raw: `{"type":"subscribe","data":{"room":""}}`, code: ws.ErrCodeInvalidPayload, field: "data.room"},
+		{name: "room not a string", raw: `{"type":"subscribe","data":{"room":42}}`, code: ws.ErrCodeInvalidPayload, field: "data.room"},
+		{name: "unexpected field", raw: `{"type":"unsubscribe","data":{"room":"system","all":true}}`, code: ws.ErrCodeInvalidPayload, field: "data.all"},
+		{name: "ping with data", raw: `{"type":"ping","data":{"at":1}}`, code: ws.ErrCodeInvalidPayload, field: "data.at"},
+		{name: "room too long", raw: `{"type":"subscribe","data":{"room":"crypto_` + strings.Repeat("A", 130) + `"}}`, code: ws.ErrCodeInvalidPayload, field: "data.room"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, perr := ws.DecodeMessage([]byte(tt.raw), ws.ProtocolVersion)
+			if tt.code == "" {
+				require.Nil(t, perr)
+				assert.Equal(t, ws.ProtocolVersion, msg.Version)
+				return
+			}
+
+			require.NotNil(t, perr)
+			assert.Equal(t, tt.code, perr.Code)
+			assert.Equal(t, tt.field, perr.Field)
+		})
+	}
+}
+
+func TestDecodeMessage_EchoesRequestID(t *testing.T) {
+	msg, perr := ws.DecodeMessage([]byte(`{"id":"abc","type":"subscribe","data":{"room":"crypto_BTCUSDT"}}`), ws.ProtocolVersion)
+	require.Nil(t, perr)
+	assert.Equal(t, "abc", msg.ID)
+	assert.Equal(t, map[string]interface{}{"room": "crypto_BTCUSDT"}, msg.Data)
+
+	_, perr = ws.DecodeMessage([]byte(`{"id":"abc","type":"subscribe","data":{}}`), ws.ProtocolVersion)
+	require.NotNil(t, perr)
+	assert.Equal(t, "abc", perr.RequestID)
+}
+
+func TestNegotiateProtocol(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		subprotocol string
+		version     int
+		echoed      string
+		wantErr     bool
+	}{
+		{name: "legacy client", version: ws.ProtocolVersion},
+		{name: "query", query: "protocol=1", version: 1},
+		{name: "query picks newest supported", query: "protocol=1,99", version: 1},
+		{name: "subprotocol", subprotocol: "priceguard.v1", version: 1, echoed: "priceguard.v1"},
+		{name: "unsupported", query: "protocol=99", wantErr: true},
+		{name: "not a number", query: "protocol=latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ws?"+tt.query, nil)
+			if tt.subprotocol != "" {
+				req.Header.Set("Sec-WebSocket-Protocol", tt.subprotocol)
+			}
+
+			version, echoed, err := ws.NegotiateProtocol(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, version)
+			assert.Equal(t, tt.echoed, echoed)
+		})
+	}
+}
+
+func TestHub_ProtocolErrorsAndNegotiation(t *testing.T) {
+	mockAuth := &MockAuthService{}
+	mockAuth.On("ValidateToken", "valid_token").Return(&entities.User{ID: uuid.New()}, nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	hub := ws.NewHub(mockAuth, logger)
+	go hub.Start()
+	defer hub.Stop()
+
+	router := gin.New()
+	router.GET("/ws", hub.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=valid_token"
+
+	_, resp, err := gws.DefaultDialer.Dial(wsURL+"&protocol=99", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	dialer := *gws.DefaultDialer
+	dialer.Subprotocols = []string{"priceguard.v1"}
+	conn, resp, err := dialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "priceguard.v1", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	welcome := readMessage(t, conn, "welcome")
+	assert.EqualValues(t, 1, welcome["protocol_version"])
+
+	require.NoError(t, conn.WriteMessage(gws.TextMessage, []byte(`not json`)))
+	assert.Equal(t, ws.ErrCodeMalformedMessage, readMessage(t, conn, "error")["code"])
+
+	require.NoError(t, conn.WriteMessage(gws.TextMessage, []byte(`{"id":"r1","type":"subscribe","data":{}}`)))
+	invalid := readMessage(t, conn, "error")
+	assert.Equal(t, ws.ErrCodeInvalidPayload, invalid["code"])
+	assert.Equal(t, "data.room", invalid["field"])
+	assert.Equal(t, "r1", invalid["request_id"])
+
+	// The connection stays usable after rejected messages
+	require.NoError(t, conn.WriteMessage(gws.TextMessage, []byte(`{"v":1,"id":"r2","type":"ping"}`)))
+	readMessage(t, conn, "pong")
+}
@@ -0,0 +1,135 @@
+package websocket_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resumeFixture struct {
+	hub   *ws.Hub
+	clock *testutils.FakeClock
+	url   string
+	user  *entities.User
+}
+
+func newResumeFixture(t *testing.T) *resumeFixture {
+	t.Helper()
+
+	user := &entities.User{ID: uuid.New()}
+	mockAuth := &MockAuthService{}
+	mockAuth.On("ValidateToken", "valid_token").Return(user, nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	hub := ws.NewHub(mockAuth, logger)
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	hub.SetClock(clock)
+	hub.SetResumeTTL(time.Minute)
+	go hub.Start()
+	t.Cleanup(hub.Stop)
+
+	router := gin.New()
+	router.GET("/ws", hub.HandleWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &resumeFixture{
+		hub:   hub,
+		clock: clock,
+		url:   "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=valid_token",
+		user:  user,
+	}
+}
+
+func (f *resumeFixture) connect(t *testing.T, resumeToken string) (*gws.Conn, map[string]interface{}) {
+	t.Helper()
+
+	url := f.url
+	if resumeToken != "" {
+		url += "&resume=" + resumeToken
+	}
+	conn, _, err := gws.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, readMessage(t, conn, "welcome")
+}
+
+// disconnect closes the connection and waits for the hub to drop the client
+func (f *resumeFixture) disconnect(t *testing.T, conn *gws.Conn) {
+	t.Helper()
+	conn.Close()
+	require.Eventually(t, func() bool { return f.hub.GetConnectedClients() == 0 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHub_ResumeRestoresSubscriptions(t *testing.T) {
+	f := newResumeFixture(t)
+
+	conn, welcome := f.connect(t, "")
+	token, _ := welcome["resume_token"].(string)
+	require.NotEmpty(t, token)
+	assert.EqualValues(t, 60, welcome["resume_expires_in"])
+	assert.NotContains(t, welcome, "resumed")
+
+	subscribe(t, conn, "crypto_BTCUSDT")
+	readMessage(t, conn, "subscribed")
+	subscribe(t, conn, ws.UserRoom(f.user.ID))
+	readMessage(t, conn, "subscribed")
+	f.disconnect(t, conn)
+	assert.NotContains(t, f.hub.GetRooms(), "crypto_BTCUSDT")
+
+	f.clock.Advance(30 * time.Second)
+	_, welcome = f.connect(t, token)
+	assert.Equal(t, true, welcome["resumed"])
+	assert.ElementsMatch(t, []interface{}{"crypto_BTCUSDT", ws.UserRoom(f.user.ID)}, welcome["rooms"])
+	assert.NotEqual(t, token, welcome["resume_token"])
+
+	rooms := f.hub.GetRooms()
+	assert.Equal(t, 1, rooms["crypto_BTCUSDT"])
+	assert.Equal(t, 1, rooms[ws.UserRoom(f.user.ID)])
+	assert.Equal(t, 1, rooms[ws.SystemRoom])
+}
+
+func TestHub_ResumeTokenIsSingleUse(t *testing.T) {
+	f := newResumeFixture(t)
+
+	conn, welcome := f.connect(t, "")
+	token := welcome["resume_token"].(string)
+	f.disconnect(t, conn)
+
+	conn, welcome = f.connect(t, token)
+	assert.Equal(t, true, welcome["resumed"])
+	f.disconnect(t, conn)
+
+	_, welcome = f.connect(t, token)
+	assert.Equal(t, false, welcome["resumed"])
+	assert.NotEmpty(t, welcome["resume_error"])
+}
+
+func TestHub_ResumeTokenExpires(t *testing.T) {
+	f := newResumeFixture(t)
+
+	conn, welcome := f.connect(t, "")
+	token := welcome["resume_token"].(string)
+	subscribe(t, conn, "crypto_ETHUSDT")
+	readMessage(t, conn, "subscribed")
+	f.disconnect(t, conn)
+
+	f.clock.Advance(2 * time.Minute)
+	_, welcome = f.connect(t, token)
+	assert.Equal(t, false, welcome["resumed"])
+	assert.Equal(t, "invalid or expired resume token", welcome["resume_error"])
+	assert.NotContains(t, f.hub.GetRooms(), "crypto_ETHUSDT")
+}
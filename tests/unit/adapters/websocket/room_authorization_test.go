@@ -0,0 +1,130 @@
+package websocket_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizeRoom(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name string
+		room string
+		code string
+	}{
+		{name: "system room", room: ws.SystemRoom},
+		{name: "market summary", room: "market_summary"},
+		{name: "symbol room", room: "crypto_BTCUSDT"},
+		{name: "symbol room with colon", room: "crypto:ETHUSDT"},
+		{name: "indicator room", room: "indicators_SOLUSDT"},
+		{name: "own user room", room: ws.UserRoom(userID)},
+		{name: "own alerts room", room: "alerts_" + userID.String()},
+		{name: "other user's room", room: ws.UserRoom(otherID), code: ws.RoomRejectForbidden},
+		{name: "other user's alerts room", room: "alerts:" + otherID.String(), code: ws.RoomRejectForbidden},
+		{name: "malformed user room", room: "user:me", code: ws.RoomRejectInvalid},
+		{name: "malformed symbol", room: "crypto_BTC/USDT", code: ws.RoomRejectInvalid},
+		{name: "empty room", room: "", code: ws.RoomRejectInvalid},
+		{name: "missing key", room: "crypto_", code: ws.RoomRejectUnknown},
+		{name: "unknown room", room: "admin_console", code: ws.RoomRejectUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ws.AuthorizeRoom(userID, tt.room)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var rejection *ws.RoomAccessError
+			require.ErrorAs(t, err, &rejection)
+			assert.Equal(t, tt.code, rejection.Code)
+			assert.Equal(t, tt.room, rejection.Room)
+		})
+	}
+}
+
+func TestHub_SubscribeEnforcesRoomAuthorization(t *testing.T) {
+	user := &entities.User{ID: uuid.New()}
+	mockAuth := &MockAuthService{}
+	mockAuth.On("ValidateToken", "valid_token").Return(user, nil)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	hub := ws.NewHub(mockAuth, logger)
+	go hub.Start()
+	defer hub.Stop()
+
+	router := gin.New()
+	router.GET("/ws", hub.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := gws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?token=valid_token", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	readMessage(t, conn, "welcome")
+
+	otherRoom := ws.UserRoom(uuid.New())
+	subscribe(t, conn, otherRoom)
+	rejected := readMessage(t, conn, "subscription_rejected")
+	assert.Equal(t, otherRoom, rejected["room"])
+	assert.Equal(t, ws.RoomRejectForbidden, rejected["code"])
+	assert.NotContains(t, hub.GetRooms(), otherRoom)
+
+	ownRoom := ws.UserRoom(user.ID)
+	subscribe(t, conn, ownRoom)
+	subscribed := readMessage(t, conn, "subscribed")
+	assert.Equal(t, ownRoom, subscribed["room"])
+	assert.Equal(t, 1, hub.GetRooms()[ownRoom])
+
+	subscribe(t, conn, "crypto_BTCUSDT")
+	readMessage(t, conn, "subscribed")
+	assert.Equal(t, 1, hub.GetRooms()["crypto_BTCUSDT"])
+}
+
+func subscribe(t *testing.T, conn *gws.Conn, room string) {
+	t.Helper()
+	require.NoError(t, conn.WriteJSON(ws.WebSocketMessage{
+		Type: "subscribe",
+		Data: ws.SubscribeMessage{Room: room},
+	}))
+}
+
+// readMessage reads frames until a message of the wanted type arrives; the hub may
+// batch several newline separated messages into one frame
+func readMessage(t *testing.T, conn *gws.Conn, messageType string) map[string]interface{} {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	for {
+		_, frame, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		for _, raw := range bytes.Split(frame, []byte("\n")) {
+			var msg struct {
+				Type string                 `json:"type"`
+				Data map[string]interface{} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(raw, &msg))
+			if msg.Type == messageType {
+				return msg.Data
+			}
+		}
+	}
+}
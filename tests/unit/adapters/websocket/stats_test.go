@@ -0,0 +1,54 @@
+package websocket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_StatsCountsUsersAndMessages(t *testing.T) {
+	f := newResumeFixture(t)
+
+	conn, _ := f.connect(t, "")
+	subscribe(t, conn, "crypto_BTCUSDT")
+	readMessage(t, conn, "subscribed")
+
+	// A second user, connected without a socket, shares the symbol room
+	other := &ws.Client{
+		ID:     uuid.NewString(),
+		UserID: uuid.New(),
+		Hub:    f.hub,
+		Send:   make(chan []byte, 16),
+		Rooms:  make(map[string]bool),
+	}
+	f.hub.Register(other)
+	<-other.Send // welcome
+	require.NoError(t, f.hub.JoinRoom(other, "crypto_BTCUSDT"))
+	require.NoError(t, f.hub.JoinRoom(other, ws.UserRoom(other.UserID)))
+
+	f.hub.Broadcast("crypto_BTCUSDT", "crypto_data_update", map[string]interface{}{"price": 50000})
+	readMessage(t, conn, "crypto_data_update")
+	<-other.Send
+
+	f.clock.Advance(2 * time.Second)
+	stats := f.hub.Stats()
+	assert.Equal(t, 2, stats.ConnectedClients)
+	assert.Equal(t, 2, stats.ConnectedUsers)
+	assert.Equal(t, 2, stats.RoomUsers["crypto_BTCUSDT"])
+	assert.Equal(t, 1, stats.RoomUsers[ws.UserRoom(other.UserID)])
+	assert.GreaterOrEqual(t, stats.MessagesSent, uint64(4))
+	assert.Zero(t, stats.MessagesDropped)
+	assert.Greater(t, stats.MessagesPerSecond, 0.0)
+
+	// Over the socket, other users' private rooms are left out
+	require.NoError(t, conn.WriteJSON(ws.WebSocketMessage{Type: "stats"}))
+	reply := readMessage(t, conn, "stats")
+	roomUsers := reply["room_users"].(map[string]interface{})
+	assert.Equal(t, float64(2), roomUsers["crypto_BTCUSDT"])
+	assert.NotContains(t, roomUsers, ws.UserRoom(other.UserID))
+	assert.Equal(t, float64(2), reply["connected_users"])
+}
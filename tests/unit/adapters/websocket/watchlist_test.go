@@ -0,0 +1,92 @@
+package websocket_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ws "github.com/growthfolio/go-priceguard-api/internal/adapters/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+func storeWatchlistPrice(t *testing.T, repos *testutils.MemoryRepositories, symbol string, at time.Time, price float64) {
+	t.Helper()
+	require.NoError(t, repos.PriceHistory.Create(context.Background(), &entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  "1m",
+		Timestamp:  at,
+		OpenPrice:  price,
+		HighPrice:  price,
+		LowPrice:   price,
+		ClosePrice: price,
+	}))
+}
+
+func TestWorker_BroadcastsWatchlistChangesToTheUsersRoom(t *testing.T) {
+	f := newResumeFixture(t)
+	repos := testutils.NewMemoryRepositories()
+	require.NoError(t, repos.UserSettings.Create(context.Background(), &entities.UserSettings{
+		UserID:          f.user.ID,
+		FavoriteSymbols: []string{"ETHUSDT", "BTCUSDT", "DOGEUSDT"},
+	}))
+
+	now := time.Now().Truncate(time.Minute)
+	storeWatchlistPrice(t, repos, "BTCUSDT", now.Add(-time.Hour), 50000)
+	storeWatchlistPrice(t, repos, "BTCUSDT", now.Add(-5*time.Minute), 54000)
+	storeWatchlistPrice(t, repos, "BTCUSDT", now.Add(-time.Minute), 54900)
+	storeWatchlistPrice(t, repos, "BTCUSDT", now, 55000)
+	storeWatchlistPrice(t, repos, "ETHUSDT", now, 3000)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	worker := ws.NewWorker(f.hub, ws.NewWebSocketHandler(f.hub, nil, nil, nil, logger), nil, nil, nil, nil, nil, nil, repos.PriceHistory, logger)
+	worker.SetWatchlistService(services.NewWatchlistTickerService(repos.PriceHistory, repos.UserSettings))
+
+	conn, _ := f.connect(t, "")
+	subscribe(t, conn, ws.WatchlistRoom(f.user.ID))
+	readMessage(t, conn, "subscribed")
+
+	worker.BroadcastWatchlists(context.Background())
+	update := readMessage(t, conn, "watchlist_update")
+	ticks := update["d"].([]interface{})
+	require.Len(t, ticks, 2)
+
+	// Watchlist order is kept and symbols without prices are left out
+	eth := ticks[0].(map[string]interface{})
+	assert.Equal(t, "ETHUSDT", eth["s"])
+	assert.Nil(t, eth["1m"])
+	btc := ticks[1].(map[string]interface{})
+	assert.Equal(t, "BTCUSDT", btc["s"])
+	assert.Equal(t, 55000.0, btc["p"])
+	assert.Equal(t, 0.182, btc["1m"])
+	assert.Equal(t, 1.852, btc["5m"])
+	assert.Equal(t, 10.0, btc["1h"])
+
+	// Unchanged ticks aren't sent again, so the next update carries the new price
+	worker.BroadcastWatchlists(context.Background())
+	storeWatchlistPrice(t, repos, "ETHUSDT", now.Add(time.Minute), 3030)
+	worker.BroadcastWatchlists(context.Background())
+	update = readMessage(t, conn, "watchlist_update")
+	eth = update["d"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 3030.0, eth["p"])
+	assert.Equal(t, 1.0, eth["1m"])
+}
+
+func TestAuthorizeRoom_WatchlistRoomsBelongToTheirUser(t *testing.T) {
+	userID := uuid.New()
+
+	assert.NoError(t, ws.AuthorizeRoom(userID, ws.WatchlistRoom(userID)))
+
+	var rejection *ws.RoomAccessError
+	require.ErrorAs(t, ws.AuthorizeRoom(userID, ws.WatchlistRoom(uuid.New())), &rejection)
+	assert.Equal(t, ws.RoomRejectForbidden, rejection.Code)
+	require.ErrorAs(t, ws.AuthorizeRoom(userID, "watchlist_user_me"), &rejection)
+	assert.Equal(t, ws.RoomRejectInvalid, rejection.Code)
+}
@@ -0,0 +1,185 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AbuseServiceTestSuite struct {
+	suite.Suite
+	alertRepo *testutils.MockAlertRepository
+	flagRepo  *testutils.MockAbuseFlagRepository
+	clock     *testutils.FakeClock
+	service   *services.AbuseService
+	ctx       context.Context
+}
+
+func (suite *AbuseServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.alertRepo = new(testutils.MockAlertRepository)
+	suite.flagRepo = new(testutils.MockAbuseFlagRepository)
+	suite.clock = testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	suite.service = services.NewAbuseService(suite.alertRepo, suite.flagRepo, logger)
+	suite.service.SetClock(suite.clock)
+	suite.service.SetThresholds(services.AbuseThresholds{
+		MaxDuplicateAlerts: 3,
+		MaxExternalAlerts:  5,
+		ThrottleDuration:   time.Hour,
+		TriggerInterval:    10 * time.Minute,
+	})
+}
+
+func duplicateAlerts(userID uuid.UUID, count int, notifyVia ...string) []entities.Alert {
+	if len(notifyVia) == 0 {
+		notifyVia = []string{"app"}
+	}
+	alerts := make([]entities.Alert, count)
+	for i := range alerts {
+		alerts[i] = entities.Alert{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Symbol:        "BTCUSDT",
+			AlertType:     "price",
+			ConditionType: "above",
+			Timeframe:     "1h",
+			NotifyVia:     notifyVia,
+		}
+	}
+	return alerts
+}
+
+func (suite *AbuseServiceTestSuite) TestScanFlagsDuplicateConditions() {
+	offender := uuid.New()
+	alerts := append(duplicateAlerts(offender, 4), duplicateAlerts(uuid.New(), 3)...)
+	now := suite.clock.Now()
+
+	suite.alertRepo.On("GetEnabled", suite.ctx).Return(alerts, nil)
+	suite.flagRepo.On("GetByUserID", suite.ctx, offender).Return([]entities.AbuseFlag{}, nil)
+	suite.flagRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.AbuseFlag")).Return(nil)
+	suite.flagRepo.On("GetActive", suite.ctx, now).Return([]entities.AbuseFlag{
+		{UserID: offender, Reason: services.AbuseReasonDuplicateConditions, ThrottledUntil: now.Add(time.Hour)},
+	}, nil)
+
+	flags, err := suite.service.Scan(suite.ctx)
+
+	suite.NoError(err)
+	suite.Require().Len(flags, 1)
+	suite.Equal(offender, flags[0].UserID)
+	suite.Equal(services.AbuseReasonDuplicateConditions, flags[0].Reason)
+	suite.Equal(4, flags[0].AlertCount)
+	suite.Equal("BTCUSDT", flags[0].Details["symbol"])
+	suite.Equal(now.Add(time.Hour), flags[0].ThrottledUntil)
+	suite.True(suite.service.IsThrottled(offender))
+}
+
+func (suite *AbuseServiceTestSuite) TestScanFlagsExternalAlertVolume() {
+	offender := uuid.New()
+	alerts := make([]entities.Alert, 0)
+	for i := 0; i < 6; i++ {
+		alert := duplicateAlerts(offender, 1, "app", "email")[0]
+		alert.TargetValue = float64(i)
+		alert.Symbol = []string{"BTCUSDT", "ETHUSDT"}[i%2]
+		alert.Timeframe = []string{"1m", "5m", "1h"}[i%3]
+		alerts = append(alerts, alert)
+	}
+
+	suite.alertRepo.On("GetEnabled", suite.ctx).Return(alerts, nil)
+	suite.flagRepo.On("GetByUserID", suite.ctx, offender).Return([]entities.AbuseFlag{}, nil)
+	suite.flagRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.AbuseFlag")).Return(nil)
+	suite.flagRepo.On("GetActive", suite.ctx, suite.clock.Now()).Return([]entities.AbuseFlag{}, nil)
+
+	flags, err := suite.service.Scan(suite.ctx)
+
+	suite.NoError(err)
+	suite.Require().Len(flags, 1)
+	suite.Equal(services.AbuseReasonAlertVolume, flags[0].Reason)
+	suite.Equal(6, flags[0].AlertCount)
+}
+
+func (suite *AbuseServiceTestSuite) TestScanSkipsUsersAlreadyFlagged() {
+	offender := uuid.New()
+	resolvedAt := suite.clock.Now().Add(-30 * time.Minute)
+
+	suite.alertRepo.On("GetEnabled", suite.ctx).Return(duplicateAlerts(offender, 4), nil)
+	suite.flagRepo.On("GetByUserID", suite.ctx, offender).Return([]entities.AbuseFlag{
+		{UserID: offender, Reason: services.AbuseReasonDuplicateConditions, ThrottledUntil: suite.clock.Now().Add(time.Hour), ResolvedAt: &resolvedAt},
+	}, nil)
+	suite.flagRepo.On("GetActive", suite.ctx, suite.clock.Now()).Return([]entities.AbuseFlag{}, nil)
+
+	flags, err := suite.service.Scan(suite.ctx)
+
+	suite.NoError(err)
+	suite.Empty(flags)
+	suite.flagRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.False(suite.service.IsThrottled(offender))
+}
+
+func (suite *AbuseServiceTestSuite) TestAllowTriggerThrottlesFlaggedUsers() {
+	offender := uuid.New()
+	other := uuid.New()
+
+	suite.alertRepo.On("GetEnabled", suite.ctx).Return([]entities.Alert{}, nil)
+	suite.flagRepo.On("GetActive", suite.ctx, mock.Anything).Return([]entities.AbuseFlag{
+		{UserID: offender, ThrottledUntil: suite.clock.Now().Add(time.Hour)},
+	}, nil)
+	_, err := suite.service.Scan(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.True(suite.service.AllowTrigger(suite.ctx, offender))
+	suite.False(suite.service.AllowTrigger(suite.ctx, offender))
+	suite.True(suite.service.AllowTrigger(suite.ctx, other))
+	suite.True(suite.service.AllowTrigger(suite.ctx, other))
+
+	suite.clock.Advance(10 * time.Minute)
+	suite.True(suite.service.AllowTrigger(suite.ctx, offender))
+
+	// The throttle lifts once the flag expires
+	suite.clock.Advance(time.Hour)
+	suite.True(suite.service.AllowTrigger(suite.ctx, offender))
+	suite.True(suite.service.AllowTrigger(suite.ctx, offender))
+}
+
+func (suite *AbuseServiceTestSuite) TestResolveFlagLiftsThrottle() {
+	id := uuid.New()
+	offender := uuid.New()
+	flag := &entities.AbuseFlag{ID: id, UserID: offender, ThrottledUntil: suite.clock.Now().Add(time.Hour)}
+
+	suite.flagRepo.On("GetByID", suite.ctx, id).Return(flag, nil)
+	suite.flagRepo.On("Resolve", suite.ctx, id, "admin@example.com", suite.clock.Now()).Return(nil)
+	suite.flagRepo.On("GetActive", suite.ctx, suite.clock.Now()).Return([]entities.AbuseFlag{}, nil)
+
+	resolved, err := suite.service.ResolveFlag(suite.ctx, id, "admin@example.com")
+
+	suite.NoError(err)
+	suite.Require().NotNil(resolved.ResolvedAt)
+	suite.Equal("admin@example.com", resolved.ResolvedBy)
+	suite.False(suite.service.IsThrottled(offender))
+}
+
+func (suite *AbuseServiceTestSuite) TestResolveUnknownFlag() {
+	id := uuid.New()
+	suite.flagRepo.On("GetByID", suite.ctx, id).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := suite.service.ResolveFlag(suite.ctx, id, "admin@example.com")
+
+	suite.ErrorIs(err, services.ErrAbuseFlagNotFound)
+}
+
+func TestAbuseServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AbuseServiceTestSuite))
+}
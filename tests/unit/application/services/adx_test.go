@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// trendingHistory returns candles newest first, as the repository does, moving by
+// step per candle
+func trendingHistory(latest time.Time, candles int, step float64) []entities.PriceHistory {
+	history := make([]entities.PriceHistory, candles)
+	for i := range history {
+		mid := 100 - step*float64(i)
+		history[i] = entities.PriceHistory{
+			HighPrice: mid + 1, LowPrice: mid - 1, ClosePrice: mid, Volume: 10,
+			Timestamp: latest.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return history
+}
+
+func TestCalculateADX_MeasuresTrendStrengthAndDirection(t *testing.T) {
+	rising := make([]indicators.PriceData, 30)
+	ranging := make([]indicators.PriceData, 30)
+	for i := range rising {
+		rising[i] = indicators.PriceData{High: 101 + float64(i), Low: 99 + float64(i), Close: 100 + float64(i)}
+		// Alternating up and down candles of the same size cancel out
+		offset := float64(i % 2)
+		ranging[i] = indicators.PriceData{High: 101 + offset, Low: 99 + offset, Close: 100 + offset}
+	}
+
+	result, err := indicators.CalculateADX(rising, 14)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, result.Value, 1e-9, "every candle moves up only")
+	assert.Greater(t, result.PlusDI, result.MinusDI)
+	assert.Equal(t, "up", result.Direction)
+	assert.Equal(t, "very_strong", result.Strength)
+
+	result, err = indicators.CalculateADX(ranging, 14)
+	require.NoError(t, err)
+	assert.Less(t, result.Value, indicators.ADXTrendDeveloping)
+	assert.Equal(t, "weak", result.Strength)
+
+	_, err = indicators.CalculateADX(rising[:27], 14)
+	assert.Error(t, err, "the first ADX needs 2*period candles")
+}
+
+func TestTechnicalIndicatorService_CalculatesADX(t *testing.T) {
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 56).Return(trendingHistory(latest, 56, 2), nil)
+	indicatorRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.TechnicalIndicator")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+
+	require.NoError(t, service.CalculateAndStoreADX(context.Background(), "BTCUSDT", "1h", 14))
+
+	indicatorRepo.AssertNumberOfCalls(t, "Create", 1)
+	adx := indicatorRepo.Calls[0].Arguments.Get(1).(*entities.TechnicalIndicator)
+	assert.Equal(t, "ADX_14", adx.IndicatorKey)
+	assert.InDelta(t, 100, *adx.Value, 1e-9)
+	assert.Equal(t, "up", adx.Metadata["direction"], "candles are read oldest first")
+	assert.Equal(t, "very_strong", adx.Metadata["strength"])
+	assert.Greater(t, adx.Metadata["plus_di"], adx.Metadata["minus_di"])
+}
+
+func TestTechnicalIndicatorService_ADXNeedsTwoPeriodsOfCandles(t *testing.T) {
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 56).Return(trendingHistory(time.Now(), 20, 1), nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, &testutils.MockTechnicalIndicatorRepository{}, logger)
+
+	err := service.CalculateAndStoreADX(context.Background(), "BTCUSDT", "1h", 14)
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+}
+
+func TestPullbackEntryService_TrendStrengthWeighsConfidence(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history := make([]entities.PriceHistory, 30)
+	for i := range history {
+		history[i] = entities.PriceHistory{
+			HighPrice: 101, LowPrice: 99, ClosePrice: 100, Volume: 10,
+			Timestamp: now.Add(time.Duration(i) * time.Hour),
+		}
+	}
+
+	// An oversold RSI makes a LONG signal; the ADX confirms or weakens it
+	confidence := func(adx *entities.TechnicalIndicator) (float64, float64) {
+		priceRepo := &testutils.MockPriceHistoryRepository{}
+		priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 50).Return(history, nil)
+
+		indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+		rsi := 25.0
+		indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "RSI_14").
+			Return(&entities.TechnicalIndicator{IndicatorType: "RSI", IndicatorKey: "RSI_14", Value: &rsi}, nil)
+		if adx != nil {
+			indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "ADX_14").Return(adx, nil)
+		}
+		indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", mock.Anything).Return(nil, errors.New("not found"))
+
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		entry, err := services.NewPullbackEntryService(priceRepo, indicatorRepo, logger).AnalyzePullbackEntry(context.Background(), "BTCUSDT", "1h")
+		require.NoError(t, err)
+		require.Equal(t, "LONG", entry.Signal)
+		return entry.Confidence, entry.TrendStrength
+	}
+	adx := func(value float64, direction string) *entities.TechnicalIndicator {
+		return &entities.TechnicalIndicator{
+			IndicatorType: "ADX",
+			IndicatorKey:  "ADX_14",
+			Value:         &value,
+			Metadata:      map[string]interface{}{"direction": direction},
+		}
+	}
+
+	base, strength := confidence(nil)
+	assert.Zero(t, strength)
+
+	strongUp, strength := confidence(adx(32, "up"))
+	assert.Equal(t, 32.0, strength)
+	assert.Equal(t, base+15, strongUp)
+
+	strongDown, _ := confidence(adx(32, "down"))
+	assert.Equal(t, base, strongDown, "a strong trend against the signal doesn't confirm it")
+
+	ranging, _ := confidence(adx(12, "up"))
+	assert.Equal(t, base-10, ranging)
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertChain_RetestIsOnlyEvaluatedAfterTheBreakout(t *testing.T) {
+	ctx := context.Background()
+	s := testutils.NewAlertScenario(t)
+	userID := uuid.New()
+	breakout := s.Alert(testutils.NewAlertBuilder().ForUser(userID).Price("BTCUSDT").Above(50000))
+	retest := s.Alert(testutils.NewAlertBuilder().ForUser(userID).Price("BTCUSDT").Below(48000).After(breakout.ID))
+
+	// Below the retest level before any breakout: the retest isn't armed yet
+	s.Price("BTCUSDT", "1h", 47000)
+	results := s.EvaluateAll()
+	require.Len(t, results, 1)
+	assert.Equal(t, breakout.ID, results[0].AlertID)
+	assert.Empty(t, s.Notifications(userID))
+
+	// The breakout arms the retest
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 51000)
+	s.EvaluateAll()
+	stored, err := s.Repos.Alerts.GetByID(ctx, retest.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ArmedAt)
+	assert.Equal(t, s.Clock.Now(), *stored.ArmedAt)
+	require.Len(t, s.Notifications(userID), 1)
+
+	// The retest triggers and waits for the next breakout again
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 47500)
+	triggered := 0
+	for _, result := range s.EvaluateAll() {
+		if result.ShouldTrigger {
+			assert.Equal(t, retest.ID, result.AlertID)
+			triggered++
+		}
+	}
+	assert.Equal(t, 1, triggered)
+	stored, err = s.Repos.Alerts.GetByID(ctx, retest.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.ArmedAt)
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 47000)
+	s.EvaluateAll()
+	assert.Len(t, s.Notifications(userID), 2)
+}
+
+func TestAlertChain_DeletingTheDependencyDetachesTheDependent(t *testing.T) {
+	ctx := context.Background()
+	s := testutils.NewAlertScenario(t)
+	breakout := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	retest := s.Alert(testutils.NewAlertBuilder().ForUser(breakout.UserID).Price("BTCUSDT").Below(48000).After(breakout.ID))
+
+	require.NoError(t, s.Repos.Alerts.Delete(ctx, breakout.ID))
+	stored, err := s.Repos.Alerts.GetByID(ctx, retest.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.DependsOnID)
+	assert.True(t, stored.Armed())
+}
+
+func TestCheckAlertDependency(t *testing.T) {
+	ctx := context.Background()
+	repo := testutils.NewMemoryAlertRepository()
+	userID := uuid.New()
+	create := func(builder *testutils.AlertBuilder) *entities.Alert {
+		alert := builder.ForUser(userID).Above(50000).Build()
+		require.NoError(t, repo.Create(ctx, alert))
+		return alert
+	}
+
+	first := create(testutils.NewAlertBuilder())
+	second := create(testutils.NewAlertBuilder().After(first.ID))
+	third := create(testutils.NewAlertBuilder().After(second.ID))
+	other := testutils.NewAlertBuilder().Above(50000).Build()
+	require.NoError(t, repo.Create(ctx, other))
+
+	assert.NoError(t, services.CheckAlertDependency(ctx, repo, third, second.ID))
+	// A new alert has no ID yet
+	assert.NoError(t, services.CheckAlertDependency(ctx, repo, &entities.Alert{UserID: userID}, third.ID))
+
+	for name, dependsOnID := range map[string]uuid.UUID{
+		"self":    first.ID,
+		"cycle":   third.ID,
+		"foreign": other.ID,
+		"unknown": uuid.New(),
+	} {
+		err := services.CheckAlertDependency(ctx, repo, first, dependsOnID)
+		var validationErr *entities.ValidationError
+		if assert.ErrorAs(t, err, &validationErr, name) {
+			assert.Equal(t, "depends_on_id", validationErr.Field, name)
+		}
+	}
+
+	// Chains are capped, counting the alert being chained
+	last := third
+	for i := 3; i < services.MaxAlertChainLength; i++ {
+		last = create(testutils.NewAlertBuilder().After(last.ID))
+	}
+	assert.NoError(t, services.CheckAlertDependency(ctx, repo, &entities.Alert{UserID: userID}, first.ID))
+	assert.Error(t, services.CheckAlertDependency(ctx, repo, &entities.Alert{UserID: userID}, last.ID))
+}
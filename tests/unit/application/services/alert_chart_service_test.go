@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chartHistory(now time.Time) []entities.PriceHistory {
+	// Newest first, as the repository returns them
+	return []entities.PriceHistory{
+		{HighPrice: 112, LowPrice: 108, ClosePrice: 110, Timestamp: now},
+		{HighPrice: 106, LowPrice: 101, ClosePrice: 105, Timestamp: now.Add(-12 * time.Hour)},
+		{HighPrice: 102, LowPrice: 98, ClosePrice: 100, Timestamp: now.Add(-24 * time.Hour)},
+	}
+}
+
+func TestAlertChartService_PriceAlert(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetBySymbol", context.Background(), "BTCUSDT", "1h", services.MaxChartPoints).Return(chartHistory(now), nil)
+
+	service := services.NewAlertChartService(priceRepo, indicatorRepo)
+	alert := &entities.Alert{Symbol: "BTCUSDT", Timeframe: "1h", AlertType: "price", ConditionType: "above", TargetValue: 120}
+
+	chart, err := service.GetChartContext(context.Background(), alert, 1000)
+	require.NoError(t, err)
+
+	require.Len(t, chart.Candles, 3)
+	assert.Equal(t, now.Add(-24*time.Hour), chart.Candles[0].Timestamp)
+	assert.Equal(t, 110.0, chart.Candles[2].Close)
+	require.NotNil(t, chart.TargetLevel)
+	assert.Equal(t, 120.0, *chart.TargetLevel)
+	assert.Equal(t, services.ChartScalePrice, chart.TargetScale)
+	assert.Empty(t, chart.Indicators)
+	indicatorRepo.AssertNotCalled(t, "GetByKey")
+}
+
+func TestAlertChartService_PercentageAlert(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", context.Background(), "BTCUSDT", "1h", services.DefaultChartPoints).Return(chartHistory(now), nil)
+
+	service := services.NewAlertChartService(priceRepo, &testutils.MockTechnicalIndicatorRepository{})
+	alert := &entities.Alert{Symbol: "BTCUSDT", Timeframe: "1h", AlertType: "percentage", ConditionType: "down", TargetValue: 5}
+
+	chart, err := service.GetChartContext(context.Background(), alert, 0)
+	require.NoError(t, err)
+
+	// 5% below the close 24 hours before the latest candle
+	require.NotNil(t, chart.TargetLevel)
+	assert.InDelta(t, 95.0, *chart.TargetLevel, 1e-9)
+}
+
+func TestAlertChartService_CrossAlertSeries(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetBySymbol", context.Background(), "ETHUSDT", "4h", 3).Return(chartHistory(now), nil)
+
+	short, long, latest := 101.0, 99.0, 103.0
+	indicatorRepo.On("GetByKey", context.Background(), "ETHUSDT", "4h", "EMA_9", 3).Return([]entities.TechnicalIndicator{
+		{Value: &latest, Timestamp: now},
+		{Value: nil, Timestamp: now.Add(-12 * time.Hour)},
+		{Value: &short, Timestamp: now.Add(-24 * time.Hour)},
+	}, nil)
+	indicatorRepo.On("GetByKey", context.Background(), "ETHUSDT", "4h", "EMA_18", 3).Return([]entities.TechnicalIndicator{
+		{Value: &long, Timestamp: now},
+	}, nil)
+
+	service := services.NewAlertChartService(priceRepo, indicatorRepo)
+	alert := &entities.Alert{Symbol: "ETHUSDT", Timeframe: "4h", AlertType: "ema_cross", ConditionType: "up", TargetValue: 9}
+
+	chart, err := service.GetChartContext(context.Background(), alert, 3)
+	require.NoError(t, err)
+
+	assert.Nil(t, chart.TargetLevel)
+	assert.Equal(t, []services.ChartPoint{
+		{Timestamp: now.Add(-24 * time.Hour), Value: short},
+		{Timestamp: now, Value: latest},
+	}, chart.Indicators["EMA_9"])
+	assert.Len(t, chart.Indicators["EMA_18"], 1)
+	indicatorRepo.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertEngine_AccountsEvaluationCostsPerUser(t *testing.T) {
+	candleTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	heavyUser, lightUser := uuid.New(), uuid.New()
+	alerts := []entities.Alert{
+		{ID: uuid.New(), UserID: lightUser, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 1e9, Timeframe: "1h", Enabled: true},
+		{ID: uuid.New(), UserID: lightUser, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "below", TargetValue: 1, Timeframe: "1h", Enabled: true},
+		{ID: uuid.New(), UserID: heavyUser, Symbol: "ETHUSDT", AlertType: "rsi", ConditionType: "above", TargetValue: 70, Timeframe: "1h", Enabled: true},
+	}
+
+	alertRepo := &testutils.MockAlertRepository{}
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	alertRepo.On("GetEnabled", mock.Anything).Return(alerts, nil)
+	priceRepo.On("GetLatest", mock.Anything, mock.Anything, "1h").Return(&entities.PriceHistory{ClosePrice: 100, Timestamp: candleTime}, nil)
+	rsi := 50.0
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "ETHUSDT", "1h", "RSI_14").
+		Return(&entities.TechnicalIndicator{IndicatorKey: "RSI_14", Value: &rsi, Timestamp: candleTime}, nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	engine := services.NewAlertEngine(alertRepo, priceRepo, indicatorRepo, &testutils.MockNotificationRepository{}, nil, logger)
+	engine.SetClock(testutils.NewFakeClock(candleTime))
+	costs := services.NewMemoryAlertCostStore()
+	engine.SetCostStore(costs)
+
+	_, err := engine.EvaluateAllAlerts(context.Background())
+	require.NoError(t, err)
+
+	top, err := costs.Top(context.Background(), candleTime, 10)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	byUser := map[uuid.UUID]services.UserEvaluationCost{}
+	for _, cost := range top {
+		byUser[cost.UserID] = cost
+		assert.Positive(t, cost.Duration)
+	}
+
+	// Both price alerts share one read of the latest BTCUSDT candle
+	assert.Equal(t, int64(2), byUser[lightUser].Evaluations)
+	assert.Equal(t, int64(1), byUser[lightUser].DBCalls)
+	assert.Equal(t, int64(1), byUser[heavyUser].Evaluations)
+	assert.Equal(t, int64(2), byUser[heavyUser].DBCalls, "the latest candle and the RSI")
+}
+
+func TestRedisAlertCostStore_RanksUsersByEvaluationTime(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	day := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	heavyUser, lightUser := uuid.New(), uuid.New()
+	instanceA := services.NewRedisAlertCostStore(client)
+	instanceB := services.NewRedisAlertCostStore(client)
+
+	require.NoError(t, instanceA.Add(ctx, day, lightUser, services.EvaluationCost{Evaluations: 1, DBCalls: 1, Duration: time.Millisecond}))
+	require.NoError(t, instanceB.Add(ctx, day, heavyUser, services.EvaluationCost{Evaluations: 1, DBCalls: 3, Duration: 4 * time.Millisecond}))
+	require.NoError(t, instanceA.Add(ctx, day, heavyUser, services.EvaluationCost{Evaluations: 1, DBCalls: 2, Duration: 2 * time.Millisecond}))
+	require.NoError(t, instanceA.Add(ctx, day.Add(2*time.Hour), lightUser, services.EvaluationCost{Evaluations: 5, Duration: time.Second}))
+
+	top, err := instanceB.Top(ctx, day, 10)
+	require.NoError(t, err)
+	require.Len(t, top, 2, "costs are kept per UTC day")
+	assert.Equal(t, heavyUser, top[0].UserID)
+	assert.Equal(t, int64(2), top[0].Evaluations)
+	assert.Equal(t, int64(5), top[0].DBCalls)
+	assert.Equal(t, 6.0, top[0].DurationMS)
+	assert.Equal(t, lightUser, top[1].UserID)
+
+	top, err = instanceB.Top(ctx, day, 1)
+	require.NoError(t, err)
+	assert.Len(t, top, 1)
+
+	assert.Equal(t, 31*24*time.Hour, server.TTL("alert_cost:2024-05-01"))
+	assert.Equal(t, 31*24*time.Hour, server.TTL("alert_cost:2024-05-01:"+heavyUser.String()))
+}
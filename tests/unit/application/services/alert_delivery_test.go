@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+func TestAlertEngine_BroadcastsTriggerToConnectedUser(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	webSocket := &testutils.MockAlertWebSocketService{}
+	webSocket.On("IsUserConnected", mock.Anything, mock.Anything).Return(true)
+	webSocket.On("BroadcastAlertTriggered", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	webSocket.On("BroadcastNotificationUpdate", mock.Anything, mock.Anything).Return(nil)
+	s.Engine.SetWebSocketService(webSocket)
+
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	s.Price("BTCUSDT", "1h", 51000)
+	result := s.Evaluate(alert)
+	require.True(t, result.ShouldTrigger)
+	assert.Equal(t, true, result.Context["websocket_delivered"])
+
+	webSocket.AssertCalled(t, "BroadcastAlertTriggered", mock.Anything, alert, result)
+	notifications := s.Notifications(alert.UserID)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, true, notifications[0].Context["websocket_delivered"])
+	webSocket.AssertCalled(t, "BroadcastNotificationUpdate", mock.Anything, &notifications[0])
+}
+
+func TestAlertEngine_SkipsWebSocketForOfflineUser(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	webSocket := &testutils.MockAlertWebSocketService{}
+	webSocket.On("IsUserConnected", mock.Anything, mock.Anything).Return(false)
+	s.Engine.SetWebSocketService(webSocket)
+
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	s.Price("BTCUSDT", "1h", 51000)
+	require.True(t, s.Evaluate(alert).ShouldTrigger)
+
+	// The trigger is still recorded for the notification list and the queued channels
+	notifications := s.Notifications(alert.UserID)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, false, notifications[0].Context["websocket_delivered"])
+	webSocket.AssertNotCalled(t, "BroadcastAlertTriggered", mock.Anything, mock.Anything, mock.Anything)
+	webSocket.AssertNotCalled(t, "BroadcastNotificationUpdate", mock.Anything, mock.Anything)
+}
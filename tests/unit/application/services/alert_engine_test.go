@@ -2,16 +2,19 @@ package services_test
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/growthfolio/go-priceguard-api/internal/application/services"
 	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
 	"github.com/growthfolio/go-priceguard-api/tests/testutils"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -119,36 +122,34 @@ func (suite *AlertEngineTestSuite) TestEvaluateAllAlerts_WithAlerts() {
 	suite.mockAlertRepo.On("GetEnabled", suite.ctx).Return(alerts, nil)
 
 	// Mock price data for evaluation - using ClosePrice field
-	btcPriceHistory := []entities.PriceHistory{
-		{
-			Symbol:     "BTCUSDT",
-			Timeframe:  "1h",
-			OpenPrice:  50500.0,
-			HighPrice:  51200.0,
-			LowPrice:   50400.0,
-			ClosePrice: 51000.0,
-			Volume:     1000.0,
-			Timestamp:  time.Now(),
-		},
+	btcPriceHistory := &entities.PriceHistory{
+		Symbol:     "BTCUSDT",
+		Timeframe:  "1h",
+		OpenPrice:  50500.0,
+		HighPrice:  51200.0,
+		LowPrice:   50400.0,
+		ClosePrice: 51000.0,
+		Volume:     1000.0,
+		Timestamp:  time.Now(),
 	}
-	ethPriceHistory := []entities.PriceHistory{
-		{
-			Symbol:     "ETHUSDT",
-			Timeframe:  "1h",
-			OpenPrice:  2950.0,
-			HighPrice:  2980.0,
-			LowPrice:   2890.0,
-			ClosePrice: 2900.0,
-			Volume:     1500.0,
-			Timestamp:  time.Now(),
-		},
+	ethPriceHistory := &entities.PriceHistory{
+		Symbol:     "ETHUSDT",
+		Timeframe:  "1h",
+		OpenPrice:  2950.0,
+		HighPrice:  2980.0,
+		LowPrice:   2890.0,
+		ClosePrice: 2900.0,
+		Volume:     1500.0,
+		Timestamp:  time.Now(),
 	}
 
-	suite.mockPriceHistoryRepo.On("GetLatest", suite.ctx, "BTCUSDT", 1).Return(btcPriceHistory, nil)
-	suite.mockPriceHistoryRepo.On("GetLatest", suite.ctx, "ETHUSDT", 1).Return(ethPriceHistory, nil)
+	suite.mockPriceHistoryRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(btcPriceHistory, nil).Once()
+	suite.mockPriceHistoryRepo.On("GetLatest", mock.Anything, "ETHUSDT", "1h").Return(ethPriceHistory, nil).Once()
 
-	// Mock notification creation for triggered alerts
-	suite.mockNotificationRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.Notification")).Return(nil).Times(2)
+	// Mock the updates and notifications of triggered alerts
+	suite.mockAlertRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Alert")).Return(nil).Times(2)
+	suite.mockAlertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.mockNotificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil).Times(2)
 
 	// Execute
 	results, err := suite.alertEngine.EvaluateAllAlerts(suite.ctx)
@@ -507,7 +508,7 @@ func (suite *AlertEngineTestSuite) TestEvaluateAlert_PriceBelow_NotTriggered() {
 		Timestamp:  time.Now(),
 	}
 
-	suite.mockPriceHistoryRepo.On("GetLatest", suite.ctx, "BTCUSDT", "1h").Return(priceData, nil)
+	suite.mockPriceHistoryRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(priceData, nil)
 
 	// Execute
 	result, err := suite.alertEngine.EvaluateAlert(suite.ctx, alert)
@@ -664,6 +665,55 @@ func (suite *AlertEngineTestSuite) TestProcessTriggeredAlert_Success() {
 	assert.True(suite.T(), result.ShouldTrigger)
 }
 
+func (suite *AlertEngineTestSuite) TestProcessTriggeredAlert_PersistsEvaluationContext() {
+	alertID := uuid.New()
+	alert := &entities.Alert{
+		ID:            alertID,
+		UserID:        uuid.New(),
+		Symbol:        "BTCUSDT",
+		AlertType:     "price",
+		ConditionType: "above",
+		TargetValue:   50000.0,
+		Timeframe:     "1h",
+		Enabled:       true,
+		NotifyVia:     []string{"app"},
+	}
+
+	priceData := &entities.PriceHistory{
+		Symbol:     "BTCUSDT",
+		Timeframe:  "1h",
+		OpenPrice:  54000.0,
+		HighPrice:  55500.0,
+		LowPrice:   53800.0,
+		ClosePrice: 55000.0,
+		Volume:     1200.0,
+		Timestamp:  time.Now(),
+	}
+
+	var stored *entities.Notification
+	suite.mockPriceHistoryRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(priceData, nil)
+	suite.mockAlertRepo.On("Update", mock.Anything, alert).Return(nil)
+	suite.mockAlertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.mockNotificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*entities.Notification) }).
+		Return(nil)
+
+	_, err := suite.alertEngine.EvaluateAlert(suite.ctx, alert)
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored)
+	suite.Equal(55000.0, stored.Context["current_value"])
+	suite.Equal(50000.0, stored.Context["target_value"])
+	suite.Equal("BTCUSDT", stored.Context["symbol"])
+	suite.Equal(map[string]interface{}{
+		"open":   54000.0,
+		"high":   55500.0,
+		"low":    53800.0,
+		"close":  55000.0,
+		"volume": 1200.0,
+	}, stored.Context["price_data"])
+}
+
 func (suite *AlertEngineTestSuite) TestGetAlertStats_Success() {
 	// Mock the repository to return some test data
 	alerts := []entities.Alert{
@@ -721,13 +771,12 @@ func (suite *AlertEngineTestSuite) TestConcurrentEvaluation() {
 		Timestamp:  time.Now(),
 	}
 
-	// Setup mocks for concurrent access
+	// Setup mocks for concurrent access; the alerts share one price lookup
 	suite.mockAlertRepo.On("GetEnabled", suite.ctx).Return(alerts, nil)
-	for range alerts {
-		suite.mockPriceHistoryRepo.On("GetLatest", suite.ctx, "BTCUSDT", "1h").Return(priceData, nil)
-		suite.mockAlertRepo.On("MarkTriggered", suite.ctx, mock.AnythingOfType("uuid.UUID")).Return(nil)
-		suite.mockNotificationRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.Notification")).Return(nil)
-	}
+	suite.mockPriceHistoryRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(priceData, nil).Once()
+	suite.mockAlertRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Alert")).Return(nil).Times(len(alerts))
+	suite.mockAlertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.mockNotificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil).Times(len(alerts))
 
 	// Execute
 	results, err := suite.alertEngine.EvaluateAllAlerts(suite.ctx)
@@ -736,3 +785,132 @@ func (suite *AlertEngineTestSuite) TestConcurrentEvaluation() {
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), results, len(alerts))
 }
+
+func TestAlertEngine_ThrottleUsesClock(t *testing.T) {
+	mockAlertRepo := &testutils.MockAlertRepository{}
+	mockPriceHistoryRepo := &testutils.MockPriceHistoryRepository{}
+	mockTechnicalIndicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	mockNotificationRepo := &testutils.MockNotificationRepository{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	alertEngine := services.NewAlertEngine(
+		mockAlertRepo,
+		mockPriceHistoryRepo,
+		mockTechnicalIndicatorRepo,
+		mockNotificationRepo,
+		nil,
+		logger,
+	)
+	alertEngine.SetClock(fakeClock)
+
+	alert := &entities.Alert{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		Symbol:        "BTCUSDT",
+		AlertType:     "price",
+		ConditionType: "above",
+		TargetValue:   50000.0,
+		Timeframe:     "1h",
+		Enabled:       true,
+	}
+
+	mockPriceHistoryRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{
+		Symbol:     "BTCUSDT",
+		Timeframe:  "1h",
+		ClosePrice: 51000.0,
+		Timestamp:  fakeClock.Now(),
+	}, nil)
+	mockAlertRepo.On("Update", mock.Anything, alert).Return(nil)
+	mockAlertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	mockNotificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+	result, err := alertEngine.EvaluateAlert(ctx, alert)
+	assert.NoError(t, err)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, fakeClock.Now(), *alert.TriggeredAt)
+
+	// Still inside the 5 minute throttle window
+	fakeClock.Advance(4 * time.Minute)
+	result, err = alertEngine.EvaluateAlert(ctx, alert)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	// Window elapsed, the alert is evaluated again
+	fakeClock.Advance(2 * time.Minute)
+	result, err = alertEngine.EvaluateAlert(ctx, alert)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	mockPriceHistoryRepo.AssertNumberOfCalls(t, "GetLatest", 2)
+}
+
+// countingPriceHistory counts the latest-candle and history reads reaching the repository
+type countingPriceHistory struct {
+	repositories.PriceHistoryRepository
+	latestReads  atomic.Int32
+	historyReads atomic.Int32
+}
+
+func (r *countingPriceHistory) GetLatest(ctx context.Context, symbol, timeframe string) (*entities.PriceHistory, error) {
+	r.latestReads.Add(1)
+	return r.PriceHistoryRepository.GetLatest(ctx, symbol, timeframe)
+}
+
+func (r *countingPriceHistory) GetBySymbol(ctx context.Context, symbol, timeframe string, limit int) ([]entities.PriceHistory, error) {
+	r.historyReads.Add(1)
+	return r.PriceHistoryRepository.GetBySymbol(ctx, symbol, timeframe, limit)
+}
+
+func TestAlertEngine_EvaluateAllAlerts_ReadsEachMarketOnce(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	prices := &countingPriceHistory{PriceHistoryRepository: repos.PriceHistory}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	engine := services.NewAlertEngine(repos.Alerts, prices, repos.TechnicalIndicators, repos.Notifications, nil, logger)
+
+	now := time.Now()
+	require.NoError(t, repos.PriceHistory.BulkInsert(ctx, []entities.PriceHistory{
+		{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 40000, Timestamp: now.Add(-24 * time.Hour)},
+		{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 51000, Timestamp: now},
+		{Symbol: "BTCUSDT", Timeframe: "4h", ClosePrice: 51000, Timestamp: now},
+		{Symbol: "ETHUSDT", Timeframe: "1h", ClosePrice: 2900, Timestamp: now},
+	}))
+
+	var builders []*testutils.AlertBuilder
+	for i := 0; i < 20; i++ {
+		builders = append(builders,
+			testutils.NewAlertBuilder().Price("BTCUSDT").Above(float64(45000+i*500)),
+			testutils.NewAlertBuilder().Price("ETHUSDT").Below(3000),
+			testutils.NewAlertBuilder().Percentage("BTCUSDT").Up(10),
+		)
+	}
+	builders = append(builders, testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000).On("4h"))
+	for _, builder := range builders {
+		require.NoError(t, repos.Alerts.Create(ctx, builder.Build()))
+	}
+
+	results, err := engine.EvaluateAllAlerts(ctx)
+	require.NoError(t, err)
+	assert.Len(t, results, len(builders))
+	for _, result := range results {
+		assert.NotZero(t, result.CurrentValue)
+	}
+
+	// One latest candle per symbol and timeframe, one history for the percentage alerts
+	assert.Equal(t, int32(3), prices.latestReads.Load())
+	assert.Equal(t, int32(1), prices.historyReads.Load())
+
+	// The next cycle reads the market afresh; the alerts that triggered are throttled
+	require.NoError(t, repos.PriceHistory.Create(ctx, &entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 52000, Timestamp: now.Add(time.Hour)}))
+	results, err = engine.EvaluateAllAlerts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), prices.latestReads.Load())
+	require.NotEmpty(t, results)
+	for _, result := range results {
+		assert.Equal(t, 52000.0, result.CurrentValue)
+	}
+}
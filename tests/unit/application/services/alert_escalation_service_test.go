@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AlertEscalationServiceTestSuite struct {
+	suite.Suite
+	alertRepo        *testutils.MemoryAlertRepository
+	notificationRepo *testutils.MemoryNotificationRepository
+	redis            *redis.Client
+	clock            *testutils.FakeClock
+	service          *services.AlertEscalationService
+	userID           uuid.UUID
+	ctx              context.Context
+}
+
+func (suite *AlertEscalationServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.userID = uuid.New()
+	suite.alertRepo = testutils.NewMemoryAlertRepository()
+	suite.notificationRepo = testutils.NewMemoryNotificationRepository()
+	suite.clock = testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	mr := miniredis.RunT(suite.T())
+	suite.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	suite.T().Cleanup(func() { suite.redis.Close() })
+
+	notificationService := services.NewNotificationService(suite.notificationRepo, new(testutils.MockUserRepository),
+		services.NewRedisClientWrapper(suite.redis), logger)
+	notificationService.SetClock(suite.clock)
+
+	suite.service = services.NewAlertEscalationService(suite.alertRepo, suite.notificationRepo, notificationService, logger)
+	suite.service.SetClock(suite.clock)
+}
+
+// triggeredAlert stores an acknowledgement-required alert that just triggered
+func (suite *AlertEscalationServiceTestSuite) triggeredAlert(intervalMinutes int) *entities.Alert {
+	alert := &entities.Alert{
+		UserID:             suite.userID,
+		Symbol:             "BTCUSDT",
+		AlertType:          "price",
+		ConditionType:      "below",
+		TargetValue:        40000,
+		Timeframe:          "1m",
+		Enabled:            true,
+		NotifyVia:          []string{"app"},
+		AckRequired:        true,
+		AckIntervalMinutes: intervalMinutes,
+	}
+	alert.AwaitAck(suite.clock.Now())
+	suite.Require().NoError(suite.alertRepo.Create(suite.ctx, alert))
+	return alert
+}
+
+func (suite *AlertEscalationServiceTestSuite) queuedChannels() [][]services.NotificationChannel {
+	members, err := suite.redis.ZRange(suite.ctx, "notification_queue", 0, -1).Result()
+	suite.Require().NoError(err)
+
+	channels := make([][]services.NotificationChannel, 0, len(members))
+	for _, member := range members {
+		var queued services.QueuedNotification
+		suite.Require().NoError(json.Unmarshal([]byte(member), &queued))
+		suite.Equal(services.NotificationTypeAlertEscalation, queued.Type)
+		channels = append(channels, queued.Channels)
+	}
+	return channels
+}
+
+func (suite *AlertEscalationServiceTestSuite) TestEscalatesEveryIntervalOverMoreChannels() {
+	alert := suite.triggeredAlert(5)
+
+	escalated, err := suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Zero(escalated, "the interval hasn't elapsed since the trigger")
+
+	suite.clock.Advance(5 * time.Minute)
+	escalated, err = suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, escalated)
+
+	// Running again within the interval sends nothing
+	suite.clock.Advance(time.Minute)
+	escalated, err = suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Zero(escalated)
+
+	suite.clock.Advance(4 * time.Minute)
+	escalated, err = suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, escalated)
+
+	stored, err := suite.alertRepo.GetByID(suite.ctx, alert.ID)
+	suite.Require().NoError(err)
+	suite.Equal(2, stored.EscalationLevel)
+	suite.Require().NotNil(stored.LastEscalatedAt)
+	suite.Equal(suite.clock.Now(), *stored.LastEscalatedAt)
+
+	notifications, err := suite.notificationRepo.GetByUserID(suite.ctx, suite.userID, 10, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(notifications, 2)
+	for _, notification := range notifications {
+		suite.Equal(services.NotificationTypeAlertEscalation, notification.NotificationType)
+		suite.Equal(alert.ID, *notification.AlertID)
+	}
+
+	suite.Equal([][]services.NotificationChannel{
+		{services.ChannelPush},
+		{services.ChannelPush, services.ChannelTelegram},
+	}, suite.queuedChannels())
+}
+
+func (suite *AlertEscalationServiceTestSuite) TestAcknowledgeStopsEscalation() {
+	alert := suite.triggeredAlert(0)
+
+	suite.clock.Advance(entities.DefaultAckInterval)
+	escalated, err := suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, escalated)
+
+	acknowledged, err := suite.service.Acknowledge(suite.ctx, suite.userID, alert.ID)
+	suite.Require().NoError(err)
+	suite.False(acknowledged.AwaitingAck())
+	suite.Require().NotNil(acknowledged.AcknowledgedAt)
+	suite.Equal(suite.clock.Now(), *acknowledged.AcknowledgedAt)
+
+	suite.clock.Advance(entities.DefaultAckInterval)
+	escalated, err = suite.service.EscalateDue(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Zero(escalated)
+
+	_, err = suite.service.Acknowledge(suite.ctx, suite.userID, alert.ID)
+	suite.ErrorIs(err, services.ErrAlertNotAwaitingAck)
+}
+
+func (suite *AlertEscalationServiceTestSuite) TestAcknowledgeRejectsOtherUsersAlerts() {
+	alert := suite.triggeredAlert(0)
+
+	_, err := suite.service.Acknowledge(suite.ctx, uuid.New(), alert.ID)
+	suite.ErrorIs(err, services.ErrAlertNotFound)
+
+	_, err = suite.service.Acknowledge(suite.ctx, suite.userID, uuid.New())
+	suite.ErrorIs(err, services.ErrAlertNotFound)
+}
+
+func (suite *AlertEscalationServiceTestSuite) TestStopsAfterMaxEscalations() {
+	alert := suite.triggeredAlert(1)
+
+	for i := 0; i < services.MaxAlertEscalations+3; i++ {
+		suite.clock.Advance(time.Minute)
+		_, err := suite.service.EscalateDue(suite.ctx)
+		suite.Require().NoError(err)
+	}
+
+	stored, err := suite.alertRepo.GetByID(suite.ctx, alert.ID)
+	suite.Require().NoError(err)
+	suite.Equal(services.MaxAlertEscalations, stored.EscalationLevel)
+	suite.True(stored.AwaitingAck(), "the alert can still be acknowledged")
+}
+
+func TestAlertEscalationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AlertEscalationServiceTestSuite))
+}
+
+func TestAlertEscalationChannels(t *testing.T) {
+	alert := &entities.Alert{NotifyVia: []string{"app", "email"}}
+
+	assert.Equal(t, []services.NotificationChannel{services.ChannelInApp, services.ChannelEmail},
+		services.AlertEscalationChannels(alert, 0))
+	assert.Equal(t, []services.NotificationChannel{services.ChannelInApp, services.ChannelEmail, services.ChannelPush},
+		services.AlertEscalationChannels(alert, 1))
+	// Email is already one of the alert's channels, so the third level adds nothing new
+	assert.Equal(t, []services.NotificationChannel{services.ChannelInApp, services.ChannelEmail, services.ChannelPush, services.ChannelTelegram},
+		services.AlertEscalationChannels(alert, 3))
+	assert.Equal(t, []services.NotificationChannel{services.ChannelInApp, services.ChannelEmail, services.ChannelPush, services.ChannelTelegram, services.ChannelSMS},
+		services.AlertEscalationChannels(alert, services.MaxAlertEscalations))
+}
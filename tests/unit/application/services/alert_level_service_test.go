@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveAlertLevels(t *testing.T) {
+	userID := uuid.New()
+	above := entities.Alert{ID: uuid.New(), UserID: userID, Symbol: "BTCUSDT", AlertType: "price",
+		ConditionType: "above", TargetValue: 52000, Timeframe: "1h", Enabled: true, Priority: "high"}
+	alerts := []entities.Alert{
+		above,
+		{ID: uuid.New(), UserID: userID, Symbol: "BTCUSDT", AlertType: "price", TargetValue: 45000, Enabled: false},
+		{ID: uuid.New(), UserID: userID, Symbol: "BTCUSDT", AlertType: "rsi", TargetValue: 70, Enabled: true},
+		{ID: uuid.New(), UserID: userID, Symbol: "ETHUSDT", AlertType: "price", TargetValue: 3000, Enabled: true},
+	}
+
+	levels := services.ActiveAlertLevels(alerts, "btcusdt")
+	require.Len(t, levels, 1)
+	assert.Equal(t, services.AlertLevel{
+		AlertID:       above.ID,
+		ConditionType: "above",
+		TargetValue:   52000,
+		Timeframe:     "1h",
+		Priority:      "high",
+	}, levels[0])
+
+	assert.Empty(t, services.ActiveAlertLevels(alerts, "SOLUSDT"))
+}
+
+func TestAlertLevelService_PublishAlertLevels(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	alertRepo := new(testutils.MockAlertRepository)
+	wsService := new(testutils.MockAlertWebSocketService)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewAlertLevelService(alertRepo, wsService, logger)
+
+	alertRepo.On("GetByUserID", ctx, userID, mock.Anything, 0).Return([]entities.Alert{
+		{ID: uuid.New(), UserID: userID, Symbol: "BTCUSDT", AlertType: "price", TargetValue: 52000, Enabled: true},
+	}, nil).Once()
+	wsService.On("BroadcastAlertLevels", ctx, userID, "BTCUSDT", mock.MatchedBy(func(levels []services.AlertLevel) bool {
+		return len(levels) == 1 && levels[0].TargetValue == 52000
+	})).Return(nil).Once()
+
+	service.PublishAlertLevels(ctx, userID, "BTCUSDT")
+
+	// A failed lookup is logged and nothing is pushed
+	alertRepo.On("GetByUserID", ctx, userID, mock.Anything, 0).Return([]entities.Alert{}, errors.New("db down")).Once()
+	service.PublishAlertLevels(ctx, userID, "BTCUSDT")
+
+	alertRepo.AssertExpectations(t)
+	wsService.AssertExpectations(t)
+}
@@ -0,0 +1,490 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertBuilder_BuildsValidAlerts(t *testing.T) {
+	userID := uuid.New()
+	builder := testutils.NewAlertBuilder().ForUser(userID).Price("ETHUSDT").Below(3000).On("4h")
+
+	alert := builder.Build()
+	require.NoError(t, alert.Validate())
+	assert.Equal(t, userID, alert.UserID)
+	assert.Equal(t, "ETHUSDT", alert.Symbol)
+	assert.Equal(t, "price", alert.AlertType)
+	assert.Equal(t, "below", alert.ConditionType)
+	assert.Equal(t, 3000.0, alert.TargetValue)
+	assert.Equal(t, "4h", alert.Timeframe)
+	assert.True(t, alert.Enabled)
+
+	// Builds are independent copies
+	alert.NotifyVia[0] = "email"
+	assert.Equal(t, []string{"app"}, []string(builder.Build().NotifyVia))
+
+	for _, b := range []*testutils.AlertBuilder{
+		testutils.NewAlertBuilder().Percentage("BTCUSDT").Up(5),
+		testutils.NewAlertBuilder().RSI("BTCUSDT").Above(70),
+		testutils.NewAlertBuilder().EMACross("BTCUSDT", 9).CrossUp(),
+		testutils.NewAlertBuilder().SMACross("BTCUSDT", 20).CrossDown(),
+		testutils.NewAlertBuilder().MACDCross("BTCUSDT").CrossUp(),
+		testutils.NewAlertBuilder().Volume("BTCUSDT").Spike(3),
+		testutils.NewAlertBuilder().Price("BTCUSDT").CrossesUp(50000),
+		testutils.NewAlertBuilder().AnyOf("BTCUSDT", testutils.Condition("price", "above", 50000), testutils.Condition("rsi", "below", 30)),
+	} {
+		assert.NoError(t, b.Build().Validate())
+	}
+}
+
+func TestAlertScenario_PriceAlertTriggersOnceWithinThrottle(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+
+	s.Price("BTCUSDT", "1h", 49000)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+	assert.Empty(t, s.Notifications(alert.UserID))
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 51000)
+	result := s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 51000.0, result.CurrentValue)
+
+	notifications := s.Notifications(alert.UserID)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, alert.ID, *notifications[0].AlertID)
+	assert.Equal(t, 51000.0, notifications[0].Context["current_value"])
+
+	stored, err := s.Repos.Alerts.GetByID(context.Background(), alert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, s.Clock.Now(), *stored.TriggeredAt)
+
+	// Throttled for five minutes after triggering
+	s.Advance(time.Minute)
+	assert.Nil(t, s.Evaluate(alert))
+	s.Advance(5 * time.Minute)
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+	assert.Len(t, s.Notifications(alert.UserID), 2)
+}
+
+func TestAlertScenario_CustomCooldownSurvivesRestart(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000).WithCooldown(time.Hour))
+
+	s.Price("BTCUSDT", "1h", 51000)
+	require.True(t, s.Evaluate(alert).ShouldTrigger)
+
+	// The in-process throttle is gone after a restart, the stored trigger time isn't
+	s.Advance(30 * time.Minute).Restart()
+	stored, err := s.Repos.Alerts.GetByID(context.Background(), alert.ID)
+	require.NoError(t, err)
+	assert.Nil(t, s.Evaluate(stored))
+
+	s.Advance(30 * time.Minute)
+	assert.True(t, s.Evaluate(stored).ShouldTrigger)
+	assert.Len(t, s.Notifications(alert.UserID), 2)
+}
+
+func TestAlertScenario_PriceCrossesLevel(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	up := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").CrossesUp(50000))
+	down := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").CrossesDown(50000))
+
+	// Already above the level: the first evaluation only records the side
+	s.Price("BTCUSDT", "1h", 51000)
+	assert.False(t, s.Evaluate(up).ShouldTrigger)
+	assert.False(t, s.Evaluate(down).ShouldTrigger)
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 49000)
+	assert.False(t, s.Evaluate(up).ShouldTrigger)
+	result := s.Evaluate(down)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 49000.0, result.CurrentValue)
+	assert.Equal(t, 51000.0, result.Context["previous_close"])
+
+	// Staying below isn't another cross
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 48000)
+	assert.False(t, s.Evaluate(up).ShouldTrigger)
+
+	// Touching the level and leaving it upwards is a cross
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 50000)
+	assert.False(t, s.Evaluate(up).ShouldTrigger)
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 50500)
+	assert.True(t, s.Evaluate(up).ShouldTrigger)
+
+	assert.Len(t, s.Notifications(up.UserID), 1)
+	assert.Len(t, s.Notifications(down.UserID), 1)
+}
+
+func TestAlertScenario_PriceCrossDetectedAcrossRestart(t *testing.T) {
+	s := testutils.NewAlertScenario(t).PersistState()
+	alert := s.Alert(testutils.NewAlertBuilder().Price("ETHUSDT").CrossesUp(3000))
+
+	s.Price("ETHUSDT", "1h", 2900)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+
+	// The side recorded before the restart is read back from Redis
+	s.Advance(time.Hour).Restart().Price("ETHUSDT", "1h", 3100)
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+}
+
+func TestAlertScenario_PriceCrossUpgradesOrIgnoresSavedState(t *testing.T) {
+	ctx := context.Background()
+	s := testutils.NewAlertScenario(t).PersistState()
+	alert := s.Alert(testutils.NewAlertBuilder().Price("ETHUSDT").CrossesUp(3000))
+	stateKey := "alert:" + alert.ID.String()
+
+	// Snapshots saved before versioning are taken as the alert's own
+	require.NoError(t, s.States.Save(ctx, stateKey, map[string]interface{}{"close": 2900.0}, 0))
+	s.Price("ETHUSDT", "1h", 3100)
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+
+	state, found, err := s.States.Load(ctx, stateKey)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, float64(services.AlertStateVersion), state["version"])
+	assert.Equal(t, "1h", state["timeframe"])
+	assert.Equal(t, 3000.0, state["target_value"])
+
+	// A snapshot from a newer build isn't compared against
+	require.NoError(t, s.States.Save(ctx, stateKey, map[string]interface{}{"version": services.AlertStateVersion + 1, "close": 2900.0}, 0))
+	s.Advance(time.Hour).Price("ETHUSDT", "1h", 3100)
+	result := s.Evaluate(alert)
+	assert.False(t, result.ShouldTrigger)
+	assert.NotContains(t, result.Context, "previous_close")
+
+	// Nor is one taken before the alert's level moved
+	s.Advance(time.Hour).Price("ETHUSDT", "1h", 3050)
+	alert.TargetValue = 3200
+	result = s.Evaluate(alert)
+	assert.NotContains(t, result.Context, "previous_close")
+	s.Advance(time.Hour).Price("ETHUSDT", "1h", 3300)
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+}
+
+func TestAlertScenario_PriceCrossFirstEvaluationAfterRestartWithoutStore(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().Price("ETHUSDT").CrossesUp(3000))
+
+	s.Price("ETHUSDT", "1h", 2900)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+
+	// Without a state store the engine starts over and can only record the side
+	s.Advance(time.Hour).Restart().Price("ETHUSDT", "1h", 3100)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+}
+
+func TestAlertScenario_RecoversTriggerMissedDuringDowntime(t *testing.T) {
+	s := testutils.NewAlertScenario(t).PersistState()
+	above := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	cross := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").CrossesDown(48000))
+
+	s.Price("BTCUSDT", "1h", 49000)
+	for _, result := range s.EvaluateAll() {
+		assert.False(t, result.ShouldTrigger)
+	}
+	downFrom := s.Clock.Now()
+
+	// The price spiked and fell back while the service was down
+	s.Advance(3*time.Hour).Candles("BTCUSDT", "1h", 51000, 47000, 47500)
+	s.Restart()
+
+	recovered := s.Recover(services.DefaultRecoveryWindow)
+	require.Len(t, recovered, 2)
+	for _, result := range recovered {
+		assert.Equal(t, true, result.Context["recovered"])
+	}
+
+	notifications := s.Notifications(above.UserID)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "Alert Triggered During Downtime", notifications[0].Title)
+	assert.Contains(t, notifications[0].Message, "While the service was down")
+	assert.Equal(t, downFrom.Add(time.Hour), notifications[0].Context["candle_time"])
+	assert.Len(t, s.Notifications(cross.UserID), 1)
+
+	// The recovered trigger starts the cooldown, so the live evaluation stays quiet
+	assert.Nil(t, s.Evaluate(above))
+	assert.Empty(t, s.Recover(services.DefaultRecoveryWindow), "the gap was already recovered")
+}
+
+func TestAlertScenario_SymbolEvaluationDoesNotShrinkRecovery(t *testing.T) {
+	s := testutils.NewAlertScenario(t).PersistState()
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+
+	s.Price("BTCUSDT", "1h", 49000)
+	s.EvaluateAll()
+
+	// Only another symbol's candle close was evaluated before the service went down
+	s.Advance(3*time.Hour).Candles("BTCUSDT", "1h", 51000, 47000, 47500)
+	_, err := s.Engine.EvaluateSymbolAlerts(context.Background(), "ETHUSDT")
+	require.NoError(t, err)
+	s.Restart()
+
+	require.Len(t, s.Recover(services.DefaultRecoveryWindow), 1)
+	assert.Len(t, s.Notifications(alert.UserID), 1)
+}
+
+func TestAlertScenario_RecoveryIsBoundedByWindow(t *testing.T) {
+	s := testutils.NewAlertScenario(t).PersistState()
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+
+	s.Price("BTCUSDT", "1h", 49000)
+	s.EvaluateAll()
+
+	// The only trigger is 9 hours old, out of a 6 hour window
+	closes := make([]float64, 10)
+	for i := range closes {
+		closes[i] = 49000
+	}
+	closes[0] = 51000
+	s.Advance(10*time.Hour).Candles("BTCUSDT", "1h", closes...)
+	s.Restart()
+
+	assert.Empty(t, s.Recover(6*time.Hour))
+	assert.Empty(t, s.Notifications(alert.UserID))
+}
+
+func TestAlertScenario_NoRecoveryWithoutPreviousEvaluation(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+
+	s.Candles("BTCUSDT", "1h", 51000, 49000)
+
+	// Nothing tells how long the service was down, so nothing is replayed
+	assert.Empty(t, s.Recover(services.DefaultRecoveryWindow))
+	assert.Empty(t, s.Notifications(alert.UserID))
+}
+
+func TestAlertScenario_PercentageChangeOver24h(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	up := s.Alert(testutils.NewAlertBuilder().Percentage("BTCUSDT").Up(5))
+	down := s.Alert(testutils.NewAlertBuilder().Percentage("BTCUSDT").Down(5))
+
+	// 25 hourly candles: 40000 a day ago, 42400 now
+	closes := make([]float64, 25)
+	for i := range closes {
+		closes[i] = 40000 + float64(i)*100
+	}
+	s.Candles("BTCUSDT", "1h", closes...)
+
+	result := s.Evaluate(up)
+	assert.True(t, result.ShouldTrigger)
+	assert.InDelta(t, 6.0, result.CurrentValue, 1e-9)
+	assert.Equal(t, 40000.0, result.Context["base_price"])
+	assert.False(t, s.Evaluate(down).ShouldTrigger)
+}
+
+func TestAlertScenario_RSIThresholds(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	oversold := s.Alert(testutils.NewAlertBuilder().RSI("ETHUSDT").Below(30).On("15m"))
+	overbought := s.Alert(testutils.NewAlertBuilder().RSI("ETHUSDT").Above(70).On("15m"))
+
+	s.Price("ETHUSDT", "15m", 2900).RSI("ETHUSDT", "15m", 25)
+
+	assert.True(t, s.Evaluate(oversold).ShouldTrigger)
+	result := s.Evaluate(overbought)
+	assert.False(t, result.ShouldTrigger)
+	assert.Equal(t, 25.0, result.CurrentValue)
+}
+
+func TestAlertScenario_MovingAverageCrossNeedsPreviousState(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().EMACross("BTCUSDT", 9).CrossUp())
+
+	s.Price("BTCUSDT", "1h", 50000).MovingAverages("BTCUSDT", "1h", "EMA", 9, 49800, 50000)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger, "the first evaluation only records the state")
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 50500).MovingAverages("BTCUSDT", "1h", "EMA", 9, 50200, 50100)
+	result := s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, "EMA(9) crossed above EMA(18) for BTCUSDT", result.Message)
+}
+
+func TestAlertScenario_MACDCrossDown(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().MACDCross("BTCUSDT").CrossDown())
+
+	s.Price("BTCUSDT", "1h", 50000).MACD("BTCUSDT", "1h", 120, 100)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 49500).MACD("BTCUSDT", "1h", 80, 95)
+	result := s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, -15.0, result.CurrentValue)
+	assert.Len(t, s.Notifications(alert.UserID), 1)
+}
+
+func TestAlertScenario_VolumeThresholds(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	above := s.Alert(testutils.NewAlertBuilder().Volume("BTCUSDT").Above(1500))
+	below := s.Alert(testutils.NewAlertBuilder().Volume("BTCUSDT").Below(1500))
+
+	s.Volumes("BTCUSDT", "1h", 1200)
+	assert.False(t, s.Evaluate(above).ShouldTrigger)
+	result := s.Evaluate(below)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 1200.0, result.CurrentValue)
+
+	s.Advance(time.Hour).Volumes("BTCUSDT", "1h", 1800)
+	assert.True(t, s.Evaluate(above).ShouldTrigger)
+}
+
+func TestAlertScenario_VolumeSpikeAgainstRollingAverage(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().Volume("BTCUSDT").Spike(3))
+
+	// Without a full 20-candle window there is no average to compare against
+	s.Volumes("BTCUSDT", "1h", 1000, 5000)
+	_, err := s.Engine.EvaluateAlert(context.Background(), alert)
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+
+	// 25 candles: older ones at 9000 fall outside the window, the last 20 average 1000
+	volumes := make([]float64, 25)
+	for i := range volumes {
+		volumes[i] = 1000
+		if i < 4 {
+			volumes[i] = 9000
+		}
+	}
+	volumes[24] = 2500
+	s.Advance(48*time.Hour).Volumes("BTCUSDT", "1h", volumes...)
+
+	result := s.Evaluate(alert)
+	assert.False(t, result.ShouldTrigger)
+	assert.InDelta(t, 2.5, result.CurrentValue, 1e-9)
+	assert.InDelta(t, 1000.0, result.Context["average_volume"], 1e-9)
+
+	// The 2500 candle joins the window, which now averages 1075
+	s.Advance(time.Hour).Volumes("BTCUSDT", "1h", 3300)
+	result = s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.InDelta(t, 3300.0/1075, result.CurrentValue, 1e-9)
+	assert.Contains(t, result.Message, "20-candle average")
+	assert.Len(t, s.Notifications(alert.UserID), 1)
+}
+
+func TestAlertScenario_CompositeCombinesNestedConditions(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().AllOf("BTCUSDT",
+		testutils.Condition("price", "above", 50000),
+		testutils.Group("or",
+			testutils.Condition("rsi", "below", 30),
+			testutils.Condition("volume", "above", 5000),
+		),
+	))
+
+	s.Price("BTCUSDT", "1h", 51000).RSI("BTCUSDT", "1h", 45)
+	result := s.Evaluate(alert)
+	assert.False(t, result.ShouldTrigger)
+	assert.Equal(t, 1.0, result.CurrentValue)
+	conditions := result.Context["conditions"].([]map[string]interface{})
+	require.Len(t, conditions, 3)
+	assert.Equal(t, true, conditions[0]["met"])
+	assert.Equal(t, "rsi", conditions[1]["alert_type"])
+	assert.Equal(t, 45.0, conditions[1]["current_value"])
+	assert.Equal(t, false, conditions[2]["met"])
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 51000).RSI("BTCUSDT", "1h", 25)
+	result = s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 2.0, result.CurrentValue)
+	assert.Contains(t, result.Message, "2 of 3 conditions met for BTCUSDT")
+
+	notifications := s.Notifications(alert.UserID)
+	require.Len(t, notifications, 1)
+	assert.Len(t, notifications[0].Context["conditions"], 3)
+}
+
+func TestAlertScenario_CompositeOrIgnoresConditionsWithoutData(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().AnyOf("BTCUSDT",
+		testutils.Condition("rsi", "below", 30),
+		testutils.Condition("price", "above", 50000),
+	))
+
+	// No RSI has been computed yet
+	s.Price("BTCUSDT", "1h", 51000)
+	result := s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+
+	conditions := result.Context["conditions"].([]map[string]interface{})
+	assert.Equal(t, false, conditions[0]["met"])
+	assert.Contains(t, conditions[0]["message"], "RSI")
+}
+
+func TestAlertScenario_CompositeTracksCrossoverPerCondition(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	alert := s.Alert(testutils.NewAlertBuilder().AllOf("BTCUSDT",
+		testutils.Condition("ema_cross", "up", 9),
+		testutils.Condition("price", "above", 50000),
+	))
+
+	s.Price("BTCUSDT", "1h", 50000).MovingAverages("BTCUSDT", "1h", "EMA", 9, 49800, 50000)
+	assert.False(t, s.Evaluate(alert).ShouldTrigger)
+
+	s.Advance(time.Hour).Price("BTCUSDT", "1h", 50500).MovingAverages("BTCUSDT", "1h", "EMA", 9, 50200, 50100)
+	result := s.Evaluate(alert)
+	assert.True(t, result.ShouldTrigger)
+	assert.Contains(t, result.Message, "EMA(9) crossed above EMA(18) for BTCUSDT")
+}
+
+func TestAlertScenario_SkipsEvaluationOnStaleData(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	stale := map[string]int{}
+	s.Engine.SetStaleDataRecorder(services.StaleDataRecorderFunc(func(source string) { stale[source]++ }))
+	s.Engine.SetMaxDataAge(10 * time.Minute)
+
+	alert := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	s.Price("BTCUSDT", "1h", 51000)
+
+	// The candle is still open, then closed for less than the limit
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+	s.Advance(time.Hour + 5*time.Minute)
+	assert.True(t, s.Evaluate(alert).ShouldTrigger)
+
+	// The collection stopped: the candle closed too long ago to act on
+	s.Advance(10 * time.Minute)
+	_, err := s.Engine.EvaluateAlert(context.Background(), alert)
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+	assert.Empty(t, s.EvaluateAll())
+	assert.Equal(t, 2, stale[services.StaleSourcePrice])
+}
+
+func TestAlertScenario_AlertMaxDataAgeOverridesDefault(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	s.Engine.SetMaxDataAge(time.Hour)
+	strict := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000).On("1m").WithMaxDataAge(30 * time.Second))
+	lenient := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000).On("1m"))
+
+	s.Price("BTCUSDT", "1m", 51000)
+	s.Advance(2 * time.Minute)
+
+	_, err := s.Engine.EvaluateAlert(context.Background(), strict)
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+	assert.True(t, s.Evaluate(lenient).ShouldTrigger)
+}
+
+func TestAlertScenario_SkipsEvaluationOnStaleIndicator(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	stale := map[string]int{}
+	s.Engine.SetStaleDataRecorder(services.StaleDataRecorderFunc(func(source string) { stale[source]++ }))
+	s.Engine.SetMaxDataAge(10 * time.Minute)
+	alert := s.Alert(testutils.NewAlertBuilder().RSI("BTCUSDT").Below(30))
+
+	// The indicator refresh stopped while prices keep coming in
+	s.RSI("BTCUSDT", "1h", 25)
+	s.Advance(2 * time.Hour).Price("BTCUSDT", "1h", 51000)
+
+	_, err := s.Engine.EvaluateAlert(context.Background(), alert)
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+	assert.Equal(t, 1, stale[services.StaleSourceIndicator])
+	assert.Zero(t, stale[services.StaleSourcePrice])
+}
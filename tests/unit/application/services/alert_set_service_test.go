@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlertSetService(t *testing.T, repos *testutils.MemoryRepositories) *services.AlertSetService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	service := services.NewAlertSetService(repos.AlertSets, repos.Alerts, logger)
+	service.SetSymbolRestrictionService(services.NewSymbolRestrictionService(repos.SymbolRestrictions, repos.Alerts, logger))
+	return service
+}
+
+// seedAlertSet creates a set with two BTC alerts, one of them triggered, a
+// disabled LUNA alert and an alert outside the set
+func seedAlertSet(t *testing.T, repos *testutils.MemoryRepositories, service *services.AlertSetService, owner uuid.UUID) *entities.AlertSet {
+	ctx := context.Background()
+	set, err := service.CreateSet(ctx, owner, " BTC swing plan ", "Daily pullback entries")
+	require.NoError(t, err)
+
+	for _, alert := range []*entities.Alert{
+		testutils.NewAlertBuilder().ForUser(owner).Price("BTCUSDT").Below(60000).InSet(set.ID).Build(),
+		testutils.NewAlertBuilder().ForUser(owner).Price("BTCUSDT").Above(70000).InSet(set.ID).TriggeredAt(time.Now()).Build(),
+		testutils.NewAlertBuilder().ForUser(owner).Price("LUNAUSDT").Above(1).InSet(set.ID).Disabled().Build(),
+		testutils.NewAlertBuilder().ForUser(owner).Price("ETHUSDT").Above(4000).Build(),
+	} {
+		require.NoError(t, repos.Alerts.Create(ctx, alert))
+	}
+	return set
+}
+
+func TestAlertSetService_SummarizesSetsWithTheirAlerts(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newAlertSetService(t, repos)
+	owner := uuid.New()
+
+	set := seedAlertSet(t, repos, service, owner)
+	assert.Equal(t, "BTC swing plan", set.Name)
+	_, err := service.CreateSet(ctx, owner, "Alt breakouts", "")
+	require.NoError(t, err)
+
+	sets, err := service.ListSets(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	assert.Equal(t, "Alt breakouts", sets[0].Name)
+	assert.Zero(t, sets[0].Stats.Total)
+	assert.Equal(t, services.AlertSetStats{Total: 3, Enabled: 2, Triggered: 1, Symbols: []string{"BTCUSDT", "LUNAUSDT"}}, sets[1].Stats)
+
+	details, err := service.GetSet(ctx, owner, set.ID)
+	require.NoError(t, err)
+	assert.Len(t, details.Alerts, 3)
+	assert.Equal(t, 3, details.Stats.Total)
+
+	// Names are unique per user, ignoring case
+	_, err = service.CreateSet(ctx, owner, "btc SWING plan", "")
+	assert.ErrorIs(t, err, entities.ErrConflict)
+	_, err = service.CreateSet(ctx, uuid.New(), "BTC swing plan", "")
+	assert.NoError(t, err)
+	_, err = service.CreateSet(ctx, owner, "", "")
+	var validationErr *entities.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	renamed := "BTC swing plan Q3"
+	updated, err := service.UpdateSet(ctx, owner, set.ID, services.AlertSetUpdate{Name: &renamed})
+	require.NoError(t, err)
+	assert.Equal(t, renamed, updated.Name)
+	assert.Equal(t, "Daily pullback entries", updated.Description)
+}
+
+func TestAlertSetService_EnablesAndDisablesAlertsAsAUnit(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newAlertSetService(t, repos)
+	owner := uuid.New()
+	set := seedAlertSet(t, repos, service, owner)
+
+	result, err := service.SetEnabled(ctx, owner, set.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, &services.AlertSetToggleResult{Changed: 2}, result)
+
+	// Alerts on blacklisted symbols stay off when the set is enabled again
+	require.NoError(t, repos.SymbolRestrictions.Upsert(ctx, &entities.SymbolRestriction{Symbol: "LUNAUSDT", Level: entities.SymbolBlacklisted, Reason: "delisted"}))
+	result, err = service.SetEnabled(ctx, owner, set.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, &services.AlertSetToggleResult{Changed: 2, Skipped: 1}, result)
+
+	alerts, err := repos.Alerts.GetByUserID(ctx, owner, 0, 0)
+	require.NoError(t, err)
+	for _, alert := range alerts {
+		assert.Equal(t, alert.Symbol != "LUNAUSDT", alert.Enabled, alert.Symbol)
+	}
+}
+
+func TestAlertSetService_DeleteCascadesUnlessAlertsAreKept(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newAlertSetService(t, repos)
+	owner := uuid.New()
+
+	set := seedAlertSet(t, repos, service, owner)
+	kept, err := service.DeleteSet(ctx, owner, set.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, kept)
+	alerts, err := repos.Alerts.GetByUserID(ctx, owner, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, alerts, 4)
+	for _, alert := range alerts {
+		assert.Nil(t, alert.SetID)
+	}
+
+	set = seedAlertSet(t, repos, service, owner)
+	deleted, err := service.DeleteSet(ctx, owner, set.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	alerts, err = repos.Alerts.GetByUserID(ctx, owner, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, alerts, 5)
+
+	_, err = service.GetSet(ctx, owner, set.ID)
+	assert.ErrorIs(t, err, services.ErrAlertSetNotFound)
+}
+
+func TestAlertSetService_OtherUsersSetsAreNotFound(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newAlertSetService(t, repos)
+	owner, intruder := uuid.New(), uuid.New()
+	set := seedAlertSet(t, repos, service, owner)
+
+	_, err := service.CheckSet(ctx, intruder, set.ID)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+	_, err = service.SetEnabled(ctx, intruder, set.ID, false)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+	_, err = service.DeleteSet(ctx, intruder, set.ID, false)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+
+	details, err := service.GetSet(ctx, owner, set.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, details.Stats.Enabled)
+}
@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testShards        = 16
+	testShardLeaseTTL = 90 * time.Second
+)
+
+// shardCounts records what an AlertSharder reports
+type shardCounts struct {
+	owned    int
+	handoffs map[string]int
+}
+
+func (c *shardCounts) SetOwnedShards(owned int)        { c.owned = owned }
+func (c *shardCounts) ObserveHandoff(direction string) { c.handoffs[direction]++ }
+
+func newTestSharder(client *redis.Client, owner string, clock *testutils.FakeClock) (*services.AlertSharder, *shardCounts) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	sharder := services.NewAlertSharder(client, owner, testShards, testShardLeaseTTL, logger)
+	sharder.SetClock(clock)
+	counts := &shardCounts{handoffs: make(map[string]int)}
+	sharder.SetRecorder(counts)
+	return sharder, counts
+}
+
+// owners counts, for each alert, how many of the sharders evaluate it
+func owners(alertIDs []uuid.UUID, sharders ...*services.AlertSharder) []int {
+	counts := make([]int, len(alertIDs))
+	for i, alertID := range alertIDs {
+		for _, sharder := range sharders {
+			if sharder.Owns(alertID) {
+				counts[i]++
+			}
+		}
+	}
+	return counts
+}
+
+func TestAlertSharder_SplitsAlertsBetweenReplicas(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	a, countsA := newTestSharder(client, "instance-a", clock)
+	require.NoError(t, a.Rebalance(ctx))
+	assert.Equal(t, testShards, a.OwnedShards(), "a single replica holds every shard")
+
+	// A second replica joins: the first gives up half its shards on its next cycle
+	// and the second picks them up on its following one
+	b, countsB := newTestSharder(client, "instance-b", clock)
+	require.NoError(t, b.Rebalance(ctx))
+	require.NoError(t, a.Rebalance(ctx))
+	require.NoError(t, b.Rebalance(ctx))
+
+	assert.Equal(t, testShards/2, a.OwnedShards())
+	assert.Equal(t, testShards/2, b.OwnedShards())
+	assert.Equal(t, testShards/2, countsA.owned)
+	assert.Equal(t, testShards/2, countsA.handoffs[services.ShardReleased])
+	assert.Equal(t, testShards/2, countsB.handoffs[services.ShardAcquired])
+
+	alertIDs := make([]uuid.UUID, 200)
+	for i := range alertIDs {
+		alertIDs[i] = uuid.New()
+	}
+	for i, count := range owners(alertIDs, a, b) {
+		assert.Equal(t, 1, count, "alert %s must be evaluated by exactly one replica", alertIDs[i])
+	}
+}
+
+func TestAlertSharder_TakesOverShardsOfStoppedReplica(t *testing.T) {
+	ctx := context.Background()
+	server, client := testutils.StartMiniredis(t)
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	a, _ := newTestSharder(client, "instance-a", clock)
+	b, countsB := newTestSharder(client, "instance-b", clock)
+	require.NoError(t, a.Rebalance(ctx))
+	require.NoError(t, b.Rebalance(ctx))
+	require.NoError(t, a.Rebalance(ctx))
+	require.NoError(t, b.Rebalance(ctx))
+	require.Equal(t, testShards/2, b.OwnedShards())
+
+	// a stops renewing: its leases and membership expire and b takes everything over
+	clock.Advance(testShardLeaseTTL + time.Second)
+	server.FastForward(testShardLeaseTTL + time.Second)
+	assert.False(t, a.Owns(uuid.New()), "expired leases may be held by another replica")
+
+	require.NoError(t, b.Rebalance(ctx))
+	assert.Equal(t, testShards, b.OwnedShards())
+	assert.Equal(t, testShards, countsB.handoffs[services.ShardAcquired])
+
+	// a comes back and finds its shards taken
+	require.NoError(t, a.Rebalance(ctx))
+	for _, count := range owners([]uuid.UUID{uuid.New(), uuid.New(), uuid.New()}, a, b) {
+		assert.Equal(t, 1, count)
+	}
+}
+
+func TestAlertSharder_ReleaseAllHandsShardsOver(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	a, countsA := newTestSharder(client, "instance-a", clock)
+	b, _ := newTestSharder(client, "instance-b", clock)
+	require.NoError(t, a.Rebalance(ctx))
+
+	require.NoError(t, a.ReleaseAll(ctx))
+	assert.Zero(t, a.OwnedShards())
+	assert.Zero(t, countsA.owned)
+
+	// Without waiting for the lease TTL
+	require.NoError(t, b.Rebalance(ctx))
+	assert.Equal(t, testShards, b.OwnedShards())
+}
+
+func TestAlertEngine_EvaluatesOnlyOwnedShards(t *testing.T) {
+	_, client := testutils.StartMiniredis(t)
+	s := testutils.NewAlertScenario(t)
+	a, _ := newTestSharder(client, "instance-a", s.Clock)
+	b, _ := newTestSharder(client, "instance-b", s.Clock)
+
+	for i := 0; i < 20; i++ {
+		s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	}
+	s.Price("BTCUSDT", "1h", 51000)
+
+	// Both replicas join before either evaluates
+	require.NoError(t, a.Rebalance(context.Background()))
+	require.NoError(t, b.Rebalance(context.Background()))
+	require.NoError(t, a.Rebalance(context.Background()))
+
+	s.Engine.SetSharder(a)
+	evaluatedByA := len(s.EvaluateAll())
+	s.Restart()
+	s.Engine.SetSharder(b)
+	evaluatedByB := len(s.EvaluateAll())
+
+	assert.Equal(t, 20, evaluatedByA+evaluatedByB)
+	assert.NotZero(t, evaluatedByA)
+	assert.NotZero(t, evaluatedByB)
+}
+
+func TestAlertEngine_RecoversOnlyShardsEvaluatedBefore(t *testing.T) {
+	_, client := testutils.StartMiniredis(t)
+	s := testutils.NewAlertScenario(t).PersistState()
+	a, _ := newTestSharder(client, "instance-a", s.Clock)
+	b, _ := newTestSharder(client, "instance-b", s.Clock)
+
+	alerts := make([]*entities.Alert, 20)
+	for i := range alerts {
+		alerts[i] = s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	}
+	s.Price("BTCUSDT", "1h", 49000)
+
+	require.NoError(t, a.Rebalance(context.Background()))
+	require.NoError(t, b.Rebalance(context.Background()))
+	require.NoError(t, a.Rebalance(context.Background()))
+
+	// Only the first replica ran a cycle before both went down
+	s.Engine.SetSharder(a)
+	s.EvaluateAll()
+	s.Advance(3*time.Hour).Candles("BTCUSDT", "1h", 51000, 47000, 47500)
+
+	s.Restart()
+	s.Engine.SetSharder(b)
+	assert.Empty(t, s.Recover(services.DefaultRecoveryWindow), "b's shards were never evaluated")
+
+	s.Restart()
+	s.Engine.SetSharder(a)
+	recovered := s.Recover(services.DefaultRecoveryWindow)
+	assert.NotEmpty(t, recovered)
+	for _, alert := range alerts {
+		want := 0
+		if a.Owns(alert.ID) {
+			want = 1
+		}
+		assert.Len(t, s.Notifications(alert.UserID), want)
+	}
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAlertStateStore_SaveLoadExpires(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	store := services.NewMemoryAlertStateStore()
+	store.SetClock(fakeClock)
+
+	_, found, err := store.Load(ctx, "alert:1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, store.Save(ctx, "alert:1", map[string]interface{}{"short_ma": 10.0, "long_ma": 12.0}, 0))
+	require.NoError(t, store.Save(ctx, "other:1", map[string]interface{}{}, time.Hour))
+
+	state, found, err := store.Load(ctx, "alert:1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 10.0, state["short_ma"])
+	count, _ := store.Count(ctx, "alert:")
+	assert.Equal(t, 1, count)
+
+	fakeClock.Advance(time.Hour)
+	_, found, _ = store.Load(ctx, "other:1")
+	assert.False(t, found)
+
+	fakeClock.Advance(7*24*time.Hour - time.Hour)
+	_, found, _ = store.Load(ctx, "alert:1")
+	assert.False(t, found)
+	count, _ = store.Count(ctx, "alert:")
+	assert.Zero(t, count)
+}
+
+func TestRedisAlertStateStore_SharedBetweenInstances(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	instanceA := services.NewRedisAlertStateStore(client)
+	instanceB := services.NewRedisAlertStateStore(client)
+
+	require.NoError(t, instanceA.Save(ctx, "alert:1", map[string]interface{}{"macd": 1.5, "signal": 1.2}, 0))
+
+	state, found, err := instanceB.Load(ctx, "alert:1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1.5, state["macd"])
+	assert.Equal(t, 1.2, state["signal"])
+
+	count, err := instanceB.Count(ctx, "alert:")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	ttl := server.TTL("alert_state:alert:1")
+	assert.Equal(t, 7*24*time.Hour, ttl)
+
+	server.FastForward(7 * 24 * time.Hour)
+	_, found, err = instanceB.Load(ctx, "alert:1")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestAlertStateTTL_FollowsTimeframe(t *testing.T) {
+	assert.Equal(t, 6*time.Hour, services.AlertStateTTL("1m"))
+	assert.Equal(t, 12*time.Hour, services.AlertStateTTL("4h"))
+	assert.Equal(t, 3*24*time.Hour, services.AlertStateTTL("1d"))
+	assert.Equal(t, 7*24*time.Hour, services.AlertStateTTL("unknown"))
+
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := services.NewRedisAlertStateStore(client)
+	require.NoError(t, store.Save(ctx, "alert:1", map[string]interface{}{"close": 1.0}, services.AlertStateTTL("4h")))
+	assert.Equal(t, 12*time.Hour, server.TTL("alert_state:alert:1"))
+}
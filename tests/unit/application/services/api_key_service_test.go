@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+func newAPIKeyService(t *testing.T) (*services.APIKeyService, *testutils.MemoryAPIKeyRepository, *testutils.FakeClock) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := testutils.NewMemoryAPIKeyRepository()
+	clock := testutils.NewFakeClock(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	service := services.NewAPIKeyService(repo, logger)
+	service.SetClock(clock)
+	return service, repo, clock
+}
+
+func TestAPIKeyService_CreateStoresOnlyTheHash(t *testing.T) {
+	service, repo, _ := newAPIKeyService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	created, err := service.CreateAPIKey(ctx, userID, "  Spreadsheet  ")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, "pg_"))
+	assert.Equal(t, "Spreadsheet", created.Name)
+	assert.Equal(t, created.Key[:len(created.Prefix)], created.Prefix)
+	assert.NotContains(t, created.KeyHash, created.Key)
+
+	keys, err := repo.GetByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotEqual(t, created.Key, keys[0].KeyHash)
+
+	key, err := service.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+}
+
+func TestAPIKeyService_CreateValidatesName(t *testing.T) {
+	service, _, _ := newAPIKeyService(t)
+
+	_, err := service.CreateAPIKey(context.Background(), uuid.New(), " ")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestAPIKeyService_CreateCapsActiveKeys(t *testing.T) {
+	service, _, _ := newAPIKeyService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var first *services.CreatedAPIKey
+	for i := 0; i < services.MaxAPIKeysPerUser; i++ {
+		created, err := service.CreateAPIKey(ctx, userID, "key")
+		require.NoError(t, err)
+		if first == nil {
+			first = created
+		}
+	}
+	_, err := service.CreateAPIKey(ctx, userID, "one too many")
+	assert.ErrorIs(t, err, services.ErrTooManyAPIKeys)
+
+	// Revoked keys free their slot
+	require.NoError(t, service.RevokeAPIKey(ctx, userID, first.ID))
+	_, err = service.CreateAPIKey(ctx, userID, "replacement")
+	assert.NoError(t, err)
+}
+
+func TestAPIKeyService_AuthenticateRejectsUnknownAndRevokedKeys(t *testing.T) {
+	service, _, _ := newAPIKeyService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	created, err := service.CreateAPIKey(ctx, userID, "Bot")
+	require.NoError(t, err)
+
+	_, err = service.Authenticate(ctx, "pg_unknown")
+	assert.ErrorIs(t, err, services.ErrAPIKeyNotFound)
+	_, err = service.Authenticate(ctx, strings.TrimPrefix(created.Key, "pg_"))
+	assert.ErrorIs(t, err, services.ErrAPIKeyNotFound)
+
+	// Only the owner can revoke the key
+	assert.ErrorIs(t, service.RevokeAPIKey(ctx, uuid.New(), created.ID), services.ErrAPIKeyNotFound)
+	require.NoError(t, service.RevokeAPIKey(ctx, userID, created.ID))
+	_, err = service.Authenticate(ctx, created.Key)
+	assert.ErrorIs(t, err, services.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, service.RevokeAPIKey(ctx, userID, created.ID), services.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_AuthenticateRecordsUseAtMostEveryMinute(t *testing.T) {
+	service, repo, clock := newAPIKeyService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	created, err := service.CreateAPIKey(ctx, userID, "Dashboard")
+	require.NoError(t, err)
+	firstUse := clock.Now()
+	_, err = service.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+
+	lastUsed := func() time.Time {
+		keys, err := repo.GetByUserID(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, keys[0].LastUsedAt)
+		return *keys[0].LastUsedAt
+	}
+	assert.Equal(t, firstUse, lastUsed())
+
+	clock.Advance(30 * time.Second)
+	_, err = service.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, firstUse, lastUsed())
+
+	clock.Advance(time.Minute)
+	_, err = service.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), lastUsed())
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKlineSource serves klines from listedAt on, honouring the interval, limit
+// and time range like Binance does
+type fakeKlineSource struct {
+	listedAt time.Time
+	requests int
+}
+
+func (f *fakeKlineSource) GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error) {
+	f.requests++
+	var klines [][]interface{}
+	first := time.UnixMilli(*startTime)
+	if first.Before(f.listedAt) {
+		first = f.listedAt
+	}
+	step := time.Duration(indicators.GetTimeframeMilliseconds(interval)) * time.Millisecond
+	for open := first; open.UnixMilli() <= *endTime && len(klines) < limit; open = open.Add(step) {
+		price := strconv.FormatFloat(100+float64(open.Minute()), 'f', 2, 64)
+		klines = append(klines, []interface{}{float64(open.UnixMilli()), price, price, price, price, "1.5"})
+	}
+	return klines, nil
+}
+
+func TestBackfillService_PagesThroughTheRangeAndSkipsStoredCandles(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2499 * time.Minute)
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+	require.NoError(t, priceRepo.Create(ctx, &entities.PriceHistory{
+		Symbol: "BTCUSDT", Timeframe: "1m", Timestamp: from.Add(10 * time.Minute), ClosePrice: 110,
+	}))
+
+	source := &fakeKlineSource{listedAt: from}
+	service := services.NewBackfillService(source, priceRepo, logger)
+
+	result, err := service.Backfill(ctx, services.BackfillRequest{Symbol: "BTCUSDT", Timeframe: "1m", From: from, To: to})
+	require.NoError(t, err)
+	assert.Equal(t, 2500, result.Fetched)
+	assert.Equal(t, 2499, result.Inserted)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 3, result.Pages)
+
+	stored, err := priceRepo.GetByTimeRange(ctx, "BTCUSDT", "1m", from, to)
+	require.NoError(t, err)
+	require.Len(t, stored, 2500)
+	assert.Equal(t, from, stored[0].Timestamp.UTC())
+	assert.Equal(t, to, stored[len(stored)-1].Timestamp.UTC())
+	assert.Equal(t, 1.5, stored[1].Volume)
+
+	// Running it again over the same range stores nothing new
+	result, err = service.Backfill(ctx, services.BackfillRequest{Symbol: "BTCUSDT", Timeframe: "1m", From: from, To: to})
+	require.NoError(t, err)
+	assert.Zero(t, result.Inserted)
+	assert.Equal(t, 2500, result.Skipped)
+}
+
+func TestBackfillService_ValidatesTheRequest(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	source := &fakeKlineSource{}
+	service := services.NewBackfillService(source, testutils.NewMemoryPriceHistoryRepository(), logger)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	for name, req := range map[string]services.BackfillRequest{
+		"timeframe": {Symbol: "BTCUSDT", Timeframe: "3m", From: from, To: from.Add(time.Hour)},
+		"to":        {Symbol: "BTCUSDT", Timeframe: "1h", From: from, To: from.Add(-time.Hour)},
+		"range":     {Symbol: "BTCUSDT", Timeframe: "1m", From: from, To: from.AddDate(0, 1, 0)},
+	} {
+		_, err := service.Backfill(context.Background(), req)
+		var validationErr *entities.ValidationError
+		assert.ErrorAs(t, err, &validationErr, name)
+	}
+	assert.Zero(t, source.requests)
+
+	// Without the cap, long ranges are allowed; a symbol listed late in the range
+	// has fewer candles than the range spans
+	service.SetMaxCandles(0)
+	to := from.AddDate(5, 0, 0)
+	source.listedAt = to.Add(-9 * time.Hour)
+	result, err := service.Backfill(context.Background(), services.BackfillRequest{Symbol: "BTCUSDT", Timeframe: "1h", From: from, To: to})
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Inserted)
+	assert.Equal(t, 1, result.Pages)
+}
@@ -0,0 +1,144 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var majors = []entities.BasketComponent{
+	{Symbol: "BTCUSDT", Weight: 50},
+	{Symbol: "ETHUSDT", Weight: 30},
+	{Symbol: "SOLUSDT", Weight: 20},
+}
+
+func newTestBasketService(repos *testutils.MemoryRepositories, clock *testutils.FakeClock) *services.BasketService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	service := services.NewBasketService(repos.Baskets, repos.PriceHistory, repos.Alerts, logger)
+	service.SetClock(clock)
+	return service
+}
+
+// storePrices stores a 1m candle closing at each price at the clock's time
+func storePrices(t *testing.T, repos *testutils.MemoryRepositories, clock *testutils.FakeClock, prices map[string]float64) {
+	for symbol, price := range prices {
+		require.NoError(t, repos.PriceHistory.Create(context.Background(), &entities.PriceHistory{
+			Symbol: symbol, Timeframe: "1m", Timestamp: clock.Now(),
+			OpenPrice: price, HighPrice: price, LowPrice: price, ClosePrice: price,
+		}))
+	}
+}
+
+func TestBasketService_ComputesTheWeightedReturnOfItsComponents(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service := newTestBasketService(repos, clock)
+	userID := uuid.New()
+
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 60000, "ETHUSDT": 3000, "SOLUSDT": 150})
+	basket, err := service.CreateBasket(ctx, userID, " Majors ", majors)
+	require.NoError(t, err)
+	assert.Equal(t, "Majors", basket.Name)
+	assert.True(t, entities.IsBasketSymbol(basket.Symbol))
+	assert.Equal(t, 60000.0, basket.Components[0].ReferencePrice)
+
+	run, err := service.ComputeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Stored)
+
+	// BTC +10%, ETH -10%, SOL +20%: 100 * (0.5*1.1 + 0.3*0.9 + 0.2*1.2) = 106
+	clock.Advance(time.Minute)
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 66000, "ETHUSDT": 2700, "SOLUSDT": 180})
+	run, err = service.ComputeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Stored)
+
+	// Nothing new to compute until a component price changes
+	run, err = service.ComputeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.Stored)
+	assert.Equal(t, 1, run.Skipped)
+
+	history, err := service.GetHistory(ctx, userID, basket.ID, "1m", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.InDelta(t, 100, history[0].ClosePrice, 1e-9)
+	assert.InDelta(t, 106, history[1].ClosePrice, 1e-9)
+
+	value, err := service.GetBasket(ctx, userID, basket.ID)
+	require.NoError(t, err)
+	require.NotNil(t, value.Value)
+	assert.InDelta(t, 106, *value.Value, 1e-9)
+
+	// Other users don't see the basket
+	_, err = service.GetBasket(ctx, uuid.New(), basket.ID)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestBasketService_SkipsStaleComponentsAndRequiresPricesAtCreation(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service := newTestBasketService(repos, clock)
+	userID := uuid.New()
+
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 60000, "ETHUSDT": 3000})
+	_, err := service.CreateBasket(ctx, userID, "Majors", majors)
+	var validationErr *entities.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Message, "SOLUSDT")
+
+	storePrices(t, repos, clock, map[string]float64{"SOLUSDT": 150})
+	_, err = service.CreateBasket(ctx, userID, "Majors", majors)
+	require.NoError(t, err)
+
+	// SOL stops updating: the basket keeps its last value
+	clock.Advance(10 * time.Minute)
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 61000, "ETHUSDT": 3100})
+	run, err := service.ComputeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.Stored)
+	assert.Equal(t, 1, run.Skipped)
+}
+
+func TestBasketService_AlertsAndDeletion(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service := newTestBasketService(repos, clock)
+	userID := uuid.New()
+
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 60000, "ETHUSDT": 3000, "SOLUSDT": 150})
+	basket, err := service.CreateBasket(ctx, userID, "Majors", majors)
+	require.NoError(t, err)
+
+	alert := testutils.NewAlertBuilder().ForUser(userID).Price(basket.Symbol).Above(110).On("1m").Build()
+	assert.NoError(t, service.CheckAlert(ctx, alert))
+	require.NoError(t, repos.Alerts.Create(ctx, alert))
+
+	var validationErr *entities.ValidationError
+	assert.ErrorAs(t, service.CheckAlert(ctx, testutils.NewAlertBuilder().Price(basket.Symbol).Above(110).On("1m").Build()), &validationErr, "foreign basket")
+	assert.ErrorAs(t, service.CheckAlert(ctx, testutils.NewAlertBuilder().ForUser(userID).RSI(basket.Symbol).Above(70).On("1m").Build()), &validationErr, "indicator alert")
+	assert.ErrorAs(t, service.CheckAlert(ctx, testutils.NewAlertBuilder().ForUser(userID).Price(basket.Symbol).Above(110).On("1h").Build()), &validationErr, "timeframe not computed")
+	assert.NoError(t, service.CheckAlert(ctx, testutils.NewAlertBuilder().RSI("BTCUSDT").Above(70).Build()))
+
+	deleted, err := service.DeleteBasket(ctx, userID, basket.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	_, err = repos.Alerts.GetByID(ctx, alert.ID)
+	assert.Error(t, err)
+	_, err = service.GetBasket(ctx, userID, basket.ID)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandleEventBus_DispatchesByTimeframeInOrder(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	bus := services.NewCandleEventBus(logger)
+
+	var calls []string
+	bus.Subscribe("1h", "indicators", func(ctx context.Context, event services.CandleClosed) error {
+		calls = append(calls, "indicators "+event.Symbol)
+		return errors.New("exchange unavailable")
+	})
+	bus.Subscribe("1h", "panicking", func(ctx context.Context, event services.CandleClosed) error {
+		panic("boom")
+	})
+	bus.Subscribe("1h", "alerts", func(ctx context.Context, event services.CandleClosed) error {
+		calls = append(calls, "alerts "+event.Symbol)
+		return nil
+	})
+	bus.Subscribe("1m", "ticks", func(ctx context.Context, event services.CandleClosed) error {
+		calls = append(calls, "ticks "+event.Symbol)
+		return nil
+	})
+
+	bus.Publish(context.Background(), services.CandleClosed{Symbol: "BTCUSDT", Timeframe: "1h"})
+	bus.Publish(context.Background(), services.CandleClosed{Symbol: "ETHUSDT", Timeframe: "4h"})
+
+	// A failing or panicking handler doesn't keep the next ones from running
+	assert.Equal(t, []string{"indicators BTCUSDT", "alerts BTCUSDT"}, calls)
+}
+
+func TestCandleCloseTracker_ClosesCandlesCrossedByLaterPrice(t *testing.T) {
+	tracker := services.NewCandleCloseTracker()
+	price := func(at time.Time, close float64) entities.PriceHistory {
+		return entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1m", Timestamp: at, ClosePrice: close}
+	}
+	start := time.Date(2024, 5, 1, 12, 58, 10, 0, time.UTC)
+
+	assert.Empty(t, tracker.Observe(price(start, 100)), "the first price closes nothing")
+	assert.Empty(t, tracker.Observe(price(start.Add(30*time.Second), 101)), "same minute")
+
+	closed := tracker.Observe(price(start.Add(60*time.Second), 102))
+	require.Len(t, closed, 1)
+	assert.Equal(t, "1m", closed[0].Timeframe)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 58, 0, 0, time.UTC), closed[0].OpenTime)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 59, 0, 0, time.UTC), closed[0].CloseTime)
+	assert.Equal(t, 101.0, closed[0].ClosePrice)
+
+	// Crossing the hour closes every timeframe up to 1h, the longest first
+	closed = tracker.Observe(price(time.Date(2024, 5, 1, 13, 0, 5, 0, time.UTC), 103))
+	timeframes := make([]string, len(closed))
+	for i, event := range closed {
+		timeframes[i] = event.Timeframe
+	}
+	assert.Equal(t, []string{"1h", "30m", "15m", "5m", "1m"}, timeframes)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), closed[0].OpenTime)
+
+	// Late prices are ignored
+	assert.Empty(t, tracker.Observe(price(start, 99)))
+}
+
+func TestAlertEngine_EvaluateSymbolAlerts(t *testing.T) {
+	s := testutils.NewAlertScenario(t)
+	btc := s.Alert(testutils.NewAlertBuilder().Price("BTCUSDT").Above(50000))
+	s.Alert(testutils.NewAlertBuilder().Price("ETHUSDT").Above(3000))
+	s.Price("BTCUSDT", "1h", 51000).Price("ETHUSDT", "1h", 3100)
+
+	results, err := s.Engine.EvaluateSymbolAlerts(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, btc.ID, results[0].AlertID)
+	assert.True(t, results[0].ShouldTrigger)
+}
@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dailyCloses builds newest-first daily candles from closes listed oldest first
+func dailyCloses(latest time.Time, closes ...float64) []entities.PriceHistory {
+	history := make([]entities.PriceHistory, len(closes))
+	for i, close := range closes {
+		history[len(closes)-1-i] = entities.PriceHistory{
+			ClosePrice: close,
+			Timestamp:  latest.AddDate(0, 0, i-len(closes)+1),
+		}
+	}
+	return history
+}
+
+func TestCorrelationService_Correlation(t *testing.T) {
+	latest := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1d", 6).Return(dailyCloses(latest, 100, 110, 99, 120, 114, 130), nil).Once()
+	// Same returns as BTCUSDT at a tenth of the price
+	priceRepo.On("GetBySymbol", mock.Anything, "ETHUSDT", "1d", 6).Return(dailyCloses(latest, 10, 11, 9.9, 12, 11.4, 13), nil).Once()
+	priceRepo.On("GetBySymbol", mock.Anything, "USDCUSDT", "1d", 6).Return(dailyCloses(latest, 1, 1, 1, 1, 1, 1), nil).Once()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewCorrelationService(priceRepo, logger)
+
+	matrix, err := service.Correlation(context.Background(), []string{"BTCUSDT", "ETHUSDT", "USDCUSDT"}, "1d", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT", "USDCUSDT"}, matrix.Symbols)
+	require.NotNil(t, matrix.Matrix[0][0])
+	assert.Equal(t, 1.0, *matrix.Matrix[0][0])
+	require.NotNil(t, matrix.Matrix[0][1])
+	assert.Equal(t, 1.0, *matrix.Matrix[0][1])
+	assert.Equal(t, matrix.Matrix[0][1], matrix.Matrix[1][0])
+	assert.Nil(t, matrix.Matrix[0][2], "a flat series has no correlation")
+	assert.Equal(t, 5, matrix.Observations[0][1])
+
+	// Served from cache: the repository expectations above only allow one call each
+	cached, err := service.Correlation(context.Background(), []string{"BTCUSDT", "ETHUSDT", "USDCUSDT"}, "1d", 5)
+	require.NoError(t, err)
+	assert.Same(t, matrix, cached)
+	priceRepo.AssertExpectations(t)
+}
+
+func TestCorrelationService_MatchesReturnsByCandleTime(t *testing.T) {
+	latest := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	btc := dailyCloses(latest, 100, 90, 99, 89.1, 98.01)
+	// Opposite moves, but the history stops a day earlier
+	eth := dailyCloses(latest.AddDate(0, 0, -1), 10, 11, 9.9, 10.89)
+
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1d", 31).Return(btc, nil)
+	priceRepo.On("GetBySymbol", mock.Anything, "ETHUSDT", "1d", 31).Return(eth, nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	matrix, err := services.NewCorrelationService(priceRepo, logger).Correlation(context.Background(), []string{"BTCUSDT", "ETHUSDT"}, "1d", 0)
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultCorrelationWindow, matrix.Window)
+	require.NotNil(t, matrix.Matrix[0][1])
+	assert.Equal(t, -1.0, *matrix.Matrix[0][1])
+	assert.Equal(t, 3, matrix.Observations[0][1])
+}
@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDetailIncludes(t *testing.T) {
+	opts, err := services.ParseDetailIncludes("indicators, alerts")
+	require.NoError(t, err)
+	assert.True(t, opts.Indicators)
+	assert.False(t, opts.Stats)
+	assert.True(t, opts.Alerts)
+
+	opts, err = services.ParseDetailIncludes("")
+	require.NoError(t, err)
+	assert.Equal(t, services.CryptoDetailOptions{}, opts)
+
+	_, err = services.ParseDetailIncludes("stats,orderbook")
+	assert.Error(t, err)
+}
+
+func TestStats24h(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Newest first, as the repository returns them
+	history := []entities.PriceHistory{
+		{OpenPrice: 104, HighPrice: 106, LowPrice: 103, ClosePrice: 105, Volume: 10, Timestamp: now},
+		{OpenPrice: 98, HighPrice: 110, LowPrice: 97, ClosePrice: 104, Volume: 20, Timestamp: now.Add(-time.Hour)},
+		{OpenPrice: 100, HighPrice: 101, LowPrice: 95, ClosePrice: 98, Volume: 30, Timestamp: now.Add(-2 * time.Hour)},
+	}
+
+	stats := services.Stats24h(history)
+	require.NotNil(t, stats)
+	assert.Equal(t, services.CryptoStats24h{
+		Open: 100, High: 110, Low: 95, Close: 105, Volume: 60, Change: 5, ChangePercent: 5,
+	}, *stats)
+
+	assert.Nil(t, services.Stats24h(nil))
+}
+
+func TestCryptoDetailService_GetDetailExtras(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	priceRepo := new(testutils.MockPriceHistoryRepository)
+	indicatorRepo := new(testutils.MockTechnicalIndicatorRepository)
+	alertRepo := new(testutils.MockAlertRepository)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewCryptoDetailService(
+		services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger), priceRepo, alertRepo)
+
+	rsi := 61.5
+	indicatorRepo.On("GetIndicatorKeys", mock.Anything, "BTCUSDT", "4h").Return([]string{"EMA_12", "RSI_14"}, nil)
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "4h", "RSI_14").
+		Return(&entities.TechnicalIndicator{IndicatorType: "RSI", Value: &rsi}, nil)
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "4h", mock.Anything).Return(nil, errors.New("not found"))
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 24).Return([]entities.PriceHistory{
+		{OpenPrice: 100, HighPrice: 102, LowPrice: 99, ClosePrice: 101, Volume: 5},
+	}, nil)
+	alertRepo.On("GetByUserID", mock.Anything, userID, mock.Anything, 0).Return([]entities.Alert{
+		{Symbol: "BTCUSDT", Enabled: true},
+		{Symbol: "btcusdt", Enabled: true},
+		{Symbol: "BTCUSDT", Enabled: false},
+		{Symbol: "ETHUSDT", Enabled: true},
+	}, nil)
+
+	extras, err := service.GetDetailExtras(ctx, "BTCUSDT", services.CryptoDetailOptions{
+		Indicators: true, Stats: true, Alerts: true, Timeframe: "4h", UserID: userID,
+	})
+	require.NoError(t, err)
+	require.Contains(t, extras.Indicators, "RSI_14")
+	assert.Equal(t, rsi, *extras.Indicators["RSI_14"].Value)
+	require.NotNil(t, extras.Stats24h)
+	assert.Equal(t, 101.0, extras.Stats24h.Close)
+	require.NotNil(t, extras.ActiveAlerts)
+	assert.Equal(t, 2, *extras.ActiveAlerts)
+
+	// Only the requested sections are loaded
+	extras, err = service.GetDetailExtras(ctx, "BTCUSDT", services.CryptoDetailOptions{Stats: true})
+	require.NoError(t, err)
+	assert.Nil(t, extras.Indicators)
+	assert.Nil(t, extras.ActiveAlerts)
+	alertRepo.AssertNumberOfCalls(t, "GetByUserID", 1)
+}
+
+func TestCryptoDetailService_GetDetailExtrasFails(t *testing.T) {
+	priceRepo := new(testutils.MockPriceHistoryRepository)
+	alertRepo := new(testutils.MockAlertRepository)
+	service := services.NewCryptoDetailService(nil, priceRepo, alertRepo)
+
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 24).Return([]entities.PriceHistory{}, errors.New("db down"))
+	alertRepo.On("GetByUserID", mock.Anything, mock.Anything, mock.Anything, 0).Return([]entities.Alert{}, nil)
+
+	_, err := service.GetDetailExtras(context.Background(), "BTCUSDT", services.CryptoDetailOptions{Stats: true, Alerts: true})
+	assert.Error(t, err)
+}
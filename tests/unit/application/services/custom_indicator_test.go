@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// closeChange is the change of the close over its period, a minimal custom indicator
+type closeChange struct{}
+
+func (closeChange) Name() string      { return "CloseChange" }
+func (closeChange) Period() int       { return 3 }
+func (closeChange) Params() []float64 { return nil }
+func (closeChange) Calculate(data []indicators.PriceData) (float64, map[string]interface{}, error) {
+	return data[len(data)-1].Close - data[0].Close, map[string]interface{}{"first": data[0].Close}, nil
+}
+
+func init() {
+	indicators.MustRegisterCustomIndicator(closeChange{})
+}
+
+func TestRegisterCustomIndicator_Rejections(t *testing.T) {
+	assert.Error(t, indicators.RegisterCustomIndicator(closeChange{}), "duplicate")
+	assert.Error(t, indicators.RegisterCustomIndicator(namedIndicator{name: "RSI"}), "built-in name")
+	assert.Error(t, indicators.RegisterCustomIndicator(namedIndicator{name: "Price"}), "existing alert type")
+
+	custom, ok := indicators.LookupCustomIndicator("closechange")
+	require.True(t, ok)
+	assert.Equal(t, "CloseChange_3", indicators.CustomIndicatorKey(custom))
+}
+
+type namedIndicator struct {
+	closeChange
+	name string
+}
+
+func (n namedIndicator) Name() string { return n.name }
+
+func TestAlertValidation_AcceptsCustomIndicatorAlerts(t *testing.T) {
+	alert := &entities.Alert{UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "closechange", ConditionType: "above", TargetValue: 5, Timeframe: "1h"}
+	assert.NoError(t, alert.Validate())
+
+	alert.ConditionType = "up"
+	assert.ErrorIs(t, alert.Validate(), entities.ErrValidation)
+}
+
+func TestTechnicalIndicatorService_CalculatesCustomIndicators(t *testing.T) {
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	// Newest first, as the repository returns it
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 3).Return([]entities.PriceHistory{
+		{ClosePrice: 110, Timestamp: latest},
+		{ClosePrice: 104, Timestamp: latest.Add(-time.Hour)},
+		{ClosePrice: 100, Timestamp: latest.Add(-2 * time.Hour)},
+	}, nil)
+	indicatorRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.TechnicalIndicator")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+
+	require.NoError(t, service.CalculateAndStoreCustom(context.Background(), "BTCUSDT", "1h", closeChange{}))
+
+	stored := indicatorRepo.Calls[0].Arguments.Get(1).(*entities.TechnicalIndicator)
+	assert.Equal(t, "CloseChange", stored.IndicatorType)
+	assert.Equal(t, "CloseChange_3", stored.IndicatorKey)
+	assert.Equal(t, 10.0, *stored.Value)
+	assert.Equal(t, 100.0, stored.Metadata["first"])
+}
+
+func TestAlertEngine_EvaluatesCustomIndicatorAlert(t *testing.T) {
+	candleTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{ClosePrice: 110, Timestamp: candleTime}, nil)
+	value := 10.0
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "CloseChange_3").
+		Return(&entities.TechnicalIndicator{IndicatorType: "CloseChange", IndicatorKey: "CloseChange_3", Value: &value, Timestamp: candleTime}, nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	engine := services.NewAlertEngine(&testutils.MockAlertRepository{}, priceRepo, indicatorRepo, &testutils.MockNotificationRepository{}, nil, logger)
+
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "closechange", ConditionType: "below", TargetValue: 5, Timeframe: "1h", Enabled: true}
+	result, err := engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	assert.Equal(t, value, result.CurrentValue)
+	assert.False(t, result.ShouldTrigger)
+	indicatorRepo.AssertExpectations(t)
+}
@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDCASimulationService_Simulate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 20)
+	// Daily closes oldest first; no candle on day 7, so the second weekly buy uses day 8
+	candles := make([]entities.PriceHistory, 0, 21)
+	for day := 0; day <= 20; day++ {
+		if day == 7 {
+			continue
+		}
+		close := 100.0
+		if day >= 8 {
+			close = 50
+		}
+		if day == 20 {
+			close = 200
+		}
+		candles = append(candles, entities.PriceHistory{ClosePrice: close, Timestamp: start.AddDate(0, 0, day)})
+	}
+
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetByTimeRange", mock.Anything, "BTCUSDT", "1d", start, end).Return(candles, nil)
+
+	result, err := services.NewDCASimulationService(priceRepo).Simulate(context.Background(), services.DCASimulationRequest{
+		Symbol: "BTCUSDT", Amount: 100, Frequency: services.DCAFrequencyWeekly, StartDate: start, EndDate: end,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Points, 3)
+	assert.Equal(t, start, result.Points[0].Date)
+	assert.Equal(t, start.AddDate(0, 0, 8), result.Points[1].Date)
+	assert.Equal(t, start.AddDate(0, 0, 14), result.Points[2].Date)
+	assert.Equal(t, 1.0, result.Points[0].Holdings)
+	assert.Equal(t, -25.0, result.Points[1].ROI)
+
+	assert.Equal(t, 3, result.Purchases)
+	assert.Equal(t, 300.0, result.Invested)
+	assert.Equal(t, 5.0, result.Holdings)
+	assert.Equal(t, 60.0, result.AverageCost)
+	assert.Equal(t, 200.0, result.FinalPrice)
+	assert.Equal(t, 1000.0, result.FinalValue)
+	assert.InDelta(t, 233.3333, result.ROI, 1e-4)
+}
+
+func TestDCASimulationService_Validation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := services.NewDCASimulationService(&testutils.MockPriceHistoryRepository{})
+
+	for name, req := range map[string]services.DCASimulationRequest{
+		"amount":    {Symbol: "BTCUSDT", Amount: 0, Frequency: "daily", StartDate: start, EndDate: start.AddDate(0, 1, 0)},
+		"frequency": {Symbol: "BTCUSDT", Amount: 10, Frequency: "hourly", StartDate: start, EndDate: start.AddDate(0, 1, 0)},
+		"end_date":  {Symbol: "BTCUSDT", Amount: 10, Frequency: "daily", StartDate: start, EndDate: start},
+		"range":     {Symbol: "BTCUSDT", Amount: 10, Frequency: "daily", StartDate: start, EndDate: start.AddDate(11, 0, 0)},
+	} {
+		_, err := service.Simulate(context.Background(), req)
+		assert.ErrorIs(t, err, entities.ErrValidation, name)
+	}
+}
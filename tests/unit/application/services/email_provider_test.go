@@ -0,0 +1,267 @@
+package services_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmailSender fails the first sends with errs and records the rest
+type fakeEmailSender struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	sent     []*services.EmailMessage
+}
+
+func (s *fakeEmailSender) SendEmail(ctx context.Context, message *services.EmailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return err
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func newEmailRecipient(userRepo *testutils.MockUserRepository) uuid.UUID {
+	userID := uuid.New()
+	userRepo.On("GetByID", mock.Anything, userID).Return(&entities.User{ID: userID, Name: "Ada <Lovelace>", Email: "ada@example.com"}, nil)
+	return userID
+}
+
+func TestEmailProvider_RendersTemplatedEmail(t *testing.T) {
+	userRepo := new(testutils.MockUserRepository)
+	userID := newEmailRecipient(userRepo)
+	sender := &fakeEmailSender{}
+	provider := services.NewEmailProvider(services.SMTPProviderName, sender, userRepo)
+
+	require.NoError(t, provider.Send(context.Background(), &services.QueuedNotification{
+		UserID:  userID,
+		Title:   "Price Alert Triggered",
+		Message: "Your alert for BTCUSDT has been triggered.",
+		Data:    map[string]interface{}{"symbol": "BTCUSDT", "current_value": 51000.5, "target_value": 50000},
+	}))
+
+	require.Len(t, sender.sent, 1)
+	message := sender.sent[0]
+	assert.Equal(t, "ada@example.com", message.To)
+	assert.Equal(t, "Price Alert Triggered", message.Subject)
+	assert.Equal(t, "Your alert for BTCUSDT has been triggered.", message.TextBody)
+	assert.Contains(t, message.HTMLBody, "<h2 style=\"margin-top: 0;\">Price Alert Triggered</h2>")
+	assert.Contains(t, message.HTMLBody, "Hi Ada &lt;Lovelace&gt;,")
+	assert.Contains(t, message.HTMLBody, "51000.5")
+	assert.Less(t, strings.Index(message.HTMLBody, "BTCUSDT</td>"), strings.Index(message.HTMLBody, "51000.5"))
+}
+
+func TestEmailProvider_RetriesTransientFailures(t *testing.T) {
+	userRepo := new(testutils.MockUserRepository)
+	userID := newEmailRecipient(userRepo)
+	notification := &services.QueuedNotification{UserID: userID, Title: "Price Alert Triggered"}
+
+	sender := &fakeEmailSender{errs: []error{errors.New("connection reset"), &textproto.Error{Code: 421, Msg: "try again later"}}}
+	provider := services.NewEmailProvider(services.SMTPProviderName, sender, userRepo)
+	provider.SetRetry(3, time.Millisecond)
+	require.NoError(t, provider.Send(context.Background(), notification))
+	assert.Equal(t, 3, sender.attempts)
+	assert.Len(t, sender.sent, 1)
+
+	// Rejections are final
+	sender = &fakeEmailSender{errs: []error{&textproto.Error{Code: 550, Msg: "mailbox unavailable"}}}
+	provider = services.NewEmailProvider(services.SMTPProviderName, sender, userRepo)
+	provider.SetRetry(3, time.Millisecond)
+	assert.Error(t, provider.Send(context.Background(), notification))
+	assert.Equal(t, 1, sender.attempts)
+
+	// Retries stop at the limit
+	sender = &fakeEmailSender{errs: []error{errors.New("timeout"), errors.New("timeout"), errors.New("timeout")}}
+	provider = services.NewEmailProvider(services.SMTPProviderName, sender, userRepo)
+	provider.SetRetry(2, time.Millisecond)
+	assert.Error(t, provider.Send(context.Background(), notification))
+	assert.Equal(t, 2, sender.attempts)
+}
+
+// smtpServer is a minimal SMTP server accepting every message
+type smtpServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+}
+
+func startSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.Fields(line + " ")[0]); command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSMTPSender_DeliversMultipartEmail(t *testing.T) {
+	server := startSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	sender := services.NewSMTPSender(services.SMTPConfig{Host: host, Port: portNumber, From: "alerts@priceguard.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, sender.HealthCheck(ctx))
+	require.NoError(t, sender.SendEmail(ctx, &services.EmailMessage{
+		To:       "ada@example.com",
+		Subject:  "Price Alert Triggered\r\nBcc: victim@example.com",
+		HTMLBody: "<p>BTCUSDT crossed 50000</p>",
+		TextBody: "BTCUSDT crossed 50000",
+	}))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Len(t, server.messages, 1)
+	message, err := textproto.NewReader(bufio.NewReader(strings.NewReader(server.messages[0]))).ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", message.Get("To"))
+	assert.Equal(t, "Price Alert Triggered Bcc: victim@example.com", message.Get("Subject"))
+	assert.Empty(t, message.Get("Bcc"))
+	assert.Contains(t, message.Get("Content-Type"), "multipart/alternative")
+	assert.Contains(t, server.messages[0], "<p>BTCUSDT crossed 50000</p>")
+}
+
+func TestSMTPProviderFactory_RequiresServer(t *testing.T) {
+	factory := services.SMTPProviderFactory(services.SMTPConfig{Port: 587, From: "alerts@priceguard.com"}, new(testutils.MockUserRepository))
+
+	_, err := factory(nil)
+	assert.Error(t, err)
+
+	provider, err := factory(map[string]string{"host": "smtp.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, services.SMTPProviderName, provider.Name())
+
+	_, err = factory(map[string]string{"host": "smtp.example.com", "from": "not an address"})
+	assert.Error(t, err)
+}
+
+func TestNotificationService_EmailFollowsUserSettings(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	optedIn, optedOut, failing := uuid.New(), uuid.New(), uuid.New()
+	settingsRepo := new(testutils.MockUserSettingsRepository)
+	settingsRepo.On("GetByUserID", mock.Anything, optedIn).Return(&entities.UserSettings{NotificationsEmail: true}, nil)
+	settingsRepo.On("GetByUserID", mock.Anything, optedOut).Return(&entities.UserSettings{NotificationsEmail: false}, nil)
+	settingsRepo.On("GetByUserID", mock.Anything, failing).Return(&entities.UserSettings{NotificationsEmail: true}, nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+	service.SetClock(testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+	service.SetUserSettingsRepository(settingsRepo)
+	sender := &failingForSender{failFor: failing}
+	service.SetChannelSender(services.ChannelEmail, sender)
+
+	for _, userID := range []uuid.UUID{optedIn, optedOut, failing} {
+		require.NoError(t, service.QueueNotification(ctx, &services.QueuedNotification{
+			UserID:   userID,
+			Title:    "Price Alert Triggered",
+			Channels: []services.NotificationChannel{services.ChannelEmail},
+		}))
+	}
+	assert.Equal(t, 3, service.ProcessBatch(ctx))
+
+	assert.Equal(t, []uuid.UUID{optedIn}, sender.sent)
+	stats := service.DeliveryStats()[services.ChannelEmail]
+	assert.Equal(t, int64(1), stats.Sent)
+	assert.Equal(t, int64(1), stats.Skipped)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Contains(t, stats.LastError, "smtp unavailable")
+	require.NotNil(t, stats.LastErrorAt)
+
+	// Only the failed delivery is retried
+	assert.Equal(t, int64(1), client.ZCard(ctx, "notification_queue").Val())
+
+	notificationStats, err := service.GetNotificationStats(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, notificationStats, "deliveries")
+}
+
+// failingForSender fails deliveries to one user and records the others
+type failingForSender struct {
+	mu      sync.Mutex
+	failFor uuid.UUID
+	sent    []uuid.UUID
+}
+
+func (s *failingForSender) Send(ctx context.Context, notification *services.QueuedNotification) error {
+	if notification.UserID == s.failFor {
+		return errors.New("smtp unavailable")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, notification.UserID)
+	return nil
+}
@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportServiceTestSuite struct {
+	suite.Suite
+	userRepo         *testutils.MockUserRepository
+	settingsRepo     *testutils.MockUserSettingsRepository
+	alertRepo        *testutils.MockAlertRepository
+	notificationRepo *testutils.MockNotificationRepository
+	storage          *testutils.MemoryStorage
+	clock            *testutils.FakeClock
+	service          *services.ExportService
+	userID           uuid.UUID
+	ctx              context.Context
+}
+
+func (suite *ExportServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.userID = uuid.New()
+	suite.userRepo = new(testutils.MockUserRepository)
+	suite.settingsRepo = new(testutils.MockUserSettingsRepository)
+	suite.alertRepo = new(testutils.MockAlertRepository)
+	suite.notificationRepo = new(testutils.MockNotificationRepository)
+	suite.clock = testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC))
+	suite.storage = testutils.NewMemoryStorage()
+	suite.storage.SetClock(suite.clock)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	suite.service = services.NewExportService(suite.userRepo, suite.settingsRepo, suite.alertRepo,
+		suite.notificationRepo, suite.storage, 30*time.Minute, logger)
+	suite.service.SetClock(suite.clock)
+
+	suite.alertRepo.On("GetByUserID", mock.Anything, suite.userID, 500, 0).Return([]entities.Alert{
+		{
+			ID:            uuid.New(),
+			UserID:        suite.userID,
+			Symbol:        "BTCUSDT",
+			AlertType:     "price",
+			ConditionType: "above",
+			TargetValue:   65000.5,
+			Timeframe:     "1h",
+			Enabled:       true,
+			NotifyVia:     []string{"app", "email"},
+		},
+	}, nil)
+}
+
+func (suite *ExportServiceTestSuite) TestJSONExport() {
+	suite.userRepo.On("GetByID", mock.Anything, suite.userID).Return(&entities.User{ID: suite.userID, Email: "ana@example.com"}, nil)
+	suite.settingsRepo.On("GetByUserID", mock.Anything, suite.userID).Return(&entities.UserSettings{UserID: suite.userID, Theme: "dark"}, nil)
+	suite.notificationRepo.On("GetByUserID", mock.Anything, suite.userID, 500, 0).Return([]entities.Notification{
+		{ID: uuid.New(), UserID: suite.userID, Title: "BTC above target"},
+	}, nil)
+
+	result, err := suite.service.ExportUserData(suite.ctx, suite.userID, services.ExportFormatJSON)
+	suite.Require().NoError(err)
+
+	suite.Equal("exports/"+suite.userID.String()+"/20240501T093000Z.json", result.Key)
+	suite.Equal(services.ExportFormatJSON, result.Format)
+	suite.Equal(suite.clock.Now().Add(30*time.Minute), result.ExpiresAt)
+	suite.True(strings.HasPrefix(result.URL, suite.storage.PublicURL(result.Key)))
+
+	data, contentType, exists := suite.storage.Object(result.Key)
+	suite.Require().True(exists)
+	suite.Equal("application/json", contentType)
+
+	var export map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(data, &export))
+	suite.Equal("ana@example.com", export["user"].(map[string]interface{})["email"])
+	suite.Equal("dark", export["settings"].(map[string]interface{})["theme"])
+	suite.Len(export["alerts"], 1)
+	suite.Len(export["notifications"], 1)
+}
+
+func (suite *ExportServiceTestSuite) TestCSVExport() {
+	result, err := suite.service.ExportUserData(suite.ctx, suite.userID, services.ExportFormatCSV)
+	suite.Require().NoError(err)
+
+	data, contentType, exists := suite.storage.Object(result.Key)
+	suite.Require().True(exists)
+	suite.Equal("text/csv", contentType)
+
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 2)
+	suite.Equal("symbol", rows[0][1])
+	suite.Equal("BTCUSDT", rows[1][1])
+	suite.Equal("65000.5", rows[1][4])
+	suite.Equal("app|email", rows[1][7])
+	suite.userRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything, mock.Anything)
+}
+
+func (suite *ExportServiceTestSuite) TestMissingUserFails() {
+	suite.userRepo.On("GetByID", mock.Anything, suite.userID).Return(nil, errors.New("record not found"))
+
+	_, err := suite.service.ExportUserData(suite.ctx, suite.userID, services.ExportFormatJSON)
+	suite.Error(err)
+	suite.Empty(suite.storage.Keys())
+}
+
+func (suite *ExportServiceTestSuite) TestParseExportFormat() {
+	format, err := services.ParseExportFormat("")
+	suite.NoError(err)
+	suite.Equal(services.ExportFormatJSON, format)
+
+	format, err = services.ParseExportFormat("CSV")
+	suite.NoError(err)
+	suite.Equal(services.ExportFormatCSV, format)
+
+	_, err = services.ParseExportFormat("xlsx")
+	suite.Error(err)
+}
+
+func TestExportServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportServiceTestSuite))
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/repositories"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// fakeFCM answers sends per token: tokens in unregistered are rejected as
+// UNREGISTERED, those in failing get a 503
+type fakeFCM struct {
+	mu           sync.Mutex
+	unregistered map[string]bool
+	failing      map[string]bool
+	received     []map[string]interface{}
+}
+
+func (f *fakeFCM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/projects/priceguard/messages:send" || r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		Message map[string]interface{} `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	token, _ := body.Message["token"].(string)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.unregistered[token]:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",
+			"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+	case f.failing[token]:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":503,"message":"The service is currently unavailable.","status":"UNAVAILABLE"}}`))
+	default:
+		f.received = append(f.received, body.Message)
+		w.Write([]byte(`{"name":"projects/priceguard/messages/1"}`))
+	}
+}
+
+func newTestFCMProvider(t *testing.T, fcm *fakeFCM, devices repositories.DeviceTokenRepository) *services.FCMProvider {
+	server := httptest.NewServer(fcm)
+	t.Cleanup(server.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	provider := services.NewFCMProvider("priceguard", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}), devices, logger)
+	provider.SetBaseURL(server.URL)
+	return provider
+}
+
+func TestFCMProvider_SendsToEveryDeviceAndPrunesInvalidTokens(t *testing.T) {
+	userID := uuid.New()
+	devices := new(testutils.MockDeviceTokenRepository)
+	devices.On("GetByUserID", mock.Anything, userID).Return([]entities.DeviceToken{
+		{UserID: userID, Token: "phone", Platform: "android"},
+		{UserID: userID, Token: "tablet", Platform: "ios"},
+		{UserID: userID, Token: "uninstalled", Platform: "android"},
+	}, nil)
+	devices.On("DeleteByTokens", mock.Anything, []string{"uninstalled"}).Return(int64(1), nil)
+
+	fcm := &fakeFCM{unregistered: map[string]bool{"uninstalled": true}}
+	provider := newTestFCMProvider(t, fcm, devices)
+
+	notification := &services.QueuedNotification{
+		ID:      uuid.New(),
+		UserID:  userID,
+		Type:    "alert_triggered",
+		Title:   "Price Alert Triggered",
+		Message: "BTCUSDT crossed 50000",
+		Data:    map[string]interface{}{"symbol": "BTCUSDT", "current_value": 51000.5},
+	}
+	require.NoError(t, provider.Send(context.Background(), notification))
+
+	require.Len(t, fcm.received, 2)
+	for _, message := range fcm.received {
+		assert.Equal(t, map[string]interface{}{"title": "Price Alert Triggered", "body": "BTCUSDT crossed 50000"}, message["notification"])
+		data := message["data"].(map[string]interface{})
+		assert.Equal(t, "BTCUSDT", data["symbol"])
+		assert.Equal(t, "51000.5", data["current_value"])
+		assert.Equal(t, notification.ID.String(), data["notification_id"])
+	}
+	devices.AssertExpectations(t)
+}
+
+func TestFCMProvider_FailsWhenNoDeviceGotThePush(t *testing.T) {
+	userID := uuid.New()
+	devices := new(testutils.MockDeviceTokenRepository)
+	devices.On("GetByUserID", mock.Anything, userID).Return([]entities.DeviceToken{
+		{UserID: userID, Token: "phone", Platform: "android"},
+	}, nil)
+
+	provider := newTestFCMProvider(t, &fakeFCM{failing: map[string]bool{"phone": true}}, devices)
+
+	err := provider.Send(context.Background(), &services.QueuedNotification{UserID: userID, Title: "Price Alert Triggered"})
+	assert.ErrorContains(t, err, "503")
+	devices.AssertNotCalled(t, "DeleteByTokens", mock.Anything, mock.Anything)
+}
+
+func TestFCMProvider_NoDevicesIsNotAFailure(t *testing.T) {
+	userID := uuid.New()
+	devices := new(testutils.MockDeviceTokenRepository)
+	devices.On("GetByUserID", mock.Anything, userID).Return([]entities.DeviceToken{}, nil)
+
+	fcm := &fakeFCM{}
+	provider := newTestFCMProvider(t, fcm, devices)
+
+	assert.NoError(t, provider.Send(context.Background(), &services.QueuedNotification{UserID: userID, Title: "Price Alert Triggered"}))
+	assert.Empty(t, fcm.received)
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+}
+
+func TestFCMProvider_ConcurrentSendsPruneAgainstSharedStore(t *testing.T) {
+	ctx := context.Background()
+	devices := testutils.NewMemoryDeviceTokenRepository()
+	fcm := &fakeFCM{unregistered: map[string]bool{}}
+
+	users := make([]uuid.UUID, 20)
+	for i := range users {
+		users[i] = uuid.New()
+		require.NoError(t, devices.Register(ctx, &entities.DeviceToken{UserID: users[i], Token: "phone-" + users[i].String(), Platform: "android"}))
+		require.NoError(t, devices.Register(ctx, &entities.DeviceToken{UserID: users[i], Token: "old-" + users[i].String(), Platform: "ios"}))
+		fcm.unregistered["old-"+users[i].String()] = true
+	}
+
+	provider := newTestFCMProvider(t, fcm, devices)
+
+	var wg sync.WaitGroup
+	for _, userID := range users {
+		wg.Add(1)
+		go func(userID uuid.UUID) {
+			defer wg.Done()
+			assert.NoError(t, provider.Send(ctx, &services.QueuedNotification{ID: uuid.New(), UserID: userID, Title: "Price Alert Triggered"}))
+		}(userID)
+	}
+	wg.Wait()
+
+	assert.Len(t, fcm.received, len(users))
+	for _, userID := range users {
+		remaining, err := devices.GetByUserID(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1, "only the uninstalled device is pruned")
+		assert.Equal(t, "phone-"+userID.String(), remaining[0].Token)
+	}
+}
@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateIchimoku_ProjectsTheCloudAKijunPeriodAhead(t *testing.T) {
+	rising := make([]indicators.PriceData, 78)
+	for i := range rising {
+		rising[i] = indicators.PriceData{High: 101 + float64(i), Low: 99 + float64(i), Close: 100 + float64(i)}
+	}
+
+	result, err := indicators.CalculateIchimoku(rising, 9, 26, 52)
+	require.NoError(t, err)
+	assert.Equal(t, 173.0, result.Tenkan)
+	assert.Equal(t, 164.5, result.Kijun)
+	assert.Equal(t, 168.75, result.SenkouA)
+	assert.Equal(t, 151.5, result.SenkouB)
+	assert.Equal(t, 177.0, result.Chikou)
+
+	// The cloud under the latest candle comes from the lines 26 candles earlier
+	assert.Equal(t, 142.75, result.CloudTop)
+	assert.Equal(t, 125.5, result.CloudBottom)
+	assert.Equal(t, "bullish", result.CloudColor)
+	assert.Equal(t, indicators.CloudAbove, result.Position)
+
+	_, err = indicators.CalculateIchimoku(rising[:77], 9, 26, 52)
+	assert.Error(t, err, "the projected Senkou B needs 52+26 candles")
+}
+
+func TestTechnicalIndicatorService_CalculatesIchimoku(t *testing.T) {
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 78).Return(trendingHistory(latest, 78, 1), nil)
+	indicatorRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.TechnicalIndicator")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+
+	require.NoError(t, service.CalculateAndStoreIchimoku(context.Background(), "BTCUSDT", "1h"))
+
+	indicatorRepo.AssertNumberOfCalls(t, "Create", 1)
+	ichimoku := indicatorRepo.Calls[0].Arguments.Get(1).(*entities.TechnicalIndicator)
+	assert.Equal(t, "Ichimoku_9_26_52", ichimoku.IndicatorKey)
+	assert.Equal(t, 96.0, *ichimoku.Value)
+	assert.Equal(t, "above", ichimoku.Metadata["position"], "candles are read oldest first")
+	assert.Equal(t, "bullish", ichimoku.Metadata["cloud_color"])
+	assert.Greater(t, ichimoku.Metadata["cloud_top"], ichimoku.Metadata["cloud_bottom"])
+}
+
+func TestTechnicalIndicatorService_IchimokuNeedsTheDisplacedCloud(t *testing.T) {
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 78).Return(trendingHistory(time.Now(), 60, 1), nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, &testutils.MockTechnicalIndicatorRepository{}, logger)
+
+	err := service.CalculateAndStoreIchimoku(context.Background(), "BTCUSDT", "1h")
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+}
+
+func TestAlertEngine_IchimokuCloudTransitions(t *testing.T) {
+	candleTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cloud := &entities.TechnicalIndicator{
+		IndicatorType: "Ichimoku",
+		IndicatorKey:  "Ichimoku_9_26_52",
+		Metadata:      map[string]interface{}{"cloud_top": 110.0, "cloud_bottom": 105.0},
+		Timestamp:     candleTime,
+	}
+
+	// evaluate runs an alert on the given closes and returns whether each triggered
+	evaluate := func(conditionType string, closes ...float64) []bool {
+		alertRepo := &testutils.MockAlertRepository{}
+		priceRepo := &testutils.MockPriceHistoryRepository{}
+		indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+		notificationRepo := &testutils.MockNotificationRepository{}
+		for _, close := range closes {
+			priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{ClosePrice: close, Timestamp: candleTime}, nil).Once()
+		}
+		indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "Ichimoku_9_26_52").Return(cloud, nil)
+		alertRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Alert")).Return(nil)
+		alertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+		notificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		engine := services.NewAlertEngine(alertRepo, priceRepo, indicatorRepo, notificationRepo, nil, logger)
+		alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "ichimoku_cloud", ConditionType: conditionType, Timeframe: "1h", Enabled: true}
+		require.NoError(t, alert.Validate())
+
+		triggered := make([]bool, len(closes))
+		for i := range closes {
+			result, err := engine.EvaluateAlert(context.Background(), alert)
+			require.NoError(t, err)
+			triggered[i] = result.ShouldTrigger
+		}
+		return triggered
+	}
+
+	// The first evaluation only records where the close stands
+	assert.Equal(t, []bool{false, false, true}, evaluate("enters", 100, 104, 107))
+	assert.Equal(t, []bool{false, false, true}, evaluate("exits", 107, 108, 104))
+	assert.Equal(t, []bool{false, false, false, true}, evaluate("exits_above", 107, 104, 107, 115), "leaving below isn't leaving above")
+	assert.Equal(t, []bool{false, false}, evaluate("exits_below", 115, 100), "crossing the whole cloud never was inside it")
+}
@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type IncidentServiceTestSuite struct {
+	suite.Suite
+	bannerRepo *testutils.MockSystemBannerRepository
+	wsService  *testutils.MockAlertWebSocketService
+	clock      *testutils.FakeClock
+	service    *services.IncidentService
+	ctx        context.Context
+}
+
+func (suite *IncidentServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.bannerRepo = new(testutils.MockSystemBannerRepository)
+	suite.wsService = new(testutils.MockAlertWebSocketService)
+	suite.clock = testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	suite.service = services.NewIncidentService(suite.bannerRepo, suite.wsService, logger)
+	suite.service.SetClock(suite.clock)
+}
+
+func (suite *IncidentServiceTestSuite) TestPublishBannerBroadcastsToAllClients() {
+	banner := &entities.SystemBanner{
+		Title:     "Binance degraded",
+		Message:   "Price updates may be delayed",
+		ExpiresAt: suite.clock.Now().Add(2 * time.Hour),
+	}
+
+	suite.bannerRepo.On("Create", suite.ctx, banner).Return(nil)
+	suite.wsService.On("BroadcastSystemAlert", suite.ctx, services.SystemAlertBanner, "Binance degraded",
+		"Price updates may be delayed", mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["severity"] == "info" && data["pinned"] == true
+		})).Return(nil)
+
+	err := suite.service.PublishBanner(suite.ctx, banner)
+
+	suite.NoError(err)
+	suite.Equal("info", banner.Severity)
+	suite.Equal(suite.clock.Now(), banner.CreatedAt)
+	suite.bannerRepo.AssertExpectations(suite.T())
+	suite.wsService.AssertExpectations(suite.T())
+}
+
+func (suite *IncidentServiceTestSuite) TestPublishBannerRejectsInvalidBanner() {
+	banner := &entities.SystemBanner{
+		Title:     "Maintenance",
+		Message:   "Database upgrade",
+		Severity:  "warning",
+		ExpiresAt: suite.clock.Now().Add(-time.Minute),
+	}
+
+	err := suite.service.PublishBanner(suite.ctx, banner)
+
+	suite.True(errors.Is(err, entities.ErrValidation))
+	suite.bannerRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.wsService.AssertNotCalled(suite.T(), "BroadcastSystemAlert", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *IncidentServiceTestSuite) TestPublishBannerSurvivesBroadcastFailure() {
+	banner := &entities.SystemBanner{
+		Title:     "Maintenance",
+		Message:   "Database upgrade",
+		Severity:  "warning",
+		ExpiresAt: suite.clock.Now().Add(time.Hour),
+	}
+
+	suite.bannerRepo.On("Create", suite.ctx, banner).Return(nil)
+	suite.wsService.On("BroadcastSystemAlert", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("hub closed"))
+
+	suite.NoError(suite.service.PublishBanner(suite.ctx, banner))
+}
+
+func (suite *IncidentServiceTestSuite) TestRevokeBanner() {
+	id := uuid.New()
+	banner := &entities.SystemBanner{
+		ID:        id,
+		Title:     "Maintenance",
+		Message:   "Database upgrade",
+		Severity:  "warning",
+		ExpiresAt: suite.clock.Now().Add(time.Hour),
+	}
+
+	suite.bannerRepo.On("GetByID", suite.ctx, id).Return(banner, nil)
+	suite.bannerRepo.On("Revoke", suite.ctx, id, suite.clock.Now()).Return(nil)
+	suite.wsService.On("BroadcastSystemAlert", suite.ctx, services.SystemAlertBannerRevoked, "Maintenance",
+		"Database upgrade", mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["banner_id"] == id && data["pinned"] == false
+		})).Return(nil)
+
+	revoked, err := suite.service.RevokeBanner(suite.ctx, id)
+
+	suite.NoError(err)
+	suite.Require().NotNil(revoked.RevokedAt)
+	suite.Equal(suite.clock.Now(), *revoked.RevokedAt)
+	suite.wsService.AssertExpectations(suite.T())
+}
+
+func (suite *IncidentServiceTestSuite) TestRevokeExpiredBanner() {
+	id := uuid.New()
+	banner := &entities.SystemBanner{
+		ID:        id,
+		ExpiresAt: suite.clock.Now().Add(time.Hour),
+	}
+	suite.bannerRepo.On("GetByID", suite.ctx, id).Return(banner, nil)
+	suite.clock.Advance(2 * time.Hour)
+
+	_, err := suite.service.RevokeBanner(suite.ctx, id)
+
+	suite.ErrorIs(err, services.ErrBannerNotActive)
+	suite.bannerRepo.AssertNotCalled(suite.T(), "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *IncidentServiceTestSuite) TestGetActiveBannersUsesClock() {
+	active := []entities.SystemBanner{{ID: uuid.New(), Title: "Maintenance"}}
+	suite.bannerRepo.On("GetActive", suite.ctx, suite.clock.Now()).Return(active, nil)
+
+	banners, err := suite.service.GetActiveBanners(suite.ctx)
+
+	suite.NoError(err)
+	suite.Equal(active, banners)
+}
+
+func TestIncidentServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(IncidentServiceTestSuite))
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newIndicatorCache(t *testing.T) *services.IndicatorCache {
+	srv := miniredis.RunT(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	layered := cache.NewLayeredCache(10, time.Minute, redis.NewClient(&redis.Options{Addr: srv.Addr()}), cache.WriteThrough, logger)
+	t.Cleanup(func() { layered.Close() })
+	return services.NewIndicatorCache(layered, logger)
+}
+
+func indicatorHistory(latest time.Time, candles int) []entities.PriceHistory {
+	history := make([]entities.PriceHistory, candles)
+	for i := range history {
+		history[i] = entities.PriceHistory{ClosePrice: 100 + float64(i), Timestamp: latest.Add(-time.Duration(i) * time.Hour)}
+	}
+	return history
+}
+
+func TestTechnicalIndicatorService_ReusesResultWithinCandle(t *testing.T) {
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 24).Return(indicatorHistory(latest, 24), nil).Twice()
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 24).Return(indicatorHistory(latest.Add(time.Hour), 24), nil).Once()
+	indicatorRepo.On("Create", mock.Anything, mock.MatchedBy(func(indicator *entities.TechnicalIndicator) bool {
+		return indicator.IndicatorKey == "EMA_12"
+	})).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+	service.SetResultCache(newIndicatorCache(t))
+
+	require.NoError(t, service.CalculateAndStoreEMA(context.Background(), "BTCUSDT", "1h", 12))
+	require.NoError(t, service.CalculateAndStoreEMA(context.Background(), "BTCUSDT", "1h", 12))
+	indicatorRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	cached, found := service.CachedIndicator(context.Background(), "BTCUSDT", "1h", "EMA_12", latest)
+	require.True(t, found)
+	assert.Equal(t, "EMA", cached.IndicatorType)
+
+	// A new candle invalidates the result
+	require.NoError(t, service.CalculateAndStoreEMA(context.Background(), "BTCUSDT", "1h", 12))
+	indicatorRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestAlertEngine_UsesCachedIndicator(t *testing.T) {
+	candleTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	indicatorCache := newIndicatorCache(t)
+	rsi := 75.0
+	indicatorCache.Set(context.Background(), "BTCUSDT", "1h", "RSI_14", candleTime, []entities.TechnicalIndicator{
+		{IndicatorType: "RSI", IndicatorKey: "RSI_14", Value: &rsi, Timestamp: candleTime},
+	})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{ClosePrice: 100, Timestamp: candleTime}, nil)
+
+	indicatorService := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+	indicatorService.SetResultCache(indicatorCache)
+	engine := services.NewAlertEngine(&testutils.MockAlertRepository{}, priceRepo, indicatorRepo, &testutils.MockNotificationRepository{}, indicatorService, logger)
+
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "rsi", ConditionType: "below", TargetValue: 30, Timeframe: "1h", Enabled: true}
+	result, err := engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	assert.Equal(t, rsi, result.CurrentValue)
+	assert.False(t, result.ShouldTrigger)
+	indicatorRepo.AssertNotCalled(t, "GetLatestByKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkOnlyIndicatorRepository fails any indicator stored one by one, so a test
+// can tell the worker only wrote through BulkInsert
+type bulkOnlyIndicatorRepository struct {
+	*testutils.MemoryTechnicalIndicatorRepository
+	t     *testing.T
+	mutex sync.Mutex
+	bulks int
+}
+
+func (r *bulkOnlyIndicatorRepository) Create(ctx context.Context, indicator *entities.TechnicalIndicator) error {
+	r.t.Errorf("indicator %s of %s stored outside a batch", indicator.IndicatorKey, indicator.Symbol)
+	return r.MemoryTechnicalIndicatorRepository.Create(ctx, indicator)
+}
+
+func (r *bulkOnlyIndicatorRepository) BulkInsert(ctx context.Context, indicators []entities.TechnicalIndicator) error {
+	r.mutex.Lock()
+	r.bulks++
+	r.mutex.Unlock()
+	return r.MemoryTechnicalIndicatorRepository.BulkInsert(ctx, indicators)
+}
+
+func TestIndicatorCalculationWorker_CalculatesActiveSymbolsInBatches(t *testing.T) {
+	ctx := context.Background()
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cryptoRepo := testutils.NewMemoryCryptoCurrencyRepository()
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+	for _, crypto := range []entities.CryptoCurrency{
+		{Symbol: "BTCUSDT", Name: "Bitcoin", Active: true},
+		{Symbol: "ETHUSDT", Name: "Ethereum", Active: true},
+		{Symbol: "SOLUSDT", Name: "Solana", Active: true},
+		{Symbol: "DOGEUSDT", Name: "Dogecoin", Active: false},
+	} {
+		crypto := crypto
+		require.NoError(t, cryptoRepo.Create(ctx, &crypto))
+
+		history := trendingHistory(latest, 120, 0.5)
+		for i := range history {
+			history[i].Symbol = crypto.Symbol
+			history[i].Timeframe = "1h"
+		}
+		require.NoError(t, priceRepo.BulkInsert(ctx, history))
+	}
+
+	indicatorRepo := &bulkOnlyIndicatorRepository{MemoryTechnicalIndicatorRepository: testutils.NewMemoryTechnicalIndicatorRepository(), t: t}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	indicatorService := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+	indicatorService.SetResultCache(newIndicatorCache(t))
+
+	var observed sync.Map
+	worker := services.NewIndicatorCalculationWorker(cryptoRepo, indicatorService, logger)
+	worker.SetTimeframes([]string{"1h"})
+	worker.SetBatchSize(2)
+	worker.SetConcurrency(2)
+	worker.SetRecorder(services.IndicatorCalculationRecorderFunc(func(scope string, duration time.Duration) {
+		count, _ := observed.LoadOrStore(scope, new(int))
+		*count.(*int)++
+	}))
+
+	run, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, run.Markets)
+	assert.Zero(t, run.Failed)
+	assert.Positive(t, run.Stored)
+	assert.Equal(t, 2, indicatorRepo.bulks, "three markets in batches of two")
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		rsi, err := indicatorRepo.GetLatestByKey(ctx, symbol, "1h", entities.IndicatorKey("RSI", 14))
+		require.NoError(t, err, symbol)
+		assert.NotNil(t, rsi.Value, symbol)
+	}
+	keys, err := indicatorRepo.GetIndicatorKeys(ctx, "DOGEUSDT", "1h")
+	require.NoError(t, err)
+	assert.Empty(t, keys, "inactive symbols are left out")
+
+	batches, _ := observed.Load(services.IndicatorCalculationScopeBatch)
+	runs, _ := observed.Load(services.IndicatorCalculationScopeRun)
+	assert.Equal(t, 2, *batches.(*int))
+	assert.Equal(t, 1, *runs.(*int))
+
+	// Nothing new closed, so the next run stores nothing
+	run, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, run.Markets)
+	assert.Zero(t, run.Stored)
+	assert.Zero(t, run.Failed)
+}
@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJobScheduler() (*services.JobScheduler, *testutils.FakeClock) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	clock := testutils.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	scheduler := services.NewJobScheduler(logger)
+	scheduler.SetClock(clock)
+	return scheduler, clock
+}
+
+// waitForRuns waits until the job finished the given number of runs
+func waitForRuns(t *testing.T, scheduler *services.JobScheduler, name string, runs int) *services.JobStatus {
+	var status *services.JobStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = scheduler.Status(name)
+		require.NoError(t, err)
+		return status.Runs >= runs && !status.Running
+	}, 2*time.Second, 5*time.Millisecond)
+	return status
+}
+
+func TestJobScheduler_RecordsScheduledRuns(t *testing.T) {
+	scheduler, clock := newTestJobScheduler()
+	ran := make(chan struct{}, 1)
+	scheduler.Register(services.JobPriceRetention, time.Hour, func(ctx context.Context) error {
+		clock.Advance(3 * time.Second)
+		ran <- struct{}{}
+		return errors.New("database unavailable")
+	})
+	scheduler.Register(services.JobExchangeSync, 0, func(ctx context.Context) error {
+		t.Error("jobs without an interval only run when triggered")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx)
+	defer scheduler.Stop()
+	<-ran
+
+	status := waitForRuns(t, scheduler, services.JobPriceRetention, 1)
+	assert.Equal(t, services.JobTriggerScheduled, status.LastTrigger)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), *status.LastRunAt)
+	assert.Equal(t, 3*time.Second, status.LastDuration)
+	assert.Equal(t, "database unavailable", status.LastError)
+	assert.Equal(t, time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC), *status.NextRunAt)
+
+	statuses := scheduler.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, services.JobExchangeSync, statuses[1].Name)
+	assert.Zero(t, statuses[1].Runs)
+	assert.Nil(t, statuses[1].NextRunAt)
+}
+
+func TestJobScheduler_SkipsIntervalsTakenByAnotherInstance(t *testing.T) {
+	scheduler, _ := newTestJobScheduler()
+	locks := services.NewMemoryThrottleStore()
+	scheduler.SetThrottleStore(locks)
+
+	// Another replica already runs the job this interval
+	acquired, err := locks.Acquire(context.Background(), "job_lock:"+services.JobIndicatorCalculation, time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	scheduler.Register(services.JobIndicatorCalculation, time.Hour, func(ctx context.Context) error {
+		t.Error("the interval belongs to another instance")
+		return nil
+	})
+	scheduler.Start(context.Background())
+
+	require.Eventually(t, func() bool {
+		status, err := scheduler.Status(services.JobIndicatorCalculation)
+		require.NoError(t, err)
+		return status.NextRunAt != nil
+	}, 2*time.Second, 5*time.Millisecond)
+	scheduler.Stop()
+
+	status, err := scheduler.Status(services.JobIndicatorCalculation)
+	require.NoError(t, err)
+	assert.Zero(t, status.Runs)
+}
+
+func TestJobScheduler_TriggersRunsOnDemand(t *testing.T) {
+	scheduler, _ := newTestJobScheduler()
+	release := make(chan struct{})
+	scheduler.Register(services.JobCacheWarmup, 0, func(ctx context.Context) error {
+		<-release
+		return ctx.Err()
+	})
+
+	// The run outlives the request triggering it
+	ctx, cancel := context.WithCancel(context.Background())
+	status, err := scheduler.Trigger(ctx, services.JobCacheWarmup)
+	cancel()
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+	assert.Equal(t, services.JobTriggerManual, status.LastTrigger)
+
+	_, err = scheduler.Trigger(context.Background(), services.JobCacheWarmup)
+	assert.ErrorIs(t, err, services.ErrJobRunning)
+
+	close(release)
+	status = waitForRuns(t, scheduler, services.JobCacheWarmup, 1)
+	assert.Empty(t, status.LastError)
+
+	_, err = scheduler.Trigger(context.Background(), "vacuum")
+	assert.ErrorIs(t, err, services.ErrJobNotFound)
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// latencies records the latencies reported per stage
+type latencies struct {
+	mu      sync.Mutex
+	byStage map[string][]time.Duration
+}
+
+func newLatencies() *latencies {
+	return &latencies{byStage: make(map[string][]time.Duration)}
+}
+
+func (l *latencies) ObserveLatency(stage string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.byStage[stage] = append(l.byStage[stage], latency)
+}
+
+func TestAlertEngine_ReportsPipelineLatencies(t *testing.T) {
+	eventTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	alertRepo := &testutils.MockAlertRepository{}
+	notificationRepo := &testutils.MockNotificationRepository{}
+	webSocket := &testutils.MockAlertWebSocketService{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1m").Return(&entities.PriceHistory{
+		Symbol:     "BTCUSDT",
+		Timeframe:  "1m",
+		ClosePrice: 55000,
+		Timestamp:  eventTime,
+		EventTime:  &eventTime,
+		CreatedAt:  eventTime.Add(time.Second),
+	}, nil)
+	alertRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Alert")).Return(nil)
+	alertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	notificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+	webSocket.On("IsUserConnected", mock.Anything, mock.Anything).Return(true)
+	webSocket.On("BroadcastAlertTriggered", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	webSocket.On("BroadcastNotificationUpdate", mock.Anything, mock.Anything).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	engine := services.NewAlertEngine(alertRepo, priceRepo, &testutils.MockTechnicalIndicatorRepository{}, notificationRepo, nil, logger)
+	engine.SetWebSocketService(webSocket)
+	engine.SetClock(testutils.NewFakeClock(eventTime.Add(3 * time.Second)))
+	recorded := newLatencies()
+	engine.SetLatencyRecorder(recorded)
+
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 50000, Timeframe: "1m", Enabled: true}
+	result, err := engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	require.True(t, result.ShouldTrigger)
+
+	require.NotNil(t, result.Trace)
+	assert.Equal(t, eventTime, result.Trace.EventTime)
+	assert.Equal(t, []time.Duration{2 * time.Second}, recorded.byStage[services.LatencyStageEvaluation])
+	assert.Equal(t, []time.Duration{0}, recorded.byStage[services.LatencyStageBroadcast])
+	assert.Equal(t, []time.Duration{3 * time.Second}, recorded.byStage[services.LatencyStageEndToEnd])
+}
+
+func TestAlertEngine_SkipsLatenciesWithoutEventTime(t *testing.T) {
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{ClosePrice: 45000, Timestamp: time.Now()}, nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	engine := services.NewAlertEngine(&testutils.MockAlertRepository{}, priceRepo, &testutils.MockTechnicalIndicatorRepository{}, &testutils.MockNotificationRepository{}, nil, logger)
+
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 50000, Timeframe: "1h", Enabled: true}
+	result, err := engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	assert.Nil(t, result.Trace)
+}
+
+func TestNotificationService_ReportsDeliveryLatency(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	userRepo := new(testutils.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&entities.User{ID: userID}, nil)
+	notificationRepo := new(testutils.MockNotificationRepository)
+	notificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(notificationRepo, userRepo, services.NewRedisClientWrapper(client), logger)
+	service.SetClock(testutils.NewFakeClock(now))
+	service.SetChannelSender(services.ChannelEmail, &countingSender{})
+	recorded := newLatencies()
+	service.SetLatencyRecorder(recorded)
+
+	trace := &services.AlertTrace{EventTime: now.Add(-3 * time.Second), IngestedAt: now.Add(-2 * time.Second), EvaluatedAt: now.Add(-2 * time.Second)}
+	alert := &entities.Alert{ID: uuid.New(), UserID: userID, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 50000, Timeframe: "1m"}
+	require.NoError(t, service.QueueAlertNotification(services.WithAlertTrace(ctx, trace), alert, 51000, []services.NotificationChannel{services.ChannelEmail}))
+
+	// The trace travels with the queued notification
+	queued, err := client.ZRange(ctx, "notification_queue", 0, -1).Result()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	var notification services.QueuedNotification
+	require.NoError(t, json.Unmarshal([]byte(queued[0]), &notification))
+	require.NotNil(t, notification.Trace)
+	assert.True(t, trace.EventTime.Equal(notification.Trace.EventTime))
+
+	assert.Equal(t, 1, service.ProcessBatch(ctx))
+	assert.Equal(t, []time.Duration{2 * time.Second}, recorded.byStage[services.LatencyStageNotification])
+}
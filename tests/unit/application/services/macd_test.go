@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTechnicalIndicatorService_CalculatesMACD(t *testing.T) {
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	// Closes fall by 1 per candle, so each EMA lags the price by (period-1)/2
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 8).Return(indicatorHistory(latest, 8), nil)
+	indicatorRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.TechnicalIndicator")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+
+	require.NoError(t, service.CalculateAndStoreMACD(context.Background(), "BTCUSDT", "1h", 2, 3, 2))
+
+	indicatorRepo.AssertNumberOfCalls(t, "Create", 2)
+	macd := indicatorRepo.Calls[0].Arguments.Get(1).(*entities.TechnicalIndicator)
+	assert.Equal(t, "MACD_2_3_2", macd.IndicatorKey)
+	assert.InDelta(t, -0.5, *macd.Value, 1e-9)
+	assert.InDelta(t, -0.5, macd.Metadata["signal"], 1e-9)
+	assert.InDelta(t, 0.0, macd.Metadata["histogram"], 1e-9)
+
+	signal := indicatorRepo.Calls[1].Arguments.Get(1).(*entities.TechnicalIndicator)
+	assert.Equal(t, "MACD_Signal_2_3_2", signal.IndicatorKey)
+	assert.InDelta(t, -0.5, *signal.Value, 1e-9)
+}
+
+func TestTechnicalIndicatorService_MACDRejectsInvalidPeriods(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(&testutils.MockPriceHistoryRepository{}, &testutils.MockTechnicalIndicatorRepository{}, logger)
+
+	err := service.CalculateAndStoreMACD(context.Background(), "BTCUSDT", "1h", 26, 12, 9)
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestAlertEngine_MACDCrossUp(t *testing.T) {
+	candleTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	alertRepo := &testutils.MockAlertRepository{}
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	notificationRepo := &testutils.MockNotificationRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1h").Return(&entities.PriceHistory{ClosePrice: 110, Timestamp: candleTime}, nil)
+
+	macdIndicator := func(macd, signal float64) *entities.TechnicalIndicator {
+		return &entities.TechnicalIndicator{
+			IndicatorType: "MACD",
+			IndicatorKey:  "MACD_12_26_9",
+			Value:         &macd,
+			Metadata:      map[string]interface{}{"signal": signal},
+			Timestamp:     candleTime,
+		}
+	}
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "MACD_12_26_9").Return(macdIndicator(-1, 0.5), nil).Once()
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "MACD_12_26_9").Return(macdIndicator(1, 0.5), nil).Once()
+	alertRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Alert")).Return(nil)
+	alertRepo.On("GetDependents", mock.Anything, mock.Anything).Return(nil, nil)
+	notificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	engine := services.NewAlertEngine(alertRepo, priceRepo, indicatorRepo, notificationRepo, nil, logger)
+
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "macd_cross", ConditionType: "up", TargetValue: 1, Timeframe: "1h", Enabled: true}
+
+	// The first evaluation only records the MACD and signal
+	result, err := engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	assert.False(t, result.ShouldTrigger)
+	assert.InDelta(t, -1.5, result.CurrentValue, 1e-9)
+
+	result, err = engine.EvaluateAlert(context.Background(), alert)
+	require.NoError(t, err)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, "MACD crossed above its signal line for BTCUSDT", result.Message)
+	assert.InDelta(t, 0.5, result.Context["histogram"], 1e-9)
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is a named provider with a configurable health check
+type fakeProvider struct {
+	countingSender
+	name      string
+	healthErr error
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) HealthCheck(ctx context.Context) error {
+	return p.healthErr
+}
+
+func newTestRegistry() *services.ProviderRegistry {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return services.NewProviderRegistry(logger)
+}
+
+func TestProviderRegistry_DefaultsToLogProvider(t *testing.T) {
+	registry := newTestRegistry()
+
+	statuses := registry.Status(context.Background())
+	require.Len(t, statuses, 4)
+	var channels []services.NotificationChannel
+	for _, status := range statuses {
+		channels = append(channels, status.Channel)
+		assert.Equal(t, services.LogProviderName, status.Provider)
+		assert.True(t, status.Healthy)
+		assert.Equal(t, []string{services.LogProviderName}, status.Available)
+	}
+	assert.Equal(t, []services.NotificationChannel{services.ChannelEmail, services.ChannelPush, services.ChannelSMS, services.ChannelTelegram}, channels)
+}
+
+func TestProviderRegistry_ConfigureSwapsProviderAndReportsHealth(t *testing.T) {
+	registry := newTestRegistry()
+
+	var gotSettings map[string]string
+	ses := &fakeProvider{name: "ses", healthErr: errors.New("credentials expired")}
+	registry.Register(services.ChannelEmail, "ses", func(settings map[string]string) (services.NotificationProvider, error) {
+		gotSettings = settings
+		return ses, nil
+	})
+
+	require.NoError(t, registry.Configure(services.ChannelEmail, services.ProviderSelection{
+		Provider: "ses",
+		Settings: map[string]string{"region": "us-east-1"},
+	}))
+	assert.Equal(t, "us-east-1", gotSettings["region"])
+
+	provider, ok := registry.Provider(services.ChannelEmail)
+	require.True(t, ok)
+	assert.Equal(t, "ses", provider.Name())
+
+	statuses := registry.Status(context.Background())
+	require.Len(t, statuses, 4)
+	assert.Equal(t, services.ChannelEmail, statuses[0].Channel)
+	assert.Equal(t, "ses", statuses[0].Provider)
+	assert.Equal(t, []string{"log", "ses"}, statuses[0].Available)
+	assert.False(t, statuses[0].Healthy)
+	assert.Equal(t, "credentials expired", statuses[0].Error)
+}
+
+func TestProviderRegistry_FailedConfigureKeepsCurrentProvider(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(services.ChannelSMS, "twilio", func(settings map[string]string) (services.NotificationProvider, error) {
+		return nil, errors.New("missing account sid")
+	})
+
+	err := registry.Configure(services.ChannelSMS, services.ProviderSelection{Provider: "sns"})
+	assert.ErrorIs(t, err, services.ErrUnknownProvider)
+
+	err = registry.Configure(services.ChannelSMS, services.ProviderSelection{Provider: "twilio"})
+	assert.ErrorContains(t, err, "missing account sid")
+
+	provider, ok := registry.Provider(services.ChannelSMS)
+	require.True(t, ok)
+	assert.Equal(t, services.LogProviderName, provider.Name())
+}
+
+func TestProviderRegistry_ReloadAppliesValidSelections(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Register(services.ChannelPush, "fcm", func(map[string]string) (services.NotificationProvider, error) {
+		return &fakeProvider{name: "fcm"}, nil
+	})
+
+	err := registry.Reload(map[services.NotificationChannel]services.ProviderSelection{
+		services.ChannelPush:  {Provider: "fcm"},
+		services.ChannelEmail: {Provider: "smtp"},
+	})
+	assert.ErrorIs(t, err, services.ErrUnknownProvider)
+
+	push, _ := registry.Provider(services.ChannelPush)
+	assert.Equal(t, "fcm", push.Name())
+	email, _ := registry.Provider(services.ChannelEmail)
+	assert.Equal(t, services.LogProviderName, email.Name())
+}
+
+func TestNotificationService_DeliversThroughSwappedProvider(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+
+	onesignal := &fakeProvider{name: "onesignal"}
+	service.Providers().Register(services.ChannelPush, "onesignal", func(map[string]string) (services.NotificationProvider, error) {
+		return onesignal, nil
+	})
+	require.NoError(t, service.Providers().Configure(services.ChannelPush, services.ProviderSelection{Provider: "onesignal"}))
+
+	id := uuid.New()
+	require.NoError(t, service.QueueNotification(ctx, &services.QueuedNotification{
+		ID:       id,
+		UserID:   uuid.New(),
+		Title:    "Price Alert Triggered",
+		Channels: []services.NotificationChannel{services.ChannelPush},
+	}))
+
+	assert.Equal(t, 1, service.ProcessBatch(ctx))
+	assert.Equal(t, []uuid.UUID{id}, onesignal.sent)
+}
@@ -0,0 +1,321 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var queueStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// flakySender fails its first failures deliveries and records the ones that succeed
+type flakySender struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	sent     []uuid.UUID
+}
+
+func (s *flakySender) Send(ctx context.Context, notification *services.QueuedNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("provider unavailable")
+	}
+	s.sent = append(s.sent, notification.ID)
+	return nil
+}
+
+// queueHarness is a NotificationService on miniredis with a fake clock
+type queueHarness struct {
+	service *services.NotificationService
+	clock   *testutils.FakeClock
+	queue   *testutils.NotificationQueue
+	server  *miniredis.Miniredis
+	client  *redis.Client
+}
+
+func newQueueHarness(t *testing.T) *queueHarness {
+	server, client := testutils.StartMiniredis(t)
+	clock := testutils.NewFakeClock(queueStart)
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), quietLogger())
+	service.SetClock(clock)
+	return &queueHarness{
+		service: service,
+		clock:   clock,
+		queue:   testutils.NewNotificationQueue(t, client, ""),
+		server:  server,
+		client:  client,
+	}
+}
+
+func queueNotification(t *testing.T, service *services.NotificationService, title string, priority services.NotificationPriority, scheduledAt time.Time, channels ...services.NotificationChannel) *services.QueuedNotification {
+	t.Helper()
+	if len(channels) == 0 {
+		channels = []services.NotificationChannel{services.ChannelInApp}
+	}
+	notification := &services.QueuedNotification{
+		UserID:      uuid.New(),
+		Title:       title,
+		Channels:    channels,
+		Priority:    priority,
+		ScheduledAt: scheduledAt,
+	}
+	require.NoError(t, service.QueueNotification(context.Background(), notification))
+	return notification
+}
+
+func queuedTitles(queue *testutils.NotificationQueue) []string {
+	var titles []string
+	for _, notification := range queue.Queued() {
+		titles = append(titles, notification.Title)
+	}
+	return titles
+}
+
+func TestNotificationQueue_QueueNotificationDefaults(t *testing.T) {
+	h := newQueueHarness(t)
+
+	notification := &services.QueuedNotification{UserID: uuid.New(), Channels: []services.NotificationChannel{services.ChannelEmail}}
+	require.NoError(t, h.service.QueueNotification(context.Background(), notification))
+
+	queued := h.queue.Queued()
+	require.Len(t, queued, 1)
+	assert.NotEqual(t, uuid.Nil, queued[0].ID)
+	assert.Equal(t, services.PriorityNormal, queued[0].Priority)
+	assert.Equal(t, 3, queued[0].MaxRetries)
+	assert.True(t, queued[0].ScheduledAt.Equal(queueStart))
+	assert.Equal(t, float64(queueStart.Unix()), h.queue.Score(queued[0]))
+}
+
+func TestNotificationQueue_ProcessBatch_ClaimsDueNotificationsByPriority(t *testing.T) {
+	h := newQueueHarness(t)
+
+	// Twelve normal notifications that became due one minute apart
+	for i := 12; i >= 1; i-- {
+		queueNotification(t, h.service, fmt.Sprintf("normal-%d", i), services.PriorityNormal, queueStart.Add(-time.Duration(i)*time.Minute))
+	}
+	queueNotification(t, h.service, "low", services.PriorityLow, queueStart)
+	queueNotification(t, h.service, "future", services.PriorityNormal, queueStart.Add(time.Minute))
+	// Pulled ahead of their schedule: high by an hour, urgent by a day
+	queueNotification(t, h.service, "high", services.PriorityHigh, queueStart.Add(30*time.Minute))
+	queueNotification(t, h.service, "urgent", services.PriorityUrgent, queueStart.Add(23*time.Hour))
+
+	// A batch claims at most ten notifications, most overdue score first
+	assert.Equal(t, 10, h.service.ProcessBatch(context.Background()))
+	assert.Equal(t, []string{"normal-4", "normal-3", "normal-2", "normal-1", "low", "future"}, queuedTitles(h.queue))
+	assert.Zero(t, h.queue.InFlight())
+
+	assert.Equal(t, 5, h.service.ProcessBatch(context.Background()))
+	assert.Equal(t, []string{"future"}, queuedTitles(h.queue))
+
+	// Not due yet
+	assert.Equal(t, 0, h.service.ProcessBatch(context.Background()))
+	h.clock.Advance(time.Minute)
+	assert.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+	assert.Empty(t, h.queue.Queued())
+	assert.Empty(t, h.queue.DeadLetters())
+}
+
+func TestNotificationQueue_ConcurrentWorkersClaimEachNotificationOnce(t *testing.T) {
+	h := newQueueHarness(t)
+	email := &flakySender{}
+	h.service.SetChannelSender(services.ChannelEmail, email)
+
+	// A second worker sharing the same queue
+	other := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(h.client), quietLogger())
+	other.SetClock(h.clock)
+	other.SetChannelSender(services.ChannelEmail, email)
+
+	const total = 40
+	for i := 0; i < total; i++ {
+		queueNotification(t, h.service, fmt.Sprintf("n-%d", i), services.PriorityNormal, queueStart, services.ChannelEmail)
+	}
+
+	var wg sync.WaitGroup
+	for _, worker := range []*services.NotificationService{h.service, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for worker.ProcessBatch(context.Background()) > 0 {
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every notification was delivered by exactly one worker and acknowledged
+	assert.Len(t, email.sent, total)
+	seen := make(map[uuid.UUID]bool, total)
+	for _, id := range email.sent {
+		assert.False(t, seen[id], "notification %s delivered twice", id)
+		seen[id] = true
+	}
+	assert.Empty(t, h.queue.Queued())
+	assert.Zero(t, h.queue.InFlight())
+	assert.Empty(t, h.queue.DeadLetters())
+}
+
+func TestNotificationQueue_RetryBackoffThenDeadLetter(t *testing.T) {
+	h := newQueueHarness(t)
+	email := &flakySender{failures: 100}
+	h.service.SetChannelSender(services.ChannelEmail, email)
+
+	notification := &services.QueuedNotification{
+		UserID:     uuid.New(),
+		Title:      "Price Alert Triggered",
+		Channels:   []services.NotificationChannel{services.ChannelEmail},
+		MaxRetries: 4,
+	}
+	require.NoError(t, h.service.QueueNotification(context.Background(), notification))
+
+	// Each failure reschedules the notification retries² minutes later
+	for retries, backoff := range []time.Duration{time.Minute, 4 * time.Minute, 9 * time.Minute} {
+		require.Equal(t, 1, h.service.ProcessBatch(context.Background()), "attempt %d", retries+1)
+
+		queued := h.queue.Queued()
+		require.Len(t, queued, 1)
+		assert.Equal(t, notification.ID, queued[0].ID)
+		assert.Equal(t, retries+1, queued[0].Retries)
+		assert.True(t, queued[0].ScheduledAt.Equal(h.clock.Now().Add(backoff)), "attempt %d scheduled at %s", retries+1, queued[0].ScheduledAt)
+		assert.Equal(t, float64(h.clock.Now().Add(backoff).Unix()), h.queue.Score(queued[0]))
+		assert.Empty(t, h.queue.DeadLetters())
+
+		// Not retried before its backoff elapses
+		h.clock.Advance(backoff - time.Second)
+		assert.Equal(t, 0, h.service.ProcessBatch(context.Background()))
+		h.clock.Advance(time.Second)
+	}
+
+	// The fourth failure exhausts MaxRetries
+	require.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+	assert.Empty(t, h.queue.Queued())
+	assert.Zero(t, h.queue.InFlight())
+	assert.Equal(t, 4, email.attempts)
+
+	letters := h.queue.DeadLetters()
+	require.Len(t, letters, 1)
+	assert.Equal(t, "max_retries_exceeded", letters[0].Reason)
+	assert.Contains(t, letters[0].Notification, notification.ID.String())
+}
+
+func TestNotificationQueue_RecoversAfterTransientFailures(t *testing.T) {
+	h := newQueueHarness(t)
+	push := &flakySender{failures: 2}
+	h.service.SetChannelSender(services.ChannelPush, push)
+
+	// The default of three retries allows three attempts
+	notification := queueNotification(t, h.service, "Price Alert Triggered", services.PriorityNormal, queueStart, services.ChannelPush)
+	for _, backoff := range []time.Duration{time.Minute, 4 * time.Minute, 0} {
+		require.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+		h.clock.Advance(backoff)
+	}
+
+	assert.Equal(t, []uuid.UUID{notification.ID}, push.sent)
+	assert.Empty(t, h.queue.Queued())
+	assert.Empty(t, h.queue.DeadLetters())
+	assert.Zero(t, h.queue.InFlight())
+}
+
+func TestNotificationQueue_DeadLettersUnparseableEntries(t *testing.T) {
+	h := newQueueHarness(t)
+	valid := queueNotification(t, h.service, "ok", services.PriorityNormal, queueStart)
+	require.NoError(t, h.client.ZAdd(context.Background(), h.queue.QueueKey, redis.Z{Score: 0, Member: `{"id":`}).Err())
+
+	assert.Equal(t, 2, h.service.ProcessBatch(context.Background()))
+
+	letters := h.queue.DeadLetters()
+	require.Len(t, letters, 1)
+	assert.Equal(t, "parse_error", letters[0].Reason)
+	assert.Equal(t, `{"id":`, letters[0].Notification)
+	assert.NotContains(t, letters[0].Notification, valid.ID.String())
+	assert.Empty(t, h.queue.Queued())
+	assert.Zero(t, h.queue.InFlight())
+}
+
+func TestNotificationQueue_RedisUnavailable(t *testing.T) {
+	h := newQueueHarness(t)
+	queueNotification(t, h.service, "queued", services.PriorityNormal, queueStart)
+
+	h.server.SetError("LOADING Redis is loading the dataset in memory")
+	assert.Equal(t, 0, h.service.ProcessBatch(context.Background()))
+	assert.Error(t, h.service.QueueNotification(context.Background(), &services.QueuedNotification{UserID: uuid.New()}))
+
+	// Nothing was lost or claimed while Redis was failing
+	h.server.SetError("")
+	assert.Equal(t, []string{"queued"}, queuedTitles(h.queue))
+	assert.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+	assert.Empty(t, h.queue.Queued())
+	assert.Zero(t, h.queue.InFlight())
+}
+
+func TestNotificationQueue_PersistsDeliveryResultsForChannelWindows(t *testing.T) {
+	h := newQueueHarness(t)
+	deliveries := testutils.NewMemoryNotificationDeliveryRepository()
+	h.service.SetDeliveryRepository(deliveries)
+	email := &flakySender{failures: 1}
+	h.service.SetChannelSender(services.ChannelEmail, email)
+
+	type observed struct {
+		channel services.NotificationChannel
+		status  string
+		attempt int
+		latency time.Duration
+	}
+	var observations []observed
+	h.service.SetDeliveryRecorder(services.DeliveryRecorderFunc(func(channel services.NotificationChannel, status string, attempt int, latency time.Duration) {
+		observations = append(observations, observed{channel, status, attempt, latency})
+	}))
+
+	// The first email attempt fails and is retried after a minute
+	queueNotification(t, h.service, "Price Alert Triggered", services.PriorityNormal, queueStart, services.ChannelEmail)
+	require.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+	h.clock.Advance(time.Minute)
+	require.Equal(t, 1, h.service.ProcessBatch(context.Background()))
+
+	assert.Equal(t, []observed{
+		{services.ChannelEmail, entities.DeliveryStatusFailed, 1, 0},
+		{services.ChannelEmail, entities.DeliveryStatusSent, 2, time.Minute},
+	}, observations)
+
+	stored := deliveries.All()
+	require.Len(t, stored, 2)
+	assert.Contains(t, stored[0].Error, "provider unavailable")
+	assert.Equal(t, int64(60000), stored[1].LatencyMs)
+
+	// Over an hour later both attempts only count in the day window
+	h.clock.Advance(time.Hour + time.Second)
+	stats, err := h.service.GetNotificationStats(context.Background())
+	require.NoError(t, err)
+	windows := stats["delivery_windows"].(map[string][]entities.ChannelDeliveryWindow)
+	assert.Empty(t, windows["15m"])
+	assert.Empty(t, windows["1h"])
+	require.Len(t, windows["24h"], 1)
+	assert.Equal(t, entities.ChannelDeliveryWindow{
+		Channel:      "email",
+		Sent:         1,
+		Failed:       1,
+		Retries:      1,
+		AvgLatencyMs: 60000,
+		SuccessRate:  0.5,
+	}, windows["24h"][0])
+
+	// Results older than the retention are cleaned up with the DLQ
+	require.NoError(t, h.service.CleanupOldNotifications(context.Background(), 30*time.Minute))
+	assert.Empty(t, deliveries.All())
+}
@@ -3,6 +3,9 @@ package services_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -210,6 +214,30 @@ func (suite *NotificationServiceTestSuite) TestStartStopProcessing() {
 	assert.True(suite.T(), true) // Placeholder assertion
 }
 
+func (suite *NotificationServiceTestSuite) TestGetNotificationPage() {
+	userID := uuid.New()
+	unread := []entities.Notification{{ID: uuid.New(), UserID: userID, Title: "Alert Triggered"}}
+
+	// Oversized pages are capped and negative offsets reset
+	suite.mockNotificationRepo.On("GetUnread", suite.ctx, userID, services.MaxNotificationPageSize, 0).Return(unread, nil)
+	suite.mockNotificationRepo.On("CountUnreadByUserID", suite.ctx, userID).Return(int64(7), nil)
+
+	page, err := suite.notificationService.GetNotificationPage(suite.ctx, userID, 500, -10, true)
+	suite.Require().NoError(err)
+	suite.Equal(unread, page.Notifications)
+	suite.Equal(services.MaxNotificationPageSize, page.Limit)
+	suite.Equal(0, page.Offset)
+	suite.Equal(1, page.Count)
+	suite.True(page.UnreadOnly)
+	suite.Equal(int64(7), page.UnreadCount)
+
+	suite.mockNotificationRepo.On("GetByUserID", suite.ctx, userID, services.DefaultNotificationPageSize, 50).
+		Return([]entities.Notification{}, fmt.Errorf("db down"))
+
+	_, err = suite.notificationService.GetNotificationPage(suite.ctx, userID, 0, 50, false)
+	suite.Error(err)
+}
+
 func TestNotificationServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(NotificationServiceTestSuite))
 }
@@ -234,6 +262,7 @@ func TestNotificationService_GetNotificationStats(t *testing.T) {
 		// Mock Redis operations for queue stats
 		mockRedisClient.On("ZCard", ctx, "notification_queue").Return(&redis.IntCmd{})
 		mockRedisClient.On("ZCard", ctx, "notification_dlq").Return(&redis.IntCmd{})
+		mockRedisClient.On("ZCard", ctx, "notification_inflight").Return(&redis.IntCmd{})
 
 		stats, err := notificationService.GetNotificationStats(ctx)
 
@@ -305,3 +334,276 @@ func TestNotificationService_NotificationPriorityTypes(t *testing.T) {
 		assert.Equal(t, expectedStrings[i], string(priority))
 	}
 }
+
+func TestNotificationService_AlertNotificationPriority(t *testing.T) {
+	tests := []struct {
+		priority string
+		expected services.NotificationPriority
+	}{
+		{"", services.PriorityHigh},
+		{"low", services.PriorityLow},
+		{"normal", services.PriorityNormal},
+		{"high", services.PriorityHigh},
+		{"urgent", services.PriorityUrgent},
+		{"bogus", services.PriorityHigh},
+	}
+
+	for _, tt := range tests {
+		alert := &entities.Alert{Priority: tt.priority}
+		assert.Equal(t, tt.expected, services.AlertNotificationPriority(alert), "priority %q", tt.priority)
+	}
+}
+
+func TestNotificationService_QueueAlertNotificationUsesAlertPriority(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	userRepo := new(testutils.MockUserRepository)
+	userRepo.On("GetByID", ctx, userID).Return(&entities.User{ID: userID}, nil)
+	notificationRepo := new(testutils.MockNotificationRepository)
+	notificationRepo.On("Create", ctx, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(notificationRepo, userRepo, services.NewRedisClientWrapper(client), logger)
+	service.SetClock(testutils.NewFakeClock(now))
+
+	channels := []services.NotificationChannel{services.ChannelEmail}
+	for _, priority := range []string{"low", "urgent"} {
+		alert := &entities.Alert{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Symbol:        "BTCUSDT",
+			AlertType:     "price",
+			ConditionType: "above",
+			TargetValue:   50000,
+			Timeframe:     "1h",
+			Priority:      priority,
+		}
+		require.NoError(t, service.QueueAlertNotification(ctx, alert, 51000, channels))
+	}
+
+	// Urgent alerts are scored ahead of everything else in the queue
+	queued, err := client.ZRangeWithScores(ctx, "notification_queue", 0, -1).Result()
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+
+	var first, second services.QueuedNotification
+	require.NoError(t, json.Unmarshal([]byte(queued[0].Member.(string)), &first))
+	require.NoError(t, json.Unmarshal([]byte(queued[1].Member.(string)), &second))
+	assert.Equal(t, services.PriorityUrgent, first.Priority)
+	assert.Equal(t, float64(now.Unix()-86400), queued[0].Score)
+	assert.Equal(t, services.PriorityLow, second.Priority)
+	assert.Equal(t, float64(now.Unix()), queued[1].Score)
+}
+
+func TestNotificationService_ProcessBatch(t *testing.T) {
+	for _, pipelining := range []bool{true, false} {
+		t.Run(fmt.Sprintf("pipelining=%t", pipelining), func(t *testing.T) {
+			ctx := context.Background()
+			_, client := testutils.StartMiniredis(t)
+
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+				new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+			service.SetClock(testutils.NewFakeClock(now))
+			// A tiny pipeline size forces the batch to be split across several pipelines
+			service.SetPipelining(pipelining, 2)
+
+			delivered := &services.QueuedNotification{UserID: uuid.New(), Title: "ok",
+				Channels: []services.NotificationChannel{services.ChannelInApp}}
+			retried := &services.QueuedNotification{UserID: uuid.New(), Title: "retry",
+				Channels: []services.NotificationChannel{"fax"}}
+			exhausted := &services.QueuedNotification{UserID: uuid.New(), Title: "dead",
+				Channels: []services.NotificationChannel{"fax"}, Retries: 2}
+			for _, n := range []*services.QueuedNotification{delivered, retried, exhausted} {
+				require.NoError(t, service.QueueNotification(ctx, n))
+			}
+			require.NoError(t, client.ZAdd(ctx, "notification_queue", redis.Z{Score: 0, Member: "not json"}).Err())
+
+			assert.Equal(t, 4, service.ProcessBatch(ctx))
+
+			// Only the retried notification is left, pushed back by its backoff
+			queued, err := client.ZRangeWithScores(ctx, "notification_queue", 0, -1).Result()
+			require.NoError(t, err)
+			require.Len(t, queued, 1)
+			var rescheduled services.QueuedNotification
+			require.NoError(t, json.Unmarshal([]byte(queued[0].Member.(string)), &rescheduled))
+			assert.Equal(t, retried.ID, rescheduled.ID)
+			assert.Equal(t, 1, rescheduled.Retries)
+			assert.Equal(t, now.Add(time.Minute).Unix(), rescheduled.ScheduledAt.Unix())
+
+			dlqSize, err := client.ZCard(ctx, "notification_dlq").Result()
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), dlqSize)
+
+			// Every claimed notification was acknowledged
+			inFlight, err := client.ZCard(ctx, "notification_inflight").Result()
+			require.NoError(t, err)
+			assert.Zero(t, inFlight)
+
+			assert.Equal(t, 0, service.ProcessBatch(ctx))
+		})
+	}
+}
+
+func TestNotificationService_QueuePartitionsAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	newRegion := func(region string) *services.NotificationService {
+		service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+			new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+		service.SetClock(testutils.NewFakeClock(now))
+		service.SetQueuePartition(region)
+		return service
+	}
+	usEast := newRegion("us-east-1")
+	euWest := newRegion("eu-west-1")
+
+	require.NoError(t, usEast.QueueNotification(ctx, &services.QueuedNotification{UserID: uuid.New(),
+		Channels: []services.NotificationChannel{services.ChannelInApp}}))
+
+	queued, err := client.ZCard(ctx, "notification_queue:{us-east-1}").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), queued)
+
+	// Each region only delivers its own partition
+	assert.Equal(t, 0, euWest.ProcessBatch(ctx))
+	assert.Equal(t, 1, usEast.ProcessBatch(ctx))
+}
+
+func TestNotificationService_ReclaimExpired(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := testutils.NewFakeClock(now)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+	service.SetClock(clock)
+	service.SetVisibilityTimeout(time.Minute)
+
+	// Simulate a worker that claimed a notification and crashed before acknowledging it
+	notification := services.QueuedNotification{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		Title:       "Price Alert Triggered",
+		Channels:    []services.NotificationChannel{services.ChannelInApp},
+		Priority:    services.PriorityHigh,
+		ScheduledAt: now.Add(-time.Minute),
+	}
+	member, err := json.Marshal(notification)
+	require.NoError(t, err)
+	require.NoError(t, client.ZAdd(ctx, "notification_inflight",
+		redis.Z{Score: float64(now.Add(30 * time.Second).Unix()), Member: string(member)}).Err())
+
+	reclaimed, err := service.ReclaimExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, reclaimed, "still within its visibility timeout")
+
+	clock.Advance(time.Minute)
+	reclaimed, err = service.ReclaimExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reclaimed)
+
+	// Back in the queue with its original priority score, no longer in flight
+	score, err := client.ZScore(ctx, "notification_queue", string(member)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, float64(now.Add(-time.Minute).Unix()-3600), score)
+	inFlight, err := client.ZCard(ctx, "notification_inflight").Result()
+	require.NoError(t, err)
+	assert.Zero(t, inFlight)
+
+	assert.Equal(t, 1, service.ProcessBatch(ctx))
+	queued, err := client.ZCard(ctx, "notification_queue").Result()
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+}
+
+// blockingSender simulates a stalled provider: it returns only when released or cancelled
+type blockingSender struct {
+	release chan struct{}
+}
+
+func (s *blockingSender) Send(ctx context.Context, notification *services.QueuedNotification) error {
+	select {
+	case <-s.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// countingSender records every notification it delivers
+type countingSender struct {
+	mu   sync.Mutex
+	sent []uuid.UUID
+}
+
+func (s *countingSender) Send(ctx context.Context, notification *services.QueuedNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, notification.ID)
+	return nil
+}
+
+func TestNotificationService_SlowChannelDoesNotBlockOthers(t *testing.T) {
+	ctx := context.Background()
+	_, client := testutils.StartMiniredis(t)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewNotificationService(new(testutils.MockNotificationRepository),
+		new(testutils.MockUserRepository), services.NewRedisClientWrapper(client), logger)
+	service.SetClock(testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+	service.SetDeliveryConfig(services.DeliveryConfig{
+		Workers:            4,
+		ChannelConcurrency: 2,
+		ChannelTimeouts: map[services.NotificationChannel]time.Duration{
+			services.ChannelEmail: 50 * time.Millisecond,
+			services.ChannelPush:  time.Second,
+		},
+	})
+
+	email := &blockingSender{release: make(chan struct{})}
+	t.Cleanup(func() { close(email.release) })
+	push := &countingSender{}
+	service.SetChannelSender(services.ChannelEmail, email)
+	service.SetChannelSender(services.ChannelPush, push)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, service.QueueNotification(ctx, &services.QueuedNotification{
+			UserID:   uuid.New(),
+			Title:    "Price Alert Triggered",
+			Channels: []services.NotificationChannel{services.ChannelEmail, services.ChannelPush},
+		}))
+	}
+
+	start := time.Now()
+	assert.Equal(t, 4, service.ProcessBatch(ctx))
+
+	// Email timeouts overlap instead of adding up, and push still went out for every notification
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Len(t, push.sent, 4)
+
+	// The stalled email deliveries are retried later
+	queued, err := client.ZRange(ctx, "notification_queue", 0, -1).Result()
+	require.NoError(t, err)
+	require.Len(t, queued, 4)
+	for _, member := range queued {
+		var notification services.QueuedNotification
+		require.NoError(t, json.Unmarshal([]byte(member), &notification))
+		assert.Equal(t, 1, notification.Retries)
+	}
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDepthStream hands the depth messages sent on its channels to the subscribers
+type fakeDepthStream struct {
+	channels map[string]chan []byte
+	streams  []string
+}
+
+func (f *fakeDepthStream) StartWebSocket(ctx context.Context, streams []string) error {
+	f.streams = streams
+	return nil
+}
+
+func (f *fakeDepthStream) StopWebSocket() {}
+
+func (f *fakeDepthStream) SubscribeToStream(stream string) <-chan []byte {
+	f.channels[stream] = make(chan []byte, 10)
+	return f.channels[stream]
+}
+
+func (f *fakeDepthStream) UnsubscribeFromStream(stream string) {
+	close(f.channels[stream])
+}
+
+func (f *fakeDepthStream) GetDepthWebSocketStream(symbol string, levels int) string {
+	return fmt.Sprintf("%s@depth%d@100ms", strings.ToLower(symbol), levels)
+}
+
+// recordingBroadcaster records the rooms sent a message
+type recordingBroadcaster struct {
+	mu    sync.Mutex
+	rooms []string
+}
+
+func (r *recordingBroadcaster) BroadcastLocal(room, messageType string, data interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rooms = append(r.rooms, room+" "+messageType)
+}
+
+func TestOrderBookService_KeepsBooksFromTheDepthStream(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	clock := testutils.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	source := &fakeDepthStream{channels: make(map[string]chan []byte)}
+	broadcaster := &recordingBroadcaster{}
+
+	service := services.NewOrderBookService(source, logger)
+	service.SetClock(clock)
+	service.SetBroadcaster(broadcaster)
+	assert.Error(t, service.Start(context.Background(), []string{"btcusdt"}, 15))
+	require.NoError(t, service.Start(context.Background(), []string{"btcusdt"}, 5))
+	assert.Equal(t, []string{"btcusdt@depth5@100ms"}, source.streams)
+
+	_, err := service.GetOrderBook("BTCUSDT")
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+
+	stream := source.channels["btcusdt@depth5@100ms"]
+	stream <- []byte(`{"stream":"btcusdt@depth5@100ms","data":{"lastUpdateId":7,"bids":[["41999.5","2"],["41999","0"],["41998","1"]],"asks":[["42000.5","3"],["42001","1"]]}}`)
+	// An older update arriving late doesn't replace the book
+	stream <- []byte(`{"stream":"btcusdt@depth5@100ms","data":{"lastUpdateId":6,"bids":[["1","1"]],"asks":[["2","1"]]}}`)
+	service.Stop()
+
+	book, err := service.GetOrderBook("btcusdt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), book.LastUpdateID)
+	assert.Equal(t, []services.OrderBookLevel{{Price: 41999.5, Quantity: 2}, {Price: 41998, Quantity: 1}}, book.Bids)
+	assert.Equal(t, 41999.5, book.BestBid)
+	assert.Equal(t, 42000.5, book.BestAsk)
+	assert.Equal(t, 1.0, book.Spread)
+	assert.InDelta(t, 1/42000.0*100, book.SpreadPercent, 1e-9)
+
+	// The room is sent the book once a second however often it updates
+	require.NoError(t, service.ApplyDepth(&external.DepthWebSocketData{Symbol: "BTCUSDT", LastUpdateID: 8}))
+	clock.Advance(time.Second)
+	require.NoError(t, service.ApplyDepth(&external.DepthWebSocketData{Symbol: "BTCUSDT", LastUpdateID: 9}))
+	assert.Equal(t, []string{"orderbook:BTCUSDT order_book_update", "orderbook:BTCUSDT order_book_update"}, broadcaster.rooms)
+
+	clock.Advance(time.Minute)
+	_, err = service.GetOrderBook("BTCUSDT")
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+	_, err = service.GetOrderBook("ETHUSDT")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExchange serves one 1m kline per symbol, opened at openedAt
+type fakeExchange struct {
+	openedAt time.Time
+	prices   map[string][2]float64 // Close price and volume
+	err      error
+}
+
+func (e *fakeExchange) GetKlines(ctx context.Context, symbol, interval string, limit int, startTime, endTime *int64) ([][]interface{}, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	price, ok := e.prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	closePrice, volume := fmt.Sprint(price[0]), fmt.Sprint(price[1])
+	return [][]interface{}{{float64(e.openedAt.UnixMilli()), closePrice, closePrice, closePrice, closePrice, volume}}, nil
+}
+
+type aggregationFixture struct {
+	service *services.PriceAggregationService
+	repos   *testutils.MemoryRepositories
+	clock   *testutils.FakeClock
+}
+
+func newAggregationFixture(t *testing.T, exchanges map[string]*fakeExchange) *aggregationFixture {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	clock := testutils.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC))
+	repos := testutils.NewMemoryRepositories()
+
+	service := services.NewPriceAggregationService(repos.ExchangePrices, repos.PriceHistory, logger)
+	service.SetClock(clock)
+	service.SetSymbols([]string{"btcusdt"})
+	for name, exchange := range exchanges {
+		service.AddExchange(name, exchange)
+	}
+	return &aggregationFixture{service: service, repos: repos, clock: clock}
+}
+
+func TestPriceAggregationService_StoresMedianOfFreshExchangePrices(t *testing.T) {
+	openedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := newAggregationFixture(t, map[string]*fakeExchange{
+		"binance":  {openedAt: openedAt, prices: map[string][2]float64{"BTCUSDT": {42000, 10}}},
+		"coinbase": {openedAt: openedAt, prices: map[string][2]float64{"BTCUSDT": {42100, 2}}},
+		"kraken":   {openedAt: openedAt, prices: map[string][2]float64{"BTCUSDT": {41900, 1}}},
+		// Failing and stale exchanges are left out of the consolidated price
+		"down":  {err: errors.New("connection refused")},
+		"stale": {openedAt: openedAt.Add(-time.Hour), prices: map[string][2]float64{"BTCUSDT": {1, 1}}},
+	})
+	ctx := context.Background()
+
+	run, err := f.service.CollectOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &services.AggregationRun{Stored: 1, Quotes: 3, Failed: 2}, run)
+
+	candle, err := f.repos.PriceHistory.GetLatest(ctx, "AGG-BTCUSDT", "1m")
+	require.NoError(t, err)
+	assert.Equal(t, 42000.0, candle.ClosePrice)
+	assert.Equal(t, 13.0, candle.Volume)
+
+	history, err := f.repos.ExchangePrices.GetHistory(ctx, "BTCUSDT", "kraken", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 41900.0, history[0].Price)
+
+	consolidated, err := f.service.GetConsolidatedPrice(ctx, "btcusdt")
+	require.NoError(t, err)
+	assert.Equal(t, "AGG-BTCUSDT", consolidated.AlertSymbol)
+	assert.Equal(t, 42000.0, consolidated.Price)
+	require.Len(t, consolidated.Quotes, 3)
+	assert.Equal(t, "binance", consolidated.Quotes[0].Exchange)
+	assert.Equal(t, "kraken", consolidated.BestBuy.Exchange)
+	assert.Equal(t, "coinbase", consolidated.BestSell.Exchange)
+	assert.InDelta(t, 200.0/41900*100, consolidated.SpreadPercent, 1e-9)
+
+	// The prices expire with the max quote age
+	f.clock.Advance(10 * time.Minute)
+	_, err = f.service.GetConsolidatedPrice(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+	_, err = f.service.GetConsolidatedPrice(ctx, "ETHUSDT")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestPriceAggregationService_WeighsByVolumeAndExpiresHistory(t *testing.T) {
+	openedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := newAggregationFixture(t, map[string]*fakeExchange{
+		"binance":  {openedAt: openedAt, prices: map[string][2]float64{"BTCUSDT": {100, 3}}},
+		"coinbase": {openedAt: openedAt, prices: map[string][2]float64{"BTCUSDT": {200, 1}}},
+	})
+	require.NoError(t, f.service.SetMethod(services.AggregationVWAP))
+	assert.Error(t, f.service.SetMethod("mean"))
+	f.service.SetHistoryRetention(time.Hour)
+	ctx := context.Background()
+
+	_, err := f.service.CollectOnce(ctx)
+	require.NoError(t, err)
+	candle, err := f.repos.PriceHistory.GetLatest(ctx, "AGG-BTCUSDT", "1m")
+	require.NoError(t, err)
+	assert.Equal(t, 125.0, candle.ClosePrice)
+
+	f.clock.Advance(2 * time.Hour)
+	run, err := f.service.CollectOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Skipped)
+	assert.Equal(t, int64(2), run.Deleted)
+}
+
+func TestPriceAggregationService_ChecksAlertsOnConsolidatedPrices(t *testing.T) {
+	f := newAggregationFixture(t, map[string]*fakeExchange{"binance": {}, "kraken": {}})
+	ctx := context.Background()
+
+	assert.NoError(t, f.service.CheckAlert(ctx, &entities.Alert{Symbol: "ETHUSDT", AlertType: "rsi", Timeframe: "1h"}))
+	assert.NoError(t, f.service.CheckAlert(ctx, &entities.Alert{Symbol: "AGG-BTCUSDT", AlertType: "price", Timeframe: "1m"}))
+
+	for _, alert := range []*entities.Alert{
+		{Symbol: "AGG-ETHUSDT", AlertType: "price", Timeframe: "1m"},
+		{Symbol: "AGG-BTCUSDT", AlertType: "rsi", Timeframe: "1m"},
+		{Symbol: "AGG-BTCUSDT", AlertType: "percentage", Timeframe: "1h"},
+	} {
+		var validationErr *entities.ValidationError
+		assert.ErrorAs(t, f.service.CheckAlert(ctx, alert), &validationErr, alert.Symbol)
+	}
+
+	disabled := newAggregationFixture(t, map[string]*fakeExchange{"binance": {}})
+	_, err := disabled.service.CollectOnce(ctx)
+	assert.ErrorIs(t, err, services.ErrAggregationDisabled)
+}
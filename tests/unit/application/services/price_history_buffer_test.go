@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func bufferedCandle(symbol string, minute int) entities.PriceHistory {
+	return entities.PriceHistory{
+		Symbol: symbol, Timeframe: "1m", ClosePrice: 100,
+		Timestamp: time.Date(2024, 5, 1, 12, minute, 0, 0, time.UTC),
+	}
+}
+
+func TestPriceHistoryBuffer_FlushesBySizeAndOnStop(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+
+	buffer := services.NewPriceHistoryBuffer(priceRepo, logger)
+	buffer.SetFlushSize(3)
+	buffer.SetFlushInterval(time.Hour)
+	buffer.Start(ctx)
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "BNBUSDT"} {
+		buffer.Add(ctx, bufferedCandle(symbol, 0))
+	}
+	assert.Equal(t, 1, buffer.Pending())
+	symbols, err := priceRepo.GetSymbols(ctx, "1m")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}, symbols)
+
+	buffer.Stop()
+	assert.Zero(t, buffer.Pending())
+	_, err = priceRepo.GetLatest(ctx, "BNBUSDT", "1m")
+	assert.NoError(t, err)
+}
+
+func TestPriceHistoryBuffer_KeepsABoundedBacklogWhileInsertsFail(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	priceRepo := new(testutils.MockPriceHistoryRepository)
+	priceRepo.On("BulkInsert", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Times(11)
+
+	buffer := services.NewPriceHistoryBuffer(priceRepo, logger)
+	buffer.SetFlushSize(2)
+	for i := 0; i < 22; i++ {
+		buffer.Add(ctx, bufferedCandle("BTCUSDT", i))
+	}
+	// Failed batches stay queued, capped at ten flush sizes
+	assert.Equal(t, 20, buffer.Pending())
+
+	var stored []entities.PriceHistory
+	priceRepo.On("BulkInsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]entities.PriceHistory)
+	}).Return(nil).Once()
+	require.NoError(t, buffer.Flush(ctx))
+	require.Len(t, stored, 20)
+	// The oldest candles were dropped and the rest kept their order
+	assert.Equal(t, 2, stored[0].Timestamp.Minute())
+	assert.Equal(t, 21, stored[19].Timestamp.Minute())
+	assert.Zero(t, buffer.Pending())
+}
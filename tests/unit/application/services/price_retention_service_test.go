@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minuteCandles returns n 1m candles from start closing at 100, 101, ...
+func minuteCandles(symbol string, start time.Time, n int) []entities.PriceHistory {
+	candles := make([]entities.PriceHistory, n)
+	for i := range candles {
+		price := 100 + float64(i)
+		candles[i] = entities.PriceHistory{
+			Symbol: symbol, Timeframe: "1m", Timestamp: start.Add(time.Duration(i) * time.Minute),
+			OpenPrice: price, HighPrice: price + 1, LowPrice: price - 1, ClosePrice: price, Volume: 1,
+		}
+	}
+	return candles
+}
+
+func TestPriceRetentionService_DownsamplesThenDeletesExpiredCandles(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+
+	// Three expired hours of 1m candles, the first of which the exchange already
+	// provided an hourly candle for, and an hour still kept
+	expired := time.Now().UTC().AddDate(0, 0, -9).Truncate(24 * time.Hour).Add(2 * time.Hour)
+	require.NoError(t, priceRepo.BulkInsert(ctx, minuteCandles("BTCUSDT", expired, 180)))
+	require.NoError(t, priceRepo.BulkInsert(ctx, minuteCandles("BTCUSDT", time.Now().UTC().Add(-2*time.Hour), 60)))
+	require.NoError(t, priceRepo.Create(ctx, &entities.PriceHistory{
+		Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: expired, OpenPrice: 1, HighPrice: 1, LowPrice: 1, ClosePrice: 1,
+	}))
+
+	service := services.NewPriceRetentionService(priceRepo, logger)
+	require.NoError(t, service.SetPolicies([]services.RetentionPolicy{{Timeframe: "1m", KeepDays: 7, DownsampleTo: []string{"1h", "1d"}}}))
+
+	// A dry run reports the changes without making them
+	run, err := service.RunOnce(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, run.DryRun)
+	assert.Equal(t, int64(180), run.Deleted)
+	assert.Equal(t, 3, run.Downsampled)
+	require.Len(t, run.Results, 1)
+	assert.Equal(t, map[string]int{"1h": 2, "1d": 1}, run.Results[0].Downsampled)
+	count, err := priceRepo.CountBefore(ctx, "BTCUSDT", "1m", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(240), count)
+
+	run, err = service.RunOnce(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(180), run.Deleted)
+	assert.Equal(t, 3, run.Downsampled)
+
+	minutes, err := priceRepo.GetBySymbol(ctx, "BTCUSDT", "1m", 0)
+	require.NoError(t, err)
+	assert.Len(t, minutes, 60)
+
+	hours, err := priceRepo.GetByTimeRange(ctx, "BTCUSDT", "1h", expired, expired.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hours, 3)
+	assert.Equal(t, 1.0, hours[0].ClosePrice, "the exchange's candle is kept")
+	assert.Equal(t, entities.PriceHistory{
+		ID: hours[1].ID, Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: expired.Add(time.Hour),
+		OpenPrice: 160, HighPrice: 220, LowPrice: 159, ClosePrice: 219, Volume: 60, CreatedAt: hours[1].CreatedAt,
+	}, hours[1])
+
+	days, err := priceRepo.GetBySymbol(ctx, "BTCUSDT", "1d", 0)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, 100.0, days[0].OpenPrice)
+	assert.Equal(t, 279.0, days[0].ClosePrice)
+	assert.Equal(t, 180.0, days[0].Volume)
+
+	// Nothing is left to expire
+	run, err = service.RunOnce(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, run.Results)
+}
+
+func TestPriceRetentionService_RejectsInvalidPolicies(t *testing.T) {
+	service := services.NewPriceRetentionService(testutils.NewMemoryPriceHistoryRepository(), logrus.New())
+
+	for name, policy := range map[string]services.RetentionPolicy{
+		"timeframe":  {Timeframe: "2m", KeepDays: 7},
+		"keep days":  {Timeframe: "1m", KeepDays: 0},
+		"not longer": {Timeframe: "1h", KeepDays: 7, DownsampleTo: []string{"5m"}},
+		"multiple":   {Timeframe: "30m", KeepDays: 7, DownsampleTo: []string{"15m"}},
+		"over a day": {Timeframe: "1h", KeepDays: 7, DownsampleTo: []string{"1w"}},
+	} {
+		var validationErr *entities.ValidationError
+		assert.ErrorAs(t, service.SetPolicies([]services.RetentionPolicy{policy}), &validationErr, name)
+	}
+	assert.Empty(t, service.Policies())
+}
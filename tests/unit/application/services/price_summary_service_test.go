@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var summaryStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hourlyCandles stores the hourly candles from hour first to last after summaryStart,
+// each closing at 100 plus its hour
+func hourlyCandles(t *testing.T, repos *testutils.MemoryRepositories, symbol string, first, last int) {
+	var candles []entities.PriceHistory
+	for hour := first; hour <= last; hour++ {
+		price := float64(100 + hour)
+		candles = append(candles, entities.PriceHistory{
+			Symbol: symbol, Timeframe: "1h", Timestamp: summaryStart.Add(time.Duration(hour) * time.Hour),
+			OpenPrice: price, HighPrice: price + 1, LowPrice: price - 1, ClosePrice: price, Volume: 1,
+		})
+	}
+	require.NoError(t, repos.PriceHistory.BulkInsert(context.Background(), candles))
+}
+
+func TestPriceSummaryService_CompactsCandlesIncrementally(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(summaryStart.Add(2*24*time.Hour + 10*time.Hour + 30*time.Minute))
+	ctx := context.Background()
+
+	service := services.NewPriceSummaryService(repos.PriceHistory, repos.PriceSummaries, logger)
+	service.SetClock(clock)
+	service.SetBackfillDays(2)
+	assert.Error(t, service.SetTimeframe("7m"))
+	assert.Error(t, service.SetTimeframe("1w"))
+
+	// Two days and the current day's first 11 hours; the older candle is past the backfill
+	hourlyCandles(t, repos, "BTCUSDT", -48, -48)
+	hourlyCandles(t, repos, "BTCUSDT", 0, 58)
+
+	run, err := service.CompactOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &services.SummaryRun{Symbols: 1, Stored: 3}, run)
+
+	summaries, err := service.GetDailySummaries(ctx, "BTCUSDT", 7)
+	require.NoError(t, err)
+	require.Len(t, summaries, 3)
+	first := summaries[0]
+	assert.Equal(t, summaryStart, first.Day)
+	assert.Equal(t, []float64{100, 124, 99, 123, 24}, []float64{first.OpenPrice, first.HighPrice, first.LowPrice, first.ClosePrice, first.Volume})
+	assert.Equal(t, 24, first.Candles)
+	assert.Equal(t, "1h", first.SourceTimeframe)
+	assert.Greater(t, first.Volatility, 0.0)
+	assert.InDelta(t, 23.0, first.ChangePercent(), 1e-9)
+	assert.Equal(t, 11, summaries[2].Candles)
+
+	// The next run only rewrites the current day
+	hourlyCandles(t, repos, "BTCUSDT", 59, 63)
+	clock.Advance(5 * time.Hour)
+	run, err = service.CompactOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Stored)
+
+	summaries, err = service.GetDailySummaries(ctx, "BTCUSDT", 1)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 16, summaries[0].Candles)
+	assert.Equal(t, 163.0, summaries[0].ClosePrice)
+
+	_, err = service.GetDailySummaries(ctx, "ETHUSDT", 7)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestAlertEngine_PercentageWindowReadsDailySummaries(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repos := testutils.NewMemoryRepositories()
+	ctx := context.Background()
+
+	now := summaryStart.Add(30 * 24 * time.Hour)
+	require.NoError(t, repos.PriceHistory.Create(ctx, &entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1h", ClosePrice: 121, Timestamp: now}))
+	// A week before, the day ending at midnight is the nearest to the window's start
+	require.NoError(t, repos.PriceSummaries.Upsert(ctx, []entities.PriceSummary{
+		{Symbol: "BTCUSDT", Day: now.AddDate(0, 0, -8), OpenPrice: 90, ClosePrice: 110},
+		{Symbol: "BTCUSDT", Day: now.AddDate(0, 0, -7), OpenPrice: 110, ClosePrice: 130},
+	}))
+
+	engine := services.NewAlertEngine(repos.Alerts, repos.PriceHistory, repos.TechnicalIndicators, repos.Notifications, nil, logger)
+	engine.SetPriceSummaryRepository(repos.PriceSummaries)
+	alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: "BTCUSDT", AlertType: "percentage", ConditionType: "up", TargetValue: 10, Timeframe: "1h", WindowDays: 7, Enabled: true}
+	require.NoError(t, alert.Validate())
+
+	result, err := engine.EvaluateAlert(ctx, alert)
+	require.NoError(t, err)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 110.0, result.Context["base_price"])
+	assert.Contains(t, result.Message, "in 7d")
+
+	// Without summaries around the window's start there is nothing to compare with
+	longer := &entities.Alert{ID: uuid.New(), UserID: alert.UserID, Symbol: "BTCUSDT", AlertType: "percentage", ConditionType: "up", TargetValue: 10, Timeframe: "1h", WindowDays: 60, Enabled: true}
+	_, err = engine.EvaluateAlert(ctx, longer)
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+
+	// Without summaries at all the recent candles can't stand in for the week
+	withoutSummaries := services.NewAlertEngine(repos.Alerts, repos.PriceHistory, repos.TechnicalIndicators, repos.Notifications, nil, logger)
+	week := &entities.Alert{ID: uuid.New(), UserID: alert.UserID, Symbol: "BTCUSDT", AlertType: "percentage", ConditionType: "up", TargetValue: 10, Timeframe: "1h", WindowDays: 7, Enabled: true}
+	_, err = withoutSummaries.EvaluateAlert(ctx, week)
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+}
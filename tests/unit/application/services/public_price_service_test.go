@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+func storeMinuteCandle(t *testing.T, repo *testutils.MemoryPriceHistoryRepository, symbol string, at time.Time, open, high, low, close, volume float64) {
+	t.Helper()
+	require.NoError(t, repo.Create(context.Background(), &entities.PriceHistory{
+		Symbol:     symbol,
+		Timeframe:  "1m",
+		Timestamp:  at,
+		OpenPrice:  open,
+		HighPrice:  high,
+		LowPrice:   low,
+		ClosePrice: close,
+		Volume:     volume,
+	}))
+}
+
+func TestPublicPriceService_ComputesRolling24hStats(t *testing.T) {
+	repo := testutils.NewMemoryPriceHistoryRepository()
+	clock := testutils.NewFakeClock(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	service := services.NewPublicPriceService(repo)
+	service.SetClock(clock)
+
+	now := clock.Now()
+	// Older than 24h, left out of the statistics
+	storeMinuteCandle(t, repo, "BTCUSDT", now.Add(-25*time.Hour), 10, 500, 5, 10, 999)
+	storeMinuteCandle(t, repo, "BTCUSDT", now.Add(-20*time.Hour), 100, 105, 95, 102, 10)
+	storeMinuteCandle(t, repo, "BTCUSDT", now.Add(-10*time.Hour), 102, 130, 101, 120, 20)
+	storeMinuteCandle(t, repo, "BTCUSDT", now.Add(-time.Minute), 120, 121, 109, 110, 5)
+
+	ticker, err := service.GetTicker(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 110.0, ticker.Price)
+	assert.Equal(t, 100.0, ticker.Open24h)
+	assert.Equal(t, 130.0, ticker.High24h)
+	assert.Equal(t, 95.0, ticker.Low24h)
+	assert.Equal(t, 35.0, ticker.Volume24h)
+	assert.InDelta(t, 10.0, ticker.Change24h, 1e-9)
+	assert.InDelta(t, 10.0, ticker.ChangePercent24h, 1e-9)
+	assert.Equal(t, now.Add(-time.Minute), ticker.UpdatedAt)
+}
+
+func TestPublicPriceService_CachesTickersForTheirTTL(t *testing.T) {
+	repo := testutils.NewMemoryPriceHistoryRepository()
+	clock := testutils.NewFakeClock(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	service := services.NewPublicPriceService(repo)
+	service.SetClock(clock)
+	ctx := context.Background()
+
+	_, err := service.GetTicker(ctx, "ETHUSDT")
+	assert.ErrorIs(t, err, services.ErrPublicTickerNotFound)
+	storeMinuteCandle(t, repo, "ETHUSDT", clock.Now(), 2000, 2000, 2000, 2000, 1)
+
+	// The missing symbol is cached like any other until the TTL passes
+	_, err = service.GetTicker(ctx, "ETHUSDT")
+	assert.ErrorIs(t, err, services.ErrPublicTickerNotFound)
+
+	clock.Advance(services.PublicTickerTTL + time.Second)
+	ticker, err := service.GetTicker(ctx, "ETHUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 2000.0, ticker.Price)
+
+	storeMinuteCandle(t, repo, "ETHUSDT", clock.Now(), 2100, 2100, 2100, 2100, 1)
+	ticker, err = service.GetTicker(ctx, "ETHUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 2000.0, ticker.Price)
+}
+
+func TestPublicPriceService_GetTickersSkipsSymbolsWithoutRecentPrices(t *testing.T) {
+	repo := testutils.NewMemoryPriceHistoryRepository()
+	clock := testutils.NewFakeClock(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	service := services.NewPublicPriceService(repo)
+	service.SetClock(clock)
+
+	storeMinuteCandle(t, repo, "SOLUSDT", clock.Now().Add(-time.Minute), 150, 150, 150, 150, 1)
+	storeMinuteCandle(t, repo, "ADAUSDT", clock.Now().Add(-48*time.Hour), 1, 1, 1, 1, 1)
+
+	tickers, err := service.GetTickers(context.Background(), []string{"ADAUSDT", "SOLUSDT", "XRPUSDT"})
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	assert.Equal(t, "SOLUSDT", tickers[0].Symbol)
+}
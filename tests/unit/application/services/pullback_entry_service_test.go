@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullbackEntryService_EMATrendUsesIndicatorKeys(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history := make([]entities.PriceHistory, 30)
+	for i := range history {
+		history[i] = entities.PriceHistory{
+			HighPrice: 101, LowPrice: 99, ClosePrice: 100, Volume: 10,
+			Timestamp: now.Add(time.Duration(i) * time.Hour),
+		}
+	}
+
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 50).Return(history, nil)
+
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	fast, slow := 105.0, 100.0
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "EMA_12").
+		Return(&entities.TechnicalIndicator{IndicatorType: "EMA", IndicatorKey: "EMA_12", Value: &fast}, nil)
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", "EMA_26").
+		Return(&entities.TechnicalIndicator{IndicatorType: "EMA", IndicatorKey: "EMA_26", Value: &slow}, nil)
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1h", mock.Anything).Return(nil, errors.New("not found"))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewPullbackEntryService(priceRepo, indicatorRepo, logger)
+
+	entry, err := service.AnalyzePullbackEntry(context.Background(), "BTCUSDT", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, "BULLISH", entry.EMATrend)
+	indicatorRepo.AssertExpectations(t)
+}
@@ -0,0 +1,223 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportServiceTestSuite struct {
+	suite.Suite
+	settingsRepo     *testutils.MockUserSettingsRepository
+	alertRepo        *testutils.MockAlertRepository
+	priceRepo        *testutils.MockPriceHistoryRepository
+	indicatorRepo    *testutils.MockTechnicalIndicatorRepository
+	notificationRepo *testutils.MockNotificationRepository
+	storage          *testutils.MemoryStorage
+	redis            *redis.Client
+	clock            *testutils.FakeClock
+	service          *services.ReportService
+	userID           uuid.UUID
+	ctx              context.Context
+}
+
+// 2024-05-06 is a Monday
+var reportNow = time.Date(2024, 5, 6, 9, 15, 0, 0, time.UTC)
+
+func (suite *ReportServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.userID = uuid.New()
+	suite.settingsRepo = new(testutils.MockUserSettingsRepository)
+	suite.alertRepo = new(testutils.MockAlertRepository)
+	suite.priceRepo = new(testutils.MockPriceHistoryRepository)
+	suite.indicatorRepo = new(testutils.MockTechnicalIndicatorRepository)
+	suite.notificationRepo = new(testutils.MockNotificationRepository)
+	suite.clock = testutils.NewFakeClock(reportNow)
+	suite.storage = testutils.NewMemoryStorage()
+	suite.storage.SetClock(suite.clock)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	mr := miniredis.RunT(suite.T())
+	suite.redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	suite.T().Cleanup(func() { suite.redis.Close() })
+
+	notificationService := services.NewNotificationService(suite.notificationRepo, new(testutils.MockUserRepository),
+		services.NewRedisClientWrapper(suite.redis), logger)
+	notificationService.SetClock(suite.clock)
+
+	suite.service = services.NewReportService(suite.settingsRepo, suite.alertRepo, suite.priceRepo,
+		suite.indicatorRepo, notificationService, logger)
+	suite.service.SetClock(suite.clock)
+	suite.service.SetStorage(suite.storage)
+}
+
+func (suite *ReportServiceTestSuite) settings(frequency string) *entities.UserSettings {
+	return &entities.UserSettings{
+		UserID:             suite.userID,
+		ReportFrequency:    frequency,
+		ReportHour:         8,
+		NotificationsEmail: true,
+		FavoriteSymbols:    []string{"BTCUSDT"},
+	}
+}
+
+func (suite *ReportServiceTestSuite) seedMarketData(to time.Time) {
+	rsi := func(v float64) *float64 { return &v }
+	triggeredAt := to.Add(-2 * time.Hour)
+	staleTrigger := to.Add(-72 * time.Hour)
+
+	// Newest first, as the repositories return them; the oldest candle falls outside the window
+	suite.priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", 25).Return([]entities.PriceHistory{
+		{Symbol: "BTCUSDT", OpenPrice: 108, HighPrice: 112, LowPrice: 107, ClosePrice: 110, Timestamp: to.Add(-time.Hour)},
+		{Symbol: "BTCUSDT", OpenPrice: 100, HighPrice: 109, LowPrice: 95, ClosePrice: 108, Timestamp: to.Add(-23 * time.Hour)},
+		{Symbol: "BTCUSDT", OpenPrice: 50, HighPrice: 500, LowPrice: 1, ClosePrice: 60, Timestamp: to.Add(-30 * time.Hour)},
+	}, nil)
+	suite.indicatorRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1h", "RSI", 25).Return([]entities.TechnicalIndicator{
+		{Symbol: "BTCUSDT", IndicatorType: "RSI", Value: rsi(74), Timestamp: to.Add(-time.Hour)},
+		{Symbol: "BTCUSDT", IndicatorType: "RSI", Value: rsi(62), Timestamp: to.Add(-20 * time.Hour)},
+	}, nil)
+	suite.alertRepo.On("GetByUserID", mock.Anything, suite.userID, 500, 0).Return([]entities.Alert{
+		{ID: uuid.New(), UserID: suite.userID, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 109, Timeframe: "1h", TriggeredAt: &triggeredAt},
+		{ID: uuid.New(), UserID: suite.userID, Symbol: "ETHUSDT", AlertType: "price", ConditionType: "below", TargetValue: 2000, Timeframe: "1h", TriggeredAt: &staleTrigger},
+		{ID: uuid.New(), UserID: suite.userID, Symbol: "SOLUSDT", AlertType: "price", ConditionType: "above", TargetValue: 200, Timeframe: "1h"},
+	}, nil)
+}
+
+func (suite *ReportServiceTestSuite) TestGenerateDailyReport() {
+	to := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	suite.seedMarketData(to)
+
+	report, err := suite.service.GenerateReport(suite.ctx, suite.settings("daily"), services.ReportDaily, to)
+	suite.Require().NoError(err)
+
+	suite.Equal(to.Add(-24*time.Hour), report.From)
+	suite.Require().Len(report.Watchlist, 1)
+	suite.Equal(services.WatchlistPerformance{
+		Symbol: "BTCUSDT", Open: 100, Close: 110, High: 112, Low: 95, ChangePercent: 10,
+	}, report.Watchlist[0])
+
+	suite.Require().Len(report.TriggeredAlerts, 1)
+	suite.Equal("BTCUSDT", report.TriggeredAlerts[0].Symbol)
+
+	suite.Require().Len(report.IndicatorChanges, 1)
+	suite.Equal("entered overbought territory", report.IndicatorChanges[0].Note)
+	suite.Equal("1 watchlist symbols, 1 alerts triggered, 1 notable indicator changes", report.Summary())
+}
+
+func (suite *ReportServiceTestSuite) TestRenderers() {
+	to := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	suite.seedMarketData(to)
+	report, err := suite.service.GenerateReport(suite.ctx, suite.settings("daily"), services.ReportDaily, to)
+	suite.Require().NoError(err)
+
+	html, err := services.RenderReportHTML(report)
+	suite.Require().NoError(err)
+	suite.Contains(string(html), "BTCUSDT")
+	suite.Contains(string(html), "10.00%")
+	suite.Contains(string(html), "entered overbought territory")
+
+	pdf := services.RenderReportPDF(report)
+	suite.True(bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	suite.True(bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	suite.Contains(string(pdf), "BTCUSDT")
+}
+
+func (suite *ReportServiceTestSuite) TestRunDueReportsDeliversAndRecordsSlot() {
+	settings := suite.settings("daily")
+	suite.seedMarketData(time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC))
+
+	suite.settingsRepo.On("GetWithReportsEnabled", mock.Anything).Return([]entities.UserSettings{*settings}, nil).Once()
+	suite.settingsRepo.On("Update", mock.Anything, mock.MatchedBy(func(s *entities.UserSettings) bool {
+		return s.LastReportAt != nil && s.LastReportAt.Equal(reportNow)
+	})).Return(nil).Once()
+	suite.notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *entities.Notification) bool {
+		return n.UserID == suite.userID && n.NotificationType == services.NotificationTypeMarketReport
+	})).Return(nil).Once()
+
+	sent, err := suite.service.RunDueReports(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, sent)
+
+	keys := suite.storage.Keys()
+	suite.Len(keys, 2)
+	for _, key := range keys {
+		suite.True(strings.HasPrefix(key, services.ReportPrefix+suite.userID.String()+"/daily-20240506"))
+	}
+
+	queued, err := suite.redis.ZRange(suite.ctx, "notification_queue", 0, -1).Result()
+	suite.Require().NoError(err)
+	suite.Require().Len(queued, 1)
+
+	var notification services.QueuedNotification
+	suite.Require().NoError(json.Unmarshal([]byte(queued[0]), &notification))
+	suite.Equal([]services.NotificationChannel{services.ChannelEmail}, notification.Channels)
+	suite.Contains(notification.Data["html"], "PriceGuard daily market summary")
+	suite.NotEmpty(notification.Data["pdf_url"])
+
+	suite.settingsRepo.AssertExpectations(suite.T())
+	suite.notificationRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ReportServiceTestSuite) TestRunDueReportsSkipsAlreadySentSlot() {
+	settings := suite.settings("daily")
+	sentAt := time.Date(2024, 5, 6, 8, 5, 0, 0, time.UTC)
+	settings.LastReportAt = &sentAt
+
+	suite.settingsRepo.On("GetWithReportsEnabled", mock.Anything).Return([]entities.UserSettings{*settings}, nil)
+
+	sent, err := suite.service.RunDueReports(suite.ctx)
+	suite.NoError(err)
+	suite.Zero(sent)
+	suite.Empty(suite.storage.Keys())
+}
+
+func TestReportServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportServiceTestSuite))
+}
+
+func TestDueSlot(t *testing.T) {
+	beforeSlot := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)  // Monday, before the 08:00 slot
+	afterSlot := time.Date(2024, 5, 8, 9, 0, 0, 0, time.UTC)   // Wednesday
+	lastMonday := time.Date(2024, 5, 6, 8, 30, 0, 0, time.UTC) // sent right after Monday's slot
+
+	tests := []struct {
+		name         string
+		frequency    string
+		now          time.Time
+		lastReportAt *time.Time
+		expectedSlot time.Time
+		expectedDue  bool
+	}{
+		{"daily before today's hour uses yesterday", "daily", beforeSlot, nil, time.Date(2024, 5, 5, 8, 0, 0, 0, time.UTC), true},
+		{"daily after hour", "daily", afterSlot, &lastMonday, time.Date(2024, 5, 8, 8, 0, 0, 0, time.UTC), true},
+		{"weekly waits for next Monday", "weekly", afterSlot, &lastMonday, time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC), false},
+		{"weekly before Monday slot uses previous week", "weekly", beforeSlot, nil, time.Date(2024, 4, 29, 8, 0, 0, 0, time.UTC), true},
+		{"disabled", "none", afterSlot, nil, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &entities.UserSettings{ReportFrequency: tt.frequency, ReportHour: 8, LastReportAt: tt.lastReportAt}
+			slot, due := services.DueSlot(settings, tt.now)
+			assert.Equal(t, tt.expectedSlot, slot)
+			assert.Equal(t, tt.expectedDue, due)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSandbox(repos *testutils.MemoryRepositories, clock *testutils.FakeClock) (*services.MarketSimulator, *services.SandboxService) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	simulator := services.NewMarketSimulator(repos.PriceHistory, logger)
+	simulator.SetClock(clock)
+	simulator.SetRoll(func() float64 { return 1 })
+	sandbox := services.NewSandboxService(repos.Users, repos.UserSettings, repos.Alerts, simulator, logger)
+	sandbox.SetClock(clock)
+	return simulator, sandbox
+}
+
+func TestMarketSimulator_WalksFromTheRealPairsPrice(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	simulator, _ := newTestSandbox(repos, clock)
+	storePrices(t, repos, clock, map[string]float64{"BTCUSDT": 60000})
+
+	clock.Advance(30 * time.Second)
+	stored, err := simulator.Tick(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(services.DefaultSimulatedSymbols), stored)
+
+	btc, err := repos.PriceHistory.GetLatest(ctx, "DEMO-BTCUSDT", "1m")
+	require.NoError(t, err)
+	assert.Equal(t, 60000.0, btc.OpenPrice)
+	assert.InDelta(t, 60120.12, btc.ClosePrice, 0.01) // One 20bps step up
+	assert.Equal(t, btc.ClosePrice, btc.HighPrice)
+
+	// Pairs without a real price start from their default, and the walk continues
+	// from the last simulated price
+	eth, err := simulator.CurrentPrice(ctx, "DEMO-ETHUSDT")
+	require.NoError(t, err)
+	assert.Greater(t, eth, 3200.0)
+	clock.Advance(30 * time.Second)
+	_, err = simulator.Tick(ctx)
+	require.NoError(t, err)
+	btc, err = repos.PriceHistory.GetLatest(ctx, "DEMO-BTCUSDT", "1m")
+	require.NoError(t, err)
+	assert.InDelta(t, 60120.12, btc.OpenPrice, 0.01)
+
+	// Real market data is left alone
+	market, err := repos.PriceHistory.GetLatest(ctx, "BTCUSDT", "1m")
+	require.NoError(t, err)
+	assert.Equal(t, 60000.0, market.ClosePrice)
+}
+
+func TestSandboxService_ProvisionsAWatchlistAndAlertsOnSimulatedSymbols(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	simulator, sandbox := newTestSandbox(repos, clock)
+
+	user, err := sandbox.Provision(ctx)
+	require.NoError(t, err)
+	assert.True(t, user.Sandbox)
+
+	settings, err := repos.UserSettings.GetByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, simulator.Symbols(), []string(settings.FavoriteSymbols))
+	assert.False(t, settings.NotificationsEmail)
+
+	alerts, err := repos.Alerts.GetByUserID(ctx, user.ID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2*len(simulator.Symbols())+1)
+	for _, alert := range alerts {
+		assert.True(t, entities.IsSandboxSymbol(alert.Symbol), alert.Symbol)
+		assert.NoError(t, alert.Validate())
+		if alert.Symbol == "DEMO-BTCUSDT" && alert.ConditionType == "above" {
+			assert.Equal(t, 65650.0, alert.TargetValue)
+		}
+	}
+}
+
+func TestSandboxService_DeletesOnlyStaleSandboxUsers(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	_, sandbox := newTestSandbox(repos, clock)
+	sandbox.SetTTL(2 * time.Hour)
+
+	realUser := &entities.User{GoogleID: "google-1", Email: "jo@example.com", Name: "Jo", CreatedAt: clock.Now()}
+	require.NoError(t, repos.Users.Create(ctx, realUser))
+	stale, err := sandbox.Provision(ctx)
+	require.NoError(t, err)
+	clock.Advance(90 * time.Minute)
+	fresh, err := sandbox.Provision(ctx)
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour)
+	deleted, err := sandbox.CleanupStale(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = repos.Users.GetByID(ctx, stale.ID)
+	assert.Error(t, err)
+	for _, user := range []*entities.User{realUser, fresh} {
+		_, err = repos.Users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+	}
+}
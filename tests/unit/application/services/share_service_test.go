@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type stubPullbackAnalyzer struct {
+	entry *services.PullbackEntry
+}
+
+func (s stubPullbackAnalyzer) AnalyzePullbackEntry(ctx context.Context, symbol, timeframe string) (*services.PullbackEntry, error) {
+	return s.entry, nil
+}
+
+type ShareServiceTestSuite struct {
+	suite.Suite
+	shareRepo        *testutils.MockShareLinkRepository
+	notificationRepo *testutils.MockNotificationRepository
+	clock            *testutils.FakeClock
+	service          *services.ShareService
+	userID           uuid.UUID
+	ctx              context.Context
+}
+
+func (suite *ShareServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.userID = uuid.New()
+	suite.shareRepo = new(testutils.MockShareLinkRepository)
+	suite.notificationRepo = new(testutils.MockNotificationRepository)
+	suite.clock = testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	analyzer := stubPullbackAnalyzer{entry: &services.PullbackEntry{Symbol: "BTCUSDT", Timeframe: "1h", Signal: "LONG", Confidence: 72}}
+	suite.service = services.NewShareService(suite.shareRepo, analyzer, suite.notificationRepo, logger)
+	suite.service.SetClock(suite.clock)
+}
+
+func (suite *ShareServiceTestSuite) TestSharePullbackAnalysis_StoresHashedTokenAndSnapshot() {
+	var stored *entities.ShareLink
+	suite.shareRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.ShareLink")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*entities.ShareLink) }).
+		Return(nil)
+
+	created, err := suite.service.SharePullbackAnalysis(suite.ctx, suite.userID, "BTCUSDT", "1h", 0)
+	suite.Require().NoError(err)
+
+	hash := sha256.Sum256([]byte(created.Token))
+	suite.Equal(hex.EncodeToString(hash[:]), stored.TokenHash)
+	suite.Equal(services.SharedSnapshotPath+created.Token, created.URL)
+	suite.Equal(services.ShareResourcePullback, stored.ResourceType)
+	suite.Equal("LONG", stored.Snapshot["signal"])
+	suite.Equal(suite.clock.Now().Add(services.DefaultShareLinkTTL), stored.ExpiresAt)
+}
+
+func (suite *ShareServiceTestSuite) TestSharePullbackAnalysis_RejectsLongExpiry() {
+	_, err := suite.service.SharePullbackAnalysis(suite.ctx, suite.userID, "BTCUSDT", "1h", 31*24*time.Hour)
+	suite.ErrorIs(err, entities.ErrValidation)
+	suite.shareRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *ShareServiceTestSuite) TestShareAlertTrigger() {
+	alertID := uuid.New()
+	ownID, foreignID, systemID := uuid.New(), uuid.New(), uuid.New()
+	suite.notificationRepo.On("GetByID", suite.ctx, ownID).Return(&entities.Notification{
+		ID: ownID, UserID: suite.userID, AlertID: &alertID, Title: "Price Alert",
+		Context: map[string]interface{}{"symbol": "ETHUSDT", "current_value": 3100.0},
+	}, nil)
+	suite.notificationRepo.On("GetByID", suite.ctx, foreignID).Return(&entities.Notification{ID: foreignID, UserID: uuid.New(), AlertID: &alertID}, nil)
+	suite.notificationRepo.On("GetByID", suite.ctx, systemID).Return(&entities.Notification{ID: systemID, UserID: suite.userID}, nil)
+	suite.shareRepo.On("Create", suite.ctx, mock.AnythingOfType("*entities.ShareLink")).Return(nil)
+
+	created, err := suite.service.ShareAlertTrigger(suite.ctx, suite.userID, ownID, 24*time.Hour)
+	suite.Require().NoError(err)
+	suite.Equal("ETHUSDT", created.Symbol)
+	suite.Equal(services.ShareResourceAlertTrigger, created.ResourceType)
+	suite.Equal("Price Alert", created.Snapshot["title"])
+
+	_, err = suite.service.ShareAlertTrigger(suite.ctx, suite.userID, foreignID, 0)
+	suite.ErrorIs(err, services.ErrShareLinkNotFound)
+	_, err = suite.service.ShareAlertTrigger(suite.ctx, suite.userID, systemID, 0)
+	suite.ErrorIs(err, services.ErrNotAlertTrigger)
+}
+
+func (suite *ShareServiceTestSuite) TestGetSharedSnapshot_HidesExpiredAndRevokedLinks() {
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	revokedAt := suite.clock.Now().Add(-time.Minute)
+	suite.shareRepo.On("GetByTokenHash", suite.ctx, hash("active")).Return(&entities.ShareLink{Symbol: "BTCUSDT", ExpiresAt: suite.clock.Now().Add(time.Hour)}, nil)
+	suite.shareRepo.On("GetByTokenHash", suite.ctx, hash("revoked")).Return(&entities.ShareLink{ExpiresAt: suite.clock.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	suite.shareRepo.On("GetByTokenHash", suite.ctx, hash("unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	link, err := suite.service.GetSharedSnapshot(suite.ctx, "active")
+	suite.Require().NoError(err)
+	suite.Equal("BTCUSDT", link.Symbol)
+
+	_, err = suite.service.GetSharedSnapshot(suite.ctx, "revoked")
+	suite.ErrorIs(err, services.ErrShareLinkNotFound)
+	_, err = suite.service.GetSharedSnapshot(suite.ctx, "unknown")
+	suite.ErrorIs(err, services.ErrShareLinkNotFound)
+
+	suite.clock.Advance(2 * time.Hour)
+	_, err = suite.service.GetSharedSnapshot(suite.ctx, "active")
+	suite.ErrorIs(err, services.ErrShareLinkNotFound)
+}
+
+func (suite *ShareServiceTestSuite) TestRevokeShareLink() {
+	id, otherID := uuid.New(), uuid.New()
+	suite.shareRepo.On("Revoke", suite.ctx, id, suite.userID, suite.clock.Now()).Return(nil)
+	suite.shareRepo.On("Revoke", suite.ctx, otherID, suite.userID, suite.clock.Now()).Return(gorm.ErrRecordNotFound)
+
+	suite.NoError(suite.service.RevokeShareLink(suite.ctx, suite.userID, id))
+	suite.ErrorIs(suite.service.RevokeShareLink(suite.ctx, suite.userID, otherID), services.ErrShareLinkNotFound)
+}
+
+func TestShareServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ShareServiceTestSuite))
+}
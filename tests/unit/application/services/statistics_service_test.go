@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatisticsService_ComputesAndStoresStats(t *testing.T) {
+	latest := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	history := dailyCloses(latest, 100, 110, 99, 120, 90, 108)
+
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1d").Return(&history[0], nil)
+	priceRepo.On("GetBySymbol", mock.Anything, "BTCUSDT", "1d", 6).Return(history, nil)
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1d", "Volatility_5").Return(nil, errors.New("record not found"))
+	indicatorRepo.On("BulkInsert", mock.Anything, mock.MatchedBy(func(records []entities.TechnicalIndicator) bool {
+		return len(records) == 3 && records[0].IndicatorKey == "Volatility_5" &&
+			records[1].IndicatorKey == "MaxDrawdown_5" && records[2].IndicatorKey == "Sharpe_5" &&
+			records[0].Timestamp.Equal(latest)
+	})).Return(nil)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	stats, err := services.NewStatisticsService(priceRepo, indicatorRepo, logger).GetStats(context.Background(), "BTCUSDT", "1d", 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, stats.Window)
+	assert.Equal(t, latest, stats.CandleTime)
+	// Peak of 120 down to 90
+	assert.Equal(t, 25.0, stats.MaxDrawdown)
+	assert.Equal(t, 8.0, stats.PeriodReturn)
+	assert.Greater(t, stats.Volatility, 0.0)
+	// Positive drift gives a positive ratio
+	assert.InDelta(t, math.Log(1.08)/5/(stats.Volatility/100/math.Sqrt(365))*math.Sqrt(365), stats.Sharpe, 0.01)
+	indicatorRepo.AssertExpectations(t)
+}
+
+func TestStatisticsService_ReusesStatsStoredForLatestCandle(t *testing.T) {
+	latest := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	priceRepo := &testutils.MockPriceHistoryRepository{}
+	priceRepo.On("GetLatest", mock.Anything, "BTCUSDT", "1d").Return(&entities.PriceHistory{ClosePrice: 100, Timestamp: latest}, nil)
+
+	indicatorRepo := &testutils.MockTechnicalIndicatorRepository{}
+	for key, value := range map[string]float64{"Volatility_30": 55.5, "MaxDrawdown_30": 12, "Sharpe_30": 1.4} {
+		value := value
+		indicatorRepo.On("GetLatestByKey", mock.Anything, "BTCUSDT", "1d", key).Return(&entities.TechnicalIndicator{
+			IndicatorKey: key, Value: &value, Timestamp: latest,
+			Metadata: map[string]interface{}{"period_return": 4.2},
+		}, nil)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	stats, err := services.NewStatisticsService(priceRepo, indicatorRepo, logger).GetStats(context.Background(), "BTCUSDT", "1d", 0)
+	require.NoError(t, err)
+	assert.Equal(t, services.SymbolStats{
+		Symbol: "BTCUSDT", Timeframe: "1d", Window: 30,
+		Volatility: 55.5, MaxDrawdown: 12, Sharpe: 1.4, PeriodReturn: 4.2, CandleTime: latest,
+	}, *stats)
+	priceRepo.AssertNotCalled(t, "GetBySymbol", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wavyCandles returns hourly candles oldest first, swinging up and down so RSI has
+// both gains and losses to average
+func wavyCandles(first time.Time, candles int) []entities.PriceHistory {
+	history := make([]entities.PriceHistory, candles)
+	for i := range history {
+		mid := 100 + 10*math.Sin(float64(i)/3) + 0.2*float64(i)
+		history[i] = entities.PriceHistory{
+			Symbol: "BTCUSDT", Timeframe: "1h",
+			OpenPrice: mid - 0.5, HighPrice: mid + 1.5, LowPrice: mid - 1.5, ClosePrice: mid, Volume: 10,
+			Timestamp: first.Add(time.Duration(i) * time.Hour),
+		}
+	}
+	return history
+}
+
+func TestStreamingIndicators_MatchFullWindowCalculations(t *testing.T) {
+	candles := wavyCandles(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), 80)
+	closes := make([]float64, len(candles))
+	priceData := make([]indicators.PriceData, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.ClosePrice
+		priceData[i] = indicators.PriceData{High: candle.HighPrice, Low: candle.LowPrice, Close: candle.ClosePrice}
+	}
+
+	ema := indicators.NewStreamingEMA(12)
+	rsi := indicators.NewStreamingRSI(14)
+	atr := indicators.NewStreamingATR(14)
+	for i := range candles {
+		emaValue, emaReady := ema.Update(closes[i])
+		rsiValue, rsiReady := rsi.Update(closes[i])
+		atrValue, atrReady := atr.Update(priceData[i])
+
+		// Each is ready exactly when the full calculation has enough data
+		assert.Equal(t, i+1 >= 12, emaReady, "EMA at candle %d", i)
+		assert.Equal(t, i+1 >= 15, rsiReady, "RSI at candle %d", i)
+		assert.Equal(t, i+1 >= 15, atrReady, "ATR at candle %d", i)
+
+		if emaReady {
+			series, err := indicators.EMASeries(nil, closes[:i+1], 12)
+			require.NoError(t, err)
+			assert.InDelta(t, series[len(series)-1], emaValue, 1e-9)
+		}
+		if rsiReady {
+			series, err := indicators.RSISeries(nil, closes[:i+1], 14)
+			require.NoError(t, err)
+			assert.InDelta(t, series[len(series)-1], rsiValue, 1e-9)
+		}
+		if atrReady {
+			expected, err := indicators.CalculateATR(priceData[:i+1], 14)
+			require.NoError(t, err)
+			assert.InDelta(t, expected, atrValue, 1e-9)
+		}
+	}
+}
+
+func TestStreamingIndicatorService_UpdatesIndicatorsOnClosedCandles(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	priceRepo := testutils.NewMemoryPriceHistoryRepository()
+	indicatorRepo := testutils.NewMemoryTechnicalIndicatorRepository()
+	indicatorService := services.NewTechnicalIndicatorService(priceRepo, indicatorRepo, logger)
+	service := services.NewStreamingIndicatorService(nil, priceRepo, indicatorService, logger)
+
+	candles := wavyCandles(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), 64)
+	require.NoError(t, priceRepo.BulkInsert(ctx, candles[:60]))
+
+	expectRSI := func(history []entities.PriceHistory) {
+		t.Helper()
+		closes := make([]float64, len(history))
+		for i, candle := range history {
+			closes[i] = candle.ClosePrice
+		}
+		series, err := indicators.RSISeries(nil, closes, 14)
+		require.NoError(t, err)
+
+		latest, err := indicatorRepo.GetLatestByKey(ctx, "BTCUSDT", "1h", entities.IndicatorKey("RSI", 14))
+		require.NoError(t, err)
+		assert.InDelta(t, series[len(series)-1], *latest.Value, 1e-9)
+	}
+
+	// The first candle builds the state from the stored ones, then each closed
+	// candle is stored with its indicators
+	require.NoError(t, service.ApplyClosedCandle(ctx, candles[60]))
+	require.NoError(t, service.ApplyClosedCandle(ctx, candles[61]))
+	expectRSI(candles[:62])
+
+	for _, key := range []string{
+		entities.IndicatorKey("EMA", 12),
+		entities.IndicatorKey("EMA", 26),
+		entities.IndicatorKey("ATR", 14),
+	} {
+		stored, err := indicatorRepo.GetByKey(ctx, "BTCUSDT", "1h", key, 0)
+		require.NoError(t, err)
+		assert.Len(t, stored, 2, key)
+	}
+	latestCandle, err := priceRepo.GetLatest(ctx, "BTCUSDT", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, candles[61].Timestamp, latestCandle.Timestamp)
+
+	// A candle delivered twice is applied once
+	require.NoError(t, service.ApplyClosedCandle(ctx, candles[61]))
+	stored, err := indicatorRepo.GetByKey(ctx, "BTCUSDT", "1h", entities.IndicatorKey("RSI", 14), 0)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	// A missed candle, stored meanwhile by the scheduled collection, rebuilds the state
+	require.NoError(t, priceRepo.Create(ctx, &candles[62]))
+	require.NoError(t, service.ApplyClosedCandle(ctx, candles[63]))
+	expectRSI(candles)
+}
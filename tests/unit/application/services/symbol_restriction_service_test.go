@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSymbolRestrictionService(t *testing.T, repos *testutils.MemoryRepositories) *services.SymbolRestrictionService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	notificationService := services.NewNotificationService(repos.Notifications, repos.Users, services.NewRedisClientWrapper(rdb), logger)
+
+	service := services.NewSymbolRestrictionService(repos.SymbolRestrictions, repos.Alerts, logger)
+	service.SetNotificationService(notificationService)
+	return service
+}
+
+func TestSymbolRestrictionService_BlacklistPausesAlertsAndNotifiesOwners(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newSymbolRestrictionService(t, repos)
+
+	owner, other := uuid.New(), uuid.New()
+	for _, alert := range []*entities.Alert{
+		testutils.NewAlertBuilder().ForUser(owner).Price("LUNAUSDT").Above(1).Build(),
+		testutils.NewAlertBuilder().ForUser(owner).Price("LUNAUSDT").Below(0.5).Build(),
+		testutils.NewAlertBuilder().ForUser(other).Price("LUNAUSDT").Above(2).Disabled().Build(),
+		testutils.NewAlertBuilder().ForUser(other).Price("BTCUSDT").Above(50000).Build(),
+	} {
+		require.NoError(t, repos.Alerts.Create(ctx, alert))
+	}
+
+	paused, err := service.Restrict(ctx, &entities.SymbolRestriction{Symbol: "lunausdt", Level: entities.SymbolBlacklisted, Reason: "delisted"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, paused)
+
+	alerts, err := repos.Alerts.GetBySymbol(ctx, "LUNAUSDT")
+	require.NoError(t, err)
+	for _, alert := range alerts {
+		assert.False(t, alert.Enabled)
+	}
+	btc, err := repos.Alerts.GetBySymbol(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.True(t, btc[0].Enabled)
+
+	// One notification per owner whose alerts were paused
+	notifications, err := repos.Notifications.GetByUserID(ctx, owner, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, services.NotificationTypeAlertsPaused, notifications[0].NotificationType)
+	assert.Contains(t, notifications[0].Message, "delisted")
+	notifications, err = repos.Notifications.GetByUserID(ctx, other, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	assert.ErrorIs(t, service.CheckNewAlert(ctx, "LUNAUSDT"), entities.ErrConflict)
+	assert.ErrorIs(t, service.CheckEnableAlert(ctx, "LUNAUSDT"), entities.ErrConflict)
+	assert.False(t, service.IsCollected(ctx, "LUNAUSDT"))
+	assert.True(t, service.IsCollected(ctx, "BTCUSDT"))
+	assert.NoError(t, service.CheckNewAlert(ctx, "BTCUSDT"))
+}
+
+func TestSymbolRestrictionService_GreylistOnlyRefusesNewAlerts(t *testing.T) {
+	ctx := context.Background()
+	repos := testutils.NewMemoryRepositories()
+	service := newSymbolRestrictionService(t, repos)
+
+	alert := testutils.NewAlertBuilder().Price("PUMPUSDT").Above(1).Build()
+	require.NoError(t, repos.Alerts.Create(ctx, alert))
+
+	paused, err := service.Restrict(ctx, &entities.SymbolRestriction{Symbol: "PUMPUSDT", Level: entities.SymbolGreylisted, Reason: "wash trading"})
+	require.NoError(t, err)
+	assert.Zero(t, paused)
+
+	stored, err := repos.Alerts.GetByID(ctx, alert.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Enabled)
+
+	assert.ErrorIs(t, service.CheckNewAlert(ctx, "PUMPUSDT"), entities.ErrConflict)
+	assert.NoError(t, service.CheckEnableAlert(ctx, "PUMPUSDT"))
+	assert.True(t, service.IsCollected(ctx, "PUMPUSDT"))
+
+	// Lifting the restriction is seen right away by this instance
+	require.NoError(t, service.Lift(ctx, "PUMPUSDT"))
+	assert.NoError(t, service.CheckNewAlert(ctx, "PUMPUSDT"))
+	assert.ErrorIs(t, service.Lift(ctx, "PUMPUSDT"), entities.ErrNotFound)
+
+	_, err = service.Restrict(ctx, &entities.SymbolRestriction{Symbol: "PUMPUSDT", Level: "banned", Reason: "wash trading"})
+	var validationErr *entities.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
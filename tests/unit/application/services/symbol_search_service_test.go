@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSymbolSearchService(t *testing.T) (*services.SymbolSearchService, *testutils.MockCryptoCurrencyRepository) {
+	t.Helper()
+
+	cryptoRepo := new(testutils.MockCryptoCurrencyRepository)
+	cryptoRepo.On("GetActive", mock.Anything, mock.Anything, 0).Return([]entities.CryptoCurrency{
+		{Symbol: "BTCUSDT", Name: "Bitcoin", Active: true},
+		{Symbol: "BTCDOMUSDT", Name: "Bitcoin Dominance", Active: true},
+		{Symbol: "WBTCUSDT", Name: "Wrapped Bitcoin", Active: true},
+		{Symbol: "ETHUSDT", Name: "Ethereum", Active: true},
+		{Symbol: "ETHBTC", Name: "Ethereum/Bitcoin", Active: true},
+	}, nil)
+
+	volumes := map[string]float64{"BTCUSDT": 900, "BTCDOMUSDT": 10, "WBTCUSDT": 50, "ETHUSDT": 700, "ETHBTC": 20}
+	priceRepo := new(testutils.MockPriceHistoryRepository)
+	for symbol, volume := range volumes {
+		priceRepo.On("GetLatest", mock.Anything, symbol, "1d").Return(&entities.PriceHistory{Volume: volume}, nil)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return services.NewSymbolSearchService(cryptoRepo, priceRepo, logger), cryptoRepo
+}
+
+func symbols(matches []services.SymbolMatch) []string {
+	result := make([]string, len(matches))
+	for i, match := range matches {
+		result[i] = match.Symbol
+	}
+	return result
+}
+
+func TestSymbolSearchService_RanksMatches(t *testing.T) {
+	service, _ := newSymbolSearchService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		// Symbol prefixes by volume, then substrings by volume
+		{"btc", []string{"BTCUSDT", "BTCDOMUSDT", "WBTCUSDT", "ETHBTC"}},
+		{"BTCUSDT", []string{"BTCUSDT", "WBTCUSDT", "BTCDOMUSDT"}},
+		// Name prefixes come after symbol prefixes
+		{"eth", []string{"ETHUSDT", "ETHBTC"}},
+		{"wrapped", []string{"WBTCUSDT"}},
+		// Letters in order match fuzzily
+		{"btusd", []string{"BTCUSDT", "WBTCUSDT", "BTCDOMUSDT"}},
+		{"xrp", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches, err := service.Search(ctx, tt.query, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, symbols(matches))
+		})
+	}
+
+	matches, err := service.Search(ctx, "btc", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "BTCDOMUSDT"}, symbols(matches))
+	assert.Equal(t, 900.0, matches[0].Volume24h)
+}
+
+func TestSymbolSearchService_CachesIndex(t *testing.T) {
+	service, cryptoRepo := newSymbolSearchService(t)
+	ctx := context.Background()
+
+	for _, query := range []string{"b", "bt", "btc", "btc"} {
+		_, err := service.Search(ctx, query, 10)
+		require.NoError(t, err)
+	}
+	cryptoRepo.AssertNumberOfCalls(t, "GetActive", 1)
+}
+
+func TestSymbolSearchService_IndexFailure(t *testing.T) {
+	cryptoRepo := new(testutils.MockCryptoCurrencyRepository)
+	cryptoRepo.On("GetActive", mock.Anything, mock.Anything, 0).Return([]entities.CryptoCurrency{}, errors.New("db down"))
+
+	service := services.NewSymbolSearchService(cryptoRepo, new(testutils.MockPriceHistoryRepository), logrus.New())
+	_, err := service.Search(context.Background(), "btc", 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTechnicalIndicatorService_LatestIndicatorsIncludeEveryStoredSeries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	indicatorRepo := testutils.NewMemoryTechnicalIndicatorRepository()
+	store := func(indicatorType, key string, value float64, timestamp time.Time) {
+		require.NoError(t, indicatorRepo.Create(ctx, &entities.TechnicalIndicator{
+			Symbol: "BTCUSDT", Timeframe: "1h", IndicatorType: indicatorType, IndicatorKey: key, Value: &value, Timestamp: timestamp,
+		}))
+	}
+	store("MACD", "MACD_12_26_9", 1.5, now)
+	store("MACD", "MACD_12_26_9", 2.5, now.Add(time.Hour))
+	// Series no built-in calculation stores are listed too
+	store("ATR", "ATR_14", 320, now)
+	store("VolumeProfile", "VolumeProfile_24", 64000, now)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := services.NewTechnicalIndicatorService(testutils.NewMemoryPriceHistoryRepository(), indicatorRepo, logger)
+
+	latest, err := service.GetLatestIndicators(ctx, "BTCUSDT", "1h")
+	require.NoError(t, err)
+	assert.Len(t, latest, 3)
+	assert.Equal(t, 2.5, *latest["MACD_12_26_9"].Value)
+	assert.Equal(t, 320.0, *latest["ATR_14"].Value)
+	assert.Equal(t, 64000.0, *latest["VolumeProfile_24"].Value)
+
+	latest, err = service.GetLatestIndicators(ctx, "BTCUSDT", "4h")
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+}
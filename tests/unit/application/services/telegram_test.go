@@ -0,0 +1,204 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeTelegram is a Bot API answering sendMessage and getMe; chats in blocked
+// answer 403 like a chat that blocked the bot
+type fakeTelegram struct {
+	mu      sync.Mutex
+	blocked map[string]bool
+	sent    []map[string]interface{}
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/bottest-token/getMe":
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"priceguard_bot"}}`))
+	case "/bottest-token/sendMessage":
+		var params map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&params)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.blocked[params["chat_id"].(string)] {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		f.sent = append(f.sent, params)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	default:
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+	}
+}
+
+func newTestTelegramBot(t *testing.T, fake *fakeTelegram) *services.TelegramBot {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	bot := services.NewTelegramBot("test-token")
+	bot.SetBaseURL(server.URL)
+	return bot
+}
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func alertNotification(userID uuid.UUID) *services.QueuedNotification {
+	return &services.QueuedNotification{
+		ID:      uuid.New(),
+		UserID:  userID,
+		Type:    "alert_triggered",
+		Title:   "Price Alert Triggered",
+		Message: "Your alert for BTCUSDT has been triggered.",
+		Data: map[string]interface{}{
+			"symbol":        "BTCUSDT",
+			"alert_type":    "price",
+			"condition":     "above",
+			"target_value":  50000.0,
+			"current_value": 51000.5,
+			"timeframe":     "1h",
+		},
+	}
+}
+
+func TestFormatTelegramMessage(t *testing.T) {
+	message := services.FormatTelegramMessage(alertNotification(uuid.New()))
+	assert.Equal(t, "<b>Price Alert Triggered</b>\n\n<b>BTCUSDT</b> · 1h\nCondition: price above 50000\nCurrent price: <b>51000.5</b>", message)
+
+	message = services.FormatTelegramMessage(&services.QueuedNotification{Type: "system", Title: "Maintenance", Message: "Back at 10:00 <UTC>"})
+	assert.Equal(t, "<b>Maintenance</b>\nBack at 10:00 &lt;UTC&gt;", message)
+}
+
+func TestTelegramProvider_SendsToLinkedChat(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	links := testutils.NewMemoryTelegramLinkRepository()
+	require.NoError(t, links.Save(ctx, &entities.TelegramLink{UserID: userID, ChatID: "42"}))
+
+	fake := &fakeTelegram{}
+	provider := services.NewTelegramProvider(newTestTelegramBot(t, fake), links, quietLogger())
+
+	require.NoError(t, provider.Send(ctx, alertNotification(userID)))
+	require.Len(t, fake.sent, 1)
+	assert.Equal(t, "42", fake.sent[0]["chat_id"])
+	assert.Equal(t, "HTML", fake.sent[0]["parse_mode"])
+	assert.Contains(t, fake.sent[0]["text"], "Current price: <b>51000.5</b>")
+
+	// Users who never linked a chat are skipped
+	assert.NoError(t, provider.Send(ctx, alertNotification(uuid.New())))
+	assert.Len(t, fake.sent, 1)
+	assert.NoError(t, provider.HealthCheck(ctx))
+}
+
+func TestTelegramProvider_UnlinksBlockedChat(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	links := testutils.NewMemoryTelegramLinkRepository()
+	require.NoError(t, links.Save(ctx, &entities.TelegramLink{UserID: userID, ChatID: "42"}))
+
+	fake := &fakeTelegram{blocked: map[string]bool{"42": true}}
+	provider := services.NewTelegramProvider(newTestTelegramBot(t, fake), links, quietLogger())
+
+	require.NoError(t, provider.Send(ctx, alertNotification(userID)))
+	_, err := links.GetByUserID(ctx, userID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func startUpdate(chatID int64, text string) *services.TelegramUpdate {
+	var update services.TelegramUpdate
+	json.Unmarshal([]byte(fmt.Sprintf(
+		`{"update_id":1,"message":{"text":%q,"chat":{"id":%d,"type":"private","username":"satoshi"}}}`,
+		text, chatID)), &update)
+	return &update
+}
+
+func TestTelegramLinkService_LinksChatWithCode(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	links := testutils.NewMemoryTelegramLinkRepository()
+	fake := &fakeTelegram{}
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	service := services.NewTelegramLinkService(links, newTestTelegramBot(t, fake), "@priceguard_bot", quietLogger())
+	service.SetClock(clock)
+
+	code, err := service.CreateLinkCode(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://t.me/priceguard_bot?start="+code.Code, code.URL)
+	assert.Equal(t, clock.Now().Add(services.TelegramLinkCodeTTL), code.ExpiresAt)
+
+	pending, err := service.GetLink(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, pending.IsLinked())
+	assert.NotContains(t, pending.CodeHash, code.Code, "only the hash of the code is stored")
+
+	require.NoError(t, service.HandleUpdate(ctx, startUpdate(42, "/start "+code.Code)))
+
+	link, err := service.GetLink(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, link.IsLinked())
+	assert.Equal(t, "42", link.ChatID)
+	assert.Equal(t, "satoshi", link.Username)
+	assert.Empty(t, link.CodeHash)
+	require.Len(t, fake.sent, 1)
+	assert.Contains(t, fake.sent[0]["text"], "Connected")
+
+	// The code is single use
+	require.NoError(t, service.HandleUpdate(ctx, startUpdate(99, "/start "+code.Code)))
+	link, _ = service.GetLink(ctx, userID)
+	assert.Equal(t, "42", link.ChatID)
+
+	require.NoError(t, service.Unlink(ctx, userID))
+	assert.ErrorIs(t, service.Unlink(ctx, userID), services.ErrTelegramLinkNotFound)
+}
+
+func TestTelegramLinkService_RejectsExpiredCode(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	links := testutils.NewMemoryTelegramLinkRepository()
+	fake := &fakeTelegram{}
+	clock := testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	service := services.NewTelegramLinkService(links, newTestTelegramBot(t, fake), "priceguard_bot", quietLogger())
+	service.SetClock(clock)
+
+	code, err := service.CreateLinkCode(ctx, userID)
+	require.NoError(t, err)
+	clock.Advance(services.TelegramLinkCodeTTL)
+
+	require.NoError(t, service.HandleUpdate(ctx, startUpdate(42, "/start "+code.Code)))
+	link, err := service.GetLink(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, link.IsLinked())
+	require.Len(t, fake.sent, 1)
+	assert.Contains(t, fake.sent[0]["text"], "expired")
+}
+
+func TestTelegramLinkService_RequiresBot(t *testing.T) {
+	service := services.NewTelegramLinkService(testutils.NewMemoryTelegramLinkRepository(), nil, "", quietLogger())
+
+	_, err := service.CreateLinkCode(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, services.ErrTelegramNotConfigured)
+}
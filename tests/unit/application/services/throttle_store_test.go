@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryThrottleStore_AcquireExpires(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	store := services.NewMemoryThrottleStore()
+	store.SetClock(fakeClock)
+
+	acquired, err := store.Acquire(ctx, "alert:1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, _ = store.Acquire(ctx, "alert:1", time.Minute)
+	assert.False(t, acquired)
+	active, _ := store.Active(ctx, "alert:1")
+	assert.True(t, active)
+	count, _ := store.Count(ctx, "alert:")
+	assert.Equal(t, 1, count)
+
+	fakeClock.Advance(time.Minute)
+	active, _ = store.Active(ctx, "alert:1")
+	assert.False(t, active)
+	acquired, _ = store.Acquire(ctx, "alert:1", time.Minute)
+	assert.True(t, acquired)
+}
+
+func TestRedisThrottleStore_OnlyOneInstanceAcquires(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	regionA := services.NewRedisThrottleStore(client, "us-east-1a")
+	regionB := services.NewRedisThrottleStore(client, "eu-west-1a")
+
+	acquired, err := regionA.Acquire(ctx, "alert:1", 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = regionB.Acquire(ctx, "alert:1", 5*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	active, err := regionB.Active(ctx, "alert:1")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	_, err = regionB.Acquire(ctx, "abuse:1", time.Minute)
+	require.NoError(t, err)
+	count, err := regionA.Count(ctx, "alert:")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	server.FastForward(5 * time.Minute)
+	acquired, err = regionB.Acquire(ctx, "alert:1", 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
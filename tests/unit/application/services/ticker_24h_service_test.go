@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTickerSource returns the same 24h statistics on every request
+type fakeTickerSource struct {
+	tickers []external.Ticker24h
+}
+
+func (f *fakeTickerSource) GetAllTickers24h(ctx context.Context) ([]external.Ticker24h, error) {
+	return f.tickers, nil
+}
+
+func TestTicker24hService_RefreshesAndTriggersChange24hAlerts(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repos := testutils.NewMemoryRepositories()
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	clock := testutils.NewFakeClock(now)
+	ctx := context.Background()
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		require.NoError(t, repos.Cryptos.Create(ctx, &entities.CryptoCurrency{Symbol: symbol, Name: symbol, Active: true}))
+	}
+	source := &fakeTickerSource{tickers: []external.Ticker24h{
+		{Symbol: "BTCUSDT", LastPrice: "42000", PriceChange: "2000", PriceChangePercent: "5.000", Volume: "1000", QuoteVolume: "42000000", CloseTime: now.UnixMilli()},
+		{Symbol: "ETHUSDT", LastPrice: "bad", PriceChange: "0", PriceChangePercent: "0", Volume: "0", QuoteVolume: "0"},
+		{Symbol: "XRPUSDT", LastPrice: "0.5", PriceChange: "0", PriceChangePercent: "0", Volume: "0", QuoteVolume: "0"},
+	}}
+
+	service := services.NewTicker24hService(source, repos.Cryptos, logger)
+	service.SetClock(clock)
+	run, err := service.RefreshOnce(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, &services.Ticker24hRun{Symbols: 3, Updated: 1, Missing: 1, Failed: 1}, run)
+
+	btc, err := repos.Cryptos.GetBySymbol(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, btc.PriceChangePercent)
+	assert.Equal(t, 42000000.0, btc.QuoteVolume)
+	require.NotNil(t, btc.Ticker24h.RefreshedAt)
+	assert.True(t, now.Equal(*btc.Ticker24h.RefreshedAt))
+
+	engine := services.NewAlertEngine(repos.Alerts, repos.PriceHistory, repos.TechnicalIndicators, repos.Notifications, nil, logger)
+	engine.SetClock(clock)
+	engine.SetMaxDataAge(5 * time.Minute)
+	engine.SetCryptoCurrencyRepository(repos.Cryptos)
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+		require.NoError(t, repos.PriceHistory.Create(ctx, &entities.PriceHistory{Symbol: symbol, Timeframe: "1h", ClosePrice: 42000, Timestamp: now.Truncate(time.Hour)}))
+	}
+	change24h := func(symbol, condition string, target float64) *entities.Alert {
+		alert := &entities.Alert{ID: uuid.New(), UserID: uuid.New(), Symbol: symbol, AlertType: "change_24h", ConditionType: condition, TargetValue: target, Timeframe: "1h", Enabled: true}
+		require.NoError(t, alert.Validate())
+		return alert
+	}
+
+	result, err := engine.EvaluateAlert(ctx, change24h("BTCUSDT", "up", 4))
+	require.NoError(t, err)
+	assert.True(t, result.ShouldTrigger)
+	assert.Equal(t, 5.0, result.CurrentValue)
+	assert.Equal(t, 42000000.0, result.Context["quote_volume"])
+
+	result, err = engine.EvaluateAlert(ctx, change24h("BTCUSDT", "down", 4))
+	require.NoError(t, err)
+	assert.False(t, result.ShouldTrigger)
+
+	// Statistics never collected, or no longer refreshed, aren't evaluated
+	_, err = engine.EvaluateAlert(ctx, change24h("ETHUSDT", "up", 4))
+	assert.ErrorIs(t, err, entities.ErrInsufficientData)
+	clock.Advance(10 * time.Minute)
+	_, err = engine.EvaluateAlert(ctx, change24h("BTCUSDT", "up", 4))
+	assert.ErrorIs(t, err, entities.ErrStaleData)
+}
@@ -0,0 +1,57 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/growthfolio/go-priceguard-api/internal/application/services"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+func TestWatchlistTickerService_ToleratesCollectionJitter(t *testing.T) {
+	repos := testutils.NewMemoryRepositories()
+	service := services.NewWatchlistTickerService(repos.PriceHistory, repos.UserSettings)
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	// Collected slightly less than a minute apart
+	storeMinuteCandle(t, repos.PriceHistory, "SOLUSDT", now.Add(-58*time.Second), 200, 200, 200, 200, 0)
+	storeMinuteCandle(t, repos.PriceHistory, "SOLUSDT", now, 202, 202, 202, 202, 0)
+
+	ticks, err := service.Ticks(context.Background(), []string{"SOLUSDT", "SOLUSDT", "XRPUSDT"})
+	require.NoError(t, err)
+	require.Len(t, ticks, 1)
+
+	tick := ticks["SOLUSDT"]
+	assert.Equal(t, 202.0, tick.Price)
+	require.NotNil(t, tick.Change1m)
+	assert.Equal(t, 1.0, *tick.Change1m)
+	assert.Nil(t, tick.Change5m)
+	assert.Nil(t, tick.Change1h)
+}
+
+func TestWatchlistTickerService_WatchlistSymbols(t *testing.T) {
+	repos := testutils.NewMemoryRepositories()
+	service := services.NewWatchlistTickerService(repos.PriceHistory, repos.UserSettings)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	require.NoError(t, repos.UserSettings.Create(ctx, &entities.UserSettings{
+		UserID:          userID,
+		FavoriteSymbols: []string{"BTCUSDT", "ETHUSDT"},
+	}))
+
+	symbols, err := service.WatchlistSymbols(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, symbols)
+
+	// Users without settings have an empty watchlist
+	symbols, err = service.WatchlistSymbols(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, symbols)
+}
@@ -0,0 +1,132 @@
+package container_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apihttp "github.com/growthfolio/go-priceguard-api/internal/adapters/http"
+	"github.com/growthfolio/go-priceguard-api/internal/container"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/database"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+)
+
+// newDependencies loads a test configuration with env applied on top and connects
+// it to SQLite and miniredis
+func newDependencies(t *testing.T, env map[string]string) *container.Dependencies {
+	t.Helper()
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("JWT_SECRET", "container-secret")
+	t.Setenv("GOOGLE_CLIENT_ID", "container-client")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "container-client-secret")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	_, redisClient := testutils.StartMiniredis(t)
+	return &container.Dependencies{
+		Config:      cfg,
+		Logger:      logger,
+		DBManager:   database.NewManagerWithConnections(testutils.OpenSQLite(t), redisClient, logger),
+		RedisClient: redisClient,
+	}
+}
+
+func TestNew_BuildsEveryComponent(t *testing.T) {
+	app := container.New(newDependencies(t, nil))
+
+	// Optional components are left out without their configuration
+	assert.Nil(t, app.Faults)
+	assert.Nil(t, app.Storage)
+	assert.Nil(t, app.Handlers.Export)
+	assert.Nil(t, app.Handlers.Fault)
+	assert.Nil(t, app.Handlers.Sandbox)
+	assert.Nil(t, app.Handlers.Webhook)
+
+	for _, module := range []interface{}{app.Repositories, app.Services, app.Notifications, app.Realtime, app.Jobs, app.Handlers} {
+		value := reflect.ValueOf(module).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			name := value.Type().Name() + "." + value.Type().Field(i).Name
+			if name == "Handlers.Export" || name == "Handlers.Fault" || name == "Handlers.Sandbox" || name == "Handlers.Webhook" {
+				continue
+			}
+			assert.False(t, value.Field(i).IsZero(), "%s was not built", name)
+		}
+	}
+}
+
+func TestStartStop_WaitsForTheBackgroundWork(t *testing.T) {
+	app := container.New(newDependencies(t, map[string]string{
+		"SANDBOX_ENABLED":  "true",
+		"STORAGE_ENDPOINT": "http://127.0.0.1:1",
+		"STORAGE_BUCKET":   "priceguard",
+	}))
+	require.NotNil(t, app.Storage)
+
+	app.Start(context.Background())
+	assert.True(t, app.Services.CryptoData.IsCollecting())
+	assert.True(t, app.Jobs.AlertMonitor.IsRunning())
+
+	app.Stop()
+	assert.False(t, app.Services.CryptoData.IsCollecting())
+	assert.False(t, app.Jobs.AlertMonitor.IsRunning())
+
+	// Stopping again is a no-op
+	app.Stop()
+}
+
+func TestStart_DataCollectionAutoStartCanBeTurnedOff(t *testing.T) {
+	app := container.New(newDependencies(t, map[string]string{"PRICE_COLLECTION_AUTO_START": "false"}))
+
+	app.Start(context.Background())
+	defer app.Stop()
+	assert.False(t, app.Services.CryptoData.IsCollecting())
+	assert.True(t, app.Jobs.AlertMonitor.IsRunning())
+}
+
+func TestNew_FaultInjectionAddsAdminRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := container.New(newDependencies(t, map[string]string{"FAULT_INJECTION_ENABLED": "true"}))
+	require.NotNil(t, app.Faults)
+	require.NotNil(t, app.Handlers.Fault)
+
+	// Routes can be registered without starting the background services
+	router := gin.New()
+	apihttp.RegisterRoutes(router, app)
+
+	routes := make(map[string]bool)
+	for _, route := range router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes["GET /api/admin/faults"])
+	assert.True(t, routes["GET /ws"])
+	assert.True(t, routes["POST /api/alerts"])
+	assert.False(t, routes["POST /api/user/export"])
+	assert.False(t, routes["POST /api/auth/sandbox"])
+}
+
+func TestNew_SandboxAddsDemoSignIn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := container.New(newDependencies(t, map[string]string{"SANDBOX_ENABLED": "true"}))
+	require.NotNil(t, app.Handlers.Sandbox)
+
+	router := gin.New()
+	apihttp.RegisterRoutes(router, app)
+
+	routes := make(map[string]bool)
+	for _, route := range router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes["POST /api/auth/sandbox"])
+}
@@ -0,0 +1,28 @@
+package entities_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainError_MatchesKindThroughWrapping(t *testing.T) {
+	err := entities.NewDomainError(entities.ErrInsufficientData, "need %d candles, got %d", 14, 3)
+	wrapped := fmt.Errorf("failed to calculate RSI: %w", err)
+
+	assert.Equal(t, "need 14 candles, got 3", err.Error())
+	assert.ErrorIs(t, wrapped, entities.ErrInsufficientData)
+	assert.False(t, errors.Is(wrapped, entities.ErrNotFound))
+
+	var domainErr *entities.DomainError
+	assert.True(t, errors.As(wrapped, &domainErr))
+	assert.Equal(t, "need 14 candles, got 3", domainErr.Message)
+}
+
+func TestFavoriteErrors_HaveKinds(t *testing.T) {
+	assert.ErrorIs(t, entities.ErrFavoriteExists, entities.ErrConflict)
+	assert.ErrorIs(t, entities.ErrFavoriteNotFound, entities.ErrNotFound)
+}
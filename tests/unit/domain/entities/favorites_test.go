@@ -0,0 +1,78 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserSettings_AddFavorite(t *testing.T) {
+	settings := &entities.UserSettings{FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT"}}
+
+	require.NoError(t, settings.AddFavorite("adausdt", "Cardano", nil))
+	position := 0
+	require.NoError(t, settings.AddFavorite("SOLUSDT", "", &position))
+
+	assert.Equal(t, pq.StringArray{"SOLUSDT", "BTCUSDT", "ETHUSDT", "ADAUSDT"}, settings.FavoriteSymbols)
+	assert.Equal(t, "Cardano", settings.FavoriteLabels["ADAUSDT"])
+	assert.ErrorIs(t, settings.AddFavorite("btcusdt", "", nil), entities.ErrFavoriteExists)
+}
+
+func TestUserSettings_UpdateFavorite(t *testing.T) {
+	settings := &entities.UserSettings{FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT", "ADAUSDT"}}
+
+	label := "Bitcoin"
+	position := 10
+	require.NoError(t, settings.UpdateFavorite("btcusdt", &label, &position))
+	assert.Equal(t, pq.StringArray{"ETHUSDT", "ADAUSDT", "BTCUSDT"}, settings.FavoriteSymbols)
+	assert.Equal(t, []entities.FavoriteSymbol{
+		{Symbol: "ETHUSDT", Position: 0},
+		{Symbol: "ADAUSDT", Position: 1},
+		{Symbol: "BTCUSDT", Label: "Bitcoin", Position: 2},
+	}, settings.Favorites())
+
+	empty := ""
+	require.NoError(t, settings.UpdateFavorite("BTCUSDT", &empty, nil))
+	assert.NotContains(t, settings.FavoriteLabels, "BTCUSDT")
+
+	assert.ErrorIs(t, settings.UpdateFavorite("XRPUSDT", &label, nil), entities.ErrFavoriteNotFound)
+}
+
+func TestUserSettings_RemoveFavorite(t *testing.T) {
+	settings := &entities.UserSettings{
+		FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT"},
+		FavoriteLabels:  map[string]string{"BTCUSDT": "Bitcoin"},
+	}
+
+	require.NoError(t, settings.RemoveFavorite("BTCUSDT"))
+	assert.Equal(t, pq.StringArray{"ETHUSDT"}, settings.FavoriteSymbols)
+	assert.Empty(t, settings.FavoriteLabels)
+	assert.ErrorIs(t, settings.RemoveFavorite("BTCUSDT"), entities.ErrFavoriteNotFound)
+}
+
+func TestUserSettings_ReorderFavorites(t *testing.T) {
+	settings := &entities.UserSettings{FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT", "ADAUSDT"}}
+
+	require.NoError(t, settings.ReorderFavorites([]string{"adausdt", "BTCUSDT", "ETHUSDT"}))
+	assert.Equal(t, pq.StringArray{"ADAUSDT", "BTCUSDT", "ETHUSDT"}, settings.FavoriteSymbols)
+
+	var validationErr *entities.ValidationError
+	assert.ErrorAs(t, settings.ReorderFavorites([]string{"BTCUSDT", "ETHUSDT"}), &validationErr)
+	assert.ErrorAs(t, settings.ReorderFavorites([]string{"BTCUSDT", "BTCUSDT", "ETHUSDT"}), &validationErr)
+	assert.Equal(t, pq.StringArray{"ADAUSDT", "BTCUSDT", "ETHUSDT"}, settings.FavoriteSymbols)
+}
+
+func TestUserSettings_SetFavoriteSymbolsPrunesLabels(t *testing.T) {
+	settings := &entities.UserSettings{
+		FavoriteSymbols: pq.StringArray{"BTCUSDT", "ETHUSDT"},
+		FavoriteLabels:  map[string]string{"BTCUSDT": "Bitcoin", "ETHUSDT": "Ether"},
+	}
+
+	settings.SetFavoriteSymbols([]string{" ethusdt ", "ADAUSDT"})
+
+	assert.Equal(t, pq.StringArray{"ETHUSDT", "ADAUSDT"}, settings.FavoriteSymbols)
+	assert.Equal(t, map[string]string{"ETHUSDT": "Ether"}, settings.FavoriteLabels)
+}
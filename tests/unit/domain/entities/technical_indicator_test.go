@@ -319,3 +319,10 @@ func TestTechnicalIndicator_RequiredFields(t *testing.T) {
 	assert.NotZero(t, indicator.Timestamp)
 	// Value can be nil in some cases, so we don't require it
 }
+
+func TestIndicatorKey(t *testing.T) {
+	assert.Equal(t, "EMA_12", entities.IndicatorKey("EMA", 12))
+	assert.Equal(t, "EMA_26", entities.IndicatorKey("EMA", 26))
+	assert.Equal(t, "SuperTrend_10_3", entities.IndicatorKey("SuperTrend", 10, 3.0))
+	assert.Equal(t, "BB_Upper_20_2.5", entities.IndicatorKey("BB_Upper", 20, 2.5))
+}
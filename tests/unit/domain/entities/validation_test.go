@@ -0,0 +1,223 @@
+package entities_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func validAlert() entities.Alert {
+	return entities.Alert{
+		UserID:        uuid.New(),
+		Symbol:        "BTCUSDT",
+		AlertType:     "price",
+		ConditionType: "above",
+		TargetValue:   50000.0,
+		Timeframe:     "1h",
+		NotifyVia:     pq.StringArray{"app", "email"},
+	}
+}
+
+func compositeAlert(conditions ...entities.AlertCondition) entities.Alert {
+	alert := validAlert()
+	alert.AlertType = "composite"
+	alert.ConditionType = "and"
+	alert.TargetValue = 0
+	alert.Conditions = conditions
+	return alert
+}
+
+func leaf(alertType, conditionType string, target float64) entities.AlertCondition {
+	return entities.AlertCondition{AlertType: alertType, ConditionType: conditionType, TargetValue: target}
+}
+
+func group(operator string, conditions ...entities.AlertCondition) entities.AlertCondition {
+	return entities.AlertCondition{AlertType: "composite", ConditionType: operator, Conditions: conditions}
+}
+
+func TestAlert_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *entities.Alert)
+		field  string
+	}{
+		{name: "valid alert", modify: func(a *entities.Alert) {}},
+		{name: "percentage up", modify: func(a *entities.Alert) { a.AlertType = "percentage"; a.ConditionType = "up"; a.TargetValue = 5 }},
+		{name: "ema cross down", modify: func(a *entities.Alert) { a.AlertType = "ema_cross"; a.ConditionType = "down"; a.TargetValue = 0 }},
+		{name: "missing user", modify: func(a *entities.Alert) { a.UserID = uuid.Nil }, field: "user_id"},
+		{name: "missing symbol", modify: func(a *entities.Alert) { a.Symbol = "" }, field: "symbol"},
+		{name: "volume spike", modify: func(a *entities.Alert) { a.AlertType = "volume"; a.ConditionType = "spike"; a.TargetValue = 3 }},
+		{name: "price crosses down", modify: func(a *entities.Alert) { a.ConditionType = "crosses_down" }},
+		{name: "unknown type", modify: func(a *entities.Alert) { a.AlertType = "funding_rate" }, field: "alert_type"},
+		{name: "condition not valid for type", modify: func(a *entities.Alert) { a.ConditionType = "up" }, field: "condition_type"},
+		{name: "non positive price", modify: func(a *entities.Alert) { a.TargetValue = 0 }, field: "target_value"},
+		{name: "volume spike of 1x", modify: func(a *entities.Alert) { a.AlertType = "volume"; a.ConditionType = "spike"; a.TargetValue = 1 }, field: "target_value"},
+		{name: "non positive volume", modify: func(a *entities.Alert) { a.AlertType = "volume"; a.ConditionType = "below"; a.TargetValue = 0 }, field: "target_value"},
+		{name: "rsi out of range", modify: func(a *entities.Alert) { a.AlertType = "rsi"; a.TargetValue = 120 }, field: "target_value"},
+		{name: "composite", modify: func(a *entities.Alert) { *a = compositeAlert(leaf("price", "above", 50000), leaf("rsi", "below", 30)) }},
+		{name: "composite with one condition", modify: func(a *entities.Alert) { *a = compositeAlert(leaf("price", "above", 50000)) }, field: "conditions"},
+		{name: "composite with invalid condition", modify: func(a *entities.Alert) { *a = compositeAlert(leaf("price", "above", 50000), leaf("rsi", "below", 120)) }, field: "conditions.target_value"},
+		{name: "composite nested too deep", modify: func(a *entities.Alert) {
+			deepest := group("or", leaf("price", "above", 1), leaf("price", "below", 2))
+			*a = compositeAlert(leaf("price", "above", 1), group("and", leaf("price", "above", 1), group("or", leaf("price", "above", 1), deepest)))
+		}, field: "conditions"},
+		{name: "composite with too many conditions", modify: func(a *entities.Alert) {
+			leaves := make([]entities.AlertCondition, 11)
+			for i := range leaves {
+				leaves[i] = leaf("price", "above", float64(i+1))
+			}
+			*a = compositeAlert(leaves...)
+		}, field: "conditions"},
+		{name: "conditions on a price alert", modify: func(a *entities.Alert) { a.Conditions = []entities.AlertCondition{leaf("rsi", "below", 30)} }, field: "conditions"},
+		{name: "unknown timeframe", modify: func(a *entities.Alert) { a.Timeframe = "2h" }, field: "timeframe"},
+		{name: "unknown channel", modify: func(a *entities.Alert) { a.NotifyVia = pq.StringArray{"pigeon"} }, field: "notify_via"},
+		{name: "urgent priority", modify: func(a *entities.Alert) { a.Priority = "urgent" }},
+		{name: "unknown priority", modify: func(a *entities.Alert) { a.Priority = "asap" }, field: "priority"},
+		{name: "hour cooldown", modify: func(a *entities.Alert) { a.CooldownSeconds = 3600 }},
+		{name: "cooldown under a minute", modify: func(a *entities.Alert) { a.CooldownSeconds = 30 }, field: "cooldown_seconds"},
+		{name: "negative cooldown", modify: func(a *entities.Alert) { a.CooldownSeconds = -60 }, field: "cooldown_seconds"},
+		{name: "cooldown over a week", modify: func(a *entities.Alert) { a.CooldownSeconds = 8 * 24 * 3600 }, field: "cooldown_seconds"},
+		{name: "ack every 30 minutes", modify: func(a *entities.Alert) { a.AckRequired, a.AckIntervalMinutes = true, 30 }},
+		{name: "negative ack interval", modify: func(a *entities.Alert) { a.AckIntervalMinutes = -5 }, field: "ack_interval_minutes"},
+		{name: "ack interval over a day", modify: func(a *entities.Alert) { a.AckIntervalMinutes = 25 * 60 }, field: "ack_interval_minutes"},
+		{name: "data up to 2 minutes old", modify: func(a *entities.Alert) { a.MaxDataAgeSeconds = 120 }},
+		{name: "max data age under 10 seconds", modify: func(a *entities.Alert) { a.MaxDataAgeSeconds = 5 }, field: "max_data_age_seconds"},
+		{name: "max data age over a day", modify: func(a *entities.Alert) { a.MaxDataAgeSeconds = 25 * 3600 }, field: "max_data_age_seconds"},
+		{name: "percentage over 30 days", modify: func(a *entities.Alert) {
+			a.AlertType, a.ConditionType, a.TargetValue, a.WindowDays = "percentage", "up", 5, 30
+		}},
+		{name: "window on a price alert", modify: func(a *entities.Alert) { a.WindowDays = 7 }, field: "window_days"},
+		{name: "percentage over two years", modify: func(a *entities.Alert) {
+			a.AlertType, a.ConditionType, a.TargetValue, a.WindowDays = "percentage", "up", 5, 730
+		}, field: "window_days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := validAlert()
+			tt.modify(&alert)
+
+			err := alert.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *entities.ValidationError
+			assert.True(t, errors.As(err, &validationErr))
+			assert.True(t, errors.Is(err, entities.ErrValidation))
+			assert.Equal(t, "alert", validationErr.Entity)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestNotification_Validate(t *testing.T) {
+	notification := entities.Notification{
+		UserID:           uuid.New(),
+		Title:            "Alert Triggered",
+		Message:          "BTCUSDT crossed 50000",
+		NotificationType: "alert_triggered",
+	}
+	assert.NoError(t, notification.Validate())
+
+	notification.Title = ""
+	err := notification.Validate()
+	assert.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), "title")
+
+	notification.Title = "Alert Triggered"
+	notification.NotificationType = ""
+	assert.ErrorIs(t, notification.Validate(), entities.ErrValidation)
+}
+
+func TestUserSettings_Validate(t *testing.T) {
+	settings := entities.UserSettings{
+		Theme:            "dark",
+		DefaultTimeframe: "4h",
+		DefaultView:      "overview",
+		RiskProfile:      "moderate",
+		FavoriteSymbols:  pq.StringArray{"BTCUSDT", "ETHUSDT"},
+		ReportFrequency:  "weekly",
+		ReportHour:       8,
+		Locale:           "pt-BR",
+	}
+	assert.NoError(t, settings.Validate())
+
+	invalid := []func(s *entities.UserSettings){
+		func(s *entities.UserSettings) { s.Theme = "neon" },
+		func(s *entities.UserSettings) { s.DefaultTimeframe = "3m" },
+		func(s *entities.UserSettings) { s.RiskProfile = "yolo" },
+		func(s *entities.UserSettings) { s.FavoriteSymbols = pq.StringArray{""} },
+		func(s *entities.UserSettings) { s.FavoriteLabels = map[string]string{"ADAUSDT": "Cardano"} },
+		func(s *entities.UserSettings) {
+			s.FavoriteLabels = map[string]string{"BTCUSDT": strings.Repeat("x", 33)}
+		},
+		func(s *entities.UserSettings) { s.ReportFrequency = "hourly" },
+		func(s *entities.UserSettings) { s.ReportHour = 24 },
+		func(s *entities.UserSettings) { s.Locale = "pt_br" },
+	}
+	for _, modify := range invalid {
+		copied := settings
+		modify(&copied)
+		assert.ErrorIs(t, copied.Validate(), entities.ErrValidation)
+	}
+}
+
+func TestSystemBanner_Validate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	banner := entities.SystemBanner{
+		Title:     "Scheduled maintenance",
+		Message:   "Alerts may be delayed for 30 minutes",
+		Severity:  "warning",
+		ExpiresAt: now.Add(2 * time.Hour),
+	}
+	assert.NoError(t, banner.Validate(now))
+
+	invalid := []func(b *entities.SystemBanner){
+		func(b *entities.SystemBanner) { b.Title = "" },
+		func(b *entities.SystemBanner) { b.Message = "" },
+		func(b *entities.SystemBanner) { b.Severity = "panic" },
+		func(b *entities.SystemBanner) { b.ExpiresAt = now },
+		func(b *entities.SystemBanner) { b.ExpiresAt = now.Add(8 * 24 * time.Hour) },
+	}
+	for _, modify := range invalid {
+		copied := banner
+		modify(&copied)
+		assert.ErrorIs(t, copied.Validate(now), entities.ErrValidation)
+	}
+}
+
+func TestBasket_Validate(t *testing.T) {
+	basket := entities.Basket{
+		UserID: uuid.New(),
+		Name:   "Majors",
+		Components: []entities.BasketComponent{
+			{Symbol: "BTCUSDT", Weight: 50},
+			{Symbol: "ETHUSDT", Weight: 30},
+			{Symbol: "SOLUSDT", Weight: 20},
+		},
+	}
+	assert.NoError(t, basket.Validate())
+
+	invalid := []func(b *entities.Basket){
+		func(b *entities.Basket) { b.Name = "" },
+		func(b *entities.Basket) { b.Components = b.Components[:1] },
+		func(b *entities.Basket) { b.Components[2].Weight = 10 },
+		func(b *entities.Basket) { b.Components[1].Weight, b.Components[2].Weight = 60, -10 },
+		func(b *entities.Basket) { b.Components[2].Symbol = "BTCUSDT" },
+		func(b *entities.Basket) { b.Components[2].Symbol = entities.BasketSymbol(uuid.New()) },
+	}
+	for _, modify := range invalid {
+		copied := basket
+		copied.Components = append([]entities.BasketComponent(nil), basket.Components...)
+		modify(&copied)
+		assert.ErrorIs(t, copied.Validate(), entities.ErrValidation)
+	}
+}
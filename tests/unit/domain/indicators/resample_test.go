@@ -0,0 +1,65 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/indicators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResample_AggregatesOHLCVIntoAlignedBuckets(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 58, 0, 0, time.UTC)
+	var candles []entities.PriceHistory
+	for i, price := range []float64{100, 101, 99, 104, 102} {
+		candles = append(candles, entities.PriceHistory{
+			Symbol:     "BTCUSDT",
+			Timeframe:  "1m",
+			Timestamp:  start.Add(time.Duration(i) * time.Minute),
+			OpenPrice:  price - 0.5,
+			HighPrice:  price + 1,
+			LowPrice:   price - 1,
+			ClosePrice: price,
+			Volume:     2,
+		})
+	}
+
+	hourly := indicators.Resample(candles, "1h")
+	require.Len(t, hourly, 2)
+
+	assert.Equal(t, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), hourly[0].Timestamp)
+	assert.Equal(t, entities.PriceHistory{
+		Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: hourly[0].Timestamp,
+		OpenPrice: 99.5, HighPrice: 102, LowPrice: 99, ClosePrice: 101, Volume: 4,
+	}, hourly[0])
+	assert.Equal(t, entities.PriceHistory{
+		Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		OpenPrice: 98.5, HighPrice: 105, LowPrice: 98, ClosePrice: 102, Volume: 6,
+	}, hourly[1])
+
+	assert.Len(t, indicators.Resample(candles, "1d"), 1)
+	assert.Nil(t, indicators.Resample(candles, "3m"))
+	assert.Nil(t, indicators.Resample(nil, "1h"))
+}
+
+func TestParseTimeframe(t *testing.T) {
+	assert.Equal(t, int64(2*60*60*1000), indicators.ParseTimeframe("2h"))
+	assert.Equal(t, int64(90*60*1000), indicators.ParseTimeframe("90m"))
+	assert.Equal(t, int64(3*24*60*60*1000), indicators.ParseTimeframe("3d"))
+	assert.Equal(t, indicators.GetTimeframeMilliseconds("1w"), indicators.ParseTimeframe("1w"))
+	for _, timeframe := range []string{"", "h", "0h", "02h", "-1h", "1.5h", "2y", "1M", "5000m"} {
+		assert.Zero(t, indicators.ParseTimeframe(timeframe), timeframe)
+	}
+
+	candles := []entities.PriceHistory{
+		{Timestamp: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), OpenPrice: 1, HighPrice: 2, LowPrice: 1, ClosePrice: 2},
+		{Timestamp: time.Date(2024, 5, 1, 9, 2, 0, 0, time.UTC), OpenPrice: 2, HighPrice: 3, LowPrice: 2, ClosePrice: 3},
+		{Timestamp: time.Date(2024, 5, 1, 9, 3, 0, 0, time.UTC), OpenPrice: 3, HighPrice: 4, LowPrice: 3, ClosePrice: 4},
+	}
+	resampled := indicators.ResampleCustom(candles, "3m")
+	require.Len(t, resampled, 2)
+	assert.Equal(t, 3.0, resampled[0].ClosePrice)
+	assert.Equal(t, "3m", resampled[1].Timeframe)
+}
@@ -0,0 +1,138 @@
+package anonymize_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/anonymize"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// seedProduction stores a user with every kind of personal data and returns its ID
+func seedProduction(t *testing.T, db *gorm.DB) uuid.UUID {
+	t.Helper()
+	picture := "https://lh3.googleusercontent.com/a/photo"
+	user := &entities.User{ID: uuid.New(), GoogleID: "google-123", Email: "Jane.Doe@example.com", Name: "Jane Doe", Picture: &picture}
+	alert := &entities.Alert{ID: uuid.New(), UserID: user.ID, Symbol: "BTCUSDT", AlertType: "price", ConditionType: "above", TargetValue: 70000, Timeframe: "1h", Enabled: true}
+
+	for _, row := range []interface{}{
+		user,
+		&entities.UserSettings{ID: uuid.New(), UserID: user.ID, Theme: "dark"},
+		alert,
+		&entities.Notification{ID: uuid.New(), UserID: user.ID, AlertID: &alert.ID, Title: "Price Alert Triggered", Message: "BTCUSDT above 70000", NotificationType: "alert_triggered"},
+		&entities.DeviceToken{ID: uuid.New(), UserID: user.ID, Token: "fcm-token", Platform: "ios", Name: "Jane's iPhone"},
+		&entities.TelegramLink{UserID: user.ID, ChatID: "123456789", Username: "janedoe"},
+		&entities.APIKey{ID: uuid.New(), UserID: user.ID, Name: "bot", Prefix: "pg_abcd", KeyHash: "real-key-hash"},
+		&entities.Session{ID: uuid.New(), UserID: user.ID, TokenHash: "session-hash", ExpiresAt: time.Now().Add(time.Hour)},
+		&entities.PriceHistory{Symbol: "BTCUSDT", Timeframe: "1h", OpenPrice: 1, HighPrice: 1, LowPrice: 1, ClosePrice: 1, Volume: 1, Timestamp: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		require.NoError(t, db.Create(row).Error)
+	}
+	return user.ID
+}
+
+func TestCloner_PseudonymizesPersonalDataAndKeepsReferences(t *testing.T) {
+	ctx := context.Background()
+	source, target := testutils.OpenSQLite(t), testutils.OpenSQLite(t)
+	userID := seedProduction(t, source)
+
+	pseudo := anonymize.NewPseudonymizer([]byte("staging-secret"))
+	cloner := anonymize.NewCloner(source, target, pseudo, anonymize.Options{BatchSize: 2, MarketData: true}, quietLogger())
+
+	counts, err := cloner.Run(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, counts, anonymize.TableCount{Table: "users", Rows: 1})
+	assert.Contains(t, counts, anonymize.TableCount{Table: "price_histories", Rows: 1})
+
+	var user entities.User
+	require.NoError(t, target.First(&user, "id = ?", userID).Error)
+	assert.Equal(t, pseudo.Email("jane.doe@EXAMPLE.com"), user.Email)
+	assert.True(t, strings.HasSuffix(user.Email, "@"+anonymize.PseudonymDomain))
+	assert.NotContains(t, user.Name, "Jane")
+	assert.NotEqual(t, "google-123", user.GoogleID)
+	assert.Nil(t, user.Picture)
+
+	// Children still point at the copied user
+	var alert entities.Alert
+	require.NoError(t, target.First(&alert, "user_id = ?", userID).Error)
+	var notification entities.Notification
+	require.NoError(t, target.First(&notification, "alert_id = ?", alert.ID).Error)
+
+	var device entities.DeviceToken
+	require.NoError(t, target.First(&device, "user_id = ?", userID).Error)
+	assert.NotEqual(t, "fcm-token", device.Token)
+	assert.Empty(t, device.Name)
+
+	var link entities.TelegramLink
+	require.NoError(t, target.First(&link, "user_id = ?", userID).Error)
+	assert.NotEqual(t, "123456789", link.ChatID)
+	assert.NotContains(t, link.Username, "jane")
+
+	var key entities.APIKey
+	require.NoError(t, target.First(&key, "user_id = ?", userID).Error)
+	assert.NotEqual(t, "real-key-hash", key.KeyHash)
+	assert.Len(t, key.Prefix, len("pg_abcd"))
+
+	var sessions int64
+	require.NoError(t, target.Model(&entities.Session{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+
+	// A second run with the same secret upserts the same rows
+	_, err = cloner.Run(ctx)
+	require.NoError(t, err)
+	var users, prices int64
+	require.NoError(t, target.Model(&entities.User{}).Count(&users).Error)
+	require.NoError(t, target.Model(&entities.PriceHistory{}).Count(&prices).Error)
+	assert.Equal(t, int64(1), users)
+	assert.Equal(t, int64(1), prices)
+}
+
+func TestCloner_TruncateRemovesStagingOnlyRows(t *testing.T) {
+	ctx := context.Background()
+	source, target := testutils.OpenSQLite(t), testutils.OpenSQLite(t)
+	seedProduction(t, source)
+
+	stagingOnly := &entities.User{ID: uuid.New(), GoogleID: "qa", Email: "qa@staging", Name: "QA"}
+	require.NoError(t, target.Create(stagingOnly).Error)
+	require.NoError(t, target.Create(&entities.Session{ID: uuid.New(), UserID: stagingOnly.ID, TokenHash: "qa", ExpiresAt: time.Now()}).Error)
+
+	cloner := anonymize.NewCloner(source, target, anonymize.NewPseudonymizer([]byte("s")), anonymize.Options{Truncate: true}, quietLogger())
+	counts, err := cloner.Run(ctx)
+	require.NoError(t, err)
+	for _, count := range counts {
+		assert.NotEqual(t, "price_histories", count.Table, "market data is opt-in")
+	}
+
+	var users, sessions int64
+	require.NoError(t, target.Model(&entities.User{}).Count(&users).Error)
+	require.NoError(t, target.Model(&entities.Session{}).Count(&sessions).Error)
+	assert.Equal(t, int64(1), users)
+	assert.Zero(t, sessions)
+	assert.ErrorIs(t, target.First(&entities.User{}, "id = ?", stagingOnly.ID).Error, gorm.ErrRecordNotFound)
+}
+
+func TestPseudonymizer_IsKeyedBySecret(t *testing.T) {
+	a, b := anonymize.NewPseudonymizer([]byte("a")), anonymize.NewPseudonymizer([]byte("b"))
+
+	assert.Equal(t, a.Email("x@example.com"), a.Email(" X@Example.com "))
+	assert.NotEqual(t, a.Email("x@example.com"), b.Email("x@example.com"))
+	assert.NotEqual(t, a.Email("x@example.com"), a.Email("y@example.com"))
+	assert.NotEqual(t, a.Token("google_id", "1"), a.Token("device_token", "1"))
+	assert.Empty(t, a.Email(""))
+	assert.Empty(t, a.Token("google_id", ""))
+}
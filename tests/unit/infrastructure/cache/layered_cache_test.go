@@ -8,6 +8,7 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/cache"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -95,3 +96,22 @@ func TestLayeredCache_PromotionFromRedis(t *testing.T) {
 	assert.Equal(t, int64(1), metrics.L2Stats.Hits)
 	assert.Equal(t, int64(0), metrics.L2Stats.Misses)
 }
+
+func TestMemoryCache_ExpiryUsesClock(t *testing.T) {
+	mc := cache.NewMemoryCache(10, time.Hour)
+	defer mc.Close()
+
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mc.SetClock(fakeClock)
+
+	assert.NoError(t, mc.Set("key", "value", time.Minute))
+
+	fakeClock.Advance(59 * time.Second)
+	value, found := mc.Get("key")
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+
+	fakeClock.Advance(2 * time.Second)
+	_, found = mc.Get("key")
+	assert.False(t, found)
+}
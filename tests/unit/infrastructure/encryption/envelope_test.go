@@ -0,0 +1,145 @@
+package encryption_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(seed byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune('a'+seed)), 32)))
+}
+
+func newEnvelope(t *testing.T, id string, seed byte, retired ...encryption.MasterKey) *encryption.Envelope {
+	t.Helper()
+	key, err := encryption.NewStaticMasterKey(id, testKey(seed))
+	require.NoError(t, err)
+	envelope, err := encryption.NewEnvelope(key, retired...)
+	require.NoError(t, err)
+	return envelope
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	envelope := newEnvelope(t, "primary", 0)
+	userID := uuid.New()
+
+	encrypted, err := envelope.Encrypt(ctx, userID, "+5511999990000")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "5511999990000")
+	assert.True(t, encryption.IsEncrypted(encrypted))
+
+	again, err := envelope.Encrypt(ctx, userID, "+5511999990000")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "every value gets its own data key and nonce")
+
+	decrypted, err := envelope.Decrypt(ctx, userID, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "+5511999990000", decrypted)
+}
+
+func TestEnvelope_BindsValuesToUser(t *testing.T) {
+	ctx := context.Background()
+	envelope := newEnvelope(t, "primary", 0)
+
+	encrypted, err := envelope.Encrypt(ctx, uuid.New(), "whsec_123")
+	require.NoError(t, err)
+
+	_, err = envelope.Decrypt(ctx, uuid.New(), encrypted)
+	assert.Error(t, err)
+}
+
+func TestEnvelope_EmptyValues(t *testing.T) {
+	ctx := context.Background()
+	envelope := newEnvelope(t, "primary", 0)
+
+	encrypted, err := envelope.Encrypt(ctx, uuid.New(), "")
+	require.NoError(t, err)
+	assert.Empty(t, encrypted)
+
+	decrypted, err := envelope.Decrypt(ctx, uuid.New(), "")
+	require.NoError(t, err)
+	assert.Empty(t, decrypted)
+}
+
+func TestEnvelope_RejectsPlaintextAndTamperedValues(t *testing.T) {
+	ctx := context.Background()
+	envelope := newEnvelope(t, "primary", 0)
+	userID := uuid.New()
+
+	assert.False(t, encryption.IsEncrypted("123456789"))
+	_, err := envelope.Decrypt(ctx, userID, "123456789")
+	assert.ErrorIs(t, err, encryption.ErrMalformedValue)
+
+	encrypted, err := envelope.Encrypt(ctx, userID, "123456789")
+	require.NoError(t, err)
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	_, err = envelope.Decrypt(ctx, userID, tampered)
+	assert.Error(t, err)
+}
+
+func TestEnvelope_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	old := newEnvelope(t, "2024-01", 0)
+	encrypted, err := old.Encrypt(ctx, userID, "chat-42")
+	require.NoError(t, err)
+
+	retired, err := encryption.NewStaticMasterKey("2024-01", testKey(0))
+	require.NoError(t, err)
+	rotated := newEnvelope(t, "2024-06", 1, retired)
+
+	assert.True(t, rotated.NeedsRotation(encrypted))
+	decrypted, err := rotated.Decrypt(ctx, userID, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-42", decrypted)
+
+	reencrypted, err := rotated.Encrypt(ctx, userID, decrypted)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(reencrypted))
+
+	// Without the retired key old values can no longer be read
+	_, err = newEnvelope(t, "2024-06", 1).Decrypt(ctx, userID, encrypted)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
+
+func TestNewStaticMasterKey_RejectsInvalidKeys(t *testing.T) {
+	_, err := encryption.NewStaticMasterKey("primary", "not base64!")
+	assert.Error(t, err)
+
+	_, err = encryption.NewStaticMasterKey("primary", base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
+
+func TestNewEnvelopeFromConfig(t *testing.T) {
+	_, err := encryption.NewEnvelopeFromConfig(&config.EncryptionConfig{})
+	assert.Error(t, err)
+
+	envelope, err := encryption.NewEnvelopeFromConfig(&config.EncryptionConfig{
+		MasterKey:   testKey(1),
+		MasterKeyID: "2024-06",
+		RetiredKeys: []string{"2024-01:" + testKey(0)},
+	})
+	require.NoError(t, err)
+
+	encrypted, err := newEnvelope(t, "2024-01", 0).Encrypt(context.Background(), uuid.Nil, "secret")
+	require.NoError(t, err)
+	decrypted, err := envelope.Decrypt(context.Background(), uuid.Nil, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	_, err = encryption.NewEnvelopeFromConfig(&config.EncryptionConfig{
+		MasterKey:   testKey(1),
+		MasterKeyID: "primary",
+		RetiredKeys: []string{"primary:" + testKey(0)},
+	})
+	assert.Error(t, err, "retired keys can't reuse the primary ID")
+}
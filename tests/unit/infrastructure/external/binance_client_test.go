@@ -0,0 +1,129 @@
+package external_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var binanceStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// binanceKline is a closed 1m kline stream message of the minute after binanceStart
+func binanceKline(minute int) string {
+	openTime := binanceStart.Add(time.Duration(minute) * time.Minute).UnixMilli()
+	return fmt.Sprintf(`{"stream":"btcusdt@kline_1m","data":{"e":"kline","E":%d,"s":"BTCUSDT","k":{"i":"1m","t":%d,"T":%d,"s":"BTCUSDT","o":"%d","c":"%d","h":"%d","l":"%d","v":"1","x":true}}}`,
+		openTime+60000, openTime, openTime+59999, 100+minute, 100+minute, 100+minute, 100+minute)
+}
+
+// fakeBinance drops the first WebSocket connection after one kline and serves the
+// minute klines missed meanwhile on the REST API
+type fakeBinance struct {
+	upgrader    websocket.Upgrader
+	mu          sync.Mutex
+	connections int
+	streams     []string
+	klineQuery  string
+}
+
+func (f *fakeBinance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v3/klines":
+		f.mu.Lock()
+		f.klineQuery = r.URL.RawQuery
+		f.mu.Unlock()
+
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+		var rows [][]interface{}
+		for minute := 0; minute < 10; minute++ {
+			openTime := binanceStart.Add(time.Duration(minute) * time.Minute).UnixMilli()
+			if openTime < start || openTime > end {
+				continue
+			}
+			price := fmt.Sprint(100 + minute)
+			rows = append(rows, []interface{}{openTime, price, price, price, price, "1", openTime + 59999})
+		}
+		json.NewEncoder(w).Encode(rows)
+	case "/stream":
+		conn, err := f.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		f.mu.Lock()
+		f.connections++
+		connection := f.connections
+		f.streams = append(f.streams, r.URL.Query().Get("streams"))
+		f.mu.Unlock()
+
+		if connection == 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte(binanceKline(0)))
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(binanceKline(3)))
+		conn.ReadMessage()
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBinanceClient_ReconnectsAndBackfillsMissedKlines(t *testing.T) {
+	fake := &fakeBinance{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	client := external.NewBinanceClient(&config.BinanceConfig{
+		BaseURL:   server.URL,
+		WSBaseURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+	}, logger)
+	client.SetReconnectBackoff(10*time.Millisecond, 50*time.Millisecond)
+	// Reconnected during the fourth minute: the second and third were missed
+	client.SetClock(testutils.NewFakeClock(binanceStart.Add(3*time.Minute + 30*time.Second)))
+	defer client.StopWebSocket()
+
+	streams := []string{client.GetKlineWebSocketStream("BTCUSDT", "1m"), client.GetTickerWebSocketStream("BTCUSDT")}
+	messages := client.SubscribeToStream(streams[0])
+	require.NoError(t, client.StartWebSocket(context.Background(), streams))
+
+	var closes []string
+	for len(closes) < 4 {
+		select {
+		case message := <-messages:
+			kline, err := external.ParseKlineMessage(message)
+			require.NoError(t, err)
+			assert.True(t, kline.Kline.IsClosed)
+			assert.Equal(t, "BTCUSDT", kline.Symbol)
+			closes = append(closes, kline.Kline.Close)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d klines", len(closes))
+		}
+	}
+
+	// The missed candles arrive in order before the live one
+	assert.Equal(t, []string{"100", "101", "102", "103"}, closes)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 2, fake.connections)
+	assert.Equal(t, []string{"btcusdt@kline_1m/btcusdt@ticker", "btcusdt@kline_1m/btcusdt@ticker"}, fake.streams)
+	assert.Contains(t, fake.klineQuery, "startTime="+strconv.FormatInt(binanceStart.Add(time.Minute).UnixMilli(), 10))
+}
@@ -0,0 +1,180 @@
+package external_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var coinbaseStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeCoinbase serves a BTC-USDT ticker and hourly or minute candles counted from
+// coinbaseStart, and records the requests
+type fakeCoinbase struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func newCoinbaseClient(t *testing.T, handler http.Handler) *external.CoinbaseClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return external.NewCoinbaseClient(&config.CoinbaseConfig{
+		BaseURL:   server.URL,
+		WSBaseURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+	}, logger)
+}
+
+func (f *fakeCoinbase) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests[r.URL.Path]++
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/products":
+		w.Write([]byte(`[{"id":"BTC-USDT","status":"online","trading_disabled":false},{"id":"LUNA-USD","status":"delisted","trading_disabled":true}]`))
+	case "/products/BTC-USDT/ticker":
+		w.Write([]byte(`{"price":"42000.5","time":"2024-01-01T12:00:00.123Z"}`))
+	case "/products/BTC-USDT/candles":
+		granularity := r.URL.Query().Get("granularity")
+		start, _ := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+		end, _ := time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+		step := time.Minute
+		if granularity == "3600" {
+			step = time.Hour
+		}
+
+		// Newest first, each [time, low, high, open, close, volume]
+		var rows [][]float64
+		for at := end.Truncate(step); !at.Before(start); at = at.Add(-step) {
+			i := float64(at.Sub(coinbaseStart) / step)
+			rows = append(rows, []float64{float64(at.Unix()), 100 + i - 1, 100 + i + 2, 100 + i, 100 + i + 1, 1})
+		}
+		json.NewEncoder(w).Encode(rows)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"NotFound"}`))
+	}
+}
+
+func TestCoinbaseClient_AggregatesKlinesOfIntervalsItLacks(t *testing.T) {
+	fake := &fakeCoinbase{requests: make(map[string]int)}
+	client := newCoinbaseClient(t, fake)
+	ctx := context.Background()
+
+	start := coinbaseStart.UnixMilli()
+	klines, err := client.GetKlines(ctx, "BTCUSDT", "4h", 3, &start, nil)
+	require.NoError(t, err)
+	require.Len(t, klines, 3)
+
+	// Each 4h kline is made of four hourly candles, in Binance's array format
+	assert.Equal(t, float64(coinbaseStart.Add(4*time.Hour).UnixMilli()), klines[1][0])
+	assert.Equal(t, []interface{}{"104", "109", "103", "108", "4"}, klines[1][1:6])
+	assert.Equal(t, float64(coinbaseStart.Add(8*time.Hour).UnixMilli()-1), klines[1][6])
+
+	// A thousand minutes take four more pages of 300 candles
+	end := coinbaseStart.Add(2 * 24 * time.Hour).UnixMilli()
+	klines, err = client.GetKlines(ctx, "BTCUSDT", "1m", 1000, nil, &end)
+	require.NoError(t, err)
+	require.Len(t, klines, 1000)
+	assert.Equal(t, float64(end), klines[999][0])
+	assert.Equal(t, 5, fake.requests["/products/BTC-USDT/candles"])
+
+	_, err = client.GetKlines(ctx, "BTCUSDT", "1M", 10, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestCoinbaseClient_MapsTickersAndProductsToBinanceSymbols(t *testing.T) {
+	client := newCoinbaseClient(t, &fakeCoinbase{requests: make(map[string]int)})
+	ctx := context.Background()
+
+	assert.Equal(t, "BTC-USDT", external.CoinbaseProductID("BTCUSDT"))
+	assert.Equal(t, "ETH-USD", external.CoinbaseProductID("ethusd"))
+	assert.Equal(t, "SOL-BTC", external.CoinbaseProductID("SOLBTC"))
+
+	ticker, err := client.GetTickerPrice(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", ticker.Symbol)
+	assert.Equal(t, "42000.5", ticker.Price)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 123000000, time.UTC), ticker.EventTime.UTC())
+
+	info, err := client.GetExchangeInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, info.Symbols, 2)
+	assert.Equal(t, "BTCUSDT", info.Symbols[0].Symbol)
+	assert.Equal(t, "TRADING", info.Symbols[0].Status)
+	assert.Equal(t, "BREAK", info.Symbols[1].Status)
+
+	_, err = client.GetTickerPrice(ctx, "DOGEUSDT")
+	assert.Error(t, err)
+}
+
+func TestCoinbaseClient_BuildsKlineStreamsFromTrades(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	client := newCoinbaseClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var subscribe struct {
+			Type       string   `json:"type"`
+			ProductIDs []string `json:"product_ids"`
+		}
+		if err := conn.ReadJSON(&subscribe); err != nil || subscribe.Type != "subscribe" || subscribe.ProductIDs[0] != "BTC-USDT" {
+			return
+		}
+		for _, trade := range []string{
+			`{"type":"subscriptions"}`,
+			`{"type":"ticker","product_id":"BTC-USDT","price":"100","last_size":"1","time":"2024-01-01T12:00:10Z"}`,
+			`{"type":"ticker","product_id":"BTC-USDT","price":"105","last_size":"2","time":"2024-01-01T12:00:40Z"}`,
+			`{"type":"ticker","product_id":"BTC-USDT","price":"103","last_size":"1","time":"2024-01-01T12:01:05Z"}`,
+		} {
+			conn.WriteMessage(websocket.TextMessage, []byte(trade))
+		}
+		conn.ReadMessage()
+	}))
+	defer client.StopWebSocket()
+
+	stream := client.GetKlineWebSocketStream("BTCUSDT", "1m")
+	messages := client.SubscribeToStream(stream)
+	require.NoError(t, client.StartWebSocket(context.Background(), []string{stream}))
+
+	var klines []*external.KlineWebSocketData
+	for len(klines) < 4 {
+		select {
+		case message := <-messages:
+			kline, err := external.ParseKlineMessage(message)
+			require.NoError(t, err)
+			klines = append(klines, kline)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d kline messages", len(klines))
+		}
+	}
+
+	// The first candle is reported closed when the next one's first trade arrives
+	closed := klines[2].Kline
+	assert.True(t, closed.IsClosed)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).UnixMilli(), closed.OpenTime)
+	assert.Equal(t, []string{"100", "105", "100", "105", "3"}, []string{closed.Open, closed.High, closed.Low, closed.Close, closed.Volume})
+	assert.False(t, klines[3].Kline.IsClosed)
+	assert.Equal(t, "103", klines[3].Kline.Open)
+	assert.Equal(t, "BTCUSDT", klines[3].Symbol)
+}
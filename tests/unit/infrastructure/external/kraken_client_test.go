@@ -0,0 +1,213 @@
+package external_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/external"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var krakenStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newKrakenClient(t *testing.T, handler http.Handler) *external.KrakenClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return external.NewKrakenClient(&config.KrakenConfig{
+		BaseURL:   server.URL,
+		WSBaseURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+	}, logger)
+}
+
+// fakeKraken serves XBTUSDT under its canonical name and hourly candles counted
+// from krakenStart
+func fakeKraken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	respond := func(result interface{}, errs ...string) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": append([]string{}, errs...), "result": result})
+	}
+
+	pair := r.URL.Query().Get("pair")
+	switch {
+	case r.URL.Path == "/0/public/AssetPairs":
+		respond(map[string]interface{}{
+			"XXBTZUSD": map[string]string{"altname": "XBTUSD", "wsname": "XBT/USD", "status": "online"},
+			"XDGUSDT":  map[string]string{"altname": "XDGUSDT", "wsname": "XDG/USDT", "status": "cancel_only"},
+		})
+	case r.URL.Path == "/0/public/SystemStatus":
+		respond(map[string]string{"status": "maintenance"})
+	case pair == "ETHUSDT":
+		respond(nil, "EService:Unavailable")
+	case pair != "XBTUSDT":
+		respond(nil, "EQuery:Unknown asset pair")
+	case r.URL.Path == "/0/public/Ticker":
+		respond(map[string]interface{}{"XBTUSDT": map[string][]string{"c": {"42000.5", "0.01"}}})
+	case r.URL.Path == "/0/public/OHLC":
+		since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+		var rows [][]interface{}
+		for i := int64(0); i < 24; i++ {
+			at := krakenStart.Add(time.Duration(i) * time.Hour).Unix()
+			if at <= since {
+				continue
+			}
+			price := func(offset int64) string { return fmt.Sprint(100 + i + offset) }
+			rows = append(rows, []interface{}{at, price(0), price(2), price(-1), price(1), price(0), "1", 5})
+		}
+		respond(map[string]interface{}{"XBTUSDT": rows, "last": krakenStart.Unix()})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestKrakenClient_MapsSymbolsToKrakenPairs(t *testing.T) {
+	client := newKrakenClient(t, http.HandlerFunc(fakeKraken))
+	ctx := context.Background()
+
+	assert.Equal(t, "XBTUSDT", external.KrakenPair("BTCUSDT"))
+	assert.Equal(t, "XDGUSD", external.KrakenPair("dogeusd"))
+	assert.Equal(t, "ETHXBT", external.KrakenPair("ETHBTC"))
+
+	ticker, err := client.GetTickerPrice(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", ticker.Symbol)
+	assert.Equal(t, "42000.5", ticker.Price)
+
+	info, err := client.GetExchangeInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, info.Symbols, 2)
+	assert.Equal(t, "BTCUSD", info.Symbols[0].Symbol)
+	assert.Equal(t, "TRADING", info.Symbols[0].Status)
+	assert.Equal(t, "DOGEUSDT", info.Symbols[1].Symbol)
+	assert.Equal(t, "BREAK", info.Symbols[1].Status)
+
+	// Errors come in the response body; outages are reported as such
+	_, err = client.GetTickerPrice(ctx, "SOLUSDT")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, entities.ErrUpstreamUnavailable)
+	_, err = client.GetTickerPrice(ctx, "ETHUSDT")
+	assert.ErrorIs(t, err, entities.ErrUpstreamUnavailable)
+
+	assert.Error(t, client.HealthCheck(ctx))
+}
+
+func TestKrakenClient_AggregatesKlinesOfIntervalsItLacks(t *testing.T) {
+	client := newKrakenClient(t, http.HandlerFunc(fakeKraken))
+	ctx := context.Background()
+
+	start := krakenStart.Add(2 * time.Hour).UnixMilli()
+	klines, err := client.GetKlines(ctx, "BTCUSDT", "1h", 3, &start, nil)
+	require.NoError(t, err)
+	require.Len(t, klines, 3)
+	assert.Equal(t, float64(start), klines[0][0])
+	assert.Equal(t, []interface{}{"102", "104", "101", "103", "1"}, klines[0][1:6])
+
+	// Each 2h kline is made of two hourly candles, in Binance's array format
+	klines, err = client.GetKlines(ctx, "BTCUSDT", "2h", 2, &start, nil)
+	require.NoError(t, err)
+	require.Len(t, klines, 2)
+	assert.Equal(t, []interface{}{"102", "105", "101", "104", "2"}, klines[0][1:6])
+	assert.Equal(t, float64(krakenStart.Add(4*time.Hour).UnixMilli()-1), klines[0][6])
+
+	_, err = client.GetKlines(ctx, "BTCUSDT", "1M", 10, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestKrakenClient_StreamsTickersAndClosedKlines(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	client := newKrakenClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		channels := make(map[string]bool)
+		for i := 0; i < 2; i++ {
+			var subscribe struct {
+				Method string `json:"method"`
+				Params struct {
+					Channel  string   `json:"channel"`
+					Symbol   []string `json:"symbol"`
+					Interval int      `json:"interval"`
+				} `json:"params"`
+			}
+			if err := conn.ReadJSON(&subscribe); err != nil || subscribe.Method != "subscribe" || subscribe.Params.Symbol[0] != "BTC/USDT" {
+				return
+			}
+			channels[fmt.Sprintf("%s:%d", subscribe.Params.Channel, subscribe.Params.Interval)] = true
+		}
+		if !channels["ticker:0"] || !channels["ohlc:1"] {
+			return
+		}
+
+		for _, message := range []string{
+			`{"method":"subscribe","success":true}`,
+			`{"channel":"heartbeat"}`,
+			`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/USDT","last":42000.5,"volume":12.5,"change_pct":1.25}]}`,
+			`{"channel":"ohlc","type":"update","data":[{"symbol":"BTC/USDT","open":100,"high":105,"low":100,"close":105,"volume":3,"interval_begin":"2024-01-01T12:00:00Z","interval":1,"timestamp":"2024-01-01T12:00:40Z"}]}`,
+			`{"channel":"ohlc","type":"update","data":[{"symbol":"BTC/USDT","open":103,"high":103,"low":103,"close":103,"volume":1,"interval_begin":"2024-01-01T12:01:00Z","interval":1,"timestamp":"2024-01-01T12:01:05Z"}]}`,
+		} {
+			conn.WriteMessage(websocket.TextMessage, []byte(message))
+		}
+		conn.ReadMessage()
+	}))
+	defer client.StopWebSocket()
+
+	tickerStream := client.GetTickerWebSocketStream("BTCUSDT")
+	klineStream := client.GetKlineWebSocketStream("BTCUSDT", "1m")
+	tickers := client.SubscribeToStream(tickerStream)
+	messages := client.SubscribeToStream(klineStream)
+	require.NoError(t, client.StartWebSocket(context.Background(), []string{tickerStream, klineStream}))
+
+	select {
+	case message := <-tickers:
+		var envelope struct {
+			Data external.TickerData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(message, &envelope))
+		ticker := envelope.Data
+		assert.Equal(t, "24hrTicker", ticker.EventType)
+		assert.Equal(t, "BTCUSDT", ticker.Symbol)
+		assert.Equal(t, "42000.5", ticker.Price)
+		assert.Equal(t, "1.250", ticker.Change)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the ticker")
+	}
+
+	var klines []*external.KlineWebSocketData
+	for len(klines) < 3 {
+		select {
+		case message := <-messages:
+			kline, err := external.ParseKlineMessage(message)
+			require.NoError(t, err)
+			klines = append(klines, kline)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d kline messages", len(klines))
+		}
+	}
+
+	// The first candle is reported closed when the next one starts
+	closed := klines[1].Kline
+	assert.True(t, closed.IsClosed)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).UnixMilli(), closed.OpenTime)
+	assert.Equal(t, []string{"100", "105", "100", "105", "3"}, []string{closed.Open, closed.High, closed.Low, closed.Close, closed.Volume})
+	assert.False(t, klines[2].Kline.IsClosed)
+	assert.Equal(t, "103", klines[2].Kline.Open)
+	assert.Equal(t, "BTCUSDT", klines[2].Symbol)
+}
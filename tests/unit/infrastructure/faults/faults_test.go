@@ -0,0 +1,137 @@
+package faults_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/growthfolio/go-priceguard-api/internal/domain/entities"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/faults"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestInjector_AffectsPercentageOfCalls(t *testing.T) {
+	ctx := context.Background()
+	injector := faults.NewInjector()
+	roll := 0.0
+	injector.SetRoll(func() float64 { return roll })
+	require.NoError(t, injector.Set(faults.Fault{Target: faults.TargetBinance, Percent: 25, Error: true}))
+
+	roll = 0.24
+	assert.ErrorIs(t, injector.Inject(ctx, faults.TargetBinance), faults.ErrInjected)
+	roll = 0.25
+	assert.NoError(t, injector.Inject(ctx, faults.TargetBinance))
+
+	// Other dependencies are untouched
+	roll = 0
+	assert.NoError(t, injector.Inject(ctx, faults.TargetRedis))
+
+	assert.True(t, injector.Clear(faults.TargetBinance))
+	assert.NoError(t, injector.Inject(ctx, faults.TargetBinance))
+	assert.False(t, injector.Clear(faults.TargetBinance))
+}
+
+func TestInjector_DelayHonoursContext(t *testing.T) {
+	injector := faults.NewInjector()
+	require.NoError(t, injector.Set(faults.Fault{Target: faults.TargetRedis, Percent: 100, Delay: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, injector.Inject(ctx, faults.TargetRedis), context.DeadlineExceeded)
+}
+
+func TestInjector_FaultsExpire(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := testutils.NewFakeClock(now)
+	injector := faults.NewInjector()
+	injector.SetClock(fakeClock)
+
+	expiresAt := now.Add(time.Minute)
+	require.NoError(t, injector.Set(faults.Fault{Target: faults.TargetPostgres, Percent: 100, Error: true, ExpiresAt: &expiresAt}))
+	assert.Len(t, injector.Faults(), 1)
+
+	fakeClock.Advance(time.Minute)
+	assert.Empty(t, injector.Faults())
+	assert.NoError(t, injector.Inject(context.Background(), faults.TargetPostgres))
+}
+
+func TestInjector_RejectsInvalidFaults(t *testing.T) {
+	injector := faults.NewInjector()
+	assert.ErrorIs(t, injector.Set(faults.Fault{Target: "kafka", Percent: 10, Error: true}), faults.ErrInvalidFault)
+	assert.ErrorIs(t, injector.Set(faults.Fault{Target: faults.TargetRedis, Percent: 110, Error: true}), faults.ErrInvalidFault)
+	assert.ErrorIs(t, injector.Set(faults.Fault{Target: faults.TargetRedis, Percent: 10}), faults.ErrInvalidFault)
+}
+
+func TestNilInjectorNeverInjects(t *testing.T) {
+	var injector *faults.Injector
+	assert.NoError(t, injector.Inject(context.Background(), faults.TargetBinance))
+}
+
+func TestParseFaults(t *testing.T) {
+	parsed, err := faults.ParseFaults("binance:25:error, redis:10:200ms+error,postgres:5:1s")
+	require.NoError(t, err)
+	assert.Equal(t, []faults.Fault{
+		{Target: faults.TargetBinance, Percent: 25, Error: true},
+		{Target: faults.TargetRedis, Percent: 10, Delay: 200 * time.Millisecond, Error: true},
+		{Target: faults.TargetPostgres, Percent: 5, Delay: time.Second},
+	}, parsed)
+
+	parsed, err = faults.ParseFaults("")
+	require.NoError(t, err)
+	assert.Empty(t, parsed)
+
+	_, err = faults.ParseFaults("binance:25")
+	assert.ErrorIs(t, err, faults.ErrInvalidFault)
+	_, err = faults.ParseFaults("binance:25:timeout")
+	assert.ErrorIs(t, err, faults.ErrInvalidFault)
+}
+
+func TestRedisHook_FailsCommandsAndPipelines(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	injector := faults.NewInjector()
+	client.AddHook(injector.RedisHook())
+	require.NoError(t, client.Set(ctx, "key", "value", 0).Err())
+
+	require.NoError(t, injector.Set(faults.Fault{Target: faults.TargetRedis, Percent: 100, Error: true}))
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), faults.ErrInjected)
+
+	pipe := client.Pipeline()
+	get := pipe.Get(ctx, "key")
+	_, err := pipe.Exec(ctx)
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	assert.ErrorIs(t, get.Err(), faults.ErrInjected)
+
+	injector.Clear(faults.TargetRedis)
+	assert.Equal(t, "value", client.Get(ctx, "key").Val())
+}
+
+func TestGormPlugin_FailsStatements(t *testing.T) {
+	// A dry run builds statements without a database, which is all the hook needs
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=faults"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	injector := faults.NewInjector()
+	require.NoError(t, db.Use(injector.GormPlugin()))
+
+	var alerts []entities.Alert
+	require.NoError(t, db.Find(&alerts).Error)
+
+	require.NoError(t, injector.Set(faults.Fault{Target: faults.TargetPostgres, Percent: 100, Error: true}))
+	assert.ErrorIs(t, db.Find(&alerts).Error, faults.ErrInjected)
+	assert.ErrorIs(t, db.Create(&entities.CryptoCurrency{Symbol: "BTCUSDT"}).Error, faults.ErrInjected)
+}
@@ -0,0 +1,206 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/secrets"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// stubProvider serves values from a map that tests change between refreshes
+type stubProvider struct {
+	mutex  sync.Mutex
+	values map[string]string
+	err    error
+	calls  int
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Fetch(_ context.Context, keys []string) (map[string]string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	values := make(map[string]string)
+	for _, key := range keys {
+		if value, found := p.values[key]; found {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (p *stubProvider) set(key, value string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.values[key] = value
+}
+
+func TestManager_CachesUntilTTL(t *testing.T) {
+	ctx := context.Background()
+	provider := &stubProvider{values: map[string]string{"JWT_SECRET": "one"}}
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+
+	manager := secrets.NewManager(provider, time.Minute, "JWT_SECRET", "DB_PASSWORD")
+	manager.SetClock(fakeClock)
+
+	value, found, err := manager.Get(ctx, "JWT_SECRET")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "one", value)
+
+	_, found, err = manager.Get(ctx, "DB_PASSWORD")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, 1, provider.calls)
+
+	provider.set("JWT_SECRET", "two")
+	fakeClock.Advance(time.Minute)
+
+	value, _, err = manager.Get(ctx, "JWT_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "two", value)
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestManager_RotationHooks(t *testing.T) {
+	ctx := context.Background()
+	provider := &stubProvider{values: map[string]string{"JWT_SECRET": "one", "DB_PASSWORD": "pw"}}
+	manager := secrets.NewManager(provider, time.Minute, "JWT_SECRET", "DB_PASSWORD")
+
+	var rotated []string
+	manager.OnRotate("JWT_SECRET", func(key, value string) {
+		rotated = append(rotated, key+"="+value)
+	})
+
+	// The initial load isn't a rotation
+	require.NoError(t, manager.Refresh(ctx))
+	assert.Empty(t, rotated)
+
+	require.NoError(t, manager.Refresh(ctx))
+	assert.Empty(t, rotated)
+
+	provider.set("JWT_SECRET", "two")
+	provider.set("DB_PASSWORD", "new-pw")
+	require.NoError(t, manager.Refresh(ctx))
+	assert.Equal(t, []string{"JWT_SECRET=two"}, rotated)
+}
+
+func TestManager_RefreshErrorKeepsCache(t *testing.T) {
+	ctx := context.Background()
+	provider := &stubProvider{values: map[string]string{"JWT_SECRET": "one"}}
+	manager := secrets.NewManager(provider, time.Hour, "JWT_SECRET")
+	require.NoError(t, manager.Refresh(ctx))
+
+	provider.err = errors.New("backend down")
+	assert.Error(t, manager.Refresh(ctx))
+
+	value, found, err := manager.Get(ctx, "JWT_SECRET")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "one", value)
+}
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/data/apps/priceguard", r.URL.Path)
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]interface{}{
+					"JWT_SECRET":  "vault-jwt",
+					"DB_PASSWORD": "vault-db",
+					"UNRELATED":   "ignored",
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider, err := secrets.NewVaultProvider(server.URL, "root", "kv", "/apps/priceguard/")
+	require.NoError(t, err)
+
+	values, err := provider.Fetch(context.Background(), []string{"JWT_SECRET", "DB_PASSWORD", "REDIS_PASSWORD"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"JWT_SECRET": "vault-jwt", "DB_PASSWORD": "vault-db"}, values)
+
+	denied, err := secrets.NewVaultProvider(server.URL, "wrong", "kv", "apps/priceguard")
+	require.NoError(t, err)
+	_, err = denied.Fetch(context.Background(), []string{"JWT_SECRET"})
+	assert.Error(t, err)
+}
+
+func TestAWSProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "20240501T090000Z", r.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		authorization := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(authorization,
+			"AWS4-HMAC-SHA256 Credential=AKID/20240501/us-east-1/secretsmanager/aws4_request, "+
+				"SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature="))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "prod/priceguard", body["SecretId"])
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"SecretString": `{"BINANCE_API_KEY":"aws-key","BINANCE_API_SECRET":"aws-secret"}`,
+		})
+	}))
+	defer server.Close()
+
+	provider, err := secrets.NewAWSProvider("us-east-1", "prod/priceguard", "AKID", "SECRET", "session", server.URL)
+	require.NoError(t, err)
+	provider.SetClock(testutils.NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)))
+
+	values, err := provider.Fetch(context.Background(), []string{"BINANCE_API_KEY", "BINANCE_API_SECRET", "JWT_SECRET"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BINANCE_API_KEY": "aws-key", "BINANCE_API_SECRET": "aws-secret"}, values)
+}
+
+func TestAWSProvider_RequiresCredentials(t *testing.T) {
+	_, err := secrets.NewAWSProvider("us-east-1", "prod/priceguard", "", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestGCPProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gcp-token", r.Header.Get("Authorization"))
+		if r.URL.Path != "/v1/projects/acme/secrets/priceguard-jwt-secret/versions/latest:access" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte("gcp-jwt"))},
+		})
+	}))
+	defer server.Close()
+
+	provider := secrets.NewGCPProviderWithTokenSource("acme", "priceguard-",
+		oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcp-token"}))
+	provider.SetBaseURL(server.URL)
+
+	values, err := provider.Fetch(context.Background(), []string{"JWT_SECRET", "DB_PASSWORD"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"JWT_SECRET": "gcp-jwt"}, values)
+}
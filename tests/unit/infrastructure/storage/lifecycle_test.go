@@ -0,0 +1,56 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleCleaner_RemovesOnlyExpiredObjectsUnderPrefix(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	fakeClock := testutils.NewFakeClock(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	memory := testutils.NewMemoryStorage()
+	memory.SetClock(fakeClock)
+
+	require.NoError(t, memory.PutObject(ctx, "exports/u1/old.json", []byte("{}"), "application/json"))
+	require.NoError(t, memory.PutObject(ctx, "avatars/u1/old.png", []byte("png"), "image/png"))
+	fakeClock.Advance(48 * time.Hour)
+	require.NoError(t, memory.PutObject(ctx, "exports/u1/new.json", []byte("{}"), "application/json"))
+	fakeClock.Advance(time.Hour)
+
+	cleaner := storage.NewLifecycleCleaner(memory, logger, storage.LifecycleRule{Prefix: "exports/", MaxAge: 24 * time.Hour})
+	cleaner.SetClock(fakeClock)
+
+	deleted, err := cleaner.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.ElementsMatch(t, []string{"avatars/u1/old.png", "exports/u1/new.json"}, memory.Keys())
+
+	deleted, err = cleaner.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestLifecycleCleaner_StartStop(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	memory := testutils.NewMemoryStorage()
+	require.NoError(t, memory.PutObject(context.Background(), "exports/u1/a.json", []byte("{}"), "application/json"))
+
+	cleaner := storage.NewLifecycleCleaner(memory, logger, storage.LifecycleRule{Prefix: "exports/", MaxAge: -time.Second})
+	cleaner.Start(context.Background(), 5*time.Millisecond)
+	defer cleaner.Stop()
+
+	assert.Eventually(t, func() bool { return len(memory.Keys()) == 0 }, time.Second, 5*time.Millisecond)
+}
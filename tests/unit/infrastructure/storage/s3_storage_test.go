@@ -0,0 +1,237 @@
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/config"
+	"github.com/growthfolio/go-priceguard-api/internal/infrastructure/storage"
+	"github.com/growthfolio/go-priceguard-api/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method  string
+	path    string
+	headers http.Header
+	body    []byte
+}
+
+// fakeS3 records requests and stores objects in memory
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	requests []recordedRequest
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, recordedRequest{method: r.Method, path: r.URL.EscapedPath(), headers: r.Header.Clone(), body: body})
+
+	switch r.Method {
+	case http.MethodGet:
+		// ListObjectsV2 with one key per page to exercise continuation tokens
+		prefix := "/avatars-bucket/" + r.URL.Query().Get("prefix")
+		var keys []string
+		for path := range f.objects {
+			if strings.HasPrefix(path, prefix) {
+				keys = append(keys, strings.TrimPrefix(path, "/avatars-bucket/"))
+			}
+		}
+		sort.Strings(keys)
+
+		start := 0
+		if token := r.URL.Query().Get("continuation-token"); token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, "<ListBucketResult>")
+		if start < len(keys) {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2024-03-01T10:00:00.000Z</LastModified></Contents>",
+				keys[start], len(f.objects["/avatars-bucket/"+keys[start]]))
+		}
+		if start+1 < len(keys) {
+			fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", start+1)
+		} else {
+			fmt.Fprint(w, "<IsTruncated>false</IsTruncated>")
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if _, exists := f.objects[r.URL.Path]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestStorage(t *testing.T) (*storage.S3Storage, *fakeS3, *httptest.Server) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s3, err := storage.NewS3Storage(&config.StorageConfig{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		Bucket:          "avatars-bucket",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		UsePathStyle:    true,
+	})
+	require.NoError(t, err)
+	s3.SetClock(testutils.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	return s3, fake, server
+}
+
+func TestS3Storage_PutObjectSignsRequest(t *testing.T) {
+	s3, fake, _ := newTestStorage(t)
+	data := []byte("fake-png-bytes")
+
+	require.NoError(t, s3.PutObject(context.Background(), "avatars/user 1/a.png", data, "image/png"))
+
+	require.Len(t, fake.requests, 1)
+	req := fake.requests[0]
+	assert.Equal(t, http.MethodPut, req.method)
+	assert.Equal(t, "/avatars-bucket/avatars/user%201/a.png", req.path)
+	assert.Equal(t, data, req.body)
+	assert.Equal(t, "image/png", req.headers.Get("Content-Type"))
+	assert.Equal(t, "20240301T120000Z", req.headers.Get("X-Amz-Date"))
+
+	sum := sha256.Sum256(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), req.headers.Get("X-Amz-Content-Sha256"))
+
+	auth := req.headers.Get("Authorization")
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240301/eu-west-1/s3/aws4_request, "))
+	assert.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, ")
+	assert.Regexp(t, `Signature=[0-9a-f]{64}$`, auth)
+}
+
+func TestS3Storage_SignatureIsDeterministic(t *testing.T) {
+	s3, fake, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, s3.PutObject(ctx, "k.png", []byte("a"), "image/png"))
+	require.NoError(t, s3.PutObject(ctx, "k.png", []byte("a"), "image/png"))
+	require.NoError(t, s3.PutObject(ctx, "k.png", []byte("b"), "image/png"))
+
+	require.Len(t, fake.requests, 3)
+	assert.Equal(t, fake.requests[0].headers.Get("Authorization"), fake.requests[1].headers.Get("Authorization"))
+	assert.NotEqual(t, fake.requests[0].headers.Get("Authorization"), fake.requests[2].headers.Get("Authorization"))
+}
+
+func TestS3Storage_DeleteObject(t *testing.T) {
+	s3, _, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, s3.PutObject(ctx, "avatars/x.png", []byte("x"), "image/png"))
+	assert.NoError(t, s3.DeleteObject(ctx, "avatars/x.png"))
+	assert.ErrorIs(t, s3.DeleteObject(ctx, "avatars/x.png"), storage.ErrObjectNotFound)
+}
+
+func TestS3Storage_PublicURLRoundTrip(t *testing.T) {
+	s3, _, server := newTestStorage(t)
+
+	url := s3.PublicURL("avatars/user 1/a.png")
+	assert.Equal(t, server.URL+"/avatars-bucket/avatars/user%201/a.png", url)
+
+	key, owned := s3.KeyFromURL(url)
+	assert.True(t, owned)
+	assert.Equal(t, "avatars/user 1/a.png", key)
+
+	_, owned = s3.KeyFromURL("https://lh3.googleusercontent.com/a/photo.jpg")
+	assert.False(t, owned)
+}
+
+func TestS3Storage_PublicBaseURLAndVirtualHost(t *testing.T) {
+	s3, err := storage.NewS3Storage(&config.StorageConfig{
+		Endpoint:      "https://s3.amazonaws.com",
+		Bucket:        "media",
+		PublicBaseURL: "https://cdn.example.com/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/avatars/a.png", s3.PublicURL("avatars/a.png"))
+
+	s3, err = storage.NewS3Storage(&config.StorageConfig{
+		Endpoint: "https://s3.amazonaws.com",
+		Bucket:   "media",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://media.s3.amazonaws.com/avatars/a.png", s3.PublicURL("avatars/a.png"))
+}
+
+func TestNewS3Storage_RequiresConfiguration(t *testing.T) {
+	_, err := storage.NewS3Storage(&config.StorageConfig{})
+	assert.Error(t, err)
+
+	_, err = storage.NewS3Storage(&config.StorageConfig{Endpoint: "not a url", Bucket: "b"})
+	assert.Error(t, err)
+}
+
+func TestS3Storage_ListObjectsFollowsContinuationTokens(t *testing.T) {
+	s3, fake, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"exports/u1/a.json", "exports/u1/b.csv", "exports/u2/c.json", "avatars/u1/x.png"} {
+		require.NoError(t, s3.PutObject(ctx, key, []byte(key), "application/octet-stream"))
+	}
+
+	objects, err := s3.ListObjects(ctx, "exports/")
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, "exports/u1/a.json", objects[0].Key)
+	assert.Equal(t, int64(len("exports/u1/a.json")), objects[0].Size)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), objects[0].LastModified)
+
+	last := fake.requests[len(fake.requests)-1]
+	assert.Equal(t, http.MethodGet, last.method)
+	assert.Contains(t, last.headers.Get("Authorization"), "SignedHeaders=host;x-amz-content-sha256;x-amz-date")
+}
+
+func TestS3Storage_PresignGetURL(t *testing.T) {
+	s3, _, server := newTestStorage(t)
+
+	presigned, err := s3.PresignGetURL("exports/u1/a.json", 15*time.Minute)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(presigned)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), parsed.Host)
+	assert.Equal(t, "/avatars-bucket/exports/u1/a.json", parsed.Path)
+
+	query := parsed.Query()
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKIDEXAMPLE/20240301/eu-west-1/s3/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20240301T120000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.Equal(t, "host", query.Get("X-Amz-SignedHeaders"))
+	assert.Regexp(t, `^[0-9a-f]{64}$`, query.Get("X-Amz-Signature"))
+
+	again, err := s3.PresignGetURL("exports/u1/a.json", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, presigned, again)
+
+	_, err = s3.PresignGetURL("exports/u1/a.json", 8*24*time.Hour)
+	assert.Error(t, err)
+}